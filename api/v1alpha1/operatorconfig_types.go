@@ -0,0 +1,126 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigConditionApplied reports whether the settings of an OperatorConfig are in effect
+const OperatorConfigConditionApplied = "Applied"
+
+// ApplicationImagesConfig configures the images of the workspace containers
+type ApplicationImagesConfig struct {
+	// Registry is the prefix of the application images without a registry (e.g. example.com/my-registry).
+	// Overrides --application-images-registry.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+
+	// PullPolicy is the image pull policy of the workspace containers.
+	// Overrides --application-images-pull-policy.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+
+	// PullSecret is the name of a Secret added to the image pull secrets of every workspace pod.
+	// The Secret must exist in the namespace of each workspace.
+	// Overrides --application-images-pull-secret.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	PullSecret string `json:"pullSecret,omitempty"`
+}
+
+// WatchedResource is a resource kind the controller watches, to reconcile the access resources of
+// that kind that access strategies render
+type WatchedResource struct {
+	// Group of the resource, empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version of the resource
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Kind of the resource
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// OperatorConfigSpec holds the operator settings that change without restarting the manager.
+// Unset fields keep the value of the corresponding manager flag.
+type OperatorConfigSpec struct {
+	// ApplicationImages configures the images of the workspace containers
+	// +optional
+	ApplicationImages *ApplicationImagesConfig `json:"applicationImages,omitempty"`
+
+	// RequireTemplate rejects the workspaces that users other than cluster admins create without
+	// a templateRef, or update to remove it. Overrides --require-template.
+	// +optional
+	RequireTemplate *bool `json:"requireTemplate,omitempty"`
+
+	// IdleCheckInterval is the interval between the idle checks of running workspaces.
+	// Overrides --idle-check-interval.
+	// +optional
+	IdleCheckInterval *metav1.Duration `json:"idleCheckInterval,omitempty"`
+
+	// WatchResources lists resource kinds the controller watches in addition to those of
+	// --watch-resources-gvk. Kinds added are watched without restarting the manager, kinds
+	// removed stay watched until it restarts.
+	// +listType=atomic
+	// +optional
+	WatchResources []WatchedResource `json:"watchResources,omitempty"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	// ObservedGeneration is the generation of the spec last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the resource's state
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type==\"Applied\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OperatorConfig is the Schema for the operatorconfigs API
+// It holds the settings of the operator installed in its namespace, which the controller and the
+// webhooks apply as soon as they change. The operator reads the OperatorConfig named by
+// --operator-config-name in the namespace of the controller.
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the settings of the operator
+	// +optional
+	Spec OperatorConfigSpec `json:"spec,omitempty"`
+
+	// Status defines the observed state of OperatorConfig
+	// +optional
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -9,21 +9,121 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
-// VolumeSpec defines a volume to mount from an existing PVC
+// VolumeSpec defines a volume to mount from an existing PVC or from an NFS server
+// +kubebuilder:validation:XValidation:rule="has(self.persistentVolumeClaimName) != has(self.nfs)",message="exactly one of persistentVolumeClaimName and nfs must be set"
 type VolumeSpec struct {
 	// Name is a unique identifier for this volume within the pod (maps to pod.spec.volumes[].name)
 	Name string `json:"name"`
 
-	// PersistentVolumeClaimName is the name of the existing PVC to mount
-	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// PersistentVolumeClaimName is the name of the existing PVC to mount, e.g. a PVC bound to a
+	// pre-existing PersistentVolume of an EFS file system
+	// +optional
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+
+	// NFS mounts an export of an NFS server directly, without a PVC
+	// +optional
+	NFS *NFSVolumeSource `json:"nfs,omitempty"`
 
 	// MountPath is the path where the volume should be mounted (Unix-style path, e.g. /data)
 	MountPath string `json:"mountPath"`
+
+	// SubPath mounts a directory of the volume rather than its root, e.g. datasets/imagenet
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[^/]`
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// ReadOnly mounts the volume read-only, so that a shared dataset can be attached to many
+	// workspaces without any of them modifying it
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// NFSVolumeSource defines an export of an NFS server, e.g. an EFS file system
+type NFSVolumeSource struct {
+	// Server is the host name or IP address of the NFS server
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Server string `json:"server"`
+
+	// Path is the exported path on the NFS server
+	// +kubebuilder:validation:MaxLength=4096
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+}
+
+// ObjectStorageProvider is the object store of an ObjectStorageMount
+// +kubebuilder:validation:Enum=S3;GCS
+type ObjectStorageProvider string
+
+const (
+	// ObjectStorageProviderS3 mounts an Amazon S3 bucket
+	ObjectStorageProviderS3 ObjectStorageProvider = "S3"
+	// ObjectStorageProviderGCS mounts a Google Cloud Storage bucket
+	ObjectStorageProviderGCS ObjectStorageProvider = "GCS"
+)
+
+// ObjectStorageMountMode is how an ObjectStorageMount exposes its bucket to the workspace
+// +kubebuilder:validation:Enum=CSI;Sync
+type ObjectStorageMountMode string
+
+const (
+	// ObjectStorageMountModeCSI mounts the bucket with the CSI driver of the provider, which must
+	// be installed on the cluster: Mountpoint for Amazon S3 or Cloud Storage FUSE
+	ObjectStorageMountModeCSI ObjectStorageMountMode = "CSI"
+	// ObjectStorageMountModeSync copies the bucket to an emptyDir volume with a sync sidecar
+	ObjectStorageMountModeSync ObjectStorageMountMode = "Sync"
+)
+
+// ObjectStorageMount defines a bucket of an object store mounted in the workspace
+// +kubebuilder:validation:XValidation:rule="!(self.provider == 'GCS' && self.mode == 'CSI' && has(self.credentialsSecretName))",message="the GCS CSI driver does not support credentialsSecretName, use workload identity"
+type ObjectStorageMount struct {
+	// Name identifies the mount, and names its volume in the pod
+	// +kubebuilder:validation:MaxLength=48
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Provider is the object store of the bucket
+	Provider ObjectStorageProvider `json:"provider"`
+
+	// Bucket is the name of the bucket
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=222
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9._-]*[a-z0-9]$`
+	Bucket string `json:"bucket"`
+
+	// Prefix mounts the objects under this prefix of the bucket rather than the whole bucket, e.g. datasets/imagenet
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:Pattern=`^[^/]`
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// MountPath is the path where the bucket is mounted in the workspace container
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^/`
+	MountPath string `json:"mountPath"`
+
+	// Mode is how the bucket is exposed to the workspace
+	// +kubebuilder:default=CSI
+	// +optional
+	Mode ObjectStorageMountMode `json:"mode,omitempty"`
+
+	// ReadOnly mounts the bucket read-only. In Sync mode, the sidecar only downloads the bucket.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// CredentialsSecretName is the name of a Secret of the workspace namespace holding the credentials
+	// of the bucket. When empty, the credentials come from the ServiceAccount of the workspace, e.g.
+	// with IAM roles for service accounts (IRSA) or GKE workload identity.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
 }
 
 // ContainerConfig defines container command and args configuration
@@ -35,6 +135,52 @@ type ContainerConfig struct {
 	Args []string `json:"args,omitempty"`
 }
 
+// ApplicationType is the kind of application served by a workspace, which decides the port it
+// listens on, the path of its default probes and the environment variable holding its base URL
+// +kubebuilder:validation:Enum=jupyterlab;codeserver;rstudio;custom
+type ApplicationType string
+
+const (
+	// ApplicationTypeJupyterLab serves JupyterLab, or another Jupyter server, on port 8888
+	ApplicationTypeJupyterLab ApplicationType = "jupyterlab"
+	// ApplicationTypeCodeServer serves VS Code in the browser with code-server on port 8080
+	ApplicationTypeCodeServer ApplicationType = "codeserver"
+	// ApplicationTypeRStudio serves RStudio Server on port 8787
+	ApplicationTypeRStudio ApplicationType = "rstudio"
+	// ApplicationTypeCustom serves any other application on port 8888, probed on /
+	ApplicationTypeCustom ApplicationType = "custom"
+)
+
+// WorkspacePort defines an additional named port of the workspace, e.g. for TensorBoard or MLflow
+// running next to the main application
+type WorkspacePort struct {
+	// Name of the port on the workspace Service and container, e.g. tensorboard
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Port the application listens on in the workspace pod
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Path is the subpath access strategies route to this port, relative to the base path of the workspace
+	// Default: /<name>/.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// HealthPath is the path of the health endpoint the application serves on this port, e.g.
+	// /tensorboard/ for TensorBoard started with --path_prefix=/tensorboard. When set, the controller
+	// probes it through the workspace Service while the workspace runs, and reports the readiness of
+	// the application in status.applications.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	HealthPath string `json:"healthPath,omitempty"`
+}
+
 // StorageSpec defines the storage configuration for Workspace
 type StorageSpec struct {
 	// StorageClassName specifies the storage class to use for persistent storage
@@ -49,6 +195,85 @@ type StorageSpec struct {
 	// MountPath specifies where to mount the persistent volume in the container
 	// Default is /home/jovyan (jovyan is the standard user in Jupyter images)
 	MountPath string `json:"mountPath,omitempty"`
+
+	// RestoreFromSnapshot names a WorkspaceSnapshot in the workspace's namespace to populate
+	// the persistent volume from when it is first created.
+	// It can only be set when the workspace is created.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="restoreFromSnapshot is immutable"
+	// +optional
+	RestoreFromSnapshot *SnapshotRef `json:"restoreFromSnapshot,omitempty"`
+
+	// ProvisioningHook runs a Job against the persistent volume once, after the controller
+	// creates it and before the workspace becomes available
+	// +optional
+	ProvisioningHook *StorageProvisioningHook `json:"provisioningHook,omitempty"`
+
+	// RetainPolicy controls what happens to the persistent volume when the workspace is deleted
+	// +kubebuilder:default=Delete
+	// +optional
+	RetainPolicy StorageRetainPolicy `json:"retainPolicy,omitempty"`
+}
+
+// StorageRetainPolicy controls what happens to the primary storage of a deleted workspace
+// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+type StorageRetainPolicy string
+
+const (
+	// StorageRetainPolicyDelete deletes the PVC with the workspace
+	StorageRetainPolicyDelete StorageRetainPolicy = "Delete"
+
+	// StorageRetainPolicyRetain keeps the PVC, released for a new workspace with the same name and owner
+	StorageRetainPolicyRetain StorageRetainPolicy = "Retain"
+
+	// StorageRetainPolicySnapshot takes a WorkspaceSnapshot of the PVC before deleting it.
+	// The PVC is retained instead when the snapshot cannot be taken.
+	StorageRetainPolicySnapshot StorageRetainPolicy = "Snapshot"
+)
+
+// SnapshotRef defines a reference to a WorkspaceSnapshot
+type SnapshotRef struct {
+	// Name of the WorkspaceSnapshot
+	Name string `json:"name"`
+}
+
+// StorageProvisioningHook defines a Job that prepares a newly created home volume,
+// e.g. to seed skeleton files, set quotas or fix permissions.
+// Command and args may reference $(WORKSPACE_NAME), $(WORKSPACE_NAMESPACE),
+// $(WORKSPACE_OWNER) and $(STORAGE_MOUNT_PATH), which are set on the hook container.
+type StorageProvisioningHook struct {
+	// Image is the container image that runs the hook
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command overrides the entrypoint of the image
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments passed to the command
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env sets additional environment variables on the hook container
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// SecurityContext of the hook container, defaults to the workspace container security context
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// TimeoutSeconds bounds how long the hook Job may run before it is marked failed
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// BackoffLimit is the number of retries before the hook Job is marked failed
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 }
 
 // AccessStrategyRef defines a reference to a WorkspaceAccessStrategy
@@ -72,6 +297,23 @@ type TemplateRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// CloneSource defines a reference to the Workspace a workspace is cloned from
+type CloneSource struct {
+	// Name of the source Workspace
+	Name string `json:"name"`
+
+	// Namespace where the source Workspace is located
+	// When omitted, defaults to the workspace's namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// IncludeStorage clones the persistent volume of the source workspace into the new
+	// workspace's volume. The source must be in the workspace's namespace, and its storage
+	// class must support volume cloning
+	// +optional
+	IncludeStorage bool `json:"includeStorage,omitempty"`
+}
+
 // IdleShutdownSpec defines idle shutdown configuration
 type IdleShutdownSpec struct {
 	// Enabled indicates if idle shutdown is enabled
@@ -90,6 +332,12 @@ type IdleDetectionSpec struct {
 	// HTTPGet specifies the HTTP request to perform for idle detection
 	// +optional
 	HTTPGet *IdleHTTPGetAction `json:"httpGet,omitempty"`
+
+	// HubActivity accepts the activity that the workspace reports to the JupyterHub activity API
+	// served by the operator, e.g. from jupyterhub-singleuser. The workspace is idle once neither
+	// the reported activity nor the HTTPGet probe, when set, shows activity within the idle timeout.
+	// +optional
+	HubActivity bool `json:"hubActivity,omitempty"`
 }
 
 // IdleHTTPGetAction extends corev1.HTTPGetAction with transport and response parsing options.
@@ -128,6 +376,270 @@ type IdleLastActivityTimestampSpec struct {
 	Format string `json:"format,omitempty"`
 }
 
+// CullingPolicySpec exempts a workspace from automated stops such as idle shutdown,
+// so that long-running jobs are not interrupted
+// +kubebuilder:validation:XValidation:rule="!(has(self.disabled) && self.disabled && has(self.keepAliveUntil))",message="disabled and keepAliveUntil are mutually exclusive"
+type CullingPolicySpec struct {
+	// Disabled exempts the workspace from culling until the field is unset
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// KeepAliveUntil exempts the workspace from culling until the given time
+	// +optional
+	KeepAliveUntil *metav1.Time `json:"keepAliveUntil,omitempty"`
+}
+
+// WorkspaceProbes defines the probes of the main workspace container
+type WorkspaceProbes struct {
+	// Readiness gates the workspace from receiving traffic until the application serves requests
+	// +optional
+	Readiness *corev1.Probe `json:"readiness,omitempty"`
+
+	// Liveness restarts the main container when the application stops responding
+	// +optional
+	Liveness *corev1.Probe `json:"liveness,omitempty"`
+
+	// Startup holds off the readiness and liveness probes until the application has started,
+	// so that slow-starting images are not restarted or reported unavailable while starting
+	// +optional
+	Startup *corev1.Probe `json:"startup,omitempty"`
+}
+
+// FailurePolicySpec bounds how long the controller retries a starting workspace whose containers
+// keep failing, e.g. one stuck in a crash loop
+type FailurePolicySpec struct {
+	// RetryLimit is the number of container restarts of the starting workspace pod tolerated
+	// before the controller gives up and marks the workspace as Degraded.
+	// Default: 5. Minimum: 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetryLimit int32 `json:"retryLimit,omitempty"`
+
+	// BackoffSeconds is the delay before the controller checks a failing workspace again after its
+	// first container restart, doubled at each further restart up to MaxBackoffSeconds.
+	// Default: 10. Minimum: 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BackoffSeconds int32 `json:"backoffSeconds,omitempty"`
+
+	// MaxBackoffSeconds caps the delay between checks of a failing workspace.
+	// Default: 300. Minimum: 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackoffSeconds int32 `json:"maxBackoffSeconds,omitempty"`
+}
+
+// DriftPolicy defines how the controller handles manual changes to the resources it generates
+// for a workspace
+// +kubebuilder:validation:Enum=Revert;Ignore;Warn
+type DriftPolicy string
+
+const (
+	// DriftPolicyRevert reverts manual changes to the generated resources
+	DriftPolicyRevert DriftPolicy = "Revert"
+	// DriftPolicyIgnore keeps manual changes to the generated resources without reporting them
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+	// DriftPolicyWarn keeps manual changes to the generated resources and reports them
+	// in the DriftDetected condition
+	DriftPolicyWarn DriftPolicy = "Warn"
+)
+
+// SecurityProfileLevel is the Pod Security Standard that workspace pods comply with
+// +kubebuilder:validation:Enum=Restricted
+type SecurityProfileLevel string
+
+const (
+	// SecurityProfileRestricted makes workspace pods comply with the restricted Pod Security Standard:
+	// containers run as non-root with the RuntimeDefault seccomp profile, cannot escalate privileges
+	// and drop all capabilities
+	SecurityProfileRestricted SecurityProfileLevel = "Restricted"
+)
+
+// SecurityProfile defines the Pod Security Standard that the controller enforces on workspace pods,
+// and the exceptions workspaces may request
+type SecurityProfile struct {
+	// Level is the Pod Security Standard of the workspace pods
+	// +kubebuilder:default=Restricted
+	// +optional
+	Level SecurityProfileLevel `json:"level,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of the workspace containers as read-only.
+	// An emptyDir volume is mounted on /tmp so that applications can still write temporary files
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// AllowRunAsRoot lets workspaces run their containers as root by setting runAsNonRoot to false
+	// or runAsUser to 0 in their security contexts
+	// +optional
+	AllowRunAsRoot bool `json:"allowRunAsRoot,omitempty"`
+
+	// AllowedCapabilities lists the capabilities workspaces may add back to their containers,
+	// in addition to NET_BIND_SERVICE which the restricted standard allows
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	AllowedCapabilities []corev1.Capability `json:"allowedCapabilities,omitempty"`
+}
+
+// GPUSharingMode is how a workspace shares its NVIDIA GPUs with other pods
+// +kubebuilder:validation:Enum=Exclusive;TimeSliced;MPS
+type GPUSharingMode string
+
+const (
+	// GPUSharingExclusive gives the workspace whole GPUs
+	GPUSharingExclusive GPUSharingMode = "Exclusive"
+	// GPUSharingTimeSliced gives the workspace replicas of GPUs that the NVIDIA device plugin
+	// shares between pods by time-slicing, without memory or fault isolation
+	GPUSharingTimeSliced GPUSharingMode = "TimeSliced"
+	// GPUSharingMPS gives the workspace replicas of GPUs that the NVIDIA device plugin shares
+	// between pods with the CUDA Multi-Process Service, which partitions their threads and memory
+	GPUSharingMPS GPUSharingMode = "MPS"
+)
+
+// GPUSharing defines how the NVIDIA GPUs (nvidia.com/gpu) a workspace requests are shared with
+// other pods. The sharing itself is configured on the nodes, in the NVIDIA device plugin.
+type GPUSharing struct {
+	// Mode is how the GPUs of the workspace are shared
+	// +kubebuilder:default=Exclusive
+	// +optional
+	Mode GPUSharingMode `json:"mode,omitempty"`
+
+	// SharedResourceName is the resource the NVIDIA device plugin advertises shared GPUs as, when it
+	// renames them, e.g. nvidia.com/gpu.shared. In the TimeSliced and MPS modes, the nvidia.com/gpu
+	// requests and limits of the workspace are translated to this resource
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^nvidia\.com/gpu(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	SharedResourceName string `json:"sharedResourceName,omitempty"`
+
+	// MPS configures the CUDA MPS client of the workspace, in the MPS mode. It is ignored in the
+	// other modes, so that a template can configure it for the workspaces that choose MPS
+	// +optional
+	MPS *GPUMPSConfig `json:"mps,omitempty"`
+}
+
+// GPUMPSConfig configures the CUDA Multi-Process Service client of a workspace, through the
+// CUDA_MPS_* environment variables of its container
+type GPUMPSConfig struct {
+	// PipeDirectory is the directory of the pipes of the MPS control daemon in the workspace
+	// container, set as CUDA_MPS_PIPE_DIRECTORY. It must match where the device plugin mounts them
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	PipeDirectory string `json:"pipeDirectory,omitempty"`
+
+	// ActiveThreadPercentage limits the share of the threads of each GPU the workspace may use,
+	// set as CUDA_MPS_ACTIVE_THREAD_PERCENTAGE
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ActiveThreadPercentage *int32 `json:"activeThreadPercentage,omitempty"`
+
+	// PinnedDeviceMemoryLimit limits the memory the workspace may allocate on each GPU, set as
+	// CUDA_MPS_PINNED_DEVICE_MEM_LIMIT
+	// +optional
+	PinnedDeviceMemoryLimit *resource.Quantity `json:"pinnedDeviceMemoryLimit,omitempty"`
+}
+
+// NetworkIsolation defines the NetworkPolicy the controller generates for the workspace pod. The
+// policy denies all ingress traffic to the pod except from the controller, the ingress controller
+// and the listed namespaces and CIDRs.
+type NetworkIsolation struct {
+	// IngressControllerNamespace is the namespace of the ingress controller or gateway routing
+	// traffic to the workspaces, e.g. traefik
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	IngressControllerNamespace string `json:"ingressControllerNamespace,omitempty"`
+
+	// AllowedNamespaces lists other namespaces whose pods can reach the workspace, e.g. monitoring
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=63
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedCIDRs lists the IP ranges that can reach the workspace, e.g. the addresses of an
+	// external load balancer, in CIDR notation
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=43
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// CapacityType is the capacity type of the nodes provisioned for a workspace
+// +kubebuilder:validation:Enum=Spot;OnDemand
+type CapacityType string
+
+const (
+	// CapacityTypeSpot provisions spot nodes, which are cheaper but can be reclaimed
+	CapacityTypeSpot CapacityType = "Spot"
+	// CapacityTypeOnDemand provisions on-demand nodes
+	CapacityTypeOnDemand CapacityType = "OnDemand"
+)
+
+// ProvisioningHints tells node autoprovisioners, such as Karpenter or the Cluster Autoscaler, which
+// nodes to provision for the workspace pod. The controller requires the pod to run on nodes with
+// the matching well-known labels, which autoprovisioners honor when they scale up.
+type ProvisioningHints struct {
+	// InstanceFamilies lists the instance families the pod can run on, e.g. g5 or m7i, matched
+	// against the karpenter.k8s.aws/instance-family node label
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=63
+	// +optional
+	InstanceFamilies []string `json:"instanceFamilies,omitempty"`
+
+	// CapacityType is the capacity type of the node, matched against the karpenter.sh/capacity-type
+	// node label
+	// +optional
+	CapacityType CapacityType `json:"capacityType,omitempty"`
+
+	// Zones lists the zones the pod can run in, matched against the topology.kubernetes.io/zone
+	// node label
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=63
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// DoNotDisrupt keeps autoprovisioners from consolidating or scaling down the node of the
+	// running workspace pod
+	// +optional
+	DoNotDisrupt bool `json:"doNotDisrupt,omitempty"`
+}
+
+// JupyterServerConfig standardizes the configuration of the Jupyter server and JupyterLab in the
+// workspace container. The controller generates it into a ConfigMap holding a
+// jupyter_server_config.py file and a JupyterLab overrides.json file, mounted in the container.
+type JupyterServerConfig struct {
+	// DefaultKernel is the name of the kernel that new notebooks and consoles start, e.g. python3
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DefaultKernel string `json:"defaultKernel,omitempty"`
+
+	// TerminalsEnabled enables or disables the terminals of the Jupyter server
+	// The setting of the image is kept when unset
+	// +optional
+	TerminalsEnabled *bool `json:"terminalsEnabled,omitempty"`
+
+	// Extensions enables (true) or disables (false) Jupyter server extensions, by module name,
+	// e.g. jupyterlab_git
+	// +kubebuilder:validation:MaxProperties=50
+	// +optional
+	Extensions map[string]bool `json:"extensions,omitempty"`
+
+	// LabSettingsOverrides overrides the default settings of JupyterLab, by plugin ID, as in the
+	// overrides.json file of JupyterLab, e.g. {"@jupyterlab/apputils-extension:themes": {"theme": "JupyterLab Dark"}}
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	LabSettingsOverrides *runtime.RawExtension `json:"labSettingsOverrides,omitempty"`
+
+	// LabSettingsDir is the JupyterLab application settings directory of the image, where
+	// overrides.json is mounted
+	// Default: /opt/conda/share/jupyter/lab/settings, the directory of the Jupyter Docker Stacks images.
+	// +kubebuilder:validation:MaxLength=4096
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	LabSettingsDir string `json:"labSettingsDir,omitempty"`
+}
+
 // WorkspaceSpec defines the desired state of Workspace
 type WorkspaceSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -141,17 +653,33 @@ type WorkspaceSpec struct {
 	// Image specifies the container image to use
 	Image string `json:"image,omitempty"`
 
-	// DesiredStatus specifies the desired operational status
-	// +kubebuilder:validation:Enum=Running;Stopped
+	// DesiredStatus specifies the desired operational status.
+	// Hibernated stops the workspace like Stopped, but keeps status.accessURL resolved
+	// from the access strategy as a placeholder for dormant workspaces.
+	// +kubebuilder:validation:Enum=Running;Stopped;Hibernated
 	DesiredStatus string `json:"desiredStatus,omitempty"`
 
 	// OwnershipType specifies who can modify the workspace.
 	// Public means anyone with RBAC permissions can update/delete the workspace.
 	// OwnerOnly means only the creator can update/delete the workspace.
-	// +kubebuilder:validation:Enum=Public;OwnerOnly
+	// GroupOnly means the creator and the members of OwnerGroup can update/delete the workspace.
+	// +kubebuilder:validation:Enum=Public;OwnerOnly;GroupOnly
 	// +optional
 	OwnershipType string `json:"ownershipType,omitempty"`
 
+	// OwnerGroup is the group whose members can update/delete a GroupOnly workspace.
+	// Required when OwnershipType is GroupOnly.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	OwnerGroup string `json:"ownerGroup,omitempty"`
+
+	// OwnerTransferTo requests to transfer the ownership of the workspace to this user.
+	// Only admins can set it, once the workspace exists. The controller then rewrites the
+	// created-by annotation to this user and clears the field.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	OwnerTransferTo string `json:"ownerTransferTo,omitempty"`
+
 	// AccessType specifies who can connect to the workspace.
 	// Public means anyone with RBAC permissions can connect to workspace.
 	// OwnerOnly means only the creator can connect to the workspace.
@@ -162,21 +690,58 @@ type WorkspaceSpec struct {
 	// Resources specifies the resource requirements
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Profile selects a named size of the template, whose resource requirements replace Resources
+	// Requires TemplateRef, and must name one of the template profiles
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
 	// Storage specifies the storage configuration
 	Storage *StorageSpec `json:"storage,omitempty"`
 
-	// Volumes specifies additional volumes to mount from existing PersistantVolumeClaims
+	// Volumes specifies additional volumes to mount from existing PersistantVolumeClaims or NFS servers
 	// +kubebuilder:validation:XValidation:rule="!self.exists(v, v.name == 'workspace-storage')",message="volume name 'workspace-storage' is reserved"
 	Volumes []VolumeSpec `json:"volumes,omitempty"`
 
+	// ObjectStorageMounts lists the S3 or GCS buckets to mount in the workspace container
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:XValidation:rule="self.all(m, self.exists_one(n, n.name == m.name))",message="object storage mount names must be unique"
+	// +optional
+	ObjectStorageMounts []ObjectStorageMount `json:"objectStorageMounts,omitempty"`
+
 	// ContainerConfig specifies container command and args configuration
 	ContainerConfig *ContainerConfig `json:"containerConfig,omitempty"`
 
+	// Ports lists additional named ports exposed on the workspace Service, next to the "http" port
+	// of the main application. Access strategies can route subpaths to them through .Routes.
+	// When a template is used, template's DefaultPorts are applied if workspace has none
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:XValidation:rule="!self.exists(p, p.name == 'http' || p.name == 'ssh')",message="port names 'http' and 'ssh' are reserved"
+	// +kubebuilder:validation:XValidation:rule="!self.exists(p, p.port == 8888)",message="port 8888 is reserved for the main application"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, self.exists_one(q, q.port == p.port))",message="ports must be unique"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Ports []WorkspacePort `json:"ports,omitempty"`
+
 	// Env specifies environment variables for the workspace container
 	// When a template is used, template's BaseEnv vars are merged (workspace vars take precedence by name)
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// EnvFrom specifies ConfigMaps and Secrets whose keys are injected as environment variables
+	// into the workspace container.
+	// When a template is used, template's BaseEnvFrom sources are merged
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// ExtraEnv specifies additional environment variables for this workspace only, added after Env.
+	// Unlike Env, it is never merged with template defaults, and it cannot redefine a variable of Env
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+
 	// NodeSelector specifies node selection constraints for the workspace pod
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
@@ -186,35 +751,132 @@ type WorkspaceSpec struct {
 	// Tolerations specifies tolerations for the workspace pod to schedule on nodes with matching taints
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PriorityClassName is the PriorityClass of the workspace pod, which sets its scheduling priority
+	// and whether it may preempt lower priority pods
+	// When a template is used, it must be allowed by the template
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Lifecycle specifies actions that the management system should take
 	// in response to container lifecycle events (for instance, lifecycle hooks)
 	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
 
+	// StopGracePeriodSeconds is how long the workspace pod may take to shut down when the workspace
+	// stops. When set, the workspace container also stops the Jupyter server through a preStop hook
+	// running `jupyter server stop`, unless lifecycle sets its own preStop hook, so that kernels shut
+	// down and pending autosaves complete before the container is killed.
+	// Defaults to the 30 seconds of Kubernetes, without the preStop hook, when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	StopGracePeriodSeconds *int64 `json:"stopGracePeriodSeconds,omitempty"`
+
+	// RestartRequestedAt requests a restart of the workspace pod: setting it to a new time, e.g.
+	// the current time, recreates the pod of a running workspace. Stopped workspaces are not started.
+	// +optional
+	RestartRequestedAt *metav1.Time `json:"restartRequestedAt,omitempty"`
+
 	// ReadinessProbe specifies the readiness probe for the main workspace container.
+	// Deprecated: use probes.readiness instead.
 	// +optional
 	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
 
+	// Probes specifies the readiness, liveness and startup probes for the main workspace container.
+	// Probes left empty default to an HTTP GET on the Jupyter /api/status endpoint.
+	// readinessProbe, when set, takes precedence over the default readiness probe.
+	// +optional
+	Probes *WorkspaceProbes `json:"probes,omitempty"`
+
+	// StartupDeadlineSeconds is how long the workspace may take to become available after it starts,
+	// or after its spec changes while it starts, before the controller gives up and marks it as Degraded.
+	// No deadline applies when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StartupDeadlineSeconds *int32 `json:"startupDeadlineSeconds,omitempty"`
+
+	// FailurePolicy makes the controller back off from, then give up on, a starting workspace whose
+	// containers keep restarting. Without it, the controller retries such a workspace indefinitely.
+	// +optional
+	FailurePolicy *FailurePolicySpec `json:"failurePolicy,omitempty"`
+
+	// DriftPolicy controls how the controller handles manual changes to the Deployment, Service
+	// and access resources it generates for the workspace. Changes to the workspace, its template
+	// or its access strategy are applied regardless of the policy.
+	// Default: Revert.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
 	// AccessStrategy specifies the WorkspaceAccessStrategy to use
 	// +optional
 	AccessStrategy *AccessStrategyRef `json:"accessStrategy,omitempty"`
 
+	// CustomDomain is a host name to serve the workspace on, e.g. alice-notebook.team.example.com,
+	// instead of a path of a shared host. Access strategies render it through .CustomDomain.
+	// Requires TemplateRef, and must be a subdomain of one of the template AllowedDomainSuffixes.
+	// Each custom domain can only be used by one workspace.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)+$`
+	// +optional
+	CustomDomain string `json:"customDomain,omitempty"`
+
+	// AccessEnabled controls whether the workspace can be accessed. When false, the controller
+	// deletes the access resources and clears the access URL while the workspace keeps running,
+	// e.g. to cut off external access during an incident without interrupting computation.
+	// +kubebuilder:default=true
+	// +optional
+	AccessEnabled *bool `json:"accessEnabled,omitempty"`
+
+	// SSHPublicKeys lists the public keys, in authorized_keys format, allowed to connect to the
+	// workspace over SSH, e.g. with VS Code Remote-SSH.
+	// Only used when the access strategy of the workspace uses the "ssh" provider.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=set
+	// +optional
+	SSHPublicKeys []string `json:"sshPublicKeys,omitempty"`
+
 	// TemplateRef references a WorkspaceTemplate to use as base configuration
 	// When set, template provides defaults and workspace spec fields act as overrides
 	// +optional
 	TemplateRef *TemplateRef `json:"templateRef,omitempty"`
 
+	// CloneFrom creates the workspace as a clone of another workspace: spec fields and labels
+	// the workspace leaves empty are copied from the source when the workspace is created.
+	// It can only be set when the workspace is created.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloneFrom is immutable"
+	// +optional
+	CloneFrom *CloneSource `json:"cloneFrom,omitempty"`
+
 	// IdleShutdown specifies idle shutdown configuration
 	// +optional
 	IdleShutdown *IdleShutdownSpec `json:"idleShutdown,omitempty"`
 
+	// CullingPolicy exempts the workspace from idle shutdown and other automated stops
+	// +optional
+	CullingPolicy *CullingPolicySpec `json:"cullingPolicy,omitempty"`
+
 	// AppType specifies the application type for this workspace
 	// +optional
 	AppType string `json:"appType,omitempty"`
 
+	// ApplicationType is the kind of application the workspace serves, which switches the port the
+	// workspace Service routes to, the path of the default probes and the base URL environment variable
+	// When a template is used, template's ApplicationType is applied if workspace has none
+	// Default: jupyterlab.
+	// +optional
+	ApplicationType ApplicationType `json:"applicationType,omitempty"`
+
 	// ServiceAccountName specifies the name of the ServiceAccount to use for the workspace pod
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// ImagePullSecrets references Secrets in the workspace namespace used to pull the images of
+	// the workspace pod from private registries.
+	// When a template is used, template's ImagePullSecrets are merged
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
 	// PodSecurityContext specifies pod-level security context
 	// Overrides template defaults when specified
 	// +optional
@@ -226,12 +888,49 @@ type WorkspaceSpec struct {
 	// +optional
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
 
+	// SecurityProfile makes the controller build a workspace pod compliant with a Pod Security Standard,
+	// applied over PodSecurityContext, ContainerSecurityContext and the init containers
+	// When a template is used, it is copied from the template and cannot be changed
+	// +optional
+	SecurityProfile *SecurityProfile `json:"securityProfile,omitempty"`
+
+	// NetworkIsolation makes the controller generate a NetworkPolicy that only lets the controller,
+	// the ingress controller and the listed sources reach the workspace pod
+	// When a template is used, it is copied from the template and cannot be changed
+	// +optional
+	NetworkIsolation *NetworkIsolation `json:"networkIsolation,omitempty"`
+
+	// RuntimeClassName is the RuntimeClass of the workspace pod, which selects the container runtime
+	// (for instance a sandboxed runtime such as gVisor or Kata Containers)
+	// When a template is used, it must be allowed by the template
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// GPUSharing defines how the NVIDIA GPUs of the workspace are shared with other pods
+	// When a template is used, its mode must be allowed by the template, and the rest is copied
+	// from the template and cannot be changed
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+
+	// ProvisioningHints tells node autoprovisioners which nodes to provision for the workspace pod
+	// When a template is used, it is copied from the template and cannot be changed
+	// +optional
+	ProvisioningHints *ProvisioningHints `json:"provisioningHints,omitempty"`
+
 	// InitContainers specifies init containers to run before the workspace container starts
 	// When a template is used, template's DefaultInitContainers are applied if workspace has none
 	// Requires AllowCustomInitContainers=true on the template to specify custom init containers
 	// +kubebuilder:validation:MaxItems=10
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// JupyterServerConfig configures the Jupyter server and JupyterLab in the workspace container
+	// through a generated ConfigMap, so that the configuration need not be baked into the image.
+	// Changes restart the workspace pod.
+	// When a template is used, it is copied from the template and cannot be changed
+	// +optional
+	JupyterServerConfig *JupyterServerConfig `json:"jupyterServerConfig,omitempty"`
 }
 
 // AccessResourceStatus defines the status of a resource created from a template
@@ -249,6 +948,392 @@ type AccessResourceStatus struct {
 	Namespace string `json:"namespace"`
 }
 
+// StorageExpansionPhase summarizes the progress of a primary storage expansion
+type StorageExpansionPhase string
+
+const (
+	// StorageExpansionInProgress means the PVC was expanded and the volume is being resized
+	StorageExpansionInProgress StorageExpansionPhase = "InProgress"
+
+	// StorageExpansionFileSystemResizePending means the volume was resized and the file system
+	// resize is waiting for the node
+	StorageExpansionFileSystemResizePending StorageExpansionPhase = "FileSystemResizePending"
+
+	// StorageExpansionCompleted means the PVC capacity reached the requested size
+	StorageExpansionCompleted StorageExpansionPhase = "Completed"
+
+	// StorageExpansionUnsupported means the PVC's StorageClass does not allow volume expansion
+	StorageExpansionUnsupported StorageExpansionPhase = "Unsupported"
+
+	// StorageExpansionFailed means the storage provider reported the resize as infeasible
+	StorageExpansionFailed StorageExpansionPhase = "Failed"
+)
+
+// StorageExpansionStatus tracks the most recent expansion of the workspace's primary storage
+type StorageExpansionStatus struct {
+	// Phase summarizes the progress of the expansion
+	Phase StorageExpansionPhase `json:"phase"`
+
+	// RequestedSize is the storage size the PVC was asked to expand to
+	RequestedSize resource.Quantity `json:"requestedSize"`
+
+	// CurrentCapacity is the capacity currently reported by the PVC
+	// +optional
+	CurrentCapacity *resource.Quantity `json:"currentCapacity,omitempty"`
+
+	// Message is a human-readable explanation of the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CullingStatus reports the culling policy in effect for the workspace
+type CullingStatus struct {
+	// Exempt is true when the workspace is currently protected from automated stops
+	Exempt bool `json:"exempt"`
+
+	// ExemptUntil is the time at which the current exemption expires,
+	// unset when the exemption has no end
+	// +optional
+	ExemptUntil *metav1.Time `json:"exemptUntil,omitempty"`
+
+	// Message is a human-readable explanation of the effective policy
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// EffectiveSpecStatus reports the configuration the workspace pod runs with, once the template
+// defaults, user preferences and access strategy deployment modifications are applied
+type EffectiveSpecStatus struct {
+	// ObservedGeneration is the workspace generation the configuration was resolved from
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Image is the resolved image of the primary container
+	Image string `json:"image"`
+
+	// Command is the command of the primary container
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments of the primary container
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is the environment of the primary container, including the variables merged by the access strategy
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom lists the ConfigMaps and Secrets injected as environment variables into the primary container
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Resources are the resource requirements of the primary container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Profile is the template profile the resource requirements were resolved from
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// VolumeMounts are the volume mounts of the primary container
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Containers lists the names of the pod containers, including the sidecars added by the access strategy
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// InitContainers lists the names of the pod init containers
+	// +optional
+	InitContainers []string `json:"initContainers,omitempty"`
+
+	// ServiceAccountName is the service account of the pod
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// NodeSelector is the node selector of the pod
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the pod
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the pod
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName is the RuntimeClass of the pod
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// ImagePullSecrets are the image pull secrets of the pod, including the operator default
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// WorkspacePodStatus reports the state of the workspace pod, so that users can tell why a
+// workspace does not start without access to its pods
+type WorkspacePodStatus struct {
+	// Name of the pod
+	Name string `json:"name"`
+
+	// Phase of the pod: Pending, Running, Succeeded, Failed or Unknown
+	// +optional
+	Phase corev1.PodPhase `json:"phase,omitempty"`
+
+	// UnschedulableMessage explains why the scheduler cannot place the pod,
+	// e.g. "0/3 nodes are available: 3 Insufficient nvidia.com/gpu."
+	// +optional
+	UnschedulableMessage string `json:"unschedulableMessage,omitempty"`
+
+	// Containers reports the state of the init containers, then of the containers of the pod
+	// +optional
+	Containers []WorkspaceContainerStatus `json:"containers,omitempty"`
+}
+
+// WorkspaceUsageStatus accounts the running time of a workspace and the resources it requested
+// while running, over its lifetime, for chargeback. Resource usage is the request multiplied by
+// the running time, e.g. a workspace requesting 500m CPU for two hours used 3600000 CPU
+// millicore-seconds, or one core-hour.
+type WorkspaceUsageStatus struct {
+	// RunningSeconds is the time the workspace was available, in seconds
+	RunningSeconds int64 `json:"runningSeconds"`
+
+	// CPUMilliCoreSeconds is the CPU the workspace requested while running, in millicore-seconds
+	CPUMilliCoreSeconds int64 `json:"cpuMilliCoreSeconds"`
+
+	// MemoryMiBSeconds is the memory the workspace requested while running, in MiB-seconds
+	MemoryMiBSeconds int64 `json:"memoryMiBSeconds"`
+
+	// GPUSeconds is the NVIDIA GPUs the workspace requested while running, in GPU-seconds
+	GPUSeconds int64 `json:"gpuSeconds"`
+
+	// LastAccountedTime is the time up to which the usage of the running workspace is accounted.
+	// Unset while the workspace does not run.
+	// +optional
+	LastAccountedTime *metav1.Time `json:"lastAccountedTime,omitempty"`
+
+	// Current reports the live resource usage of the running workspace, when resource metrics
+	// collection is enabled on the controller. Unset while the workspace does not run.
+	// +optional
+	Current *WorkspaceResourceUtilization `json:"current,omitempty"`
+
+	// RightSizing tracks the utilization of the requests of the workspace over the right-sizing
+	// window, and the requests recommended from it, when resource metrics collection is enabled
+	// on the controller
+	// +optional
+	RightSizing *WorkspaceRightSizingStatus `json:"rightSizing,omitempty"`
+}
+
+// WorkspaceRightSizingStatus tracks the utilization of the CPU and memory requests of a workspace
+// over windows of running time. A workspace that consistently uses too little or too much of a
+// request over a whole window is recommended a request sized after its peak usage.
+type WorkspaceRightSizingStatus struct {
+	// Requests are the CPU and memory requests of the workspace the window observes. The window
+	// restarts when they change.
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// WindowStartTime is when the current window started
+	WindowStartTime metav1.Time `json:"windowStartTime"`
+
+	// Samples is the number of usage samples in the current window
+	Samples int32 `json:"samples"`
+
+	// CPU is the utilization of the CPU request over the current window
+	// +optional
+	CPU *WorkspaceUtilizationRange `json:"cpu,omitempty"`
+
+	// Memory is the utilization of the memory request over the current window
+	// +optional
+	Memory *WorkspaceUtilizationRange `json:"memory,omitempty"`
+
+	// RecommendedRequests are the requests recommended after the last complete window, for the
+	// resources the workspace consistently used too little or too much of. Unset when the requests
+	// suit the workspace.
+	// +optional
+	RecommendedRequests corev1.ResourceList `json:"recommendedRequests,omitempty"`
+}
+
+// WorkspaceUtilizationRange is the range of the utilization of a resource request over a window
+type WorkspaceUtilizationRange struct {
+	// MinPercent is the lowest utilization of the request, in percent
+	MinPercent int32 `json:"minPercent"`
+
+	// MaxPercent is the highest utilization of the request, in percent
+	MaxPercent int32 `json:"maxPercent"`
+
+	// Peak is the highest usage of the resource
+	Peak resource.Quantity `json:"peak"`
+}
+
+// WorkspaceResourceUtilization reports the live resource usage of the workspace container, sampled
+// from the resource metrics API, e.g. metrics-server, and from Prometheus for GPUs, along with its
+// utilization of the resources the workspace requests
+type WorkspaceResourceUtilization struct {
+	// CPU is the CPU the workspace container uses
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// CPUPercent is CPU as a percentage of the CPU request of the workspace.
+	// Unset without a CPU request.
+	// +optional
+	CPUPercent *int32 `json:"cpuPercent,omitempty"`
+
+	// Memory is the working set memory of the workspace container
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// MemoryPercent is Memory as a percentage of the memory request of the workspace.
+	// Unset without a memory request.
+	// +optional
+	MemoryPercent *int32 `json:"memoryPercent,omitempty"`
+
+	// GPUPercent is the average utilization of the GPUs of the workspace, in percent.
+	// Unset unless the controller queries Prometheus for GPU metrics.
+	// +optional
+	GPUPercent *int32 `json:"gpuPercent,omitempty"`
+
+	// SampleTime is when the usage was sampled
+	SampleTime metav1.Time `json:"sampleTime"`
+}
+
+// WorkspacePoolClaimStatus records the standby pod of a WorkspacePool a workspace claimed when it
+// started. The workspace pod prefers the node of the standby pod, which already pulled its image.
+type WorkspacePoolClaimStatus struct {
+	// PoolName is the name of the WorkspacePool
+	PoolName string `json:"poolName"`
+
+	// PoolNamespace is the namespace of the WorkspacePool
+	PoolNamespace string `json:"poolNamespace"`
+
+	// PodName is the name of the claimed standby pod
+	PodName string `json:"podName"`
+
+	// NodeName is the node the standby pod ran on
+	NodeName string `json:"nodeName"`
+
+	// ClaimTime is when the workspace claimed the standby pod
+	ClaimTime metav1.Time `json:"claimTime"`
+}
+
+// WorkspaceInterruptionStatus records the interruption of the node of a workspace pod, e.g. the
+// reclaim of a spot instance, which made the controller reschedule the workspace on another node
+type WorkspaceInterruptionStatus struct {
+	// NodeName is the name of the interrupted node
+	NodeName string `json:"nodeName"`
+
+	// PodName is the name of the pod the controller deleted from the node
+	PodName string `json:"podName"`
+
+	// Reason is the node taint that signaled the interruption,
+	// e.g. aws-node-termination-handler/spot-itn
+	Reason string `json:"reason"`
+
+	// Time is when the controller rescheduled the workspace
+	Time metav1.Time `json:"time"`
+}
+
+// WorkspaceContainerState is the state of a container of the workspace pod
+// +kubebuilder:validation:Enum=Waiting;Running;Terminated
+type WorkspaceContainerState string
+
+// States of a container of the workspace pod
+const (
+	WorkspaceContainerStateWaiting    WorkspaceContainerState = "Waiting"
+	WorkspaceContainerStateRunning    WorkspaceContainerState = "Running"
+	WorkspaceContainerStateTerminated WorkspaceContainerState = "Terminated"
+)
+
+// WorkspaceContainerStatus reports the state of a container of the workspace pod
+type WorkspaceContainerStatus struct {
+	// Name of the container
+	Name string `json:"name"`
+
+	// Init is true for init containers
+	// +optional
+	Init bool `json:"init,omitempty"`
+
+	// Ready is true when the container passes its readiness probe
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// State of the container
+	// +optional
+	State WorkspaceContainerState `json:"state,omitempty"`
+
+	// Reason of a waiting or terminated container, e.g. ImagePullBackOff, CrashLoopBackOff or OOMKilled
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message of a waiting or terminated container, e.g. the error pulling its image
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// RestartCount is the number of times the container restarted
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+
+	// LastTerminationReason is the reason the previous instance of the container terminated,
+	// e.g. OOMKilled or Error
+	// +optional
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+
+	// LastTerminationExitCode is the exit code of the previous instance of the container
+	// +optional
+	LastTerminationExitCode *int32 `json:"lastTerminationExitCode,omitempty"`
+}
+
+// WorkspaceStartupStatus tracks the current startup of the workspace, against its startup deadline
+// and failure policy
+type WorkspaceStartupStatus struct {
+	// StartTime is when the workspace started, or when its spec last changed while it was starting
+	StartTime metav1.Time `json:"startTime"`
+
+	// ObservedGeneration is the workspace generation the startup began with
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Retries is the highest restart count of a container of the workspace pod during this startup
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+}
+
+// WorkspaceApplicationStatus reports an additional application of a running workspace, declared
+// in spec.ports
+type WorkspaceApplicationStatus struct {
+	// Name of the port of the application
+	Name string `json:"name"`
+
+	// URL is the access URL of the workspace joined with the subpath routed to the application,
+	// empty while the workspace has no access URL
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Ready tells whether the application answered its last probe on its healthPath with a success
+	// status. Unset when the port declares no healthPath.
+	// +optional
+	Ready *bool `json:"ready,omitempty"`
+}
+
+// VanityURLStatus reports the vanity URL registered for the workspace with the external registrar
+type VanityURLStatus struct {
+	// URL is the friendly URL returned by the registrar
+	URL string `json:"url"`
+
+	// AccessURL is the access URL the vanity URL was registered for
+	AccessURL string `json:"accessURL"`
+}
+
+// RemoteAccessStatus reports the remote access a pod events handler set up for the workspace pod
+type RemoteAccessStatus struct {
+	// PodUID is the UID of the workspace pod the remote access was set up for
+	PodUID string `json:"podUID"`
+
+	// SSMInstanceID is the ID of the SSM managed node registered for the pod (e.g. mi-0123456789abcdef0)
+	// +optional
+	SSMInstanceID string `json:"ssmInstanceId,omitempty"`
+}
+
 // WorkspaceStatus defines the observed state of Workspace.
 type WorkspaceStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -269,6 +1354,11 @@ type WorkspaceStatus struct {
 	// +optional
 	AccessURL string `json:"accessURL,omitempty"`
 
+	// VanityURL reports the friendly URL registered for the access URL with the vanity URL
+	// webhook of the controller, if any. Cleared once the workspace stops or loses its access URL.
+	// +optional
+	VanityURL *VanityURLStatus `json:"vanityURL,omitempty"`
+
 	// ApplicationBasePath is the resolved routing prefix for the workspace application.
 	// Set during access-resources reconciliation; used by idle detection to construct
 	// the full endpoint path.
@@ -291,6 +1381,12 @@ type WorkspaceStatus struct {
 	// +optional
 	ObservedAccessStrategyVersion string `json:"observedAccessStrategyVersion,omitempty"`
 
+	// AppliedAccessStrategyVersion captures, in the format of ObservedAccessStrategyVersion, the
+	// AccessStrategy the access resources and access URL of the workspace were last rendered from.
+	// The AccessStrategy controller compares it to report the propagation of AccessStrategy changes.
+	// +optional
+	AppliedAccessStrategyVersion string `json:"appliedAccessStrategyVersion,omitempty"`
+
 	// AccessStartupProbeSucceeded indicates whether the access startup probe
 	// has passed. Set to true when the probe succeeds; reset to false when
 	// the workspace stops.
@@ -309,6 +1405,59 @@ type WorkspaceStatus struct {
 	// +optional
 	EarliestNextProbeTime *metav1.Time `json:"earliestNextProbeTime,omitempty"`
 
+	// StorageExpansion tracks the progress of the most recent primary storage expansion,
+	// triggered by increasing spec.storage.size on an existing workspace
+	// +optional
+	StorageExpansion *StorageExpansionStatus `json:"storageExpansion,omitempty"`
+
+	// Culling reports the effective culling policy derived from spec.cullingPolicy
+	// +optional
+	Culling *CullingStatus `json:"culling,omitempty"`
+
+	// EffectiveSpec reports the fully-resolved configuration of the workspace pod, as applied to
+	// its Deployment. Cleared once the workspace stops.
+	// +optional
+	EffectiveSpec *EffectiveSpecStatus `json:"effectiveSpec,omitempty"`
+
+	// PodStatus reports the container states, restarts and scheduling failures of the workspace pod,
+	// the most recently created one during a rollout. Cleared once the workspace stops.
+	// +optional
+	PodStatus *WorkspacePodStatus `json:"podStatus,omitempty"`
+
+	// Applications reports the URL and readiness of the additional applications of the workspace,
+	// declared in spec.ports. Cleared once the workspace stops.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Applications []WorkspaceApplicationStatus `json:"applications,omitempty"`
+
+	// LastInterruption records the last interruption of the node of the workspace pod, which made
+	// the controller reschedule the workspace on another node
+	// +optional
+	LastInterruption *WorkspaceInterruptionStatus `json:"lastInterruption,omitempty"`
+
+	// Usage accounts the running time and resource requests of the workspace, when usage
+	// accounting is enabled on the controller, and reports its live resource usage and right-sizing
+	// recommendations, when resource metrics collection is enabled
+	// +optional
+	Usage *WorkspaceUsageStatus `json:"usage,omitempty"`
+
+	// PoolClaim records the standby pod of a WorkspacePool the workspace claimed when it last
+	// started, if any
+	// +optional
+	PoolClaim *WorkspacePoolClaimStatus `json:"poolClaim,omitempty"`
+
+	// Startup tracks the startup of a workspace that is not available yet, against
+	// spec.startupDeadlineSeconds and spec.failurePolicy. Cleared once the workspace runs or stops.
+	// +optional
+	Startup *WorkspaceStartupStatus `json:"startup,omitempty"`
+
+	// RemoteAccess reports the remote access set up for the current workspace pod by the
+	// podEventsHandler of its AccessStrategy. Cleared once the pod is deleted and its
+	// remote access resources are released.
+	// +optional
+	RemoteAccess *RemoteAccessStatus `json:"remoteAccess,omitempty"`
+
 	// Conditions represent the current state of the Workspace resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
@@ -335,6 +1484,8 @@ type WorkspaceStatus struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="CreatedBy",type="string",JSONPath=`.metadata.annotations['workspace\.jupyter\.org/created-by']`,priority=1
 // +kubebuilder:printcolumn:name="AccessType",type="string",JSONPath=".spec.accessType",priority=1
+// +kubebuilder:selectablefield:JSONPath=".spec.templateRef.name"
+// +kubebuilder:selectablefield:JSONPath=".spec.accessStrategy.name"
 
 // Workspace is the Schema for the workspaces API
 type Workspace struct {
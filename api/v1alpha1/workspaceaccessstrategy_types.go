@@ -11,6 +11,8 @@ import (
 )
 
 // AccessResourceTemplate defines a template for creating Kubernetes resources
+// +kubebuilder:validation:XValidation:rule="!has(self.generate) || self.kind == 'Secret'",message="generate is only supported for templates of kind Secret"
+// +kubebuilder:validation:XValidation:rule="!has(self.generate) || self.generate.all(g, g.type != 'Htpasswd' || self.generate.exists(b, b.key == g.from && b.type == 'BasicAuth'))",message="Htpasswd generators must reference the key of a BasicAuth generator of the same template"
 type AccessResourceTemplate struct {
 	// Kind of the Kubernetes resource to create
 	Kind string `json:"kind"`
@@ -25,6 +27,56 @@ type AccessResourceTemplate struct {
 	// Template is a YAML template string for the resource
 	// Template variables include Workspace, AccessStrategy and Service objects
 	Template string `json:"template"`
+
+	// Generate adds generated values to the data of a Secret, such as per-workspace credentials.
+	// Values are generated once per workspace and kept by the controller, so that re-rendering
+	// the template does not change them.
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	// +kubebuilder:validation:MaxItems=16
+	Generate []SecretGenerator `json:"generate,omitempty"`
+}
+
+// SecretGeneratorType is the type of a value generated for a Secret
+// +kubebuilder:validation:Enum=Token;BasicAuth;Htpasswd
+type SecretGeneratorType string
+
+const (
+	// SecretGeneratorToken generates a random token
+	SecretGeneratorToken SecretGeneratorType = "Token"
+	// SecretGeneratorBasicAuth generates a random password, stored as a username:password pair
+	SecretGeneratorBasicAuth SecretGeneratorType = "BasicAuth"
+	// SecretGeneratorHtpasswd stores the htpasswd entry of a BasicAuth pair, with a bcrypt hash
+	SecretGeneratorHtpasswd SecretGeneratorType = "Htpasswd"
+)
+
+// SecretGenerator defines a value generated for a key of a Secret
+// +kubebuilder:validation:XValidation:rule="self.type != 'Htpasswd' || has(self.from)",message="from is required for Htpasswd generators"
+type SecretGenerator struct {
+	// Key of the Secret data holding the generated value
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Key string `json:"key"`
+
+	// Type of the generated value: Token, BasicAuth or Htpasswd
+	Type SecretGeneratorType `json:"type"`
+
+	// Length of the generated token or password, defaults to 32 characters
+	// +optional
+	// +kubebuilder:validation:Minimum=16
+	// +kubebuilder:validation:Maximum=128
+	Length int32 `json:"length,omitempty"`
+
+	// Username of a BasicAuth pair, defaults to the workspace name
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[^:]+$`
+	Username string `json:"username,omitempty"`
+
+	// From is the key of the BasicAuth generator whose pair a Htpasswd entry hashes
+	// +optional
+	From string `json:"from,omitempty"`
 }
 
 // AccessEnvTemplate defines a template for environment variables
@@ -110,7 +162,10 @@ type AccessHTTPGetProbe struct {
 	// URLTemplate is a Go text/template resolving to the URL to probe.
 	// Available variables: .Workspace, .AccessStrategy, .Service
 	// (same as accessURLTemplate and accessResourceTemplates).
-	URLTemplate string `json:"urlTemplate"`
+	// When empty, the controller probes the path of the workspace access URL
+	// through the workspace Service, e.g. http://<service>.<namespace>.svc:<port>/<path>.
+	// +optional
+	URLTemplate string `json:"urlTemplate,omitempty"`
 
 	// AdditionalSuccessStatusCodes extends the default success range (200–399)
 	// with extra HTTP status codes that indicate the route is live.
@@ -120,11 +175,100 @@ type AccessHTTPGetProbe struct {
 	AdditionalSuccessStatusCodes []int `json:"additionalSuccessStatusCodes,omitempty"`
 }
 
+// SSHAccess configures the "ssh" access provider, which exposes an SSH server running
+// in the workspace pod and authorizes the keys listed in the workspace spec.sshPublicKeys.
+type SSHAccess struct {
+	// ServiceType of the Service exposing the SSH server, LoadBalancer or NodePort.
+	// Default: LoadBalancer.
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServiceAnnotations are added to the Service exposing the SSH server,
+	// e.g. to configure a cloud load balancer.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// SidecarImage is the image of a sidecar container running the SSH server.
+	// The sidecar mounts the volumes of the workspace container.
+	// When empty, the workspace image must run the SSH server itself.
+	// +optional
+	SidecarImage string `json:"sidecarImage,omitempty"`
+
+	// Port on which the SSH server listens in the workspace pod. Default: 2222.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// TraefikAccess configures the "traefik" access provider. The options apply to the services of the
+// rendered IngressRoutes that route to the workspace Service. Traefik forwards websocket upgrades
+// without configuration; the connections of idle kernels are bounded by the respondingTimeouts
+// of the Traefik entrypoint, which are not set per route.
+type TraefikAccess struct {
+	// StickySession pins the requests of a client to the same workspace pod with a cookie
+	// +optional
+	StickySession *TraefikStickySession `json:"stickySession,omitempty"`
+
+	// Timeouts of the connections from Traefik to the workspace pod
+	// +optional
+	Timeouts *TraefikTimeouts `json:"timeouts,omitempty"`
+}
+
+// TraefikStickySession configures the affinity cookie of the workspace services
+type TraefikStickySession struct {
+	// CookieName is the name of the affinity cookie. Default: a name generated by Traefik.
+	// +optional
+	CookieName string `json:"cookieName,omitempty"`
+
+	// Secure restricts the cookie to HTTPS requests
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+
+	// HTTPOnly hides the cookie from JavaScript
+	// +optional
+	HTTPOnly bool `json:"httpOnly,omitempty"`
+
+	// SameSite is the SameSite attribute of the cookie
+	// +kubebuilder:validation:Enum=none;lax;strict
+	// +optional
+	SameSite string `json:"sameSite,omitempty"`
+
+	// MaxAge is the lifetime of the cookie in seconds. Default: the cookie expires with the browser session.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAge int32 `json:"maxAge,omitempty"`
+}
+
+// TraefikTimeouts configures the forwarding timeouts of the Traefik ServersTransport of a workspace
+type TraefikTimeouts struct {
+	// DialTimeout is the time allowed to open a connection to the workspace pod. Traefik default: 30s.
+	// +optional
+	DialTimeout *metav1.Duration `json:"dialTimeout,omitempty"`
+
+	// ResponseHeaderTimeout is the time allowed for the workspace pod to send the response headers
+	// of a request. Traefik default: no timeout.
+	// +optional
+	ResponseHeaderTimeout *metav1.Duration `json:"responseHeaderTimeout,omitempty"`
+
+	// IdleConnTimeout is the time an idle keep-alive connection to the workspace pod is kept open.
+	// Traefik default: 90s.
+	// +optional
+	IdleConnTimeout *metav1.Duration `json:"idleConnTimeout,omitempty"`
+}
+
 // WorkspaceAccessStrategySpec defines the desired state of WorkspaceAccessStrategy
 type WorkspaceAccessStrategySpec struct {
 	// DisplayName is a human-readable name for this access strategy
 	DisplayName string `json:"displayName"`
 
+	// Provider selects the access provider that builds the access resources and the access URL
+	// Built-in providers are "template", "traefik", "ingress", "gateway-api", "istio" and "ssh"
+	// When omitted, the "template" provider renders AccessResourceTemplates as-is
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
 	// AccessResourceTemplates defines templates for resources created in the routes namespace
 	AccessResourceTemplates []AccessResourceTemplate `json:"accessResourceTemplates"`
 
@@ -183,6 +327,14 @@ type WorkspaceAccessStrategySpec struct {
 	// exist in the API server.
 	// +optional
 	AccessStartupProbe *AccessStartupProbe `json:"accessStartupProbe,omitempty"`
+
+	// SSH configures the "ssh" provider. Ignored by the other providers.
+	// +optional
+	SSH *SSHAccess `json:"ssh,omitempty"`
+
+	// Traefik configures the "traefik" provider. Ignored by the other providers.
+	// +optional
+	Traefik *TraefikAccess `json:"traefik,omitempty"`
 }
 
 // WorkspaceAccessStrategyStatus defines the observed state of WorkspaceAccessStrategy
@@ -194,10 +346,30 @@ type WorkspaceAccessStrategyStatus struct {
 	// +patchMergeKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the generation of the spec whose propagation is reported
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Workspaces is the number of workspaces that render the AccessStrategy: those referencing it
+	// that are desired to be running with access enabled
+	// +optional
+	Workspaces int32 `json:"workspaces"`
+
+	// UpdatedWorkspaces is the number of those workspaces whose access resources and access URL
+	// are rendered from the observed generation
+	// +optional
+	UpdatedWorkspaces int32 `json:"updatedWorkspaces"`
+
+	// Message reports the propagation of the observed generation, e.g. "120 of 3000 workspaces updated"
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updatedWorkspaces"
+// +kubebuilder:printcolumn:name="Workspaces",type="integer",JSONPath=".status.workspaces"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // WorkspaceAccessStrategy is the Schema for the workspaceaccessstrategies API
@@ -0,0 +1,243 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageMapping maps an image used by workspaces to the image that replaces it
+type ImageMapping struct {
+	// From is the image to replace, matched exactly against the workspace image
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// To is the image workspaces using From are updated to
+	// +kubebuilder:validation:MinLength=1
+	To string `json:"to"`
+}
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// MaintenanceWindow is a recurring UTC time window during which workspaces may be updated
+type MaintenanceWindow struct {
+	// Start is the UTC time of day at which the window opens, in HH:MM format
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open (e.g. "4h")
+	Duration metav1.Duration `json:"duration"`
+
+	// Days are the days of the week on which the window opens. The window opens every day when empty.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+}
+
+// ImageRolloutPolicy controls the pace of a rollout
+type ImageRolloutPolicy struct {
+	// MaxUnavailable is the maximum number of running workspaces restarted with their new image
+	// at the same time. Stopped workspaces are not restarted by the update and do not count.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MaxUnavailable int32 `json:"maxUnavailable,omitempty"`
+
+	// MaintenanceWindow restricts when updates start. In-flight updates complete after the window closes.
+	// Updates start at any time when omitted.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// OnlyStopped restricts the updates to stopped workspaces, so that no running workspace is
+	// restarted: running workspaces are updated once they stop. Stopped workspaces are always
+	// updated first; unset OnlyStopped to then restart the remaining running workspaces.
+	// +optional
+	OnlyStopped bool `json:"onlyStopped,omitempty"`
+
+	// ProgressDeadline is how long a running workspace may take to become available with its
+	// new image before its update is counted as failed
+	// +kubebuilder:default="10m"
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+// WorkspaceImageRolloutSpec defines the desired state of WorkspaceImageRollout
+type WorkspaceImageRolloutSpec struct {
+	// Images maps the images to replace to their new images
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=from
+	Images []ImageMapping `json:"images"`
+
+	// Namespaces restricts the rollout to workspaces in these namespaces.
+	// Workspaces in all watched namespaces are updated when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector restricts the rollout to workspaces matching these labels
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Policy controls the pace of the rollout
+	// +optional
+	Policy ImageRolloutPolicy `json:"policy,omitempty"`
+
+	// Paused stops the rollout from starting new updates, or rollbacks, until unset.
+	// In-flight updates complete.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Rollback reverts the workspaces updated by the rollout to their previous image,
+	// at the pace set by maxUnavailable. Maintenance windows do not apply to rollbacks.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// ImageRolloutPhase is a high-level summary of where the rollout is in its lifecycle
+type ImageRolloutPhase string
+
+const (
+	// ImageRolloutPhaseProgressing means workspaces are being updated
+	ImageRolloutPhaseProgressing ImageRolloutPhase = "Progressing"
+
+	// ImageRolloutPhaseWaiting means workspaces remain to be updated but the maintenance window is closed
+	ImageRolloutPhaseWaiting ImageRolloutPhase = "Waiting"
+
+	// ImageRolloutPhasePaused means the rollout is paused
+	ImageRolloutPhasePaused ImageRolloutPhase = "Paused"
+
+	// ImageRolloutPhaseCompleted means no matching workspace remains to be updated
+	ImageRolloutPhaseCompleted ImageRolloutPhase = "Completed"
+
+	// ImageRolloutPhaseRollingBack means updated workspaces are being reverted to their previous image
+	ImageRolloutPhaseRollingBack ImageRolloutPhase = "RollingBack"
+
+	// ImageRolloutPhaseRolledBack means all the updated workspaces were reverted to their previous image
+	ImageRolloutPhaseRolledBack ImageRolloutPhase = "RolledBack"
+)
+
+// ImageRolloutWorkspaceState is the state of the update of one workspace
+type ImageRolloutWorkspaceState string
+
+const (
+	// ImageRolloutWorkspaceUpdating means the workspace image was changed and the workspace is restarting
+	ImageRolloutWorkspaceUpdating ImageRolloutWorkspaceState = "Updating"
+
+	// ImageRolloutWorkspaceUpdated means the workspace runs, or will start, with its new image
+	ImageRolloutWorkspaceUpdated ImageRolloutWorkspaceState = "Updated"
+
+	// ImageRolloutWorkspaceFailed means the update was rejected or the workspace did not become
+	// available with its new image. Failed workspaces are not retried.
+	ImageRolloutWorkspaceFailed ImageRolloutWorkspaceState = "Failed"
+
+	// ImageRolloutWorkspaceRollingBack means the workspace image was reverted and the workspace is restarting
+	ImageRolloutWorkspaceRollingBack ImageRolloutWorkspaceState = "RollingBack"
+
+	// ImageRolloutWorkspaceRolledBack means the workspace runs, or will start, with its previous image
+	ImageRolloutWorkspaceRolledBack ImageRolloutWorkspaceState = "RolledBack"
+)
+
+// ImageRolloutWorkspaceStatus reports the update of one workspace
+type ImageRolloutWorkspaceStatus struct {
+	// Namespace of the workspace
+	Namespace string `json:"namespace"`
+
+	// Name of the workspace
+	Name string `json:"name"`
+
+	// FromImage is the image of the workspace before the rollout
+	FromImage string `json:"fromImage"`
+
+	// ToImage is the image the rollout updated the workspace to
+	ToImage string `json:"toImage"`
+
+	// State is the state of the update
+	State ImageRolloutWorkspaceState `json:"state"`
+
+	// LastTransitionTime is when the state last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Message is a human-readable explanation of the state
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkspaceImageRolloutStatus defines the observed state of WorkspaceImageRollout
+type WorkspaceImageRolloutStatus struct {
+	// Phase is a high-level summary of the rollout state
+	// +optional
+	Phase ImageRolloutPhase `json:"phase,omitempty"`
+
+	// Pending is the number of matching workspaces that remain to be updated
+	// +optional
+	Pending int32 `json:"pending"`
+
+	// Updating is the number of workspaces being updated or rolled back
+	// +optional
+	Updating int32 `json:"updating"`
+
+	// Succeeded is the number of workspaces updated to their new image
+	// +optional
+	Succeeded int32 `json:"succeeded"`
+
+	// Failed is the number of workspaces whose update failed
+	// +optional
+	Failed int32 `json:"failed"`
+
+	// RolledBack is the number of workspaces reverted to their previous image
+	// +optional
+	RolledBack int32 `json:"rolledBack"`
+
+	// Workspaces reports the workspaces updated by the rollout
+	// +optional
+	Workspaces []ImageRolloutWorkspaceStatus `json:"workspaces,omitempty"`
+
+	// Message is a human-readable explanation of the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last acted upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pending"
+// +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeeded"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkspaceImageRollout is the Schema for the workspaceimagerollouts API
+// It progressively updates the image of the workspaces across the cluster that use one of
+// the images it replaces, e.g. to roll out a patched image fleet-wide
+type WorkspaceImageRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of WorkspaceImageRollout
+	Spec WorkspaceImageRolloutSpec `json:"spec"`
+
+	// Status defines the observed state of WorkspaceImageRollout
+	// +optional
+	Status WorkspaceImageRolloutStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceImageRolloutList contains a list of WorkspaceImageRollout
+type WorkspaceImageRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceImageRollout `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceImageRollout{}, &WorkspaceImageRolloutList{})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspacePoolSpec defines the desired state of WorkspacePool
+type WorkspacePoolSpec struct {
+	// TemplateRef references the WorkspaceTemplate whose workspaces the pool serves.
+	// When its namespace is omitted, the template is looked up in the namespace of the pool.
+	TemplateRef TemplateRef `json:"templateRef"`
+
+	// Size is the number of standby pods the pool keeps running
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Size int32 `json:"size"`
+
+	// Image of the standby pods, which must be the image of the workspaces for them to claim
+	// a standby pod. When omitted, the default image of the template is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// PriorityClassName of the standby pods. A priority lower than the one of workspaces lets
+	// the scheduler preempt standby pods when the cluster runs out of capacity.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// WorkspacePoolStatus defines the observed state of WorkspacePool
+type WorkspacePoolStatus struct {
+	// ObservedGeneration is the generation of the pool the status reflects
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Image is the resolved image of the standby pods
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Ready is the number of standby pods ready to be claimed
+	// +optional
+	Ready int32 `json:"ready"`
+
+	// Starting is the number of standby pods pulling their image or starting
+	// +optional
+	Starting int32 `json:"starting"`
+
+	// Claims is the number of standby pods claimed by workspaces since the pool was created
+	// +optional
+	Claims int64 `json:"claims,omitempty"`
+
+	// LastClaimTime is when a workspace last claimed a standby pod of the pool
+	// +optional
+	LastClaimTime *metav1.Time `json:"lastClaimTime,omitempty"`
+
+	// Conditions represent the latest available observations of the resource's state
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.templateRef.name"
+// +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".spec.size"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Claims",type="integer",JSONPath=".status.claims"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkspacePool is the Schema for the workspacepools API. It keeps warm standby pods running the
+// image of a template, so that workspaces of the template start on a node that already pulled it.
+type WorkspacePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of WorkspacePool
+	Spec WorkspacePoolSpec `json:"spec"`
+
+	// Status defines the observed state of WorkspacePool
+	// +optional
+	Status WorkspacePoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspacePoolList contains a list of WorkspacePool
+type WorkspacePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspacePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspacePool{}, &WorkspacePoolList{})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuotaScope selects who a WorkspaceQuota limits
+// +kubebuilder:validation:Enum=Namespace;User
+type QuotaScope string
+
+const (
+	// QuotaScopeNamespace applies the limits to the sum of all workspaces in the namespace
+	QuotaScopeNamespace QuotaScope = "Namespace"
+
+	// QuotaScopeUser applies the limits separately to the workspaces created by each user in the namespace
+	QuotaScopeUser QuotaScope = "User"
+)
+
+// ResourceStorage is the WorkspaceQuota resource name for the aggregate primary storage size of workspaces
+const ResourceStorage corev1.ResourceName = "storage"
+
+// WorkspaceQuotaSpec defines the desired state of WorkspaceQuota
+type WorkspaceQuotaSpec struct {
+	// Scope selects whether the limits apply to the namespace as a whole or to each user
+	// Users are identified by the workspace's created-by annotation
+	// +kubebuilder:default=Namespace
+	// +optional
+	Scope QuotaScope `json:"scope,omitempty"`
+
+	// MaxWorkspaces is the maximum number of workspaces, running or stopped
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxWorkspaces *int32 `json:"maxWorkspaces,omitempty"`
+
+	// MaxRunningWorkspaces is the maximum number of workspaces with desiredStatus Running
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRunningWorkspaces *int32 `json:"maxRunningWorkspaces,omitempty"`
+
+	// Resources caps the aggregate resources of the workspaces.
+	// Compute resources (e.g. cpu, memory, nvidia.com/gpu) are summed over running workspaces,
+	// using each workspace's request, or its limit when no request is set.
+	// The "storage" resource is summed over the primary storage size of all workspaces.
+	// +optional
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+}
+
+// WorkspaceQuotaUsage is the amount of quota consumed
+type WorkspaceQuotaUsage struct {
+	// Workspaces is the number of workspaces
+	Workspaces int32 `json:"workspaces"`
+
+	// RunningWorkspaces is the number of workspaces with desiredStatus Running
+	RunningWorkspaces int32 `json:"runningWorkspaces"`
+
+	// Resources is the aggregate of the resources limited by the quota
+	// +optional
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+}
+
+// UserQuotaUsage is the quota consumed by the workspaces of one user
+type UserQuotaUsage struct {
+	// User is the username from the workspace's created-by annotation
+	User string `json:"user"`
+
+	// Used is the quota consumed by the user's workspaces
+	Used WorkspaceQuotaUsage `json:"used"`
+}
+
+// WorkspaceQuotaStatus defines the observed state of WorkspaceQuota
+type WorkspaceQuotaStatus struct {
+	// ObservedGeneration is the generation of the spec the usage was last computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Used is the quota consumed by all workspaces in the namespace
+	// +optional
+	Used *WorkspaceQuotaUsage `json:"used,omitempty"`
+
+	// Users is the quota consumed by each user, reported for User-scoped quotas
+	// +listType=map
+	// +listMapKey=user
+	// +optional
+	Users []UserQuotaUsage `json:"users,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Scope",type="string",JSONPath=".spec.scope"
+// +kubebuilder:printcolumn:name="Workspaces",type="integer",JSONPath=".status.used.workspaces"
+// +kubebuilder:printcolumn:name="Running",type="integer",JSONPath=".status.used.runningWorkspaces"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkspaceQuota is the Schema for the workspacequotas API
+// It caps the number of workspaces and their aggregate resources in its namespace,
+// either for the namespace as a whole or for each user
+type WorkspaceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of WorkspaceQuota
+	Spec WorkspaceQuotaSpec `json:"spec"`
+
+	// Status defines the observed state of WorkspaceQuota
+	// +optional
+	Status WorkspaceQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceQuotaList contains a list of WorkspaceQuota
+type WorkspaceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceQuota{}, &WorkspaceQuotaList{})
+}
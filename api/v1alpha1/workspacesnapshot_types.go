@@ -0,0 +1,124 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotTrigger controls when the snapshot of the workspace storage is taken
+// +kubebuilder:validation:Enum=OnDemand;OnStop
+type SnapshotTrigger string
+
+const (
+	// SnapshotTriggerOnDemand takes the snapshot as soon as the WorkspaceSnapshot is created
+	SnapshotTriggerOnDemand SnapshotTrigger = "OnDemand"
+
+	// SnapshotTriggerOnStop waits for the workspace to be stopped before taking the snapshot,
+	// which guarantees the volume is not being written to
+	SnapshotTriggerOnStop SnapshotTrigger = "OnStop"
+)
+
+// SnapshotPhase is a high-level summary of where the snapshot is in its lifecycle
+type SnapshotPhase string
+
+const (
+	// SnapshotPhasePending means the snapshot has not been requested from the storage provider yet
+	SnapshotPhasePending SnapshotPhase = "Pending"
+
+	// SnapshotPhaseInProgress means the VolumeSnapshot exists but is not ready to use
+	SnapshotPhaseInProgress SnapshotPhase = "InProgress"
+
+	// SnapshotPhaseReady means the VolumeSnapshot is ready and can be used to restore a workspace
+	SnapshotPhaseReady SnapshotPhase = "Ready"
+
+	// SnapshotPhaseFailed means the storage provider reported an error for the VolumeSnapshot
+	SnapshotPhaseFailed SnapshotPhase = "Failed"
+)
+
+// WorkspaceSnapshotSpec defines the desired state of WorkspaceSnapshot
+type WorkspaceSnapshotSpec struct {
+	// WorkspaceName is the name of the workspace, in the same namespace, whose primary storage is snapshotted
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="workspaceName is immutable"
+	WorkspaceName string `json:"workspaceName"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass to use for the snapshot
+	// When omitted, the cluster default VolumeSnapshotClass is used
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="volumeSnapshotClassName is immutable"
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Trigger controls when the snapshot is taken
+	// +kubebuilder:default=OnDemand
+	// +optional
+	Trigger SnapshotTrigger `json:"trigger,omitempty"`
+}
+
+// WorkspaceSnapshotStatus defines the observed state of WorkspaceSnapshot
+type WorkspaceSnapshotStatus struct {
+	// Phase is a high-level summary of the snapshot state
+	// +optional
+	Phase SnapshotPhase `json:"phase,omitempty"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot created for this snapshot
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// SourcePVCName is the name of the PersistentVolumeClaim that was snapshotted
+	// +optional
+	SourcePVCName string `json:"sourcePVCName,omitempty"`
+
+	// RestoreSize is the minimum size of a volume restored from this snapshot
+	// +optional
+	RestoreSize *resource.Quantity `json:"restoreSize,omitempty"`
+
+	// Message is a human-readable explanation of the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Conditions represent the latest available observations of the resource's state
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".spec.workspaceName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="RestoreSize",type="string",JSONPath=".status.restoreSize"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkspaceSnapshot is the Schema for the workspacesnapshots API
+type WorkspaceSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of WorkspaceSnapshot
+	Spec WorkspaceSnapshotSpec `json:"spec"`
+
+	// Status defines the observed state of WorkspaceSnapshot
+	// +optional
+	Status WorkspaceSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceSnapshotList contains a list of WorkspaceSnapshot
+type WorkspaceSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceSnapshot{}, &WorkspaceSnapshotList{})
+}
@@ -11,6 +11,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// TemplateUpdateStrategy defines how changes of the default image of a template reach the
+// workspaces using it
+// +kubebuilder:validation:Enum=Never;OnStop;Immediate
+type TemplateUpdateStrategy string
+
+const (
+	// TemplateUpdateStrategyNever keeps the image of existing workspaces
+	TemplateUpdateStrategyNever TemplateUpdateStrategy = "Never"
+	// TemplateUpdateStrategyOnStop updates the image of workspaces that are not running,
+	// so that running workspaces get the new image the next time they stop
+	TemplateUpdateStrategyOnStop TemplateUpdateStrategy = "OnStop"
+	// TemplateUpdateStrategyImmediate updates the image of all workspaces, restarting the running ones
+	TemplateUpdateStrategyImmediate TemplateUpdateStrategy = "Immediate"
+)
+
 // WorkspaceTemplateSpec defines the desired state of WorkspaceTemplate
 type WorkspaceTemplateSpec struct {
 	// DisplayName is the human-readable name of this template
@@ -31,18 +46,38 @@ type WorkspaceTemplateSpec struct {
 	DefaultImage string `json:"defaultImage"`
 
 	// AllowedImages is a list of container images that can be used with this template
-	// If empty, only DefaultImage is allowed (secure by default)
-	// If populated, workspace can override image with any from this list
+	// If empty and AllowedRegistries is empty, only DefaultImage is allowed (secure by default)
+	// If populated, workspace can override image with any matching this list
+	// An entry is an exact image, a glob where * matches any characters (e.g. ghcr.io/org/*, *:2025.*),
+	// or a regular expression when it starts with ^ and ends with $
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
 	AllowedImages []string `json:"allowedImages,omitempty"`
 
-	// AllowCustomImages allows workspaces to use any container image, bypassing the AllowedImages restriction
+	// AllowedRegistries is a list of registry prefixes (e.g. ghcr.io/org, docker.io) whose images
+	// workspaces can use with this template, in addition to AllowedImages
+	// A prefix matches at a path boundary, and images without a registry are on docker.io
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=253
+	// +kubebuilder:validation:items:Pattern=`^[^*/][^*]*[^*/]$|^[^*/]$`
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// AllowCustomImages allows workspaces to use any container image, bypassing the AllowedImages
+	// and AllowedRegistries restrictions
 	// When true, workspaces can specify any image regardless of the AllowedImages list
 	// +kubebuilder:default=false
 	// +optional
 	AllowCustomImages *bool `json:"allowCustomImages,omitempty"`
 
+	// UpdateStrategy controls how changes of DefaultImage reach the workspaces that got their image
+	// from it: Immediate updates them all, restarting the running ones, OnStop updates stopped
+	// workspaces and running ones once they stop, and Never keeps their image.
+	// Default: Never.
+	// +optional
+	UpdateStrategy TemplateUpdateStrategy `json:"updateStrategy,omitempty"`
+
 	// DefaultResources specifies the default resource requirements
 	// +optional
 	DefaultResources *corev1.ResourceRequirements `json:"defaultResources,omitempty"`
@@ -51,6 +86,19 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	ResourceBounds *ResourceBounds `json:"resourceBounds,omitempty"`
 
+	// Profiles are named sizes (e.g. small, medium, large, gpu) that workspaces using this template
+	// select with spec.profile instead of setting raw resources
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Profiles []ResourceProfile `json:"profiles,omitempty"`
+
+	// DefaultProfile is the profile of workspaces that specify neither a profile nor resources
+	// It takes precedence over DefaultResources and must name one of Profiles
+	// +optional
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+
 	// PrimaryStorage defines storage configuration
 	// +optional
 	PrimaryStorage *StorageConfig `json:"primaryStorage,omitempty"`
@@ -59,12 +107,30 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultContainerConfig *ContainerConfig `json:"defaultContainerConfig,omitempty"`
 
+	// DefaultPorts specifies the additional named ports of workspaces using this template
+	// Applied during defaulting if the workspace does not specify any ports
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:XValidation:rule="!self.exists(p, p.name == 'http' || p.name == 'ssh')",message="port names 'http' and 'ssh' are reserved"
+	// +kubebuilder:validation:XValidation:rule="!self.exists(p, p.port == 8888)",message="port 8888 is reserved for the main application"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, self.exists_one(q, q.port == p.port))",message="ports must be unique"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DefaultPorts []WorkspacePort `json:"defaultPorts,omitempty"`
+
 	// BaseEnv specifies environment variables to add to workspaces using this template
 	// Variables are added during defaulting if no variable with the same name exists on the workspace
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
 	BaseEnv []corev1.EnvVar `json:"baseEnv,omitempty"`
 
+	// BaseEnvFrom specifies ConfigMaps and Secrets whose keys are injected as environment variables
+	// into workspaces using this template.
+	// Sources are added during defaulting if the workspace does not already reference them
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	BaseEnvFrom []corev1.EnvFromSource `json:"baseEnvFrom,omitempty"`
+
 	// EnvRequirements specifies validation rules for workspace environment variables
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
@@ -78,11 +144,35 @@ type WorkspaceTemplateSpec struct {
 
 	// DefaultVolumes specifies default additional volumes for workspaces using this template
 	// Volumes are applied during defaulting only if the workspace does not specify any volumes
-	// Each volume references a pre-existing PVC by name in the workspace's namespace
+	// Each volume references a pre-existing PVC by name in the workspace's namespace, or an NFS export
 	// +kubebuilder:validation:MaxItems=10
 	// +optional
 	DefaultVolumes []VolumeSpec `json:"defaultVolumes,omitempty"`
 
+	// AllowedVolumeMountPaths lists the paths under which workspaces can mount their volumes, e.g. /data
+	// A path matches at a path boundary. Any mount path is allowed when empty.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=253
+	// +kubebuilder:validation:items:Pattern=`^/`
+	// +optional
+	AllowedVolumeMountPaths []string `json:"allowedVolumeMountPaths,omitempty"`
+
+	// AllowedNFSServers lists the NFS servers whose exports workspaces can mount as volumes
+	// Workspaces cannot mount NFS volumes when empty.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=253
+	// +optional
+	AllowedNFSServers []string `json:"allowedNFSServers,omitempty"`
+
+	// AllowedBuckets lists the buckets workspaces can mount with objectStorageMounts, as URLs such as
+	// s3://team-datasets or gs://team-datasets/shared. A mount is allowed when its bucket and prefix
+	// are under one of the URLs. Workspaces cannot mount buckets when empty.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=1024
+	// +kubebuilder:validation:items:Pattern=`^(s3|gs)://[^/]+`
+	// +optional
+	AllowedBuckets []string `json:"allowedBuckets,omitempty"`
+
 	// DefaultNodeSelector specifies default node selection constraints
 	// +optional
 	DefaultNodeSelector map[string]string `json:"defaultNodeSelector,omitempty"`
@@ -95,6 +185,30 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultTolerations []corev1.Toleration `json:"defaultTolerations,omitempty"`
 
+	// DefaultPriorityClassName is the PriorityClass of workspaces that do not specify one
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DefaultPriorityClassName string `json:"defaultPriorityClassName,omitempty"`
+
+	// AllowedPriorityClassNames lists the PriorityClasses that workspaces using this template can use
+	// If empty, only DefaultPriorityClassName is allowed (secure by default)
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	AllowedPriorityClassNames []string `json:"allowedPriorityClassNames,omitempty"`
+
+	// ImagePullSecrets references Secrets used to pull the images of workspaces using this template
+	// from private registries. The Secrets must exist in the namespace of each workspace.
+	// They are added during defaulting to the workspace's imagePullSecrets if not already listed
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImageDeprecations announces the end of life of images of this template. Workspaces using one
+	// of them are admitted with a warning from 30 days before its end of life
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	ImageDeprecations []ImageDeprecation `json:"imageDeprecations,omitempty"`
+
 	// DefaultOwnershipType specifies default ownershipType for workspaces using this template
 	// OwnershipType controls which users may edit/delete the workspace
 	// +kubebuilder:validation:Enum=Public;OwnerOnly
@@ -102,6 +216,28 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultOwnershipType string `json:"defaultOwnershipType,omitempty"`
 
+	// AllowedUsers lists the users who may create workspaces with this template, or switch
+	// workspaces to it, in addition to the members of AllowedGroups
+	// If both are empty, any user may use the template
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	AllowedUsers []string `json:"allowedUsers,omitempty"`
+
+	// AllowedGroups lists the groups whose members may create workspaces with this template, or
+	// switch workspaces to it
+	// If both AllowedUsers and AllowedGroups are empty, any user may use the template
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+
+	// DefaultNamespaceSelector restricts a template labeled as default template to the workspaces
+	// of the namespaces matching these labels. A default template with a matching selector takes
+	// priority over one without selector in the same namespace.
+	// +optional
+	DefaultNamespaceSelector *metav1.LabelSelector `json:"defaultNamespaceSelector,omitempty"`
+
 	// BaseLabels specifies labels to add to workspaces using this template
 	// Labels are added during defaulting if not already present on the workspace
 	// +kubebuilder:validation:MaxItems=50
@@ -133,6 +269,15 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultAccessStrategy *AccessStrategyRef `json:"defaultAccessStrategy,omitempty"`
 
+	// AllowedDomainSuffixes lists the domains (e.g. team.example.com) whose subdomains workspaces
+	// using this template can set as customDomain
+	// If empty, workspaces cannot set a custom domain (secure by default)
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=253
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	AllowedDomainSuffixes []string `json:"allowedDomainSuffixes,omitempty"`
+
 	// DefaultLifecycle specifies default lifecycle hooks for workspaces using this template
 	// +optional
 	DefaultLifecycle *corev1.Lifecycle `json:"defaultLifecycle,omitempty"`
@@ -143,6 +288,12 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultReadinessProbe *corev1.Probe `json:"defaultReadinessProbe,omitempty"`
 
+	// DefaultProbes specifies the default probes for the main workspace container
+	// for workspaces using this template.
+	// Applied only if the workspace does not specify its own probes.
+	// +optional
+	DefaultProbes *WorkspaceProbes `json:"defaultProbes,omitempty"`
+
 	// DefaultPodSecurityContext specifies default pod-level security context
 	// +optional
 	DefaultPodSecurityContext *corev1.PodSecurityContext `json:"defaultPodSecurityContext,omitempty"`
@@ -151,6 +302,56 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultContainerSecurityContext *corev1.SecurityContext `json:"defaultContainerSecurityContext,omitempty"`
 
+	// SecurityProfile is the Pod Security Standard enforced on the pods of workspaces using this template,
+	// with the exceptions workspaces may request in their security contexts
+	// +optional
+	SecurityProfile *SecurityProfile `json:"securityProfile,omitempty"`
+
+	// NetworkIsolation isolates the pods of workspaces using this template with a default-deny
+	// NetworkPolicy, which only lets the controller, the ingress controller and the listed sources in
+	// +optional
+	NetworkIsolation *NetworkIsolation `json:"networkIsolation,omitempty"`
+
+	// IdentityMapping runs the pods of workspaces using this template with a UID and GID mapped from
+	// their creator, so that home directory files keep the same owner across shared storage backends
+	// +optional
+	IdentityMapping *IdentityMapping `json:"identityMapping,omitempty"`
+
+	// RuntimeClassName is the RuntimeClass of workspaces using this template, for instance a sandboxed
+	// runtime such as gVisor or Kata Containers. Workspaces that do not specify one use it, and
+	// workspaces can only override it with one of AllowedRuntimeClassNames
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// AllowedRuntimeClassNames lists the RuntimeClasses that workspaces using this template can use
+	// If empty, only RuntimeClassName is allowed (secure by default)
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	AllowedRuntimeClassNames []string `json:"allowedRuntimeClassNames,omitempty"`
+
+	// GPUSharing is the GPU sharing of workspaces using this template. Workspaces that do not specify
+	// one use it, and workspaces can only change its mode to one of AllowedGPUSharingModes
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+
+	// AllowedGPUSharingModes lists the GPU sharing modes that workspaces using this template can use
+	// If empty, only the mode of GPUSharing is allowed (secure by default)
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	AllowedGPUSharingModes []GPUSharingMode `json:"allowedGPUSharingModes,omitempty"`
+
+	// ProvisioningHints tells node autoprovisioners, such as Karpenter or the Cluster Autoscaler,
+	// which nodes to provision for the pods of workspaces using this template
+	// +optional
+	ProvisioningHints *ProvisioningHints `json:"provisioningHints,omitempty"`
+
+	// JupyterServerConfig standardizes the configuration of the Jupyter server and JupyterLab in
+	// workspaces using this template, such as their default kernel, server extensions and terminals.
+	// Workspaces that do not specify one use it, and cannot change it
+	// +optional
+	JupyterServerConfig *JupyterServerConfig `json:"jupyterServerConfig,omitempty"`
+
 	// DefaultInitContainers specifies default init containers for workspaces using this template
 	// Applied during defaulting if the workspace does not specify any init containers
 	// +kubebuilder:validation:MaxItems=10
@@ -166,6 +367,20 @@ type WorkspaceTemplateSpec struct {
 	// AppType specifies the application type for workspaces using this template
 	// +optional
 	AppType string `json:"appType,omitempty"`
+
+	// ApplicationType is the kind of application workspaces using this template serve, which switches
+	// the port the workspace Service routes to, the path of the default probes and the base URL
+	// environment variable
+	// Applied during defaulting if the workspace does not specify one
+	// Default: jupyterlab.
+	// +optional
+	ApplicationType ApplicationType `json:"applicationType,omitempty"`
+
+	// ApplyMode determines how the constraints of this template apply to existing workspaces
+	// that no longer satisfy them, e.g. after the template is tightened
+	// Default: Enforce.
+	// +optional
+	ApplyMode TemplateApplyMode `json:"applyMode,omitempty"`
 }
 
 // TemplateLabel defines a label key-value pair to add to workspaces
@@ -214,6 +429,13 @@ type EnvRequirement struct {
 	// If empty, any value is accepted
 	// +optional
 	Regex string `json:"regex,omitempty"`
+
+	// Protected prevents workspaces from overriding the environment variable.
+	// The variable must keep the value set by BaseEnv, or be left unset when BaseEnv does not
+	// define it, and it cannot be set in extraEnv
+	// +kubebuilder:default=false
+	// +optional
+	Protected *bool `json:"protected,omitempty"`
 }
 
 // ResourceBounds defines minimum and maximum resource limits for any resource type.
@@ -238,6 +460,41 @@ type ResourceBounds struct {
 	Resources map[corev1.ResourceName]ResourceRange `json:"resources,omitempty"`
 }
 
+// ResourceProfile defines a named size of workspaces
+type ResourceProfile struct {
+	// Name identifies the profile in workspace.spec.profile
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// DisplayName is the human-readable name of the profile
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Resources are the resource requirements of workspaces selecting the profile
+	// +kubebuilder:validation:Required
+	Resources corev1.ResourceRequirements `json:"resources"`
+}
+
+// ImageDeprecation announces the end of life of an image
+type ImageDeprecation struct {
+	// Image is the image reaching its end of life, e.g. "quay.io/jupyter/scipy-notebook:2024-01-15"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// EndOfLife is when the image stops being supported
+	// +kubebuilder:validation:Required
+	EndOfLife metav1.Time `json:"endOfLife"`
+
+	// Replacement is the image that workspaces should move to
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+}
+
 // ResourceRange defines min and max for a resource
 // NOTE: CEL validation for min <= max is not possible due to resource.Quantity type limitations
 // Consistency (min <= max) is enforced by the WorkspaceTemplate validating webhook
@@ -276,6 +533,11 @@ type StorageConfig struct {
 	// +kubebuilder:default="/home/jovyan"
 	// +optional
 	DefaultMountPath string `json:"defaultMountPath,omitempty"`
+
+	// ProvisioningHook is copied to workspaces using this template and runs once
+	// when the controller creates their persistent volume
+	// +optional
+	ProvisioningHook *StorageProvisioningHook `json:"provisioningHook,omitempty"`
 }
 
 // IdleShutdownOverridePolicy defines idle shutdown override constraints
@@ -294,6 +556,76 @@ type IdleShutdownOverridePolicy struct {
 	MaxIdleTimeoutInMinutes *int `json:"maxIdleTimeoutInMinutes,omitempty"`
 }
 
+// TemplateApplyMode determines how the constraints of a template apply to the workspaces that
+// violate them
+// +kubebuilder:validation:Enum=Enforce;Migrate;Audit
+type TemplateApplyMode string
+
+const (
+	// TemplateApplyModeEnforce rejects any update of a violating workspace, other than stopping it,
+	// until the workspace satisfies the template
+	TemplateApplyModeEnforce TemplateApplyMode = "Enforce"
+
+	// TemplateApplyModeMigrate resets the violating image, resources, priority class and runtime class
+	// of a workspace to the template defaults the next time it starts
+	TemplateApplyModeMigrate TemplateApplyMode = "Migrate"
+
+	// TemplateApplyModeAudit admits the updates of violating workspaces that add no violation, and
+	// only flags them with a TemplateViolation condition
+	TemplateApplyModeAudit TemplateApplyMode = "Audit"
+)
+
+// IdentityMappingSource is where the UID and GID of a workspace creator come from
+// +kubebuilder:validation:Enum=ConfigMap;Hash
+type IdentityMappingSource string
+
+const (
+	// IdentityMappingConfigMap looks up the creator in a ConfigMap whose data maps usernames
+	// to "uid" or "uid:gid"
+	IdentityMappingConfigMap IdentityMappingSource = "ConfigMap"
+
+	// IdentityMappingHash derives the UID from a hash of the username, between MinID and MaxID
+	IdentityMappingHash IdentityMappingSource = "Hash"
+)
+
+// IdentityMapping maps the creator of a workspace, from its created-by annotation, to the UID and GID
+// of the workspace pod and main container
+// +kubebuilder:validation:XValidation:rule="self.source != 'ConfigMap' || (has(self.configMapName) && size(self.configMapName) > 0)",message="configMapName is required when source is ConfigMap"
+// +kubebuilder:validation:XValidation:rule="self.minID <= self.maxID",message="minID must not exceed maxID"
+type IdentityMapping struct {
+	// Source is where the UID and GID of the creator come from
+	Source IdentityMappingSource `json:"source"`
+
+	// ConfigMapName is the name of the ConfigMap mapping usernames to "uid" or "uid:gid",
+	// in the namespace of the template. Required when Source is ConfigMap
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// MinID is the lowest UID and GID workspaces can run with
+	// +kubebuilder:default=10000
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinID int64 `json:"minID,omitempty"`
+
+	// MaxID is the highest UID and GID workspaces can run with
+	// +kubebuilder:default=2147483647
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	MaxID int64 `json:"maxID,omitempty"`
+
+	// GroupID is the GID of all the workspace pods, for instance a group shared by the users of a
+	// file system. When unset, the GID is the one of the ConfigMap entry, or else the UID
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	GroupID *int64 `json:"groupID,omitempty"`
+
+	// SetFSGroup also sets the fsGroup of the pod to the GID, so that the volumes that support it
+	// are owned by the group
+	// +optional
+	SetFSGroup bool `json:"setFSGroup,omitempty"`
+}
+
 // WorkspaceTemplateStatus defines the observed state of WorkspaceTemplate
 // Follows Kubernetes API conventions for status reporting
 type WorkspaceTemplateStatus struct {
@@ -302,6 +634,25 @@ type WorkspaceTemplateStatus struct {
 	// When metadata.generation != status.observedGeneration, the controller has not yet processed the latest spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// InUseBy reports the active workspaces blocking the deletion of the template.
+	// Only set while the template is being deleted.
+	// +optional
+	InUseBy *TemplateUsage `json:"inUseBy,omitempty"`
+}
+
+// TemplateUsage summarizes the active workspaces using a template
+type TemplateUsage struct {
+	// Count is the number of active workspaces using the template
+	Count int32 `json:"count"`
+
+	// Workspaces lists a sample of these workspaces, as namespace/name
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Workspaces []string `json:"workspaces,omitempty"`
+
+	// LastCheckedTime is when the workspaces were last counted
+	LastCheckedTime metav1.Time `json:"lastCheckedTime"`
 }
 
 // +kubebuilder:object:root=true
@@ -310,6 +661,7 @@ type WorkspaceTemplateStatus struct {
 // +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".spec.displayName"
 // +kubebuilder:printcolumn:name="Default Image",type="string",JSONPath=".spec.defaultImage"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:selectablefield:JSONPath=".spec.defaultAccessStrategy.name"
 
 // WorkspaceTemplate is the Schema for the workspacetemplates API
 // Templates define reusable, secure-by-default configurations for workspaces.
@@ -0,0 +1,67 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceUserPreferencesSpec defines the defaults a user prefers for their workspaces
+type WorkspaceUserPreferencesSpec struct {
+	// User is the username the preferences apply to, matched against the
+	// created-by annotation of the workspaces in the same namespace.
+	// Users can only name themselves; admins can name any user.
+	// +kubebuilder:validation:MinLength=1
+	User string `json:"user"`
+
+	// Image is the preferred container image for workspaces that do not specify one.
+	// It is skipped for workspaces whose template does not allow it.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources are the preferred resource requirements for workspaces that do not specify any.
+	// They are skipped for workspaces whose template resource bounds do not allow them.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Timezone is the preferred IANA time zone (e.g. "Europe/Paris"), set as the TZ
+	// environment variable of workspaces that do not define TZ
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_+\-]+(/[A-Za-z0-9_+\-]+)*$`
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image"
+// +kubebuilder:printcolumn:name="Timezone",type="string",JSONPath=".spec.timezone"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkspaceUserPreferences is the Schema for the workspaceuserpreferences API
+// It stores the defaults a user prefers for the workspaces they create in its namespace,
+// which the workspace defaulting webhook applies within the constraints of the template
+type WorkspaceUserPreferences struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the preferences of the user
+	Spec WorkspaceUserPreferencesSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceUserPreferencesList contains a list of WorkspaceUserPreferences
+type WorkspaceUserPreferencesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceUserPreferences `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceUserPreferences{}, &WorkspaceUserPreferencesList{})
+}
@@ -68,6 +68,11 @@ func (in *AccessResourceStatus) DeepCopy() *AccessResourceStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessResourceTemplate) DeepCopyInto(out *AccessResourceTemplate) {
 	*out = *in
+	if in.Generate != nil {
+		in, out := &in.Generate, &out.Generate
+		*out = make([]SecretGenerator, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessResourceTemplate.
@@ -115,6 +120,36 @@ func (in *AccessStrategyRef) DeepCopy() *AccessStrategyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationImagesConfig) DeepCopyInto(out *ApplicationImagesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationImagesConfig.
+func (in *ApplicationImagesConfig) DeepCopy() *ApplicationImagesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationImagesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSource) DeepCopyInto(out *CloneSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSource.
+func (in *CloneSource) DeepCopy() *CloneSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
 	*out = *in
@@ -140,6 +175,44 @@ func (in *ContainerConfig) DeepCopy() *ContainerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CullingPolicySpec) DeepCopyInto(out *CullingPolicySpec) {
+	*out = *in
+	if in.KeepAliveUntil != nil {
+		in, out := &in.KeepAliveUntil, &out.KeepAliveUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CullingPolicySpec.
+func (in *CullingPolicySpec) DeepCopy() *CullingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CullingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CullingStatus) DeepCopyInto(out *CullingStatus) {
+	*out = *in
+	if in.ExemptUntil != nil {
+		in, out := &in.ExemptUntil, &out.ExemptUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CullingStatus.
+func (in *CullingStatus) DeepCopy() *CullingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CullingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentModifications) DeepCopyInto(out *DeploymentModifications) {
 	*out = *in
@@ -160,6 +233,82 @@ func (in *DeploymentModifications) DeepCopy() *DeploymentModifications {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveSpecStatus) DeepCopyInto(out *EffectiveSpecStatus) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveSpecStatus.
+func (in *EffectiveSpecStatus) DeepCopy() *EffectiveSpecStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveSpecStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvRequirement) DeepCopyInto(out *EnvRequirement) {
 	*out = *in
@@ -168,6 +317,11 @@ func (in *EnvRequirement) DeepCopyInto(out *EnvRequirement) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Protected != nil {
+		in, out := &in.Protected, &out.Protected
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvRequirement.
@@ -180,6 +334,86 @@ func (in *EnvRequirement) DeepCopy() *EnvRequirement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailurePolicySpec) DeepCopyInto(out *FailurePolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailurePolicySpec.
+func (in *FailurePolicySpec) DeepCopy() *FailurePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailurePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUMPSConfig) DeepCopyInto(out *GPUMPSConfig) {
+	*out = *in
+	if in.ActiveThreadPercentage != nil {
+		in, out := &in.ActiveThreadPercentage, &out.ActiveThreadPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PinnedDeviceMemoryLimit != nil {
+		in, out := &in.PinnedDeviceMemoryLimit, &out.PinnedDeviceMemoryLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUMPSConfig.
+func (in *GPUMPSConfig) DeepCopy() *GPUMPSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUMPSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSharing) DeepCopyInto(out *GPUSharing) {
+	*out = *in
+	if in.MPS != nil {
+		in, out := &in.MPS, &out.MPS
+		*out = new(GPUMPSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSharing.
+func (in *GPUSharing) DeepCopy() *GPUSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityMapping) DeepCopyInto(out *IdentityMapping) {
+	*out = *in
+	if in.GroupID != nil {
+		in, out := &in.GroupID, &out.GroupID
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityMapping.
+func (in *IdentityMapping) DeepCopy() *IdentityMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IdleDetectionSpec) DeepCopyInto(out *IdleDetectionSpec) {
 	*out = *in
@@ -283,258 +517,1435 @@ func (in *IdleShutdownSpec) DeepCopy() *IdleShutdownSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LabelRequirement) DeepCopyInto(out *LabelRequirement) {
+func (in *ImageDeprecation) DeepCopyInto(out *ImageDeprecation) {
 	*out = *in
-	if in.Required != nil {
-		in, out := &in.Required, &out.Required
-		*out = new(bool)
-		**out = **in
-	}
+	in.EndOfLife.DeepCopyInto(&out.EndOfLife)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelRequirement.
-func (in *LabelRequirement) DeepCopy() *LabelRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDeprecation.
+func (in *ImageDeprecation) DeepCopy() *ImageDeprecation {
 	if in == nil {
 		return nil
 	}
-	out := new(LabelRequirement)
+	out := new(ImageDeprecation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodModifications) DeepCopyInto(out *PodModifications) {
+func (in *ImageMapping) DeepCopyInto(out *ImageMapping) {
 	*out = *in
-	if in.AdditionalContainers != nil {
-		in, out := &in.AdditionalContainers, &out.AdditionalContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.PrimaryContainerModifications != nil {
-		in, out := &in.PrimaryContainerModifications, &out.PrimaryContainerModifications
-		*out = new(PrimaryContainerModifications)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodModifications.
-func (in *PodModifications) DeepCopy() *PodModifications {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMapping.
+func (in *ImageMapping) DeepCopy() *ImageMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(PodModifications)
+	out := new(ImageMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrimaryContainerModifications) DeepCopyInto(out *PrimaryContainerModifications) {
+func (in *ImageRolloutPolicy) DeepCopyInto(out *ImageRolloutPolicy) {
 	*out = *in
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.MergeEnv != nil {
-		in, out := &in.MergeEnv, &out.MergeEnv
-		*out = make([]AccessEnvTemplate, len(*in))
-		copy(*out, *in)
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryContainerModifications.
-func (in *PrimaryContainerModifications) DeepCopy() *PrimaryContainerModifications {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRolloutPolicy.
+func (in *ImageRolloutPolicy) DeepCopy() *ImageRolloutPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PrimaryContainerModifications)
+	out := new(ImageRolloutPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+func (in *ImageRolloutWorkspaceStatus) DeepCopyInto(out *ImageRolloutWorkspaceStatus) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make(map[v1.ResourceName]ResourceRange, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
-func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRolloutWorkspaceStatus.
+func (in *ImageRolloutWorkspaceStatus) DeepCopy() *ImageRolloutWorkspaceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceBounds)
+	out := new(ImageRolloutWorkspaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRange) DeepCopyInto(out *ResourceRange) {
+func (in *JupyterServerConfig) DeepCopyInto(out *JupyterServerConfig) {
 	*out = *in
-	out.Min = in.Min.DeepCopy()
-	out.Max = in.Max.DeepCopy()
+	if in.TerminalsEnabled != nil {
+		in, out := &in.TerminalsEnabled, &out.TerminalsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LabSettingsOverrides != nil {
+		in, out := &in.LabSettingsOverrides, &out.LabSettingsOverrides
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRange.
-func (in *ResourceRange) DeepCopy() *ResourceRange {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JupyterServerConfig.
+func (in *JupyterServerConfig) DeepCopy() *JupyterServerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRange)
+	out := new(JupyterServerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+func (in *LabelRequirement) DeepCopyInto(out *LabelRequirement) {
 	*out = *in
-	out.DefaultSize = in.DefaultSize.DeepCopy()
-	if in.MinSize != nil {
-		in, out := &in.MinSize, &out.MinSize
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelRequirement.
+func (in *LabelRequirement) DeepCopy() *LabelRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NFSVolumeSource) DeepCopyInto(out *NFSVolumeSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSVolumeSource.
+func (in *NFSVolumeSource) DeepCopy() *NFSVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NFSVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIsolation) DeepCopyInto(out *NetworkIsolation) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIsolation.
+func (in *NetworkIsolation) DeepCopy() *NetworkIsolation {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIsolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorageMount) DeepCopyInto(out *ObjectStorageMount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageMount.
+func (in *ObjectStorageMount) DeepCopy() *ObjectStorageMount {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorageMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.ApplicationImages != nil {
+		in, out := &in.ApplicationImages, &out.ApplicationImages
+		*out = new(ApplicationImagesConfig)
+		**out = **in
+	}
+	if in.RequireTemplate != nil {
+		in, out := &in.RequireTemplate, &out.RequireTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IdleCheckInterval != nil {
+		in, out := &in.IdleCheckInterval, &out.IdleCheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.WatchResources != nil {
+		in, out := &in.WatchResources, &out.WatchResources
+		*out = make([]WatchedResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodModifications) DeepCopyInto(out *PodModifications) {
+	*out = *in
+	if in.AdditionalContainers != nil {
+		in, out := &in.AdditionalContainers, &out.AdditionalContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrimaryContainerModifications != nil {
+		in, out := &in.PrimaryContainerModifications, &out.PrimaryContainerModifications
+		*out = new(PrimaryContainerModifications)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodModifications.
+func (in *PodModifications) DeepCopy() *PodModifications {
+	if in == nil {
+		return nil
+	}
+	out := new(PodModifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryContainerModifications) DeepCopyInto(out *PrimaryContainerModifications) {
+	*out = *in
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MergeEnv != nil {
+		in, out := &in.MergeEnv, &out.MergeEnv
+		*out = make([]AccessEnvTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryContainerModifications.
+func (in *PrimaryContainerModifications) DeepCopy() *PrimaryContainerModifications {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryContainerModifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningHints) DeepCopyInto(out *ProvisioningHints) {
+	*out = *in
+	if in.InstanceFamilies != nil {
+		in, out := &in.InstanceFamilies, &out.InstanceFamilies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningHints.
+func (in *ProvisioningHints) DeepCopy() *ProvisioningHints {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteAccessStatus) DeepCopyInto(out *RemoteAccessStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteAccessStatus.
+func (in *RemoteAccessStatus) DeepCopy() *RemoteAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[v1.ResourceName]ResourceRange, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceProfile) DeepCopyInto(out *ResourceProfile) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceProfile.
+func (in *ResourceProfile) DeepCopy() *ResourceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRange) DeepCopyInto(out *ResourceRange) {
+	*out = *in
+	out.Min = in.Min.DeepCopy()
+	out.Max = in.Max.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRange.
+func (in *ResourceRange) DeepCopy() *ResourceRange {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHAccess) DeepCopyInto(out *SSHAccess) {
+	*out = *in
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHAccess.
+func (in *SSHAccess) DeepCopy() *SSHAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGenerator) DeepCopyInto(out *SecretGenerator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGenerator.
+func (in *SecretGenerator) DeepCopy() *SecretGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityProfile) DeepCopyInto(out *SecurityProfile) {
+	*out = *in
+	if in.AllowedCapabilities != nil {
+		in, out := &in.AllowedCapabilities, &out.AllowedCapabilities
+		*out = make([]v1.Capability, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityProfile.
+func (in *SecurityProfile) DeepCopy() *SecurityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRef) DeepCopyInto(out *SnapshotRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRef.
+func (in *SnapshotRef) DeepCopy() *SnapshotRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+	*out = *in
+	out.DefaultSize = in.DefaultSize.DeepCopy()
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DefaultStorageClassName != nil {
+		in, out := &in.DefaultStorageClassName, &out.DefaultStorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProvisioningHook != nil {
+		in, out := &in.ProvisioningHook, &out.ProvisioningHook
+		*out = new(StorageProvisioningHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
+func (in *StorageConfig) DeepCopy() *StorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageExpansionStatus) DeepCopyInto(out *StorageExpansionStatus) {
+	*out = *in
+	out.RequestedSize = in.RequestedSize.DeepCopy()
+	if in.CurrentCapacity != nil {
+		in, out := &in.CurrentCapacity, &out.CurrentCapacity
 		x := (*in).DeepCopy()
 		*out = &x
 	}
-	if in.MaxSize != nil {
-		in, out := &in.MaxSize, &out.MaxSize
-		x := (*in).DeepCopy()
-		*out = &x
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageExpansionStatus.
+func (in *StorageExpansionStatus) DeepCopy() *StorageExpansionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageExpansionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProvisioningHook) DeepCopyInto(out *StorageProvisioningHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProvisioningHook.
+func (in *StorageProvisioningHook) DeepCopy() *StorageProvisioningHook {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProvisioningHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+	if in.RestoreFromSnapshot != nil {
+		in, out := &in.RestoreFromSnapshot, &out.RestoreFromSnapshot
+		*out = new(SnapshotRef)
+		**out = **in
+	}
+	if in.ProvisioningHook != nil {
+		in, out := &in.ProvisioningHook, &out.ProvisioningHook
+		*out = new(StorageProvisioningHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLabel) DeepCopyInto(out *TemplateLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLabel.
+func (in *TemplateLabel) DeepCopy() *TemplateLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRef) DeepCopyInto(out *TemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRef.
+func (in *TemplateRef) DeepCopy() *TemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateUsage) DeepCopyInto(out *TemplateUsage) {
+	*out = *in
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastCheckedTime.DeepCopyInto(&out.LastCheckedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateUsage.
+func (in *TemplateUsage) DeepCopy() *TemplateUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraefikAccess) DeepCopyInto(out *TraefikAccess) {
+	*out = *in
+	if in.StickySession != nil {
+		in, out := &in.StickySession, &out.StickySession
+		*out = new(TraefikStickySession)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(TraefikTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraefikAccess.
+func (in *TraefikAccess) DeepCopy() *TraefikAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(TraefikAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraefikStickySession) DeepCopyInto(out *TraefikStickySession) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraefikStickySession.
+func (in *TraefikStickySession) DeepCopy() *TraefikStickySession {
+	if in == nil {
+		return nil
+	}
+	out := new(TraefikStickySession)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraefikTimeouts) DeepCopyInto(out *TraefikTimeouts) {
+	*out = *in
+	if in.DialTimeout != nil {
+		in, out := &in.DialTimeout, &out.DialTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ResponseHeaderTimeout != nil {
+		in, out := &in.ResponseHeaderTimeout, &out.ResponseHeaderTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.IdleConnTimeout != nil {
+		in, out := &in.IdleConnTimeout, &out.IdleConnTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraefikTimeouts.
+func (in *TraefikTimeouts) DeepCopy() *TraefikTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(TraefikTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserQuotaUsage) DeepCopyInto(out *UserQuotaUsage) {
+	*out = *in
+	in.Used.DeepCopyInto(&out.Used)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserQuotaUsage.
+func (in *UserQuotaUsage) DeepCopy() *UserQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(UserQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VanityURLStatus) DeepCopyInto(out *VanityURLStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VanityURLStatus.
+func (in *VanityURLStatus) DeepCopy() *VanityURLStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VanityURLStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+	*out = *in
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(NFSVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
+func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WatchedResource) DeepCopyInto(out *WatchedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WatchedResource.
+func (in *WatchedResource) DeepCopy() *WatchedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(WatchedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workspace) DeepCopyInto(out *Workspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
+func (in *Workspace) DeepCopy() *Workspace {
+	if in == nil {
+		return nil
+	}
+	out := new(Workspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Workspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategy) DeepCopyInto(out *WorkspaceAccessStrategy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategy.
+func (in *WorkspaceAccessStrategy) DeepCopy() *WorkspaceAccessStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceAccessStrategy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategyList) DeepCopyInto(out *WorkspaceAccessStrategyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceAccessStrategy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyList.
+func (in *WorkspaceAccessStrategyList) DeepCopy() *WorkspaceAccessStrategyList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategySpec) DeepCopyInto(out *WorkspaceAccessStrategySpec) {
+	*out = *in
+	if in.AccessResourceTemplates != nil {
+		in, out := &in.AccessResourceTemplates, &out.AccessResourceTemplates
+		*out = make([]AccessResourceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CreateConnectionHandlerMap != nil {
+		in, out := &in.CreateConnectionHandlerMap, &out.CreateConnectionHandlerMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DefaultStorageClassName != nil {
-		in, out := &in.DefaultStorageClassName, &out.DefaultStorageClassName
-		*out = new(string)
+	if in.CreateConnectionContext != nil {
+		in, out := &in.CreateConnectionContext, &out.CreateConnectionContext
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodEventsContext != nil {
+		in, out := &in.PodEventsContext, &out.PodEventsContext
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeploymentModifications != nil {
+		in, out := &in.DeploymentModifications, &out.DeploymentModifications
+		*out = new(DeploymentModifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessStartupProbe != nil {
+		in, out := &in.AccessStartupProbe, &out.AccessStartupProbe
+		*out = new(AccessStartupProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(SSHAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Traefik != nil {
+		in, out := &in.Traefik, &out.Traefik
+		*out = new(TraefikAccess)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategySpec.
+func (in *WorkspaceAccessStrategySpec) DeepCopy() *WorkspaceAccessStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategyStatus) DeepCopyInto(out *WorkspaceAccessStrategyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyStatus.
+func (in *WorkspaceAccessStrategyStatus) DeepCopy() *WorkspaceAccessStrategyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceApplicationStatus) DeepCopyInto(out *WorkspaceApplicationStatus) {
+	*out = *in
+	if in.Ready != nil {
+		in, out := &in.Ready, &out.Ready
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
-func (in *StorageConfig) DeepCopy() *StorageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceApplicationStatus.
+func (in *WorkspaceApplicationStatus) DeepCopy() *WorkspaceApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceContainerStatus) DeepCopyInto(out *WorkspaceContainerStatus) {
+	*out = *in
+	if in.LastTerminationExitCode != nil {
+		in, out := &in.LastTerminationExitCode, &out.LastTerminationExitCode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceContainerStatus.
+func (in *WorkspaceContainerStatus) DeepCopy() *WorkspaceContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceContainerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceImageRollout) DeepCopyInto(out *WorkspaceImageRollout) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceImageRollout.
+func (in *WorkspaceImageRollout) DeepCopy() *WorkspaceImageRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceImageRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceImageRollout) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceImageRolloutList) DeepCopyInto(out *WorkspaceImageRolloutList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceImageRollout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceImageRolloutList.
+func (in *WorkspaceImageRolloutList) DeepCopy() *WorkspaceImageRolloutList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceImageRolloutList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceImageRolloutList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceImageRolloutSpec) DeepCopyInto(out *WorkspaceImageRolloutSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Policy.DeepCopyInto(&out.Policy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceImageRolloutSpec.
+func (in *WorkspaceImageRolloutSpec) DeepCopy() *WorkspaceImageRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceImageRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceImageRolloutStatus) DeepCopyInto(out *WorkspaceImageRolloutStatus) {
+	*out = *in
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]ImageRolloutWorkspaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceImageRolloutStatus.
+func (in *WorkspaceImageRolloutStatus) DeepCopy() *WorkspaceImageRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceImageRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceInterruptionStatus) DeepCopyInto(out *WorkspaceInterruptionStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceInterruptionStatus.
+func (in *WorkspaceInterruptionStatus) DeepCopy() *WorkspaceInterruptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceInterruptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Workspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceList.
+func (in *WorkspaceList) DeepCopy() *WorkspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspacePodStatus) DeepCopyInto(out *WorkspacePodStatus) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]WorkspaceContainerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePodStatus.
+func (in *WorkspacePodStatus) DeepCopy() *WorkspacePodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspacePodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspacePool) DeepCopyInto(out *WorkspacePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePool.
+func (in *WorkspacePool) DeepCopy() *WorkspacePool {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspacePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspacePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspacePoolClaimStatus) DeepCopyInto(out *WorkspacePoolClaimStatus) {
+	*out = *in
+	in.ClaimTime.DeepCopyInto(&out.ClaimTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePoolClaimStatus.
+func (in *WorkspacePoolClaimStatus) DeepCopy() *WorkspacePoolClaimStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfig)
+	out := new(WorkspacePoolClaimStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+func (in *WorkspacePoolList) DeepCopyInto(out *WorkspacePoolList) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspacePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out.Size = in.Size.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
-func (in *StorageSpec) DeepCopy() *StorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePoolList.
+func (in *WorkspacePoolList) DeepCopy() *WorkspacePoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSpec)
+	out := new(WorkspacePoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspacePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TemplateLabel) DeepCopyInto(out *TemplateLabel) {
+func (in *WorkspacePoolSpec) DeepCopyInto(out *WorkspacePoolSpec) {
 	*out = *in
+	out.TemplateRef = in.TemplateRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLabel.
-func (in *TemplateLabel) DeepCopy() *TemplateLabel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePoolSpec.
+func (in *WorkspacePoolSpec) DeepCopy() *WorkspacePoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TemplateLabel)
+	out := new(WorkspacePoolSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TemplateRef) DeepCopyInto(out *TemplateRef) {
+func (in *WorkspacePoolStatus) DeepCopyInto(out *WorkspacePoolStatus) {
 	*out = *in
+	if in.LastClaimTime != nil {
+		in, out := &in.LastClaimTime, &out.LastClaimTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRef.
-func (in *TemplateRef) DeepCopy() *TemplateRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePoolStatus.
+func (in *WorkspacePoolStatus) DeepCopy() *WorkspacePoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TemplateRef)
+	out := new(WorkspacePoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+func (in *WorkspacePort) DeepCopyInto(out *WorkspacePort) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
-func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePort.
+func (in *WorkspacePort) DeepCopy() *WorkspacePort {
 	if in == nil {
 		return nil
 	}
-	out := new(VolumeSpec)
+	out := new(WorkspacePort)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Workspace) DeepCopyInto(out *Workspace) {
+func (in *WorkspaceProbes) DeepCopyInto(out *WorkspaceProbes) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
-func (in *Workspace) DeepCopy() *Workspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceProbes.
+func (in *WorkspaceProbes) DeepCopy() *WorkspaceProbes {
 	if in == nil {
 		return nil
 	}
-	out := new(Workspace)
+	out := new(WorkspaceProbes)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Workspace) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategy) DeepCopyInto(out *WorkspaceAccessStrategy) {
+func (in *WorkspaceQuota) DeepCopyInto(out *WorkspaceQuota) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -542,18 +1953,18 @@ func (in *WorkspaceAccessStrategy) DeepCopyInto(out *WorkspaceAccessStrategy) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategy.
-func (in *WorkspaceAccessStrategy) DeepCopy() *WorkspaceAccessStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceQuota.
+func (in *WorkspaceQuota) DeepCopy() *WorkspaceQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategy)
+	out := new(WorkspaceQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *WorkspaceAccessStrategy) DeepCopyObject() runtime.Object {
+func (in *WorkspaceQuota) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -561,31 +1972,31 @@ func (in *WorkspaceAccessStrategy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategyList) DeepCopyInto(out *WorkspaceAccessStrategyList) {
+func (in *WorkspaceQuotaList) DeepCopyInto(out *WorkspaceQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]WorkspaceAccessStrategy, len(*in))
+		*out = make([]WorkspaceQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyList.
-func (in *WorkspaceAccessStrategyList) DeepCopy() *WorkspaceAccessStrategyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceQuotaList.
+func (in *WorkspaceQuotaList) DeepCopy() *WorkspaceQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategyList)
+	out := new(WorkspaceQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
+func (in *WorkspaceQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -593,110 +2004,273 @@ func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategySpec) DeepCopyInto(out *WorkspaceAccessStrategySpec) {
+func (in *WorkspaceQuotaSpec) DeepCopyInto(out *WorkspaceQuotaSpec) {
 	*out = *in
-	if in.AccessResourceTemplates != nil {
-		in, out := &in.AccessResourceTemplates, &out.AccessResourceTemplates
-		*out = make([]AccessResourceTemplate, len(*in))
-		copy(*out, *in)
+	if in.MaxWorkspaces != nil {
+		in, out := &in.MaxWorkspaces, &out.MaxWorkspaces
+		*out = new(int32)
+		**out = **in
 	}
-	if in.CreateConnectionHandlerMap != nil {
-		in, out := &in.CreateConnectionHandlerMap, &out.CreateConnectionHandlerMap
-		*out = make(map[string]string, len(*in))
+	if in.MaxRunningWorkspaces != nil {
+		in, out := &in.MaxRunningWorkspaces, &out.MaxRunningWorkspaces
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(v1.ResourceList, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = val.DeepCopy()
 		}
 	}
-	if in.CreateConnectionContext != nil {
-		in, out := &in.CreateConnectionContext, &out.CreateConnectionContext
-		*out = make(map[string]string, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceQuotaSpec.
+func (in *WorkspaceQuotaSpec) DeepCopy() *WorkspaceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceQuotaStatus) DeepCopyInto(out *WorkspaceQuotaStatus) {
+	*out = *in
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = new(WorkspaceQuotaUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]UserQuotaUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceQuotaStatus.
+func (in *WorkspaceQuotaStatus) DeepCopy() *WorkspaceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceQuotaUsage) DeepCopyInto(out *WorkspaceQuotaUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(v1.ResourceList, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = val.DeepCopy()
 		}
 	}
-	if in.PodEventsContext != nil {
-		in, out := &in.PodEventsContext, &out.PodEventsContext
-		*out = make(map[string]string, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceQuotaUsage.
+func (in *WorkspaceQuotaUsage) DeepCopy() *WorkspaceQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceResourceUtilization) DeepCopyInto(out *WorkspaceResourceUtilization) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPUPercent != nil {
+		in, out := &in.CPUPercent, &out.CPUPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryPercent != nil {
+		in, out := &in.MemoryPercent, &out.MemoryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GPUPercent != nil {
+		in, out := &in.GPUPercent, &out.GPUPercent
+		*out = new(int32)
+		**out = **in
+	}
+	in.SampleTime.DeepCopyInto(&out.SampleTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceResourceUtilization.
+func (in *WorkspaceResourceUtilization) DeepCopy() *WorkspaceResourceUtilization {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceResourceUtilization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceRightSizingStatus) DeepCopyInto(out *WorkspaceRightSizingStatus) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(v1.ResourceList, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = val.DeepCopy()
 		}
 	}
-	if in.DeploymentModifications != nil {
-		in, out := &in.DeploymentModifications, &out.DeploymentModifications
-		*out = new(DeploymentModifications)
+	in.WindowStartTime.DeepCopyInto(&out.WindowStartTime)
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(WorkspaceUtilizationRange)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AccessStartupProbe != nil {
-		in, out := &in.AccessStartupProbe, &out.AccessStartupProbe
-		*out = new(AccessStartupProbe)
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(WorkspaceUtilizationRange)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RecommendedRequests != nil {
+		in, out := &in.RecommendedRequests, &out.RecommendedRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategySpec.
-func (in *WorkspaceAccessStrategySpec) DeepCopy() *WorkspaceAccessStrategySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceRightSizingStatus.
+func (in *WorkspaceRightSizingStatus) DeepCopy() *WorkspaceRightSizingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategySpec)
+	out := new(WorkspaceRightSizingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategyStatus) DeepCopyInto(out *WorkspaceAccessStrategyStatus) {
+func (in *WorkspaceSnapshot) DeepCopyInto(out *WorkspaceSnapshot) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyStatus.
-func (in *WorkspaceAccessStrategyStatus) DeepCopy() *WorkspaceAccessStrategyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshot.
+func (in *WorkspaceSnapshot) DeepCopy() *WorkspaceSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategyStatus)
+	out := new(WorkspaceSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
+func (in *WorkspaceSnapshotList) DeepCopyInto(out *WorkspaceSnapshotList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Workspace, len(*in))
+		*out = make([]WorkspaceSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotList.
+func (in *WorkspaceSnapshotList) DeepCopy() *WorkspaceSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotSpec) DeepCopyInto(out *WorkspaceSnapshotSpec) {
+	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotSpec.
+func (in *WorkspaceSnapshotSpec) DeepCopy() *WorkspaceSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotStatus) DeepCopyInto(out *WorkspaceSnapshotStatus) {
+	*out = *in
+	if in.RestoreSize != nil {
+		in, out := &in.RestoreSize, &out.RestoreSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceList.
-func (in *WorkspaceList) DeepCopy() *WorkspaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotStatus.
+func (in *WorkspaceSnapshotStatus) DeepCopy() *WorkspaceSnapshotStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceList)
+	out := new(WorkspaceSnapshotStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *WorkspaceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
@@ -713,6 +2287,13 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make([]VolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObjectStorageMounts != nil {
+		in, out := &in.ObjectStorageMounts, &out.ObjectStorageMounts
+		*out = make([]ObjectStorageMount, len(*in))
 		copy(*out, *in)
 	}
 	if in.ContainerConfig != nil {
@@ -720,6 +2301,11 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(ContainerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]WorkspacePort, len(*in))
+		copy(*out, *in)
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]v1.EnvVar, len(*in))
@@ -727,6 +2313,20 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -751,26 +2351,75 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(v1.Lifecycle)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StopGracePeriodSeconds != nil {
+		in, out := &in.StopGracePeriodSeconds, &out.StopGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RestartRequestedAt != nil {
+		in, out := &in.RestartRequestedAt, &out.RestartRequestedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.ReadinessProbe != nil {
 		in, out := &in.ReadinessProbe, &out.ReadinessProbe
 		*out = new(v1.Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(WorkspaceProbes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupDeadlineSeconds != nil {
+		in, out := &in.StartupDeadlineSeconds, &out.StartupDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(FailurePolicySpec)
+		**out = **in
+	}
 	if in.AccessStrategy != nil {
 		in, out := &in.AccessStrategy, &out.AccessStrategy
 		*out = new(AccessStrategyRef)
 		**out = **in
 	}
+	if in.AccessEnabled != nil {
+		in, out := &in.AccessEnabled, &out.AccessEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SSHPublicKeys != nil {
+		in, out := &in.SSHPublicKeys, &out.SSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.TemplateRef != nil {
 		in, out := &in.TemplateRef, &out.TemplateRef
 		*out = new(TemplateRef)
 		**out = **in
 	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(CloneSource)
+		**out = **in
+	}
 	if in.IdleShutdown != nil {
 		in, out := &in.IdleShutdown, &out.IdleShutdown
 		*out = new(IdleShutdownSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CullingPolicy != nil {
+		in, out := &in.CullingPolicy, &out.CullingPolicy
+		*out = new(CullingPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -781,6 +2430,26 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(v1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(SecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUSharing != nil {
+		in, out := &in.GPUSharing, &out.GPUSharing
+		*out = new(GPUSharing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningHints != nil {
+		in, out := &in.ProvisioningHints, &out.ProvisioningHints
+		*out = new(ProvisioningHints)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]v1.Container, len(*in))
@@ -788,6 +2457,11 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.JupyterServerConfig != nil {
+		in, out := &in.JupyterServerConfig, &out.JupyterServerConfig
+		*out = new(JupyterServerConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSpec.
@@ -800,9 +2474,30 @@ func (in *WorkspaceSpec) DeepCopy() *WorkspaceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceStartupStatus) DeepCopyInto(out *WorkspaceStartupStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceStartupStatus.
+func (in *WorkspaceStartupStatus) DeepCopy() *WorkspaceStartupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceStartupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 	*out = *in
+	if in.VanityURL != nil {
+		in, out := &in.VanityURL, &out.VanityURL
+		*out = new(VanityURLStatus)
+		**out = **in
+	}
 	if in.AccessResources != nil {
 		in, out := &in.AccessResources, &out.AccessResources
 		*out = make([]AccessResourceStatus, len(*in))
@@ -817,6 +2512,58 @@ func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 		in, out := &in.EarliestNextProbeTime, &out.EarliestNextProbeTime
 		*out = (*in).DeepCopy()
 	}
+	if in.StorageExpansion != nil {
+		in, out := &in.StorageExpansion, &out.StorageExpansion
+		*out = new(StorageExpansionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Culling != nil {
+		in, out := &in.Culling, &out.Culling
+		*out = new(CullingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(EffectiveSpecStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodStatus != nil {
+		in, out := &in.PodStatus, &out.PodStatus
+		*out = new(WorkspacePodStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]WorkspaceApplicationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastInterruption != nil {
+		in, out := &in.LastInterruption, &out.LastInterruption
+		*out = new(WorkspaceInterruptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(WorkspaceUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PoolClaim != nil {
+		in, out := &in.PoolClaim, &out.PoolClaim
+		*out = new(WorkspacePoolClaimStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(WorkspaceStartupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteAccess != nil {
+		in, out := &in.RemoteAccess, &out.RemoteAccess
+		*out = new(RemoteAccessStatus)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -842,7 +2589,7 @@ func (in *WorkspaceTemplate) DeepCopyInto(out *WorkspaceTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceTemplate.
@@ -903,6 +2650,11 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AllowCustomImages != nil {
 		in, out := &in.AllowCustomImages, &out.AllowCustomImages
 		*out = new(bool)
@@ -918,6 +2670,13 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(ResourceBounds)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]ResourceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PrimaryStorage != nil {
 		in, out := &in.PrimaryStorage, &out.PrimaryStorage
 		*out = new(StorageConfig)
@@ -928,6 +2687,11 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(ContainerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultPorts != nil {
+		in, out := &in.DefaultPorts, &out.DefaultPorts
+		*out = make([]WorkspacePort, len(*in))
+		copy(*out, *in)
+	}
 	if in.BaseEnv != nil {
 		in, out := &in.BaseEnv, &out.BaseEnv
 		*out = make([]v1.EnvVar, len(*in))
@@ -935,6 +2699,13 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BaseEnvFrom != nil {
+		in, out := &in.BaseEnvFrom, &out.BaseEnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.EnvRequirements != nil {
 		in, out := &in.EnvRequirements, &out.EnvRequirements
 		*out = make([]EnvRequirement, len(*in))
@@ -950,6 +2721,23 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 	if in.DefaultVolumes != nil {
 		in, out := &in.DefaultVolumes, &out.DefaultVolumes
 		*out = make([]VolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedVolumeMountPaths != nil {
+		in, out := &in.AllowedVolumeMountPaths, &out.AllowedVolumeMountPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedNFSServers != nil {
+		in, out := &in.AllowedNFSServers, &out.AllowedNFSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedBuckets != nil {
+		in, out := &in.AllowedBuckets, &out.AllowedBuckets
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	if in.DefaultNodeSelector != nil {
@@ -971,6 +2759,38 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AllowedPriorityClassNames != nil {
+		in, out := &in.AllowedPriorityClassNames, &out.AllowedPriorityClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageDeprecations != nil {
+		in, out := &in.ImageDeprecations, &out.ImageDeprecations
+		*out = make([]ImageDeprecation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedUsers != nil {
+		in, out := &in.AllowedUsers, &out.AllowedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultNamespaceSelector != nil {
+		in, out := &in.DefaultNamespaceSelector, &out.DefaultNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.BaseLabels != nil {
 		in, out := &in.BaseLabels, &out.BaseLabels
 		*out = make([]TemplateLabel, len(*in))
@@ -998,6 +2818,11 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(AccessStrategyRef)
 		**out = **in
 	}
+	if in.AllowedDomainSuffixes != nil {
+		in, out := &in.AllowedDomainSuffixes, &out.AllowedDomainSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.DefaultLifecycle != nil {
 		in, out := &in.DefaultLifecycle, &out.DefaultLifecycle
 		*out = new(v1.Lifecycle)
@@ -1008,6 +2833,11 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(v1.Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultProbes != nil {
+		in, out := &in.DefaultProbes, &out.DefaultProbes
+		*out = new(WorkspaceProbes)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultPodSecurityContext != nil {
 		in, out := &in.DefaultPodSecurityContext, &out.DefaultPodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -1018,6 +2848,46 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(v1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(SecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdentityMapping != nil {
+		in, out := &in.IdentityMapping, &out.IdentityMapping
+		*out = new(IdentityMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedRuntimeClassNames != nil {
+		in, out := &in.AllowedRuntimeClassNames, &out.AllowedRuntimeClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GPUSharing != nil {
+		in, out := &in.GPUSharing, &out.GPUSharing
+		*out = new(GPUSharing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedGPUSharingModes != nil {
+		in, out := &in.AllowedGPUSharingModes, &out.AllowedGPUSharingModes
+		*out = make([]GPUSharingMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisioningHints != nil {
+		in, out := &in.ProvisioningHints, &out.ProvisioningHints
+		*out = new(ProvisioningHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JupyterServerConfig != nil {
+		in, out := &in.JupyterServerConfig, &out.JupyterServerConfig
+		*out = new(JupyterServerConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultInitContainers != nil {
 		in, out := &in.DefaultInitContainers, &out.DefaultInitContainers
 		*out = make([]v1.Container, len(*in))
@@ -1045,6 +2915,11 @@ func (in *WorkspaceTemplateSpec) DeepCopy() *WorkspaceTemplateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceTemplateStatus) DeepCopyInto(out *WorkspaceTemplateStatus) {
 	*out = *in
+	if in.InUseBy != nil {
+		in, out := &in.InUseBy, &out.InUseBy
+		*out = new(TemplateUsage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceTemplateStatus.
@@ -1056,3 +2931,126 @@ func (in *WorkspaceTemplateStatus) DeepCopy() *WorkspaceTemplateStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceUsageStatus) DeepCopyInto(out *WorkspaceUsageStatus) {
+	*out = *in
+	if in.LastAccountedTime != nil {
+		in, out := &in.LastAccountedTime, &out.LastAccountedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Current != nil {
+		in, out := &in.Current, &out.Current
+		*out = new(WorkspaceResourceUtilization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RightSizing != nil {
+		in, out := &in.RightSizing, &out.RightSizing
+		*out = new(WorkspaceRightSizingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceUsageStatus.
+func (in *WorkspaceUsageStatus) DeepCopy() *WorkspaceUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceUserPreferences) DeepCopyInto(out *WorkspaceUserPreferences) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceUserPreferences.
+func (in *WorkspaceUserPreferences) DeepCopy() *WorkspaceUserPreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceUserPreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceUserPreferences) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceUserPreferencesList) DeepCopyInto(out *WorkspaceUserPreferencesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceUserPreferences, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceUserPreferencesList.
+func (in *WorkspaceUserPreferencesList) DeepCopy() *WorkspaceUserPreferencesList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceUserPreferencesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceUserPreferencesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceUserPreferencesSpec) DeepCopyInto(out *WorkspaceUserPreferencesSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceUserPreferencesSpec.
+func (in *WorkspaceUserPreferencesSpec) DeepCopy() *WorkspaceUserPreferencesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceUserPreferencesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceUtilizationRange) DeepCopyInto(out *WorkspaceUtilizationRange) {
+	*out = *in
+	out.Peak = in.Peak.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceUtilizationRange.
+func (in *WorkspaceUtilizationRange) DeepCopy() *WorkspaceUtilizationRange {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceUtilizationRange)
+	in.DeepCopyInto(out)
+	return out
+}
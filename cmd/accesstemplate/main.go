@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main implements the accesstemplate binary, which lints the templates of a
+// WorkspaceAccessStrategy file and previews what they render to for a workspace.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/accessrender"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
+)
+
+const usage = `Usage:
+  accesstemplate lint --strategy=strategy.yaml
+  accesstemplate render --strategy=strategy.yaml [--workspace=workspace.yaml]
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "lint":
+		runLint(os.Args[2:])
+	case "render":
+		runRender(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// runLint reports the parse errors of all the templates of a strategy, one per line
+func runLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	strategyFile := flags.String("strategy", "", "WorkspaceAccessStrategy file to lint.")
+	_ = flags.Parse(args)
+
+	strategy := readAccessStrategy(*strategyFile)
+	exitOnLintErrors(*strategyFile, strategy)
+	log.Printf("%s: %d templates OK", *strategyFile, len(accesstemplate.Templates(strategy)))
+}
+
+// runRender prints the access resources and URLs the strategy renders to for a workspace
+func runRender(args []string) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+
+	if err := accessrender.Run("render", args, scheme, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exitOnLintErrors prints the template errors of the strategy, one per line, and exits if there are any
+func exitOnLintErrors(file string, strategy *workspacev1alpha1.WorkspaceAccessStrategy) {
+	errs := accesstemplate.Lint(strategy)
+	if len(errs) == 0 {
+		return
+	}
+	for _, err := range errs {
+		log.Printf("%s: %v", file, err)
+	}
+	os.Exit(1)
+}
+
+// readAccessStrategy reads a WorkspaceAccessStrategy from a YAML file
+func readAccessStrategy(file string) *workspacev1alpha1.WorkspaceAccessStrategy {
+	if file == "" {
+		log.Fatalf("--strategy must be set")
+	}
+	strategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	if err := accessrender.ReadObject(file, strategy); err != nil {
+		log.Fatal(err)
+	}
+	return strategy
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main provides the entry point for the API gateway, which exposes a REST API to list,
+// create, start and stop workspaces for clients without direct cluster access.
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/apiserver"
+)
+
+func main() {
+	var port int
+	var certPath string
+	var keyPath string
+	var audiences string
+
+	flag.IntVar(&port, "port", apiserver.DefaultServerPort, "Port of the API gateway server")
+	flag.StringVar(&certPath, "tls-cert-file", "",
+		"Certificate file to serve HTTPS with. The server serves plain HTTP when unset, e.g. behind a TLS-terminating ingress.")
+	flag.StringVar(&keyPath, "tls-key-file", "", "Private key file of --tls-cert-file")
+	flag.StringVar(&audiences, "token-audiences", "",
+		"Comma-separated audiences bearer tokens are reviewed against. "+
+			"Defaults to the audience of the Kubernetes API server.")
+	opts := zap.Options{
+		Development: false,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog := ctrl.Log.WithName("setup")
+
+	if (certPath == "") != (keyPath == "") {
+		setupLog.Error(nil, "--tls-cert-file and --tls-key-file must be set together")
+		os.Exit(1)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+
+	configOpts := []apiserver.ConfigOption{
+		apiserver.WithServerPort(port),
+		apiserver.WithTLS(certPath, keyPath),
+	}
+	if audiences != "" {
+		configOpts = append(configOpts, apiserver.WithAudiences(splitList(audiences)))
+	}
+
+	logger := ctrl.Log.WithName("apiserver")
+	server, err := apiserver.NewAPIServerForConfig(apiserver.NewConfig(configOpts...), &logger, ctrl.GetConfigOrDie(), scheme)
+	if err != nil {
+		setupLog.Error(err, "Failed to create API gateway server")
+		os.Exit(1)
+	}
+
+	// Start server (blocks until signal or error)
+	if err := server.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "API gateway server exited with error")
+		os.Exit(1)
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
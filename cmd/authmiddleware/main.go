@@ -11,6 +11,7 @@ import (
 	"os"
 
 	"github.com/jupyter-infra/jupyter-k8s/internal/authmiddleware"
+	"github.com/jupyter-infra/jupyter-k8s/internal/metricsauth"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -19,7 +20,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
 func main() {
@@ -57,12 +57,16 @@ func main() {
 	scheme := runtime.NewScheme()
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	metricsServerOptions, err := metricsauth.ServerOptions(cfg.MetricsOptions())
+	if err != nil {
+		setupLog.Error(err, "Invalid metrics server configuration")
+		os.Exit(1)
+	}
+
 	// Create manager with namespace-scoped cache
 	mgr, err := ctrl.NewManager(k8sConfig, ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: cfg.MetricsAddr,
-		},
+		Scheme:                 scheme,
+		Metrics:                metricsServerOptions,
 		HealthProbeBindAddress: cfg.ProbeAddr,
 		LeaderElection:         false, // No leader election needed for stateless services
 		Cache: cache.Options{
@@ -0,0 +1,61 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main implements the benchgate binary, which compares two runs of the controller
+// benchmarks and fails when a benchmark regressed beyond the time or allocation thresholds.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/benchgate"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	base := flag.String("base", "", "Output of go test -bench -benchmem before the change.")
+	head := flag.String("head", "", "Output of go test -bench -benchmem after the change.")
+	maxTimeIncrease := flag.Float64("max-time-increase", 0.2,
+		"Relative increase of the median ns/op beyond which a benchmark regressed.")
+	maxAllocsIncrease := flag.Float64("max-allocs-increase", 0.05,
+		"Relative increase of the median allocs/op beyond which a benchmark regressed.")
+	flag.Parse()
+
+	if *base == "" || *head == "" {
+		log.Fatal("--base and --head are required")
+	}
+	baseResults, err := parseFile(*base)
+	if err != nil {
+		log.Fatal(err)
+	}
+	headResults, err := parseFile(*head)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	comparisons := benchgate.Compare(baseResults, headResults, benchgate.Thresholds{
+		Time:   *maxTimeIncrease,
+		Allocs: *maxAllocsIncrease,
+	})
+	if len(comparisons) == 0 {
+		log.Fatalf("no benchmark of %s is in %s", *head, *base)
+	}
+	if regressions := benchgate.Report(os.Stdout, comparisons); regressions > 0 {
+		log.Fatalf("%d benchmark(s) regressed", regressions)
+	}
+}
+
+func parseFile(path string) (benchgate.Results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open benchmark results: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return benchgate.Parse(f)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main implements the configbundle binary, which exports the operator configuration
+// of a cluster as a YAML bundle and imports it into another cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/configbundle"
+)
+
+const usage = `Usage:
+  configbundle export [--namespaces=ns1,ns2] [--operator-namespace=ns] [--output=bundle.yaml]
+  configbundle import --file=bundle.yaml [--dry-run] [--operator-namespace=ns]
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	switch os.Args[1] {
+	case "export":
+		runExport(ctx, os.Args[2:])
+	case "import":
+		runImport(ctx, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// runExport writes the bundle of the current cluster to a file or stdout
+func runExport(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	namespaces := flags.String("namespaces", "", "Comma-separated list of namespaces to export. Exports all namespaces if not set.")
+	operatorNamespace := flags.String("operator-namespace", "",
+		"Namespace of the controller manager, to export its flags. Skipped if not set.")
+	output := flags.String("output", "", "File to write the bundle to. Writes to stdout if not set.")
+	_ = flags.Parse(args)
+
+	bundle, err := configbundle.Export(ctx, newClient(), configbundle.ExportOptions{
+		Namespaces:        parseCommaSeparatedList(*namespaces),
+		OperatorNamespace: *operatorNamespace,
+	})
+	if err != nil {
+		log.Fatalf("Failed to export bundle: %v", err)
+	}
+
+	data, err := configbundle.Marshal(bundle)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o600); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+	log.Printf("Exported %d access strategies and %d templates to %s",
+		len(bundle.AccessStrategies), len(bundle.Templates), *output)
+}
+
+// runImport validates a bundle against the current cluster and applies it
+func runImport(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "Bundle file to import.")
+	dryRun := flags.Bool("dry-run", false, "Validate the bundle against the cluster without applying it.")
+	operatorNamespace := flags.String("operator-namespace", "",
+		"Namespace of the controller manager, to report drift from the bundle's operator flags. Skipped if not set.")
+	_ = flags.Parse(args)
+
+	if *file == "" {
+		log.Fatalf("--file must be set")
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+	bundle, err := configbundle.Parse(data)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	result, err := configbundle.Import(ctx, newClient(), bundle, configbundle.ImportOptions{
+		DryRun:            *dryRun,
+		OperatorNamespace: *operatorNamespace,
+	})
+	if result != nil {
+		keys := make([]string, 0, len(result.Actions))
+		for key := range result.Actions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if *dryRun {
+				log.Printf("%s %s (dry run)", key, result.Actions[key])
+			} else {
+				log.Printf("%s %s", key, result.Actions[key])
+			}
+		}
+		for _, drift := range result.OperatorDrift {
+			log.Printf("Operator drift: %s", drift)
+		}
+	}
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+}
+
+// newClient creates a client for the cluster of the current kubeconfig context
+func newClient() client.Client {
+	scheme := runtime.NewScheme()
+	if err := workspacev1alpha1.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to add workspace types to scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to add apps types to scheme: %v", err)
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		log.Fatalf("Failed to load kubeconfig: %v", err)
+	}
+	k8sClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes client: %v", err)
+	}
+	return k8sClient
+}
+
+// parseCommaSeparatedList splits a comma-separated flag value, dropping empty entries
+func parseCommaSeparatedList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main implements the loadsim binary, which creates synthetic workspaces against a test
+// cluster or envtest and reports the reconcile throughput, latency and API calls of the controller.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/loadsim"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	workspaces := flag.Int("workspaces", loadsim.DefaultWorkspaces, "Number of workspaces to create.")
+	namespace := flag.String("namespace", loadsim.DefaultNamespace,
+		"Namespace to create the workspaces in. Created if missing.")
+	creationConcurrency := flag.Int("creation-concurrency", loadsim.DefaultCreationConcurrency,
+		"Number of workspaces created in parallel.")
+	timeout := flag.Duration("timeout", loadsim.DefaultTimeout,
+		"How long to wait for the controller to reconcile all the workspaces.")
+	useEnvtest := flag.Bool("envtest", false,
+		"Run against a local envtest control plane instead of the cluster of the current kubeconfig context. "+
+			"Requires KUBEBUILDER_ASSETS.")
+	crdDir := flag.String("crd-dir", "config/crd/bases", "Directory of the CRDs to install in envtest.")
+	inProcessController := flag.Bool("in-process-controller", true,
+		"Run the controller in this process, to count its API calls. "+
+			"Set to false to measure a controller already running in the cluster.")
+	maxConcurrentReconciles := flag.Int("max-concurrent-reconciles", 1,
+		"Number of workspaces the in-process controller reconciles in parallel.")
+	cleanup := flag.Bool("cleanup", true, "Delete the objects of the simulation once it completes.")
+	format := flag.String("format", "text", "Report format: text or json.")
+	output := flag.String("output", "", "File to write the report to. Writes to stdout if not set.")
+	zapOpts := zap.Options{}
+	zapOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&zapOpts)))
+
+	if *format != "text" && *format != "json" {
+		log.Fatalf("--format must be text or json")
+	}
+
+	ctx, cancel := context.WithCancel(ctrl.SetupSignalHandler())
+	defer cancel()
+
+	config, stop := restConfig(*useEnvtest, *crdDir)
+	defer stop()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+
+	opts := loadsim.Options{
+		RunID:               fmt.Sprintf("%d", time.Now().Unix()),
+		Namespace:           *namespace,
+		Workspaces:          *workspaces,
+		CreationConcurrency: *creationConcurrency,
+		Timeout:             *timeout,
+		Cleanup:             *cleanup,
+	}
+	if *inProcessController {
+		opts.APICalls = loadsim.NewAPICallCounter()
+		startController(ctx, opts.APICalls.Wrap(config), scheme, *maxConcurrentReconciles)
+	}
+
+	// The simulation uses its own client, so that its requests are not counted as controller calls
+	k8sClient, err := client.NewWithWatch(config, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	log.Printf("Creating %d workspaces in namespace %s (run %s)", opts.Workspaces, opts.Namespace, opts.RunID)
+	report, err := loadsim.Run(ctx, k8sClient, opts)
+	if err != nil {
+		log.Fatalf("Load simulation failed: %v", err)
+	}
+
+	if err := writeReport(report, *format, *output); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	if report.Reconciled < report.Workspaces {
+		log.Printf("%d workspaces were not reconciled within %s", report.Workspaces-report.Reconciled, *timeout)
+	}
+}
+
+// restConfig returns the config of the cluster to load, and a function releasing it
+func restConfig(useEnvtest bool, crdDir string) (*rest.Config, func()) {
+	if !useEnvtest {
+		config, err := ctrl.GetConfig()
+		if err != nil {
+			log.Fatalf("Failed to load kubeconfig: %v", err)
+		}
+		return config, func() {}
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{crdDir},
+		ErrorIfCRDPathMissing: true,
+	}
+	config, err := testEnv.Start()
+	if err != nil {
+		log.Fatalf("Failed to start envtest: %v", err)
+	}
+	return config, func() {
+		if err := testEnv.Stop(); err != nil {
+			log.Printf("Failed to stop envtest: %v", err)
+		}
+	}
+}
+
+// startController runs the workspace, template and access strategy controllers in this process
+func startController(ctx context.Context, config *rest.Config, scheme *runtime.Scheme, maxConcurrentReconciles int) {
+	mgr, err := ctrl.NewManager(config, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create manager: %v", err)
+	}
+
+	accessStrategyFanOut := controller.NewAccessStrategyFanOut(0, 0)
+	if err := controller.SetupWorkspaceController(mgr, controller.WorkspaceControllerOptions{
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		AccessStrategyFanOut:    accessStrategyFanOut,
+	}); err != nil {
+		log.Fatalf("Failed to set up workspace controller: %v", err)
+	}
+	if err := controller.SetupWorkspaceTemplateController(mgr); err != nil {
+		log.Fatalf("Failed to set up workspace template controller: %v", err)
+	}
+	if err := controller.SetupWorkspaceAccessStrategyController(mgr, accessStrategyFanOut, nil); err != nil {
+		log.Fatalf("Failed to set up workspace access strategy controller: %v", err)
+	}
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			log.Fatalf("Controller stopped: %v", err)
+		}
+	}()
+	if !mgr.GetCache().WaitForCacheSync(ctx) {
+		log.Fatalf("Failed to sync the controller cache")
+	}
+}
+
+// writeReport writes the report to a file or stdout
+func writeReport(report *loadsim.Report, format string, output string) error {
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		w = file
+	}
+
+	if format == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteText(w)
+}
@@ -24,10 +24,12 @@ SOFTWARE.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -36,8 +38,11 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,14 +50,21 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/accessrender"
+	"github.com/jupyter-infra/jupyter-k8s/internal/accounting"
+	"github.com/jupyter-infra/jupyter-k8s/internal/certrotator"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 	"github.com/jupyter-infra/jupyter-k8s/internal/extensionapi"
+	"github.com/jupyter-infra/jupyter-k8s/internal/hubactivity"
+	"github.com/jupyter-infra/jupyter-k8s/internal/imageverify"
+	"github.com/jupyter-infra/jupyter-k8s/internal/landing"
+	"github.com/jupyter-infra/jupyter-k8s/internal/metricsauth"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 	webhookv1alpha1 "github.com/jupyter-infra/jupyter-k8s/internal/webhook/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -70,6 +82,7 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -123,23 +136,146 @@ func parsePluginEndpoints(raw string) (map[string]string, error) {
 	return endpoints, nil
 }
 
+// parseTrustedNamespaceSelector parses the label selector for namespaces whose access
+// strategies may be referenced from any namespace. An empty value trusts no labeled namespace.
+func parseTrustedNamespaceSelector(raw string) (labels.Selector, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted namespace selector %q: %w", raw, err)
+	}
+	return selector, nil
+}
+
+// parseCommaSeparatedList parses a comma-separated list, dropping empty items
+func parseCommaSeparatedList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// defaultLeaderElectionID is the base name of the leader election lease
+const defaultLeaderElectionID = "a446807b.jupyter.org"
+
+// buildCacheOptions restricts the manager cache to the watched namespaces, plus the shared
+// template namespace so that shared templates and access strategies can still be resolved.
+// An empty watchNamespaces watches all namespaces.
+// Secrets are only watched in the controller's namespace to avoid requiring cluster-wide
+// secret list/watch permissions (needed for JWT secret informer).
+func buildCacheOptions(watchNamespaces []string, defaultTemplateNamespace, podNamespace string) cache.Options {
+	options := cache.Options{}
+
+	if len(watchNamespaces) > 0 {
+		options.DefaultNamespaces = map[string]cache.Config{}
+		for _, namespace := range watchNamespaces {
+			options.DefaultNamespaces[namespace] = cache.Config{}
+		}
+		if defaultTemplateNamespace != "" {
+			options.DefaultNamespaces[defaultTemplateNamespace] = cache.Config{}
+		}
+	}
+
+	if podNamespace != "" {
+		options.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {
+				Namespaces: map[string]cache.Config{
+					podNamespace: {},
+				},
+			},
+			&workspacev1alpha1.OperatorConfig{}: {
+				Namespaces: map[string]cache.Config{
+					podNamespace: {},
+				},
+			},
+		}
+	}
+	return options
+}
+
 // nolint:gocyclo
+// renderAccessCommand is the manager subcommand printing the access resources and URLs an access
+// strategy renders to for a workspace, without applying them or connecting to the cluster
+const renderAccessCommand = "render-access"
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderAccessCommand {
+		if err := accessrender.Run(renderAccessCommand, os.Args[2:], scheme, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
+	var metricsAuth, metricsClientCAFile string
 	var webhookCertPath, webhookCertName, webhookCertKey string
+	var certRotation bool
+	var certRotationSecretName, certRotationDNSNames string
+	var certRotationValidatingWebhooks, certRotationMutatingWebhooks string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	var applicationImagesPullPolicy string
 	var applicationImagesRegistry string
+	var applicationImagesPullSecret string
+	var objectStorageSyncImage string
 	var watchTraefik bool
 	var enableExtensionAPI bool
+	var enableLandingPage bool
+	var landingPagePort int
+	var landingPageTitle string
 	var watchResourcesGVK string
+	var accessProvidersFlag string
 	var enableWorkspacePodWatching bool
+	var remoteAccessCleanupDryRun bool
+	var enableWorkspaceSnapshots bool
+	var enableNodeInterruptionHandling bool
+	var enableUsageAccounting bool
+	var usageAccountingInterval time.Duration
+	var usageReportDir string
+	var usageReportFormat string
+	var usageReportInterval time.Duration
+	var enableResourceMetrics bool
+	var resourceMetricsInterval time.Duration
+	var resourceMetricsPrometheusURL string
+	var resourceMetricsGPUQuery string
+	var enableRightSizing bool
+	var rightSizingWindow time.Duration
+	var rightSizingLowUtilization int
+	var rightSizingHighUtilization int
+	var rightSizingAutoApply bool
+	var vanityURLWebhookURL string
+	var groupResolverURL string
 	var defaultTemplateNamespace string
+	var sameNamespaceTemplatesOnly bool
+	var enableTemplateCatalog bool
+	var templateCatalogConfigMap string
+	var requireTemplate bool
+	var operatorConfigName string
+	var finalizerJanitorInterval time.Duration
+	var watchNamespacesFlag string
+	var accessStrategyTrustedNamespaceSelector string
+	var workspaceMaxAnnotationsSize int
+	var workspaceMaxLabels int
+	var workspaceReservedMetadataPrefixes string
+	var allowPrivilegedWorkspaces bool
+	var imageSignaturePolicyFile string
+	var imageSignatureRegistryAuthFile string
+	var externalPolicyURL string
+	var externalPolicyFailOpen bool
 	var jwtIssuer string
 	var jwtAudience string
 	var jwtSecretName string
@@ -147,12 +283,36 @@ func main() {
 	var newKeyUseDelay time.Duration
 	var pluginEndpointsFlag string
 	var idleCheckInterval time.Duration
+	var idleNotificationWebhookURL string
+	var idleNotificationGracePeriod time.Duration
+	var enableHubActivityAPI bool
+	var hubActivityAPIPort int
+	var hubActivityAPIURL string
+	var workspaceMaxConcurrentReconciles int
+	var reconcileRequeueBase time.Duration
+	var workspaceProgressingTimeout time.Duration
+	var accessStrategyFanOutBurst int
+	var accessStrategyFanOutQPS float64
+	var resourceNamePrefix string
+	var adoptableResourceNamePrefixes string
+	var adoptOrphanedResources bool
+	var shardIndex int
+	var shardCount int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "",
+		"Name of the leader election lease. Defaults to "+defaultLeaderElectionID+
+			" suffixed with the controller namespace, so that several installs can share a cluster.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Duration that non-leader candidates wait before forcing to acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration that the acting leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration the leader election clients wait between tries of actions.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -162,22 +322,138 @@ func main() {
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	flag.BoolVar(&certRotation, "cert-rotation", false,
+		"Provision and rotate the webhook and metrics server certificates with a self-signed CA stored in a Secret, "+
+			"for clusters that do not run cert-manager")
+	flag.StringVar(&certRotationSecretName, "cert-rotation-secret-name", "jupyter-k8s-webhook-server-cert",
+		"The Secret holding the CA and the serving certificate with --cert-rotation, in the controller namespace")
+	flag.StringVar(&certRotationDNSNames, "cert-rotation-dns-names", "",
+		"Comma-separated DNS names of the serving certificate with --cert-rotation, "+
+			"e.g. the names of the webhook and metrics Services")
+	flag.StringVar(&certRotationValidatingWebhooks, "cert-rotation-validating-webhook-configurations", "",
+		"Comma-separated ValidatingWebhookConfigurations whose CA bundle is patched with --cert-rotation")
+	flag.StringVar(&certRotationMutatingWebhooks, "cert-rotation-mutating-webhook-configurations", "",
+		"Comma-separated MutatingWebhookConfigurations whose CA bundle is patched with --cert-rotation")
+	flag.StringVar(&metricsAuth, "metrics-auth", "",
+		"Authentication of the metrics endpoint: token (Kubernetes TokenReview and SubjectAccessReview), "+
+			"mtls (client certificates signed by --metrics-client-ca-file) or none. "+
+			"Defaults to token over HTTPS and none over HTTP.")
+	flag.StringVar(&metricsClientCAFile, "metrics-client-ca-file", "",
+		"PEM bundle of the CAs signing the client certificates accepted with --metrics-auth=mtls")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.StringVar(&applicationImagesPullPolicy, "application-images-pull-policy", "",
 		"Image pull policy for Application containers (Always, IfNotPresent, or Never)")
 	flag.StringVar(&applicationImagesRegistry, "application-images-registry", "",
 		"Registry prefix for application images (e.g. example.com/my-registry)")
+	flag.StringVar(&applicationImagesPullSecret, "application-images-pull-secret", "",
+		"Name of a Secret added to the image pull secrets of every workspace pod. "+
+			"The Secret must exist in the namespace of each workspace")
+	flag.StringVar(&objectStorageSyncImage, "object-storage-sync-image", "",
+		"Image of the sidecar syncing the object storage mounts of workspaces in Sync mode")
 	flag.BoolVar(&watchTraefik, "watch-traefik", false,
 		"Watch traefik sub-resources (easy mode)")
 	flag.BoolVar(&enableExtensionAPI, "enable-extension-api", false,
 		"Enable extension API server")
+	flag.BoolVar(&enableLandingPage, "enable-landing-page", false,
+		"Enable the landing page that lists the workspaces of the caller and links to their access URLs. "+
+			"Must be exposed behind an authenticating proxy that sets the X-Auth-Request-User header.")
+	flag.IntVar(&landingPagePort, "landing-page-port", landing.DefaultServerPort,
+		"Port of the landing page server")
+	flag.StringVar(&landingPageTitle, "landing-page-title", landing.DefaultTitle,
+		"Title of the landing page")
 	flag.StringVar(&watchResourcesGVK, "watch-resources-gvk", "",
 		"Comma-separated list of Group/Version/Kind to watch (format: group/version/kind,group/version/kind,...)")
+	flag.StringVar(&accessProvidersFlag, "access-providers", "",
+		"Comma-separated list of access providers whose resources to watch (e.g. traefik,ingress,gateway-api,istio)")
 	flag.BoolVar(&enableWorkspacePodWatching, "enable-workspace-pod-watching", false,
 		"Enable workspace pod event watching for workspace lifecycle management")
+	flag.BoolVar(&remoteAccessCleanupDryRun, "remote-access-cleanup-dry-run", false,
+		"Log the remote access resources of deleted workspace pods instead of releasing them")
+	flag.BoolVar(&enableWorkspaceSnapshots, "enable-workspace-snapshots", false,
+		"Enable the WorkspaceSnapshot controller (requires the snapshot.storage.k8s.io CRDs)")
+	flag.BoolVar(&enableNodeInterruptionHandling, "enable-node-interruption-handling", false,
+		"Reschedule running workspaces from nodes about to be terminated, e.g. on spot interruptions")
+	flag.BoolVar(&enableUsageAccounting, "enable-usage-accounting", false,
+		"Account the running time and resource-request-hours of workspaces in their status and in metrics")
+	flag.DurationVar(&usageAccountingInterval, "usage-accounting-interval", accounting.DefaultInterval,
+		"How often the usage of running workspaces is checkpointed in their status")
+	flag.StringVar(&usageReportDir, "usage-report-dir", "",
+		"Directory, e.g. a mounted PVC or S3 bucket, to periodically write usage reports to. "+
+			"When empty, usage reports are disabled")
+	flag.StringVar(&usageReportFormat, "usage-report-format", accounting.FormatCSV,
+		"Format of usage reports: csv or json")
+	flag.DurationVar(&usageReportInterval, "usage-report-interval", accounting.DefaultReportInterval,
+		"How often usage reports are written")
+	flag.BoolVar(&enableResourceMetrics, "enable-resource-metrics", false,
+		"Sample the live CPU, memory and GPU usage of running workspaces from the resource metrics API "+
+			"(requires metrics-server) into their status.usage.current")
+	flag.DurationVar(&resourceMetricsInterval, "resource-metrics-interval", accounting.DefaultSampleInterval,
+		"How often the live usage of running workspaces is sampled")
+	flag.StringVar(&resourceMetricsPrometheusURL, "resource-metrics-prometheus-url", "",
+		"URL of the Prometheus API queried for the GPU utilization of workspaces. "+
+			"When empty, the GPU utilization is not sampled")
+	flag.StringVar(&resourceMetricsGPUQuery, "resource-metrics-gpu-query", accounting.DefaultGPUQuery,
+		"Prometheus query of the GPU utilization of a workspace pod, in percent, with {namespace} and {pod} placeholders")
+	flag.BoolVar(&enableRightSizing, "enable-right-sizing", false,
+		"Recommend requests to workspaces that consistently use too little or too much of their CPU or memory "+
+			"requests, in their RightSizingRecommended condition (requires --enable-resource-metrics)")
+	flag.DurationVar(&rightSizingWindow, "right-sizing-window", accounting.DefaultRightSizingWindow,
+		"Running time over which the utilization of the requests of a workspace is observed before recommending requests")
+	flag.IntVar(&rightSizingLowUtilization, "right-sizing-low-utilization", accounting.DefaultLowUtilizationPercent,
+		"Utilization of a request, in percent, below which a workspace is recommended a lower request")
+	flag.IntVar(&rightSizingHighUtilization, "right-sizing-high-utilization", accounting.DefaultHighUtilizationPercent,
+		"Utilization of a request, in percent, above which a workspace is recommended a higher request")
+	flag.BoolVar(&rightSizingAutoApply, "right-sizing-auto-apply", false,
+		"Apply the requests recommended to a workspace when it next starts, within the resource bounds of its template")
+	flag.StringVar(&vanityURLWebhookURL, "vanity-url-webhook-url", "",
+		"URL of a webhook registering workspace access URLs with a URL shortener or DNS registrar. "+
+			"When empty, vanity URLs are disabled")
+	flag.StringVar(&groupResolverURL, "group-resolver-url", "",
+		"URL of a webhook resolving the groups of users, to authorize the members of the owner group "+
+			"of GroupOnly workspaces. When empty, only the groups of the request are used")
 	flag.StringVar(&defaultTemplateNamespace, "default-template-namespace", "",
 		"Default namespace for WorkspaceTemplate resolution when templateRef.namespace is not specified")
+	flag.BoolVar(&sameNamespaceTemplatesOnly, "same-namespace-templates-only", false,
+		"Only allow workspaces to reference templates and access strategies from their own namespace, "+
+			"ignoring --default-template-namespace")
+	flag.BoolVar(&enableTemplateCatalog, "enable-template-catalog", false,
+		"Maintain a catalog of the workspace templates, indexed by the users and groups that may use them, "+
+			"in a ConfigMap of the controller namespace")
+	flag.StringVar(&templateCatalogConfigMap, "template-catalog-configmap", controller.DefaultTemplateCatalogConfigMapName,
+		"Name of the ConfigMap holding the template catalog (requires --enable-template-catalog)")
+	flag.BoolVar(&requireTemplate, "require-template", false,
+		"Reject the workspaces that users other than cluster admins create without a templateRef, or update to remove it")
+	flag.StringVar(&operatorConfigName, "operator-config-name", operatorconfig.DefaultName,
+		"Name of the OperatorConfig of the controller namespace whose settings override the manager flags")
+	flag.DurationVar(&finalizerJanitorInterval, "finalizer-janitor-interval", controller.DefaultFinalizerJanitorInterval,
+		"Interval between the sweeps removing the protection finalizers of templates and access strategies "+
+			"that nothing references anymore (0 disables the sweeps)")
+	flag.StringVar(&watchNamespacesFlag, "watch-namespaces", "",
+		"Comma-separated list of namespaces to watch. Watches all namespaces if not set.")
+	flag.StringVar(&accessStrategyTrustedNamespaceSelector, "access-strategy-trusted-namespace-selector", "",
+		"Label selector for namespaces whose WorkspaceAccessStrategies may be referenced from any namespace "+
+			"(e.g. workspace.jupyter.org/shared-access-strategies=true)")
+	flag.IntVar(&workspaceMaxAnnotationsSize, "workspace-max-annotations-size", webhookv1alpha1.DefaultMaxAnnotationsSize,
+		"Maximum total size in bytes of the annotations users may set on a workspace (0 for unlimited)")
+	flag.IntVar(&workspaceMaxLabels, "workspace-max-labels", webhookv1alpha1.DefaultMaxLabels,
+		"Maximum number of labels users may set on a workspace (0 for unlimited)")
+	flag.StringVar(&workspaceReservedMetadataPrefixes, "workspace-reserved-metadata-prefixes", "",
+		"Comma-separated list of label and annotation key prefixes users may not set on workspaces "+
+			"(e.g. example.com/,billing.example.com/)")
+	flag.BoolVar(&allowPrivilegedWorkspaces, "allow-privileged-workspaces", false,
+		"Allow workspaces to run privileged containers, host processes or capabilities beyond the baseline "+
+			"Pod Security Standard")
+	flag.StringVar(&imageSignaturePolicyFile, "image-signature-policy-file", "",
+		"Path of an image signature policy file. When set, workspace images must be pinned by digest and carry "+
+			"a cosign signature that verifies against its public keys or keyless identities")
+	flag.StringVar(&imageSignatureRegistryAuthFile, "image-signature-registry-auth-file", "",
+		"Path of a docker config file with the credentials to read image signatures from private registries")
+	flag.StringVar(&externalPolicyURL, "external-policy-url", "",
+		"URL of an external policy engine, e.g. the OPA data API of a rule producing violations, that the "+
+			"workspace webhook posts the admitted workspace and its template to. When empty, no external policy applies")
+	flag.BoolVar(&externalPolicyFailOpen, "external-policy-fail-open", false,
+		"Admit workspaces when the external policy engine cannot be reached, instead of rejecting them")
 	flag.StringVar(&jwtIssuer, "jwt-issuer", "",
 		"JWT issuer claim. Uses server default if not set.")
 	flag.StringVar(&jwtAudience, "jwt-audience", "",
@@ -192,6 +468,45 @@ func main() {
 		"Comma-separated list of plugin name=endpoint pairs (e.g. aws=http://localhost:8080)")
 	flag.DurationVar(&idleCheckInterval, "idle-check-interval", controller.DefaultIdleCheckInterval,
 		"Interval between idle status checks for running workspaces")
+	flag.StringVar(&idleNotificationWebhookURL, "idle-notification-webhook-url", "",
+		"URL of a webhook, e.g. a Slack or SMTP bridge, notifying the owners of idle workspaces before "+
+			"they are stopped. When empty, idle workspaces are stopped without notice")
+	flag.DurationVar(&idleNotificationGracePeriod, "idle-notification-grace-period",
+		controller.DefaultIdleNotificationGracePeriod,
+		"Time between the notification of an idle workspace and its stop, in which the owner can keep it alive")
+	flag.BoolVar(&enableHubActivityAPI, "enable-hub-activity-api", false,
+		"Enable the JupyterHub activity API, to which workspaces with hub activity detection report their activity")
+	flag.IntVar(&hubActivityAPIPort, "hub-activity-api-port", hubactivity.DefaultServerPort,
+		"Port of the JupyterHub activity API server")
+	flag.StringVar(&hubActivityAPIURL, "hub-activity-api-url", "",
+		"URL at which workspace pods reach the JupyterHub activity API server, e.g. the URL of its Service. "+
+			"When empty, workspaces with hub activity detection are not given the JupyterHub environment")
+	flag.IntVar(&workspaceMaxConcurrentReconciles, "workspace-max-concurrent-reconciles", 1,
+		"Number of workspaces reconciled in parallel")
+	flag.DurationVar(&reconcileRequeueBase, "reconcile-requeue-base", controller.DefaultRequeueBaseDelay,
+		"Delay before the first retry of a failed workspace reconcile; retries back off exponentially")
+	flag.DurationVar(&workspaceProgressingTimeout, "workspace-progressing-timeout", controller.DefaultProgressingTimeout,
+		"How long a starting workspace may stay in the same Progressing state before it is flagged as stalled. "+
+			"0 disables the progressing watchdog")
+	flag.IntVar(&accessStrategyFanOutBurst, "access-strategy-fanout-burst", controller.DefaultAccessStrategyFanOutBurst,
+		"Number of workspaces updated at once when AccessStrategies change, before the fan-out QPS applies")
+	flag.Float64Var(&accessStrategyFanOutQPS, "access-strategy-fanout-qps", controller.DefaultAccessStrategyFanOutQPS,
+		"Number of workspaces updated per second when AccessStrategies change, across all AccessStrategies")
+	flag.StringVar(&resourceNamePrefix, "resource-name-prefix", controller.ResourcePrefix,
+		"Prefix of the names of the deployments, services, PVCs and secrets of new workspaces. "+
+			"Set distinct prefixes for operator installations that share namespaces")
+	flag.StringVar(&adoptableResourceNamePrefixes, "adoptable-resource-name-prefixes", "",
+		"Comma-separated list of prefixes, besides the default one, under which existing workspaces adopt "+
+			"uncontrolled deployments and PVCs, e.g. those of a previous notebook operator")
+	flag.BoolVar(&adoptOrphanedResources, "adopt-orphaned-resources", true,
+		"Let workspaces adopt the deployments, services and PVCs under their names that no object controls, "+
+			"e.g. after a restore from backup or a reinstall of the operator")
+	flag.IntVar(&shardIndex, "shard-index", 0,
+		"Shard of the workspaces this manager reconciles, from 0 to shard-count minus one. "+
+			"Shard 0 coordinates the shards and runs the other controllers and the webhooks")
+	flag.IntVar(&shardCount, "shard-count", 1,
+		"Number of shards the workspaces are split into by a hash of their namespace, "+
+			"each reconciled by its own manager replicas. 1 disables sharding")
 	opts := zap.Options{
 		Development: false,
 	}
@@ -200,6 +515,16 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	sharding := controller.Sharding{Index: shardIndex, Count: shardCount}
+	if err := sharding.Validate(); err != nil {
+		setupLog.Error(err, "invalid sharding flags")
+		os.Exit(1)
+	}
+	if sharding.Enabled() {
+		setupLog.Info("Sharding workspaces by namespace", "shard-index", shardIndex, "shard-count", shardCount,
+			"coordinator", sharding.IsCoordinator())
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -215,6 +540,18 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	// With certificate rotation, the rotator writes the serving certificate of the webhook
+	// and metrics servers before the manager starts
+	if certRotation {
+		if webhookCertPath == "" {
+			webhookCertPath = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+		webhookCertName, webhookCertKey = certrotator.CertKey, certrotator.KeyKey
+		if metricsCertPath != "" {
+			metricsCertName, metricsCertKey = certrotator.CertKey, certrotator.KeyKey
+		}
+	}
+
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
 	webhookServerOptions := webhook.Options{
@@ -236,18 +573,15 @@ func main() {
 	// More info:
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
-	metricsServerOptions := metricsserver.Options{
-		BindAddress:   metricsAddr,
-		SecureServing: secureMetrics,
-		TLSOpts:       tlsOpts,
-	}
-
-	if secureMetrics {
-		// FilterProvider is used to protect the metrics endpoint with authn/authz.
-		// These configurations ensure that only authorized users and service accounts
-		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
-		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/metrics/filters#WithAuthenticationAndAuthorization
-		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	//
+	// With token authentication, only authorized users and service accounts can access the metrics endpoint.
+	// The RBAC are configured in 'config/rbac/kustomization.yaml'.
+	metricsOptions := metricsauth.Options{
+		BindAddress:  metricsAddr,
+		Secure:       secureMetrics,
+		Auth:         metricsAuth,
+		ClientCAFile: metricsClientCAFile,
+		TLSOpts:      tlsOpts,
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -262,18 +596,29 @@ func main() {
 		setupLog.Info("Initializing metrics certificate watcher using provided certificates",
 			"metrics-cert-path", metricsCertPath, "metrics-cert-name", metricsCertName, "metrics-cert-key", metricsCertKey)
 
-		metricsServerOptions.CertDir = metricsCertPath
-		metricsServerOptions.CertName = metricsCertName
-		metricsServerOptions.KeyName = metricsCertKey
+		metricsOptions.CertDir = metricsCertPath
+		metricsOptions.CertName = metricsCertName
+		metricsOptions.KeyName = metricsCertKey
+	}
+
+	metricsServerOptions, err := metricsauth.ServerOptions(metricsOptions)
+	if err != nil {
+		setupLog.Error(err, "invalid metrics server configuration")
+		os.Exit(1)
 	}
+	setupLog.Info("Configured metrics endpoint", "bind-address", metricsAddr, "auth", metricsOptions.ResolveAuth())
 
+	podNamespace := os.Getenv("CONTROLLER_POD_NAMESPACE")
 	mgrOptions := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "a446807b.jupyter.org",
+		LeaderElectionID:       sharding.LeaderElectionID(controller.ResolveLeaderElectionID(leaderElectionID, defaultLeaderElectionID, podNamespace)),
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -287,18 +632,30 @@ func main() {
 		// LeaderElectionReleaseOnCancel: true,
 	}
 
-	// Scope Secret watching to the controller's namespace to avoid requiring
-	// cluster-wide secret list/watch permissions (needed for JWT secret informer).
-	if podNamespace := os.Getenv("CONTROLLER_POD_NAMESPACE"); podNamespace != "" {
-		mgrOptions.Cache = cache.Options{
-			ByObject: map[client.Object]cache.ByObject{
-				&corev1.Secret{}: {
-					Namespaces: map[string]cache.Config{
-						podNamespace: {},
-					},
-				},
-			},
+	// Shared templates are disabled when workspaces may only use their own namespace
+	if sameNamespaceTemplatesOnly && defaultTemplateNamespace != "" {
+		setupLog.Info("Ignoring the default template namespace in same-namespace-templates-only mode",
+			"default-template-namespace", defaultTemplateNamespace)
+		defaultTemplateNamespace = ""
+	}
+
+	watchNamespaces := parseCommaSeparatedList(watchNamespacesFlag)
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("Restricting the controller to namespaces", "namespaces", watchNamespaces)
+	}
+	mgrOptions.Cache = buildCacheOptions(watchNamespaces, defaultTemplateNamespace, podNamespace)
+
+	// The other shards only cache the workspaces of their shard, the coordinator caches all of them
+	if !sharding.IsCoordinator() {
+		shardSelector, err := sharding.Selector()
+		if err != nil {
+			setupLog.Error(err, "unable to build shard selector")
+			os.Exit(1)
 		}
+		if mgrOptions.Cache.ByObject == nil {
+			mgrOptions.Cache.ByObject = map[client.Object]cache.ByObject{}
+		}
+		mgrOptions.Cache.ByObject[&workspacev1alpha1.Workspace{}] = cache.ByObject{Label: shardSelector}
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
@@ -307,6 +664,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	if certRotation {
+		certDirs := []string{webhookCertPath}
+		if metricsCertPath != "" {
+			certDirs = append(certDirs, metricsCertPath)
+		}
+		if err := certrotator.SetupCertRotatorWithManager(mgr, certrotator.Options{
+			SecretName:                      certRotationSecretName,
+			Namespace:                       os.Getenv("CONTROLLER_POD_NAMESPACE"),
+			DNSNames:                        parseCommaSeparatedList(certRotationDNSNames),
+			CertDirs:                        certDirs,
+			ValidatingWebhookConfigurations: parseCommaSeparatedList(certRotationValidatingWebhooks),
+			MutatingWebhookConfigurations:   parseCommaSeparatedList(certRotationMutatingWebhooks),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up certificate rotation")
+			os.Exit(1)
+		}
+		setupLog.Info("Certificate rotation enabled", "secret", certRotationSecretName, "cert-dirs", certDirs)
+	}
+
 	// Parse GVK watches
 	gvkWatches, err := parseGVKWatches(watchResourcesGVK)
 	if err != nil {
@@ -314,6 +690,32 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Hold the settings the OperatorConfig of the controller namespace overrides at runtime,
+	// starting from the manager flags
+	resourceWatches := make([]schema.GroupVersionKind, 0, len(gvkWatches))
+	for _, watch := range gvkWatches {
+		resourceWatches = append(resourceWatches, schema.GroupVersionKind{
+			Group:   watch.Group,
+			Version: watch.Version,
+			Kind:    watch.Kind,
+		})
+	}
+	operatorConfig := operatorconfig.NewStore(operatorconfig.Settings{
+		ApplicationImagesRegistry:   applicationImagesRegistry,
+		ApplicationImagesPullPolicy: getImagePullPolicy(applicationImagesPullPolicy),
+		ApplicationImagesPullSecret: applicationImagesPullSecret,
+		RequireTemplate:             requireTemplate,
+		IdleCheckInterval:           idleCheckInterval,
+		ResourceWatches:             resourceWatches,
+	})
+	if podNamespace != "" {
+		if err := controller.LoadOperatorConfig(
+			context.Background(), mgr.GetAPIReader(), operatorConfig, podNamespace, operatorConfigName); err != nil {
+			setupLog.Error(err, "unable to load operator config")
+			os.Exit(1)
+		}
+	}
+
 	// Parse plugin endpoints
 	pluginEndpoints, err := parsePluginEndpoints(pluginEndpointsFlag)
 	if err != nil {
@@ -321,20 +723,96 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Configure the names of the child resources of workspaces
+	if err := controller.ConfigureResourceNaming(
+		resourceNamePrefix, parseCommaSeparatedList(adoptableResourceNamePrefixes)); err != nil {
+		setupLog.Error(err, "Error configuring resource name prefixes")
+		os.Exit(1)
+	}
+	controller.ConfigureOrphanAdoption(adoptOrphanedResources)
+
+	// Parse the access strategy namespace trust policy
+	trustedNamespaceSelector, err := parseTrustedNamespaceSelector(accessStrategyTrustedNamespaceSelector)
+	if err != nil {
+		setupLog.Error(err, "Error parsing access strategy trusted namespace selector")
+		os.Exit(1)
+	}
+
+	// Limits on the labels and annotations users may set on workspaces
+	workspaceMetadataLimits := webhookv1alpha1.MetadataLimits{
+		MaxAnnotationsSize:  workspaceMaxAnnotationsSize,
+		MaxLabels:           workspaceMaxLabels,
+		ReservedKeyPrefixes: parseCommaSeparatedList(workspaceReservedMetadataPrefixes),
+	}
+
+	// Propagate AccessStrategy changes to their workspaces in batches. With sharding, the workspace
+	// controller of each shard watches AccessStrategies itself, since the fan-out only feeds the
+	// workspace controller of the manager running the AccessStrategy controller.
+	var accessStrategyFanOut *controller.AccessStrategyFanOut
+	if !sharding.Enabled() {
+		accessStrategyFanOut = controller.NewAccessStrategyFanOut(accessStrategyFanOutBurst, accessStrategyFanOutQPS)
+	}
+
+	// Watch the kinds of the access resources AccessStrategies render. The AccessStrategy controller
+	// only runs on the coordinator, so with sharding the other shards only watch the kinds of
+	// --watch-resources-gvk and of the OperatorConfig.
+	var accessResourceKinds *controller.AccessResourceKinds
+	if sharding.IsCoordinator() {
+		accessResourceKinds = controller.NewAccessResourceKinds()
+	}
+
+	// Recommend right-sized requests from the live usage of workspaces
+	var rightSizingOptions *accounting.RightSizingOptions
+	if enableRightSizing {
+		if !enableResourceMetrics {
+			setupLog.Info("Right-sizing requires resource metrics, no recommendations will be made",
+				"flag", "--enable-resource-metrics")
+		}
+		rightSizingOptions = &accounting.RightSizingOptions{
+			Window:                 rightSizingWindow,
+			LowUtilizationPercent:  int32(rightSizingLowUtilization),
+			HighUtilizationPercent: int32(rightSizingHighUtilization),
+		}
+	}
+
 	// Configure controller options
 	controllerOpts := controller.WorkspaceControllerOptions{
-		ApplicationImagesPullPolicy: getImagePullPolicy(applicationImagesPullPolicy),
-		ApplicationImagesRegistry:   applicationImagesRegistry,
-		WatchTraefik:                watchTraefik,
-		ResourceWatches:             make([]controller.GVKWatch, 0),
-		EnableWorkspacePodWatching:  enableWorkspacePodWatching,
-		DefaultTemplateNamespace:    defaultTemplateNamespace,
-		PluginEndpoints:             pluginEndpoints,
-		IdleCheckInterval:           idleCheckInterval,
+		ApplicationImagesPullPolicy:    getImagePullPolicy(applicationImagesPullPolicy),
+		ApplicationImagesRegistry:      applicationImagesRegistry,
+		ApplicationImagesPullSecret:    applicationImagesPullSecret,
+		ObjectStorageSyncImage:         objectStorageSyncImage,
+		EnableWorkspaceSnapshots:       enableWorkspaceSnapshots,
+		WatchTraefik:                   watchTraefik,
+		ResourceWatches:                make([]controller.GVKWatch, 0),
+		AccessProviders:                parseCommaSeparatedList(accessProvidersFlag),
+		EnableWorkspacePodWatching:     enableWorkspacePodWatching,
+		RemoteAccessCleanupDryRun:      remoteAccessCleanupDryRun,
+		EnableNodeInterruptionHandling: enableNodeInterruptionHandling,
+		EnableUsageAccounting:          enableUsageAccounting,
+		UsageAccountingInterval:        usageAccountingInterval,
+		EnableResourceMetrics:          enableResourceMetrics,
+		ResourceMetricsInterval:        resourceMetricsInterval,
+		ResourceMetricsPrometheusURL:   resourceMetricsPrometheusURL,
+		ResourceMetricsGPUQuery:        resourceMetricsGPUQuery,
+		RightSizing:                    rightSizingOptions,
+		DefaultTemplateNamespace:       defaultTemplateNamespace,
+		PluginEndpoints:                pluginEndpoints,
+		IdleCheckInterval:              idleCheckInterval,
+		IdleNotificationWebhookURL:     idleNotificationWebhookURL,
+		IdleNotificationGracePeriod:    idleNotificationGracePeriod,
+		HubActivityAPIURL:              hubActivityAPIURL,
+		MaxConcurrentReconciles:        workspaceMaxConcurrentReconciles,
+		RequeueBaseDelay:               reconcileRequeueBase,
+		ProgressingTimeout:             workspaceProgressingTimeout,
+		AccessStrategyFanOut:           accessStrategyFanOut,
+		TemplateComplianceChecker:      webhookv1alpha1.NewTemplateValidator(mgr.GetClient(), defaultTemplateNamespace),
+		Sharding:                       sharding,
+		OperatorConfig:                 operatorConfig,
+		AccessResourceKinds:            accessResourceKinds,
 	}
 
-	// Convert parsed GVKWatches to controller.GVKWatch format
-	for _, watch := range gvkWatches {
+	// Convert the watched resource kinds to controller.GVKWatch format
+	for _, watch := range operatorConfig.Settings().ResourceWatches {
 		controllerOpts.ResourceWatches = append(controllerOpts.ResourceWatches, controller.GVKWatch{
 			Group:   watch.Group,
 			Version: watch.Version,
@@ -342,47 +820,193 @@ func main() {
 		})
 	}
 
-	if err := controller.SetupWorkspaceController(mgr, controllerOpts); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Workspace")
+	// Index the references between workspaces, templates and access strategies
+	if err := workspaceutil.SetupFieldIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
 		os.Exit(1)
 	}
 
-	if err := controller.SetupWorkspaceTemplateController(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "WorkspaceTemplate")
-		os.Exit(1)
+	// Every replica applies the OperatorConfig, since the webhooks of every replica read it
+	if podNamespace != "" {
+		if err := controller.SetupOperatorConfigController(mgr, operatorConfig, podNamespace, operatorConfigName); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+			os.Exit(1)
+		}
 	}
 
-	if err := controller.SetupWorkspaceAccessStrategyController(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "WorkspaceAccessStrategy")
+	if err := controller.SetupWorkspaceController(mgr, controllerOpts); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Workspace")
 		os.Exit(1)
 	}
-	// Set up Workspace webhook (enabled by default, controlled by ENABLE_WORKSPACE_WEBHOOK)
-	// nolint:goconst
-	if os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false" {
-		if err := webhookv1alpha1.SetupWorkspaceWebhookWithManager(mgr, defaultTemplateNamespace); err != nil {
-			setupLog.Error(err, "unable to create webhook", "webhook", "Workspace")
+
+	// The other shards only run the workspace controller
+	if sharding.IsCoordinator() {
+		imageResolver := controller.NewImageResolver(applicationImagesRegistry).WithOperatorConfig(operatorConfig)
+		if sharding.Enabled() {
+			if err := controller.SetupWorkspaceShardController(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "WorkspaceShard")
+				os.Exit(1)
+			}
+		}
+
+		if err := controller.SetupWorkspaceTemplateController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspaceTemplate")
 			os.Exit(1)
 		}
 
-		// Setup pod exec webhook for security validation
-		if err := webhookv1alpha1.SetupPodExecWebhookWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create webhook", "webhook", "PodExec")
+		if err := controller.SetupWorkspaceAccessStrategyController(mgr, accessStrategyFanOut, accessResourceKinds); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspaceAccessStrategy")
 			os.Exit(1)
 		}
-	}
 
-	// Set up WorkspaceTemplate webhook (enabled by default, controlled by ENABLE_WORKSPACE_TEMPLATE_WEBHOOK)
-	// This webhook manages lazy finalizers to prevent template deletion while in use
-	// nolint:goconst
-	if os.Getenv("ENABLE_WORKSPACE_TEMPLATE_WEBHOOK") != "false" {
-		if err := webhookv1alpha1.SetupWorkspaceTemplateWebhookWithManager(mgr, defaultTemplateNamespace); err != nil {
-			setupLog.Error(err, "unable to create webhook", "webhook", "WorkspaceTemplate")
+		if finalizerJanitorInterval > 0 {
+			if err := controller.SetupFinalizerJanitor(mgr, finalizerJanitorInterval); err != nil {
+				setupLog.Error(err, "unable to set up finalizer janitor")
+				os.Exit(1)
+			}
+		}
+
+		if err := controller.SetupWorkspaceQuotaController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspaceQuota")
+			os.Exit(1)
+		}
+
+		if err := controller.SetupWorkspaceImageRolloutController(mgr, imageResolver); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspaceImageRollout")
+			os.Exit(1)
+		}
+
+		if err := controller.SetupWorkspacePoolController(mgr, imageResolver); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspacePool")
+			os.Exit(1)
+		}
+
+		if enableWorkspaceSnapshots {
+			if err := controller.SetupWorkspaceSnapshotController(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "WorkspaceSnapshot")
+				os.Exit(1)
+			}
+		}
+
+		if enableUsageAccounting && usageReportDir != "" {
+			if err := accounting.SetupReporterWithManager(mgr, usageReportDir, usageReportFormat, usageReportInterval); err != nil {
+				setupLog.Error(err, "unable to set up usage reporter")
+				os.Exit(1)
+			}
+		}
+
+		if enableTemplateCatalog {
+			if podNamespace == "" {
+				setupLog.Error(nil, "the template catalog requires the CONTROLLER_POD_NAMESPACE environment variable")
+				os.Exit(1)
+			}
+			if err := controller.SetupTemplateCatalogController(
+				mgr, podNamespace, templateCatalogConfigMap, defaultTemplateNamespace); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "TemplateCatalog")
+				os.Exit(1)
+			}
+		}
+
+		if vanityURLWebhookURL != "" {
+			if err := controller.SetupVanityURLController(mgr, vanityURLWebhookURL); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "VanityURL")
+				os.Exit(1)
+			}
+		}
+
+		// Track the template updates admitted by the WorkspaceTemplate webhook, so that the Workspace
+		// webhook does not enforce a template from the cache before the informer delivers them
+		templateFreshness, err := webhookv1alpha1.SetupTemplateFreshnessWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to set up template freshness tracking")
+			os.Exit(1)
+		}
+
+		// Reuse the templates and access strategies read by the webhooks across the defaulters and
+		// validators of an admission, and across admissions within a short TTL
+		lookupCache, err := webhookv1alpha1.SetupLookupCacheWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to set up webhook lookup cache")
 			os.Exit(1)
 		}
+
+		// Set up Workspace webhook (enabled by default, controlled by ENABLE_WORKSPACE_WEBHOOK)
+		// nolint:goconst
+		if os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false" {
+			var groupResolver webhookv1alpha1.GroupResolverInterface
+			if groupResolverURL != "" {
+				groupResolver = webhookv1alpha1.NewGroupResolverWebhook(groupResolverURL)
+			}
+			var imageVerifier webhookv1alpha1.ImageVerifierInterface
+			if imageSignaturePolicyFile != "" {
+				policy, err := imageverify.LoadPolicy(imageSignaturePolicyFile)
+				if err != nil {
+					setupLog.Error(err, "unable to load image signature policy")
+					os.Exit(1)
+				}
+				imageVerifier, err = imageverify.NewVerifier(policy, imageverify.Options{
+					RegistryCredentialsFile: imageSignatureRegistryAuthFile,
+				})
+				if err != nil {
+					setupLog.Error(err, "unable to create image signature verifier")
+					os.Exit(1)
+				}
+			}
+			var externalPolicy webhookv1alpha1.ExternalPolicyInterface
+			if externalPolicyURL != "" {
+				externalPolicy = webhookv1alpha1.NewExternalPolicyWebhook(externalPolicyURL)
+			}
+			if err := webhookv1alpha1.SetupWorkspaceWebhookWithManager(
+				mgr, defaultTemplateNamespace, trustedNamespaceSelector, workspaceMetadataLimits,
+				allowPrivilegedWorkspaces, templateFreshness, lookupCache, groupResolver, imageVerifier,
+				externalPolicy, externalPolicyFailOpen, rightSizingAutoApply, operatorConfig); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "Workspace")
+				os.Exit(1)
+			}
+
+			// Setup pod exec webhook for security validation
+			if err := webhookv1alpha1.SetupPodExecWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "PodExec")
+				os.Exit(1)
+			}
+
+			// Setup WorkspaceSnapshot webhook, which checks the user may read the snapshotted workspace
+			if err := webhookv1alpha1.SetupWorkspaceSnapshotWebhookWithManager(mgr, groupResolver); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "WorkspaceSnapshot")
+				os.Exit(1)
+			}
+
+			// Setup WorkspaceUserPreferences webhook, which checks users only set their own preferences
+			if err := webhookv1alpha1.SetupWorkspaceUserPreferencesWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "WorkspaceUserPreferences")
+				os.Exit(1)
+			}
+		}
+
+		// Set up WorkspaceTemplate webhook (enabled by default, controlled by ENABLE_WORKSPACE_TEMPLATE_WEBHOOK)
+		// This webhook manages lazy finalizers to prevent template deletion while in use
+		// nolint:goconst
+		if os.Getenv("ENABLE_WORKSPACE_TEMPLATE_WEBHOOK") != "false" {
+			if err := webhookv1alpha1.SetupWorkspaceTemplateWebhookWithManager(
+				mgr, defaultTemplateNamespace, trustedNamespaceSelector, templateFreshness, lookupCache); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "WorkspaceTemplate")
+				os.Exit(1)
+			}
+		}
+
+		// Set up WorkspaceAccessStrategy webhook (enabled by default, controlled by ENABLE_WORKSPACE_ACCESS_STRATEGY_WEBHOOK)
+		// This webhook rejects access strategies whose templates do not parse
+		// nolint:goconst
+		if os.Getenv("ENABLE_WORKSPACE_ACCESS_STRATEGY_WEBHOOK") != "false" {
+			if err := webhookv1alpha1.SetupWorkspaceAccessStrategyWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "WorkspaceAccessStrategy")
+				os.Exit(1)
+			}
+		}
 	}
 
 	// nolint:goconst
-	if enableExtensionAPI {
+	if enableExtensionAPI && sharding.IsCoordinator() {
 		setupLog.Info("Setting up extension API server")
 		// Create config with a different port to avoid conflict with metrics
 		configOpts := []extensionapi.ConfigOption{
@@ -419,6 +1043,28 @@ func main() {
 	} else {
 		setupLog.Info("Extension API server is disabled. Use --enable-extension-api to enable it.")
 	}
+
+	if enableLandingPage && sharding.IsCoordinator() {
+		config := landing.NewConfig(
+			landing.WithServerPort(landingPagePort),
+			landing.WithTitle(landingPageTitle),
+		)
+		if err := landing.SetupLandingServerWithManager(mgr, config); err != nil {
+			setupLog.Error(err, "unable to create landing page server", "landing", "Server")
+			os.Exit(1)
+		}
+		setupLog.Info("Landing page server setup successful", "port", config.ServerPort)
+	}
+
+	// Every replica serves the JupyterHub activity API, which records the activity of workspaces of any shard
+	if enableHubActivityAPI {
+		config := hubactivity.NewConfig(hubactivity.WithServerPort(hubActivityAPIPort))
+		if err := hubactivity.SetupHubActivityServerWithManager(mgr, config); err != nil {
+			setupLog.Error(err, "unable to create hub activity API server", "hubactivity", "Server")
+			os.Exit(1)
+		}
+		setupLog.Info("JupyterHub activity API server setup successful", "port", config.ServerPort)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -7,13 +7,16 @@ Distributed under the terms of the MIT license
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -76,9 +79,15 @@ func parseGVKWatches(gvkList string) ([]GVKWatch, error) {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
 	var probeAddr string
 	var applicationImagesPullPolicy string
 	var applicationImagesRegistry string
+	var applicationImagesPullSecret string
+	var objectStorageSyncImage string
 	var requireTemplate bool
 	var watchTraefik bool
 	var watchResourcesGVK string
@@ -88,10 +97,23 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "",
+		"Name of the leader election lease. Defaults to jupyter-k8s-controller suffixed with the controller namespace.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Duration that non-leader candidates wait before forcing to acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration that the acting leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration the leader election clients wait between tries of actions.")
 	flag.StringVar(&applicationImagesPullPolicy, "application-images-pull-policy", "",
 		"Image pull policy for Application containers (Always, IfNotPresent, or Never)")
 	flag.StringVar(&applicationImagesRegistry, "application-images-registry", "",
 		"Registry prefix for application images (e.g. example.com/my-registry)")
+	flag.StringVar(&applicationImagesPullSecret, "application-images-pull-secret", "",
+		"Name of a Secret added to the image pull secrets of every workspace pod. "+
+			"The Secret must exist in the namespace of each workspace")
+	flag.StringVar(&objectStorageSyncImage, "object-storage-sync-image", "",
+		"Image of the sidecar syncing the object storage mounts of workspaces in Sync mode")
 	flag.BoolVar(&requireTemplate, "require-template", false,
 		"Require all workspaces to reference a WorkspaceTemplate")
 	flag.BoolVar(&watchTraefik, "watch-traefik", false,
@@ -119,10 +141,12 @@ func main() {
 		Scheme:                 scheme,
 		LeaderElection:         enableLeaderElection,
 		HealthProbeBindAddress: probeAddr,
-		// TODO: Add hash/random suffix to LeaderElectionID to prevent conflicts
-		// Other operators use patterns like "jupyter-k8s-controller-<hash>" to ensure
-		// uniqueness when multiple operators might be deployed in the same cluster
-		LeaderElectionID: "jupyter-k8s-controller",
+		// Suffix the LeaderElectionID with the controller namespace to prevent conflicts
+		// when multiple operators are deployed in the same cluster
+		LeaderElectionID: controller.ResolveLeaderElectionID(leaderElectionID, "jupyter-k8s-controller", os.Getenv("CONTROLLER_POD_NAMESPACE")),
+		LeaseDuration:    &leaseDuration,
+		RenewDeadline:    &renewDeadline,
+		RetryPeriod:      &retryPeriod,
 	})
 	if err != nil {
 		setupLog.Error(err, "Error creating manager")
@@ -150,6 +174,8 @@ func main() {
 	controllerOpts := controller.WorkspaceControllerOptions{
 		ApplicationImagesPullPolicy: getImagePullPolicy(applicationImagesPullPolicy),
 		ApplicationImagesRegistry:   applicationImagesRegistry,
+		ApplicationImagesPullSecret: applicationImagesPullSecret,
+		ObjectStorageSyncImage:      objectStorageSyncImage,
 		WatchTraefik:                watchTraefik,
 		ResourceWatches:             make([]controller.GVKWatch, 0),
 	}
@@ -163,6 +189,12 @@ func main() {
 		})
 	}
 
+	// Index the references between workspaces, templates and access strategies
+	if err = workspaceutil.SetupFieldIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "Error setting up field indexes")
+		os.Exit(1)
+	}
+
 	// Setup controllers
 	if err = controller.SetupWorkspaceController(mgr, controllerOpts); err != nil {
 		setupLog.Error(err, "Error setting up workspace controller")
@@ -174,7 +206,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := controller.SetupWorkspaceAccessStrategyController(mgr); err != nil {
+	if err := controller.SetupWorkspaceAccessStrategyController(mgr, nil, nil); err != nil {
 		setupLog.Error(err, "Error setting up workspace access strategy controller")
 		os.Exit(1)
 	}
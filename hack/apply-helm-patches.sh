@@ -45,6 +45,13 @@ if ! grep -q "^  topologySpreadConstraints:" "${CHART_DIR}/values.yaml"; then
     sed -i '/^  tolerations: \[\]/a\\n  ## Topology spread constraints\n  ##\n  topologySpreadConstraints: []' "${CHART_DIR}/values.yaml"
 fi
 
+# --- values.yaml: add metrics authentication config ---
+if ! grep -q "clientCASecret:" "${CHART_DIR}/values.yaml"; then
+    echo "Adding metrics authentication config..."
+    # Insert after the metrics port, the last key of the generated metrics section
+    sed -i '/^  port: 8443$/a\  # -- Serve metrics over HTTPS. When false, metrics are served over HTTP without authentication\n  secure: true\n  # -- Authentication of the metrics endpoint: token (Kubernetes TokenReview and SubjectAccessReview), mtls (client certificates signed by clientCASecret) or none. Empty for token.\n  auth: ""\n  # -- Name of a Secret whose ca.crt signs the client certificates accepted with auth mtls\n  clientCASecret: ""' "${CHART_DIR}/values.yaml"
+fi
+
 # --- manager.yaml: add conditional args ---
 MANAGER_YAML="${CHART_DIR}/templates/manager/manager.yaml"
 echo "Patching manager.yaml args..."
@@ -54,7 +61,7 @@ echo "Patching manager.yaml args..."
 # by dedicated values sections rather than baked into the args list.
 if ! grep -q "extensionApi" "${MANAGER_YAML}"; then
     sed -i '/{{- range .Values.manager.args }}/,/{{- end }}/ {
-        /{{- end }}/a\        - "--application-images-pull-policy={{ .Values.application.imagesPullPolicy }}"\n        - "--application-images-registry={{ .Values.application.imagesRegistry }}"\n        - "--default-template-namespace={{ .Values.workspaceTemplates.defaultNamespace }}"\n        {{- if .Values.accessResources.traefik.enable }}\n        - --watch-traefik\n        {{- end }}\n        {{- if .Values.extensionApi.enable }}\n        - --enable-extension-api\n        {{- if .Values.extensionApi.jwtIssuer }}\n        - --jwt-issuer={{ .Values.extensionApi.jwtIssuer }}\n        {{- end }}\n        {{- if .Values.extensionApi.jwtAudience }}\n        - --jwt-audience={{ .Values.extensionApi.jwtAudience }}\n        {{- end }}\n        {{- end }}\n        {{- if and .Values.extensionApi.enable .Values.extensionApi.jwtSecret.enable }}\n        - --jwt-secret-name={{ .Values.extensionApi.jwtSecret.secretName }}\n        {{- if .Values.extensionApi.jwtSecret.tokenTTL }}\n        - --jwt-ttl={{ .Values.extensionApi.jwtSecret.tokenTTL }}\n        {{- end }}\n        {{- if .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n        - --new-key-use-delay={{ .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n        {{- end }}\n        {{- end }}\n        {{- if .Values.workspacePodWatching.enable }}\n        - --enable-workspace-pod-watching\n        {{- end }}\n        {{- if .Values.controller.plugins }}\n        - "--plugin-endpoints={{ range $i, $p := .Values.controller.plugins }}{{ if $i }},{{ end }}{{ $p.name }}=http://localhost:{{ $p.port }}{{ end }}"\n        {{- end }}\n        {{- if .Values.idleShutdown.checkInterval }}\n        - "--idle-check-interval={{ .Values.idleShutdown.checkInterval }}"\n        {{- end }}
+        /{{- end }}/a\        {{- if .Values.metrics.enable }}\n        {{- if not .Values.metrics.secure }}\n        - --metrics-secure=false\n        {{- end }}\n        {{- if .Values.metrics.auth }}\n        - "--metrics-auth={{ .Values.metrics.auth }}"\n        {{- end }}\n        {{- if .Values.metrics.clientCASecret }}\n        - --metrics-client-ca-file=/tmp/k8s-metrics-server/client-ca/ca.crt\n        {{- end }}\n        {{- end }}\n        - "--application-images-pull-policy={{ .Values.application.imagesPullPolicy }}"\n        - "--application-images-registry={{ .Values.application.imagesRegistry }}"\n        {{- if .Values.application.imagesPullSecret }}\n        - "--application-images-pull-secret={{ .Values.application.imagesPullSecret }}"\n        {{- end }}\n        - "--default-template-namespace={{ .Values.workspaceTemplates.defaultNamespace }}"\n        {{- if .Values.leaderElection.id }}\n        - "--leader-election-id={{ .Values.leaderElection.id }}"\n        {{- end }}\n        - "--leader-elect-lease-duration={{ .Values.leaderElection.leaseDuration }}"\n        - "--leader-elect-renew-deadline={{ .Values.leaderElection.renewDeadline }}"\n        - "--leader-elect-retry-period={{ .Values.leaderElection.retryPeriod }}"\n        {{- if .Values.workspaceTemplates.sameNamespaceOnly }}\n        - --same-namespace-templates-only\n        {{- end }}\n        {{- if .Values.controller.watchNamespaces }}\n        - "--watch-namespaces={{ join "," .Values.controller.watchNamespaces }}"\n        {{- end }}\n        - "--workspace-max-annotations-size={{ .Values.workspaceMetadata.maxAnnotationsSize }}"\n        - "--workspace-max-labels={{ .Values.workspaceMetadata.maxLabels }}"\n        {{- if .Values.workspaceMetadata.reservedPrefixes }}\n        - "--workspace-reserved-metadata-prefixes={{ join "," .Values.workspaceMetadata.reservedPrefixes }}"\n        {{- end }}\n        {{- if .Values.workspaceSecurity.allowPrivileged }}\n        - --allow-privileged-workspaces\n        {{- end }}\n        {{- if .Values.workspaceSecurity.imageSignatures.policyConfigMap }}\n        - --image-signature-policy-file=/tmp/image-signatures/policy/policy.yaml\n        {{- if .Values.workspaceSecurity.imageSignatures.registryCredentialsSecret }}\n        - --image-signature-registry-auth-file=/tmp/image-signatures/registry-auth/.dockerconfigjson\n        {{- end }}\n        {{- end }}\n        {{- if .Values.workspaceSecurity.externalPolicy.url }}\n        - "--external-policy-url={{ .Values.workspaceSecurity.externalPolicy.url }}"\n        {{- if .Values.workspaceSecurity.externalPolicy.failOpen }}\n        - --external-policy-fail-open\n        {{- end }}\n        {{- end }}\n        {{- if .Values.accessResources.traefik.enable }}\n        - --watch-traefik\n        {{- end }}\n        {{- if .Values.accessResources.providers }}\n        - "--access-providers={{ join "," .Values.accessResources.providers }}"\n        {{- end }}\n        {{- if .Values.accessResources.trustedNamespaceSelector }}\n        - "--access-strategy-trusted-namespace-selector={{ .Values.accessResources.trustedNamespaceSelector }}"\n        {{- end }}\n        {{- if .Values.extensionApi.enable }}\n        - --enable-extension-api\n        {{- if .Values.extensionApi.jwtIssuer }}\n        - --jwt-issuer={{ .Values.extensionApi.jwtIssuer }}\n        {{- end }}\n        {{- if .Values.extensionApi.jwtAudience }}\n        - --jwt-audience={{ .Values.extensionApi.jwtAudience }}\n        {{- end }}\n        {{- end }}\n        {{- if and .Values.extensionApi.enable .Values.extensionApi.jwtSecret.enable }}\n        - --jwt-secret-name={{ .Values.extensionApi.jwtSecret.secretName }}\n        {{- if .Values.extensionApi.jwtSecret.tokenTTL }}\n        - --jwt-ttl={{ .Values.extensionApi.jwtSecret.tokenTTL }}\n        {{- end }}\n        {{- if .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n        - --new-key-use-delay={{ .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n        {{- end }}\n        {{- end }}\n        {{- if .Values.landingPage.enable }}\n        - --enable-landing-page\n        - "--landing-page-port={{ .Values.landingPage.port }}"\n        - "--landing-page-title={{ .Values.landingPage.title }}"\n        {{- end }}\n        {{- if .Values.workspacePodWatching.enable }}\n        - --enable-workspace-pod-watching\n        {{- end }}\n        {{- if .Values.workspacePodWatching.remoteAccessCleanupDryRun }}\n        - --remote-access-cleanup-dry-run\n        {{- end }}\n        {{- if .Values.workspaceSnapshots.enable }}\n        - --enable-workspace-snapshots\n        {{- end }}\n        {{- if .Values.vanityURLs.webhookURL }}\n        - "--vanity-url-webhook-url={{ .Values.vanityURLs.webhookURL }}"\n        {{- end }}\n        {{- if .Values.workspaceOwnership.groupResolverURL }}\n        - "--group-resolver-url={{ .Values.workspaceOwnership.groupResolverURL }}"\n        {{- end }}\n        {{- if .Values.controller.plugins }}\n        - "--plugin-endpoints={{ range $i, $p := .Values.controller.plugins }}{{ if $i }},{{ end }}{{ $p.name }}=http://localhost:{{ $p.port }}{{ end }}"\n        {{- end }}\n        {{- if .Values.idleShutdown.checkInterval }}\n        - "--idle-check-interval={{ .Values.idleShutdown.checkInterval }}"\n        {{- end }}\n        {{- if .Values.idleShutdown.notifications.webhookURL }}\n        - "--idle-notification-webhook-url={{ .Values.idleShutdown.notifications.webhookURL }}"\n        - "--idle-notification-grace-period={{ .Values.idleShutdown.notifications.gracePeriod }}"\n        {{- end }}\n        - "--workspace-max-concurrent-reconciles={{ .Values.controller.maxConcurrentReconciles }}"\n        - "--reconcile-requeue-base={{ .Values.controller.requeueBaseDelay }}"\n        - "--workspace-progressing-timeout={{ .Values.controller.progressingTimeout }}"\n        - "--access-strategy-fanout-burst={{ .Values.controller.accessStrategyFanOutBurst }}"\n        - "--access-strategy-fanout-qps={{ .Values.controller.accessStrategyFanOutQPS }}"\n        - "--resource-name-prefix={{ .Values.controller.resourceNamePrefix }}"\n        {{- if .Values.controller.adoptableResourceNamePrefixes }}\n        - "--adoptable-resource-name-prefixes={{ join "," .Values.controller.adoptableResourceNamePrefixes }}"\n        {{- end }}\n        - "--adopt-orphaned-resources={{ .Values.controller.adoptOrphanedResources }}"
     }' "${MANAGER_YAML}"
 fi
 
@@ -96,7 +103,7 @@ echo "Rewriting volumeMounts and volumes sections..."
 sed -i '/^        volumeMounts:$/,/^      {{- range .Values.controller.plugins }}$/{
     /^      {{- range .Values.controller.plugins }}$/!d
 }' "${MANAGER_YAML}"
-sed -i '/^      {{- range .Values.controller.plugins }}$/i\        volumeMounts:\n        {{- if .Values.certManager.enable }}\n        - mountPath: /tmp/k8s-webhook-server/serving-certs\n          name: webhook-certs\n          readOnly: true\n        {{- end }}\n        {{- if and .Values.metrics.enable .Values.certManager.enable }}\n        - mountPath: /tmp/k8s-metrics-server/metrics-certs\n          name: metrics-certs\n          readOnly: true\n        {{- end }}\n        {{- if .Values.extensionApi.enable }}\n        - mountPath: /tmp/extension-server/serving-certs\n          name: extension-server-cert\n          readOnly: true\n        {{- end }}' "${MANAGER_YAML}"
+sed -i '/^      {{- range .Values.controller.plugins }}$/i\        volumeMounts:\n        {{- if .Values.certManager.enable }}\n        - mountPath: /tmp/k8s-webhook-server/serving-certs\n          name: webhook-certs\n          readOnly: true\n        {{- end }}\n        {{- if and .Values.metrics.enable .Values.certManager.enable }}\n        - mountPath: /tmp/k8s-metrics-server/metrics-certs\n          name: metrics-certs\n          readOnly: true\n        {{- end }}\n        {{- if and .Values.metrics.enable .Values.metrics.clientCASecret }}\n        - mountPath: /tmp/k8s-metrics-server/client-ca\n          name: metrics-client-ca\n          readOnly: true\n        {{- end }}\n        {{- if .Values.workspaceSecurity.imageSignatures.policyConfigMap }}\n        - mountPath: /tmp/image-signatures/policy\n          name: image-signature-policy\n          readOnly: true\n        {{- if .Values.workspaceSecurity.imageSignatures.registryCredentialsSecret }}\n        - mountPath: /tmp/image-signatures/registry-auth\n          name: image-signature-registry-auth\n          readOnly: true\n        {{- end }}\n        {{- end }}\n        {{- if .Values.extensionApi.enable }}\n        - mountPath: /tmp/extension-server/serving-certs\n          name: extension-server-cert\n          readOnly: true\n        {{- end }}' "${MANAGER_YAML}"
 
 # Replace the volumes block (from 'volumes:' to end of file)
 sed -i '/^      volumes:$/,$d' "${MANAGER_YAML}"
@@ -112,6 +119,21 @@ cat >> "${MANAGER_YAML}" << 'VOLEOF'
         secret:
           secretName: metrics-server-cert
       {{- end }}
+      {{- if and .Values.metrics.enable .Values.metrics.clientCASecret }}
+      - name: metrics-client-ca
+        secret:
+          secretName: {{ .Values.metrics.clientCASecret }}
+      {{- end }}
+      {{- if .Values.workspaceSecurity.imageSignatures.policyConfigMap }}
+      - name: image-signature-policy
+        configMap:
+          name: {{ .Values.workspaceSecurity.imageSignatures.policyConfigMap }}
+      {{- if .Values.workspaceSecurity.imageSignatures.registryCredentialsSecret }}
+      - name: image-signature-registry-auth
+        secret:
+          secretName: {{ .Values.workspaceSecurity.imageSignatures.registryCredentialsSecret }}
+      {{- end }}
+      {{- end }}
       {{- if .Values.extensionApi.enable }}
       - name: extension-server-cert
         secret:
@@ -325,6 +347,77 @@ if [ -f "${APISERVICE_YAML}" ]; then
     sed -i 's|{{ .Release.Namespace }}/jupyter-k8s-extension-server-cert|{{ .Release.Namespace }}/{{ include "jupyter-k8s.resourceName" (dict "suffix" "extension-server-cert" "context" $) }}|' "${APISERVICE_YAML}"
 fi
 
+# --- manager.yaml: expose the landing page port ---
+if ! grep -q "landing-page$" "${MANAGER_YAML}"; then
+    echo "Adding landing page container port..."
+    sed -i '/^          name: webhook-server$/{n;s/$/\n        {{- if .Values.landingPage.enable }}\n        - containerPort: {{ .Values.landingPage.port }}\n          name: landing-page\n          protocol: TCP\n        {{- end }}/}' "${MANAGER_YAML}"
+fi
+
+# --- Create landing page Service and Ingress ---
+echo "Creating landing page templates..."
+cat > "${CHART_DIR}/templates/extras/landing-page.yaml" << 'LANDINGEOF'
+{{- if .Values.landingPage.enable }}
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: {{ .Release.Service }}
+    app.kubernetes.io/name: {{ include "jupyter-k8s.name" . }}
+    helm.sh/chart: {{ .Chart.Name }}-{{ .Chart.Version | replace "+" "_" }}
+    app.kubernetes.io/instance: {{ .Release.Name }}
+  name: {{ include "jupyter-k8s.resourceName" (dict "suffix" "landing-page" "context" $) }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  ports:
+  - name: http
+    port: 80
+    protocol: TCP
+    targetPort: landing-page
+  selector:
+    control-plane: controller-manager
+  type: ClusterIP
+{{- if .Values.landingPage.ingress.enable }}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: {{ .Release.Service }}
+    app.kubernetes.io/name: {{ include "jupyter-k8s.name" . }}
+    helm.sh/chart: {{ .Chart.Name }}-{{ .Chart.Version | replace "+" "_" }}
+    app.kubernetes.io/instance: {{ .Release.Name }}
+  name: {{ include "jupyter-k8s.resourceName" (dict "suffix" "landing-page" "context" $) }}
+  namespace: {{ .Release.Namespace }}
+  {{- with .Values.landingPage.ingress.annotations }}
+  annotations: {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  {{- if .Values.landingPage.ingress.className }}
+  ingressClassName: {{ .Values.landingPage.ingress.className }}
+  {{- end }}
+  {{- if .Values.landingPage.ingress.tlsSecretName }}
+  tls:
+  - hosts:
+    - {{ .Values.landingPage.ingress.host | quote }}
+    secretName: {{ .Values.landingPage.ingress.tlsSecretName }}
+  {{- end }}
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: {{ include "jupyter-k8s.resourceName" (dict "suffix" "landing-page" "context" $) }}
+            port:
+              name: http
+    {{- if .Values.landingPage.ingress.host }}
+    host: {{ .Values.landingPage.ingress.host | quote }}
+    {{- end }}
+{{- end }}
+{{- end }}
+LANDINGEOF
+
 # --- Create extension API auth RoleBinding in kube-system ---
 echo "Creating extension API auth RoleBinding template..."
 cat > "${CHART_DIR}/templates/rbac/extension-api-auth-binding.yaml" << 'AUTHEOF'
@@ -402,6 +495,16 @@ sed -i '/--application-images-pull-policy/d' "${CHART_DIR}/values.yaml"
 sed -i '/--application-images-registry/d' "${CHART_DIR}/values.yaml"
 sed -i '/--default-template-namespace/d' "${CHART_DIR}/values.yaml"
 
+# --- Webhook configurations: scope to watched namespaces ---
+# When controller.watchNamespaces is set, the webhooks only intercept requests
+# in those namespaces so that several installs can share a cluster.
+for WEBHOOK_YAML in "${CHART_DIR}/templates/webhook/mutating-webhook-configuration.yaml" "${CHART_DIR}/templates/webhook/validating-webhook-configuration.yaml"; do
+    if [ -f "${WEBHOOK_YAML}" ] && ! grep -q "watchNamespaces" "${WEBHOOK_YAML}"; then
+        echo "Scoping $(basename "${WEBHOOK_YAML}") to watched namespaces..."
+        sed -i '/^  sideEffects: None$/a\  {{- if .Values.controller.watchNamespaces }}\n  namespaceSelector:\n    matchExpressions:\n    - key: kubernetes.io/metadata.name\n      operator: In\n      values:\n      {{- if .Values.workspaceTemplates.defaultNamespace }}\n      - {{ .Values.workspaceTemplates.defaultNamespace | quote }}\n      {{- end }}\n      {{- range .Values.controller.watchNamespaces }}\n      - {{ . | quote }}\n      {{- end }}\n  {{- end }}' "${WEBHOOK_YAML}"
+    fi
+done
+
 # --- values.yaml: inject helm-docs annotations ---
 # helm-docs (docs-helm-ref target) reads `# --` comments from values.yaml to
 # generate docs/source/reference/helm-charts/operator.md. kubebuilder + the
@@ -45,16 +45,41 @@ ANNOTATIONS = {
     "crd.keep": "Keep CRDs when uninstalling",
     "metrics.enable": "Enable metrics endpoint",
     "metrics.port": "Metrics server port",
+    "metrics.secure": "Serve metrics over HTTPS. When false, metrics are served over HTTP without authentication",
+    "metrics.auth": "Authentication of the metrics endpoint: token (Kubernetes TokenReview and SubjectAccessReview), mtls (client certificates signed by clientCASecret) or none. Empty for token.",
+    "metrics.clientCASecret": "Name of a Secret whose ca.crt signs the client certificates accepted with auth mtls",
     "certManager.enable": "Enable cert-manager integration (required for webhooks and metrics TLS)",
     "webhook.enable": "Enable admission webhooks",
     "webhook.port": "Webhook server port",
     "prometheus.enable": "Enable Prometheus ServiceMonitor",
     "application.imagesPullPolicy": "Image pull policy for workspace pod containers",
     "application.imagesRegistry": "Image registry prefix for workspace pod containers",
+    "application.imagesPullSecret": "Name of a Secret added to the image pull secrets of every workspace pod, e.g. the credentials of a private imagesRegistry. The Secret must exist in the namespace of each workspace.",
     "workspaceTemplates.defaultNamespace": "Namespace where shared workspace templates are stored",
+    "workspaceTemplates.sameNamespaceOnly": "Only allow workspaces to use templates and access strategies from their own namespace. When true, defaultNamespace is ignored.",
+    "workspaceMetadata.maxAnnotationsSize": "Maximum total size in bytes of workspace annotations (0 for unlimited)",
+    "workspaceMetadata.maxLabels": "Maximum number of workspace labels (0 for unlimited)",
+    "workspaceMetadata.reservedPrefixes": "Label and annotation key prefixes users may not set on workspaces",
+    "workspaceSecurity.allowPrivileged": "Allow workspaces to run privileged containers, host processes or non-baseline capabilities",
+    "workspaceSecurity.imageSignatures.policyConfigMap": "Name of a ConfigMap whose policy.yaml is the image signature policy. When set, workspace images must carry a cosign signature that verifies against the policy",
+    "workspaceSecurity.imageSignatures.registryCredentialsSecret": "Name of a docker config Secret whose .dockerconfigjson holds the credentials to read signatures from private registries",
+    "workspaceSecurity.externalPolicy.url": "URL of an external policy engine evaluating workspaces at admission, e.g. an OPA data API rule producing violations (disabled when empty)",
+    "workspaceSecurity.externalPolicy.failOpen": "Admit workspaces when the external policy engine cannot be reached, instead of rejecting them",
+    "workspaceOwnership.groupResolverURL": "URL of the webhook resolving user groups for GroupOnly workspaces (request groups only when empty)",
+    "leaderElection.id": "Name of the leader election lease. Defaults to a name suffixed with the release namespace, unique per install.",
+    "leaderElection.leaseDuration": "Duration that non-leader candidates wait before forcing to acquire leadership",
+    "leaderElection.renewDeadline": "Duration that the acting leader retries refreshing leadership before giving it up",
+    "leaderElection.retryPeriod": "Duration the leader election clients wait between tries of actions",
     "workspacePodWatching.enable": "Enable workspace pod event watching for lifecycle management (required for remote access plugins)",
+    "workspacePodWatching.remoteAccessCleanupDryRun": "Log the remote access resources of deleted workspace pods instead of releasing them",
+    "workspaceSnapshots.enable": "Enable the WorkspaceSnapshot controller (requires the snapshot.storage.k8s.io CRDs)",
+    "vanityURLs.webhookURL": "URL of the webhook registering workspace access URLs as vanity URLs (disabled when empty)",
+    "idleShutdown.notifications.webhookURL": "URL of the webhook, e.g. a Slack or SMTP bridge, notifying the owners of idle workspaces before they are stopped (disabled when empty)",
+    "idleShutdown.notifications.gracePeriod": "Time between the notification of an idle workspace and its stop, in which the owner can keep it alive",
     "accessResources.traefik.enable": "Enable watching Traefik IngressRoute resources",
+    "accessResources.providers": "Access providers whose resources to watch (traefik, ingress, gateway-api, istio)",
     "accessResources.additionalGvk": "Additional Group-Version-Kind resources to watch for access strategy",
+    "accessResources.trustedNamespaceSelector": "Label selector for namespaces whose access strategies may be referenced from any namespace",
     "extensionApi.enable": "Enable the Extension API server (serves Connection APIs)",
     "extensionApi.jwtIssuer": "JWT issuer claim",
     "extensionApi.jwtAudience": "JWT audience claim",
@@ -68,6 +93,23 @@ ANNOTATIONS = {
     "extensionApi.jwtSecret.rotator.imageTag": "Rotator image tag (defaults to chart appVersion)",
     "extensionApi.jwtSecret.rotator.imagePullPolicy": "Rotator image pull policy",
     "extensionApi.jwtSecret.rotator.resources": "Rotator resource limits and requests",
+    "landingPage.enable": "Serve the landing page from the controller. Must be exposed behind an authenticating proxy that sets the X-Auth-Request-User and X-Auth-Request-Groups headers.",
+    "landingPage.port": "Port of the landing page server in the controller pod",
+    "landingPage.title": "Title of the landing page",
+    "landingPage.ingress.enable": "Create an Ingress for the landing page",
+    "landingPage.ingress.className": "Ingress class name",
+    "landingPage.ingress.host": "Host of the landing page",
+    "landingPage.ingress.annotations": "Annotations of the ingress, e.g. to route requests through the authenticating proxy",
+    "landingPage.ingress.tlsSecretName": "Name of the Secret holding the TLS certificate of the host",
+    "controller.watchNamespaces": "Namespaces the controller watches and the webhooks apply to. Empty watches all namespaces. workspaceTemplates.defaultNamespace is always included.",
+    "controller.maxConcurrentReconciles": "Number of workspaces reconciled in parallel. Raise for large fleets (1000+ workspaces).",
+    "controller.requeueBaseDelay": "Delay before the first retry of a failed workspace reconcile. Retries back off exponentially, up to 1000s.",
+    "controller.progressingTimeout": "How long a starting workspace may stay in the same Progressing state before the controller flags it as stalled, deletes its pods that are not ready once, and escalates with an event. \"0\" disables the watchdog.",
+    "controller.accessStrategyFanOutBurst": "Number of workspaces reconciled at once when AccessStrategies they reference change, before accessStrategyFanOutQPS applies",
+    "controller.accessStrategyFanOutQPS": "Number of workspaces reconciled per second when AccessStrategies they reference change, shared by all AccessStrategies",
+    "controller.resourceNamePrefix": "Prefix of the names of the deployments, services, PVCs and secrets of new workspaces. Give operator installations that share namespaces distinct prefixes. Existing workspaces keep the prefix they were created with.",
+    "controller.adoptableResourceNamePrefixes": "Prefixes, besides \"workspace\", under which existing workspaces adopt deployments and PVCs no other object controls, e.g. those left by a previous notebook operator",
+    "controller.adoptOrphanedResources": "Whether workspaces adopt the deployments, services and PVCs under their names that no object controls, e.g. after a restore from backup or a reinstall of the operator. When false, such resources are reported as name collisions.",
     "controller.plugins": "Plugin sidecars to deploy alongside the controller. Each plugin runs as a sidecar container in the controller pod.",
 }
 
@@ -0,0 +1,222 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package accessrender renders the access resources and URLs of a WorkspaceAccessStrategy for a
+// workspace without applying them, so that strategy authors can iterate on their templates safely.
+// It renders with the access provider of the strategy and the Service of the workspace, like the
+// workspace controller. It backs the render command of the accesstemplate binary and the
+// render-access command of the manager.
+package accessrender
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
+)
+
+// Field is a rendered template of the strategy other than its access resources
+type Field struct {
+	// Path of the field, e.g. spec.accessURLTemplate
+	Path string
+	// Value the field renders to
+	Value string
+}
+
+// Rendering is what an access strategy renders to for a workspace
+type Rendering struct {
+	// Service is the workspace Service the templates are rendered with
+	Service *corev1.Service
+	// Resources are the access resources the controller would apply
+	Resources []*unstructured.Unstructured
+	// AccessURL is the access URL the controller would publish, empty when the provider cannot tell
+	AccessURL string
+	// Fields are the other rendered templates of the strategy, in field order
+	Fields []Field
+}
+
+// Render renders the access of workspace through accessStrategy. Template errors are returned as
+// accesstemplate.ErrorList when the templates do not parse, else as the error of the first template
+// that fails to execute.
+func Render(
+	scheme *runtime.Scheme,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) (*Rendering, error) {
+	if errs := accesstemplate.Lint(accessStrategy); len(errs) > 0 {
+		return nil, errs
+	}
+
+	service, err := controller.NewServiceBuilder(scheme).BuildService(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the workspace service: %w", err)
+	}
+	provider, err := controller.DefaultAccessProviders().Get(accessStrategy.Spec.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	rendering := &Rendering{Service: service}
+	if rendering.Resources, err = provider.BuildResources(workspace, accessStrategy, service); err != nil {
+		return nil, err
+	}
+	if rendering.AccessURL, err = provider.AccessURL(workspace, accessStrategy, service); err != nil {
+		return nil, err
+	}
+
+	spec := accessStrategy.Spec
+	serviceData := accesstemplate.NewData(workspace, accessStrategy, service)
+	render := func(path, text string, data *accesstemplate.Data) error {
+		if text == "" {
+			return nil
+		}
+		value, err := accesstemplate.Render(path, text, data)
+		if err != nil {
+			return err
+		}
+		rendering.Fields = append(rendering.Fields, Field{Path: path, Value: value})
+		return nil
+	}
+
+	if err := render(accesstemplate.FieldApplicationBasePathTemplate, spec.ApplicationBasePathTemplate, serviceData); err != nil {
+		return nil, err
+	}
+	if err := render(accesstemplate.FieldBearerAuthURLTemplate, spec.BearerAuthURLTemplate, serviceData); err != nil {
+		return nil, err
+	}
+	if spec.AccessStartupProbe != nil && spec.AccessStartupProbe.HTTPGet != nil {
+		if err := render(accesstemplate.FieldAccessStartupProbeURL, spec.AccessStartupProbe.HTTPGet.URLTemplate, serviceData); err != nil {
+			return nil, err
+		}
+	}
+	if spec.DeploymentModifications != nil &&
+		spec.DeploymentModifications.PodModifications != nil &&
+		spec.DeploymentModifications.PodModifications.PrimaryContainerModifications != nil {
+		// Merged environment variables are rendered before the workspace Service exists
+		envData := accesstemplate.NewData(workspace, accessStrategy, nil)
+		for i, env := range spec.DeploymentModifications.PodModifications.PrimaryContainerModifications.MergeEnv {
+			if err := render(accesstemplate.MergeEnvField(i), env.ValueTemplate, envData); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rendering, nil
+}
+
+// Write prints the access resources as YAML documents, followed by the access URL and the other
+// rendered fields as comments
+func (r *Rendering) Write(w io.Writer) error {
+	for _, resource := range r.Resources {
+		data, err := yaml.Marshal(resource.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", resource.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	if r.AccessURL != "" {
+		if _, err := fmt.Fprintf(w, "# %s: %s\n", accesstemplate.FieldAccessURLTemplate, r.AccessURL); err != nil {
+			return err
+		}
+	}
+	for _, field := range r.Fields {
+		if _, err := fmt.Fprintf(w, "# %s: %s\n", field.Path, field.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run implements a render command: it parses args, reads the --strategy and --workspace files,
+// and writes their rendering to w. It uses a sample workspace when --workspace is not set.
+func Run(name string, args []string, scheme *runtime.Scheme, w io.Writer) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	strategyFile := flags.String("strategy", "", "WorkspaceAccessStrategy file to render, - for stdin.")
+	workspaceFile := flags.String("workspace", "",
+		"Workspace file to render the strategy for. Uses a sample workspace if not set.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *strategyFile == "" {
+		return errors.New("--strategy must be set")
+	}
+	if *strategyFile == "-" && *workspaceFile == "-" {
+		return errors.New("only one of --strategy and --workspace can read stdin")
+	}
+
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	if err := ReadObject(*strategyFile, accessStrategy); err != nil {
+		return err
+	}
+	workspace := SampleWorkspace()
+	if *workspaceFile != "" {
+		workspace = &workspacev1alpha1.Workspace{}
+		if err := ReadObject(*workspaceFile, workspace); err != nil {
+			return err
+		}
+	}
+
+	rendering, err := Render(scheme, workspace, accessStrategy)
+	if err != nil {
+		var templateErrs accesstemplate.ErrorList
+		if errors.As(err, &templateErrs) {
+			return prefixErrors(*strategyFile, templateErrs)
+		}
+		return fmt.Errorf("%s: %w", *strategyFile, err)
+	}
+	return rendering.Write(w)
+}
+
+// prefixErrors prefixes each template error with the strategy file, one per line
+func prefixErrors(file string, templateErrs accesstemplate.ErrorList) error {
+	errs := make([]error, 0, len(templateErrs))
+	for _, err := range templateErrs {
+		errs = append(errs, fmt.Errorf("%s: %w", file, err))
+	}
+	return errors.Join(errs...)
+}
+
+// SampleWorkspace returns the workspace render previews use when no workspace file is given
+func SampleWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sample-workspace",
+			Namespace: "default",
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: "Sample workspace",
+		},
+	}
+}
+
+// ReadObject reads a YAML file into obj, rejecting unknown fields. It reads stdin when file is "-".
+func ReadObject(file string, obj any) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := yaml.UnmarshalStrict(data, obj); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accessrender
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testStrategy = `apiVersion: workspace.jupyter.org/v1alpha1
+kind: WorkspaceAccessStrategy
+metadata:
+  name: web
+  namespace: default
+spec:
+  displayName: Web
+  accessURLTemplate: "https://{{ .Workspace.Name }}.example.com/"
+  applicationBasePathTemplate: "/workspaces/{{ .Workspace.Name }}"
+  accessResourceTemplates:
+    - kind: Service
+      apiVersion: v1
+      namePrefix: web
+      template: |
+        spec:
+          ports:
+            - port: {{ port .Service.Spec.Ports "http" }}
+`
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRender(t *testing.T) {
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, ReadObject(writeTestFile(t, "strategy.yaml", testStrategy), accessStrategy))
+
+	rendering, err := Render(newTestScheme(t), SampleWorkspace(), accessStrategy)
+	require.NoError(t, err)
+
+	require.NotNil(t, rendering.Service)
+	require.Len(t, rendering.Resources, 1)
+	assert.Equal(t, "Service", rendering.Resources[0].GetKind())
+	assert.Equal(t, "https://sample-workspace.example.com/", rendering.AccessURL)
+	assert.Equal(t, []Field{{
+		Path:  "spec.applicationBasePathTemplate",
+		Value: "/workspaces/sample-workspace",
+	}}, rendering.Fields)
+
+	var out bytes.Buffer
+	require.NoError(t, rendering.Write(&out))
+	assert.Contains(t, out.String(), "---\napiVersion: v1\nkind: Service\n")
+	assert.Contains(t, out.String(), "# spec.accessURLTemplate: https://sample-workspace.example.com/\n")
+	assert.Contains(t, out.String(), "# spec.applicationBasePathTemplate: /workspaces/sample-workspace\n")
+}
+
+func TestRun_PrefixesTemplateErrorsWithFile(t *testing.T) {
+	strategyFile := writeTestFile(t, "strategy.yaml", `apiVersion: workspace.jupyter.org/v1alpha1
+kind: WorkspaceAccessStrategy
+metadata:
+  name: broken
+spec:
+  displayName: Broken
+  accessURLTemplate: "https://{{ .Workspace.Name"
+`)
+
+	err := Run("render-access", []string{"--strategy=" + strategyFile}, newTestScheme(t), &bytes.Buffer{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), strategyFile+": spec.accessURLTemplate")
+}
+
+func TestRun_RequiresStrategy(t *testing.T) {
+	err := Run("render-access", nil, newTestScheme(t), &bytes.Buffer{})
+
+	assert.EqualError(t, err, "--strategy must be set")
+}
+
+func TestRun_RejectsStdinTwice(t *testing.T) {
+	err := Run("render-access", []string{"--strategy=-", "--workspace=-"}, newTestScheme(t), &bytes.Buffer{})
+
+	assert.EqualError(t, err, "only one of --strategy and --workspace can read stdin")
+}
+
+func TestReadObject_RejectsUnknownFields(t *testing.T) {
+	err := ReadObject(writeTestFile(t, "workspace.yaml", "spec:\n  imagee: jupyter\n"), &workspacev1alpha1.Workspace{})
+
+	assert.ErrorContains(t, err, "failed to parse")
+}
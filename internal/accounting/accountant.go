@@ -0,0 +1,183 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package accounting accounts the running time and resource-request-hours of workspaces for
+// chargeback. The Accountant accrues them in the status of the workspaces and in Prometheus
+// counters, and the Reporter periodically writes them as CSV or JSON usage reports. The Sampler
+// surfaces the live resource usage of running workspaces next to their accounted usage.
+package accounting
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultInterval is how often the usage of running workspaces is checkpointed in their status
+const DefaultInterval = 5 * time.Minute
+
+// nvidiaGPUResource is the resource of NVIDIA GPUs. Shared GPUs are advertised under the same
+// name followed by a suffix, e.g. nvidia.com/gpu.shared.
+const nvidiaGPUResource = "nvidia.com/gpu"
+
+const (
+	secondsPerHour = 3600
+	milliPerUnit   = 1000
+	mibPerGiB      = 1024
+	bytesPerMiB    = 1 << 20
+)
+
+// The usage counters, by workspace
+var (
+	runningSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workspace_running_seconds_total",
+		Help: "Time workspaces were available, in seconds",
+	}, []string{"namespace", "workspace"})
+	cpuCoreHours = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workspace_cpu_request_core_hours_total",
+		Help: "CPU requested by running workspaces, in core-hours",
+	}, []string{"namespace", "workspace"})
+	memoryGiBHours = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workspace_memory_request_gib_hours_total",
+		Help: "Memory requested by running workspaces, in GiB-hours",
+	}, []string{"namespace", "workspace"})
+	gpuHours = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workspace_gpu_request_hours_total",
+		Help: "NVIDIA GPUs requested by running workspaces, in GPU-hours",
+	}, []string{"namespace", "workspace"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(runningSeconds, cpuCoreHours, memoryGiBHours, gpuHours)
+}
+
+// Accountant accrues the usage of workspaces in their status.usage while they run. It checkpoints
+// the usage of a running workspace at most once per interval, to bound the status updates, and
+// once more when the workspace stops. It only changes the workspace in memory; the status update
+// that follows persists it.
+type Accountant struct {
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewAccountant creates a new Accountant. A zero or negative interval uses DefaultInterval.
+func NewAccountant(interval time.Duration) *Accountant {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Accountant{interval: interval, now: time.Now}
+}
+
+// Interval returns how often the usage of running workspaces is checkpointed
+func (a *Accountant) Interval() time.Duration {
+	if a == nil {
+		return 0
+	}
+	return a.interval
+}
+
+// Record accounts a running workspace. It opens its accounting period when it just started, and
+// accrues its usage since the last checkpoint once the interval elapsed.
+func (a *Accountant) Record(workspace *workspacev1alpha1.Workspace) {
+	if a == nil {
+		return
+	}
+	now := a.now()
+	usage := workspace.Status.Usage
+	if usage == nil {
+		usage = &workspacev1alpha1.WorkspaceUsageStatus{}
+		workspace.Status.Usage = usage
+	}
+	if usage.LastAccountedTime == nil {
+		usage.LastAccountedTime = &metav1.Time{Time: now}
+		return
+	}
+	if now.Sub(usage.LastAccountedTime.Time) < a.interval {
+		return
+	}
+	accrue(workspace, now)
+}
+
+// Stop accrues the usage of a workspace that no longer runs up to now, and closes its accounting
+// period
+func (a *Accountant) Stop(workspace *workspacev1alpha1.Workspace) {
+	if a == nil {
+		return
+	}
+	usage := workspace.Status.Usage
+	if usage == nil || usage.LastAccountedTime == nil {
+		return
+	}
+	accrue(workspace, a.now())
+	usage.LastAccountedTime = nil
+}
+
+// Forget drops the counters of a deleted workspace
+func (a *Accountant) Forget(workspace *workspacev1alpha1.Workspace) {
+	if a == nil {
+		return
+	}
+	labels := prometheus.Labels{"namespace": workspace.Namespace, "workspace": workspace.Name}
+	runningSeconds.Delete(labels)
+	cpuCoreHours.Delete(labels)
+	memoryGiBHours.Delete(labels)
+	gpuHours.Delete(labels)
+}
+
+// accrue adds the usage of the workspace from its last checkpoint to now, in whole seconds, and
+// moves the checkpoint accordingly so that the remainder is accounted next time
+func accrue(workspace *workspacev1alpha1.Workspace, now time.Time) {
+	usage := workspace.Status.Usage
+	seconds := int64(now.Sub(usage.LastAccountedTime.Time) / time.Second)
+	if seconds <= 0 {
+		return
+	}
+	usage.LastAccountedTime = &metav1.Time{Time: usage.LastAccountedTime.Add(time.Duration(seconds) * time.Second)}
+
+	requests := resourceRequests(workspace)
+	cpuMilli := requests.Cpu().MilliValue()
+	memoryMiB := requests.Memory().Value() / bytesPerMiB
+	gpus := gpuCount(requests)
+
+	usage.RunningSeconds += seconds
+	usage.CPUMilliCoreSeconds += cpuMilli * seconds
+	usage.MemoryMiBSeconds += memoryMiB * seconds
+	usage.GPUSeconds += gpus * seconds
+
+	labels := prometheus.Labels{"namespace": workspace.Namespace, "workspace": workspace.Name}
+	runningSeconds.With(labels).Add(float64(seconds))
+	cpuCoreHours.With(labels).Add(float64(cpuMilli*seconds) / milliPerUnit / secondsPerHour)
+	memoryGiBHours.With(labels).Add(float64(memoryMiB*seconds) / mibPerGiB / secondsPerHour)
+	gpuHours.With(labels).Add(float64(gpus*seconds) / secondsPerHour)
+}
+
+// resourceRequests returns the requests of the workspace pod, as applied to its Deployment, or
+// else as set on the workspace
+func resourceRequests(workspace *workspacev1alpha1.Workspace) corev1.ResourceList {
+	if effective := workspace.Status.EffectiveSpec; effective != nil {
+		return effective.Resources.Requests
+	}
+	if workspace.Spec.Resources != nil {
+		return workspace.Spec.Resources.Requests
+	}
+	return nil
+}
+
+// gpuCount returns the NVIDIA GPUs requested, whole or shared
+func gpuCount(requests corev1.ResourceList) int64 {
+	var count int64
+	for name, quantity := range requests {
+		if string(name) == nvidiaGPUResource || strings.HasPrefix(string(name), nvidiaGPUResource+".") {
+			count += quantity.Value()
+		}
+	}
+	return count
+}
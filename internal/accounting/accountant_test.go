@@ -0,0 +1,127 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newAccountingTestWorkspace(name string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+					"nvidia.com/gpu":      resource.MustParse("1"),
+				},
+			},
+		},
+	}
+}
+
+func TestAccountant_RecordAccruesOncePerInterval(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	accountant := NewAccountant(5 * time.Minute)
+	accountant.now = func() time.Time { return now }
+	workspace := newAccountingTestWorkspace("record-ws")
+	defer accountant.Forget(workspace)
+
+	// Opens the accounting period
+	accountant.Record(workspace)
+	require.NotNil(t, workspace.Status.Usage)
+	assert.Equal(t, now, workspace.Status.Usage.LastAccountedTime.Time)
+	assert.Zero(t, workspace.Status.Usage.RunningSeconds)
+
+	// Within the interval
+	now = now.Add(time.Minute)
+	accountant.Record(workspace)
+	assert.Zero(t, workspace.Status.Usage.RunningSeconds)
+
+	// Past the interval
+	now = now.Add(time.Hour - time.Minute)
+	accountant.Record(workspace)
+	usage := workspace.Status.Usage
+	assert.Equal(t, int64(3600), usage.RunningSeconds)
+	assert.Equal(t, int64(500*3600), usage.CPUMilliCoreSeconds)
+	assert.Equal(t, int64(2048*3600), usage.MemoryMiBSeconds)
+	assert.Equal(t, int64(3600), usage.GPUSeconds)
+	assert.Equal(t, now, usage.LastAccountedTime.Time)
+
+	labels := []string{"default", "record-ws"}
+	assert.InDelta(t, 3600, testutil.ToFloat64(runningSeconds.WithLabelValues(labels...)), 1e-9)
+	assert.InDelta(t, 0.5, testutil.ToFloat64(cpuCoreHours.WithLabelValues(labels...)), 1e-9)
+	assert.InDelta(t, 2, testutil.ToFloat64(memoryGiBHours.WithLabelValues(labels...)), 1e-9)
+	assert.InDelta(t, 1, testutil.ToFloat64(gpuHours.WithLabelValues(labels...)), 1e-9)
+}
+
+func TestAccountant_StopClosesPeriod(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	accountant := NewAccountant(time.Hour)
+	accountant.now = func() time.Time { return now }
+	workspace := newAccountingTestWorkspace("stop-ws")
+	defer accountant.Forget(workspace)
+
+	accountant.Record(workspace)
+	now = now.Add(90*time.Second + 500*time.Millisecond)
+	accountant.Stop(workspace)
+
+	assert.Equal(t, int64(90), workspace.Status.Usage.RunningSeconds)
+	assert.Nil(t, workspace.Status.Usage.LastAccountedTime)
+
+	// Stopped workspaces do not accrue
+	now = now.Add(time.Hour)
+	accountant.Stop(workspace)
+	assert.Equal(t, int64(90), workspace.Status.Usage.RunningSeconds)
+}
+
+func TestAccountant_UsesEffectiveSpecRequests(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	accountant := NewAccountant(time.Minute)
+	accountant.now = func() time.Time { return now }
+	workspace := newAccountingTestWorkspace("effective-ws")
+	defer accountant.Forget(workspace)
+	workspace.Status.EffectiveSpec = &workspacev1alpha1.EffectiveSpecStatus{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:      resource.MustParse("2"),
+				"nvidia.com/gpu.shared": resource.MustParse("2"),
+			},
+		},
+	}
+
+	accountant.Record(workspace)
+	now = now.Add(time.Minute)
+	accountant.Record(workspace)
+
+	usage := workspace.Status.Usage
+	assert.Equal(t, int64(2000*60), usage.CPUMilliCoreSeconds)
+	assert.Zero(t, usage.MemoryMiBSeconds)
+	assert.Equal(t, int64(2*60), usage.GPUSeconds)
+}
+
+func TestAccountant_NilIsDisabled(t *testing.T) {
+	var accountant *Accountant
+	workspace := newAccountingTestWorkspace("nil-ws")
+
+	accountant.Record(workspace)
+	accountant.Stop(workspace)
+	accountant.Forget(workspace)
+
+	assert.Nil(t, workspace.Status.Usage)
+	assert.Zero(t, accountant.Interval())
+}
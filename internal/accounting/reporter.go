@@ -0,0 +1,209 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Report formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// DefaultReportInterval is how often usage reports are written
+const DefaultReportInterval = time.Hour
+
+// annotationCreatedBy is the annotation holding the user who created a workspace
+const annotationCreatedBy = "workspace.jupyter.org/created-by"
+
+// csvHeader is the header row of CSV usage reports
+var csvHeader = []string{
+	"namespace", "workspace", "owner", "template", "runningHours", "cpuCoreHours", "memoryGiBHours", "gpuHours",
+}
+
+// UsageRecord is the usage of a workspace in a usage report. The usage is cumulative since the
+// workspace was created.
+type UsageRecord struct {
+	Namespace      string  `json:"namespace"`
+	Workspace      string  `json:"workspace"`
+	Owner          string  `json:"owner,omitempty"`
+	Template       string  `json:"template,omitempty"`
+	RunningHours   float64 `json:"runningHours"`
+	CPUCoreHours   float64 `json:"cpuCoreHours"`
+	MemoryGiBHours float64 `json:"memoryGiBHours"`
+	GPUHours       float64 `json:"gpuHours"`
+}
+
+// Reporter periodically writes the usage of all workspaces to a new file in a directory, e.g. on a
+// mounted PVC or S3 bucket, for chargeback
+type Reporter struct {
+	reader   client.Reader
+	dir      string
+	format   string
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewReporter creates a new Reporter. A zero or negative interval uses DefaultReportInterval.
+func NewReporter(reader client.Reader, dir, format string, interval time.Duration) (*Reporter, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("usage report directory must be set")
+	}
+	if format != FormatCSV && format != FormatJSON {
+		return nil, fmt.Errorf("unsupported usage report format %q, must be %s or %s", format, FormatCSV, FormatJSON)
+	}
+	if interval <= 0 {
+		interval = DefaultReportInterval
+	}
+	return &Reporter{
+		reader:   reader,
+		dir:      dir,
+		format:   format,
+		interval: interval,
+		now:      time.Now,
+	}, nil
+}
+
+// Start writes a usage report every interval until the context is done
+func (r *Reporter) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("usage-reporter")
+	logger.Info("Starting usage reporter", "dir", r.dir, "format", r.format, "interval", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			path, err := r.WriteReport(ctx)
+			if err != nil {
+				logger.Error(err, "Failed to write usage report")
+				continue
+			}
+			logger.Info("Wrote usage report", "path", path)
+		}
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// Only the leader writes reports, so that each report is written once
+func (r *Reporter) NeedLeaderElection() bool {
+	return true
+}
+
+// SetupReporterWithManager sets up the usage reporter and adds it to the manager
+func SetupReporterWithManager(mgr ctrl.Manager, dir, format string, interval time.Duration) error {
+	reporter, err := NewReporter(mgr.GetAPIReader(), dir, format, interval)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(reporter); err != nil {
+		return fmt.Errorf("failed to add usage reporter to manager: %w", err)
+	}
+	return nil
+}
+
+// WriteReport writes the usage of all workspaces to a new file, and returns its path
+func (r *Reporter) WriteReport(ctx context.Context) (string, error) {
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := r.reader.List(ctx, workspaces); err != nil {
+		return "", fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	records := make([]UsageRecord, 0, len(workspaces.Items))
+	for i := range workspaces.Items {
+		records = append(records, newUsageRecord(&workspaces.Items[i]))
+	}
+
+	// Each report is a new file: S3 mounts support neither overwriting nor renaming files
+	path := filepath.Join(r.dir, fmt.Sprintf("usage-%s.%s", r.now().UTC().Format("20060102T150405Z"), r.format))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create usage report: %w", err)
+	}
+	if r.format == FormatJSON {
+		err = writeJSON(file, records)
+	} else {
+		err = writeCSV(file, records)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write usage report %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// newUsageRecord returns the usage record of a workspace
+func newUsageRecord(workspace *workspacev1alpha1.Workspace) UsageRecord {
+	record := UsageRecord{
+		Namespace: workspace.Namespace,
+		Workspace: workspace.Name,
+		Owner:     workspace.Annotations[annotationCreatedBy],
+		Template:  workspace.Labels[workspaceutil.LabelWorkspaceTemplate],
+	}
+	if record.Template == "" && workspace.Spec.TemplateRef != nil {
+		record.Template = workspace.Spec.TemplateRef.Name
+	}
+	if usage := workspace.Status.Usage; usage != nil {
+		record.RunningHours = float64(usage.RunningSeconds) / secondsPerHour
+		record.CPUCoreHours = float64(usage.CPUMilliCoreSeconds) / milliPerUnit / secondsPerHour
+		record.MemoryGiBHours = float64(usage.MemoryMiBSeconds) / mibPerGiB / secondsPerHour
+		record.GPUHours = float64(usage.GPUSeconds) / secondsPerHour
+	}
+	return record
+}
+
+func writeJSON(w io.Writer, records []UsageRecord) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func writeCSV(w io.Writer, records []UsageRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write([]string{
+			record.Namespace,
+			record.Workspace,
+			record.Owner,
+			record.Template,
+			formatHours(record.RunningHours),
+			formatHours(record.CPUCoreHours),
+			formatHours(record.MemoryGiBHours),
+			formatHours(record.GPUHours),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', 4, 64)
+}
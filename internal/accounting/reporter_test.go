@@ -0,0 +1,105 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func newTestReporter(t *testing.T, format string) *Reporter {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-notebook",
+			Namespace:   "team-a",
+			Annotations: map[string]string{annotationCreatedBy: "alice"},
+			Labels:      map[string]string{workspaceutil.LabelWorkspaceTemplate: "gpu-small"},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			Usage: &workspacev1alpha1.WorkspaceUsageStatus{
+				RunningSeconds:      7200,
+				CPUMilliCoreSeconds: 14400000,
+				MemoryMiBSeconds:    29491200,
+				GPUSeconds:          7200,
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+
+	reporter, err := NewReporter(k8sClient, t.TempDir(), format, 0)
+	require.NoError(t, err)
+	reporter.now = func() time.Time { return time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC) }
+	return reporter
+}
+
+func TestReporter_WritesCSVReport(t *testing.T) {
+	reporter := newTestReporter(t, FormatCSV)
+
+	path, err := reporter.WriteReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(reporter.dir, "usage-20260101T100000Z.csv"), path)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"namespace,workspace,owner,template,runningHours,cpuCoreHours,memoryGiBHours,gpuHours\n"+
+			"team-a,my-notebook,alice,gpu-small,2.0000,4.0000,8.0000,2.0000\n",
+		string(content))
+
+	// Reports are never overwritten
+	_, err = reporter.WriteReport(context.Background())
+	assert.Error(t, err)
+}
+
+func TestReporter_WritesJSONReport(t *testing.T) {
+	reporter := newTestReporter(t, FormatJSON)
+
+	path, err := reporter.WriteReport(context.Background())
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var records []UsageRecord
+	require.NoError(t, json.Unmarshal(content, &records))
+	assert.Equal(t, []UsageRecord{{
+		Namespace:      "team-a",
+		Workspace:      "my-notebook",
+		Owner:          "alice",
+		Template:       "gpu-small",
+		RunningHours:   2,
+		CPUCoreHours:   4,
+		MemoryGiBHours: 8,
+		GPUHours:       2,
+	}}, records)
+}
+
+func TestNewReporter_RejectsInvalidConfig(t *testing.T) {
+	_, err := NewReporter(nil, "", FormatCSV, 0)
+	assert.Error(t, err)
+
+	_, err = NewReporter(nil, t.TempDir(), "xml", 0)
+	assert.Error(t, err)
+
+	reporter, err := NewReporter(nil, t.TempDir(), FormatJSON, 0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReportInterval, reporter.interval)
+	assert.True(t, reporter.NeedLeaderElection())
+}
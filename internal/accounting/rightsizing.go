@@ -0,0 +1,185 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Right-sizing defaults
+const (
+	// DefaultRightSizingWindow is the default running time over which the utilization of the
+	// requests of a workspace is observed before recommending requests
+	DefaultRightSizingWindow = 24 * time.Hour
+
+	// DefaultLowUtilizationPercent is the default utilization of a request below which a workspace
+	// is recommended a lower request
+	DefaultLowUtilizationPercent = 20
+
+	// DefaultHighUtilizationPercent is the default utilization of a request above which a workspace
+	// is recommended a higher request
+	DefaultHighUtilizationPercent = 90
+)
+
+// rightSizingHeadroomPercent is the headroom recommended requests leave above the peak usage
+const rightSizingHeadroomPercent = 25
+
+// Recommended requests are rounded up to these steps
+var (
+	cpuRequestStep    = resource.MustParse("50m")
+	memoryRequestStep = resource.MustParse("64Mi")
+)
+
+// RightSizingOptions configures the right-sizing recommendations of a Sampler
+type RightSizingOptions struct {
+	// Window is the running time over which the utilization of the requests of a workspace is
+	// observed. Zero means use DefaultRightSizingWindow.
+	Window time.Duration
+
+	// LowUtilizationPercent is the utilization of a request below which a workspace is recommended
+	// a lower request. Zero means use DefaultLowUtilizationPercent.
+	LowUtilizationPercent int32
+
+	// HighUtilizationPercent is the utilization of a request above which a workspace is recommended
+	// a higher request. Zero means use DefaultHighUtilizationPercent.
+	HighUtilizationPercent int32
+}
+
+// withDefaults returns the options with their defaults filled in
+func (o RightSizingOptions) withDefaults() RightSizingOptions {
+	if o.Window <= 0 {
+		o.Window = DefaultRightSizingWindow
+	}
+	if o.LowUtilizationPercent <= 0 {
+		o.LowUtilizationPercent = DefaultLowUtilizationPercent
+	}
+	if o.HighUtilizationPercent <= 0 {
+		o.HighUtilizationPercent = DefaultHighUtilizationPercent
+	}
+	return o
+}
+
+// observeRightSizing adds a usage sample to the right-sizing window of a workspace. Once the window
+// holds the samples of its whole running time, it recommends requests for the resources whose
+// utilization stayed below the low or above the high threshold throughout, and starts a new window.
+// The window restarts without a recommendation when the requests of the workspace change.
+func (s *Sampler) observeRightSizing(workspace *workspacev1alpha1.Workspace, current *workspacev1alpha1.WorkspaceResourceUtilization,
+	requests corev1.ResourceList, now time.Time) {
+	if s.options.RightSizing == nil {
+		return
+	}
+	usage := workspace.Status.Usage
+	observed := corev1.ResourceList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if request, ok := requests[name]; ok && !request.IsZero() {
+			observed[name] = request.DeepCopy()
+		}
+	}
+	if len(observed) == 0 {
+		usage.RightSizing = nil
+		return
+	}
+
+	window := usage.RightSizing
+	if window == nil || !sameRequests(window.Requests, observed) {
+		window = &workspacev1alpha1.WorkspaceRightSizingStatus{Requests: observed, WindowStartTime: metav1.Time{Time: now}}
+		usage.RightSizing = window
+	}
+	window.Samples++
+	window.CPU = extendUtilizationRange(window.CPU, current.CPU, current.CPUPercent)
+	window.Memory = extendUtilizationRange(window.Memory, current.Memory, current.MemoryPercent)
+	if window.Samples < s.windowSamples() {
+		return
+	}
+
+	options := *s.options.RightSizing
+	recommended := corev1.ResourceList{}
+	if request, ok := recommendRequest(window.CPU, observed[corev1.ResourceCPU], cpuRequestStep, options); ok {
+		recommended[corev1.ResourceCPU] = request
+	}
+	if request, ok := recommendRequest(window.Memory, observed[corev1.ResourceMemory], memoryRequestStep, options); ok {
+		recommended[corev1.ResourceMemory] = request
+	}
+	window.RecommendedRequests = nil
+	if len(recommended) > 0 {
+		window.RecommendedRequests = recommended
+	}
+	window.WindowStartTime = metav1.Time{Time: now}
+	window.Samples = 0
+	window.CPU = nil
+	window.Memory = nil
+}
+
+// windowSamples returns the number of samples taken over the running time of a window
+func (s *Sampler) windowSamples() int32 {
+	samples := (s.options.RightSizing.Window + s.options.Interval - 1) / s.options.Interval
+	return int32(max(samples, 1))
+}
+
+// sameRequests returns whether the observed requests of a window are the current ones
+func sameRequests(windowRequests, requests corev1.ResourceList) bool {
+	if len(windowRequests) != len(requests) {
+		return false
+	}
+	for name, request := range requests {
+		windowRequest, ok := windowRequests[name]
+		if !ok || windowRequest.Cmp(request) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// extendUtilizationRange extends the utilization range of a request with a sample. Samples without
+// the usage or utilization of the resource leave the range as it is.
+func extendUtilizationRange(utilization *workspacev1alpha1.WorkspaceUtilizationRange, used *resource.Quantity,
+	percent *int32) *workspacev1alpha1.WorkspaceUtilizationRange {
+	if used == nil || percent == nil {
+		return utilization
+	}
+	if utilization == nil {
+		return &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: *percent, MaxPercent: *percent, Peak: used.DeepCopy()}
+	}
+	utilization.MinPercent = min(utilization.MinPercent, *percent)
+	utilization.MaxPercent = max(utilization.MaxPercent, *percent)
+	if used.Cmp(utilization.Peak) > 0 {
+		utilization.Peak = used.DeepCopy()
+	}
+	return utilization
+}
+
+// recommendRequest recommends the peak usage plus headroom, rounded up to step, for a request whose
+// utilization stayed below the low or above the high threshold over the window. It recommends
+// nothing when the rounded recommendation would not move the request in the right direction.
+func recommendRequest(utilization *workspacev1alpha1.WorkspaceUtilizationRange, request, step resource.Quantity,
+	options RightSizingOptions) (resource.Quantity, bool) {
+	if utilization == nil {
+		return resource.Quantity{}, false
+	}
+	low := utilization.MaxPercent < options.LowUtilizationPercent
+	high := utilization.MinPercent > options.HighUtilizationPercent
+	if !low && !high {
+		return resource.Quantity{}, false
+	}
+
+	stepMilli := step.MilliValue()
+	milli := utilization.Peak.MilliValue() * (100 + rightSizingHeadroomPercent) / 100
+	milli = max((milli+stepMilli-1)/stepMilli*stepMilli, stepMilli)
+	recommended := *resource.NewMilliQuantity(milli, step.Format)
+	if milli%milliPerUnit == 0 {
+		recommended = *resource.NewQuantity(milli/milliPerUnit, step.Format)
+	}
+	if (low && recommended.Cmp(request) >= 0) || (high && recommended.Cmp(request) <= 0) {
+		return resource.Quantity{}, false
+	}
+	return recommended, true
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// setPodMetricsUsage sets the usage of the workspace container in the pod metrics read by the sampler
+func setPodMetricsUsage(t *testing.T, sampler *Sampler, cpu, memory string) {
+	t.Helper()
+	podMetrics := &unstructured.Unstructured{}
+	podMetrics.SetGroupVersionKind(podMetricsGVK)
+	key := types.NamespacedName{Namespace: "default", Name: testPodName}
+	k8sClient := sampler.reader.(client.Client)
+	require.NoError(t, k8sClient.Get(context.Background(), key, podMetrics))
+	podMetrics.Object["containers"] = newTestPodMetrics(cpu, memory).Object["containers"]
+	require.NoError(t, k8sClient.Update(context.Background(), podMetrics))
+}
+
+// sampleWindow samples a workspace once per interval over a whole right-sizing window, with the
+// usage of the workspace container cycling through cpu and memory
+func sampleWindow(t *testing.T, sampler *Sampler, workspace *workspacev1alpha1.Workspace, now *time.Time, cpu, memory []string) {
+	t.Helper()
+	require.Len(t, cpu, len(memory))
+	for i := range int(sampler.windowSamples()) {
+		setPodMetricsUsage(t, sampler, cpu[i%len(cpu)], memory[i%len(memory)])
+		*now = now.Add(sampler.Interval())
+		sampler.Sample(context.Background(), workspace)
+	}
+}
+
+func newRightSizingTestSampler() (*Sampler, *time.Time) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	sampler := newTestSampler(SamplerOptions{
+		Interval:    10 * time.Minute,
+		RightSizing: &RightSizingOptions{Window: time.Hour},
+	}, newTestPodMetrics("0", "0"))
+	sampler.now = func() time.Time { return now }
+	return sampler, &now
+}
+
+func TestSampler_RightSizingRecommendsLowerRequests(t *testing.T) {
+	sampler, now := newRightSizingTestSampler()
+	workspace := newSamplerTestWorkspace()
+
+	// 500m CPU and 2Gi memory requested; CPU stays under 20%, memory within the thresholds
+	sampleWindow(t, sampler, workspace, now, []string{"20m", "90m"}, []string{"1Gi", "1500Mi"})
+
+	rightSizing := workspace.Status.Usage.RightSizing
+	require.NotNil(t, rightSizing)
+	require.Len(t, rightSizing.RecommendedRequests, 1)
+	assert.Equal(t, "150m", rightSizing.RecommendedRequests.Cpu().String())
+	assert.Equal(t, *now, rightSizing.WindowStartTime.Time)
+	assert.Zero(t, rightSizing.Samples)
+	assert.Nil(t, rightSizing.CPU)
+}
+
+func TestSampler_RightSizingRecommendsHigherRequests(t *testing.T) {
+	sampler, now := newRightSizingTestSampler()
+	workspace := newSamplerTestWorkspace()
+
+	// Memory stays above 90% of the 2Gi request
+	sampleWindow(t, sampler, workspace, now, []string{"200m", "300m"}, []string{"1900Mi", "2100Mi"})
+
+	rightSizing := workspace.Status.Usage.RightSizing
+	require.Len(t, rightSizing.RecommendedRequests, 1)
+	assert.Equal(t, "2688Mi", rightSizing.RecommendedRequests.Memory().String())
+}
+
+func TestSampler_RightSizingNeedsConsistentUtilization(t *testing.T) {
+	sampler, now := newRightSizingTestSampler()
+	workspace := newSamplerTestWorkspace()
+
+	// A single spike of CPU keeps the request
+	sampleWindow(t, sampler, workspace, now, []string{"20m", "20m", "400m"}, []string{"1Gi", "1Gi", "1Gi"})
+
+	rightSizing := workspace.Status.Usage.RightSizing
+	require.NotNil(t, rightSizing)
+	assert.Nil(t, rightSizing.RecommendedRequests)
+}
+
+func TestSampler_RightSizingWindowInProgress(t *testing.T) {
+	sampler, now := newRightSizingTestSampler()
+	workspace := newSamplerTestWorkspace()
+	setPodMetricsUsage(t, sampler, "20m", "1Gi")
+
+	sampler.Sample(context.Background(), workspace)
+	*now = now.Add(sampler.Interval())
+	setPodMetricsUsage(t, sampler, "60m", "1500Mi")
+	sampler.Sample(context.Background(), workspace)
+
+	rightSizing := workspace.Status.Usage.RightSizing
+	require.NotNil(t, rightSizing)
+	assert.Equal(t, int32(2), rightSizing.Samples)
+	assert.Equal(t, &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: 4, MaxPercent: 12, Peak: resource.MustParse("60m")}, rightSizing.CPU)
+	assert.Equal(t, int32(50), rightSizing.Memory.MinPercent)
+	assert.Equal(t, int32(73), rightSizing.Memory.MaxPercent)
+	assert.Nil(t, rightSizing.RecommendedRequests)
+}
+
+func TestSampler_RightSizingRestartsWhenRequestsChange(t *testing.T) {
+	sampler, now := newRightSizingTestSampler()
+	workspace := newSamplerTestWorkspace()
+	sampleWindow(t, sampler, workspace, now, []string{"20m"}, []string{"1Gi"})
+	require.NotEmpty(t, workspace.Status.Usage.RightSizing.RecommendedRequests)
+
+	workspace.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("150m")
+	*now = now.Add(sampler.Interval())
+	sampler.Sample(context.Background(), workspace)
+
+	rightSizing := workspace.Status.Usage.RightSizing
+	assert.Nil(t, rightSizing.RecommendedRequests)
+	assert.Equal(t, int32(1), rightSizing.Samples)
+	assert.True(t, rightSizing.Requests.Cpu().Equal(resource.MustParse("150m")))
+
+	// The right-sizing window is kept once the workspace stops
+	sampler.Clear(workspace)
+	assert.NotNil(t, workspace.Status.Usage.RightSizing)
+}
+
+func TestSampler_RightSizingDisabled(t *testing.T) {
+	sampler := newTestSampler(SamplerOptions{}, newTestPodMetrics("20m", "1Gi"))
+	workspace := newSamplerTestWorkspace()
+
+	sampler.Sample(context.Background(), workspace)
+
+	assert.Nil(t, workspace.Status.Usage.RightSizing)
+}
+
+func TestRecommendRequest(t *testing.T) {
+	options := RightSizingOptions{}.withDefaults()
+	tests := []struct {
+		name        string
+		utilization *workspacev1alpha1.WorkspaceUtilizationRange
+		request     string
+		step        resource.Quantity
+		expected    string
+	}{
+		{name: "no samples", request: "1", step: cpuRequestStep},
+		{name: "within thresholds", request: "1", step: cpuRequestStep,
+			utilization: &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: 10, MaxPercent: 95, Peak: resource.MustParse("950m")}},
+		{name: "low", request: "1", step: cpuRequestStep, expected: "150m",
+			utilization: &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: 2, MaxPercent: 11, Peak: resource.MustParse("110m")}},
+		{name: "low rounds to at least one step", request: "1", step: cpuRequestStep, expected: "50m",
+			utilization: &workspacev1alpha1.WorkspaceUtilizationRange{MaxPercent: 0, Peak: resource.MustParse("0")}},
+		{name: "low on a request already one step", request: "50m", step: cpuRequestStep,
+			utilization: &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: 2, MaxPercent: 10, Peak: resource.MustParse("5m")}},
+		{name: "high", request: "4Gi", step: memoryRequestStep, expected: "5Gi",
+			utilization: &workspacev1alpha1.WorkspaceUtilizationRange{MinPercent: 92, MaxPercent: 100, Peak: resource.MustParse("4Gi")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommended, ok := recommendRequest(tt.utilization, resource.MustParse(tt.request), tt.step, options)
+
+			if tt.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, recommended.String())
+		})
+	}
+}
+
+func TestSampler_WindowSamples(t *testing.T) {
+	sampler := NewSampler(nil, SamplerOptions{Interval: time.Minute, RightSizing: &RightSizingOptions{}})
+	assert.Equal(t, int32(24*60), sampler.windowSamples())
+	assert.Equal(t, RightSizingOptions{
+		Window:                 DefaultRightSizingWindow,
+		LowUtilizationPercent:  DefaultLowUtilizationPercent,
+		HighUtilizationPercent: DefaultHighUtilizationPercent,
+	}, *sampler.options.RightSizing)
+
+	sampler = NewSampler(nil, SamplerOptions{Interval: 7 * time.Minute, RightSizing: &RightSizingOptions{Window: 15 * time.Minute}})
+	assert.Equal(t, int32(3), sampler.windowSamples())
+}
@@ -0,0 +1,261 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultSampleInterval is the default interval between samples of the live usage of a workspace
+const DefaultSampleInterval = time.Minute
+
+// DefaultGPUQuery is the default Prometheus query of the GPU utilization of a workspace pod, in
+// percent, from the NVIDIA DCGM exporter. {namespace} and {pod} are replaced with the namespace and
+// name of the pod.
+const DefaultGPUQuery = `avg(DCGM_FI_DEV_GPU_UTIL{namespace="{namespace}",pod="{pod}"})`
+
+// prometheusQueryTimeout bounds a query of the GPU utilization of a workspace
+const prometheusQueryTimeout = 5 * time.Second
+
+// podMetricsGVK is the kind of the pod metrics of the resource metrics API, read as unstructured
+// objects so that the controller does not depend on the metrics client
+var podMetricsGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}
+
+// SamplerOptions configures a Sampler
+type SamplerOptions struct {
+	// Container is the name of the workspace container whose usage is sampled
+	Container string
+
+	// Interval is the interval between samples of a workspace. Zero means use DefaultSampleInterval.
+	Interval time.Duration
+
+	// PrometheusURL is the URL of the Prometheus API queried for the GPU utilization of workspaces.
+	// When empty, the GPU utilization is not sampled.
+	PrometheusURL string
+
+	// GPUQuery is the Prometheus query of the GPU utilization. Empty means use DefaultGPUQuery.
+	GPUQuery string
+
+	// RightSizing configures the right-sizing recommendations made from the samples. When nil,
+	// no recommendations are made.
+	RightSizing *RightSizingOptions
+}
+
+// Sampler samples the live resource usage of running workspaces in their status.usage.current,
+// from the resource metrics API, e.g. metrics-server, and from Prometheus for GPUs. It samples a
+// workspace at most once per interval, to bound the status updates, and recommends right-sized
+// requests from the samples when configured to. It only changes the workspace in memory; the
+// status update that follows persists it.
+type Sampler struct {
+	reader     client.Reader
+	options    SamplerOptions
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewSampler creates a new Sampler reading pod metrics with reader, which must not be a cached
+// client since pod metrics cannot be watched
+func NewSampler(reader client.Reader, options SamplerOptions) *Sampler {
+	if options.Interval <= 0 {
+		options.Interval = DefaultSampleInterval
+	}
+	if options.GPUQuery == "" {
+		options.GPUQuery = DefaultGPUQuery
+	}
+	if options.RightSizing != nil {
+		rightSizing := options.RightSizing.withDefaults()
+		options.RightSizing = &rightSizing
+	}
+	return &Sampler{
+		reader:     reader,
+		options:    options,
+		httpClient: &http.Client{Timeout: prometheusQueryTimeout},
+		now:        time.Now,
+	}
+}
+
+// Interval returns the interval between samples of a workspace
+func (s *Sampler) Interval() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.options.Interval
+}
+
+// Sample samples the usage of the pod of a running workspace once the interval elapsed since the
+// last sample. A failed sample is logged and keeps the previous one, since the metrics of a new pod
+// are only available after a scrape.
+func (s *Sampler) Sample(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	if s == nil || workspace.Status.PodStatus == nil || workspace.Status.PodStatus.Name == "" {
+		return
+	}
+	now := s.now()
+	if usage := workspace.Status.Usage; usage != nil && usage.Current != nil &&
+		now.Sub(usage.Current.SampleTime.Time) < s.options.Interval {
+		return
+	}
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name, "pod", workspace.Status.PodStatus.Name)
+
+	cpu, memory, err := s.containerUsage(ctx, workspace.Namespace, workspace.Status.PodStatus.Name)
+	if err != nil {
+		logger.V(1).Info("Failed to sample workspace resource usage", "error", err.Error())
+		return
+	}
+
+	requests := resourceRequests(workspace)
+	current := &workspacev1alpha1.WorkspaceResourceUtilization{
+		CPU:           &cpu,
+		CPUPercent:    utilizationPercent(cpu.MilliValue(), requests.Cpu().MilliValue()),
+		Memory:        &memory,
+		MemoryPercent: utilizationPercent(memory.Value(), requests.Memory().Value()),
+		SampleTime:    metav1.Time{Time: now},
+	}
+	if s.options.PrometheusURL != "" && gpuCount(requests) > 0 {
+		gpuPercent, err := s.gpuUtilization(ctx, workspace.Namespace, workspace.Status.PodStatus.Name)
+		if err != nil {
+			logger.V(1).Info("Failed to sample workspace GPU utilization", "error", err.Error())
+		} else {
+			current.GPUPercent = gpuPercent
+		}
+	}
+
+	if workspace.Status.Usage == nil {
+		workspace.Status.Usage = &workspacev1alpha1.WorkspaceUsageStatus{}
+	}
+	workspace.Status.Usage.Current = current
+	s.observeRightSizing(workspace, current, requests, now)
+}
+
+// Clear drops the live usage of a workspace that no longer runs, and its usage status altogether
+// when nothing was accounted in it
+func (s *Sampler) Clear(workspace *workspacev1alpha1.Workspace) {
+	if s == nil || workspace.Status.Usage == nil {
+		return
+	}
+	workspace.Status.Usage.Current = nil
+	if *workspace.Status.Usage == (workspacev1alpha1.WorkspaceUsageStatus{}) {
+		workspace.Status.Usage = nil
+	}
+}
+
+// containerUsage returns the CPU and memory the workspace container of a pod uses, from its pod metrics
+func (s *Sampler) containerUsage(ctx context.Context, namespace, podName string) (resource.Quantity, resource.Quantity, error) {
+	podMetrics := &unstructured.Unstructured{}
+	podMetrics.SetGroupVersionKind(podMetricsGVK)
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, podMetrics); err != nil {
+		return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("failed to get pod metrics: %w", err)
+	}
+
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("invalid pod metrics: %w", err)
+	}
+	for _, container := range containers {
+		fields, ok := container.(map[string]any)
+		if !ok || fields["name"] != s.options.Container {
+			continue
+		}
+		usage, _, err := unstructured.NestedStringMap(fields, "usage")
+		if err != nil {
+			return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("invalid pod metrics: %w", err)
+		}
+		cpu, err := resource.ParseQuantity(usage[string(corev1.ResourceCPU)])
+		if err != nil {
+			return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("invalid CPU usage: %w", err)
+		}
+		memory, err := resource.ParseQuantity(usage[string(corev1.ResourceMemory)])
+		if err != nil {
+			return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("invalid memory usage: %w", err)
+		}
+		return cpu, memory, nil
+	}
+	return resource.Quantity{}, resource.Quantity{}, fmt.Errorf("no metrics for container %s", s.options.Container)
+}
+
+// prometheusQueryResponse is the response of an instant query of the Prometheus API
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			// Value is the [timestamp, "value"] pair of the sample
+			Value []any `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// gpuUtilization queries Prometheus for the GPU utilization of a pod, in percent. It returns nil
+// when Prometheus has no GPU metrics for the pod.
+func (s *Sampler) gpuUtilization(ctx context.Context, namespace, podName string) (*int32, error) {
+	query := strings.NewReplacer("{namespace}", namespace, "{pod}", podName).Replace(s.options.GPUQuery)
+	endpoint := strings.TrimRight(s.options.PrometheusURL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus query: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("prometheus query failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid prometheus response: %w", err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", result.Error)
+	}
+	if len(result.Data.Result) == 0 {
+		return nil, nil
+	}
+	sample := result.Data.Result[0].Value
+	if len(sample) != 2 {
+		return nil, fmt.Errorf("invalid prometheus sample %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid prometheus sample %v", sample)
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(percent) {
+		return nil, fmt.Errorf("invalid GPU utilization %q", value)
+	}
+	return ptr.To(int32(math.Round(percent))), nil
+}
+
+// utilizationPercent returns used as a percentage of requested, or nil without a request
+func utilizationPercent(used, requested int64) *int32 {
+	if requested <= 0 {
+		return nil
+	}
+	return ptr.To(int32(math.Round(float64(used) * 100 / float64(requested))))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accounting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testPodName = "sample-ws-pod"
+
+func newSamplerTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newAccountingTestWorkspace("sample-ws")
+	workspace.Status.PodStatus = &workspacev1alpha1.WorkspacePodStatus{Name: testPodName}
+	return workspace
+}
+
+func newTestPodMetrics(cpu, memory string) *unstructured.Unstructured {
+	podMetrics := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": testPodName, "namespace": "default"},
+		"containers": []any{
+			map[string]any{"name": "sidecar", "usage": map[string]any{"cpu": "1", "memory": "1Gi"}},
+			map[string]any{"name": "workspace", "usage": map[string]any{"cpu": cpu, "memory": memory}},
+		},
+	}}
+	podMetrics.SetGroupVersionKind(podMetricsGVK)
+	return podMetrics
+}
+
+func newTestSampler(options SamplerOptions, podMetrics ...*unstructured.Unstructured) *Sampler {
+	builder := fake.NewClientBuilder().WithScheme(runtime.NewScheme())
+	for _, obj := range podMetrics {
+		builder = builder.WithObjects(obj)
+	}
+	options.Container = "workspace"
+	return NewSampler(builder.Build(), options)
+}
+
+func TestSampler_SampleReportsUtilizationOncePerInterval(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	sampler := newTestSampler(SamplerOptions{}, newTestPodMetrics("250m", "3Gi"))
+	sampler.now = func() time.Time { return now }
+	workspace := newSamplerTestWorkspace()
+
+	sampler.Sample(context.Background(), workspace)
+
+	require.NotNil(t, workspace.Status.Usage)
+	current := workspace.Status.Usage.Current
+	require.NotNil(t, current)
+	assert.True(t, current.CPU.Equal(resource.MustParse("250m")))
+	assert.Equal(t, ptr.To[int32](50), current.CPUPercent)
+	assert.True(t, current.Memory.Equal(resource.MustParse("3Gi")))
+	assert.Equal(t, ptr.To[int32](150), current.MemoryPercent)
+	assert.Nil(t, current.GPUPercent)
+	assert.Equal(t, now, current.SampleTime.Time)
+
+	// Within the interval, the previous sample is kept
+	now = now.Add(30 * time.Second)
+	sampler.Sample(context.Background(), workspace)
+	assert.Equal(t, now.Add(-30*time.Second), workspace.Status.Usage.Current.SampleTime.Time)
+
+	// Past the interval
+	now = now.Add(DefaultSampleInterval)
+	sampler.Sample(context.Background(), workspace)
+	assert.Equal(t, now, workspace.Status.Usage.Current.SampleTime.Time)
+}
+
+func TestSampler_SampleWithoutRequests(t *testing.T) {
+	sampler := newTestSampler(SamplerOptions{}, newTestPodMetrics("250m", "3Gi"))
+	workspace := newSamplerTestWorkspace()
+	workspace.Spec.Resources = nil
+
+	sampler.Sample(context.Background(), workspace)
+
+	current := workspace.Status.Usage.Current
+	require.NotNil(t, current)
+	assert.NotNil(t, current.CPU)
+	assert.Nil(t, current.CPUPercent)
+	assert.Nil(t, current.MemoryPercent)
+}
+
+func TestSampler_SampleKeepsPreviousSampleWithoutMetrics(t *testing.T) {
+	sampler := newTestSampler(SamplerOptions{})
+	workspace := newSamplerTestWorkspace()
+	previous := &workspacev1alpha1.WorkspaceResourceUtilization{SampleTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+	workspace.Status.Usage = &workspacev1alpha1.WorkspaceUsageStatus{Current: previous}
+
+	sampler.Sample(context.Background(), workspace)
+
+	assert.Same(t, previous, workspace.Status.Usage.Current)
+}
+
+func TestSampler_SampleQueriesPrometheusForGPUs(t *testing.T) {
+	var query string
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		query = r.URL.Query().Get("query")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1767261600,"72.6"]}]}}`))
+	}))
+	defer prometheus.Close()
+	sampler := newTestSampler(SamplerOptions{PrometheusURL: prometheus.URL + "/"}, newTestPodMetrics("250m", "1Gi"))
+	workspace := newSamplerTestWorkspace()
+
+	sampler.Sample(context.Background(), workspace)
+
+	assert.Equal(t, `avg(DCGM_FI_DEV_GPU_UTIL{namespace="default",pod="sample-ws-pod"})`, query)
+	assert.Equal(t, ptr.To[int32](73), workspace.Status.Usage.Current.GPUPercent)
+
+	// Without GPU requests, Prometheus is not queried
+	query = ""
+	workspace = newSamplerTestWorkspace()
+	delete(workspace.Spec.Resources.Requests, "nvidia.com/gpu")
+	sampler.Sample(context.Background(), workspace)
+	assert.Empty(t, query)
+	assert.Nil(t, workspace.Status.Usage.Current.GPUPercent)
+}
+
+func TestSampler_Clear(t *testing.T) {
+	sampler := newTestSampler(SamplerOptions{})
+	current := &workspacev1alpha1.WorkspaceResourceUtilization{SampleTime: metav1.Now()}
+
+	// Keeps the accounted usage
+	workspace := newSamplerTestWorkspace()
+	workspace.Status.Usage = &workspacev1alpha1.WorkspaceUsageStatus{RunningSeconds: 60, Current: current}
+	sampler.Clear(workspace)
+	assert.Equal(t, &workspacev1alpha1.WorkspaceUsageStatus{RunningSeconds: 60}, workspace.Status.Usage)
+
+	// Drops an otherwise empty usage status
+	workspace.Status.Usage = &workspacev1alpha1.WorkspaceUsageStatus{Current: current}
+	sampler.Clear(workspace)
+	assert.Nil(t, workspace.Status.Usage)
+
+	// A nil sampler leaves the status alone
+	workspace.Status.Usage = &workspacev1alpha1.WorkspaceUsageStatus{Current: current}
+	(*Sampler)(nil).Clear(workspace)
+	assert.Same(t, current, workspace.Status.Usage.Current)
+}
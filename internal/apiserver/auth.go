@@ -0,0 +1,91 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errUnauthenticated is returned when the bearer token of a request is missing or rejected
+var errUnauthenticated = errors.New("unauthenticated request")
+
+// ClientFactory creates the clients the API gateway performs the operations of a caller with
+type ClientFactory interface {
+	// ClientFor returns a client acting as user
+	ClientFor(user authenticationv1.UserInfo) (client.Client, error)
+}
+
+// impersonatingClientFactory creates clients impersonating the caller with the credentials of the
+// API gateway, which needs the impersonate permission on users, groups, uids and userextras
+type impersonatingClientFactory struct {
+	config *rest.Config
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+// NewImpersonatingClientFactory creates a ClientFactory impersonating callers with config.
+// The clients share the REST mapper, so that each request does not run an API discovery.
+func NewImpersonatingClientFactory(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper) ClientFactory {
+	return &impersonatingClientFactory{config: config, scheme: scheme, mapper: mapper}
+}
+
+// ClientFor returns a client impersonating user
+func (f *impersonatingClientFactory) ClientFor(user authenticationv1.UserInfo) (client.Client, error) {
+	config := rest.CopyConfig(f.config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+	}
+	if len(user.Extra) > 0 {
+		config.Impersonate.Extra = make(map[string][]string, len(user.Extra))
+		for key, values := range user.Extra {
+			config.Impersonate.Extra[key] = values
+		}
+	}
+
+	k8sClient, err := client.New(config, client.Options{Scheme: f.scheme, Mapper: f.mapper})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonating client: %w", err)
+	}
+	return k8sClient, nil
+}
+
+// authenticate reviews the bearer token of the request and returns the identity of the caller.
+// It returns errUnauthenticated when the token is missing or rejected.
+func (s *APIServer) authenticate(ctx context.Context, r *http.Request) (*authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		return nil, errUnauthenticated
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     strings.TrimSpace(token),
+			Audiences: s.config.Audiences,
+		},
+	}
+	result, err := s.tokenReviews.Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TokenReview: %w", err)
+	}
+	if !result.Status.Authenticated {
+		s.logger.V(1).Info("Bearer token rejected", "error", result.Status.Error)
+		return nil, errUnauthenticated
+	}
+	return &result.Status.User, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package apiserver provides the API gateway, a REST API that lets clients without cluster
+// access, such as a JupyterLab extension or a web dashboard, list, create, start and stop
+// workspaces. It authenticates callers with a TokenReview of their bearer token and performs
+// every operation impersonating them, so that the RBAC of the caller applies.
+package apiserver
+
+import "time"
+
+// Default values
+const (
+	DefaultServerPort      = 8095
+	DefaultReadTimeout     = 10 * time.Second
+	DefaultWriteTimeout    = 30 * time.Second
+	DefaultShutdownTimeout = 10 * time.Second
+
+	// DefaultMaxRequestBodyBytes bounds the body of a workspace creation request
+	DefaultMaxRequestBodyBytes = 1 << 20
+)
+
+// APIServerConfig contains the configuration for the API gateway
+type APIServerConfig struct {
+	ServerPort int
+
+	// CertPath and KeyPath, when both set, make the server serve HTTPS
+	CertPath string
+	KeyPath  string
+
+	// Audiences are the audiences bearer tokens are reviewed against; the default audience
+	// of the Kubernetes API server when empty
+	Audiences []string
+
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
+	ShutdownTimeout     time.Duration
+	MaxRequestBodyBytes int64
+}
+
+// ConfigOption is a function that modifies an APIServerConfig
+type ConfigOption func(*APIServerConfig)
+
+// WithServerPort sets the server port
+func WithServerPort(port int) ConfigOption {
+	return func(c *APIServerConfig) {
+		c.ServerPort = port
+	}
+}
+
+// WithTLS sets the certificate and key files the server serves HTTPS with
+func WithTLS(certPath, keyPath string) ConfigOption {
+	return func(c *APIServerConfig) {
+		c.CertPath = certPath
+		c.KeyPath = keyPath
+	}
+}
+
+// WithAudiences sets the audiences bearer tokens are reviewed against
+func WithAudiences(audiences []string) ConfigOption {
+	return func(c *APIServerConfig) {
+		c.Audiences = audiences
+	}
+}
+
+// NewConfig creates a new APIServerConfig with default values and applies the given options
+func NewConfig(opts ...ConfigOption) *APIServerConfig {
+	config := &APIServerConfig{
+		ServerPort:          DefaultServerPort,
+		ReadTimeout:         DefaultReadTimeout,
+		WriteTimeout:        DefaultWriteTimeout,
+		ShutdownTimeout:     DefaultShutdownTimeout,
+		MaxRequestBodyBytes: DefaultMaxRequestBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
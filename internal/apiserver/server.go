@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// workspacesPath is the prefix of the workspace routes of a namespace
+const workspacesPath = "/api/v1/namespaces/{namespace}/workspaces"
+
+// APIServer serves the REST API of the API gateway
+type APIServer struct {
+	config       *APIServerConfig
+	tokenReviews authenticationv1client.TokenReviewInterface
+	clients      ClientFactory
+	logger       *logr.Logger
+	httpServer   *http.Server
+}
+
+// NewAPIServer creates a new API gateway server
+func NewAPIServer(
+	config *APIServerConfig,
+	logger *logr.Logger,
+	tokenReviews authenticationv1client.TokenReviewInterface,
+	clients ClientFactory,
+) *APIServer {
+	server := &APIServer{
+		config:       config,
+		logger:       logger,
+		tokenReviews: tokenReviews,
+		clients:      clients,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+workspacesPath, server.handleListWorkspaces)
+	mux.HandleFunc("POST "+workspacesPath, server.handleCreateWorkspace)
+	mux.HandleFunc("GET "+workspacesPath+"/{name}", server.handleGetWorkspace)
+	mux.HandleFunc("POST "+workspacesPath+"/{name}/start", server.handleStartWorkspace)
+	mux.HandleFunc("POST "+workspacesPath+"/{name}/stop", server.handleStopWorkspace)
+	mux.HandleFunc("GET "+workspacesPath+"/{name}/access", server.handleGetWorkspaceAccess)
+	mux.HandleFunc("GET /health", server.handleHealth)
+
+	server.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", config.ServerPort),
+		Handler:      mux,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	}
+	return server
+}
+
+// Start starts the API gateway server. It blocks until the context is cancelled,
+// then shuts the server down gracefully.
+func (s *APIServer) Start(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		if s.config.CertPath != "" && s.config.KeyPath != "" {
+			s.logger.Info("Starting API gateway server with TLS", "port", s.config.ServerPort)
+			err = s.httpServer.ListenAndServeTLS(s.config.CertPath, s.config.KeyPath)
+		} else {
+			s.logger.Info("Starting API gateway server", "port", s.config.ServerPort)
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("API gateway server error: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down API gateway server: %w", err)
+	}
+	return nil
+}
+
+// NewAPIServerForConfig creates the API gateway server for a cluster: it reviews tokens and
+// impersonates callers with restConfig, the credentials of the gateway
+func NewAPIServerForConfig(
+	config *APIServerConfig,
+	logger *logr.Logger,
+	restConfig *rest.Config,
+	scheme *runtime.Scheme,
+) (*APIServer, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate the TokenReview client: %w", err)
+	}
+
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	mapper, err := apiutil.NewDynamicRESTMapper(restConfig, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	clients := NewImpersonatingClientFactory(restConfig, scheme, mapper)
+	return NewAPIServer(config, logger, clientset.AuthenticationV1().TokenReviews(), clients), nil
+}
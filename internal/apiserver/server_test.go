@@ -0,0 +1,260 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+const (
+	testToken     = "valid-token"
+	testUser      = "alice"
+	testNamespace = "team-a"
+)
+
+// fakeClientFactory returns the same client for every caller and records who it acted as
+type fakeClientFactory struct {
+	client client.Client
+	users  []authenticationv1.UserInfo
+}
+
+func (f *fakeClientFactory) ClientFor(user authenticationv1.UserInfo) (client.Client, error) {
+	f.users = append(f.users, user)
+	return f.client, nil
+}
+
+func newTestWorkspace(name string, conditions ...metav1.Condition) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testNamespace,
+			Annotations: map[string]string{controller.AnnotationCreatedBy: testUser},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: name,
+			Image:       "jupyter/base-notebook",
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			AccessURL:  "https://example.com/workspaces/" + testNamespace + "/" + name + "/",
+			Conditions: conditions,
+		},
+	}
+}
+
+// newTestServer creates an API gateway whose TokenReviews only authenticate testToken as testUser
+func newTestServer(t *testing.T, funcs interceptor.Funcs, objs ...client.Object) (*APIServer, *fakeClientFactory) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(funcs).Build()
+
+	clientset := k8sfake.NewClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == testToken {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: testUser, Groups: []string{"team-a-users"}}
+		}
+		return true, review, nil
+	})
+
+	clients := &fakeClientFactory{client: k8sClient}
+	logger := logr.Discard()
+	return NewAPIServer(NewConfig(), &logger, clientset.AuthenticationV1().TokenReviews(), clients), clients
+}
+
+// serve sends an authenticated request to the server
+func serve(server *APIServer, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+testToken)
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRequestsRequireValidBearerToken(t *testing.T) {
+	server, clients := newTestServer(t, interceptor.Funcs{})
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/team-a/workspaces", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/team-a/workspaces", nil)
+	request.Header.Set("Authorization", "Bearer forged-token")
+	recorder = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	assert.Empty(t, clients.users)
+}
+
+func TestListWorkspacesActsAsCaller(t *testing.T) {
+	available := metav1.Condition{Type: controller.ConditionTypeAvailable, Status: metav1.ConditionTrue}
+	stopped := metav1.Condition{Type: controller.ConditionTypeStopped, Status: metav1.ConditionTrue}
+	stoppedWorkspace := newTestWorkspace("b-stopped", stopped)
+	stoppedWorkspace.Spec.DesiredStatus = controller.DesiredStateStopped
+	server, clients := newTestServer(t, interceptor.Funcs{},
+		stoppedWorkspace, newTestWorkspace("a-running", available))
+
+	recorder := serve(server, http.MethodGet, "/api/v1/namespaces/team-a/workspaces", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	response := WorkspaceListResponse{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Len(t, response.Workspaces, 2)
+	assert.Equal(t, "a-running", response.Workspaces[0].Name)
+	assert.Equal(t, StateRunning, response.Workspaces[0].State)
+	assert.NotEmpty(t, response.Workspaces[0].AccessURL)
+	assert.Equal(t, testUser, response.Workspaces[0].CreatedBy)
+	assert.Equal(t, "b-stopped", response.Workspaces[1].Name)
+	assert.Equal(t, StateStopped, response.Workspaces[1].State)
+	assert.Empty(t, response.Workspaces[1].AccessURL)
+
+	require.Len(t, clients.users, 1)
+	assert.Equal(t, testUser, clients.users[0].Username)
+	assert.Equal(t, []string{"team-a-users"}, clients.users[0].Groups)
+}
+
+func TestKubernetesErrorsAreRelayed(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "workspace.jupyter.org", Resource: "workspaces"},
+		"", assert.AnError)
+	server, _ := newTestServer(t, interceptor.Funcs{
+		List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+			return forbidden
+		},
+	})
+
+	recorder := serve(server, http.MethodGet, "/api/v1/namespaces/team-b/workspaces", "")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "forbidden")
+
+	recorder = serve(server, http.MethodGet, "/api/v1/namespaces/team-a/workspaces/missing", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	server, clients := newTestServer(t, interceptor.Funcs{})
+
+	recorder := serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces",
+		`{"name": "new", "spec": {"displayName": "New workspace", "image": "jupyter/base-notebook"}}`)
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, clients.client.Get(t.Context(), types.NamespacedName{Namespace: testNamespace, Name: "new"}, workspace))
+	assert.Equal(t, "New workspace", workspace.Spec.DisplayName)
+
+	recorder = serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces", `{"spec": {}}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces", `{"name": "typo", "sepc": {}}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces", `{"name": "new", "spec": {}}`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestStartAndStopWorkspace(t *testing.T) {
+	server, clients := newTestServer(t, interceptor.Funcs{}, newTestWorkspace("ws"))
+	key := types.NamespacedName{Namespace: testNamespace, Name: "ws"}
+
+	recorder := serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces/ws/stop", "")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, clients.client.Get(t.Context(), key, workspace))
+	assert.Equal(t, controller.DesiredStateStopped, workspace.Spec.DesiredStatus)
+	assert.Equal(t, "jupyter/base-notebook", workspace.Spec.Image, "the rest of the spec is kept")
+
+	recorder = serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces/ws/start", "")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.NoError(t, clients.client.Get(t.Context(), key, workspace))
+	assert.Equal(t, controller.DesiredStateRunning, workspace.Spec.DesiredStatus)
+
+	recorder = serve(server, http.MethodPost, "/api/v1/namespaces/team-a/workspaces/missing/start", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetWorkspaceAccess(t *testing.T) {
+	available := metav1.Condition{Type: controller.ConditionTypeAvailable, Status: metav1.ConditionTrue}
+	running := newTestWorkspace("running", available)
+	running.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{
+		URL:       "https://go.example.com/running",
+		AccessURL: running.Status.AccessURL,
+	}
+	server, _ := newTestServer(t, interceptor.Funcs{}, running, newTestWorkspace("starting"))
+
+	recorder := serve(server, http.MethodGet, "/api/v1/namespaces/team-a/workspaces/running/access", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	response := AccessResponse{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, AccessResponse{
+		State:     StateRunning,
+		AccessURL: running.Status.AccessURL,
+		VanityURL: "https://go.example.com/running",
+	}, response)
+
+	recorder = serve(server, http.MethodGet, "/api/v1/namespaces/team-a/workspaces/starting/access", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	response = AccessResponse{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, AccessResponse{State: StateStarting}, response)
+}
+
+func TestWorkspaceState(t *testing.T) {
+	condition := func(conditionType string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue}
+	}
+	accessDisabled := false
+	tests := []struct {
+		name          string
+		desiredStatus string
+		conditions    []metav1.Condition
+		accessEnabled *bool
+		want          string
+	}{
+		{name: "starting", want: StateStarting},
+		{name: "running", conditions: []metav1.Condition{condition(controller.ConditionTypeAvailable)}, want: StateRunning},
+		{name: "degraded", conditions: []metav1.Condition{condition(controller.ConditionTypeDegraded)}, want: StateDegraded},
+		{name: "access disabled", conditions: []metav1.Condition{condition(controller.ConditionTypeAvailable)},
+			accessEnabled: &accessDisabled, want: StateAccessDisabled},
+		{name: "stopping", desiredStatus: controller.DesiredStateStopped,
+			conditions: []metav1.Condition{condition(controller.ConditionTypeAvailable)}, want: StateStopping},
+		{name: "stopped", desiredStatus: controller.DesiredStateStopped,
+			conditions: []metav1.Condition{condition(controller.ConditionTypeStopped)}, want: StateStopped},
+		{name: "hibernated", desiredStatus: controller.DesiredStateHibernated,
+			conditions: []metav1.Condition{condition(controller.ConditionTypeStopped)}, want: StateHibernated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newTestWorkspace("ws", tt.conditions...)
+			ws.Spec.DesiredStatus = tt.desiredStatus
+			ws.Spec.AccessEnabled = tt.accessEnabled
+			assert.Equal(t, tt.want, workspaceState(ws))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// handleListWorkspaces returns the workspaces of a namespace the caller can list
+func (s *APIServer) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	k8sClient, ok := s.clientForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := k8sClient.List(r.Context(), workspaces, client.InNamespace(r.PathValue("namespace"))); err != nil {
+		s.writeKubernetesError(w, err, "list workspaces")
+		return
+	}
+
+	response := WorkspaceListResponse{Workspaces: []WorkspaceResponse{}}
+	for i := range workspaces.Items {
+		if workspaces.Items[i].DeletionTimestamp.IsZero() {
+			response.Workspaces = append(response.Workspaces, newWorkspaceResponse(&workspaces.Items[i]))
+		}
+	}
+	sort.Slice(response.Workspaces, func(i, j int) bool {
+		return response.Workspaces[i].Name < response.Workspaces[j].Name
+	})
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetWorkspace returns a workspace
+func (s *APIServer) handleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	k8sClient, ok := s.clientForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := k8sClient.Get(r.Context(), requestWorkspaceKey(r), workspace); err != nil {
+		s.writeKubernetesError(w, err, "get workspace")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newWorkspaceResponse(workspace))
+}
+
+// handleGetWorkspaceAccess returns the URLs a workspace can be reached at
+func (s *APIServer) handleGetWorkspaceAccess(w http.ResponseWriter, r *http.Request) {
+	k8sClient, ok := s.clientForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := k8sClient.Get(r.Context(), requestWorkspaceKey(r), workspace); err != nil {
+		s.writeKubernetesError(w, err, "get workspace")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newAccessResponse(workspace))
+}
+
+// handleCreateWorkspace creates a workspace. The webhooks of the controller apply the template
+// defaults and record the caller as the creator of the workspace.
+func (s *APIServer) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	k8sClient, ok := s.clientForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	request := CreateWorkspaceRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.config.MaxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid workspace creation request: %v", err))
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      request.Name,
+			Namespace: r.PathValue("namespace"),
+			Labels:    request.Labels,
+		},
+		Spec: request.Spec,
+	}
+	if err := k8sClient.Create(r.Context(), workspace); err != nil {
+		s.writeKubernetesError(w, err, "create workspace")
+		return
+	}
+	s.writeJSON(w, http.StatusCreated, newWorkspaceResponse(workspace))
+}
+
+// handleStartWorkspace sets the desired status of a workspace to Running
+func (s *APIServer) handleStartWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.setDesiredStatus(w, r, controller.DesiredStateRunning)
+}
+
+// handleStopWorkspace sets the desired status of a workspace to Stopped
+func (s *APIServer) handleStopWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.setDesiredStatus(w, r, controller.DesiredStateStopped)
+}
+
+// setDesiredStatus patches the desired status of the workspace of the request
+func (s *APIServer) setDesiredStatus(w http.ResponseWriter, r *http.Request, desiredStatus string) {
+	k8sClient, ok := s.clientForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]any{"spec": map[string]string{"desiredStatus": desiredStatus}})
+	if err != nil {
+		s.writeKubernetesError(w, err, "patch workspace")
+		return
+	}
+	key := requestWorkspaceKey(r)
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+	}
+	if err := k8sClient.Patch(r.Context(), workspace, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		s.writeKubernetesError(w, err, "patch workspace")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newWorkspaceResponse(workspace))
+}
+
+// clientForRequest authenticates the caller and returns a client acting as them.
+// It writes an error response and returns false if the request cannot be served.
+func (s *APIServer) clientForRequest(w http.ResponseWriter, r *http.Request) (client.Client, bool) {
+	user, err := s.authenticate(r.Context(), r)
+	if errors.Is(err, errUnauthenticated) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return nil, false
+	}
+	if err != nil {
+		s.logger.Error(err, "Failed to authenticate request")
+		writeError(w, http.StatusInternalServerError, "failed to authenticate request")
+		return nil, false
+	}
+
+	k8sClient, err := s.clients.ClientFor(*user)
+	if err != nil {
+		s.logger.Error(err, "Failed to create client", "username", user.Username)
+		writeError(w, http.StatusInternalServerError, "failed to create client")
+		return nil, false
+	}
+	return k8sClient, true
+}
+
+// requestWorkspaceKey returns the namespace and name of the workspace of the request
+func requestWorkspaceKey(r *http.Request) types.NamespacedName {
+	return types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+}
+
+// writeKubernetesError relays the status of a Kubernetes API error, such as a forbidden request
+// or a webhook rejection, to the caller. Other errors are only logged.
+func (s *APIServer) writeKubernetesError(w http.ResponseWriter, err error, action string) {
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) && apiStatus.Status().Code >= http.StatusBadRequest {
+		writeError(w, int(apiStatus.Status().Code), apiStatus.Status().Message)
+		return
+	}
+	s.logger.Error(err, "Kubernetes API request failed", "action", action)
+	writeError(w, http.StatusInternalServerError, "failed to "+action)
+}
+
+// writeJSON writes a JSON response
+func (s *APIServer) writeJSON(w http.ResponseWriter, statusCode int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error(err, "Failed to encode response")
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
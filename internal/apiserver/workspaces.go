@@ -0,0 +1,124 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package apiserver
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Workspace states reported by the API gateway
+const (
+	StateRunning        = "Running"
+	StateStarting       = "Starting"
+	StateStopping       = "Stopping"
+	StateStopped        = "Stopped"
+	StateHibernated     = "Hibernated"
+	StateDegraded       = "Degraded"
+	StateAccessDisabled = "AccessDisabled"
+)
+
+// CreateWorkspaceRequest is the body of a workspace creation request
+type CreateWorkspaceRequest struct {
+	Name   string                          `json:"name"`
+	Labels map[string]string               `json:"labels,omitempty"`
+	Spec   workspacev1alpha1.WorkspaceSpec `json:"spec"`
+}
+
+// WorkspaceResponse describes a workspace
+type WorkspaceResponse struct {
+	Name              string             `json:"name"`
+	Namespace         string             `json:"namespace"`
+	DisplayName       string             `json:"displayName"`
+	Image             string             `json:"image,omitempty"`
+	DesiredStatus     string             `json:"desiredStatus"`
+	State             string             `json:"state"`
+	AccessURL         string             `json:"accessURL,omitempty"`
+	CreatedBy         string             `json:"createdBy,omitempty"`
+	CreationTimestamp metav1.Time        `json:"creationTimestamp"`
+	Conditions        []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// WorkspaceListResponse is the response of a workspace list request
+type WorkspaceListResponse struct {
+	Workspaces []WorkspaceResponse `json:"workspaces"`
+}
+
+// AccessResponse gives the URLs a workspace can be reached at. They are only set while the
+// workspace is running with its access enabled.
+type AccessResponse struct {
+	State     string `json:"state"`
+	AccessURL string `json:"accessURL,omitempty"`
+	VanityURL string `json:"vanityURL,omitempty"`
+}
+
+// newWorkspaceResponse describes a workspace; the access URL is only set when the workspace can be reached
+func newWorkspaceResponse(ws *workspacev1alpha1.Workspace) WorkspaceResponse {
+	response := WorkspaceResponse{
+		Name:              ws.Name,
+		Namespace:         ws.Namespace,
+		DisplayName:       ws.Spec.DisplayName,
+		Image:             ws.Spec.Image,
+		DesiredStatus:     desiredStatus(ws),
+		State:             workspaceState(ws),
+		CreatedBy:         ws.Annotations[controller.AnnotationCreatedBy],
+		CreationTimestamp: ws.CreationTimestamp,
+		Conditions:        ws.Status.Conditions,
+	}
+	if response.State == StateRunning {
+		response.AccessURL = ws.Status.AccessURL
+	}
+	return response
+}
+
+// newAccessResponse gives the URLs of a workspace
+func newAccessResponse(ws *workspacev1alpha1.Workspace) AccessResponse {
+	response := AccessResponse{State: workspaceState(ws)}
+	if response.State != StateRunning {
+		return response
+	}
+	response.AccessURL = ws.Status.AccessURL
+	if vanity := ws.Status.VanityURL; vanity != nil && vanity.AccessURL == ws.Status.AccessURL {
+		response.VanityURL = vanity.URL
+	}
+	return response
+}
+
+// desiredStatus returns the desired status of the workspace, Running when unset
+func desiredStatus(ws *workspacev1alpha1.Workspace) string {
+	if ws.Spec.DesiredStatus == "" {
+		return controller.DefaultDesiredStatus
+	}
+	return ws.Spec.DesiredStatus
+}
+
+// workspaceState summarizes the conditions of the workspace
+func workspaceState(ws *workspacev1alpha1.Workspace) string {
+	conditions := ws.Status.Conditions
+	stopping := controller.IsStoppedDesiredStatus(desiredStatus(ws))
+
+	switch {
+	case stopping && meta.IsStatusConditionTrue(conditions, controller.ConditionTypeStopped):
+		if desiredStatus(ws) == controller.DesiredStateHibernated {
+			return StateHibernated
+		}
+		return StateStopped
+	case stopping:
+		return StateStopping
+	case meta.IsStatusConditionTrue(conditions, controller.ConditionTypeDegraded):
+		return StateDegraded
+	case !workspaceutil.IsAccessEnabled(ws):
+		return StateAccessDisabled
+	case meta.IsStatusConditionTrue(conditions, controller.ConditionTypeAvailable):
+		return StateRunning
+	default:
+		return StateStarting
+	}
+}
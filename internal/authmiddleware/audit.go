@@ -0,0 +1,362 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package authmiddleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audit decisions
+const (
+	AuditDecisionAllow = "allow"
+	AuditDecisionDeny  = "deny"
+	AuditDecisionError = "error"
+)
+
+// Audited routes
+const (
+	AuditRouteAuth       = "auth"
+	AuditRouteBearerAuth = "bearer-auth"
+	AuditRouteVerify     = "verify"
+)
+
+// AuditRecord is a structured record of a single workspace access decision
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId"`
+	Route     string    `json:"route"`
+	User      string    `json:"user,omitempty"`
+	Workspace string    `json:"workspace,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Host      string    `json:"host,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Verb      string    `json:"verb"`
+	Decision  string    `json:"decision"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latencyMs"`
+}
+
+// AuditSink receives audit records
+type AuditSink interface {
+	// Write records an audit record. It must not block the request on slow destinations.
+	Write(record *AuditRecord)
+	// Close flushes the pending audit records
+	Close(ctx context.Context) error
+}
+
+// Auditor fans audit records out to sinks
+type Auditor struct {
+	sinks []AuditSink
+}
+
+// NewAuditor creates an Auditor from the audit configuration, or nil when auditing is disabled
+func NewAuditor(config *Config, stdout io.Writer, logger *slog.Logger) *Auditor {
+	var sinks []AuditSink
+	if config.AuditLogEnable {
+		sinks = append(sinks, NewWriterAuditSink(stdout))
+	}
+	if config.AuditWebhookURL != "" {
+		sinks = append(sinks, NewWebhookAuditSink(
+			config.AuditWebhookURL, config.AuditWebhookFlushInterval, logger))
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	return &Auditor{sinks: sinks}
+}
+
+// Write sends the record to every sink
+func (a *Auditor) Write(record *AuditRecord) {
+	for _, sink := range a.sinks {
+		sink.Write(record)
+	}
+}
+
+// Close flushes every sink
+func (a *Auditor) Close(ctx context.Context) error {
+	var errs []error
+	for _, sink := range a.sinks {
+		if err := sink.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to flush audit sinks: %v", errs)
+	}
+	return nil
+}
+
+// WriterAuditSink writes audit records as JSON lines, e.g. to stdout
+type WriterAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterAuditSink creates a WriterAuditSink writing to w
+func NewWriterAuditSink(w io.Writer) *WriterAuditSink {
+	return &WriterAuditSink{encoder: json.NewEncoder(w)}
+}
+
+// auditLogLine wraps audit records written to stdout, so that log pipelines can tell them
+// apart from the server logs
+type auditLogLine struct {
+	Audit *AuditRecord `json:"audit"`
+}
+
+// Write encodes the record as a single JSON line
+func (s *WriterAuditSink) Write(record *AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.encoder.Encode(auditLogLine{Audit: record})
+}
+
+// Close is a no-op, records are written synchronously
+func (s *WriterAuditSink) Close(_ context.Context) error {
+	return nil
+}
+
+// Webhook audit sink batching, so that the sink does not call the webhook on every request
+const (
+	auditWebhookBufferSize   = 10000
+	auditWebhookMaxBatchSize = 500
+	auditWebhookTimeout      = 10 * time.Second
+)
+
+// WebhookAuditSink posts batches of audit records as a JSON array to an HTTP endpoint,
+// such as a log collector or an ingestion endpoint that stores them in S3.
+// Records are buffered in memory; when the buffer is full, new records are dropped.
+type WebhookAuditSink struct {
+	endpoint      string
+	client        *http.Client
+	flushInterval time.Duration
+	logger        *slog.Logger
+	records       chan *AuditRecord
+	done          chan struct{}
+	stopped       chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewWebhookAuditSink creates a WebhookAuditSink and starts its flush loop
+func NewWebhookAuditSink(endpoint string, flushInterval time.Duration, logger *slog.Logger) *WebhookAuditSink {
+	if flushInterval <= 0 {
+		flushInterval = DefaultAuditWebhookFlushInterval
+	}
+	s := &WebhookAuditSink{
+		endpoint:      endpoint,
+		client:        &http.Client{Timeout: auditWebhookTimeout},
+		flushInterval: flushInterval,
+		logger:        logger,
+		records:       make(chan *AuditRecord, auditWebhookBufferSize),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write queues the record for the next batch, dropping it when the buffer is full
+func (s *WebhookAuditSink) Write(record *AuditRecord) {
+	select {
+	case s.records <- record:
+	default:
+		s.logger.Warn("Audit webhook buffer full, dropping audit record", "requestId", record.RequestID)
+	}
+}
+
+// Close flushes the buffered records and stops the flush loop
+func (s *WebhookAuditSink) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.done) })
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("audit webhook flush interrupted: %w", ctx.Err())
+	}
+}
+
+// run posts a batch every flush interval, or as soon as a batch is full
+func (s *WebhookAuditSink) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*AuditRecord, 0, auditWebhookMaxBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.post(batch); err != nil {
+			s.logger.Error("Failed to post audit records", "error", err, "records", len(batch))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case record := <-s.records:
+			batch = append(batch, record)
+			if len(batch) >= auditWebhookMaxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.done:
+			for {
+				select {
+				case record := <-s.records:
+					batch = append(batch, record)
+					if len(batch) >= auditWebhookMaxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// post sends a batch of records to the webhook
+func (s *WebhookAuditSink) post(batch []*AuditRecord) error {
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit records: %w", err)
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// auditContextKey is the request context key of the pending auditEntry
+type auditContextKey struct{}
+
+// auditEntry collects the fields of an audit record only known to the route handlers
+type auditEntry struct {
+	user string
+}
+
+// setAuditUser records the user a route handler identified, if the request is audited
+func setAuditUser(r *http.Request, user string) {
+	if entry, ok := r.Context().Value(auditContextKey{}).(*auditEntry); ok {
+		entry.user = user
+	}
+}
+
+// auditResponseWriter captures the response status code
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// getOrCreateRequestID returns the request ID of the reverse proxy, or generates one
+func getOrCreateRequestID(r *http.Request) string {
+	if requestID := r.Header.Get(HeaderRequestID); requestID != "" {
+		return requestID
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// auditPath strips the query of the forwarded URI, which may carry bearer tokens
+func auditPath(forwardedURI string) string {
+	if parsed, err := url.Parse(forwardedURI); err == nil {
+		return parsed.Path
+	}
+	path, _, _ := strings.Cut(forwardedURI, "?")
+	return path
+}
+
+// auditDecision maps the response status to an audit decision
+func auditDecision(status int) string {
+	switch {
+	case status >= 200 && status < 300:
+		return AuditDecisionAllow
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return AuditDecisionDeny
+	default:
+		return AuditDecisionError
+	}
+}
+
+// withAudit propagates a request ID to the response headers, so the reverse proxy can
+// forward it to the workspace, and emits an audit record once the handler returns
+func (s *Server) withAudit(route string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := getOrCreateRequestID(r)
+		w.Header().Set(HeaderRequestID, requestID)
+		if s.auditor == nil {
+			handler(w, r)
+			return
+		}
+
+		start := time.Now()
+		entry := &auditEntry{}
+		recorder := &auditResponseWriter{ResponseWriter: w}
+		handler(recorder, r.WithContext(context.WithValue(r.Context(), auditContextKey{}, entry)))
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		verb := GetForwardedMethod(r)
+		if verb == "" {
+			verb = r.Method
+		}
+		record := &AuditRecord{
+			Time:      start.UTC(),
+			RequestID: requestID,
+			Route:     route,
+			User:      entry.user,
+			Host:      r.Header.Get(HeaderForwardedHost),
+			Path:      auditPath(r.Header.Get(HeaderForwardedURI)),
+			Verb:      verb,
+			Decision:  auditDecision(status),
+			Status:    status,
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if workspaceInfo, err := s.ExtractWorkspaceInfo(r); err == nil {
+			record.Workspace = workspaceInfo.Name
+			record.Namespace = workspaceInfo.Namespace
+		}
+		s.auditor.Write(record)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package authmiddleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditTestServer creates a Server auditing to the returned buffer
+func newAuditTestServer() (*Server, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	server := &Server{
+		config: &Config{
+			RoutingMode:                 RoutingModePath,
+			WorkspaceNamespacePathRegex: DefaultWorkspaceNamespacePathRegex,
+			WorkspaceNamePathRegex:      DefaultWorkspaceNamePathRegex,
+		},
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		auditor: &Auditor{sinks: []AuditSink{NewWriterAuditSink(out)}},
+	}
+	return server, out
+}
+
+func decodeAuditLine(t *testing.T, out *bytes.Buffer) *AuditRecord {
+	var line auditLogLine
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	require.NotNil(t, line.Audit)
+	return line.Audit
+}
+
+func TestWithAuditRecordsAllowedRequest(t *testing.T) {
+	server, out := newAuditTestServer()
+	handler := server.withAudit(AuditRouteVerify, func(w http.ResponseWriter, r *http.Request) {
+		setAuditUser(r, "alice")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/verify", nil)
+	req.Header.Set(HeaderForwardedURI, testWorkspacePath)
+	req.Header.Set(HeaderForwardedHost, testDomainValue)
+	req.Header.Set(HeaderForwardedMethod, http.MethodPost)
+	req.Header.Set(HeaderRequestID, "req-123")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, "req-123", w.Header().Get(HeaderRequestID))
+	record := decodeAuditLine(t, out)
+	assert.Equal(t, "req-123", record.RequestID)
+	assert.Equal(t, AuditRouteVerify, record.Route)
+	assert.Equal(t, "alice", record.User)
+	assert.Equal(t, "myworkspace", record.Workspace)
+	assert.Equal(t, "default", record.Namespace)
+	assert.Equal(t, testDomainValue, record.Host)
+	assert.Equal(t, testWorkspacePath, record.Path)
+	assert.Equal(t, http.MethodPost, record.Verb)
+	assert.Equal(t, AuditDecisionAllow, record.Decision)
+	assert.Equal(t, http.StatusOK, record.Status)
+	assert.GreaterOrEqual(t, record.LatencyMs, 0.0)
+}
+
+func TestWithAuditRecordsDeniedRequest(t *testing.T) {
+	server, out := newAuditTestServer()
+	handler := server.withAudit(AuditRouteVerify, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/verify", nil)
+	req.Header.Set(HeaderForwardedURI, testWorkspacePath)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	record := decodeAuditLine(t, out)
+	assert.Equal(t, AuditDecisionDeny, record.Decision)
+	assert.Equal(t, http.StatusUnauthorized, record.Status)
+	assert.Empty(t, record.User)
+	assert.Equal(t, http.MethodGet, record.Verb)
+}
+
+func TestWithAuditGeneratesRequestID(t *testing.T) {
+	server, out := newAuditTestServer()
+	handler := server.withAudit(AuditRouteVerify, func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/verify", nil))
+
+	requestID := w.Header().Get(HeaderRequestID)
+	assert.Len(t, requestID, 32)
+	assert.Equal(t, requestID, decodeAuditLine(t, out).RequestID)
+}
+
+func TestWithAuditStripsQueryFromPath(t *testing.T) {
+	server, out := newAuditTestServer()
+	handler := server.withAudit(AuditRouteBearerAuth, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/bearer-auth", nil)
+	req.Header.Set(HeaderForwardedURI, "/workspaces/default/myworkspace/lab?token=secret")
+	handler(httptest.NewRecorder(), req)
+
+	record := decodeAuditLine(t, out)
+	assert.Equal(t, "/workspaces/default/myworkspace/lab", record.Path)
+	assert.NotContains(t, out.String(), "secret")
+	assert.Equal(t, AuditDecisionError, record.Decision)
+}
+
+func TestWithAuditWithoutAuditorSetsRequestID(t *testing.T) {
+	server := &Server{}
+	called := false
+	handler := server.withAudit(AuditRouteVerify, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/verify", nil))
+
+	assert.True(t, called)
+	assert.NotEmpty(t, w.Header().Get(HeaderRequestID))
+}
+
+func TestNewAuditorDisabled(t *testing.T) {
+	assert.Nil(t, NewAuditor(&Config{}, io.Discard, slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+func TestWebhookAuditSinkFlushesOnClose(t *testing.T) {
+	var mu sync.Mutex
+	var received []*AuditRecord
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []*AuditRecord
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		mu.Lock()
+		received = append(received, batch...)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	sink := NewWebhookAuditSink(webhook.URL, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	sink.Write(&AuditRecord{RequestID: "req-1"})
+	sink.Write(&AuditRecord{RequestID: "req-2"})
+	require.NoError(t, sink.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	assert.Equal(t, "req-1", received[0].RequestID)
+	assert.Equal(t, "req-2", received[1].RequestID)
+}
+
+func TestWebhookAuditSinkFlushesOnInterval(t *testing.T) {
+	posted := make(chan []*AuditRecord, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []*AuditRecord
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		posted <- batch
+	}))
+	defer webhook.Close()
+
+	sink := NewWebhookAuditSink(webhook.URL, 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer func() { _ = sink.Close(context.Background()) }()
+	sink.Write(&AuditRecord{RequestID: "req-1"})
+
+	select {
+	case batch := <-posted:
+		require.Len(t, batch, 1)
+		assert.Equal(t, "req-1", batch[0].RequestID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("audit records were not posted")
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/metricsauth"
 )
 
 // Environment variable names
@@ -26,6 +29,12 @@ const (
 	EnvProbeAddr       = "PROBE_ADDR"
 	EnvNamespace       = "NAMESPACE"
 
+	// Metrics endpoint configuration
+	EnvMetricsSecure       = "METRICS_SECURE"
+	EnvMetricsAuth         = "METRICS_AUTH"
+	EnvMetricsCertDir      = "METRICS_CERT_DIR"
+	EnvMetricsClientCAFile = "METRICS_CLIENT_CA_FILE"
+
 	// Auth configuration
 	EnvJwtSigningType    = "JWT_SIGNING_TYPE"
 	EnvJwtIssuer         = "JWT_ISSUER"
@@ -45,13 +54,20 @@ const (
 	EnvWorkspaceNameSubdomainRegex      = "WORKSPACE_NAME_SUBDOMAIN_REGEX"
 
 	// Cookie configuration
-	EnvCookieName     = "COOKIE_NAME"
-	EnvCookieSecure   = "COOKIE_SECURE"
-	EnvCookieDomain   = "COOKIE_DOMAIN"
-	EnvCookiePath     = "COOKIE_PATH"
-	EnvCookieMaxAge   = "COOKIE_MAX_AGE"
-	EnvCookieHttpOnly = "COOKIE_HTTP_ONLY"
-	EnvCookieSameSite = "COOKIE_SAME_SITE"
+	EnvCookieName       = "COOKIE_NAME"
+	EnvCookieSecure     = "COOKIE_SECURE"
+	EnvCookieDomain     = "COOKIE_DOMAIN"
+	EnvCookiePath       = "COOKIE_PATH"
+	EnvCookieMaxAge     = "COOKIE_MAX_AGE"
+	EnvCookieHttpOnly   = "COOKIE_HTTP_ONLY"
+	EnvCookieSameSite   = "COOKIE_SAME_SITE"
+	EnvCookieHostPrefix = "COOKIE_HOST_PREFIX"
+
+	// CSRF configuration
+	EnvCSRFEnable         = "CSRF_ENABLE"
+	EnvCSRFCookieName     = "CSRF_COOKIE_NAME"
+	EnvCSRFHeaderName     = "CSRF_HEADER_NAME"
+	EnvCSRFTrustedOrigins = "CSRF_TRUSTED_ORIGINS"
 
 	// Path configuration
 	EnvPathRegexPattern            = "PATH_REGEX_PATTERN"
@@ -64,6 +80,11 @@ const (
 	EnvOIDCIssuerURL       = "OIDC_ISSUER_URL"
 	EnvOIDCClientID        = "OIDC_CLIENT_ID"
 	EnvOIDCInitTimeoutSecs = "OIDC_INIT_TIMEOUT_SECONDS"
+
+	// Audit configuration
+	EnvAuditLogEnable            = "AUDIT_LOG_ENABLE"
+	EnvAuditWebhookURL           = "AUDIT_WEBHOOK_URL"
+	EnvAuditWebhookFlushInterval = "AUDIT_WEBHOOK_FLUSH_INTERVAL"
 )
 
 // JWT signing types
@@ -80,6 +101,7 @@ const (
 	DefaultShutdownTimeout = 30 * time.Second
 	DefaultMetricsAddr     = ":9090"
 	DefaultProbeAddr       = ":9091"
+	DefaultMetricsSecure   = false
 	// DefaultTrustedProxies is a slice, defined in createDefaultConfig
 
 	// Auth defaults
@@ -96,12 +118,18 @@ const (
 	DefaultEnableBearerAuth  = false
 
 	// Cookie defaults
-	DefaultCookieName     = "workspace_auth"
-	DefaultCookieSecure   = true
-	DefaultCookiePath     = "/"
-	DefaultCookieMaxAge   = 24 * time.Hour
-	DefaultCookieHttpOnly = true
-	DefaultCookieSameSite = SameSiteLax
+	DefaultCookieName       = "workspace_auth"
+	DefaultCookieSecure     = true
+	DefaultCookiePath       = "/"
+	DefaultCookieMaxAge     = 24 * time.Hour
+	DefaultCookieHttpOnly   = true
+	DefaultCookieSameSite   = SameSiteLax
+	DefaultCookieHostPrefix = false
+
+	// CSRF defaults
+	DefaultCSRFEnable     = false
+	DefaultCSRFCookieName = "workspace_csrf"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
 
 	// Path defaults
 	DefaultPathRegexPattern            = `^(/workspaces/[^/]+/[^/]+)(?:/.*)?$`
@@ -117,6 +145,10 @@ const (
 	DefaultOidcUsernamePrefix  = "github:"
 	DefaultOidcGroupsPrefix    = "github:"
 	DefaultOIDCInitTimeoutSecs = 30
+
+	// Audit defaults
+	DefaultAuditLogEnable            = true
+	DefaultAuditWebhookFlushInterval = 5 * time.Second
 )
 
 // Config holds all configuration for the workspaces-auth service
@@ -131,6 +163,12 @@ type Config struct {
 	ProbeAddr       string
 	Namespace       string // Namespace to watch for secrets
 
+	// Metrics endpoint configuration
+	MetricsSecure       bool   // Serve metrics over HTTPS
+	MetricsAuth         string // token, mtls or none; defaults to token over HTTPS and none over HTTP
+	MetricsCertDir      string // Directory of tls.crt and tls.key; self-signed when empty
+	MetricsClientCAFile string // PEM bundle of the CAs signing the client certificates of mtls
+
 	// Auth configuration
 	JWTSigningType    string
 	JWTIssuer         string
@@ -145,13 +183,20 @@ type Config struct {
 	EnableBearerAuth  bool
 
 	// Cookie configuration
-	CookieName     string
-	CookieSecure   bool
-	CookieDomain   string
-	CookiePath     string
-	CookieMaxAge   time.Duration
-	CookieHTTPOnly bool
-	CookieSameSite string
+	CookieName       string
+	CookieSecure     bool
+	CookieDomain     string
+	CookiePath       string
+	CookieMaxAge     time.Duration
+	CookieHTTPOnly   bool
+	CookieSameSite   string
+	CookieHostPrefix bool // Prefix cookie names with __Host-, which binds them to the exact host
+
+	// CSRF configuration
+	CSRFEnable         bool     // Require a CSRF token on state-changing proxied requests
+	CSRFCookieName     string   // Cookie holding the CSRF token, readable by the workspace frontend
+	CSRFHeaderName     string   // Header the workspace frontend echoes the CSRF token in
+	CSRFTrustedOrigins []string // Origins allowed besides the requested host, e.g. https://ide.example.com
 
 	// Path configuration
 	PathRegexPattern            string // Regex pattern to extract app path from full path
@@ -169,6 +214,11 @@ type Config struct {
 	OIDCIssuerURL       string
 	OIDCClientID        string
 	OIDCInitTimeoutSecs int
+
+	// Audit configuration
+	AuditLogEnable            bool          // Write access audit records to stdout
+	AuditWebhookURL           string        // Optional endpoint receiving batches of audit records
+	AuditWebhookFlushInterval time.Duration // Maximum delay before buffered audit records are posted
 }
 
 // NewConfig creates a Config with values from environment variables
@@ -181,6 +231,10 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := applyMetricsConfig(config); err != nil {
+		return nil, err
+	}
+
 	if err := applyJWTConfig(config); err != nil {
 		return nil, err
 	}
@@ -189,6 +243,10 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := applyCSRFConfig(config); err != nil {
+		return nil, err
+	}
+
 	if err := applyPathConfig(config); err != nil {
 		return nil, err
 	}
@@ -197,6 +255,14 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := applyAuditConfig(config); err != nil {
+		return nil, err
+	}
+
+	if err := validateCookieConfig(config); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -211,6 +277,7 @@ func createDefaultConfig() *Config {
 		TrustedProxies:  []string{"127.0.0.1", "::1"}, // Default trusted proxies
 		MetricsAddr:     DefaultMetricsAddr,
 		ProbeAddr:       DefaultProbeAddr,
+		MetricsSecure:   DefaultMetricsSecure,
 
 		// Auth defaults
 		JWTSigningType:    DefaultJwtSigningType,
@@ -226,12 +293,18 @@ func createDefaultConfig() *Config {
 		EnableBearerAuth:  DefaultEnableBearerAuth,
 
 		// Cookie defaults
-		CookieName:     DefaultCookieName,
-		CookieSecure:   DefaultCookieSecure,
-		CookiePath:     DefaultCookiePath,
-		CookieMaxAge:   DefaultCookieMaxAge,
-		CookieHTTPOnly: DefaultCookieHttpOnly,
-		CookieSameSite: DefaultCookieSameSite,
+		CookieName:       DefaultCookieName,
+		CookieSecure:     DefaultCookieSecure,
+		CookiePath:       DefaultCookiePath,
+		CookieMaxAge:     DefaultCookieMaxAge,
+		CookieHTTPOnly:   DefaultCookieHttpOnly,
+		CookieSameSite:   DefaultCookieSameSite,
+		CookieHostPrefix: DefaultCookieHostPrefix,
+
+		// CSRF defaults
+		CSRFEnable:     DefaultCSRFEnable,
+		CSRFCookieName: DefaultCSRFCookieName,
+		CSRFHeaderName: DefaultCSRFHeaderName,
 
 		// Path defaults
 		// This regex extracts application path: /workspaces/<namespace>/<app-name>
@@ -250,6 +323,10 @@ func createDefaultConfig() *Config {
 		OidcUsernamePrefix:  DefaultOidcUsernamePrefix,
 		OidcGroupsPrefix:    DefaultOidcGroupsPrefix,
 		OIDCInitTimeoutSecs: DefaultOIDCInitTimeoutSecs,
+
+		// Audit defaults
+		AuditLogEnable:            DefaultAuditLogEnable,
+		AuditWebhookFlushInterval: DefaultAuditWebhookFlushInterval,
 	}
 }
 
@@ -306,6 +383,42 @@ func applyServerConfig(config *Config) error {
 	return nil
 }
 
+// applyMetricsConfig applies metrics endpoint environment variable overrides
+func applyMetricsConfig(config *Config) error {
+	if metricsSecure := os.Getenv(EnvMetricsSecure); metricsSecure != "" {
+		secure, err := strconv.ParseBool(metricsSecure)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvMetricsSecure, err)
+		}
+		config.MetricsSecure = secure
+	}
+
+	if metricsAuth := os.Getenv(EnvMetricsAuth); metricsAuth != "" {
+		config.MetricsAuth = metricsAuth
+	}
+
+	if certDir := os.Getenv(EnvMetricsCertDir); certDir != "" {
+		config.MetricsCertDir = certDir
+	}
+
+	if clientCAFile := os.Getenv(EnvMetricsClientCAFile); clientCAFile != "" {
+		config.MetricsClientCAFile = clientCAFile
+	}
+
+	return config.MetricsOptions().Validate()
+}
+
+// MetricsOptions returns the options of the metrics server
+func (c *Config) MetricsOptions() metricsauth.Options {
+	return metricsauth.Options{
+		BindAddress:  c.MetricsAddr,
+		Secure:       c.MetricsSecure,
+		Auth:         c.MetricsAuth,
+		ClientCAFile: c.MetricsClientCAFile,
+		CertDir:      c.MetricsCertDir,
+	}
+}
+
 // applyJWTConfig applies JWT-related environment variable overrides
 func applyJWTConfig(config *Config) error {
 	// Set signing type first so we can use it for validation
@@ -450,6 +563,67 @@ func applyCookieConfig(config *Config) error {
 		config.CookieSameSite = cookieSameSite
 	}
 
+	if cookieHostPrefix := os.Getenv(EnvCookieHostPrefix); cookieHostPrefix != "" {
+		hostPrefix, err := strconv.ParseBool(cookieHostPrefix)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvCookieHostPrefix, err)
+		}
+		config.CookieHostPrefix = hostPrefix
+	}
+
+	return nil
+}
+
+// applyCSRFConfig applies CSRF-related environment variable overrides
+func applyCSRFConfig(config *Config) error {
+	if csrfEnable := os.Getenv(EnvCSRFEnable); csrfEnable != "" {
+		enable, err := strconv.ParseBool(csrfEnable)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvCSRFEnable, err)
+		}
+		config.CSRFEnable = enable
+	}
+
+	if csrfCookieName := os.Getenv(EnvCSRFCookieName); csrfCookieName != "" {
+		config.CSRFCookieName = csrfCookieName
+	}
+
+	if csrfHeaderName := os.Getenv(EnvCSRFHeaderName); csrfHeaderName != "" {
+		config.CSRFHeaderName = csrfHeaderName
+	}
+
+	if trustedOrigins := os.Getenv(EnvCSRFTrustedOrigins); trustedOrigins != "" {
+		config.CSRFTrustedOrigins = splitAndTrim(trustedOrigins, ",")
+	}
+
+	return nil
+}
+
+// validateCookieConfig rejects cookie settings that browsers would silently ignore
+func validateCookieConfig(config *Config) error {
+	if strings.EqualFold(config.CookieSameSite, SameSiteNone) && !config.CookieSecure {
+		return fmt.Errorf("%s=%s requires %s=true", EnvCookieSameSite, config.CookieSameSite, EnvCookieSecure)
+	}
+
+	if config.CookieHostPrefix {
+		// __Host- cookies must be secure, host-only and scoped to the whole host
+		if !config.CookieSecure {
+			return fmt.Errorf("%s requires %s=true", EnvCookieHostPrefix, EnvCookieSecure)
+		}
+		if config.CookieDomain != "" {
+			return fmt.Errorf("%s cannot be used with %s", EnvCookieHostPrefix, EnvCookieDomain)
+		}
+		if config.RoutingMode != RoutingModeSubdomain {
+			return fmt.Errorf("%s requires %s=%s, since workspaces sharing a host would share the cookie",
+				EnvCookieHostPrefix, EnvRoutingMode, RoutingModeSubdomain)
+		}
+	}
+
+	// The CSRF cookie always carries the __Host- prefix, which browsers only accept over HTTPS
+	if config.CSRFEnable && !config.CookieSecure {
+		return fmt.Errorf("%s requires %s=true", EnvCSRFEnable, EnvCookieSecure)
+	}
+
 	return nil
 }
 
@@ -489,3 +663,31 @@ func applyOidcConfig(config *Config) error {
 
 	return nil
 }
+
+// applyAuditConfig applies audit-related environment variable overrides
+func applyAuditConfig(config *Config) error {
+	if auditLogEnable := os.Getenv(EnvAuditLogEnable); auditLogEnable != "" {
+		enable, err := strconv.ParseBool(auditLogEnable)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvAuditLogEnable, err)
+		}
+		config.AuditLogEnable = enable
+	}
+
+	if auditWebhookURL := os.Getenv(EnvAuditWebhookURL); auditWebhookURL != "" {
+		config.AuditWebhookURL = auditWebhookURL
+	}
+
+	if flushInterval := os.Getenv(EnvAuditWebhookFlushInterval); flushInterval != "" {
+		d, err := time.ParseDuration(flushInterval)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvAuditWebhookFlushInterval, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", EnvAuditWebhookFlushInterval, d)
+		}
+		config.AuditWebhookFlushInterval = d
+	}
+
+	return nil
+}
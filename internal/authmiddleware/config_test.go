@@ -8,9 +8,12 @@ package authmiddleware
 import (
 	"log/slog"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/metricsauth"
 )
 
 // TestNewConfigDefault verifies that default values are used correctly if not passed
@@ -656,3 +659,322 @@ func TestOIDCVerifierInitConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestApplyAuditConfig tests the audit log environment variable overrides
+func TestApplyAuditConfig(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		env                   map[string]string
+		expectedLogEnable     bool
+		expectedWebhookURL    string
+		expectedFlushInterval time.Duration
+		expectError           bool
+	}{
+		{
+			name:                  "Default values when env vars not set",
+			expectedLogEnable:     DefaultAuditLogEnable,
+			expectedFlushInterval: DefaultAuditWebhookFlushInterval,
+		},
+		{
+			name: "Webhook configured",
+			env: map[string]string{
+				EnvAuditLogEnable:            "false",
+				EnvAuditWebhookURL:           "https://audit.example.com/records",
+				EnvAuditWebhookFlushInterval: "30s",
+			},
+			expectedLogEnable:     false,
+			expectedWebhookURL:    "https://audit.example.com/records",
+			expectedFlushInterval: 30 * time.Second,
+		},
+		{
+			name:        "Invalid log enable",
+			env:         map[string]string{EnvAuditLogEnable: testInvalidValue},
+			expectError: true,
+		},
+		{
+			name:        "Invalid flush interval",
+			env:         map[string]string{EnvAuditWebhookFlushInterval: testInvalidValue},
+			expectError: true,
+		},
+		{
+			name:        "Zero flush interval",
+			env:         map[string]string{EnvAuditWebhookFlushInterval: "0s"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			config := createDefaultConfig()
+			err := applyAuditConfig(config)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyAuditConfig() error = %v", err)
+			}
+			if config.AuditLogEnable != tc.expectedLogEnable {
+				t.Errorf("Expected AuditLogEnable to be %v, got %v", tc.expectedLogEnable, config.AuditLogEnable)
+			}
+			if config.AuditWebhookURL != tc.expectedWebhookURL {
+				t.Errorf("Expected AuditWebhookURL to be %q, got %q", tc.expectedWebhookURL, config.AuditWebhookURL)
+			}
+			if config.AuditWebhookFlushInterval != tc.expectedFlushInterval {
+				t.Errorf("Expected AuditWebhookFlushInterval to be %v, got %v",
+					tc.expectedFlushInterval, config.AuditWebhookFlushInterval)
+			}
+		})
+	}
+}
+
+func TestApplyCSRFConfig(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		env                    map[string]string
+		expectedEnable         bool
+		expectedCookieName     string
+		expectedHeaderName     string
+		expectedTrustedOrigins []string
+		expectError            bool
+	}{
+		{
+			name:               "Default values when env vars not set",
+			expectedEnable:     DefaultCSRFEnable,
+			expectedCookieName: DefaultCSRFCookieName,
+			expectedHeaderName: DefaultCSRFHeaderName,
+		},
+		{
+			name: "CSRF protection configured",
+			env: map[string]string{
+				EnvCSRFEnable:         "true",
+				EnvCSRFCookieName:     "my_csrf",
+				EnvCSRFHeaderName:     "X-My-CSRF",
+				EnvCSRFTrustedOrigins: "https://a.example.com,https://b.example.com",
+			},
+			expectedEnable:         true,
+			expectedCookieName:     "my_csrf",
+			expectedHeaderName:     "X-My-CSRF",
+			expectedTrustedOrigins: []string{"https://a.example.com", "https://b.example.com"},
+		},
+		{
+			name:        "Invalid enable",
+			env:         map[string]string{EnvCSRFEnable: testInvalidValue},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			config := createDefaultConfig()
+			err := applyCSRFConfig(config)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyCSRFConfig() error = %v", err)
+			}
+			if config.CSRFEnable != tc.expectedEnable {
+				t.Errorf("Expected CSRFEnable to be %v, got %v", tc.expectedEnable, config.CSRFEnable)
+			}
+			if config.CSRFCookieName != tc.expectedCookieName {
+				t.Errorf("Expected CSRFCookieName to be %q, got %q", tc.expectedCookieName, config.CSRFCookieName)
+			}
+			if config.CSRFHeaderName != tc.expectedHeaderName {
+				t.Errorf("Expected CSRFHeaderName to be %q, got %q", tc.expectedHeaderName, config.CSRFHeaderName)
+			}
+			if !reflect.DeepEqual(config.CSRFTrustedOrigins, tc.expectedTrustedOrigins) {
+				t.Errorf("Expected CSRFTrustedOrigins to be %v, got %v", tc.expectedTrustedOrigins, config.CSRFTrustedOrigins)
+			}
+		})
+	}
+}
+
+func TestApplyMetricsConfig(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		env                  map[string]string
+		expectedSecure       bool
+		expectedAuth         string
+		expectedCertDir      string
+		expectedClientCAFile string
+		expectError          bool
+	}{
+		{
+			name:           "Default values when env vars not set",
+			expectedSecure: DefaultMetricsSecure,
+		},
+		{
+			name: "Token authentication over HTTPS",
+			env: map[string]string{
+				EnvMetricsSecure:  "true",
+				EnvMetricsCertDir: "/certs",
+			},
+			expectedSecure:  true,
+			expectedCertDir: "/certs",
+		},
+		{
+			name: "Client certificate authentication",
+			env: map[string]string{
+				EnvMetricsSecure:       "true",
+				EnvMetricsAuth:         metricsauth.AuthMTLS,
+				EnvMetricsClientCAFile: "/client-ca/ca.crt",
+			},
+			expectedSecure:       true,
+			expectedAuth:         metricsauth.AuthMTLS,
+			expectedClientCAFile: "/client-ca/ca.crt",
+		},
+		{
+			name:        "Invalid secure",
+			env:         map[string]string{EnvMetricsSecure: testInvalidValue},
+			expectError: true,
+		},
+		{
+			name:        "Authentication over HTTP",
+			env:         map[string]string{EnvMetricsAuth: metricsauth.AuthToken},
+			expectError: true,
+		},
+		{
+			name: "Client certificate authentication without a client CA",
+			env: map[string]string{
+				EnvMetricsSecure: "true",
+				EnvMetricsAuth:   metricsauth.AuthMTLS,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			config := createDefaultConfig()
+			err := applyMetricsConfig(config)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyMetricsConfig() error = %v", err)
+			}
+			if config.MetricsSecure != tc.expectedSecure {
+				t.Errorf("Expected MetricsSecure to be %v, got %v", tc.expectedSecure, config.MetricsSecure)
+			}
+			if config.MetricsAuth != tc.expectedAuth {
+				t.Errorf("Expected MetricsAuth to be %q, got %q", tc.expectedAuth, config.MetricsAuth)
+			}
+			if config.MetricsCertDir != tc.expectedCertDir {
+				t.Errorf("Expected MetricsCertDir to be %q, got %q", tc.expectedCertDir, config.MetricsCertDir)
+			}
+			if config.MetricsClientCAFile != tc.expectedClientCAFile {
+				t.Errorf("Expected MetricsClientCAFile to be %q, got %q", tc.expectedClientCAFile, config.MetricsClientCAFile)
+			}
+		})
+	}
+}
+
+func TestValidateCookieConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(config *Config)
+		expectError bool
+	}{
+		{
+			name:   "Defaults",
+			modify: func(config *Config) {},
+		},
+		{
+			name: "SameSite none without secure",
+			modify: func(config *Config) {
+				config.CookieSameSite = "None"
+				config.CookieSecure = false
+			},
+			expectError: true,
+		},
+		{
+			name: "Host prefix with subdomain routing",
+			modify: func(config *Config) {
+				config.CookieHostPrefix = true
+				config.RoutingMode = RoutingModeSubdomain
+			},
+		},
+		{
+			name: "Host prefix with path routing",
+			modify: func(config *Config) {
+				config.CookieHostPrefix = true
+			},
+			expectError: true,
+		},
+		{
+			name: "Host prefix with cookie domain",
+			modify: func(config *Config) {
+				config.CookieHostPrefix = true
+				config.RoutingMode = RoutingModeSubdomain
+				config.CookieDomain = "example.com"
+			},
+			expectError: true,
+		},
+		{
+			name: "Host prefix without secure",
+			modify: func(config *Config) {
+				config.CookieHostPrefix = true
+				config.RoutingMode = RoutingModeSubdomain
+				config.CookieSecure = false
+			},
+			expectError: true,
+		},
+		{
+			name: "CSRF with path routing",
+			modify: func(config *Config) {
+				config.CSRFEnable = true
+			},
+		},
+		{
+			name: "CSRF without secure",
+			modify: func(config *Config) {
+				config.CSRFEnable = true
+				config.CookieSecure = false
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createDefaultConfig()
+			tc.modify(config)
+
+			err := validateCookieConfig(config)
+
+			if tc.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		})
+	}
+}
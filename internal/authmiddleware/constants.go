@@ -14,11 +14,18 @@ const (
 	HeaderAuthorization                = "Authorization"
 
 	// Headers from reverse proxy
-	HeaderForwardedURI   = "X-Forwarded-Uri"
-	HeaderForwardedHost  = "X-Forwarded-Host"
-	HeaderForwardedProto = "X-Forwarded-Proto"
+	HeaderForwardedURI    = "X-Forwarded-Uri"
+	HeaderForwardedHost   = "X-Forwarded-Host"
+	HeaderForwardedProto  = "X-Forwarded-Proto"
+	HeaderForwardedMethod = "X-Forwarded-Method"
+	HeaderOriginalMethod  = "X-Original-Method"
 
-	// No headers set by middleware yet
+	// Headers from the browser, forwarded by the reverse proxy
+	HeaderOrigin  = "Origin"
+	HeaderReferer = "Referer"
+
+	// Headers set by middleware, reused from the reverse proxy when present
+	HeaderRequestID = "X-Request-Id"
 
 	// Special groups
 	SystemAuthenticatedGroup = "system:authenticated"
@@ -8,6 +8,7 @@ package authmiddleware
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -26,11 +27,17 @@ var (
 	ErrInvalidCookie = errors.New("invalid cookie")
 )
 
+// CookieHostPrefix is the cookie name prefix that makes browsers reject the cookie unless it is
+// secure, host-only and scoped to the whole host, so that sibling subdomains cannot overwrite it
+const CookieHostPrefix = "__Host-"
+
 // CookieHandler exposes CookieManager interface to facilitate unit-testing
 type CookieHandler interface {
 	SetCookie(w http.ResponseWriter, token string, path string, domain string)
 	GetCookie(r *http.Request, path string) (string, error)
 	ClearCookie(w http.ResponseWriter, path string, domain string)
+	SetCSRFCookie(w http.ResponseWriter, token string)
+	GetCSRFCookie(r *http.Request) (string, error)
 }
 
 // CookieManager handles cookie operations
@@ -42,6 +49,8 @@ type CookieManager struct {
 	cookieMaxAge       time.Duration
 	cookieHTTPOnly     bool
 	cookieSameSiteHttp http.SameSite
+	cookieHostPrefix   bool
+	csrfCookieName     string // __Host- prefixed, empty when CSRF protection is disabled
 	pathRegexPattern   string // Regex pattern for path-based cookie naming
 }
 
@@ -60,6 +69,11 @@ func NewCookieManager(cfg *Config) (*CookieManager, error) {
 		return nil, fmt.Errorf("invalid same site value: %s", cfg.CookieSameSite)
 	}
 
+	csrfCookieName := ""
+	if cfg.CSRFEnable {
+		csrfCookieName = CookieHostPrefix + strings.TrimPrefix(cfg.CSRFCookieName, CookieHostPrefix)
+	}
+
 	return &CookieManager{
 		cookieName:         cfg.CookieName,
 		cookieSecure:       cfg.CookieSecure,
@@ -68,48 +82,84 @@ func NewCookieManager(cfg *Config) (*CookieManager, error) {
 		cookieMaxAge:       cfg.CookieMaxAge,
 		cookieHTTPOnly:     cfg.CookieHTTPOnly,
 		cookieSameSiteHttp: sameSiteHttp,
+		cookieHostPrefix:   cfg.CookieHostPrefix,
+		csrfCookieName:     csrfCookieName,
 		pathRegexPattern:   cfg.PathRegexPattern,
 	}, nil
 }
 
 // SetCookie sets an auth cookie with the given token
 func (m *CookieManager) SetCookie(w http.ResponseWriter, token string, path string, domain string) {
-	cookieName := m.cookieName
-	cookiePath := m.cookiePath
-
-	// Extract the app path for cookie path only
-	if path != "" {
-		// Extract the app path using the regex pattern
-		appPath := path
-		if m.pathRegexPattern != "" {
-			appPath = ExtractAppPath(path, m.pathRegexPattern)
-		}
+	http.SetCookie(w, m.newCookie(m.cookieName, token, path, domain, m.cookieHTTPOnly))
+}
 
-		// Use the extracted app path for cookie path if it's not empty
-		if appPath != "" && appPath != "/" {
-			cookiePath = appPath
-		}
+// GetCookie retrieves the auth token from the cookie
+func (m *CookieManager) GetCookie(r *http.Request, path string) (string, error) {
+	return m.getCookieValue(r, m.cookieName)
+}
+
+// ClearCookie removes the auth cookie. The CSRF cookie is shared by the workspaces of the host
+// and grants no access on its own, so it is kept.
+func (m *CookieManager) ClearCookie(w http.ResponseWriter, path string, domain string) {
+	cookie := m.newCookie(m.cookieName, "", path, domain, m.cookieHTTPOnly)
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+// SetCSRFCookie sets the CSRF cookie with the given token. Unlike the auth cookie, the CSRF cookie
+// is readable by JavaScript, so that the workspace frontend can echo the token in a header.
+// It always carries the __Host- prefix: a sibling subdomain could otherwise plant a token of its
+// choosing, which it could then echo in the header of a forged request.
+func (m *CookieManager) SetCSRFCookie(w http.ResponseWriter, token string) {
+	if m.csrfCookieName == "" {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.csrfCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(m.cookieMaxAge.Seconds()),
+		Secure:   true,
+		SameSite: m.cookieSameSiteHttp,
+	})
+}
+
+// GetCSRFCookie retrieves the CSRF token from the cookie
+func (m *CookieManager) GetCSRFCookie(r *http.Request) (string, error) {
+	if m.csrfCookieName == "" {
+		return "", ErrNoCookie
+	}
+	cookie, err := r.Cookie(m.csrfCookieName)
+	if err != nil {
+		return "", ErrNoCookie
 	}
+	return cookie.Value, nil
+}
 
+// newCookie builds a cookie scoped to the app path and domain of the request, with the
+// name prefix, path and domain required by the __Host- prefix when it is enabled
+func (m *CookieManager) newCookie(name, value, path, domain string, httpOnly bool) *http.Cookie {
 	cookie := &http.Cookie{
-		Name:     cookieName,
-		Value:    token,
-		Path:     cookiePath,
-		Domain:   domain,
+		Name:     m.prefixedName(name),
+		Value:    value,
+		Path:     m.scopePath(path),
+		Domain:   m.scopeDomain(domain),
 		MaxAge:   int(m.cookieMaxAge.Seconds()),
-		HttpOnly: m.cookieHTTPOnly,
+		HttpOnly: httpOnly,
 		Secure:   m.cookieSecure,
 		SameSite: m.cookieSameSiteHttp,
 	}
-
-	http.SetCookie(w, cookie)
+	if m.cookieHostPrefix {
+		cookie.Path = "/"
+		cookie.Domain = ""
+		cookie.Secure = true
+	}
+	return cookie
 }
 
-// GetCookie retrieves the auth token from the cookie
-func (m *CookieManager) GetCookie(r *http.Request, path string) (string, error) {
-	cookieName := m.cookieName
-
-	cookie, err := r.Cookie(cookieName)
+// getCookieValue returns the value of the named cookie of the request
+func (m *CookieManager) getCookieValue(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(m.prefixedName(name))
 	if err != nil {
 		if err == http.ErrNoCookie {
 			return "", ErrNoCookie
@@ -120,35 +170,46 @@ func (m *CookieManager) GetCookie(r *http.Request, path string) (string, error)
 	return cookie.Value, nil
 }
 
-// ClearCookie removes the auth cookie
-func (m *CookieManager) ClearCookie(w http.ResponseWriter, path string, domain string) {
-	cookieName := m.cookieName
-	cookiePath := m.cookiePath
-
-	// Extract the app path for cookie path only
-	if path != "" {
-		// Extract the app path using the regex pattern
-		appPath := path
-		if m.pathRegexPattern != "" {
-			appPath = ExtractAppPath(path, m.pathRegexPattern)
-		}
+// prefixedName returns the cookie name, with the __Host- prefix when enabled
+func (m *CookieManager) prefixedName(name string) string {
+	if m.cookieHostPrefix {
+		return CookieHostPrefix + name
+	}
+	return name
+}
 
-		// Use the extracted app path for cookie path if it's not empty
-		if appPath != "" && appPath != "/" {
-			cookiePath = appPath
-		}
+// scopePath returns the cookie path for a request path: the app path extracted with the
+// path regex, so that each workspace gets its own cookie, or the configured cookie path
+func (m *CookieManager) scopePath(path string) string {
+	if path == "" {
+		return m.cookiePath
 	}
 
-	cookie := &http.Cookie{
-		Name:     cookieName,
-		Value:    "",
-		Path:     cookiePath,
-		Domain:   domain,
-		MaxAge:   -1,
-		HttpOnly: m.cookieHTTPOnly,
-		Secure:   m.cookieSecure,
-		SameSite: m.cookieSameSiteHttp,
+	appPath := path
+	if m.pathRegexPattern != "" {
+		appPath = ExtractAppPath(path, m.pathRegexPattern)
 	}
 
-	http.SetCookie(w, cookie)
+	if appPath != "" && appPath != "/" {
+		return appPath
+	}
+	return m.cookiePath
+}
+
+// scopeDomain returns the cookie domain for a request host: the configured cookie domain when
+// the host belongs to it, which shares the cookie with its subdomains, or the request host
+func (m *CookieManager) scopeDomain(host string) string {
+	if m.cookieDomain == "" || host == "" {
+		return host
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	domain := strings.TrimPrefix(m.cookieDomain, ".")
+	if strings.EqualFold(hostname, domain) || strings.HasSuffix(strings.ToLower(hostname), "."+strings.ToLower(domain)) {
+		return m.cookieDomain
+	}
+	return host
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -232,7 +233,6 @@ func TestSetCookieNameAndPath(t *testing.T) {
 	config := &Config{
 		CookieName:       testAuthCookieName,
 		CookieSecure:     true,
-		CookieDomain:     testDomainValue,
 		CookiePath:       "/",
 		CookieMaxAge:     1 * time.Hour,
 		CookieHTTPOnly:   true,
@@ -448,7 +448,6 @@ func TestClearCookie(t *testing.T) {
 	config := &Config{
 		CookieName:       testAuthCookieName,
 		CookieSecure:     true,
-		CookieDomain:     testDomainValue,
 		CookiePath:       "/",
 		CookieMaxAge:     1 * time.Hour,
 		CookieHTTPOnly:   true,
@@ -541,3 +540,191 @@ func TestClearCookie(t *testing.T) {
 		})
 	}
 }
+
+// TestCookieDomainScoping verifies that the configured cookie domain only applies to hosts within it
+func TestCookieDomainScoping(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cookieDomain   string
+		host           string
+		expectedDomain string
+	}{
+		{
+			name:           "No cookie domain keeps the request host",
+			host:           "app1-ns1.workspaces.example.com",
+			expectedDomain: "app1-ns1.workspaces.example.com",
+		},
+		{
+			name:           "Subdomain of the cookie domain",
+			cookieDomain:   ".workspaces.example.com",
+			host:           "app1-ns1.workspaces.example.com",
+			expectedDomain: ".workspaces.example.com",
+		},
+		{
+			name:           "Cookie domain itself, with a port",
+			cookieDomain:   "workspaces.example.com",
+			host:           "workspaces.example.com:8443",
+			expectedDomain: "workspaces.example.com",
+		},
+		{
+			name:           "Host outside the cookie domain",
+			cookieDomain:   "workspaces.example.com",
+			host:           "evilworkspaces.example.com",
+			expectedDomain: "evilworkspaces.example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager, err := NewCookieManager(&Config{
+				CookieName:     testAuthCookieName,
+				CookieSecure:   true,
+				CookieDomain:   tc.cookieDomain,
+				CookiePath:     "/",
+				CookieMaxAge:   1 * time.Hour,
+				CookieHTTPOnly: true,
+				CookieSameSite: SameSiteLax,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create cookie manager: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			manager.SetCookie(w, "test-token", "/", tc.host)
+
+			cookies := w.Result().Cookies()
+			if len(cookies) != 1 {
+				t.Fatalf("Expected 1 cookie but got %d", len(cookies))
+			}
+			if cookies[0].Domain != strings.TrimPrefix(tc.expectedDomain, ".") {
+				t.Errorf("Expected cookie domain %q but got %q", tc.expectedDomain, cookies[0].Domain)
+			}
+		})
+	}
+}
+
+// TestCookieHostPrefix verifies that __Host- cookies are secure, host-only and scoped to the whole host
+func TestCookieHostPrefix(t *testing.T) {
+	manager, err := NewCookieManager(&Config{
+		CookieName:       testAuthCookieName,
+		CookiePath:       "/",
+		CookieMaxAge:     1 * time.Hour,
+		CookieHTTPOnly:   true,
+		CookieSameSite:   SameSiteLax,
+		CookieHostPrefix: true,
+		PathRegexPattern: DefaultPathRegexPattern,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cookie manager: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	manager.SetCookie(w, "test-token", testPathNamespace1Lab, "app1-ns1.workspaces.example.com")
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie but got %d", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != CookieHostPrefix+testAuthCookieName {
+		t.Errorf("Expected cookie name %q but got %q", CookieHostPrefix+testAuthCookieName, cookie.Name)
+	}
+	if cookie.Path != "/" || cookie.Domain != "" || !cookie.Secure {
+		t.Errorf("Expected a secure host-only cookie on /, got path %q, domain %q, secure %v",
+			cookie.Path, cookie.Domain, cookie.Secure)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	token, err := manager.GetCookie(req, testPathNamespace1Lab)
+	if err != nil || token != "test-token" {
+		t.Errorf("Expected to read back the prefixed cookie, got %q, %v", token, err)
+	}
+}
+
+// TestCSRFCookie verifies that the CSRF cookie is readable by JavaScript, bound to the host with
+// the __Host- prefix, and only set when enabled
+func TestCSRFCookie(t *testing.T) {
+	config := &Config{
+		CookieName:       testAuthCookieName,
+		CookieSecure:     true,
+		CookiePath:       "/",
+		CookieMaxAge:     1 * time.Hour,
+		CookieHTTPOnly:   true,
+		CookieSameSite:   SameSiteLax,
+		CSRFCookieName:   DefaultCSRFCookieName,
+		PathRegexPattern: DefaultPathRegexPattern,
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		manager, err := NewCookieManager(config)
+		if err != nil {
+			t.Fatalf("Failed to create cookie manager: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		manager.SetCSRFCookie(w, "csrf-token")
+		if len(w.Result().Cookies()) != 0 {
+			t.Errorf("Expected no CSRF cookie when CSRF protection is disabled")
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		enabledConfig := *config
+		enabledConfig.CSRFEnable = true
+		manager, err := NewCookieManager(&enabledConfig)
+		if err != nil {
+			t.Fatalf("Failed to create cookie manager: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		manager.SetCSRFCookie(w, "csrf-token")
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("Expected 1 cookie but got %d", len(cookies))
+		}
+		cookie := cookies[0]
+		if cookie.Name != CookieHostPrefix+DefaultCSRFCookieName || cookie.HttpOnly {
+			t.Errorf("Unexpected CSRF cookie: name %q, httpOnly %v", cookie.Name, cookie.HttpOnly)
+		}
+		if cookie.Path != "/" || cookie.Domain != "" || !cookie.Secure {
+			t.Errorf("CSRF cookie does not meet the __Host- requirements: path %q, domain %q, secure %v",
+				cookie.Path, cookie.Domain, cookie.Secure)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		token, err := manager.GetCSRFCookie(req)
+		if err != nil || token != "csrf-token" {
+			t.Errorf("Expected to read back the CSRF cookie, got %q, %v", token, err)
+		}
+
+		unprefixed := httptest.NewRequest(http.MethodGet, "/", nil)
+		unprefixed.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: "planted"})
+		if _, err := manager.GetCSRFCookie(unprefixed); err == nil {
+			t.Errorf("Expected a CSRF cookie without the __Host- prefix to be ignored")
+		}
+
+		clearW := httptest.NewRecorder()
+		manager.ClearCookie(clearW, testPathNamespace1, testDomainValue)
+		if len(clearW.Result().Cookies()) != 1 {
+			t.Errorf("Expected ClearCookie to only clear the auth cookie")
+		}
+	})
+
+	t.Run("Prefixed name", func(t *testing.T) {
+		prefixedConfig := *config
+		prefixedConfig.CSRFEnable = true
+		prefixedConfig.CSRFCookieName = CookieHostPrefix + DefaultCSRFCookieName
+		manager, err := NewCookieManager(&prefixedConfig)
+		if err != nil {
+			t.Fatalf("Failed to create cookie manager: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		manager.SetCSRFCookie(w, "csrf-token")
+		if name := w.Result().Cookies()[0].Name; name != CookieHostPrefix+DefaultCSRFCookieName {
+			t.Errorf("Expected the prefix not to be repeated, got %q", name)
+		}
+	})
+}
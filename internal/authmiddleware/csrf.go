@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package authmiddleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CSRF errors
+var (
+	ErrCSRFMethodUnknown    = errors.New("request method is unknown")
+	ErrCSRFOriginNotAllowed = errors.New("request origin is not allowed")
+	ErrCSRFTokenMissing     = errors.New("CSRF token missing")
+	ErrCSRFTokenMismatch    = errors.New("CSRF token does not match")
+)
+
+// csrfSafeMethods are the methods that must not change state, which CSRF protection lets through
+var csrfSafeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace}
+
+// GenerateCSRFToken returns a random CSRF token
+func GenerateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate CSRF token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validateCSRF checks a proxied request against cross-site request forgery, using the
+// double-submit pattern: state-changing requests must echo the token of the CSRF cookie in
+// the CSRF header, which a cross-site page cannot read. Their Origin, when the browser sends
+// it, must also be the requested host or a trusted origin.
+// Requests whose method the reverse proxy does not forward are rejected, since they may change state.
+func (s *Server) validateCSRF(r *http.Request) error {
+	method := GetForwardedMethod(r)
+	if method == "" {
+		return fmt.Errorf("%w: missing %s or %s header", ErrCSRFMethodUnknown, HeaderForwardedMethod, HeaderOriginalMethod)
+	}
+	if slices.Contains(csrfSafeMethods, method) {
+		return nil
+	}
+
+	if origin := requestOrigin(r); origin != "" && !s.isTrustedOrigin(r, origin) {
+		return fmt.Errorf("%w: %s", ErrCSRFOriginNotAllowed, origin)
+	}
+
+	headerToken := r.Header.Get(s.config.CSRFHeaderName)
+	cookieToken, err := s.cookieManager.GetCSRFCookie(r)
+	if headerToken == "" || err != nil || cookieToken == "" {
+		return ErrCSRFTokenMissing
+	}
+	if subtle.ConstantTimeCompare([]byte(headerToken), []byte(cookieToken)) != 1 {
+		return ErrCSRFTokenMismatch
+	}
+	return nil
+}
+
+// issueCSRFCookie sets a new CSRF cookie along with a session cookie
+func (s *Server) issueCSRFCookie(w http.ResponseWriter) {
+	if !s.config.CSRFEnable {
+		return
+	}
+	token, err := GenerateCSRFToken()
+	if err != nil {
+		s.logger.Error("Failed to issue CSRF cookie", "error", err)
+		return
+	}
+	s.cookieManager.SetCSRFCookie(w, token)
+}
+
+// ensureCSRFCookie issues a CSRF cookie for sessions that do not have one yet,
+// e.g. sessions established before CSRF protection was enabled
+func (s *Server) ensureCSRFCookie(w http.ResponseWriter, r *http.Request) {
+	if token, err := s.cookieManager.GetCSRFCookie(r); err == nil && token != "" {
+		return
+	}
+	s.issueCSRFCookie(w)
+}
+
+// requestOrigin returns the origin of the proxied request from its Origin header,
+// or from its Referer header when the browser omits Origin
+func requestOrigin(r *http.Request) string {
+	// An opaque "null" origin (sandboxed frames, privacy-sensitive redirects) is never trusted
+	if origin := r.Header.Get(HeaderOrigin); origin != "" {
+		return origin
+	}
+	referer, err := url.Parse(r.Header.Get(HeaderReferer))
+	if err != nil || referer.Scheme == "" || referer.Host == "" {
+		return ""
+	}
+	return referer.Scheme + "://" + referer.Host
+}
+
+// isTrustedOrigin returns true for the origin of the requested host and the configured trusted origins
+func (s *Server) isTrustedOrigin(r *http.Request, origin string) bool {
+	proto := r.Header.Get(HeaderForwardedProto)
+	if proto == "" {
+		proto = "https"
+	}
+	if strings.EqualFold(origin, proto+"://"+r.Header.Get(HeaderForwardedHost)) {
+		return true
+	}
+	return slices.ContainsFunc(s.config.CSRFTrustedOrigins, func(trusted string) bool {
+		return strings.EqualFold(origin, strings.TrimSuffix(trusted, "/"))
+	})
+}
@@ -0,0 +1,220 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package authmiddleware
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/jwt"
+)
+
+const testCSRFToken = "csrf-token"
+
+func newCSRFTestServer(cookieHandler *MockCookieHandler, jwtHandler *MockJWTHandler) *Server {
+	return &Server{
+		config: &Config{
+			PathRegexPattern:   DefaultPathRegexPattern,
+			CSRFEnable:         true,
+			CSRFHeaderName:     DefaultCSRFHeaderName,
+			CSRFTrustedOrigins: []string{"https://ide.example.com/"},
+		},
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cookieManager: cookieHandler,
+		jwtManager:    jwtHandler,
+	}
+}
+
+func newCSRFTestRequest(method string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/verify", nil)
+	req.Header.Set(HeaderForwardedURI, testPathNamespace1Lab)
+	req.Header.Set(HeaderForwardedHost, testDomainValue)
+	req.Header.Set(HeaderForwardedProto, "https")
+	if method != "" {
+		req.Header.Set(HeaderForwardedMethod, method)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req
+}
+
+func TestGenerateCSRFToken(t *testing.T) {
+	first, err := GenerateCSRFToken()
+	require.NoError(t, err)
+	second, err := GenerateCSRFToken()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestValidateCSRF(t *testing.T) {
+	testCases := []struct {
+		name        string
+		method      string
+		headers     map[string]string
+		cookieToken string
+		expectedErr error
+	}{
+		{
+			name:   "Safe method needs no token",
+			method: http.MethodGet,
+		},
+		{
+			name:    "Safe method forwarded by nginx needs no token",
+			headers: map[string]string{HeaderOriginalMethod: http.MethodGet},
+		},
+		{
+			name:        "Unknown method is rejected",
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFMethodUnknown,
+		},
+		{
+			name:        "State-changing method forwarded by nginx needs the token",
+			headers:     map[string]string{HeaderOriginalMethod: http.MethodPost},
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFTokenMissing,
+		},
+		{
+			name:        "Matching token",
+			method:      http.MethodPost,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken},
+			cookieToken: testCSRFToken,
+		},
+		{
+			name:        "Matching token from the requested origin",
+			method:      http.MethodDelete,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken, HeaderOrigin: "https://" + testDomainValue},
+			cookieToken: testCSRFToken,
+		},
+		{
+			name:        "Matching token from a trusted origin",
+			method:      http.MethodPut,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken, HeaderOrigin: "https://ide.example.com"},
+			cookieToken: testCSRFToken,
+		},
+		{
+			name:        "Missing header token",
+			method:      http.MethodPost,
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFTokenMissing,
+		},
+		{
+			name:        "Missing cookie token",
+			method:      http.MethodPost,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken},
+			expectedErr: ErrCSRFTokenMissing,
+		},
+		{
+			name:        "Mismatched token",
+			method:      http.MethodPatch,
+			headers:     map[string]string{DefaultCSRFHeaderName: "forged"},
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFTokenMismatch,
+		},
+		{
+			name:        "Cross-site origin",
+			method:      http.MethodPost,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken, HeaderOrigin: "https://evil.example.org"},
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFOriginNotAllowed,
+		},
+		{
+			name:        "Cross-site referer without origin",
+			method:      http.MethodPost,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken, HeaderReferer: "https://evil.example.org/page"},
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFOriginNotAllowed,
+		},
+		{
+			name:        "Opaque origin",
+			method:      http.MethodPost,
+			headers:     map[string]string{DefaultCSRFHeaderName: testCSRFToken, HeaderOrigin: "null"},
+			cookieToken: testCSRFToken,
+			expectedErr: ErrCSRFOriginNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newCSRFTestServer(&MockCookieHandler{
+				GetCSRFCookieFunc: func(r *http.Request) (string, error) {
+					if tc.cookieToken == "" {
+						return "", ErrNoCookie
+					}
+					return tc.cookieToken, nil
+				},
+			}, nil)
+
+			err := server.validateCSRF(newCSRFTestRequest(tc.method, tc.headers))
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr), "expected %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func newCSRFTestJWTHandler() *MockJWTHandler {
+	return &MockJWTHandler{
+		ValidateTokenFunc: func(tokenString string) (*jwt.Claims, error) {
+			return &jwt.Claims{
+				User:      testUserString,
+				Path:      testPathNamespace1,
+				Domain:    testDomainValue,
+				TokenType: jwt.TokenTypeSession,
+			}, nil
+		},
+	}
+}
+
+func TestHandleVerify_CSRFTokenMismatch_Returns403(t *testing.T) {
+	server := newCSRFTestServer(&MockCookieHandler{
+		GetCSRFCookieFunc: func(r *http.Request) (string, error) { return testCSRFToken, nil },
+	}, newCSRFTestJWTHandler())
+	w := httptest.NewRecorder()
+
+	server.handleVerify(w, newCSRFTestRequest(http.MethodPost, map[string]string{DefaultCSRFHeaderName: "forged"}))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "CSRF validation failed")
+}
+
+func TestHandleVerify_IssuesMissingCSRFCookie(t *testing.T) {
+	var issuedToken string
+	server := newCSRFTestServer(&MockCookieHandler{
+		SetCSRFCookieFunc: func(w http.ResponseWriter, token string) {
+			issuedToken = token
+		},
+	}, newCSRFTestJWTHandler())
+	w := httptest.NewRecorder()
+
+	server.handleVerify(w, newCSRFTestRequest(http.MethodGet, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, issuedToken)
+}
+
+func TestHandleVerify_UnknownMethod_Returns403(t *testing.T) {
+	server := newCSRFTestServer(&MockCookieHandler{
+		GetCSRFCookieFunc: func(r *http.Request) (string, error) { return testCSRFToken, nil },
+	}, newCSRFTestJWTHandler())
+	w := httptest.NewRecorder()
+
+	server.handleVerify(w, newCSRFTestRequest("", map[string]string{DefaultCSRFHeaderName: testCSRFToken}))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -30,6 +30,17 @@ func GetForwardedURI(r *http.Request) (string, error) {
 	return uri, nil
 }
 
+// GetForwardedMethod returns the method of the proxied request, in upper case, from the
+// X-Forwarded-Method header (Traefik) or the X-Original-Method header (nginx), or "" when
+// the reverse proxy forwards neither
+func GetForwardedMethod(r *http.Request) string {
+	method := r.Header.Get(HeaderForwardedMethod)
+	if method == "" {
+		method = r.Header.Get(HeaderOriginalMethod)
+	}
+	return strings.ToUpper(method)
+}
+
 // ExtractSubdomain extracts the subdomain part from a host (before first dot)
 func ExtractSubdomain(host string) string {
 	parts := strings.Split(host, ".")
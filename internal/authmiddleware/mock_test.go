@@ -88,9 +88,11 @@ func (m *MockJWTHandler) ShouldRefreshToken(claims *jwt.Claims) bool {
 
 // MockCookieHandler implements the CookieHandler interface for testing
 type MockCookieHandler struct {
-	SetCookieFunc   func(w http.ResponseWriter, token string, path string, domain string)
-	GetCookieFunc   func(r *http.Request, path string) (string, error)
-	ClearCookieFunc func(w http.ResponseWriter, path string, domain string)
+	SetCookieFunc     func(w http.ResponseWriter, token string, path string, domain string)
+	GetCookieFunc     func(r *http.Request, path string) (string, error)
+	ClearCookieFunc   func(w http.ResponseWriter, path string, domain string)
+	SetCSRFCookieFunc func(w http.ResponseWriter, token string)
+	GetCSRFCookieFunc func(r *http.Request) (string, error)
 }
 
 // Ensure MockCookieHandler implements the CookieHandler interface
@@ -118,6 +120,21 @@ func (m *MockCookieHandler) ClearCookie(w http.ResponseWriter, path string, doma
 	}
 }
 
+// SetCSRFCookie calls the mock implementation
+func (m *MockCookieHandler) SetCSRFCookie(w http.ResponseWriter, token string) {
+	if m.SetCSRFCookieFunc != nil {
+		m.SetCSRFCookieFunc(w, token)
+	}
+}
+
+// GetCSRFCookie calls the mock implementation
+func (m *MockCookieHandler) GetCSRFCookie(r *http.Request) (string, error) {
+	if m.GetCSRFCookieFunc != nil {
+		return m.GetCSRFCookieFunc(r)
+	}
+	return "", ErrNoCookie
+}
+
 // RequestRecord represents a recorded HTTP request
 type RequestRecord struct {
 	Method  string
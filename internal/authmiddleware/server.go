@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
 
 	"github.com/jupyter-infra/jupyter-k8s/internal/jwt"
 	"k8s.io/client-go/kubernetes"
@@ -25,6 +26,7 @@ type Server struct {
 	httpServer    *http.Server
 	restClient    rest.Interface
 	oidcVerifier  OIDCVerifierInterface
+	auditor       *Auditor
 }
 
 // NewServer creates a new server instance
@@ -68,6 +70,7 @@ func NewServer(config *Config, jwtManager jwt.Handler, cookieManager CookieHandl
 		logger:        logger,
 		restClient:    restClient,
 		oidcVerifier:  oidcVerifier,
+		auditor:       NewAuditor(config, os.Stdout, logger),
 	}
 }
 
@@ -91,12 +94,12 @@ func (s *Server) Start() error {
 
 	// Register routes
 	if s.config.EnableOAuth {
-		router.HandleFunc("/auth", s.handleAuth)
+		router.HandleFunc("/auth", s.withAudit(AuditRouteAuth, s.handleAuth))
 	}
 	if s.config.EnableBearerAuth {
-		router.HandleFunc("/bearer-auth", s.handleBearerAuth)
+		router.HandleFunc("/bearer-auth", s.withAudit(AuditRouteBearerAuth, s.handleBearerAuth))
 	}
-	router.HandleFunc("/verify", s.handleVerify)
+	router.HandleFunc("/verify", s.withAudit(AuditRouteVerify, s.handleVerify))
 	router.HandleFunc("/health", s.handleHealth)
 
 	// Configure HTTP server
@@ -136,6 +139,13 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	// Flush the audit records of the drained requests
+	if s.auditor != nil {
+		if err := s.auditor.Close(shutdownCtx); err != nil {
+			s.logger.Error("Audit flush error", "error", err)
+		}
+	}
+
 	s.logger.Info("HTTP server shutdown complete")
 	return nil
 }
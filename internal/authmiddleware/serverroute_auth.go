@@ -86,6 +86,7 @@ func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
 	k8sUID := oidcClaims.Subject
 	k8sUsername := GetOIDCUsernameFromToken(s.config, oidcClaims)
 	k8sGroups := GetOIDCGroupsFromToken(s.config, oidcClaims)
+	setAuditUser(r, k8sUsername)
 
 	// Verify preferred username in header if available
 	if headerPreferredUsername != "" && k8sUsername != headerPreferredUsername {
@@ -181,6 +182,7 @@ func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
 
 	// Set cookie using appPath and same domain as JWT token
 	s.cookieManager.SetCookie(w, jwtToken, appPath, host)
+	s.issueCSRFCookie(w)
 
 	// Create empty response
 	response := map[string]string{}
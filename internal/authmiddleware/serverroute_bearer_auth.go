@@ -103,6 +103,7 @@ func (s *Server) handleBearerAuth(w http.ResponseWriter, r *http.Request) {
 	uid := reviewStatus.User.UID
 	groups := reviewStatus.User.Groups
 	extra := reviewStatus.User.Extra
+	setAuditUser(r, user)
 
 	// Generate new long-term session token
 	sessionToken, err := s.jwtManager.GenerateToken(
@@ -115,6 +116,7 @@ func (s *Server) handleBearerAuth(w http.ResponseWriter, r *http.Request) {
 
 	// Set session cookie using appPath and same domain as JWT token
 	s.cookieManager.SetCookie(w, sessionToken, appPath, host)
+	s.issueCSRFCookie(w)
 
 	// Log successful token exchange
 	s.logger.Info("Token exchange successful",
@@ -47,6 +47,8 @@ func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setAuditUser(r, claims.User)
+
 	// Validate token type - verify should only accept session tokens
 	if claims.TokenType != jwt.TokenTypeSession {
 		s.logger.Info("Invalid token type for verify", "expected", jwt.TokenTypeSession, "actual", claims.TokenType)
@@ -70,6 +72,16 @@ func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The session cookie alone does not prove the request comes from the workspace frontend
+	if s.config.CSRFEnable {
+		if err := s.validateCSRF(r); err != nil {
+			s.logger.Warn("CSRF validation failed", "error", err, "user", claims.User, "path", requestPath)
+			http.Error(w, "CSRF validation failed", http.StatusForbidden)
+			return
+		}
+		s.ensureCSRFCookie(w, r)
+	}
+
 	// Check if token needs to be refreshed
 	if s.jwtManager.ShouldRefreshToken(claims) {
 		s.logger.Debug("Refreshing token", "user", claims.User, "path", claims.Path)
@@ -33,3 +33,10 @@ var (
 	// ContextKeyRegion is the context key for the AWS region used in SSM registration.
 	ContextKeyRegion = plugin.ContextEntry{Key: "region"}
 )
+
+// Pod event context keys with a default value.
+var (
+	// ContextKeySsmRegistrationFile is the context key for the registration file the SSM agent
+	// writes in the sidecar once registered, holding the ID of the managed node.
+	ContextKeySsmRegistrationFile = plugin.ContextEntry{Key: "ssmRegistrationFile", Default: "/var/lib/amazon/ssm/registration"}
+)
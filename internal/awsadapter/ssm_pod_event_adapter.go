@@ -32,6 +32,13 @@ type RegistrationState struct {
 	WorkspaceRestartCount int32     `json:"workspaceRestartCount"`
 	SetupInProgress       bool      `json:"setupInProgress,omitempty"`
 	SetupStartedAt        time.Time `json:"setupStartedAt,omitempty"`
+	// ManagedInstanceID is the ID of the SSM managed node the sidecar registered as
+	ManagedInstanceID string `json:"managedInstanceId,omitempty"`
+}
+
+// ssmAgentRegistration is the subset of the SSM agent registration file read by the adapter
+type ssmAgentRegistration struct {
+	ManagedInstanceID string `json:"ManagedInstanceID"`
 }
 
 // AwsSsmPodEventAdapter handles SSM-based pod lifecycle events.
@@ -43,8 +50,14 @@ type AwsSsmPodEventAdapter struct {
 	podExecUtil  pluginadapters.PodExecInterface
 }
 
+var (
+	_ pluginadapters.PodEventPluginAdapter = &AwsSsmPodEventAdapter{}
+	_ pluginadapters.RemoteAccessCleaner   = &AwsSsmPodEventAdapter{}
+)
+
 // NewAwsSsmPodEventAdapter creates a new AwsSsmPodEventAdapter,
-// which implements pluginadapters.PodEventPluginAdapter for SSM-based remote access.
+// which implements pluginadapters.PodEventPluginAdapter and pluginadapters.RemoteAccessCleaner
+// for SSM-based remote access.
 // pluginClient handles cloud SDK operations (register, deregister).
 // podExecUtil handles pod exec operations (state files, registration scripts).
 func NewAwsSsmPodEventAdapter(pluginClient plugin.RemoteAccessPluginApis, podExecUtil pluginadapters.PodExecInterface) (*AwsSsmPodEventAdapter, error) {
@@ -69,11 +82,15 @@ func NewAwsSsmPodEventAdapter(pluginClient plugin.RemoteAccessPluginApis, podExe
 // Concurrency Protection:
 // - Random delay (0-2s) spreads out concurrent pod events
 // - SetupInProgress flag prevents duplicate setup attempts
-func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string) error {
+//
+// Returns the ID of the SSM managed node the sidecar registered as, or nil while the setup is pending.
+func (s *AwsSsmPodEventAdapter) HandlePodRunning(
+	ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string,
+) (*pluginadapters.RemoteAccessInfo, error) {
 	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", namespace, "workspace", workspaceName)
 
 	if s.pluginClient == nil {
-		return fmt.Errorf("plugin client not available")
+		return nil, fmt.Errorf("plugin client not available")
 	}
 
 	// Propagate a request ID so plugin logs can be correlated with this pod event
@@ -84,7 +101,7 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 	// Early exit if sidecar not running yet
 	if !pluginadapters.IsContainerRunning(pod, sidecarContainer) {
 		logger.V(1).Info("Sidecar container not running yet, waiting")
-		return nil
+		return nil, nil
 	}
 
 	// Random delay to spread out concurrent events
@@ -108,7 +125,7 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 	// Check if setup is already in progress
 	if state != nil && state.SetupInProgress {
 		logger.V(1).Info("Setup already in progress by another event, skipping")
-		return nil
+		return nil, nil
 	}
 
 	// Determine what needs to be setup
@@ -133,7 +150,7 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 
 		if !sidecarRestarted && !workspaceRestarted {
 			logger.V(1).Info("No restarts detected, already setup")
-			return nil
+			return &pluginadapters.RemoteAccessInfo{InstanceID: state.ManagedInstanceID}, nil
 		}
 
 		logger.Info("Container restart detected",
@@ -145,12 +162,19 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 		needCleanup = sidecarRestarted // Only cleanup if sidecar restarted
 	}
 
+	// The managed node is kept unless the sidecar registers again
+	var managedInstanceID string
+	if state != nil && !needSidecarSetup {
+		managedInstanceID = state.ManagedInstanceID
+	}
+
 	// Mark setup as in progress
 	inProgressState := &RegistrationState{
 		SidecarRestartCount:   currentSidecarRestarts,
 		WorkspaceRestartCount: currentWorkspaceRestarts,
 		SetupInProgress:       true,
 		SetupStartedAt:        time.Now(),
+		ManagedInstanceID:     managedInstanceID,
 	}
 	if err := s.writeRegistrationState(ctx, pod, sidecarContainer, stateFile, inProgressState); err != nil {
 		logger.Error(err, "Failed to write in-progress state, continuing anyway")
@@ -164,6 +188,8 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 		logger.V(1).Info("Setting up sidecar container")
 		if err := s.setupSidecarContainer(ctx, pod, workspaceName, namespace, podEventsContext, needCleanup); err != nil {
 			setupErr = err
+		} else {
+			managedInstanceID = s.readManagedInstanceID(ctx, pod, sidecarContainer, podEventsContext)
 		}
 	}
 
@@ -179,6 +205,7 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 		SidecarRestartCount:   currentSidecarRestarts,
 		WorkspaceRestartCount: currentWorkspaceRestarts,
 		SetupInProgress:       false, // Clear the flag
+		ManagedInstanceID:     managedInstanceID,
 	}
 	if err := s.writeRegistrationState(ctx, pod, sidecarContainer, stateFile, finalState); err != nil {
 		logger.Error(err, "Failed to write final state")
@@ -187,15 +214,15 @@ func (s *AwsSsmPodEventAdapter) HandlePodRunning(ctx context.Context, pod *corev
 	}
 
 	if setupErr != nil {
-		return setupErr
+		return nil, setupErr
 	}
 
-	logger.Info("Container setup completed")
-	return nil
+	logger.Info("Container setup completed", "managedInstanceId", managedInstanceID)
+	return &pluginadapters.RemoteAccessInfo{InstanceID: managedInstanceID}, nil
 }
 
-// HandlePodDeleted delegates cleanup to the plugin sidecar
-func (s *AwsSsmPodEventAdapter) HandlePodDeleted(ctx context.Context, pod *corev1.Pod, _ map[string]string) error {
+// CleanupRemoteAccess delegates the deregistration of the SSM managed node to the plugin sidecar
+func (s *AwsSsmPodEventAdapter) CleanupRemoteAccess(ctx context.Context, pod *corev1.Pod, _ map[string]string) error {
 	if s.pluginClient == nil {
 		return fmt.Errorf("plugin client not available")
 	}
@@ -236,6 +263,26 @@ func (s *AwsSsmPodEventAdapter) readRegistrationState(ctx context.Context, pod *
 	return &state, nil
 }
 
+// readManagedInstanceID reads the ID of the SSM managed node from the registration file of the SSM agent.
+// Returns an empty ID when the file cannot be read: the ID is informational and does not fail the setup.
+func (s *AwsSsmPodEventAdapter) readManagedInstanceID(ctx context.Context, pod *corev1.Pod, sidecarContainer string, podEventsContext map[string]string) string {
+	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", pod.Namespace)
+
+	registrationFile := ContextKeySsmRegistrationFile.ResolveStr(podEventsContext)
+	output, err := s.podExecUtil.ExecInPod(ctx, pod, sidecarContainer, []string{catCommand, registrationFile}, "")
+	if err != nil {
+		logger.Error(err, "Failed to read SSM agent registration file", "file", registrationFile)
+		return ""
+	}
+
+	var registration ssmAgentRegistration
+	if err := json.Unmarshal([]byte(output), &registration); err != nil {
+		logger.Error(err, "Failed to parse SSM agent registration file", "file", registrationFile)
+		return ""
+	}
+	return registration.ManagedInstanceID
+}
+
 // writeRegistrationState writes state to shared volume
 func (s *AwsSsmPodEventAdapter) writeRegistrationState(ctx context.Context, pod *corev1.Pod, sidecarContainer, stateFile string, state *RegistrationState) error {
 	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", pod.Namespace)
@@ -29,6 +29,8 @@ const (
 	testRegistrationScript       = "/usr/local/bin/register-ssm.sh"
 	testRemoteAccessServerScript = "/opt/amazon/sagemaker/workspace/remote-access/start-remote-access-server.sh"
 	testRemoteAccessServerPort   = "2222"
+	testSsmRegistrationFile      = "/var/lib/amazon/ssm/registration"
+	testManagedInstanceID        = "mi-0123456789abcdef0"
 )
 
 func testPodEventsContext() map[string]string {
@@ -97,7 +99,8 @@ func mockWriteStateFile(mockPodExec *MockPodExecUtil, expectedState *Registratio
 					}
 					return state.SidecarRestartCount == expectedState.SidecarRestartCount &&
 						state.WorkspaceRestartCount == expectedState.WorkspaceRestartCount &&
-						state.SetupInProgress == expectedState.SetupInProgress
+						state.SetupInProgress == expectedState.SetupInProgress &&
+						state.ManagedInstanceID == expectedState.ManagedInstanceID
 				}
 			}
 			return false
@@ -138,6 +141,17 @@ func mockMarkerFile(mockPodExec *MockPodExecUtil) {
 	).Return("", nil).Once()
 }
 
+// mockReadSSMRegistration mocks reading the registration file of the SSM agent in the sidecar
+func mockReadSSMRegistration(mockPodExec *MockPodExecUtil) {
+	mockPodExec.On("ExecInPod",
+		mock.Anything,
+		mock.Anything,
+		testSidecarContainer,
+		[]string{catCommand, testSsmRegistrationFile},
+		"",
+	).Return(`{"ManagedInstanceID":"`+testManagedInstanceID+`","Region":"us-west-2"}`, nil).Once()
+}
+
 // mockWorkspaceSetup mocks starting the remote access server in workspace container
 func mockWorkspaceSetup(mockPodExec *MockPodExecUtil) {
 	mockPodExec.On("ExecInPod",
@@ -265,6 +279,7 @@ func TestHandlePodRunning_FirstTimeSetup_NoStateFile(t *testing.T) {
 	mockRegisterNodeAgent(mockPluginClient)
 	mockSSMRegistration(mockPodExecUtil)
 	mockMarkerFile(mockPodExecUtil)
+	mockReadSSMRegistration(mockPodExecUtil)
 
 	// Mock 4: Workspace setup
 	mockWorkspaceSetup(mockPodExecUtil)
@@ -274,6 +289,7 @@ func TestHandlePodRunning_FirstTimeSetup_NoStateFile(t *testing.T) {
 		SidecarRestartCount:   0,
 		WorkspaceRestartCount: 0,
 		SetupInProgress:       false,
+		ManagedInstanceID:     testManagedInstanceID,
 	}
 	mockWriteStateFile(mockPodExecUtil, completeState)
 
@@ -296,9 +312,10 @@ func TestHandlePodRunning_FirstTimeSetup_NoStateFile(t *testing.T) {
 		},
 	}
 	pod := createTestPod(containerStatuses)
-	err = handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
+	info, err := handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
 
 	assert.NoError(t, err)
+	assert.Equal(t, testManagedInstanceID, info.InstanceID)
 	mockPodExecUtil.AssertExpectations(t)
 	mockPluginClient.AssertExpectations(t)
 }
@@ -330,12 +347,14 @@ func TestHandlePodRunning_SidecarContainerRestart(t *testing.T) {
 	mockRegisterNodeAgent(mockPluginClient)
 	mockSSMRegistration(mockPodExecUtil)
 	mockMarkerFile(mockPodExecUtil)
+	mockReadSSMRegistration(mockPodExecUtil)
 
 	// Mock 5: Write state - mark complete
 	completeState := &RegistrationState{
 		SidecarRestartCount:   2,
 		WorkspaceRestartCount: 0,
 		SetupInProgress:       false,
+		ManagedInstanceID:     testManagedInstanceID,
 	}
 	mockWriteStateFile(mockPodExecUtil, completeState)
 
@@ -358,9 +377,10 @@ func TestHandlePodRunning_SidecarContainerRestart(t *testing.T) {
 		},
 	}
 	pod := createTestPod(containerStatuses)
-	err = handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
+	info, err := handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
 
 	assert.NoError(t, err)
+	assert.Equal(t, testManagedInstanceID, info.InstanceID)
 	mockPodExecUtil.AssertExpectations(t)
 	mockPluginClient.AssertExpectations(t)
 }
@@ -374,6 +394,7 @@ func TestHandlePodRunning_NoRestartDetected_AlreadySetup(t *testing.T) {
 		SidecarRestartCount:   0,
 		WorkspaceRestartCount: 0,
 		SetupInProgress:       false,
+		ManagedInstanceID:     testManagedInstanceID,
 	}
 	mockReadStateFile(mockPodExecUtil, existingState)
 
@@ -395,9 +416,10 @@ func TestHandlePodRunning_NoRestartDetected_AlreadySetup(t *testing.T) {
 		},
 	}
 	pod := createTestPod(containerStatuses)
-	err = handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
+	info, err := handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
 
 	assert.NoError(t, err)
+	assert.Equal(t, testManagedInstanceID, info.InstanceID)
 	mockPodExecUtil.AssertExpectations(t)
 	mockPluginClient.AssertExpectations(t)
 }
@@ -426,9 +448,10 @@ func TestHandlePodRunning_SidecarNotRunning(t *testing.T) {
 		},
 	}
 	pod := createTestPod(containerStatuses)
-	err = handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
+	info, err := handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
 
 	assert.NoError(t, err)
+	assert.Nil(t, info)
 	mockPodExecUtil.AssertExpectations(t)
 	mockPluginClient.AssertExpectations(t)
 }
@@ -462,16 +485,17 @@ func TestHandlePodRunning_SetupInProgress(t *testing.T) {
 		},
 	}
 	pod := createTestPod(containerStatuses)
-	err = handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
+	info, err := handler.HandlePodRunning(context.Background(), pod, "test-workspace", "test-namespace", testPodEventsContext())
 
 	assert.NoError(t, err)
+	assert.Nil(t, info)
 	mockPodExecUtil.AssertExpectations(t)
 	mockPluginClient.AssertExpectations(t)
 }
 
-// HandlePodDeleted Tests
+// CleanupRemoteAccess Tests
 
-func TestHandlePodDeleted_PluginClientNotAvailable(t *testing.T) {
+func TestCleanupRemoteAccess_PluginClientNotAvailable(t *testing.T) {
 	mockPodExecUtil := &MockPodExecUtil{}
 	handler := &AwsSsmPodEventAdapter{
 		pluginClient: nil,
@@ -480,13 +504,13 @@ func TestHandlePodDeleted_PluginClientNotAvailable(t *testing.T) {
 
 	pod := createTestPod([]corev1.ContainerStatus{})
 
-	err := handler.HandlePodDeleted(context.Background(), pod, testPodEventsContext())
+	err := handler.CleanupRemoteAccess(context.Background(), pod, testPodEventsContext())
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "plugin client not available")
 }
 
-func TestHandlePodDeleted_Success(t *testing.T) {
+func TestCleanupRemoteAccess_Success(t *testing.T) {
 	mockPluginClient := &MockPluginRemoteAccessClient{}
 	mockPodExecUtil := &MockPodExecUtil{}
 
@@ -497,13 +521,13 @@ func TestHandlePodDeleted_Success(t *testing.T) {
 
 	pod := createTestPod([]corev1.ContainerStatus{})
 
-	err = handler.HandlePodDeleted(context.Background(), pod, testPodEventsContext())
+	err = handler.CleanupRemoteAccess(context.Background(), pod, testPodEventsContext())
 
 	assert.NoError(t, err)
 	mockPluginClient.AssertExpectations(t)
 }
 
-func TestHandlePodDeleted_CleanupFailure(t *testing.T) {
+func TestCleanupRemoteAccess_CleanupFailure(t *testing.T) {
 	mockPluginClient := &MockPluginRemoteAccessClient{}
 	mockPodExecUtil := &MockPodExecUtil{}
 
@@ -515,7 +539,7 @@ func TestHandlePodDeleted_CleanupFailure(t *testing.T) {
 
 	pod := createTestPod([]corev1.ContainerStatus{})
 
-	err = handler.HandlePodDeleted(context.Background(), pod, testPodEventsContext())
+	err = handler.CleanupRemoteAccess(context.Background(), pod, testPodEventsContext())
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "deregister failed")
@@ -0,0 +1,198 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package benchgate compares two runs of the Go benchmarks of the controller and reports the
+// benchmarks whose time or allocations per operation regressed beyond a threshold.
+package benchgate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Sample is one result line of a benchmark
+type Sample struct {
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+}
+
+// Results are the samples of each benchmark, by package-qualified name without the GOMAXPROCS suffix
+type Results map[string][]Sample
+
+// Parse reads the output of go test -bench -benchmem. Benchmarks are keyed by the package of the
+// preceding "pkg:" line and their name, so that results of several packages and runs merge.
+func Parse(r io.Reader) (Results, error) {
+	results := Results{}
+	pkg := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = rest
+			continue
+		}
+		if !strings.HasPrefix(line, "Benchmark") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		// name, iterations, then value/unit pairs
+		if len(fields) < 4 || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			continue
+		}
+		var sample Sample
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q of benchmark %s", fields[i], fields[0])
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				sample.NsPerOp = value
+			case "B/op":
+				sample.BytesPerOp = value
+			case "allocs/op":
+				sample.AllocsPerOp = value
+			}
+		}
+
+		name := trimProcs(fields[0])
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		results[name] = append(results[name], sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read benchmark results: %w", err)
+	}
+	return results, nil
+}
+
+// trimProcs removes the -GOMAXPROCS suffix go test adds to benchmark names
+func trimProcs(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// Thresholds are the relative increases of the medians beyond which a benchmark regressed
+type Thresholds struct {
+	// Time is the allowed increase of ns/op, e.g. 0.2 for 20%
+	Time float64
+	// Allocs is the allowed increase of allocs/op
+	Allocs float64
+}
+
+// Comparison is the change of a benchmark between the base and the head runs
+type Comparison struct {
+	Name       string
+	BaseNs     float64
+	HeadNs     float64
+	BaseAllocs float64
+	HeadAllocs float64
+	// Regressed lists the metrics beyond their threshold
+	Regressed []string
+}
+
+// TimeDelta returns the relative change of ns/op
+func (c Comparison) TimeDelta() float64 {
+	return delta(c.BaseNs, c.HeadNs)
+}
+
+// AllocsDelta returns the relative change of allocs/op
+func (c Comparison) AllocsDelta() float64 {
+	return delta(c.BaseAllocs, c.HeadAllocs)
+}
+
+func delta(base, head float64) float64 {
+	if base == 0 {
+		if head == 0 {
+			return 0
+		}
+		return 1
+	}
+	return (head - base) / base
+}
+
+// Compare compares the medians of the benchmarks of both runs, sorted by name. Benchmarks
+// missing from one of the runs are skipped.
+func Compare(base, head Results, thresholds Thresholds) []Comparison {
+	names := make([]string, 0, len(head))
+	for name := range head {
+		if _, ok := base[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	comparisons := make([]Comparison, 0, len(names))
+	for _, name := range names {
+		c := Comparison{
+			Name:       name,
+			BaseNs:     median(base[name], func(s Sample) float64 { return s.NsPerOp }),
+			HeadNs:     median(head[name], func(s Sample) float64 { return s.NsPerOp }),
+			BaseAllocs: median(base[name], func(s Sample) float64 { return s.AllocsPerOp }),
+			HeadAllocs: median(head[name], func(s Sample) float64 { return s.AllocsPerOp }),
+		}
+		if c.TimeDelta() > thresholds.Time {
+			c.Regressed = append(c.Regressed, "time")
+		}
+		if c.AllocsDelta() > thresholds.Allocs {
+			c.Regressed = append(c.Regressed, "allocs")
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// median returns the median of a metric of the samples, which is robust to outlier runs
+func median(samples []Sample, metric func(Sample) float64) float64 {
+	values := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		values = append(values, metric(s))
+	}
+	slices.Sort(values)
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Report writes the comparisons as a table and returns the number of regressed benchmarks
+func Report(w io.Writer, comparisons []Comparison) int {
+	regressions := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tbase ns/op\thead ns/op\tdelta\tbase allocs\thead allocs\tdelta\t")
+	for _, c := range comparisons {
+		marker := ""
+		if len(c.Regressed) > 0 {
+			regressions++
+			marker = "REGRESSED: " + strings.Join(c.Regressed, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%.0f\t%.0f\t%+.1f%%\t%.0f\t%.0f\t%+.1f%%\t%s\n",
+			c.Name, c.BaseNs, c.HeadNs, 100*c.TimeDelta(), c.BaseAllocs, c.HeadAllocs, 100*c.AllocsDelta(), marker)
+	}
+	_ = tw.Flush()
+	return regressions
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package benchgate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBaseOutput = `goos: linux
+goarch: amd64
+pkg: github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate
+cpu: Intel(R) Xeon(R)
+BenchmarkRender-8   	   50000	     21000 ns/op	    4000 B/op	      80 allocs/op
+BenchmarkRender-8   	   50000	     20000 ns/op	    4000 B/op	      80 allocs/op
+BenchmarkRender-8   	   50000	     90000 ns/op	    4000 B/op	      80 allocs/op
+PASS
+ok  	github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate	3.2s
+pkg: github.com/jupyter-infra/jupyter-k8s/internal/controller
+BenchmarkBuildDeployment-8   	   10000	    100000 ns/op	   20000 B/op	     300 allocs/op
+BenchmarkRemoved-8           	   10000	    100000 ns/op	   20000 B/op	     300 allocs/op
+`
+
+func TestParse(t *testing.T) {
+	results, err := Parse(strings.NewReader(testBaseOutput))
+	require.NoError(t, err)
+
+	render := results["github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate.BenchmarkRender"]
+	require.Len(t, render, 3)
+	assert.Equal(t, Sample{NsPerOp: 21000, BytesPerOp: 4000, AllocsPerOp: 80}, render[0])
+	assert.Len(t, results["github.com/jupyter-infra/jupyter-k8s/internal/controller.BenchmarkBuildDeployment"], 1)
+}
+
+func TestParse_InvalidValue(t *testing.T) {
+	_, err := Parse(strings.NewReader("BenchmarkRender-8   50000   fast ns/op\n"))
+	assert.EqualError(t, err, `invalid value "fast" of benchmark BenchmarkRender-8`)
+}
+
+func TestTrimProcs(t *testing.T) {
+	assert.Equal(t, "BenchmarkRender", trimProcs("BenchmarkRender-16"))
+	assert.Equal(t, "BenchmarkRender/large-template", trimProcs("BenchmarkRender/large-template"))
+	assert.Equal(t, "BenchmarkRender", trimProcs("BenchmarkRender"))
+}
+
+func TestCompare(t *testing.T) {
+	base, err := Parse(strings.NewReader(testBaseOutput))
+	require.NoError(t, err)
+	head, err := Parse(strings.NewReader(`pkg: github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate
+BenchmarkRender-8   	   50000	     23000 ns/op	    4000 B/op	      80 allocs/op
+pkg: github.com/jupyter-infra/jupyter-k8s/internal/controller
+BenchmarkBuildDeployment-8   	   10000	    100000 ns/op	   20000 B/op	     400 allocs/op
+BenchmarkAdded-8             	   10000	    100000 ns/op	   20000 B/op	     300 allocs/op
+`))
+	require.NoError(t, err)
+
+	comparisons := Compare(base, head, Thresholds{Time: 0.2, Allocs: 0.05})
+	require.Len(t, comparisons, 2, "benchmarks missing from one run are skipped")
+
+	deployment := comparisons[0]
+	assert.Equal(t, "github.com/jupyter-infra/jupyter-k8s/internal/controller.BenchmarkBuildDeployment", deployment.Name)
+	assert.Equal(t, []string{"allocs"}, deployment.Regressed)
+
+	render := comparisons[1]
+	assert.Equal(t, 21000.0, render.BaseNs, "the median ignores the outlier run")
+	assert.InDelta(t, 0.095, render.TimeDelta(), 0.001)
+	assert.Empty(t, render.Regressed)
+
+	var out bytes.Buffer
+	assert.Equal(t, 1, Report(&out, comparisons))
+	assert.Contains(t, out.String(), "REGRESSED: allocs")
+}
@@ -0,0 +1,407 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package certrotator provisions and rotates the TLS certificates of the webhook and metrics
+// servers for clusters that do not run cert-manager. A self-signed CA and the serving certificate
+// are stored in a Secret shared by all the replicas; every replica writes the serving certificate
+// to its certificate directories and patches the CA bundle onto the webhook configurations.
+package certrotator
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The rotator has no RBAC markers, so that the manager role does not carry its permissions when
+// rotation is disabled: the Helm chart grants them with certRotation.enable, restricted to the
+// Secret and webhook configurations of the install.
+
+// Defaults of Options
+const (
+	DefaultCAValidity        = 5 * 365 * 24 * time.Hour
+	DefaultCertValidity      = 365 * 24 * time.Hour
+	DefaultRotationLookahead = 30 * 24 * time.Hour
+	DefaultCheckInterval     = time.Hour
+)
+
+// initialProvisioningTimeout bounds the provisioning done before the manager starts
+const initialProvisioningTimeout = time.Minute
+
+// Options configures the certificate rotator
+type Options struct {
+	// SecretName is the name of the Secret holding the CA and the serving certificate
+	SecretName string
+	// Namespace of the Secret, the namespace of the controller
+	Namespace string
+	// DNSNames of the serving certificate: the names of the Services of the webhook and metrics servers
+	DNSNames []string
+	// CertDirs are the directories the serving certificate is written to, as tls.crt and tls.key
+	CertDirs []string
+	// ValidatingWebhookConfigurations are the names of the configurations whose CA bundle is patched
+	ValidatingWebhookConfigurations []string
+	// MutatingWebhookConfigurations are the names of the configurations whose CA bundle is patched
+	MutatingWebhookConfigurations []string
+	// CAValidity is the lifetime of a generated CA. Default: DefaultCAValidity.
+	CAValidity time.Duration
+	// CertValidity is the lifetime of a serving certificate. Default: DefaultCertValidity.
+	CertValidity time.Duration
+	// RotationLookahead is how long before their expiry the certificates are rotated.
+	// Default: DefaultRotationLookahead.
+	RotationLookahead time.Duration
+	// CheckInterval is the interval at which the certificates are checked. Default: DefaultCheckInterval.
+	CheckInterval time.Duration
+}
+
+// withDefaults returns the options with the unset durations defaulted
+func (o Options) withDefaults() Options {
+	if o.CAValidity <= 0 {
+		o.CAValidity = DefaultCAValidity
+	}
+	if o.CertValidity <= 0 {
+		o.CertValidity = DefaultCertValidity
+	}
+	if o.RotationLookahead <= 0 {
+		o.RotationLookahead = DefaultRotationLookahead
+	}
+	if o.CheckInterval <= 0 {
+		o.CheckInterval = DefaultCheckInterval
+	}
+	return o
+}
+
+// validate checks the options that have no default
+func (o Options) validate() error {
+	if o.SecretName == "" || o.Namespace == "" {
+		return errors.New("the certificate Secret name and namespace must be set")
+	}
+	if len(o.DNSNames) == 0 {
+		return errors.New("at least one DNS name must be set")
+	}
+	if len(o.CertDirs) == 0 {
+		return errors.New("at least one certificate directory must be set")
+	}
+	if o.RotationLookahead >= o.CertValidity {
+		return fmt.Errorf("the rotation lookahead %s must be shorter than the certificate validity %s",
+			o.RotationLookahead, o.CertValidity)
+	}
+	return nil
+}
+
+// Rotator keeps the certificates of the Secret valid, and the certificate directories and
+// webhook configurations in sync with it. It implements the controller-runtime Runnable interface.
+type Rotator struct {
+	options Options
+	// reader reads the Secret and webhook configurations uncached, to avoid cluster-wide informers
+	reader client.Reader
+	writer client.Writer
+	logger logr.Logger
+	now    func() time.Time
+}
+
+// NewRotator creates a certificate rotator
+func NewRotator(reader client.Reader, writer client.Writer, options Options) (*Rotator, error) {
+	options = options.withDefaults()
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	return &Rotator{
+		options: options,
+		reader:  reader,
+		writer:  writer,
+		logger:  ctrl.Log.WithName("cert-rotator"),
+		now:     time.Now,
+	}, nil
+}
+
+// SetupCertRotatorWithManager provisions the certificates, so that the webhook and metrics servers
+// find them when the manager starts, and adds the rotator to the manager
+func SetupCertRotatorWithManager(mgr ctrl.Manager, options Options) error {
+	rotator, err := NewRotator(mgr.GetAPIReader(), mgr.GetClient(), options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), initialProvisioningTimeout)
+	defer cancel()
+	if err := rotator.Ensure(ctx); err != nil {
+		return fmt.Errorf("failed to provision certificates: %w", err)
+	}
+	return mgr.Add(rotator)
+}
+
+// Start checks the certificates every CheckInterval until ctx is done. Implements the controller-runtime
+// Runnable interface.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.options.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Ensure(ctx); err != nil {
+				// the certificates are rotated ahead of their expiry: retry on the next check
+				r.logger.Error(err, "Failed to rotate certificates")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false because every replica serves webhooks with the certificates.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ensure rotates the certificates of the Secret if needed, then writes them to the certificate
+// directories and patches the CA bundle onto the webhook configurations
+func (r *Rotator) Ensure(ctx context.Context) error {
+	var certs *certificates
+	// Replicas race to create and rotate the Secret: the losers use the certificates of the winner
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		var err error
+		certs, err = r.ensureSecret(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := r.writeCertDirs(certs); err != nil {
+		return err
+	}
+	return r.patchWebhookConfigurations(ctx, certs.caBundle())
+}
+
+// ensureSecret returns the certificates of the Secret, creating or rotating them if needed
+func (r *Rotator) ensureSecret(ctx context.Context) (*certificates, error) {
+	secret := &corev1.Secret{}
+	err := r.reader.Get(ctx, types.NamespacedName{Name: r.options.SecretName, Namespace: r.options.Namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get certificate secret %s: %w", r.options.SecretName, err)
+	}
+	exists := err == nil
+
+	var existing *certificates
+	if exists {
+		existing, err = parseCertificates(secret.Data)
+		if err != nil {
+			r.logger.Info("Replacing invalid certificate secret", "secret", r.options.SecretName, "reason", err.Error())
+			existing = nil
+		}
+	}
+
+	certs, changed, err := r.rotate(existing)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return certs, nil
+	}
+
+	data, err := encodeCertificates(certs)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.options.SecretName, Namespace: r.options.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		if err := r.writer.Create(ctx, secret); err != nil {
+			return nil, err
+		}
+		r.logger.Info("Created certificate secret", "secret", r.options.SecretName,
+			"notAfter", certs.serving.cert.NotAfter)
+		return certs, nil
+	}
+
+	secret.Data = data
+	if err := r.writer.Update(ctx, secret); err != nil {
+		return nil, err
+	}
+	r.logger.Info("Rotated certificates", "secret", r.options.SecretName, "notAfter", certs.serving.cert.NotAfter)
+	return certs, nil
+}
+
+// rotate returns the certificates to store in place of existing, and whether they differ from it.
+// existing is nil when the Secret does not hold valid certificates.
+func (r *Rotator) rotate(existing *certificates) (*certificates, bool, error) {
+	now := r.now()
+	lookahead := r.options.RotationLookahead
+
+	if existing == nil || expiresWithin(existing.ca.cert, now, lookahead) {
+		ca, err := newCA(now, r.options.CAValidity)
+		if err != nil {
+			return nil, false, err
+		}
+		serving, err := newServingCert(ca, r.options.DNSNames, now, r.options.CertValidity)
+		if err != nil {
+			return nil, false, err
+		}
+		certs := &certificates{ca: ca, serving: serving}
+		if existing != nil {
+			certs.previousCAs = validCAs(append([]*x509.Certificate{existing.ca.cert}, existing.previousCAs...), now)
+		}
+		return certs, true, nil
+	}
+
+	certs := &certificates{ca: existing.ca, serving: existing.serving, previousCAs: validCAs(existing.previousCAs, now)}
+	changed := len(certs.previousCAs) != len(existing.previousCAs)
+	if expiresWithin(existing.serving.cert, now, lookahead) ||
+		!coversDNSNames(existing.serving.cert, r.options.DNSNames) ||
+		existing.serving.cert.CheckSignatureFrom(existing.ca.cert) != nil {
+		serving, err := newServingCert(existing.ca, r.options.DNSNames, now, r.options.CertValidity)
+		if err != nil {
+			return nil, false, err
+		}
+		certs.serving = serving
+		changed = true
+	}
+	return certs, changed, nil
+}
+
+// validCAs returns the CAs that have not expired at now
+func validCAs(cas []*x509.Certificate, now time.Time) []*x509.Certificate {
+	var valid []*x509.Certificate
+	for _, ca := range cas {
+		if now.Before(ca.NotAfter) {
+			valid = append(valid, ca)
+		}
+	}
+	return valid
+}
+
+// writeCertDirs writes the serving certificate to the certificate directories. The certificate
+// watchers of the webhook and metrics servers reload it when the files change.
+func (r *Rotator) writeCertDirs(certs *certificates) error {
+	key, err := encodePrivateKey(certs.serving.key)
+	if err != nil {
+		return err
+	}
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		// the key first, so that the watchers never load a certificate without its key
+		{KeyKey, key},
+		{CertKey, encodeCertificate(certs.serving.cert)},
+		{CACertKey, certs.caBundle()},
+	}
+
+	for _, dir := range r.options.CertDirs {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create certificate directory %s: %w", dir, err)
+		}
+		for _, file := range files {
+			if err := writeFileIfChanged(filepath.Join(dir, file.name), file.content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFileIfChanged replaces the content of path, through a rename so that readers never see
+// a partial file
+func writeFileIfChanged(path string, content []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// patchWebhookConfigurations sets the CA bundle of the webhooks of the configured webhook
+// configurations. Configurations that do not exist yet are patched on a later check.
+func (r *Rotator) patchWebhookConfigurations(ctx context.Context, caBundle []byte) error {
+	for _, name := range r.options.ValidatingWebhookConfigurations {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		err := r.patchWebhookConfiguration(ctx, name, config, func() bool {
+			changed := false
+			for i := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[i].ClientConfig, caBundle) || changed
+			}
+			return changed
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range r.options.MutatingWebhookConfigurations {
+		config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		err := r.patchWebhookConfiguration(ctx, name, config, func() bool {
+			changed := false
+			for i := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[i].ClientConfig, caBundle) || changed
+			}
+			return changed
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchWebhookConfiguration gets the webhook configuration name into config, applies setCABundles,
+// and updates the configuration if they changed it
+func (r *Rotator) patchWebhookConfiguration(
+	ctx context.Context,
+	name string,
+	config client.Object,
+	setCABundles func() bool,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.reader.Get(ctx, types.NamespacedName{Name: name}, config); err != nil {
+			if apierrors.IsNotFound(err) {
+				r.logger.Info("Webhook configuration not found, skipping CA bundle injection", "name", name)
+				return nil
+			}
+			return fmt.Errorf("failed to get webhook configuration %s: %w", name, err)
+		}
+		if !setCABundles() {
+			return nil
+		}
+		if err := r.writer.Update(ctx, config); err != nil {
+			return err
+		}
+		r.logger.Info("Injected CA bundle", "webhookConfiguration", name)
+		return nil
+	})
+}
+
+// setCABundle sets the CA bundle of a webhook and returns whether it changed
+func setCABundle(clientConfig *admissionregistrationv1.WebhookClientConfig, caBundle []byte) bool {
+	if bytes.Equal(clientConfig.CABundle, caBundle) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
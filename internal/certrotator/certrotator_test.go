@@ -0,0 +1,225 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package certrotator
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testSecretName        = "webhook-cert"
+	testNamespace         = "jupyter-k8s-system"
+	testDNSName           = "jupyter-k8s-controller-manager.jupyter-k8s-system.svc"
+	testValidatingWebhook = "jupyter-k8s-validating-webhook-configuration"
+	testMutatingWebhook   = "jupyter-k8s-mutating-webhook-configuration"
+)
+
+// getTestClient creates a fake controller-runtime client holding the webhook configurations
+func getTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = admissionregistrationv1.AddToScheme(scheme)
+	objects = append(objects,
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testValidatingWebhook},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vworkspace.kb.io"}, {Name: "vtemplate.kb.io"}},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testMutatingWebhook},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mworkspace.kb.io"}},
+		},
+	)
+	return fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+// newTestRotator creates a rotator writing to a temporary directory, at a fixed time
+func newTestRotator(t *testing.T, k8sClient client.Client, now time.Time) *Rotator {
+	rotator, err := NewRotator(k8sClient, k8sClient, Options{
+		SecretName:                      testSecretName,
+		Namespace:                       testNamespace,
+		DNSNames:                        []string{testDNSName},
+		CertDirs:                        []string{filepath.Join(t.TempDir(), "webhook"), filepath.Join(t.TempDir(), "metrics")},
+		ValidatingWebhookConfigurations: []string{testValidatingWebhook},
+		MutatingWebhookConfigurations:   []string{testMutatingWebhook, "missing"},
+	})
+	require.NoError(t, err)
+	rotator.now = func() time.Time { return now }
+	return rotator
+}
+
+// getTestSecret returns the certificate Secret and its parsed certificates
+func getTestSecret(t *testing.T, k8sClient client.Client) (*corev1.Secret, *certificates) {
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(context.Background(),
+		types.NamespacedName{Name: testSecretName, Namespace: testNamespace}, secret))
+	certs, err := parseCertificates(secret.Data)
+	require.NoError(t, err)
+	return secret, certs
+}
+
+// verifyServingCert checks that the serving certificate of certs is trusted by caBundle for testDNSName
+func verifyServingCert(t *testing.T, certs *certificates, caBundle []byte, now time.Time) {
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caBundle))
+	_, err := certs.serving.cert.Verify(x509.VerifyOptions{
+		DNSName:     testDNSName,
+		Roots:       roots,
+		CurrentTime: now,
+	})
+	assert.NoError(t, err)
+}
+
+func TestEnsureProvisionsCertificates(t *testing.T) {
+	k8sClient := getTestClient()
+	now := time.Now()
+	rotator := newTestRotator(t, k8sClient, now)
+
+	require.NoError(t, rotator.Ensure(context.Background()))
+
+	secret, certs := getTestSecret(t, k8sClient)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	assert.Empty(t, certs.previousCAs)
+	verifyServingCert(t, certs, secret.Data[CACertKey], now)
+
+	for _, dir := range rotator.options.CertDirs {
+		for _, key := range []string{CertKey, KeyKey, CACertKey} {
+			content, err := os.ReadFile(filepath.Join(dir, key))
+			require.NoError(t, err)
+			assert.Equal(t, secret.Data[key], content, key)
+		}
+	}
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: testValidatingWebhook}, validating))
+	for _, webhook := range validating.Webhooks {
+		assert.Equal(t, secret.Data[CACertKey], webhook.ClientConfig.CABundle, webhook.Name)
+	}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: testMutatingWebhook}, mutating))
+	assert.Equal(t, secret.Data[CACertKey], mutating.Webhooks[0].ClientConfig.CABundle)
+}
+
+func TestEnsureKeepsValidCertificates(t *testing.T) {
+	k8sClient := getTestClient()
+	now := time.Now()
+	require.NoError(t, newTestRotator(t, k8sClient, now).Ensure(context.Background()))
+	before, _ := getTestSecret(t, k8sClient)
+
+	// Another replica uses the certificates of the Secret
+	require.NoError(t, newTestRotator(t, k8sClient, now.Add(24*time.Hour)).Ensure(context.Background()))
+
+	after, _ := getTestSecret(t, k8sClient)
+	assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	assert.Equal(t, before.Data, after.Data)
+}
+
+func TestEnsureRotatesExpiringServingCert(t *testing.T) {
+	k8sClient := getTestClient()
+	now := time.Now()
+	require.NoError(t, newTestRotator(t, k8sClient, now).Ensure(context.Background()))
+	_, before := getTestSecret(t, k8sClient)
+
+	later := now.Add(DefaultCertValidity - DefaultRotationLookahead + time.Hour)
+	require.NoError(t, newTestRotator(t, k8sClient, later).Ensure(context.Background()))
+
+	secret, after := getTestSecret(t, k8sClient)
+	assert.Equal(t, before.ca.cert.Raw, after.ca.cert.Raw, "the CA is kept")
+	assert.NotEqual(t, before.serving.cert.Raw, after.serving.cert.Raw)
+	verifyServingCert(t, after, secret.Data[CACertKey], later)
+}
+
+func TestEnsureRotatesExpiringCA(t *testing.T) {
+	k8sClient := getTestClient()
+	now := time.Now()
+	require.NoError(t, newTestRotator(t, k8sClient, now).Ensure(context.Background()))
+	_, before := getTestSecret(t, k8sClient)
+
+	later := now.Add(DefaultCAValidity - DefaultRotationLookahead + time.Hour)
+	require.NoError(t, newTestRotator(t, k8sClient, later).Ensure(context.Background()))
+
+	secret, after := getTestSecret(t, k8sClient)
+	assert.NotEqual(t, before.ca.cert.Raw, after.ca.cert.Raw)
+	require.Len(t, after.previousCAs, 1, "the previous CA stays trusted until it expires")
+	assert.Equal(t, before.ca.cert.Raw, after.previousCAs[0].Raw)
+	verifyServingCert(t, after, secret.Data[CACertKey], later)
+
+	// Once expired, the previous CA is dropped from the bundle
+	expired := before.ca.cert.NotAfter.Add(time.Hour)
+	require.NoError(t, newTestRotator(t, k8sClient, expired).Ensure(context.Background()))
+	_, pruned := getTestSecret(t, k8sClient)
+	assert.Equal(t, after.ca.cert.Raw, pruned.ca.cert.Raw)
+	assert.Empty(t, pruned.previousCAs)
+}
+
+func TestEnsureReissuesForNewDNSNames(t *testing.T) {
+	k8sClient := getTestClient()
+	now := time.Now()
+	require.NoError(t, newTestRotator(t, k8sClient, now).Ensure(context.Background()))
+
+	rotator := newTestRotator(t, k8sClient, now)
+	rotator.options.DNSNames = []string{testDNSName, "jupyter-k8s-metrics.jupyter-k8s-system.svc"}
+	require.NoError(t, rotator.Ensure(context.Background()))
+
+	_, certs := getTestSecret(t, k8sClient)
+	assert.Equal(t, rotator.options.DNSNames, certs.serving.cert.DNSNames)
+}
+
+func TestEnsureReplacesInvalidSecret(t *testing.T) {
+	k8sClient := getTestClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecretName, Namespace: testNamespace},
+		Data:       map[string][]byte{CertKey: []byte("not a certificate")},
+	})
+	now := time.Now()
+
+	require.NoError(t, newTestRotator(t, k8sClient, now).Ensure(context.Background()))
+
+	secret, certs := getTestSecret(t, k8sClient)
+	verifyServingCert(t, certs, secret.Data[CACertKey], now)
+}
+
+func TestNewRotatorValidatesOptions(t *testing.T) {
+	k8sClient := getTestClient()
+	valid := Options{
+		SecretName: testSecretName,
+		Namespace:  testNamespace,
+		DNSNames:   []string{testDNSName},
+		CertDirs:   []string{t.TempDir()},
+	}
+
+	rotator, err := NewRotator(k8sClient, k8sClient, valid)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCheckInterval, rotator.options.CheckInterval)
+
+	noDNSNames := valid
+	noDNSNames.DNSNames = nil
+	_, err = NewRotator(k8sClient, k8sClient, noDNSNames)
+	assert.ErrorContains(t, err, "DNS name")
+
+	noCertDirs := valid
+	noCertDirs.CertDirs = nil
+	_, err = NewRotator(k8sClient, k8sClient, noCertDirs)
+	assert.ErrorContains(t, err, "certificate directory")
+
+	longLookahead := valid
+	longLookahead.RotationLookahead = DefaultCertValidity
+	_, err = NewRotator(k8sClient, k8sClient, longLookahead)
+	assert.ErrorContains(t, err, "rotation lookahead")
+}
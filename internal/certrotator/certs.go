@@ -0,0 +1,256 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package certrotator
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Keys of the Secret holding the certificates, compatible with a kubernetes.io/tls Secret
+const (
+	// CACertKey holds the PEM bundle of the CAs: the signing CA first, then the previous CAs
+	// that have not expired, so that clients trust the certificates of replicas not yet rotated
+	CACertKey = "ca.crt"
+	// CAKeyKey holds the PEM private key of the signing CA
+	CAKeyKey = "ca.key"
+	// CertKey holds the PEM serving certificate
+	CertKey = "tls.crt"
+	// KeyKey holds the PEM private key of the serving certificate
+	KeyKey = "tls.key"
+)
+
+// pem block types
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "EC PRIVATE KEY"
+)
+
+// caCommonName is the subject of the generated CAs
+const caCommonName = "jupyter-k8s-webhook-ca"
+
+// keyPair is a certificate and its private key
+type keyPair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// certificates are the contents of the Secret, parsed
+type certificates struct {
+	// ca signs the serving certificate
+	ca *keyPair
+	// previousCAs are the CAs ca replaced, kept in the bundle until they expire
+	previousCAs []*x509.Certificate
+	// serving is the certificate of the webhook and metrics servers
+	serving *keyPair
+}
+
+// newCA generates a self-signed CA valid from now for validity
+func newCA(now time.Time, validity time.Duration) (*keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: caCommonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return signCertificate(template, template, &key.PublicKey, key, key)
+}
+
+// newServingCert generates a serving certificate for dnsNames, signed by ca and valid from now for validity.
+// The certificate does not outlive ca.
+func newServingCert(ca *keyPair, dnsNames []string, now time.Time, validity time.Duration) (*keyPair, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("the serving certificate needs at least one DNS name")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return signCertificate(template, ca.cert, &key.PublicKey, key, ca.key)
+}
+
+// signCertificate creates the certificate of template for key, signed by parent with parentKey
+func signCertificate(
+	template, parent *x509.Certificate,
+	publicKey *ecdsa.PublicKey,
+	key, parentKey *ecdsa.PrivateKey,
+) (*keyPair, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created certificate: %w", err)
+	}
+	return &keyPair{cert: cert, key: key}, nil
+}
+
+// newSerialNumber returns a random 128-bit certificate serial number
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// expiresWithin returns whether cert is no longer valid at now+lookahead
+func expiresWithin(cert *x509.Certificate, now time.Time, lookahead time.Duration) bool {
+	return !now.Add(lookahead).Before(cert.NotAfter)
+}
+
+// coversDNSNames returns whether cert is valid for all of dnsNames
+func coversDNSNames(cert *x509.Certificate, dnsNames []string) bool {
+	for _, name := range dnsNames {
+		if cert.VerifyHostname(name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCertificates parses the data of the Secret. It returns an error when a key is missing
+// or does not hold a valid PEM block, in which case the certificates are generated again.
+func parseCertificates(data map[string][]byte) (*certificates, error) {
+	caCerts, err := parseCertificateBundle(data[CACertKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", CACertKey, err)
+	}
+	caKey, err := parsePrivateKey(data[CAKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", CAKeyKey, err)
+	}
+	servingCerts, err := parseCertificateBundle(data[CertKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", CertKey, err)
+	}
+	servingKey, err := parsePrivateKey(data[KeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", KeyKey, err)
+	}
+
+	return &certificates{
+		ca:          &keyPair{cert: caCerts[0], key: caKey},
+		previousCAs: caCerts[1:],
+		serving:     &keyPair{cert: servingCerts[0], key: servingKey},
+	}, nil
+}
+
+// parseCertificateBundle parses the certificates of a PEM bundle, requiring at least one
+func parseCertificateBundle(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypeCertificate {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate")
+	}
+	return certs, nil
+}
+
+// parsePrivateKey parses a PEM EC private key
+func parsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypePrivateKey {
+		return nil, errors.New("no PEM private key")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// encodeCertificates encodes the certificates into the data of the Secret
+func encodeCertificates(certs *certificates) (map[string][]byte, error) {
+	caKey, err := encodePrivateKey(certs.ca.key)
+	if err != nil {
+		return nil, err
+	}
+	servingKey, err := encodePrivateKey(certs.serving.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		CACertKey: certs.caBundle(),
+		CAKeyKey:  caKey,
+		CertKey:   encodeCertificate(certs.serving.cert),
+		KeyKey:    servingKey,
+	}, nil
+}
+
+// caBundle returns the PEM bundle of the signing CA and the previous CAs
+func (c *certificates) caBundle() []byte {
+	var bundle bytes.Buffer
+	bundle.Write(encodeCertificate(c.ca.cert))
+	for _, ca := range c.previousCAs {
+		bundle.Write(encodeCertificate(ca))
+	}
+	return bundle.Bytes()
+}
+
+// encodeCertificate encodes a certificate as PEM
+func encodeCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw})
+}
+
+// encodePrivateKey encodes an EC private key as PEM
+func encodePrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
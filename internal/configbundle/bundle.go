@@ -0,0 +1,166 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package configbundle exports the effective operator configuration of a cluster
+// (workspace templates, access strategies and manager flags) as a normalized YAML bundle,
+// and imports such a bundle into another cluster after validating it.
+package configbundle
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Bundle identifiers
+const (
+	BundleAPIVersion = "workspace.jupyter.org/v1alpha1"
+	BundleKind       = "ConfigBundle"
+)
+
+// lastAppliedAnnotation is set by kubectl apply and is specific to the source cluster
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// operatorManagedLabels are stamped by the operator webhooks and recomputed on import
+var operatorManagedLabels = []string{
+	workspaceutil.LabelAccessStrategyName,
+	workspaceutil.LabelAccessStrategyNamespace,
+}
+
+// Bundle is the normalized operator configuration of a cluster
+type Bundle struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Operator holds the flags of the controller manager, for comparison between clusters
+	Operator *OperatorConfig `json:"operator,omitempty"`
+
+	// AccessStrategies are applied before Templates, which may reference them
+	AccessStrategies []Object[workspacev1alpha1.WorkspaceAccessStrategySpec] `json:"accessStrategies,omitempty"`
+
+	Templates []Object[workspacev1alpha1.WorkspaceTemplateSpec] `json:"templates,omitempty"`
+}
+
+// OperatorConfig is the configuration of the controller manager deployment
+type OperatorConfig struct {
+	// Args maps each manager flag to its value; boolean flags set without a value map to "true"
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// Object is a namespaced resource stripped of cluster-specific metadata and status
+type Object[T any] struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     T        `json:"spec"`
+}
+
+// Metadata is the portable subset of a resource's ObjectMeta
+type Metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NewBundle returns an empty bundle
+func NewBundle() *Bundle {
+	return &Bundle{
+		APIVersion: BundleAPIVersion,
+		Kind:       BundleKind,
+	}
+}
+
+// Marshal serializes the bundle to YAML, sorting objects so that the output is stable
+func Marshal(bundle *Bundle) ([]byte, error) {
+	sortObjects(bundle.AccessStrategies)
+	sortObjects(bundle.Templates)
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	return data, nil
+}
+
+// Parse deserializes and validates a YAML bundle; unknown fields are rejected
+func Parse(data []byte) (*Bundle, error) {
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict(data, bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if err := Validate(bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Validate checks that the bundle is well-formed before it is applied
+func Validate(bundle *Bundle) error {
+	if bundle.APIVersion != BundleAPIVersion || bundle.Kind != BundleKind {
+		return fmt.Errorf("unsupported bundle %s/%s, expected %s/%s",
+			bundle.APIVersion, bundle.Kind, BundleAPIVersion, BundleKind)
+	}
+	if err := validateObjects("access strategy", bundle.AccessStrategies); err != nil {
+		return err
+	}
+	return validateObjects("template", bundle.Templates)
+}
+
+// validateObjects checks that every object is named, namespaced and unique
+func validateObjects[T any](kind string, objects []Object[T]) error {
+	seen := make(map[string]bool, len(objects))
+	for i, object := range objects {
+		if object.Metadata.Name == "" || object.Metadata.Namespace == "" {
+			return fmt.Errorf("%s at index %d must set metadata.name and metadata.namespace", kind, i)
+		}
+		key := object.Metadata.Namespace + "/" + object.Metadata.Name
+		if seen[key] {
+			return fmt.Errorf("duplicate %s %s", kind, key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// sortObjects orders objects by namespace, then name
+func sortObjects[T any](objects []Object[T]) {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Metadata.Namespace != objects[j].Metadata.Namespace {
+			return objects[i].Metadata.Namespace < objects[j].Metadata.Namespace
+		}
+		return objects[i].Metadata.Name < objects[j].Metadata.Name
+	})
+}
+
+// normalizeLabels drops the labels the operator manages itself
+func normalizeLabels(labels map[string]string) map[string]string {
+	normalized := map[string]string{}
+	for key, value := range labels {
+		normalized[key] = value
+	}
+	for _, key := range operatorManagedLabels {
+		delete(normalized, key)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+// normalizeAnnotations drops the annotations that are specific to the source cluster
+func normalizeAnnotations(annotations map[string]string) map[string]string {
+	normalized := map[string]string{}
+	for key, value := range annotations {
+		if key != lastAppliedAnnotation {
+			normalized[key] = value
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
@@ -0,0 +1,259 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package configbundle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	testNamespace        = "team-a"
+	testSharedNamespace  = "jupyter-k8s-shared"
+	testOperatorNs       = "jupyter-k8s-system"
+	testStrategyName     = "web-access"
+	testTemplateName     = "python"
+	testProtectFinalizer = "workspace.jupyter.org/protection"
+)
+
+// getTestClient creates a fake controller-runtime client for testing
+func getTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = workspacev1alpha1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newTestTemplate(namespace, name, image string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"team":                                "data",
+				workspaceutil.LabelAccessStrategyName: testStrategyName,
+				workspaceutil.LabelAccessStrategyNamespace: testSharedNamespace,
+			},
+			Annotations: map[string]string{lastAppliedAnnotation: "{}"},
+		},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  name,
+			DefaultImage: image,
+		},
+	}
+}
+
+func newTestStrategy(namespace, name string) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       workspacev1alpha1.WorkspaceAccessStrategySpec{DisplayName: name},
+	}
+}
+
+func newTestManagerDeployment(args ...string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jupyter-k8s-controller-manager",
+			Namespace: testOperatorNs,
+			Labels:    map[string]string{managerDeploymentLabel: managerDeploymentValue},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: managerContainerName, Args: args}},
+				},
+			},
+		},
+	}
+}
+
+func TestExportNormalizesAndSortsObjects(t *testing.T) {
+	c := getTestClient(
+		newTestTemplate(testNamespace, "r", "r:1"),
+		newTestTemplate(testSharedNamespace, testTemplateName, "python:1"),
+		newTestStrategy(testSharedNamespace, testStrategyName),
+		newTestManagerDeployment("--leader-elect", "--default-template-namespace=jupyter-k8s-shared"),
+	)
+
+	bundle, err := Export(context.Background(), c, ExportOptions{OperatorNamespace: testOperatorNs})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := Marshal(bundle)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	if len(bundle.Templates) != 2 || bundle.Templates[0].Metadata.Namespace != testSharedNamespace {
+		t.Errorf("Expected templates sorted by namespace, got %+v", bundle.Templates)
+	}
+	labels := bundle.Templates[0].Metadata.Labels
+	if labels["team"] != "data" || len(labels) != 1 {
+		t.Errorf("Expected operator-managed labels to be dropped, got %v", labels)
+	}
+	if bundle.Templates[0].Metadata.Annotations != nil {
+		t.Errorf("Expected last-applied annotation to be dropped, got %v", bundle.Templates[0].Metadata.Annotations)
+	}
+	if bundle.Operator.Args["leader-elect"] != "true" ||
+		bundle.Operator.Args["default-template-namespace"] != testSharedNamespace {
+		t.Errorf("Unexpected operator args: %v", bundle.Operator.Args)
+	}
+	for _, field := range []string{"resourceVersion", "creationTimestamp", "status"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("Expected %s to be stripped from the bundle:\n%s", field, data)
+		}
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse of exported bundle failed: %v", err)
+	}
+	if len(parsed.Templates) != 2 || len(parsed.AccessStrategies) != 1 {
+		t.Errorf("Round trip lost objects: %+v", parsed)
+	}
+}
+
+func TestExportRestrictsNamespaces(t *testing.T) {
+	c := getTestClient(
+		newTestTemplate(testNamespace, "r", "r:1"),
+		newTestTemplate(testSharedNamespace, testTemplateName, "python:1"),
+	)
+
+	bundle, err := Export(context.Background(), c, ExportOptions{Namespaces: []string{testNamespace}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(bundle.Templates) != 1 || bundle.Templates[0].Metadata.Namespace != testNamespace {
+		t.Errorf("Expected only templates of %s, got %+v", testNamespace, bundle.Templates)
+	}
+	if bundle.Operator != nil {
+		t.Errorf("Expected no operator config without an operator namespace")
+	}
+}
+
+func TestParseRejectsInvalidBundles(t *testing.T) {
+	tests := map[string]string{
+		"wrong kind":    "apiVersion: workspace.jupyter.org/v1alpha1\nkind: Other\n",
+		"unknown field": "apiVersion: workspace.jupyter.org/v1alpha1\nkind: ConfigBundle\nextra: true\n",
+		"missing namespace": "apiVersion: workspace.jupyter.org/v1alpha1\nkind: ConfigBundle\n" +
+			"templates:\n- metadata:\n    name: a\n  spec:\n    displayName: a\n",
+		"duplicate": "apiVersion: workspace.jupyter.org/v1alpha1\nkind: ConfigBundle\n" +
+			"templates:\n- metadata: {name: a, namespace: b}\n  spec: {displayName: a}\n" +
+			"- metadata: {name: a, namespace: b}\n  spec: {displayName: a}\n",
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected Parse to fail", name)
+		}
+	}
+}
+
+func TestImportCreatesUpdatesAndPreservesFinalizers(t *testing.T) {
+	existing := newTestStrategy(testSharedNamespace, testStrategyName)
+	existing.Finalizers = []string{testProtectFinalizer}
+	existing.Labels = map[string]string{"kept": "true"}
+	c := getTestClient(existing)
+
+	bundle := NewBundle()
+	bundle.AccessStrategies = []Object[workspacev1alpha1.WorkspaceAccessStrategySpec]{{
+		Metadata: Metadata{Name: testStrategyName, Namespace: testSharedNamespace, Labels: map[string]string{"env": "prod"}},
+		Spec:     workspacev1alpha1.WorkspaceAccessStrategySpec{DisplayName: "Web access"},
+	}}
+	bundle.Templates = []Object[workspacev1alpha1.WorkspaceTemplateSpec]{{
+		Metadata: Metadata{Name: testTemplateName, Namespace: testSharedNamespace},
+		Spec:     workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: testTemplateName, DefaultImage: "python:2"},
+	}}
+
+	result, err := Import(context.Background(), c, bundle, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Actions["WorkspaceAccessStrategy jupyter-k8s-shared/web-access"] != ActionUpdated {
+		t.Errorf("Expected strategy to be updated, got %v", result.Actions)
+	}
+	if result.Actions["WorkspaceTemplate jupyter-k8s-shared/python"] != ActionCreated {
+		t.Errorf("Expected template to be created, got %v", result.Actions)
+	}
+
+	strategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(existing), strategy); err != nil {
+		t.Fatalf("Failed to get strategy: %v", err)
+	}
+	if strategy.Spec.DisplayName != "Web access" {
+		t.Errorf("Expected spec to be replaced, got %q", strategy.Spec.DisplayName)
+	}
+	if len(strategy.Finalizers) != 1 || strategy.Labels["kept"] != "true" || strategy.Labels["env"] != "prod" {
+		t.Errorf("Expected finalizers and labels to be merged, got %v %v", strategy.Finalizers, strategy.Labels)
+	}
+
+	result, err = Import(context.Background(), c, bundle, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Second import failed: %v", err)
+	}
+	for key, action := range result.Actions {
+		if action != ActionUnchanged {
+			t.Errorf("Expected %s to be unchanged on re-import, got %s", key, action)
+		}
+	}
+}
+
+func TestImportDryRunDoesNotPersist(t *testing.T) {
+	c := getTestClient()
+	bundle := NewBundle()
+	bundle.Templates = []Object[workspacev1alpha1.WorkspaceTemplateSpec]{{
+		Metadata: Metadata{Name: testTemplateName, Namespace: testNamespace},
+		Spec:     workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: testTemplateName},
+	}}
+
+	result, err := Import(context.Background(), c, bundle, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Actions["WorkspaceTemplate team-a/python"] != ActionCreated {
+		t.Errorf("Expected dry run to report a creation, got %v", result.Actions)
+	}
+
+	templates := &workspacev1alpha1.WorkspaceTemplateList{}
+	if err := c.List(context.Background(), templates); err != nil {
+		t.Fatalf("Failed to list templates: %v", err)
+	}
+	if len(templates.Items) != 0 {
+		t.Errorf("Expected dry run not to create templates, got %d", len(templates.Items))
+	}
+}
+
+func TestImportReportsOperatorDrift(t *testing.T) {
+	c := getTestClient(newTestManagerDeployment("--leader-elect", "--default-template-namespace=other"))
+	bundle := NewBundle()
+	bundle.Operator = &OperatorConfig{Args: map[string]string{
+		"default-template-namespace": testSharedNamespace,
+		"watch-traefik":              "true",
+	}}
+
+	result, err := Import(context.Background(), c, bundle, ImportOptions{OperatorNamespace: testOperatorNs})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	expected := []string{
+		`--default-template-namespace: "other", expected "jupyter-k8s-shared"`,
+		`--leader-elect: "true" is not in the bundle`,
+		`--watch-traefik: "true" is not set`,
+	}
+	if strings.Join(result.OperatorDrift, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected drift:\n%s", strings.Join(result.OperatorDrift, "\n"))
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package configbundle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Controller manager deployment lookup
+const (
+	managerDeploymentLabel = "control-plane"
+	managerDeploymentValue = "controller-manager"
+	managerContainerName   = "manager"
+)
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Namespaces restricts the export to these namespaces; empty exports all namespaces
+	Namespaces []string
+
+	// OperatorNamespace is the namespace of the controller manager; empty skips the operator config
+	OperatorNamespace string
+}
+
+// Export reads the templates, access strategies and operator config of a cluster into a bundle
+func Export(ctx context.Context, c client.Reader, opts ExportOptions) (*Bundle, error) {
+	bundle := NewBundle()
+
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	for _, namespace := range namespaces {
+		strategies := &workspacev1alpha1.WorkspaceAccessStrategyList{}
+		if err := c.List(ctx, strategies, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list access strategies: %w", err)
+		}
+		for i := range strategies.Items {
+			strategy := &strategies.Items[i]
+			if !strategy.DeletionTimestamp.IsZero() {
+				continue
+			}
+			bundle.AccessStrategies = append(bundle.AccessStrategies, Object[workspacev1alpha1.WorkspaceAccessStrategySpec]{
+				Metadata: exportMetadata(strategy),
+				Spec:     strategy.Spec,
+			})
+		}
+
+		templates := &workspacev1alpha1.WorkspaceTemplateList{}
+		if err := c.List(ctx, templates, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list templates: %w", err)
+		}
+		for i := range templates.Items {
+			template := &templates.Items[i]
+			if !template.DeletionTimestamp.IsZero() {
+				continue
+			}
+			bundle.Templates = append(bundle.Templates, Object[workspacev1alpha1.WorkspaceTemplateSpec]{
+				Metadata: exportMetadata(template),
+				Spec:     template.Spec,
+			})
+		}
+	}
+
+	if opts.OperatorNamespace != "" {
+		operator, err := ReadOperatorConfig(ctx, c, opts.OperatorNamespace)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Operator = operator
+	}
+	return bundle, nil
+}
+
+// ReadOperatorConfig reads the flags of the controller manager deployment in the namespace
+func ReadOperatorConfig(ctx context.Context, c client.Reader, namespace string) (*OperatorConfig, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments,
+		client.InNamespace(namespace),
+		client.MatchingLabels{managerDeploymentLabel: managerDeploymentValue},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list controller manager deployments: %w", err)
+	}
+	if len(deployments.Items) != 1 {
+		return nil, fmt.Errorf("expected one controller manager deployment in namespace %s, found %d",
+			namespace, len(deployments.Items))
+	}
+
+	for _, container := range deployments.Items[0].Spec.Template.Spec.Containers {
+		if container.Name == managerContainerName {
+			return &OperatorConfig{Args: parseArgs(container.Args)}, nil
+		}
+	}
+	return nil, fmt.Errorf("controller manager deployment %s/%s has no %s container",
+		namespace, deployments.Items[0].Name, managerContainerName)
+}
+
+// parseArgs maps --flag=value arguments to their values; arguments that are not flags are ignored
+func parseArgs(args []string) map[string]string {
+	parsed := map[string]string{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, found := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !found {
+			value = "true"
+		}
+		parsed[name] = value
+	}
+	return parsed
+}
+
+// exportMetadata keeps the portable subset of an object's metadata
+func exportMetadata(obj client.Object) Metadata {
+	return Metadata{
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		Labels:      normalizeLabels(obj.GetLabels()),
+		Annotations: normalizeAnnotations(obj.GetAnnotations()),
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package configbundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Action is what an import does to an object
+type Action string
+
+// Import actions
+const (
+	ActionCreated   Action = "created"
+	ActionUpdated   Action = "updated"
+	ActionUnchanged Action = "unchanged"
+)
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	// DryRun validates the bundle against the API server without persisting any change
+	DryRun bool
+
+	// OperatorNamespace is the namespace of the target controller manager; when set,
+	// differences with the bundle's operator config are reported in ImportResult.OperatorDrift
+	OperatorNamespace string
+}
+
+// ImportResult reports the outcome of an import
+type ImportResult struct {
+	// Actions maps "<kind> <namespace>/<name>" to the action taken
+	Actions map[string]Action
+
+	// OperatorDrift lists the manager flags that differ between the bundle and the target cluster.
+	// The operator config is owned by the Helm release and is never applied by Import.
+	OperatorDrift []string
+}
+
+// Import validates every object of the bundle with a server-side dry-run, so that CRD schemas
+// and admission webhooks run, and only applies the bundle if all of them pass.
+// Access strategies are applied before templates. Labels and annotations from the bundle are
+// merged into existing objects, and specs are replaced.
+func Import(ctx context.Context, c client.Client, bundle *Bundle, opts ImportOptions) (*ImportResult, error) {
+	if err := Validate(bundle); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Actions: map[string]Action{}}
+	if errs := applyBundle(ctx, c, bundle, true, result.Actions); len(errs) > 0 {
+		return nil, fmt.Errorf("bundle failed validation, nothing was applied: %w", errors.Join(errs...))
+	}
+
+	if !opts.DryRun {
+		result.Actions = map[string]Action{}
+		if errs := applyBundle(ctx, c, bundle, false, result.Actions); len(errs) > 0 {
+			return result, fmt.Errorf("failed to apply bundle: %w", errors.Join(errs...))
+		}
+	}
+
+	if opts.OperatorNamespace != "" && bundle.Operator != nil {
+		target, err := ReadOperatorConfig(ctx, c, opts.OperatorNamespace)
+		if err != nil {
+			return result, err
+		}
+		result.OperatorDrift = DiffOperatorConfig(bundle.Operator, target)
+	}
+	return result, nil
+}
+
+// DiffOperatorConfig describes the flags that differ between the desired and actual operator config
+func DiffOperatorConfig(desired, actual *OperatorConfig) []string {
+	var drift []string
+	for name, value := range desired.Args {
+		actualValue, found := actual.Args[name]
+		switch {
+		case !found:
+			drift = append(drift, fmt.Sprintf("--%s: %q is not set", name, value))
+		case actualValue != value:
+			drift = append(drift, fmt.Sprintf("--%s: %q, expected %q", name, actualValue, value))
+		}
+	}
+	for name, value := range actual.Args {
+		if _, found := desired.Args[name]; !found {
+			drift = append(drift, fmt.Sprintf("--%s: %q is not in the bundle", name, value))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// applyBundle applies every object of the bundle, records the action taken for each object
+// and returns one error per failed object
+func applyBundle(ctx context.Context, c client.Client, bundle *Bundle, dryRun bool, actions map[string]Action) []error {
+	var errs []error
+	record := func(kind string, meta Metadata, action Action, err error) {
+		key := fmt.Sprintf("%s %s/%s", kind, meta.Namespace, meta.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		actions[key] = action
+	}
+
+	for _, object := range bundle.AccessStrategies {
+		desired := &workspacev1alpha1.WorkspaceAccessStrategy{
+			ObjectMeta: importMetadata(object.Metadata),
+			Spec:       object.Spec,
+		}
+		action, err := applyObject(ctx, c, desired, &workspacev1alpha1.WorkspaceAccessStrategy{}, dryRun,
+			func(existing client.Object) {
+				existing.(*workspacev1alpha1.WorkspaceAccessStrategy).Spec = object.Spec
+			})
+		record("WorkspaceAccessStrategy", object.Metadata, action, err)
+	}
+
+	for _, object := range bundle.Templates {
+		desired := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: importMetadata(object.Metadata),
+			Spec:       object.Spec,
+		}
+		action, err := applyObject(ctx, c, desired, &workspacev1alpha1.WorkspaceTemplate{}, dryRun,
+			func(existing client.Object) {
+				existing.(*workspacev1alpha1.WorkspaceTemplate).Spec = object.Spec
+			})
+		record("WorkspaceTemplate", object.Metadata, action, err)
+	}
+	return errs
+}
+
+// applyObject creates desired, or updates the existing object with its spec, labels and annotations.
+// existing must be an empty object of the same type as desired.
+func applyObject(
+	ctx context.Context,
+	c client.Client,
+	desired, existing client.Object,
+	dryRun bool,
+	setSpec func(existing client.Object),
+) (Action, error) {
+	var createOpts []client.CreateOption
+	var updateOpts []client.UpdateOption
+	if dryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		if err := c.Create(ctx, desired, createOpts...); err != nil {
+			return "", err
+		}
+		return ActionCreated, nil
+	}
+
+	updated := existing.DeepCopyObject().(client.Object)
+	setSpec(updated)
+	updated.SetLabels(mergeMaps(updated.GetLabels(), desired.GetLabels()))
+	updated.SetAnnotations(mergeMaps(updated.GetAnnotations(), desired.GetAnnotations()))
+	if equality.Semantic.DeepEqual(existing, updated) {
+		return ActionUnchanged, nil
+	}
+	if err := c.Update(ctx, updated, updateOpts...); err != nil {
+		return "", err
+	}
+	return ActionUpdated, nil
+}
+
+// importMetadata converts bundle metadata to an ObjectMeta
+func importMetadata(meta Metadata) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+// mergeMaps returns base overridden by overrides, or base if there is nothing to override
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
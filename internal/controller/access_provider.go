@@ -0,0 +1,224 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Names of the built-in access providers
+const (
+	// AccessProviderTemplate renders the access strategy's accessResourceTemplates as-is.
+	// It is used when the access strategy does not name a provider.
+	AccessProviderTemplate = "template"
+	// AccessProviderTraefik routes traffic with Traefik IngressRoute and Middleware resources
+	AccessProviderTraefik = "traefik"
+	// AccessProviderIngress routes traffic with networking.k8s.io Ingress resources
+	AccessProviderIngress = "ingress"
+	// AccessProviderGatewayAPI routes traffic with Gateway API HTTPRoute resources
+	AccessProviderGatewayAPI = "gateway-api"
+	// AccessProviderIstio routes traffic with Istio VirtualService resources
+	AccessProviderIstio = "istio"
+	// AccessProviderSSH exposes an SSH server running in the workspace pod with a Service
+	AccessProviderSSH = "ssh"
+)
+
+// AccessProvider builds the routing resources of a workspace for one ingress implementation.
+// The provider is selected by WorkspaceAccessStrategy.spec.provider; additional providers
+// can be compiled in with RegisterAccessProvider.
+type AccessProvider interface {
+	// Name is the identifier the access strategy uses to select this provider
+	Name() string
+
+	// BuildResources returns the access resources of the workspace. The resources must be
+	// namespaced in the workspace namespace so that the workspace can own them.
+	BuildResources(
+		workspace *workspacev1alpha1.Workspace,
+		accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+		service *corev1.Service,
+	) ([]*unstructured.Unstructured, error)
+
+	// AccessURL returns the URL at which the workspace is served, or "" when the provider cannot tell
+	AccessURL(
+		workspace *workspacev1alpha1.Workspace,
+		accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+		service *corev1.Service,
+	) (string, error)
+
+	// WatchedGVKs lists the kinds of the resources built by the provider, which the workspace
+	// controller watches when the provider is enabled
+	WatchedGVKs() []schema.GroupVersionKind
+
+	// ResourceReady tells whether an access resource, as read from the cluster, is ready to serve
+	// the workspace, e.g. whether an Ingress was assigned an address, and if not, what it waits for.
+	// The workspace only becomes available once all of its access resources are ready.
+	ResourceReady(obj *unstructured.Unstructured) (bool, string)
+}
+
+// AccessProviderRegistry maps provider names to access providers
+type AccessProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]AccessProvider
+}
+
+// NewAccessProviderRegistry creates a registry holding the given providers
+func NewAccessProviderRegistry(providers ...AccessProvider) (*AccessProviderRegistry, error) {
+	registry := &AccessProviderRegistry{providers: map[string]AccessProvider{}}
+	for _, provider := range providers {
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register adds a provider to the registry. Provider names must be unique.
+func (r *AccessProviderRegistry) Register(provider AccessProvider) error {
+	name := provider.Name()
+	if name == "" {
+		return fmt.Errorf("access provider name must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("access provider %q is already registered", name)
+	}
+	r.providers[name] = provider
+	return nil
+}
+
+// Get returns the provider registered under name. An empty name selects AccessProviderTemplate.
+func (r *AccessProviderRegistry) Get(name string) (AccessProvider, error) {
+	if name == "" {
+		name = AccessProviderTemplate
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown access provider %q", name)
+	}
+	return provider, nil
+}
+
+// Names returns the sorted names of the registered providers
+func (r *AccessProviderRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultAccessProviders is the registry used by the workspace controller
+var defaultAccessProviders = newBuiltinAccessProviderRegistry()
+
+// DefaultAccessProviders returns the registry used by the workspace controller,
+// holding the built-in providers and any provider added with RegisterAccessProvider
+func DefaultAccessProviders() *AccessProviderRegistry {
+	return defaultAccessProviders
+}
+
+// RegisterAccessProvider compiles a third-party provider into the workspace controller.
+// Call it from an init function, before the controller is set up.
+func RegisterAccessProvider(provider AccessProvider) error {
+	return defaultAccessProviders.Register(provider)
+}
+
+// newBuiltinAccessProviderRegistry creates a registry holding the built-in providers
+func newBuiltinAccessProviderRegistry() *AccessProviderRegistry {
+	builder := NewAccessResourcesBuilder()
+	registry, err := NewAccessProviderRegistry(
+		NewTemplateAccessProvider(AccessProviderTemplate, builder),
+		NewTraefikAccessProvider(builder),
+		NewTemplateAccessProvider(AccessProviderIngress, builder,
+			schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}),
+		NewTemplateAccessProvider(AccessProviderGatewayAPI, builder,
+			schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}),
+		NewTemplateAccessProvider(AccessProviderIstio, builder,
+			schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1", Kind: "VirtualService"}),
+		NewSSHAccessProvider(builder),
+	)
+	if err != nil {
+		// The built-in provider names are distinct constants
+		panic(err)
+	}
+	return registry
+}
+
+// TemplateAccessProvider builds access resources by rendering the access strategy's
+// accessResourceTemplates, and the access URL from its accessURLTemplate
+type TemplateAccessProvider struct {
+	name        string
+	builder     *AccessResourcesBuilder
+	watchedGVKs []schema.GroupVersionKind
+}
+
+// NewTemplateAccessProvider creates a template-based provider that watches the given kinds
+func NewTemplateAccessProvider(
+	name string,
+	builder *AccessResourcesBuilder,
+	watchedGVKs ...schema.GroupVersionKind,
+) *TemplateAccessProvider {
+	return &TemplateAccessProvider{
+		name:        name,
+		builder:     builder,
+		watchedGVKs: watchedGVKs,
+	}
+}
+
+// Name returns the provider name
+func (p *TemplateAccessProvider) Name() string {
+	return p.name
+}
+
+// BuildResources renders each of the access strategy's accessResourceTemplates
+func (p *TemplateAccessProvider) BuildResources(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) ([]*unstructured.Unstructured, error) {
+	resources := make([]*unstructured.Unstructured, 0, len(accessStrategy.Spec.AccessResourceTemplates))
+	for _, resourceTemplate := range accessStrategy.Spec.AccessResourceTemplates {
+		obj, err := p.builder.BuildUnstructuredResource(resourceTemplate, workspace, accessStrategy, service)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, obj)
+	}
+	return resources, nil
+}
+
+// AccessURL resolves the access strategy's accessURLTemplate
+func (p *TemplateAccessProvider) AccessURL(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) (string, error) {
+	return p.builder.ResolveAccessURL(workspace, accessStrategy, service)
+}
+
+// WatchedGVKs returns the kinds the provider builds
+func (p *TemplateAccessProvider) WatchedGVKs() []schema.GroupVersionKind {
+	return p.watchedGVKs
+}
+
+// ResourceReady checks the status of the resource with the readiness rules of the built-in kinds
+func (p *TemplateAccessProvider) ResourceReady(obj *unstructured.Unstructured) (bool, string) {
+	return accessResourceReady(obj)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SSHAccessProvider exposes an SSH server running in the workspace pod, either in a sidecar
+// or in the workspace image. It builds a Secret holding the keys of the workspace spec.sshPublicKeys
+// and a LoadBalancer or NodePort Service, in addition to the access strategy's accessResourceTemplates.
+// The deployment side (sidecar, volume, environment) is applied by the DeploymentBuilder.
+type SSHAccessProvider struct {
+	*TemplateAccessProvider
+}
+
+// NewSSHAccessProvider creates the "ssh" access provider
+func NewSSHAccessProvider(builder *AccessResourcesBuilder) *SSHAccessProvider {
+	return &SSHAccessProvider{
+		TemplateAccessProvider: NewTemplateAccessProvider(AccessProviderSSH, builder),
+	}
+}
+
+// BuildResources returns the SSH keys Secret and Service, followed by the rendered accessResourceTemplates
+func (p *SSHAccessProvider) BuildResources(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) ([]*unstructured.Unstructured, error) {
+	secret, err := toUnstructuredResource(newSSHKeysSecret(workspace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SSH keys secret: %w", err)
+	}
+	sshService, err := toUnstructuredResource(newSSHService(workspace, accessStrategy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SSH service: %w", err)
+	}
+
+	templated, err := p.TemplateAccessProvider.BuildResources(workspace, accessStrategy, service)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*unstructured.Unstructured{secret, sshService}, templated...), nil
+}
+
+// newSSHKeysSecret builds the Secret holding the authorized_keys file of the workspace
+func newSSHKeysSecret(workspace *workspacev1alpha1.Workspace) *corev1.Secret {
+	var authorizedKeys strings.Builder
+	for _, key := range workspace.Spec.SSHPublicKeys {
+		authorizedKeys.WriteString(strings.TrimSpace(key))
+		authorizedKeys.WriteString("\n")
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateSSHKeysSecretName(workspace),
+			Namespace: workspace.Namespace,
+			Labels:    GenerateLabels(workspace.Name),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			SSHAuthorizedKeysFileName: []byte(authorizedKeys.String()),
+		},
+	}
+}
+
+// newSSHService builds the Service exposing the SSH server of the workspace outside of the cluster
+func newSSHService(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) *corev1.Service {
+	serviceType := corev1.ServiceTypeLoadBalancer
+	var annotations map[string]string
+	if ssh := accessStrategy.Spec.SSH; ssh != nil {
+		if ssh.ServiceType != "" {
+			serviceType = ssh.ServiceType
+		}
+		annotations = ssh.ServiceAnnotations
+	}
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        GenerateSSHServiceName(workspace),
+			Namespace:   workspace.Namespace,
+			Labels:      GenerateLabels(workspace.Name),
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: GenerateLabels(workspace.Name),
+			Ports: []corev1.ServicePort{{
+				Name:       "ssh",
+				Port:       SSHServicePort,
+				TargetPort: intstr.FromInt32(sshPort(accessStrategy)),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// sshPort returns the port on which the SSH server listens in the workspace pod
+func sshPort(accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) int32 {
+	if accessStrategy.Spec.SSH != nil && accessStrategy.Spec.SSH.Port > 0 {
+		return accessStrategy.Spec.SSH.Port
+	}
+	return DefaultSSHPort
+}
+
+// toUnstructuredResource converts a typed object to an access resource
+func toUnstructuredResource(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	resource := &unstructured.Unstructured{Object: content}
+	// drop the null creationTimestamp the converter adds, so that it does not show in updates
+	unstructured.RemoveNestedField(resource.Object, "metadata", "creationTimestamp")
+	return resource, nil
+}
+
+// WatchedGVKs returns no kinds: the workspace controller already watches its Services,
+// and the SSH keys Secret is only changed by the controller itself
+func (p *SSHAccessProvider) WatchedGVKs() []schema.GroupVersionKind {
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newSSHTestAccessStrategy(ssh *workspacev1alpha1.SSHAccess) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			Provider: AccessProviderSSH,
+			SSH:      ssh,
+		},
+	}
+}
+
+func newSSHTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:   "ws",
+			Image:         imageMinimalNotebook,
+			SSHPublicKeys: []string{"ssh-ed25519 AAAA alice", " ssh-rsa BBBB bob\n"},
+		},
+	}
+}
+
+func TestSSHAccessProviderBuildResources(t *testing.T) {
+	provider, err := DefaultAccessProviders().Get(AccessProviderSSH)
+	require.NoError(t, err)
+	workspace := newSSHTestWorkspace()
+	accessStrategy := newSSHTestAccessStrategy(&workspacev1alpha1.SSHAccess{
+		ServiceType:        corev1.ServiceTypeNodePort,
+		ServiceAnnotations: map[string]string{"example.com/lb": "internal"},
+		Port:               2022,
+	})
+
+	resources, err := provider.BuildResources(workspace, accessStrategy, nil)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[0].Object, secret))
+	assert.Equal(t, GenerateSSHKeysSecretName(workspace), secret.Name)
+	assert.Equal(t, "team-a", secret.Namespace)
+	assert.Equal(t, "ssh-ed25519 AAAA alice\nssh-rsa BBBB bob\n", string(secret.Data[SSHAuthorizedKeysFileName]))
+
+	service := &corev1.Service{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[1].Object, service))
+	assert.Equal(t, "Service", resources[1].GetKind())
+	assert.Equal(t, corev1.ServiceTypeNodePort, service.Spec.Type)
+	assert.Equal(t, "internal", service.Annotations["example.com/lb"])
+	assert.Equal(t, GenerateLabels("ws"), service.Spec.Selector)
+	require.Len(t, service.Spec.Ports, 1)
+	assert.Equal(t, int32(SSHServicePort), service.Spec.Ports[0].Port)
+	assert.Equal(t, int32(2022), service.Spec.Ports[0].TargetPort.IntVal)
+}
+
+func TestSSHAccessProviderDefaultsToLoadBalancer(t *testing.T) {
+	resources, err := NewSSHAccessProvider(NewAccessResourcesBuilder()).
+		BuildResources(newSSHTestWorkspace(), newSSHTestAccessStrategy(nil), nil)
+	require.NoError(t, err)
+
+	service := &corev1.Service{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[1].Object, service))
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+	assert.Equal(t, int32(DefaultSSHPort), service.Spec.Ports[0].TargetPort.IntVal)
+}
+
+func TestSSHAccessRunsInPrimaryContainerWithoutSidecarImage(t *testing.T) {
+	container := buildTestDeploymentContainer(t, newSSHTestWorkspace(), newSSHTestAccessStrategy(nil))
+
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: SSHPortEnvVar, Value: "2222"})
+	assert.Contains(t, container.Env, corev1.EnvVar{
+		Name: SSHAuthorizedKeysFileEnvVar, Value: SSHAuthorizedKeysMountPath + "/" + SSHAuthorizedKeysFileName})
+	assert.Contains(t, container.Ports, corev1.ContainerPort{Name: "ssh", ContainerPort: DefaultSSHPort, Protocol: corev1.ProtocolTCP})
+}
+
+func TestSSHAccessAddsSidecar(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil)
+	workspace := newSSHTestWorkspace()
+
+	deployment, err := builder.BuildDeploymentWithAccessStrategy(t.Context(), workspace,
+		newSSHTestAccessStrategy(&workspacev1alpha1.SSHAccess{SidecarImage: "example.com/sshd:1"}))
+	require.NoError(t, err)
+
+	podSpec := deployment.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 2)
+	sidecar := podSpec.Containers[1]
+	assert.Equal(t, SSHContainerName, sidecar.Name)
+	assert.Equal(t, "example.com/sshd:1", sidecar.Image)
+	assert.Contains(t, sidecar.VolumeMounts, corev1.VolumeMount{
+		Name: SSHAuthorizedKeysVolumeName, MountPath: SSHAuthorizedKeysMountPath, ReadOnly: true})
+	assert.NotContains(t, podSpec.Containers[0].Ports, corev1.ContainerPort{
+		Name: "ssh", ContainerPort: DefaultSSHPort, Protocol: corev1.ProtocolTCP})
+
+	var keysVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == SSHAuthorizedKeysVolumeName {
+			keysVolume = &podSpec.Volumes[i]
+		}
+	}
+	require.NotNil(t, keysVolume)
+	assert.Equal(t, GenerateSSHKeysSecretName(workspace), keysVolume.Secret.SecretName)
+	assert.True(t, *keysVolume.Secret.Optional)
+}
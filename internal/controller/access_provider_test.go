@@ -0,0 +1,184 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// stubAccessProvider builds a fixed list of resources
+type stubAccessProvider struct {
+	name      string
+	resources []*unstructured.Unstructured
+	gvks      []schema.GroupVersionKind
+	// notReady is what every resource waits for, or "" when resources are ready
+	notReady string
+}
+
+func (p *stubAccessProvider) Name() string { return p.name }
+
+func (p *stubAccessProvider) BuildResources(
+	_ *workspacev1alpha1.Workspace,
+	_ *workspacev1alpha1.WorkspaceAccessStrategy,
+	_ *corev1.Service,
+) ([]*unstructured.Unstructured, error) {
+	return p.resources, nil
+}
+
+func (p *stubAccessProvider) AccessURL(
+	_ *workspacev1alpha1.Workspace,
+	_ *workspacev1alpha1.WorkspaceAccessStrategy,
+	_ *corev1.Service,
+) (string, error) {
+	return "https://stub.example.com/", nil
+}
+
+func (p *stubAccessProvider) WatchedGVKs() []schema.GroupVersionKind { return p.gvks }
+
+func (p *stubAccessProvider) ResourceReady(_ *unstructured.Unstructured) (bool, string) {
+	return p.notReady == "", p.notReady
+}
+
+func TestAccessProviderRegistry(t *testing.T) {
+	registry, err := NewAccessProviderRegistry(&stubAccessProvider{name: "stub"})
+	require.NoError(t, err)
+
+	provider, err := registry.Get("stub")
+	require.NoError(t, err)
+	assert.Equal(t, "stub", provider.Name())
+
+	_, err = registry.Get("missing")
+	assert.ErrorContains(t, err, `unknown access provider "missing"`)
+
+	assert.ErrorContains(t, registry.Register(&stubAccessProvider{name: "stub"}), "already registered")
+	assert.Error(t, registry.Register(&stubAccessProvider{}))
+
+	require.NoError(t, registry.Register(&stubAccessProvider{name: "another"}))
+	assert.Equal(t, []string{"another", "stub"}, registry.Names())
+}
+
+func TestDefaultAccessProviders_Builtins(t *testing.T) {
+	registry := DefaultAccessProviders()
+
+	// An access strategy without a provider uses the template provider
+	provider, err := registry.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, AccessProviderTemplate, provider.Name())
+	assert.Empty(t, provider.WatchedGVKs())
+
+	for _, name := range []string{AccessProviderTraefik, AccessProviderIngress, AccessProviderGatewayAPI, AccessProviderIstio} {
+		provider, err := registry.Get(name)
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, provider.WatchedGVKs(), name)
+	}
+}
+
+func TestTemplateAccessProvider(t *testing.T) {
+	provider := NewTemplateAccessProvider("test", NewAccessResourcesBuilder())
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+	}
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "strategy", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessURLTemplate: "https://example.com/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/",
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{Kind: "HTTPRoute", ApiVersion: "gateway.networking.k8s.io/v1", NamePrefix: "route", Template: "spec: {}"},
+				{Kind: "Ingress", ApiVersion: "networking.k8s.io/v1", NamePrefix: "ingress", Template: "spec: {}"},
+			},
+		},
+	}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ws-service", Namespace: "team-a"}}
+
+	resources, err := provider.BuildResources(workspace, accessStrategy, service)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "route-ws", resources[0].GetName())
+	assert.Equal(t, "HTTPRoute", resources[0].GetKind())
+	assert.Equal(t, "ingress-ws", resources[1].GetName())
+	assert.Equal(t, "team-a", resources[1].GetNamespace())
+
+	url, err := provider.AccessURL(workspace, accessStrategy, service)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/team-a/ws/", url)
+}
+
+func TestAccessResourceWatches(t *testing.T) {
+	ingressGVK := schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+	registry, err := NewAccessProviderRegistry(
+		&stubAccessProvider{name: "stub", gvks: []schema.GroupVersionKind{ingressGVK}},
+		builtinAccessProvider(t, AccessProviderTraefik),
+	)
+	require.NoError(t, err)
+
+	gvks, err := accessResourceWatches(WorkspaceControllerOptions{
+		WatchTraefik:    true,
+		AccessProviders: []string{"stub"},
+		ResourceWatches: []GVKWatch{{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}},
+	}, registry)
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{
+		ingressGVK,
+		{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindIngressRoute},
+		{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindMiddleware},
+		{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindServersTransport},
+	}, gvks)
+
+	_, err = accessResourceWatches(WorkspaceControllerOptions{AccessProviders: []string{"missing"}}, registry)
+	assert.ErrorContains(t, err, `unknown access provider "missing"`)
+}
+
+// builtinAccessProvider returns a built-in provider from the default registry
+func builtinAccessProvider(t *testing.T, name string) AccessProvider {
+	provider, err := DefaultAccessProviders().Get(name)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestEnsureAccessResourcesExist_ProviderErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil)
+
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+	}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ws-service", Namespace: "team-a"}}
+
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "strategy"},
+		Spec:       workspacev1alpha1.WorkspaceAccessStrategySpec{Provider: "missing"},
+	}
+	err := rm.EnsureAccessResourcesExist(context.Background(), workspace, accessStrategy, service)
+	assert.ErrorContains(t, err, `unknown access provider "missing"`)
+
+	// Resources outside the workspace namespace cannot be owned by the workspace
+	foreign := &unstructured.Unstructured{}
+	foreign.SetAPIVersion("networking.k8s.io/v1")
+	foreign.SetKind("Ingress")
+	foreign.SetName("ingress-ws")
+	foreign.SetNamespace("other")
+	rm.accessProviders, err = NewAccessProviderRegistry(
+		&stubAccessProvider{name: "stub", resources: []*unstructured.Unstructured{foreign}})
+	require.NoError(t, err)
+
+	accessStrategy.Spec.Provider = "stub"
+	err = rm.EnsureAccessResourcesExist(context.Background(), workspace, accessStrategy, service)
+	assert.ErrorContains(t, err, "instead of the workspace namespace")
+	assert.Empty(t, workspace.Status.AccessResources)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TraefikAccessProvider routes traffic with the Traefik IngressRoute and Middleware resources of
+// the access strategy's accessResourceTemplates. It applies the access strategy spec.traefik options
+// to the IngressRoute services targeting the workspace Service: a sticky session cookie, and a
+// ServersTransport holding the forwarding timeouts.
+type TraefikAccessProvider struct {
+	*TemplateAccessProvider
+}
+
+// NewTraefikAccessProvider creates the "traefik" access provider
+func NewTraefikAccessProvider(builder *AccessResourcesBuilder) *TraefikAccessProvider {
+	return &TraefikAccessProvider{
+		TemplateAccessProvider: NewTemplateAccessProvider(AccessProviderTraefik, builder,
+			schema.GroupVersionKind{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindIngressRoute},
+			schema.GroupVersionKind{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindMiddleware},
+			schema.GroupVersionKind{Group: traefikAPIGroup, Version: traefikAPIGroupVersion, Kind: kindServersTransport}),
+	}
+}
+
+// BuildResources returns the rendered accessResourceTemplates, with the spec.traefik options
+// applied to their IngressRoutes, followed by the ServersTransport of the workspace if timeouts are set
+func (p *TraefikAccessProvider) BuildResources(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) ([]*unstructured.Unstructured, error) {
+	resources, err := p.TemplateAccessProvider.BuildResources(workspace, accessStrategy, service)
+	if err != nil {
+		return nil, err
+	}
+
+	options := accessStrategy.Spec.Traefik
+	if options == nil || service == nil {
+		return resources, nil
+	}
+
+	var transport *unstructured.Unstructured
+	if options.Timeouts != nil {
+		transport = newTraefikServersTransport(workspace, options.Timeouts)
+	}
+	for _, resource := range resources {
+		if resource.GetAPIVersion() != traefikAPIVersion || resource.GetKind() != kindIngressRoute {
+			continue
+		}
+		if err := applyTraefikServiceOptions(resource, service, options, transport); err != nil {
+			return nil, fmt.Errorf("failed to apply traefik options to IngressRoute %s: %w", resource.GetName(), err)
+		}
+	}
+	if transport != nil {
+		resources = append(resources, transport)
+	}
+	return resources, nil
+}
+
+// applyTraefikServiceOptions sets the sticky cookie and the ServersTransport of the services
+// of ingressRoute that target the workspace Service
+func applyTraefikServiceOptions(
+	ingressRoute *unstructured.Unstructured,
+	service *corev1.Service,
+	options *workspacev1alpha1.TraefikAccess,
+	transport *unstructured.Unstructured,
+) error {
+	routes, found, err := unstructured.NestedSlice(ingressRoute.Object, "spec", "routes")
+	if err != nil || !found {
+		return err
+	}
+
+	for i, route := range routes {
+		routeMap, ok := route.(map[string]any)
+		if !ok {
+			continue
+		}
+		services, found, err := unstructured.NestedSlice(routeMap, "services")
+		if err != nil {
+			return fmt.Errorf("spec.routes[%d].services: %w", i, err)
+		}
+		if !found {
+			continue
+		}
+		for j, routeService := range services {
+			serviceMap, ok := routeService.(map[string]any)
+			if !ok || !isWorkspaceServiceRef(serviceMap, service) {
+				continue
+			}
+			if options.StickySession != nil {
+				serviceMap["sticky"] = map[string]any{"cookie": traefikStickyCookie(options.StickySession)}
+			}
+			if transport != nil {
+				serviceMap["serversTransport"] = transport.GetName()
+			}
+			services[j] = serviceMap
+		}
+		routeMap["services"] = services
+		routes[i] = routeMap
+	}
+	return unstructured.SetNestedSlice(ingressRoute.Object, routes, "spec", "routes")
+}
+
+// isWorkspaceServiceRef returns whether an IngressRoute service targets the workspace Service.
+// The namespace of the reference defaults to the namespace of the IngressRoute, the workspace namespace.
+func isWorkspaceServiceRef(ref map[string]any, service *corev1.Service) bool {
+	if kind, _ := ref["kind"].(string); kind != "" && kind != "Service" {
+		return false
+	}
+	name, _ := ref["name"].(string)
+	namespace, _ := ref["namespace"].(string)
+	return name == service.Name && (namespace == "" || namespace == service.Namespace)
+}
+
+// traefikStickyCookie returns the sticky.cookie of an IngressRoute service
+func traefikStickyCookie(sticky *workspacev1alpha1.TraefikStickySession) map[string]any {
+	cookie := map[string]any{
+		"secure":   sticky.Secure,
+		"httpOnly": sticky.HTTPOnly,
+	}
+	if sticky.CookieName != "" {
+		cookie["name"] = sticky.CookieName
+	}
+	if sticky.SameSite != "" {
+		cookie["sameSite"] = sticky.SameSite
+	}
+	if sticky.MaxAge > 0 {
+		cookie["maxAge"] = int64(sticky.MaxAge)
+	}
+	return cookie
+}
+
+// newTraefikServersTransport builds the ServersTransport holding the forwarding timeouts of the workspace
+func newTraefikServersTransport(
+	workspace *workspacev1alpha1.Workspace,
+	timeouts *workspacev1alpha1.TraefikTimeouts,
+) *unstructured.Unstructured {
+	forwardingTimeouts := map[string]any{}
+	if timeouts.DialTimeout != nil {
+		forwardingTimeouts["dialTimeout"] = timeouts.DialTimeout.Duration.String()
+	}
+	if timeouts.ResponseHeaderTimeout != nil {
+		forwardingTimeouts["responseHeaderTimeout"] = timeouts.ResponseHeaderTimeout.Duration.String()
+	}
+	if timeouts.IdleConnTimeout != nil {
+		forwardingTimeouts["idleConnTimeout"] = timeouts.IdleConnTimeout.Duration.String()
+	}
+
+	transport := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"forwardingTimeouts": forwardingTimeouts},
+	}}
+	transport.SetAPIVersion(traefikAPIVersion)
+	transport.SetKind(kindServersTransport)
+	transport.SetName(GenerateTraefikServersTransportName(workspace))
+	transport.SetNamespace(workspace.Namespace)
+	transport.SetLabels(GenerateLabels(workspace.Name))
+	return transport
+}
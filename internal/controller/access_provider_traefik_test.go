@@ -0,0 +1,156 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// traefikTestRouteTemplate routes to the workspace Service and to another service
+const traefikTestRouteTemplate = `spec:
+  routes:
+    - match: "PathPrefix(` + "`/{{ .Workspace.Name }}/`" + `)"
+      kind: Rule
+      services:
+        - name: "{{ .Service.Name }}"
+          namespace: "{{ .Service.Namespace }}"
+          port: {{ port .Ports "http" }}
+        - name: auth-service
+          port: 8080
+`
+
+func newTraefikTestAccessStrategy(traefik *workspacev1alpha1.TraefikAccess) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "traefik", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			Provider: AccessProviderTraefik,
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{Kind: kindIngressRoute, ApiVersion: traefikAPIVersion, NamePrefix: "route", Template: traefikTestRouteTemplate},
+				{Kind: kindMiddleware, ApiVersion: traefikAPIVersion, NamePrefix: "strip", Template: "spec: {}"},
+			},
+			Traefik: traefik,
+		},
+	}
+}
+
+func newTraefikTestService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-service", Namespace: "team-a"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: JupyterPort}}},
+	}
+}
+
+// traefikTestRouteServices returns the services of the first route of an IngressRoute
+func traefikTestRouteServices(t *testing.T, ingressRoute *unstructured.Unstructured) []map[string]any {
+	routes, _, err := unstructured.NestedSlice(ingressRoute.Object, "spec", "routes")
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	services, _, err := unstructured.NestedSlice(routes[0].(map[string]any), "services")
+	require.NoError(t, err)
+
+	result := make([]map[string]any, 0, len(services))
+	for _, service := range services {
+		result = append(result, service.(map[string]any))
+	}
+	return result
+}
+
+func TestTraefikAccessProviderWithoutOptions(t *testing.T) {
+	provider := builtinAccessProvider(t, AccessProviderTraefik)
+
+	resources, err := provider.BuildResources(newSSHTestWorkspace(), newTraefikTestAccessStrategy(nil), newTraefikTestService())
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	services := traefikTestRouteServices(t, resources[0])
+	require.Len(t, services, 2)
+	assert.Equal(t, int64(JupyterPort), services[0]["port"])
+	assert.NotContains(t, services[0], "sticky")
+	assert.NotContains(t, services[0], "serversTransport")
+}
+
+func TestTraefikAccessProviderAppliesOptions(t *testing.T) {
+	provider := NewTraefikAccessProvider(NewAccessResourcesBuilder())
+	workspace := newSSHTestWorkspace()
+	accessStrategy := newTraefikTestAccessStrategy(&workspacev1alpha1.TraefikAccess{
+		StickySession: &workspacev1alpha1.TraefikStickySession{
+			CookieName: "jupyter-affinity",
+			Secure:     true,
+			HTTPOnly:   true,
+			SameSite:   "lax",
+			MaxAge:     3600,
+		},
+		Timeouts: &workspacev1alpha1.TraefikTimeouts{
+			DialTimeout:     &metav1.Duration{Duration: 10 * time.Second},
+			IdleConnTimeout: &metav1.Duration{Duration: time.Hour},
+		},
+	})
+
+	resources, err := provider.BuildResources(workspace, accessStrategy, newTraefikTestService())
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	services := traefikTestRouteServices(t, resources[0])
+	require.Len(t, services, 2)
+	assert.Equal(t, map[string]any{"cookie": map[string]any{
+		"name":     "jupyter-affinity",
+		"secure":   true,
+		"httpOnly": true,
+		"sameSite": "lax",
+		"maxAge":   int64(3600),
+	}}, services[0]["sticky"])
+	assert.Equal(t, GenerateTraefikServersTransportName(workspace), services[0]["serversTransport"])
+
+	// Services other than the workspace Service are left as rendered
+	assert.NotContains(t, services[1], "sticky")
+	assert.NotContains(t, services[1], "serversTransport")
+
+	// The Middleware is not an IngressRoute
+	assert.NotContains(t, resources[1].Object["spec"], "routes")
+
+	transport := resources[2]
+	assert.Equal(t, kindServersTransport, transport.GetKind())
+	assert.Equal(t, traefikAPIVersion, transport.GetAPIVersion())
+	assert.Equal(t, GenerateTraefikServersTransportName(workspace), transport.GetName())
+	assert.Equal(t, "team-a", transport.GetNamespace())
+	forwardingTimeouts, _, err := unstructured.NestedStringMap(transport.Object, "spec", "forwardingTimeouts")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"dialTimeout": "10s", "idleConnTimeout": "1h0m0s"}, forwardingTimeouts)
+}
+
+func TestTraefikAccessProviderStickySessionOnly(t *testing.T) {
+	accessStrategy := newTraefikTestAccessStrategy(&workspacev1alpha1.TraefikAccess{
+		StickySession: &workspacev1alpha1.TraefikStickySession{},
+	})
+
+	resources, err := NewTraefikAccessProvider(NewAccessResourcesBuilder()).
+		BuildResources(newSSHTestWorkspace(), accessStrategy, newTraefikTestService())
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	services := traefikTestRouteServices(t, resources[0])
+	assert.Equal(t, map[string]any{"cookie": map[string]any{"secure": false, "httpOnly": false}}, services[0]["sticky"])
+	assert.NotContains(t, services[0], "serversTransport")
+}
+
+func TestIsWorkspaceServiceRef(t *testing.T) {
+	service := newTraefikTestService()
+
+	assert.True(t, isWorkspaceServiceRef(map[string]any{"name": "ws-service"}, service))
+	assert.True(t, isWorkspaceServiceRef(map[string]any{"name": "ws-service", "namespace": "team-a", "kind": "Service"}, service))
+	assert.False(t, isWorkspaceServiceRef(map[string]any{"name": "ws-service", "namespace": "team-b"}, service))
+	assert.False(t, isWorkspaceServiceRef(map[string]any{"name": "ws-service", "kind": "TraefikService"}, service))
+	assert.False(t, isWorkspaceServiceRef(map[string]any{"name": "other"}, service))
+}
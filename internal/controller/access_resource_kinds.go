@@ -0,0 +1,125 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// AccessResourceKinds collects the kinds of the access resources the AccessStrategies render, so
+// that the workspace controller watches them, and reconciles the drift of the access resources,
+// without listing them in --watch-resources-gvk.
+// The AccessStrategy controller records the kinds of each AccessStrategy, and the workspace
+// controller watches the kinds it does not watch yet whenever they change.
+type AccessResourceKinds struct {
+	providers *AccessProviderRegistry
+	changes   chan struct{}
+
+	mu               sync.Mutex
+	byAccessStrategy map[types.NamespacedName][]schema.GroupVersionKind
+}
+
+// NewAccessResourceKinds creates an AccessResourceKinds resolving the kinds of the built-in and
+// registered access providers
+func NewAccessResourceKinds() *AccessResourceKinds {
+	return &AccessResourceKinds{
+		providers:        DefaultAccessProviders(),
+		changes:          make(chan struct{}, 1),
+		byAccessStrategy: map[types.NamespacedName][]schema.GroupVersionKind{},
+	}
+}
+
+// record sets the kinds of the access resources of an AccessStrategy, and notifies the workspace
+// controller when they changed
+func (k *AccessResourceKinds) record(accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) {
+	gvks := k.accessStrategyKinds(accessStrategy)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key := types.NamespacedName{Namespace: accessStrategy.Namespace, Name: accessStrategy.Name}
+	if previous, exists := k.byAccessStrategy[key]; exists && slices.Equal(previous, gvks) {
+		return
+	}
+	k.byAccessStrategy[key] = gvks
+	k.notify()
+}
+
+// forget drops the kinds of a deleted AccessStrategy. The workspace controller keeps watching them.
+func (k *AccessResourceKinds) forget(accessStrategy types.NamespacedName) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.byAccessStrategy, accessStrategy)
+}
+
+// kinds returns the deduplicated kinds of the access resources of all the AccessStrategies, sorted
+func (k *AccessResourceKinds) kinds() []schema.GroupVersionKind {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	seen := map[schema.GroupVersionKind]bool{}
+	var gvks []schema.GroupVersionKind
+	for _, accessStrategyKinds := range k.byAccessStrategy {
+		for _, gvk := range accessStrategyKinds {
+			if !seen[gvk] {
+				seen[gvk] = true
+				gvks = append(gvks, gvk)
+			}
+		}
+	}
+	sortKinds(gvks)
+	return gvks
+}
+
+// changed returns the channel receiving a value once the kinds changed. Changes made while a value
+// is pending are coalesced into it.
+func (k *AccessResourceKinds) changed() <-chan struct{} {
+	return k.changes
+}
+
+// notify signals a change without blocking. The caller must hold the lock.
+func (k *AccessResourceKinds) notify() {
+	select {
+	case k.changes <- struct{}{}:
+	default:
+	}
+}
+
+// accessStrategyKinds returns the kinds of the access resources of an AccessStrategy: those of its
+// accessResourceTemplates, and those its provider builds
+func (k *AccessResourceKinds) accessStrategyKinds(
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) []schema.GroupVersionKind {
+	seen := map[schema.GroupVersionKind]bool{}
+	var gvks []schema.GroupVersionKind
+	add := func(gvk schema.GroupVersionKind) {
+		if gvk.Kind != "" && gvk.Version != "" && !seen[gvk] {
+			seen[gvk] = true
+			gvks = append(gvks, gvk)
+		}
+	}
+
+	for _, resourceTemplate := range accessStrategy.Spec.AccessResourceTemplates {
+		add(schema.FromAPIVersionAndKind(resourceTemplate.ApiVersion, resourceTemplate.Kind))
+	}
+	// Unknown providers fail the rendering of the workspaces, which report it
+	if provider, err := k.providers.Get(accessStrategy.Spec.Provider); err == nil {
+		for _, gvk := range provider.WatchedGVKs() {
+			add(gvk)
+		}
+	}
+	sortKinds(gvks)
+	return gvks
+}
+
+// sortKinds sorts resource kinds by their string form
+func sortKinds(gvks []schema.GroupVersionKind) {
+	sort.Slice(gvks, func(i, j int) bool { return gvks[i].String() < gvks[j].String() })
+}
@@ -0,0 +1,86 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newAccessResourceKindsTestStrategy(name string, templates ...workspacev1alpha1.AccessResourceTemplate) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			DisplayName:             name,
+			AccessResourceTemplates: templates,
+		},
+	}
+}
+
+func TestAccessResourceKindsRecordsTemplateKinds(t *testing.T) {
+	kinds := NewAccessResourceKinds()
+	kinds.record(newAccessResourceKindsTestStrategy("routes",
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "route.openshift.io/v1", Kind: "Route"},
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"},
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"},
+	))
+	kinds.record(newAccessResourceKindsTestStrategy("traefik",
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"},
+	))
+
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+		testIngressRouteGVK,
+	}, kinds.kinds())
+	assert.Len(t, kinds.changed(), 1, "changes are coalesced")
+}
+
+func TestAccessResourceKindsNotifiesOnlyChanges(t *testing.T) {
+	kinds := NewAccessResourceKinds()
+	accessStrategy := newAccessResourceKindsTestStrategy("traefik",
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"})
+	kinds.record(accessStrategy)
+	<-kinds.changed()
+
+	kinds.record(accessStrategy)
+	assert.Empty(t, kinds.changed(), "recording the same kinds again is not a change")
+
+	kinds.forget(client.ObjectKeyFromObject(accessStrategy))
+	assert.Empty(t, kinds.kinds())
+}
+
+func TestWorkspaceAccessStrategyReconciler_RecordsAccessResourceKinds(t *testing.T) {
+	accessStrategy := newAccessResourceKindsTestStrategy("traefik",
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"})
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	k8sClient := newIndexedClientBuilder(scheme).
+		WithObjects(accessStrategy).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceAccessStrategy{}).
+		Build()
+
+	kinds := NewAccessResourceKinds()
+	reconciler := &WorkspaceAccessStrategyReconciler{Client: k8sClient, Scheme: scheme, AccessResourceKinds: kinds}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(accessStrategy)}
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{testIngressRouteGVK}, kinds.kinds())
+
+	require.NoError(t, k8sClient.Delete(context.Background(), accessStrategy))
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, kinds.kinds())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// API groups of the access resources with built-in readiness rules
+const (
+	networkingAPIGroup = "networking.k8s.io"
+	gatewayAPIGroup    = "gateway.networking.k8s.io"
+)
+
+// conditionTypeReady is the condition through which resources such as cert-manager Certificates
+// report their readiness
+const conditionTypeReady = "Ready"
+
+// conditionTypeAccepted is the condition through which a Gateway accepts an HTTPRoute
+const conditionTypeAccepted = "Accepted"
+
+// accessResourceReady applies the readiness rules of the built-in kinds to an access resource:
+//   - an Ingress, or a Service of type LoadBalancer, is ready once it was assigned an address
+//   - an HTTPRoute is ready once all of its parent Gateways accepted it
+//   - any other resource is ready unless it has a Ready condition that is not True, as for
+//     cert-manager Certificates that are not issued yet
+//
+// Resources without a status, such as Secrets, ConfigMaps and Traefik IngressRoutes, are ready.
+func accessResourceReady(obj *unstructured.Unstructured) (bool, string) {
+	group := obj.GroupVersionKind().Group
+	switch {
+	case group == networkingAPIGroup && obj.GetKind() == "Ingress":
+		return loadBalancerAssigned(obj)
+	case group == "" && obj.GetKind() == "Service":
+		serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+		if serviceType != string(corev1.ServiceTypeLoadBalancer) {
+			return true, ""
+		}
+		return loadBalancerAssigned(obj)
+	case group == gatewayAPIGroup && obj.GetKind() == "HTTPRoute":
+		return routeAccepted(obj)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]any)
+		if !ok || condition["type"] != conditionTypeReady {
+			continue
+		}
+		if condition["status"] == string(corev1.ConditionTrue) {
+			return true, ""
+		}
+		if message, _ := condition["message"].(string); message != "" {
+			return false, message
+		}
+		return false, "waiting for its Ready condition"
+	}
+	return true, ""
+}
+
+// loadBalancerAssigned tells whether status.loadBalancer of an Ingress or Service holds an address
+func loadBalancerAssigned(obj *unstructured.Unstructured) (bool, string) {
+	ingresses, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	for _, item := range ingresses {
+		ingress, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if ingress["ip"] != nil || ingress["hostname"] != nil {
+			return true, ""
+		}
+	}
+	return false, "waiting for a load balancer address"
+}
+
+// routeAccepted tells whether every parent Gateway listed in the status of an HTTPRoute accepted it
+func routeAccepted(obj *unstructured.Unstructured) (bool, string) {
+	parents, _, _ := unstructured.NestedSlice(obj.Object, "status", "parents")
+	if len(parents) == 0 {
+		return false, "waiting to be accepted by its Gateway"
+	}
+	for _, item := range parents {
+		parent, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		accepted := false
+		for _, conditionItem := range conditions {
+			condition, ok := conditionItem.(map[string]any)
+			if ok && condition["type"] == conditionTypeAccepted && condition["status"] == string(corev1.ConditionTrue) {
+				accepted = true
+			}
+		}
+		if !accepted {
+			parentName, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+			return false, fmt.Sprintf("waiting to be accepted by Gateway %s", parentName)
+		}
+	}
+	return true, ""
+}
+
+// CheckAccessResourcesReady reads the access resources tracked in the Workspace status and asks the
+// access provider whether each of them is ready. It returns false with a message listing what the
+// resources wait for when any of them is not ready, or is not found in the cache yet.
+func (rm *ResourceManager) CheckAccessResourcesReady(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	provider AccessProvider,
+) (bool, string, error) {
+	var pending []string
+	for _, resource := range workspace.Status.AccessResources {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(rm.getGroupVersionKind(resource.APIVersion, resource.Kind))
+		if err := rm.client.Get(ctx, types.NamespacedName{
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+		}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				pending = append(pending, fmt.Sprintf("%s %s: not found", resource.Kind, resource.Name))
+				continue
+			}
+			return false, "", fmt.Errorf("failed to get access resource %s %s: %w", resource.Kind, resource.Name, err)
+		}
+
+		if ready, message := provider.ResourceReady(obj); !ready {
+			pending = append(pending, fmt.Sprintf("%s %s: %s", resource.Kind, resource.Name, message))
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), nil
+	}
+	return true, "", nil
+}
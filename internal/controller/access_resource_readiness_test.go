@@ -0,0 +1,195 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newReadinessTestResource(apiVersion, kind, name string, fields map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	for key, value := range fields {
+		obj.Object[key] = value
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(testNamespaceName)
+	return obj
+}
+
+func TestAccessResourceReady_Ingress(t *testing.T) {
+	ingress := newReadinessTestResource("networking.k8s.io/v1", "Ingress", "ws-ingress", nil)
+
+	ready, message := accessResourceReady(ingress)
+	assert.False(t, ready)
+	assert.Equal(t, "waiting for a load balancer address", message)
+
+	ingress.Object["status"] = map[string]any{
+		"loadBalancer": map[string]any{"ingress": []any{map[string]any{"hostname": "lb.example.com"}}},
+	}
+	ready, _ = accessResourceReady(ingress)
+	assert.True(t, ready)
+}
+
+func TestAccessResourceReady_Service(t *testing.T) {
+	clusterIP := newReadinessTestResource("v1", "Service", "ws-ssh", map[string]any{
+		"spec": map[string]any{"type": "ClusterIP"},
+	})
+	ready, _ := accessResourceReady(clusterIP)
+	assert.True(t, ready)
+
+	loadBalancer := newReadinessTestResource("v1", "Service", "ws-ssh", map[string]any{
+		"spec": map[string]any{"type": "LoadBalancer"},
+	})
+	ready, _ = accessResourceReady(loadBalancer)
+	assert.False(t, ready)
+
+	loadBalancer.Object["status"] = map[string]any{
+		"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "203.0.113.10"}}},
+	}
+	ready, _ = accessResourceReady(loadBalancer)
+	assert.True(t, ready)
+}
+
+func TestAccessResourceReady_HTTPRoute(t *testing.T) {
+	route := newReadinessTestResource("gateway.networking.k8s.io/v1", "HTTPRoute", "ws-route", nil)
+	ready, message := accessResourceReady(route)
+	assert.False(t, ready)
+	assert.Equal(t, "waiting to be accepted by its Gateway", message)
+
+	route.Object["status"] = map[string]any{"parents": []any{map[string]any{
+		"parentRef":  map[string]any{"name": "public"},
+		"conditions": []any{map[string]any{"type": "Accepted", "status": "False"}},
+	}}}
+	ready, message = accessResourceReady(route)
+	assert.False(t, ready)
+	assert.Equal(t, "waiting to be accepted by Gateway public", message)
+
+	route.Object["status"] = map[string]any{"parents": []any{map[string]any{
+		"parentRef":  map[string]any{"name": "public"},
+		"conditions": []any{map[string]any{"type": "Accepted", "status": "True"}},
+	}}}
+	ready, _ = accessResourceReady(route)
+	assert.True(t, ready)
+}
+
+func TestAccessResourceReady_ReadyCondition(t *testing.T) {
+	certificate := newReadinessTestResource("cert-manager.io/v1", "Certificate", "ws-tls", map[string]any{
+		"status": map[string]any{"conditions": []any{map[string]any{
+			"type": "Ready", "status": "False", "message": "Issuing certificate as Secret does not exist",
+		}}},
+	})
+	ready, message := accessResourceReady(certificate)
+	assert.False(t, ready)
+	assert.Equal(t, "Issuing certificate as Secret does not exist", message)
+
+	// Resources without a status, such as Traefik IngressRoutes, are ready
+	ingressRoute := newReadinessTestResource("traefik.io/v1alpha1", "IngressRoute", "ws-route", nil)
+	ready, _ = accessResourceReady(ingressRoute)
+	assert.True(t, ready)
+}
+
+func newReadinessTestStateMachine(t *testing.T, objs ...client.Object) *StateMachine {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &StateMachine{resourceManager: NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil)}
+}
+
+func newReadinessTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: testStrategyName},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			AccessURL: "https://example.com/ws/",
+			AccessResources: []workspacev1alpha1.AccessResourceStatus{{
+				Kind: "Ingress", APIVersion: "networking.k8s.io/v1", Name: "ws-ingress", Namespace: testNamespaceName,
+			}},
+		},
+	}
+}
+
+func TestReconcileAccessReadiness_WaitsForIngressAddress(t *testing.T) {
+	ingress := newReadinessTestResource("networking.k8s.io/v1", "Ingress", "ws-ingress", nil)
+	sm := newReadinessTestStateMachine(t, ingress)
+	workspace := newReadinessTestWorkspace()
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: testStrategyName, Namespace: testNamespaceName},
+		Spec:       workspacev1alpha1.WorkspaceAccessStrategySpec{Provider: AccessProviderIngress},
+	}
+
+	ready, err := sm.ReconcileAccessReadiness(context.Background(), workspace, accessStrategy)
+
+	require.NoError(t, err)
+	assert.False(t, ready)
+	assert.Empty(t, workspace.Status.AccessURL)
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeAccessReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonAccessResourcesPending, condition.Reason)
+	assert.Equal(t, "Waiting for access resources: Ingress ws-ingress: waiting for a load balancer address",
+		condition.Message)
+
+	ingress.Object["status"] = map[string]any{
+		"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "203.0.113.10"}}},
+	}
+	sm = newReadinessTestStateMachine(t, ingress)
+
+	ready, err = sm.ReconcileAccessReadiness(context.Background(), workspace, accessStrategy)
+
+	require.NoError(t, err)
+	assert.True(t, ready)
+	condition = FindCondition(&workspace.Status.Conditions, ConditionTypeAccessReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonAccessResourcesReady, condition.Reason)
+}
+
+func TestReconcileAccessReadiness_MissingResourceIsPending(t *testing.T) {
+	sm := newReadinessTestStateMachine(t)
+	sm.resourceManager.accessProviders, _ = NewAccessProviderRegistry(&stubAccessProvider{name: AccessProviderTemplate})
+	workspace := newReadinessTestWorkspace()
+
+	ready, err := sm.ReconcileAccessReadiness(context.Background(), workspace,
+		&workspacev1alpha1.WorkspaceAccessStrategy{ObjectMeta: metav1.ObjectMeta{Name: testStrategyName}})
+
+	require.NoError(t, err)
+	assert.False(t, ready)
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeAccessReady)
+	require.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "Ingress ws-ingress: not found")
+}
+
+func TestReconcileAccessReadiness_WithoutAccessStrategy(t *testing.T) {
+	sm := newReadinessTestStateMachine(t)
+	workspace := newReadinessTestWorkspace()
+	workspace.Spec.AccessStrategy = nil
+	workspace.Status.Conditions = []metav1.Condition{
+		NewCondition(ConditionTypeAccessReady, metav1.ConditionFalse, ReasonAccessResourcesPending, "pending"),
+	}
+
+	ready, err := sm.ReconcileAccessReadiness(context.Background(), workspace, nil)
+
+	require.NoError(t, err)
+	assert.True(t, ready)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeAccessReady))
+}
@@ -6,13 +6,13 @@ Distributed under the terms of the MIT license
 package controller
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
-	"text/template"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -29,13 +29,6 @@ func NewAccessResourcesBuilder() *AccessResourcesBuilder {
 	return &AccessResourcesBuilder{}
 }
 
-// fullAccessResourceData provides values for template substitutions
-type fullAccessResourceData struct {
-	Workspace      *workspacev1alpha1.Workspace
-	AccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
-	Service        *corev1.Service
-}
-
 // BuildUnstructuredResource builds an unstructured resource from a template
 func (b *AccessResourcesBuilder) BuildUnstructuredResource(
 	accessResourceTemplate workspacev1alpha1.AccessResourceTemplate,
@@ -47,24 +40,18 @@ func (b *AccessResourcesBuilder) BuildUnstructuredResource(
 	name := fmt.Sprintf("%s-%s", accessResourceTemplate.NamePrefix, workspace.Name)
 
 	// Process resource template
-	resourceTmpl, err := template.New("resource").Funcs(template.FuncMap{
-		"b32encode": workspaceutil.EncodeNamespaceB32,
-	}).Parse(accessResourceTemplate.Template)
+	field := accessResourceTemplateField(accessStrategy, accessResourceTemplate)
+	resourceTmpl, err := accesstemplate.Parse(field, accessResourceTemplate.Template)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse resource template: %w", err)
+		return nil, errs.Render(err, "failed to parse resource template")
 	}
 
-	accessResourceData := &fullAccessResourceData{
-		Workspace:      workspace,
-		AccessStrategy: accessStrategy,
-		Service:        service,
-	}
+	accessResourceData := accesstemplate.NewData(workspace, accessStrategy, service)
 
-	var resourceBuffer bytes.Buffer
-	if err := resourceTmpl.Execute(&resourceBuffer, accessResourceData); err != nil {
-		return nil, fmt.Errorf("failed to execute resource template: %w", err)
+	resourceYAML, err := accesstemplate.Execute(field, resourceTmpl, accessResourceData)
+	if err != nil {
+		return nil, errs.Render(err, "failed to execute resource template")
 	}
-	resourceYAML := resourceBuffer.String()
 
 	// First add apiVersion and kind to the YAML
 	yamlWithMeta := fmt.Sprintf("apiVersion: %s\nkind: %s\n%s",
@@ -75,7 +62,7 @@ func (b *AccessResourcesBuilder) BuildUnstructuredResource(
 	// Convert YAML to unstructured.Unstructured
 	obj := &unstructured.Unstructured{}
 	if err := yaml.Unmarshal([]byte(yamlWithMeta), obj); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal resource YAML: %w", err)
+		return nil, errs.Render(err, "failed to unmarshal resource YAML")
 	}
 
 	// Set basic metadata
@@ -123,33 +110,41 @@ func (b *AccessResourcesBuilder) BuildUnstructuredResource(
 	return obj, nil
 }
 
-// ResolveTemplateURL resolves a Go text/template URL string using workspace, access strategy,
-// and service data. Shared by ResolveAccessURL and the access startup prober.
+// accessResourceTemplateField returns the path of the access resource template in the strategy,
+// matched by name prefix, for template errors to point at it
+func accessResourceTemplateField(
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	accessResourceTemplate workspacev1alpha1.AccessResourceTemplate,
+) string {
+	for i, candidate := range accessStrategy.Spec.AccessResourceTemplates {
+		if candidate.NamePrefix == accessResourceTemplate.NamePrefix {
+			return accesstemplate.ResourceTemplateField(i)
+		}
+	}
+	return "spec.accessResourceTemplates.template"
+}
+
+// ResolveTemplateURL resolves the Go text/template URL string of field using workspace, access
+// strategy, and service data. Shared by ResolveAccessURL and the access startup prober.
 func (b *AccessResourcesBuilder) ResolveTemplateURL(
+	field string,
 	urlTemplate string,
 	workspace *workspacev1alpha1.Workspace,
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
 	service *corev1.Service,
 ) (string, error) {
-	tmpl, err := template.New("url").Funcs(template.FuncMap{
-		"b32encode": workspaceutil.EncodeNamespaceB32,
-	}).Parse(urlTemplate)
+	tmpl, err := accesstemplate.Parse(field, urlTemplate)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse URL template: %w", err)
+		return "", errs.Render(err, "failed to parse URL template")
 	}
 
-	data := &fullAccessResourceData{
-		Workspace:      workspace,
-		AccessStrategy: accessStrategy,
-		Service:        service,
-	}
+	data := accesstemplate.NewData(workspace, accessStrategy, service)
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute URL template: %w", err)
+	resolved, err := accesstemplate.Execute(field, tmpl, data)
+	if err != nil {
+		return "", errs.Render(err, "failed to execute URL template")
 	}
-
-	return buf.String(), nil
+	return resolved, nil
 }
 
 // ResolveAccessURL processes the AccessURLTemplate
@@ -161,7 +156,8 @@ func (b *AccessResourcesBuilder) ResolveAccessURL(
 	if accessStrategy.Spec.AccessURLTemplate == "" {
 		return "", nil
 	}
-	return b.ResolveTemplateURL(accessStrategy.Spec.AccessURLTemplate, workspace, accessStrategy, service)
+	return b.ResolveTemplateURL(accesstemplate.FieldAccessURLTemplate, accessStrategy.Spec.AccessURLTemplate,
+		workspace, accessStrategy, service)
 }
 
 // ResolveApplicationBasePath resolves the access strategy's applicationBasePathTemplate.
@@ -174,7 +170,8 @@ func (b *AccessResourcesBuilder) ResolveApplicationBasePath(
 	if accessStrategy.Spec.ApplicationBasePathTemplate == "" {
 		return "", nil
 	}
-	resolved, err := b.ResolveTemplateURL(accessStrategy.Spec.ApplicationBasePathTemplate, workspace, accessStrategy, service)
+	resolved, err := b.ResolveTemplateURL(accesstemplate.FieldApplicationBasePathTemplate,
+		accessStrategy.Spec.ApplicationBasePathTemplate, workspace, accessStrategy, service)
 	if err != nil {
 		return "", err
 	}
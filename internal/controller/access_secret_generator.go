@@ -0,0 +1,244 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+)
+
+// DefaultGeneratedSecretLength is the length of generated tokens and passwords without a length
+const DefaultGeneratedSecretLength = 32
+
+// generatedSecretAlphabet holds the characters of generated tokens and passwords, which are safe
+// in URLs, headers and htpasswd files
+const generatedSecretAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// accessSecretGenerators returns the generators of the accessResourceTemplate that rendered obj,
+// or nil when obj is not a Secret with generated values
+func accessSecretGenerators(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	obj *unstructured.Unstructured,
+) []workspacev1alpha1.SecretGenerator {
+	if obj.GetKind() != "Secret" {
+		return nil
+	}
+	for _, accessResourceTemplate := range accessStrategy.Spec.AccessResourceTemplates {
+		if accessResourceTemplate.Kind == "Secret" &&
+			fmt.Sprintf("%s-%s", accessResourceTemplate.NamePrefix, workspace.Name) == obj.GetName() {
+			return accessResourceTemplate.Generate
+		}
+	}
+	return nil
+}
+
+// resolveGeneratedSecretValues returns the values of the generators of the Secret named name.
+// Values kept in stored are reused and missing ones are generated and added to stored, under
+// the key {name}.{key}. It reports whether stored changed.
+func resolveGeneratedSecretValues(
+	stored map[string][]byte,
+	name string,
+	defaultUsername string,
+	generators []workspacev1alpha1.SecretGenerator,
+) (map[string][]byte, bool, error) {
+	values := make(map[string][]byte, len(generators))
+	changed := false
+	keep := func(key string, value []byte) {
+		values[key] = value
+		storedKey := name + "." + key
+		if string(stored[storedKey]) != string(value) {
+			stored[storedKey] = value
+			changed = true
+		}
+	}
+
+	// Htpasswd entries hash BasicAuth pairs, which are resolved first
+	for _, generator := range generators {
+		if generator.Type == workspacev1alpha1.SecretGeneratorHtpasswd {
+			continue
+		}
+		existing := stored[name+"."+generator.Key]
+		switch generator.Type {
+		case workspacev1alpha1.SecretGeneratorToken:
+			if len(existing) > 0 {
+				keep(generator.Key, existing)
+				continue
+			}
+			token, err := randomSecretString(generator.Length)
+			if err != nil {
+				return nil, false, err
+			}
+			keep(generator.Key, []byte(token))
+		case workspacev1alpha1.SecretGeneratorBasicAuth:
+			username := generator.Username
+			if username == "" {
+				username = defaultUsername
+			}
+			// a changed username keeps the password
+			if _, password, ok := strings.Cut(string(existing), ":"); ok && password != "" {
+				keep(generator.Key, []byte(username+":"+password))
+				continue
+			}
+			password, err := randomSecretString(generator.Length)
+			if err != nil {
+				return nil, false, err
+			}
+			keep(generator.Key, []byte(username+":"+password))
+		default:
+			return nil, false, errs.Render(nil, "unsupported type %q of generated secret key %s", generator.Type, generator.Key)
+		}
+	}
+
+	for _, generator := range generators {
+		if generator.Type != workspacev1alpha1.SecretGeneratorHtpasswd {
+			continue
+		}
+		pair, ok := values[generator.From]
+		if !ok || !isBasicAuthGenerator(generators, generator.From) {
+			return nil, false, errs.Render(nil, "generated secret key %s must reference a BasicAuth key", generator.Key)
+		}
+		username, password, _ := strings.Cut(string(pair), ":")
+
+		// the entry is regenerated only when it no longer matches the pair, as bcrypt salts each hash
+		existing := stored[name+"."+generator.Key]
+		if existingUsername, hash, ok := strings.Cut(string(existing), ":"); ok && existingUsername == username &&
+			bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			keep(generator.Key, existing)
+			continue
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to hash password of generated secret key %s: %w", generator.From, err)
+		}
+		keep(generator.Key, []byte(username+":"+string(hash)))
+	}
+
+	return values, changed, nil
+}
+
+// isBasicAuthGenerator returns true when key is generated as a BasicAuth pair
+func isBasicAuthGenerator(generators []workspacev1alpha1.SecretGenerator, key string) bool {
+	for _, generator := range generators {
+		if generator.Key == key {
+			return generator.Type == workspacev1alpha1.SecretGeneratorBasicAuth
+		}
+	}
+	return false
+}
+
+// randomSecretString returns a random string of generatedSecretAlphabet characters
+func randomSecretString(length int32) (string, error) {
+	if length <= 0 {
+		length = DefaultGeneratedSecretLength
+	}
+	alphabetSize := big.NewInt(int64(len(generatedSecretAlphabet)))
+	var b strings.Builder
+	for range length {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random value: %w", err)
+		}
+		b.WriteByte(generatedSecretAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// applyGeneratedSecretValues sets the generated values of an access resource Secret. The values
+// are kept in a Secret owned by the workspace rather than in the access resource, so that they
+// survive re-renders of the template as well as the deletion of access resources on stop.
+func (rm *ResourceManager) applyGeneratedSecretValues(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	obj *unstructured.Unstructured,
+	generators []workspacev1alpha1.SecretGenerator,
+) error {
+	store, exists, err := rm.getGeneratedAccessSecrets(ctx, workspace)
+	if err != nil {
+		return err
+	}
+
+	values, changed, err := resolveGeneratedSecretValues(store.Data, obj.GetName(), workspace.Name, generators)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if !exists {
+			if err := controllerutil.SetControllerReference(workspace, store, rm.scheme); err != nil {
+				return fmt.Errorf("failed to set controller reference: %w", err)
+			}
+			if err := rm.client.Create(ctx, store); err != nil {
+				return fmt.Errorf("failed to create generated access secrets: %w", err)
+			}
+		} else if err := rm.client.Update(ctx, store); err != nil {
+			return fmt.Errorf("failed to update generated access secrets: %w", err)
+		}
+		logf.FromContext(ctx).Info("Stored generated access secret values",
+			"secret", store.Name, "accessResource", obj.GetName())
+	}
+
+	for key, value := range values {
+		if err := unstructured.SetNestedField(obj.Object, base64.StdEncoding.EncodeToString(value), "data", key); err != nil {
+			return fmt.Errorf("failed to set generated key %s of %s: %w", key, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// getGeneratedAccessSecrets returns the Secret keeping the generated values of the workspace, or
+// a new one when it does not exist. The Secret is read as an unstructured object, which the
+// manager does not cache, since the cache only holds the Secrets of the controller namespace.
+func (rm *ResourceManager) getGeneratedAccessSecrets(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+) (*corev1.Secret, bool, error) {
+	key := types.NamespacedName{Namespace: workspace.Namespace, Name: GenerateAccessSecretsName(workspace)}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	err := rm.client.Get(ctx, key, existing)
+	if apierrors.IsNotFound(err) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    GenerateLabels(workspace.Name),
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{},
+		}, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get generated access secrets: %w", err)
+	}
+
+	store := &corev1.Secret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, store); err != nil {
+		return nil, false, fmt.Errorf("failed to convert generated access secrets: %w", err)
+	}
+	if store.Data == nil {
+		store.Data = map[string][]byte{}
+	}
+	return store, true, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var testSecretGenerators = []workspacev1alpha1.SecretGenerator{
+	{Key: "htpasswd", Type: workspacev1alpha1.SecretGeneratorHtpasswd, From: "credentials"},
+	{Key: "credentials", Type: workspacev1alpha1.SecretGeneratorBasicAuth, Username: "jovyan", Length: 20},
+	{Key: "token", Type: workspacev1alpha1.SecretGeneratorToken},
+}
+
+func TestResolveGeneratedSecretValues_GeneratesMissingValues(t *testing.T) {
+	stored := map[string][]byte{}
+	values, changed, err := resolveGeneratedSecretValues(stored, "auth-ws", "ws", testSecretGenerators)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.Len(t, values["token"], DefaultGeneratedSecretLength)
+	username, password, ok := strings.Cut(string(values["credentials"]), ":")
+	require.True(t, ok)
+	assert.Equal(t, "jovyan", username)
+	assert.Len(t, password, 20)
+
+	entryUsername, hash, ok := strings.Cut(string(values["htpasswd"]), ":")
+	require.True(t, ok)
+	assert.Equal(t, "jovyan", entryUsername)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)))
+
+	assert.Equal(t, values["token"], stored["auth-ws.token"])
+	assert.Equal(t, values["credentials"], stored["auth-ws.credentials"])
+	assert.Equal(t, values["htpasswd"], stored["auth-ws.htpasswd"])
+}
+
+func TestResolveGeneratedSecretValues_ReusesStoredValues(t *testing.T) {
+	stored := map[string][]byte{}
+	first, _, err := resolveGeneratedSecretValues(stored, "auth-ws", "ws", testSecretGenerators)
+	require.NoError(t, err)
+
+	second, changed, err := resolveGeneratedSecretValues(stored, "auth-ws", "ws", testSecretGenerators)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, first, second)
+}
+
+func TestResolveGeneratedSecretValues_RenamedUserKeepsPassword(t *testing.T) {
+	stored := map[string][]byte{}
+	first, _, err := resolveGeneratedSecretValues(stored, "auth-ws", "ws", testSecretGenerators)
+	require.NoError(t, err)
+
+	renamed := append([]workspacev1alpha1.SecretGenerator(nil), testSecretGenerators...)
+	renamed[1].Username = "alice"
+	second, changed, err := resolveGeneratedSecretValues(stored, "auth-ws", "ws", renamed)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	_, password, _ := strings.Cut(string(first["credentials"]), ":")
+	assert.Equal(t, "alice:"+password, string(second["credentials"]))
+	assert.True(t, strings.HasPrefix(string(second["htpasswd"]), "alice:"), "the htpasswd entry follows the pair")
+	assert.Equal(t, first["token"], second["token"])
+}
+
+func TestResolveGeneratedSecretValues_DefaultsUsernameToWorkspaceName(t *testing.T) {
+	generators := []workspacev1alpha1.SecretGenerator{{Key: "credentials", Type: workspacev1alpha1.SecretGeneratorBasicAuth}}
+	values, _, err := resolveGeneratedSecretValues(map[string][]byte{}, "auth-ws", "ws", generators)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(values["credentials"]), "ws:"))
+}
+
+func TestResolveGeneratedSecretValues_HtpasswdRequiresBasicAuthKey(t *testing.T) {
+	generators := []workspacev1alpha1.SecretGenerator{
+		{Key: "token", Type: workspacev1alpha1.SecretGeneratorToken},
+		{Key: "htpasswd", Type: workspacev1alpha1.SecretGeneratorHtpasswd, From: "token"},
+	}
+	_, _, err := resolveGeneratedSecretValues(map[string][]byte{}, "auth-ws", "ws", generators)
+	assert.EqualError(t, err, "generated secret key htpasswd must reference a BasicAuth key")
+}
+
+func TestEnsureAccessResourcesExist_KeepsGeneratedSecretValues(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	resourceManager := NewResourceManager(k8sClient, scheme, nil, nil, nil, NewAccessResourcesBuilder(), nil)
+
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespaceName, UID: "test-uid"},
+	}
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: testStrategyName, Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{{
+				Kind:       "Secret",
+				ApiVersion: "v1",
+				NamePrefix: "auth",
+				Template:   "type: Opaque\ndata:\n  realm: d29ya3NwYWNl",
+				Generate:   testSecretGenerators,
+			}},
+		},
+	}
+	secretKey := types.NamespacedName{Name: "auth-" + testWorkspaceName, Namespace: testNamespaceName}
+
+	require.NoError(t, resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, nil))
+	first := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, secretKey, first))
+	assert.Equal(t, "workspace", string(first.Data["realm"]))
+	assert.Len(t, first.Data["token"], DefaultGeneratedSecretLength)
+
+	store := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{
+		Name: GenerateAccessSecretsName(workspace), Namespace: testNamespaceName,
+	}, store))
+	assert.Equal(t, first.Data["token"], store.Data[secretKey.Name+".token"])
+	assert.True(t, metav1.IsControlledBy(store, workspace))
+
+	// Re-rendering, and recreating the access resources after a stop, keeps the values
+	require.NoError(t, resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, nil))
+	require.NoError(t, k8sClient.Delete(ctx, first))
+	require.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, secretKey, &corev1.Secret{})))
+	workspace.Status.AccessResources = nil
+	require.NoError(t, resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, nil))
+
+	recreated := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, secretKey, recreated))
+	assert.Equal(t, first.Data, recreated.Data)
+}
+
+func TestAccessSecretGenerators_MatchesSecretTemplates(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{Kind: "Service", NamePrefix: "auth"},
+				{Kind: "Secret", NamePrefix: "auth", Generate: testSecretGenerators},
+			},
+		},
+	}
+	secret, err := toUnstructuredResource(&corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "auth-ws"},
+	})
+	require.NoError(t, err)
+	service, err := toUnstructuredResource(&corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "auth-ws"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, testSecretGenerators, accessSecretGenerators(workspace, accessStrategy, secret))
+	assert.Nil(t, accessSecretGenerators(workspace, accessStrategy, service))
+}
@@ -11,9 +11,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
 	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -69,8 +72,7 @@ func (p *AccessStartupProber) Probe(
 		return false, fmt.Errorf("accessStartupProbe.httpGet is required")
 	}
 
-	url, err := p.builder.ResolveTemplateURL(
-		probe.HTTPGet.URLTemplate, workspace, accessStrategy, service)
+	probeURL, err := p.resolveProbeURL(probe.HTTPGet, workspace, accessStrategy, service)
 	if err != nil {
 		return false, fmt.Errorf("failed to resolve probe URL: %w", err)
 	}
@@ -79,7 +81,7 @@ func (p *AccessStartupProber) Probe(
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to create probe request: %w", err)
 	}
@@ -89,22 +91,58 @@ func (p *AccessStartupProber) Probe(
 		var dnsErr *net.DNSError
 		if errors.As(err, &dnsErr) {
 			logger.Info("Access startup probe: DNS not yet resolved",
-				"url", url, "dnsError", dnsErr)
+				"url", probeURL, "dnsError", dnsErr)
 			return false, nil
 		}
 		// Connection failures (refused, timeout) are expected while the
 		// route propagates — return not-ready so the caller retries.
-		logger.V(1).Info("Access startup probe connection failed", "url", url, "error", err)
+		logger.V(1).Info("Access startup probe connection failed", "url", probeURL, "error", err)
 		return false, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	success := isProbeStatusSuccess(resp.StatusCode, probe.HTTPGet.AdditionalSuccessStatusCodes)
 	logger.V(1).Info("Access startup probe response",
-		"url", url, "statusCode", resp.StatusCode, "success", success)
+		"url", probeURL, "statusCode", resp.StatusCode, "success", success)
 	return success, nil
 }
 
+// resolveProbeURL returns the URL to probe: the templated URL when httpGet.urlTemplate is set,
+// otherwise the access URL of the workspace routed through its cluster Service.
+func (p *AccessStartupProber) resolveProbeURL(
+	httpGet *workspacev1alpha1.AccessHTTPGetProbe,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) (string, error) {
+	if httpGet.URLTemplate != "" {
+		return p.builder.ResolveTemplateURL(accesstemplate.FieldAccessStartupProbeURL, httpGet.URLTemplate,
+			workspace, accessStrategy, service)
+	}
+	return serviceAccessURL(workspace.Status.AccessURL, service)
+}
+
+// serviceAccessURL rewrites the scheme and host of accessURL to the in-cluster address
+// of service, so that the probe reaches the workspace without going through external DNS.
+func serviceAccessURL(accessURL string, service *corev1.Service) (string, error) {
+	if accessURL == "" {
+		return "", fmt.Errorf("workspace has no access URL to probe")
+	}
+	if service == nil || len(service.Spec.Ports) == 0 {
+		return "", fmt.Errorf("workspace service has no port to probe")
+	}
+	parsed, err := url.Parse(accessURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid access URL %q: %w", accessURL, err)
+	}
+	parsed.Scheme = "http"
+	parsed.User = nil
+	parsed.Host = net.JoinHostPort(
+		fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace),
+		strconv.Itoa(int(service.Spec.Ports[0].Port)))
+	return parsed.String(), nil
+}
+
 func isProbeStatusSuccess(statusCode int, additionalCodes []int) bool {
 	if statusCode >= 200 && statusCode < 400 {
 		return true
@@ -13,6 +13,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
@@ -305,6 +306,33 @@ var _ = Describe("AccessStartupProber", func() {
 			Expect(receivedPath).To(Equal("/workspaces/test-namespace/test-workspace/"))
 		})
 
+		It("should probe the access URL through the service when urlTemplate is empty", func() {
+			accessStrategy.Spec.AccessStartupProbe = &workspacev1alpha1.AccessStartupProbe{
+				HTTPGet: &workspacev1alpha1.AccessHTTPGetProbe{},
+			}
+			workspace.Status.AccessURL = "https://user@example.com/workspaces/test-namespace/test-workspace/?a=b"
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "workspace-svc", Namespace: testNamespaceName},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8888}}},
+			}
+
+			probeURL, err := prober.resolveProbeURL(
+				accessStrategy.Spec.AccessStartupProbe.HTTPGet, workspace, accessStrategy, service)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probeURL).To(Equal(
+				"http://workspace-svc." + testNamespaceName + ".svc:8888/workspaces/test-namespace/test-workspace/?a=b"))
+		})
+
+		It("should return error when urlTemplate is empty and there is no access URL", func() {
+			accessStrategy.Spec.AccessStartupProbe = &workspacev1alpha1.AccessStartupProbe{
+				HTTPGet: &workspacev1alpha1.AccessHTTPGetProbe{},
+			}
+
+			_, err := prober.Probe(context.Background(), workspace, accessStrategy, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no access URL"))
+		})
+
 		It("should return error for nil httpGet", func() {
 			accessStrategy.Spec.AccessStartupProbe = &workspacev1alpha1.AccessStartupProbe{
 				TimeoutSeconds: 5,
@@ -0,0 +1,166 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// AccessStrategy fan-out defaults, matching the burst and rate of the workspace queue
+const (
+	DefaultAccessStrategyFanOutBurst = 100
+	DefaultAccessStrategyFanOutQPS   = 10.0
+)
+
+// AccessStrategyFanOut propagates the changes of an AccessStrategy to the workspaces that render it
+// at a bounded rate, so that a change to an AccessStrategy referenced by thousands of workspaces does
+// not flood the workspace queue and the API server with access resource and status updates.
+// A token bucket shared by all the AccessStrategies bounds the rate: up to burst workspaces are
+// enqueued at once, then qps workspaces per second.
+// The AccessStrategy controller enqueues the stale workspaces through it, and the workspace
+// controller watches its source.
+type AccessStrategyFanOut struct {
+	burst   int
+	limiter *rate.Limiter
+	events  chan event.GenericEvent
+	now     func() time.Time
+
+	mu     sync.Mutex
+	states map[types.NamespacedName]*fanOutState
+}
+
+// fanOutState tracks the propagation of one version of an AccessStrategy
+type fanOutState struct {
+	// version is the versioned ID of the AccessStrategy being propagated
+	version string
+	// cursor is the key of the last workspace enqueued, in the order of their keys
+	cursor string
+}
+
+// NewAccessStrategyFanOut creates an AccessStrategyFanOut enqueuing up to burst workspaces at once,
+// then qps workspaces per second. Non-positive values use the defaults.
+func NewAccessStrategyFanOut(burst int, qps float64) *AccessStrategyFanOut {
+	if burst <= 0 {
+		burst = DefaultAccessStrategyFanOutBurst
+	}
+	if qps <= 0 {
+		qps = DefaultAccessStrategyFanOutQPS
+	}
+	return &AccessStrategyFanOut{
+		burst:   burst,
+		limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		events:  make(chan event.GenericEvent, burst),
+		now:     time.Now,
+		states:  map[types.NamespacedName]*fanOutState{},
+	}
+}
+
+// source returns the source of the workspace reconciliation requests of the fan-out
+func (f *AccessStrategyFanOut) source() source.Source {
+	return source.Channel(f.events, &handler.EnqueueRequestForObject{})
+}
+
+// propagate enqueues as many of the stale workspaces of an AccessStrategy at version, the workspaces
+// whose access resources are not rendered from it yet, as the rate allows. It returns how long until
+// the next workspaces are due, or zero when every stale workspace was enqueued for this version:
+// those that remain stale, e.g. because their compute is not ready, update on their own reconciliation.
+func (f *AccessStrategyFanOut) propagate(
+	accessStrategy types.NamespacedName,
+	version string,
+	stale []*workspacev1alpha1.Workspace,
+) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(stale) == 0 {
+		delete(f.states, accessStrategy)
+		return 0
+	}
+	state := f.states[accessStrategy]
+	if state == nil || state.version != version {
+		state = &fanOutState{version: version}
+		f.states[accessStrategy] = state
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return workspaceKey(stale[i]) < workspaceKey(stale[j])
+	})
+	pending := stale[sort.Search(len(stale), func(i int) bool {
+		return workspaceKey(stale[i]) > state.cursor
+	}):]
+	if len(pending) == 0 {
+		return 0
+	}
+
+	now := f.now()
+	allowed := min(len(pending), int(f.limiter.TokensAt(now)))
+
+	// The channel holds a burst: when the workspace controller has not consumed the previous
+	// workspaces yet, the rest wait for a full burst to be consumed
+	sent := 0
+send:
+	for _, ws := range pending[:allowed] {
+		select {
+		case f.events <- event.GenericEvent{Object: ws}:
+			state.cursor = workspaceKey(ws)
+			sent++
+		default:
+			break send
+		}
+	}
+	f.limiter.AllowN(now, sent)
+
+	switch {
+	case sent == len(pending):
+		return 0
+	case sent < allowed:
+		// Give the workspace controller the time of a burst to consume the channel
+		return time.Duration(f.burst) * f.interval()
+	default:
+		return f.delayFor(now, min(f.burst, len(pending)-sent))
+	}
+}
+
+// delayFor returns how long until the rate allows n more workspaces, at least the time it allows one
+func (f *AccessStrategyFanOut) delayFor(now time.Time, n int) time.Duration {
+	missing := float64(n) - f.limiter.TokensAt(now)
+	return max(f.interval(), time.Duration(missing*float64(f.interval())))
+}
+
+// interval returns the time the rate allows one more workspace
+func (f *AccessStrategyFanOut) interval() time.Duration {
+	return time.Duration(float64(time.Second) / float64(f.limiter.Limit()))
+}
+
+// forget drops the propagation state of a deleted AccessStrategy
+func (f *AccessStrategyFanOut) forget(accessStrategy types.NamespacedName) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.states, accessStrategy)
+}
+
+// workspaceKey returns the namespace/name key ordering the workspaces of a fan-out
+func workspaceKey(ws *workspacev1alpha1.Workspace) string {
+	return client.ObjectKeyFromObject(ws).String()
+}
+
+// rendersAccessStrategy returns true when the workspace renders the access resources of its
+// AccessStrategy: it is desired to be running with access enabled
+func rendersAccessStrategy(ws *workspacev1alpha1.Workspace) bool {
+	return isRunningWorkspace(ws) && workspaceutil.IsAccessEnabled(ws)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const testFanOutStrategyName = "shared-strategy"
+
+func newFanOutTestWorkspaces(count int) []*workspacev1alpha1.Workspace {
+	workspaces := make([]*workspacev1alpha1.Workspace, 0, count)
+	for i := range count {
+		workspaces = append(workspaces, &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ws-%02d", i), Namespace: testNamespaceName},
+		})
+	}
+	return workspaces
+}
+
+// drainFanOut returns the names of the workspaces enqueued by the fan-out
+func drainFanOut(f *AccessStrategyFanOut) []string {
+	var names []string
+	for {
+		select {
+		case e := <-f.events:
+			names = append(names, e.Object.GetName())
+		default:
+			return names
+		}
+	}
+}
+
+// newTestFanOut returns a fan-out enqueuing up to 2 workspaces at once, then one every 8 seconds,
+// and a pointer to its clock
+func newTestFanOut() (*AccessStrategyFanOut, *time.Time) {
+	fanOut := NewAccessStrategyFanOut(2, 0.125)
+	now := time.Now()
+	fanOut.now = func() time.Time { return now }
+	return fanOut, &now
+}
+
+func TestAccessStrategyFanOut_EnqueuesAtRate(t *testing.T) {
+	fanOut, now := newTestFanOut()
+	key := types.NamespacedName{Name: testFanOutStrategyName, Namespace: testNamespaceName}
+	stale := newFanOutTestWorkspaces(5)
+
+	// A burst, then the rest waits for the rate to allow the next burst
+	assert.Equal(t, 16*time.Second, fanOut.propagate(key, "uid.1", stale))
+	assert.Equal(t, []string{"ws-00", "ws-01"}, drainFanOut(fanOut))
+
+	*now = now.Add(8 * time.Second)
+	assert.Equal(t, 16*time.Second, fanOut.propagate(key, "uid.1", stale))
+	assert.Equal(t, []string{"ws-02"}, drainFanOut(fanOut))
+
+	// The last workspaces complete the propagation
+	*now = now.Add(16 * time.Second)
+	assert.Zero(t, fanOut.propagate(key, "uid.1", stale[1:]))
+	assert.Equal(t, []string{"ws-03", "ws-04"}, drainFanOut(fanOut))
+	assert.Zero(t, fanOut.propagate(key, "uid.1", stale))
+	assert.Empty(t, drainFanOut(fanOut), "workspaces that remain stale are not enqueued again")
+}
+
+func TestAccessStrategyFanOut_RestartsOnNewVersion(t *testing.T) {
+	fanOut, now := newTestFanOut()
+	key := types.NamespacedName{Name: testFanOutStrategyName, Namespace: testNamespaceName}
+	stale := newFanOutTestWorkspaces(3)
+
+	fanOut.propagate(key, "uid.1", stale)
+	assert.Equal(t, []string{"ws-00", "ws-01"}, drainFanOut(fanOut))
+
+	*now = now.Add(16 * time.Second)
+	fanOut.propagate(key, "uid.2", stale)
+	assert.Equal(t, []string{"ws-00", "ws-01"}, drainFanOut(fanOut), "a new version restarts from the first workspace")
+}
+
+func TestAccessStrategyFanOut_SharesRateAcrossAccessStrategies(t *testing.T) {
+	fanOut, now := newTestFanOut()
+	other := types.NamespacedName{Name: "other-strategy", Namespace: testNamespaceName}
+	key := types.NamespacedName{Name: testFanOutStrategyName, Namespace: testNamespaceName}
+
+	// Another AccessStrategy used the burst
+	assert.Zero(t, fanOut.propagate(other, "other.1", newFanOutTestWorkspaces(2)))
+	assert.Len(t, drainFanOut(fanOut), 2)
+	assert.Equal(t, 16*time.Second, fanOut.propagate(key, "uid.1", newFanOutTestWorkspaces(2)))
+	assert.Empty(t, drainFanOut(fanOut))
+
+	*now = now.Add(16 * time.Second)
+	assert.Zero(t, fanOut.propagate(key, "uid.1", newFanOutTestWorkspaces(2)))
+	assert.Equal(t, []string{"ws-00", "ws-01"}, drainFanOut(fanOut))
+}
+
+func TestAccessStrategyFanOut_WaitsWhenQueueIsFull(t *testing.T) {
+	fanOut, now := newTestFanOut()
+	other := types.NamespacedName{Name: "other-strategy", Namespace: testNamespaceName}
+	key := types.NamespacedName{Name: testFanOutStrategyName, Namespace: testNamespaceName}
+
+	// Another AccessStrategy filled the channel, which the workspace controller did not consume yet
+	fanOut.propagate(other, "other.1", newFanOutTestWorkspaces(2))
+	*now = now.Add(16 * time.Second)
+	assert.Equal(t, 16*time.Second, fanOut.propagate(key, "uid.1", newFanOutTestWorkspaces(2)))
+	assert.Len(t, drainFanOut(fanOut), 2)
+
+	assert.Zero(t, fanOut.propagate(key, "uid.1", newFanOutTestWorkspaces(2)), "the unused rate is kept")
+	assert.Equal(t, []string{"ws-00", "ws-01"}, drainFanOut(fanOut))
+}
+
+func TestNewAccessStrategyFanOut_Defaults(t *testing.T) {
+	fanOut := NewAccessStrategyFanOut(0, 0)
+	assert.Equal(t, DefaultAccessStrategyFanOutBurst, fanOut.burst)
+	assert.Equal(t, DefaultAccessStrategyFanOutBurst, fanOut.limiter.Burst())
+	assert.InDelta(t, DefaultAccessStrategyFanOutQPS, float64(fanOut.limiter.Limit()), 0)
+}
+
+func newFanOutTestWorkspace(name, desiredStatus, appliedVersion string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespaceName,
+			Labels: map[string]string{
+				workspaceutil.LabelAccessStrategyName:      testFanOutStrategyName,
+				workspaceutil.LabelAccessStrategyNamespace: testNamespaceName,
+			},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DesiredStatus:  desiredStatus,
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: testFanOutStrategyName, Namespace: testNamespaceName},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{AppliedAccessStrategyVersion: appliedVersion},
+	}
+}
+
+func TestWorkspaceAccessStrategyReconciler_ReportsPropagation(t *testing.T) {
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testFanOutStrategyName,
+			Namespace:  testNamespaceName,
+			UID:        "strategy-uid",
+			Generation: 3,
+			Finalizers: []string{workspaceutil.AccessStrategyFinalizerName},
+		},
+	}
+	version := versionedAccessStrategyID(accessStrategy)
+	disabled := newFanOutTestWorkspace("disabled", DesiredStateRunning, "")
+	disabled.Spec.AccessEnabled = ptr.To(false)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	statusUpdates := 0
+	k8sClient := newIndexedClientBuilder(scheme).
+		WithObjects(
+			accessStrategy,
+			newFanOutTestWorkspace("updated", DesiredStateRunning, version),
+			newFanOutTestWorkspace("stale-a", DesiredStateRunning, "strategy-uid.2"),
+			newFanOutTestWorkspace("stale-b", DesiredStateRunning, ""),
+			newFanOutTestWorkspace("stopped", DesiredStateStopped, ""),
+			disabled,
+		).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceAccessStrategy{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string,
+				obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				statusUpdates++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	fanOut := NewAccessStrategyFanOut(1, 0.25)
+	now := time.Now()
+	fanOut.now = func() time.Time { return now }
+	reconciler := &WorkspaceAccessStrategyReconciler{Client: k8sClient, Scheme: scheme, FanOut: fanOut}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(accessStrategy)}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Second, result.RequeueAfter, "the second stale workspace waits for the rate")
+	assert.Equal(t, []string{"stale-a"}, drainFanOut(fanOut))
+
+	updated := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, k8sClient.Get(context.Background(), req.NamespacedName, updated))
+	assert.Equal(t, int32(3), updated.Status.Workspaces)
+	assert.Equal(t, int32(1), updated.Status.UpdatedWorkspaces)
+	assert.Equal(t, int64(3), updated.Status.ObservedGeneration)
+	assert.Equal(t, "1 of 3 workspaces updated", updated.Status.Message)
+	assert.Equal(t, 1, statusUpdates)
+
+	// Unchanged counts do not update the status
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, statusUpdates)
+}
+
+func TestWorkspaceAccessStrategyReconciler_WithoutFanOutManagesFinalizersOnly(t *testing.T) {
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testFanOutStrategyName,
+			Namespace:  testNamespaceName,
+			Finalizers: []string{workspaceutil.AccessStrategyFinalizerName},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	k8sClient := newIndexedClientBuilder(scheme).
+		WithObjects(accessStrategy, newFanOutTestWorkspace("stale", DesiredStateRunning, "")).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceAccessStrategy{}).
+		Build()
+
+	reconciler := &WorkspaceAccessStrategyReconciler{Client: k8sClient, Scheme: scheme}
+	_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(accessStrategy)})
+	require.NoError(t, err)
+
+	updated := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(accessStrategy), updated))
+	assert.Empty(t, updated.Status.Message)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ApplicationProberInterface allows mocking in tests
+type ApplicationProberInterface interface {
+	Probe(ctx context.Context, service *corev1.Service, port int32, path string) bool
+}
+
+// ApplicationProber probes the health endpoints of the additional applications of workspaces,
+// declared in spec.ports, through the workspace Service
+type ApplicationProber struct {
+	client *http.Client
+}
+
+// NewApplicationProber creates a new ApplicationProber with a shared http.Client
+func NewApplicationProber() *ApplicationProber {
+	return &ApplicationProber{
+		client: &http.Client{
+			Timeout: DefaultApplicationProbeTimeoutSeconds * time.Second,
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Probe returns whether the application listening on port answers an HTTP GET on path with a
+// success status. Connection failures are reported as not ready, since they are expected while
+// the application starts.
+func (p *ApplicationProber) Probe(ctx context.Context, service *corev1.Service, port int32, path string) bool {
+	probeURL := (&url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace), strconv.Itoa(int(port))),
+		Path:   path,
+	}).String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("Application probe connection failed", "url", probeURL, "error", err)
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return isProbeStatusSuccess(resp.StatusCode, nil)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// benchmarkWorkspace returns a workspace with the env, volumes and scheduling of a typical
+// team template after defaulting
+func benchmarkWorkspace() *workspacev1alpha1.Workspace {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "benchmark-workspace",
+			Namespace: "team-a",
+			UID:       "benchmark-uid",
+			Labels:    map[string]string{"team": "a", "cost-center": "1234"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: "Benchmark workspace",
+			Image:       "quay.io/jupyter/scipy-notebook:latest",
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("20Gi")},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "notebooks", Effect: corev1.TaintEffectNoSchedule},
+			},
+			NodeSelector: map[string]string{"node-pool": "notebooks"},
+		},
+	}
+	for i := range 20 {
+		workspace.Spec.Env = append(workspace.Spec.Env, corev1.EnvVar{Name: fmt.Sprintf("VAR_%d", i), Value: "value"})
+	}
+	for i := range 3 {
+		workspace.Spec.Volumes = append(workspace.Spec.Volumes, workspacev1alpha1.VolumeSpec{
+			Name:                      fmt.Sprintf("data-%d", i),
+			PersistentVolumeClaimName: fmt.Sprintf("team-a-data-%d", i),
+			MountPath:                 fmt.Sprintf("/data/%d", i),
+		})
+	}
+	return workspace
+}
+
+// benchmarkAccessStrategy returns an access strategy with an IngressRoute, a sidecar and merged env
+func benchmarkAccessStrategy() *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "traefik", Namespace: "jupyter-k8s-system"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{{
+				Kind:       "IngressRoute",
+				ApiVersion: "traefik.io/v1alpha1",
+				NamePrefix: "web",
+				Template: `spec:
+  entryPoints: [websecure]
+  routes:
+    - match: PathPrefix(` + "`/workspaces/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/`" + `)
+      kind: Rule
+      services:
+        - name: {{ .Service.Name }}
+          port: 8888
+`,
+			}},
+			AccessURLTemplate: "https://notebooks.example.com/workspaces/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/",
+			DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+				PodModifications: &workspacev1alpha1.PodModifications{
+					AdditionalContainers: []corev1.Container{{Name: "auth-proxy", Image: "example.com/auth-proxy:latest"}},
+					PrimaryContainerModifications: &workspacev1alpha1.PrimaryContainerModifications{
+						MergeEnv: []workspacev1alpha1.AccessEnvTemplate{
+							{Name: "JUPYTER_BASE_URL", ValueTemplate: "/workspaces/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// BenchmarkBuildDeploymentWithAccessStrategy measures building the Deployment of a workspace,
+// which runs on every reconcile of a running workspace
+func BenchmarkBuildDeploymentWithAccessStrategy(b *testing.B) {
+	scheme := runtime.NewScheme()
+	if err := workspacev1alpha1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{ApplicationImagesPullPolicy: corev1.PullIfNotPresent}, nil)
+	workspace := benchmarkWorkspace()
+	accessStrategy := benchmarkAccessStrategy()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := builder.BuildDeploymentWithAccessStrategy(ctx, workspace, accessStrategy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBuildUnstructuredResource measures rendering an access resource of a workspace
+func BenchmarkBuildUnstructuredResource(b *testing.B) {
+	builder := NewAccessResourcesBuilder()
+	workspace := benchmarkWorkspace()
+	accessStrategy := benchmarkAccessStrategy()
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateServiceName(workspace), Namespace: workspace.Namespace},
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := builder.BuildUnstructuredResource(
+			accessStrategy.Spec.AccessResourceTemplates[0], workspace, accessStrategy, service); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
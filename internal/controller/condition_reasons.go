@@ -0,0 +1,66 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// quotaExceededMessage is part of the message of the API server when a ResourceQuota rejects a create
+const quotaExceededMessage = "exceeded quota"
+
+// imagePullWaitingReasons are the waiting reasons of a container whose image cannot be pulled
+var imagePullWaitingReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// degradedReasonForError returns ReasonQuotaExceeded when a ResourceQuota rejected the create
+// that failed with err, and fallback otherwise
+func degradedReasonForError(err error, fallback string) string {
+	if apierrors.IsForbidden(err) && strings.Contains(err.Error(), quotaExceededMessage) {
+		return ReasonQuotaExceeded
+	}
+	return fallback
+}
+
+// resolveComputeFailure returns the stable reason and the message of a failure that keeps the
+// workspace pod from starting, or empty strings when there is none: the image of a container
+// cannot be pulled, or a ResourceQuota rejects the pod
+func resolveComputeFailure(
+	deployment *appsv1.Deployment,
+	podStatus *workspacev1alpha1.WorkspacePodStatus,
+) (string, string) {
+	if podStatus != nil {
+		for _, container := range podStatus.Containers {
+			if container.State == workspacev1alpha1.WorkspaceContainerStateWaiting &&
+				imagePullWaitingReasons[container.Reason] {
+				message := "Container " + container.Name + " cannot pull its image"
+				if container.Message != "" {
+					message += ": " + container.Message
+				}
+				return ReasonImagePullError, message
+			}
+		}
+	}
+	if deployment != nil {
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue &&
+				strings.Contains(condition.Message, quotaExceededMessage) {
+				return ReasonQuotaExceeded, truncatePodStatusMessage(condition.Message)
+			}
+		}
+	}
+	return "", ""
+}
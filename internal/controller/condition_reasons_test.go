@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+)
+
+func TestDegradedReasonForError(t *testing.T) {
+	pvcs := schema.GroupResource{Resource: "persistentvolumeclaims"}
+	quotaErr := apierrors.NewForbidden(pvcs, "workspace-pvc",
+		errors.New("exceeded quota: team-quota, requested: requests.storage=10Gi"))
+	rbacErr := apierrors.NewForbidden(pvcs, "workspace-pvc", errors.New("cannot create resource"))
+
+	assert.Equal(t, ReasonQuotaExceeded, degradedReasonForError(quotaErr, ReasonStorageProvisioning))
+	assert.Equal(t, ReasonQuotaExceeded,
+		degradedReasonForError(errs.Internal(quotaErr, "failed to create PVC"), ReasonStorageProvisioning))
+	assert.Equal(t, ReasonStorageProvisioning, degradedReasonForError(rbacErr, ReasonStorageProvisioning))
+	assert.Equal(t, ReasonDeploymentError, degradedReasonForError(errors.New("boom"), ReasonDeploymentError))
+}
+
+func TestResolveComputeFailure_ImagePull(t *testing.T) {
+	for _, waitingReason := range []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName"} {
+		podStatus := &workspacev1alpha1.WorkspacePodStatus{
+			Name: "workspace-pod",
+			Containers: []workspacev1alpha1.WorkspaceContainerStatus{
+				{Name: "init", Init: true, State: workspacev1alpha1.WorkspaceContainerStateTerminated, Reason: "Completed"},
+				{Name: "workspace", State: workspacev1alpha1.WorkspaceContainerStateWaiting,
+					Reason: waitingReason, Message: "manifest unknown"},
+			},
+		}
+
+		reason, message := resolveComputeFailure(nil, podStatus)
+
+		assert.Equal(t, ReasonImagePullError, reason, waitingReason)
+		assert.Equal(t, "Container workspace cannot pull its image: manifest unknown", message)
+	}
+}
+
+func TestResolveComputeFailure_QuotaExceeded(t *testing.T) {
+	deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+		Type:    appsv1.DeploymentReplicaFailure,
+		Status:  corev1.ConditionTrue,
+		Reason:  "FailedCreate",
+		Message: `pods "workspace-abc" is forbidden: exceeded quota: team-quota, requested: requests.cpu=2`,
+	}}}}
+
+	reason, message := resolveComputeFailure(deployment, nil)
+
+	assert.Equal(t, ReasonQuotaExceeded, reason)
+	assert.Contains(t, message, "exceeded quota: team-quota")
+}
+
+func TestResolveComputeFailure_None(t *testing.T) {
+	podStatus := &workspacev1alpha1.WorkspacePodStatus{
+		Name: "workspace-pod",
+		Containers: []workspacev1alpha1.WorkspaceContainerStatus{
+			{Name: "workspace", State: workspacev1alpha1.WorkspaceContainerStateWaiting, Reason: "ContainerCreating"},
+		},
+	}
+	deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated",
+	}}}}
+
+	reason, message := resolveComputeFailure(deployment, podStatus)
+
+	assert.Empty(t, reason)
+	assert.Empty(t, message)
+	reason, _ = resolveComputeFailure(nil, nil)
+	assert.Empty(t, reason)
+}
+
+func TestStatusManager_StartingStatusReportsComputeFailure(t *testing.T) {
+	ctx := context.Background()
+	fakeClient, workspace, _ := newStatusPatchTestClient(t)
+	statusManager := NewStatusManager(fakeClient)
+	snapshot := workspace.Status.DeepCopy()
+
+	readiness := WorkspaceRunningReadiness{
+		serviceReady:          true,
+		computeFailureReason:  ReasonImagePullError,
+		computeFailureMessage: "Container workspace cannot pull its image",
+	}
+	require.NoError(t, statusManager.UpdateStartingStatus(ctx, workspace, readiness, snapshot))
+
+	stored := getStatusPatchTestWorkspace(t, fakeClient)
+	degraded := FindCondition(&stored.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, ReasonImagePullError, degraded.Reason)
+	progressing := FindCondition(&stored.Status.Conditions, ConditionTypeProgressing)
+	require.NotNil(t, progressing)
+	assert.Equal(t, metav1.ConditionTrue, progressing.Status)
+}
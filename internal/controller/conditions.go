@@ -28,17 +28,57 @@ const (
 
 	// ConditionTypeDeleting indicates the Workspace is being deleted and resources are being cleaned up
 	ConditionTypeDeleting = "Deleting"
+
+	// ConditionTypeReachable indicates whether the access startup probe reached the Workspace access URL.
+	// It is only set when the access strategy defines an access startup probe.
+	ConditionTypeReachable = "Reachable"
+
+	// ConditionTypeAccessReady indicates whether all access resources of the Workspace are ready, e.g.
+	// an Ingress was assigned an address. It is only set when the Workspace uses an access strategy.
+	ConditionTypeAccessReady = "AccessReady"
+
+	// ConditionTypeStalled indicates the Workspace stayed in Progressing beyond the progressing timeout.
+	// It is only set while a starting Workspace is stuck.
+	ConditionTypeStalled = "Stalled"
+
+	// ConditionTypeDriftDetected indicates the generated resources of the Workspace were changed manually
+	// and the changes were kept. It is only set while drift remains under the Warn drift policy.
+	ConditionTypeDriftDetected = "DriftDetected"
+
+	// ConditionTypeTemplateViolation indicates the Workspace violates the current constraints of its template,
+	// e.g. after the template was tightened. It is only set while violations remain.
+	ConditionTypeTemplateViolation = "TemplateViolation"
+
+	// ConditionTypeStorageProvisioned indicates whether the storage provisioning hook prepared the
+	// volume of the Workspace. It is only set while the hook runs and once it completed.
+	ConditionTypeStorageProvisioned = "StorageProvisioned"
+
+	// ConditionTypeScaleUpPending indicates the pod of a Workspace with provisioning hints waits for
+	// node autoprovisioners to scale up a node. It is only set while the pod is unschedulable.
+	ConditionTypeScaleUpPending = "ScaleUpPending"
+
+	// ConditionTypeRescheduling indicates the node of the Workspace pod is being terminated, e.g. on a
+	// spot interruption, and the Workspace is rescheduled on another node. It is only set until the
+	// Workspace runs again.
+	ConditionTypeRescheduling = "Rescheduling"
+
+	// ConditionTypeRightSizingRecommended indicates the Workspace consistently used too little or too
+	// much of its CPU or memory requests over the right-sizing window, and recommends requests. It is
+	// only set while a recommendation stands. It is advisory.
+	ConditionTypeRightSizingRecommended = "RightSizingRecommended"
 )
 
 // Condition reasons for Workspace resources
 const (
 	// ConditionTypeAvailable and ConditionTypeProgressing reasons
-	ReasonResourcesNotReady   = "ResourcesNotReady"
-	ReasonComputeNotReady     = "ComputeNotReady"
-	ReasonServiceNotReady     = "ServiceNotReady"
-	ReasonAccessNotReady      = "AccessNotReady"
-	ReasonResourcesReady      = "ResourcesReady"
-	ReasonDesiredStateStopped = "DesiredStateStopped"
+	ReasonResourcesNotReady      = "ResourcesNotReady"
+	ReasonComputeNotReady        = "ComputeNotReady"
+	ReasonServiceNotReady        = "ServiceNotReady"
+	ReasonStorageNotReady        = "StorageNotReady"
+	ReasonAccessNotReady         = "AccessNotReady"
+	ReasonResourcesReady         = "ResourcesReady"
+	ReasonDesiredStateStopped    = "DesiredStateStopped"
+	ReasonDesiredStateHibernated = "DesiredStateHibernated"
 
 	// StoppedTypeCondition reasons and ConditionTypeProgressing reasons
 	ReasonResourcesNotStopped = "ResourcesNotStopped"
@@ -46,19 +86,70 @@ const (
 	ReasonServiceNotStopped   = "ServiceNotStopped"
 	ReasonAccessNotStopped    = "AccessNotStopped"
 	ReasonResourcesStopped    = "AllResourcesStopped"
+	ReasonResourcesHibernated = "ResourcesHibernated"
 	ReasonDesiredStateRunning = "DesiredStateRunning"
 
 	// ConditionTypeDegraded reasons
 	ReasonDeploymentError              = "ComputeError"
 	ReasonServiceError                 = "ServiceError"
+	ReasonNetworkPolicyError           = "NetworkPolicyError"
+	ReasonJupyterConfigError           = "JupyterConfigError"
+	ReasonHubActivityTokenError        = "HubActivityTokenError"
 	ReasonAccessProbeThresholdExceeded = "AccessProbeThresholdExceeded"
+	ReasonStartupDeadlineExceeded      = "StartupDeadlineExceeded"
+	ReasonRetryLimitExceeded           = "RetryLimitExceeded"
 	ReasonNoError                      = "NoError"
 
+	// Stable ConditionTypeDegraded reasons. Clients map them to remediation text instead of
+	// matching the message, so they are never renamed.
+	ReasonImagePullError       = "ImagePullError"
+	ReasonQuotaExceeded        = "QuotaExceeded"
+	ReasonTemplateNotFound     = "TemplateNotFound"
+	ReasonAccessResourceFailed = "AccessResourceFailed"
+	ReasonStorageProvisioning  = "StorageProvisioning"
+
 	// ConditionTypeAvailable reasons (special cases)
-	ReasonPreempted = "Preempted"
+	ReasonPreempted      = "Preempted"
+	ReasonAccessDisabled = "AccessDisabled"
+
+	// ConditionTypeReachable reasons
+	ReasonAccessProbeSucceeded = "AccessProbeSucceeded"
+	ReasonAccessProbePending   = "AccessProbePending"
+
+	// ConditionTypeAccessReady reasons
+	ReasonAccessResourcesReady   = "AccessResourcesReady"
+	ReasonAccessResourcesPending = "AccessResourcesPending"
 
 	// ConditionTypeDeleting reasons
 	ReasonDeletionInProgress = "DeletionInProgress"
+
+	// ConditionTypeStalled reasons
+	ReasonProgressingTimeoutExceeded = "ProgressingTimeoutExceeded"
+	ReasonStallRemediationFailed     = "StallRemediationFailed"
+
+	// ConditionTypeDriftDetected reasons
+	ReasonResourcesDrifted = "ResourcesDrifted"
+
+	// ConditionTypeStorageProvisioned reasons
+	ReasonProvisioningHookRunning   = "ProvisioningHookRunning"
+	ReasonProvisioningHookSucceeded = "ProvisioningHookSucceeded"
+	ReasonProvisioningHookFailed    = "ProvisioningHookFailed"
+
+	// ConditionTypeScaleUpPending reasons
+	ReasonWaitingForScaleUp = "WaitingForScaleUp"
+
+	// ConditionTypeRescheduling reasons
+	ReasonNodeInterrupted = "NodeInterrupted"
+
+	// ConditionTypeRightSizingRecommended reasons
+	ReasonOverprovisioned    = "Overprovisioned"
+	ReasonUnderprovisioned   = "Underprovisioned"
+	ReasonRequestsMismatched = "RequestsMismatched"
+
+	// ConditionTypeTemplateViolation reasons, one per template apply mode
+	ReasonTemplateUpdatesBlocked   = "TemplateUpdatesBlocked"
+	ReasonTemplateMigrationPending = "TemplateMigrationPending"
+	ReasonTemplateViolationAudited = "TemplateViolationAudited"
 )
 
 // NewCondition creates a new condition with the specified status
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
@@ -18,12 +19,65 @@ const (
 	// DefaultMemoryRequest is the default memory request for workspace containers
 	DefaultMemoryRequest = "128Mi"
 
-	// JupyterPort is the default port for Jupyter server
+	// JupyterPort is the default port for Jupyter server, and the "http" port of the workspace Service
+	// whatever the application type, so that access strategies route to every application alike
 	JupyterPort = 8888
 
 	// DefaultMountPath is the default mount path for workspace storage
 	DefaultMountPath = "/home/jovyan"
 
+	// SSHServicePort is the port of the Service exposing the SSH server of a workspace
+	SSHServicePort = 22
+	// DefaultSSHPort is the default port on which the SSH server listens in the workspace pod
+	DefaultSSHPort = 2222
+	// SSHContainerName is the name of the sidecar container running the SSH server
+	SSHContainerName = "sshd"
+	// SSHAuthorizedKeysVolumeName is the name of the volume holding the authorized SSH keys
+	SSHAuthorizedKeysVolumeName = "ssh-authorized-keys"
+	// SSHAuthorizedKeysMountPath is the directory where the authorized SSH keys are mounted
+	SSHAuthorizedKeysMountPath = "/etc/ssh/workspace"
+	// SSHAuthorizedKeysFileName is the key of the authorized keys file in the SSH keys Secret
+	SSHAuthorizedKeysFileName = "authorized_keys"
+	// SSHAuthorizedKeysFileEnvVar tells the SSH server where to read the authorized keys from
+	SSHAuthorizedKeysFileEnvVar = "SSH_AUTHORIZED_KEYS_FILE"
+	// SSHPortEnvVar tells the SSH server which port to listen on
+	SSHPortEnvVar = "SSH_PORT"
+
+	// JupyterConfigVolumeName is the name of the volume holding the generated Jupyter configuration
+	JupyterConfigVolumeName = "jupyter-config"
+	// JupyterServerConfigDir is the Jupyter config directory where the generated server configuration
+	// is mounted. It takes precedence over /etc/jupyter, where images such as the Jupyter Docker
+	// Stacks keep their own configuration.
+	JupyterServerConfigDir = "/usr/local/etc/jupyter"
+	// JupyterServerConfigFileName is the key of the Jupyter server configuration in the Jupyter config ConfigMap
+	JupyterServerConfigFileName = "jupyter_server_config.py"
+	// JupyterLabOverridesFileName is the key of the JupyterLab settings overrides in the Jupyter config ConfigMap
+	JupyterLabOverridesFileName = "overrides.json"
+	// DefaultJupyterLabSettingsDir is the JupyterLab application settings directory of the Jupyter Docker Stacks images
+	DefaultJupyterLabSettingsDir = "/opt/conda/share/jupyter/lab/settings"
+
+	// S3CSIDriver is the CSI driver of Mountpoint for Amazon S3
+	S3CSIDriver = "s3.csi.aws.com"
+	// GCSCSIDriver is the CSI driver of Cloud Storage FUSE
+	GCSCSIDriver = "gcsfuse.csi.storage.gke.io"
+	// GCSFuseVolumesAnnotation makes GKE inject the Cloud Storage FUSE sidecar in the workspace pod
+	GCSFuseVolumesAnnotation = "gke-gcsfuse/volumes"
+	// ObjectStorageVolumePrefix prefixes the names of the volumes and sync sidecars of object storage mounts
+	ObjectStorageVolumePrefix = "object-storage-"
+	// ObjectStorageURLEnvVar tells the sync sidecar which bucket URL to sync, e.g. s3://bucket/prefix
+	ObjectStorageURLEnvVar = "OBJECT_STORAGE_URL"
+	// ObjectStorageMountPathEnvVar tells the sync sidecar which directory to sync the bucket to
+	ObjectStorageMountPathEnvVar = "OBJECT_STORAGE_MOUNT_PATH"
+	// ObjectStorageReadOnlyEnvVar tells the sync sidecar to only download the bucket
+	ObjectStorageReadOnlyEnvVar = "OBJECT_STORAGE_READ_ONLY"
+
+	// WorkspaceNameEnvVar, WorkspaceNamespaceEnvVar, WorkspaceOwnerEnvVar and StorageMountPathEnvVar
+	// tell the storage provisioning hook which workspace and volume it prepares
+	WorkspaceNameEnvVar      = "WORKSPACE_NAME"
+	WorkspaceNamespaceEnvVar = "WORKSPACE_NAMESPACE"
+	WorkspaceOwnerEnvVar     = "WORKSPACE_OWNER"
+	StorageMountPathEnvVar   = "STORAGE_MOUNT_PATH"
+
 	// AppLabel is the label key for application identification
 	AppLabel = "app"
 
@@ -45,11 +99,17 @@ const (
 
 	// AppLabelValue is the label value for app label
 	AppLabelValue = "jupyter"
+	// ProvisioningHookComponent is the component label value of the storage provisioning hook Job and
+	// pod. The pod does not carry the workspace labels, so that it is never mistaken for the workspace pod.
+	ProvisioningHookComponent = "provisioning-hook"
 
 	// AnnotationCreatedBy is the annotation key for tracking resource creator
 	AnnotationCreatedBy = "workspace.jupyter.org/created-by"
 	// AnnotationLastUpdatedBy is the annotation key for tracking last updater
 	AnnotationLastUpdatedBy = "workspace.jupyter.org/last-updated-by"
+	// AnnotationResourceNamePrefix is the annotation key recording the prefix of the names of the
+	// child resources of a workspace
+	AnnotationResourceNamePrefix = "workspace.jupyter.org/resource-name-prefix"
 	// AnnotationServiceAccountUsers is the annotation key for service account users
 	AnnotationServiceAccountUsers = "workspace.jupyter.org/service-account-users"
 	// AnnotationServiceAccountUserPatterns is the annotation key for service account user patterns
@@ -61,6 +121,9 @@ const (
 	DesiredStateRunning = "Running"
 	// DesiredStateStopped indicates the workspace is stopped
 	DesiredStateStopped = "Stopped"
+	// DesiredStateHibernated indicates the workspace is stopped and only retains its storage
+	// and a placeholder of its access URL
+	DesiredStateHibernated = "Hibernated"
 
 	// PreemptedReason is the reason for preempted workspaces
 	PreemptedReason = "Workspace preempted due to resource contention"
@@ -68,6 +131,74 @@ const (
 	// PreemptionReasonAnnotation is the annotation key for preemption reason
 	PreemptionReasonAnnotation = "workspace.jupyter.org/preemption-reason"
 
+	// AnnotationAccessStrategyMigrationTarget is the annotation key requesting the migration of a
+	// running workspace to another access strategy, as "<name>" or "<namespace>/<name>"
+	AnnotationAccessStrategyMigrationTarget = "workspace.jupyter.org/migrate-access-strategy-to"
+
+	// AnnotationRemoteAccessHandler is the pod annotation key recording the podEventsHandler
+	// (e.g. "aws:ssm-remote-access") that set up the remote access of the pod.
+	// It is set along with RemoteAccessFinalizerName, and tells which plugin releases the
+	// remote access resources of the pod on deletion.
+	AnnotationRemoteAccessHandler = "workspace.jupyter.org/remote-access-handler"
+
+	// AnnotationGPUSharingMode is the pod annotation key recording the GPU sharing mode of the
+	// workspace, when it shares its GPUs with other pods (TimeSliced or MPS)
+	AnnotationGPUSharingMode = "workspace.jupyter.org/gpu-sharing-mode"
+
+	// AnnotationDesiredStateHash is the annotation key recording, on the Deployment, Service and
+	// access resources of a workspace, the hash of the state the controller last applied to them.
+	// It tells manual changes to a resource apart from changes of its desired state.
+	AnnotationDesiredStateHash = "workspace.jupyter.org/desired-state-hash"
+
+	// AnnotationProvisioningHook is the PVC annotation key recording whether the storage provisioning
+	// hook of the workspace ran on the volume. The controller sets it to pending when it creates the
+	// PVC, so the hook only runs on new volumes.
+	AnnotationProvisioningHook = "workspace.jupyter.org/provisioning-hook"
+	// ProvisioningHookPending and ProvisioningHookSucceeded are the values of AnnotationProvisioningHook
+	ProvisioningHookPending   = "pending"
+	ProvisioningHookSucceeded = "succeeded"
+
+	// AnnotationIdleStopScheduledAt is the annotation key recording, on a workspace whose owner was
+	// notified that it is idle, the time the controller stops it at, in RFC 3339 format
+	AnnotationIdleStopScheduledAt = "workspace.jupyter.org/idle-stop-scheduled-at"
+	// AnnotationKeepAlive is the annotation key the owner of a workspace sets to the current time,
+	// in RFC 3339 format, to cancel a scheduled idle stop during its grace period
+	AnnotationKeepAlive = "workspace.jupyter.org/keep-alive"
+	// AnnotationHubLastActivity is the annotation key recording the last activity that the workspace
+	// reported to the JupyterHub activity API, in RFC 3339 format
+	AnnotationHubLastActivity = "workspace.jupyter.org/hub-last-activity"
+
+	// LabelWorkspacePool is the label key recording, on a standby pod, the WorkspacePool it belongs to
+	LabelWorkspacePool = "workspace.jupyter.org/pool-name"
+	// LabelStandbyState is the label key recording whether a standby pod is available or was
+	// claimed by a workspace. A claimed pod is deleted and replaced by the pool controller.
+	LabelStandbyState = "workspace.jupyter.org/standby-state"
+	// StandbyStateAvailable and StandbyStateClaimed are the values of LabelStandbyState
+	StandbyStateAvailable = "available"
+	StandbyStateClaimed   = "claimed"
+	// AnnotationClaimedBy is the annotation key recording, on a claimed standby pod, the workspace
+	// that claimed it, as "<namespace>/<name>"
+	AnnotationClaimedBy = "workspace.jupyter.org/claimed-by"
+
+	// AnnotationRestartedAt is the pod annotation key recording the spec.restartRequestedAt of the
+	// workspace, in RFC 3339 format, so that a new restart request recreates the pod
+	AnnotationRestartedAt = "workspace.jupyter.org/restarted-at"
+	// AnnotationTemplateImage is the annotation key recording, on a workspace whose image was
+	// defaulted from its template, that default image. The workspace follows the default image of
+	// its template, according to the template update strategy, while its image matches it.
+	AnnotationTemplateImage = "workspace.jupyter.org/template-image"
+	// AnnotationJupyterConfigHash is the pod annotation key recording a hash of the Jupyter
+	// configuration generated for the workspace, so that configuration changes recreate the pod
+	AnnotationJupyterConfigHash = "workspace.jupyter.org/jupyter-config-hash"
+
+	// LabelShard is the label key recording the shard key of a workspace, a hash of its namespace,
+	// by which sharded manager replicas select the workspaces they reconcile
+	LabelShard = "workspace.jupyter.org/shard"
+
+	// AnnotationRetainedFor is the annotation key recording, on the PVC a deleted workspace retained,
+	// the owner of that workspace. Only a new workspace with the same name and owner adopts the PVC.
+	AnnotationRetainedFor = "workspace.jupyter.org/retained-for"
+
 	// KindPod represents the Pod resource kind
 	KindPod = "Pod"
 
@@ -81,6 +212,12 @@ const (
 	// DefaultDesiredStatus is the default desired status for workspaces
 	DefaultDesiredStatus = "Running"
 
+	// DefaultApplicationProbePeriod is the interval between the probes of the health endpoints of the
+	// additional applications of a running workspace
+	DefaultApplicationProbePeriod = 30 * time.Second
+	// DefaultApplicationProbeTimeoutSeconds is the timeout of a probe of an additional application
+	DefaultApplicationProbeTimeoutSeconds = 2
+
 	// DefaultAccessStartupProbePeriodSeconds is the default probe interval in seconds
 	DefaultAccessStartupProbePeriodSeconds = 2
 	// DefaultAccessStartupProbeTimeoutSeconds is the default probe timeout in seconds
@@ -88,6 +225,33 @@ const (
 	// DefaultAccessStartupProbeFailureThreshold is the default max consecutive failures
 	DefaultAccessStartupProbeFailureThreshold = 20
 
+	// DefaultWorkspaceProbePath is the Jupyter endpoint hit by the default workspace probes
+	DefaultWorkspaceProbePath = "/api/status"
+	// JupyterBaseURLEnvVar is the environment variable holding the base URL of the Jupyter server
+	JupyterBaseURLEnvVar = workspaceutil.JupyterBaseURLEnvVar
+
+	// HubActivityTokenSecretKey is the key of the token authenticating the activity reports of a
+	// workspace in its hub activity token Secret
+	HubActivityTokenSecretKey = "token"
+	// JupyterHub environment variables set in workspaces reporting their activity to the JupyterHub
+	// activity API, as jupyterhub-singleuser and hub-aware extensions read them
+	JupyterHubAPIURLEnvVar      = "JUPYTERHUB_API_URL"
+	JupyterHubActivityURLEnvVar = "JUPYTERHUB_ACTIVITY_URL"
+	JupyterHubAPITokenEnvVar    = "JUPYTERHUB_API_TOKEN"
+	JupyterHubUserEnvVar        = "JUPYTERHUB_USER"
+	// DefaultStartupProbePeriodSeconds and DefaultStartupProbeFailureThreshold give
+	// slow-starting images up to 10 minutes to serve their first request
+	DefaultStartupProbePeriodSeconds    = 5
+	DefaultStartupProbeFailureThreshold = 120
+	// DefaultReadinessProbePeriodSeconds is the default readiness probe interval in seconds
+	DefaultReadinessProbePeriodSeconds    = 10
+	DefaultReadinessProbeFailureThreshold = 3
+	// DefaultLivenessProbePeriodSeconds is the default liveness probe interval in seconds
+	DefaultLivenessProbePeriodSeconds    = 30
+	DefaultLivenessProbeFailureThreshold = 5
+	// DefaultWorkspaceProbeTimeoutSeconds is the default timeout of the workspace probes
+	DefaultWorkspaceProbeTimeoutSeconds = 5
+
 	// ProbeBackoffThreshold is the number of retries (counting from the end)
 	// that use exponential backoff. The first max(0, failureThreshold - this)
 	// retries use the configured periodSeconds; the last 10 use backoff.
@@ -97,6 +261,13 @@ const (
 	// (default 30s, SOA-driven up to 300s).
 	ProbeBackoffMaxRetrySeconds = 302
 
+	// DefaultFailurePolicyRetryLimit is the default number of container restarts tolerated by a failure policy
+	DefaultFailurePolicyRetryLimit = 5
+	// DefaultFailurePolicyBackoffSeconds is the default delay before checking a failing workspace again
+	DefaultFailurePolicyBackoffSeconds = 10
+	// DefaultFailurePolicyMaxBackoffSeconds caps the default delay between checks of a failing workspace
+	DefaultFailurePolicyMaxBackoffSeconds = 300
+
 	// MinimalRequeueDelay is the delay for near-immediate requeue
 	MinimalRequeueDelay = 10 * time.Millisecond
 	// PollRequeueDelay is the delay for polling reconciliation
@@ -113,19 +284,36 @@ const (
 	// floor are clamped up to it.
 	MinIdleCheckInterval = 1 * time.Second
 
+	// DefaultProgressingTimeout is how long a starting workspace may stay in the same Progressing
+	// state before the progressing watchdog flags it as stalled
+	DefaultProgressingTimeout = 15 * time.Minute
+
+	// DeletionStopTimeout bounds how long deletion waits for the workspace pods to shut down
+	// gracefully before the remaining resources and the finalizer are removed anyway
+	DeletionStopTimeout = 5 * time.Minute
+
 	// IdleProbeTimeout is the per-request timeout for network-based idle detection probes
 	IdleProbeTimeout = 10 * time.Second
 
 	// WorkspaceFinalizerName is the finalizer name for workspace cleanup protection
 	WorkspaceFinalizerName = "workspace.jupyter.org/workspace-protection"
 
+	// RemoteAccessFinalizerName is the finalizer holding workspace pods until the remote access
+	// resources set up by their pod events handler are released
+	RemoteAccessFinalizerName = "workspace.jupyter.org/remote-access-cleanup"
+
+	// VanityURLFinalizerName is the finalizer holding workspaces until their vanity URL
+	// is deregistered
+	VanityURLFinalizerName = "workspace.jupyter.org/vanity-url-cleanup"
+
 	// ControllerPodNamespaceEnv is the environment variable for the controller pod namespace
 	ControllerPodNamespaceEnv = "CONTROLLER_POD_NAMESPACE"
 
 	// ControllerPodServiceAccountEnv is the environment variable for the controller pod service account
 	ControllerPodServiceAccountEnv = "CONTROLLER_POD_SERVICE_ACCOUNT"
 
-	// ResourcePrefix is the prefix for workspace resource names
+	// ResourcePrefix is the default prefix for workspace resource names, and the prefix of the
+	// child resources of workspaces created before the prefix was configurable
 	ResourcePrefix = "workspace"
 
 	// ReservedMetadataPrefix is the prefix reserved for system-managed labels and annotations
@@ -143,12 +331,30 @@ const (
 	// volumeNameWorkspaceStorage is the volume name for workspace storage
 	volumeNameWorkspaceStorage = "workspace-storage"
 
+	// volumeNameTmp is the volume name of the writable /tmp of workspaces with a read-only root filesystem
+	volumeNameTmp = "tmp"
+	// tmpMountPath is the mount path of the writable /tmp volume
+	tmpMountPath = "/tmp"
+
 	// kindIngressRoute is the Traefik IngressRoute resource kind
 	kindIngressRoute = "IngressRoute"
 	// kindMiddleware is the Traefik Middleware resource kind
 	kindMiddleware = "Middleware"
+	// kindServersTransport is the Traefik ServersTransport resource kind
+	kindServersTransport = "ServersTransport"
+	// traefikAPIGroup is the Traefik CRD API group
+	traefikAPIGroup = "traefik.io"
+	// traefikAPIGroupVersion is the Traefik CRD API version within traefikAPIGroup
+	traefikAPIGroupVersion = "v1alpha1"
 	// traefikAPIVersion is the Traefik CRD API version
-	traefikAPIVersion = "traefik.io/v1alpha1"
+	traefikAPIVersion = traefikAPIGroup + "/" + traefikAPIGroupVersion
+
+	// kindVolumeSnapshot is the CSI VolumeSnapshot resource kind
+	kindVolumeSnapshot = "VolumeSnapshot"
+	// volumeSnapshotAPIGroup is the CSI snapshot API group
+	volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	// volumeSnapshotAPIVersion is the CSI snapshot API version
+	volumeSnapshotAPIVersion = volumeSnapshotAPIGroup + "/v1"
 )
 
 // MetadataKeyPolicy defines how a system-managed metadata key behaves across operations
@@ -164,28 +370,97 @@ const (
 // SystemManagedMetadataKeys defines all workspace.jupyter.org/ prefixed keys that the system manages.
 // Any new system-managed key with the reserved prefix MUST be added here.
 var SystemManagedMetadataKeys = map[string]MetadataKeyPolicy{
-	AnnotationCreatedBy:             SetOnCreateOnly,
-	AnnotationLastUpdatedBy:         SetAlways,
-	PreemptionReasonAnnotation:      SetAlways,
-	LabelWorkspaceTemplate:          SetAlways,
-	LabelWorkspaceTemplateNamespace: SetAlways,
-	LabelAccessStrategyName:         SetAlways,
-	LabelAccessStrategyNamespace:    SetAlways,
+	AnnotationCreatedBy:                     SetOnCreateOnly,
+	AnnotationLastUpdatedBy:                 SetAlways,
+	AnnotationResourceNamePrefix:            SetOnCreateOnly,
+	PreemptionReasonAnnotation:              SetAlways,
+	LabelWorkspaceTemplate:                  SetAlways,
+	LabelWorkspaceTemplateNamespace:         SetAlways,
+	LabelAccessStrategyName:                 SetAlways,
+	LabelAccessStrategyNamespace:            SetAlways,
+	AnnotationKeepAlive:                     SetAlways,
+	AnnotationAccessStrategyMigrationTarget: SetAlways,
+	AnnotationTemplateImage:                 SetAlways,
 }
 
 // GenerateDeploymentName creates a consistent deployment name
-func GenerateDeploymentName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s", ResourcePrefix, workspaceName)
+func GenerateDeploymentName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s", ResourceNamePrefix(workspace), workspace.Name)
 }
 
 // GenerateServiceName creates a consistent service name
-func GenerateServiceName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s-service", ResourcePrefix, workspaceName)
+func GenerateServiceName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-service", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateNetworkPolicyName creates a consistent NetworkPolicy name
+func GenerateNetworkPolicyName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-network-policy", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateJupyterConfigMapName creates a consistent name for the ConfigMap holding the generated
+// Jupyter configuration of a workspace
+func GenerateJupyterConfigMapName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-jupyter-config", ResourceNamePrefix(workspace), workspace.Name)
 }
 
 // GeneratePVCName creates a consistent PVC name
-func GeneratePVCName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s-pvc", ResourcePrefix, workspaceName)
+func GeneratePVCName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-pvc", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateProvisioningJobName creates a consistent name for the storage provisioning hook Job
+func GenerateProvisioningJobName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-provision", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateRetentionSnapshotName creates a consistent name for the WorkspaceSnapshot taken of the
+// storage of a deleted workspace. It includes the workspace UID, so that workspaces later created
+// with the same name get their own snapshot.
+func GenerateRetentionSnapshotName(workspace *workspacev1alpha1.Workspace) string {
+	uid := string(workspace.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-deleted-%s", workspace.Name, uid)
+}
+
+// GenerateVolumeSnapshotName creates a consistent VolumeSnapshot name for a WorkspaceSnapshot
+func GenerateVolumeSnapshotName(snapshotName string) string {
+	return fmt.Sprintf("%s-%s-snapshot", ResourcePrefix, snapshotName)
+}
+
+// GenerateSSHServiceName creates a consistent name for the Service exposing the SSH server of a workspace
+func GenerateSSHServiceName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-ssh", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateTraefikServersTransportName creates a consistent name for the Traefik ServersTransport of a workspace
+func GenerateTraefikServersTransportName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-transport", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateSSHKeysSecretName creates a consistent name for the Secret holding the authorized SSH keys of a workspace
+func GenerateSSHKeysSecretName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-ssh-keys", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateAccessSecretsName creates a consistent name for the Secret keeping the values generated
+// for the access resource Secrets of a workspace
+func GenerateAccessSecretsName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-access-secrets", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// GenerateHubActivityTokenSecretName creates a consistent name for the Secret holding the token
+// with which a workspace reports its activity to the JupyterHub activity API
+func GenerateHubActivityTokenSecretName(workspace *workspacev1alpha1.Workspace) string {
+	return fmt.Sprintf("%s-%s-hub-token", ResourceNamePrefix(workspace), workspace.Name)
+}
+
+// HubActivityAPIPath returns the path of the JupyterHub API of a workspace on the JupyterHub
+// activity API server. The workspace reports its activity as the user named after it.
+func HubActivityAPIPath(namespace string) string {
+	return fmt.Sprintf("/namespaces/%s/hub/api", namespace)
 }
 
 // GenerateLabels creates consistent labels for resources
@@ -0,0 +1,73 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsCullingExempt returns true when the workspace's culling policy protects it from
+// automated stops at the given time. Every subsystem that stops a running workspace
+// on its own initiative must check this before doing so.
+func IsCullingExempt(workspace *workspacev1alpha1.Workspace, now time.Time) bool {
+	policy := workspace.Spec.CullingPolicy
+	if policy == nil {
+		return false
+	}
+	if policy.Disabled {
+		return true
+	}
+	return policy.KeepAliveUntil != nil && now.Before(policy.KeepAliveUntil.Time)
+}
+
+// cullingExemptionRemaining returns how long the current keep-alive exemption lasts,
+// or zero when the workspace is not exempt or is exempt indefinitely
+func cullingExemptionRemaining(workspace *workspacev1alpha1.Workspace, now time.Time) time.Duration {
+	policy := workspace.Spec.CullingPolicy
+	if policy == nil || policy.Disabled || policy.KeepAliveUntil == nil {
+		return 0
+	}
+	if remaining := policy.KeepAliveUntil.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// resolveCullingStatus computes the effective culling policy reported in status,
+// or nil when the workspace does not set a culling policy
+func resolveCullingStatus(workspace *workspacev1alpha1.Workspace, now time.Time) *workspacev1alpha1.CullingStatus {
+	policy := workspace.Spec.CullingPolicy
+	if policy == nil {
+		return nil
+	}
+
+	switch {
+	case policy.Disabled:
+		return &workspacev1alpha1.CullingStatus{
+			Exempt:  true,
+			Message: "Culling is disabled for this workspace",
+		}
+	case policy.KeepAliveUntil == nil:
+		return &workspacev1alpha1.CullingStatus{
+			Message: "No culling exemption is set",
+		}
+	case now.Before(policy.KeepAliveUntil.Time):
+		exemptUntil := metav1.NewTime(policy.KeepAliveUntil.Time)
+		return &workspacev1alpha1.CullingStatus{
+			Exempt:      true,
+			ExemptUntil: &exemptUntil,
+			Message:     fmt.Sprintf("Culling is suspended until %s", policy.KeepAliveUntil.UTC().Format(time.RFC3339)),
+		}
+	default:
+		return &workspacev1alpha1.CullingStatus{
+			Message: fmt.Sprintf("Keep-alive expired at %s", policy.KeepAliveUntil.UTC().Format(time.RFC3339)),
+		}
+	}
+}
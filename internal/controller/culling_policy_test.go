@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsCullingExempt(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		policy   *workspacev1alpha1.CullingPolicySpec
+		expected bool
+	}{
+		{name: "no policy", policy: nil, expected: false},
+		{name: "empty policy", policy: &workspacev1alpha1.CullingPolicySpec{}, expected: false},
+		{name: "disabled", policy: &workspacev1alpha1.CullingPolicySpec{Disabled: true}, expected: true},
+		{
+			name:     "keep-alive in the future",
+			policy:   &workspacev1alpha1.CullingPolicySpec{KeepAliveUntil: &metav1.Time{Time: now.Add(time.Hour)}},
+			expected: true,
+		},
+		{
+			name:     "keep-alive expired",
+			policy:   &workspacev1alpha1.CullingPolicySpec{KeepAliveUntil: &metav1.Time{Time: now.Add(-time.Hour)}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{CullingPolicy: tt.policy}}
+			assert.Equal(t, tt.expected, IsCullingExempt(ws, now))
+		})
+	}
+}
+
+func TestResolveCullingStatus(t *testing.T) {
+	now := time.Now()
+	keepAlive := metav1.NewTime(now.Add(time.Hour))
+
+	ws := &workspacev1alpha1.Workspace{}
+	assert.Nil(t, resolveCullingStatus(ws, now))
+
+	ws.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{Disabled: true}
+	status := resolveCullingStatus(ws, now)
+	require.NotNil(t, status)
+	assert.True(t, status.Exempt)
+	assert.Nil(t, status.ExemptUntil)
+
+	ws.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{KeepAliveUntil: &keepAlive}
+	status = resolveCullingStatus(ws, now)
+	require.NotNil(t, status)
+	assert.True(t, status.Exempt)
+	require.NotNil(t, status.ExemptUntil)
+	assert.True(t, status.ExemptUntil.Equal(&keepAlive))
+
+	status = resolveCullingStatus(ws, now.Add(2*time.Hour))
+	require.NotNil(t, status)
+	assert.False(t, status.Exempt)
+	assert.Nil(t, status.ExemptUntil)
+}
+
+func TestHandleIdleShutdown_SkipsCullingExemptWorkspace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	sm := &StateMachine{
+		resourceManager: NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil),
+		recorder:        record.NewFakeRecorder(10),
+		idleChecker:     NewWorkspaceIdleChecker(fakeClient, time.Hour),
+	}
+
+	ws := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "exempt", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DesiredStatus: DesiredStateRunning,
+			IdleShutdown: &workspacev1alpha1.IdleShutdownSpec{
+				Enabled:              true,
+				IdleTimeoutInMinutes: 1,
+			},
+			CullingPolicy: &workspacev1alpha1.CullingPolicySpec{
+				KeepAliveUntil: &metav1.Time{Time: time.Now().Add(10 * time.Minute)},
+			},
+		},
+	}
+
+	result, err := sm.handleIdleShutdownForRunningWorkspace(context.Background(), ws, nil)
+	require.NoError(t, err)
+	// Requeued when the keep-alive expires rather than at the idle check interval
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.LessOrEqual(t, result.RequeueAfter, 10*time.Minute)
+	assert.Equal(t, DesiredStateRunning, ws.Spec.DesiredStatus)
+}
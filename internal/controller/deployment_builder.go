@@ -8,8 +8,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -33,7 +37,7 @@ func NewDeploymentBuilder(scheme *runtime.Scheme, options WorkspaceControllerOpt
 	return &DeploymentBuilder{
 		scheme:        scheme,
 		options:       options,
-		imageResolver: NewImageResolver(options.ApplicationImagesRegistry),
+		imageResolver: NewImageResolver(options.ApplicationImagesRegistry).WithOperatorConfig(options.OperatorConfig),
 	}
 }
 
@@ -46,6 +50,19 @@ func (db *DeploymentBuilder) BuildDeployment(ctx context.Context, workspace *wor
 		Spec:       db.buildDeploymentSpec(workspace, resources),
 	}
 
+	if err := applyObjectStorageMounts(deployment, workspace, db.options.ObjectStorageSyncImage); err != nil {
+		return nil, err
+	}
+
+	applyGPUSharing(&deployment.Spec.Template, workspace)
+	applyProvisioningHints(&deployment.Spec.Template, workspace)
+	applyPoolClaimAffinity(&deployment.Spec.Template, workspace)
+	applyGracefulShutdown(&deployment.Spec.Template, workspace)
+	applyHubActivityEnv(&deployment.Spec.Template, workspace, db.options.HubActivityAPIURL)
+	if err := applyJupyterServerConfig(&deployment.Spec.Template, workspace); err != nil {
+		return nil, err
+	}
+
 	if err := controllerutil.SetControllerReference(workspace, deployment, db.scheme); err != nil {
 		return nil, fmt.Errorf("failed to set controller reference: %w", err)
 	}
@@ -72,6 +89,10 @@ func (db *DeploymentBuilder) BuildDeploymentWithAccessStrategy(
 		}
 	}
 
+	applyApplicationBaseURL(&deployment.Spec.Template.Spec.Containers[0], workspace)
+	applyWorkspaceProbes(&deployment.Spec.Template.Spec.Containers[0], workspace)
+	applySecurityProfile(&deployment.Spec.Template.Spec, workspace)
+
 	return deployment, nil
 }
 
@@ -96,7 +117,7 @@ func (db *DeploymentBuilder) buildObjectMeta(workspace *workspacev1alpha1.Worksp
 	}
 
 	return metav1.ObjectMeta{
-		Name:        GenerateDeploymentName(workspace.Name),
+		Name:        GenerateDeploymentName(workspace),
 		Namespace:   workspace.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
@@ -129,6 +150,14 @@ func (db *DeploymentBuilder) buildPodAnnotations(workspace *workspacev1alpha1.Wo
 		}
 	}
 
+	// A new restart request changes the pod template, which recreates the pod
+	if restartRequestedAt := workspace.Spec.RestartRequestedAt; restartRequestedAt != nil {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[AnnotationRestartedAt] = restartRequestedAt.UTC().Format(time.RFC3339)
+	}
+
 	return annotations
 }
 
@@ -170,7 +199,7 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 				Name: volumeNameWorkspaceStorage,
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: GeneratePVCName(workspace.Name),
+						ClaimName: GeneratePVCName(workspace),
 					},
 				},
 			},
@@ -184,12 +213,8 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 			continue
 		}
 		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
-			Name: vol.Name,
-			VolumeSource: corev1.VolumeSource{
-				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: vol.PersistentVolumeClaimName,
-				},
-			},
+			Name:         vol.Name,
+			VolumeSource: buildVolumeSource(vol),
 		})
 	}
 
@@ -206,10 +231,18 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 		podSpec.Tolerations = workspace.Spec.Tolerations
 	}
 
+	podSpec.PriorityClassName = workspace.Spec.PriorityClassName
+
+	if workspace.Spec.RuntimeClassName != "" {
+		podSpec.RuntimeClassName = ptr.To(workspace.Spec.RuntimeClassName)
+	}
+
 	if workspace.Spec.ServiceAccountName != "" {
 		podSpec.ServiceAccountName = workspace.Spec.ServiceAccountName
 	}
 
+	podSpec.ImagePullSecrets = db.buildImagePullSecrets(workspace)
+
 	// Apply pod security context
 	if workspace.Spec.PodSecurityContext != nil {
 		podSpec.SecurityContext = workspace.Spec.PodSecurityContext
@@ -223,6 +256,28 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 	return podSpec
 }
 
+// buildImagePullSecrets merges the image pull secrets of the workspace, which already include those
+// of its template, with the operator default secret
+func (db *DeploymentBuilder) buildImagePullSecrets(workspace *workspacev1alpha1.Workspace) []corev1.LocalObjectReference {
+	secrets := slices.Clone(workspace.Spec.ImagePullSecrets)
+	defaultSecret := db.options.ApplicationImagesPullSecret
+	if db.options.OperatorConfig != nil {
+		defaultSecret = db.options.OperatorConfig.Settings().ApplicationImagesPullSecret
+	}
+	if defaultSecret != "" && !slices.ContainsFunc(secrets, func(s corev1.LocalObjectReference) bool { return s.Name == defaultSecret }) {
+		secrets = append(secrets, corev1.LocalObjectReference{Name: defaultSecret})
+	}
+	return secrets
+}
+
+// imagesPullPolicy returns the image pull policy of the application containers
+func (db *DeploymentBuilder) imagesPullPolicy() corev1.PullPolicy {
+	if db.options.OperatorConfig != nil {
+		return db.options.OperatorConfig.Settings().ApplicationImagesPullPolicy
+	}
+	return db.options.ApplicationImagesPullPolicy
+}
+
 // buildPrimaryContainer creates the container specification
 func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.Workspace, resources corev1.ResourceRequirements) corev1.Container {
 	image := db.imageResolver.ResolveImage(workspace)
@@ -238,20 +293,15 @@ func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.
 	container := corev1.Container{
 		Name:            ResourcePrefix,
 		Image:           image,
-		ImagePullPolicy: db.options.ApplicationImagesPullPolicy,
+		ImagePullPolicy: db.imagesPullPolicy(),
 		SecurityContext: workspace.Spec.ContainerSecurityContext,
 		Command:         command,
 		Args:            args,
 		Lifecycle:       workspace.Spec.Lifecycle,
-		Env:             workspace.Spec.Env,
-		Ports: []corev1.ContainerPort{
-			{
-				Name:          httpScheme,
-				ContainerPort: JupyterPort,
-				Protocol:      corev1.ProtocolTCP,
-			},
-		},
-		Resources: resources,
+		Env:             buildContainerEnv(workspace),
+		EnvFrom:         workspace.Spec.EnvFrom,
+		Ports:           buildContainerPorts(workspace),
+		Resources:       resources,
 		// ReadinessProbe gates the pod from Service endpoints until the IDE is
 		// actually accepting connections, preventing transient 502s on startup.
 		// nil when neither workspace nor template configures one (opt-in via config).
@@ -277,12 +327,63 @@ func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.
 		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
 			Name:      vol.Name,
 			MountPath: vol.MountPath,
+			SubPath:   vol.SubPath,
+			ReadOnly:  vol.ReadOnly,
 		})
 	}
 
 	return container
 }
 
+// buildVolumeSource returns the NFS export or the existing PVC an additional volume mounts
+func buildVolumeSource(vol workspacev1alpha1.VolumeSpec) corev1.VolumeSource {
+	if vol.NFS != nil {
+		return corev1.VolumeSource{
+			NFS: &corev1.NFSVolumeSource{
+				Server:   vol.NFS.Server,
+				Path:     vol.NFS.Path,
+				ReadOnly: vol.ReadOnly,
+			},
+		}
+	}
+	return corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: vol.PersistentVolumeClaimName,
+			ReadOnly:  vol.ReadOnly,
+		},
+	}
+}
+
+// buildContainerPorts returns the "http" port of the main application, which depends on
+// spec.applicationType, followed by spec.ports
+func buildContainerPorts(workspace *workspacev1alpha1.Workspace) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{
+		{
+			Name:          httpScheme,
+			ContainerPort: workspaceutil.GetApplication(workspace).Port,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	for _, port := range workspace.Spec.Ports {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.Port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+// buildContainerEnv returns spec.env followed by the workspace-only spec.extraEnv
+func buildContainerEnv(workspace *workspacev1alpha1.Workspace) []corev1.EnvVar {
+	if len(workspace.Spec.ExtraEnv) == 0 {
+		return workspace.Spec.Env
+	}
+	env := make([]corev1.EnvVar, 0, len(workspace.Spec.Env)+len(workspace.Spec.ExtraEnv))
+	env = append(env, workspace.Spec.Env...)
+	return append(env, workspace.Spec.ExtraEnv...)
+}
+
 // parseResourceRequirements extracts and validates resource requirements
 func (db *DeploymentBuilder) parseResourceRequirements(workspace *workspacev1alpha1.Workspace) corev1.ResourceRequirements {
 	defaultCPU := resource.MustParse(DefaultCPURequest)
@@ -6,22 +6,16 @@ Distributed under the terms of the MIT license
 package controller
 
 import (
-	"bytes"
 	"fmt"
-	"text/template"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// partialAccessResourceData provides values for template substitutions
-type partialAccessResourceData struct {
-	Workspace      *workspacev1alpha1.Workspace
-	AccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
-}
-
 func (b *DeploymentBuilder) getPrimaryContainerMergeEnv(
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
 ) *[]workspacev1alpha1.AccessEnvTemplate {
@@ -45,28 +39,27 @@ func (b *DeploymentBuilder) resolveAccessStrategyPrimaryContainerEnv(
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
 	workspace *workspacev1alpha1.Workspace,
 ) ([]map[string]string, error) {
-	data := &partialAccessResourceData{
-		Workspace:      workspace,
-		AccessStrategy: accessStrategy,
-	}
+	// The env is resolved before the workspace Service exists
+	data := accesstemplate.NewData(workspace, accessStrategy, nil)
 
 	var envVars = []map[string]string{}
 	mergeEnv := b.getPrimaryContainerMergeEnv(accessStrategy)
 	if mergeEnv != nil {
-		for _, envTemplate := range *mergeEnv {
-			tmpl, err := template.New("env").Parse(envTemplate.ValueTemplate)
+		for i, envTemplate := range *mergeEnv {
+			field := accesstemplate.MergeEnvField(i)
+			tmpl, err := accesstemplate.Parse(field, envTemplate.ValueTemplate)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse env template for %s: %w", envTemplate.Name, err)
+				return nil, errs.Render(err, "failed to parse env template for %s", envTemplate.Name)
 			}
 
-			var value bytes.Buffer
-			if err := tmpl.Execute(&value, data); err != nil {
-				return nil, fmt.Errorf("failed to execute env template for %s: %w", envTemplate.Name, err)
+			value, err := accesstemplate.Execute(field, tmpl, data)
+			if err != nil {
+				return nil, errs.Render(err, "failed to execute env template for %s", envTemplate.Name)
 			}
 
 			envVars = append(envVars, map[string]string{
 				"name":  envTemplate.Name,
-				"value": value.String(),
+				"value": value,
 			})
 		}
 	}
@@ -151,6 +144,9 @@ func (db *DeploymentBuilder) ApplyAccessStrategyToDeployment(
 		return fmt.Errorf("failed to apply deployment spec modifications: %w", err)
 	}
 
+	// Run the SSH server of the "ssh" provider
+	applySSHAccessToDeployment(deployment, workspace, accessStrategy)
+
 	return nil
 }
 
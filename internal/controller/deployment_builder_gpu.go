@@ -0,0 +1,109 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// nvidiaGPUResource is the resource of the NVIDIA GPUs workspaces request
+	nvidiaGPUResource corev1.ResourceName = "nvidia.com/gpu"
+
+	// The environment variables configuring the CUDA MPS client of a workspace
+	envCUDAMPSPipeDirectory          = "CUDA_MPS_PIPE_DIRECTORY"
+	envCUDAMPSActiveThreadPercentage = "CUDA_MPS_ACTIVE_THREAD_PERCENTAGE"
+	envCUDAMPSPinnedDeviceMemLimit   = "CUDA_MPS_PINNED_DEVICE_MEM_LIMIT"
+)
+
+// applyGPUSharing makes the pod of a workspace that shares its GPUs (TimeSliced or MPS) request
+// the shared GPU resource of workspace.spec.gpuSharing, records the mode in a pod annotation and,
+// in the MPS mode, configures the CUDA MPS client. Resources, annotations and env are copied
+// before being changed, since the pod template shares them with the workspace.
+func applyGPUSharing(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace) {
+	sharing := workspace.Spec.GPUSharing
+	if sharing == nil || sharing.Mode == "" || sharing.Mode == workspacev1alpha1.GPUSharingExclusive {
+		return
+	}
+
+	annotations := maps.Clone(podTemplate.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationGPUSharingMode] = string(sharing.Mode)
+	podTemplate.Annotations = annotations
+
+	container := &podTemplate.Spec.Containers[0]
+	gpuResource := nvidiaGPUResource
+	if sharing.SharedResourceName != "" {
+		gpuResource = corev1.ResourceName(sharing.SharedResourceName)
+		container.Resources = *container.Resources.DeepCopy()
+		renameResource(container.Resources.Requests, nvidiaGPUResource, gpuResource)
+		renameResource(container.Resources.Limits, nvidiaGPUResource, gpuResource)
+	}
+
+	if sharing.Mode == workspacev1alpha1.GPUSharingMPS && sharing.MPS != nil {
+		gpuCount := container.Resources.Limits[gpuResource]
+		if gpuCount.IsZero() {
+			gpuCount = container.Resources.Requests[gpuResource]
+		}
+		container.Env = setEnvVars(container.Env, buildMPSEnv(sharing.MPS, gpuCount.Value()))
+	}
+}
+
+// renameResource moves the quantity of a resource to another resource name
+func renameResource(resources corev1.ResourceList, from, to corev1.ResourceName) {
+	quantity, exists := resources[from]
+	if !exists {
+		return
+	}
+	delete(resources, from)
+	resources[to] = quantity
+}
+
+// buildMPSEnv returns the environment variables configuring the CUDA MPS client. The pinned
+// memory limit applies to each of the gpuCount GPUs the container sees.
+func buildMPSEnv(mps *workspacev1alpha1.GPUMPSConfig, gpuCount int64) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if mps.PipeDirectory != "" {
+		env = append(env, corev1.EnvVar{Name: envCUDAMPSPipeDirectory, Value: mps.PipeDirectory})
+	}
+	if mps.ActiveThreadPercentage != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  envCUDAMPSActiveThreadPercentage,
+			Value: strconv.Itoa(int(*mps.ActiveThreadPercentage)),
+		})
+	}
+	if mps.PinnedDeviceMemoryLimit != nil && gpuCount > 0 {
+		limitMiB := mps.PinnedDeviceMemoryLimit.Value() / (1024 * 1024)
+		limits := make([]string, 0, gpuCount)
+		for device := range gpuCount {
+			limits = append(limits, fmt.Sprintf("%d=%dM", device, limitMiB))
+		}
+		env = append(env, corev1.EnvVar{Name: envCUDAMPSPinnedDeviceMemLimit, Value: strings.Join(limits, ",")})
+	}
+	return env
+}
+
+// setEnvVars returns env with the variables of overrides, replacing those with the same name, so
+// that workspaces cannot lift the limits of their MPS client
+func setEnvVars(env []corev1.EnvVar, overrides []corev1.EnvVar) []corev1.EnvVar {
+	if len(overrides) == 0 {
+		return env
+	}
+	result := slices.DeleteFunc(slices.Clone(env), func(v corev1.EnvVar) bool {
+		return slices.ContainsFunc(overrides, func(o corev1.EnvVar) bool { return o.Name == v.Name })
+	})
+	return append(result, overrides...)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newGPUSharingTestWorkspace(sharing *workspacev1alpha1.GPUSharing) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: "gpu",
+			Image:       imageMinimalNotebook,
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{nvidiaGPUResource: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{nvidiaGPUResource: resource.MustParse("2")},
+			},
+			Env:        []corev1.EnvVar{{Name: envCUDAMPSActiveThreadPercentage, Value: "100"}},
+			GPUSharing: sharing,
+		},
+	}
+}
+
+func buildGPUSharingTestPodTemplate(t *testing.T, workspace *workspacev1alpha1.Workspace) corev1.PodTemplateSpec {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	deployment, err := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil).BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+	return deployment.Spec.Template
+}
+
+func TestApplyGPUSharing_ExclusiveKeepsWholeGPUs(t *testing.T) {
+	workspace := newGPUSharingTestWorkspace(&workspacev1alpha1.GPUSharing{
+		Mode:               workspacev1alpha1.GPUSharingExclusive,
+		SharedResourceName: "nvidia.com/gpu.shared",
+	})
+
+	podTemplate := buildGPUSharingTestPodTemplate(t, workspace)
+
+	container := podTemplate.Spec.Containers[0]
+	assert.Contains(t, container.Resources.Limits, nvidiaGPUResource)
+	assert.NotContains(t, podTemplate.Annotations, AnnotationGPUSharingMode)
+}
+
+func TestApplyGPUSharing_TimeSlicedRequestsSharedResource(t *testing.T) {
+	workspace := newGPUSharingTestWorkspace(&workspacev1alpha1.GPUSharing{
+		Mode:               workspacev1alpha1.GPUSharingTimeSliced,
+		SharedResourceName: "nvidia.com/gpu.shared",
+	})
+
+	podTemplate := buildGPUSharingTestPodTemplate(t, workspace)
+
+	container := podTemplate.Spec.Containers[0]
+	assert.NotContains(t, container.Resources.Requests, nvidiaGPUResource)
+	assert.Equal(t, resource.MustParse("2"), container.Resources.Requests["nvidia.com/gpu.shared"])
+	assert.Equal(t, resource.MustParse("2"), container.Resources.Limits["nvidia.com/gpu.shared"])
+	assert.Equal(t, "TimeSliced", podTemplate.Annotations[AnnotationGPUSharingMode])
+	// The workspace resources are left untouched
+	assert.Contains(t, workspace.Spec.Resources.Limits, nvidiaGPUResource)
+}
+
+func TestApplyGPUSharing_TimeSlicedWithoutRenameKeepsResource(t *testing.T) {
+	workspace := newGPUSharingTestWorkspace(&workspacev1alpha1.GPUSharing{Mode: workspacev1alpha1.GPUSharingTimeSliced})
+
+	podTemplate := buildGPUSharingTestPodTemplate(t, workspace)
+
+	assert.Contains(t, podTemplate.Spec.Containers[0].Resources.Limits, nvidiaGPUResource)
+	assert.Equal(t, "TimeSliced", podTemplate.Annotations[AnnotationGPUSharingMode])
+}
+
+func TestApplyGPUSharing_MPSConfiguresClient(t *testing.T) {
+	limit := resource.MustParse("4Gi")
+	workspace := newGPUSharingTestWorkspace(&workspacev1alpha1.GPUSharing{
+		Mode: workspacev1alpha1.GPUSharingMPS,
+		MPS: &workspacev1alpha1.GPUMPSConfig{
+			PipeDirectory:           "/tmp/nvidia-mps",
+			ActiveThreadPercentage:  ptr.To(int32(50)),
+			PinnedDeviceMemoryLimit: &limit,
+		},
+	})
+
+	podTemplate := buildGPUSharingTestPodTemplate(t, workspace)
+
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: envCUDAMPSPipeDirectory, Value: "/tmp/nvidia-mps"},
+		{Name: envCUDAMPSActiveThreadPercentage, Value: "50"},
+		{Name: envCUDAMPSPinnedDeviceMemLimit, Value: "0=4096M,1=4096M"},
+	}, podTemplate.Spec.Containers[0].Env)
+	assert.Equal(t, "MPS", podTemplate.Annotations[AnnotationGPUSharingMode])
+	// The workspace env is left untouched
+	assert.Equal(t, "100", workspace.Spec.Env[0].Value)
+}
+
+func TestApplyGPUSharing_MPSConfigIgnoredInOtherModes(t *testing.T) {
+	workspace := newGPUSharingTestWorkspace(&workspacev1alpha1.GPUSharing{
+		Mode: workspacev1alpha1.GPUSharingTimeSliced,
+		MPS:  &workspacev1alpha1.GPUMPSConfig{ActiveThreadPercentage: ptr.To(int32(50))},
+	})
+
+	podTemplate := buildGPUSharingTestPodTemplate(t, workspace)
+
+	assert.Equal(t, workspace.Spec.Env, podTemplate.Spec.Containers[0].Env)
+}
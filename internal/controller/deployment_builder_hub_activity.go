@@ -0,0 +1,59 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// HubActivityEnabled returns true when the idle shutdown of the workspace accepts the activity it
+// reports to the JupyterHub activity API
+func HubActivityEnabled(workspace *workspacev1alpha1.Workspace) bool {
+	idleConfig := workspace.Spec.IdleShutdown
+	return idleConfig != nil && idleConfig.Enabled && idleConfig.Detection.HubActivity
+}
+
+// applyHubActivityEnv gives the primary container of a workspace with hub activity detection the
+// JupyterHub environment, with which jupyterhub-singleuser and hub-aware extensions report the
+// activity of the workspace to the JupyterHub activity API server at apiURL. The workspace reports
+// as the user named after it, with the token of its hub activity token Secret. Variables set in
+// the env of the workspace take precedence.
+func applyHubActivityEnv(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace, apiURL string) {
+	if apiURL == "" || !HubActivityEnabled(workspace) {
+		return
+	}
+	hubAPIURL := strings.TrimRight(apiURL, "/") + HubActivityAPIPath(workspace.Namespace)
+	hubEnv := []corev1.EnvVar{
+		{Name: JupyterHubAPIURLEnvVar, Value: hubAPIURL},
+		{Name: JupyterHubActivityURLEnvVar, Value: fmt.Sprintf("%s/users/%s/activity", hubAPIURL, workspace.Name)},
+		{Name: JupyterHubUserEnvVar, Value: workspace.Name},
+		{
+			Name: JupyterHubAPITokenEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: GenerateHubActivityTokenSecretName(workspace)},
+					Key:                  HubActivityTokenSecretKey,
+				},
+			},
+		},
+	}
+
+	// the env is cloned, since the container may share it with the workspace
+	container := &podTemplate.Spec.Containers[0]
+	env := slices.Clone(container.Env)
+	for _, envVar := range hubEnv {
+		if !slices.ContainsFunc(env, func(existing corev1.EnvVar) bool { return existing.Name == envVar.Name }) {
+			env = append(env, envVar)
+		}
+	}
+	container.Env = env
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyJupyterServerConfig mounts the Jupyter configuration generated for the workspace in the
+// primary container: the server configuration in JupyterServerConfigDir, and the JupyterLab settings
+// overrides in the settings directory of the image. The files are mounted with subPath so that the
+// rest of these directories stays visible, and such files are not updated when the ConfigMap
+// changes, so the hash of the configuration is recorded on the pod to recreate it instead.
+func applyJupyterServerConfig(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace) error {
+	config := workspace.Spec.JupyterServerConfig
+	if config == nil {
+		return nil
+	}
+	data, err := buildJupyterConfigData(config)
+	if err != nil {
+		return err
+	}
+	hash, err := jupyterConfigHash(data)
+	if err != nil {
+		return err
+	}
+
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+		Name: JupyterConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: GenerateJupyterConfigMapName(workspace)},
+			},
+		},
+	})
+
+	container := &podTemplate.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      JupyterConfigVolumeName,
+		MountPath: path.Join(JupyterServerConfigDir, JupyterServerConfigFileName),
+		SubPath:   JupyterServerConfigFileName,
+		ReadOnly:  true,
+	})
+	if _, ok := data[JupyterLabOverridesFileName]; ok {
+		settingsDir := config.LabSettingsDir
+		if settingsDir == "" {
+			settingsDir = DefaultJupyterLabSettingsDir
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      JupyterConfigVolumeName,
+			MountPath: path.Join(settingsDir, JupyterLabOverridesFileName),
+			SubPath:   JupyterLabOverridesFileName,
+			ReadOnly:  true,
+		})
+	}
+
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[AnnotationJupyterConfigHash] = hash
+	return nil
+}
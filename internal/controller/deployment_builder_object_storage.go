@@ -0,0 +1,126 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ObjectStorageURL returns the URL of the bucket and prefix of an object storage mount,
+// e.g. s3://team-datasets/imagenet
+func ObjectStorageURL(mount workspacev1alpha1.ObjectStorageMount) string {
+	scheme := "s3"
+	if mount.Provider == workspacev1alpha1.ObjectStorageProviderGCS {
+		scheme = "gs"
+	}
+	url := scheme + "://" + mount.Bucket
+	if mount.Prefix != "" {
+		url += "/" + strings.Trim(mount.Prefix, "/")
+	}
+	return url
+}
+
+// applyObjectStorageMounts mounts the buckets of spec.objectStorageMounts in the primary container:
+// with an inline CSI volume in CSI mode, or with an emptyDir volume that a sidecar syncs in Sync mode.
+func applyObjectStorageMounts(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace, syncImage string) error {
+	podTemplate := &deployment.Spec.Template
+	podSpec := &podTemplate.Spec
+	primaryContainer := &podSpec.Containers[0]
+
+	for _, mount := range workspace.Spec.ObjectStorageMounts {
+		volumeName := ObjectStorageVolumePrefix + mount.Name
+		primaryContainer.VolumeMounts = append(primaryContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: mount.MountPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+
+		if mount.Mode == workspacev1alpha1.ObjectStorageMountModeSync {
+			if syncImage == "" {
+				return fmt.Errorf("object storage mount %q uses the Sync mode, but no sync image is configured", mount.Name)
+			}
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name:         volumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+			podSpec.Containers = append(podSpec.Containers, buildObjectStorageSyncContainer(mount, volumeName, syncImage, primaryContainer))
+			continue
+		}
+
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         volumeName,
+			VolumeSource: corev1.VolumeSource{CSI: buildObjectStorageCSIVolume(mount)},
+		})
+		if mount.Provider == workspacev1alpha1.ObjectStorageProviderGCS {
+			if podTemplate.Annotations == nil {
+				podTemplate.Annotations = map[string]string{}
+			}
+			podTemplate.Annotations[GCSFuseVolumesAnnotation] = "true"
+		}
+	}
+	return nil
+}
+
+// buildObjectStorageCSIVolume returns the inline CSI volume mounting the bucket of an object storage mount
+func buildObjectStorageCSIVolume(mount workspacev1alpha1.ObjectStorageMount) *corev1.CSIVolumeSource {
+	prefix := strings.Trim(mount.Prefix, "/")
+	volume := &corev1.CSIVolumeSource{
+		ReadOnly:         &mount.ReadOnly,
+		VolumeAttributes: map[string]string{"bucketName": mount.Bucket},
+	}
+
+	switch mount.Provider {
+	case workspacev1alpha1.ObjectStorageProviderGCS:
+		volume.Driver = GCSCSIDriver
+		if prefix != "" {
+			volume.VolumeAttributes["mountOptions"] = "implicit-dirs,only-dir=" + prefix
+		}
+	default:
+		volume.Driver = S3CSIDriver
+		if prefix != "" {
+			volume.VolumeAttributes["mountOptions"] = "prefix " + prefix + "/"
+		}
+		if mount.CredentialsSecretName != "" {
+			volume.NodePublishSecretRef = &corev1.LocalObjectReference{Name: mount.CredentialsSecretName}
+		}
+	}
+	return volume
+}
+
+// buildObjectStorageSyncContainer returns the sidecar syncing the bucket of an object storage mount
+// with its emptyDir volume. The keys of the credentials Secret are set as its environment variables.
+func buildObjectStorageSyncContainer(
+	mount workspacev1alpha1.ObjectStorageMount,
+	volumeName, syncImage string,
+	primaryContainer *corev1.Container,
+) corev1.Container {
+	container := corev1.Container{
+		Name:  volumeName,
+		Image: syncImage,
+		Env: []corev1.EnvVar{
+			{Name: ObjectStorageURLEnvVar, Value: ObjectStorageURL(mount)},
+			{Name: ObjectStorageMountPathEnvVar, Value: mount.MountPath},
+			{Name: ObjectStorageReadOnlyEnvVar, Value: strconv.FormatBool(mount.ReadOnly)},
+		},
+		VolumeMounts:    []corev1.VolumeMount{{Name: volumeName, MountPath: mount.MountPath}},
+		SecurityContext: primaryContainer.SecurityContext.DeepCopy(),
+	}
+	if mount.CredentialsSecretName != "" {
+		container.EnvFrom = []corev1.EnvFromSource{{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: mount.CredentialsSecretName},
+			},
+		}}
+	}
+	return container
+}
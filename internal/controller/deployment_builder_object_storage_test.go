@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newObjectStorageTestWorkspace(mounts ...workspacev1alpha1.ObjectStorageMount) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "datasets", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:         "datasets",
+			Image:               imageMinimalNotebook,
+			ObjectStorageMounts: mounts,
+		},
+	}
+}
+
+func TestObjectStorageURL(t *testing.T) {
+	assert.Equal(t, "s3://team-datasets", ObjectStorageURL(workspacev1alpha1.ObjectStorageMount{
+		Provider: workspacev1alpha1.ObjectStorageProviderS3, Bucket: "team-datasets",
+	}))
+	assert.Equal(t, "gs://team-datasets/imagenet", ObjectStorageURL(workspacev1alpha1.ObjectStorageMount{
+		Provider: workspacev1alpha1.ObjectStorageProviderGCS, Bucket: "team-datasets", Prefix: "imagenet/",
+	}))
+}
+
+func TestObjectStorageMountsCSI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := newObjectStorageTestWorkspace(
+		workspacev1alpha1.ObjectStorageMount{
+			Name: "imagenet", Provider: workspacev1alpha1.ObjectStorageProviderS3, Bucket: "team-datasets",
+			Prefix: "imagenet", MountPath: "/data/imagenet", Mode: workspacev1alpha1.ObjectStorageMountModeCSI,
+			ReadOnly: true, CredentialsSecretName: "s3-credentials",
+		},
+		workspacev1alpha1.ObjectStorageMount{
+			Name: "models", Provider: workspacev1alpha1.ObjectStorageProviderGCS, Bucket: "team-models",
+			MountPath: "/data/models", Mode: workspacev1alpha1.ObjectStorageMountModeCSI,
+		},
+	)
+
+	deployment, err := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil).BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "object-storage-imagenet",
+		VolumeSource: corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{
+			Driver:               S3CSIDriver,
+			ReadOnly:             ptr.To(true),
+			VolumeAttributes:     map[string]string{"bucketName": "team-datasets", "mountOptions": "prefix imagenet/"},
+			NodePublishSecretRef: &corev1.LocalObjectReference{Name: "s3-credentials"},
+		}},
+	})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "object-storage-models",
+		VolumeSource: corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{
+			Driver:           GCSCSIDriver,
+			ReadOnly:         ptr.To(false),
+			VolumeAttributes: map[string]string{"bucketName": "team-models"},
+		}},
+	})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "object-storage-imagenet", MountPath: "/data/imagenet", ReadOnly: true,
+	})
+	assert.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "true", deployment.Spec.Template.Annotations[GCSFuseVolumesAnnotation])
+}
+
+func TestObjectStorageMountsSync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := newObjectStorageTestWorkspace(workspacev1alpha1.ObjectStorageMount{
+		Name: "imagenet", Provider: workspacev1alpha1.ObjectStorageProviderS3, Bucket: "team-datasets",
+		MountPath: "/data/imagenet", Mode: workspacev1alpha1.ObjectStorageMountModeSync,
+		ReadOnly: true, CredentialsSecretName: "s3-credentials",
+	})
+
+	_, err := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil).BuildDeployment(context.Background(), workspace)
+	require.ErrorContains(t, err, "no sync image is configured")
+
+	options := WorkspaceControllerOptions{ObjectStorageSyncImage: "example.com/sync:1.0"}
+	deployment, err := NewDeploymentBuilder(scheme, options, nil).BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name:         "object-storage-imagenet",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	require.Len(t, podSpec.Containers, 2)
+	sidecar := podSpec.Containers[1]
+	assert.Equal(t, "object-storage-imagenet", sidecar.Name)
+	assert.Equal(t, "example.com/sync:1.0", sidecar.Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: ObjectStorageURLEnvVar, Value: "s3://team-datasets"},
+		{Name: ObjectStorageMountPathEnvVar, Value: "/data/imagenet"},
+		{Name: ObjectStorageReadOnlyEnvVar, Value: "true"},
+	}, sidecar.Env)
+	assert.Equal(t, "s3-credentials", sidecar.EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "object-storage-imagenet", MountPath: "/data/imagenet"}}, sidecar.VolumeMounts)
+}
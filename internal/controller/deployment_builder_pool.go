@@ -0,0 +1,51 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// poolClaimAffinityWeight is the weight of the preference for the node of the claimed standby pod,
+// the highest the scheduler allows
+const poolClaimAffinityWeight int32 = 100
+
+// nodeNameField is the node field a node selector term matches the name of nodes with
+const nodeNameField = "metadata.name"
+
+// applyPoolClaimAffinity makes the pod of a workspace that claimed a standby pod prefer the node
+// of that pod, which already pulled the image of the workspace. It is a preference rather than a
+// requirement, so that the workspace still starts elsewhere if the node is gone or full.
+// The affinity is copied before being changed, since the pod template shares it with the workspace.
+func applyPoolClaimAffinity(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace) {
+	claim := workspace.Status.PoolClaim
+	if claim == nil || claim.NodeName == "" {
+		return
+	}
+
+	affinity := &corev1.Affinity{}
+	if podTemplate.Spec.Affinity != nil {
+		affinity = podTemplate.Spec.Affinity.DeepCopy()
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.PreferredSchedulingTerm{
+			Weight: poolClaimAffinityWeight,
+			Preference: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      nodeNameField,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{claim.NodeName},
+				}},
+			},
+		})
+	podTemplate.Spec.Affinity = affinity
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// applyWorkspaceProbes sets the probes of the primary container from workspace.spec.probes.
+// Probes the workspace leaves empty default to an HTTP GET on the health endpoint of the
+// application, e.g. the Jupyter status endpoint. It runs after the access strategy is applied,
+// so that the default probes use the Jupyter base URL the access strategy injects in the
+// container environment, for the applications serving under it.
+func applyWorkspaceProbes(container *corev1.Container, workspace *workspacev1alpha1.Workspace) {
+	probes := workspace.Spec.Probes
+	if probes == nil {
+		return
+	}
+	application := workspaceutil.GetApplication(workspace)
+	path := application.ProbePath
+	if application.ServesBaseURL {
+		path = resolveIdlePath(jupyterBaseURL(container), path)
+	}
+
+	switch {
+	case probes.Readiness != nil:
+		container.ReadinessProbe = probes.Readiness.DeepCopy()
+	case workspace.Spec.ReadinessProbe == nil:
+		container.ReadinessProbe = newDefaultWorkspaceProbe(path, application.Port,
+			DefaultReadinessProbePeriodSeconds, DefaultReadinessProbeFailureThreshold)
+	}
+
+	if probes.Liveness != nil {
+		container.LivenessProbe = probes.Liveness.DeepCopy()
+	} else {
+		container.LivenessProbe = newDefaultWorkspaceProbe(path, application.Port,
+			DefaultLivenessProbePeriodSeconds, DefaultLivenessProbeFailureThreshold)
+	}
+
+	if probes.Startup != nil {
+		container.StartupProbe = probes.Startup.DeepCopy()
+	} else {
+		container.StartupProbe = newDefaultWorkspaceProbe(path, application.Port,
+			DefaultStartupProbePeriodSeconds, DefaultStartupProbeFailureThreshold)
+	}
+}
+
+// newDefaultWorkspaceProbe creates an HTTP GET probe on the port of the application.
+// Every field is set explicitly so that the probe matches what the API server stores,
+// and NeedsUpdate does not detect a difference on each reconciliation.
+func newDefaultWorkspaceProbe(path string, port, periodSeconds, failureThreshold int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   path,
+				Port:   intstr.FromInt32(port),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+		TimeoutSeconds:   DefaultWorkspaceProbeTimeoutSeconds,
+		PeriodSeconds:    periodSeconds,
+		SuccessThreshold: 1,
+		FailureThreshold: failureThreshold,
+	}
+}
+
+// jupyterBaseURL returns the literal value of the Jupyter base URL in the container environment
+func jupyterBaseURL(container *corev1.Container) string {
+	for _, env := range container.Env {
+		if env.Name == JupyterBaseURLEnvVar {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// applyApplicationBaseURL copies the Jupyter base URL the access strategy injects in the container
+// environment to the base URL environment variable of applications reading another one, e.g.
+// CODE_SERVER_BASE_URL, unless the container already sets it
+func applyApplicationBaseURL(container *corev1.Container, workspace *workspacev1alpha1.Workspace) {
+	application := workspaceutil.GetApplication(workspace)
+	baseURL := jupyterBaseURL(container)
+	if application.BaseURLEnvVar == JupyterBaseURLEnvVar || baseURL == "" {
+		return
+	}
+	for _, env := range container.Env {
+		if env.Name == application.BaseURLEnvVar {
+			return
+		}
+	}
+	container.Env = append(container.Env, corev1.EnvVar{Name: application.BaseURLEnvVar, Value: baseURL})
+}
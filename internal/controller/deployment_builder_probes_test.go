@@ -0,0 +1,158 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func buildTestDeploymentContainer(
+	t *testing.T,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) corev1.Container {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil)
+
+	deployment, err := builder.BuildDeploymentWithAccessStrategy(context.Background(), workspace, accessStrategy)
+	require.NoError(t, err)
+	return deployment.Spec.Template.Spec.Containers[0]
+}
+
+func newProbesTestWorkspace(probes *workspacev1alpha1.WorkspaceProbes) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "probes", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: "probes",
+			Image:       imageMinimalNotebook,
+			Probes:      probes,
+		},
+	}
+}
+
+func newBaseURLTestAccessStrategy() *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+				PodModifications: &workspacev1alpha1.PodModifications{
+					PrimaryContainerModifications: &workspacev1alpha1.PrimaryContainerModifications{
+						MergeEnv: []workspacev1alpha1.AccessEnvTemplate{{
+							Name:          JupyterBaseURLEnvVar,
+							ValueTemplate: "/workspaces/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/",
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestWorkspaceProbesUnsetLeavesProbesAlone(t *testing.T) {
+	container := buildTestDeploymentContainer(t, newProbesTestWorkspace(nil), nil)
+
+	assert.Nil(t, container.ReadinessProbe)
+	assert.Nil(t, container.LivenessProbe)
+	assert.Nil(t, container.StartupProbe)
+}
+
+func TestWorkspaceProbesDefaultToJupyterStatus(t *testing.T) {
+	container := buildTestDeploymentContainer(t, newProbesTestWorkspace(&workspacev1alpha1.WorkspaceProbes{}), nil)
+
+	for name, probe := range map[string]*corev1.Probe{
+		"readiness": container.ReadinessProbe,
+		"liveness":  container.LivenessProbe,
+		"startup":   container.StartupProbe,
+	} {
+		require.NotNil(t, probe, name)
+		require.NotNil(t, probe.HTTPGet, name)
+		assert.Equal(t, DefaultWorkspaceProbePath, probe.HTTPGet.Path, name)
+		assert.Equal(t, int32(JupyterPort), probe.HTTPGet.Port.IntVal, name)
+	}
+	assert.Equal(t, int32(DefaultStartupProbeFailureThreshold), container.StartupProbe.FailureThreshold)
+}
+
+func TestWorkspaceProbesUseJupyterBaseURLFromAccessStrategy(t *testing.T) {
+	container := buildTestDeploymentContainer(t,
+		newProbesTestWorkspace(&workspacev1alpha1.WorkspaceProbes{}), newBaseURLTestAccessStrategy())
+
+	assert.Equal(t, "/workspaces/default/probes/api/status", container.LivenessProbe.HTTPGet.Path)
+}
+
+func TestWorkspaceProbesPrecedence(t *testing.T) {
+	workspace := newProbesTestWorkspace(&workspacev1alpha1.WorkspaceProbes{
+		Startup: &corev1.Probe{FailureThreshold: 300},
+	})
+	workspace.Spec.ReadinessProbe = &corev1.Probe{FailureThreshold: 7}
+
+	container := buildTestDeploymentContainer(t, workspace, nil)
+
+	assert.Equal(t, int32(300), container.StartupProbe.FailureThreshold)
+	assert.Equal(t, int32(7), container.ReadinessProbe.FailureThreshold,
+		"spec.readinessProbe takes precedence over the default readiness probe")
+
+	workspace.Spec.Probes.Readiness = &corev1.Probe{FailureThreshold: 9}
+	container = buildTestDeploymentContainer(t, workspace, nil)
+	assert.Equal(t, int32(9), container.ReadinessProbe.FailureThreshold,
+		"spec.probes.readiness takes precedence over spec.readinessProbe")
+}
+
+func TestWorkspaceProbesFollowApplicationType(t *testing.T) {
+	workspace := newProbesTestWorkspace(&workspacev1alpha1.WorkspaceProbes{})
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+	container := buildTestDeploymentContainer(t, workspace, newBaseURLTestAccessStrategy())
+
+	assert.Equal(t, "/healthz", container.LivenessProbe.HTTPGet.Path,
+		"code-server serves at the root behind the proxy stripping its base URL")
+	assert.Equal(t, int32(8080), container.LivenessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, int32(8080), container.Ports[0].ContainerPort)
+}
+
+func TestApplicationBaseURLInjected(t *testing.T) {
+	workspace := newProbesTestWorkspace(nil)
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeRStudio
+
+	container := buildTestDeploymentContainer(t, workspace, newBaseURLTestAccessStrategy())
+
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "RSTUDIO_BASE_URL", Value: "/workspaces/default/probes/"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: JupyterBaseURLEnvVar, Value: "/workspaces/default/probes/"})
+}
+
+func TestApplicationBaseURLKeepsContainerValue(t *testing.T) {
+	workspace := newProbesTestWorkspace(nil)
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+	workspace.Spec.Env = []corev1.EnvVar{{Name: "CODE_SERVER_BASE_URL", Value: "/custom/"}}
+
+	container := buildTestDeploymentContainer(t, workspace, newBaseURLTestAccessStrategy())
+
+	var values []string
+	for _, env := range container.Env {
+		if env.Name == "CODE_SERVER_BASE_URL" {
+			values = append(values, env.Value)
+		}
+	}
+	assert.Equal(t, []string{"/custom/"}, values)
+}
+
+func TestApplicationBaseURLWithoutAccessStrategy(t *testing.T) {
+	workspace := newProbesTestWorkspace(nil)
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+	container := buildTestDeploymentContainer(t, workspace, nil)
+
+	assert.Empty(t, container.Env)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// The well-known node labels Karpenter sets on the nodes it provisions, which the Cluster
+	// Autoscaler also reads from the labels of its node groups
+	labelInstanceFamily = "karpenter.k8s.aws/instance-family"
+	labelCapacityType   = "karpenter.sh/capacity-type"
+	labelZone           = corev1.LabelTopologyZone
+
+	// The values of labelCapacityType
+	capacityTypeSpot     = "spot"
+	capacityTypeOnDemand = "on-demand"
+
+	// The pod annotations keeping Karpenter and the Cluster Autoscaler from disrupting the node
+	annotationKarpenterDoNotDisrupt = "karpenter.sh/do-not-disrupt"
+	annotationSafeToEvict           = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+)
+
+// applyProvisioningHints makes the pod of a workspace with provisioning hints require nodes with
+// the matching labels, by adding them to every term of its required node affinity, so that node
+// autoprovisioners scale up the right nodes. With DoNotDisrupt, it also annotates the pod so that
+// they do not disrupt its node. The affinity and annotations are copied before being changed, since
+// the pod template shares them with the workspace.
+func applyProvisioningHints(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace) {
+	hints := workspace.Spec.ProvisioningHints
+	if hints == nil {
+		return
+	}
+
+	if requirements := provisioningRequirements(hints); len(requirements) > 0 {
+		affinity := &corev1.Affinity{}
+		if podTemplate.Spec.Affinity != nil {
+			affinity = podTemplate.Spec.Affinity.DeepCopy()
+		}
+		if affinity.NodeAffinity == nil {
+			affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		nodeAffinity := affinity.NodeAffinity
+		if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+		}
+		selector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		// Terms are ORed, so each of them must carry the requirements
+		if len(selector.NodeSelectorTerms) == 0 {
+			selector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+		}
+		for i := range selector.NodeSelectorTerms {
+			term := &selector.NodeSelectorTerms[i]
+			term.MatchExpressions = append(term.MatchExpressions, requirements...)
+		}
+		podTemplate.Spec.Affinity = affinity
+	}
+
+	if hints.DoNotDisrupt {
+		annotations := maps.Clone(podTemplate.Annotations)
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[annotationKarpenterDoNotDisrupt] = "true"
+		annotations[annotationSafeToEvict] = "false"
+		podTemplate.Annotations = annotations
+	}
+}
+
+// provisioningRequirements returns the node selector requirements of the provisioning hints
+func provisioningRequirements(hints *workspacev1alpha1.ProvisioningHints) []corev1.NodeSelectorRequirement {
+	var requirements []corev1.NodeSelectorRequirement
+	if len(hints.InstanceFamilies) > 0 {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      labelInstanceFamily,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   slices.Clone(hints.InstanceFamilies),
+		})
+	}
+	switch hints.CapacityType {
+	case workspacev1alpha1.CapacityTypeSpot:
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      labelCapacityType,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{capacityTypeSpot},
+		})
+	case workspacev1alpha1.CapacityTypeOnDemand:
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      labelCapacityType,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{capacityTypeOnDemand},
+		})
+	}
+	if len(hints.Zones) > 0 {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      labelZone,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   slices.Clone(hints.Zones),
+		})
+	}
+	return requirements
+}
+
+// updateScaleUpPendingCondition sets the ScaleUpPending condition while the pod of a workspace with
+// provisioning hints is unschedulable, which is when autoprovisioners scale up nodes for it, and
+// removes it otherwise
+func updateScaleUpPendingCondition(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	podStatus := workspace.Status.PodStatus
+	if workspace.Spec.ProvisioningHints == nil || podStatus == nil || podStatus.UnschedulableMessage == "" {
+		removeScaleUpPendingCondition(workspace)
+		return
+	}
+	condition := NewCondition(ConditionTypeScaleUpPending, metav1.ConditionTrue, ReasonWaitingForScaleUp,
+		"Waiting for a node to be provisioned: "+podStatus.UnschedulableMessage)
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+// removeScaleUpPendingCondition drops the ScaleUpPending condition once the workspace pod is scheduled
+func removeScaleUpPendingCondition(workspace *workspacev1alpha1.Workspace) {
+	if FindCondition(&workspace.Status.Conditions, ConditionTypeScaleUpPending) == nil {
+		return
+	}
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions)-1)
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeScaleUpPending {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
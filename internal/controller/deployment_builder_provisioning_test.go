@@ -0,0 +1,129 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newProvisioningTestWorkspace(hints *workspacev1alpha1.ProvisioningHints) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "provisioning", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:       "provisioning",
+			Image:             imageMinimalNotebook,
+			ProvisioningHints: hints,
+		},
+	}
+}
+
+func buildProvisioningTestPodTemplate(t *testing.T, workspace *workspacev1alpha1.Workspace) corev1.PodTemplateSpec {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	deployment, err := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil).BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+	return deployment.Spec.Template
+}
+
+func TestApplyProvisioningHints_RequiresMatchingNodes(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(&workspacev1alpha1.ProvisioningHints{
+		InstanceFamilies: []string{"g5", "g6"},
+		CapacityType:     workspacev1alpha1.CapacityTypeOnDemand,
+		Zones:            []string{"us-west-2a"},
+	})
+
+	podTemplate := buildProvisioningTestPodTemplate(t, workspace)
+
+	require.NotNil(t, podTemplate.Spec.Affinity)
+	terms := podTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: labelInstanceFamily, Operator: corev1.NodeSelectorOpIn, Values: []string{"g5", "g6"}},
+		{Key: labelCapacityType, Operator: corev1.NodeSelectorOpIn, Values: []string{"on-demand"}},
+		{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"us-west-2a"}},
+	}, terms[0].MatchExpressions)
+	assert.NotContains(t, podTemplate.Annotations, annotationKarpenterDoNotDisrupt)
+}
+
+func TestApplyProvisioningHints_AddsRequirementsToEveryTerm(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(&workspacev1alpha1.ProvisioningHints{
+		CapacityType: workspacev1alpha1.CapacityTypeSpot,
+	})
+	workspace.Spec.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+				},
+			},
+		},
+	}
+
+	podTemplate := buildProvisioningTestPodTemplate(t, workspace)
+
+	spotRequirement := corev1.NodeSelectorRequirement{
+		Key: labelCapacityType, Operator: corev1.NodeSelectorOpIn, Values: []string{"spot"},
+	}
+	terms := podTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 2)
+	for _, term := range terms {
+		assert.Len(t, term.MatchExpressions, 2)
+		assert.Contains(t, term.MatchExpressions, spotRequirement)
+	}
+	// The workspace affinity is left untouched
+	workspaceTerms := workspace.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Len(t, workspaceTerms[0].MatchExpressions, 1)
+}
+
+func TestApplyProvisioningHints_DoNotDisruptAnnotatesPod(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(&workspacev1alpha1.ProvisioningHints{DoNotDisrupt: true})
+
+	podTemplate := buildProvisioningTestPodTemplate(t, workspace)
+
+	assert.Nil(t, podTemplate.Spec.Affinity)
+	assert.Equal(t, "true", podTemplate.Annotations[annotationKarpenterDoNotDisrupt])
+	assert.Equal(t, "false", podTemplate.Annotations[annotationSafeToEvict])
+}
+
+func TestUpdateScaleUpPendingCondition(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(&workspacev1alpha1.ProvisioningHints{CapacityType: workspacev1alpha1.CapacityTypeSpot})
+	workspace.Status.PodStatus = &workspacev1alpha1.WorkspacePodStatus{
+		Name:                 "provisioning-pod",
+		UnschedulableMessage: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+	}
+
+	updateScaleUpPendingCondition(context.Background(), workspace)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeScaleUpPending)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonWaitingForScaleUp, condition.Reason)
+	assert.Contains(t, condition.Message, "didn't match Pod's node affinity/selector")
+
+	workspace.Status.PodStatus.UnschedulableMessage = ""
+	updateScaleUpPendingCondition(context.Background(), workspace)
+
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeScaleUpPending))
+}
+
+func TestUpdateScaleUpPendingCondition_IgnoresWorkspacesWithoutHints(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(nil)
+	workspace.Status.PodStatus = &workspacev1alpha1.WorkspacePodStatus{UnschedulableMessage: "0/3 nodes are available"}
+
+	updateScaleUpPendingCondition(context.Background(), workspace)
+
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeScaleUpPending))
+}
@@ -0,0 +1,43 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestRestartRequestedAtChangesPodTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil)
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: testNamespaceName},
+		Spec:       workspacev1alpha1.WorkspaceSpec{Image: imageMinimalNotebook},
+	}
+
+	deployment, err := builder.BuildDeploymentWithAccessStrategy(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, deployment.Spec.Template.Annotations, AnnotationRestartedAt)
+
+	restartRequestedAt := metav1.NewTime(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	workspace.Spec.RestartRequestedAt = &restartRequestedAt
+	needsUpdate, err := builder.NeedsUpdate(context.Background(), deployment, workspace, nil)
+	require.NoError(t, err)
+	assert.True(t, needsUpdate)
+
+	deployment, err = builder.BuildDeploymentWithAccessStrategy(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-01T09:30:00Z", deployment.Spec.Template.Annotations[AnnotationRestartedAt])
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// capabilityAll drops every capability of a container
+	capabilityAll corev1.Capability = "ALL"
+	// capabilityNetBindService is the only capability the restricted Pod Security Standard lets containers add
+	capabilityNetBindService corev1.Capability = "NET_BIND_SERVICE"
+)
+
+// applySecurityProfile makes the pod of a workspace comply with workspace.spec.securityProfile.
+// It runs after the access strategy is applied, so that the sidecars and init containers the
+// access strategy adds comply as well. Security contexts are copied before being changed,
+// since the pod spec shares them with the workspace.
+func applySecurityProfile(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	profile := workspace.Spec.SecurityProfile
+	if profile == nil {
+		return
+	}
+
+	podSecurityContext := podSpec.SecurityContext.DeepCopy()
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+	if !profile.AllowRunAsRoot {
+		podSecurityContext.RunAsNonRoot = ptr.To(true)
+		if ptr.Deref(podSecurityContext.RunAsUser, -1) == 0 {
+			podSecurityContext.RunAsUser = nil
+		}
+	}
+	podSecurityContext.SeccompProfile = restrictedSeccompProfile(podSecurityContext.SeccompProfile)
+	podSpec.SecurityContext = podSecurityContext
+
+	if profile.ReadOnlyRootFilesystem &&
+		!slices.ContainsFunc(podSpec.Volumes, func(v corev1.Volume) bool { return v.Name == volumeNameTmp }) {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         volumeNameTmp,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	podSpec.InitContainers = slices.Clone(podSpec.InitContainers)
+	for i := range podSpec.InitContainers {
+		applyContainerSecurityProfile(&podSpec.InitContainers[i], profile)
+	}
+	for i := range podSpec.Containers {
+		applyContainerSecurityProfile(&podSpec.Containers[i], profile)
+	}
+}
+
+// applyContainerSecurityProfile sets the security context of a container to comply with the profile
+func applyContainerSecurityProfile(container *corev1.Container, profile *workspacev1alpha1.SecurityProfile) {
+	securityContext := container.SecurityContext.DeepCopy()
+	if securityContext == nil {
+		securityContext = &corev1.SecurityContext{}
+	}
+
+	securityContext.AllowPrivilegeEscalation = ptr.To(false)
+	if securityContext.Privileged != nil {
+		securityContext.Privileged = ptr.To(false)
+	}
+
+	if !profile.AllowRunAsRoot {
+		if securityContext.RunAsNonRoot != nil {
+			securityContext.RunAsNonRoot = ptr.To(true)
+		}
+		if ptr.Deref(securityContext.RunAsUser, -1) == 0 {
+			securityContext.RunAsUser = nil
+		}
+	}
+
+	if securityContext.SeccompProfile != nil {
+		securityContext.SeccompProfile = restrictedSeccompProfile(securityContext.SeccompProfile)
+	}
+
+	var added []corev1.Capability
+	if securityContext.Capabilities != nil {
+		for _, capability := range securityContext.Capabilities.Add {
+			if SecurityProfileAllowsCapability(profile, capability) {
+				added = append(added, capability)
+			}
+		}
+	}
+	securityContext.Capabilities = &corev1.Capabilities{
+		Add:  added,
+		Drop: []corev1.Capability{capabilityAll},
+	}
+
+	if profile.ReadOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = ptr.To(true)
+		if !slices.ContainsFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool { return m.MountPath == tmpMountPath }) {
+			container.VolumeMounts = append(slices.Clone(container.VolumeMounts), corev1.VolumeMount{
+				Name:      volumeNameTmp,
+				MountPath: tmpMountPath,
+			})
+		}
+	}
+
+	container.SecurityContext = securityContext
+}
+
+// restrictedSeccompProfile returns the seccomp profile to use under the restricted standard:
+// the RuntimeDefault profile, unless a Localhost profile is set
+func restrictedSeccompProfile(seccompProfile *corev1.SeccompProfile) *corev1.SeccompProfile {
+	if seccompProfile != nil && seccompProfile.Type == corev1.SeccompProfileTypeLocalhost {
+		return seccompProfile
+	}
+	return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+}
+
+// SecurityProfileAllowsCapability returns whether a container may add a capability under the profile:
+// NET_BIND_SERVICE, which the restricted standard allows, or one of the allowed capabilities of the profile
+func SecurityProfileAllowsCapability(profile *workspacev1alpha1.SecurityProfile, capability corev1.Capability) bool {
+	name := NormalizeCapability(capability)
+	if name == capabilityNetBindService {
+		return true
+	}
+	return slices.ContainsFunc(profile.AllowedCapabilities, func(allowed corev1.Capability) bool {
+		return NormalizeCapability(allowed) == name
+	})
+}
+
+// NormalizeCapability returns the name of a capability in upper case without the CAP_ prefix,
+// as the container runtimes accept both forms
+func NormalizeCapability(capability corev1.Capability) corev1.Capability {
+	return corev1.Capability(strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_"))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func buildTestPodSpec(t *testing.T, workspace *workspacev1alpha1.Workspace) corev1.PodSpec {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil)
+
+	deployment, err := builder.BuildDeploymentWithAccessStrategy(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	return deployment.Spec.Template.Spec
+}
+
+func newSecurityProfileTestWorkspace(profile *workspacev1alpha1.SecurityProfile) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:     "restricted",
+			Image:           imageMinimalNotebook,
+			SecurityProfile: profile,
+		},
+	}
+}
+
+func TestSecurityProfileUnsetLeavesSecurityContextsAlone(t *testing.T) {
+	podSpec := buildTestPodSpec(t, newSecurityProfileTestWorkspace(nil))
+
+	assert.Nil(t, podSpec.SecurityContext)
+	assert.Nil(t, podSpec.Containers[0].SecurityContext)
+}
+
+func TestSecurityProfileRestrictedPodSpec(t *testing.T) {
+	workspace := newSecurityProfileTestWorkspace(&workspacev1alpha1.SecurityProfile{
+		Level: workspacev1alpha1.SecurityProfileRestricted,
+	})
+	workspace.Spec.InitContainers = []corev1.Container{{Name: "setup", Image: imageMinimalNotebook}}
+
+	podSpec := buildTestPodSpec(t, workspace)
+
+	require.NotNil(t, podSpec.SecurityContext)
+	assert.Equal(t, ptr.To(true), podSpec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSpec.SecurityContext.SeccompProfile.Type)
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		require.NotNil(t, container.SecurityContext, container.Name)
+		assert.Equal(t, ptr.To(false), container.SecurityContext.AllowPrivilegeEscalation, container.Name)
+		assert.Equal(t, []corev1.Capability{capabilityAll}, container.SecurityContext.Capabilities.Drop, container.Name)
+		assert.Empty(t, container.SecurityContext.Capabilities.Add, container.Name)
+		assert.Nil(t, container.SecurityContext.ReadOnlyRootFilesystem, container.Name)
+	}
+	assert.Nil(t, workspace.Spec.InitContainers[0].SecurityContext, "the workspace must not be modified")
+}
+
+func TestSecurityProfileOverridesInsecureSettings(t *testing.T) {
+	workspace := newSecurityProfileTestWorkspace(&workspacev1alpha1.SecurityProfile{
+		Level: workspacev1alpha1.SecurityProfileRestricted,
+	})
+	workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{
+		RunAsUser:      ptr.To(int64(0)),
+		FSGroup:        ptr.To(int64(100)),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+	}
+	workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+		Privileged:               ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(true),
+		RunAsNonRoot:             ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "cap_net_bind_service"}},
+	}
+
+	podSpec := buildTestPodSpec(t, workspace)
+
+	assert.Nil(t, podSpec.SecurityContext.RunAsUser)
+	assert.Equal(t, ptr.To(int64(100)), podSpec.SecurityContext.FSGroup)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSpec.SecurityContext.SeccompProfile.Type)
+	securityContext := podSpec.Containers[0].SecurityContext
+	assert.Equal(t, ptr.To(false), securityContext.Privileged)
+	assert.Equal(t, ptr.To(false), securityContext.AllowPrivilegeEscalation)
+	assert.Equal(t, ptr.To(true), securityContext.RunAsNonRoot)
+	assert.Equal(t, []corev1.Capability{"cap_net_bind_service"}, securityContext.Capabilities.Add)
+	assert.Equal(t, ptr.To(true), workspace.Spec.ContainerSecurityContext.Privileged, "the workspace must not be modified")
+}
+
+func TestSecurityProfileExceptions(t *testing.T) {
+	workspace := newSecurityProfileTestWorkspace(&workspacev1alpha1.SecurityProfile{
+		Level:               workspacev1alpha1.SecurityProfileRestricted,
+		AllowRunAsRoot:      true,
+		AllowedCapabilities: []corev1.Capability{"SYS_PTRACE"},
+	})
+	workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+		RunAsUser:    ptr.To(int64(0)),
+		Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_PTRACE"}},
+	}
+
+	podSpec := buildTestPodSpec(t, workspace)
+
+	assert.Nil(t, podSpec.SecurityContext.RunAsNonRoot)
+	securityContext := podSpec.Containers[0].SecurityContext
+	assert.Equal(t, ptr.To(int64(0)), securityContext.RunAsUser)
+	assert.Equal(t, []corev1.Capability{"SYS_PTRACE"}, securityContext.Capabilities.Add)
+}
+
+func TestSecurityProfileReadOnlyRootFilesystemMountsTmp(t *testing.T) {
+	workspace := newSecurityProfileTestWorkspace(&workspacev1alpha1.SecurityProfile{
+		Level:                  workspacev1alpha1.SecurityProfileRestricted,
+		ReadOnlyRootFilesystem: true,
+	})
+
+	podSpec := buildTestPodSpec(t, workspace)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, ptr.To(true), container.SecurityContext.ReadOnlyRootFilesystem)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: volumeNameTmp, MountPath: tmpMountPath})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name:         volumeNameTmp,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+}
@@ -0,0 +1,45 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// applyGracefulShutdown gives the workspace pod spec.stopGracePeriodSeconds to shut down, and makes
+// the primary container stop the Jupyter server in a preStop hook, so that the server shuts its
+// kernels down and pending autosaves complete before the kubelet sends SIGTERM. Applications
+// without a stop command, such as code-server, only get the grace period.
+// A preStop hook set in spec.lifecycle takes precedence. The lifecycle is copied before being
+// changed, since the container shares it with the workspace.
+func applyGracefulShutdown(podTemplate *corev1.PodTemplateSpec, workspace *workspacev1alpha1.Workspace) {
+	gracePeriodSeconds := workspace.Spec.StopGracePeriodSeconds
+	if gracePeriodSeconds == nil {
+		return
+	}
+	podTemplate.Spec.TerminationGracePeriodSeconds = gracePeriodSeconds
+
+	container := &podTemplate.Spec.Containers[0]
+	stopCommand := workspaceutil.GetApplication(workspace).StopCommand
+	if stopCommand == nil || (container.Lifecycle != nil && container.Lifecycle.PreStop != nil) {
+		return
+	}
+	lifecycle := &corev1.Lifecycle{}
+	if container.Lifecycle != nil {
+		lifecycle = container.Lifecycle.DeepCopy()
+	}
+	lifecycle.PreStop = &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{
+			Command: slices.Clone(stopCommand),
+		},
+	}
+	container.Lifecycle = lifecycle
+}
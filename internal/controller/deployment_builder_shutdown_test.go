@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newShutdownTestPodTemplate(lifecycle *corev1.Lifecycle) *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: ResourcePrefix, Image: imageMinimalNotebook, Lifecycle: lifecycle}},
+		},
+	}
+}
+
+func newShutdownTestWorkspace(gracePeriodSeconds *int64) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "shutdown", Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image:                  imageMinimalNotebook,
+			StopGracePeriodSeconds: gracePeriodSeconds,
+		},
+	}
+}
+
+func TestApplyGracefulShutdown_UnsetLeavesPodAlone(t *testing.T) {
+	podTemplate := newShutdownTestPodTemplate(nil)
+
+	applyGracefulShutdown(podTemplate, newShutdownTestWorkspace(nil))
+
+	assert.Nil(t, podTemplate.Spec.TerminationGracePeriodSeconds)
+	assert.Nil(t, podTemplate.Spec.Containers[0].Lifecycle)
+}
+
+func TestApplyGracefulShutdown_StopsJupyterServer(t *testing.T) {
+	podTemplate := newShutdownTestPodTemplate(nil)
+
+	applyGracefulShutdown(podTemplate, newShutdownTestWorkspace(ptr.To(int64(120))))
+
+	assert.Equal(t, ptr.To(int64(120)), podTemplate.Spec.TerminationGracePeriodSeconds)
+	lifecycle := podTemplate.Spec.Containers[0].Lifecycle
+	require.NotNil(t, lifecycle)
+	require.NotNil(t, lifecycle.PreStop)
+	assert.Equal(t, []string{"jupyter", "server", "stop", "8888"}, lifecycle.PreStop.Exec.Command)
+}
+
+func TestApplyGracefulShutdown_OnlyGracePeriodWithoutStopCommand(t *testing.T) {
+	podTemplate := newShutdownTestPodTemplate(nil)
+	workspace := newShutdownTestWorkspace(ptr.To(int64(120)))
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+	applyGracefulShutdown(podTemplate, workspace)
+
+	assert.Equal(t, ptr.To(int64(120)), podTemplate.Spec.TerminationGracePeriodSeconds)
+	assert.Nil(t, podTemplate.Spec.Containers[0].Lifecycle)
+}
+
+func TestApplyGracefulShutdown_KeepsPostStartWithoutChangingWorkspace(t *testing.T) {
+	workspace := newShutdownTestWorkspace(ptr.To(int64(60)))
+	workspace.Spec.Lifecycle = &corev1.Lifecycle{
+		PostStart: &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+	}
+	podTemplate := newShutdownTestPodTemplate(workspace.Spec.Lifecycle)
+
+	applyGracefulShutdown(podTemplate, workspace)
+
+	lifecycle := podTemplate.Spec.Containers[0].Lifecycle
+	require.NotNil(t, lifecycle.PreStop)
+	assert.NotNil(t, lifecycle.PostStart)
+	assert.Nil(t, workspace.Spec.Lifecycle.PreStop)
+}
+
+func TestApplyGracefulShutdown_CustomPreStopTakesPrecedence(t *testing.T) {
+	preStop := &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"save-all"}}}
+	podTemplate := newShutdownTestPodTemplate(&corev1.Lifecycle{PreStop: preStop})
+
+	applyGracefulShutdown(podTemplate, newShutdownTestWorkspace(ptr.To(int64(60))))
+
+	assert.Equal(t, ptr.To(int64(60)), podTemplate.Spec.TerminationGracePeriodSeconds)
+	assert.Equal(t, []string{"save-all"}, podTemplate.Spec.Containers[0].Lifecycle.PreStop.Exec.Command)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// applySSHAccessToDeployment wires the SSH server of an access strategy using the "ssh" provider
+// into the workspace pod: it mounts the SSH keys Secret, and runs the SSH server in a sidecar
+// when the access strategy names a sidecar image, or in the primary container otherwise.
+func applySSHAccessToDeployment(
+	deployment *appsv1.Deployment,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) {
+	if accessStrategy.Spec.Provider != AccessProviderSSH {
+		return
+	}
+	podSpec := &deployment.Spec.Template.Spec
+	port := sshPort(accessStrategy)
+
+	// The Secret is created with the other access resources once the pod is ready,
+	// so the pod must not wait for it
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: SSHAuthorizedKeysVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  GenerateSSHKeysSecretName(workspace),
+				DefaultMode: ptr.To[int32](0o440),
+				Optional:    ptr.To(true),
+			},
+		},
+	})
+	keysMount := corev1.VolumeMount{
+		Name:      SSHAuthorizedKeysVolumeName,
+		MountPath: SSHAuthorizedKeysMountPath,
+		ReadOnly:  true,
+	}
+	env := []corev1.EnvVar{
+		{Name: SSHPortEnvVar, Value: fmt.Sprint(port)},
+		{Name: SSHAuthorizedKeysFileEnvVar, Value: SSHAuthorizedKeysMountPath + "/" + SSHAuthorizedKeysFileName},
+	}
+	containerPort := corev1.ContainerPort{Name: "ssh", ContainerPort: port, Protocol: corev1.ProtocolTCP}
+
+	primaryContainer := &podSpec.Containers[0]
+	if accessStrategy.Spec.SSH == nil || accessStrategy.Spec.SSH.SidecarImage == "" {
+		// The workspace image runs the SSH server
+		primaryContainer.VolumeMounts = append(primaryContainer.VolumeMounts, keysMount)
+		primaryContainer.Env = append(primaryContainer.Env, env...)
+		primaryContainer.Ports = append(primaryContainer.Ports, containerPort)
+		return
+	}
+
+	// The sidecar shares the volumes of the workspace container, so that SSH sessions see the workspace files
+	volumeMounts := append(append([]corev1.VolumeMount{}, primaryContainer.VolumeMounts...), keysMount)
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:            SSHContainerName,
+		Image:           accessStrategy.Spec.SSH.SidecarImage,
+		Env:             env,
+		Ports:           []corev1.ContainerPort{containerPort},
+		VolumeMounts:    volumeMounts,
+		SecurityContext: primaryContainer.SecurityContext.DeepCopy(),
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 )
 
 var _ = Describe("DeploymentBuilder", func() {
@@ -64,7 +65,7 @@ var _ = Describe("DeploymentBuilder", func() {
 			// Verify volume is added
 			Expect(deployment.Spec.Template.Spec.Volumes).To(HaveLen(1))
 			Expect(deployment.Spec.Template.Spec.Volumes[0].Name).To(Equal(volumeNameWorkspaceStorage))
-			Expect(deployment.Spec.Template.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal(GeneratePVCName(workspace.Name)))
+			Expect(deployment.Spec.Template.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal(GeneratePVCName(workspace)))
 
 			// Verify volume mount is added to container
 			container := deployment.Spec.Template.Spec.Containers[0]
@@ -152,6 +153,52 @@ var _ = Describe("DeploymentBuilder", func() {
 			Expect(volumeMap["data-volume"]).To(Equal("data-pvc"))
 			Expect(volumeMap["shared-volume"]).To(Equal("shared-pvc"))
 		})
+
+		It("should mount read-only NFS volumes and sub paths", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-nfs-volumes",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Volumes: []workspacev1alpha1.VolumeSpec{
+						{
+							Name:      "datasets",
+							NFS:       &workspacev1alpha1.NFSVolumeSource{Server: "fs-1234.efs.us-west-2.amazonaws.com", Path: "/"},
+							MountPath: "/data/datasets",
+							SubPath:   "imagenet",
+							ReadOnly:  true,
+						},
+						{
+							Name:                      "models",
+							PersistentVolumeClaimName: "models-pvc",
+							MountPath:                 "/data/models",
+							ReadOnly:                  true,
+						},
+					},
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "datasets",
+				VolumeSource: corev1.VolumeSource{NFS: &corev1.NFSVolumeSource{
+					Server: "fs-1234.efs.us-west-2.amazonaws.com", Path: "/", ReadOnly: true,
+				}},
+			}))
+			Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "models",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "models-pvc", ReadOnly: true,
+				}},
+			}))
+			Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name: "datasets", MountPath: "/data/datasets", SubPath: "imagenet", ReadOnly: true,
+			}))
+		})
 	})
 
 	Context("Container Configuration", func() {
@@ -266,6 +313,35 @@ var _ = Describe("DeploymentBuilder", func() {
 			Expect(container.Env[1].ValueFrom.ConfigMapKeyRef.Key).To(Equal("config-key"))
 		})
 
+		It("should append extraEnv after env and set envFrom", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-extra-env",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Env:      []corev1.EnvVar{{Name: "FROM_TEMPLATE", Value: "template"}},
+					ExtraEnv: []corev1.EnvVar{{Name: "MINE", Value: "workspace"}},
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "team-config"},
+						},
+					}},
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Env).To(Equal([]corev1.EnvVar{
+				{Name: "FROM_TEMPLATE", Value: "template"},
+				{Name: "MINE", Value: "workspace"},
+			}))
+			Expect(container.EnvFrom).To(Equal(workspace.Spec.EnvFrom))
+			Expect(workspace.Spec.Env).To(HaveLen(1))
+		})
+
 		It("should handle empty env array", func() {
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -304,6 +380,31 @@ var _ = Describe("DeploymentBuilder", func() {
 			Expect(container.Env).To(BeEmpty())
 		})
 
+		It("should declare additional ports after the http port", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-ports",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Ports: []workspacev1alpha1.WorkspacePort{
+						{Name: "tensorboard", Port: 6006},
+						{Name: "mlflow", Port: 5000, Path: "/tracking/"},
+					},
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Ports).To(Equal([]corev1.ContainerPort{
+				{Name: httpScheme, ContainerPort: JupyterPort, Protocol: corev1.ProtocolTCP},
+				{Name: "tensorboard", ContainerPort: 6006, Protocol: corev1.ProtocolTCP},
+				{Name: "mlflow", ContainerPort: 5000, Protocol: corev1.ProtocolTCP},
+			}))
+		})
+
 		It("should set command, args, and env together", func() {
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -504,6 +605,128 @@ var _ = Describe("DeploymentBuilder", func() {
 		})
 	})
 
+	Context("Priority class", func() {
+		It("should set the priority class when specified", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-priority",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					PriorityClassName: "interactive",
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("interactive"))
+		})
+
+		It("should leave the priority class empty when not specified", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-no-priority",
+					Namespace: testNamespace,
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(BeEmpty())
+		})
+	})
+
+	Context("Runtime class", func() {
+		It("should set the runtime class when specified", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-runtime",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					RuntimeClassName: "gvisor",
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.RuntimeClassName).To(HaveValue(Equal("gvisor")))
+		})
+
+		It("should leave the runtime class unset when not specified", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-no-runtime",
+					Namespace: testNamespace,
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.RuntimeClassName).To(BeNil())
+		})
+	})
+
+	Context("Image Pull Secrets", func() {
+		var workspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			workspace = &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-pull-secrets",
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "team-registry"}},
+				},
+			}
+		})
+
+		It("should set the workspace image pull secrets", func() {
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "team-registry"}}))
+		})
+
+		It("should append the operator default secret once", func() {
+			options.ApplicationImagesPullSecret = "quay-credentials"
+			builder := NewDeploymentBuilder(scheme, options, k8sClient)
+
+			deployment, err := builder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{
+				{Name: "team-registry"}, {Name: "quay-credentials"},
+			}))
+
+			workspace.Spec.ImagePullSecrets = append(workspace.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: "quay-credentials"})
+			deployment, err = builder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(HaveLen(2))
+		})
+
+		It("should use the image settings of the operator config", func() {
+			options.ApplicationImagesPullSecret = "quay-credentials"
+			options.OperatorConfig = operatorconfig.NewStore(operatorconfig.Settings{
+				ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+				ApplicationImagesPullSecret: "quay-credentials",
+			})
+			options.OperatorConfig.Apply(&workspacev1alpha1.OperatorConfigSpec{
+				ApplicationImages: &workspacev1alpha1.ApplicationImagesConfig{
+					PullPolicy: corev1.PullAlways,
+					PullSecret: "ecr-credentials",
+				},
+			})
+			builder := NewDeploymentBuilder(scheme, options, k8sClient)
+
+			deployment, err := builder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{
+				{Name: "team-registry"}, {Name: "ecr-credentials"},
+			}))
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+		})
+	})
+
 	Context("Lifecycle Hooks", func() {
 		It("should set lifecycle hooks", func() {
 			workspace := &workspacev1alpha1.Workspace{
@@ -0,0 +1,341 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxReportedDriftedFields caps the number of drifted fields listed in the DriftDetected condition
+// and in drift events
+const maxReportedDriftedFields = 20
+
+// DriftedResource lists the fields of a generated resource that were changed outside of the controller
+type DriftedResource struct {
+	Kind   string
+	Name   string
+	Fields []string
+}
+
+// driftRecorder collects the drifted resources of each workspace during a reconciliation.
+// A workspace is only reconciled by one worker at a time, so entries of a workspace are not
+// shared between reconciliations running in parallel.
+type driftRecorder struct {
+	mu      sync.Mutex
+	drifted map[types.NamespacedName][]DriftedResource
+}
+
+// resolveDriftPolicy returns the drift policy of the workspace, which defaults to Revert
+func resolveDriftPolicy(workspace *workspacev1alpha1.Workspace) workspacev1alpha1.DriftPolicy {
+	if workspace.Spec.DriftPolicy == "" {
+		return workspacev1alpha1.DriftPolicyRevert
+	}
+	return workspace.Spec.DriftPolicy
+}
+
+// recordDrift adds a drifted resource to the drift report of the workspace
+func (rm *ResourceManager) recordDrift(workspace *workspacev1alpha1.Workspace, kind, name string, fields []string) {
+	rm.drift.mu.Lock()
+	defer rm.drift.mu.Unlock()
+	if rm.drift.drifted == nil {
+		rm.drift.drifted = make(map[types.NamespacedName][]DriftedResource)
+	}
+	key := types.NamespacedName{Namespace: workspace.Namespace, Name: workspace.Name}
+	rm.drift.drifted[key] = append(rm.drift.drifted[key], DriftedResource{Kind: kind, Name: name, Fields: fields})
+}
+
+// takeDrift returns and clears the drift report of the workspace
+func (rm *ResourceManager) takeDrift(workspace *workspacev1alpha1.Workspace) []DriftedResource {
+	rm.drift.mu.Lock()
+	defer rm.drift.mu.Unlock()
+	key := types.NamespacedName{Namespace: workspace.Namespace, Name: workspace.Name}
+	drifted := rm.drift.drifted[key]
+	delete(rm.drift.drifted, key)
+	return drifted
+}
+
+// deploymentDriftState returns the part of a Deployment that the controller owns: its pod template
+func deploymentDriftState(deployment *appsv1.Deployment) (map[string]any, error) {
+	template, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&deployment.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pod template of deployment %s: %w", deployment.Name, err)
+	}
+	return map[string]any{"spec": map[string]any{"template": template}}, nil
+}
+
+// serviceDriftState returns the part of a Service that the controller owns: its spec
+func serviceDriftState(service *corev1.Service) (map[string]any, error) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&service.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert spec of service %s: %w", service.Name, err)
+	}
+	return map[string]any{"spec": spec}, nil
+}
+
+// accessResourceDriftState returns the part of an access resource that the controller owns:
+// its spec, or its data for Secrets and ConfigMaps
+func accessResourceDriftState(obj *unstructured.Unstructured) map[string]any {
+	state := map[string]any{}
+	for _, field := range []string{"spec", "data"} {
+		if value, found := obj.Object[field]; found {
+			state[field] = value
+		}
+	}
+	return state
+}
+
+// desiredStateHash returns the hash of the desired state of a generated resource
+func desiredStateHash(state map[string]any) (string, error) {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize desired state: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// detectDrift returns the fields of a generated resource that differ from its desired state, along
+// with the hash of the desired state. Differences are only drift when the desired state is the one
+// the controller last applied: otherwise the workspace, its template or its access strategy changed
+// and the resource is merely out of date. Resources applied before the hash was recorded never drift.
+func detectDrift(existing metav1.Object, existingState, desiredState map[string]any) ([]string, string, error) {
+	desiredHash, err := desiredStateHash(desiredState)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing.GetAnnotations()[AnnotationDesiredStateHash] != desiredHash {
+		return nil, desiredHash, nil
+	}
+	return diffDriftState(existingState, desiredState), desiredHash, nil
+}
+
+// diffDriftState returns the paths of the fields of the existing state that differ from the desired state
+func diffDriftState(existingState, desiredState map[string]any) []string {
+	var fields []string
+	collectDriftedFields("", existingState, desiredState, &fields)
+	return fields
+}
+
+// deploymentDrift returns the drifted fields of a deployment, and the hash of its desired state
+func deploymentDrift(existing, desired *appsv1.Deployment) ([]string, string, error) {
+	existingState, err := deploymentDriftState(existing)
+	if err != nil {
+		return nil, "", err
+	}
+	desiredState, err := deploymentDriftState(desired)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectDrift(existing, existingState, desiredState)
+}
+
+// serviceDrift returns the drifted fields of a service, and the hash of its desired state
+func serviceDrift(existing, desired *corev1.Service) ([]string, string, error) {
+	existingState, err := serviceDriftState(existing)
+	if err != nil {
+		return nil, "", err
+	}
+	desiredState, err := serviceDriftState(desired)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectDrift(existing, existingState, desiredState)
+}
+
+// accessResourceDrift returns the drifted fields of an access resource, and the hash of its desired state
+func accessResourceDrift(existing, desired *unstructured.Unstructured) ([]string, string, error) {
+	return detectDrift(existing, accessResourceDriftState(existing), accessResourceDriftState(desired))
+}
+
+// accessResourceMissesDesiredStateHash returns true when an access resource matches its desired state
+// but does not record its hash, e.g. when it was created before drift detection
+func accessResourceMissesDesiredStateHash(existing, desired *unstructured.Unstructured, desiredHash string) bool {
+	desiredState := accessResourceDriftState(desired)
+	return len(desiredState) > 0 &&
+		existing.GetAnnotations()[AnnotationDesiredStateHash] != desiredHash &&
+		len(diffDriftState(accessResourceDriftState(existing), desiredState)) == 0
+}
+
+// collectDriftedFields appends the paths of the fields set in desired with a different value in existing.
+// Fields only set in existing, such as the values defaulted by the API server, are not drift.
+func collectDriftedFields(path string, existing, desired any, fields *[]string) {
+	switch desiredValue := desired.(type) {
+	case map[string]any:
+		existingValue, ok := existing.(map[string]any)
+		if !ok {
+			if len(desiredValue) > 0 {
+				*fields = append(*fields, path)
+			}
+			return
+		}
+		keys := make([]string, 0, len(desiredValue))
+		for key := range desiredValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectDriftedFields(joinFieldPath(path, key), existingValue[key], desiredValue[key], fields)
+		}
+	case []any:
+		existingValue, ok := existing.([]any)
+		if !ok || len(existingValue) != len(desiredValue) {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := range desiredValue {
+			collectDriftedFields(fmt.Sprintf("%s[%d]", path, i), existingValue[i], desiredValue[i], fields)
+		}
+	case nil:
+		// Unset in the desired state
+	default:
+		if !reflect.DeepEqual(existing, desired) {
+			*fields = append(*fields, path)
+		}
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// setDesiredStateHash records the hash of the desired state applied to a generated resource
+func setDesiredStateHash(obj metav1.Object, hash string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationDesiredStateHash] = hash
+	obj.SetAnnotations(annotations)
+}
+
+// stampDeploymentDesiredState records the hash of the pod template of a deployment about to be applied
+func stampDeploymentDesiredState(deployment *appsv1.Deployment) error {
+	state, err := deploymentDriftState(deployment)
+	if err != nil {
+		return err
+	}
+	hash, err := desiredStateHash(state)
+	if err != nil {
+		return err
+	}
+	setDesiredStateHash(deployment, hash)
+	return nil
+}
+
+// stampServiceDesiredState records the hash of the spec of a service about to be applied
+func stampServiceDesiredState(service *corev1.Service) error {
+	state, err := serviceDriftState(service)
+	if err != nil {
+		return err
+	}
+	hash, err := desiredStateHash(state)
+	if err != nil {
+		return err
+	}
+	setDesiredStateHash(service, hash)
+	return nil
+}
+
+// stampAccessResourceDesiredState records the hash of the spec or data of an access resource about to be applied
+func stampAccessResourceDesiredState(obj *unstructured.Unstructured) error {
+	hash, err := desiredStateHash(accessResourceDriftState(obj))
+	if err != nil {
+		return err
+	}
+	setDesiredStateHash(obj, hash)
+	return nil
+}
+
+// driftMessage describes the drifted resources, listing at most maxReportedDriftedFields fields
+func driftMessage(drifted []DriftedResource) string {
+	var resources []string
+	reported, omitted := 0, 0
+	for _, resource := range drifted {
+		fields := resource.Fields
+		if remaining := maxReportedDriftedFields - reported; len(fields) > remaining {
+			omitted += len(fields) - remaining
+			fields = fields[:remaining]
+		}
+		reported += len(fields)
+		if len(fields) > 0 {
+			resources = append(resources, fmt.Sprintf("%s %s: %s", resource.Kind, resource.Name, strings.Join(fields, ", ")))
+		}
+	}
+	message := strings.Join(resources, "; ")
+	if omitted > 0 {
+		message += fmt.Sprintf(" (and %d more)", omitted)
+	}
+	return message
+}
+
+// reportDrift handles the drift found while applying the generated resources of a running workspace,
+// according to its drift policy. It only sets the DriftDetected condition in memory; the status update
+// that follows persists it.
+func (sm *StateMachine) reportDrift(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	drifted := sm.resourceManager.takeDrift(workspace)
+	switch resolveDriftPolicy(workspace) {
+	case workspacev1alpha1.DriftPolicyWarn:
+		if len(drifted) == 0 {
+			removeDriftDetectedCondition(workspace)
+			return
+		}
+		message := "Generated resources were changed manually: " + driftMessage(drifted)
+		if condition := FindCondition(&workspace.Status.Conditions, ConditionTypeDriftDetected); condition == nil ||
+			condition.Message != message {
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, "DriftDetected", message)
+		}
+		setDriftDetectedCondition(ctx, workspace, message)
+	case workspacev1alpha1.DriftPolicyIgnore:
+		removeDriftDetectedCondition(workspace)
+	default:
+		removeDriftDetectedCondition(workspace)
+		if len(drifted) > 0 {
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "DriftReverted",
+				"Reverted manual changes to generated resources: "+driftMessage(drifted))
+		}
+	}
+}
+
+// setDriftDetectedCondition reports the drifted resources in the DriftDetected condition
+func setDriftDetectedCondition(ctx context.Context, workspace *workspacev1alpha1.Workspace, message string) {
+	condition := NewCondition(ConditionTypeDriftDetected, metav1.ConditionTrue, ReasonResourcesDrifted, message)
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+// removeDriftDetectedCondition drops the DriftDetected condition once no drift remains
+func removeDriftDetectedCondition(workspace *workspacev1alpha1.Workspace) {
+	if FindCondition(&workspace.Status.Conditions, ConditionTypeDriftDetected) == nil {
+		return
+	}
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions)-1)
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeDriftDetected {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
@@ -0,0 +1,271 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newDriftTestStateMachine(t *testing.T, objs ...client.Object) (*StateMachine, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	statusManager := NewStatusManager(fakeClient)
+	rm := NewResourceManager(fakeClient, scheme, nil, NewServiceBuilder(scheme), nil, NewAccessResourcesBuilder(), statusManager)
+	return &StateMachine{
+		resourceManager: rm,
+		statusManager:   statusManager,
+		recorder:        record.NewFakeRecorder(10),
+	}, fakeClient
+}
+
+func newDriftTestWorkspace(policy workspacev1alpha1.DriftPolicy) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a", UID: types.UID("uid-ws")},
+		Spec:       workspacev1alpha1.WorkspaceSpec{DriftPolicy: policy},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			Conditions: []metav1.Condition{
+				NewCondition(ConditionTypeAvailable, metav1.ConditionTrue, ReasonResourcesReady, "Workspace is ready"),
+			},
+		},
+	}
+}
+
+func newDriftTestAccessStrategy() *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{Kind: "ConfigMap", ApiVersion: "v1", NamePrefix: "routes", Template: "data:\n  upstream: \"{{ .Workspace.Name }}\""},
+			},
+		},
+	}
+}
+
+func TestDiffDriftState(t *testing.T) {
+	existing := map[string]any{
+		"spec": map[string]any{
+			"clusterIP": "10.0.0.1",
+			"selector":  map[string]any{"app": "other"},
+			"ports": []any{
+				map[string]any{"name": "http", "port": int64(9999), "protocol": "TCP"},
+			},
+		},
+	}
+	desired := map[string]any{
+		"spec": map[string]any{
+			"selector": map[string]any{"app": "jupyter"},
+			"ports": []any{
+				map[string]any{"name": "http", "port": int64(8888)},
+			},
+		},
+	}
+
+	// Fields only set on the existing resource, like the defaulted clusterIP, are not drift
+	assert.Equal(t, []string{"spec.ports[0].port", "spec.selector.app"}, diffDriftState(existing, desired))
+	assert.Empty(t, diffDriftState(existing, existing))
+
+	// Removed list items drift as a whole
+	desired["spec"].(map[string]any)["ports"] = []any{
+		map[string]any{"port": int64(9999)},
+		map[string]any{"port": int64(8889)},
+	}
+	assert.Contains(t, diffDriftState(existing, desired), "spec.ports")
+}
+
+func TestDetectDrift_OnlyOnceDesiredStateIsApplied(t *testing.T) {
+	desired := &corev1.Service{Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "jupyter"}}}
+	existing := &corev1.Service{Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "other"}}}
+
+	// No hash recorded: the service predates drift detection
+	fields, desiredHash, err := serviceDrift(existing, desired)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+	assert.NotEmpty(t, desiredHash)
+
+	// The recorded hash is stale: the desired state changed since it was applied
+	setDesiredStateHash(existing, "stale")
+	fields, _, err = serviceDrift(existing, desired)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	// The desired state was applied, then changed manually
+	setDesiredStateHash(existing, desiredHash)
+	fields, _, err = serviceDrift(existing, desired)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spec.selector.app"}, fields)
+}
+
+func TestDriftMessage_CapsReportedFields(t *testing.T) {
+	fields := make([]string, maxReportedDriftedFields+3)
+	for i := range fields {
+		fields[i] = "spec.field"
+	}
+	message := driftMessage([]DriftedResource{
+		{Kind: "Service", Name: "ws-service", Fields: []string{"spec.selector.app"}},
+		{Kind: "Deployment", Name: "ws", Fields: fields},
+	})
+
+	assert.True(t, strings.HasPrefix(message, "Service ws-service: spec.selector.app; Deployment ws: spec.field"))
+	assert.True(t, strings.HasSuffix(message, "(and 4 more)"))
+	assert.Equal(t, maxReportedDriftedFields, strings.Count(message, "spec."))
+}
+
+func TestEnsureServiceExists_DriftPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		policy       workspacev1alpha1.DriftPolicy
+		reverted     bool
+		condition    bool
+		eventReasons []string
+	}{
+		{policy: "", reverted: true, eventReasons: []string{"DriftReverted"}},
+		{policy: workspacev1alpha1.DriftPolicyRevert, reverted: true, eventReasons: []string{"DriftReverted"}},
+		{policy: workspacev1alpha1.DriftPolicyWarn, condition: true, eventReasons: []string{"DriftDetected"}},
+		{policy: workspacev1alpha1.DriftPolicyIgnore},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			workspace := newDriftTestWorkspace(tc.policy)
+			sm, fakeClient := newDriftTestStateMachine(t, workspace)
+
+			service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+			require.NoError(t, err)
+			assert.NotEmpty(t, service.Annotations[AnnotationDesiredStateHash])
+
+			// Change the port of the service manually
+			service.Spec.Ports[0].Port = 9999
+			require.NoError(t, fakeClient.Update(ctx, service))
+
+			_, err = sm.resourceManager.EnsureServiceExists(ctx, workspace)
+			require.NoError(t, err)
+			sm.reportDrift(ctx, workspace)
+
+			updated := &corev1.Service{}
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(service), updated))
+			if tc.reverted {
+				assert.Equal(t, int32(JupyterPort), updated.Spec.Ports[0].Port)
+			} else {
+				assert.Equal(t, int32(9999), updated.Spec.Ports[0].Port)
+			}
+
+			condition := FindCondition(&workspace.Status.Conditions, ConditionTypeDriftDetected)
+			if tc.condition {
+				require.NotNil(t, condition)
+				assert.Equal(t, metav1.ConditionTrue, condition.Status)
+				assert.Equal(t, ReasonResourcesDrifted, condition.Reason)
+				assert.Contains(t, condition.Message, "Service "+service.Name+": spec.ports[0].port")
+			} else {
+				assert.Nil(t, condition)
+			}
+
+			events := sm.recorder.(*record.FakeRecorder).Events
+			for _, reason := range tc.eventReasons {
+				require.Len(t, events, 1)
+				assert.Contains(t, <-events, reason)
+			}
+			assert.Empty(t, events)
+		})
+	}
+}
+
+func TestEnsureServiceExists_AppliesDesiredChangesUnderWarn(t *testing.T) {
+	ctx := context.Background()
+	workspace := newDriftTestWorkspace(workspacev1alpha1.DriftPolicyWarn)
+	sm, fakeClient := newDriftTestStateMachine(t, workspace)
+
+	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+
+	// A service without hash, e.g. created before drift detection, is brought up to date
+	delete(service.Annotations, AnnotationDesiredStateHash)
+	service.Spec.Ports[0].Port = 9999
+	require.NoError(t, fakeClient.Update(ctx, service))
+
+	_, err = sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Empty(t, sm.resourceManager.takeDrift(workspace))
+
+	updated := &corev1.Service{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(service), updated))
+	assert.Equal(t, int32(JupyterPort), updated.Spec.Ports[0].Port)
+	assert.NotEmpty(t, updated.Annotations[AnnotationDesiredStateHash])
+}
+
+func TestEnsureAccessResourcesExist_DriftPolicy(t *testing.T) {
+	ctx := context.Background()
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ws-service", Namespace: "team-a"}}
+	accessStrategy := newDriftTestAccessStrategy()
+
+	for _, tc := range []struct {
+		policy   workspacev1alpha1.DriftPolicy
+		upstream string
+	}{
+		{policy: workspacev1alpha1.DriftPolicyRevert, upstream: "ws"},
+		{policy: workspacev1alpha1.DriftPolicyWarn, upstream: "elsewhere"},
+		{policy: workspacev1alpha1.DriftPolicyIgnore, upstream: "elsewhere"},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			workspace := newDriftTestWorkspace(tc.policy)
+			sm, fakeClient := newDriftTestStateMachine(t, workspace)
+
+			require.NoError(t, sm.resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, service))
+			require.Len(t, workspace.Status.AccessResources, 1)
+			key := types.NamespacedName{Namespace: "team-a", Name: workspace.Status.AccessResources[0].Name}
+
+			configMap := &corev1.ConfigMap{}
+			require.NoError(t, fakeClient.Get(ctx, key, configMap))
+			assert.NotEmpty(t, configMap.Annotations[AnnotationDesiredStateHash])
+			configMap.Data["upstream"] = "elsewhere"
+			require.NoError(t, fakeClient.Update(ctx, configMap))
+
+			require.NoError(t, sm.resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, service))
+			assert.Equal(t, []DriftedResource{{Kind: "ConfigMap", Name: key.Name, Fields: []string{"data.upstream"}}},
+				sm.resourceManager.takeDrift(workspace))
+
+			require.NoError(t, fakeClient.Get(ctx, key, configMap))
+			assert.Equal(t, tc.upstream, configMap.Data["upstream"])
+		})
+	}
+}
+
+func TestReportDrift_ClearsConditionOnceDriftIsGone(t *testing.T) {
+	ctx := context.Background()
+	workspace := newDriftTestWorkspace(workspacev1alpha1.DriftPolicyWarn)
+	sm, _ := newDriftTestStateMachine(t, workspace)
+
+	sm.resourceManager.recordDrift(workspace, "Service", "ws-service", []string{"spec.selector.app"})
+	sm.reportDrift(ctx, workspace)
+	require.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeDriftDetected))
+
+	// The same drift is only reported once
+	sm.resourceManager.recordDrift(workspace, "Service", "ws-service", []string{"spec.selector.app"})
+	sm.reportDrift(ctx, workspace)
+	events := sm.recorder.(*record.FakeRecorder).Events
+	require.Len(t, events, 1)
+	<-events
+
+	sm.reportDrift(ctx, workspace)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeDriftDetected))
+	assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeAvailable))
+}
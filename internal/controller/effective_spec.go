@@ -0,0 +1,51 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+)
+
+// resolveEffectiveSpec computes the effective configuration reported in status from the pod
+// template of the workspace Deployment, which already merges the template defaults, user
+// preferences and access strategy deployment modifications
+func resolveEffectiveSpec(workspace *workspacev1alpha1.Workspace, deployment *appsv1.Deployment) *workspacev1alpha1.EffectiveSpecStatus {
+	if deployment == nil {
+		return nil
+	}
+	// Copy, so that the status does not alias the cached Deployment
+	podSpec := deployment.Spec.Template.Spec.DeepCopy()
+
+	effectiveSpec := &workspacev1alpha1.EffectiveSpecStatus{
+		ObservedGeneration: workspace.Generation,
+		Profile:            workspace.Spec.Profile,
+		ServiceAccountName: podSpec.ServiceAccountName,
+		NodeSelector:       podSpec.NodeSelector,
+		Tolerations:        podSpec.Tolerations,
+		PriorityClassName:  podSpec.PriorityClassName,
+		RuntimeClassName:   ptr.Deref(podSpec.RuntimeClassName, ""),
+		ImagePullSecrets:   podSpec.ImagePullSecrets,
+	}
+	for _, container := range podSpec.Containers {
+		effectiveSpec.Containers = append(effectiveSpec.Containers, container.Name)
+		if container.Name != ResourcePrefix {
+			continue
+		}
+		effectiveSpec.Image = container.Image
+		effectiveSpec.Command = container.Command
+		effectiveSpec.Args = container.Args
+		effectiveSpec.Env = container.Env
+		effectiveSpec.EnvFrom = container.EnvFrom
+		effectiveSpec.Resources = container.Resources
+		effectiveSpec.VolumeMounts = container.VolumeMounts
+	}
+	for _, container := range podSpec.InitContainers {
+		effectiveSpec.InitContainers = append(effectiveSpec.InitContainers, container.Name)
+	}
+	return effectiveSpec
+}
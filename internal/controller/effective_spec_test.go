@@ -0,0 +1,79 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestResolveEffectiveSpec_MergesAccessStrategyModifications(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", Generation: 3},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image:   "jupyter/base-notebook:latest",
+			Profile: "small",
+			Env:     []corev1.EnvVar{{Name: "FROM_SPEC", Value: "1"}},
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+			NodeSelector:       map[string]string{"pool": "notebooks"},
+			ServiceAccountName: "notebook-sa",
+			InitContainers:     []corev1.Container{{Name: "init", Image: "busybox"}},
+		},
+	}
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "strategy", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+				PodModifications: &workspacev1alpha1.PodModifications{
+					PrimaryContainerModifications: &workspacev1alpha1.PrimaryContainerModifications{
+						MergeEnv: []workspacev1alpha1.AccessEnvTemplate{
+							{Name: "BASE_URL", ValueTemplate: "/workspaces/{{ .Workspace.Name }}"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	builder := NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, nil)
+	deployment, err := builder.BuildDeploymentWithAccessStrategy(context.Background(), workspace, accessStrategy)
+	require.NoError(t, err)
+
+	effectiveSpec := resolveEffectiveSpec(workspace, deployment)
+
+	require.NotNil(t, effectiveSpec)
+	assert.Equal(t, int64(3), effectiveSpec.ObservedGeneration)
+	assert.Equal(t, "jupyter/base-notebook:latest", effectiveSpec.Image)
+	assert.Contains(t, effectiveSpec.Env, corev1.EnvVar{Name: "FROM_SPEC", Value: "1"})
+	assert.Contains(t, effectiveSpec.Env, corev1.EnvVar{Name: "BASE_URL", Value: "/workspaces/ws"})
+	assert.Equal(t, resource.MustParse("500m"), effectiveSpec.Resources.Requests[corev1.ResourceCPU])
+	assert.Equal(t, "small", effectiveSpec.Profile)
+	assert.Equal(t, []string{ResourcePrefix}, effectiveSpec.Containers)
+	assert.Equal(t, []string{"init"}, effectiveSpec.InitContainers)
+	assert.Equal(t, "notebook-sa", effectiveSpec.ServiceAccountName)
+	assert.Equal(t, map[string]string{"pool": "notebooks"}, effectiveSpec.NodeSelector)
+
+	// The status must not alias the Deployment
+	effectiveSpec.Env[0].Value = "changed"
+	assert.Equal(t, "1", deployment.Spec.Template.Spec.Containers[0].Env[0].Value)
+}
+
+func TestResolveEffectiveSpec_NilDeployment(t *testing.T) {
+	assert.Nil(t, resolveEffectiveSpec(&workspacev1alpha1.Workspace{}, nil))
+}
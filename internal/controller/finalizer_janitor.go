@@ -0,0 +1,218 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// DefaultFinalizerJanitorInterval is the default interval between the sweeps of the finalizer janitor
+const DefaultFinalizerJanitorInterval = 10 * time.Minute
+
+// staleFinalizer identifies a protection finalizer of a template or access strategy
+type staleFinalizer struct {
+	kind      string
+	object    types.NamespacedName
+	finalizer string
+}
+
+// FinalizerJanitor removes the protection finalizers that WorkspaceTemplates and
+// WorkspaceAccessStrategies keep after nothing references them anymore, e.g. the finalizers the
+// workspace webhook added for a workspace whose creation then failed. The template and access
+// strategy controllers only remove these finalizers when an event reconciles the object again,
+// which may never come, leaving the object undeletable.
+//
+// The references are read from the field indexes of the cache, and a finalizer is only removed once
+// two consecutive sweeps found it unreferenced on an unchanged object, so that neither a workspace
+// being created nor a cache lagging behind the API server gets the finalizer removed too early.
+type FinalizerJanitor struct {
+	client   client.Client
+	recorder record.EventRecorder
+	interval time.Duration
+
+	// stale holds the resource versions of the objects whose finalizers the previous sweep found
+	// unreferenced. It is only used by the sweeps, which never run concurrently.
+	stale map[staleFinalizer]string
+}
+
+// NewFinalizerJanitor creates a new FinalizerJanitor. A zero or negative interval uses
+// DefaultFinalizerJanitorInterval.
+func NewFinalizerJanitor(k8sClient client.Client, recorder record.EventRecorder, interval time.Duration) *FinalizerJanitor {
+	if interval <= 0 {
+		interval = DefaultFinalizerJanitorInterval
+	}
+	return &FinalizerJanitor{
+		client:   k8sClient,
+		recorder: recorder,
+		interval: interval,
+		stale:    map[staleFinalizer]string{},
+	}
+}
+
+// Start sweeps the stale finalizers every interval until the context is done
+func (j *FinalizerJanitor) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("finalizer-janitor")
+	logger.Info("Starting finalizer janitor", "interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			removed, err := j.Sweep(logf.IntoContext(ctx, logger))
+			if err != nil {
+				logger.Error(err, "Failed to sweep stale finalizers")
+				continue
+			}
+			if removed > 0 {
+				logger.Info("Removed stale finalizers", "count", removed)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// Only the leader sweeps, like the controllers managing the finalizers
+func (j *FinalizerJanitor) NeedLeaderElection() bool {
+	return true
+}
+
+// SetupFinalizerJanitor sets up the finalizer janitor and adds it to the manager
+func SetupFinalizerJanitor(mgr ctrl.Manager, interval time.Duration) error {
+	janitor := NewFinalizerJanitor(mgr.GetClient(), mgr.GetEventRecorderFor("finalizer-janitor"), interval)
+	if err := mgr.Add(janitor); err != nil {
+		return fmt.Errorf("failed to add finalizer janitor to manager: %w", err)
+	}
+	return nil
+}
+
+// Sweep removes the protection finalizers found unreferenced on the previous sweep that are still
+// unreferenced, and returns how many it removed
+func (j *FinalizerJanitor) Sweep(ctx context.Context) (int, error) {
+	stale := map[staleFinalizer]string{}
+	removed := 0
+
+	templates := &workspacev1alpha1.WorkspaceTemplateList{}
+	if err := j.client.List(ctx, templates); err != nil {
+		return 0, fmt.Errorf("failed to list workspace templates: %w", err)
+	}
+	for i := range templates.Items {
+		template := &templates.Items[i]
+		finalizers, err := j.unreferencedFinalizers(ctx, "WorkspaceTemplate", template,
+			map[string]func() (bool, error){
+				workspace.TemplateFinalizerName: func() (bool, error) {
+					return workspace.HasActiveWorkspacesWithTemplate(ctx, j.client, template.Name, template.Namespace)
+				},
+			}, stale)
+		if err != nil {
+			return removed, err
+		}
+		removed += j.removeFinalizers(ctx, template, finalizers)
+	}
+
+	accessStrategies := &workspacev1alpha1.WorkspaceAccessStrategyList{}
+	if err := j.client.List(ctx, accessStrategies); err != nil {
+		return removed, fmt.Errorf("failed to list workspace access strategies: %w", err)
+	}
+	for i := range accessStrategies.Items {
+		accessStrategy := &accessStrategies.Items[i]
+		finalizers, err := j.unreferencedFinalizers(ctx, "WorkspaceAccessStrategy", accessStrategy,
+			map[string]func() (bool, error){
+				workspace.AccessStrategyFinalizerName: func() (bool, error) {
+					return workspace.HasActiveWorkspacesWithAccessStrategy(
+						ctx, j.client, accessStrategy.Name, accessStrategy.Namespace)
+				},
+				workspace.AccessStrategyTemplateFinalizerName: func() (bool, error) {
+					return workspace.HasActiveTemplatesWithAccessStrategy(
+						ctx, j.client, accessStrategy.Name, accessStrategy.Namespace)
+				},
+			}, stale)
+		if err != nil {
+			return removed, err
+		}
+		removed += j.removeFinalizers(ctx, accessStrategy, finalizers)
+	}
+
+	j.stale = stale
+	return removed, nil
+}
+
+// unreferencedFinalizers returns the protection finalizers of an object that nothing references
+// since the previous sweep, given the check of the references of each finalizer. The finalizers
+// found unreferenced for the first time are recorded in stale.
+func (j *FinalizerJanitor) unreferencedFinalizers(
+	ctx context.Context,
+	kind string,
+	obj client.Object,
+	isReferenced map[string]func() (bool, error),
+	stale map[staleFinalizer]string,
+) ([]string, error) {
+	var finalizers []string
+	for finalizer, referenced := range isReferenced {
+		if !controllerutil.ContainsFinalizer(obj, finalizer) {
+			continue
+		}
+		inUse, err := referenced()
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			continue
+		}
+		key := staleFinalizer{kind: kind, object: client.ObjectKeyFromObject(obj), finalizer: finalizer}
+		if resourceVersion, found := j.stale[key]; found && resourceVersion == obj.GetResourceVersion() {
+			finalizers = append(finalizers, finalizer)
+			continue
+		}
+		logf.FromContext(ctx).V(1).Info("Found unreferenced finalizer, removing it on the next sweep",
+			"kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace(), "finalizer", finalizer)
+		stale[key] = obj.GetResourceVersion()
+	}
+	slices.Sort(finalizers)
+	return finalizers, nil
+}
+
+// removeFinalizers removes finalizers from an object in a single update, and returns how many it
+// removed. Failures are logged and retried on the next sweeps, once the object is found unchanged.
+func (j *FinalizerJanitor) removeFinalizers(ctx context.Context, obj client.Object, finalizers []string) int {
+	if len(finalizers) == 0 {
+		return 0
+	}
+	logger := logf.FromContext(ctx).WithValues("name", obj.GetName(), "namespace", obj.GetNamespace())
+
+	for _, finalizer := range finalizers {
+		controllerutil.RemoveFinalizer(obj, finalizer)
+	}
+	if err := j.client.Update(ctx, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to remove stale finalizers", "finalizers", finalizers)
+		}
+		return 0
+	}
+
+	logger.Info("Removed stale finalizers", "finalizers", finalizers)
+	if j.recorder != nil {
+		j.recorder.Eventf(obj, "Normal", "StaleFinalizerRemoved",
+			"Removed finalizers %v: nothing references this resource anymore", finalizers)
+	}
+	return len(finalizers)
+}
@@ -0,0 +1,246 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newJanitorScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newProtectedTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "orphan-template",
+			Namespace:  "default",
+			Finalizers: []string{workspace.TemplateFinalizerName},
+		},
+	}
+}
+
+func newProtectedAccessStrategy() *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orphan-access-strategy",
+			Namespace: "default",
+			Finalizers: []string{
+				workspace.AccessStrategyFinalizerName,
+				workspace.AccessStrategyTemplateFinalizerName,
+			},
+		},
+	}
+}
+
+func newJanitor(k8sClient client.Client) (*FinalizerJanitor, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(10)
+	return NewFinalizerJanitor(k8sClient, recorder, 0), recorder
+}
+
+func TestFinalizerJanitor_RemovesFinalizersUnreferencedOnTwoSweeps(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newIndexedClientBuilder(newJanitorScheme(t)).
+		WithObjects(newProtectedTemplate(), newProtectedAccessStrategy()).
+		Build()
+	janitor, recorder := newJanitor(k8sClient)
+
+	removed, err := janitor.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed, "the first sweep only records the unreferenced finalizers")
+
+	removed, err = janitor.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(newProtectedTemplate()), template))
+	assert.Empty(t, template.Finalizers)
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(newProtectedAccessStrategy()), accessStrategy))
+	assert.Empty(t, accessStrategy.Finalizers)
+
+	require.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "StaleFinalizerRemoved")
+}
+
+func TestFinalizerJanitor_KeepsReferencedFinalizers(t *testing.T) {
+	ctx := context.Background()
+	referrer := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "referrer", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			TemplateRef:    &workspacev1alpha1.TemplateRef{Name: "orphan-template"},
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: "orphan-access-strategy"},
+		},
+	}
+	k8sClient := newIndexedClientBuilder(newJanitorScheme(t)).
+		WithObjects(newProtectedTemplate(), newProtectedAccessStrategy(), referrer).
+		Build()
+	janitor, _ := newJanitor(k8sClient)
+
+	for range 2 {
+		_, err := janitor.Sweep(ctx)
+		require.NoError(t, err)
+	}
+
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(newProtectedTemplate()), template))
+	assert.Equal(t, []string{workspace.TemplateFinalizerName}, template.Finalizers)
+
+	// Only the finalizer of the templates referring to the access strategy is stale
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(newProtectedAccessStrategy()), accessStrategy))
+	assert.Equal(t, []string{workspace.AccessStrategyFinalizerName}, accessStrategy.Finalizers)
+}
+
+func TestFinalizerJanitor_ResetsWhenObjectChanges(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newIndexedClientBuilder(newJanitorScheme(t)).WithObjects(newProtectedTemplate()).Build()
+	janitor, _ := newJanitor(k8sClient)
+
+	removed, err := janitor.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(newProtectedTemplate()), template))
+	template.Labels = map[string]string{"changed": "true"}
+	require.NoError(t, k8sClient.Update(ctx, template))
+
+	removed, err = janitor.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed, "a changed object is seen as unreferenced for the first time")
+
+	removed, err = janitor.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+}
+
+var _ = Describe("Finalizer janitor", func() {
+	var (
+		ctx          context.Context
+		cancel       context.CancelFunc
+		cachedClient client.Client
+		janitor      *FinalizerJanitor
+		templates    []*workspacev1alpha1.WorkspaceTemplate
+		referrer     *workspacev1alpha1.Workspace
+	)
+
+	newTemplate := func(prefix string) *workspacev1alpha1.WorkspaceTemplate {
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano()),
+				Namespace:  testNamespace,
+				Finalizers: []string{workspace.TemplateFinalizerName},
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  testTemplateDisplayName,
+				DefaultImage: imageQuayMinimalNotebook,
+			},
+		}
+		Expect(k8sClient.Create(ctx, template)).To(Succeed())
+		templates = append(templates, template)
+		return template
+	}
+
+	templateFinalizers := func(template *workspacev1alpha1.WorkspaceTemplate) []string {
+		current := &workspacev1alpha1.WorkspaceTemplate{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(template), current)).To(Succeed())
+		return current.Finalizers
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		templates = nil
+		referrer = nil
+
+		// The janitor reads the references from the field indexes of the manager cache
+		informers, err := cache.New(cfg, cache.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workspace.SetupFieldIndexes(ctx, informers)).To(Succeed())
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+		cachedClient, err = client.New(cfg, client.Options{
+			Scheme: scheme.Scheme,
+			Cache:  &client.CacheOptions{Reader: informers},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		janitor = NewFinalizerJanitor(cachedClient, record.NewFakeRecorder(10), time.Minute)
+	})
+
+	AfterEach(func() {
+		if referrer != nil {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, referrer))).To(Succeed())
+		}
+		for _, template := range templates {
+			current := &workspacev1alpha1.WorkspaceTemplate{}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(template), current); err == nil {
+				controllerutil.RemoveFinalizer(current, workspace.TemplateFinalizerName)
+				Expect(client.IgnoreNotFound(k8sClient.Update(ctx, current))).To(Succeed())
+			}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, template))).To(Succeed())
+		}
+		cancel()
+	})
+
+	It("should remove the finalizers of unreferenced templates only", func() {
+		orphan := newTemplate("orphan-template")
+		used := newTemplate("used-template")
+		referrer = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("referrer-%d", time.Now().UnixNano()),
+				Namespace: testNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: used.Name, Namespace: used.Namespace},
+			},
+		}
+		Expect(k8sClient.Create(ctx, referrer)).To(Succeed())
+
+		By("waiting for the cache to observe the workspace and the templates")
+		Eventually(func() error {
+			return cachedClient.Get(ctx, client.ObjectKeyFromObject(referrer), &workspacev1alpha1.Workspace{})
+		}).Should(Succeed())
+		for _, template := range []*workspacev1alpha1.WorkspaceTemplate{orphan, used} {
+			Eventually(func() error {
+				return cachedClient.Get(ctx, client.ObjectKeyFromObject(template), &workspacev1alpha1.WorkspaceTemplate{})
+			}).Should(Succeed())
+		}
+
+		By("sweeping twice")
+		_, err := janitor.Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templateFinalizers(orphan)).To(ConsistOf(workspace.TemplateFinalizerName))
+		_, err = janitor.Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(templateFinalizers(orphan)).To(BeEmpty())
+		Expect(templateFinalizers(used)).To(ConsistOf(workspace.TemplateFinalizerName))
+	})
+})
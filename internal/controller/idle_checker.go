@@ -16,6 +16,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 )
 
 // IdleCheckResult represents the result of an idle check operation
@@ -34,6 +35,9 @@ type WorkspaceIdleChecker struct {
 	client        client.Client
 	checkInterval time.Duration
 
+	// config overrides checkInterval with the idle check interval of the OperatorConfig, when set
+	config *operatorconfig.Store
+
 	// httpClient is shared across all network idle checks so TCP (and TLS)
 	// connections to frequently-probed services are reused between cycles
 	// instead of re-handshaked. http.Client is safe for concurrent use; the
@@ -45,15 +49,9 @@ type WorkspaceIdleChecker struct {
 // If checkInterval is zero or negative, DefaultIdleCheckInterval is used.
 // Positive values below MinIdleCheckInterval are clamped up to that floor.
 func NewWorkspaceIdleChecker(k8sClient client.Client, checkInterval time.Duration) *WorkspaceIdleChecker {
-	switch {
-	case checkInterval <= 0:
-		checkInterval = DefaultIdleCheckInterval
-	case checkInterval < MinIdleCheckInterval:
-		checkInterval = MinIdleCheckInterval
-	}
 	return &WorkspaceIdleChecker{
 		client:        k8sClient,
-		checkInterval: checkInterval,
+		checkInterval: clampIdleCheckInterval(checkInterval),
 		httpClient: &http.Client{
 			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
 				return http.ErrUseLastResponse
@@ -62,15 +60,37 @@ func NewWorkspaceIdleChecker(k8sClient client.Client, checkInterval time.Duratio
 	}
 }
 
+// clampIdleCheckInterval returns DefaultIdleCheckInterval for zero or negative intervals, and
+// clamps positive intervals below MinIdleCheckInterval up to that floor
+func clampIdleCheckInterval(checkInterval time.Duration) time.Duration {
+	switch {
+	case checkInterval <= 0:
+		return DefaultIdleCheckInterval
+	case checkInterval < MinIdleCheckInterval:
+		return MinIdleCheckInterval
+	}
+	return checkInterval
+}
+
 // CheckInterval returns the configured interval between idle checks.
 func (w *WorkspaceIdleChecker) CheckInterval() time.Duration {
+	if w.config != nil {
+		return clampIdleCheckInterval(w.config.Settings().IdleCheckInterval)
+	}
 	return w.checkInterval
 }
 
 // CheckWorkspaceIdle checks if a workspace is idle using the configured detection method.
+// The activity reported to the JupyterHub activity API is checked first, with hub activity detection.
 // For transport:network, it uses the service ClusterIP (already available from the reconcile).
 // For transport:podExec, it finds a running pod and execs curl into it.
 func (w *WorkspaceIdleChecker) CheckWorkspaceIdle(ctx context.Context, workspace *workspacev1alpha1.Workspace, service *corev1.Service, idleConfig *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error) {
+	if idleConfig.Detection.HubActivity {
+		if result, decided := checkHubActivity(ctx, workspace, idleConfig); decided {
+			return result, nil
+		}
+	}
+
 	transport := transportPodExec
 	if idleConfig.Detection.HTTPGet != nil && idleConfig.Detection.HTTPGet.Transport != "" {
 		transport = idleConfig.Detection.HTTPGet.Transport
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 )
 
 // MockIdleDetector mocks the IdleDetector for testing
@@ -216,6 +218,22 @@ func TestNewWorkspaceIdleChecker_Success(t *testing.T) {
 	assert.NotNil(t, checker.client)
 }
 
+func TestWorkspaceIdleChecker_CheckInterval_FollowsOperatorConfig(t *testing.T) {
+	checker := NewWorkspaceIdleChecker(nil, 5*time.Minute)
+	checker.config = operatorconfig.NewStore(operatorconfig.Settings{IdleCheckInterval: 5 * time.Minute})
+	assert.Equal(t, 5*time.Minute, checker.CheckInterval())
+
+	checker.config.Apply(&workspacev1alpha1.OperatorConfigSpec{
+		IdleCheckInterval: &metav1.Duration{Duration: 2 * time.Minute},
+	})
+	assert.Equal(t, 2*time.Minute, checker.CheckInterval())
+
+	checker.config.Apply(&workspacev1alpha1.OperatorConfigSpec{
+		IdleCheckInterval: &metav1.Duration{Duration: time.Second},
+	})
+	assert.Equal(t, MinIdleCheckInterval, checker.CheckInterval(), "intervals below the floor are clamped")
+}
+
 // Test CheckWorkspaceIdle - Success Cases with mocked detector
 func TestWorkspaceIdleChecker_CheckWorkspaceIdle_Success_NotIdle(t *testing.T) {
 	setup := setupWorkspaceIdleCheckerTest(t)
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// HubLastActivity returns the last activity that the workspace reported to the JupyterHub
+// activity API, and whether it reported any. Activity in the future is read as now, like the
+// activity API records it.
+func HubLastActivity(workspace *workspacev1alpha1.Workspace) (time.Time, bool) {
+	value, ok := workspace.Annotations[AnnotationHubLastActivity]
+	if !ok {
+		return time.Time{}, false
+	}
+	lastActivity, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if now := time.Now(); lastActivity.After(now) {
+		lastActivity = now
+	}
+	return lastActivity, true
+}
+
+// checkHubActivity checks the activity that the workspace reported to the JupyterHub activity API,
+// and reports whether it decided the idle state of the workspace. A workspace that reported activity
+// within the idle timeout is active. Otherwise, the HTTPGet probe decides when one is set; without
+// one, the workspace is idle once the timeout elapsed since its last reported activity, or since it
+// became available when it reported none since.
+func checkHubActivity(ctx context.Context, workspace *workspacev1alpha1.Workspace, idleConfig *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, bool) {
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+	timeout := time.Duration(idleConfig.IdleTimeoutInMinutes) * time.Minute
+
+	lastActivity, reported := HubLastActivity(workspace)
+	if reported && time.Since(lastActivity) <= timeout {
+		logger.V(1).Info("Workspace reported activity to the hub activity API", "lastActivity", lastActivity)
+		return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, true
+	}
+	if idleConfig.Detection.HTTPGet != nil {
+		return nil, false
+	}
+
+	if available := meta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeAvailable); available != nil &&
+		available.LastTransitionTime.After(lastActivity) {
+		lastActivity = available.LastTransitionTime.Time
+	}
+	if lastActivity.IsZero() {
+		lastActivity = workspace.CreationTimestamp.Time
+	}
+	return &IdleCheckResult{IsIdle: checkIdleTimeout(ctx, workspace.Name, lastActivity, idleConfig), ShouldRetry: true}, true
+}
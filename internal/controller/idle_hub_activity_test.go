@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newHubActivityTestWorkspace(httpGet *workspacev1alpha1.IdleHTTPGetAction) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "hub", Namespace: testNamespaceName, UID: types.UID("uid-hub")},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image: imageMinimalNotebook,
+			IdleShutdown: &workspacev1alpha1.IdleShutdownSpec{
+				Enabled:              true,
+				IdleTimeoutInMinutes: 30,
+				Detection:            workspacev1alpha1.IdleDetectionSpec{HTTPGet: httpGet, HubActivity: true},
+			},
+		},
+	}
+}
+
+func setAvailableSince(workspace *workspacev1alpha1.Workspace, since time.Time) {
+	workspace.Status.Conditions = []metav1.Condition{{
+		Type:               ConditionTypeAvailable,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(since),
+	}}
+}
+
+func TestCheckHubActivity(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	tests := []struct {
+		name           string
+		httpGet        *workspacev1alpha1.IdleHTTPGetAction
+		lastActivity   time.Time
+		availableSince time.Time
+		decided        bool
+		idle           bool
+	}{
+		{name: "recent activity", lastActivity: now.Add(-5 * time.Minute), availableSince: now.Add(-2 * time.Hour), decided: true},
+		{name: "recent activity with probe", httpGet: &workspacev1alpha1.IdleHTTPGetAction{},
+			lastActivity: now.Add(-5 * time.Minute), availableSince: now.Add(-2 * time.Hour), decided: true},
+		{name: "stale activity", lastActivity: now.Add(-time.Hour), availableSince: now.Add(-2 * time.Hour), decided: true, idle: true},
+		{name: "stale activity with probe", httpGet: &workspacev1alpha1.IdleHTTPGetAction{},
+			lastActivity: now.Add(-time.Hour), availableSince: now.Add(-2 * time.Hour)},
+		{name: "no activity since recent start", availableSince: now.Add(-10 * time.Minute), decided: true},
+		{name: "no activity since restart", lastActivity: now.Add(-3 * time.Hour), availableSince: now.Add(-10 * time.Minute), decided: true},
+		{name: "no activity since start", availableSince: now.Add(-time.Hour), decided: true, idle: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newHubActivityTestWorkspace(tt.httpGet)
+			setAvailableSince(workspace, tt.availableSince)
+			if !tt.lastActivity.IsZero() {
+				workspace.Annotations = map[string]string{AnnotationHubLastActivity: tt.lastActivity.UTC().Format(time.RFC3339)}
+			}
+
+			result, decided := checkHubActivity(ctx, workspace, workspace.Spec.IdleShutdown)
+
+			assert.Equal(t, tt.decided, decided)
+			if decided {
+				assert.Equal(t, tt.idle, result.IsIdle)
+			}
+		})
+	}
+}
+
+func TestHubLastActivity_ClampsFutureActivity(t *testing.T) {
+	workspace := newHubActivityTestWorkspace(nil)
+	workspace.Annotations = map[string]string{AnnotationHubLastActivity: "9999-01-01T00:00:00Z"}
+
+	lastActivity, reported := HubLastActivity(workspace)
+
+	require.True(t, reported)
+	assert.WithinDuration(t, time.Now(), lastActivity, time.Minute)
+}
+
+func TestApplyHubActivityEnv(t *testing.T) {
+	workspace := newHubActivityTestWorkspace(nil)
+	workspace.Spec.Env = []corev1.EnvVar{{Name: JupyterHubUserEnvVar, Value: "alice"}}
+	podTemplate := newShutdownTestPodTemplate(nil)
+	podTemplate.Spec.Containers[0].Env = workspace.Spec.Env
+
+	applyHubActivityEnv(podTemplate, workspace, "http://hub-activity.jupyter-k8s-system.svc/")
+
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: JupyterHubUserEnvVar, Value: "alice"},
+		{Name: JupyterHubAPIURLEnvVar, Value: "http://hub-activity.jupyter-k8s-system.svc/namespaces/test-namespace/hub/api"},
+		{Name: JupyterHubActivityURLEnvVar, Value: "http://hub-activity.jupyter-k8s-system.svc/namespaces/test-namespace/hub/api/users/hub/activity"},
+		{Name: JupyterHubAPITokenEnvVar, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: GenerateHubActivityTokenSecretName(workspace)},
+			Key:                  HubActivityTokenSecretKey,
+		}}},
+	}, podTemplate.Spec.Containers[0].Env)
+	assert.Len(t, workspace.Spec.Env, 1)
+
+	// Without the URL of the API server, the workspace is not given the JupyterHub environment
+	podTemplate = newShutdownTestPodTemplate(nil)
+	applyHubActivityEnv(podTemplate, workspace, "")
+	assert.Empty(t, podTemplate.Spec.Containers[0].Env)
+}
+
+func TestEnsureHubActivityToken(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	workspace := newHubActivityTestWorkspace(nil)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+	key := types.NamespacedName{Name: GenerateHubActivityTokenSecretName(workspace), Namespace: workspace.Namespace}
+
+	// Creates the Secret with a token
+	require.NoError(t, rm.EnsureHubActivityToken(ctx, workspace))
+	secret := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, key, secret))
+	token := secret.Data[HubActivityTokenSecretKey]
+	assert.Len(t, token, DefaultGeneratedSecretLength)
+	assert.True(t, metav1.IsControlledBy(secret, workspace))
+
+	// Keeps the token
+	require.NoError(t, rm.EnsureHubActivityToken(ctx, workspace))
+	require.NoError(t, fakeClient.Get(ctx, key, secret))
+	assert.Equal(t, token, secret.Data[HubActivityTokenSecretKey])
+
+	// Deletes the Secret once hub activity detection is disabled
+	workspace.Spec.IdleShutdown.Detection.HubActivity = false
+	require.NoError(t, rm.EnsureHubActivityToken(ctx, workspace))
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, key, secret)))
+	require.NoError(t, rm.EnsureHubActivityToken(ctx, workspace))
+}
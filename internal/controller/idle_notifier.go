@@ -0,0 +1,165 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultIdleNotificationGracePeriod is the default time between the notification of an idle
+// workspace and its stop
+const DefaultIdleNotificationGracePeriod = 15 * time.Minute
+
+// Idle notification webhook calls are retried on transient errors; a notification that still
+// fails does not hold back the stop of the workspace
+const (
+	idleNotificationWebhookTimeout      = 10 * time.Second
+	idleNotificationWebhookAttempts     = 3
+	idleNotificationWebhookInitialDelay = 500 * time.Millisecond
+	idleNotificationWebhookMaxBodyBytes = 64 * 1024
+)
+
+// IdleNotifierInterface notifies the owners of idle workspaces, e.g. through a Slack or SMTP
+// bridge, before the controller stops them
+type IdleNotifierInterface interface {
+	// Notify tells the owner of the workspace that it stops at scheduledStop
+	Notify(ctx context.Context, workspace *workspacev1alpha1.Workspace, scheduledStop time.Time) error
+	// GracePeriod is the time between the notification and the stop, in which the owner
+	// can keep the workspace alive
+	GracePeriod() time.Duration
+}
+
+// IdleNotificationWebhookRequest is the JSON body posted to the idle notification webhook
+type IdleNotificationWebhookRequest struct {
+	Workspace    string `json:"workspace"`
+	Namespace    string `json:"namespace"`
+	WorkspaceUID string `json:"workspaceUID"`
+	DisplayName  string `json:"displayName,omitempty"`
+	// Owner is the user who created the workspace
+	Owner                string `json:"owner,omitempty"`
+	IdleTimeoutInMinutes int    `json:"idleTimeoutInMinutes"`
+	// ScheduledStopTime is the time the workspace stops at, in RFC 3339 format
+	ScheduledStopTime string `json:"scheduledStopTime"`
+	// KeepAliveAnnotation is the annotation the owner sets to the current time to keep the workspace alive
+	KeepAliveAnnotation string `json:"keepAliveAnnotation"`
+}
+
+// IdleNotificationWebhook implements IdleNotifierInterface by posting
+// IdleNotificationWebhookRequests to an HTTP endpoint
+type IdleNotificationWebhook struct {
+	endpoint    string
+	gracePeriod time.Duration
+	client      *http.Client
+	backoff     wait.Backoff
+}
+
+var _ IdleNotifierInterface = &IdleNotificationWebhook{}
+
+// NewIdleNotificationWebhook creates an IdleNotificationWebhook posting to endpoint.
+// If gracePeriod is zero or negative, DefaultIdleNotificationGracePeriod is used.
+func NewIdleNotificationWebhook(endpoint string, gracePeriod time.Duration) *IdleNotificationWebhook {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultIdleNotificationGracePeriod
+	}
+	return &IdleNotificationWebhook{
+		endpoint:    endpoint,
+		gracePeriod: gracePeriod,
+		client:      &http.Client{Timeout: idleNotificationWebhookTimeout},
+		backoff: wait.Backoff{
+			Duration: idleNotificationWebhookInitialDelay, Factor: 2, Steps: idleNotificationWebhookAttempts,
+		},
+	}
+}
+
+// GracePeriod returns the time between the notification and the stop of a workspace
+func (n *IdleNotificationWebhook) GracePeriod() time.Duration {
+	return n.gracePeriod
+}
+
+// Notify posts the notification, retrying transient failures with exponential backoff
+func (n *IdleNotificationWebhook) Notify(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	scheduledStop time.Time,
+) error {
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+
+	request := &IdleNotificationWebhookRequest{
+		Workspace:           workspace.Name,
+		Namespace:           workspace.Namespace,
+		WorkspaceUID:        string(workspace.UID),
+		DisplayName:         workspace.Spec.DisplayName,
+		Owner:               workspace.Annotations[AnnotationCreatedBy],
+		ScheduledStopTime:   scheduledStop.UTC().Format(time.RFC3339),
+		KeepAliveAnnotation: AnnotationKeepAlive,
+	}
+	if workspace.Spec.IdleShutdown != nil {
+		request.IdleTimeoutInMinutes = workspace.Spec.IdleShutdown.IdleTimeoutInMinutes
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idle notification webhook request: %w", err)
+	}
+
+	var lastErr error
+	err = wait.ExponentialBackoffWithContext(ctx, n.backoff, func(ctx context.Context) (bool, error) {
+		lastErr = n.post(ctx, payload)
+		if lastErr == nil {
+			return true, nil
+		}
+		if errors.Is(lastErr, errIdleNotificationWebhookRetryable) {
+			logger.V(1).Info("Idle notification webhook call failed, retrying", "error", lastErr.Error())
+			return false, nil
+		}
+		return false, lastErr
+	})
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// errIdleNotificationWebhookRetryable marks webhook failures worth retrying: network errors,
+// throttling and server errors
+var errIdleNotificationWebhookRetryable = errors.New("retryable idle notification webhook error")
+
+// post performs a single webhook call
+func (n *IdleNotificationWebhook) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create idle notification webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errIdleNotificationWebhookRetryable, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, idleNotificationWebhookMaxBodyBytes))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%w: status %d", errIdleNotificationWebhookRetryable, resp.StatusCode)
+	default:
+		return fmt.Errorf("idle notification webhook rejected the request: status %d: %s",
+			resp.StatusCode, bytes.TrimSpace(body))
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newTestIdleNotificationWebhook(endpoint string) *IdleNotificationWebhook {
+	notifier := NewIdleNotificationWebhook(endpoint, 0)
+	notifier.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: idleNotificationWebhookAttempts}
+	return notifier
+}
+
+func newIdleNotificationTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testWorkspaceName,
+			Namespace:   testNamespaceName,
+			UID:         "workspace-uid",
+			Annotations: map[string]string{AnnotationCreatedBy: "alice"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:   "Alice's notebook",
+			DesiredStatus: DesiredStateRunning,
+			IdleShutdown:  &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30},
+		},
+	}
+}
+
+func TestIdleNotificationWebhook_Notify(t *testing.T) {
+	var received IdleNotificationWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	scheduledStop := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	notifier := newTestIdleNotificationWebhook(server.URL)
+	require.NoError(t, notifier.Notify(context.Background(), newIdleNotificationTestWorkspace(), scheduledStop))
+
+	assert.Equal(t, DefaultIdleNotificationGracePeriod, notifier.GracePeriod())
+	assert.Equal(t, IdleNotificationWebhookRequest{
+		Workspace:            testWorkspaceName,
+		Namespace:            testNamespaceName,
+		WorkspaceUID:         "workspace-uid",
+		DisplayName:          "Alice's notebook",
+		Owner:                "alice",
+		IdleTimeoutInMinutes: 30,
+		ScheduledStopTime:    "2026-10-01T12:00:00Z",
+		KeepAliveAnnotation:  AnnotationKeepAlive,
+	}, received)
+}
+
+func TestIdleNotificationWebhook_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < idleNotificationWebhookAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	err := newTestIdleNotificationWebhook(server.URL).
+		Notify(context.Background(), newIdleNotificationTestWorkspace(), time.Now())
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(idleNotificationWebhookAttempts), calls.Load())
+}
+
+func TestIdleNotificationWebhook_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, "unknown owner", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := newTestIdleNotificationWebhook(server.URL).
+		Notify(context.Background(), newIdleNotificationTestWorkspace(), time.Now())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown owner")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// fakeIdleNotifier records the notifications instead of sending them
+type fakeIdleNotifier struct {
+	notified []time.Time
+	err      error
+}
+
+func (n *fakeIdleNotifier) Notify(_ context.Context, _ *workspacev1alpha1.Workspace, scheduledStop time.Time) error {
+	n.notified = append(n.notified, scheduledStop)
+	return n.err
+}
+
+func (n *fakeIdleNotifier) GracePeriod() time.Duration {
+	return 10 * time.Minute
+}
+
+func newIdleNotificationTestStateMachine(
+	t *testing.T,
+	workspace *workspacev1alpha1.Workspace,
+	notifier IdleNotifierInterface,
+) (*StateMachine, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	return &StateMachine{
+		resourceManager: NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil),
+		recorder:        record.NewFakeRecorder(10),
+		idleChecker:     NewWorkspaceIdleChecker(fakeClient, time.Hour),
+		idleNotifier:    notifier,
+	}, fakeClient
+}
+
+func TestScheduleIdleStop_NotifiesAndSchedulesStop(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	notifier := &fakeIdleNotifier{}
+	sm, _ := newIdleNotificationTestStateMachine(t, workspace, notifier)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := sm.scheduleIdleStop(context.Background(), workspace, workspace.Spec.IdleShutdown, now)
+
+	require.NoError(t, err)
+	require.Len(t, notifier.notified, 1)
+	assert.Equal(t, now.Add(10*time.Minute), notifier.notified[0])
+	assert.Equal(t, "2026-10-01T12:10:00Z", workspace.Annotations[AnnotationIdleStopScheduledAt])
+	assert.Equal(t, 10*time.Minute, result.RequeueAfter)
+	assert.Equal(t, DesiredStateRunning, workspace.Spec.DesiredStatus)
+}
+
+func TestScheduleIdleStop_SchedulesStopWhenNotificationFails(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	notifier := &fakeIdleNotifier{err: assert.AnError}
+	sm, _ := newIdleNotificationTestStateMachine(t, workspace, notifier)
+
+	_, err := sm.scheduleIdleStop(context.Background(), workspace, workspace.Spec.IdleShutdown, time.Now())
+
+	require.NoError(t, err)
+	assert.Contains(t, workspace.Annotations, AnnotationIdleStopScheduledAt)
+}
+
+func TestScheduleIdleStop_WaitsForGracePeriod(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	workspace.Annotations[AnnotationIdleStopScheduledAt] = "2026-10-01T12:10:00Z"
+	notifier := &fakeIdleNotifier{}
+	sm, _ := newIdleNotificationTestStateMachine(t, workspace, notifier)
+	now := time.Date(2026, 10, 1, 12, 4, 0, 0, time.UTC)
+
+	result, err := sm.scheduleIdleStop(context.Background(), workspace, workspace.Spec.IdleShutdown, now)
+
+	require.NoError(t, err)
+	assert.Empty(t, notifier.notified)
+	assert.Equal(t, 6*time.Minute, result.RequeueAfter)
+	assert.Equal(t, DesiredStateRunning, workspace.Spec.DesiredStatus)
+}
+
+func TestScheduleIdleStop_StopsAfterGracePeriod(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	workspace.Annotations[AnnotationIdleStopScheduledAt] = "2026-10-01T12:10:00Z"
+	// A keep-alive set before the notification does not cancel the stop
+	workspace.Annotations[AnnotationKeepAlive] = "2026-10-01T11:30:00Z"
+	sm, fakeClient := newIdleNotificationTestStateMachine(t, workspace, &fakeIdleNotifier{})
+	now := time.Date(2026, 10, 1, 12, 10, 0, 0, time.UTC)
+
+	_, err := sm.scheduleIdleStop(context.Background(), workspace, workspace.Spec.IdleShutdown, now)
+	require.NoError(t, err)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	assert.NotContains(t, stored.Annotations, AnnotationIdleStopScheduledAt)
+}
+
+func TestScheduleIdleStop_KeepAliveCancelsStop(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	workspace.Annotations[AnnotationIdleStopScheduledAt] = "2026-10-01T12:10:00Z"
+	workspace.Annotations[AnnotationKeepAlive] = "2026-10-01T12:05:00Z"
+	sm, fakeClient := newIdleNotificationTestStateMachine(t, workspace, &fakeIdleNotifier{})
+	now := time.Date(2026, 10, 1, 12, 10, 0, 0, time.UTC)
+
+	result, err := sm.scheduleIdleStop(context.Background(), workspace, workspace.Spec.IdleShutdown, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, result.RequeueAfter)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateRunning, stored.Spec.DesiredStatus)
+	assert.NotContains(t, stored.Annotations, AnnotationIdleStopScheduledAt)
+}
+
+func TestCancelIdleStop_NoopWithoutScheduledStop(t *testing.T) {
+	workspace := newIdleNotificationTestWorkspace()
+	recorder := record.NewFakeRecorder(10)
+	sm, _ := newIdleNotificationTestStateMachine(t, workspace, &fakeIdleNotifier{})
+	sm.recorder = recorder
+
+	require.NoError(t, sm.cancelIdleStop(context.Background(), workspace, "the workspace is active again"))
+	assert.Empty(t, recorder.Events)
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 )
 
 // ImageResolver handles the resolution of image references
@@ -17,6 +18,9 @@ type ImageResolver struct {
 	// Registry prefix to use for images (e.g. "example.com/my-registry")
 	// If empty, uses the image names directly (for local development)
 	Registry string
+
+	// config overrides Registry with the registry of the OperatorConfig, when set
+	config *operatorconfig.Store
 }
 
 // NewImageResolver creates a new image resolver
@@ -26,6 +30,20 @@ func NewImageResolver(registry string) *ImageResolver {
 	}
 }
 
+// WithOperatorConfig makes the resolver follow the registry of the OperatorConfig
+func (r *ImageResolver) WithOperatorConfig(config *operatorconfig.Store) *ImageResolver {
+	r.config = config
+	return r
+}
+
+// registry returns the registry prefix of the images
+func (r *ImageResolver) registry() string {
+	if r.config != nil {
+		return r.config.Settings().ApplicationImagesRegistry
+	}
+	return r.Registry
+}
+
 // ResolveImage resolves an image reference from a Workspace spec
 // It handles:
 // - Direct image references
@@ -47,7 +65,8 @@ func (r *ImageResolver) ResolveImage(workspace *workspacev1alpha1.Workspace) str
 	}
 
 	// Add registry prefix if it's set and the image doesn't already have one
-	if r.Registry != "" {
+	registry := r.registry()
+	if registry != "" {
 		// Split image into repository and tag parts
 		parts := strings.SplitN(image, ":", 2)
 		repository := parts[0]
@@ -59,7 +78,7 @@ func (r *ImageResolver) ResolveImage(workspace *workspacev1alpha1.Workspace) str
 		// Check if repository has a slash (which would indicate it already has a registry)
 		if !strings.Contains(repository, "/") {
 			// Simple image name - prepend the registry
-			return fmt.Sprintf("%s/%s:%s", r.Registry, repository, tag)
+			return fmt.Sprintf("%s/%s:%s", registry, repository, tag)
 		}
 	}
 
@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// JupyterConfigBuilder handles creation of the ConfigMap holding the Jupyter configuration of a Workspace
+type JupyterConfigBuilder struct {
+	scheme *runtime.Scheme
+}
+
+// NewJupyterConfigBuilder creates a new JupyterConfigBuilder
+func NewJupyterConfigBuilder(scheme *runtime.Scheme) *JupyterConfigBuilder {
+	return &JupyterConfigBuilder{scheme: scheme}
+}
+
+// BuildConfigMap creates the ConfigMap holding the Jupyter configuration generated from the
+// spec.jupyterServerConfig of the given Workspace
+func (jb *JupyterConfigBuilder) BuildConfigMap(workspace *workspacev1alpha1.Workspace) (*corev1.ConfigMap, error) {
+	data, err := buildJupyterConfigData(workspace.Spec.JupyterServerConfig)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateJupyterConfigMapName(workspace),
+			Namespace: workspace.Namespace,
+			Labels:    GenerateLabels(workspace.Name),
+		},
+		Data: data,
+	}
+
+	// Set owner reference for garbage collection
+	if err := controllerutil.SetControllerReference(workspace, configMap, jb.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	return configMap, nil
+}
+
+// buildJupyterConfigData generates the files of the Jupyter configuration: the Jupyter server
+// configuration, and the JupyterLab settings overrides when the config has any
+func buildJupyterConfigData(config *workspacev1alpha1.JupyterServerConfig) (map[string]string, error) {
+	data := map[string]string{
+		JupyterServerConfigFileName: renderJupyterServerConfig(config),
+	}
+	if config.LabSettingsOverrides != nil && len(config.LabSettingsOverrides.Raw) > 0 {
+		var overrides bytes.Buffer
+		if err := json.Indent(&overrides, config.LabSettingsOverrides.Raw, "", "  "); err != nil {
+			return nil, fmt.Errorf("invalid JupyterLab settings overrides: %w", err)
+		}
+		data[JupyterLabOverridesFileName] = overrides.String() + "\n"
+	}
+	return data, nil
+}
+
+// renderJupyterServerConfig renders the jupyter_server_config.py file of the config. The base URL
+// is read from the environment, where the access strategy of the workspace sets it.
+func renderJupyterServerConfig(config *workspacev1alpha1.JupyterServerConfig) string {
+	var b strings.Builder
+	b.WriteString("# Generated by jupyter-k8s from the jupyterServerConfig of the workspace\n")
+	b.WriteString("import os\n\n")
+	fmt.Fprintf(&b, "if %[1]s in os.environ:\n    c.ServerApp.base_url = os.environ[%[1]s]\n", strconv.Quote(JupyterBaseURLEnvVar))
+
+	if config.DefaultKernel != "" {
+		fmt.Fprintf(&b, "c.MultiKernelManager.default_kernel_name = %s\n", strconv.QuoteToASCII(config.DefaultKernel))
+	}
+	if config.TerminalsEnabled != nil {
+		fmt.Fprintf(&b, "c.ServerApp.terminals_enabled = %s\n", pythonBool(*config.TerminalsEnabled))
+	}
+	if len(config.Extensions) > 0 {
+		b.WriteString("c.ServerApp.jpserver_extensions.update({\n")
+		for _, name := range slices.Sorted(maps.Keys(config.Extensions)) {
+			fmt.Fprintf(&b, "    %s: %s,\n", strconv.QuoteToASCII(name), pythonBool(config.Extensions[name]))
+		}
+		b.WriteString("})\n")
+	}
+	return b.String()
+}
+
+// pythonBool returns the Python literal of value
+func pythonBool(value bool) string {
+	if value {
+		return "True"
+	}
+	return "False"
+}
+
+// jupyterConfigHash returns the hash of the files of a Jupyter configuration
+func jupyterConfigHash(data map[string]string) (string, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize jupyter config: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newJupyterConfigTestWorkspace(config *workspacev1alpha1.JupyterServerConfig) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceName, UID: types.UID("uid-ws")},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image:               imageMinimalNotebook,
+			JupyterServerConfig: config,
+		},
+	}
+}
+
+func TestBuildJupyterConfigMap(t *testing.T) {
+	builder := NewJupyterConfigBuilder(newNetworkPolicyTestScheme(t))
+	workspace := newJupyterConfigTestWorkspace(&workspacev1alpha1.JupyterServerConfig{
+		DefaultKernel:        "python3",
+		TerminalsEnabled:     ptr.To(false),
+		Extensions:           map[string]bool{"jupyterlab_git": true, "jupyter_collaboration": false},
+		LabSettingsOverrides: &runtime.RawExtension{Raw: []byte(`{"@jupyterlab/apputils-extension:themes":{"theme":"JupyterLab Dark"}}`)},
+	})
+
+	configMap, err := builder.BuildConfigMap(workspace)
+
+	require.NoError(t, err)
+	assert.Equal(t, GenerateJupyterConfigMapName(workspace), configMap.Name)
+	assert.True(t, metav1.IsControlledBy(configMap, workspace))
+	assert.Equal(t, `# Generated by jupyter-k8s from the jupyterServerConfig of the workspace
+import os
+
+if "JUPYTER_BASE_URL" in os.environ:
+    c.ServerApp.base_url = os.environ["JUPYTER_BASE_URL"]
+c.MultiKernelManager.default_kernel_name = "python3"
+c.ServerApp.terminals_enabled = False
+c.ServerApp.jpserver_extensions.update({
+    "jupyter_collaboration": False,
+    "jupyterlab_git": True,
+})
+`, configMap.Data[JupyterServerConfigFileName])
+	assert.Equal(t, `{
+  "@jupyterlab/apputils-extension:themes": {
+    "theme": "JupyterLab Dark"
+  }
+}
+`, configMap.Data[JupyterLabOverridesFileName])
+}
+
+func TestBuildJupyterConfigMap_QuotesKernelName(t *testing.T) {
+	builder := NewJupyterConfigBuilder(newNetworkPolicyTestScheme(t))
+	workspace := newJupyterConfigTestWorkspace(&workspacev1alpha1.JupyterServerConfig{DefaultKernel: "ir\"\nimport sys"})
+
+	configMap, err := builder.BuildConfigMap(workspace)
+
+	require.NoError(t, err)
+	assert.Contains(t, configMap.Data[JupyterServerConfigFileName], `c.MultiKernelManager.default_kernel_name = "ir\"\nimport sys"`)
+	assert.NotContains(t, configMap.Data, JupyterLabOverridesFileName)
+}
+
+func TestApplyJupyterServerConfig(t *testing.T) {
+	podTemplate := newShutdownTestPodTemplate(nil)
+	workspace := newJupyterConfigTestWorkspace(&workspacev1alpha1.JupyterServerConfig{
+		DefaultKernel:        "python3",
+		LabSettingsOverrides: &runtime.RawExtension{Raw: []byte(`{}`)},
+		LabSettingsDir:       "/usr/share/jupyter/lab/settings",
+	})
+
+	require.NoError(t, applyJupyterServerConfig(podTemplate, workspace))
+
+	require.Len(t, podTemplate.Spec.Volumes, 1)
+	assert.Equal(t, GenerateJupyterConfigMapName(workspace), podTemplate.Spec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, []corev1.VolumeMount{
+		{
+			Name:      JupyterConfigVolumeName,
+			MountPath: "/usr/local/etc/jupyter/jupyter_server_config.py",
+			SubPath:   JupyterServerConfigFileName,
+			ReadOnly:  true,
+		},
+		{
+			Name:      JupyterConfigVolumeName,
+			MountPath: "/usr/share/jupyter/lab/settings/overrides.json",
+			SubPath:   JupyterLabOverridesFileName,
+			ReadOnly:  true,
+		},
+	}, podTemplate.Spec.Containers[0].VolumeMounts)
+	hash := podTemplate.Annotations[AnnotationJupyterConfigHash]
+	assert.NotEmpty(t, hash)
+
+	// A configuration change changes the hash, so that the pod is recreated
+	workspace.Spec.JupyterServerConfig.DefaultKernel = "ir"
+	podTemplate = newShutdownTestPodTemplate(nil)
+	require.NoError(t, applyJupyterServerConfig(podTemplate, workspace))
+	assert.NotEqual(t, hash, podTemplate.Annotations[AnnotationJupyterConfigHash])
+}
+
+func TestApplyJupyterServerConfig_UnsetLeavesPodAlone(t *testing.T) {
+	podTemplate := newShutdownTestPodTemplate(nil)
+
+	require.NoError(t, applyJupyterServerConfig(podTemplate, newJupyterConfigTestWorkspace(nil)))
+
+	assert.Empty(t, podTemplate.Spec.Volumes)
+	assert.Empty(t, podTemplate.Spec.Containers[0].VolumeMounts)
+	assert.NotContains(t, podTemplate.Annotations, AnnotationJupyterConfigHash)
+}
+
+func TestEnsureJupyterConfigMap(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	workspace := newJupyterConfigTestWorkspace(&workspacev1alpha1.JupyterServerConfig{TerminalsEnabled: ptr.To(false)})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+	key := types.NamespacedName{Name: GenerateJupyterConfigMapName(workspace), Namespace: workspace.Namespace}
+
+	// Creates the ConfigMap
+	require.NoError(t, rm.EnsureJupyterConfigMap(ctx, workspace))
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(ctx, key, configMap))
+	assert.Contains(t, configMap.Data[JupyterServerConfigFileName], "c.ServerApp.terminals_enabled = False")
+
+	// Reverts manual changes
+	configMap.Data[JupyterServerConfigFileName] = "c.ServerApp.terminals_enabled = True\n"
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	require.NoError(t, rm.EnsureJupyterConfigMap(ctx, workspace))
+	require.NoError(t, fakeClient.Get(ctx, key, configMap))
+	assert.Contains(t, configMap.Data[JupyterServerConfigFileName], "c.ServerApp.terminals_enabled = False")
+
+	// Deletes the ConfigMap once the config is removed
+	workspace.Spec.JupyterServerConfig = nil
+	require.NoError(t, rm.EnsureJupyterConfigMap(ctx, workspace))
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, key, configMap)))
+	require.NoError(t, rm.EnsureJupyterConfigMap(ctx, workspace))
+}
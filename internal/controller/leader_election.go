@@ -0,0 +1,18 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+// ResolveLeaderElectionID returns the explicit leader election ID if set, otherwise the default ID
+// suffixed with the controller namespace so that leases of installs in different namespaces never collide
+func ResolveLeaderElectionID(leaderElectionID, defaultID, podNamespace string) string {
+	if leaderElectionID != "" {
+		return leaderElectionID
+	}
+	if podNamespace == "" {
+		return defaultID
+	}
+	return defaultID + "-" + podNamespace
+}
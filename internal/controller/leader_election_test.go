@@ -0,0 +1,19 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLeaderElectionID(t *testing.T) {
+	assert.Equal(t, "custom", ResolveLeaderElectionID("custom", "a446807b.jupyter.org", "jupyter-k8s-system"))
+	assert.Equal(t, "a446807b.jupyter.org-jupyter-k8s-system",
+		ResolveLeaderElectionID("", "a446807b.jupyter.org", "jupyter-k8s-system"))
+	assert.Equal(t, "a446807b.jupyter.org", ResolveLeaderElectionID("", "a446807b.jupyter.org", ""))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// NetworkPolicyBuilder handles creation of the NetworkPolicy isolating the pod of a Workspace
+type NetworkPolicyBuilder struct {
+	scheme *runtime.Scheme
+	// controllerNamespace is always allowed in, so that the controller can check the idleness of workspaces
+	controllerNamespace string
+}
+
+// NewNetworkPolicyBuilder creates a new NetworkPolicyBuilder
+func NewNetworkPolicyBuilder(scheme *runtime.Scheme, controllerNamespace string) *NetworkPolicyBuilder {
+	return &NetworkPolicyBuilder{
+		scheme:              scheme,
+		controllerNamespace: controllerNamespace,
+	}
+}
+
+// BuildNetworkPolicy creates the NetworkPolicy for the network isolation of the given Workspace
+func (nb *NetworkPolicyBuilder) BuildNetworkPolicy(workspace *workspacev1alpha1.Workspace) (*networkingv1.NetworkPolicy, error) {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateNetworkPolicyName(workspace),
+			Namespace: workspace.Namespace,
+			Labels:    GenerateLabels(workspace.Name),
+		},
+		Spec: nb.buildNetworkPolicySpec(workspace.Spec.NetworkIsolation, workspace.Name),
+	}
+
+	// Set owner reference for garbage collection
+	if err := controllerutil.SetControllerReference(workspace, policy, nb.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	return policy, nil
+}
+
+// buildNetworkPolicySpec denies all ingress traffic to the workspace pod except from the allowed
+// namespaces and CIDRs. A policy without ingress rule denies all ingress traffic.
+func (nb *NetworkPolicyBuilder) buildNetworkPolicySpec(
+	isolation *workspacev1alpha1.NetworkIsolation, workspaceName string) networkingv1.NetworkPolicySpec {
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: GenerateLabels(workspaceName)},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+
+	var peers []networkingv1.NetworkPolicyPeer
+	seen := map[string]bool{}
+	allowNamespace := func(namespace string) {
+		if namespace == "" || seen[namespace] {
+			return
+		}
+		seen[namespace] = true
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelMetadataName: namespace},
+			},
+		})
+	}
+
+	allowNamespace(nb.controllerNamespace)
+	if isolation != nil {
+		allowNamespace(isolation.IngressControllerNamespace)
+		for _, namespace := range isolation.AllowedNamespaces {
+			allowNamespace(namespace)
+		}
+		for _, cidr := range isolation.AllowedCIDRs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+	}
+
+	// An ingress rule without peers would allow all traffic
+	if len(peers) > 0 {
+		spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: peers}}
+	}
+	return spec
+}
+
+// NeedsUpdate checks if the existing network policy differs from the one the workspace requires
+func (nb *NetworkPolicyBuilder) NeedsUpdate(existingPolicy *networkingv1.NetworkPolicy, workspace *workspacev1alpha1.Workspace) bool {
+	desiredSpec := nb.buildNetworkPolicySpec(workspace.Spec.NetworkIsolation, workspace.Name)
+	return !equality.Semantic.DeepEqual(existingPolicy.Spec, desiredSpec)
+}
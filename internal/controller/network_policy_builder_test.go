@@ -0,0 +1,122 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newNetworkPolicyTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newNetworkPolicyTestWorkspace(isolation *workspacev1alpha1.NetworkIsolation) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a", UID: types.UID("uid-ws")},
+		Spec:       workspacev1alpha1.WorkspaceSpec{NetworkIsolation: isolation},
+	}
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}},
+	}
+}
+
+func TestBuildNetworkPolicy(t *testing.T) {
+	builder := NewNetworkPolicyBuilder(newNetworkPolicyTestScheme(t), "jupyter-k8s-system")
+	workspace := newNetworkPolicyTestWorkspace(&workspacev1alpha1.NetworkIsolation{
+		IngressControllerNamespace: "traefik",
+		AllowedNamespaces:          []string{"monitoring", "traefik", "jupyter-k8s-system"},
+		AllowedCIDRs:               []string{"10.0.0.0/16"},
+	})
+
+	policy, err := builder.BuildNetworkPolicy(workspace)
+
+	require.NoError(t, err)
+	assert.Equal(t, GenerateNetworkPolicyName(workspace), policy.Name)
+	assert.Equal(t, "team-a", policy.Namespace)
+	assert.True(t, metav1.IsControlledBy(policy, workspace))
+	assert.Equal(t, GenerateLabels("ws"), policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	require.Len(t, policy.Spec.Ingress, 1)
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{
+		namespacePeer("jupyter-k8s-system"),
+		namespacePeer("traefik"),
+		namespacePeer("monitoring"),
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}},
+	}, policy.Spec.Ingress[0].From)
+}
+
+func TestBuildNetworkPolicy_DeniesAllWithoutPeers(t *testing.T) {
+	builder := NewNetworkPolicyBuilder(newNetworkPolicyTestScheme(t), "")
+
+	policy, err := builder.BuildNetworkPolicy(newNetworkPolicyTestWorkspace(&workspacev1alpha1.NetworkIsolation{}))
+
+	require.NoError(t, err)
+	assert.Empty(t, policy.Spec.Ingress)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+}
+
+func TestEnsureNetworkPolicy(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	workspace := newNetworkPolicyTestWorkspace(&workspacev1alpha1.NetworkIsolation{AllowedCIDRs: []string{"10.0.0.0/16"}})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+	key := types.NamespacedName{Name: GenerateNetworkPolicyName(workspace), Namespace: workspace.Namespace}
+
+	// Creates the policy
+	require.NoError(t, rm.EnsureNetworkPolicy(ctx, workspace))
+	policy := &networkingv1.NetworkPolicy{}
+	require.NoError(t, fakeClient.Get(ctx, key, policy))
+	assert.Equal(t, "10.0.0.0/16", policy.Spec.Ingress[0].From[0].IPBlock.CIDR)
+
+	// Reverts manual changes
+	policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{}}
+	require.NoError(t, fakeClient.Update(ctx, policy))
+	require.NoError(t, rm.EnsureNetworkPolicy(ctx, workspace))
+	require.NoError(t, fakeClient.Get(ctx, key, policy))
+	require.Len(t, policy.Spec.Ingress[0].From, 1)
+
+	// Deletes the policy once isolation is removed
+	workspace.Spec.NetworkIsolation = nil
+	require.NoError(t, rm.EnsureNetworkPolicy(ctx, workspace))
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, key, policy)))
+	require.NoError(t, rm.EnsureNetworkPolicy(ctx, workspace))
+}
+
+func TestEnsureNetworkPolicy_KeepsUncontrolledPolicyWithoutIsolation(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	workspace := newNetworkPolicyTestWorkspace(nil)
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateNetworkPolicyName(workspace), Namespace: workspace.Namespace},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, policy).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+	require.NoError(t, rm.EnsureNetworkPolicy(ctx, workspace))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy))
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// nodeInterruptionTaints are the taints that node autoprovisioners and termination handlers put on
+// a node shortly before it is terminated, e.g. when its spot instance is reclaimed
+var nodeInterruptionTaints = []string{
+	// Karpenter, when it disrupts a node, including on spot interruption notices
+	"karpenter.sh/disrupted",
+	"karpenter.sh/disruption",
+	// AWS Node Termination Handler, on spot interruption notices and ASG lifecycle terminations
+	"aws-node-termination-handler/spot-itn",
+	"aws-node-termination-handler/asg-lifecycle-termination",
+	// GKE, on the termination notice of spot and preemptible VMs
+	"cloud.google.com/impending-node-termination",
+	// Cluster Autoscaler, when it scales down a node
+	"ToBeDeletedByClusterAutoscaler",
+}
+
+// nodeInterruptions counts the workspaces rescheduled from interrupted nodes
+var nodeInterruptions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "workspace_node_interruptions_total",
+	Help: "Number of workspaces rescheduled from an interrupted node, by the taint that signaled the interruption",
+}, []string{"reason"})
+
+func init() {
+	metrics.Registry.MustRegister(nodeInterruptions)
+}
+
+// NodeInterruptionHandler reschedules running workspaces whose node is about to be terminated, e.g.
+// on a spot interruption notice. It deletes the pod of the workspace from the tainted node, so that
+// the Deployment recreates it on another node before the node goes away, and reports it in the
+// Rescheduling condition rather than leaving users with a dead kernel.
+type NodeInterruptionHandler struct {
+	client   client.Client
+	recorder record.EventRecorder
+	now      func() time.Time
+}
+
+// NewNodeInterruptionHandler creates a new NodeInterruptionHandler
+func NewNodeInterruptionHandler(k8sClient client.Client, recorder record.EventRecorder) *NodeInterruptionHandler {
+	return &NodeInterruptionHandler{
+		client:   k8sClient,
+		recorder: recorder,
+		now:      time.Now,
+	}
+}
+
+// nodeInterruptionReason returns the taint of the node signaling its interruption, if any
+func nodeInterruptionReason(node *corev1.Node) (string, bool) {
+	for _, taint := range node.Spec.Taints {
+		for _, key := range nodeInterruptionTaints {
+			if taint.Key == key {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isInterruptedNode returns true when the object is a node being interrupted
+func isInterruptedNode(obj client.Object) bool {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return false
+	}
+	_, interrupted := nodeInterruptionReason(node)
+	return interrupted
+}
+
+// HandleNodeEvents maps an interrupted node to the workspaces whose pods run on it
+func (h *NodeInterruptionHandler) HandleNodeEvents(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isInterruptedNode(obj) {
+		return nil
+	}
+	logger := logf.FromContext(ctx).WithValues("node", obj.GetName())
+
+	podList := &corev1.PodList{}
+	if err := h.client.List(ctx, podList, client.MatchingLabels{
+		AppLabel:       AppLabelValue,
+		LabelComponent: ResourcePrefix,
+	}); err != nil {
+		logger.Error(err, "Failed to list workspace pods of interrupted node")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, pod := range podList.Items {
+		workspaceName := pod.Labels[workspaceutil.LabelWorkspaceName]
+		if pod.Spec.NodeName != obj.GetName() || workspaceName == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKey{Name: workspaceName, Namespace: pod.Namespace},
+		})
+	}
+	if len(requests) > 0 {
+		logger.Info("Detected node interruption", "workspaces", len(requests))
+	}
+	return requests
+}
+
+// Check reschedules a running workspace whose pod runs on an interrupted node, and removes the
+// Rescheduling condition once the workspace runs on another node. It only sets the condition and
+// status.lastInterruption in memory; the status update that follows persists them.
+func (h *NodeInterruptionHandler) Check(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if h == nil {
+		return nil
+	}
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+
+	podList := &corev1.PodList{}
+	if err := h.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return fmt.Errorf("failed to list workspace pods: %w", err)
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Spec.NodeName == "" {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := h.client.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get node %s: %w", pod.Spec.NodeName, err)
+		}
+		reason, interrupted := nodeInterruptionReason(node)
+		if !interrupted {
+			continue
+		}
+
+		if err := h.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete workspace pod %s: %w", pod.Name, err)
+		}
+		logger.Info("Deleted pod of workspace from interrupted node", "pod", pod.Name, "node", node.Name, "taint", reason)
+		nodeInterruptions.WithLabelValues(reason).Inc()
+
+		workspace.Status.LastInterruption = &workspacev1alpha1.WorkspaceInterruptionStatus{
+			NodeName: node.Name,
+			PodName:  pod.Name,
+			Reason:   reason,
+			Time:     metav1.NewTime(h.now()),
+		}
+		message := fmt.Sprintf("Node %s is being terminated (%s), rescheduling the workspace on another node",
+			node.Name, reason)
+		h.recorder.Event(workspace, corev1.EventTypeWarning, "NodeInterrupted", message)
+		condition := NewCondition(ConditionTypeRescheduling, metav1.ConditionTrue, ReasonNodeInterrupted, message)
+		if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+			workspace.Status.Conditions = conditions
+		}
+		return nil
+	}
+
+	// The workspace is rescheduled once a pod other than the deleted one is ready
+	interruption := workspace.Status.LastInterruption
+	podStatus := workspace.Status.PodStatus
+	if interruption != nil && podStatus != nil && podStatus.Name != interruption.PodName && isPodStatusReady(podStatus) &&
+		FindCondition(&workspace.Status.Conditions, ConditionTypeRescheduling) != nil {
+		h.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceRescheduled",
+			fmt.Sprintf("Workspace was rescheduled from interrupted node %s", interruption.NodeName))
+		removeReschedulingCondition(workspace)
+	}
+	return nil
+}
+
+// isPodStatusReady returns true when the workspace pod runs and all its containers are ready
+func isPodStatusReady(podStatus *workspacev1alpha1.WorkspacePodStatus) bool {
+	if podStatus.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, container := range podStatus.Containers {
+		if !container.Init && !container.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// removeReschedulingCondition drops the Rescheduling condition once the workspace runs on another
+// node, or stops
+func removeReschedulingCondition(workspace *workspacev1alpha1.Workspace) {
+	if FindCondition(&workspace.Status.Conditions, ConditionTypeRescheduling) == nil {
+		return
+	}
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions)-1)
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeRescheduling {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newInterruptionTestNode(name string, taints ...string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, taint := range taints {
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: taint, Effect: corev1.TaintEffectNoSchedule})
+	}
+	return node
+}
+
+func newInterruptionTestPod(name, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: GenerateLabels("ws")},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func newTestNodeInterruptionHandler(t *testing.T, objects ...client.Object) (*NodeInterruptionHandler, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	recorder := record.NewFakeRecorder(10)
+	return NewNodeInterruptionHandler(k8sClient, recorder), k8sClient, recorder
+}
+
+func TestNodeInterruptionHandler_ReschedulesPodFromInterruptedNode(t *testing.T) {
+	now := time.Now()
+	handler, k8sClient, recorder := newTestNodeInterruptionHandler(t,
+		newInterruptionTestNode("spot-node", "aws-node-termination-handler/spot-itn"),
+		newInterruptionTestPod("ws-pod", "spot-node"))
+	handler.now = func() time.Time { return now }
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+
+	require.NoError(t, handler.Check(context.Background(), workspace))
+
+	pods := &corev1.PodList{}
+	require.NoError(t, k8sClient.List(context.Background(), pods))
+	assert.Empty(t, pods.Items)
+
+	rescheduling := FindCondition(&workspace.Status.Conditions, ConditionTypeRescheduling)
+	require.NotNil(t, rescheduling)
+	assert.Equal(t, metav1.ConditionTrue, rescheduling.Status)
+	assert.Equal(t, ReasonNodeInterrupted, rescheduling.Reason)
+	assert.Contains(t, rescheduling.Message, "spot-node")
+	assert.Equal(t, &workspacev1alpha1.WorkspaceInterruptionStatus{
+		NodeName: "spot-node",
+		PodName:  "ws-pod",
+		Reason:   "aws-node-termination-handler/spot-itn",
+		Time:     metav1.NewTime(now),
+	}, workspace.Status.LastInterruption)
+	assert.Contains(t, <-recorder.Events, "NodeInterrupted")
+}
+
+func TestNodeInterruptionHandler_KeepsPodOnHealthyNode(t *testing.T) {
+	handler, k8sClient, recorder := newTestNodeInterruptionHandler(t,
+		newInterruptionTestNode("node", "example.com/dedicated"),
+		newInterruptionTestPod("ws-pod", "node"))
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+
+	require.NoError(t, handler.Check(context.Background(), workspace))
+
+	pods := &corev1.PodList{}
+	require.NoError(t, k8sClient.List(context.Background(), pods))
+	assert.Len(t, pods.Items, 1)
+	assert.Empty(t, workspace.Status.Conditions)
+	assert.Nil(t, workspace.Status.LastInterruption)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestNodeInterruptionHandler_RemovesConditionOnceRescheduled(t *testing.T) {
+	handler, _, recorder := newTestNodeInterruptionHandler(t,
+		newInterruptionTestNode("node"),
+		newInterruptionTestPod("ws-new-pod", "node"))
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+	workspace.Status.LastInterruption = &workspacev1alpha1.WorkspaceInterruptionStatus{NodeName: "spot-node", PodName: "ws-pod"}
+	workspace.Status.Conditions = []metav1.Condition{
+		NewCondition(ConditionTypeRescheduling, metav1.ConditionTrue, ReasonNodeInterrupted, "rescheduling"),
+	}
+
+	// Not ready yet
+	workspace.Status.PodStatus = &workspacev1alpha1.WorkspacePodStatus{Name: "ws-new-pod", Phase: corev1.PodPending}
+	require.NoError(t, handler.Check(context.Background(), workspace))
+	assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeRescheduling))
+
+	workspace.Status.PodStatus = &workspacev1alpha1.WorkspacePodStatus{
+		Name:  "ws-new-pod",
+		Phase: corev1.PodRunning,
+		Containers: []workspacev1alpha1.WorkspaceContainerStatus{
+			{Name: "init", Init: true},
+			{Name: "workspace", Ready: true},
+		},
+	}
+	require.NoError(t, handler.Check(context.Background(), workspace))
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeRescheduling))
+	assert.NotNil(t, workspace.Status.LastInterruption)
+	assert.Contains(t, <-recorder.Events, "WorkspaceRescheduled")
+}
+
+func TestNodeInterruptionHandler_HandleNodeEvents(t *testing.T) {
+	otherPod := newInterruptionTestPod("other-pod", "other-node")
+	otherPod.Labels = GenerateLabels("other")
+	handler, _, _ := newTestNodeInterruptionHandler(t,
+		newInterruptionTestPod("ws-pod", "spot-node"), otherPod)
+
+	requests := handler.HandleNodeEvents(context.Background(),
+		newInterruptionTestNode("spot-node", "karpenter.sh/disrupted"))
+	require.Len(t, requests, 1)
+	assert.Equal(t, client.ObjectKey{Name: "ws", Namespace: "default"}, requests[0].NamespacedName)
+
+	assert.Empty(t, handler.HandleNodeEvents(context.Background(), newInterruptionTestNode("spot-node")))
+}
+
+func TestNodeInterruptionHandler_NilIsDisabled(t *testing.T) {
+	var handler *NodeInterruptionHandler
+	assert.NoError(t, handler.Check(context.Background(), &workspacev1alpha1.Workspace{}))
+}
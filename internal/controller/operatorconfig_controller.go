@@ -0,0 +1,123 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerPkg "sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+// OperatorConfigReasonApplied is the reason of the Applied condition of OperatorConfigs in effect
+const OperatorConfigReasonApplied = "Applied"
+
+// OperatorConfigReconciler applies the OperatorConfig of the controller namespace to the settings
+// read by the controllers and webhooks, and restores the manager flags once it is deleted.
+// It runs on every replica, leader or not, since the webhooks of every replica read the settings.
+type OperatorConfigReconciler struct {
+	client.Client
+	store *operatorconfig.Store
+	// key is the namespaced name of the OperatorConfig
+	key types.NamespacedName
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=operatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the OperatorConfig to the settings and reports it in its status
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("operatorConfig", req.Name, "namespace", req.Namespace)
+
+	config := &workspacev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, r.key, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.store.Apply(nil)
+			logger.Info("OperatorConfig not found, using the manager flags")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	r.store.Apply(&config.Spec)
+	logger.Info("Applied OperatorConfig", "generation", config.Generation)
+
+	return ctrl.Result{}, r.updateStatus(ctx, config, metav1.Condition{
+		Type:    workspacev1alpha1.OperatorConfigConditionApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  OperatorConfigReasonApplied,
+		Message: "The settings are in effect",
+	})
+}
+
+// updateStatus records the generation applied and the Applied condition, when they changed
+func (r *OperatorConfigReconciler) updateStatus(
+	ctx context.Context, config *workspacev1alpha1.OperatorConfig, condition metav1.Condition) error {
+	patch := client.MergeFrom(config.DeepCopy())
+	condition.ObservedGeneration = config.Generation
+	changed := meta.SetStatusCondition(&config.Status.Conditions, condition)
+	if !changed && config.Status.ObservedGeneration == config.Generation {
+		return nil
+	}
+	config.Status.ObservedGeneration = config.Generation
+	if err := r.Status().Patch(ctx, config, patch); err != nil {
+		return fmt.Errorf("failed to update operator config status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It does not need leader election, so that every replica keeps its settings up to date.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.OperatorConfig{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == r.key.Name && obj.GetNamespace() == r.key.Namespace
+			}))).
+		Named("operatorconfig").
+		WithOptions(controllerPkg.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
+
+// LoadOperatorConfig applies the OperatorConfig name of namespace to store before the manager
+// starts, so that the controllers set up with the settings, like the watches of the workspace
+// controller, start with it.
+// The settings keep the manager flags when the OperatorConfig, or its CRD, does not exist.
+func LoadOperatorConfig(ctx context.Context, reader client.Reader, store *operatorconfig.Store, namespace, name string) error {
+	config := &workspacev1alpha1.OperatorConfig{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, config); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get operator config: %w", err)
+	}
+	store.Apply(&config.Spec)
+	return nil
+}
+
+// SetupOperatorConfigController sets up the OperatorConfig controller with the Manager, applying
+// the OperatorConfig name of namespace to store
+func SetupOperatorConfigController(mgr ctrl.Manager, store *operatorconfig.Store, namespace, name string) error {
+	reconciler := &OperatorConfigReconciler{
+		Client: mgr.GetClient(),
+		store:  store,
+		key:    types.NamespacedName{Namespace: namespace, Name: name},
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+const testOperatorConfigNamespace = "jupyter-k8s-system"
+
+var testOperatorConfigKey = types.NamespacedName{Namespace: testOperatorConfigNamespace, Name: operatorconfig.DefaultName}
+
+func newOperatorConfigTestReconciler(
+	t *testing.T, objs ...client.Object) (*OperatorConfigReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.OperatorConfig{}).
+		Build()
+
+	return &OperatorConfigReconciler{
+		Client: fakeClient,
+		store:  operatorconfig.NewStore(operatorconfig.Settings{ApplicationImagesRegistry: "docker.io/library"}),
+		key:    testOperatorConfigKey,
+	}, fakeClient
+}
+
+func newTestOperatorConfig(spec workspacev1alpha1.OperatorConfigSpec) *workspacev1alpha1.OperatorConfig {
+	return &workspacev1alpha1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: testOperatorConfigKey.Name, Namespace: testOperatorConfigKey.Namespace},
+		Spec:       spec,
+	}
+}
+
+func getTestOperatorConfigApplied(t *testing.T, c client.Client) *metav1.Condition {
+	config := &workspacev1alpha1.OperatorConfig{}
+	require.NoError(t, c.Get(context.Background(), testOperatorConfigKey, config))
+	return meta.FindStatusCondition(config.Status.Conditions, workspacev1alpha1.OperatorConfigConditionApplied)
+}
+
+func TestOperatorConfigReconcileAppliesSettings(t *testing.T) {
+	reconciler, fakeClient := newOperatorConfigTestReconciler(t, newTestOperatorConfig(workspacev1alpha1.OperatorConfigSpec{
+		ApplicationImages: &workspacev1alpha1.ApplicationImagesConfig{Registry: "registry.example.com/notebooks"},
+		RequireTemplate:   ptr.To(true),
+	}))
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: testOperatorConfigKey})
+	require.NoError(t, err)
+
+	settings := reconciler.store.Settings()
+	assert.Equal(t, "registry.example.com/notebooks", settings.ApplicationImagesRegistry)
+	assert.True(t, settings.RequireTemplate)
+	condition := getTestOperatorConfigApplied(t, fakeClient)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, OperatorConfigReasonApplied, condition.Reason)
+}
+
+func TestOperatorConfigReconcileRestoresFlagsWhenDeleted(t *testing.T) {
+	reconciler, _ := newOperatorConfigTestReconciler(t)
+	reconciler.store.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: testOperatorConfigKey})
+	require.NoError(t, err)
+
+	assert.False(t, reconciler.store.Settings().RequireTemplate)
+	assert.Equal(t, "docker.io/library", reconciler.store.Settings().ApplicationImagesRegistry)
+}
+
+func TestLoadOperatorConfig(t *testing.T) {
+	_, fakeClient := newOperatorConfigTestReconciler(t, newTestOperatorConfig(workspacev1alpha1.OperatorConfigSpec{
+		WatchResources: []workspacev1alpha1.WatchedResource{{Group: "route.openshift.io", Version: "v1", Kind: "Route"}},
+	}))
+	store := operatorconfig.NewStore(operatorconfig.Settings{})
+
+	require.NoError(t, LoadOperatorConfig(context.Background(), fakeClient, store,
+		testOperatorConfigNamespace, operatorconfig.DefaultName))
+	assert.Equal(t, []schema.GroupVersionKind{{Group: "route.openshift.io", Version: "v1", Kind: "Route"}},
+		store.Settings().ResourceWatches)
+
+	require.NoError(t, LoadOperatorConfig(context.Background(), fakeClient, store,
+		testOperatorConfigNamespace, "missing"), "a missing OperatorConfig keeps the settings")
+}
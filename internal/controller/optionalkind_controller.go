@@ -0,0 +1,299 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+// resourceWatchesRequest is the request to watch the resource kinds of the operator settings and
+// of the AccessStrategies.
+// It cannot be mistaken for a CustomResourceDefinition, whose names are never empty.
+var resourceWatchesRequest = reconcile.Request{}
+
+// splitInstalledKinds separates the kinds the API server serves from those whose
+// CustomResourceDefinition is not installed yet, e.g. Traefik, Gateway API or Istio kinds
+// when the operator starts before their CRDs are applied.
+func splitInstalledKinds(
+	mapper meta.RESTMapper,
+	gvks []schema.GroupVersionKind,
+) (installed, pending []schema.GroupVersionKind, err error) {
+	for _, gvk := range gvks {
+		if _, mappingErr := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); mappingErr != nil {
+			if !meta.IsNoMatchError(mappingErr) {
+				return nil, nil, fmt.Errorf("failed to resolve %s: %w", gvk, mappingErr)
+			}
+			pending = append(pending, gvk)
+			continue
+		}
+		installed = append(installed, gvk)
+	}
+	return installed, pending, nil
+}
+
+// OptionalKindReconciler starts the watches of the workspace controller on access resource kinds
+// whose CustomResourceDefinition was not installed when the operator started. When such a CRD
+// becomes established, it watches the kind and requeues the workspaces with an AccessStrategy,
+// so that those that failed with "no matches for kind" are reprocessed without waiting for their
+// backoff or an operator restart.
+// It also watches the resource kinds added at runtime to the watchResources of the OperatorConfig,
+// and those of the access resources of AccessStrategies, without restarting the operator.
+type OptionalKindReconciler struct {
+	client.Client
+	manager             ctrl.Manager
+	workspaceController controller.Controller
+	requeue             chan<- event.GenericEvent
+	// config holds the resource kinds of the operator settings, nil when they do not change at runtime
+	config *operatorconfig.Store
+	// accessResourceKinds holds the kinds the AccessStrategies render, nil when they are not collected
+	accessResourceKinds *AccessResourceKinds
+
+	mu      sync.Mutex
+	watched map[schema.GroupKind]schema.GroupVersionKind
+	pending map[schema.GroupKind]schema.GroupVersionKind
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+
+// NewOptionalKindReconciler creates an OptionalKindReconciler for the pending kinds, given the kinds
+// the workspace controller already watches. requeue must be watched by the workspace controller.
+func NewOptionalKindReconciler(
+	mgr ctrl.Manager,
+	workspaceController controller.Controller,
+	watched, pending []schema.GroupVersionKind,
+	requeue chan<- event.GenericEvent,
+) *OptionalKindReconciler {
+	return &OptionalKindReconciler{
+		Client:              mgr.GetClient(),
+		manager:             mgr,
+		workspaceController: workspaceController,
+		requeue:             requeue,
+		watched:             kindsByGroupKind(watched),
+		pending:             kindsByGroupKind(pending),
+	}
+}
+
+// kindsByGroupKind indexes resource kinds by their group and kind
+func kindsByGroupKind(gvks []schema.GroupVersionKind) map[schema.GroupKind]schema.GroupVersionKind {
+	byGroupKind := make(map[schema.GroupKind]schema.GroupVersionKind, len(gvks))
+	for _, gvk := range gvks {
+		byGroupKind[gvk.GroupKind()] = gvk
+	}
+	return byGroupKind
+}
+
+// Reconcile starts the watch of a pending kind once its CustomResourceDefinition is established,
+// and the watches of the resource kinds added to the operator settings and the AccessStrategies
+func (r *OptionalKindReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req == resourceWatchesRequest {
+		var gvks []schema.GroupVersionKind
+		if r.config != nil {
+			gvks = append(gvks, r.config.Settings().ResourceWatches...)
+		}
+		if r.accessResourceKinds != nil {
+			gvks = append(gvks, r.accessResourceKinds.kinds()...)
+		}
+		return ctrl.Result{}, r.WatchKinds(ctx, gvks)
+	}
+
+	logger := logf.FromContext(ctx).WithValues("customresourcedefinition", req.Name)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := r.Get(ctx, req.NamespacedName, crd); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !isCRDEstablished(crd) {
+		logger.V(1).Info("CustomResourceDefinition is not established yet")
+		return ctrl.Result{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	gvk, isPending := r.pending[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}]
+	if !isPending {
+		return ctrl.Result{}, nil
+	}
+	if !isCRDVersionServed(crd, gvk.Version) {
+		logger.Info("CustomResourceDefinition does not serve the watched version", "gvk", gvk.String())
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.watch(gvk); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Started watching access resources whose CRD was installed after startup", "gvk", gvk.String())
+
+	return ctrl.Result{}, r.requeueWorkspacesWithAccessStrategy(ctx)
+}
+
+// WatchKinds starts the watches of the workspace controller on the kinds it does not watch yet.
+// Kinds whose CustomResourceDefinition is not installed yet are watched once it is established.
+// Kinds are never unwatched: those no longer needed stay watched until the operator restarts.
+func (r *OptionalKindReconciler) WatchKinds(ctx context.Context, gvks []schema.GroupVersionKind) error {
+	logger := logf.FromContext(ctx)
+
+	r.mu.Lock()
+	started := false
+	for _, gvk := range gvks {
+		if _, isWatched := r.watched[gvk.GroupKind()]; isWatched {
+			continue
+		}
+		if _, isPending := r.pending[gvk.GroupKind()]; isPending {
+			continue
+		}
+		installed, _, err := splitInstalledKinds(r.manager.GetRESTMapper(), []schema.GroupVersionKind{gvk})
+		if err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		if len(installed) == 0 {
+			r.pending[gvk.GroupKind()] = gvk
+			logger.Info("Deferring access resource watch until its CRD is installed", "gvk", gvk.String())
+			continue
+		}
+		if err := r.watch(gvk); err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		started = true
+		logger.Info("Started watching access resources added at runtime", "gvk", gvk.String())
+	}
+	r.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	return r.requeueWorkspacesWithAccessStrategy(ctx)
+}
+
+// watch starts the watch of the workspace controller on the access resources of a kind.
+// The caller must hold the lock.
+func (r *OptionalKindReconciler) watch(gvk schema.GroupVersionKind) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	ownerHandler := handler.EnqueueRequestForOwner(
+		r.manager.GetScheme(), r.manager.GetRESTMapper(), &workspacev1alpha1.Workspace{}, handler.OnlyControllerOwner())
+	if err := r.workspaceController.Watch(source.Kind[client.Object](r.manager.GetCache(), obj, ownerHandler)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", gvk, err)
+	}
+	delete(r.pending, gvk.GroupKind())
+	r.watched[gvk.GroupKind()] = gvk
+	return nil
+}
+
+// requeueWorkspacesWithAccessStrategy sends every workspace that references an AccessStrategy to the
+// workspace controller
+func (r *OptionalKindReconciler) requeueWorkspacesWithAccessStrategy(ctx context.Context) error {
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := r.List(ctx, workspaces); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for i := range workspaces.Items {
+		if workspaces.Items[i].Spec.AccessStrategy == nil {
+			continue
+		}
+		select {
+		case r.requeue <- event.GenericEvent{Object: &workspaces.Items[i]}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// isPendingCRD returns true if the CustomResourceDefinition defines a kind that is not watched yet
+func (r *OptionalKindReconciler) isPendingCRD(obj client.Object) bool {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, isPending := r.pending[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}]
+	return isPending
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It only reconciles the CustomResourceDefinitions of pending kinds, and the resource kinds to
+// watch when the operator settings are applied or the AccessStrategies render other kinds.
+func (r *OptionalKindReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&apiextensionsv1.CustomResourceDefinition{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.isPendingCRD))).
+		Named("optionalkind")
+	if r.config != nil {
+		controllerBuilder.WatchesRawSource(resourceWatchesSource(r.config.Subscribe()))
+	}
+	if r.accessResourceKinds != nil {
+		controllerBuilder.WatchesRawSource(resourceWatchesSource(r.accessResourceKinds.changed()))
+	}
+	return controllerBuilder.Complete(r)
+}
+
+// resourceWatchesSource returns the source of the requests to watch the resource kinds to watch:
+// one when the controller starts, which covers the changes made before, and one on each change
+func resourceWatchesSource(changes <-chan struct{}) source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		queue.Add(resourceWatchesRequest)
+		go func() {
+			for {
+				select {
+				case <-changes:
+					queue.Add(resourceWatchesRequest)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return nil
+	})
+}
+
+// isCRDEstablished returns true if the API server serves the CustomResourceDefinition
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isCRDVersionServed returns true if the CustomResourceDefinition serves the version
+func isCRDVersionServed(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name == version {
+			return crdVersion.Served
+		}
+	}
+	return false
+}
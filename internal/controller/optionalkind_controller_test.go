@@ -0,0 +1,192 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+var testIngressRouteGVK = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "IngressRoute"}
+
+// stubManager provides what the OptionalKindReconciler needs from a manager
+type stubManager struct {
+	ctrl.Manager
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+func (m *stubManager) GetScheme() *runtime.Scheme     { return m.scheme }
+func (m *stubManager) GetRESTMapper() meta.RESTMapper { return m.mapper }
+func (m *stubManager) GetCache() cache.Cache          { return nil }
+func (m *stubManager) GetClient() client.Client       { return nil }
+
+// stubController records the sources it is asked to watch
+type stubController struct {
+	controller.Controller
+	watched []source.Source
+}
+
+func (c *stubController) Watch(src source.TypedSource[ctrl.Request]) error {
+	c.watched = append(c.watched, src)
+	return nil
+}
+
+func newTestCRD(established bool) *apiextensionsv1.CustomResourceDefinition {
+	status := apiextensionsv1.ConditionFalse
+	if established {
+		status = apiextensionsv1.ConditionTrue
+	}
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingressroutes.traefik.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    testIngressRouteGVK.Group,
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: testIngressRouteGVK.Kind},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: testIngressRouteGVK.Version, Served: true}},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: status},
+			},
+		},
+	}
+}
+
+func newTestOptionalKindReconciler(t *testing.T, objs ...client.Object) (*OptionalKindReconciler, *stubController, chan event.GenericEvent) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+
+	mgr := &stubManager{scheme: scheme, mapper: meta.NewDefaultRESTMapper(nil)}
+	workspaceController := &stubController{}
+	requeue := make(chan event.GenericEvent, 10)
+	reconciler := NewOptionalKindReconciler(mgr, workspaceController, nil, []schema.GroupVersionKind{testIngressRouteGVK}, requeue)
+	reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return reconciler, workspaceController, requeue
+}
+
+func TestSplitInstalledKinds(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	mapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+
+	installed, pending, err := splitInstalledKinds(mapper, []schema.GroupVersionKind{deploymentGVK, testIngressRouteGVK})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{deploymentGVK}, installed)
+	assert.Equal(t, []schema.GroupVersionKind{testIngressRouteGVK}, pending)
+}
+
+func TestOptionalKindReconcilerWaitsForEstablishedCRD(t *testing.T) {
+	crd := newTestCRD(false)
+	reconciler, workspaceController, _ := newTestOptionalKindReconciler(t, crd)
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: crd.Name}})
+	require.NoError(t, err)
+	assert.Empty(t, workspaceController.watched)
+	assert.True(t, reconciler.isPendingCRD(crd))
+}
+
+func TestOptionalKindReconcilerStartsWatchAndRequeuesWorkspaces(t *testing.T) {
+	crd := newTestCRD(true)
+	withStrategy := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-strategy", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: "traefik"},
+		},
+	}
+	withoutStrategy := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "without-strategy", Namespace: "default"},
+	}
+	reconciler, workspaceController, requeue := newTestOptionalKindReconciler(t, crd, withStrategy, withoutStrategy)
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: crd.Name}})
+	require.NoError(t, err)
+	assert.Len(t, workspaceController.watched, 1)
+	assert.False(t, reconciler.isPendingCRD(crd))
+
+	require.Len(t, requeue, 1)
+	requeued := <-requeue
+	assert.Equal(t, "with-strategy", requeued.Object.GetName())
+
+	// A second event for the same CRD does not watch the kind twice
+	_, err = reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: crd.Name}})
+	require.NoError(t, err)
+	assert.Len(t, workspaceController.watched, 1)
+}
+
+func TestOptionalKindReconcilerWatchesKindsOfOperatorConfig(t *testing.T) {
+	withStrategy := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-strategy", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: "route"},
+		},
+	}
+	reconciler, workspaceController, requeue := newTestOptionalKindReconciler(t, withStrategy)
+	routeGVK := schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+	reconciler.manager.GetRESTMapper().(*meta.DefaultRESTMapper).Add(routeGVK, meta.RESTScopeNamespace)
+	reconciler.config = operatorconfig.NewStore(operatorconfig.Settings{})
+
+	// Kinds the settings do not list are not watched
+	_, err := reconciler.Reconcile(context.Background(), resourceWatchesRequest)
+	require.NoError(t, err)
+	assert.Empty(t, workspaceController.watched)
+
+	reconciler.config.Apply(&workspacev1alpha1.OperatorConfigSpec{
+		WatchResources: []workspacev1alpha1.WatchedResource{
+			{Group: routeGVK.Group, Version: routeGVK.Version, Kind: routeGVK.Kind},
+			{Group: testIngressRouteGVK.Group, Version: testIngressRouteGVK.Version, Kind: testIngressRouteGVK.Kind},
+		},
+	})
+	_, err = reconciler.Reconcile(context.Background(), resourceWatchesRequest)
+	require.NoError(t, err)
+	assert.Len(t, workspaceController.watched, 1, "installed kinds are watched, the others stay pending")
+	assert.True(t, reconciler.isPendingCRD(newTestCRD(true)))
+	require.Len(t, requeue, 1)
+	assert.Equal(t, "with-strategy", (<-requeue).Object.GetName())
+
+	// Applying the same settings again does not watch the kinds twice
+	_, err = reconciler.Reconcile(context.Background(), resourceWatchesRequest)
+	require.NoError(t, err)
+	assert.Len(t, workspaceController.watched, 1)
+	assert.Empty(t, requeue)
+}
+
+func TestOptionalKindReconcilerWatchesKindsOfAccessStrategies(t *testing.T) {
+	reconciler, workspaceController, _ := newTestOptionalKindReconciler(t)
+	serviceGVK := schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	reconciler.manager.GetRESTMapper().(*meta.DefaultRESTMapper).Add(serviceGVK, meta.RESTScopeNamespace)
+	reconciler.watched = kindsByGroupKind([]schema.GroupVersionKind{serviceGVK})
+	reconciler.pending = kindsByGroupKind(nil)
+	reconciler.accessResourceKinds = NewAccessResourceKinds()
+	reconciler.accessResourceKinds.record(newAccessResourceKindsTestStrategy("traefik",
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "v1", Kind: "Service"},
+		workspacev1alpha1.AccessResourceTemplate{ApiVersion: "traefik.io/v1alpha1", Kind: "IngressRoute"},
+	))
+
+	_, err := reconciler.Reconcile(context.Background(), resourceWatchesRequest)
+	require.NoError(t, err)
+	assert.Empty(t, workspaceController.watched, "kinds already watched are not watched again")
+	assert.True(t, reconciler.isPendingCRD(newTestCRD(true)), "kinds not installed yet wait for their CRD")
+}
@@ -0,0 +1,28 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
+)
+
+// applyOwnerTransfer moves the workspace to the owner requested in spec.ownerTransferTo and clears
+// the request. Returns the previous owner, and false when no transfer was requested.
+func applyOwnerTransfer(workspace *workspacev1alpha1.Workspace) (string, bool) {
+	if workspace.Spec.OwnerTransferTo == "" {
+		return "", false
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = make(map[string]string)
+	}
+
+	previousOwner := workspace.Annotations[AnnotationCreatedBy]
+	// Sanitized the same way as the created-by annotation set by the webhook on create
+	workspace.Annotations[AnnotationCreatedBy] = stringutil.SanitizeUsername(workspace.Spec.OwnerTransferTo)
+	workspace.Spec.OwnerTransferTo = ""
+	return previousOwner, true
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyOwnerTransfer_RewritesCreatedBy(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationCreatedBy: "alice", AnnotationLastUpdatedBy: "alice"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{OwnerTransferTo: "bob"},
+	}
+
+	previousOwner, transferred := applyOwnerTransfer(workspace)
+
+	assert.True(t, transferred)
+	assert.Equal(t, "alice", previousOwner)
+	assert.Equal(t, "bob", workspace.Annotations[AnnotationCreatedBy])
+	assert.Equal(t, "alice", workspace.Annotations[AnnotationLastUpdatedBy])
+	assert.Empty(t, workspace.Spec.OwnerTransferTo)
+}
+
+func TestApplyOwnerTransfer_SanitizesUsername(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		Spec: workspacev1alpha1.WorkspaceSpec{OwnerTransferTo: `bob"admin`},
+	}
+
+	previousOwner, transferred := applyOwnerTransfer(workspace)
+
+	assert.True(t, transferred)
+	assert.Empty(t, previousOwner)
+	assert.Equal(t, `bob\"admin`, workspace.Annotations[AnnotationCreatedBy])
+}
+
+func TestApplyOwnerTransfer_NoRequest(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationCreatedBy: "alice"}},
+	}
+
+	_, transferred := applyOwnerTransfer(workspace)
+
+	assert.False(t, transferred)
+	assert.Equal(t, "alice", workspace.Annotations[AnnotationCreatedBy])
+}
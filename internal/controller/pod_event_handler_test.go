@@ -13,36 +13,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
-	"github.com/jupyter-infra/jupyter-k8s/internal/pluginadapters"
-	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
-// mockPodEventHandler implements pluginadapters.PodEventPluginAdapter for testing
-type mockPodEventHandler struct {
-	handlePodRunningCalled bool
-	handlePodDeletedCalled bool
-	handlePodRunningErr    error
-	handlePodDeletedErr    error
-}
-
-func (m *mockPodEventHandler) HandlePodRunning(ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string) error {
-	m.handlePodRunningCalled = true
-	return m.handlePodRunningErr
-}
-
-func (m *mockPodEventHandler) HandlePodDeleted(ctx context.Context, pod *corev1.Pod, podEventsContext map[string]string) error {
-	m.handlePodDeletedCalled = true
-	return m.handlePodDeletedErr
-}
-
-func TestNewPodEventHandler_NoPlugins(t *testing.T) {
+func TestNewPodEventHandler(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().Build()
-	mockRM := &ResourceManager{}
 
-	handler := NewPodEventHandler(fakeClient, mockRM, nil)
+	handler := NewPodEventHandler(fakeClient)
 
 	if handler == nil {
 		t.Fatal("Expected non-nil PodEventHandler")
@@ -50,388 +30,33 @@ func TestNewPodEventHandler_NoPlugins(t *testing.T) {
 	if handler.client != fakeClient {
 		t.Error("Expected client to be set correctly")
 	}
-	if handler.resourceManager != mockRM {
-		t.Error("Expected resourceManager to be set correctly")
-	}
-	if handler.podEventAdapters != nil {
-		t.Error("Expected podEventAdapters to be nil when no plugins provided")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodRunning_Success(t *testing.T) {
-	// Create workspace object
-	workspace := &workspacev1alpha1.Workspace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      testWorkspaceName,
-			Namespace: testNamespaceName,
-		},
-	}
-
-	// Create scheme and add our types
-	scheme := runtime.NewScheme()
-	_ = workspacev1alpha1.AddToScheme(scheme)
-
-	// Create fake client with workspace
-	fakeClient := fake.NewClientBuilder().
-		WithScheme(scheme).
-		WithObjects(workspace).
-		Build()
-
-	mockHandler := &mockPodEventHandler{}
-
-	// Create handler
-	handler := &PodEventHandler{
-		client:           fakeClient,
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockHandler},
-	}
-
-	// Create running workspace pod
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-			},
-		},
-		Status: corev1.PodStatus{
-			Phase: corev1.PodRunning,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result (no reconciliation triggered)")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodRunning_WorkspaceNotFound(t *testing.T) {
-	fakeClient := fake.NewClientBuilder().Build()
-
-	handler := &PodEventHandler{
-		client:          fakeClient,
-		resourceManager: &ResourceManager{},
-	}
-
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: "missing-workspace",
-			},
-		},
-		Status: corev1.PodStatus{
-			Phase: corev1.PodRunning,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result when workspace not found")
-	}
 }
 
-func TestHandleWorkspacePodEvents_PodRunning_HandlersNil(t *testing.T) {
+func TestUpdateWorkspaceDesiredStatus_Preempted(t *testing.T) {
 	workspace := &workspacev1alpha1.Workspace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testWorkspaceName,
 			Namespace: testNamespaceName,
 		},
+		Spec: workspacev1alpha1.WorkspaceSpec{DesiredStatus: DesiredStateRunning},
 	}
 
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
 
-	fakeClient := fake.NewClientBuilder().
-		WithScheme(scheme).
-		WithObjects(workspace).
-		Build()
-
-	handler := &PodEventHandler{
-		client:           fakeClient,
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: nil,
-	}
-
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-			},
-		},
-		Status: corev1.PodStatus{
-			Phase: corev1.PodRunning,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result when handlers are nil")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodDeleted_Success(t *testing.T) {
-	handler := &PodEventHandler{
-		client:           fake.NewClientBuilder().Build(),
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: &mockPodEventHandler{}},
-	}
-
-	deletionTime := metav1.Now()
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-			},
-			DeletionTimestamp: &deletionTime,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result for deleted pod")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodDeleted_HandlersNil(t *testing.T) {
-	handler := &PodEventHandler{
-		client:           fake.NewClientBuilder().Build(),
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: nil,
-	}
-
-	deletionTime := metav1.Now()
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-			},
-			DeletionTimestamp: &deletionTime,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result when handlers are nil")
-	}
-}
-
-func TestHandlePodRunning_WithPodEventsHandler(t *testing.T) {
-	tests := []struct {
-		name             string
-		podEventsHandler string
-	}{
-		{
-			name:             "AWS handler dispatches correctly",
-			podEventsHandler: "aws:ssm-remote-access",
-		},
-		{
-			name:             "Empty handler skips dispatch",
-			podEventsHandler: "",
-		},
-		{
-			name:             "Unknown handler logs error",
-			podEventsHandler: "other:unknown",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			workspace := &workspacev1alpha1.Workspace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      testWorkspaceName,
-					Namespace: testNamespaceName,
-				},
-			}
-
-			accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      testStrategyName,
-					Namespace: testNamespaceName,
-				},
-				Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
-					PodEventsHandler: tt.podEventsHandler,
-				},
-			}
-
-			scheme := runtime.NewScheme()
-			_ = workspacev1alpha1.AddToScheme(scheme)
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(scheme).
-				WithObjects(workspace, accessStrategy).
-				Build()
-
-			mockHandler := &mockPodEventHandler{}
-			handler := &PodEventHandler{
-				client:           fakeClient,
-				resourceManager:  &ResourceManager{},
-				podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockHandler},
-			}
-
-			pod := &corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test-workspace-pod",
-					Namespace: testNamespaceName,
-					Labels: map[string]string{
-						workspaceutil.LabelWorkspaceName: testWorkspaceName,
-					},
-				},
-				Status: corev1.PodStatus{
-					Phase: corev1.PodRunning,
-				},
-			}
-
-			result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-			if result != nil {
-				t.Errorf("Expected nil result but got: %v", result)
-			}
-		})
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodDeleted_WithAWSHandler(t *testing.T) {
-	mockHandler := &mockPodEventHandler{}
-
-	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "aws-access-strategy",
-			Namespace: testNamespace,
-		},
-		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
-			PodEventsHandler: "aws:ssm-remote-access",
-		},
-	}
-
-	scheme := runtime.NewScheme()
-	_ = workspacev1alpha1.AddToScheme(scheme)
-
-	handler := &PodEventHandler{
-		client: fake.NewClientBuilder().
-			WithScheme(scheme).
-			WithObjects(accessStrategy).
-			Build(),
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockHandler},
-	}
-
-	deletionTime := metav1.Now()
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-				LabelAccessStrategyName:          "aws-access-strategy",
-				LabelAccessStrategyNamespace:     testNamespace,
-			},
-			DeletionTimestamp: &deletionTime,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result for deleted pod with AWS handler")
-	}
-
-	if !mockHandler.handlePodDeletedCalled {
-		t.Error("Expected HandlePodDeleted to be called for pod with AWS handler")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodDeleted_WithNonAWSHandler(t *testing.T) {
-	mockHandler := &mockPodEventHandler{}
-
-	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "some-other-access-strategy",
-			Namespace: testNamespace,
-		},
-		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
-			PodEventsHandler: "other:handler",
-		},
-	}
-
-	scheme := runtime.NewScheme()
-	_ = workspacev1alpha1.AddToScheme(scheme)
-
-	handler := &PodEventHandler{
-		client: fake.NewClientBuilder().
-			WithScheme(scheme).
-			WithObjects(accessStrategy).
-			Build(),
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockHandler},
-	}
-
-	deletionTime := metav1.Now()
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-				LabelAccessStrategyName:          "some-other-access-strategy",
-				LabelAccessStrategyNamespace:     testNamespace,
-			},
-			DeletionTimestamp: &deletionTime,
-		},
-	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result for deleted pod with non-AWS handler")
-	}
-
-	if mockHandler.handlePodDeletedCalled {
-		t.Error("Expected HandlePodDeleted to NOT be called for pod with non-AWS handler")
-	}
-}
-
-func TestHandleWorkspacePodEvents_PodDeleted_WithoutAccessStrategyLabel(t *testing.T) {
-	mockHandler := &mockPodEventHandler{}
+	handler := NewPodEventHandler(fakeClient)
+	handler.updateWorkspaceDesiredStatus(context.Background(), testWorkspaceName, testNamespaceName, DesiredStateStopped)
 
-	handler := &PodEventHandler{
-		client:           fake.NewClientBuilder().Build(),
-		resourceManager:  &ResourceManager{},
-		podEventAdapters: map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockHandler},
-	}
-
-	deletionTime := metav1.Now()
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podNameWorkspaceSuffix,
-			Namespace: testNamespaceName,
-			Labels: map[string]string{
-				workspaceutil.LabelWorkspaceName: testWorkspaceName,
-			},
-			DeletionTimestamp: &deletionTime,
-		},
+	stored := &workspacev1alpha1.Workspace{}
+	if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), stored); err != nil {
+		t.Fatalf("Failed to get workspace: %v", err)
 	}
-
-	result := handler.HandleWorkspacePodEvents(context.Background(), pod)
-
-	if result != nil {
-		t.Error("Expected nil result for deleted pod without access strategy label")
+	if stored.Spec.DesiredStatus != DesiredStateStopped {
+		t.Errorf("Expected desiredStatus %s, got %s", DesiredStateStopped, stored.Spec.DesiredStatus)
 	}
-
-	if mockHandler.handlePodDeletedCalled {
-		t.Error("Expected HandlePodDeleted to NOT be called for pod without access strategy label")
+	if stored.Annotations[PreemptionReasonAnnotation] != PreemptedReason {
+		t.Errorf("Expected preemption annotation, got %v", stored.Annotations)
 	}
 }
 
@@ -0,0 +1,111 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// maxPodStatusMessageLength bounds the messages copied from the pod into the workspace status
+const maxPodStatusMessageLength = 1024
+
+// GetWorkspacePodStatus returns the status of the newest pod of the workspace that is not
+// terminating, or nil when the workspace has no such pod
+func (rm *ResourceManager) GetWorkspacePodStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+) (*workspacev1alpha1.WorkspacePodStatus, error) {
+	podList := &corev1.PodList{}
+	if err := rm.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return nil, fmt.Errorf("failed to list workspace pods: %w", err)
+	}
+	return resolvePodStatus(podList.Items), nil
+}
+
+// resolvePodStatus reports the newest pod that is not terminating, which is the one starting
+// during a rollout
+func resolvePodStatus(pods []corev1.Pod) *workspacev1alpha1.WorkspacePodStatus {
+	var newest *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = pod
+		}
+	}
+	if newest == nil {
+		return nil
+	}
+
+	podStatus := &workspacev1alpha1.WorkspacePodStatus{
+		Name:  newest.Name,
+		Phase: newest.Status.Phase,
+	}
+	for _, condition := range newest.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			podStatus.UnschedulableMessage = truncatePodStatusMessage(condition.Message)
+		}
+	}
+	for _, status := range newest.Status.InitContainerStatuses {
+		podStatus.Containers = append(podStatus.Containers, resolveContainerStatus(status, true))
+	}
+	for _, status := range newest.Status.ContainerStatuses {
+		podStatus.Containers = append(podStatus.Containers, resolveContainerStatus(status, false))
+	}
+	return podStatus
+}
+
+// resolveContainerStatus reports the state of a container of the workspace pod
+func resolveContainerStatus(status corev1.ContainerStatus, init bool) workspacev1alpha1.WorkspaceContainerStatus {
+	containerStatus := workspacev1alpha1.WorkspaceContainerStatus{
+		Name:         status.Name,
+		Init:         init,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+	}
+
+	switch {
+	case status.State.Waiting != nil:
+		containerStatus.State = workspacev1alpha1.WorkspaceContainerStateWaiting
+		containerStatus.Reason = status.State.Waiting.Reason
+		containerStatus.Message = truncatePodStatusMessage(status.State.Waiting.Message)
+	case status.State.Running != nil:
+		containerStatus.State = workspacev1alpha1.WorkspaceContainerStateRunning
+	case status.State.Terminated != nil:
+		containerStatus.State = workspacev1alpha1.WorkspaceContainerStateTerminated
+		containerStatus.Reason = status.State.Terminated.Reason
+		containerStatus.Message = truncatePodStatusMessage(status.State.Terminated.Message)
+	}
+
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		containerStatus.LastTerminationReason = terminated.Reason
+		containerStatus.LastTerminationExitCode = ptr.To(terminated.ExitCode)
+	}
+	return containerStatus
+}
+
+// truncatePodStatusMessage bounds a message copied from the pod, which may hold a container's
+// termination log
+func truncatePodStatusMessage(message string) string {
+	if len(message) <= maxPodStatusMessageLength {
+		return message
+	}
+	// drop the rune the cut may split
+	return strings.ToValidUTF8(message[:maxPodStatusMessageLength-3], "") + "..."
+}
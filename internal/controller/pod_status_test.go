@@ -0,0 +1,159 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPodStatusTestPod(name string, created time.Time) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            GenerateLabels("ws"),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func TestResolvePodStatus_ReportsContainerStates(t *testing.T) {
+	pod := newPodStatusTestPod("ws-pod", time.Now())
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "init",
+		Ready: true,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+	}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: ResourcePrefix,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: `Back-off pulling image "example.com/missing:latest"`,
+			}},
+		},
+		{
+			Name:         "sidecar",
+			Ready:        true,
+			RestartCount: 3,
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason:   "OOMKilled",
+				ExitCode: 137,
+			}},
+		},
+	}
+
+	podStatus := resolvePodStatus([]corev1.Pod{pod})
+
+	require.NotNil(t, podStatus)
+	assert.Equal(t, "ws-pod", podStatus.Name)
+	assert.Equal(t, corev1.PodPending, podStatus.Phase)
+	assert.Empty(t, podStatus.UnschedulableMessage)
+	assert.Equal(t, []workspacev1alpha1.WorkspaceContainerStatus{
+		{
+			Name:   "init",
+			Init:   true,
+			Ready:  true,
+			State:  workspacev1alpha1.WorkspaceContainerStateTerminated,
+			Reason: "Completed",
+		},
+		{
+			Name:    ResourcePrefix,
+			State:   workspacev1alpha1.WorkspaceContainerStateWaiting,
+			Reason:  "ImagePullBackOff",
+			Message: `Back-off pulling image "example.com/missing:latest"`,
+		},
+		{
+			Name:                    "sidecar",
+			Ready:                   true,
+			State:                   workspacev1alpha1.WorkspaceContainerStateRunning,
+			RestartCount:            3,
+			LastTerminationReason:   "OOMKilled",
+			LastTerminationExitCode: ptr.To(int32(137)),
+		},
+	}, podStatus.Containers)
+}
+
+func TestResolvePodStatus_ReportsUnschedulablePod(t *testing.T) {
+	pod := newPodStatusTestPod("ws-pod", time.Now())
+	pod.Status.Conditions = []corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+	}}
+
+	podStatus := resolvePodStatus([]corev1.Pod{pod})
+
+	require.NotNil(t, podStatus)
+	assert.Equal(t, "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.", podStatus.UnschedulableMessage)
+	assert.Empty(t, podStatus.Containers)
+}
+
+func TestResolvePodStatus_ReportsNewestPod(t *testing.T) {
+	now := time.Now()
+	old := newPodStatusTestPod("old", now.Add(-time.Hour))
+	newer := newPodStatusTestPod("newer", now)
+	terminating := newPodStatusTestPod("terminating", now.Add(time.Minute))
+	terminating.DeletionTimestamp = &metav1.Time{Time: now}
+
+	podStatus := resolvePodStatus([]corev1.Pod{old, terminating, newer})
+
+	require.NotNil(t, podStatus)
+	assert.Equal(t, "newer", podStatus.Name)
+	assert.Nil(t, resolvePodStatus([]corev1.Pod{terminating}))
+	assert.Nil(t, resolvePodStatus(nil))
+}
+
+func TestResolvePodStatus_TruncatesMessages(t *testing.T) {
+	pod := newPodStatusTestPod("ws-pod", time.Now())
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: ResourcePrefix,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:  "Error",
+			Message: strings.Repeat("é", maxPodStatusMessageLength),
+		}},
+	}}
+
+	message := resolvePodStatus([]corev1.Pod{pod}).Containers[0].Message
+
+	assert.LessOrEqual(t, len(message), maxPodStatusMessageLength)
+	assert.True(t, strings.HasSuffix(message, "..."))
+	assert.True(t, strings.HasPrefix(message, "éé"))
+}
+
+func TestGetWorkspacePodStatus_ListsWorkspacePods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+
+	pod := newPodStatusTestPod("ws-pod", time.Now())
+	otherPod := newPodStatusTestPod("other-pod", time.Now().Add(time.Minute))
+	otherPod.Labels = GenerateLabels("other")
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&pod, &otherPod).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil)
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+
+	podStatus, err := rm.GetWorkspacePodStatus(context.Background(), workspace)
+
+	require.NoError(t, err)
+	require.NotNil(t, podStatus)
+	assert.Equal(t, "ws-pod", podStatus.Name)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Progressing watchdog actions, used as the action label of the stall metric
+const (
+	stallActionRemediated = "remediated"
+	stallActionEscalated  = "escalated"
+)
+
+// progressingStalls counts the workspaces the watchdog found stuck in Progressing, by the action taken
+var progressingStalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "workspace_progressing_stalls_total",
+	Help: "Number of workspaces found stuck in Progressing beyond the progressing timeout, by watchdog action",
+}, []string{"action"})
+
+func init() {
+	metrics.Registry.MustRegister(progressingStalls)
+}
+
+// ProgressingWatchdog flags starting workspaces whose Progressing condition has not changed for longer
+// than the progressing timeout. It attempts a single remediation by deleting the pods of the workspace
+// that are not ready, so that the Deployment recreates them, and escalates with a warning event when
+// the workspace is still stuck one timeout later.
+type ProgressingWatchdog struct {
+	client   client.Client
+	recorder record.EventRecorder
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+// NewProgressingWatchdog creates a new ProgressingWatchdog. A zero or negative timeout disables it.
+func NewProgressingWatchdog(k8sClient client.Client, recorder record.EventRecorder, timeout time.Duration) *ProgressingWatchdog {
+	return &ProgressingWatchdog{
+		client:   k8sClient,
+		recorder: recorder,
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// Check runs the watchdog against a starting workspace. It only sets the Stalled condition in memory;
+// the starting status update that follows persists it.
+func (w *ProgressingWatchdog) Check(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if w == nil || w.timeout <= 0 {
+		return nil
+	}
+	now := w.now()
+
+	progressing := FindCondition(&workspace.Status.Conditions, ConditionTypeProgressing)
+	if progressing == nil || progressing.Status != metav1.ConditionTrue ||
+		now.Sub(progressing.LastTransitionTime.Time) < w.timeout {
+		// Not stuck, or progress resumed
+		removeStalledCondition(workspace)
+		return nil
+	}
+
+	stalled := FindCondition(&workspace.Status.Conditions, ConditionTypeStalled)
+	switch {
+	case stalled == nil || stalled.Status != metav1.ConditionTrue:
+		deleted, err := w.deleteUnreadyPods(ctx, workspace)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Workspace has been progressing (%s) for more than %s, deleted %d pods that were not ready",
+			progressing.Reason, w.timeout, deleted)
+		progressingStalls.WithLabelValues(stallActionRemediated).Inc()
+		w.recorder.Event(workspace, corev1.EventTypeWarning, "ProgressingStalled", message)
+		setStalledCondition(ctx, workspace, ReasonProgressingTimeoutExceeded, message)
+	case stalled.Reason == ReasonProgressingTimeoutExceeded && now.Sub(stalled.LastTransitionTime.Time) >= w.timeout:
+		message := fmt.Sprintf("Workspace is still progressing (%s) %s after its pods were deleted, manual intervention is required",
+			progressing.Reason, w.timeout)
+		progressingStalls.WithLabelValues(stallActionEscalated).Inc()
+		w.recorder.Event(workspace, corev1.EventTypeWarning, "ProgressingStallEscalated", message)
+		setStalledCondition(ctx, workspace, ReasonStallRemediationFailed, message)
+	}
+	return nil
+}
+
+// deleteUnreadyPods deletes the pods of the workspace that are not ready and not already terminating,
+// and returns how many were deleted
+func (w *ProgressingWatchdog) deleteUnreadyPods(ctx context.Context, workspace *workspacev1alpha1.Workspace) (int, error) {
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+
+	podList := &corev1.PodList{}
+	if err := w.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return 0, fmt.Errorf("failed to list workspace pods: %w", err)
+	}
+
+	deleted := 0
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || isPodReady(pod) {
+			continue
+		}
+		if err := w.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete workspace pod %s: %w", pod.Name, err)
+		}
+		logger.Info("Deleted pod of stalled workspace", "pod", pod.Name, "phase", pod.Status.Phase)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// isPodReady returns true when the Ready condition of the pod is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func setStalledCondition(ctx context.Context, workspace *workspacev1alpha1.Workspace, reason, message string) {
+	condition := NewCondition(ConditionTypeStalled, metav1.ConditionTrue, reason, message)
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+// removeStalledCondition drops the Stalled condition once the workspace is no longer stuck
+func removeStalledCondition(workspace *workspacev1alpha1.Workspace) {
+	if FindCondition(&workspace.Status.Conditions, ConditionTypeStalled) == nil {
+		return
+	}
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions)-1)
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeStalled {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
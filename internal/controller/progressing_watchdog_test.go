@@ -0,0 +1,126 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newWatchdogTestPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: GenerateLabels("ws")},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func newStartingWorkspace(progressingSince time.Time) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			Conditions: []metav1.Condition{{
+				Type:               ConditionTypeProgressing,
+				Status:             metav1.ConditionTrue,
+				Reason:             ReasonComputeNotReady,
+				LastTransitionTime: metav1.NewTime(progressingSince),
+			}},
+		},
+	}
+}
+
+func newTestProgressingWatchdog(t *testing.T, now time.Time, objects ...client.Object) (*ProgressingWatchdog, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	recorder := record.NewFakeRecorder(10)
+	watchdog := NewProgressingWatchdog(k8sClient, recorder, 10*time.Minute)
+	watchdog.now = func() time.Time { return now }
+	return watchdog, k8sClient, recorder
+}
+
+func TestProgressingWatchdog_RemediatesOnceThenEscalates(t *testing.T) {
+	now := time.Now()
+	watchdog, k8sClient, recorder := newTestProgressingWatchdog(t, now,
+		newWatchdogTestPod("wedged", false), newWatchdogTestPod("healthy", true))
+	workspace := newStartingWorkspace(now.Add(-11 * time.Minute))
+
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+
+	stalled := FindCondition(&workspace.Status.Conditions, ConditionTypeStalled)
+	require.NotNil(t, stalled)
+	assert.Equal(t, metav1.ConditionTrue, stalled.Status)
+	assert.Equal(t, ReasonProgressingTimeoutExceeded, stalled.Reason)
+	assert.Contains(t, <-recorder.Events, "ProgressingStalled")
+
+	pods := &corev1.PodList{}
+	require.NoError(t, k8sClient.List(context.Background(), pods))
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "healthy", pods.Items[0].Name)
+
+	// Still stuck, but within one timeout of the remediation
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+	assert.Empty(t, recorder.Events)
+
+	// Still stuck one timeout after the remediation
+	watchdog.now = func() time.Time { return now.Add(11 * time.Minute) }
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+	stalled = FindCondition(&workspace.Status.Conditions, ConditionTypeStalled)
+	require.NotNil(t, stalled)
+	assert.Equal(t, ReasonStallRemediationFailed, stalled.Reason)
+	assert.Contains(t, <-recorder.Events, "ProgressingStallEscalated")
+
+	// Escalates once
+	watchdog.now = func() time.Time { return now.Add(30 * time.Minute) }
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestProgressingWatchdog_ClearsStalledWhenProgressResumes(t *testing.T) {
+	now := time.Now()
+	watchdog, _, _ := newTestProgressingWatchdog(t, now)
+	workspace := newStartingWorkspace(now.Add(-time.Minute))
+	workspace.Status.Conditions = append(workspace.Status.Conditions,
+		NewCondition(ConditionTypeStalled, metav1.ConditionTrue, ReasonProgressingTimeoutExceeded, "stuck"))
+
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStalled))
+	assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeProgressing))
+}
+
+func TestProgressingWatchdog_Disabled(t *testing.T) {
+	now := time.Now()
+	watchdog, k8sClient, recorder := newTestProgressingWatchdog(t, now, newWatchdogTestPod("wedged", false))
+	watchdog.timeout = 0
+	workspace := newStartingWorkspace(now.Add(-time.Hour))
+
+	require.NoError(t, watchdog.Check(context.Background(), workspace))
+	var nilWatchdog *ProgressingWatchdog
+	require.NoError(t, nilWatchdog.Check(context.Background(), workspace))
+
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStalled))
+	assert.Empty(t, recorder.Events)
+	pods := &corev1.PodList{}
+	require.NoError(t, k8sClient.List(context.Background(), pods))
+	assert.Len(t, pods.Items, 1)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	provisioningHookContainerName = "provisioning-hook"
+	// defaultProvisioningHookTimeoutSeconds and defaultProvisioningHookBackoffLimit apply when the
+	// CRD defaults were not applied, e.g. to hooks set before the fields existed
+	defaultProvisioningHookTimeoutSeconds = 600
+	defaultProvisioningHookBackoffLimit   = 2
+)
+
+// ProvisioningJobBuilder handles creation of the Job running the storage provisioning hook of a Workspace
+type ProvisioningJobBuilder struct {
+	scheme *runtime.Scheme
+}
+
+// NewProvisioningJobBuilder creates a new ProvisioningJobBuilder
+func NewProvisioningJobBuilder(scheme *runtime.Scheme) *ProvisioningJobBuilder {
+	return &ProvisioningJobBuilder{scheme: scheme}
+}
+
+// BuildProvisioningJob creates the Job running the storage provisioning hook of the given Workspace
+// against its PVC. The hook pod is scheduled like the workspace pod, so that a volume bound on first
+// consumer is bound where the workspace pod can use it.
+func (jb *ProvisioningJobBuilder) BuildProvisioningJob(workspace *workspacev1alpha1.Workspace) (*batchv1.Job, error) {
+	storage := workspace.Spec.Storage
+	if storage == nil || storage.ProvisioningHook == nil {
+		return nil, fmt.Errorf("workspace %s has no storage provisioning hook", workspace.Name)
+	}
+	hook := storage.ProvisioningHook
+	mountPath := resolveMountPath(workspace)
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy:    corev1.RestartPolicyNever,
+		Containers:       []corev1.Container{jb.buildHookContainer(workspace, hook, mountPath)},
+		NodeSelector:     workspace.Spec.NodeSelector,
+		Affinity:         workspace.Spec.Affinity,
+		Tolerations:      workspace.Spec.Tolerations,
+		ImagePullSecrets: workspace.Spec.ImagePullSecrets,
+		SecurityContext:  workspace.Spec.PodSecurityContext,
+		Volumes: []corev1.Volume{
+			{
+				Name: volumeNameWorkspaceStorage,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: GeneratePVCName(workspace),
+					},
+				},
+			},
+		},
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateProvisioningJobName(workspace),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				LabelWorkspaceName: workspace.Name,
+				LabelComponent:     ProvisioningHookComponent,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To(ptr.Deref(hook.BackoffLimit, defaultProvisioningHookBackoffLimit)),
+			ActiveDeadlineSeconds: ptr.To(int64(ptr.Deref(hook.TimeoutSeconds, defaultProvisioningHookTimeoutSeconds))),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{LabelComponent: ProvisioningHookComponent},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+
+	// Set owner reference for garbage collection
+	if err := controllerutil.SetControllerReference(workspace, job, jb.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	return job, nil
+}
+
+// buildHookContainer creates the hook container. The variables describing the workspace come first,
+// so that the command, args and env of the hook can reference them.
+func (jb *ProvisioningJobBuilder) buildHookContainer(
+	workspace *workspacev1alpha1.Workspace,
+	hook *workspacev1alpha1.StorageProvisioningHook,
+	mountPath string) corev1.Container {
+	env := []corev1.EnvVar{
+		{Name: WorkspaceNameEnvVar, Value: workspace.Name},
+		{Name: WorkspaceNamespaceEnvVar, Value: workspace.Namespace},
+		{Name: WorkspaceOwnerEnvVar, Value: workspace.Annotations[AnnotationCreatedBy]},
+		{Name: StorageMountPathEnvVar, Value: mountPath},
+	}
+	env = append(env, hook.Env...)
+
+	securityContext := hook.SecurityContext
+	if securityContext == nil {
+		securityContext = workspace.Spec.ContainerSecurityContext
+	}
+
+	return corev1.Container{
+		Name:            provisioningHookContainerName,
+		Image:           hook.Image,
+		Command:         hook.Command,
+		Args:            hook.Args,
+		Env:             env,
+		SecurityContext: securityContext,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: volumeNameWorkspaceStorage, MountPath: mountPath},
+		},
+	}
+}
+
+// provisioningJobFinished returns whether the Job completed or failed, with the message of its
+// final condition
+func provisioningJobFinished(job *batchv1.Job) (finished bool, succeeded bool, message string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, true, condition.Message
+		case batchv1.JobFailed:
+			return true, false, condition.Message
+		}
+	}
+	return false, false, ""
+}
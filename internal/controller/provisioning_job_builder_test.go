@@ -0,0 +1,209 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newProvisioningHookTestWorkspace(hook *workspacev1alpha1.StorageProvisioningHook) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Namespace:   "team-a",
+			UID:         types.UID("uid-ws"),
+			Annotations: map[string]string{AnnotationCreatedBy: "alice"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{
+				Size:             resource.MustParse("10Gi"),
+				ProvisioningHook: hook,
+			},
+			ContainerSecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)},
+			NodeSelector:             map[string]string{"pool": "notebooks"},
+		},
+	}
+}
+
+func TestBuildProvisioningJob(t *testing.T) {
+	builder := NewProvisioningJobBuilder(newNetworkPolicyTestScheme(t))
+	workspace := newProvisioningHookTestWorkspace(&workspacev1alpha1.StorageProvisioningHook{
+		Image: "skel:1",
+		Args:  []string{"--home", "$(STORAGE_MOUNT_PATH)"},
+		Env:   []corev1.EnvVar{{Name: "SKEL", Value: "/etc/skel"}},
+	})
+
+	job, err := builder.BuildProvisioningJob(workspace)
+
+	require.NoError(t, err)
+	assert.Equal(t, GenerateProvisioningJobName(workspace), job.Name)
+	assert.Equal(t, "team-a", job.Namespace)
+	assert.True(t, metav1.IsControlledBy(job, workspace))
+	assert.Equal(t, int32(defaultProvisioningHookBackoffLimit), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(defaultProvisioningHookTimeoutSeconds), *job.Spec.ActiveDeadlineSeconds)
+
+	// The hook pod must never be selected as the workspace pod
+	podLabels := job.Spec.Template.Labels
+	assert.NotContains(t, podLabels, AppLabel)
+	assert.NotContains(t, podLabels, LabelWorkspaceName)
+	assert.Equal(t, ProvisioningHookComponent, podLabels[LabelComponent])
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, workspace.Spec.NodeSelector, podSpec.NodeSelector)
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, GeneratePVCName(workspace), podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	require.Len(t, podSpec.Containers, 1)
+	container := podSpec.Containers[0]
+	assert.Equal(t, "skel:1", container.Image)
+	assert.Equal(t, []string{"--home", "$(STORAGE_MOUNT_PATH)"}, container.Args)
+	assert.Equal(t, workspace.Spec.ContainerSecurityContext, container.SecurityContext)
+	assert.Equal(t, []corev1.VolumeMount{{Name: volumeNameWorkspaceStorage, MountPath: DefaultMountPath}}, container.VolumeMounts)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: WorkspaceNameEnvVar, Value: "ws"},
+		{Name: WorkspaceNamespaceEnvVar, Value: "team-a"},
+		{Name: WorkspaceOwnerEnvVar, Value: "alice"},
+		{Name: StorageMountPathEnvVar, Value: DefaultMountPath},
+		{Name: "SKEL", Value: "/etc/skel"},
+	}, container.Env)
+}
+
+func TestBuildProvisioningJob_HookSettingsOverrideDefaults(t *testing.T) {
+	builder := NewProvisioningJobBuilder(newNetworkPolicyTestScheme(t))
+	hookContext := &corev1.SecurityContext{RunAsUser: ptr.To(int64(0))}
+	workspace := newProvisioningHookTestWorkspace(&workspacev1alpha1.StorageProvisioningHook{
+		Image:           "quota:1",
+		SecurityContext: hookContext,
+		TimeoutSeconds:  ptr.To(int32(60)),
+		BackoffLimit:    ptr.To(int32(0)),
+	})
+	workspace.Spec.Storage.MountPath = "/home/user"
+
+	job, err := builder.BuildProvisioningJob(workspace)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(60), *job.Spec.ActiveDeadlineSeconds)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, hookContext, container.SecurityContext)
+	assert.Equal(t, "/home/user", container.VolumeMounts[0].MountPath)
+}
+
+func TestBuildPVC_MarksProvisioningHookPending(t *testing.T) {
+	builder := NewPVCBuilder(newNetworkPolicyTestScheme(t))
+
+	pvc, err := builder.BuildPVC(newProvisioningHookTestWorkspace(&workspacev1alpha1.StorageProvisioningHook{Image: "skel:1"}))
+	require.NoError(t, err)
+	assert.Equal(t, ProvisioningHookPending, pvc.Annotations[AnnotationProvisioningHook])
+
+	pvc, err = builder.BuildPVC(newProvisioningHookTestWorkspace(nil))
+	require.NoError(t, err)
+	assert.NotContains(t, pvc.Annotations, AnnotationProvisioningHook)
+}
+
+func TestEnsureStorageProvisioned(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	workspace := newProvisioningHookTestWorkspace(&workspacev1alpha1.StorageProvisioningHook{Image: "skel:1"})
+	pvc, err := NewPVCBuilder(scheme).BuildPVC(workspace)
+	require.NoError(t, err)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, pvc).
+		WithStatusSubresource(&batchv1.Job{}).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+	jobKey := types.NamespacedName{Name: GenerateProvisioningJobName(workspace), Namespace: workspace.Namespace}
+
+	// Creates the Job and waits for it
+	result, err := rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningRunning, result.Status)
+	job := &batchv1.Job{}
+	require.NoError(t, fakeClient.Get(ctx, jobKey, job))
+
+	result, err = rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningRunning, result.Status)
+
+	// Reports a failed Job until it is deleted
+	job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+	}
+	require.NoError(t, fakeClient.Status().Update(ctx, job))
+	result, err = rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningFailed, result.Status)
+	assert.Contains(t, result.Message, "BackoffLimitExceeded")
+
+	// Records a completed Job on the PVC and deletes it
+	require.NoError(t, fakeClient.Get(ctx, jobKey, job))
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, fakeClient.Status().Update(ctx, job))
+	result, err = rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningSucceeded, result.Status)
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, jobKey, job)))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc))
+	assert.Equal(t, ProvisioningHookSucceeded, pvc.Annotations[AnnotationProvisioningHook])
+
+	// Never runs the hook again on the volume
+	result, err = rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningSucceeded, result.Status)
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, jobKey, job)))
+}
+
+func TestEnsureStorageProvisioned_SkipsVolumesWithoutPendingHook(t *testing.T) {
+	ctx := context.Background()
+	scheme := newNetworkPolicyTestScheme(t)
+	// e.g. a volume created before the template added the hook
+	workspace := newProvisioningHookTestWorkspace(&workspacev1alpha1.StorageProvisioningHook{Image: "skel:1"})
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: GeneratePVCName(workspace), Namespace: workspace.Namespace},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, pvc).Build()
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+	result, err := rm.EnsureStorageProvisioned(ctx, workspace, pvc)
+	require.NoError(t, err)
+	assert.Equal(t, StorageProvisioningNotRequired, result.Status)
+	jobs := &batchv1.JobList{}
+	require.NoError(t, fakeClient.List(ctx, jobs))
+	assert.Empty(t, jobs.Items)
+}
+
+func TestSetStorageProvisionedCondition(t *testing.T) {
+	ctx := context.Background()
+	workspace := newProvisioningHookTestWorkspace(nil)
+
+	setStorageProvisionedCondition(ctx, workspace, StorageProvisioningResult{Status: StorageProvisioningRunning})
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStorageProvisioned)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonProvisioningHookRunning, condition.Reason)
+
+	setStorageProvisionedCondition(ctx, workspace, StorageProvisioningResult{Status: StorageProvisioningSucceeded})
+	condition = FindCondition(&workspace.Status.Conditions, ConditionTypeStorageProvisioned)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+	setStorageProvisionedCondition(ctx, workspace, StorageProvisioningResult{Status: StorageProvisioningNotRequired})
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStorageProvisioned))
+}
@@ -89,6 +89,7 @@ func (pb *PVCBuilder) BuildPVC(workspace *workspacev1alpha1.Workspace) (*corev1.
 		ObjectMeta: pb.buildObjectMeta(workspace),
 		Spec:       pb.buildPVCSpecWithSize(storageConfig.Size, storageConfig.StorageClassName),
 	}
+	pvc.Spec.DataSource = buildSnapshotDataSource(workspace)
 
 	// Set owner reference for garbage collection
 	if err := controllerutil.SetControllerReference(workspace, pvc, pb.scheme); err != nil {
@@ -100,11 +101,16 @@ func (pb *PVCBuilder) BuildPVC(workspace *workspacev1alpha1.Workspace) (*corev1.
 
 // buildObjectMeta creates the metadata for the PVC
 func (pb *PVCBuilder) buildObjectMeta(workspace *workspacev1alpha1.Workspace) metav1.ObjectMeta {
-	return metav1.ObjectMeta{
-		Name:      GeneratePVCName(workspace.Name),
+	meta := metav1.ObjectMeta{
+		Name:      GeneratePVCName(workspace),
 		Namespace: workspace.Namespace,
 		Labels:    GenerateLabels(workspace.Name),
 	}
+	// A new volume waits for the provisioning hook before the workspace pod mounts it
+	if workspace.Spec.Storage.ProvisioningHook != nil {
+		meta.Annotations = map[string]string{AnnotationProvisioningHook: ProvisioningHookPending}
+	}
+	return meta
 }
 
 // buildPVCSpecWithSize creates the PVC specification with the given size and storage class
@@ -127,6 +133,32 @@ func (pb *PVCBuilder) buildPVCSpecWithSize(size resource.Quantity, storageClassN
 	return spec
 }
 
+// buildSnapshotDataSource returns the VolumeSnapshot data source for a workspace restored from a
+// WorkspaceSnapshot, or nil. The data source is only honored by Kubernetes when the PVC is created;
+// it is immutable afterwards, so NeedsUpdate and UpdatePVCSpec ignore it.
+func buildSnapshotDataSource(workspace *workspacev1alpha1.Workspace) *corev1.TypedLocalObjectReference {
+	if workspace.Spec.Storage == nil || workspace.Spec.Storage.RestoreFromSnapshot == nil {
+		return nil
+	}
+
+	apiGroup := volumeSnapshotAPIGroup
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     kindVolumeSnapshot,
+		Name:     GenerateVolumeSnapshotName(workspace.Spec.Storage.RestoreFromSnapshot.Name),
+	}
+}
+
+// buildCloneDataSource returns the data source cloning the PVC of the source workspace of a
+// workspace cloned with includeStorage. Kubernetes only clones PVCs within a namespace, which the
+// webhook enforces; like the snapshot data source, it only applies on creation.
+func buildCloneDataSource(source *workspacev1alpha1.Workspace) *corev1.TypedLocalObjectReference {
+	return &corev1.TypedLocalObjectReference{
+		Kind: "PersistentVolumeClaim",
+		Name: GeneratePVCName(source),
+	}
+}
+
 // NeedsUpdate checks if the existing PVC needs to be updated based on workspace changes
 func (pb *PVCBuilder) NeedsUpdate(ctx context.Context, existingPVC *corev1.PersistentVolumeClaim, workspace *workspacev1alpha1.Workspace) (bool, error) {
 	// Build the desired PVC spec
@@ -181,3 +213,53 @@ func (pb *PVCBuilder) UpdatePVCSpec(ctx context.Context, existingPVC *corev1.Per
 
 	return nil
 }
+
+// UpdateStorageExpansionStatus refreshes workspace.Status.StorageExpansion from the PVC's
+// reported capacity and resize conditions. It is a no-op when no expansion is being tracked
+// or the tracked expansion has completed.
+func (pb *PVCBuilder) UpdateStorageExpansionStatus(workspace *workspacev1alpha1.Workspace, pvc *corev1.PersistentVolumeClaim) {
+	expansion := workspace.Status.StorageExpansion
+	if expansion == nil || expansion.Phase == workspacev1alpha1.StorageExpansionCompleted {
+		return
+	}
+
+	if expansion.Phase == workspacev1alpha1.StorageExpansionUnsupported {
+		// Clear the rejected expansion once the spec no longer asks for more than the PVC has
+		desiredSize := resolveStorageSize(workspace)
+		if desiredSize.Cmp(pvc.Spec.Resources.Requests[corev1.ResourceStorage]) <= 0 {
+			workspace.Status.StorageExpansion = nil
+		}
+		return
+	}
+
+	updated := expansion.DeepCopy()
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		updated.CurrentCapacity = &capacity
+	}
+	updated.Phase, updated.Message = resolveStorageExpansionPhase(pvc, expansion.RequestedSize)
+
+	if !reflect.DeepEqual(updated, expansion) {
+		workspace.Status.StorageExpansion = updated
+	}
+}
+
+// resolveStorageExpansionPhase derives the expansion phase from the PVC status
+func resolveStorageExpansionPhase(pvc *corev1.PersistentVolumeClaim, requestedSize resource.Quantity) (workspacev1alpha1.StorageExpansionPhase, string) {
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(requestedSize) >= 0 {
+		return workspacev1alpha1.StorageExpansionCompleted, ""
+	}
+
+	switch pvc.Status.AllocatedResourceStatuses[corev1.ResourceStorage] {
+	case corev1.PersistentVolumeClaimControllerResizeInfeasible, corev1.PersistentVolumeClaimNodeResizeInfeasible:
+		return workspacev1alpha1.StorageExpansionFailed,
+			fmt.Sprintf("Storage provider cannot resize the volume to %s", requestedSize.String())
+	}
+
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+			return workspacev1alpha1.StorageExpansionFileSystemResizePending, condition.Message
+		}
+	}
+
+	return workspacev1alpha1.StorageExpansionInProgress, fmt.Sprintf("Resizing volume to %s", requestedSize.String())
+}
@@ -155,7 +155,7 @@ func TestPVCBuilder_Metadata(t *testing.T) {
 		return
 	}
 
-	expectedName := GeneratePVCName(testWorkspaceName)
+	expectedName := GeneratePVCName(workspace)
 	if pvc.Name != expectedName {
 		t.Errorf("Expected PVC name %s, got %s", expectedName, pvc.Name)
 	}
@@ -228,3 +228,164 @@ func TestPVCBuilder_UpdateDetection(t *testing.T) {
 		t.Error("Expected update needed")
 	}
 }
+
+func TestPVCBuilder_RestoreFromSnapshot(t *testing.T) {
+	builder := setupPVCBuilder()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{
+				Size:                resource.MustParse("5Gi"),
+				RestoreFromSnapshot: &workspacev1alpha1.SnapshotRef{Name: "nightly"},
+			},
+		},
+	}
+
+	pvc, err := builder.BuildPVC(workspace)
+	if err != nil {
+		t.Fatalf("BuildPVC failed: %v", err)
+	}
+	if pvc == nil || pvc.Spec.DataSource == nil {
+		t.Fatal("Expected PVC with data source")
+		return
+	}
+	if pvc.Spec.DataSource.Kind != "VolumeSnapshot" {
+		t.Errorf("Expected data source kind VolumeSnapshot, got %s", pvc.Spec.DataSource.Kind)
+	}
+	if pvc.Spec.DataSource.APIGroup == nil || *pvc.Spec.DataSource.APIGroup != "snapshot.storage.k8s.io" {
+		t.Errorf("Expected data source API group snapshot.storage.k8s.io, got %v", pvc.Spec.DataSource.APIGroup)
+	}
+	if pvc.Spec.DataSource.Name != GenerateVolumeSnapshotName("nightly") {
+		t.Errorf("Expected data source name %s, got %s", GenerateVolumeSnapshotName("nightly"), pvc.Spec.DataSource.Name)
+	}
+
+	// The data source is create-only and must not trigger PVC updates
+	existingPVC := pvc.DeepCopy()
+	existingPVC.Spec.DataSource = nil
+	needsUpdate, err := builder.NeedsUpdate(context.Background(), existingPVC, workspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needsUpdate {
+		t.Error("Expected no update needed for data source difference")
+	}
+}
+
+func TestPVCBuilder_NoSnapshotDataSource(t *testing.T) {
+	builder := setupPVCBuilder()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("5Gi")},
+		},
+	}
+
+	pvc, err := builder.BuildPVC(workspace)
+	if err != nil {
+		t.Fatalf("BuildPVC failed: %v", err)
+	}
+	if pvc.Spec.DataSource != nil {
+		t.Errorf("Expected no data source, got %v", pvc.Spec.DataSource)
+	}
+}
+
+func TestPVCBuilder_UpdateStorageExpansionStatus(t *testing.T) {
+	builder := setupPVCBuilder()
+
+	tests := []struct {
+		name          string
+		pvcStatus     corev1.PersistentVolumeClaimStatus
+		expectedPhase workspacev1alpha1.StorageExpansionPhase
+	}{
+		{
+			name: "in progress while capacity is below the requested size",
+			pvcStatus: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+			expectedPhase: workspacev1alpha1.StorageExpansionInProgress,
+		},
+		{
+			name: "file system resize pending on the node",
+			pvcStatus: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				Conditions: []corev1.PersistentVolumeClaimCondition{
+					{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+				},
+			},
+			expectedPhase: workspacev1alpha1.StorageExpansionFileSystemResizePending,
+		},
+		{
+			name: "failed when the resize is infeasible",
+			pvcStatus: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				AllocatedResourceStatuses: map[corev1.ResourceName]corev1.ClaimResourceStatus{
+					corev1.ResourceStorage: corev1.PersistentVolumeClaimControllerResizeInfeasible,
+				},
+			},
+			expectedPhase: workspacev1alpha1.StorageExpansionFailed,
+		},
+		{
+			name: "completed once capacity reaches the requested size",
+			pvcStatus: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+			expectedPhase: workspacev1alpha1.StorageExpansionCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("20Gi")},
+				},
+				Status: workspacev1alpha1.WorkspaceStatus{
+					StorageExpansion: &workspacev1alpha1.StorageExpansionStatus{
+						Phase:         workspacev1alpha1.StorageExpansionInProgress,
+						RequestedSize: resource.MustParse("20Gi"),
+					},
+				},
+			}
+			pvc := &corev1.PersistentVolumeClaim{Status: tt.pvcStatus}
+
+			builder.UpdateStorageExpansionStatus(workspace, pvc)
+
+			if workspace.Status.StorageExpansion.Phase != tt.expectedPhase {
+				t.Errorf("Expected phase %s, got %s", tt.expectedPhase, workspace.Status.StorageExpansion.Phase)
+			}
+			if workspace.Status.StorageExpansion.CurrentCapacity == nil {
+				t.Error("Expected current capacity to be set")
+			}
+		})
+	}
+}
+
+func TestPVCBuilder_UpdateStorageExpansionStatus_ClearsUnsupported(t *testing.T) {
+	builder := setupPVCBuilder()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			StorageExpansion: &workspacev1alpha1.StorageExpansionStatus{
+				Phase:         workspacev1alpha1.StorageExpansionUnsupported,
+				RequestedSize: resource.MustParse("20Gi"),
+			},
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+
+	builder.UpdateStorageExpansionStatus(workspace, pvc)
+
+	if workspace.Status.StorageExpansion != nil {
+		t.Errorf("Expected unsupported expansion to be cleared, got %v", workspace.Status.StorageExpansion)
+	}
+}
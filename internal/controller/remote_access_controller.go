@@ -0,0 +1,333 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	builderPkg "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerPkg "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/jupyter-infra/jupyter-k8s-plugin/plugin"
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/awsadapter"
+	"github.com/jupyter-infra/jupyter-k8s/internal/pluginadapters"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Remote access retries: plugin calls reach cloud APIs (e.g. SSM activations), so failed
+// setups and cleanups back off more slowly than workspace reconciliations
+const (
+	RemoteAccessRetryBaseDelay = 1 * time.Second
+	RemoteAccessRetryMaxDelay  = 5 * time.Minute
+	// remoteAccessPendingRequeueDelay is the delay before checking again on a pending setup
+	remoteAccessPendingRequeueDelay = 10 * time.Second
+)
+
+// RemoteAccessReconciler sets up and releases the remote access of workspace pods through the
+// podEventsHandler of their AccessStrategy, e.g. the SSM hybrid activation of the pod.
+// It runs apart from the workspace controller so that transient plugin errors are retried with
+// backoff without blocking workspace reconciliation. Pods whose remote access is set up carry
+// the RemoteAccessFinalizerName finalizer until their remote access resources are released.
+type RemoteAccessReconciler struct {
+	client.Client
+	resourceManager *ResourceManager
+	// podEventAdapters maps plugin names (e.g. "aws") to their pod event adapter implementation.
+	podEventAdapters map[string]pluginadapters.PodEventPluginAdapter
+	// remoteAccessCleaners maps plugin names to the cleaner releasing the remote access
+	// resources of deleted pods.
+	remoteAccessCleaners map[string]pluginadapters.RemoteAccessCleaner
+	options              WorkspaceControllerOptions
+}
+
+// NewRemoteAccessReconciler creates a new RemoteAccessReconciler.
+// pluginClients maps plugin names to their remote access client implementations.
+// An empty or nil map disables all plugin-based remote access features.
+// When options.RemoteAccessCleanupDryRun is true, the remote access resources of deleted pods are only logged.
+func NewRemoteAccessReconciler(
+	k8sClient client.Client,
+	resourceManager *ResourceManager,
+	pluginClients map[string]plugin.RemoteAccessPluginApis,
+	options WorkspaceControllerOptions,
+) *RemoteAccessReconciler {
+	reconciler := &RemoteAccessReconciler{
+		Client:          k8sClient,
+		resourceManager: resourceManager,
+		options:         options,
+	}
+
+	if len(pluginClients) == 0 {
+		logf.Log.Info("No plugin clients provided - remote access features will be disabled")
+		return reconciler
+	}
+
+	// Create PodExecUtil (shared across all adapters)
+	podExecUtil, err := NewPodExecUtil()
+	if err != nil {
+		logf.Log.Error(err, "Failed to initialize PodExecUtil - remote access features will be disabled")
+		return reconciler
+	}
+
+	podEventAdapters := map[string]pluginadapters.PodEventPluginAdapter{}
+	remoteAccessCleaners := map[string]pluginadapters.RemoteAccessCleaner{}
+	for name, pluginClient := range pluginClients {
+		switch name {
+		case pluginNameAWS:
+			adapter, err := awsadapter.NewAwsSsmPodEventAdapter(pluginClient, podExecUtil)
+			if err != nil {
+				logf.Log.Error(err, "Failed to initialize AWS SSM pod event adapter", "plugin", name)
+				continue
+			}
+			podEventAdapters[name] = adapter
+			remoteAccessCleaners[name] = adapter
+		default:
+			logf.Log.Info("No pod event adapter mapped for plugin - skipping", "plugin", name)
+		}
+	}
+
+	if options.RemoteAccessCleanupDryRun {
+		logf.Log.Info("Remote access cleanup dry run enabled - remote access resources of deleted pods will not be released")
+		for name := range remoteAccessCleaners {
+			remoteAccessCleaners[name] = pluginadapters.NoopRemoteAccessCleaner{}
+		}
+	}
+
+	reconciler.podEventAdapters = podEventAdapters
+	reconciler.remoteAccessCleaners = remoteAccessCleaners
+	return reconciler
+}
+
+// Reconcile sets up the remote access of running workspace pods, and releases it once they are deleted
+func (r *RemoteAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("pod", req.Name, "namespace", req.Namespace)
+
+	// The replica of the shard of the workspace handles its pods
+	if !r.options.Sharding.OwnsNamespace(req.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("Pod not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !pod.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcilePodDeletion(ctx, pod)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return ctrl.Result{}, nil
+	}
+	return r.reconcilePodRunning(ctx, pod)
+}
+
+// reconcilePodRunning sets up the remote access of a running workspace pod with the
+// podEventsHandler of its AccessStrategy, and reports it in the workspace status
+func (r *RemoteAccessReconciler) reconcilePodRunning(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
+	workspaceName := pod.Labels[workspaceutil.LabelWorkspaceName]
+	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "workspace", workspaceName)
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: workspaceName, Namespace: pod.Namespace}, workspace); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("Workspace already deleted, skipping remote access setup - this is expected during workspace cleanup")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	accessStrategy, err := r.resourceManager.GetAccessStrategyForWorkspace(ctx, workspace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if accessStrategy == nil || accessStrategy.Spec.PodEventsHandler == "" {
+		return ctrl.Result{}, nil
+	}
+
+	// Plugin misconfigurations are not retried: they are fixed by updating the AccessStrategy
+	// or the controller flags, which trigger new reconciliations
+	handlerRef := accessStrategy.Spec.PodEventsHandler
+	pluginName, _ := plugin.ParseHandlerRef(handlerRef)
+	adapter, ok := r.podEventAdapters[pluginName]
+	if !ok || adapter == nil {
+		logger.Error(nil, "Pod event adapter not available - cannot setup containers", "plugin", pluginName)
+		return ctrl.Result{}, nil
+	}
+	resolvedCtx, err := pluginadapters.ResolvePodContext(accessStrategy.Spec.PodEventsContext, pod)
+	if err != nil {
+		logger.Error(err, "Failed to resolve pod events context", "plugin", pluginName)
+		return ctrl.Result{}, nil
+	}
+
+	// Without the annotation and the finalizer the remote access resources would leak on deletion
+	if err := r.markRemoteAccessHandler(ctx, pod, handlerRef); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to mark pod as managed by the pod events handler: %w", err)
+	}
+
+	info, err := adapter.HandlePodRunning(ctx, pod, workspaceName, pod.Namespace, resolvedCtx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to setup remote access with plugin %s: %w", pluginName, err)
+	}
+	if info == nil {
+		logger.V(1).Info("Remote access setup pending", "plugin", pluginName)
+		return ctrl.Result{RequeueAfter: remoteAccessPendingRequeueDelay}, nil
+	}
+
+	return ctrl.Result{}, r.updateRemoteAccessStatus(ctx, workspace, &workspacev1alpha1.RemoteAccessStatus{
+		PodUID:        string(pod.UID),
+		SSMInstanceID: info.InstanceID,
+	})
+}
+
+// markRemoteAccessHandler annotates the pod with the pod events handler setting up its remote access,
+// and adds the finalizer holding the pod until the remote access resources are cleaned up
+func (r *RemoteAccessReconciler) markRemoteAccessHandler(ctx context.Context, pod *corev1.Pod, handlerRef string) error {
+	if pod.Annotations[AnnotationRemoteAccessHandler] == handlerRef &&
+		controllerutil.ContainsFinalizer(pod, RemoteAccessFinalizerName) {
+		return nil
+	}
+
+	annotated := pod.DeepCopy()
+	if annotated.Annotations == nil {
+		annotated.Annotations = make(map[string]string)
+	}
+	annotated.Annotations[AnnotationRemoteAccessHandler] = handlerRef
+	controllerutil.AddFinalizer(annotated, RemoteAccessFinalizerName)
+	return r.Patch(ctx, annotated, client.MergeFrom(pod))
+}
+
+// reconcilePodDeletion releases the remote access resources of a deleted pod, then its finalizer.
+// Cleanup errors are returned so that the cleanup is retried with backoff.
+func (r *RemoteAccessReconciler) reconcilePodDeletion(ctx context.Context, pod *corev1.Pod) error {
+	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", pod.Namespace, "podUID", pod.UID)
+
+	// Only pods whose remote access was set up by a pod events handler hold resources to clean up
+	if !controllerutil.ContainsFinalizer(pod, RemoteAccessFinalizerName) {
+		logger.V(1).Info("Pod has no remote access finalizer, skipping resource cleanup")
+		return nil
+	}
+
+	pluginName, _ := plugin.ParseHandlerRef(pod.Annotations[AnnotationRemoteAccessHandler])
+	cleaner, ok := r.remoteAccessCleaners[pluginName]
+	if !ok || cleaner == nil {
+		// Retrying cannot help: release the pod rather than blocking its deletion
+		logger.Error(nil, "Remote access cleaner not available - releasing pod without cleaning up remote access resources",
+			"plugin", pluginName)
+	} else if err := cleaner.CleanupRemoteAccess(ctx, pod, r.resolveDeletedPodEventsContext(ctx, pod)); err != nil {
+		return fmt.Errorf("failed to cleanup remote access resources with plugin %s: %w", pluginName, err)
+	}
+
+	if err := r.clearRemoteAccessStatus(ctx, pod); err != nil {
+		return err
+	}
+
+	released := pod.DeepCopy()
+	controllerutil.RemoveFinalizer(released, RemoteAccessFinalizerName)
+	if err := r.Patch(ctx, released, client.MergeFrom(pod)); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove remote access finalizer: %w", err)
+	}
+	logger.Info("Released remote access resources of deleted pod", "plugin", pluginName)
+	return nil
+}
+
+// resolveDeletedPodEventsContext resolves the pod events context of the access strategy of a deleted pod.
+// Returns nil when the access strategy is unknown or no longer exists, so that the remote access
+// resources are still cleaned up.
+// AccessStrategy labels are set by workspace reconciler and propagated: Workspace.labels -> Deployment.labels -> Pod.labels
+func (r *RemoteAccessReconciler) resolveDeletedPodEventsContext(ctx context.Context, pod *corev1.Pod) map[string]string {
+	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", pod.Namespace)
+
+	accessStrategyName := pod.Labels[LabelAccessStrategyName]
+	accessStrategyNamespace := pod.Labels[LabelAccessStrategyNamespace]
+	if accessStrategyName == "" || accessStrategyNamespace == "" {
+		logger.V(1).Info("Pod has no access strategy labels, cleaning up without pod events context")
+		return nil
+	}
+
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      accessStrategyName,
+		Namespace: accessStrategyNamespace,
+	}, accessStrategy); err != nil {
+		logger.Info("Failed to get access strategy, cleaning up without pod events context",
+			"accessStrategy", accessStrategyName, "error", err.Error())
+		return nil
+	}
+
+	resolvedCtx, err := pluginadapters.ResolvePodContext(accessStrategy.Spec.PodEventsContext, pod)
+	if err != nil {
+		logger.Error(err, "Failed to resolve pod events context, cleaning up without pod events context")
+		return nil
+	}
+	return resolvedCtx
+}
+
+// updateRemoteAccessStatus reports the remote access of the workspace pod in status.remoteAccess
+func (r *RemoteAccessReconciler) updateRemoteAccessStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	status *workspacev1alpha1.RemoteAccessStatus,
+) error {
+	if equality.Semantic.DeepEqual(workspace.Status.RemoteAccess, status) {
+		return nil
+	}
+
+	patch := client.MergeFrom(workspace.DeepCopy())
+	workspace.Status.RemoteAccess = status
+	if err := r.Status().Patch(ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to update workspace remote access status: %w", err)
+	}
+	return nil
+}
+
+// clearRemoteAccessStatus clears status.remoteAccess of the workspace of a deleted pod,
+// unless it already reports the remote access of another pod
+func (r *RemoteAccessReconciler) clearRemoteAccessStatus(ctx context.Context, pod *corev1.Pod) error {
+	workspace := &workspacev1alpha1.Workspace{}
+	err := r.Get(ctx, client.ObjectKey{Name: pod.Labels[workspaceutil.LabelWorkspaceName], Namespace: pod.Namespace}, workspace)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if workspace.Status.RemoteAccess == nil || workspace.Status.RemoteAccess.PodUID != string(pod.UID) {
+		return nil
+	}
+	return r.updateRemoteAccessStatus(ctx, workspace, nil)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It only watches pods with workspace labels.
+func (r *RemoteAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builderPkg.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			_, hasWorkspace := obj.GetLabels()[workspaceutil.LabelWorkspaceName]
+			return hasWorkspace
+		}))).
+		Named("remoteaccess").
+		WithOptions(controllerPkg.Options{
+			MaxConcurrentReconciles: r.options.MaxConcurrentReconciles,
+			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
+				RemoteAccessRetryBaseDelay, RemoteAccessRetryMaxDelay),
+		}).
+		Complete(r)
+}
@@ -0,0 +1,325 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/pluginadapters"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	testRemoteAccessHandler = "aws:ssm-remote-access"
+	testRemoteAccessPodUID  = "pod-uid"
+	testSSMInstanceID       = "mi-0123456789abcdef0"
+)
+
+// mockRemoteAccessPlugin implements pluginadapters.PodEventPluginAdapter and
+// pluginadapters.RemoteAccessCleaner for testing
+type mockRemoteAccessPlugin struct {
+	handlePodRunningCalled    bool
+	cleanupRemoteAccessCalled bool
+	remoteAccessInfo          *pluginadapters.RemoteAccessInfo
+	handlePodRunningErr       error
+	cleanupRemoteAccessErr    error
+	cleanupPodEventsContext   map[string]string
+}
+
+func (m *mockRemoteAccessPlugin) HandlePodRunning(
+	ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string,
+) (*pluginadapters.RemoteAccessInfo, error) {
+	m.handlePodRunningCalled = true
+	return m.remoteAccessInfo, m.handlePodRunningErr
+}
+
+func (m *mockRemoteAccessPlugin) CleanupRemoteAccess(ctx context.Context, pod *corev1.Pod, podEventsContext map[string]string) error {
+	m.cleanupRemoteAccessCalled = true
+	m.cleanupPodEventsContext = podEventsContext
+	return m.cleanupRemoteAccessErr
+}
+
+func newRemoteAccessTestReconciler(
+	t *testing.T,
+	mockPlugin *mockRemoteAccessPlugin,
+	objs ...client.Object,
+) (*RemoteAccessReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).Build()
+
+	return &RemoteAccessReconciler{
+		Client:               fakeClient,
+		resourceManager:      NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, nil),
+		podEventAdapters:     map[string]pluginadapters.PodEventPluginAdapter{pluginNameAWS: mockPlugin},
+		remoteAccessCleaners: map[string]pluginadapters.RemoteAccessCleaner{pluginNameAWS: mockPlugin},
+	}, fakeClient
+}
+
+func newRemoteAccessTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: testStrategyName},
+		},
+	}
+}
+
+func newRemoteAccessTestStrategy(podEventsHandler string) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: testStrategyName, Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			PodEventsHandler: podEventsHandler,
+			PodEventsContext: map[string]string{"podUid": "controller::PodUid()"},
+		},
+	}
+}
+
+func newRemoteAccessTestPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podNameWorkspaceSuffix,
+			Namespace: testNamespaceName,
+			UID:       testRemoteAccessPodUID,
+			Labels: map[string]string{
+				workspaceutil.LabelWorkspaceName: testWorkspaceName,
+				LabelAccessStrategyName:          testStrategyName,
+				LabelAccessStrategyNamespace:     testNamespaceName,
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// newDeletedRemoteAccessTestPod returns a deleted pod whose remote access was set up
+func newDeletedRemoteAccessTestPod() *corev1.Pod {
+	pod := newRemoteAccessTestPod()
+	deletionTime := metav1.Now()
+	pod.DeletionTimestamp = &deletionTime
+	pod.Finalizers = []string{RemoteAccessFinalizerName}
+	pod.Annotations = map[string]string{AnnotationRemoteAccessHandler: testRemoteAccessHandler}
+	return pod
+}
+
+func reconcileRemoteAccess(t *testing.T, r *RemoteAccessReconciler, pod *corev1.Pod) (ctrl.Result, error) {
+	t.Helper()
+	return r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+}
+
+func TestNewRemoteAccessReconciler_NoPlugins(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().Build()
+	rm := &ResourceManager{}
+
+	reconciler := NewRemoteAccessReconciler(fakeClient, rm, nil, WorkspaceControllerOptions{})
+
+	assert.Equal(t, rm, reconciler.resourceManager)
+	assert.Nil(t, reconciler.podEventAdapters)
+	assert.Nil(t, reconciler.remoteAccessCleaners)
+}
+
+func TestRemoteAccessReconcile_PodRunning_SetsUpRemoteAccess(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{
+		remoteAccessInfo: &pluginadapters.RemoteAccessInfo{InstanceID: testSSMInstanceID},
+	}
+	workspace := newRemoteAccessTestWorkspace()
+	pod := newRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin,
+		workspace, newRemoteAccessTestStrategy(testRemoteAccessHandler), pod)
+
+	result, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.True(t, mockPlugin.handlePodRunningCalled)
+
+	// The pod is marked before the setup so that its remote access resources are released on deletion
+	stored := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), stored))
+	assert.Equal(t, testRemoteAccessHandler, stored.Annotations[AnnotationRemoteAccessHandler])
+	assert.Contains(t, stored.Finalizers, RemoteAccessFinalizerName)
+
+	storedWorkspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), storedWorkspace))
+	assert.Equal(t, &workspacev1alpha1.RemoteAccessStatus{
+		PodUID:        testRemoteAccessPodUID,
+		SSMInstanceID: testSSMInstanceID,
+	}, storedWorkspace.Status.RemoteAccess)
+}
+
+func TestRemoteAccessReconcile_PodRunning_SetupErrorIsRetried(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{handlePodRunningErr: errors.New("throttled")}
+	pod := newRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin,
+		newRemoteAccessTestWorkspace(), newRemoteAccessTestStrategy(testRemoteAccessHandler), pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "throttled")
+
+	// A partial setup is still cleaned up on deletion
+	stored := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), stored))
+	assert.Contains(t, stored.Finalizers, RemoteAccessFinalizerName)
+}
+
+func TestRemoteAccessReconcile_PodRunning_SetupPendingIsRequeued(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	workspace := newRemoteAccessTestWorkspace()
+	pod := newRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin,
+		workspace, newRemoteAccessTestStrategy(testRemoteAccessHandler), pod)
+
+	result, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.Equal(t, remoteAccessPendingRequeueDelay, result.RequeueAfter)
+
+	storedWorkspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), storedWorkspace))
+	assert.Nil(t, storedWorkspace.Status.RemoteAccess)
+}
+
+func TestRemoteAccessReconcile_PodRunning_SkipsSetup(t *testing.T) {
+	tests := []struct {
+		name             string
+		podEventsHandler string
+		withWorkspace    bool
+	}{
+		{name: "no pod events handler", podEventsHandler: "", withWorkspace: true},
+		{name: "pod events handler without adapter", podEventsHandler: "other:unknown", withWorkspace: true},
+		{name: "workspace deleted", podEventsHandler: testRemoteAccessHandler, withWorkspace: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPlugin := &mockRemoteAccessPlugin{}
+			pod := newRemoteAccessTestPod()
+			objs := []client.Object{newRemoteAccessTestStrategy(tt.podEventsHandler), pod}
+			if tt.withWorkspace {
+				objs = append(objs, newRemoteAccessTestWorkspace())
+			}
+			r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin, objs...)
+
+			result, err := reconcileRemoteAccess(t, r, pod)
+			require.NoError(t, err)
+			assert.Equal(t, ctrl.Result{}, result)
+			assert.False(t, mockPlugin.handlePodRunningCalled)
+
+			stored := &corev1.Pod{}
+			require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), stored))
+			assert.NotContains(t, stored.Finalizers, RemoteAccessFinalizerName)
+		})
+	}
+}
+
+func TestRemoteAccessReconcile_PodDeleted_ReleasesRemoteAccess(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	workspace := newRemoteAccessTestWorkspace()
+	workspace.Status.RemoteAccess = &workspacev1alpha1.RemoteAccessStatus{
+		PodUID:        testRemoteAccessPodUID,
+		SSMInstanceID: testSSMInstanceID,
+	}
+	pod := newDeletedRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin,
+		workspace, newRemoteAccessTestStrategy(testRemoteAccessHandler), pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.True(t, mockPlugin.cleanupRemoteAccessCalled)
+	assert.Equal(t, testRemoteAccessPodUID, mockPlugin.cleanupPodEventsContext["podUid"])
+
+	// Removing the last finalizer completes the deletion of the pod
+	err = fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	storedWorkspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), storedWorkspace))
+	assert.Nil(t, storedWorkspace.Status.RemoteAccess)
+}
+
+func TestRemoteAccessReconcile_PodDeleted_KeepsStatusOfNewerPod(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	workspace := newRemoteAccessTestWorkspace()
+	newerPodStatus := &workspacev1alpha1.RemoteAccessStatus{PodUID: "newer-pod-uid", SSMInstanceID: "mi-newer"}
+	workspace.Status.RemoteAccess = newerPodStatus
+	pod := newDeletedRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin,
+		workspace, newRemoteAccessTestStrategy(testRemoteAccessHandler), pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+
+	storedWorkspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), storedWorkspace))
+	assert.Equal(t, newerPodStatus, storedWorkspace.Status.RemoteAccess)
+}
+
+func TestRemoteAccessReconcile_PodDeleted_CleanupErrorKeepsFinalizer(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{cleanupRemoteAccessErr: errors.New("throttled")}
+	pod := newDeletedRemoteAccessTestPod()
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin, pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.Error(t, err)
+
+	stored := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), stored))
+	assert.Contains(t, stored.Finalizers, RemoteAccessFinalizerName)
+}
+
+func TestRemoteAccessReconcile_PodDeleted_AccessStrategyDeleted(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	pod := newDeletedRemoteAccessTestPod()
+	r, _ := newRemoteAccessTestReconciler(t, mockPlugin, pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.True(t, mockPlugin.cleanupRemoteAccessCalled,
+		"Expected CleanupRemoteAccess to be called even though the access strategy was deleted")
+	assert.Nil(t, mockPlugin.cleanupPodEventsContext)
+}
+
+func TestRemoteAccessReconcile_PodDeleted_CleanerNotAvailable(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	pod := newDeletedRemoteAccessTestPod()
+	pod.Annotations[AnnotationRemoteAccessHandler] = "other:handler"
+	r, fakeClient := newRemoteAccessTestReconciler(t, mockPlugin, pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.False(t, mockPlugin.cleanupRemoteAccessCalled)
+
+	// The pod deletion is not blocked on a cleanup that can never succeed
+	err = fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestRemoteAccessReconcile_PodDeleted_WithoutFinalizer(t *testing.T) {
+	mockPlugin := &mockRemoteAccessPlugin{}
+	// The pod was deleted before its remote access was set up
+	pod := newDeletedRemoteAccessTestPod()
+	pod.Finalizers = []string{"example.com/other"}
+	delete(pod.Annotations, AnnotationRemoteAccessHandler)
+	r, _ := newRemoteAccessTestReconciler(t, mockPlugin, pod)
+
+	_, err := reconcileRemoteAccess(t, r, pod)
+	require.NoError(t, err)
+	assert.False(t, mockPlugin.cleanupRemoteAccessCalled)
+}
@@ -8,12 +8,16 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,12 +27,18 @@ import (
 // ResourceManager handles CRUD operations for Kubernetes resources
 type ResourceManager struct {
 	client                 client.Client
+	apiReader              client.Reader
 	scheme                 *runtime.Scheme
 	deploymentBuilder      *DeploymentBuilder
 	serviceBuilder         *ServiceBuilder
 	pvcBuilder             *PVCBuilder
+	networkPolicyBuilder   *NetworkPolicyBuilder
+	jupyterConfigBuilder   *JupyterConfigBuilder
+	provisioningJobBuilder *ProvisioningJobBuilder
 	accessResourcesBuilder *AccessResourcesBuilder
+	accessProviders        *AccessProviderRegistry
 	statusManager          *StatusManager
+	drift                  driftRecorder
 }
 
 // NewResourceManager creates a new ResourceManager
@@ -43,11 +53,16 @@ func NewResourceManager(
 ) *ResourceManager {
 	return &ResourceManager{
 		client:                 k8sClient,
+		apiReader:              k8sClient,
 		scheme:                 scheme,
 		deploymentBuilder:      deploymentBuilder,
 		serviceBuilder:         serviceBuilder,
 		pvcBuilder:             pvcBuilder,
+		networkPolicyBuilder:   NewNetworkPolicyBuilder(scheme, os.Getenv(ControllerPodNamespaceEnv)),
+		jupyterConfigBuilder:   NewJupyterConfigBuilder(scheme),
+		provisioningJobBuilder: NewProvisioningJobBuilder(scheme),
 		accessResourcesBuilder: accessResourcesBuilder,
+		accessProviders:        DefaultAccessProviders(),
 		statusManager:          statusManager,
 	}
 }
@@ -55,55 +70,64 @@ func NewResourceManager(
 // GetDeployment retrieves the deployment for a Workspace
 func (rm *ResourceManager) getDeployment(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*appsv1.Deployment, error) {
 	deployment := &appsv1.Deployment{}
-	deploymentName := GenerateDeploymentName(workspace.Name)
-
-	err := rm.client.Get(ctx, types.NamespacedName{
-		Name:      deploymentName,
-		Namespace: workspace.Namespace,
-	}, deployment)
-
+	err := rm.getChild(ctx, workspace, GenerateDeploymentName(workspace), deployment)
 	return deployment, err
 }
 
 // GetService retrieves the service for a Workspace
 func (rm *ResourceManager) getService(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.Service, error) {
 	service := &corev1.Service{}
-	serviceName := GenerateServiceName(workspace.Name)
-
-	err := rm.client.Get(ctx, types.NamespacedName{
-		Name:      serviceName,
-		Namespace: workspace.Namespace,
-	}, service)
-
+	err := rm.getChild(ctx, workspace, GenerateServiceName(workspace), service)
 	return service, err
 }
 
-// getPVC retrieves the PVC for a Workspace
+// getPVC retrieves the PVC for a Workspace. A PVC retained by a deleted workspace is only adopted by
+// a workspace with the same owner, and never by a workspace being deleted.
 func (rm *ResourceManager) getPVC(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
 	pvc := &corev1.PersistentVolumeClaim{}
-	pvcName := GeneratePVCName(workspace.Name)
-
-	err := rm.client.Get(ctx, types.NamespacedName{
-		Name:      pvcName,
-		Namespace: workspace.Namespace,
-	}, pvc)
+	name := GeneratePVCName(workspace)
+	if err := rm.client.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, pvc); err != nil {
+		return pvc, err
+	}
+	return pvc, rm.adoptPVC(ctx, workspace, pvc)
+}
 
-	return pvc, err
+// adoptPVC adopts the existing PVC of the workspace, unless it is retained for another owner
+func (rm *ResourceManager) adoptPVC(ctx context.Context, workspace *workspacev1alpha1.Workspace, pvc *corev1.PersistentVolumeClaim) error {
+	name := pvc.Name
+	if retainedFor, retained := pvc.Annotations[AnnotationRetainedFor]; retained && metav1.GetControllerOf(pvc) == nil {
+		if !workspace.DeletionTimestamp.IsZero() || retainedFor != workspace.Annotations[AnnotationCreatedBy] {
+			return fmt.Errorf("%w: %s is retained for %q", ErrResourceNameCollision, name, retainedFor)
+		}
+		// Persisted by the adoption
+		delete(pvc.Annotations, AnnotationRetainedFor)
+	}
+	return rm.adoptChild(ctx, workspace, name, pvc)
 }
 
 // CreateDeployment creates a new deployment for the Workspace
 func (rm *ResourceManager) createDeployment(ctx context.Context, workspace *workspacev1alpha1.Workspace, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (*appsv1.Deployment, error) {
 	logger := logf.FromContext(ctx)
 
+	// Start on the node of a warm standby pod when one is available; persisted with the status
+	workspace.Status.PoolClaim = rm.claimStandbyPod(ctx, workspace)
+
 	deployment, err := rm.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, accessStrategy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build deployment: %w", err)
 	}
+	if err := stampDeploymentDesiredState(deployment); err != nil {
+		return nil, err
+	}
 	// Apply the changes to deployment
 	logger.Info("Creating Deployment",
 		"deployment", deployment.Name,
 		"namespace", deployment.Namespace)
 	if err := rm.client.Create(ctx, deployment); err != nil {
+		if errors.IsAlreadyExists(err) {
+			existing := &appsv1.Deployment{}
+			return existing, rm.adoptExistingChild(ctx, workspace, deployment.Name, existing)
+		}
 		return nil, fmt.Errorf("failed to create deployment: %w", err)
 	}
 
@@ -118,12 +142,19 @@ func (rm *ResourceManager) createService(ctx context.Context, workspace *workspa
 	if err != nil {
 		return nil, fmt.Errorf("failed to build service: %w", err)
 	}
+	if err := stampServiceDesiredState(service); err != nil {
+		return nil, err
+	}
 
 	logger.Info("Creating Service",
 		"service", service.Name,
 		"namespace", service.Namespace)
 
 	if err := rm.client.Create(ctx, service); err != nil {
+		if errors.IsAlreadyExists(err) {
+			existing := &corev1.Service{}
+			return existing, rm.adoptExistingChild(ctx, workspace, service.Name, existing)
+		}
 		return nil, fmt.Errorf("failed to create service: %w", err)
 	}
 
@@ -143,17 +174,40 @@ func (rm *ResourceManager) createPVC(ctx context.Context, workspace *workspacev1
 		return nil, nil // No storage requested
 	}
 
+	// A clone with includeStorage starts from a copy of the source workspace's PVC, named after
+	// the resource name prefix of the source
+	if cloneFrom := workspace.Spec.CloneFrom; cloneFrom != nil && cloneFrom.IncludeStorage && pvc.Spec.DataSource == nil {
+		source := &workspacev1alpha1.Workspace{}
+		if err := rm.client.Get(ctx, types.NamespacedName{Name: cloneFrom.Name, Namespace: workspace.Namespace}, source); err != nil {
+			return nil, fmt.Errorf("failed to get clone source workspace %s: %w", cloneFrom.Name, err)
+		}
+		pvc.Spec.DataSource = buildCloneDataSource(source)
+	}
+
 	logger.Info("Creating PVC",
 		"pvc", pvc.Name,
 		"namespace", pvc.Namespace)
 
 	if err := rm.client.Create(ctx, pvc); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return rm.adoptExistingPVC(ctx, workspace, pvc.Name)
+		}
 		return nil, fmt.Errorf("failed to create PVC: %w", err)
 	}
 
 	return pvc, nil
 }
 
+// adoptExistingPVC adopts the PVC named name that exists although the cache has not listed it yet
+func (rm *ResourceManager) adoptExistingPVC(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := rm.apiReader.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, pvc); err != nil {
+		return nil, fmt.Errorf("failed to get existing %s: %w", name, err)
+	}
+	return pvc, rm.adoptPVC(ctx, workspace, pvc)
+}
+
 // DeleteDeployment deletes the deployment for a Workspace
 func (rm *ResourceManager) deleteDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
 	logger := logf.FromContext(ctx)
@@ -217,6 +271,22 @@ func (rm *ResourceManager) ensureDeploymentUpToDate(ctx context.Context, deploym
 		}
 	}
 
+	// Manual changes are only reverted under the Revert drift policy
+	desiredDeployment, err := rm.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, accessStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build desired deployment: %w", err)
+	}
+	driftedFields, desiredHash, err := deploymentDrift(deployment, desiredDeployment)
+	if err != nil {
+		return nil, err
+	}
+	if len(driftedFields) > 0 {
+		rm.recordDrift(workspace, "Deployment", deployment.Name, driftedFields)
+		if resolveDriftPolicy(workspace) != workspacev1alpha1.DriftPolicyRevert {
+			return deployment, nil
+		}
+	}
+
 	needsUpdate, err := rm.deploymentBuilder.NeedsUpdate(ctx, deployment, workspace, accessStrategy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if deployment needs update: %w", err)
@@ -226,6 +296,14 @@ func (rm *ResourceManager) ensureDeploymentUpToDate(ctx context.Context, deploym
 		return rm.updateDeployment(ctx, deployment, workspace, accessStrategy)
 	}
 
+	if deployment.Annotations[AnnotationDesiredStateHash] != desiredHash {
+		// Record the desired state of a deployment created before drift detection
+		setDesiredStateHash(deployment, desiredHash)
+		if err := rm.client.Update(ctx, deployment); err != nil {
+			return nil, fmt.Errorf("failed to record desired state of deployment: %w", err)
+		}
+	}
+
 	return deployment, nil
 }
 
@@ -250,7 +328,11 @@ func (rm *ResourceManager) updateDeployment(ctx context.Context, deployment *app
 	}
 
 	// Update the existing deployment spec while preserving metadata like resourceVersion
+	if err := stampDeploymentDesiredState(updatedDeployment); err != nil {
+		return nil, err
+	}
 	deployment.Spec = updatedDeployment.Spec
+	setDesiredStateHash(deployment, updatedDeployment.Annotations[AnnotationDesiredStateHash])
 
 	logger.Info("Updating Deployment",
 		"deployment", deployment.Name,
@@ -283,12 +365,28 @@ func (rm *ResourceManager) ensureServiceUpToDate(ctx context.Context, service *c
 		return service, nil
 	}
 
+	// Manual changes are only reverted under the Revert drift policy
+	desiredService, err := rm.serviceBuilder.BuildService(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build desired service: %w", err)
+	}
+	driftedFields, desiredHash, err := serviceDrift(service, desiredService)
+	if err != nil {
+		return nil, err
+	}
+	if len(driftedFields) > 0 {
+		rm.recordDrift(workspace, "Service", service.Name, driftedFields)
+		if resolveDriftPolicy(workspace) != workspacev1alpha1.DriftPolicyRevert {
+			return service, nil
+		}
+	}
+
 	needsUpdate, err := rm.serviceBuilder.NeedsUpdate(ctx, service, workspace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if service needs update: %w", err)
 	}
 
-	if needsUpdate {
+	if needsUpdate || service.Annotations[AnnotationDesiredStateHash] != desiredHash {
 		return rm.updateService(ctx, service, workspace)
 	}
 
@@ -303,6 +401,9 @@ func (rm *ResourceManager) updateService(ctx context.Context, service *corev1.Se
 	if err := rm.serviceBuilder.UpdateServiceSpec(ctx, service, workspace); err != nil {
 		return nil, fmt.Errorf("failed to update service spec: %w", err)
 	}
+	if err := stampServiceDesiredState(service); err != nil {
+		return nil, err
+	}
 
 	logger.Info("Updating Service",
 		"service", service.Name,
@@ -319,7 +420,7 @@ func (rm *ResourceManager) updateService(ctx context.Context, service *corev1.Se
 func (rm *ResourceManager) EnsureDeploymentDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*appsv1.Deployment, error) {
 	deployment, err := rm.getDeployment(ctx, workspace)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if isChildMissing(err) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get deployment: %w", err)
@@ -335,7 +436,7 @@ func (rm *ResourceManager) EnsureDeploymentDeleted(ctx context.Context, workspac
 func (rm *ResourceManager) EnsureServiceDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.Service, error) {
 	service, err := rm.getService(ctx, workspace)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if isChildMissing(err) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get service: %w", err)
@@ -350,8 +451,8 @@ func (rm *ResourceManager) EnsureServiceDeleted(ctx context.Context, workspace *
 func (rm *ResourceManager) EnsurePVCDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
 	pvc, err := rm.getPVC(ctx, workspace)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil, nil // Already deleted
+		if isChildMissing(err) {
+			return nil, nil // Already deleted, or not the workspace's
 		}
 		return nil, fmt.Errorf("failed to get PVC: %w", err)
 	}
@@ -386,8 +487,12 @@ func (rm *ResourceManager) EnsurePVCExists(ctx context.Context, workspace *works
 	return rm.ensurePVCUpToDate(ctx, pvc, workspace)
 }
 
-// ensurePVCUpToDate checks if PVC needs update and updates it if necessary
+// ensurePVCUpToDate checks if PVC needs update and updates it if necessary.
+// A storage size increase expands the PVC when its StorageClass allows volume expansion;
+// the expansion progress is tracked in workspace.Status.StorageExpansion.
 func (rm *ResourceManager) ensurePVCUpToDate(ctx context.Context, pvc *corev1.PersistentVolumeClaim, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
+	logger := logf.FromContext(ctx)
+
 	// Only perform updates when workspace is available to avoid interfering with creation
 	if !rm.statusManager.IsWorkspaceAvailable(workspace) {
 		return pvc, nil
@@ -399,15 +504,88 @@ func (rm *ResourceManager) ensurePVCUpToDate(ctx context.Context, pvc *corev1.Pe
 	}
 
 	if needsUpdate {
-		return rm.updatePVC(ctx, pvc, workspace)
+		desiredSize := resolveStorageSize(workspace)
+		currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+		switch desiredSize.Cmp(currentSize) {
+		case 1:
+			return rm.expandPVC(ctx, pvc, workspace, desiredSize)
+		case -1:
+			// The webhook rejects shrinking below the PVC size; this only happens when it is bypassed
+			logger.Info("Ignoring storage size decrease, volumes cannot be shrunk",
+				"pvc", pvc.Name, "currentSize", currentSize.String(), "desiredSize", desiredSize.String())
+		default:
+			if pvc, err = rm.updatePVC(ctx, pvc, workspace); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rm.pvcBuilder.UpdateStorageExpansionStatus(workspace, pvc)
+	return pvc, nil
+}
+
+// expandPVC grows the PVC storage request to desiredSize if its StorageClass allows volume expansion
+func (rm *ResourceManager) expandPVC(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+	workspace *workspacev1alpha1.Workspace,
+	desiredSize resource.Quantity) (*corev1.PersistentVolumeClaim, error) {
+	logger := logf.FromContext(ctx)
+
+	allowed, reason, err := rm.isVolumeExpansionAllowed(ctx, pvc)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		logger.Info("Cannot expand PVC", "pvc", pvc.Name, "reason", reason)
+		workspace.Status.StorageExpansion = &workspacev1alpha1.StorageExpansionStatus{
+			Phase:         workspacev1alpha1.StorageExpansionUnsupported,
+			RequestedSize: desiredSize,
+			Message:       reason,
+		}
+		return pvc, nil
+	}
+
+	logger.Info("Expanding PVC", "pvc", pvc.Name, "desiredSize", desiredSize.String())
+	pvc, err = rm.updatePVC(ctx, pvc, workspace)
+	if err != nil {
+		return nil, err
 	}
 
+	workspace.Status.StorageExpansion = &workspacev1alpha1.StorageExpansionStatus{
+		Phase:         workspacev1alpha1.StorageExpansionInProgress,
+		RequestedSize: desiredSize,
+	}
+	rm.pvcBuilder.UpdateStorageExpansionStatus(workspace, pvc)
 	return pvc, nil
 }
 
-// updatePVC updates an existing PVC with new spec
+// isVolumeExpansionAllowed checks the PVC's StorageClass for allowVolumeExpansion.
+// Returns false with a reason when the PVC cannot be expanded.
+func (rm *ResourceManager) isVolumeExpansionAllowed(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, string, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, fmt.Sprintf("PVC %s has no StorageClass, volume expansion is not supported", pvc.Name), nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := rm.client.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return false, fmt.Sprintf("StorageClass %s not found, volume expansion is not supported", *pvc.Spec.StorageClassName), nil
+		}
+		return false, "", fmt.Errorf("failed to get StorageClass %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return false, fmt.Sprintf("StorageClass %s does not allow volume expansion", storageClass.Name), nil
+	}
+	return true, "", nil
+}
+
+// updatePVC patches an existing PVC with new spec
 func (rm *ResourceManager) updatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
 	logger := logf.FromContext(ctx)
+	original := pvc.DeepCopy()
 
 	// Update the PVC spec using the builder
 	if err := rm.pvcBuilder.UpdatePVCSpec(ctx, pvc, workspace); err != nil {
@@ -418,7 +596,7 @@ func (rm *ResourceManager) updatePVC(ctx context.Context, pvc *corev1.Persistent
 		"pvc", pvc.Name,
 		"namespace", pvc.Namespace)
 
-	if err := rm.client.Update(ctx, pvc); err != nil {
+	if err := rm.client.Patch(ctx, pvc, client.MergeFrom(original)); err != nil {
 		return nil, fmt.Errorf("failed to update PVC: %w", err)
 	}
 
@@ -464,23 +642,34 @@ func (rm *ResourceManager) CleanupAllResources(ctx context.Context, workspace *w
 	return false, nil
 }
 
+// AreWorkspacePodsDeleted checks that no pod of the workspace remains, including terminating ones
+func (rm *ResourceManager) AreWorkspacePodsDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	podList := &corev1.PodList{}
+	if err := rm.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return false, fmt.Errorf("failed to list workspace pods: %w", err)
+	}
+	return len(podList.Items) == 0, nil
+}
+
 // AreAllResourcesDeleted checks if all workspace resources are fully removed (not found)
 func (rm *ResourceManager) AreAllResourcesDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) bool {
 	// Check deployment - must be NotFound (fully deleted)
 	_, err := rm.getDeployment(ctx, workspace)
-	if err == nil || !errors.IsNotFound(err) {
+	if err == nil || !isChildMissing(err) {
 		return false // Still exists or other error
 	}
 
 	// Check service - must be NotFound (fully deleted)
 	_, err = rm.getService(ctx, workspace)
-	if err == nil || !errors.IsNotFound(err) {
+	if err == nil || !isChildMissing(err) {
 		return false // Still exists or other error
 	}
 
 	// Check PVC - must be NotFound (fully deleted)
 	_, err = rm.getPVC(ctx, workspace)
-	if err == nil || !errors.IsNotFound(err) {
+	if err == nil || !isChildMissing(err) {
 		return false // Still exists or other error
 	}
 
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -32,11 +33,20 @@ func (rm *ResourceManager) GetAccessStrategyForWorkspace(
 		// no-op: no AccessStrategy
 		return nil, nil
 	}
+	return rm.GetAccessStrategy(ctx, accessStrategyRef, workspace.Namespace)
+}
 
+// GetAccessStrategy retrieves the AccessStrategy referenced by accessStrategyRef.
+// The AccessStrategy is looked up in defaultNamespace when the reference has no namespace.
+func (rm *ResourceManager) GetAccessStrategy(
+	ctx context.Context,
+	accessStrategyRef *workspacev1alpha1.AccessStrategyRef,
+	defaultNamespace string,
+) (*workspacev1alpha1.WorkspaceAccessStrategy, error) {
 	// Determine namespace for the AccessStrategy
-	accessStrategyNamespace := workspace.Namespace
-	if workspace.Spec.AccessStrategy != nil && workspace.Spec.AccessStrategy.Namespace != "" {
-		accessStrategyNamespace = workspace.Spec.AccessStrategy.Namespace
+	accessStrategyNamespace := defaultNamespace
+	if accessStrategyRef.Namespace != "" {
+		accessStrategyNamespace = accessStrategyRef.Namespace
 	}
 
 	// Get the AccessStrategy
@@ -48,42 +58,98 @@ func (rm *ResourceManager) GetAccessStrategyForWorkspace(
 
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return nil, fmt.Errorf("access strategy %s not found in namespace %s", accessStrategyRef.Name, accessStrategyNamespace)
+			return nil, errs.NotFound(err, "access strategy %s not found in namespace %s", accessStrategyRef.Name, accessStrategyNamespace)
 		}
-		return nil, fmt.Errorf("failed to get access strategy: %w", err)
+		return nil, errs.Internal(err, "failed to get access strategy %s", accessStrategyRef.Name)
 	}
 
 	return accessStrategy, nil
 }
 
+// GetAccessProvider returns the access provider selected by the AccessStrategy
+func (rm *ResourceManager) GetAccessProvider(
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) (AccessProvider, error) {
+	provider, err := rm.accessProviders.Get(accessStrategy.Spec.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("access strategy %s: %w", accessStrategy.Name, err)
+	}
+	return provider, nil
+}
+
 // EnsureAccessResourcesExist creates or updates routing resources for the Workspace
 func (rm *ResourceManager) EnsureAccessResourcesExist(
 	ctx context.Context,
 	workspace *workspacev1alpha1.Workspace,
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
 	service *corev1.Service,
+) error {
+	return rm.ensureAccessResourcesExistForStrategies(ctx, workspace, service, accessStrategy)
+}
+
+// EnsureMigratingAccessResourcesExist creates or updates the routing resources of both the current
+// and the target AccessStrategy of a Workspace being migrated, so that the Workspace remains
+// reachable through the current AccessStrategy until the target AccessStrategy is verified.
+func (rm *ResourceManager) EnsureMigratingAccessResourcesExist(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	targetAccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) error {
+	return rm.ensureAccessResourcesExistForStrategies(ctx, workspace, service, accessStrategy, targetAccessStrategy)
+}
+
+// ensureAccessResourcesExistForStrategies applies the routing resources of the AccessStrategies
+// and deletes the tracked resources that none of them defines
+func (rm *ResourceManager) ensureAccessResourcesExistForStrategies(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	service *corev1.Service,
+	accessStrategies ...*workspacev1alpha1.WorkspaceAccessStrategy,
 ) error {
 	logger := logf.FromContext(ctx)
-	// The AccessResource MUST be in the Workspace namespace
-	// in order for the Workspace is the owner of the AccessResource
-	accessResourceNamespace := workspace.Namespace
 
-	// Track which resources are defined in the current AccessStrategy
+	// Track which resources are defined in the current AccessStrategies
 	currentResources := make(map[string]bool)
 
-	// ensure each of the resources defined in the accessStrategy exists
-	for _, resourceTemplate := range accessStrategy.Spec.AccessResourceTemplates {
-		// Build the lookup name that will be stored in status
-		lookupName := fmt.Sprintf("%s-%s", resourceTemplate.NamePrefix, workspace.Name)
-		// Track this resource as defined in the current AccessStrategy
-		resourceKey := fmt.Sprintf("%s/%s/%s", resourceTemplate.Kind, lookupName, accessResourceNamespace)
-		currentResources[resourceKey] = true
-
-		// Apply resource
-		err := rm.ensureAccessResourceExists(ctx, workspace, accessStrategy, service, &resourceTemplate, accessResourceNamespace)
+	for _, accessStrategy := range accessStrategies {
+		provider, err := rm.GetAccessProvider(accessStrategy)
 		if err != nil {
 			return err
 		}
+
+		// Build the resources defined by the AccessStrategy through its provider
+		expectedResources, err := provider.BuildResources(workspace, accessStrategy, service)
+		if err != nil {
+			return fmt.Errorf("failed to build access resources: %w", err)
+		}
+
+		// ensure each of the resources defined in the accessStrategy exists
+		for _, expectedObj := range expectedResources {
+			// The AccessResource MUST be in the Workspace namespace
+			// in order for the Workspace is the owner of the AccessResource
+			if expectedObj.GetNamespace() != workspace.Namespace {
+				return fmt.Errorf("access provider %s built %s %s in namespace %q instead of the workspace namespace %q",
+					provider.Name(), expectedObj.GetKind(), expectedObj.GetName(), expectedObj.GetNamespace(), workspace.Namespace)
+			}
+
+			// Track this resource as defined in the current AccessStrategy
+			resourceKey := fmt.Sprintf("%s/%s/%s", expectedObj.GetKind(), expectedObj.GetName(), expectedObj.GetNamespace())
+			currentResources[resourceKey] = true
+
+			// Add the values generated once per workspace to Secrets that define them
+			if generators := accessSecretGenerators(workspace, accessStrategy, expectedObj); len(generators) > 0 {
+				if err := rm.applyGeneratedSecretValues(ctx, workspace, expectedObj, generators); err != nil {
+					return err
+				}
+			}
+
+			// Apply resource
+			if err := rm.ensureAccessResourceExists(ctx, workspace, expectedObj); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Check for resources that exist in status but are no longer in the AccessStrategy
@@ -131,24 +197,20 @@ func (rm *ResourceManager) EnsureAccessResourcesExist(
 	return nil
 }
 
-// applyResource creates or updates a resource
+// ensureAccessResourceExists creates or updates an access resource built by the access provider
 func (rm *ResourceManager) ensureAccessResourceExists(
 	ctx context.Context,
 	workspace *workspacev1alpha1.Workspace,
-	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
-	service *corev1.Service,
-	resourceTemplate *workspacev1alpha1.AccessResourceTemplate,
-	accessResourceNamespace string,
+	obj *unstructured.Unstructured,
 ) error {
 	logger := logf.FromContext(ctx)
 
 	// Check if the resource exists
 	var accessResourceStatus *workspacev1alpha1.AccessResourceStatus
 	statusIdx := -1
-	lookupName := fmt.Sprintf("%s-%s", resourceTemplate.NamePrefix, workspace.Name)
 
 	for idx, existingResourceStatus := range workspace.Status.AccessResources {
-		if existingResourceStatus.Kind == resourceTemplate.Kind && existingResourceStatus.Name == lookupName && existingResourceStatus.Namespace == accessResourceNamespace {
+		if existingResourceStatus.Kind == obj.GetKind() && existingResourceStatus.Name == obj.GetName() && existingResourceStatus.Namespace == obj.GetNamespace() {
 			accessResourceStatus = &existingResourceStatus
 			statusIdx = idx
 			break
@@ -171,22 +233,30 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 		}, existingObj)
 
 		if lookupError == nil {
-			// Resource exists, but we need to check if it matches the current AccessStrategy template
-			// Build the expected resource from the current template
-			expectedObj, err := rm.accessResourcesBuilder.BuildUnstructuredResource(*resourceTemplate, workspace, accessStrategy, service)
+			// Resource exists, but we need to check if it matches the current AccessStrategy
+			expectedObj := obj.DeepCopy()
+
+			// Manual changes are only reverted under the Revert drift policy
+			driftedFields, desiredHash, err := accessResourceDrift(existingObj, expectedObj)
 			if err != nil {
-				return fmt.Errorf("failed to build expected resource: %w", err)
+				return err
+			}
+			if len(driftedFields) > 0 {
+				rm.recordDrift(workspace, existingObj.GetKind(), existingObj.GetName(), driftedFields)
+				if resolveDriftPolicy(workspace) != workspacev1alpha1.DriftPolicyRevert {
+					return nil
+				}
 			}
 
 			// Compare the specs to detect changes
-			existingSpec, existingFound, err := unstructured.NestedFieldCopy(existingObj.Object, "spec")
+			specChanged, err := accessResourceFieldChanged(existingObj, expectedObj, "spec")
 			if err != nil {
-				return fmt.Errorf("error getting existing spec: %w", err)
+				return err
 			}
-
-			expectedSpec, expectedFound, err := unstructured.NestedFieldCopy(expectedObj.Object, "spec")
+			// Secrets and ConfigMaps hold their content in data rather than spec
+			dataChanged, err := accessResourceFieldChanged(existingObj, expectedObj, "data")
 			if err != nil {
-				return fmt.Errorf("error getting expected spec: %w", err)
+				return err
 			}
 
 			// If specs are different, update the access resource
@@ -194,7 +264,7 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 			// case 1: the access resource was modified by another process (in spite of owner reference)
 			// case 2: the AccessStrategy modified the resource template, so that the actual resource
 			// no longer conforms to the resource defined in AccessStrategy
-			if existingFound && expectedFound && !reflect.DeepEqual(existingSpec, expectedSpec) {
+			if specChanged || dataChanged {
 				logger.Info("AccessResource spec doesn't match template, updating",
 					"kind", existingObj.GetKind(),
 					"name", existingObj.GetName(),
@@ -202,6 +272,7 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 
 				// Preserve metadata from existing object
 				expectedObj.SetResourceVersion(existingObj.GetResourceVersion())
+				setDesiredStateHash(expectedObj, desiredHash)
 
 				// Update the resource
 				if err := rm.client.Update(ctx, expectedObj); err != nil {
@@ -212,6 +283,12 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 					"kind", expectedObj.GetKind(),
 					"name", expectedObj.GetName(),
 					"namespace", expectedObj.GetNamespace())
+			} else if accessResourceMissesDesiredStateHash(existingObj, expectedObj, desiredHash) {
+				// Record the desired state of a resource created before drift detection
+				setDesiredStateHash(existingObj, desiredHash)
+				if err := rm.client.Update(ctx, existingObj); err != nil {
+					return fmt.Errorf("failed to record desired state of access resource: %w", err)
+				}
 			}
 
 			// Resource exists and is now up to date
@@ -232,12 +309,9 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 	// END OF CASE1: resource exists in status
 
 	// CASE 2: resource doesn't exist, try to create it
-	// First, build the resource
-	obj, err := rm.accessResourcesBuilder.BuildUnstructuredResource(*resourceTemplate, workspace, accessStrategy, service)
-	if err != nil {
-		return fmt.Errorf("failed to build resource: %w", err)
+	if err := stampAccessResourceDesiredState(obj); err != nil {
+		return err
 	}
-
 	// Set owner reference
 	if err := controllerutil.SetControllerReference(workspace, obj, rm.scheme); err != nil {
 		return fmt.Errorf("failed to set controller reference: %w", err)
@@ -288,6 +362,22 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 	return nil
 }
 
+// accessResourceFieldChanged returns true if the top-level field is defined in both the existing
+// and the expected access resource, with different values
+func accessResourceFieldChanged(existingObj, expectedObj *unstructured.Unstructured, field string) (bool, error) {
+	existingValue, existingFound, err := unstructured.NestedFieldCopy(existingObj.Object, field)
+	if err != nil {
+		return false, fmt.Errorf("error getting existing %s: %w", field, err)
+	}
+
+	expectedValue, expectedFound, err := unstructured.NestedFieldCopy(expectedObj.Object, field)
+	if err != nil {
+		return false, fmt.Errorf("error getting expected %s: %w", field, err)
+	}
+
+	return existingFound && expectedFound && !reflect.DeepEqual(existingValue, expectedValue), nil
+}
+
 // getGroupVersionKind parses the API version and returns a GroupVersionKind struct
 func (rm *ResourceManager) getGroupVersionKind(apiVersion string, kind string) schema.GroupVersionKind {
 	var group, version string
@@ -43,6 +43,14 @@ var _ = Describe("ResourceManagerForAccess", func() {
 		accessResourcesBuilder *AccessResourcesBuilder
 	)
 
+	// buildAccessResource renders the access strategy's first template for the workspace
+	buildAccessResource := func(ws *workspacev1alpha1.Workspace) *unstructured.Unstructured {
+		obj, err := accessResourcesBuilder.BuildUnstructuredResource(
+			accessStrategy.Spec.AccessResourceTemplates[0], ws, accessStrategy, service)
+		Expect(err).NotTo(HaveOccurred())
+		return obj
+	}
+
 	BeforeEach(func() {
 		// Set up the context and scheme
 		ctx = context.Background()
@@ -489,14 +497,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -511,14 +512,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -538,14 +532,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -569,14 +556,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -601,14 +581,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -634,14 +607,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -668,14 +634,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			)
 
 			// Call the function under test
-			err := brokenResourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := brokenResourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -737,14 +696,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -806,14 +758,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -845,14 +790,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -872,14 +810,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -895,14 +826,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -921,14 +845,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -952,14 +869,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			)
 
 			// Call the function under test
-			err := brokenResourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := brokenResourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -999,14 +909,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -1022,14 +925,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -1052,14 +948,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).To(HaveOccurred())
@@ -1084,14 +973,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			}
 
 			// Call the function under test
-			err := resourceManager.ensureAccessResourceExists(
-				ctx,
-				workspace,
-				accessStrategy,
-				service,
-				&accessStrategy.Spec.AccessResourceTemplates[0],
-				workspace.Namespace,
-			)
+			err := resourceManager.ensureAccessResourceExists(ctx, workspace, buildAccessResource(workspace))
 
 			// Verify the results
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// EnsureHubActivityToken creates the Secret holding the token with which a workspace with hub
+// activity detection reports its activity, and deletes the Secret of a workspace whose hub activity
+// detection was disabled. The token is generated once and kept for the lifetime of the Secret; a
+// Secret whose token was removed gets a new one. The Secret is read from the API server, so that
+// the manager does not watch every Secret of the cluster.
+func (rm *ResourceManager) EnsureHubActivityToken(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if !HubActivityEnabled(workspace) {
+		return rm.ensureHubActivityTokenDeleted(ctx, workspace)
+	}
+
+	name := GenerateHubActivityTokenSecretName(workspace)
+	secret := &corev1.Secret{}
+	if err := rm.apiReader.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get hub activity token secret: %w", err)
+		}
+		token, err := randomSecretString(DefaultGeneratedSecretLength)
+		if err != nil {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: workspace.Namespace,
+				Labels:    GenerateLabels(workspace.Name),
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{HubActivityTokenSecretKey: []byte(token)},
+		}
+		if err := controllerutil.SetControllerReference(workspace, secret, rm.scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		logf.FromContext(ctx).Info("Creating hub activity token Secret", "secret", name, "namespace", workspace.Namespace)
+		if err := rm.client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("failed to create hub activity token secret: %w", err)
+		}
+		return nil
+	}
+	if err := rm.adoptChild(ctx, workspace, name, secret); err != nil {
+		return err
+	}
+
+	if len(secret.Data[HubActivityTokenSecretKey]) > 0 {
+		return nil
+	}
+	token, err := randomSecretString(DefaultGeneratedSecretLength)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[HubActivityTokenSecretKey] = []byte(token)
+
+	logf.FromContext(ctx).Info("Regenerating hub activity token", "secret", name, "namespace", workspace.Namespace)
+	if err := rm.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update hub activity token secret: %w", err)
+	}
+	return nil
+}
+
+// ensureHubActivityTokenDeleted deletes the hub activity token Secret the workspace controls, if any.
+// Secrets the workspace does not control are left alone, rather than adopted to be deleted.
+func (rm *ResourceManager) ensureHubActivityTokenDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: GenerateHubActivityTokenSecretName(workspace), Namespace: workspace.Namespace}
+	if err := rm.apiReader.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get hub activity token secret: %w", err)
+	}
+	if owner := metav1.GetControllerOf(secret); owner == nil || owner.UID != workspace.UID || !secret.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting hub activity token Secret", "secret", secret.Name, "namespace", secret.Namespace)
+	if err := rm.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete hub activity token secret: %w", err)
+	}
+	return nil
+}
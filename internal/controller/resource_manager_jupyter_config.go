@@ -0,0 +1,84 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// EnsureJupyterConfigMap creates or updates the ConfigMap holding the Jupyter configuration of a
+// workspace with a Jupyter server config, and deletes the ConfigMap of a workspace whose config was
+// removed. The ConfigMap is read from the API server, so that the manager does not watch every
+// ConfigMap of the cluster. Manual changes to its data are reverted.
+func (rm *ResourceManager) EnsureJupyterConfigMap(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.JupyterServerConfig == nil {
+		return rm.ensureJupyterConfigMapDeleted(ctx, workspace)
+	}
+
+	desiredConfigMap, err := rm.jupyterConfigBuilder.BuildConfigMap(workspace)
+	if err != nil {
+		return fmt.Errorf("failed to build jupyter config map: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: desiredConfigMap.Name, Namespace: workspace.Namespace}
+	if err := rm.apiReader.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			logf.FromContext(ctx).Info("Creating Jupyter config ConfigMap", "configMap", desiredConfigMap.Name, "namespace", workspace.Namespace)
+			if err := rm.client.Create(ctx, desiredConfigMap); err != nil {
+				return fmt.Errorf("failed to create jupyter config map: %w", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to get jupyter config map: %w", err)
+	}
+	if err := rm.adoptChild(ctx, workspace, desiredConfigMap.Name, configMap); err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(configMap.Data, desiredConfigMap.Data) {
+		return nil
+	}
+	configMap.Data = desiredConfigMap.Data
+
+	logf.FromContext(ctx).Info("Updating Jupyter config ConfigMap", "configMap", configMap.Name, "namespace", configMap.Namespace)
+	if err := rm.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update jupyter config map: %w", err)
+	}
+	return nil
+}
+
+// ensureJupyterConfigMapDeleted deletes the Jupyter config ConfigMap the workspace controls, if any.
+// ConfigMaps the workspace does not control are left alone, rather than adopted to be deleted.
+func (rm *ResourceManager) ensureJupyterConfigMapDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: GenerateJupyterConfigMapName(workspace), Namespace: workspace.Namespace}
+	if err := rm.apiReader.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get jupyter config map: %w", err)
+	}
+	if owner := metav1.GetControllerOf(configMap); owner == nil || owner.UID != workspace.UID || !configMap.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting Jupyter config ConfigMap", "configMap", configMap.Name, "namespace", configMap.Namespace)
+	if err := rm.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete jupyter config map: %w", err)
+	}
+	return nil
+}
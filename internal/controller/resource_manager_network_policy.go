@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// EnsureNetworkPolicy creates or updates the NetworkPolicy of a workspace with network isolation,
+// and deletes the NetworkPolicy of a workspace whose network isolation was removed. Manual changes
+// to the policy are always reverted, whatever the drift policy, since they could open the workspace.
+func (rm *ResourceManager) EnsureNetworkPolicy(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.NetworkIsolation == nil {
+		return rm.ensureNetworkPolicyDeleted(ctx, workspace)
+	}
+
+	policy := &networkingv1.NetworkPolicy{}
+	if err := rm.getChild(ctx, workspace, GenerateNetworkPolicyName(workspace), policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return rm.createNetworkPolicy(ctx, workspace)
+		}
+		return fmt.Errorf("failed to get network policy: %w", err)
+	}
+
+	if !rm.networkPolicyBuilder.NeedsUpdate(policy, workspace) {
+		return nil
+	}
+	desiredPolicy, err := rm.networkPolicyBuilder.BuildNetworkPolicy(workspace)
+	if err != nil {
+		return fmt.Errorf("failed to build network policy: %w", err)
+	}
+	policy.Spec = desiredPolicy.Spec
+
+	logf.FromContext(ctx).Info("Updating NetworkPolicy", "networkPolicy", policy.Name, "namespace", policy.Namespace)
+	if err := rm.client.Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to update network policy: %w", err)
+	}
+	return nil
+}
+
+// createNetworkPolicy creates the NetworkPolicy of the workspace
+func (rm *ResourceManager) createNetworkPolicy(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	policy, err := rm.networkPolicyBuilder.BuildNetworkPolicy(workspace)
+	if err != nil {
+		return fmt.Errorf("failed to build network policy: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Creating NetworkPolicy", "networkPolicy", policy.Name, "namespace", policy.Namespace)
+	if err := rm.client.Create(ctx, policy); err != nil {
+		return fmt.Errorf("failed to create network policy: %w", err)
+	}
+	return nil
+}
+
+// ensureNetworkPolicyDeleted deletes the NetworkPolicy the workspace controls, if any. Policies the
+// workspace does not control are left alone, rather than adopted to be deleted.
+func (rm *ResourceManager) ensureNetworkPolicyDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	policy := &networkingv1.NetworkPolicy{}
+	key := types.NamespacedName{Name: GenerateNetworkPolicyName(workspace), Namespace: workspace.Namespace}
+	if err := rm.client.Get(ctx, key, policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get network policy: %w", err)
+	}
+	if owner := metav1.GetControllerOf(policy); owner == nil || owner.UID != workspace.UID || !policy.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting NetworkPolicy", "networkPolicy", policy.Name, "namespace", policy.Namespace)
+	if err := rm.client.Delete(ctx, policy); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete network policy: %w", err)
+	}
+	return nil
+}
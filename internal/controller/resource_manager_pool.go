@@ -0,0 +1,79 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// claimStandbyPod claims a ready standby pod of a WorkspacePool serving the template of the
+// workspace and running its image, by relabelling it as claimed, and returns the claim. The pool
+// controller then deletes the claimed pod, which frees its place on the node for the workspace pod.
+// Claiming is best effort: it returns nil when no standby pod is available or the claim fails,
+// in which case the workspace starts as usual.
+func (rm *ResourceManager) claimStandbyPod(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+) *workspacev1alpha1.WorkspacePoolClaimStatus {
+	templateName := workspace.Labels[workspaceutil.LabelWorkspaceTemplate]
+	templateNamespace := workspace.Labels[workspaceutil.LabelWorkspaceTemplateNamespace]
+	if templateName == "" || templateNamespace == "" || rm.deploymentBuilder == nil {
+		return nil
+	}
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+
+	pods := &corev1.PodList{}
+	if err := rm.client.List(ctx, pods, client.MatchingLabels{
+		LabelStandbyState:                             StandbyStateAvailable,
+		workspaceutil.LabelWorkspaceTemplate:          templateName,
+		workspaceutil.LabelWorkspaceTemplateNamespace: templateNamespace,
+	}); err != nil {
+		logger.Error(err, "Failed to list standby pods")
+		return nil
+	}
+
+	image := rm.deploymentBuilder.imageResolver.ResolveImage(workspace)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Spec.NodeName == "" || !isPodReady(pod) ||
+			standbyPodImage(pod) != image {
+			continue
+		}
+
+		// The update fails on conflict when another workspace claimed the pod first
+		pod.Labels[LabelStandbyState] = StandbyStateClaimed
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[AnnotationClaimedBy] = workspace.Namespace + "/" + workspace.Name
+		if err := rm.client.Update(ctx, pod); err != nil {
+			if errors.IsConflict(err) || errors.IsNotFound(err) {
+				continue
+			}
+			logger.Error(err, "Failed to claim standby pod", "pod", pod.Name)
+			return nil
+		}
+
+		logger.Info("Claimed standby pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+		return &workspacev1alpha1.WorkspacePoolClaimStatus{
+			PoolName:      pod.Labels[LabelWorkspacePool],
+			PoolNamespace: pod.Namespace,
+			PodName:       pod.Name,
+			NodeName:      pod.Spec.NodeName,
+			ClaimTime:     metav1.Now(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func newPoolClaimTestWorkspace(image string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "claimer",
+			Namespace: "user-namespace",
+			Labels: map[string]string{
+				workspaceutil.LabelWorkspaceTemplate:          testPoolTemplate,
+				workspaceutil.LabelWorkspaceTemplateNamespace: testNamespaceName,
+			},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{Image: image},
+	}
+}
+
+func TestClaimStandbyPod_ClaimsReadyPodWithImage(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	pool := newPoolTestPool(3)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPoolTestStandbyPod(t, scheme, pool, "starting", imageMinimalNotebook, StandbyStateAvailable, ""),
+		newPoolTestStandbyPod(t, scheme, pool, "other-image", "other:latest", StandbyStateAvailable, "node-a"),
+		newPoolTestStandbyPod(t, scheme, pool, "ready", imageMinimalNotebook, StandbyStateAvailable, "node-b"),
+	).Build()
+	rm := NewResourceManager(fakeClient, scheme,
+		NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, fakeClient), nil, nil, nil, nil)
+
+	claim := rm.claimStandbyPod(context.Background(), newPoolClaimTestWorkspace(imageMinimalNotebook))
+
+	require.NotNil(t, claim)
+	assert.Equal(t, testPoolName, claim.PoolName)
+	assert.Equal(t, testNamespaceName, claim.PoolNamespace)
+	assert.Equal(t, "ready", claim.PodName)
+	assert.Equal(t, "node-b", claim.NodeName)
+
+	pod := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(),
+		client.ObjectKey{Name: "ready", Namespace: testNamespaceName}, pod))
+	assert.Equal(t, StandbyStateClaimed, pod.Labels[LabelStandbyState])
+	assert.Equal(t, "user-namespace/claimer", pod.Annotations[AnnotationClaimedBy])
+}
+
+func TestClaimStandbyPod_NoMatchingPod(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	pool := newPoolTestPool(1)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPoolTestStandbyPod(t, scheme, pool, "claimed", imageMinimalNotebook, StandbyStateClaimed, "node-a"),
+	).Build()
+	rm := NewResourceManager(fakeClient, scheme,
+		NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}, fakeClient), nil, nil, nil, nil)
+
+	assert.Nil(t, rm.claimStandbyPod(context.Background(), newPoolClaimTestWorkspace(imageMinimalNotebook)))
+
+	workspace := newPoolClaimTestWorkspace(imageMinimalNotebook)
+	workspace.Labels = nil
+	assert.Nil(t, rm.claimStandbyPod(context.Background(), workspace))
+}
+
+func TestApplyPoolClaimAffinity_PrefersNodeOfClaimedPod(t *testing.T) {
+	workspace := newProvisioningTestWorkspace(nil)
+	workspace.Status.PoolClaim = &workspacev1alpha1.WorkspacePoolClaimStatus{PodName: "standby", NodeName: "node-b"}
+
+	podTemplate := buildProvisioningTestPodTemplate(t, workspace)
+
+	require.NotNil(t, podTemplate.Spec.Affinity)
+	preferred := podTemplate.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	require.Len(t, preferred, 1)
+	assert.Equal(t, poolClaimAffinityWeight, preferred[0].Weight)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: nodeNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-b"}},
+	}, preferred[0].Preference.MatchFields)
+
+	workspace.Status.PoolClaim = nil
+	assert.Nil(t, buildProvisioningTestPodTemplate(t, workspace).Spec.Affinity)
+}
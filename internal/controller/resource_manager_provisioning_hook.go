@@ -0,0 +1,153 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// StorageProvisioningStatus represents the progress of the storage provisioning hook of a workspace
+type StorageProvisioningStatus string
+
+const (
+	// StorageProvisioningNotRequired means the volume was not created with a pending provisioning hook
+	StorageProvisioningNotRequired StorageProvisioningStatus = "NotRequired"
+	// StorageProvisioningRunning means the hook Job has not finished yet
+	StorageProvisioningRunning StorageProvisioningStatus = "Running"
+	// StorageProvisioningSucceeded means the hook completed on the volume
+	StorageProvisioningSucceeded StorageProvisioningStatus = "Succeeded"
+	// StorageProvisioningFailed means the hook Job failed; deleting the Job runs the hook again
+	StorageProvisioningFailed StorageProvisioningStatus = "Failed"
+)
+
+// StorageProvisioningResult holds the progress of the storage provisioning hook
+type StorageProvisioningResult struct {
+	Status  StorageProvisioningStatus
+	Message string
+}
+
+// EnsureStorageProvisioned runs the storage provisioning hook of the workspace on a volume the
+// controller created with a pending hook. Once the hook Job completes, the PVC records it and the Job
+// is deleted, so that the hook never runs again on that volume.
+func (rm *ResourceManager) EnsureStorageProvisioned(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	pvc *corev1.PersistentVolumeClaim) (StorageProvisioningResult, error) {
+	if pvc == nil || workspace.Spec.Storage == nil || workspace.Spec.Storage.ProvisioningHook == nil {
+		return StorageProvisioningResult{Status: StorageProvisioningNotRequired}, nil
+	}
+	switch pvc.Annotations[AnnotationProvisioningHook] {
+	case ProvisioningHookSucceeded:
+		return StorageProvisioningResult{Status: StorageProvisioningSucceeded}, nil
+	case ProvisioningHookPending:
+	default:
+		return StorageProvisioningResult{Status: StorageProvisioningNotRequired}, nil
+	}
+
+	job := &batchv1.Job{}
+	if err := rm.getChild(ctx, workspace, GenerateProvisioningJobName(workspace), job); err != nil {
+		if apierrors.IsNotFound(err) {
+			return rm.createProvisioningJob(ctx, workspace)
+		}
+		return StorageProvisioningResult{}, fmt.Errorf("failed to get provisioning job: %w", err)
+	}
+	if !job.DeletionTimestamp.IsZero() {
+		// A deleted Job is being retried
+		return StorageProvisioningResult{Status: StorageProvisioningRunning}, nil
+	}
+
+	finished, succeeded, message := provisioningJobFinished(job)
+	if !finished {
+		return StorageProvisioningResult{Status: StorageProvisioningRunning}, nil
+	}
+	if !succeeded {
+		return StorageProvisioningResult{
+			Status:  StorageProvisioningFailed,
+			Message: fmt.Sprintf("Storage provisioning hook job %s failed: %s", job.Name, message),
+		}, nil
+	}
+
+	if err := rm.markStorageProvisioned(ctx, pvc); err != nil {
+		return StorageProvisioningResult{}, err
+	}
+	logf.FromContext(ctx).Info("Deleting completed provisioning Job", "job", job.Name, "namespace", job.Namespace)
+	if err := rm.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return StorageProvisioningResult{}, fmt.Errorf("failed to delete provisioning job: %w", err)
+	}
+	return StorageProvisioningResult{Status: StorageProvisioningSucceeded}, nil
+}
+
+// createProvisioningJob creates the Job running the storage provisioning hook of the workspace
+func (rm *ResourceManager) createProvisioningJob(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) (StorageProvisioningResult, error) {
+	job, err := rm.provisioningJobBuilder.BuildProvisioningJob(workspace)
+	if err != nil {
+		return StorageProvisioningResult{}, fmt.Errorf("failed to build provisioning job: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Creating provisioning Job", "job", job.Name, "namespace", job.Namespace)
+	if err := rm.client.Create(ctx, job); err != nil {
+		return StorageProvisioningResult{}, fmt.Errorf("failed to create provisioning job: %w", err)
+	}
+	return StorageProvisioningResult{Status: StorageProvisioningRunning}, nil
+}
+
+// markStorageProvisioned records on the PVC that the provisioning hook completed
+func (rm *ResourceManager) markStorageProvisioned(ctx context.Context, pvc *corev1.PersistentVolumeClaim) error {
+	original := pvc.DeepCopy()
+	pvc.Annotations[AnnotationProvisioningHook] = ProvisioningHookSucceeded
+	if err := rm.client.Patch(ctx, pvc, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to record provisioning hook on PVC: %w", err)
+	}
+	return nil
+}
+
+// setStorageProvisionedCondition reflects the progress of the storage provisioning hook in the
+// StorageProvisioned condition, which is only set on workspaces whose volume went through the hook
+func setStorageProvisionedCondition(ctx context.Context, workspace *workspacev1alpha1.Workspace, result StorageProvisioningResult) {
+	var condition metav1.Condition
+	switch result.Status {
+	case StorageProvisioningNotRequired:
+		removeStorageProvisionedCondition(workspace)
+		return
+	case StorageProvisioningSucceeded:
+		condition = NewCondition(ConditionTypeStorageProvisioned, metav1.ConditionTrue,
+			ReasonProvisioningHookSucceeded, "Storage provisioning hook completed")
+	case StorageProvisioningFailed:
+		condition = NewCondition(ConditionTypeStorageProvisioned, metav1.ConditionFalse,
+			ReasonProvisioningHookFailed, result.Message)
+	default:
+		condition = NewCondition(ConditionTypeStorageProvisioned, metav1.ConditionFalse,
+			ReasonProvisioningHookRunning, "Storage provisioning hook is running")
+	}
+
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+func removeStorageProvisionedCondition(workspace *workspacev1alpha1.Workspace) {
+	if FindCondition(&workspace.Status.Conditions, ConditionTypeStorageProvisioned) == nil {
+		return
+	}
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions)-1)
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeStorageProvisioned {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
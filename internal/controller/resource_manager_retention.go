@@ -0,0 +1,106 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// resolveRetainPolicy returns the storage retain policy of the workspace, Delete by default
+func resolveRetainPolicy(workspace *workspacev1alpha1.Workspace) workspacev1alpha1.StorageRetainPolicy {
+	if workspace.Spec.Storage == nil || workspace.Spec.Storage.RetainPolicy == "" {
+		return workspacev1alpha1.StorageRetainPolicyDelete
+	}
+	return workspace.Spec.Storage.RetainPolicy
+}
+
+// HasPVC returns whether the workspace has a PVC to retain
+func (rm *ResourceManager) HasPVC(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if _, err := rm.getPVC(ctx, workspace); err != nil {
+		if isChildMissing(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get PVC: %w", err)
+	}
+	return true, nil
+}
+
+// ReleasePVC removes the workspace from the owners of its PVC, so that the PVC outlives the workspace,
+// and records the owner of the workspace on the PVC. Returns the name of the released PVC, or an
+// empty name when the workspace controls no PVC.
+func (rm *ResourceManager) ReleasePVC(ctx context.Context, workspace *workspacev1alpha1.Workspace) (string, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	key := types.NamespacedName{Name: GeneratePVCName(workspace), Namespace: workspace.Namespace}
+	if err := rm.client.Get(ctx, key, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get PVC: %w", err)
+	}
+	if owner := metav1.GetControllerOf(pvc); owner == nil || owner.UID != workspace.UID || !pvc.DeletionTimestamp.IsZero() {
+		return "", nil
+	}
+
+	if err := controllerutil.RemoveControllerReference(workspace, pvc, rm.scheme); err != nil {
+		return "", fmt.Errorf("failed to remove controller reference: %w", err)
+	}
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[AnnotationRetainedFor] = workspace.Annotations[AnnotationCreatedBy]
+
+	logf.FromContext(ctx).Info("Releasing PVC", "pvc", pvc.Name, "namespace", pvc.Namespace)
+	if err := rm.client.Update(ctx, pvc); err != nil {
+		return "", fmt.Errorf("failed to release PVC: %w", err)
+	}
+	return pvc.Name, nil
+}
+
+// EnsureRetentionSnapshot creates the WorkspaceSnapshot of the storage of a deleted workspace if it
+// does not exist, and returns it. The snapshot is not owned by the workspace, so that it outlives it.
+func (rm *ResourceManager) EnsureRetentionSnapshot(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.WorkspaceSnapshot, error) {
+	snapshot := &workspacev1alpha1.WorkspaceSnapshot{}
+	key := types.NamespacedName{Name: GenerateRetentionSnapshotName(workspace), Namespace: workspace.Namespace}
+	err := rm.client.Get(ctx, key, snapshot)
+	if err == nil {
+		return snapshot, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get retention snapshot: %w", err)
+	}
+
+	snapshot = &workspacev1alpha1.WorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{LabelWorkspaceName: workspace.Name},
+		},
+		Spec: workspacev1alpha1.WorkspaceSnapshotSpec{
+			WorkspaceName: workspace.Name,
+			Trigger:       workspacev1alpha1.SnapshotTriggerOnDemand,
+		},
+	}
+	if owner := workspace.Annotations[AnnotationCreatedBy]; owner != "" {
+		snapshot.Annotations = map[string]string{AnnotationCreatedBy: owner}
+	}
+
+	logf.FromContext(ctx).Info("Creating retention snapshot", "workspaceSnapshot", snapshot.Name, "namespace", snapshot.Namespace)
+	if err := rm.client.Create(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to create retention snapshot: %w", err)
+	}
+	return snapshot, nil
+}
@@ -6,6 +6,7 @@ Distributed under the terms of the MIT license
 package controller
 
 import (
+	"context"
 	"testing"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
@@ -13,8 +14,13 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -114,3 +120,173 @@ func TestResourceManager_IsWorkspaceAvailable(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceManager_EnsurePVCExists_Expansion(t *testing.T) {
+	const storageClassName = "expandable"
+
+	tests := []struct {
+		name                 string
+		storageClass         *storagev1.StorageClass
+		desiredSize          string
+		expectedRequest      string
+		expectedPhase        workspacev1alpha1.StorageExpansionPhase
+		expectExpansionState bool
+	}{
+		{
+			name: "expands PVC when StorageClass allows expansion",
+			storageClass: &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+				AllowVolumeExpansion: ptr.To(true),
+			},
+			desiredSize:          "20Gi",
+			expectedRequest:      "20Gi",
+			expectedPhase:        workspacev1alpha1.StorageExpansionInProgress,
+			expectExpansionState: true,
+		},
+		{
+			name: "reports unsupported when StorageClass disallows expansion",
+			storageClass: &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+				AllowVolumeExpansion: ptr.To(false),
+			},
+			desiredSize:          "20Gi",
+			expectedRequest:      "10Gi",
+			expectedPhase:        workspacev1alpha1.StorageExpansionUnsupported,
+			expectExpansionState: true,
+		},
+		{
+			name:                 "reports unsupported when StorageClass is missing",
+			desiredSize:          "20Gi",
+			expectedRequest:      "10Gi",
+			expectedPhase:        workspacev1alpha1.StorageExpansionUnsupported,
+			expectExpansionState: true,
+		},
+		{
+			name: "ignores a size decrease",
+			storageClass: &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+				AllowVolumeExpansion: ptr.To(true),
+			},
+			desiredSize:     "5Gi",
+			expectedRequest: "10Gi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, storagev1.AddToScheme(scheme))
+
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{
+						StorageClassName: ptr.To(storageClassName),
+						Size:             resource.MustParse(tt.desiredSize),
+					},
+				},
+				Status: workspacev1alpha1.WorkspaceStatus{
+					Conditions: []metav1.Condition{
+						{Type: ConditionTypeAvailable, Status: metav1.ConditionTrue},
+					},
+				},
+			}
+			existingPVC := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: GeneratePVCName(workspace), Namespace: testNamespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: ptr.To(storageClassName),
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			}
+
+			objs := []client.Object{existingPVC}
+			if tt.storageClass != nil {
+				objs = append(objs, tt.storageClass)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			rm := NewResourceManager(fakeClient, scheme, nil, nil, NewPVCBuilder(scheme), nil, NewStatusManager(fakeClient))
+
+			_, err := rm.EnsurePVCExists(ctx, workspace)
+			require.NoError(t, err)
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{
+				Name: GeneratePVCName(workspace), Namespace: testNamespace,
+			}, pvc))
+			request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			assert.Equal(t, tt.expectedRequest, request.String())
+
+			if !tt.expectExpansionState {
+				assert.Nil(t, workspace.Status.StorageExpansion)
+				return
+			}
+			require.NotNil(t, workspace.Status.StorageExpansion)
+			assert.Equal(t, tt.expectedPhase, workspace.Status.StorageExpansion.Phase)
+			assert.Equal(t, tt.desiredSize, workspace.Status.StorageExpansion.RequestedSize.String())
+		})
+	}
+}
+
+func TestResourceManager_EnsurePVCExists_CloneFrom(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	// The source was created with another resource name prefix than the clone
+	source := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "source",
+			Namespace:   testNamespace,
+			Annotations: map[string]string{AnnotationResourceNamePrefix: "nb"},
+		},
+	}
+	newClone := func(includeStorage bool) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage:   &workspacev1alpha1.StorageSpec{Size: resource.MustParse("5Gi")},
+				CloneFrom: &workspacev1alpha1.CloneSource{Name: "source", IncludeStorage: includeStorage},
+			},
+		}
+	}
+
+	t.Run("includeStorage clones the PVC of the source", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, NewPVCBuilder(scheme), nil, NewStatusManager(fakeClient))
+
+		pvc, err := rm.EnsurePVCExists(ctx, newClone(true))
+		require.NoError(t, err)
+		require.NotNil(t, pvc.Spec.DataSource)
+		assert.Equal(t, "PersistentVolumeClaim", pvc.Spec.DataSource.Kind)
+		assert.Nil(t, pvc.Spec.DataSource.APIGroup)
+		assert.Equal(t, "nb-source-pvc", pvc.Spec.DataSource.Name)
+	})
+
+	t.Run("without includeStorage the clone starts from an empty volume", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, NewPVCBuilder(scheme), nil, NewStatusManager(fakeClient))
+
+		pvc, err := rm.EnsurePVCExists(ctx, newClone(false))
+		require.NoError(t, err)
+		assert.Nil(t, pvc.Spec.DataSource)
+	})
+
+	t.Run("a missing source is an error", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, NewPVCBuilder(scheme), nil, NewStatusManager(fakeClient))
+
+		_, err := rm.EnsurePVCExists(ctx, newClone(true))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get clone source workspace source")
+	})
+}
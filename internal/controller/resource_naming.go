@@ -0,0 +1,186 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// MaxResourceNamePrefixLength bounds the resource name prefix, so that child resource names
+// keep most of the 63 characters of a DNS label for the workspace name
+const MaxResourceNamePrefixLength = 20
+
+// ErrResourceNameCollision is returned when a child resource name of a workspace is taken by a
+// resource another object controls, such as the workspace of another operator installation
+var ErrResourceNameCollision = errors.New("resource name collision")
+
+// resourceNaming is the operator configuration of the child resource names of workspaces
+var resourceNaming = struct {
+	// prefix is recorded on new workspaces
+	prefix string
+	// adoptablePrefixes are the prefixes, besides ResourcePrefix, of existing child resources
+	// that workspaces without a recorded prefix adopt
+	adoptablePrefixes []string
+	// orphanAdoptionDisabled stops workspaces from adopting child resources nothing controls
+	orphanAdoptionDisabled bool
+}{prefix: ResourcePrefix}
+
+// ConfigureResourceNaming sets the prefix of the child resource names of new workspaces, and
+// the prefixes under which workspaces created without a recorded prefix adopt existing child
+// resources. It must be called before the manager starts.
+func ConfigureResourceNaming(prefix string, adoptablePrefixes []string) error {
+	for _, p := range append([]string{prefix}, adoptablePrefixes...) {
+		if errs := validation.IsDNS1123Label(p); len(errs) > 0 {
+			return fmt.Errorf("invalid resource name prefix %q: %v", p, errs)
+		}
+		if len(p) > MaxResourceNamePrefixLength {
+			return fmt.Errorf("invalid resource name prefix %q: must be no more than %d characters", p, MaxResourceNamePrefixLength)
+		}
+	}
+	resourceNaming.prefix = prefix
+	resourceNaming.adoptablePrefixes = adoptablePrefixes
+	return nil
+}
+
+// ConfigureOrphanAdoption sets whether workspaces adopt the child resources under their names that
+// nothing controls, e.g. after a restore from backup or a reinstall of the operator. When disabled,
+// such resources are reported as name collisions. It must be called before the manager starts.
+func ConfigureOrphanAdoption(enabled bool) {
+	resourceNaming.orphanAdoptionDisabled = !enabled
+}
+
+// ConfiguredResourceNamePrefix returns the prefix recorded on new workspaces
+func ConfiguredResourceNamePrefix() string {
+	return resourceNaming.prefix
+}
+
+// ResourceNamePrefix returns the prefix of the child resource names of the workspace: the prefix
+// recorded on the workspace, or ResourcePrefix for workspaces created before it was recorded
+func ResourceNamePrefix(workspace *workspacev1alpha1.Workspace) string {
+	if prefix := workspace.Annotations[AnnotationResourceNamePrefix]; prefix != "" {
+		return prefix
+	}
+	return ResourcePrefix
+}
+
+// resolveResourceNamePrefix returns the prefix to record on a workspace without one: the first of
+// ResourcePrefix and the adoptable prefixes under which the workspace already has a deployment or
+// PVC it can adopt, else the configured prefix
+func resolveResourceNamePrefix(ctx context.Context, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) (string, error) {
+	candidates := append([]string{ResourcePrefix}, resourceNaming.adoptablePrefixes...)
+	for _, prefix := range slices.Compact(candidates) {
+		candidate := workspace.DeepCopy()
+		if candidate.Annotations == nil {
+			candidate.Annotations = map[string]string{}
+		}
+		candidate.Annotations[AnnotationResourceNamePrefix] = prefix
+
+		children := map[string]client.Object{
+			GenerateDeploymentName(candidate): &appsv1.Deployment{},
+			GeneratePVCName(candidate):        &corev1.PersistentVolumeClaim{},
+		}
+		for name, child := range children {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, child)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to look up existing child resource %s: %w", name, err)
+			}
+			if canAdopt(child, workspace) {
+				return prefix, nil
+			}
+		}
+	}
+	return resourceNaming.prefix, nil
+}
+
+// canAdopt returns whether the workspace controls the child resource, or may adopt it because
+// nothing controls it
+func canAdopt(child metav1.Object, workspace *workspacev1alpha1.Workspace) bool {
+	return checkAdoptable(child, child.GetName(), workspace) == nil
+}
+
+// checkAdoptable returns ErrResourceNameCollision unless the workspace controls the child resource,
+// or may adopt it: nothing controls it, orphan adoption is enabled, and the child is not labelled
+// for another workspace
+func checkAdoptable(child metav1.Object, name string, workspace *workspacev1alpha1.Workspace) error {
+	if owner := metav1.GetControllerOf(child); owner != nil {
+		if owner.UID != workspace.UID {
+			return fmt.Errorf("%w: %s is controlled by %s %s", ErrResourceNameCollision, name, owner.Kind, owner.Name)
+		}
+		return nil
+	}
+	if resourceNaming.orphanAdoptionDisabled {
+		return fmt.Errorf("%w: %s is not controlled by the workspace and orphan adoption is disabled",
+			ErrResourceNameCollision, name)
+	}
+	if labelled := child.GetLabels()[LabelWorkspaceName]; labelled != "" && labelled != workspace.Name {
+		return fmt.Errorf("%w: %s is labelled for workspace %s", ErrResourceNameCollision, name, labelled)
+	}
+	return nil
+}
+
+// getChild gets the child resource of the workspace named name. A child nothing controls, left by
+// an earlier installation or another notebook operator, is adopted by the workspace; a child another
+// object controls is reported as ErrResourceNameCollision.
+func (rm *ResourceManager) getChild(ctx context.Context, workspace *workspacev1alpha1.Workspace, name string, child client.Object) error {
+	if err := rm.client.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, child); err != nil {
+		return err
+	}
+	return rm.adoptChild(ctx, workspace, name, child)
+}
+
+// adoptChild adopts the child if nothing controls it, and reports ErrResourceNameCollision if the
+// workspace may not adopt it
+func (rm *ResourceManager) adoptChild(ctx context.Context, workspace *workspacev1alpha1.Workspace, name string, child client.Object) error {
+	if err := checkAdoptable(child, name, workspace); err != nil {
+		return err
+	}
+	if metav1.IsControlledBy(child, workspace) {
+		return nil
+	}
+
+	if err := controllerutil.SetControllerReference(workspace, child, rm.scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	if err := rm.client.Update(ctx, child); err != nil {
+		return fmt.Errorf("failed to adopt %s: %w", name, err)
+	}
+	logf.FromContext(ctx).Info("Adopted existing child resource", "name", name, "namespace", workspace.Namespace)
+	return nil
+}
+
+// adoptExistingChild handles an AlreadyExists error creating the child named name: the child exists
+// although the cache has not listed it yet, e.g. right after the operator restarts. It reads the child
+// from the API server into child and adopts it, instead of retrying the creation until the cache syncs.
+func (rm *ResourceManager) adoptExistingChild(ctx context.Context, workspace *workspacev1alpha1.Workspace, name string, child client.Object) error {
+	if err := rm.apiReader.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, child); err != nil {
+		return fmt.Errorf("failed to get existing %s: %w", name, err)
+	}
+	return rm.adoptChild(ctx, workspace, name, child)
+}
+
+// isChildMissing returns whether err means the workspace has no child resource under the name,
+// either because none exists or because another object controls it
+func isChildMissing(err error) bool {
+	return apierrors.IsNotFound(err) || errors.Is(err, ErrResourceNameCollision)
+}
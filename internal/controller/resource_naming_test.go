@@ -0,0 +1,265 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// configureTestResourceNaming configures the resource naming for the duration of the test
+func configureTestResourceNaming(t *testing.T, prefix string, adoptablePrefixes ...string) {
+	t.Helper()
+	previous := resourceNaming
+	t.Cleanup(func() { resourceNaming = previous })
+	require.NoError(t, ConfigureResourceNaming(prefix, adoptablePrefixes))
+}
+
+func newResourceNamingScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return scheme
+}
+
+func newResourceNamingWorkspace(annotations map[string]string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testWorkspaceName,
+			Namespace:   testNamespace,
+			UID:         "ws-uid",
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestConfigureResourceNaming(t *testing.T) {
+	tests := []struct {
+		name              string
+		prefix            string
+		adoptablePrefixes []string
+		expectError       bool
+	}{
+		{name: "default prefix", prefix: ResourcePrefix},
+		{name: "custom prefix with adoptable prefixes", prefix: "team-a", adoptablePrefixes: []string{"jupyter", "nb"}},
+		{name: "uppercase prefix", prefix: "Team", expectError: true},
+		{name: "empty prefix", prefix: "", expectError: true},
+		{name: "prefix longer than the maximum", prefix: "a-very-long-resource-prefix", expectError: true},
+		{name: "invalid adoptable prefix", prefix: "team-a", adoptablePrefixes: []string{"not_valid"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := resourceNaming
+			t.Cleanup(func() { resourceNaming = previous })
+
+			err := ConfigureResourceNaming(tt.prefix, tt.adoptablePrefixes)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Equal(t, previous.prefix, ConfiguredResourceNamePrefix())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.prefix, ConfiguredResourceNamePrefix())
+		})
+	}
+}
+
+func TestResourceNamePrefix(t *testing.T) {
+	configureTestResourceNaming(t, "team-a")
+
+	// Workspaces created before the prefix was recorded keep the historical names
+	legacy := newResourceNamingWorkspace(nil)
+	assert.Equal(t, ResourcePrefix, ResourceNamePrefix(legacy))
+	assert.Equal(t, "workspace-test-workspace", GenerateDeploymentName(legacy))
+
+	ws := newResourceNamingWorkspace(map[string]string{AnnotationResourceNamePrefix: "nb"})
+	assert.Equal(t, "nb", ResourceNamePrefix(ws))
+	assert.Equal(t, "nb-test-workspace", GenerateDeploymentName(ws))
+	assert.Equal(t, "nb-test-workspace-service", GenerateServiceName(ws))
+	assert.Equal(t, "nb-test-workspace-pvc", GeneratePVCName(ws))
+}
+
+func TestResolveResourceNamePrefix(t *testing.T) {
+	ctx := context.Background()
+	scheme := newResourceNamingScheme(t)
+
+	uncontrolledDeployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace}}
+	}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		expected string
+	}{
+		{
+			name:     "new workspace without children gets the configured prefix",
+			expected: "team-a",
+		},
+		{
+			name:     "workspace with children under the default prefix keeps it",
+			objects:  []client.Object{uncontrolledDeployment("workspace-test-workspace")},
+			expected: ResourcePrefix,
+		},
+		{
+			name: "workspace with a PVC under an adoptable prefix adopts it",
+			objects: []client.Object{&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "jupyter-test-workspace-pvc", Namespace: testNamespace},
+			}},
+			expected: "jupyter",
+		},
+		{
+			name: "children controlled by another object are not adopted",
+			objects: []client.Object{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workspace-test-workspace",
+					Namespace: testNamespace,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "kubeflow.org/v1",
+						Kind:       "Notebook",
+						Name:       testWorkspaceName,
+						UID:        "other-uid",
+						Controller: ptr.To(true),
+					}},
+				},
+			}},
+			expected: "team-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configureTestResourceNaming(t, "team-a", "jupyter")
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			prefix, err := resolveResourceNamePrefix(ctx, fakeClient, newResourceNamingWorkspace(nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, prefix)
+		})
+	}
+}
+
+func TestResourceManager_GetChild(t *testing.T) {
+	ctx := context.Background()
+	scheme := newResourceNamingScheme(t)
+	workspace := newResourceNamingWorkspace(map[string]string{AnnotationResourceNamePrefix: ResourcePrefix})
+	name := GenerateDeploymentName(workspace)
+
+	t.Run("adopts an uncontrolled child", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		}).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+		require.NoError(t, rm.getChild(ctx, workspace, name, &appsv1.Deployment{}))
+
+		adopted := &appsv1.Deployment{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, adopted))
+		owner := metav1.GetControllerOf(adopted)
+		require.NotNil(t, owner)
+		assert.Equal(t, workspace.UID, owner.UID)
+	})
+
+	t.Run("reports a child controlled by another object as a collision", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: workspacev1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					Name:       testWorkspaceName,
+					UID:        "other-uid",
+					Controller: ptr.To(true),
+				}},
+			},
+		}).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+		err := rm.getChild(ctx, workspace, name, &appsv1.Deployment{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrResourceNameCollision))
+		assert.True(t, isChildMissing(err))
+	})
+
+	t.Run("reports an uncontrolled child labelled for another workspace as a collision", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels:    map[string]string{LabelWorkspaceName: "other-workspace"},
+			},
+		}).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+		err := rm.getChild(ctx, workspace, name, &appsv1.Deployment{})
+		assert.True(t, errors.Is(err, ErrResourceNameCollision))
+	})
+
+	t.Run("reports an uncontrolled child as a collision when orphan adoption is disabled", func(t *testing.T) {
+		configureTestResourceNaming(t, ResourcePrefix)
+		ConfigureOrphanAdoption(false)
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		}).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+		err := rm.getChild(ctx, workspace, name, &appsv1.Deployment{})
+		assert.True(t, errors.Is(err, ErrResourceNameCollision))
+		assert.False(t, canAdopt(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}, workspace))
+	})
+
+	t.Run("reports a missing child as not found", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, NewStatusManager(fakeClient))
+
+		err := rm.getChild(ctx, workspace, name, &appsv1.Deployment{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.True(t, isChildMissing(err))
+	})
+}
+
+func TestResourceManager_CreateAdoptsChildMissingFromCache(t *testing.T) {
+	ctx := context.Background()
+	scheme := newResourceNamingScheme(t)
+	workspace := newResourceNamingWorkspace(map[string]string{AnnotationResourceNamePrefix: ResourcePrefix})
+	orphan := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: GenerateServiceName(workspace), Namespace: testNamespace}}
+	apiServer := fake.NewClientBuilder().WithScheme(scheme).WithObjects(orphan).Build()
+	// The cache has not listed the service yet, e.g. right after the operator restarts
+	staleCache := interceptor.NewClient(apiServer, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			return apierrors.NewNotFound(corev1.Resource("services"), key.Name)
+		},
+	})
+	rm := NewResourceManager(staleCache, scheme, nil, NewServiceBuilder(scheme), nil, nil, NewStatusManager(staleCache))
+	rm.apiReader = apiServer
+
+	service, err := rm.EnsureServiceExists(ctx, workspace)
+
+	require.NoError(t, err)
+	assert.Equal(t, orphan.Name, service.Name)
+	adopted := &corev1.Service{}
+	require.NoError(t, apiServer.Get(ctx, client.ObjectKeyFromObject(orphan), adopted))
+	assert.True(t, metav1.IsControlledBy(adopted, workspace))
+}
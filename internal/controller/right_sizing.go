@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// reportRightSizing reports the requests recommended to a workspace after its last right-sizing
+// window in the RightSizingRecommended condition, persisted by the status update that follows. The
+// condition is removed once the workspace has no recommendation, e.g. after its requests changed.
+func (sm *StateMachine) reportRightSizing(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	existing := FindCondition(&workspace.Status.Conditions, ConditionTypeRightSizingRecommended)
+	rightSizing := RightSizingStatus(workspace)
+	if rightSizing == nil || len(rightSizing.RecommendedRequests) == 0 {
+		if existing != nil {
+			removeRightSizingCondition(workspace)
+		}
+		return
+	}
+
+	reason, message := rightSizingRecommendation(rightSizing)
+	if existing == nil || existing.Message != message {
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, ConditionTypeRightSizingRecommended, message)
+	}
+	condition := NewCondition(ConditionTypeRightSizingRecommended, metav1.ConditionTrue, reason, message)
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+// RightSizingStatus returns the right-sizing status of a workspace, or nil when it has none
+func RightSizingStatus(workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.WorkspaceRightSizingStatus {
+	if workspace.Status.Usage == nil {
+		return nil
+	}
+	return workspace.Status.Usage.RightSizing
+}
+
+// rightSizingRecommendation returns the reason and message of the RightSizingRecommended condition,
+// which tell whether the recommended requests are lower, higher or both than the current ones
+func rightSizingRecommendation(rightSizing *workspacev1alpha1.WorkspaceRightSizingStatus) (string, string) {
+	names := make([]string, 0, len(rightSizing.RecommendedRequests))
+	for name := range rightSizing.RecommendedRequests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var lower, higher bool
+	changes := make([]string, 0, len(names))
+	for _, name := range names {
+		recommended := rightSizing.RecommendedRequests[corev1.ResourceName(name)]
+		current := rightSizing.Requests[corev1.ResourceName(name)]
+		if recommended.Cmp(current) < 0 {
+			lower = true
+		} else {
+			higher = true
+		}
+		changes = append(changes, fmt.Sprintf("%s %s (currently %s)", name, recommended.String(), current.String()))
+	}
+
+	reason := ReasonRequestsMismatched
+	switch {
+	case lower && !higher:
+		reason = ReasonOverprovisioned
+	case higher && !lower:
+		reason = ReasonUnderprovisioned
+	}
+	return reason, "Workspace consistently used too little or too much of its requests; recommended requests: " +
+		strings.Join(changes, ", ")
+}
+
+// removeRightSizingCondition drops the RightSizingRecommended condition once no recommendation stands
+func removeRightSizingCondition(workspace *workspacev1alpha1.Workspace) {
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions))
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeRightSizingRecommended {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newRightSizingTestWorkspace(recommended corev1.ResourceList) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "right-sizing", Namespace: testNamespaceName},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			Usage: &workspacev1alpha1.WorkspaceUsageStatus{
+				RightSizing: &workspacev1alpha1.WorkspaceRightSizingStatus{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("2"),
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+					RecommendedRequests: recommended,
+				},
+			},
+		},
+	}
+}
+
+func TestReportRightSizing(t *testing.T) {
+	tests := []struct {
+		name        string
+		recommended corev1.ResourceList
+		reason      string
+	}{
+		{name: "lower requests", reason: ReasonOverprovisioned, recommended: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("300m"),
+		}},
+		{name: "higher requests", reason: ReasonUnderprovisioned, recommended: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("3"),
+			corev1.ResourceMemory: resource.MustParse("6Gi"),
+		}},
+		{name: "mixed requests", reason: ReasonRequestsMismatched, recommended: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("300m"),
+			corev1.ResourceMemory: resource.MustParse("6Gi"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			sm := &StateMachine{recorder: recorder}
+			workspace := newRightSizingTestWorkspace(tt.recommended)
+
+			sm.reportRightSizing(context.Background(), workspace)
+
+			condition := FindCondition(&workspace.Status.Conditions, ConditionTypeRightSizingRecommended)
+			require.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, tt.reason, condition.Reason)
+			assert.Len(t, recorder.Events, 1)
+
+			// The same recommendation is not recorded again
+			sm.reportRightSizing(context.Background(), workspace)
+			assert.Len(t, recorder.Events, 1)
+		})
+	}
+}
+
+func TestReportRightSizing_Message(t *testing.T) {
+	sm := &StateMachine{recorder: record.NewFakeRecorder(10)}
+	workspace := newRightSizingTestWorkspace(corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("6Gi"),
+		corev1.ResourceCPU:    resource.MustParse("300m"),
+	})
+
+	sm.reportRightSizing(context.Background(), workspace)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeRightSizingRecommended)
+	require.NotNil(t, condition)
+	assert.Equal(t, "Workspace consistently used too little or too much of its requests; recommended requests: "+
+		"cpu 300m (currently 2), memory 6Gi (currently 4Gi)", condition.Message)
+}
+
+func TestReportRightSizing_RemovesConditionWithoutRecommendation(t *testing.T) {
+	sm := &StateMachine{recorder: record.NewFakeRecorder(10)}
+	workspace := newRightSizingTestWorkspace(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m")})
+	sm.reportRightSizing(context.Background(), workspace)
+	require.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeRightSizingRecommended))
+
+	workspace.Status.Usage.RightSizing.RecommendedRequests = nil
+	sm.reportRightSizing(context.Background(), workspace)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeRightSizingRecommended))
+
+	// Workspaces without right-sizing are left alone
+	workspace.Status.Usage = nil
+	sm.reportRightSizing(context.Background(), workspace)
+	assert.Empty(t, workspace.Status.Conditions)
+}
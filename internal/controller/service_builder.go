@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -49,25 +50,38 @@ func (sb *ServiceBuilder) BuildService(workspace *workspacev1alpha1.Workspace) (
 // buildObjectMeta creates the metadata for the Service
 func (sb *ServiceBuilder) buildObjectMeta(workspace *workspacev1alpha1.Workspace) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
-		Name:      GenerateServiceName(workspace.Name),
+		Name:      GenerateServiceName(workspace),
 		Namespace: workspace.Namespace,
 		Labels:    GenerateLabels(workspace.Name),
 	}
 }
 
 // buildServiceSpec creates the service specification
+// The "http" port is exposed on JupyterPort whatever the application type, and targets the port
+// the application listens on
 func (sb *ServiceBuilder) buildServiceSpec(workspace *workspacev1alpha1.Workspace) corev1.ServiceSpec {
+	ports := []corev1.ServicePort{
+		{
+			Name:       httpScheme,
+			Port:       JupyterPort,
+			TargetPort: intstr.FromInt32(workspaceutil.GetApplication(workspace).Port),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+	// Additional ports are exposed on the same number they are served on in the pod
+	for _, port := range workspace.Spec.Ports {
+		ports = append(ports, corev1.ServicePort{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: intstr.FromInt32(port.Port),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+
 	return corev1.ServiceSpec{
 		Type:     corev1.ServiceTypeClusterIP,
 		Selector: GenerateLabels(workspace.Name),
-		Ports: []corev1.ServicePort{
-			{
-				Name:       httpScheme,
-				Port:       JupyterPort,
-				TargetPort: intstr.FromInt(JupyterPort),
-				Protocol:   corev1.ProtocolTCP,
-			},
-		},
+		Ports:    ports,
 	}
 }
 
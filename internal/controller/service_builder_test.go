@@ -70,5 +70,32 @@ var _ = Describe("ServiceBuilder", func() {
 			Expect(existingService.Spec.Ports).To(HaveLen(1))
 			Expect(existingService.Spec.Ports[0].Port).To(Equal(int32(JupyterPort)))
 		})
+
+		It("should detect update and expose additional ports", func() {
+			workspace.Spec.Ports = []workspacev1alpha1.WorkspacePort{{Name: "tensorboard", Port: 6006}}
+
+			needsUpdate, err := serviceBuilder.NeedsUpdate(ctx, existingService, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsUpdate).To(BeTrue())
+
+			Expect(serviceBuilder.UpdateServiceSpec(ctx, existingService, workspace)).To(Succeed())
+			Expect(existingService.Spec.Ports).To(HaveLen(2))
+			Expect(existingService.Spec.Ports[0].Name).To(Equal(httpScheme))
+			Expect(existingService.Spec.Ports[1].Name).To(Equal("tensorboard"))
+			Expect(existingService.Spec.Ports[1].Port).To(Equal(int32(6006)))
+			Expect(existingService.Spec.Ports[1].TargetPort.IntValue()).To(Equal(6006))
+		})
+
+		It("should target the port of the application on the http port", func() {
+			workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+			needsUpdate, err := serviceBuilder.NeedsUpdate(ctx, existingService, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsUpdate).To(BeTrue())
+
+			Expect(serviceBuilder.UpdateServiceSpec(ctx, existingService, workspace)).To(Succeed())
+			Expect(existingService.Spec.Ports[0].Port).To(Equal(int32(JupyterPort)))
+			Expect(existingService.Spec.Ports[0].TargetPort.IntValue()).To(Equal(8080))
+		})
 	})
 })
@@ -0,0 +1,100 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// ShardKeyCount is the number of shard keys namespaces are hashed to. A shard owns the keys whose
+// remainder by the shard count is its index, so that the shard count can change without
+// relabelling any workspace.
+const ShardKeyCount = 256
+
+// Sharding splits the Workspaces across manager replicas by a consistent hash of their namespace,
+// so that each replica reconciles the Workspaces of one shard. All the Workspaces of a namespace
+// belong to the same shard. The zero value disables sharding.
+//
+// The replica of shard 0 is the coordinator: it runs the controllers and webhooks that need every
+// Workspace, such as the WorkspaceTemplate and WorkspaceAccessStrategy controllers, and labels the
+// Workspaces with their shard key. The other replicas only watch and reconcile the Workspaces of
+// their shard, selected by label.
+type Sharding struct {
+	// Index is the shard of the replica, from 0 to Count-1
+	Index int
+
+	// Count is the number of shards; 0 or 1 disables sharding
+	Count int
+}
+
+// Enabled tells whether the Workspaces are split across several shards
+func (s Sharding) Enabled() bool {
+	return s.Count > 1
+}
+
+// IsCoordinator tells whether the replica runs the controllers and webhooks that need every
+// Workspace, which is the case of shard 0, and of the only replica when sharding is disabled
+func (s Sharding) IsCoordinator() bool {
+	return !s.Enabled() || s.Index == 0
+}
+
+// Validate checks that the index falls within the shards
+func (s Sharding) Validate() error {
+	if s.Count < 0 || s.Count > ShardKeyCount {
+		return fmt.Errorf("shard count must be between 1 and %d, got %d", ShardKeyCount, s.Count)
+	}
+	if s.Index < 0 || (s.Enabled() && s.Index >= s.Count) || (!s.Enabled() && s.Index != 0) {
+		return fmt.Errorf("shard index must be between 0 and the shard count minus one, got %d", s.Index)
+	}
+	return nil
+}
+
+// OwnsNamespace tells whether the Workspaces of the namespace belong to the shard
+func (s Sharding) OwnsNamespace(namespace string) bool {
+	return !s.Enabled() || shardKey(namespace)%s.Count == s.Index
+}
+
+// Selector selects the Workspaces of the shard by their shard key label. The selectors of the
+// shards are disjoint.
+func (s Sharding) Selector() (labels.Selector, error) {
+	keys := make([]string, 0, ShardKeyCount/max(s.Count, 1)+1)
+	for key := range ShardKeyCount {
+		if !s.Enabled() || key%s.Count == s.Index {
+			keys = append(keys, strconv.Itoa(key))
+		}
+	}
+	requirement, err := labels.NewRequirement(LabelShard, selection.In, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the selector of shard %d: %w", s.Index, err)
+	}
+	return labels.NewSelector().Add(*requirement), nil
+}
+
+// LeaderElectionID returns the leader election lease of the shard, so that each shard elects its
+// own leader among its replicas
+func (s Sharding) LeaderElectionID(base string) string {
+	if !s.Enabled() {
+		return base
+	}
+	return fmt.Sprintf("%s-shard-%d", base, s.Index)
+}
+
+// ShardKey returns the shard key of the Workspaces of a namespace, the value of their LabelShard label
+func ShardKey(namespace string) string {
+	return strconv.Itoa(shardKey(namespace))
+}
+
+// shardKey hashes a namespace to one of the ShardKeyCount shard keys
+func shardKey(namespace string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace))
+	return int(hash.Sum32() % ShardKeyCount)
+}
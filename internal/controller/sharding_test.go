@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestSharding_SelectorsAreDisjointAndCoverAllKeys(t *testing.T) {
+	const count = 3
+	selectors := make([]labels.Selector, count)
+	for index := range count {
+		selector, err := Sharding{Index: index, Count: count}.Selector()
+		require.NoError(t, err)
+		selectors[index] = selector
+	}
+
+	for key := range ShardKeyCount {
+		keyLabels := labels.Set{LabelShard: strconv.Itoa(key)}
+		matches := 0
+		for _, selector := range selectors {
+			if selector.Matches(keyLabels) {
+				matches++
+			}
+		}
+		assert.Equal(t, 1, matches, "shard key %d must belong to exactly one shard", key)
+	}
+}
+
+func TestSharding_OwnsNamespaceMatchesSelector(t *testing.T) {
+	sharding := Sharding{Index: 1, Count: 4}
+	selector, err := sharding.Selector()
+	require.NoError(t, err)
+
+	for i := range 50 {
+		namespace := fmt.Sprintf("team-%d", i)
+		assert.Equal(t, sharding.OwnsNamespace(namespace),
+			selector.Matches(labels.Set{LabelShard: ShardKey(namespace)}), "namespace %s", namespace)
+	}
+}
+
+func TestSharding_Disabled(t *testing.T) {
+	sharding := Sharding{}
+
+	assert.False(t, sharding.Enabled())
+	assert.True(t, sharding.IsCoordinator())
+	assert.True(t, sharding.OwnsNamespace("any"))
+	assert.Equal(t, "lease", sharding.LeaderElectionID("lease"))
+	assert.NoError(t, sharding.Validate())
+}
+
+func TestSharding_LeaderElectionID(t *testing.T) {
+	assert.Equal(t, "lease-shard-0", Sharding{Index: 0, Count: 2}.LeaderElectionID("lease"))
+	assert.Equal(t, "lease-shard-1", Sharding{Index: 1, Count: 2}.LeaderElectionID("lease"))
+	assert.False(t, Sharding{Index: 1, Count: 2}.IsCoordinator())
+}
+
+func TestSharding_Validate(t *testing.T) {
+	assert.NoError(t, Sharding{Index: 2, Count: 3}.Validate())
+	assert.Error(t, Sharding{Index: 3, Count: 3}.Validate())
+	assert.Error(t, Sharding{Index: -1, Count: 3}.Validate())
+	assert.Error(t, Sharding{Index: 1, Count: 1}.Validate())
+	assert.Error(t, Sharding{Index: 0, Count: ShardKeyCount + 1}.Validate())
+}
+
+func TestShardKey_IsStable(t *testing.T) {
+	assert.Equal(t, ShardKey(testNamespaceName), ShardKey(testNamespaceName))
+	key, err := strconv.Atoi(ShardKey(testNamespaceName))
+	require.NoError(t, err)
+	assert.Less(t, key, ShardKeyCount)
+}
+
+func TestWorkspaceShardReconciler_LabelsWorkspace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws",
+			Namespace: testNamespaceName,
+			Labels:    map[string]string{LabelShard: "stale"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	reconciler := &WorkspaceShardReconciler{Client: fakeClient, Scheme: scheme}
+	key := types.NamespacedName{Name: "ws", Namespace: testNamespaceName}
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	updated := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), key, updated))
+	assert.Equal(t, ShardKey(testNamespaceName), updated.Labels[LabelShard])
+}
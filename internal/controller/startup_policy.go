@@ -0,0 +1,155 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// StartupPolicyResult carries the outcome of checking a starting workspace against its startup
+// deadline and failure policy
+type StartupPolicyResult struct {
+	// GiveUp is true when the controller stops retrying the workspace
+	GiveUp  bool
+	Reason  string
+	Message string
+
+	// backoff is the delay before checking a failing workspace again, zero when it is not failing
+	backoff time.Duration
+	// deadlineRemaining is the time left before the startup deadline, zero without deadline
+	deadlineRemaining time.Duration
+}
+
+// RequeueAfter returns the delay before the next check of the starting workspace: the failure
+// backoff instead of requeueDelay once containers restart, and never after the startup deadline
+func (r StartupPolicyResult) RequeueAfter(requeueDelay time.Duration) time.Duration {
+	if r.backoff > 0 {
+		requeueDelay = r.backoff
+	}
+	if r.deadlineRemaining > 0 && r.deadlineRemaining < requeueDelay {
+		requeueDelay = r.deadlineRemaining
+	}
+	return requeueDelay
+}
+
+// checkStartupPolicy checks a starting workspace against spec.startupDeadlineSeconds and
+// spec.failurePolicy. It tracks the startup in workspace.Status.Startup in memory, restarting it
+// when the spec of the workspace changes; the status update that follows persists it.
+// Restarts are read from workspace.Status.PodStatus, which must be resolved first.
+func checkStartupPolicy(workspace *workspacev1alpha1.Workspace, now time.Time) StartupPolicyResult {
+	startup := workspace.Status.Startup
+	if startup == nil || startup.ObservedGeneration != workspace.Generation {
+		startup = &workspacev1alpha1.WorkspaceStartupStatus{
+			StartTime:          metav1.NewTime(now),
+			ObservedGeneration: workspace.Generation,
+		}
+		workspace.Status.Startup = startup
+	}
+	// Keep the highest count, so that deleting a crashing pod does not reset the retries
+	if restarts := maxContainerRestarts(workspace.Status.PodStatus); restarts > startup.Retries {
+		startup.Retries = restarts
+	}
+
+	result := StartupPolicyResult{}
+	if deadlineSeconds := workspace.Spec.StartupDeadlineSeconds; deadlineSeconds != nil {
+		deadline := time.Duration(*deadlineSeconds) * time.Second
+		elapsed := now.Sub(startup.StartTime.Time)
+		if elapsed >= deadline {
+			return StartupPolicyResult{
+				GiveUp:  true,
+				Reason:  ReasonStartupDeadlineExceeded,
+				Message: fmt.Sprintf("Workspace did not become available within %ds", *deadlineSeconds),
+			}
+		}
+		result.deadlineRemaining = deadline - elapsed
+	}
+
+	policy := workspace.Spec.FailurePolicy
+	if policy == nil || startup.Retries == 0 {
+		return result
+	}
+	retryLimit := resolveRetryLimit(policy)
+	if startup.Retries > retryLimit {
+		message := fmt.Sprintf("Workspace containers restarted %d times, more than the retry limit of %d",
+			startup.Retries, retryLimit)
+		if reason := lastContainerFailureReason(workspace.Status.PodStatus); reason != "" {
+			message = fmt.Sprintf("%s (%s)", message, reason)
+		}
+		return StartupPolicyResult{
+			GiveUp:  true,
+			Reason:  ReasonRetryLimitExceeded,
+			Message: message,
+		}
+	}
+	result.backoff = time.Duration(failureBackoffSeconds(policy, startup.Retries)) * time.Second
+	return result
+}
+
+// maxContainerRestarts returns the highest restart count of the containers of the workspace pod
+func maxContainerRestarts(podStatus *workspacev1alpha1.WorkspacePodStatus) int32 {
+	if podStatus == nil {
+		return 0
+	}
+	var restarts int32
+	for _, container := range podStatus.Containers {
+		restarts = max(restarts, container.RestartCount)
+	}
+	return restarts
+}
+
+// lastContainerFailureReason returns why the container that restarted the most is failing,
+// e.g. CrashLoopBackOff or OOMKilled
+func lastContainerFailureReason(podStatus *workspacev1alpha1.WorkspacePodStatus) string {
+	if podStatus == nil {
+		return ""
+	}
+	var failing *workspacev1alpha1.WorkspaceContainerStatus
+	for i := range podStatus.Containers {
+		container := &podStatus.Containers[i]
+		if failing == nil || container.RestartCount > failing.RestartCount {
+			failing = container
+		}
+	}
+	if failing == nil {
+		return ""
+	}
+	if failing.LastTerminationReason != "" {
+		return fmt.Sprintf("container %s: %s", failing.Name, failing.LastTerminationReason)
+	}
+	if failing.Reason != "" {
+		return fmt.Sprintf("container %s: %s", failing.Name, failing.Reason)
+	}
+	return ""
+}
+
+func resolveRetryLimit(policy *workspacev1alpha1.FailurePolicySpec) int32 {
+	if policy.RetryLimit > 0 {
+		return policy.RetryLimit
+	}
+	return DefaultFailurePolicyRetryLimit
+}
+
+// failureBackoffSeconds returns the delay before checking a failing workspace again: the backoff
+// of the policy, doubled at each restart after the first, capped by its max backoff
+func failureBackoffSeconds(policy *workspacev1alpha1.FailurePolicySpec, retries int32) int32 {
+	backoff := policy.BackoffSeconds
+	if backoff <= 0 {
+		backoff = DefaultFailurePolicyBackoffSeconds
+	}
+	maxBackoff := policy.MaxBackoffSeconds
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultFailurePolicyMaxBackoffSeconds
+	}
+	for i := int32(1); i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func newStartupPolicyTestWorkspace(restarts int32) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", Generation: 1},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			PodStatus: &workspacev1alpha1.WorkspacePodStatus{
+				Name: "ws-pod",
+				Containers: []workspacev1alpha1.WorkspaceContainerStatus{
+					{Name: "init", Init: true, State: workspacev1alpha1.WorkspaceContainerStateTerminated},
+					{
+						Name:                  "workspace",
+						State:                 workspacev1alpha1.WorkspaceContainerStateWaiting,
+						Reason:                "CrashLoopBackOff",
+						RestartCount:          restarts,
+						LastTerminationReason: "OOMKilled",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckStartupPolicy_NoPolicyNeverGivesUp(t *testing.T) {
+	now := time.Now()
+	workspace := newStartupPolicyTestWorkspace(100)
+
+	result := checkStartupPolicy(workspace, now)
+
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter(PollRequeueDelay))
+	require.NotNil(t, workspace.Status.Startup)
+	assert.Equal(t, now.Unix(), workspace.Status.Startup.StartTime.Unix())
+	assert.Equal(t, int64(1), workspace.Status.Startup.ObservedGeneration)
+	assert.Equal(t, int32(100), workspace.Status.Startup.Retries)
+}
+
+func TestCheckStartupPolicy_StartupDeadline(t *testing.T) {
+	now := time.Now()
+	workspace := newStartupPolicyTestWorkspace(0)
+	workspace.Spec.StartupDeadlineSeconds = ptr.To(int32(300))
+
+	result := checkStartupPolicy(workspace, now)
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter(PollRequeueDelay))
+	assert.Equal(t, 300*time.Second, result.RequeueAfter(time.Hour), "requeues no later than the deadline")
+
+	result = checkStartupPolicy(workspace, now.Add(299*time.Second))
+	assert.False(t, result.GiveUp)
+
+	result = checkStartupPolicy(workspace, now.Add(300*time.Second))
+	assert.True(t, result.GiveUp)
+	assert.Equal(t, ReasonStartupDeadlineExceeded, result.Reason)
+	assert.Equal(t, "Workspace did not become available within 300s", result.Message)
+}
+
+func TestCheckStartupPolicy_SpecChangeRestartsStartup(t *testing.T) {
+	now := time.Now()
+	workspace := newStartupPolicyTestWorkspace(0)
+	workspace.Spec.StartupDeadlineSeconds = ptr.To(int32(300))
+	checkStartupPolicy(workspace, now)
+
+	// e.g. the image is fixed after the deadline expired
+	workspace.Generation = 2
+	result := checkStartupPolicy(workspace, now.Add(time.Hour))
+
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, int64(2), workspace.Status.Startup.ObservedGeneration)
+	assert.Equal(t, now.Add(time.Hour).Unix(), workspace.Status.Startup.StartTime.Unix())
+}
+
+func TestCheckStartupPolicy_BacksOffThenGivesUp(t *testing.T) {
+	now := time.Now()
+	workspace := newStartupPolicyTestWorkspace(0)
+	workspace.Spec.FailurePolicy = &workspacev1alpha1.FailurePolicySpec{RetryLimit: 3}
+
+	result := checkStartupPolicy(workspace, now)
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter(PollRequeueDelay), "no backoff before a restart")
+
+	workspace.Status.PodStatus.Containers[1].RestartCount = 1
+	result = checkStartupPolicy(workspace, now)
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, 10*time.Second, result.RequeueAfter(PollRequeueDelay))
+
+	workspace.Status.PodStatus.Containers[1].RestartCount = 3
+	result = checkStartupPolicy(workspace, now)
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, 40*time.Second, result.RequeueAfter(PollRequeueDelay))
+
+	workspace.Status.PodStatus.Containers[1].RestartCount = 4
+	result = checkStartupPolicy(workspace, now)
+	assert.True(t, result.GiveUp)
+	assert.Equal(t, ReasonRetryLimitExceeded, result.Reason)
+	assert.Equal(t,
+		"Workspace containers restarted 4 times, more than the retry limit of 3 (container workspace: OOMKilled)",
+		result.Message)
+}
+
+func TestCheckStartupPolicy_RetriesSurvivePodReplacement(t *testing.T) {
+	now := time.Now()
+	workspace := newStartupPolicyTestWorkspace(3)
+	workspace.Spec.FailurePolicy = &workspacev1alpha1.FailurePolicySpec{RetryLimit: 3}
+	checkStartupPolicy(workspace, now)
+
+	// The progressing watchdog deleted the crashing pod
+	workspace.Status.PodStatus = nil
+	result := checkStartupPolicy(workspace, now)
+
+	assert.False(t, result.GiveUp)
+	assert.Equal(t, int32(3), workspace.Status.Startup.Retries)
+	assert.Equal(t, 40*time.Second, result.RequeueAfter(PollRequeueDelay))
+}
+
+func TestFailureBackoffSeconds(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  workspacev1alpha1.FailurePolicySpec
+		retries int32
+		want    int32
+	}{
+		{name: "default first retry", retries: 1, want: 10},
+		{name: "default doubles", retries: 4, want: 80},
+		{name: "default cap", retries: 20, want: 300},
+		{name: "custom", policy: workspacev1alpha1.FailurePolicySpec{BackoffSeconds: 5, MaxBackoffSeconds: 30}, retries: 3, want: 20},
+		{name: "custom cap", policy: workspacev1alpha1.FailurePolicySpec{BackoffSeconds: 5, MaxBackoffSeconds: 30}, retries: 4, want: 30},
+		{name: "backoff above cap", policy: workspacev1alpha1.FailurePolicySpec{BackoffSeconds: 60, MaxBackoffSeconds: 30}, retries: 1, want: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, failureBackoffSeconds(&tt.policy, tt.retries))
+		})
+	}
+}
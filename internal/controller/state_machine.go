@@ -8,10 +8,15 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/accounting"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -33,6 +38,30 @@ type StateMachine struct {
 	recorder            record.EventRecorder
 	idleChecker         *WorkspaceIdleChecker
 	accessStartupProber AccessStartupProberInterface
+	progressingWatchdog *ProgressingWatchdog
+
+	// applicationProber probes the additional applications of running workspaces; nil disables it
+	applicationProber ApplicationProberInterface
+
+	// nodeInterruptionHandler reschedules workspaces from interrupted nodes; nil disables it
+	nodeInterruptionHandler *NodeInterruptionHandler
+
+	// usageAccountant accrues the usage of running workspaces; nil disables usage accounting
+	usageAccountant *accounting.Accountant
+
+	// usageSampler samples the live resource usage of running workspaces; nil disables it
+	usageSampler *accounting.Sampler
+
+	// idleNotifier notifies the owners of idle workspaces before they are stopped; nil stops
+	// idle workspaces right away
+	idleNotifier IdleNotifierInterface
+
+	// templateComplianceChecker reports the template violations of workspaces; nil disables the check
+	templateComplianceChecker TemplateComplianceChecker
+
+	// workspaceSnapshotsEnabled tells whether the WorkspaceSnapshot controller runs, which the
+	// Snapshot storage retain policy requires
+	workspaceSnapshotsEnabled bool
 }
 
 // NewStateMachine creates a new StateMachine
@@ -42,6 +71,14 @@ func NewStateMachine(
 	recorder record.EventRecorder,
 	idleChecker *WorkspaceIdleChecker,
 	accessStartupProber AccessStartupProberInterface,
+	progressingWatchdog *ProgressingWatchdog,
+	applicationProber ApplicationProberInterface,
+	nodeInterruptionHandler *NodeInterruptionHandler,
+	usageAccountant *accounting.Accountant,
+	usageSampler *accounting.Sampler,
+	idleNotifier IdleNotifierInterface,
+	templateComplianceChecker TemplateComplianceChecker,
+	workspaceSnapshotsEnabled bool,
 ) *StateMachine {
 	return &StateMachine{
 		resourceManager:     resourceManager,
@@ -49,6 +86,15 @@ func NewStateMachine(
 		recorder:            recorder,
 		idleChecker:         idleChecker,
 		accessStartupProber: accessStartupProber,
+		progressingWatchdog: progressingWatchdog,
+		applicationProber:   applicationProber,
+
+		nodeInterruptionHandler:   nodeInterruptionHandler,
+		usageAccountant:           usageAccountant,
+		usageSampler:              usageSampler,
+		idleNotifier:              idleNotifier,
+		templateComplianceChecker: templateComplianceChecker,
+		workspaceSnapshotsEnabled: workspaceSnapshotsEnabled,
 	}
 }
 
@@ -62,16 +108,33 @@ func (sm *StateMachine) ReconcileDesiredState(
 	desiredStatus := sm.getDesiredStatus(workspace)
 	snapshotStatus := workspace.DeepCopy().Status
 
+	// Expose the effective culling policy; persisted by whichever status update runs below
+	workspace.Status.Culling = resolveCullingStatus(workspace, time.Now())
+
+	// Flag violations of the current template constraints; persisted the same way
+	templateErr := sm.reportTemplateCompliance(ctx, workspace)
+
 	switch desiredStatus {
 	case DesiredStateStopped:
-		return sm.reconcileDesiredStoppedStatus(ctx, workspace, &snapshotStatus)
+		return sm.reconcileDesiredStoppedStatus(ctx, workspace, &snapshotStatus, nil, false)
+	case DesiredStateHibernated:
+		return sm.reconcileDesiredStoppedStatus(ctx, workspace, &snapshotStatus, accessStrategy, true)
 	case DesiredStateRunning:
+		if templateErr != nil {
+			// The template watch triggers a reconcile once the template exists
+			if statusErr := sm.statusManager.UpdateErrorStatus(
+				ctx, workspace, ReasonTemplateNotFound, errs.UserMessage(templateErr), &snapshotStatus); statusErr != nil {
+				logger.Error(statusErr, "Failed to update error status")
+				return ctrl.Result{}, statusErr
+			}
+			return ctrl.Result{}, nil
+		}
 		return sm.reconcileDesiredRunningStatus(ctx, workspace, &snapshotStatus, accessStrategy)
 	default:
-		err := fmt.Errorf("unknown desired status: %s", desiredStatus)
+		err := errs.Internal(nil, "unknown desired status: %s", desiredStatus)
 		// Update error condition
 		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, err.Error(), &snapshotStatus); statusErr != nil {
+			ctx, workspace, ReasonDeploymentError, errs.UserMessage(err), &snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 			return ctrl.Result{}, err
 		}
@@ -87,21 +150,48 @@ func (sm *StateMachine) getDesiredStatus(workspace *workspacev1alpha1.Workspace)
 	return workspace.Spec.DesiredStatus
 }
 
+// IsStoppedDesiredStatus returns true when the desired status removes the compute of the
+// workspace, i.e. Stopped or Hibernated
+func IsStoppedDesiredStatus(desiredStatus string) bool {
+	return desiredStatus == DesiredStateStopped || desiredStatus == DesiredStateHibernated
+}
+
 // GetAccessStrategyForWorkspace retrieves the AccessStrategy for a workspace
 func (sm *StateMachine) GetAccessStrategyForWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.WorkspaceAccessStrategy, error) {
 	return sm.resourceManager.GetAccessStrategyForWorkspace(ctx, workspace)
 }
 
+// reconcileDesiredStoppedStatus deletes the compute and access resources of the workspace.
+// When hibernate is set, the workspace is reported as Hibernated once they are gone, and keeps
+// the access URL resolved from accessStrategy as a placeholder.
 func (sm *StateMachine) reconcileDesiredStoppedStatus(
 	ctx context.Context,
 	workspace *workspacev1alpha1.Workspace,
-	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	hibernate bool) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
-	logger.Info("Attempting to bring Workspace status to 'Stopped'")
+	if hibernate {
+		logger.Info("Attempting to bring Workspace status to 'Hibernated'")
+	} else {
+		logger.Info("Attempting to bring Workspace status to 'Stopped'")
+	}
+	// The progressing watchdog only watches starting workspaces
+	removeStalledCondition(workspace)
+	removeScaleUpPendingCondition(workspace)
+	removeReschedulingCondition(workspace)
+	sm.usageAccountant.Stop(workspace)
+	sm.usageSampler.Clear(workspace)
 
 	// Remove access strategy resources first
-	accessError := sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace)
+	var accessError error
+	if hibernate {
+		accessError = sm.ReconcileAccessForDesiredHibernatedStatus(ctx, workspace, accessStrategy)
+	} else {
+		accessError = sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace)
+	}
 	if accessError != nil {
+		accessError = errs.Internal(accessError, "failed to remove access resources")
 		logger.Error(accessError, "Failed to remove access strategy resources")
 		// Continue with deletion of other resources, don't block on access strategy
 	}
@@ -111,10 +201,10 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 	// It does not wait for the deployment to be fully removed
 	deployment, deploymentErr := sm.resourceManager.EnsureDeploymentDeleted(ctx, workspace)
 	if deploymentErr != nil {
-		err := fmt.Errorf("failed to get deployment: %w", deploymentErr)
+		err := errs.Internal(deploymentErr, "failed to get deployment")
 		// Update error condition
 		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, err.Error(), snapshotStatus); statusErr != nil {
+			ctx, workspace, ReasonDeploymentError, errs.UserMessage(err), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
 		return ctrl.Result{}, err
@@ -125,10 +215,10 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 	// It does not wait for the service to be fully removed
 	service, serviceErr := sm.resourceManager.EnsureServiceDeleted(ctx, workspace)
 	if serviceErr != nil {
-		err := fmt.Errorf("failed to get service: %w", serviceErr)
+		err := errs.Internal(serviceErr, "failed to get service")
 		// Update error condition
 		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonServiceError, err.Error(), snapshotStatus); statusErr != nil {
+			ctx, workspace, ReasonServiceError, errs.UserMessage(err), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
 		return ctrl.Result{}, err
@@ -144,7 +234,7 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 		// Flag as Error if AccessResources failed to delete
 		if accessError != nil {
 			if statusErr := sm.statusManager.UpdateErrorStatus(
-				ctx, workspace, ReasonServiceError, accessError.Error(), snapshotStatus); statusErr != nil {
+				ctx, workspace, ReasonAccessResourceFailed, errs.UserMessage(accessError), snapshotStatus); statusErr != nil {
 				logger.Error(statusErr, "Failed to update error status")
 			}
 			return ctrl.Result{}, accessError
@@ -159,6 +249,14 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 				return ctrl.Result{}, err
 			}
 			return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+		} else if hibernate {
+			logger.Info("Deployment and Service are both deleted, updating to Hibernated status")
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceHibernated", "Workspace has been hibernated")
+
+			if err := sm.statusManager.UpdateHibernatedStatus(ctx, workspace, snapshotStatus); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
 		} else {
 			// All resources are fully deleted, update to stopped status
 			logger.Info("Deployment and Service are both deleted, updating to Stopped status")
@@ -195,10 +293,10 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 	}
 
 	// This should not happen, return an error
-	err := fmt.Errorf("unexpected state: both deployment and service should be in deletion process")
+	err := errs.Internal(nil, "unexpected state: both deployment and service should be in deletion process")
 	// Update error condition
 	if statusErr := sm.statusManager.UpdateErrorStatus(
-		ctx, workspace, ReasonDeploymentError, err.Error(), snapshotStatus); statusErr != nil {
+		ctx, workspace, ReasonDeploymentError, errs.UserMessage(err), snapshotStatus); statusErr != nil {
 		logger.Error(statusErr, "Failed to update error status")
 	}
 	return ctrl.Result{}, err
@@ -211,38 +309,114 @@ func (sm *StateMachine) reconcileDesiredRunningStatus(
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 	logger.Info("Attempting to bring Workspace status to 'Running'")
+	// Drop the drift found by a reconciliation that did not complete
+	sm.resourceManager.takeDrift(workspace)
 
 	// Ensure PVC exists first (if storage is configured)
-	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
 	if err != nil {
-		pvcErr := fmt.Errorf("failed to ensure PVC exists: %w", err)
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, pvcErr.Error(), snapshotStatus); statusErr != nil {
+		pvcErr := errs.Internal(err, "failed to ensure PVC exists")
+		if statusErr := sm.statusManager.UpdateErrorStatus(ctx, workspace,
+			degradedReasonForError(err, ReasonStorageProvisioning), errs.UserMessage(pvcErr), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
 		return ctrl.Result{}, pvcErr
 	}
 
+	// Isolate the pod before it starts (if network isolation is configured)
+	if err := sm.resourceManager.EnsureNetworkPolicy(ctx, workspace); err != nil {
+		policyErr := errs.Internal(err, "failed to ensure network policy")
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonNetworkPolicyError, errs.UserMessage(policyErr), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return ctrl.Result{}, policyErr
+	}
+
+	// Generate the Jupyter configuration before the pod mounts it (if a Jupyter server config is set)
+	if err := sm.resourceManager.EnsureJupyterConfigMap(ctx, workspace); err != nil {
+		configErr := errs.Internal(err, "failed to ensure jupyter config map")
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonJupyterConfigError, errs.UserMessage(configErr), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return ctrl.Result{}, configErr
+	}
+
+	// Generate the token of the JupyterHub activity API before the pod references it (if hub activity detection is enabled)
+	if err := sm.resourceManager.EnsureHubActivityToken(ctx, workspace); err != nil {
+		tokenErr := errs.Internal(err, "failed to ensure hub activity token")
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonHubActivityTokenError, errs.UserMessage(tokenErr), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return ctrl.Result{}, tokenErr
+	}
+
+	// Prepare a new volume before the workspace pod mounts it (if a provisioning hook is configured)
+	provisioning, err := sm.resourceManager.EnsureStorageProvisioned(ctx, workspace, pvc)
+	if err != nil {
+		hookErr := errs.Internal(err, "failed to run storage provisioning hook")
+		if statusErr := sm.statusManager.UpdateErrorStatus(ctx, workspace,
+			degradedReasonForError(err, ReasonStorageProvisioning), errs.UserMessage(hookErr), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return ctrl.Result{}, hookErr
+	}
+	setStorageProvisionedCondition(ctx, workspace, provisioning)
+	switch provisioning.Status {
+	case StorageProvisioningFailed:
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonProvisioningHookFailed, provisioning.Message)
+		if statusErr := sm.statusManager.UpdatePermanentDegradedRunningStatus(
+			ctx, workspace, ReasonStorageProvisioning, ReasonStorageNotReady, provisioning.Message,
+			snapshotStatus); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		// Deleting the failed Job triggers a reconcile that runs the hook again
+		return ctrl.Result{}, nil
+	case StorageProvisioningRunning:
+		if err := sm.statusManager.UpdateStartingStatus(
+			ctx, workspace, WorkspaceRunningReadiness{storageProvisioning: true}, snapshotStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+		// The Job is watched, this only guards against a missed event
+		return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+	}
+
 	// EnsureDeploymentExists creates deployment if missing, or returns existing deployment
 	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, accessStrategy)
 	if err != nil {
-		deployErr := fmt.Errorf("failed to ensure deployment exists: %w", err)
+		deployErr := errs.Internal(err, "failed to ensure deployment exists")
 		// Update error condition
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, deployErr.Error(), snapshotStatus); statusErr != nil {
+		if statusErr := sm.statusManager.UpdateErrorStatus(ctx, workspace,
+			degradedReasonForError(err, ReasonDeploymentError), errs.UserMessage(deployErr), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
 		return ctrl.Result{}, deployErr
 	}
+	// Expose the resolved pod configuration; persisted by whichever status update runs below
+	workspace.Status.EffectiveSpec = resolveEffectiveSpec(workspace, deployment)
+	// Explain why the pod does not start; a failure to list pods must not block the reconcile
+	if podStatus, err := sm.resourceManager.GetWorkspacePodStatus(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to resolve workspace pod status")
+	} else {
+		workspace.Status.PodStatus = podStatus
+	}
+	updateScaleUpPendingCondition(ctx, workspace)
+	// Move the workspace off an interrupted node before the node goes away; failures must not
+	// block the reconcile
+	if err := sm.nodeInterruptionHandler.Check(ctx, workspace); err != nil {
+		logger.Error(err, "Node interruption handler failed")
+	}
 
 	// Ensure service exists
 	// EnsureServiceExists internally fetches the service and returns it with current status
 	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
 	if err != nil {
-		serviceErr := fmt.Errorf("failed to ensure service exists: %w", err)
+		serviceErr := errs.Internal(err, "failed to ensure service exists")
 		// Update error condition
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonServiceError, serviceErr.Error(), snapshotStatus); statusErr != nil {
+		if statusErr := sm.statusManager.UpdateErrorStatus(ctx, workspace,
+			degradedReasonForError(err, ReasonServiceError), errs.UserMessage(serviceErr), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
 		return ctrl.Result{}, serviceErr
@@ -259,50 +433,102 @@ func (sm *StateMachine) reconcileDesiredRunningStatus(
 	// update — it does not verify that the access route is actually serving traffic.
 	accessResourcesReady := false
 	requeueDelay := PollRequeueDelay
-	if deploymentReady && serviceReady {
-		if err := sm.ReconcileAccessForDesiredRunningStatus(ctx, workspace, service, accessStrategy); err != nil {
+	var migrationTarget *workspacev1alpha1.WorkspaceAccessStrategy
+	if deploymentReady && serviceReady && !workspaceutil.IsAccessEnabled(workspace) {
+		// Access is cut off: remove the access resources but keep the workspace running
+		if err := sm.ReconcileAccessForDisabledAccess(ctx, workspace); err != nil {
 			return ctrl.Result{}, err
 		}
+		sm.reportDrift(ctx, workspace)
+		accessResourcesReady = true
+	} else if deploymentReady && serviceReady {
+		migrationTarget = sm.GetAccessStrategyMigrationTarget(ctx, workspace)
+		if err := sm.ReconcileAccessForDesiredRunningStatus(ctx, workspace, service, accessStrategy, migrationTarget); err != nil {
+			if statusErr := sm.statusManager.UpdateErrorStatus(
+				ctx, workspace, ReasonAccessResourceFailed, errs.UserMessage(err), snapshotStatus); statusErr != nil {
+				logger.Error(statusErr, "Failed to update error status")
+			}
+			return ctrl.Result{}, err
+		}
+		sm.reportDrift(ctx, workspace)
 
-		// Gate on access startup probe before marking Available.
-		probeResult, probeErr := sm.ProbeAccessStartup(ctx, workspace, accessStrategy, service)
-		if probeErr != nil {
-			return ctrl.Result{}, probeErr
-		}
-
-		switch probeResult.Status {
-		case ProbeNotDefined:
-			accessResourcesReady = true
-		case ProbeSucceeded:
-			accessResourcesReady = true
-		case ProbeAlreadySucceeded:
-			accessResourcesReady = true
-		case ProbeFailureThresholdExceeded:
-			if statusErr := sm.statusManager.UpdatePermanentDegradedRunningStatus(
-				ctx, workspace, ReasonAccessProbeThresholdExceeded, ReasonAccessNotReady,
-				"Access startup probe failed: threshold exceeded",
-				snapshotStatus); statusErr != nil {
-				return ctrl.Result{}, statusErr
+		// Gate on the readiness of the access resources, e.g. the address of an Ingress, then on
+		// the access startup probe, before marking Available.
+		accessReady, readinessErr := sm.ReconcileAccessReadiness(ctx, workspace, accessStrategy)
+		if readinessErr != nil {
+			return ctrl.Result{}, readinessErr
+		}
+		if accessReady {
+			probeResult, probeErr := sm.ProbeAccessStartup(ctx, workspace, accessStrategy, service)
+			if probeErr != nil {
+				return ctrl.Result{}, probeErr
+			}
+			setReachableCondition(ctx, workspace, probeResult.Status)
+
+			switch probeResult.Status {
+			case ProbeNotDefined:
+				accessResourcesReady = true
+			case ProbeSucceeded:
+				accessResourcesReady = true
+			case ProbeAlreadySucceeded:
+				accessResourcesReady = true
+			case ProbeFailureThresholdExceeded:
+				if statusErr := sm.statusManager.UpdatePermanentDegradedRunningStatus(
+					ctx, workspace, ReasonAccessProbeThresholdExceeded, ReasonAccessNotReady,
+					"Access startup probe failed: threshold exceeded",
+					snapshotStatus); statusErr != nil {
+					return ctrl.Result{}, statusErr
+				}
+				// After status update, exit and stop requeuing
+				return ctrl.Result{}, nil
+			case ProbeRetrying:
+				requeueDelay = probeResult.RequeueAfter
+			case ProbePendingRetry:
+				requeueDelay = probeResult.RequeueAfter
 			}
-			// After status update, exit and stop requeuing
-			return ctrl.Result{}, nil
-		case ProbeRetrying:
-			requeueDelay = probeResult.RequeueAfter
-		case ProbePendingRetry:
-			requeueDelay = probeResult.RequeueAfter
 		}
 	}
 
 	if deploymentReady && serviceReady && accessResourcesReady {
+		// Switch to the migration target once it is reachable
+		migrationPending, err := sm.ReconcileAccessStrategyMigration(ctx, workspace, migrationTarget, service)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		logger.Info("Deployment and Service are both ready, updating to Running status")
+		removeStalledCondition(workspace)
 		sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceRunning", "Workspace is now running")
+		sm.usageAccountant.Record(workspace)
+		sm.usageSampler.Sample(ctx, workspace)
+		sm.reportRightSizing(ctx, workspace)
+		applicationsProbed := sm.reportApplications(ctx, workspace, service)
 
 		if err := sm.statusManager.UpdateRunningStatus(ctx, workspace, snapshotStatus); err != nil {
 			return ctrl.Result{}, err
 		}
 
 		// Handle idle shutdown for running workspaces
-		return sm.handleIdleShutdownForRunningWorkspace(ctx, workspace, service)
+		result, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace, service)
+		if err == nil && migrationPending && (result.RequeueAfter == 0 || result.RequeueAfter > PollRequeueDelay) {
+			// Keep probing the migration target
+			result.RequeueAfter = PollRequeueDelay
+		}
+		if interval := sm.usageAccountant.Interval(); interval > 0 &&
+			(result.RequeueAfter == 0 || result.RequeueAfter > interval) {
+			// Checkpoint the usage of the workspace while it runs
+			result.RequeueAfter = interval
+		}
+		if interval := sm.usageSampler.Interval(); interval > 0 &&
+			(result.RequeueAfter == 0 || result.RequeueAfter > interval) {
+			// Sample the live usage of the workspace while it runs
+			result.RequeueAfter = interval
+		}
+		if applicationsProbed && (result.RequeueAfter == 0 || result.RequeueAfter > DefaultApplicationProbePeriod) {
+			// Refresh the readiness of the applications of the workspace while it runs
+			result.RequeueAfter = DefaultApplicationProbePeriod
+		}
+		return result, err
 	}
 
 	// Resources are being created/started but not fully ready yet
@@ -312,11 +538,43 @@ func (sm *StateMachine) reconcileDesiredRunningStatus(
 		"accessResourcesReady", accessResourcesReady)
 	workspace.Status.DeploymentName = deployment.GetName()
 	workspace.Status.ServiceName = service.GetName()
+	// A workspace that is not available does not accrue usage
+	sm.usageAccountant.Stop(workspace)
+	sm.usageSampler.Clear(workspace)
+
+	// Give up on a workspace past its startup deadline or failure policy, until its spec changes
+	startupPolicy := checkStartupPolicy(workspace, time.Now())
+	if startupPolicy.GiveUp {
+		logger.Info("Giving up on starting workspace", "reason", startupPolicy.Reason, "message", startupPolicy.Message)
+		if degraded := FindCondition(&workspace.Status.Conditions, ConditionTypeDegraded); degraded == nil ||
+			degraded.Status != metav1.ConditionTrue || degraded.Reason != startupPolicy.Reason {
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, "StartupFailed", startupPolicy.Message)
+		}
+		if statusErr := sm.statusManager.UpdatePermanentDegradedRunningStatus(
+			ctx, workspace, startupPolicy.Reason, ReasonComputeNotReady, startupPolicy.Message,
+			snapshotStatus); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		// Pod and spec changes still trigger a reconcile, e.g. once the workspace recovers
+		return ctrl.Result{}, nil
+	}
+	requeueDelay = startupPolicy.RequeueAfter(requeueDelay)
+
+	// Flag, and attempt once to unblock, a workspace stuck starting. Watchdog failures must not
+	// block the reconcile.
+	if err := sm.progressingWatchdog.Check(ctx, workspace); err != nil {
+		logger.Error(err, "Progressing watchdog failed")
+	}
+
 	readiness := WorkspaceRunningReadiness{
 		computeReady:         deploymentReady,
 		serviceReady:         serviceReady,
 		accessResourcesReady: accessResourcesReady,
 	}
+	if !deploymentReady {
+		readiness.computeFailureReason, readiness.computeFailureMessage = resolveComputeFailure(
+			deployment, workspace.Status.PodStatus)
+	}
 	if err := sm.statusManager.UpdateStartingStatus(
 		ctx, workspace, readiness, snapshotStatus); err != nil {
 		return ctrl.Result{}, err
@@ -342,10 +600,26 @@ func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 		return ctrl.Result{}, nil
 	}
 
+	// Honor the culling policy: an exempt workspace is not probed, and is checked again
+	// once a keep-alive exemption expires
+	now := time.Now()
+	if IsCullingExempt(workspace, now) {
+		if err := sm.cancelIdleStop(ctx, workspace, "the workspace is exempt from culling"); err != nil {
+			return ctrl.Result{}, err
+		}
+		requeueAfter := sm.idleChecker.CheckInterval()
+		if remaining := cullingExemptionRemaining(workspace, now); remaining > 0 && remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+		logger.V(1).Info("Workspace is exempt from culling, skipping idle check", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	logger.Info("Processing idle shutdown",
 		"enabled", idleConfig.Enabled,
 		"idleTimeoutInMinutes", idleConfig.IdleTimeoutInMinutes,
 		"hasHTTPGet", idleConfig.Detection.HTTPGet != nil,
+		"hubActivity", idleConfig.Detection.HubActivity,
 		"workspace", workspace.Name,
 		"namespace", workspace.Namespace)
 
@@ -364,10 +638,16 @@ func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 	} else {
 		logger.V(1).Info("Successfully checked idle status", "isIdle", result.IsIdle)
 		if result.IsIdle {
+			if sm.idleNotifier != nil {
+				return sm.scheduleIdleStop(ctx, workspace, idleConfig, now)
+			}
 			logger.Info("Workspace idle timeout reached, stopping workspace",
 				"timeout", idleConfig.IdleTimeoutInMinutes)
 			return sm.stopWorkspaceDueToIdle(ctx, workspace, idleConfig)
 		}
+		if err := sm.cancelIdleStop(ctx, workspace, "the workspace is active again"); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Requeue for next idle check
@@ -385,6 +665,7 @@ func (sm *StateMachine) stopWorkspaceDueToIdle(ctx context.Context, workspace *w
 
 	// Update desired status to trigger stop
 	workspace.Spec.DesiredStatus = DesiredStateStopped
+	delete(workspace.Annotations, AnnotationIdleStopScheduledAt)
 	if err := sm.resourceManager.client.Update(ctx, workspace); err != nil {
 		logger.Error(err, "Failed to update workspace desired status")
 		return ctrl.Result{}, err
@@ -413,6 +694,34 @@ func (sm *StateMachine) ReconcileDeletion(ctx context.Context, workspace *worksp
 		return ctrl.Result{}, err
 	}
 
+	// Run the stop path first so that graceful shutdown and remote access
+	// deregistration complete before the storage and the finalizer go away
+	stopped, err := sm.stopWorkspaceForDeletion(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to stop workspace before deletion")
+		return ctrl.Result{}, err
+	}
+	if !stopped {
+		if !sm.isDeletionStopTimedOut(workspace) {
+			logger.Info("Waiting for workspace to stop before deleting resources")
+			return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+		}
+		logger.Info("Timed out waiting for workspace to stop, proceeding with deletion", "timeout", DeletionStopTimeout)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, "DeletionStopTimeout",
+			fmt.Sprintf("Workspace did not stop within %s, deleting remaining resources", DeletionStopTimeout))
+	}
+
+	// Release or snapshot the storage the retain policy keeps
+	retained, err := sm.retainPrimaryStorage(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to retain workspace storage")
+		return ctrl.Result{}, err
+	}
+	if !retained {
+		logger.Info("Waiting for the storage snapshot before deleting resources")
+		return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+	}
+
 	// Clean up all workspace resources via resource manager
 	allDeleted, err := sm.resourceManager.CleanupAllResources(ctx, workspace)
 	if err != nil {
@@ -432,6 +741,35 @@ func (sm *StateMachine) ReconcileDeletion(ctx context.Context, workspace *worksp
 		return ctrl.Result{}, err
 	}
 
+	sm.usageAccountant.Forget(workspace)
 	logger.Info("Finalizer removed, workspace deletion will proceed")
 	return ctrl.Result{}, nil
 }
+
+// stopWorkspaceForDeletion removes the access resources, deployment and service of a
+// workspace being deleted, and returns true once the workspace pods are gone
+func (sm *StateMachine) stopWorkspaceForDeletion(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	logger := logf.FromContext(ctx)
+
+	if err := sm.resourceManager.EnsureAccessResourcesDeleted(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to delete access strategy resources")
+		// Continue with the stop path, don't block on access strategy
+	}
+
+	if _, err := sm.resourceManager.EnsureDeploymentDeleted(ctx, workspace); err != nil {
+		return false, err
+	}
+	if _, err := sm.resourceManager.EnsureServiceDeleted(ctx, workspace); err != nil {
+		return false, err
+	}
+
+	return sm.resourceManager.AreWorkspacePodsDeleted(ctx, workspace)
+}
+
+// isDeletionStopTimedOut returns true when the workspace has been deleting for longer than DeletionStopTimeout
+func (sm *StateMachine) isDeletionStopTimedOut(workspace *workspacev1alpha1.Workspace) bool {
+	if workspace.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return time.Since(workspace.DeletionTimestamp.Time) > DeletionStopTimeout
+}
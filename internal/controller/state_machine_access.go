@@ -16,12 +16,15 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// ReconcileAccessForDesiredRunningStatus reconciles the access strategy for a Workspace whose desired state is Running
+// ReconcileAccessForDesiredRunningStatus reconciles the access strategy for a Workspace whose desired state is Running.
+// When migrationTarget is set, the access resources of the target AccessStrategy are applied alongside
+// the current ones, while the access URL keeps pointing to the current AccessStrategy.
 func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 	ctx context.Context,
 	workspace *workspacev1alpha1.Workspace,
 	service *corev1.Service,
-	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) error {
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	migrationTarget *workspacev1alpha1.WorkspaceAccessStrategy) error {
 	logger := logf.FromContext(ctx)
 	accessStrategyRef := workspace.Spec.AccessStrategy
 
@@ -29,13 +32,24 @@ func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 	// ensure the AccessResources exist
 	if accessStrategyRef != nil {
 
-		ensureAccessResourceErr := sm.resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, service)
+		var ensureAccessResourceErr error
+		if migrationTarget != nil {
+			ensureAccessResourceErr = sm.resourceManager.EnsureMigratingAccessResourcesExist(
+				ctx, workspace, accessStrategy, migrationTarget, service)
+		} else {
+			ensureAccessResourceErr = sm.resourceManager.EnsureAccessResourcesExist(ctx, workspace, accessStrategy, service)
+		}
 		if ensureAccessResourceErr != nil {
 			logger.Error(ensureAccessResourceErr, "Failed to apply access strategy")
 			return ensureAccessResourceErr
 		}
 
-		accessUrl, accessUrlErr := sm.resourceManager.accessResourcesBuilder.ResolveAccessURL(workspace, accessStrategy, service)
+		accessProvider, providerErr := sm.resourceManager.GetAccessProvider(accessStrategy)
+		if providerErr != nil {
+			logger.Error(providerErr, "Failed to resolve access provider")
+			return providerErr
+		}
+		accessUrl, accessUrlErr := accessProvider.AccessURL(workspace, accessStrategy, service)
 		if accessUrlErr != nil {
 			logger.Error(accessUrlErr, "Failed to retrieve Access URL from access strategy")
 			return accessUrlErr
@@ -49,6 +63,9 @@ func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 			logger.Error(appBasePathErr, "Failed to resolve applicationBasePathTemplate")
 		}
 		workspace.Status.ApplicationBasePath = applicationBasePath
+		// Report the AccessStrategy version the access resources were rendered from, so that the
+		// AccessStrategy controller tracks the propagation of its changes
+		workspace.Status.AppliedAccessStrategyVersion = versionedAccessStrategyID(accessStrategy)
 		return nil
 	}
 	// END OF CASE 1
@@ -59,7 +76,10 @@ func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 	workspace.Status.AccessResourceSelector = ""
 	workspace.Status.AccessStartupProbeSucceeded = false
 	workspace.Status.ObservedAccessStrategyVersion = ""
+	workspace.Status.AppliedAccessStrategyVersion = ""
 	clearProbeState(workspace)
+	removeReachableCondition(workspace)
+	removeAccessReadyCondition(workspace)
 
 	err := sm.resourceManager.EnsureAccessResourcesDeleted(ctx, workspace)
 	if err != nil {
@@ -78,7 +98,10 @@ func (sm *StateMachine) ReconcileAccessForDesiredStoppedStatus(ctx context.Conte
 	workspace.Status.AccessResourceSelector = ""
 	workspace.Status.AccessStartupProbeSucceeded = false
 	workspace.Status.ObservedAccessStrategyVersion = ""
+	workspace.Status.AppliedAccessStrategyVersion = ""
 	clearProbeState(workspace)
+	removeReachableCondition(workspace)
+	removeAccessReadyCondition(workspace)
 
 	err := sm.resourceManager.EnsureAccessResourcesDeleted(ctx, workspace)
 	if err != nil {
@@ -88,6 +111,97 @@ func (sm *StateMachine) ReconcileAccessForDesiredStoppedStatus(ctx context.Conte
 	return nil
 }
 
+// ReconcileAccessForDesiredHibernatedStatus removes the access resources of a Workspace whose desired
+// state is Hibernated, like for a stopped Workspace, but keeps the access URL and application base path
+// resolved from the access strategy, as a placeholder for when the workspace resumes.
+// The URL is resolved against the Service the workspace would get, since its Service is deleted.
+func (sm *StateMachine) ReconcileAccessForDesiredHibernatedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) error {
+	logger := logf.FromContext(ctx)
+
+	if err := sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace); err != nil {
+		return err
+	}
+	if accessStrategy == nil {
+		return nil
+	}
+
+	// The placeholder is best effort: failing to resolve it must not block hibernation
+	service, err := sm.resourceManager.serviceBuilder.BuildService(workspace)
+	if err != nil {
+		logger.Error(err, "Failed to build placeholder service for hibernated workspace")
+		return nil
+	}
+	accessProvider, err := sm.resourceManager.GetAccessProvider(accessStrategy)
+	if err != nil {
+		logger.Error(err, "Failed to resolve access provider for hibernated workspace")
+		return nil
+	}
+	accessURL, err := accessProvider.AccessURL(workspace, accessStrategy, service)
+	if err != nil {
+		logger.Error(err, "Failed to retrieve Access URL of hibernated workspace from access strategy")
+		return nil
+	}
+	applicationBasePath, err := sm.resourceManager.accessResourcesBuilder.ResolveApplicationBasePath(workspace, accessStrategy, service)
+	if err != nil {
+		logger.Error(err, "Failed to resolve applicationBasePathTemplate of hibernated workspace")
+	}
+	workspace.Status.AccessURL = accessURL
+	workspace.Status.ApplicationBasePath = applicationBasePath
+	return nil
+}
+
+// ReconcileAccessForDisabledAccess removes the access resources and access URL of a Workspace whose
+// spec.accessEnabled is false, like for a stopped Workspace, while leaving its compute running
+func (sm *StateMachine) ReconcileAccessForDisabledAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	hadAccess := workspace.Status.AccessURL != "" || len(workspace.Status.AccessResources) > 0
+	if err := sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace); err != nil {
+		return err
+	}
+	if hadAccess {
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, "AccessDisabled",
+			"Access resources were removed because spec.accessEnabled is false")
+	}
+	return nil
+}
+
+// ReconcileAccessReadiness reports in the AccessReady condition whether the access resources of a
+// Workspace are ready, as told by its access provider, e.g. once an Ingress was assigned an address
+// or a Certificate was issued. It returns true when the Workspace has no AccessStrategy.
+func (sm *StateMachine) ReconcileAccessReadiness(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (bool, error) {
+	if workspace.Spec.AccessStrategy == nil || accessStrategy == nil {
+		removeAccessReadyCondition(workspace)
+		return true, nil
+	}
+
+	accessProvider, err := sm.resourceManager.GetAccessProvider(accessStrategy)
+	if err != nil {
+		return false, err
+	}
+	ready, message, err := sm.resourceManager.CheckAccessResourcesReady(ctx, workspace, accessProvider)
+	if err != nil {
+		return false, err
+	}
+
+	condition := NewCondition(ConditionTypeAccessReady, metav1.ConditionTrue,
+		ReasonAccessResourcesReady, "All access resources are ready")
+	if !ready {
+		condition = NewCondition(ConditionTypeAccessReady, metav1.ConditionFalse,
+			ReasonAccessResourcesPending, "Waiting for access resources: "+message)
+		// Like until the access startup probe passes, the access URL does not serve traffic yet
+		workspace.Status.AccessURL = ""
+	}
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+	return ready, nil
+}
+
 // ProbeStatus indicates the outcome of an access startup probe cycle.
 type ProbeStatus int
 
@@ -215,6 +329,54 @@ func (sm *StateMachine) ProbeAccessStartup(
 	}, nil
 }
 
+// setReachableCondition reflects the outcome of the access startup probe in the Reachable condition.
+// Until the probe succeeds, the access URL is withheld from the status so that users
+// are not handed a URL that does not serve traffic yet.
+func setReachableCondition(ctx context.Context, workspace *workspacev1alpha1.Workspace, status ProbeStatus) {
+	var condition metav1.Condition
+	switch status {
+	case ProbeNotDefined:
+		removeReachableCondition(workspace)
+		return
+	case ProbeSucceeded, ProbeAlreadySucceeded:
+		condition = NewCondition(ConditionTypeReachable, metav1.ConditionTrue,
+			ReasonAccessProbeSucceeded, "Access URL is serving traffic")
+	case ProbeFailureThresholdExceeded:
+		condition = NewCondition(ConditionTypeReachable, metav1.ConditionFalse,
+			ReasonAccessProbeThresholdExceeded, "Access startup probe failed: threshold exceeded")
+	default:
+		condition = NewCondition(ConditionTypeReachable, metav1.ConditionFalse,
+			ReasonAccessProbePending, "Waiting for the access URL to serve traffic")
+	}
+
+	if condition.Status != metav1.ConditionTrue {
+		workspace.Status.AccessURL = ""
+	}
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+}
+
+func removeReachableCondition(workspace *workspacev1alpha1.Workspace) {
+	conditions := workspace.Status.Conditions[:0]
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeReachable {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
+
+func removeAccessReadyCondition(workspace *workspacev1alpha1.Workspace) {
+	conditions := workspace.Status.Conditions[:0]
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeAccessReady {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
+
 func clearProbeState(workspace *workspacev1alpha1.Workspace) {
 	workspace.Status.AccessStartupProbeFailures = nil
 	workspace.Status.EarliestNextProbeTime = nil
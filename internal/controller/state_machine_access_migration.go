@@ -0,0 +1,154 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AccessStrategyMigrationTargetRef parses the migration annotation of a Workspace.
+// Returns nil when the Workspace does not request a migration.
+func AccessStrategyMigrationTargetRef(workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.AccessStrategyRef {
+	value := strings.TrimSpace(workspace.Annotations[AnnotationAccessStrategyMigrationTarget])
+	if value == "" {
+		return nil
+	}
+	if namespace, name, found := strings.Cut(value, "/"); found {
+		return &workspacev1alpha1.AccessStrategyRef{Name: name, Namespace: namespace}
+	}
+	return &workspacev1alpha1.AccessStrategyRef{Name: value}
+}
+
+// isSameAccessStrategyRef returns true if both references resolve to the same AccessStrategy
+func isSameAccessStrategyRef(a, b *workspacev1alpha1.AccessStrategyRef, workspaceNamespace string) bool {
+	namespaceOf := func(ref *workspacev1alpha1.AccessStrategyRef) string {
+		if ref.Namespace != "" {
+			return ref.Namespace
+		}
+		return workspaceNamespace
+	}
+	return a.Name == b.Name && namespaceOf(a) == namespaceOf(b)
+}
+
+// GetAccessStrategyMigrationTarget returns the AccessStrategy a Workspace is migrating to,
+// or nil when the Workspace has no migration annotation, has no AccessStrategy to migrate from,
+// or already uses the target AccessStrategy.
+// A target that cannot be retrieved is reported as an event and leaves the Workspace on its
+// current AccessStrategy.
+func (sm *StateMachine) GetAccessStrategyMigrationTarget(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+) *workspacev1alpha1.WorkspaceAccessStrategy {
+	targetRef := AccessStrategyMigrationTargetRef(workspace)
+	if targetRef == nil || workspace.Spec.AccessStrategy == nil ||
+		isSameAccessStrategyRef(targetRef, workspace.Spec.AccessStrategy, workspace.Namespace) {
+		return nil
+	}
+
+	target, err := sm.resourceManager.GetAccessStrategy(ctx, targetRef, workspace.Namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get access strategy migration target")
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, "AccessStrategyMigrationFailed", errs.UserMessage(err))
+		return nil
+	}
+	return target
+}
+
+// ReconcileAccessStrategyMigration completes the migration of a running Workspace to the target
+// AccessStrategy once the access URL of the target is reachable. The caller must have applied
+// the access resources of both AccessStrategies with EnsureMigratingAccessResourcesExist.
+//
+// On success, spec.accessStrategy is switched to the target and the migration annotation is removed
+// in a single patch; the next reconciliation prunes the access resources of the previous AccessStrategy.
+// Returns true while the migration is waiting for the target to become reachable.
+func (sm *StateMachine) ReconcileAccessStrategyMigration(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	target *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) (bool, error) {
+	if target == nil {
+		return false, nil
+	}
+	logger := logf.FromContext(ctx).WithValues("targetAccessStrategy", target.Name)
+
+	targetProvider, err := sm.resourceManager.GetAccessProvider(target)
+	if err != nil {
+		return false, err
+	}
+	targetURL, err := targetProvider.AccessURL(workspace, target, service)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve the access URL of access strategy %s: %w", target.Name, err)
+	}
+
+	reachable, err := sm.probeAccessStrategyMigrationTarget(ctx, workspace, target, targetURL, service)
+	if err != nil {
+		logger.Error(err, "Failed to probe access strategy migration target")
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, "AccessStrategyMigrationFailed", errs.UserMessage(err))
+		return true, nil
+	}
+	if !reachable {
+		logger.Info("Waiting for the access URL of the migration target to be reachable", "accessURL", targetURL)
+		return true, nil
+	}
+
+	patched := workspace.DeepCopy()
+	patched.Spec.AccessStrategy = &workspacev1alpha1.AccessStrategyRef{
+		Name:      target.Name,
+		Namespace: target.Namespace,
+	}
+	delete(patched.Annotations, AnnotationAccessStrategyMigrationTarget)
+	if err := sm.resourceManager.client.Patch(ctx, patched, client.MergeFrom(workspace)); err != nil {
+		return false, fmt.Errorf("failed to switch workspace to access strategy %s: %w", target.Name, err)
+	}
+	workspace.Spec.AccessStrategy = patched.Spec.AccessStrategy
+	workspace.Annotations = patched.Annotations
+	workspace.ResourceVersion = patched.ResourceVersion
+
+	// The target was just verified: publish its URL without probing it again
+	workspace.Status.AccessURL = targetURL
+	workspace.Status.AccessStartupProbeSucceeded = true
+	workspace.Status.ObservedAccessStrategyVersion = versionedAccessStrategyID(target)
+	workspace.Status.AppliedAccessStrategyVersion = versionedAccessStrategyID(target)
+	clearProbeState(workspace)
+
+	logger.Info("Migrated workspace to access strategy", "accessURL", targetURL)
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "AccessStrategyMigrated",
+		fmt.Sprintf("Workspace migrated to access strategy %s/%s", target.Namespace, target.Name))
+	return false, nil
+}
+
+// probeAccessStrategyMigrationTarget sends a single probe to the target AccessStrategy.
+// It uses the access startup probe of the target when defined, and otherwise probes
+// the target access URL through the Workspace service.
+func (sm *StateMachine) probeAccessStrategyMigrationTarget(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	target *workspacev1alpha1.WorkspaceAccessStrategy,
+	targetURL string,
+	service *corev1.Service,
+) (bool, error) {
+	probeTarget := target
+	if probeTarget.Spec.AccessStartupProbe == nil || probeTarget.Spec.AccessStartupProbe.HTTPGet == nil {
+		probeTarget = target.DeepCopy()
+		probeTarget.Spec.AccessStartupProbe = &workspacev1alpha1.AccessStartupProbe{
+			HTTPGet: &workspacev1alpha1.AccessHTTPGetProbe{},
+		}
+	}
+
+	candidate := workspace.DeepCopy()
+	candidate.Spec.AccessStrategy = &workspacev1alpha1.AccessStrategyRef{Name: target.Name, Namespace: target.Namespace}
+	candidate.Status.AccessURL = targetURL
+	return sm.accessStartupProber.Probe(ctx, candidate, probeTarget, service)
+}
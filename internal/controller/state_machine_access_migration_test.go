@@ -0,0 +1,140 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newMigrationTestAccessStrategy(name, resourcePrefix string) *workspacev1alpha1.WorkspaceAccessStrategy {
+	return &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", UID: types.UID("uid-" + name), Generation: 1},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessURLTemplate: "https://" + name + ".example.com/{{ .Workspace.Name }}/",
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{Kind: "ConfigMap", ApiVersion: "v1", NamePrefix: resourcePrefix, Template: "data: {}"},
+			},
+		},
+	}
+}
+
+func newMigrationTestStateMachine(
+	t *testing.T,
+	prober *mockAccessStartupProber,
+	objs ...client.Object,
+) (*StateMachine, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).Build()
+
+	rm := NewResourceManager(fakeClient, scheme, nil, nil, nil, NewAccessResourcesBuilder(), nil)
+	return &StateMachine{
+		resourceManager:     rm,
+		accessStartupProber: prober,
+		recorder:            record.NewFakeRecorder(10),
+	}, fakeClient
+}
+
+func newMigratingWorkspace(target string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Namespace:   "team-a",
+			Annotations: map[string]string{AnnotationAccessStrategyMigrationTarget: target},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: "traefik"},
+		},
+	}
+}
+
+func TestAccessStrategyMigrationTargetRef(t *testing.T) {
+	assert.Nil(t, AccessStrategyMigrationTargetRef(&workspacev1alpha1.Workspace{}))
+	assert.Equal(t, &workspacev1alpha1.AccessStrategyRef{Name: "gateway"},
+		AccessStrategyMigrationTargetRef(newMigratingWorkspace("gateway")))
+	assert.Equal(t, &workspacev1alpha1.AccessStrategyRef{Name: "gateway", Namespace: "shared"},
+		AccessStrategyMigrationTargetRef(newMigratingWorkspace("shared/gateway")))
+
+	assert.True(t, isSameAccessStrategyRef(
+		&workspacev1alpha1.AccessStrategyRef{Name: "traefik"},
+		&workspacev1alpha1.AccessStrategyRef{Name: "traefik", Namespace: "team-a"}, "team-a"))
+	assert.False(t, isSameAccessStrategyRef(
+		&workspacev1alpha1.AccessStrategyRef{Name: "traefik", Namespace: "shared"},
+		&workspacev1alpha1.AccessStrategyRef{Name: "traefik"}, "team-a"))
+}
+
+func TestGetAccessStrategyMigrationTarget(t *testing.T) {
+	target := newMigrationTestAccessStrategy("gateway", "route")
+	sm, _ := newMigrationTestStateMachine(t, &mockAccessStartupProber{}, target)
+
+	found := sm.GetAccessStrategyMigrationTarget(context.Background(), newMigratingWorkspace("gateway"))
+	require.NotNil(t, found)
+	assert.Equal(t, "gateway", found.Name)
+
+	// Migrating to the current access strategy is a no-op
+	assert.Nil(t, sm.GetAccessStrategyMigrationTarget(context.Background(), newMigratingWorkspace("traefik")))
+
+	// A missing target leaves the workspace on its current access strategy
+	assert.Nil(t, sm.GetAccessStrategyMigrationTarget(context.Background(), newMigratingWorkspace("missing")))
+	assert.Contains(t, <-sm.recorder.(*record.FakeRecorder).Events, "AccessStrategyMigrationFailed")
+}
+
+func TestReconcileAccessStrategyMigration(t *testing.T) {
+	ctx := context.Background()
+	current := newMigrationTestAccessStrategy("traefik", "ingressroute")
+	target := newMigrationTestAccessStrategy("gateway", "route")
+	workspace := newMigratingWorkspace("gateway")
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-service", Namespace: "team-a"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8888}}},
+	}
+	prober := &mockAccessStartupProber{}
+	sm, fakeClient := newMigrationTestStateMachine(t, prober, workspace, current, target)
+
+	// Both access strategies are served side by side during the migration
+	require.NoError(t, sm.resourceManager.EnsureMigratingAccessResourcesExist(ctx, workspace, current, target, service))
+	require.Len(t, workspace.Status.AccessResources, 2)
+
+	// The workspace stays on its current access strategy until the target is reachable
+	pending, err := sm.ReconcileAccessStrategyMigration(ctx, workspace, target, service)
+	require.NoError(t, err)
+	assert.True(t, pending)
+	assert.Equal(t, 1, prober.probeCount)
+	assert.Equal(t, "traefik", workspace.Spec.AccessStrategy.Name)
+
+	prober.ready = true
+	pending, err = sm.ReconcileAccessStrategyMigration(ctx, workspace, target, service)
+	require.NoError(t, err)
+	assert.False(t, pending)
+	assert.Equal(t, "https://gateway.example.com/ws/", workspace.Status.AccessURL)
+	assert.True(t, workspace.Status.AccessStartupProbeSucceeded)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, "gateway", stored.Spec.AccessStrategy.Name)
+	assert.NotContains(t, stored.Annotations, AnnotationAccessStrategyMigrationTarget)
+
+	// The next reconciliation prunes the access resources of the previous access strategy
+	require.NoError(t, sm.resourceManager.EnsureAccessResourcesExist(ctx, workspace, target, service))
+	require.Len(t, workspace.Status.AccessResources, 1)
+	assert.Equal(t, "route-ws", workspace.Status.AccessResources[0].Name)
+}
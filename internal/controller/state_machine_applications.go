@@ -0,0 +1,72 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// reportApplications reports the URL and readiness of the additional applications of a running
+// workspace in status.applications, persisted by the status update that follows. The applications
+// declaring a healthPath are probed through the workspace Service, and an event is recorded when
+// one stops answering. It returns whether an application was probed, so that the workspace is
+// requeued to refresh their readiness.
+func (sm *StateMachine) reportApplications(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, service *corev1.Service) bool {
+	if len(workspace.Spec.Ports) == 0 {
+		workspace.Status.Applications = nil
+		return false
+	}
+
+	previous := make(map[string]workspacev1alpha1.WorkspaceApplicationStatus, len(workspace.Status.Applications))
+	for _, application := range workspace.Status.Applications {
+		previous[application.Name] = application
+	}
+
+	probed := false
+	applications := make([]workspacev1alpha1.WorkspaceApplicationStatus, 0, len(workspace.Spec.Ports))
+	for _, port := range workspace.Spec.Ports {
+		application := workspacev1alpha1.WorkspaceApplicationStatus{
+			Name: port.Name,
+			URL:  applicationURL(workspace.Status.AccessURL, workspaceutil.RoutePath(port)),
+		}
+		if port.HealthPath != "" && sm.applicationProber != nil && service != nil {
+			ready := sm.applicationProber.Probe(ctx, service, port.Port, port.HealthPath)
+			application.Ready = ptr.To(ready)
+			probed = true
+			if wasReady := previous[port.Name].Ready; !ready && wasReady != nil && *wasReady {
+				sm.recorder.Eventf(workspace, corev1.EventTypeWarning, "ApplicationNotReady",
+					"Application %s stopped answering on %s", port.Name, port.HealthPath)
+			}
+		}
+		applications = append(applications, application)
+	}
+	workspace.Status.Applications = applications
+	return probed
+}
+
+// applicationURL joins the access URL of a workspace with the subpath routed to an application,
+// or returns an empty URL while the workspace has no access URL
+func applicationURL(accessURL, path string) string {
+	if accessURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(accessURL)
+	if err != nil {
+		return ""
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/") + path
+	parsed.RawPath = ""
+	return parsed.String()
+}
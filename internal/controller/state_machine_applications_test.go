@@ -0,0 +1,100 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// fakeApplicationProber reports the applications listening on the ports it lists as ready
+type fakeApplicationProber struct {
+	ready  map[int32]bool
+	probes []string
+}
+
+func (p *fakeApplicationProber) Probe(_ context.Context, _ *corev1.Service, port int32, path string) bool {
+	p.probes = append(p.probes, path)
+	return p.ready[port]
+}
+
+func newApplicationsTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Ports: []workspacev1alpha1.WorkspacePort{
+				{Name: "tensorboard", Port: 6006, HealthPath: "/tensorboard/"},
+				{Name: "mlflow", Port: 5000, Path: "/tracking/"},
+			},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{AccessURL: "https://example.com/workspaces/default/apps/"},
+	}
+}
+
+func TestReportApplications(t *testing.T) {
+	prober := &fakeApplicationProber{ready: map[int32]bool{6006: true}}
+	sm := &StateMachine{recorder: record.NewFakeRecorder(10), applicationProber: prober}
+	workspace := newApplicationsTestWorkspace()
+
+	probed := sm.reportApplications(context.Background(), workspace, &corev1.Service{})
+
+	assert.True(t, probed)
+	assert.Equal(t, []string{"/tensorboard/"}, prober.probes, "only applications with a healthPath are probed")
+	assert.Equal(t, []workspacev1alpha1.WorkspaceApplicationStatus{
+		{Name: "tensorboard", URL: "https://example.com/workspaces/default/apps/tensorboard/", Ready: ptr.To(true)},
+		{Name: "mlflow", URL: "https://example.com/workspaces/default/apps/tracking/"},
+	}, workspace.Status.Applications)
+}
+
+func TestReportApplicationsRecordsApplicationNotReady(t *testing.T) {
+	prober := &fakeApplicationProber{ready: map[int32]bool{6006: true}}
+	recorder := record.NewFakeRecorder(10)
+	sm := &StateMachine{recorder: recorder, applicationProber: prober}
+	workspace := newApplicationsTestWorkspace()
+
+	sm.reportApplications(context.Background(), workspace, &corev1.Service{})
+	require.Empty(t, recorder.Events)
+
+	prober.ready[6006] = false
+	sm.reportApplications(context.Background(), workspace, &corev1.Service{})
+	assert.Equal(t, ptr.To(false), workspace.Status.Applications[0].Ready)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "ApplicationNotReady")
+
+	sm.reportApplications(context.Background(), workspace, &corev1.Service{})
+	assert.Empty(t, recorder.Events, "the event is only recorded when the application stops answering")
+}
+
+func TestReportApplicationsWithoutAccessURLOrPorts(t *testing.T) {
+	sm := &StateMachine{recorder: record.NewFakeRecorder(10)}
+	workspace := newApplicationsTestWorkspace()
+	workspace.Status.AccessURL = ""
+
+	assert.False(t, sm.reportApplications(context.Background(), workspace, &corev1.Service{}),
+		"applications are not probed without a prober")
+	assert.Equal(t, []workspacev1alpha1.WorkspaceApplicationStatus{{Name: "tensorboard"}, {Name: "mlflow"}},
+		workspace.Status.Applications)
+
+	workspace.Spec.Ports = nil
+	sm.reportApplications(context.Background(), workspace, &corev1.Service{})
+	assert.Nil(t, workspace.Status.Applications)
+}
+
+func TestApplicationURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/ws/tensorboard/", applicationURL("https://example.com/ws/", "/tensorboard/"))
+	assert.Equal(t, "https://alice.example.com/tensorboard/", applicationURL("https://alice.example.com", "/tensorboard/"))
+	assert.Equal(t, "https://example.com/ws/mlflow/?token=x", applicationURL("https://example.com/ws/?token=x", "/mlflow/"))
+	assert.Empty(t, applicationURL("", "/tensorboard/"))
+}
@@ -12,10 +12,16 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
@@ -161,3 +167,118 @@ var _ = Describe("ReconcileDeletion", func() {
 		Expect(ws.Status.ServiceName).To(BeEmpty())
 	})
 })
+
+var _ = Describe("ReconcileDeletion stop ordering", func() {
+	var (
+		ctx        context.Context
+		testScheme *runtime.Scheme
+	)
+
+	newDeletingWorkspace := func(deletedAgo time.Duration) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "stop-first",
+				Namespace:         "default",
+				Finalizers:        []string{WorkspaceFinalizerName},
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-deletedAgo)},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:         imageBaseNotebook,
+				DesiredStatus: DesiredStateRunning,
+				Storage:       &workspacev1alpha1.StorageSpec{Size: resource.MustParse("1Gi")},
+			},
+		}
+	}
+
+	newWorkspacePod := func(ws *workspacev1alpha1.Workspace) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ws.Name + "-pod",
+				Namespace: ws.Namespace,
+				Labels:    GenerateLabels(ws.Name),
+			},
+		}
+	}
+
+	newWorkspacePVC := func(ws *workspacev1alpha1.Workspace) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GeneratePVCName(ws),
+				Namespace: ws.Namespace,
+			},
+		}
+	}
+
+	buildStateMachine := func(objs ...client.Object) (*StateMachine, client.Client) {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+			Build()
+		statusManager := NewStatusManager(fakeClient)
+		rm := NewResourceManager(
+			fakeClient,
+			testScheme,
+			NewDeploymentBuilder(testScheme, WorkspaceControllerOptions{}, fakeClient),
+			NewServiceBuilder(testScheme),
+			NewPVCBuilder(testScheme),
+			NewAccessResourcesBuilder(),
+			statusManager,
+		)
+		return &StateMachine{
+			resourceManager:     rm,
+			statusManager:       statusManager,
+			accessStartupProber: &mockAccessStartupProber{},
+			recorder:            record.NewFakeRecorder(10),
+		}, fakeClient
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		testScheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+	})
+
+	It("should keep the PVC and the finalizer while workspace pods are shutting down", func() {
+		ws := newDeletingWorkspace(time.Second)
+		sm, fakeClient := buildStateMachine(ws, newWorkspacePod(ws), newWorkspacePVC(ws))
+
+		result, err := sm.ReconcileDeletion(ctx, ws)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(PollRequeueDelay))
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(newWorkspacePVC(ws)), pvc)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(ws, WorkspaceFinalizerName)).To(BeTrue())
+	})
+
+	It("should delete the remaining resources once the stop timeout has elapsed", func() {
+		ws := newDeletingWorkspace(DeletionStopTimeout + time.Minute)
+		sm, fakeClient := buildStateMachine(ws, newWorkspacePod(ws), newWorkspacePVC(ws))
+
+		_, err := sm.ReconcileDeletion(ctx, ws)
+		Expect(err).NotTo(HaveOccurred())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(newWorkspacePVC(ws)), pvc)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		recorder := sm.recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring("DeletionStopTimeout")))
+	})
+
+	It("should delete the PVC and remove the finalizer once the workspace pods are gone", func() {
+		ws := newDeletingWorkspace(time.Second)
+		sm, fakeClient := buildStateMachine(ws, newWorkspacePVC(ws))
+
+		_, err := sm.ReconcileDeletion(ctx, ws)
+		Expect(err).NotTo(HaveOccurred())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(newWorkspacePVC(ws)), pvc)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(controllerutil.ContainsFinalizer(ws, WorkspaceFinalizerName)).To(BeFalse())
+	})
+})
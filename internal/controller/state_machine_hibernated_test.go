@@ -0,0 +1,143 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("reconcileDesiredStoppedStatus when hibernating", func() {
+	var ctx context.Context
+
+	newHibernatedWorkspace := func() *workspacev1alpha1.Workspace {
+		ws := &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("sm-hibernate-%d", time.Now().UnixNano()),
+				Namespace: testNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:         imageBaseNotebook,
+				DesiredStatus: DesiredStateHibernated,
+				AccessStrategy: &workspacev1alpha1.AccessStrategyRef{
+					Name: testStrategyName,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ws)).To(Succeed())
+		return ws
+	}
+
+	newAccessStrategy := func() *workspacev1alpha1.WorkspaceAccessStrategy {
+		return &workspacev1alpha1.WorkspaceAccessStrategy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testStrategyName,
+				Namespace: testNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+				DisplayName:                 testStrategyDisplayName,
+				AccessResourceTemplates:     []workspacev1alpha1.AccessResourceTemplate{},
+				AccessURLTemplate:           "https://example.com/{{ .Workspace.Name }}/{{ .Service.Name }}/",
+				ApplicationBasePathTemplate: "/{{ .Workspace.Name }}/",
+			},
+		}
+	}
+
+	buildStateMachine := func() *StateMachine {
+		statusManager := NewStatusManager(k8sClient)
+		rm := NewResourceManager(
+			k8sClient,
+			scheme.Scheme,
+			NewDeploymentBuilder(scheme.Scheme, WorkspaceControllerOptions{}, k8sClient),
+			NewServiceBuilder(scheme.Scheme),
+			NewPVCBuilder(scheme.Scheme),
+			NewAccessResourcesBuilder(),
+			statusManager,
+		)
+		return &StateMachine{
+			resourceManager:     rm,
+			statusManager:       statusManager,
+			accessStartupProber: &mockAccessStartupProber{},
+			recorder:            record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should mark Hibernated and keep the access URL placeholder", func() {
+		workspace := newHibernatedWorkspace()
+		defer func() { _ = k8sClient.Delete(ctx, workspace) }()
+
+		sm := buildStateMachine()
+		result, err := sm.ReconcileDesiredState(ctx, workspace, newAccessStrategy())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace)).To(Succeed())
+		Expect(workspace.Status.AccessURL).To(Equal(
+			fmt.Sprintf("https://example.com/%s/%s/", workspace.Name, GenerateServiceName(workspace))))
+		Expect(workspace.Status.ApplicationBasePath).To(Equal("/" + workspace.Name + "/"))
+		Expect(workspace.Status.ServiceName).To(BeEmpty())
+		Expect(workspace.Status.DeploymentName).To(BeEmpty())
+		Expect(workspace.Status.AccessResourceSelector).To(BeEmpty())
+
+		stopped := findCondition(workspace.Status.Conditions, ConditionTypeStopped)
+		Expect(stopped).NotTo(BeNil())
+		Expect(stopped.Status).To(Equal(metav1.ConditionTrue))
+		Expect(stopped.Reason).To(Equal(ReasonResourcesHibernated))
+
+		available := findCondition(workspace.Status.Conditions, ConditionTypeAvailable)
+		Expect(available).NotTo(BeNil())
+		Expect(available.Status).To(Equal(metav1.ConditionFalse))
+		Expect(available.Reason).To(Equal(ReasonDesiredStateHibernated))
+	})
+
+	It("should hibernate without an access URL when the access strategy is unavailable", func() {
+		workspace := newHibernatedWorkspace()
+		defer func() { _ = k8sClient.Delete(ctx, workspace) }()
+
+		workspace.Status.AccessURL = exampleURLTemplate
+		Expect(k8sClient.Status().Update(ctx, workspace)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace)).To(Succeed())
+
+		sm := buildStateMachine()
+		_, err := sm.ReconcileDesiredState(ctx, workspace, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace)).To(Succeed())
+		Expect(workspace.Status.AccessURL).To(BeEmpty())
+		stopped := findCondition(workspace.Status.Conditions, ConditionTypeStopped)
+		Expect(stopped).NotTo(BeNil())
+		Expect(stopped.Reason).To(Equal(ReasonResourcesHibernated))
+	})
+
+	It("should drop the culling status", func() {
+		workspace := newHibernatedWorkspace()
+		defer func() { _ = k8sClient.Delete(ctx, workspace) }()
+
+		workspace.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{Disabled: true}
+		Expect(k8sClient.Update(ctx, workspace)).To(Succeed())
+
+		sm := buildStateMachine()
+		_, err := sm.ReconcileDesiredState(ctx, workspace, newAccessStrategy())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace)).To(Succeed())
+		Expect(workspace.Status.Culling).To(BeNil())
+	})
+})
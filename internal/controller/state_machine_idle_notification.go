@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// scheduleIdleStop handles an idle workspace when idle notifications are enabled. The first time
+// the workspace is found idle, its owner is notified and the stop is scheduled at the end of the
+// grace period. The workspace is stopped once the grace period is over, unless the owner bumped
+// the keep-alive annotation since the notification.
+func (sm *StateMachine) scheduleIdleStop(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	idleConfig *workspacev1alpha1.IdleShutdownSpec,
+	now time.Time,
+) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+	gracePeriod := sm.idleNotifier.GracePeriod()
+
+	scheduledStop, scheduled := idleStopScheduledAt(workspace)
+	if !scheduled {
+		scheduledStop = now.Add(gracePeriod)
+		if err := sm.idleNotifier.Notify(ctx, workspace, scheduledStop); err != nil {
+			// Stop the workspace anyway: an unreachable webhook must not keep idle workspaces running
+			logger.Error(err, "Failed to notify the owner of the idle workspace")
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, "IdleNotificationFailed",
+				fmt.Sprintf("Failed to notify the owner of the idle workspace: %v", err))
+		}
+
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[AnnotationIdleStopScheduledAt] = scheduledStop.UTC().Format(time.RFC3339)
+		if err := sm.resourceManager.client.Update(ctx, workspace); err != nil {
+			logger.Error(err, "Failed to record the scheduled idle stop")
+			return ctrl.Result{}, err
+		}
+
+		logger.Info("Workspace is idle, scheduled its stop", "scheduledStop", scheduledStop)
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, "IdleShutdownScheduled",
+			fmt.Sprintf("Workspace is idle and stops at %s unless %s is set to the current time",
+				scheduledStop.UTC().Format(time.RFC3339), AnnotationKeepAlive))
+		return ctrl.Result{RequeueAfter: sm.nextIdleCheck(gracePeriod)}, nil
+	}
+
+	if keepAliveRequestedSince(ctx, workspace, scheduledStop.Add(-gracePeriod)) {
+		if err := sm.cancelIdleStop(ctx, workspace, "the owner requested to keep the workspace alive"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: sm.idleChecker.CheckInterval()}, nil
+	}
+
+	if remaining := scheduledStop.Sub(now); remaining > 0 {
+		logger.V(1).Info("Waiting for the grace period of the idle workspace", "remaining", remaining)
+		return ctrl.Result{RequeueAfter: sm.nextIdleCheck(remaining)}, nil
+	}
+
+	logger.Info("Grace period of the idle workspace is over, stopping workspace",
+		"timeout", idleConfig.IdleTimeoutInMinutes)
+	return sm.stopWorkspaceDueToIdle(ctx, workspace, idleConfig)
+}
+
+// cancelIdleStop clears the scheduled idle stop of the workspace, if any
+func (sm *StateMachine) cancelIdleStop(ctx context.Context, workspace *workspacev1alpha1.Workspace, reason string) error {
+	if _, scheduled := workspace.Annotations[AnnotationIdleStopScheduledAt]; !scheduled {
+		return nil
+	}
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
+
+	delete(workspace.Annotations, AnnotationIdleStopScheduledAt)
+	if err := sm.resourceManager.client.Update(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to cancel the scheduled idle stop")
+		return err
+	}
+
+	logger.Info("Cancelled the scheduled idle stop", "reason", reason)
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "IdleShutdownCancelled",
+		fmt.Sprintf("Cancelled the scheduled idle stop: %s", reason))
+	return nil
+}
+
+// nextIdleCheck returns the delay before the next idle check, bounded by the check interval
+func (sm *StateMachine) nextIdleCheck(delay time.Duration) time.Duration {
+	if interval := sm.idleChecker.CheckInterval(); delay > interval {
+		return interval
+	}
+	return delay
+}
+
+// idleStopScheduledAt returns the time the idle workspace is scheduled to stop at, if any
+func idleStopScheduledAt(workspace *workspacev1alpha1.Workspace) (time.Time, bool) {
+	value, ok := workspace.Annotations[AnnotationIdleStopScheduledAt]
+	if !ok {
+		return time.Time{}, false
+	}
+	scheduledStop, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return scheduledStop, true
+}
+
+// keepAliveRequestedSince returns true when the owner set the keep-alive annotation of the
+// workspace to a time after the given one
+func keepAliveRequestedSince(ctx context.Context, workspace *workspacev1alpha1.Workspace, since time.Time) bool {
+	value, ok := workspace.Annotations[AnnotationKeepAlive]
+	if !ok {
+		return false
+	}
+	keepAlive, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("Ignoring invalid keep-alive annotation",
+			"workspace", workspace.Name, "value", value)
+		return false
+	}
+	return keepAlive.After(since)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// retainPrimaryStorage applies the storage retain policy of a deleted workspace before its resources
+// are cleaned up: a retained PVC is released so that the cleanup leaves it, and a snapshotted PVC is
+// only deleted once its snapshot is ready. Returns false while the snapshot is in progress.
+// A PVC whose snapshot cannot be taken is retained rather than deleted.
+func (sm *StateMachine) retainPrimaryStorage(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	policy := resolveRetainPolicy(workspace)
+	if policy == workspacev1alpha1.StorageRetainPolicyDelete {
+		return true, nil
+	}
+	if hasPVC, err := sm.resourceManager.HasPVC(ctx, workspace); err != nil || !hasPVC {
+		return err == nil, err
+	}
+
+	if policy == workspacev1alpha1.StorageRetainPolicySnapshot {
+		if !sm.workspaceSnapshotsEnabled {
+			return true, sm.releasePVC(ctx, workspace, "workspace snapshots are disabled")
+		}
+		snapshot, err := sm.resourceManager.EnsureRetentionSnapshot(ctx, workspace)
+		if err != nil {
+			return false, err
+		}
+		switch snapshot.Status.Phase {
+		case workspacev1alpha1.SnapshotPhaseReady:
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "StorageSnapshotted",
+				fmt.Sprintf("Storage was snapshotted in WorkspaceSnapshot %s", snapshot.Name))
+			return true, nil
+		case workspacev1alpha1.SnapshotPhaseFailed:
+			return true, sm.releasePVC(ctx, workspace,
+				fmt.Sprintf("WorkspaceSnapshot %s failed: %s", snapshot.Name, snapshot.Status.Message))
+		default:
+			return false, nil
+		}
+	}
+
+	return true, sm.releasePVC(ctx, workspace, "")
+}
+
+// releasePVC releases the PVC of a deleted workspace and records why it was retained
+func (sm *StateMachine) releasePVC(ctx context.Context, workspace *workspacev1alpha1.Workspace, fallbackReason string) error {
+	pvcName, err := sm.resourceManager.ReleasePVC(ctx, workspace)
+	if err != nil || pvcName == "" {
+		return err
+	}
+	if fallbackReason != "" {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, "StorageRetained",
+			fmt.Sprintf("Retained PVC %s instead of snapshotting it: %s", pvcName, fallbackReason))
+		return nil
+	}
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "StorageRetained", fmt.Sprintf("Retained PVC %s", pvcName))
+	return nil
+}
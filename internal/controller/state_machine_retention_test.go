@@ -0,0 +1,172 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newRetentionTestWorkspace(uid string, policy workspacev1alpha1.StorageRetainPolicy) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Namespace:   "team-a",
+			UID:         types.UID(uid),
+			Annotations: map[string]string{AnnotationCreatedBy: "alice"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi"), RetainPolicy: policy},
+		},
+	}
+}
+
+// newRetentionTestStateMachine returns a state machine whose workspace has a PVC and is being deleted
+func newRetentionTestStateMachine(
+	t *testing.T, workspace *workspacev1alpha1.Workspace, snapshotsEnabled bool) (*StateMachine, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	pvc, err := NewPVCBuilder(scheme).BuildPVC(workspace)
+	require.NoError(t, err)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pvc).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceSnapshot{}).Build()
+	now := metav1.Now()
+	workspace.DeletionTimestamp = &now
+
+	statusManager := NewStatusManager(fakeClient)
+	return &StateMachine{
+		resourceManager:           NewResourceManager(fakeClient, scheme, nil, nil, nil, nil, statusManager),
+		statusManager:             statusManager,
+		recorder:                  record.NewFakeRecorder(10),
+		workspaceSnapshotsEnabled: snapshotsEnabled,
+	}, fakeClient
+}
+
+func getRetentionTestPVC(t *testing.T, c client.Client) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "workspace-ws-pvc", Namespace: "team-a"}, pvc))
+	return pvc
+}
+
+func TestRetainPrimaryStorage_Delete(t *testing.T) {
+	ctx := context.Background()
+	workspace := newRetentionTestWorkspace("uid-ws-1", "")
+	sm, fakeClient := newRetentionTestStateMachine(t, workspace, true)
+
+	retained, err := sm.retainPrimaryStorage(ctx, workspace)
+
+	require.NoError(t, err)
+	assert.True(t, retained)
+	assert.True(t, metav1.IsControlledBy(getRetentionTestPVC(t, fakeClient), workspace))
+}
+
+func TestRetainPrimaryStorage_Retain(t *testing.T) {
+	ctx := context.Background()
+	workspace := newRetentionTestWorkspace("uid-ws-1", workspacev1alpha1.StorageRetainPolicyRetain)
+	sm, fakeClient := newRetentionTestStateMachine(t, workspace, false)
+	rm := sm.resourceManager
+
+	retained, err := sm.retainPrimaryStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, retained)
+	pvc := getRetentionTestPVC(t, fakeClient)
+	assert.Empty(t, pvc.OwnerReferences)
+	assert.Equal(t, "alice", pvc.Annotations[AnnotationRetainedFor])
+
+	// The cleanup of the deleted workspace leaves the released PVC
+	_, err = rm.EnsurePVCDeleted(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, rm.AreAllResourcesDeleted(ctx, workspace))
+	getRetentionTestPVC(t, fakeClient)
+
+	// A new workspace of another owner cannot take the PVC
+	other := newRetentionTestWorkspace("uid-ws-2", "")
+	other.Annotations[AnnotationCreatedBy] = "bob"
+	_, err = rm.getPVC(ctx, other)
+	assert.True(t, errors.Is(err, ErrResourceNameCollision))
+
+	// A new workspace with the same name and owner adopts it
+	successor := newRetentionTestWorkspace("uid-ws-3", "")
+	_, err = rm.getPVC(ctx, successor)
+	require.NoError(t, err)
+	pvc = getRetentionTestPVC(t, fakeClient)
+	assert.True(t, metav1.IsControlledBy(pvc, successor))
+	assert.NotContains(t, pvc.Annotations, AnnotationRetainedFor)
+}
+
+func TestRetainPrimaryStorage_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	workspace := newRetentionTestWorkspace("uid-ws-1", workspacev1alpha1.StorageRetainPolicySnapshot)
+	sm, fakeClient := newRetentionTestStateMachine(t, workspace, true)
+
+	// Waits for the snapshot
+	retained, err := sm.retainPrimaryStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, retained)
+	snapshot := &workspacev1alpha1.WorkspaceSnapshot{}
+	key := types.NamespacedName{Name: GenerateRetentionSnapshotName(workspace), Namespace: "team-a"}
+	require.NoError(t, fakeClient.Get(ctx, key, snapshot))
+	assert.Equal(t, "ws-deleted-uid-ws-1", snapshot.Name)
+	assert.Equal(t, "ws", snapshot.Spec.WorkspaceName)
+	assert.Empty(t, snapshot.OwnerReferences)
+
+	// Lets the PVC be deleted once the snapshot is ready
+	snapshot.Status.Phase = workspacev1alpha1.SnapshotPhaseReady
+	require.NoError(t, fakeClient.Status().Update(ctx, snapshot))
+	retained, err = sm.retainPrimaryStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, retained)
+	assert.True(t, metav1.IsControlledBy(getRetentionTestPVC(t, fakeClient), workspace))
+}
+
+func TestRetainPrimaryStorage_SnapshotFallsBackToRetain(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("snapshot failed", func(t *testing.T) {
+		workspace := newRetentionTestWorkspace("uid-ws-1", workspacev1alpha1.StorageRetainPolicySnapshot)
+		sm, fakeClient := newRetentionTestStateMachine(t, workspace, true)
+		snapshot, err := sm.resourceManager.EnsureRetentionSnapshot(ctx, workspace)
+		require.NoError(t, err)
+		snapshot.Status.Phase = workspacev1alpha1.SnapshotPhaseFailed
+		require.NoError(t, fakeClient.Status().Update(ctx, snapshot))
+
+		retained, err := sm.retainPrimaryStorage(ctx, workspace)
+
+		require.NoError(t, err)
+		assert.True(t, retained)
+		assert.Empty(t, getRetentionTestPVC(t, fakeClient).OwnerReferences)
+	})
+
+	t.Run("snapshots disabled", func(t *testing.T) {
+		workspace := newRetentionTestWorkspace("uid-ws-1", workspacev1alpha1.StorageRetainPolicySnapshot)
+		sm, fakeClient := newRetentionTestStateMachine(t, workspace, false)
+
+		retained, err := sm.retainPrimaryStorage(ctx, workspace)
+
+		require.NoError(t, err)
+		assert.True(t, retained)
+		assert.Empty(t, getRetentionTestPVC(t, fakeClient).OwnerReferences)
+		snapshots := &workspacev1alpha1.WorkspaceSnapshotList{}
+		require.NoError(t, fakeClient.List(ctx, snapshots))
+		assert.Empty(t, snapshots.Items)
+	})
+}
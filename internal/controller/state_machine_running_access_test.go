@@ -34,7 +34,7 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 		replicas := int32(1)
 		dep := &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateDeploymentName(ws.Name),
+				Name:      GenerateDeploymentName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: appsv1.DeploymentSpec{
@@ -69,7 +69,7 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 	createService := func(ws *workspacev1alpha1.Workspace) *corev1.Service {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateServiceName(ws.Name),
+				Name:      GenerateServiceName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: corev1.ServiceSpec{
@@ -146,6 +146,7 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 			Expect(available).NotTo(BeNil())
 			Expect(available.Status).To(Equal(metav1.ConditionTrue))
 			Expect(available.Reason).To(Equal(ReasonResourcesReady))
+			Expect(getCondition(workspace, ConditionTypeReachable)).To(BeNil())
 		})
 	})
 
@@ -213,6 +214,11 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 			progressing := getCondition(workspace, ConditionTypeProgressing)
 			Expect(progressing).NotTo(BeNil())
 			Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+
+			reachable := getCondition(workspace, ConditionTypeReachable)
+			Expect(reachable).NotTo(BeNil())
+			Expect(reachable.Status).To(Equal(metav1.ConditionTrue))
+			Expect(reachable.Reason).To(Equal(ReasonAccessProbeSucceeded))
 		})
 
 		It("should mark Available when probe succeeds after previous failures", func() {
@@ -282,6 +288,12 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 			degraded := getCondition(workspace, ConditionTypeDegraded)
 			Expect(degraded).NotTo(BeNil())
 			Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+
+			reachable := getCondition(workspace, ConditionTypeReachable)
+			Expect(reachable).NotTo(BeNil())
+			Expect(reachable.Status).To(Equal(metav1.ConditionFalse))
+			Expect(reachable.Reason).To(Equal(ReasonAccessProbePending))
+			Expect(workspace.Status.AccessURL).To(BeEmpty())
 		})
 
 		It("should report AccessNotReady and requeue when probe is pending retry", func() {
@@ -424,6 +436,11 @@ var _ = Describe("reconcileDesiredRunningStatus probe integration", func() {
 			Expect(stopped).NotTo(BeNil())
 			Expect(stopped.Status).To(Equal(metav1.ConditionFalse))
 			Expect(stopped.Reason).To(Equal(ReasonDesiredStateRunning))
+
+			reachable := getCondition(workspace, ConditionTypeReachable)
+			Expect(reachable).NotTo(BeNil())
+			Expect(reachable.Status).To(Equal(metav1.ConditionFalse))
+			Expect(reachable.Reason).To(Equal(ReasonAccessProbeThresholdExceeded))
 		})
 
 		It("should propagate probe error", func() {
@@ -48,7 +48,7 @@ var _ = Describe("reconcileDesiredRunningStatus without access strategy", func()
 		replicas := int32(1)
 		dep := &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateDeploymentName(ws.Name),
+				Name:      GenerateDeploymentName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: appsv1.DeploymentSpec{
@@ -87,7 +87,7 @@ var _ = Describe("reconcileDesiredRunningStatus without access strategy", func()
 	createService := func(ws *workspacev1alpha1.Workspace) *corev1.Service {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateServiceName(ws.Name),
+				Name:      GenerateServiceName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: corev1.ServiceSpec{
@@ -105,7 +105,7 @@ var _ = Describe("reconcileDesiredRunningStatus without access strategy", func()
 	createLoadBalancerServiceWithoutIngress := func(ws *workspacev1alpha1.Workspace) *corev1.Service {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateServiceName(ws.Name),
+				Name:      GenerateServiceName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: corev1.ServiceSpec{
@@ -157,6 +157,7 @@ var _ = Describe("reconcileDesiredStoppedStatus access resources", func() {
 			degraded := getCondition(workspace, ConditionTypeDegraded)
 			Expect(degraded).NotTo(BeNil())
 			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal(ReasonAccessResourceFailed))
 		})
 
 		It("should propagate UpdateErrorStatus failure on access error", func() {
@@ -50,7 +50,7 @@ var _ = Describe("reconcileDesiredStoppedStatus without access strategy", func()
 		replicas := int32(1)
 		dep := &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateDeploymentName(ws.Name),
+				Name:      GenerateDeploymentName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: appsv1.DeploymentSpec{
@@ -76,7 +76,7 @@ var _ = Describe("reconcileDesiredStoppedStatus without access strategy", func()
 	createLiveService := func(ws *workspacev1alpha1.Workspace) *corev1.Service {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      GenerateServiceName(ws.Name),
+				Name:      GenerateServiceName(ws),
 				Namespace: ws.Namespace,
 			},
 			Spec: corev1.ServiceSpec{
@@ -97,7 +97,7 @@ var _ = Describe("reconcileDesiredStoppedStatus without access strategy", func()
 		replicas := int32(1)
 		dep := &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:       GenerateDeploymentName(ws.Name),
+				Name:       GenerateDeploymentName(ws),
 				Namespace:  ws.Namespace,
 				Finalizers: []string{testFinalizer},
 			},
@@ -133,7 +133,7 @@ var _ = Describe("reconcileDesiredStoppedStatus without access strategy", func()
 	createDeletingService := func(ws *workspacev1alpha1.Workspace) *corev1.Service {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:       GenerateServiceName(ws.Name),
+				Name:       GenerateServiceName(ws),
 				Namespace:  ws.Namespace,
 				Finalizers: []string{testFinalizer},
 			},
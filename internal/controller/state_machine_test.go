@@ -91,3 +91,10 @@ func TestTimeUntilProbeDeadline_InTheFuture(t *testing.T) {
 	remaining := timeUntilProbeDeadline(ws)
 	assert.True(t, remaining > 0 && remaining <= 5*time.Second)
 }
+
+func TestIsStoppedDesiredStatus(t *testing.T) {
+	assert.True(t, IsStoppedDesiredStatus(DesiredStateStopped))
+	assert.True(t, IsStoppedDesiredStatus(DesiredStateHibernated))
+	assert.False(t, IsStoppedDesiredStatus(DesiredStateRunning))
+	assert.False(t, IsStoppedDesiredStatus(""))
+}
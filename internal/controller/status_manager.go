@@ -11,8 +11,11 @@ import (
 	"reflect"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -41,18 +44,77 @@ func (sm *StatusManager) updateStatus(
 		workspace.Status.Conditions = *conditionsToUpdate
 	}
 
-	if reflect.DeepEqual(workspace.Status, snapshotStatus) {
+	if reflect.DeepEqual(workspace.Status, *snapshotStatus) {
 		// no-op: status hasn't changed
 		return nil
 	}
 
-	if err := sm.client.Status().Update(ctx, workspace); err != nil {
-		return fmt.Errorf("failed to update Workspace.Status: %w", err)
+	// Within a reconcile, only the last status is written, once the reconcile completes
+	if batch := statusBatchFromContext(ctx); batch != nil {
+		batch.record(workspace, snapshotStatus)
+		return nil
+	}
+
+	if err := sm.patchStatus(ctx, workspace, snapshotStatus, &workspace.Status); err != nil {
+		return err
 	}
+	// Later updates of the same reconcile only send their own changes
+	*snapshotStatus = *workspace.Status.DeepCopy()
 	logger.Info("updated Workspace.Status")
 	return nil
 }
 
+// patchStatus patches the status of the workspace from base, the status it was read with, to
+// desired. The patch only carries the fields that changed and fails on a conflict if the workspace
+// changed since it was read; the changes are then applied to the latest workspace and patched again,
+// so that writers of other status fields, such as the remote access of the pod, never make a
+// reconcile fail.
+func (sm *StatusManager) patchStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	base *workspacev1alpha1.WorkspaceStatus,
+	desired *workspacev1alpha1.WorkspaceStatus,
+) error {
+	original := workspace.DeepCopy()
+	original.Status = *base.DeepCopy()
+	target := original.DeepCopy()
+	target.Status = *desired.DeepCopy()
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := sm.client.Status().Patch(ctx, target, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+		latest := &workspacev1alpha1.Workspace{}
+		if getErr := sm.client.Get(ctx, client.ObjectKeyFromObject(workspace), latest); getErr != nil {
+			return getErr
+		}
+		logf.FromContext(ctx).V(1).Info("Workspace changed since it was read, rebasing the status update",
+			"resourceVersion", latest.ResourceVersion)
+		original = latest
+		target = latest.DeepCopy()
+		rebaseStatus(&target.Status, base, desired.DeepCopy())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update Workspace.Status: %w", err)
+	}
+	workspace.ResourceVersion = target.ResourceVersion
+	return nil
+}
+
+// rebaseStatus sets the fields of status that changed from base to desired to their desired value
+func rebaseStatus(status, base, desired *workspacev1alpha1.WorkspaceStatus) {
+	statusValue := reflect.ValueOf(status).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	desiredValue := reflect.ValueOf(desired).Elem()
+	for i := range statusValue.NumField() {
+		if !reflect.DeepEqual(baseValue.Field(i).Interface(), desiredValue.Field(i).Interface()) {
+			statusValue.Field(i).Set(desiredValue.Field(i))
+		}
+	}
+}
+
 // IsWorkspaceAvailable checks if the workspace is in Available=True state
 func (sm *StatusManager) IsWorkspaceAvailable(workspace *workspacev1alpha1.Workspace) bool {
 	for _, condition := range workspace.Status.Conditions {
@@ -68,6 +130,13 @@ type WorkspaceRunningReadiness struct {
 	computeReady         bool
 	serviceReady         bool
 	accessResourcesReady bool
+	// storageProvisioning is set while the storage provisioning hook runs, before any other
+	// resource is created
+	storageProvisioning bool
+	// computeFailureReason is the stable Degraded reason of a failure that keeps the pod from
+	// starting, e.g. an image that cannot be pulled; empty when there is none
+	computeFailureReason  string
+	computeFailureMessage string
 }
 
 // UpdateStartingStatus sets Available to false and Progressing to true
@@ -81,7 +150,7 @@ func (sm *StatusManager) UpdateStartingStatus(
 	startingReason := ReasonResourcesNotReady
 	startingMessage := "Workspace is starting"
 
-	if readiness.computeReady && readiness.serviceReady && readiness.accessResourcesReady {
+	if readiness.computeReady && readiness.serviceReady && readiness.accessResourcesReady && !readiness.storageProvisioning {
 		return fmt.Errorf("invalid call: not all resources should be ready in method UpdateStartingStatus")
 	}
 
@@ -89,7 +158,10 @@ func (sm *StatusManager) UpdateStartingStatus(
 	waitingForService := readiness.computeReady && !readiness.serviceReady && readiness.accessResourcesReady
 	waitingForAccess := readiness.computeReady && readiness.serviceReady && !readiness.accessResourcesReady
 
-	if waitingForCompute {
+	if readiness.storageProvisioning {
+		startingReason = ReasonStorageNotReady
+		startingMessage = "Storage provisioning hook is running"
+	} else if waitingForCompute {
 		startingReason = ReasonComputeNotReady
 		startingMessage = "Compute is not ready"
 	} else if waitingForAccess {
@@ -115,13 +187,21 @@ func (sm *StatusManager) UpdateStartingStatus(
 		startingMessage,
 	)
 
-	// ensure DegradedCondition is set to False with ReasonNoError
+	// ensure DegradedCondition is set to False with ReasonNoError, unless the pod cannot start
 	degradedCondition := NewCondition(
 		ConditionTypeDegraded,
 		metav1.ConditionFalse,
 		ReasonNoError,
 		"No errors detected",
 	)
+	if readiness.computeFailureReason != "" {
+		degradedCondition = NewCondition(
+			ConditionTypeDegraded,
+			metav1.ConditionTrue,
+			readiness.computeFailureReason,
+			readiness.computeFailureMessage,
+		)
+	}
 
 	// ensure StoppedCondition is set to False with ReasonDesiredStateRunning
 	stoppedCondition := NewCondition(
@@ -201,13 +281,18 @@ func (sm *StatusManager) UpdateRunningStatus(
 	ctx context.Context,
 	workspace *workspacev1alpha1.Workspace,
 	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
-	// ensure AvailableCondition is set to true with ReasonResourcesReady
+	// ensure AvailableCondition is set to true with ReasonResourcesReady,
+	// or ReasonAccessDisabled when the workspace runs without access resources
 	availableCondition := NewCondition(
 		ConditionTypeAvailable,
 		metav1.ConditionTrue,
 		ReasonResourcesReady,
 		"Workspace is ready",
 	)
+	if !workspaceutil.IsAccessEnabled(workspace) {
+		availableCondition.Reason = ReasonAccessDisabled
+		availableCondition.Message = "Workspace is running with access disabled"
+	}
 
 	// ensure ProgressingCondition is set to false with ReasonResourcesReady
 	progressingCondition := NewCondition(
@@ -240,6 +325,9 @@ func (sm *StatusManager) UpdateRunningStatus(
 		"Workspace is running",
 	)
 
+	// the workspace is no longer starting
+	workspace.Status.Startup = nil
+
 	// apply all conditions
 	conditions := []metav1.Condition{
 		availableCondition,
@@ -406,12 +494,74 @@ func (sm *StatusManager) UpdateStoppedStatus(
 		deletingCondition,
 	}
 
+	// No generated resource is left to drift
+	removeDriftDetectedCondition(workspace)
 	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
 
 	// Clear resource names since all workspace resources have been deleted at this point.
 	// This prevents stale references and signals that no active resources exist.
 	workspace.Status.DeploymentName = ""
 	workspace.Status.ServiceName = ""
+	workspace.Status.EffectiveSpec = nil
+	workspace.Status.PodStatus = nil
+	workspace.Status.Applications = nil
+	workspace.Status.Startup = nil
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateHibernatedStatus sets Available and Progressing to false and Stopped to true, like
+// UpdateStoppedStatus, with the hibernation reasons. It also drops the culling status, since a
+// hibernated workspace has no compute to cull.
+func (sm *StatusManager) UpdateHibernatedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(
+			ConditionTypeAvailable,
+			metav1.ConditionFalse,
+			ReasonDesiredStateHibernated,
+			"Workspace is hibernated",
+		),
+		NewCondition(
+			ConditionTypeProgressing,
+			metav1.ConditionFalse,
+			ReasonDesiredStateHibernated,
+			"Workspace is hibernated",
+		),
+		NewCondition(
+			ConditionTypeDegraded,
+			metav1.ConditionFalse,
+			ReasonNoError,
+			"No errors detected",
+		),
+		NewCondition(
+			ConditionTypeStopped,
+			metav1.ConditionTrue,
+			ReasonResourcesHibernated,
+			"Workspace is hibernated",
+		),
+		NewCondition(
+			ConditionTypeDeleting,
+			metav1.ConditionFalse,
+			ReasonDesiredStateHibernated,
+			"Workspace is hibernated",
+		),
+	}
+
+	// No generated resource is left to drift
+	removeDriftDetectedCondition(workspace)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+
+	// Only the storage and the access URL placeholder remain
+	workspace.Status.DeploymentName = ""
+	workspace.Status.ServiceName = ""
+	workspace.Status.EffectiveSpec = nil
+	workspace.Status.PodStatus = nil
+	workspace.Status.Applications = nil
+	workspace.Status.Startup = nil
+	workspace.Status.Culling = nil
+
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
 
@@ -434,5 +584,9 @@ func (sm *StatusManager) UpdateDeletingStatus(
 
 	workspace.Status.DeploymentName = ""
 	workspace.Status.ServiceName = ""
+	workspace.Status.EffectiveSpec = nil
+	workspace.Status.PodStatus = nil
+	workspace.Status.Applications = nil
+	workspace.Status.Startup = nil
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
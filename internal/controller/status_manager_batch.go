@@ -0,0 +1,68 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+type statusBatchKey struct{}
+
+// statusBatch holds the status updates of the workspace during a reconcile
+type statusBatch struct {
+	workspace *workspacev1alpha1.Workspace
+	// base is the status the workspace was read with
+	base *workspacev1alpha1.WorkspaceStatus
+	// desired is the status of the last update
+	desired *workspacev1alpha1.WorkspaceStatus
+}
+
+// WithStatusBatch returns a context in which the StatusManager records the status updates of a
+// workspace instead of writing them, until FlushStatus writes the last one in a single patch
+func WithStatusBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusBatchKey{}, &statusBatch{})
+}
+
+func statusBatchFromContext(ctx context.Context) *statusBatch {
+	batch, _ := ctx.Value(statusBatchKey{}).(*statusBatch)
+	return batch
+}
+
+// record keeps a copy of the status of the workspace, and the status it was read with on the
+// first update
+func (b *statusBatch) record(workspace *workspacev1alpha1.Workspace, snapshotStatus *workspacev1alpha1.WorkspaceStatus) {
+	if b.base == nil {
+		b.base = snapshotStatus.DeepCopy()
+	}
+	b.workspace = workspace
+	b.desired = workspace.Status.DeepCopy()
+}
+
+// FlushStatus writes the last status update recorded in the batch of the context, if any. A workspace
+// that no longer exists, e.g. once its finalizer is removed, is not an error.
+func (sm *StatusManager) FlushStatus(ctx context.Context) error {
+	batch := statusBatchFromContext(ctx)
+	if batch == nil || batch.desired == nil {
+		return nil
+	}
+	desired := batch.desired
+	batch.desired = nil
+
+	if err := sm.patchStatus(ctx, batch.workspace, batch.base, desired); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	batch.base = desired
+	logf.FromContext(ctx).Info("updated Workspace.Status")
+	return nil
+}
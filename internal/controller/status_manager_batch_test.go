@@ -0,0 +1,123 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newStatusPatchTestClient returns a client holding a workspace, and counts the status patches
+func newStatusPatchTestClient(t *testing.T) (client.Client, *workspacev1alpha1.Workspace, *int) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespace},
+	}
+	patches := 0
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object,
+				patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	read := &workspacev1alpha1.Workspace{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), read))
+	return fakeClient, read, &patches
+}
+
+func getStatusPatchTestWorkspace(t *testing.T, c client.Client) *workspacev1alpha1.Workspace {
+	t.Helper()
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, c.Get(context.Background(),
+		client.ObjectKey{Name: testWorkspaceName, Namespace: testNamespace}, workspace))
+	return workspace
+}
+
+func TestStatusManager_RebasesStatusPatchOnConflict(t *testing.T) {
+	ctx := context.Background()
+	fakeClient, workspace, patches := newStatusPatchTestClient(t)
+	statusManager := NewStatusManager(fakeClient)
+	snapshot := workspace.Status.DeepCopy()
+
+	// The remote access of the pod is reported after the reconcile read the workspace
+	concurrent := getStatusPatchTestWorkspace(t, fakeClient)
+	concurrent.Status.RemoteAccess = &workspacev1alpha1.RemoteAccessStatus{PodUID: "pod-uid"}
+	require.NoError(t, fakeClient.Status().Update(ctx, concurrent))
+
+	require.NoError(t, statusManager.UpdateRunningStatus(ctx, workspace, snapshot))
+
+	assert.Equal(t, 2, *patches)
+	stored := getStatusPatchTestWorkspace(t, fakeClient)
+	assert.Equal(t, "pod-uid", stored.Status.RemoteAccess.PodUID)
+	available := FindCondition(&stored.Status.Conditions, ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, metav1.ConditionTrue, available.Status)
+	assert.Equal(t, stored.ResourceVersion, workspace.ResourceVersion)
+	assert.Equal(t, workspace.Status, *snapshot)
+}
+
+func TestStatusManager_SkipsUnchangedStatus(t *testing.T) {
+	ctx := context.Background()
+	fakeClient, workspace, patches := newStatusPatchTestClient(t)
+	statusManager := NewStatusManager(fakeClient)
+	snapshot := workspace.Status.DeepCopy()
+
+	require.NoError(t, statusManager.UpdateRunningStatus(ctx, workspace, snapshot))
+	require.NoError(t, statusManager.UpdateRunningStatus(ctx, workspace, snapshot))
+
+	assert.Equal(t, 1, *patches)
+}
+
+func TestStatusManager_CoalescesStatusUpdatesOfBatch(t *testing.T) {
+	ctx := WithStatusBatch(context.Background())
+	fakeClient, workspace, patches := newStatusPatchTestClient(t)
+	statusManager := NewStatusManager(fakeClient)
+	snapshot := workspace.Status.DeepCopy()
+
+	require.NoError(t, statusManager.UpdateStartingStatus(ctx, workspace, WorkspaceRunningReadiness{}, snapshot))
+	require.NoError(t, statusManager.UpdateErrorStatus(ctx, workspace, ReasonDeploymentError, "boom", snapshot))
+	require.NoError(t, statusManager.UpdateRunningStatus(ctx, workspace, snapshot))
+	assert.Equal(t, 0, *patches)
+	assert.Empty(t, getStatusPatchTestWorkspace(t, fakeClient).Status.Conditions)
+
+	require.NoError(t, statusManager.FlushStatus(ctx))
+	assert.Equal(t, 1, *patches)
+	stored := getStatusPatchTestWorkspace(t, fakeClient)
+	degraded := FindCondition(&stored.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionFalse, degraded.Status)
+
+	// Nothing is left to write
+	require.NoError(t, statusManager.FlushStatus(ctx))
+	assert.Equal(t, 1, *patches)
+}
+
+func TestStatusManager_FlushStatusIgnoresDeletedWorkspace(t *testing.T) {
+	ctx := WithStatusBatch(context.Background())
+	fakeClient, workspace, _ := newStatusPatchTestClient(t)
+	statusManager := NewStatusManager(fakeClient)
+
+	require.NoError(t, statusManager.UpdateDeletingStatus(ctx, workspace, workspace.Status.DeepCopy()))
+	require.NoError(t, fakeClient.Delete(ctx, workspace))
+
+	assert.NoError(t, statusManager.FlushStatus(ctx))
+}
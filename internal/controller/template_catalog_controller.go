@@ -0,0 +1,239 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Template catalog ConfigMap
+const (
+	// DefaultTemplateCatalogConfigMapName is the default name of the template catalog ConfigMap
+	DefaultTemplateCatalogConfigMapName = "workspace-template-catalog"
+
+	// TemplateCatalogDataKey is the key of the catalog in the data of the ConfigMap
+	TemplateCatalogDataKey = "catalog.json"
+
+	// TemplateCatalogLabel marks the ConfigMap holding the template catalog
+	TemplateCatalogLabel = "workspace.jupyter.org/template-catalog"
+)
+
+// TemplateCatalog lists the workspace templates with the metadata UIs display when users pick one,
+// and indexes them by the users and groups that may use them. A user may use the public templates,
+// those listed under their name and those listed under any of their groups.
+type TemplateCatalog struct {
+	// SharedNamespace is the namespace whose templates workspaces of any namespace may use, empty
+	// when workspaces may only use the templates of their own namespace
+	SharedNamespace string `json:"sharedNamespace,omitempty"`
+
+	// Templates lists the templates, ordered by namespace and name
+	Templates []TemplateCatalogEntry `json:"templates"`
+
+	// Public lists the keys of the templates anyone may use
+	Public []string `json:"public"`
+
+	// Users maps user names to the keys of the templates that list them in allowedUsers
+	Users map[string][]string `json:"users"`
+
+	// Groups maps group names to the keys of the templates that list them in allowedGroups
+	Groups map[string][]string `json:"groups"`
+}
+
+// TemplateCatalogEntry describes a template in the catalog
+type TemplateCatalogEntry struct {
+	// Key identifies the template in the indexes of the catalog, as <namespace>/<name>
+	Key string `json:"key"`
+
+	Namespace       string                            `json:"namespace"`
+	Name            string                            `json:"name"`
+	DisplayName     string                            `json:"displayName"`
+	Description     string                            `json:"description,omitempty"`
+	ApplicationType workspacev1alpha1.ApplicationType `json:"applicationType,omitempty"`
+
+	// DefaultImage and AllowedImages are the images users may pick, and AllowCustomImages whether
+	// they may set any other image
+	DefaultImage      string   `json:"defaultImage"`
+	AllowedImages     []string `json:"allowedImages,omitempty"`
+	AllowCustomImages bool     `json:"allowCustomImages,omitempty"`
+
+	// DefaultResources, Profiles and DefaultProfile are the sizes users may pick
+	DefaultResources *corev1.ResourceRequirements        `json:"defaultResources,omitempty"`
+	Profiles         []workspacev1alpha1.ResourceProfile `json:"profiles,omitempty"`
+	DefaultProfile   string                              `json:"defaultProfile,omitempty"`
+
+	// AllowedUsers and AllowedGroups restrict who may use the template, anyone when both are empty
+	AllowedUsers  []string `json:"allowedUsers,omitempty"`
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+}
+
+// TemplatesFor returns the keys of the templates the user may use, given their groups
+func (c *TemplateCatalog) TemplatesFor(username string, groups []string) []string {
+	keys := slices.Clone(c.Public)
+	keys = append(keys, c.Users[username]...)
+	for _, group := range groups {
+		keys = append(keys, c.Groups[group]...)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// BuildTemplateCatalog builds the catalog of templates. Templates being deleted are left out.
+func BuildTemplateCatalog(templates []workspacev1alpha1.WorkspaceTemplate, sharedNamespace string) *TemplateCatalog {
+	catalog := &TemplateCatalog{
+		SharedNamespace: sharedNamespace,
+		Templates:       []TemplateCatalogEntry{},
+		Public:          []string{},
+		Users:           map[string][]string{},
+		Groups:          map[string][]string{},
+	}
+	for i := range templates {
+		template := &templates[i]
+		if !template.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := template.Namespace + "/" + template.Name
+		spec := &template.Spec
+		catalog.Templates = append(catalog.Templates, TemplateCatalogEntry{
+			Key:               key,
+			Namespace:         template.Namespace,
+			Name:              template.Name,
+			DisplayName:       spec.DisplayName,
+			Description:       spec.Description,
+			ApplicationType:   spec.ApplicationType,
+			DefaultImage:      spec.DefaultImage,
+			AllowedImages:     spec.AllowedImages,
+			AllowCustomImages: spec.AllowCustomImages != nil && *spec.AllowCustomImages,
+			DefaultResources:  spec.DefaultResources,
+			Profiles:          spec.Profiles,
+			DefaultProfile:    spec.DefaultProfile,
+			AllowedUsers:      spec.AllowedUsers,
+			AllowedGroups:     spec.AllowedGroups,
+		})
+
+		if len(spec.AllowedUsers) == 0 && len(spec.AllowedGroups) == 0 {
+			catalog.Public = append(catalog.Public, key)
+			continue
+		}
+		for _, user := range spec.AllowedUsers {
+			catalog.Users[user] = append(catalog.Users[user], key)
+		}
+		for _, group := range spec.AllowedGroups {
+			catalog.Groups[group] = append(catalog.Groups[group], key)
+		}
+	}
+
+	sort.Slice(catalog.Templates, func(i, j int) bool { return catalog.Templates[i].Key < catalog.Templates[j].Key })
+	slices.Sort(catalog.Public)
+	for _, index := range []map[string][]string{catalog.Users, catalog.Groups} {
+		for name, keys := range index {
+			slices.Sort(keys)
+			index[name] = slices.Compact(keys)
+		}
+	}
+	return catalog
+}
+
+// TemplateCatalogReconciler maintains the template catalog in a ConfigMap of the controller
+// namespace, so that UIs read the templates a user may use without listing and filtering the
+// templates of the cluster themselves. Every template change rebuilds the whole catalog.
+type TemplateCatalogReconciler struct {
+	client.Client
+	// apiReader reads the catalog ConfigMap from the API server, since ConfigMaps are not cached
+	apiReader client.Reader
+	// configMap is the namespaced name of the catalog ConfigMap
+	configMap types.NamespacedName
+	// sharedNamespace is the namespace of the templates shared with all namespaces
+	sharedNamespace string
+}
+
+// Reconcile rebuilds the template catalog and updates the ConfigMap when it changed
+func (r *TemplateCatalogReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("configMap", r.configMap.Name, "namespace", r.configMap.Namespace)
+
+	templates := &workspacev1alpha1.WorkspaceTemplateList{}
+	if err := r.List(ctx, templates); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list workspace templates: %w", err)
+	}
+	catalog := BuildTemplateCatalog(templates.Items, r.sharedNamespace)
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to encode template catalog: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.apiReader.Get(ctx, r.configMap, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get template catalog config map: %w", err)
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.configMap.Name,
+				Namespace: r.configMap.Namespace,
+				Labels:    map[string]string{TemplateCatalogLabel: "true"},
+			},
+			Data: map[string]string{TemplateCatalogDataKey: string(data)},
+		}
+		if err := r.Create(ctx, configMap); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create template catalog config map: %w", err)
+		}
+		logger.Info("Created template catalog", "templates", len(catalog.Templates))
+		return ctrl.Result{}, nil
+	}
+
+	if configMap.Data[TemplateCatalogDataKey] == string(data) {
+		return ctrl.Result{}, nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[TemplateCatalogDataKey] = string(data)
+	if err := r.Update(ctx, configMap); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update template catalog config map: %w", err)
+	}
+	logger.Info("Updated template catalog", "templates", len(catalog.Templates))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// All template events map to a single request, so that bursts of changes rebuild the catalog once.
+func (r *TemplateCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	request := reconcile.Request{NamespacedName: r.configMap}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("templatecatalog").
+		Watches(&workspacev1alpha1.WorkspaceTemplate{},
+			handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{request}
+			})).
+		Complete(r)
+}
+
+// SetupTemplateCatalogController sets up the template catalog controller with the Manager,
+// maintaining the catalog in the ConfigMap name of namespace
+func SetupTemplateCatalogController(mgr ctrl.Manager, namespace, name, sharedNamespace string) error {
+	reconciler := &TemplateCatalogReconciler{
+		Client:          mgr.GetClient(),
+		apiReader:       mgr.GetAPIReader(),
+		configMap:       types.NamespacedName{Namespace: namespace, Name: name},
+		sharedNamespace: sharedNamespace,
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testTemplateCatalogNamespace = "jupyter-k8s-system"
+
+func newTemplateCatalogTestTemplate(namespace, name string, users, groups []string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:   name,
+			DefaultImage:  "quay.io/jupyter/scipy-notebook:latest",
+			AllowedUsers:  users,
+			AllowedGroups: groups,
+		},
+	}
+}
+
+func newTemplateCatalogTestReconciler(t *testing.T, objs ...client.Object) (*TemplateCatalogReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	return &TemplateCatalogReconciler{
+		Client:          fakeClient,
+		apiReader:       fakeClient,
+		configMap:       types.NamespacedName{Namespace: testTemplateCatalogNamespace, Name: DefaultTemplateCatalogConfigMapName},
+		sharedNamespace: "jupyter-k8s-shared",
+	}, fakeClient
+}
+
+func getTestTemplateCatalog(t *testing.T, c client.Client) (*corev1.ConfigMap, *TemplateCatalog) {
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+		Namespace: testTemplateCatalogNamespace, Name: DefaultTemplateCatalogConfigMapName}, configMap))
+	catalog := &TemplateCatalog{}
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data[TemplateCatalogDataKey]), catalog))
+	return configMap, catalog
+}
+
+func TestBuildTemplateCatalog(t *testing.T) {
+	gpu := newTemplateCatalogTestTemplate("jupyter-k8s-shared", "gpu", []string{"alice"}, []string{"ml-engineers"})
+	gpu.Spec.Description = "GPU workspaces"
+	gpu.Spec.AllowedImages = []string{"quay.io/jupyter/pytorch-notebook:cuda12"}
+	gpu.Spec.Profiles = []workspacev1alpha1.ResourceProfile{{
+		Name:        "large",
+		DisplayName: "Large",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+	}}
+	deleted := newTemplateCatalogTestTemplate("team-a", "deleted", nil, nil)
+	deleted.DeletionTimestamp = ptr.To(metav1.Now())
+	templates := []workspacev1alpha1.WorkspaceTemplate{
+		*newTemplateCatalogTestTemplate("team-a", "basic", nil, nil),
+		*gpu,
+		*newTemplateCatalogTestTemplate("team-a", "restricted", []string{"alice", "bob"}, nil),
+		*deleted,
+	}
+
+	catalog := BuildTemplateCatalog(templates, "jupyter-k8s-shared")
+
+	require.Len(t, catalog.Templates, 3)
+	assert.Equal(t, []string{"jupyter-k8s-shared/gpu", "team-a/basic", "team-a/restricted"},
+		[]string{catalog.Templates[0].Key, catalog.Templates[1].Key, catalog.Templates[2].Key})
+	assert.Equal(t, "GPU workspaces", catalog.Templates[0].Description)
+	assert.Equal(t, gpu.Spec.AllowedImages, catalog.Templates[0].AllowedImages)
+	assert.Equal(t, gpu.Spec.Profiles, catalog.Templates[0].Profiles)
+	assert.Equal(t, "jupyter-k8s-shared", catalog.SharedNamespace)
+	assert.Equal(t, []string{"team-a/basic"}, catalog.Public)
+	assert.Equal(t, map[string][]string{
+		"alice": {"jupyter-k8s-shared/gpu", "team-a/restricted"},
+		"bob":   {"team-a/restricted"},
+	}, catalog.Users)
+	assert.Equal(t, map[string][]string{"ml-engineers": {"jupyter-k8s-shared/gpu"}}, catalog.Groups)
+}
+
+func TestTemplateCatalogTemplatesFor(t *testing.T) {
+	catalog := BuildTemplateCatalog([]workspacev1alpha1.WorkspaceTemplate{
+		*newTemplateCatalogTestTemplate("team-a", "basic", nil, nil),
+		*newTemplateCatalogTestTemplate("team-a", "gpu", []string{"alice"}, []string{"ml-engineers"}),
+		*newTemplateCatalogTestTemplate("team-a", "restricted", []string{"bob"}, nil),
+	}, "")
+
+	assert.Equal(t, []string{"team-a/basic", "team-a/gpu"}, catalog.TemplatesFor("alice", []string{"ml-engineers"}))
+	assert.Equal(t, []string{"team-a/basic", "team-a/gpu", "team-a/restricted"},
+		catalog.TemplatesFor("bob", []string{"ml-engineers"}))
+	assert.Equal(t, []string{"team-a/basic"}, catalog.TemplatesFor("carol", nil))
+}
+
+func TestTemplateCatalogReconcileCreatesAndUpdatesConfigMap(t *testing.T) {
+	basic := newTemplateCatalogTestTemplate("team-a", "basic", nil, nil)
+	reconciler, fakeClient := newTemplateCatalogTestReconciler(t, basic)
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	configMap, catalog := getTestTemplateCatalog(t, fakeClient)
+	assert.Equal(t, "true", configMap.Labels[TemplateCatalogLabel])
+	assert.Equal(t, []string{"team-a/basic"}, catalog.Public)
+
+	require.NoError(t, fakeClient.Create(context.Background(),
+		newTemplateCatalogTestTemplate("team-a", "gpu", nil, []string{"ml-engineers"})))
+	_, err = reconciler.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	updated, catalog := getTestTemplateCatalog(t, fakeClient)
+	assert.Len(t, catalog.Templates, 2)
+	assert.Equal(t, map[string][]string{"ml-engineers": {"team-a/gpu"}}, catalog.Groups)
+
+	_, err = reconciler.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	unchanged, _ := getTestTemplateCatalog(t, fakeClient)
+	assert.Equal(t, updated.ResourceVersion, unchanged.ResourceVersion, "an unchanged catalog is not written again")
+}
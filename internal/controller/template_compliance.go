@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+)
+
+// TemplateCompliance is the result of checking a workspace against the current constraints of its template
+type TemplateCompliance struct {
+	// Template is the name of the template of the workspace
+	Template string
+
+	// ApplyMode is the apply mode of the template
+	ApplyMode workspacev1alpha1.TemplateApplyMode
+
+	// Violations describes each constraint of the template that the workspace violates
+	Violations []string
+}
+
+// TemplateComplianceChecker checks workspaces against the constraints of their template. The
+// constraints are defined by the workspace admission webhook, which provides the implementation.
+type TemplateComplianceChecker interface {
+	// CheckTemplateCompliance returns nil when the workspace has no template
+	CheckTemplateCompliance(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*TemplateCompliance, error)
+}
+
+// resolveTemplateApplyMode returns the apply mode of a template, defaulting to Enforce
+func resolveTemplateApplyMode(mode workspacev1alpha1.TemplateApplyMode) workspacev1alpha1.TemplateApplyMode {
+	if mode == "" {
+		return workspacev1alpha1.TemplateApplyModeEnforce
+	}
+	return mode
+}
+
+// templateViolationReason returns the reason of the TemplateViolation condition under an apply mode
+func templateViolationReason(mode workspacev1alpha1.TemplateApplyMode) string {
+	switch resolveTemplateApplyMode(mode) {
+	case workspacev1alpha1.TemplateApplyModeMigrate:
+		return ReasonTemplateMigrationPending
+	case workspacev1alpha1.TemplateApplyModeAudit:
+		return ReasonTemplateViolationAudited
+	default:
+		return ReasonTemplateUpdatesBlocked
+	}
+}
+
+// templateViolationMessage describes the violations and what the apply mode does about them
+func templateViolationMessage(compliance *TemplateCompliance) string {
+	violations := strings.Join(compliance.Violations, "; ")
+	switch resolveTemplateApplyMode(compliance.ApplyMode) {
+	case workspacev1alpha1.TemplateApplyModeMigrate:
+		return "Workspace violates template '" + compliance.Template + "': " + violations +
+			"; the image, resources, priority class and runtime class are reset to the template defaults on next start"
+	case workspacev1alpha1.TemplateApplyModeAudit:
+		return "Workspace violates template '" + compliance.Template + "': " + violations
+	default:
+		return "Workspace violates template '" + compliance.Template + "': " + violations +
+			"; updates other than stopping the workspace are rejected until it satisfies the template"
+	}
+}
+
+// reportTemplateCompliance checks the workspace against the current constraints of its template and
+// reports the violations in the TemplateViolation condition, persisted by the status update that
+// follows. A failed check keeps the condition as it was, and the NotFound error of a missing
+// template is returned for the caller to report.
+func (sm *StateMachine) reportTemplateCompliance(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if sm.templateComplianceChecker == nil {
+		return nil
+	}
+	logger := logf.FromContext(ctx)
+
+	compliance, err := sm.templateComplianceChecker.CheckTemplateCompliance(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to check workspace against its template")
+		if errs.Is(err, errs.KindNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	existing := FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateViolation)
+	if compliance == nil || len(compliance.Violations) == 0 {
+		if existing == nil {
+			return nil
+		}
+		removeTemplateViolationCondition(workspace)
+		if existing.Reason == ReasonTemplateMigrationPending {
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "TemplateMigrated",
+				"Workspace was migrated to the constraints of its template")
+		} else {
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "TemplateViolationResolved",
+				"Workspace satisfies the constraints of its template")
+		}
+		return nil
+	}
+
+	reason := templateViolationReason(compliance.ApplyMode)
+	message := templateViolationMessage(compliance)
+	if existing == nil || existing.Reason != reason || existing.Message != message {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, "TemplateViolation", message)
+	}
+	condition := NewCondition(ConditionTypeTemplateViolation, metav1.ConditionTrue, reason, message)
+	if conditions := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{condition}); len(conditions) > 0 {
+		workspace.Status.Conditions = conditions
+	}
+	return nil
+}
+
+// removeTemplateViolationCondition drops the TemplateViolation condition once the workspace satisfies its template
+func removeTemplateViolationCondition(workspace *workspacev1alpha1.Workspace) {
+	conditions := make([]metav1.Condition, 0, len(workspace.Status.Conditions))
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type != ConditionTypeTemplateViolation {
+			conditions = append(conditions, condition)
+		}
+	}
+	workspace.Status.Conditions = conditions
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+)
+
+type fakeTemplateComplianceChecker struct {
+	compliance *TemplateCompliance
+	err        error
+}
+
+func (f *fakeTemplateComplianceChecker) CheckTemplateCompliance(
+	_ context.Context, _ *workspacev1alpha1.Workspace) (*TemplateCompliance, error) {
+	return f.compliance, f.err
+}
+
+func newTemplateComplianceTestStateMachine(checker TemplateComplianceChecker) (*StateMachine, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(10)
+	return &StateMachine{recorder: recorder, templateComplianceChecker: checker}, recorder
+}
+
+func newTemplateComplianceTestWorkspace(conditions ...metav1.Condition) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+		Status:     workspacev1alpha1.WorkspaceStatus{Conditions: conditions},
+	}
+}
+
+func TestReportTemplateCompliance_ReasonPerApplyMode(t *testing.T) {
+	for mode, reason := range map[workspacev1alpha1.TemplateApplyMode]string{
+		"": ReasonTemplateUpdatesBlocked,
+		workspacev1alpha1.TemplateApplyModeEnforce: ReasonTemplateUpdatesBlocked,
+		workspacev1alpha1.TemplateApplyModeMigrate: ReasonTemplateMigrationPending,
+		workspacev1alpha1.TemplateApplyModeAudit:   ReasonTemplateViolationAudited,
+	} {
+		sm, recorder := newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{
+			compliance: &TemplateCompliance{Template: "tpl", ApplyMode: mode, Violations: []string{"image 'a' is not allowed"}},
+		})
+		workspace := newTemplateComplianceTestWorkspace()
+
+		sm.reportTemplateCompliance(context.Background(), workspace)
+
+		condition := FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateViolation)
+		require.NotNil(t, condition, "mode %q", mode)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, reason, condition.Reason, "mode %q", mode)
+		assert.Contains(t, condition.Message, "image 'a' is not allowed")
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning TemplateViolation")
+	}
+}
+
+func TestReportTemplateCompliance_NoEventWhenUnchanged(t *testing.T) {
+	compliance := &TemplateCompliance{Template: "tpl", Violations: []string{"image 'a' is not allowed"}}
+	sm, recorder := newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{compliance: compliance})
+	workspace := newTemplateComplianceTestWorkspace(NewCondition(ConditionTypeTemplateViolation, metav1.ConditionTrue,
+		ReasonTemplateUpdatesBlocked, templateViolationMessage(compliance)))
+
+	sm.reportTemplateCompliance(context.Background(), workspace)
+
+	assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateViolation))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReportTemplateCompliance_RemovesConditionWhenCompliant(t *testing.T) {
+	for reason, event := range map[string]string{
+		ReasonTemplateMigrationPending: "Normal TemplateMigrated",
+		ReasonTemplateUpdatesBlocked:   "Normal TemplateViolationResolved",
+	} {
+		sm, recorder := newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{
+			compliance: &TemplateCompliance{Template: "tpl"},
+		})
+		workspace := newTemplateComplianceTestWorkspace(
+			NewCondition(ConditionTypeAvailable, metav1.ConditionTrue, ReasonResourcesReady, "Workspace is ready"),
+			NewCondition(ConditionTypeTemplateViolation, metav1.ConditionTrue, reason, "violations"))
+
+		sm.reportTemplateCompliance(context.Background(), workspace)
+
+		assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateViolation))
+		assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeAvailable))
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, event)
+	}
+}
+
+func TestReportTemplateCompliance_KeepsConditionOnError(t *testing.T) {
+	sm, recorder := newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{err: errors.New("template not found")})
+	workspace := newTemplateComplianceTestWorkspace(
+		NewCondition(ConditionTypeTemplateViolation, metav1.ConditionTrue, ReasonTemplateViolationAudited, "violations"))
+
+	sm.reportTemplateCompliance(context.Background(), workspace)
+
+	assert.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateViolation))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReportTemplateCompliance_DisabledWithoutChecker(t *testing.T) {
+	sm, recorder := newTemplateComplianceTestStateMachine(nil)
+	workspace := newTemplateComplianceTestWorkspace()
+
+	sm.reportTemplateCompliance(context.Background(), workspace)
+
+	assert.Empty(t, workspace.Status.Conditions)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReportTemplateCompliance_ReturnsMissingTemplate(t *testing.T) {
+	sm, _ := newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{
+		err: errs.NotFound(errors.New("not found"), "failed to get template tpl"),
+	})
+
+	err := sm.reportTemplateCompliance(context.Background(), newTemplateComplianceTestWorkspace())
+
+	require.Error(t, err)
+	assert.True(t, errs.Is(err, errs.KindNotFound))
+
+	sm, _ = newTemplateComplianceTestStateMachine(&fakeTemplateComplianceChecker{err: errors.New("connection refused")})
+	assert.NoError(t, sm.reportTemplateCompliance(context.Background(), newTemplateComplianceTestWorkspace()))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerPkg "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Vanity URL registration retries, once the retries of a single webhook call are exhausted
+const (
+	VanityURLRetryBaseDelay = 5 * time.Second
+	VanityURLRetryMaxDelay  = 10 * time.Minute
+)
+
+// VanityURLReconciler registers the access URL of running workspaces with an external URL
+// shortener or DNS registrar, and records the friendly URL in status.vanityURL.
+// The vanity URL is deregistered once the workspace loses its access URL, e.g. when it stops,
+// and before the workspace is deleted, which the VanityURLFinalizerName finalizer waits for.
+type VanityURLReconciler struct {
+	client.Client
+	registrar VanityURLRegistrarInterface
+}
+
+// Reconcile keeps the vanity URL of a workspace in sync with its access URL
+func (r *VanityURLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspace", req.Name, "namespace", req.Namespace)
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := r.Get(ctx, req.NamespacedName, workspace); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("Workspace not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	accessURL := workspace.Status.AccessURL
+	if !workspace.DeletionTimestamp.IsZero() {
+		accessURL = ""
+	}
+
+	registered := workspace.Status.VanityURL
+	if accessURL == "" {
+		if registered != nil {
+			if err := r.registrar.Deregister(ctx, workspace, registered.URL); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to deregister vanity URL: %w", err)
+			}
+			if err := r.updateVanityURLStatus(ctx, workspace, nil); err != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Info("Deregistered vanity URL", "vanityURL", registered.URL)
+		}
+		return ctrl.Result{}, r.removeFinalizer(ctx, workspace)
+	}
+
+	if registered != nil && registered.AccessURL == accessURL {
+		return ctrl.Result{}, nil
+	}
+
+	// Add the finalizer before registering, so that the vanity URL cannot outlive the workspace
+	if !controllerutil.ContainsFinalizer(workspace, VanityURLFinalizerName) {
+		patch := client.MergeFrom(workspace.DeepCopy())
+		controllerutil.AddFinalizer(workspace, VanityURLFinalizerName)
+		if err := r.Patch(ctx, workspace, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add vanity URL finalizer: %w", err)
+		}
+	}
+
+	vanityURL, err := r.registrar.Register(ctx, workspace, accessURL)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to register vanity URL: %w", err)
+	}
+	if err := r.updateVanityURLStatus(ctx, workspace, &workspacev1alpha1.VanityURLStatus{
+		URL:       vanityURL,
+		AccessURL: accessURL,
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Registered vanity URL", "vanityURL", vanityURL, "accessURL", accessURL)
+	return ctrl.Result{}, nil
+}
+
+// updateVanityURLStatus records the vanity URL of the workspace in status.vanityURL
+func (r *VanityURLReconciler) updateVanityURLStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	status *workspacev1alpha1.VanityURLStatus,
+) error {
+	patch := client.MergeFrom(workspace.DeepCopy())
+	workspace.Status.VanityURL = status
+	if err := r.Status().Patch(ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to update workspace vanity URL status: %w", err)
+	}
+	return nil
+}
+
+// removeFinalizer releases the workspace once its vanity URL is deregistered
+func (r *VanityURLReconciler) removeFinalizer(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if !controllerutil.ContainsFinalizer(workspace, VanityURLFinalizerName) {
+		return nil
+	}
+	patch := client.MergeFrom(workspace.DeepCopy())
+	controllerutil.RemoveFinalizer(workspace, VanityURLFinalizerName)
+	if err := r.Patch(ctx, workspace, patch); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove vanity URL finalizer: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Workspace status updates trigger reconciliations, so the vanity URL follows the access URL.
+func (r *VanityURLReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.Workspace{}).
+		Named("vanityurl").
+		WithOptions(controllerPkg.Options{
+			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
+				VanityURLRetryBaseDelay, VanityURLRetryMaxDelay),
+		}).
+		Complete(r)
+}
+
+// SetupVanityURLController sets up the vanity URL controller with the Manager,
+// registering vanity URLs with the webhook at endpoint
+func SetupVanityURLController(mgr ctrl.Manager, endpoint string) error {
+	reconciler := &VanityURLReconciler{
+		Client:    mgr.GetClient(),
+		registrar: NewVanityURLWebhookRegistrar(endpoint),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// mockVanityURLRegistrar implements VanityURLRegistrarInterface for testing
+type mockVanityURLRegistrar struct {
+	registeredAccessURL string
+	deregisteredURL     string
+	registerCalls       int
+	deregisterCalls     int
+	registerErr         error
+	deregisterErr       error
+}
+
+func (m *mockVanityURLRegistrar) Register(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, accessURL string,
+) (string, error) {
+	m.registerCalls++
+	m.registeredAccessURL = accessURL
+	if m.registerErr != nil {
+		return "", m.registerErr
+	}
+	return testVanityURL, nil
+}
+
+func (m *mockVanityURLRegistrar) Deregister(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, vanityURL string,
+) error {
+	m.deregisterCalls++
+	m.deregisteredURL = vanityURL
+	return m.deregisterErr
+}
+
+func newVanityURLTestReconciler(
+	t *testing.T,
+	registrar *mockVanityURLRegistrar,
+	objs ...client.Object,
+) (*VanityURLReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).Build()
+
+	return &VanityURLReconciler{Client: fakeClient, registrar: registrar}, fakeClient
+}
+
+func reconcileVanityURL(t *testing.T, reconciler *VanityURLReconciler) error {
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Name: testWorkspaceName, Namespace: testNamespaceName},
+	})
+	return err
+}
+
+func getVanityURLTestWorkspace(t *testing.T, c client.Client) *workspacev1alpha1.Workspace {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, c.Get(context.Background(),
+		client.ObjectKey{Name: testWorkspaceName, Namespace: testNamespaceName}, workspace))
+	return workspace
+}
+
+func TestVanityURLReconciler_RegistersAccessURL(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Status.AccessURL = testAccessURL
+	registrar := &mockVanityURLRegistrar{}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	require.NoError(t, reconcileVanityURL(t, reconciler))
+
+	assert.Equal(t, testAccessURL, registrar.registeredAccessURL)
+	updated := getVanityURLTestWorkspace(t, c)
+	assert.Contains(t, updated.Finalizers, VanityURLFinalizerName)
+	require.NotNil(t, updated.Status.VanityURL)
+	assert.Equal(t, testVanityURL, updated.Status.VanityURL.URL)
+	assert.Equal(t, testAccessURL, updated.Status.VanityURL.AccessURL)
+}
+
+func TestVanityURLReconciler_SkipsRegisteredAccessURL(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Finalizers = []string{VanityURLFinalizerName}
+	workspace.Status.AccessURL = testAccessURL
+	workspace.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{URL: testVanityURL, AccessURL: testAccessURL}
+	registrar := &mockVanityURLRegistrar{}
+	reconciler, _ := newVanityURLTestReconciler(t, registrar, workspace)
+
+	require.NoError(t, reconcileVanityURL(t, reconciler))
+
+	assert.Zero(t, registrar.registerCalls)
+	assert.Zero(t, registrar.deregisterCalls)
+}
+
+func TestVanityURLReconciler_ReregistersChangedAccessURL(t *testing.T) {
+	newAccessURL := "https://jupyter.example.com/workspaces/default/test-workspace-2/"
+	workspace := newVanityURLTestWorkspace()
+	workspace.Finalizers = []string{VanityURLFinalizerName}
+	workspace.Status.AccessURL = newAccessURL
+	workspace.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{URL: testVanityURL, AccessURL: testAccessURL}
+	registrar := &mockVanityURLRegistrar{}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	require.NoError(t, reconcileVanityURL(t, reconciler))
+
+	assert.Equal(t, newAccessURL, registrar.registeredAccessURL)
+	assert.Equal(t, newAccessURL, getVanityURLTestWorkspace(t, c).Status.VanityURL.AccessURL)
+}
+
+func TestVanityURLReconciler_RegisterErrorKeepsFinalizer(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Status.AccessURL = testAccessURL
+	registrar := &mockVanityURLRegistrar{registerErr: errors.New("registrar unavailable")}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	err := reconcileVanityURL(t, reconciler)
+
+	require.Error(t, err)
+	updated := getVanityURLTestWorkspace(t, c)
+	assert.Contains(t, updated.Finalizers, VanityURLFinalizerName)
+	assert.Nil(t, updated.Status.VanityURL)
+}
+
+func TestVanityURLReconciler_DeregistersStoppedWorkspace(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Finalizers = []string{VanityURLFinalizerName}
+	workspace.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{URL: testVanityURL, AccessURL: testAccessURL}
+	registrar := &mockVanityURLRegistrar{}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	require.NoError(t, reconcileVanityURL(t, reconciler))
+
+	assert.Equal(t, testVanityURL, registrar.deregisteredURL)
+	updated := getVanityURLTestWorkspace(t, c)
+	assert.NotContains(t, updated.Finalizers, VanityURLFinalizerName)
+	assert.Nil(t, updated.Status.VanityURL)
+}
+
+func TestVanityURLReconciler_DeregisterErrorKeepsStatus(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Finalizers = []string{VanityURLFinalizerName}
+	workspace.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{URL: testVanityURL, AccessURL: testAccessURL}
+	registrar := &mockVanityURLRegistrar{deregisterErr: errors.New("registrar unavailable")}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	err := reconcileVanityURL(t, reconciler)
+
+	require.Error(t, err)
+	updated := getVanityURLTestWorkspace(t, c)
+	assert.Contains(t, updated.Finalizers, VanityURLFinalizerName)
+	assert.NotNil(t, updated.Status.VanityURL)
+}
+
+func TestVanityURLReconciler_DeregistersDeletedWorkspace(t *testing.T) {
+	workspace := newVanityURLTestWorkspace()
+	workspace.Finalizers = []string{VanityURLFinalizerName}
+	workspace.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	workspace.Status.AccessURL = testAccessURL
+	workspace.Status.VanityURL = &workspacev1alpha1.VanityURLStatus{URL: testVanityURL, AccessURL: testAccessURL}
+	registrar := &mockVanityURLRegistrar{}
+	reconciler, c := newVanityURLTestReconciler(t, registrar, workspace)
+
+	require.NoError(t, reconcileVanityURL(t, reconciler))
+
+	assert.Equal(t, 1, registrar.deregisterCalls)
+	assert.Zero(t, registrar.registerCalls)
+	// Removing the last finalizer lets the fake client delete the workspace
+	err := c.Get(context.Background(),
+		client.ObjectKey{Name: testWorkspaceName, Namespace: testNamespaceName}, &workspacev1alpha1.Workspace{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Vanity URL webhook actions
+const (
+	VanityURLActionRegister   = "register"
+	VanityURLActionDeregister = "deregister"
+)
+
+// Vanity URL webhook calls are retried on transient errors before the reconciliation backs off
+const (
+	vanityURLWebhookTimeout      = 10 * time.Second
+	vanityURLWebhookAttempts     = 3
+	vanityURLWebhookInitialDelay = 500 * time.Millisecond
+	vanityURLWebhookMaxBodyBytes = 64 * 1024
+)
+
+// VanityURLRegistrarInterface registers the access URL of workspaces with an external URL
+// shortener or DNS registrar, which gives each workspace a friendly URL
+type VanityURLRegistrarInterface interface {
+	// Register maps a friendly URL to the access URL of the workspace and returns it.
+	// Registering a workspace again, e.g. after its access URL changed, updates the mapping.
+	Register(ctx context.Context, workspace *workspacev1alpha1.Workspace, accessURL string) (string, error)
+	// Deregister removes the friendly URL of the workspace. Deregistering an unknown workspace succeeds.
+	Deregister(ctx context.Context, workspace *workspacev1alpha1.Workspace, vanityURL string) error
+}
+
+// VanityURLWebhookRequest is the JSON body posted to the vanity URL webhook
+type VanityURLWebhookRequest struct {
+	// Action is either "register" or "deregister"
+	Action       string `json:"action"`
+	Workspace    string `json:"workspace"`
+	Namespace    string `json:"namespace"`
+	WorkspaceUID string `json:"workspaceUID"`
+	AccessURL    string `json:"accessURL,omitempty"`
+	VanityURL    string `json:"vanityURL,omitempty"`
+}
+
+// VanityURLWebhookResponse is the JSON body returned by the vanity URL webhook on register
+type VanityURLWebhookResponse struct {
+	VanityURL string `json:"vanityURL"`
+}
+
+// VanityURLWebhookRegistrar implements VanityURLRegistrarInterface by posting
+// VanityURLWebhookRequests to an HTTP endpoint
+type VanityURLWebhookRegistrar struct {
+	endpoint string
+	client   *http.Client
+	backoff  wait.Backoff
+}
+
+var _ VanityURLRegistrarInterface = &VanityURLWebhookRegistrar{}
+
+// NewVanityURLWebhookRegistrar creates a VanityURLWebhookRegistrar posting to endpoint
+func NewVanityURLWebhookRegistrar(endpoint string) *VanityURLWebhookRegistrar {
+	return &VanityURLWebhookRegistrar{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: vanityURLWebhookTimeout},
+		backoff:  wait.Backoff{Duration: vanityURLWebhookInitialDelay, Factor: 2, Steps: vanityURLWebhookAttempts},
+	}
+}
+
+// Register posts a register request and returns the vanity URL of the response
+func (r *VanityURLWebhookRegistrar) Register(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	accessURL string,
+) (string, error) {
+	body, err := r.call(ctx, newVanityURLWebhookRequest(VanityURLActionRegister, workspace, accessURL, ""))
+	if err != nil {
+		return "", err
+	}
+
+	var response VanityURLWebhookResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("invalid vanity URL webhook response: %w", err)
+	}
+	if response.VanityURL == "" {
+		return "", fmt.Errorf("vanity URL webhook returned no vanityURL")
+	}
+	return response.VanityURL, nil
+}
+
+// Deregister posts a deregister request
+func (r *VanityURLWebhookRegistrar) Deregister(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	vanityURL string,
+) error {
+	_, err := r.call(ctx, newVanityURLWebhookRequest(VanityURLActionDeregister, workspace, "", vanityURL))
+	return err
+}
+
+func newVanityURLWebhookRequest(
+	action string,
+	workspace *workspacev1alpha1.Workspace,
+	accessURL, vanityURL string,
+) *VanityURLWebhookRequest {
+	return &VanityURLWebhookRequest{
+		Action:       action,
+		Workspace:    workspace.Name,
+		Namespace:    workspace.Namespace,
+		WorkspaceUID: string(workspace.UID),
+		AccessURL:    accessURL,
+		VanityURL:    vanityURL,
+	}
+}
+
+// errVanityURLWebhookRetryable marks webhook failures worth retrying: network errors,
+// throttling and server errors
+var errVanityURLWebhookRetryable = errors.New("retryable vanity URL webhook error")
+
+// call posts the request, retrying transient failures with exponential backoff,
+// and returns the body of the successful response
+func (r *VanityURLWebhookRegistrar) call(ctx context.Context, request *VanityURLWebhookRequest) ([]byte, error) {
+	logger := logf.FromContext(ctx).WithValues("action", request.Action, "workspace", request.Workspace)
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal vanity URL webhook request: %w", err)
+	}
+
+	var body []byte
+	var lastErr error
+	err = wait.ExponentialBackoffWithContext(ctx, r.backoff, func(ctx context.Context) (bool, error) {
+		body, lastErr = r.post(ctx, payload)
+		if lastErr == nil {
+			return true, nil
+		}
+		if errors.Is(lastErr, errVanityURLWebhookRetryable) {
+			logger.V(1).Info("Vanity URL webhook call failed, retrying", "error", lastErr.Error())
+			return false, nil
+		}
+		return false, lastErr
+	})
+	if err != nil {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
+// post performs a single webhook call
+func (r *VanityURLWebhookRegistrar) post(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vanity URL webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errVanityURLWebhookRetryable, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, vanityURLWebhookMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response: %w", errVanityURLWebhookRetryable, err)
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return body, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: status %d", errVanityURLWebhookRetryable, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("vanity URL webhook rejected the request: status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testAccessURL = "https://jupyter.example.com/workspaces/default/test-workspace/"
+	testVanityURL = "https://test-workspace.nb.example.com/"
+)
+
+func newTestVanityURLWebhookRegistrar(endpoint string) *VanityURLWebhookRegistrar {
+	registrar := NewVanityURLWebhookRegistrar(endpoint)
+	registrar.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: vanityURLWebhookAttempts}
+	return registrar
+}
+
+func newVanityURLTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testNamespaceName, UID: "workspace-uid"},
+	}
+}
+
+func TestVanityURLWebhookRegistrar_Register(t *testing.T) {
+	var received VanityURLWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_ = json.NewEncoder(w).Encode(VanityURLWebhookResponse{VanityURL: testVanityURL})
+	}))
+	defer server.Close()
+
+	vanityURL, err := newTestVanityURLWebhookRegistrar(server.URL).
+		Register(context.Background(), newVanityURLTestWorkspace(), testAccessURL)
+
+	require.NoError(t, err)
+	assert.Equal(t, testVanityURL, vanityURL)
+	assert.Equal(t, VanityURLWebhookRequest{
+		Action:       VanityURLActionRegister,
+		Workspace:    testWorkspaceName,
+		Namespace:    testNamespaceName,
+		WorkspaceUID: "workspace-uid",
+		AccessURL:    testAccessURL,
+	}, received)
+}
+
+func TestVanityURLWebhookRegistrar_Register_EmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestVanityURLWebhookRegistrar(server.URL).
+		Register(context.Background(), newVanityURLTestWorkspace(), testAccessURL)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned no vanityURL")
+}
+
+func TestVanityURLWebhookRegistrar_Deregister(t *testing.T) {
+	var received VanityURLWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := newTestVanityURLWebhookRegistrar(server.URL).
+		Deregister(context.Background(), newVanityURLTestWorkspace(), testVanityURL)
+
+	require.NoError(t, err)
+	assert.Equal(t, VanityURLActionDeregister, received.Action)
+	assert.Equal(t, testVanityURL, received.VanityURL)
+	assert.Empty(t, received.AccessURL)
+}
+
+func TestVanityURLWebhookRegistrar_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if calls == 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(VanityURLWebhookResponse{VanityURL: testVanityURL})
+	}))
+	defer server.Close()
+
+	vanityURL, err := newTestVanityURLWebhookRegistrar(server.URL).
+		Register(context.Background(), newVanityURLTestWorkspace(), testAccessURL)
+
+	require.NoError(t, err)
+	assert.Equal(t, testVanityURL, vanityURL)
+	assert.Equal(t, 3, calls)
+}
+
+func TestVanityURLWebhookRegistrar_GivesUpAfterAttempts(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := newTestVanityURLWebhookRegistrar(server.URL).
+		Deregister(context.Background(), newVanityURLTestWorkspace(), testVanityURL)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+	assert.Equal(t, vanityURLWebhookAttempts, calls)
+}
+
+func TestVanityURLWebhookRegistrar_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("hostname taken\n"))
+	}))
+	defer server.Close()
+
+	_, err := newTestVanityURLWebhookRegistrar(server.URL).
+		Register(context.Background(), newVanityURLTestWorkspace(), testAccessURL)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400: hostname taken")
+	assert.Equal(t, 1, calls)
+}
@@ -7,28 +7,39 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/jupyter-infra/jupyter-k8s-plugin/plugin"
 	"github.com/jupyter-infra/jupyter-k8s-plugin/pluginclient"
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/accounting"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"golang.org/x/time/rate"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	builderPkg "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerPkg "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	mngr "sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // GVKWatch represents a Group-Version-Kind to watch
@@ -46,6 +57,17 @@ type WorkspaceControllerOptions struct {
 	// Registry is the prefix to use for all application images
 	ApplicationImagesRegistry string
 
+	// ApplicationImagesPullSecret is the name of a Secret added to the image pull secrets of every
+	// workspace pod, e.g. the credentials of the private registry of ApplicationImagesRegistry
+	ApplicationImagesPullSecret string
+
+	// ObjectStorageSyncImage is the image of the sidecar syncing the object storage mounts in Sync mode
+	ObjectStorageSyncImage string
+
+	// EnableWorkspaceSnapshots tells whether the WorkspaceSnapshot controller runs. Without it, the
+	// storage of workspaces deleted with the Snapshot retain policy is retained instead.
+	EnableWorkspaceSnapshots bool
+
 	// Flag to indicate whether to watch traefik resource (for AccessStrategy)
 	// Deprecated: Use ResourceWatches instead
 	WatchTraefik bool
@@ -53,9 +75,48 @@ type WorkspaceControllerOptions struct {
 	// ResourceWatches defines custom Group-Version-Kind resources to watch
 	ResourceWatches []GVKWatch
 
-	// EnableWorkspacePodWatching controls whether workspace pod events should be watched
+	// AccessProviders names the access providers whose resource kinds are watched (e.g. "gateway-api")
+	AccessProviders []string
+
+	// EnableWorkspacePodWatching controls whether workspace pod events should be watched,
+	// to detect preemptions and to set up the remote access of workspace pods
 	EnableWorkspacePodWatching bool
 
+	// EnableNodeInterruptionHandling controls whether nodes are watched, so that running workspaces
+	// are rescheduled from nodes about to be terminated, e.g. on spot interruptions
+	EnableNodeInterruptionHandling bool
+
+	// EnableUsageAccounting controls whether the running time and resource-request-hours of
+	// workspaces are accrued in their status.usage and in Prometheus counters
+	EnableUsageAccounting bool
+
+	// UsageAccountingInterval is how often the usage of running workspaces is checkpointed
+	UsageAccountingInterval time.Duration
+
+	// EnableResourceMetrics controls whether the live CPU, memory and GPU usage of running
+	// workspaces is sampled from the resource metrics API into their status.usage.current
+	EnableResourceMetrics bool
+
+	// ResourceMetricsInterval is how often the live usage of running workspaces is sampled.
+	// Zero means use the default (1m).
+	ResourceMetricsInterval time.Duration
+
+	// ResourceMetricsPrometheusURL is the URL of the Prometheus API queried for the GPU utilization
+	// of workspaces. When empty, the GPU utilization is not sampled.
+	ResourceMetricsPrometheusURL string
+
+	// ResourceMetricsGPUQuery is the Prometheus query of the GPU utilization of a workspace pod,
+	// with {namespace} and {pod} placeholders. Empty means use the default DCGM exporter query.
+	ResourceMetricsGPUQuery string
+
+	// RightSizing configures the right-sizing recommendations made from the live usage of workspaces,
+	// reported in the RightSizingRecommended condition. When nil, no recommendations are made.
+	RightSizing *accounting.RightSizingOptions
+
+	// RemoteAccessCleanupDryRun logs the remote access resources of deleted workspace pods
+	// instead of releasing them
+	RemoteAccessCleanupDryRun bool
+
 	// DefaultTemplateNamespace is the default namespace for WorkspaceTemplate resolution
 	// when templateRef.namespace is not specified
 	DefaultTemplateNamespace string
@@ -68,8 +129,61 @@ type WorkspaceControllerOptions struct {
 	// IdleCheckInterval is the interval between idle status checks for running workspaces.
 	// Zero means use the default (5m).
 	IdleCheckInterval time.Duration
+
+	// IdleNotificationWebhookURL is the URL of the webhook notifying the owners of idle workspaces
+	// before they are stopped. When empty, idle workspaces are stopped without notice.
+	IdleNotificationWebhookURL string
+
+	// IdleNotificationGracePeriod is the time between the notification of an idle workspace and
+	// its stop. Zero means use the default (15m).
+	IdleNotificationGracePeriod time.Duration
+
+	// HubActivityAPIURL is the URL at which workspace pods reach the JupyterHub activity API server.
+	// When empty, workspaces with hub activity detection are not given the JupyterHub environment.
+	HubActivityAPIURL string
+
+	// MaxConcurrentReconciles is the number of workspaces reconciled in parallel.
+	// Zero means use the controller-runtime default (1).
+	MaxConcurrentReconciles int
+
+	// RequeueBaseDelay is the delay before the first retry of a failed reconcile; retries back off
+	// exponentially up to DefaultRequeueMaxDelay. Zero means use the default (5ms).
+	RequeueBaseDelay time.Duration
+
+	// ProgressingTimeout is how long a starting workspace may stay in the same Progressing state
+	// before the progressing watchdog flags it as stalled. Zero disables the watchdog.
+	ProgressingTimeout time.Duration
+
+	// AccessStrategyFanOut enqueues the workspaces of a changed AccessStrategy in batches.
+	// When nil, all the workspaces of an AccessStrategy are enqueued on each of its events.
+	AccessStrategyFanOut *AccessStrategyFanOut
+
+	// TemplateComplianceChecker checks workspaces against the constraints of their template, which
+	// are reported in the TemplateViolation condition and re-checked whenever the template changes.
+	// When nil, template violations are not reported.
+	TemplateComplianceChecker TemplateComplianceChecker
+
+	// AccessResourceKinds collects the kinds of the access resources the AccessStrategies render,
+	// which the controller watches in addition to ResourceWatches as they are recorded
+	AccessResourceKinds *AccessResourceKinds
+
+	// Sharding restricts the controller to the workspaces of one shard of the namespaces.
+	// The zero value reconciles all workspaces.
+	Sharding Sharding
+
+	// OperatorConfig holds the settings of the OperatorConfig, which override the application image
+	// and idle check options above as it changes. When nil, the options above apply.
+	OperatorConfig *operatorconfig.Store
 }
 
+// Workspace controller rate limits, matching the controller-runtime defaults
+const (
+	DefaultRequeueBaseDelay = 5 * time.Millisecond
+	DefaultRequeueMaxDelay  = 1000 * time.Second
+	workspaceQueueQPS       = 10
+	workspaceQueueBurst     = 100
+)
+
 // WorkspaceReconciler reconciles a Workspace object
 type WorkspaceReconciler struct {
 	client.Client
@@ -78,6 +192,10 @@ type WorkspaceReconciler struct {
 	statusManager   *StatusManager
 	podEventHandler *PodEventHandler
 	options         WorkspaceControllerOptions
+
+	// nodeInterruptionHandler maps interrupted nodes to the workspaces to reschedule; nil when
+	// node interruption handling is disabled
+	nodeInterruptionHandler *NodeInterruptionHandler
 }
 
 // SetStateMachine sets the state machine for testing purposes
@@ -90,12 +208,20 @@ func (r *WorkspaceReconciler) SetStateMachine(sm StateMachineInterface) {
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods;serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=patch;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=traefik.io,resources=middlewares,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=traefik.io,resources=serverstransports,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -103,8 +229,23 @@ func (r *WorkspaceReconciler) SetStateMachine(sm StateMachineInterface) {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 //
-// nolint:gocyclo
+// The status updates of a reconcile are coalesced into a single patch, written once it completes.
 func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = WithStatusBatch(ctx)
+	result, err := r.reconcileWorkspace(ctx, req)
+	if statusErr := r.statusManager.FlushStatus(ctx); statusErr != nil {
+		logf.FromContext(ctx).Error(statusErr, "Failed to update workspace status")
+		if err == nil {
+			return ctrl.Result{}, statusErr
+		}
+	}
+	return result, err
+}
+
+// reconcileWorkspace reconciles the workspace of the request
+//
+// nolint:gocyclo
+func (r *WorkspaceReconciler) reconcileWorkspace(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 	logger.Info("Starting reconciliation", "workspace", req.NamespacedName)
 
@@ -119,6 +260,13 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Another replica reconciles the workspaces of other shards, e.g. enqueued by the watches of
+	// shared resources such as AccessStrategies
+	if !r.options.Sharding.OwnsNamespace(workspace.Namespace) {
+		logger.V(1).Info("Workspace belongs to another shard, skipping", "workspace", req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
 	// Handle deletion if DeletionTimestamp is set
 	if !workspace.DeletionTimestamp.IsZero() {
 		return r.stateMachine.ReconcileDeletion(ctx, workspace)
@@ -207,13 +355,43 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
-	// Perform a single update if any labels or finalizer have changed
+	// Handle ownership transfer requests
+	previousOwner, ownerTransferred := applyOwnerTransfer(workspace)
+	if ownerTransferred {
+		needsUpdate = true
+	}
+
+	// Record the prefix of the child resource names of workspaces created before it was recorded,
+	// adopting the child resources they already have
+	resourceNamePrefix := ""
+	if workspace.Annotations[AnnotationResourceNamePrefix] == "" {
+		prefix, err := resolveResourceNamePrefix(ctx, r.Client, workspace)
+		if err != nil {
+			logger.Error(err, "Failed to resolve resource name prefix")
+			return ctrl.Result{}, err
+		}
+		if workspace.Annotations == nil {
+			workspace.Annotations = make(map[string]string)
+		}
+		workspace.Annotations[AnnotationResourceNamePrefix] = prefix
+		resourceNamePrefix = prefix
+		needsUpdate = true
+	}
+
+	// Perform a single update if any labels, annotations, finalizer or owner have changed
 	if needsUpdate {
 		logger.Info("Updating workspace labels",
 			"finalizerAdded", finalizerAdded,
 			"labelsChanged", labelsChanged,
 			"labelsRemoved", labelsRemoved,
+			"resourceNamePrefix", resourceNamePrefix,
 		)
+		if ownerTransferred {
+			logger.Info("Transferring workspace ownership",
+				"previousOwner", previousOwner,
+				"newOwner", workspace.Annotations[AnnotationCreatedBy],
+			)
+		}
 
 		if err := r.Update(ctx, workspace); err != nil {
 			logger.Error(err, "Failed to update workspace labels or finalizers")
@@ -227,11 +405,16 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Get desired status to decide if we need to fetch AccessStrategy
 	desiredStatus := r.stateMachine.getDesiredStatus(workspace)
 
-	// Only fetch AccessStrategy if desiredStatus is not Stopped and workspace has AccessStrategy defined
+	// Only fetch AccessStrategy if desiredStatus is not Stopped and workspace has AccessStrategy defined.
+	// Hibernated workspaces use it to resolve their access URL placeholder.
 	var accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
 	if desiredStatus != DesiredStateStopped && workspace.Spec.AccessStrategy != nil {
 		accessStrategy, err = r.stateMachine.GetAccessStrategyForWorkspace(ctx, workspace)
-		if err != nil {
+		if err != nil && desiredStatus == DesiredStateHibernated {
+			// A missing AccessStrategy must not block hibernation, only the placeholder is lost
+			logger.Error(err, "Failed to get AccessStrategy of hibernated workspace")
+			accessStrategy = nil
+		} else if err != nil {
 			logger.Error(err, "Failed to get AccessStrategy")
 			return ctrl.Result{}, err
 		}
@@ -246,31 +429,38 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&workspacev1alpha1.Workspace{}).
 		Named("workspace").
+		WithOptions(controllerPkg.Options{
+			MaxConcurrentReconciles: r.options.MaxConcurrentReconciles,
+			RateLimiter:             newWorkspaceRateLimiter(r.options.RequeueBaseDelay),
+		}).
 		// Watch for standard Kubernetes resources
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{})
 
-	// Watch for changes to AccessStrategy resources to trigger reconciliation
-	// of Workspaces that reference them
-	builder.Watches(
-		&workspacev1alpha1.WorkspaceAccessStrategy{},
-		handler.EnqueueRequestsFromMapFunc(r.accessStrategyEventHandler),
-	)
+	// Reconcile the Workspaces that reference a changed AccessStrategy, in the batches of the
+	// AccessStrategy controller when configured
+	if r.options.AccessStrategyFanOut != nil {
+		builder.WatchesRawSource(r.options.AccessStrategyFanOut.source())
+	} else {
+		builder.Watches(
+			&workspacev1alpha1.WorkspaceAccessStrategy{},
+			handler.EnqueueRequestsFromMapFunc(r.accessStrategyEventHandler),
+		)
+	}
 
-	// Conditionally watch pods based on configuration
-	if r.options.EnableWorkspacePodWatching {
+	// Re-check the Workspaces of a WorkspaceTemplate against its constraints when its spec changes
+	if r.options.TemplateComplianceChecker != nil {
 		builder.Watches(
-			&corev1.Pod{},
-			handler.EnqueueRequestsFromMapFunc(r.podEventHandler.HandleWorkspacePodEvents),
-			builderPkg.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
-				// Only watch pods with workspace labels
-				_, hasWorkspace := obj.GetLabels()[workspaceutil.LabelWorkspaceName]
-				return hasWorkspace
-			})),
+			&workspacev1alpha1.WorkspaceTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.templateEventHandler),
+			builderPkg.WithPredicates(predicate.GenerationChangedPredicate{}),
 		)
+	}
 
-		// Also watch Events to detect preemption
+	// Conditionally watch Events to detect preemption based on configuration.
+	// The remote access of workspace pods is handled by the RemoteAccessReconciler.
+	if r.options.EnableWorkspacePodWatching {
 		builder.Watches(
 			&corev1.Event{},
 			handler.EnqueueRequestsFromMapFunc(r.podEventHandler.HandleKubernetesEvents),
@@ -289,40 +479,123 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		)
 	}
 
-	// Optional traefik configuration (backward compatibility)
-	if r.options.WatchTraefik {
-		// Create an IngressRoute unstructured object for watching
-		ingressRouteGVK := &unstructured.Unstructured{}
-		ingressRouteGVK.SetAPIVersion(traefikAPIVersion)
-		ingressRouteGVK.SetKind(kindIngressRoute)
+	// Reconcile the workspaces whose pods run on a node about to be terminated
+	if r.nodeInterruptionHandler != nil {
+		builder.Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.nodeInterruptionHandler.HandleNodeEvents),
+			builderPkg.WithPredicates(predicate.NewPredicateFuncs(isInterruptedNode)),
+		)
+	}
 
-		// Create a Middleware unstructured object for watching
-		middlewareGVK := &unstructured.Unstructured{}
-		middlewareGVK.SetAPIVersion(traefikAPIVersion)
-		middlewareGVK.SetKind(kindMiddleware)
+	// Watch the NetworkPolicy of isolated workspaces, so that manual changes are reverted
+	builder.Owns(&networkingv1.NetworkPolicy{})
 
-		// Watch NetworkPolicy resources using typed API
-		builder.Owns(&networkingv1.NetworkPolicy{}).Owns(ingressRouteGVK).Owns(middlewareGVK)
-	}
+	// Watch the storage provisioning hook Jobs, so that workspaces start as soon as their hook completes
+	builder.Owns(&batchv1.Job{})
 
-	// Add additional resource watches from ResourceWatches config
-	for _, gvk := range r.options.ResourceWatches {
+	// Watch the access resource kinds of the enabled access providers and of ResourceWatches.
+	// Kinds whose CRD is not installed yet are watched once their CRD is established.
+	accessResourceGVKs, err := accessResourceWatches(r.options, DefaultAccessProviders())
+	if err != nil {
+		return err
+	}
+	installedGVKs, pendingGVKs, err := splitInstalledKinds(mgr.GetRESTMapper(), accessResourceGVKs)
+	if err != nil {
+		return err
+	}
+	for _, gvk := range installedGVKs {
 		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		builder.Owns(obj)
+	}
+
+	// Kinds added to the OperatorConfig or rendered by AccessStrategies at runtime are watched the same way
+	watchesOptionalKinds := len(pendingGVKs) > 0 || r.options.OperatorConfig != nil || r.options.AccessResourceKinds != nil
+	var requeue chan event.GenericEvent
+	if watchesOptionalKinds {
+		requeue = make(chan event.GenericEvent)
+		builder.WatchesRawSource(source.Channel(requeue, &handler.EnqueueRequestForObject{}))
+	}
+
+	workspaceController, err := builder.Build(r)
+	if err != nil {
+		return err
+	}
+
+	if !watchesOptionalKinds {
+		return nil
+	}
+	if len(pendingGVKs) > 0 {
+		logf.Log.WithName("workspace-controller").Info(
+			"Deferring access resource watches until their CRDs are installed", "kinds", fmt.Sprint(pendingGVKs))
+	}
+	watchedGVKs := append(slices.Clone(workspaceOwnedKinds), installedGVKs...)
+	optionalKindReconciler := NewOptionalKindReconciler(mgr, workspaceController, watchedGVKs, pendingGVKs, requeue)
+	optionalKindReconciler.config = r.options.OperatorConfig
+	optionalKindReconciler.accessResourceKinds = r.options.AccessResourceKinds
+	return optionalKindReconciler.SetupWithManager(mgr)
+}
+
+// workspaceOwnedKinds are the kinds of the typed resources the workspace controller owns, which
+// access resources of the same kinds do not need another watch for
+var workspaceOwnedKinds = []schema.GroupVersionKind{
+	appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	corev1.SchemeGroupVersion.WithKind("Service"),
+	corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+	networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+	batchv1.SchemeGroupVersion.WithKind("Job"),
+}
+
+// newWorkspaceRateLimiter returns the rate limiter of the workspace queue: per-workspace exponential
+// backoff from baseDelay, bounded by an overall token bucket
+func newWorkspaceRateLimiter(baseDelay time.Duration) workqueue.TypedRateLimiter[reconcile.Request] {
+	if baseDelay <= 0 {
+		baseDelay = DefaultRequeueBaseDelay
+	}
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, DefaultRequeueMaxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+			Limiter: rate.NewLimiter(rate.Limit(workspaceQueueQPS), workspaceQueueBurst),
+		},
+	)
+}
+
+// accessResourceWatches returns the deduplicated kinds of the access resources to watch: those of
+// the enabled access providers, followed by the additional ResourceWatches
+func accessResourceWatches(
+	options WorkspaceControllerOptions,
+	providers *AccessProviderRegistry,
+) ([]schema.GroupVersionKind, error) {
+	providerNames := options.AccessProviders
+	if options.WatchTraefik {
+		providerNames = append(append([]string{}, providerNames...), AccessProviderTraefik)
+	}
 
-		var apiVersion string
-		if gvk.Group == "" {
-			// Core API group
-			apiVersion = gvk.Version
-		} else {
-			apiVersion = gvk.Group + "/" + gvk.Version
+	var gvks []schema.GroupVersionKind
+	seen := map[schema.GroupVersionKind]bool{}
+	add := func(gvk schema.GroupVersionKind) {
+		if !seen[gvk] {
+			seen[gvk] = true
+			gvks = append(gvks, gvk)
 		}
+	}
 
-		obj.SetAPIVersion(apiVersion)
-		obj.SetKind(gvk.Kind)
-		builder.Owns(obj)
+	for _, name := range providerNames {
+		provider, err := providers.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot watch access resources: %w (registered providers: %s)",
+				err, strings.Join(providers.Names(), ", "))
+		}
+		for _, gvk := range provider.WatchedGVKs() {
+			add(gvk)
+		}
 	}
 
-	return builder.Complete(r)
+	for _, watch := range options.ResourceWatches {
+		add(schema.GroupVersionKind{Group: watch.Group, Version: watch.Version, Kind: watch.Kind})
+	}
+	return gvks, nil
 }
 
 // SetupWorkspaceController sets up the controller with the Manager and specified options
@@ -341,21 +614,42 @@ func SetupWorkspaceController(mgr mngr.Manager, options WorkspaceControllerOptio
 		NewAccessResourcesBuilder(),
 		statusManager,
 	)
+	resourceManager.apiReader = mgr.GetAPIReader()
 
 	// Create state machine
 	eventRecorder := mgr.GetEventRecorderFor("workspace-controller")
 	idleChecker := NewWorkspaceIdleChecker(k8sClient, options.IdleCheckInterval)
+	idleChecker.config = options.OperatorConfig
 	accessStartupProber := NewAccessStartupProber(NewAccessResourcesBuilder())
-	stateMachine := NewStateMachine(resourceManager, statusManager, eventRecorder, idleChecker, accessStartupProber)
-
-	// Create plugin clients for pod event handling (if configured)
-	pluginClients := map[string]plugin.RemoteAccessPluginApis{}
-	for name, endpoint := range options.PluginEndpoints {
-		pluginClients[name] = pluginclient.NewPluginClient(endpoint, logf.Log.WithName("plugin-"+name))
+	progressingWatchdog := NewProgressingWatchdog(k8sClient, eventRecorder, options.ProgressingTimeout)
+	var nodeInterruptionHandler *NodeInterruptionHandler
+	if options.EnableNodeInterruptionHandling {
+		nodeInterruptionHandler = NewNodeInterruptionHandler(k8sClient, eventRecorder)
+	}
+	var usageAccountant *accounting.Accountant
+	if options.EnableUsageAccounting {
+		usageAccountant = accounting.NewAccountant(options.UsageAccountingInterval)
+	}
+	var usageSampler *accounting.Sampler
+	if options.EnableResourceMetrics {
+		usageSampler = accounting.NewSampler(mgr.GetAPIReader(), accounting.SamplerOptions{
+			Container:     ResourcePrefix,
+			Interval:      options.ResourceMetricsInterval,
+			PrometheusURL: options.ResourceMetricsPrometheusURL,
+			GPUQuery:      options.ResourceMetricsGPUQuery,
+			RightSizing:   options.RightSizing,
+		})
+	}
+	var idleNotifier IdleNotifierInterface
+	if options.IdleNotificationWebhookURL != "" {
+		idleNotifier = NewIdleNotificationWebhook(options.IdleNotificationWebhookURL, options.IdleNotificationGracePeriod)
 	}
+	stateMachine := NewStateMachine(resourceManager, statusManager, eventRecorder, idleChecker, accessStartupProber,
+		progressingWatchdog, NewApplicationProber(), nodeInterruptionHandler, usageAccountant, usageSampler, idleNotifier, options.TemplateComplianceChecker,
+		options.EnableWorkspaceSnapshots)
 
 	// Create pod event handler
-	podEventHandler := NewPodEventHandler(k8sClient, resourceManager, pluginClients)
+	podEventHandler := NewPodEventHandler(k8sClient)
 
 	// Create reconciler with dependencies
 	reconciler := &WorkspaceReconciler{
@@ -365,6 +659,20 @@ func SetupWorkspaceController(mgr mngr.Manager, options WorkspaceControllerOptio
 		statusManager:   statusManager,
 		podEventHandler: podEventHandler,
 		options:         options,
+
+		nodeInterruptionHandler: nodeInterruptionHandler,
+	}
+
+	if options.EnableWorkspacePodWatching {
+		// Create plugin clients for pod remote access (if configured)
+		pluginClients := map[string]plugin.RemoteAccessPluginApis{}
+		for name, endpoint := range options.PluginEndpoints {
+			pluginClients[name] = pluginclient.NewPluginClient(endpoint, logf.Log.WithName("plugin-"+name))
+		}
+		remoteAccessReconciler := NewRemoteAccessReconciler(k8sClient, resourceManager, pluginClients, options)
+		if err := remoteAccessReconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
 	}
 
 	return reconciler.SetupWithManager(mgr)
@@ -418,3 +726,27 @@ func (r *WorkspaceReconciler) accessStrategyEventHandler(ctx context.Context, ob
 
 	return requests
 }
+
+// templateEventHandler maps WorkspaceTemplate events to Workspace reconciliation requests
+func (r *WorkspaceReconciler) templateEventHandler(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+	template, ok := obj.(*workspacev1alpha1.WorkspaceTemplate)
+	if !ok {
+		// Not a WorkspaceTemplate
+		return nil
+	}
+
+	requests, err := workspaceutil.GetWorkspaceReconciliationRequestsForTemplate(ctx, r.Client, template.Name, template.Namespace)
+	if err != nil {
+		logger.Error(err, "Failed to list Workspaces associated with template",
+			"template", template.Name,
+			"namespace", template.Namespace)
+		return nil
+	}
+
+	logger.Info("Handling WorkspaceTemplate event",
+		"template", template.Name,
+		"namespace", template.Namespace,
+		"workspaceCount", len(requests))
+	return requests
+}
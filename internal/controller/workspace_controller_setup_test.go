@@ -0,0 +1,34 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewWorkspaceRateLimiter(t *testing.T) {
+	item := reconcile.Request{NamespacedName: types.NamespacedName{Name: "ws", Namespace: "default"}}
+
+	t.Run("backs off exponentially from the base delay", func(t *testing.T) {
+		limiter := newWorkspaceRateLimiter(time.Second)
+		assert.Equal(t, time.Second, limiter.When(item))
+		assert.Equal(t, 2*time.Second, limiter.When(item))
+		assert.Equal(t, 2, limiter.NumRequeues(item))
+
+		limiter.Forget(item)
+		assert.Equal(t, time.Second, limiter.When(item))
+	})
+
+	t.Run("uses the default base delay when unset", func(t *testing.T) {
+		limiter := newWorkspaceRateLimiter(0)
+		assert.LessOrEqual(t, limiter.When(item), DefaultRequeueBaseDelay)
+	})
+}
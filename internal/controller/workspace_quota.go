@@ -0,0 +1,128 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// WorkspaceQuotaUser returns the user a workspace counts against in User-scoped quotas
+func WorkspaceQuotaUser(workspace *workspacev1alpha1.Workspace) string {
+	return workspace.Annotations[AnnotationCreatedBy]
+}
+
+// CountsTowardsWorkspaceQuota returns false for workspaces that are being deleted
+func CountsTowardsWorkspaceQuota(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.DeletionTimestamp.IsZero()
+}
+
+// isWorkspaceQuotaRunning returns true when the workspace counts as running for quotas
+func isWorkspaceQuotaRunning(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.Spec.DesiredStatus == "" || workspace.Spec.DesiredStatus == DesiredStateRunning
+}
+
+// WorkspaceQuotaUsageOf returns the quota consumed by a single workspace, restricted to the
+// resources the quota limits. Stopped workspaces only consume storage.
+func WorkspaceQuotaUsageOf(
+	workspace *workspacev1alpha1.Workspace,
+	quota *workspacev1alpha1.WorkspaceQuota,
+) workspacev1alpha1.WorkspaceQuotaUsage {
+	usage := workspacev1alpha1.WorkspaceQuotaUsage{Workspaces: 1}
+	running := isWorkspaceQuotaRunning(workspace)
+	if running {
+		usage.RunningWorkspaces = 1
+	}
+
+	if len(quota.Spec.Resources) == 0 {
+		return usage
+	}
+	usage.Resources = corev1.ResourceList{}
+	for name := range quota.Spec.Resources {
+		var amount resource.Quantity
+		switch {
+		case name == workspacev1alpha1.ResourceStorage:
+			if workspace.Spec.Storage != nil {
+				amount = workspace.Spec.Storage.Size.DeepCopy()
+			}
+		case running && workspace.Spec.Resources != nil:
+			if request, ok := workspace.Spec.Resources.Requests[name]; ok {
+				amount = request.DeepCopy()
+			} else if limit, ok := workspace.Spec.Resources.Limits[name]; ok {
+				amount = limit.DeepCopy()
+			}
+		}
+		usage.Resources[name] = amount
+	}
+	return usage
+}
+
+// AddWorkspaceQuotaUsage adds usage to total
+func AddWorkspaceQuotaUsage(total *workspacev1alpha1.WorkspaceQuotaUsage, usage workspacev1alpha1.WorkspaceQuotaUsage) {
+	total.Workspaces += usage.Workspaces
+	total.RunningWorkspaces += usage.RunningWorkspaces
+	for name, amount := range usage.Resources {
+		if total.Resources == nil {
+			total.Resources = corev1.ResourceList{}
+		}
+		sum := total.Resources[name]
+		sum.Add(amount)
+		total.Resources[name] = sum
+	}
+}
+
+// newWorkspaceQuotaUsage returns an empty usage listing every resource the quota limits
+func newWorkspaceQuotaUsage(quota *workspacev1alpha1.WorkspaceQuota) workspacev1alpha1.WorkspaceQuotaUsage {
+	usage := workspacev1alpha1.WorkspaceQuotaUsage{}
+	if len(quota.Spec.Resources) > 0 {
+		usage.Resources = corev1.ResourceList{}
+		for name := range quota.Spec.Resources {
+			usage.Resources[name] = resource.Quantity{}
+		}
+	}
+	return usage
+}
+
+// ComputeWorkspaceQuotaStatus computes the usage of a quota from the workspaces in its namespace
+func ComputeWorkspaceQuotaStatus(
+	quota *workspacev1alpha1.WorkspaceQuota,
+	workspaces []workspacev1alpha1.Workspace,
+) workspacev1alpha1.WorkspaceQuotaStatus {
+	used := newWorkspaceQuotaUsage(quota)
+	perUser := map[string]*workspacev1alpha1.WorkspaceQuotaUsage{}
+
+	for i := range workspaces {
+		workspace := &workspaces[i]
+		if !CountsTowardsWorkspaceQuota(workspace) {
+			continue
+		}
+		usage := WorkspaceQuotaUsageOf(workspace, quota)
+		AddWorkspaceQuotaUsage(&used, usage)
+
+		if quota.Spec.Scope == workspacev1alpha1.QuotaScopeUser {
+			user := WorkspaceQuotaUser(workspace)
+			if perUser[user] == nil {
+				userUsage := newWorkspaceQuotaUsage(quota)
+				perUser[user] = &userUsage
+			}
+			AddWorkspaceQuotaUsage(perUser[user], usage)
+		}
+	}
+
+	status := workspacev1alpha1.WorkspaceQuotaStatus{
+		ObservedGeneration: quota.Generation,
+		Used:               &used,
+	}
+	for user, usage := range perUser {
+		status.Users = append(status.Users, workspacev1alpha1.UserQuotaUsage{User: user, Used: *usage})
+	}
+	sort.Slice(status.Users, func(i, j int) bool { return status.Users[i].User < status.Users[j].User })
+	return status
+}
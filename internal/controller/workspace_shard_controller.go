@@ -0,0 +1,82 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// WorkspaceShardReconciler labels Workspaces with the shard key of their namespace, by which the
+// manager replicas of the other shards select them. It runs on the coordinator of sharded managers.
+type WorkspaceShardReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// Reconcile sets the shard key label of a Workspace
+func (r *WorkspaceShardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspace", req.Name, "namespace", req.Namespace)
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := r.Get(ctx, req.NamespacedName, workspace); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	shardKey := ShardKey(workspace.Namespace)
+	if !workspace.DeletionTimestamp.IsZero() || workspace.Labels[LabelShard] == shardKey {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(workspace.DeepCopy())
+	if workspace.Labels == nil {
+		workspace.Labels = map[string]string{}
+	}
+	workspace.Labels[LabelShard] = shardKey
+	if err := r.Patch(ctx, workspace, patch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to label workspace with its shard key: %w", err)
+	}
+	logger.V(1).Info("Labeled workspace with its shard key", "shardKey", shardKey)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It only reconciles the Workspaces whose shard key label is missing or wrong.
+func (r *WorkspaceShardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.Workspace{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[LabelShard] != ShardKey(obj.GetNamespace())
+			}),
+		)).
+		Named("workspaceshard").
+		Complete(r)
+}
+
+// SetupWorkspaceShardController sets up the Workspace shard labeling controller with the Manager
+func SetupWorkspaceShardController(mgr ctrl.Manager) error {
+	reconciler := &WorkspaceShardReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -7,10 +7,12 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,12 +33,23 @@ type WorkspaceAccessStrategyReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	EventRecorder record.EventRecorder
+
+	// FanOut propagates the changes of the AccessStrategy to the workspaces that render it in
+	// batches, and the progress is reported in the status. When nil, only the protection
+	// finalizers are managed.
+	FanOut *AccessStrategyFanOut
+
+	// AccessResourceKinds collects the kinds of the access resources of the AccessStrategy, which
+	// the workspace controller watches. When nil, only the kinds configured by flags are watched.
+	AccessResourceKinds *AccessResourceKinds
 }
 
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaceaccessstrategies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaceaccessstrategies/status,verbs=get;update;patch
 
 // Reconcile handles WorkspaceAccessStrategy finalizer logic to prevent deletion
-// when the AccessStrategy is still referenced by Workspaces.
+// when the AccessStrategy is still referenced by Workspaces, and propagates its changes
+// to the workspaces that render it.
 func (r *WorkspaceAccessStrategyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx).WithValues(
 		"workspaceaccessstrategy", req.Name,
@@ -48,12 +61,23 @@ func (r *WorkspaceAccessStrategyReconciler) Reconcile(ctx context.Context, req c
 		if errors.IsNotFound(err) {
 			// Already deleted, nothing to do
 			logger.V(1).Info("WorkspaceAccessStrategy not found, it may have been deleted")
+			if r.FanOut != nil {
+				r.FanOut.forget(req.NamespacedName)
+			}
+			if r.AccessResourceKinds != nil {
+				r.AccessResourceKinds.forget(req.NamespacedName)
+			}
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get WorkspaceAccessStrategy")
 		return ctrl.Result{}, err
 	}
 
+	// Have the workspace controller watch the kinds of the access resources the AccessStrategy renders
+	if r.AccessResourceKinds != nil {
+		r.AccessResourceKinds.record(accessStrategy)
+	}
+
 	// Manage the two protection finalizers independently (lazy finalizer pattern). Workspace and
 	// template references each get their own finalizer, so each is added/removed purely on its own
 	// signal and Kubernetes keeps the AccessStrategy alive until BOTH referrer types release it.
@@ -85,7 +109,64 @@ func (r *WorkspaceAccessStrategyReconciler) Reconcile(ctx context.Context, req c
 	}
 
 	logger.V(1).Info("Finalizer state is correct, no action needed")
-	return ctrl.Result{}, nil
+
+	if r.FanOut == nil || !accessStrategy.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	return r.reconcilePropagation(ctx, logger, accessStrategy)
+}
+
+// reconcilePropagation enqueues the next batch of the workspaces whose access resources are not
+// rendered from the current version of the AccessStrategy, and reports how many are in the status.
+// The status is only updated when the counts change.
+func (r *WorkspaceAccessStrategyReconciler) reconcilePropagation(
+	ctx context.Context,
+	logger logr.Logger,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (ctrl.Result, error) {
+
+	workspaces, _, err := workspace.ListActiveWorkspacesByAccessStrategy(
+		ctx, r.Client, accessStrategy.Name, accessStrategy.Namespace, "", 0)
+	if err != nil {
+		logger.Error(err, "Failed to list workspaces using AccessStrategy")
+		return ctrl.Result{}, err
+	}
+
+	version := versionedAccessStrategyID(accessStrategy)
+	var rendering, updated int32
+	var stale []*workspacev1alpha1.Workspace
+	for i := range workspaces {
+		ws := &workspaces[i]
+		if !rendersAccessStrategy(ws) {
+			continue
+		}
+		rendering++
+		if ws.Status.AppliedAccessStrategyVersion == version {
+			updated++
+		} else {
+			stale = append(stale, ws)
+		}
+	}
+
+	requeueAfter := r.FanOut.propagate(client.ObjectKeyFromObject(accessStrategy), version, stale)
+
+	status := accessStrategy.Status.DeepCopy()
+	status.ObservedGeneration = accessStrategy.Generation
+	status.Workspaces = rendering
+	status.UpdatedWorkspaces = updated
+	status.Message = fmt.Sprintf("%d of %d workspaces updated", updated, rendering)
+	if !equality.Semantic.DeepEqual(status, &accessStrategy.Status) {
+		accessStrategy.Status = *status
+		if err := r.Status().Update(ctx, accessStrategy); err != nil {
+			if errors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			logger.Error(err, "Failed to update AccessStrategy status")
+			return ctrl.Result{}, err
+		}
+		logger.V(1).Info("Updated AccessStrategy propagation status", "updated", updated, "workspaces", rendering)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // reconcileFinalizers brings both protection finalizers into agreement with the current reference state
@@ -268,15 +349,20 @@ func (r *WorkspaceAccessStrategyReconciler) findAccessStrategiesForTemplate(ctx
 }
 
 // SetupWorkspaceAccessStrategyController sets up the controller with the Manager.
-func SetupWorkspaceAccessStrategyController(mgr ctrl.Manager) error {
+// fanOut may be nil, in which case the controller only manages the protection finalizers, and
+// accessResourceKinds may be nil, in which case the kinds of the access resources are not collected.
+func SetupWorkspaceAccessStrategyController(
+	mgr ctrl.Manager, fanOut *AccessStrategyFanOut, accessResourceKinds *AccessResourceKinds) error {
 	k8sClient := mgr.GetClient()
 	scheme := mgr.GetScheme()
 	eventRecorder := mgr.GetEventRecorderFor("workspaceaccessstrategy-controller")
 
 	reconciler := &WorkspaceAccessStrategyReconciler{
-		Client:        k8sClient,
-		Scheme:        scheme,
-		EventRecorder: eventRecorder,
+		Client:              k8sClient,
+		Scheme:              scheme,
+		EventRecorder:       eventRecorder,
+		FanOut:              fanOut,
+		AccessResourceKinds: accessResourceKinds,
 	}
 
 	return reconciler.SetupWithManager(mgr)
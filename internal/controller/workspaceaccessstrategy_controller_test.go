@@ -221,7 +221,12 @@ var _ = Describe("AccessStrategy controller", func() {
 			mockClient.listFunc = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
 				if templateList, ok := list.(*workspacev1alpha1.WorkspaceTemplateList); ok {
 					templateList.Items = []workspacev1alpha1.WorkspaceTemplate{
-						{ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName}},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName},
+							Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+								DefaultAccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: accessStrategyKey.Name},
+							},
+						},
 					}
 				}
 				// WorkspaceList left empty
@@ -263,7 +268,12 @@ var _ = Describe("AccessStrategy controller", func() {
 			mockClient.listFunc = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
 				if templateList, ok := list.(*workspacev1alpha1.WorkspaceTemplateList); ok {
 					templateList.Items = []workspacev1alpha1.WorkspaceTemplate{
-						{ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName}},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName},
+							Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+								DefaultAccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: accessStrategyKey.Name},
+							},
+						},
 					}
 				}
 				return nil
@@ -300,7 +310,12 @@ var _ = Describe("AccessStrategy controller", func() {
 			mockClient.listFunc = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
 				if templateList, ok := list.(*workspacev1alpha1.WorkspaceTemplateList); ok {
 					templateList.Items = []workspacev1alpha1.WorkspaceTemplate{
-						{ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName}},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: templateNameTmpl, Namespace: testNamespaceName},
+							Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+								DefaultAccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: accessStrategyKey.Name},
+							},
+						},
 					}
 				}
 				return nil
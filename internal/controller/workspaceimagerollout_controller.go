@@ -0,0 +1,602 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultImageRolloutProgressDeadline is how long a running workspace may take to become
+// available with its new image when the rollout policy does not set a progress deadline
+const DefaultImageRolloutProgressDeadline = 10 * time.Minute
+
+// WorkspaceImageRolloutReconciler reconciles a WorkspaceImageRollout object by progressively
+// changing the image of the matching workspaces, and reverting them on rollback.
+// Running workspaces are restarted at most maxUnavailable at a time; an update completes once
+// the workspace deployment is available with the new image.
+type WorkspaceImageRolloutReconciler struct {
+	client.Client
+	imageResolver *ImageResolver
+	recorder      record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaceimagerollouts/status,verbs=get;update;patch
+
+// Reconcile tracks the in-flight workspace updates of a WorkspaceImageRollout, then starts
+// new updates, or rollbacks, as allowed by its policy
+func (r *WorkspaceImageRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspaceimagerollout", req.Name)
+
+	rollout := &workspacev1alpha1.WorkspaceImageRollout{}
+	if err := r.Get(ctx, req.NamespacedName, rollout); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("WorkspaceImageRollout not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !rollout.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	workspaces, err := r.listRolloutWorkspaces(ctx, rollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	for i := range rollout.Status.Workspaces {
+		if err := r.checkInFlightUpdate(ctx, rollout, &rollout.Status.Workspaces[i], workspaces, now); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In-flight updates complete while paused or outside the maintenance window, but none start.
+	// Rollbacks are urgent and ignore the maintenance window.
+	windowOpen, untilOpen := MaintenanceWindowState(rollout.Spec.Policy.MaintenanceWindow, now)
+	var pending int32
+	var requeueAfter time.Duration
+	if rollout.Spec.Rollback {
+		pending, err = r.startRollbacks(ctx, rollout, workspaces, !rollout.Spec.Paused, now)
+	} else {
+		pending, err = r.startUpdates(ctx, rollout, workspaces, !rollout.Spec.Paused && windowOpen, now)
+		if !windowOpen && pending > 0 {
+			requeueAfter = untilOpen
+		}
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	updating := r.updateRolloutStatus(rollout, pending, windowOpen)
+	if err := r.Status().Update(ctx, rollout); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update WorkspaceImageRollout status: %w", err)
+	}
+
+	if updating > 0 && (requeueAfter == 0 || requeueAfter > LongRequeueDelay) {
+		// Workspace updates requeue us; poll as a fallback for the progress deadline
+		requeueAfter = LongRequeueDelay
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// listRolloutWorkspaces returns the workspaces in the scope of the rollout, keyed by namespace/name
+func (r *WorkspaceImageRolloutReconciler) listRolloutWorkspaces(
+	ctx context.Context,
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+) (map[types.NamespacedName]*workspacev1alpha1.Workspace, error) {
+	opts := []client.ListOption{}
+	if rollout.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rollout.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace selector: %w", err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	list := &workspacev1alpha1.WorkspaceList{}
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	workspaces := map[types.NamespacedName]*workspacev1alpha1.Workspace{}
+	for i := range list.Items {
+		ws := &list.Items[i]
+		if len(rollout.Spec.Namespaces) > 0 && !slices.Contains(rollout.Spec.Namespaces, ws.Namespace) {
+			continue
+		}
+		workspaces[types.NamespacedName{Name: ws.Name, Namespace: ws.Namespace}] = ws
+	}
+	return workspaces, nil
+}
+
+// checkInFlightUpdate completes or fails an update, or rollback, once the workspace is
+// available with its new image, is degraded, or exceeds the progress deadline
+func (r *WorkspaceImageRolloutReconciler) checkInFlightUpdate(
+	ctx context.Context,
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	entry *workspacev1alpha1.ImageRolloutWorkspaceStatus,
+	workspaces map[types.NamespacedName]*workspacev1alpha1.Workspace,
+	now time.Time,
+) error {
+	var targetImage string
+	var doneState workspacev1alpha1.ImageRolloutWorkspaceState
+	switch entry.State {
+	case workspacev1alpha1.ImageRolloutWorkspaceUpdating:
+		targetImage, doneState = entry.ToImage, workspacev1alpha1.ImageRolloutWorkspaceUpdated
+	case workspacev1alpha1.ImageRolloutWorkspaceRollingBack:
+		targetImage, doneState = entry.FromImage, workspacev1alpha1.ImageRolloutWorkspaceRolledBack
+	default:
+		return nil
+	}
+
+	ws := workspaces[types.NamespacedName{Name: entry.Name, Namespace: entry.Namespace}]
+	if ws == nil {
+		r.failEntry(rollout, entry, "Workspace was deleted during the update", now)
+		return nil
+	}
+	if ws.Spec.Image != targetImage {
+		r.failEntry(rollout, entry, fmt.Sprintf("Workspace image was changed to %s during the update", ws.Spec.Image), now)
+		return nil
+	}
+
+	if !isRunningWorkspace(ws) {
+		setEntryState(entry, doneState, "Workspace will start with its new image", now)
+		return nil
+	}
+
+	// Ignore a degraded condition that predates the update, which the new image may fix
+	if degraded := FindCondition(&ws.Status.Conditions, ConditionTypeDegraded); degraded != nil &&
+		degraded.Status == metav1.ConditionTrue && !degraded.LastTransitionTime.Before(&entry.LastTransitionTime) {
+		r.failEntry(rollout, entry, fmt.Sprintf("Workspace is degraded: %s", degraded.Message), now)
+		return nil
+	}
+
+	rolledOut, err := r.isImageRolledOut(ctx, ws)
+	if err != nil {
+		return err
+	}
+	if rolledOut {
+		setEntryState(entry, doneState, "Workspace is available with its new image", now)
+		return nil
+	}
+
+	deadline := DefaultImageRolloutProgressDeadline
+	if rollout.Spec.Policy.ProgressDeadline != nil {
+		deadline = rollout.Spec.Policy.ProgressDeadline.Duration
+	}
+	if now.Sub(entry.LastTransitionTime.Time) > deadline {
+		r.failEntry(rollout, entry, fmt.Sprintf("Workspace did not become available within %s", deadline), now)
+	}
+	return nil
+}
+
+// isImageRolledOut returns true once all the pods of the workspace deployment run the workspace image
+func (r *WorkspaceImageRolloutReconciler) isImageRolledOut(ctx context.Context, ws *workspacev1alpha1.Workspace) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: GenerateDeploymentName(ws), Namespace: ws.Namespace}, deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get workspace deployment: %w", err)
+	}
+
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, nil
+	}
+
+	image := r.imageResolver.ResolveImage(ws)
+	if !slices.ContainsFunc(deployment.Spec.Template.Spec.Containers, func(c corev1.Container) bool {
+		return c.Image == image
+	}) {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.Replicas == replicas &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas, nil
+}
+
+// rolloutCandidate is a workspace the rollout will update
+type rolloutCandidate struct {
+	workspace *workspacev1alpha1.Workspace
+	toImage   string
+}
+
+// rolloutCandidates returns the workspaces that use one of the images replaced by the rollout,
+// except those whose update failed, stopped workspaces first then by namespace and name
+func rolloutCandidates(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	workspaces map[types.NamespacedName]*workspacev1alpha1.Workspace,
+) []rolloutCandidate {
+	var candidates []rolloutCandidate
+	for key, ws := range workspaces {
+		idx := slices.IndexFunc(rollout.Spec.Images, func(m workspacev1alpha1.ImageMapping) bool {
+			return m.From == ws.Spec.Image
+		})
+		if idx < 0 {
+			continue
+		}
+		if entry := findRolloutEntry(rollout, key); entry != nil &&
+			entry.State == workspacev1alpha1.ImageRolloutWorkspaceFailed && entry.FromImage == ws.Spec.Image {
+			continue
+		}
+		candidates = append(candidates, rolloutCandidate{workspace: ws, toImage: rollout.Spec.Images[idx].To})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		wi, wj := candidates[i].workspace, candidates[j].workspace
+		if isRunningWorkspace(wi) != isRunningWorkspace(wj) {
+			return !isRunningWorkspace(wi)
+		}
+		if wi.Namespace != wj.Namespace {
+			return wi.Namespace < wj.Namespace
+		}
+		return wi.Name < wj.Name
+	})
+	return candidates
+}
+
+// startUpdates changes the image of the candidate workspaces when start is set: all the stopped
+// ones, and as many running ones as maxUnavailable allows. Returns the number left to update.
+func (r *WorkspaceImageRolloutReconciler) startUpdates(
+	ctx context.Context,
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	workspaces map[types.NamespacedName]*workspacev1alpha1.Workspace,
+	start bool,
+	now time.Time,
+) (int32, error) {
+	slots := rolloutMaxUnavailable(rollout) - countUnavailable(rollout, workspaces)
+
+	var pending int32
+	for _, candidate := range rolloutCandidates(rollout, workspaces) {
+		ws := candidate.workspace
+		running := isRunningWorkspace(ws)
+		if !start || running && (rollout.Spec.Policy.OnlyStopped || slots <= 0) {
+			pending++
+			continue
+		}
+
+		entry := upsertRolloutEntry(rollout, ws)
+		entry.FromImage, entry.ToImage = ws.Spec.Image, candidate.toImage
+		if err := r.setWorkspaceImage(ctx, rollout, entry, ws, candidate.toImage,
+			workspacev1alpha1.ImageRolloutWorkspaceUpdating, workspacev1alpha1.ImageRolloutWorkspaceUpdated, now); err != nil {
+			return 0, err
+		}
+		if running {
+			slots--
+		}
+	}
+	return pending, nil
+}
+
+// startRollbacks reverts the workspaces updated by the rollout to their previous image when start
+// is set, as many running ones at a time as maxUnavailable allows. Returns the number left to revert.
+func (r *WorkspaceImageRolloutReconciler) startRollbacks(
+	ctx context.Context,
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	workspaces map[types.NamespacedName]*workspacev1alpha1.Workspace,
+	start bool,
+	now time.Time,
+) (int32, error) {
+	slots := rolloutMaxUnavailable(rollout) - countUnavailable(rollout, workspaces)
+
+	var pending int32
+	for i := range rollout.Status.Workspaces {
+		entry := &rollout.Status.Workspaces[i]
+		if entry.State != workspacev1alpha1.ImageRolloutWorkspaceUpdated &&
+			entry.State != workspacev1alpha1.ImageRolloutWorkspaceFailed {
+			continue
+		}
+
+		ws := workspaces[types.NamespacedName{Name: entry.Name, Namespace: entry.Namespace}]
+		switch {
+		case ws == nil:
+			continue
+		case ws.Spec.Image == entry.FromImage:
+			// The update was rejected, or the image was reverted by hand
+			setEntryState(entry, workspacev1alpha1.ImageRolloutWorkspaceRolledBack, "Workspace uses its previous image", now)
+			continue
+		case ws.Spec.Image != entry.ToImage:
+			// The image was changed outside the rollout; leave it alone
+			continue
+		}
+
+		running := isRunningWorkspace(ws)
+		if !start || running && slots <= 0 {
+			pending++
+			continue
+		}
+		if err := r.setWorkspaceImage(ctx, rollout, entry, ws, entry.FromImage,
+			workspacev1alpha1.ImageRolloutWorkspaceRollingBack, workspacev1alpha1.ImageRolloutWorkspaceRolledBack, now); err != nil {
+			return 0, err
+		}
+		if running {
+			slots--
+		}
+	}
+	return pending, nil
+}
+
+// setWorkspaceImage patches the workspace image and moves its rollout entry to the in-flight state,
+// or directly to the done state for stopped workspaces, which start with the new image.
+// Patches rejected by the workspace webhook, e.g. for an image the template does not allow,
+// fail the entry rather than the reconciliation.
+func (r *WorkspaceImageRolloutReconciler) setWorkspaceImage(
+	ctx context.Context,
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	entry *workspacev1alpha1.ImageRolloutWorkspaceStatus,
+	ws *workspacev1alpha1.Workspace,
+	image string,
+	inFlightState, doneState workspacev1alpha1.ImageRolloutWorkspaceState,
+	now time.Time,
+) error {
+	logger := logf.FromContext(ctx)
+
+	patch := client.MergeFrom(ws.DeepCopy())
+	ws.Spec.Image = image
+	if err := r.Patch(ctx, ws, patch); err != nil {
+		if errors.IsForbidden(err) || errors.IsInvalid(err) || errors.IsBadRequest(err) {
+			r.failEntry(rollout, entry, fmt.Sprintf("Workspace update was rejected: %v", err), now)
+			return nil
+		}
+		return fmt.Errorf("failed to update workspace %s/%s image: %w", ws.Namespace, ws.Name, err)
+	}
+	logger.Info("Changed workspace image", "workspace", ws.Name, "namespace", ws.Namespace, "image", image)
+
+	if !isRunningWorkspace(ws) {
+		setEntryState(entry, doneState, "Workspace will start with its new image", now)
+		return nil
+	}
+	setEntryState(entry, inFlightState, "Waiting for the workspace to become available with its new image", now)
+	return nil
+}
+
+// failEntry marks the update of a workspace as failed and records an event
+func (r *WorkspaceImageRolloutReconciler) failEntry(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	entry *workspacev1alpha1.ImageRolloutWorkspaceStatus,
+	message string,
+	now time.Time,
+) {
+	setEntryState(entry, workspacev1alpha1.ImageRolloutWorkspaceFailed, message, now)
+	if r.recorder != nil {
+		r.recorder.Event(rollout, "Warning", "WorkspaceUpdateFailed",
+			fmt.Sprintf("Workspace %s/%s: %s", entry.Namespace, entry.Name, message))
+	}
+}
+
+// updateRolloutStatus recomputes the counts and phase of the rollout from its workspace entries.
+// Returns the number of in-flight updates.
+func (r *WorkspaceImageRolloutReconciler) updateRolloutStatus(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	pending int32,
+	windowOpen bool,
+) int32 {
+	status := &rollout.Status
+	status.Pending = pending
+	status.Updating, status.Succeeded, status.Failed, status.RolledBack = 0, 0, 0, 0
+	for _, entry := range status.Workspaces {
+		switch entry.State {
+		case workspacev1alpha1.ImageRolloutWorkspaceUpdating, workspacev1alpha1.ImageRolloutWorkspaceRollingBack:
+			status.Updating++
+		case workspacev1alpha1.ImageRolloutWorkspaceUpdated:
+			status.Succeeded++
+		case workspacev1alpha1.ImageRolloutWorkspaceFailed:
+			status.Failed++
+		case workspacev1alpha1.ImageRolloutWorkspaceRolledBack:
+			status.RolledBack++
+		}
+	}
+	status.ObservedGeneration = rollout.Generation
+
+	done := status.Pending == 0 && status.Updating == 0
+	switch {
+	case rollout.Spec.Rollback && done:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseRolledBack
+		status.Message = fmt.Sprintf("%d workspaces rolled back", status.RolledBack)
+	case done:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseCompleted
+		status.Message = fmt.Sprintf("%d workspaces updated, %d failed", status.Succeeded, status.Failed)
+	case rollout.Spec.Paused:
+		status.Phase = workspacev1alpha1.ImageRolloutPhasePaused
+		status.Message = fmt.Sprintf("Paused with %d workspaces updating and %d pending", status.Updating, status.Pending)
+	case rollout.Spec.Rollback:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseRollingBack
+		status.Message = fmt.Sprintf("%d workspaces rolling back, %d pending", status.Updating, status.Pending)
+	case status.Updating > 0:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseProgressing
+		status.Message = fmt.Sprintf("%d workspaces updating, %d pending", status.Updating, status.Pending)
+	case !windowOpen:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseWaiting
+		status.Message = fmt.Sprintf("Waiting for the maintenance window with %d workspaces pending", status.Pending)
+	default:
+		status.Phase = workspacev1alpha1.ImageRolloutPhaseWaiting
+		status.Message = fmt.Sprintf("Waiting for %d running workspaces to stop", status.Pending)
+	}
+	return status.Updating
+}
+
+// countUnavailable returns the number of running workspaces being restarted by the rollout
+func countUnavailable(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	workspaces map[types.NamespacedName]*workspacev1alpha1.Workspace,
+) int32 {
+	var unavailable int32
+	for _, entry := range rollout.Status.Workspaces {
+		if entry.State != workspacev1alpha1.ImageRolloutWorkspaceUpdating &&
+			entry.State != workspacev1alpha1.ImageRolloutWorkspaceRollingBack {
+			continue
+		}
+		if ws := workspaces[types.NamespacedName{Name: entry.Name, Namespace: entry.Namespace}]; ws != nil && isRunningWorkspace(ws) {
+			unavailable++
+		}
+	}
+	return unavailable
+}
+
+// rolloutMaxUnavailable returns the maxUnavailable of the rollout policy, at least 1
+func rolloutMaxUnavailable(rollout *workspacev1alpha1.WorkspaceImageRollout) int32 {
+	return max(rollout.Spec.Policy.MaxUnavailable, 1)
+}
+
+// upsertRolloutEntry returns the status entry of the workspace, adding it if the rollout did not update it yet
+func upsertRolloutEntry(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	ws *workspacev1alpha1.Workspace,
+) *workspacev1alpha1.ImageRolloutWorkspaceStatus {
+	if entry := findRolloutEntry(rollout, types.NamespacedName{Name: ws.Name, Namespace: ws.Namespace}); entry != nil {
+		return entry
+	}
+	rollout.Status.Workspaces = append(rollout.Status.Workspaces, workspacev1alpha1.ImageRolloutWorkspaceStatus{
+		Namespace: ws.Namespace,
+		Name:      ws.Name,
+	})
+	return &rollout.Status.Workspaces[len(rollout.Status.Workspaces)-1]
+}
+
+// findRolloutEntry returns the status entry of the workspace, or nil if the rollout did not update it
+func findRolloutEntry(
+	rollout *workspacev1alpha1.WorkspaceImageRollout,
+	key types.NamespacedName,
+) *workspacev1alpha1.ImageRolloutWorkspaceStatus {
+	for i := range rollout.Status.Workspaces {
+		entry := &rollout.Status.Workspaces[i]
+		if entry.Name == key.Name && entry.Namespace == key.Namespace {
+			return entry
+		}
+	}
+	return nil
+}
+
+// setEntryState sets the state of a workspace entry, and its transition time when the state changes
+func setEntryState(
+	entry *workspacev1alpha1.ImageRolloutWorkspaceStatus,
+	state workspacev1alpha1.ImageRolloutWorkspaceState,
+	message string,
+	now time.Time,
+) {
+	if entry.State != state {
+		entry.LastTransitionTime = metav1.NewTime(now)
+	}
+	entry.State = state
+	entry.Message = message
+}
+
+// isRunningWorkspace returns true when the workspace is desired to be running
+func isRunningWorkspace(ws *workspacev1alpha1.Workspace) bool {
+	return !IsStoppedDesiredStatus(ws.Spec.DesiredStatus)
+}
+
+// MaintenanceWindowState returns whether the maintenance window is open at now and, when it is
+// closed, how long until it next opens. A nil window is always open.
+func MaintenanceWindowState(window *workspacev1alpha1.MaintenanceWindow, now time.Time) (bool, time.Duration) {
+	if window == nil {
+		return true, 0
+	}
+
+	startOfDay, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return false, LongRequeueDelay
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), startOfDay.Hour(), startOfDay.Minute(), 0, 0, time.UTC)
+
+	var untilOpen time.Duration
+	// Windows that opened yesterday may still be open; look a week ahead for the next opening
+	for offset := -1; offset <= 7; offset++ {
+		start := today.AddDate(0, 0, offset)
+		if len(window.Days) > 0 && !slices.Contains(window.Days, workspacev1alpha1.Weekday(start.Weekday().String())) {
+			continue
+		}
+		if !now.Before(start) && now.Before(start.Add(window.Duration.Duration)) {
+			return true, 0
+		}
+		if start.After(now) && untilOpen == 0 {
+			untilOpen = start.Sub(now)
+		}
+	}
+	if untilOpen == 0 {
+		untilOpen = LongRequeueDelay
+	}
+	return false, untilOpen
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It watches Workspaces so that updates progress as workspaces restart, stop or are created.
+func (r *WorkspaceImageRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.WorkspaceImageRollout{}).
+		Watches(
+			&workspacev1alpha1.Workspace{},
+			handler.EnqueueRequestsFromMapFunc(r.findRolloutsForWorkspace),
+		).
+		Named("workspaceimagerollout").
+		Complete(r)
+}
+
+// findRolloutsForWorkspace maps a Workspace to the WorkspaceImageRollouts that may update it
+func (r *WorkspaceImageRolloutReconciler) findRolloutsForWorkspace(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+
+	rollouts := &workspacev1alpha1.WorkspaceImageRolloutList{}
+	if err := r.List(ctx, rollouts); err != nil {
+		logger.Error(err, "Failed to list WorkspaceImageRollouts")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, rollout := range rollouts.Items {
+		if len(rollout.Spec.Namespaces) > 0 && !slices.Contains(rollout.Spec.Namespaces, obj.GetNamespace()) {
+			continue
+		}
+		if rollout.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rollout.Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+				continue
+			}
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: rollout.Name}})
+	}
+	return requests
+}
+
+// SetupWorkspaceImageRolloutController sets up the WorkspaceImageRollout controller with the Manager.
+// imageResolver resolves the images like the workspace controller, to match the image of the
+// workspace deployments.
+func SetupWorkspaceImageRolloutController(mgr ctrl.Manager, imageResolver *ImageResolver) error {
+	reconciler := &WorkspaceImageRolloutReconciler{
+		Client:        mgr.GetClient(),
+		imageResolver: imageResolver,
+		recorder:      mgr.GetEventRecorderFor("workspaceimagerollout-controller"),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,343 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testRolloutName = "cve-fix"
+	testOldImage    = "jupyter/scipy-notebook:old"
+	testNewImage    = "jupyter/scipy-notebook:new"
+)
+
+func newImageRolloutTestRollout() *workspacev1alpha1.WorkspaceImageRollout {
+	return &workspacev1alpha1.WorkspaceImageRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: testRolloutName},
+		Spec: workspacev1alpha1.WorkspaceImageRolloutSpec{
+			Images: []workspacev1alpha1.ImageMapping{{From: testOldImage, To: testNewImage}},
+			Policy: workspacev1alpha1.ImageRolloutPolicy{MaxUnavailable: 1},
+		},
+	}
+}
+
+func newImageRolloutTestWorkspace(name, image string, running bool) *workspacev1alpha1.Workspace {
+	desiredStatus := DesiredStateRunning
+	if !running {
+		desiredStatus = DesiredStateStopped
+	}
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespaceName},
+		Spec:       workspacev1alpha1.WorkspaceSpec{Image: image, DesiredStatus: desiredStatus},
+	}
+}
+
+func newImageRolloutTestDeployment(name, image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateDeploymentName(&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name}}),
+			Namespace: testNamespaceName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "jupyter", Image: image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+	}
+}
+
+func newImageRolloutTestReconciler(
+	t *testing.T,
+	funcs *interceptor.Funcs,
+	objs ...client.Object,
+) (*WorkspaceImageRolloutReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceImageRollout{})
+	if funcs != nil {
+		builder = builder.WithInterceptorFuncs(*funcs)
+	}
+	fakeClient := builder.Build()
+
+	return &WorkspaceImageRolloutReconciler{Client: fakeClient, imageResolver: NewImageResolver("")}, fakeClient
+}
+
+func reconcileImageRollout(t *testing.T, reconciler *WorkspaceImageRolloutReconciler) ctrl.Result {
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Name: testRolloutName},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func getImageRollout(t *testing.T, c client.Client) *workspacev1alpha1.WorkspaceImageRollout {
+	rollout := &workspacev1alpha1.WorkspaceImageRollout{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: testRolloutName}, rollout))
+	return rollout
+}
+
+func getImageRolloutWorkspaceImage(t *testing.T, c client.Client, name string) string {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: name, Namespace: testNamespaceName}, workspace))
+	return workspace.Spec.Image
+}
+
+func TestWorkspaceImageRollout_UpdatesStoppedAndMaxUnavailableRunning(t *testing.T) {
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		newImageRolloutTestRollout(),
+		newImageRolloutTestWorkspace("running-a", testOldImage, true),
+		newImageRolloutTestWorkspace("running-b", testOldImage, true),
+		newImageRolloutTestWorkspace("stopped", testOldImage, false),
+		newImageRolloutTestWorkspace("other", "other:latest", true),
+	)
+
+	result := reconcileImageRollout(t, reconciler)
+
+	assert.Equal(t, testNewImage, getImageRolloutWorkspaceImage(t, c, "stopped"))
+	assert.Equal(t, testNewImage, getImageRolloutWorkspaceImage(t, c, "running-a"))
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "running-b"))
+	assert.Equal(t, "other:latest", getImageRolloutWorkspaceImage(t, c, "other"))
+
+	rollout := getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseProgressing, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Pending)
+	assert.Equal(t, int32(1), rollout.Status.Updating)
+	assert.Equal(t, int32(1), rollout.Status.Succeeded)
+	assert.Equal(t, LongRequeueDelay, result.RequeueAfter)
+}
+
+func TestWorkspaceImageRollout_CompletesOnceDeploymentIsRolledOut(t *testing.T) {
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		newImageRolloutTestRollout(),
+		newImageRolloutTestWorkspace("running", testOldImage, true),
+		newImageRolloutTestDeployment("running", testNewImage),
+	)
+
+	reconcileImageRollout(t, reconciler)
+	reconcileImageRollout(t, reconciler)
+
+	rollout := getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseCompleted, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Succeeded)
+	require.Len(t, rollout.Status.Workspaces, 1)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutWorkspaceUpdated, rollout.Status.Workspaces[0].State)
+	assert.Equal(t, testOldImage, rollout.Status.Workspaces[0].FromImage)
+}
+
+func TestWorkspaceImageRollout_WaitsForDeploymentWithNewImage(t *testing.T) {
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		newImageRolloutTestRollout(),
+		newImageRolloutTestWorkspace("running", testOldImage, true),
+		newImageRolloutTestDeployment("running", testOldImage),
+	)
+
+	reconcileImageRollout(t, reconciler)
+	reconcileImageRollout(t, reconciler)
+
+	rollout := getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseProgressing, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Updating)
+}
+
+func TestWorkspaceImageRollout_FailsAfterProgressDeadline(t *testing.T) {
+	rollout := newImageRolloutTestRollout()
+	rollout.Status.Workspaces = []workspacev1alpha1.ImageRolloutWorkspaceStatus{{
+		Namespace:          testNamespaceName,
+		Name:               "running",
+		FromImage:          testOldImage,
+		ToImage:            testNewImage,
+		State:              workspacev1alpha1.ImageRolloutWorkspaceUpdating,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+	}}
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		rollout,
+		newImageRolloutTestWorkspace("running", testNewImage, true),
+	)
+
+	reconcileImageRollout(t, reconciler)
+
+	rollout = getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseCompleted, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Failed)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutWorkspaceFailed, rollout.Status.Workspaces[0].State)
+}
+
+func TestWorkspaceImageRollout_RecordsRejectedUpdatesAsFailed(t *testing.T) {
+	funcs := &interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return apierrors.NewForbidden(schema.GroupResource{Resource: "workspaces"}, obj.GetName(),
+				assert.AnError)
+		},
+	}
+	reconciler, c := newImageRolloutTestReconciler(t, funcs,
+		newImageRolloutTestRollout(),
+		newImageRolloutTestWorkspace("running", testOldImage, true),
+	)
+
+	reconcileImageRollout(t, reconciler)
+	reconcileImageRollout(t, reconciler)
+
+	rollout := getImageRollout(t, c)
+	assert.Equal(t, int32(1), rollout.Status.Failed)
+	assert.Equal(t, int32(0), rollout.Status.Pending, "failed workspaces should not be retried")
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseCompleted, rollout.Status.Phase)
+}
+
+func TestWorkspaceImageRollout_OnlyStoppedLeavesRunningWorkspaces(t *testing.T) {
+	rollout := newImageRolloutTestRollout()
+	rollout.Spec.Policy.OnlyStopped = true
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		rollout,
+		newImageRolloutTestWorkspace("running", testOldImage, true),
+		newImageRolloutTestWorkspace("stopped", testOldImage, false),
+	)
+
+	reconcileImageRollout(t, reconciler)
+
+	assert.Equal(t, testNewImage, getImageRolloutWorkspaceImage(t, c, "stopped"))
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "running"))
+	rollout = getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseWaiting, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Pending)
+}
+
+func TestWorkspaceImageRollout_PausedStartsNoUpdate(t *testing.T) {
+	rollout := newImageRolloutTestRollout()
+	rollout.Spec.Paused = true
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		rollout,
+		newImageRolloutTestWorkspace("stopped", testOldImage, false),
+	)
+
+	reconcileImageRollout(t, reconciler)
+
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "stopped"))
+	rollout = getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhasePaused, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.Pending)
+}
+
+func TestWorkspaceImageRollout_ClosedMaintenanceWindowStartsNoUpdate(t *testing.T) {
+	rollout := newImageRolloutTestRollout()
+	now := time.Now().UTC()
+	rollout.Spec.Policy.MaintenanceWindow = &workspacev1alpha1.MaintenanceWindow{
+		Start:    now.Add(2 * time.Hour).Format("15:04"),
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		rollout,
+		newImageRolloutTestWorkspace("stopped", testOldImage, false),
+	)
+
+	result := reconcileImageRollout(t, reconciler)
+
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "stopped"))
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseWaiting, getImageRollout(t, c).Status.Phase)
+	assert.InDelta(t, 2*time.Hour, result.RequeueAfter, float64(time.Minute))
+}
+
+func TestWorkspaceImageRollout_RollbackRevertsUpdatedWorkspaces(t *testing.T) {
+	rollout := newImageRolloutTestRollout()
+	rollout.Spec.Rollback = true
+	rollout.Status.Workspaces = []workspacev1alpha1.ImageRolloutWorkspaceStatus{
+		{
+			Namespace: testNamespaceName, Name: "updated", FromImage: testOldImage, ToImage: testNewImage,
+			State: workspacev1alpha1.ImageRolloutWorkspaceUpdated,
+		},
+		{
+			Namespace: testNamespaceName, Name: "changed", FromImage: testOldImage, ToImage: testNewImage,
+			State: workspacev1alpha1.ImageRolloutWorkspaceUpdated,
+		},
+	}
+	reconciler, c := newImageRolloutTestReconciler(t, nil,
+		rollout,
+		newImageRolloutTestWorkspace("updated", testNewImage, false),
+		newImageRolloutTestWorkspace("changed", "custom:latest", false),
+		newImageRolloutTestWorkspace("untouched", testOldImage, false),
+	)
+
+	reconcileImageRollout(t, reconciler)
+
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "updated"))
+	assert.Equal(t, "custom:latest", getImageRolloutWorkspaceImage(t, c, "changed"))
+	assert.Equal(t, testOldImage, getImageRolloutWorkspaceImage(t, c, "untouched"))
+	rollout = getImageRollout(t, c)
+	assert.Equal(t, workspacev1alpha1.ImageRolloutPhaseRolledBack, rollout.Status.Phase)
+	assert.Equal(t, int32(1), rollout.Status.RolledBack)
+}
+
+func TestMaintenanceWindowState(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 1, 8, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		window        *workspacev1alpha1.MaintenanceWindow
+		wantOpen      bool
+		wantUntilOpen time.Duration
+	}{
+		{
+			name:     "no window",
+			wantOpen: true,
+		},
+		{
+			name:     "open",
+			window:   &workspacev1alpha1.MaintenanceWindow{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			wantOpen: true,
+		},
+		{
+			name:          "closed today",
+			window:        &workspacev1alpha1.MaintenanceWindow{Start: "22:00", Duration: metav1.Duration{Duration: time.Hour}},
+			wantUntilOpen: 22*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "open since yesterday",
+			window: &workspacev1alpha1.MaintenanceWindow{
+				Start: "23:00", Duration: metav1.Duration{Duration: 25 * time.Hour},
+				Days: []workspacev1alpha1.Weekday{"Tuesday"},
+			},
+			wantOpen: true,
+		},
+		{
+			name: "closed on this day",
+			window: &workspacev1alpha1.MaintenanceWindow{
+				Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour},
+				Days: []workspacev1alpha1.Weekday{"Friday"},
+			},
+			wantUntilOpen: 46*time.Hour + 30*time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, untilOpen := MaintenanceWindowState(tt.window, now)
+			assert.Equal(t, tt.wantOpen, open)
+			assert.Equal(t, tt.wantUntilOpen, untilOpen)
+		})
+	}
+}
@@ -0,0 +1,334 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	// ConditionTypePoolReady indicates all the standby pods of the WorkspacePool are ready
+	ConditionTypePoolReady = "Ready"
+
+	// WorkspacePool condition reasons
+	ReasonPoolReady          = "PoolReady"
+	ReasonPoolScaling        = "PoolScaling"
+	ReasonPoolTemplateError  = "TemplateNotFound"
+	ReasonPoolImageUndefined = "ImageUndefined"
+
+	// standbyContainerName is the name of the container of standby pods
+	standbyContainerName = "standby"
+
+	// standbyTerminationGracePeriodSeconds lets claimed standby pods release their node quickly
+	standbyTerminationGracePeriodSeconds int64 = 5
+
+	// poolTemplateRetryDelay is how long a pool whose template is missing waits before trying again
+	poolTemplateRetryDelay = time.Minute
+)
+
+// WorkspacePoolReconciler reconciles a WorkspacePool object by keeping its number of standby pods
+// running the image of its template. Workspaces of the template claim a ready standby pod when they
+// start, which the reconciler then deletes and replaces, so that the workspace pod can take its
+// place on a node that already pulled the image.
+type WorkspacePoolReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	imageResolver *ImageResolver
+	recorder      record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=create;update
+
+// Reconcile replaces the claimed and failed standby pods of a WorkspacePool, scales its standby
+// pods to its size and reports them in its status
+func (r *WorkspacePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspacepool", req.Name, "namespace", req.Namespace)
+
+	pool := &workspacev1alpha1.WorkspacePool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("WorkspacePool not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !pool.DeletionTimestamp.IsZero() {
+		// The standby pods are owned by the WorkspacePool and garbage collected with it
+		return ctrl.Result{}, nil
+	}
+
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	if err := r.Get(ctx, poolTemplateKey(pool), template); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		message := fmt.Sprintf("WorkspaceTemplate %s not found", poolTemplateKey(pool))
+		if err := r.updateStatus(ctx, pool, metav1.ConditionFalse, ReasonPoolTemplateError, message); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: poolTemplateRetryDelay}, nil
+	}
+
+	image := r.standbyImage(pool, template)
+	if image == "" {
+		return ctrl.Result{}, r.updateStatus(ctx, pool, metav1.ConditionFalse, ReasonPoolImageUndefined,
+			"Neither the pool nor its template sets an image")
+	}
+	pool.Status.Image = image
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(pool.Namespace),
+		client.MatchingLabels{LabelWorkspacePool: pool.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list standby pods: %w", err)
+	}
+
+	var ready, starting []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || !metav1.IsControlledBy(pod, pool) {
+			continue
+		}
+		switch {
+		case pod.Labels[LabelStandbyState] == StandbyStateClaimed:
+			// Make room for the workspace that claimed the pod
+			if err := r.deleteStandbyPod(ctx, pod); err != nil {
+				return ctrl.Result{}, err
+			}
+			recordPoolClaim(pool)
+			logger.Info("Released claimed standby pod", "pod", pod.Name, "claimedBy", pod.Annotations[AnnotationClaimedBy])
+			r.recorder.Event(pool, corev1.EventTypeNormal, "StandbyPodClaimed",
+				fmt.Sprintf("Workspace %s claimed standby pod %s", pod.Annotations[AnnotationClaimedBy], pod.Name))
+		case pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded ||
+			standbyPodImage(pod) != image:
+			if err := r.deleteStandbyPod(ctx, pod); err != nil {
+				return ctrl.Result{}, err
+			}
+		case isPodReady(pod):
+			ready = append(ready, pod)
+		default:
+			starting = append(starting, pod)
+		}
+	}
+
+	// Scale down starting pods first, since ready pods are the ones cutting the startup latency
+	surplus := len(ready) + len(starting) - int(pool.Spec.Size)
+	for surplus > 0 {
+		var pod *corev1.Pod
+		if len(starting) > 0 {
+			pod, starting = starting[len(starting)-1], starting[:len(starting)-1]
+		} else {
+			pod, ready = ready[len(ready)-1], ready[:len(ready)-1]
+		}
+		if err := r.deleteStandbyPod(ctx, pod); err != nil {
+			return ctrl.Result{}, err
+		}
+		surplus--
+	}
+	for ; surplus < 0; surplus++ {
+		pod, err := r.buildStandbyPod(pool, template, image)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, pod); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create standby pod: %w", err)
+		}
+		logger.V(1).Info("Created standby pod", "pod", pod.Name)
+		starting = append(starting, pod)
+	}
+
+	pool.Status.Ready = int32(len(ready))
+	pool.Status.Starting = int32(len(starting))
+	if pool.Status.Ready == pool.Spec.Size {
+		return ctrl.Result{}, r.updateStatus(ctx, pool, metav1.ConditionTrue, ReasonPoolReady,
+			fmt.Sprintf("%d standby pods are ready", pool.Status.Ready))
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, pool, metav1.ConditionFalse, ReasonPoolScaling,
+		fmt.Sprintf("%d of %d standby pods are ready", pool.Status.Ready, pool.Spec.Size))
+}
+
+// poolTemplateKey returns the key of the WorkspaceTemplate of the pool
+func poolTemplateKey(pool *workspacev1alpha1.WorkspacePool) client.ObjectKey {
+	namespace := pool.Spec.TemplateRef.Namespace
+	if namespace == "" {
+		namespace = pool.Namespace
+	}
+	return client.ObjectKey{Name: pool.Spec.TemplateRef.Name, Namespace: namespace}
+}
+
+// standbyImage returns the resolved image of the standby pods, as the image of the workspaces of
+// the template would be resolved
+func (r *WorkspacePoolReconciler) standbyImage(
+	pool *workspacev1alpha1.WorkspacePool,
+	template *workspacev1alpha1.WorkspaceTemplate,
+) string {
+	image := pool.Spec.Image
+	if image == "" {
+		image = template.Spec.DefaultImage
+	}
+	if image == "" {
+		return ""
+	}
+	return r.imageResolver.ResolveImage(&workspacev1alpha1.Workspace{
+		Spec: workspacev1alpha1.WorkspaceSpec{Image: image},
+	})
+}
+
+// buildStandbyPod returns a standby pod running the image with the default resources and
+// scheduling constraints of the template, so that it reserves a place a workspace of the
+// template fits in
+func (r *WorkspacePoolReconciler) buildStandbyPod(
+	pool *workspacev1alpha1.WorkspacePool,
+	template *workspacev1alpha1.WorkspaceTemplate,
+	image string,
+) (*corev1.Pod, error) {
+	container := corev1.Container{
+		Name:            standbyContainerName,
+		Image:           image,
+		SecurityContext: template.Spec.DefaultContainerSecurityContext.DeepCopy(),
+	}
+	if template.Spec.DefaultResources != nil {
+		container.Resources = *template.Spec.DefaultResources.DeepCopy()
+	}
+
+	terminationGracePeriodSeconds := standbyTerminationGracePeriodSeconds
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pool.Name + "-standby-",
+			Namespace:    pool.Namespace,
+			Labels: map[string]string{
+				LabelWorkspacePool:                            pool.Name,
+				LabelStandbyState:                             StandbyStateAvailable,
+				workspaceutil.LabelWorkspaceTemplate:          template.Name,
+				workspaceutil.LabelWorkspaceTemplateNamespace: template.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers:                    []corev1.Container{container},
+			NodeSelector:                  template.Spec.DefaultNodeSelector,
+			Affinity:                      template.Spec.DefaultAffinity.DeepCopy(),
+			Tolerations:                   template.Spec.DefaultTolerations,
+			SecurityContext:               template.Spec.DefaultPodSecurityContext.DeepCopy(),
+			PriorityClassName:             pool.Spec.PriorityClassName,
+			TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+		},
+	}
+	if err := controllerutil.SetControllerReference(pool, pod, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference on standby pod: %w", err)
+	}
+	return pod, nil
+}
+
+// deleteStandbyPod deletes a standby pod, ignoring pods already gone
+func (r *WorkspacePoolReconciler) deleteStandbyPod(ctx context.Context, pod *corev1.Pod) error {
+	if err := r.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete standby pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// recordPoolClaim accounts the claim of a standby pod in the status of its pool
+func recordPoolClaim(pool *workspacev1alpha1.WorkspacePool) {
+	pool.Status.Claims++
+	claimTime := metav1.Now()
+	pool.Status.LastClaimTime = &claimTime
+}
+
+// standbyPodImage returns the image of a standby pod
+func standbyPodImage(pod *corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == standbyContainerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// updateStatus sets the Ready condition and persists the WorkspacePool status
+func (r *WorkspacePoolReconciler) updateStatus(
+	ctx context.Context,
+	pool *workspacev1alpha1.WorkspacePool,
+	status metav1.ConditionStatus,
+	reason, message string) error {
+
+	pool.Status.ObservedGeneration = pool.Generation
+	meta.SetStatusCondition(&pool.Status.Conditions, NewCondition(ConditionTypePoolReady, status, reason, message))
+
+	if err := r.Status().Update(ctx, pool); err != nil {
+		return fmt.Errorf("failed to update WorkspacePool status: %w", err)
+	}
+	return nil
+}
+
+// findPoolsForTemplate maps a WorkspaceTemplate to the WorkspacePools serving it, so that
+// standby pods follow changes of the default image and resources of the template
+func (r *WorkspacePoolReconciler) findPoolsForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	template, ok := obj.(*workspacev1alpha1.WorkspaceTemplate)
+	if !ok {
+		return nil
+	}
+
+	pools := &workspacev1alpha1.WorkspacePoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list WorkspacePools", "template", template.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range pools.Items {
+		if poolTemplateKey(&pools.Items[i]) == client.ObjectKeyFromObject(template) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&pools.Items[i]),
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It owns the standby pods, so that claimed and failed pods are replaced right away.
+func (r *WorkspacePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.WorkspacePool{}).
+		Owns(&corev1.Pod{}).
+		Watches(
+			&workspacev1alpha1.WorkspaceTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.findPoolsForTemplate),
+		).
+		Named("workspacepool").
+		Complete(r)
+}
+
+// SetupWorkspacePoolController sets up the WorkspacePool controller with the Manager.
+// imageResolver resolves the images like the workspace controller, to warm the same images.
+func SetupWorkspacePoolController(mgr ctrl.Manager, imageResolver *ImageResolver) error {
+	reconciler := &WorkspacePoolReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		imageResolver: imageResolver,
+		recorder:      mgr.GetEventRecorderFor("workspacepool-controller"),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
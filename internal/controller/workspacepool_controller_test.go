@@ -0,0 +1,221 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	testPoolName     = "notebook-pool"
+	testPoolTemplate = "notebook-template"
+)
+
+func newPoolTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newPoolTestPool(size int32) *workspacev1alpha1.WorkspacePool {
+	return &workspacev1alpha1.WorkspacePool{
+		ObjectMeta: metav1.ObjectMeta{Name: testPoolName, Namespace: testNamespaceName, UID: "pool-uid"},
+		Spec: workspacev1alpha1.WorkspacePoolSpec{
+			TemplateRef: workspacev1alpha1.TemplateRef{Name: testPoolTemplate},
+			Size:        size,
+		},
+	}
+}
+
+func newPoolTestTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: testPoolTemplate, Namespace: testNamespaceName},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:         "Notebook",
+			DefaultImage:        imageMinimalNotebook,
+			DefaultNodeSelector: map[string]string{"pool": "notebooks"},
+		},
+	}
+}
+
+// newPoolTestStandbyPod returns a standby pod of the test pool, ready when nodeName is set
+func newPoolTestStandbyPod(
+	t *testing.T,
+	scheme *runtime.Scheme,
+	pool *workspacev1alpha1.WorkspacePool,
+	name, image, state, nodeName string,
+) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespaceName,
+			Labels: map[string]string{
+				LabelWorkspacePool:                            pool.Name,
+				LabelStandbyState:                             state,
+				workspaceutil.LabelWorkspaceTemplate:          testPoolTemplate,
+				workspaceutil.LabelWorkspaceTemplateNamespace: testNamespaceName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: standbyContainerName, Image: image}},
+		},
+	}
+	if nodeName != "" {
+		pod.Status = corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		}
+	}
+	require.NoError(t, controllerutil.SetControllerReference(pool, pod, scheme))
+	return pod
+}
+
+func newPoolTestReconciler(
+	t *testing.T,
+	scheme *runtime.Scheme,
+	objs ...client.Object,
+) (*WorkspacePoolReconciler, client.Client) {
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&workspacev1alpha1.WorkspacePool{}).Build()
+	return &WorkspacePoolReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		imageResolver: NewImageResolver(""),
+		recorder:      record.NewFakeRecorder(10),
+	}, fakeClient
+}
+
+func reconcilePool(t *testing.T, reconciler *WorkspacePoolReconciler) ctrl.Result {
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Name: testPoolName, Namespace: testNamespaceName},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func listPoolTestPods(t *testing.T, c client.Client) []corev1.Pod {
+	pods := &corev1.PodList{}
+	require.NoError(t, c.List(context.Background(), pods, client.InNamespace(testNamespaceName)))
+	return pods.Items
+}
+
+func getPoolTestPool(t *testing.T, c client.Client) *workspacev1alpha1.WorkspacePool {
+	pool := &workspacev1alpha1.WorkspacePool{}
+	require.NoError(t, c.Get(context.Background(),
+		client.ObjectKey{Name: testPoolName, Namespace: testNamespaceName}, pool))
+	return pool
+}
+
+func TestWorkspacePool_CreatesStandbyPodsFromTemplate(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	reconciler, c := newPoolTestReconciler(t, scheme, newPoolTestPool(2), newPoolTestTemplate())
+
+	reconcilePool(t, reconciler)
+
+	pods := listPoolTestPods(t, c)
+	require.Len(t, pods, 2)
+	for _, pod := range pods {
+		assert.Equal(t, StandbyStateAvailable, pod.Labels[LabelStandbyState])
+		assert.Equal(t, testPoolTemplate, pod.Labels[workspaceutil.LabelWorkspaceTemplate])
+		assert.Equal(t, imageMinimalNotebook, standbyPodImage(&pod))
+		assert.Equal(t, map[string]string{"pool": "notebooks"}, pod.Spec.NodeSelector)
+	}
+
+	pool := getPoolTestPool(t, c)
+	assert.Equal(t, int32(0), pool.Status.Ready)
+	assert.Equal(t, int32(2), pool.Status.Starting)
+	assert.Equal(t, imageMinimalNotebook, pool.Status.Image)
+	condition := meta.FindStatusCondition(pool.Status.Conditions, ConditionTypePoolReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonPoolScaling, condition.Reason)
+}
+
+func TestWorkspacePool_ReplacesClaimedPod(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	pool := newPoolTestPool(1)
+	claimed := newPoolTestStandbyPod(t, scheme, pool, "claimed", imageMinimalNotebook, StandbyStateClaimed, "node-a")
+	reconciler, c := newPoolTestReconciler(t, scheme, pool, newPoolTestTemplate(), claimed)
+
+	reconcilePool(t, reconciler)
+
+	pods := listPoolTestPods(t, c)
+	require.Len(t, pods, 1)
+	assert.NotEqual(t, "claimed", pods[0].Name)
+	assert.Equal(t, StandbyStateAvailable, pods[0].Labels[LabelStandbyState])
+
+	pool = getPoolTestPool(t, c)
+	assert.Equal(t, int64(1), pool.Status.Claims)
+	assert.NotNil(t, pool.Status.LastClaimTime)
+}
+
+func TestWorkspacePool_ReplacesPodsOfPreviousImageAndScalesDown(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	pool := newPoolTestPool(1)
+	reconciler, c := newPoolTestReconciler(t, scheme, pool, newPoolTestTemplate(),
+		newPoolTestStandbyPod(t, scheme, pool, "outdated", "jupyter/minimal-notebook:old", StandbyStateAvailable, "node-a"),
+		newPoolTestStandbyPod(t, scheme, pool, "ready", imageMinimalNotebook, StandbyStateAvailable, "node-b"),
+		newPoolTestStandbyPod(t, scheme, pool, "starting", imageMinimalNotebook, StandbyStateAvailable, ""),
+	)
+
+	reconcilePool(t, reconciler)
+
+	pods := listPoolTestPods(t, c)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "ready", pods[0].Name)
+
+	pool = getPoolTestPool(t, c)
+	assert.Equal(t, int32(1), pool.Status.Ready)
+	condition := meta.FindStatusCondition(pool.Status.Conditions, ConditionTypePoolReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonPoolReady, condition.Reason)
+}
+
+func TestWorkspacePool_TemplateNotFound(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	reconciler, c := newPoolTestReconciler(t, scheme, newPoolTestPool(2))
+
+	result := reconcilePool(t, reconciler)
+
+	assert.Equal(t, poolTemplateRetryDelay, result.RequeueAfter)
+	assert.Empty(t, listPoolTestPods(t, c))
+	condition := meta.FindStatusCondition(getPoolTestPool(t, c).Status.Conditions, ConditionTypePoolReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonPoolTemplateError, condition.Reason)
+}
+
+func TestWorkspacePool_FindPoolsForTemplate(t *testing.T) {
+	scheme := newPoolTestScheme(t)
+	other := newPoolTestPool(1)
+	other.Name = "other-pool"
+	other.Spec.TemplateRef.Name = "other-template"
+	reconciler, _ := newPoolTestReconciler(t, scheme, newPoolTestPool(1), other)
+
+	requests := reconciler.findPoolsForTemplate(context.Background(), newPoolTestTemplate())
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, testPoolName, requests[0].Name)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// WorkspaceQuotaReconciler reports the usage of WorkspaceQuotas in their status.
+// Quotas are enforced by the Workspace validating webhook; this controller only surfaces usage.
+type WorkspaceQuotaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacequotas/status,verbs=get;update;patch
+
+// Reconcile recomputes the usage of a WorkspaceQuota from the workspaces in its namespace
+func (r *WorkspaceQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspacequota", req.Name, "namespace", req.Namespace)
+
+	quota := &workspacev1alpha1.WorkspaceQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("WorkspaceQuota not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := r.List(ctx, workspaces, client.InNamespace(quota.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	status := ComputeWorkspaceQuotaStatus(quota, workspaces.Items)
+	if equality.Semantic.DeepEqual(quota.Status, status) {
+		return ctrl.Result{}, nil
+	}
+
+	quota.Status = status
+	if err := r.Status().Update(ctx, quota); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update WorkspaceQuota status: %w", err)
+	}
+	logger.V(1).Info("Updated WorkspaceQuota usage",
+		"workspaces", status.Used.Workspaces, "runningWorkspaces", status.Used.RunningWorkspaces)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It watches Workspaces so quota usage follows workspace creation, deletion, start and stop.
+func (r *WorkspaceQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.WorkspaceQuota{}).
+		Watches(
+			&workspacev1alpha1.Workspace{},
+			handler.EnqueueRequestsFromMapFunc(r.findQuotasForWorkspace),
+		).
+		Named("workspacequota").
+		Complete(r)
+}
+
+// findQuotasForWorkspace maps a Workspace to the WorkspaceQuotas of its namespace
+func (r *WorkspaceQuotaReconciler) findQuotasForWorkspace(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+
+	quotas := &workspacev1alpha1.WorkspaceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Error(err, "Failed to list WorkspaceQuotas", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(quotas.Items))
+	for _, quota := range quotas.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: quota.Name, Namespace: quota.Namespace},
+		})
+	}
+	return requests
+}
+
+// SetupWorkspaceQuotaController sets up the WorkspaceQuota controller with the Manager
+func SetupWorkspaceQuotaController(mgr ctrl.Manager) error {
+	reconciler := &WorkspaceQuotaReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WorkspaceQuota controller", func() {
+	const quotaNs = "default"
+
+	var (
+		ctx      context.Context
+		scheme   *runtime.Scheme
+		quotaKey types.NamespacedName
+	)
+
+	newQuotaWorkspace := func(name, user, desiredStatus string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   quotaNs,
+				Annotations: map[string]string{AnnotationCreatedBy: user},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DesiredStatus: desiredStatus,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				},
+				Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+			},
+		}
+	}
+
+	newQuota := func(scope workspacev1alpha1.QuotaScope) *workspacev1alpha1.WorkspaceQuota {
+		return &workspacev1alpha1.WorkspaceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: quotaNs, Generation: 2},
+			Spec: workspacev1alpha1.WorkspaceQuotaSpec{
+				Scope: scope,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:                resource.MustParse("8"),
+					"nvidia.com/gpu":                  resource.MustParse("2"),
+					workspacev1alpha1.ResourceStorage: resource.MustParse("100Gi"),
+				},
+			},
+		}
+	}
+
+	newReconciler := func(objs ...client.Object) *WorkspaceQuotaReconciler {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&workspacev1alpha1.WorkspaceQuota{}).
+			Build()
+		return &WorkspaceQuotaReconciler{Client: fakeClient, Scheme: scheme}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		quotaKey = types.NamespacedName{Name: "quota", Namespace: quotaNs}
+	})
+
+	It("should report namespace usage, counting compute of running workspaces only", func() {
+		reconciler := newReconciler(
+			newQuota(workspacev1alpha1.QuotaScopeNamespace),
+			newQuotaWorkspace("running", "alice", DesiredStateRunning),
+			newQuotaWorkspace("stopped", "bob", DesiredStateStopped),
+		)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: quotaKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		quota := &workspacev1alpha1.WorkspaceQuota{}
+		Expect(reconciler.Get(ctx, quotaKey, quota)).To(Succeed())
+		Expect(quota.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(quota.Status.Used).NotTo(BeNil())
+		Expect(quota.Status.Used.Workspaces).To(Equal(int32(2)))
+		Expect(quota.Status.Used.RunningWorkspaces).To(Equal(int32(1)))
+		Expect(quota.Status.Used.Resources.Cpu().String()).To(Equal("1"))
+		gpu := quota.Status.Used.Resources["nvidia.com/gpu"]
+		Expect(gpu.String()).To(Equal("1"))
+		storage := quota.Status.Used.Resources[workspacev1alpha1.ResourceStorage]
+		Expect(storage.String()).To(Equal("20Gi"))
+		Expect(quota.Status.Users).To(BeEmpty())
+	})
+
+	It("should report usage per user for User-scoped quotas", func() {
+		reconciler := newReconciler(
+			newQuota(workspacev1alpha1.QuotaScopeUser),
+			newQuotaWorkspace("alice-1", "alice", DesiredStateRunning),
+			newQuotaWorkspace("alice-2", "alice", DesiredStateRunning),
+			newQuotaWorkspace("bob-1", "bob", DesiredStateStopped),
+		)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: quotaKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		quota := &workspacev1alpha1.WorkspaceQuota{}
+		Expect(reconciler.Get(ctx, quotaKey, quota)).To(Succeed())
+		Expect(quota.Status.Users).To(HaveLen(2))
+		Expect(quota.Status.Users[0].User).To(Equal("alice"))
+		Expect(quota.Status.Users[0].Used.RunningWorkspaces).To(Equal(int32(2)))
+		Expect(quota.Status.Users[0].Used.Resources.Cpu().String()).To(Equal("2"))
+		Expect(quota.Status.Users[1].User).To(Equal("bob"))
+		Expect(quota.Status.Users[1].Used.Workspaces).To(Equal(int32(1)))
+		Expect(quota.Status.Users[1].Used.RunningWorkspaces).To(Equal(int32(0)))
+	})
+
+	It("should not count workspaces being deleted", func() {
+		deleting := newQuotaWorkspace("deleting", "alice", DesiredStateRunning)
+		deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		deleting.Finalizers = []string{WorkspaceFinalizerName}
+		reconciler := newReconciler(newQuota(workspacev1alpha1.QuotaScopeNamespace), deleting)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: quotaKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		quota := &workspacev1alpha1.WorkspaceQuota{}
+		Expect(reconciler.Get(ctx, quotaKey, quota)).To(Succeed())
+		Expect(quota.Status.Used.Workspaces).To(Equal(int32(0)))
+	})
+
+	It("should map a workspace to the quotas of its namespace", func() {
+		reconciler := newReconciler(newQuota(workspacev1alpha1.QuotaScopeNamespace))
+		requests := reconciler.findQuotasForWorkspace(ctx, newQuotaWorkspace("ws", "alice", DesiredStateRunning))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].NamespacedName).To(Equal(quotaKey))
+	})
+})
@@ -0,0 +1,306 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeSnapshotReady indicates the WorkspaceSnapshot can be used to restore a workspace
+	ConditionTypeSnapshotReady = "Ready"
+
+	// WorkspaceSnapshot condition reasons
+	ReasonWaitingForWorkspace     = "WaitingForWorkspace"
+	ReasonWaitingForWorkspaceStop = "WaitingForWorkspaceStop"
+	ReasonNoPrimaryStorage        = "NoPrimaryStorage"
+	ReasonSnapshotInProgress      = "SnapshotInProgress"
+	ReasonSnapshotReady           = "SnapshotReady"
+	ReasonSnapshotError           = "SnapshotError"
+)
+
+// WorkspaceSnapshotReconciler reconciles a WorkspaceSnapshot object by creating a CSI
+// VolumeSnapshot of the referenced workspace's primary storage and mirroring its state.
+type WorkspaceSnapshotReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacesnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+// Reconcile creates the VolumeSnapshot for a WorkspaceSnapshot once its trigger is satisfied,
+// then tracks the VolumeSnapshot until it is ready to use or has failed.
+func (r *WorkspaceSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("workspacesnapshot", req.Name, "namespace", req.Namespace)
+
+	snapshot := &workspacev1alpha1.WorkspaceSnapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("WorkspaceSnapshot not found, it may have been deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !snapshot.DeletionTimestamp.IsZero() {
+		// The VolumeSnapshot is owned by the WorkspaceSnapshot and garbage collected with it
+		return ctrl.Result{}, nil
+	}
+
+	// Terminal phases: the VolumeSnapshot content is immutable once ready or failed
+	if snapshot.Status.Phase == workspacev1alpha1.SnapshotPhaseReady ||
+		snapshot.Status.Phase == workspacev1alpha1.SnapshotPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	volumeSnapshot, err := r.getVolumeSnapshot(ctx, snapshot)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if volumeSnapshot == nil {
+		ws, err := r.checkSnapshotSource(ctx, snapshot)
+		if err != nil || ws == nil {
+			return ctrl.Result{}, err
+		}
+
+		volumeSnapshot, err = r.createVolumeSnapshot(ctx, snapshot, ws)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.syncStatusFromVolumeSnapshot(ctx, snapshot, volumeSnapshot); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if snapshot.Status.Phase == workspacev1alpha1.SnapshotPhaseInProgress {
+		// Owned VolumeSnapshot updates requeue us; poll as a fallback
+		return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// checkSnapshotSource verifies the source workspace is in a state where the snapshot can be taken,
+// and returns it. Returns nil (and records why in status) when the snapshot must wait or cannot be taken.
+func (r *WorkspaceSnapshotReconciler) checkSnapshotSource(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) (*workspacev1alpha1.Workspace, error) {
+	ws := &workspacev1alpha1.Workspace{}
+	err := r.Get(ctx, types.NamespacedName{Name: snapshot.Spec.WorkspaceName, Namespace: snapshot.Namespace}, ws)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The workspace watch requeues us if the workspace is created later
+			return nil, r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhasePending, metav1.ConditionFalse,
+				ReasonWaitingForWorkspace, fmt.Sprintf("Workspace %s not found", snapshot.Spec.WorkspaceName))
+		}
+		return nil, err
+	}
+
+	if ws.Spec.Storage == nil {
+		return nil, r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhaseFailed, metav1.ConditionFalse,
+			ReasonNoPrimaryStorage, fmt.Sprintf("Workspace %s has no primary storage to snapshot", ws.Name))
+	}
+
+	if snapshot.Spec.Trigger == workspacev1alpha1.SnapshotTriggerOnStop && !isWorkspaceStopped(ws) {
+		return nil, r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhasePending, metav1.ConditionFalse,
+			ReasonWaitingForWorkspaceStop, fmt.Sprintf("Waiting for workspace %s to stop", ws.Name))
+	}
+
+	return ws, nil
+}
+
+// isWorkspaceStopped returns true when the workspace reports that all of its compute is stopped
+func isWorkspaceStopped(ws *workspacev1alpha1.Workspace) bool {
+	stopped := FindCondition(&ws.Status.Conditions, ConditionTypeStopped)
+	return stopped != nil && stopped.Status == metav1.ConditionTrue
+}
+
+// getVolumeSnapshot returns the VolumeSnapshot for the WorkspaceSnapshot, or nil if it does not exist
+func (r *WorkspaceSnapshotReconciler) getVolumeSnapshot(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) (*unstructured.Unstructured, error) {
+	volumeSnapshot := newVolumeSnapshotObject()
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      GenerateVolumeSnapshotName(snapshot.Name),
+		Namespace: snapshot.Namespace,
+	}, volumeSnapshot)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get VolumeSnapshot: %w", err)
+	}
+	return volumeSnapshot, nil
+}
+
+// createVolumeSnapshot creates the VolumeSnapshot of the source workspace's PVC
+func (r *WorkspaceSnapshotReconciler) createVolumeSnapshot(
+	ctx context.Context,
+	snapshot *workspacev1alpha1.WorkspaceSnapshot,
+	ws *workspacev1alpha1.Workspace) (*unstructured.Unstructured, error) {
+	logger := logf.FromContext(ctx)
+
+	volumeSnapshot, err := BuildVolumeSnapshot(snapshot, ws, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Create(ctx, volumeSnapshot); err != nil {
+		return nil, fmt.Errorf("failed to create VolumeSnapshot: %w", err)
+	}
+
+	logger.Info("Created VolumeSnapshot", "volumeSnapshot", volumeSnapshot.GetName())
+	if r.recorder != nil {
+		r.recorder.Event(snapshot, "Normal", "SnapshotCreated",
+			fmt.Sprintf("Created VolumeSnapshot %s", volumeSnapshot.GetName()))
+	}
+	return volumeSnapshot, nil
+}
+
+// BuildVolumeSnapshot builds the CSI VolumeSnapshot for a WorkspaceSnapshot of the workspace ws
+func BuildVolumeSnapshot(
+	snapshot *workspacev1alpha1.WorkspaceSnapshot,
+	ws *workspacev1alpha1.Workspace,
+	scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	volumeSnapshot := newVolumeSnapshotObject()
+	volumeSnapshot.SetName(GenerateVolumeSnapshotName(snapshot.Name))
+	volumeSnapshot.SetNamespace(snapshot.Namespace)
+	volumeSnapshot.SetLabels(GenerateLabels(snapshot.Spec.WorkspaceName))
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": GeneratePVCName(ws),
+		},
+	}
+	if snapshot.Spec.VolumeSnapshotClassName != nil {
+		spec["volumeSnapshotClassName"] = *snapshot.Spec.VolumeSnapshotClassName
+	}
+	volumeSnapshot.Object["spec"] = spec
+
+	if err := controllerutil.SetControllerReference(snapshot, volumeSnapshot, scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	return volumeSnapshot, nil
+}
+
+// syncStatusFromVolumeSnapshot mirrors the VolumeSnapshot readiness into the WorkspaceSnapshot status
+func (r *WorkspaceSnapshotReconciler) syncStatusFromVolumeSnapshot(
+	ctx context.Context,
+	snapshot *workspacev1alpha1.WorkspaceSnapshot,
+	volumeSnapshot *unstructured.Unstructured) error {
+
+	snapshot.Status.VolumeSnapshotName = volumeSnapshot.GetName()
+	snapshot.Status.SourcePVCName, _, _ = unstructured.NestedString(volumeSnapshot.Object, "spec", "source", "persistentVolumeClaimName")
+
+	if errMessage, found, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "error", "message"); found && errMessage != "" {
+		return r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhaseFailed, metav1.ConditionFalse,
+			ReasonSnapshotError, errMessage)
+	}
+
+	if restoreSize, found, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "restoreSize"); found {
+		if quantity, err := resource.ParseQuantity(restoreSize); err == nil {
+			snapshot.Status.RestoreSize = &quantity
+		}
+	}
+
+	if readyToUse, _, _ := unstructured.NestedBool(volumeSnapshot.Object, "status", "readyToUse"); readyToUse {
+		return r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhaseReady, metav1.ConditionTrue,
+			ReasonSnapshotReady, "Snapshot is ready to use")
+	}
+
+	return r.updateStatus(ctx, snapshot, workspacev1alpha1.SnapshotPhaseInProgress, metav1.ConditionFalse,
+		ReasonSnapshotInProgress, fmt.Sprintf("Waiting for VolumeSnapshot %s to be ready", volumeSnapshot.GetName()))
+}
+
+// updateStatus sets the phase and Ready condition and persists the WorkspaceSnapshot status
+func (r *WorkspaceSnapshotReconciler) updateStatus(
+	ctx context.Context,
+	snapshot *workspacev1alpha1.WorkspaceSnapshot,
+	phase workspacev1alpha1.SnapshotPhase,
+	status metav1.ConditionStatus,
+	reason, message string) error {
+
+	snapshot.Status.Phase = phase
+	snapshot.Status.Message = message
+	meta.SetStatusCondition(&snapshot.Status.Conditions, NewCondition(ConditionTypeSnapshotReady, status, reason, message))
+
+	if err := r.Status().Update(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to update WorkspaceSnapshot status: %w", err)
+	}
+	return nil
+}
+
+// newVolumeSnapshotObject returns an empty unstructured VolumeSnapshot
+func newVolumeSnapshotObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(volumeSnapshotAPIVersion)
+	obj.SetKind(kindVolumeSnapshot)
+	return obj
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It owns VolumeSnapshots and watches Workspaces so OnStop snapshots are taken once the workspace stops.
+func (r *WorkspaceSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.WorkspaceSnapshot{}).
+		Owns(newVolumeSnapshotObject()).
+		Watches(
+			&workspacev1alpha1.Workspace{},
+			handler.EnqueueRequestsFromMapFunc(r.findSnapshotsForWorkspace),
+		).
+		Named("workspacesnapshot").
+		Complete(r)
+}
+
+// findSnapshotsForWorkspace maps a Workspace to the pending WorkspaceSnapshots that reference it
+func (r *WorkspaceSnapshotReconciler) findSnapshotsForWorkspace(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+
+	snapshots := &workspacev1alpha1.WorkspaceSnapshotList{}
+	if err := r.List(ctx, snapshots, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Error(err, "Failed to list WorkspaceSnapshots", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, snapshot := range snapshots.Items {
+		if snapshot.Spec.WorkspaceName != obj.GetName() || snapshot.Status.VolumeSnapshotName != "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snapshot.Name, Namespace: snapshot.Namespace},
+		})
+	}
+	return requests
+}
+
+// SetupWorkspaceSnapshotController sets up the WorkspaceSnapshot controller with the Manager.
+// It requires the snapshot.storage.k8s.io CRDs to be installed in the cluster.
+func SetupWorkspaceSnapshotController(mgr ctrl.Manager) error {
+	reconciler := &WorkspaceSnapshotReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor("workspacesnapshot-controller"),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WorkspaceSnapshot controller", func() {
+	const (
+		snapshotName = "snap"
+		sourceName   = "source-ws"
+		snapshotNs   = "default"
+	)
+
+	var (
+		ctx         context.Context
+		scheme      *runtime.Scheme
+		snapshotKey types.NamespacedName
+	)
+
+	newSnapshot := func(trigger workspacev1alpha1.SnapshotTrigger) *workspacev1alpha1.WorkspaceSnapshot {
+		return &workspacev1alpha1.WorkspaceSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: snapshotName, Namespace: snapshotNs, UID: "snap-uid"},
+			Spec: workspacev1alpha1.WorkspaceSnapshotSpec{
+				WorkspaceName: sourceName,
+				Trigger:       trigger,
+			},
+		}
+	}
+
+	newWorkspace := func(stopped bool) *workspacev1alpha1.Workspace {
+		ws := &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: sourceName, Namespace: snapshotNs},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("1Gi")},
+			},
+		}
+		if stopped {
+			ws.Status.Conditions = []metav1.Condition{
+				NewCondition(ConditionTypeStopped, metav1.ConditionTrue, ReasonResourcesStopped, ""),
+			}
+		}
+		return ws
+	}
+
+	newReconciler := func(objs ...client.Object) *WorkspaceSnapshotReconciler {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&workspacev1alpha1.WorkspaceSnapshot{}).
+			Build()
+		return &WorkspaceSnapshotReconciler{Client: fakeClient, Scheme: scheme}
+	}
+
+	getSnapshot := func(r *WorkspaceSnapshotReconciler) *workspacev1alpha1.WorkspaceSnapshot {
+		snapshot := &workspacev1alpha1.WorkspaceSnapshot{}
+		Expect(r.Get(ctx, snapshotKey, snapshot)).To(Succeed())
+		return snapshot
+	}
+
+	getVolumeSnapshot := func(r *WorkspaceSnapshotReconciler) (*unstructured.Unstructured, error) {
+		volumeSnapshot := newVolumeSnapshotObject()
+		err := r.Get(ctx, types.NamespacedName{
+			Name:      GenerateVolumeSnapshotName(snapshotName),
+			Namespace: snapshotNs,
+		}, volumeSnapshot)
+		return volumeSnapshot, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		snapshotKey = types.NamespacedName{Name: snapshotName, Namespace: snapshotNs}
+	})
+
+	It("should build a VolumeSnapshot of the workspace PVC owned by the snapshot", func() {
+		snapshot := newSnapshot(workspacev1alpha1.SnapshotTriggerOnDemand)
+		className := "csi-snapclass"
+		snapshot.Spec.VolumeSnapshotClassName = &className
+
+		ws := newWorkspace(true)
+		ws.Annotations = map[string]string{AnnotationResourceNamePrefix: "nb"}
+
+		volumeSnapshot, err := BuildVolumeSnapshot(snapshot, ws, scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volumeSnapshot.GetName()).To(Equal(GenerateVolumeSnapshotName(snapshotName)))
+		Expect(volumeSnapshot.GetAPIVersion()).To(Equal("snapshot.storage.k8s.io/v1"))
+
+		pvcName, _, _ := unstructured.NestedString(volumeSnapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		Expect(pvcName).To(Equal("nb-" + sourceName + "-pvc"))
+		class, _, _ := unstructured.NestedString(volumeSnapshot.Object, "spec", "volumeSnapshotClassName")
+		Expect(class).To(Equal(className))
+
+		Expect(volumeSnapshot.GetOwnerReferences()).To(HaveLen(1))
+		Expect(volumeSnapshot.GetOwnerReferences()[0].UID).To(Equal(snapshot.UID))
+	})
+
+	It("should create the VolumeSnapshot on demand", func() {
+		r := newReconciler(newSnapshot(workspacev1alpha1.SnapshotTriggerOnDemand), newWorkspace(false))
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(LongRequeueDelay))
+
+		_, err = getVolumeSnapshot(r)
+		Expect(err).NotTo(HaveOccurred())
+
+		snapshot := getSnapshot(r)
+		Expect(snapshot.Status.Phase).To(Equal(workspacev1alpha1.SnapshotPhaseInProgress))
+		Expect(snapshot.Status.VolumeSnapshotName).To(Equal(GenerateVolumeSnapshotName(snapshotName)))
+	})
+
+	It("should wait for the workspace to stop when triggered on stop", func() {
+		r := newReconciler(newSnapshot(workspacev1alpha1.SnapshotTriggerOnStop), newWorkspace(false))
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = getVolumeSnapshot(r)
+		Expect(err).To(HaveOccurred())
+
+		snapshot := getSnapshot(r)
+		Expect(snapshot.Status.Phase).To(Equal(workspacev1alpha1.SnapshotPhasePending))
+		Expect(snapshot.Status.Conditions[0].Reason).To(Equal(ReasonWaitingForWorkspaceStop))
+	})
+
+	It("should create the VolumeSnapshot once the workspace is stopped", func() {
+		r := newReconciler(newSnapshot(workspacev1alpha1.SnapshotTriggerOnStop), newWorkspace(true))
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = getVolumeSnapshot(r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail when the workspace has no primary storage", func() {
+		ws := newWorkspace(false)
+		ws.Spec.Storage = nil
+		r := newReconciler(newSnapshot(workspacev1alpha1.SnapshotTriggerOnDemand), ws)
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getSnapshot(r).Status.Phase).To(Equal(workspacev1alpha1.SnapshotPhaseFailed))
+	})
+
+	It("should mark the snapshot ready when the VolumeSnapshot is ready to use", func() {
+		snapshot := newSnapshot(workspacev1alpha1.SnapshotTriggerOnDemand)
+		volumeSnapshot, err := BuildVolumeSnapshot(snapshot, newWorkspace(false), scheme)
+		Expect(err).NotTo(HaveOccurred())
+		volumeSnapshot.Object["status"] = map[string]interface{}{
+			"readyToUse":  true,
+			"restoreSize": "1Gi",
+		}
+		r := newReconciler(snapshot, newWorkspace(false), volumeSnapshot)
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		updated := getSnapshot(r)
+		Expect(updated.Status.Phase).To(Equal(workspacev1alpha1.SnapshotPhaseReady))
+		Expect(updated.Status.RestoreSize).NotTo(BeNil())
+		Expect(updated.Status.RestoreSize.String()).To(Equal("1Gi"))
+	})
+
+	It("should mark the snapshot failed when the VolumeSnapshot reports an error", func() {
+		snapshot := newSnapshot(workspacev1alpha1.SnapshotTriggerOnDemand)
+		volumeSnapshot, err := BuildVolumeSnapshot(snapshot, newWorkspace(false), scheme)
+		Expect(err).NotTo(HaveOccurred())
+		volumeSnapshot.Object["status"] = map[string]interface{}{
+			"error": map[string]interface{}{"message": "driver failure"},
+		}
+		r := newReconciler(snapshot, newWorkspace(false), volumeSnapshot)
+
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: snapshotKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := getSnapshot(r)
+		Expect(updated.Status.Phase).To(Equal(workspacev1alpha1.SnapshotPhaseFailed))
+		Expect(updated.Status.Message).To(Equal("driver failure"))
+	})
+})
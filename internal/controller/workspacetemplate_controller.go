@@ -8,8 +8,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -28,11 +31,27 @@ const (
 	templateFinalizerName = "workspace.jupyter.org/template-protection"
 )
 
+// Counting the workspaces that block the deletion of a template
+const (
+	// templateUsagePageSize is the number of workspaces listed per page
+	templateUsagePageSize = 500
+	// templateUsageSampleSize is the number of workspace names reported in status.inUseBy
+	templateUsageSampleSize = 10
+	// templateUsageRefreshInterval is the minimum interval between two counts while deletion is blocked;
+	// in between, workspace events only trigger an existence check against the informer cache
+	templateUsageRefreshInterval = 30 * time.Second
+)
+
 // WorkspaceTemplateReconciler reconciles a WorkspaceTemplate object
 type WorkspaceTemplateReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	recorder record.EventRecorder
+	// apiReader reads from the API server, to paginate the workspaces blocking a template deletion.
+	// Falls back to the client when nil.
+	apiReader client.Reader
+	// now returns the current time, replaced in tests
+	now func() time.Time
 }
 
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacetemplates/status,verbs=get;update;patch
@@ -77,6 +96,11 @@ func (r *WorkspaceTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Move the workspaces following the default image to its current value
+	if err := r.reconcileImageUpdates(ctx, template); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Handle spec changes to track generation updates
 	shouldUpdateStatus, newGeneration := r.handleSpecChanges(ctx, template)
 
@@ -217,35 +241,58 @@ func (r *WorkspaceTemplateReconciler) handleDeletion(ctx context.Context, templa
 		return ctrl.Result{}, nil
 	}
 
-	// Check if any workspaces are using this template
-	// Reads from controller-runtime's informer cache (not direct API calls)
-	hasWorkspaces, err := workspace.HasActiveWorkspacesWithTemplate(ctx, r.Client, template.Name, template.Namespace)
+	// While a recent count of the blocking workspaces is reported, only check that one still exists.
+	// Reads from controller-runtime's informer cache (not direct API calls), so that the deletion of
+	// thousands of workspaces does not list all of them on every event.
+	if sinceLastCount, ok := r.sinceLastTemplateUsageCount(template); ok && sinceLastCount < templateUsageRefreshInterval {
+		hasWorkspaces, err := workspace.HasActiveWorkspacesWithTemplate(ctx, r.Client, template.Name, template.Namespace)
+		if err != nil {
+			logger.Error(err, "Failed to list workspaces using template")
+			return ctrl.Result{}, err
+		}
+		if hasWorkspaces {
+			logger.V(1).Info("Template is still in use, blocking deletion",
+				"templateName", template.Name,
+				"templateNamespace", template.Namespace)
+			return ctrl.Result{RequeueAfter: templateUsageRefreshInterval - sinceLastCount}, nil
+		}
+	}
+
+	// Count the workspaces using this template, page by page from the API server.
+	// The count is computed once per reconciliation, for both the finalizer and the status.
+	usage, err := workspace.SummarizeActiveWorkspacesByTemplate(ctx, r.templateUsageReader(),
+		template.Name, template.Namespace, templateUsagePageSize, templateUsageSampleSize)
 	if err != nil {
 		logger.Error(err, "Failed to list workspaces using template")
 		return ctrl.Result{}, err
 	}
 
-	if hasWorkspaces {
+	if usage.Count > 0 {
 		logger.Info("Template is in use, blocking deletion",
 			"templateName", template.Name,
-			"templateNamespace", template.Namespace)
-	} else {
-		logger.Info("No workspaces using template",
-			"templateName", template.Name)
-	}
+			"templateNamespace", template.Namespace,
+			"workspaces", usage.Count)
 
-	if hasWorkspaces {
-		msg := "Cannot delete template: in use by workspace(s)"
+		msg := fmt.Sprintf("Cannot delete template: in use by %d workspace(s), including %s",
+			usage.Count, strings.Join(usage.Sample, ", "))
 		if r.recorder != nil {
 			r.recorder.Event(template, "Warning", "TemplateInUse", msg)
 		}
 
+		if err := r.updateStatusInUseBy(ctx, template, usage); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		// Don't remove finalizer - block deletion
 		// Return nil (not error) - we successfully determined template is in use
-		// Template will be reconciled again when workspace changes (via watch)
-		return ctrl.Result{}, nil
+		// Template will be reconciled again when workspace changes (via watch), and the count
+		// refreshed once the refresh interval elapsed
+		return ctrl.Result{RequeueAfter: templateUsageRefreshInterval}, nil
 	}
 
+	logger.Info("No workspaces using template",
+		"templateName", template.Name)
+
 	// No workspaces using template - safe to delete
 	logger.Info("No workspaces using template, removing finalizer",
 		"templateName", template.Name)
@@ -261,6 +308,49 @@ func (r *WorkspaceTemplateReconciler) handleDeletion(ctx context.Context, templa
 	return ctrl.Result{}, nil
 }
 
+// templateUsageReader returns the reader used to paginate the workspaces using a template
+func (r *WorkspaceTemplateReconciler) templateUsageReader() client.Reader {
+	if r.apiReader != nil {
+		return r.apiReader
+	}
+	return r.Client
+}
+
+// sinceLastTemplateUsageCount returns the time elapsed since status.inUseBy was last counted,
+// and false when the template does not report blocking workspaces
+func (r *WorkspaceTemplateReconciler) sinceLastTemplateUsageCount(template *workspacev1alpha1.WorkspaceTemplate) (time.Duration, bool) {
+	inUseBy := template.Status.InUseBy
+	if inUseBy == nil || inUseBy.Count == 0 || inUseBy.LastCheckedTime.IsZero() {
+		return 0, false
+	}
+	return r.currentTime().Sub(inUseBy.LastCheckedTime.Time), true
+}
+
+func (r *WorkspaceTemplateReconciler) currentTime() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// updateStatusInUseBy reports the workspaces blocking the deletion of the template in status.inUseBy
+func (r *WorkspaceTemplateReconciler) updateStatusInUseBy(
+	ctx context.Context,
+	template *workspacev1alpha1.WorkspaceTemplate,
+	usage *workspace.TemplateUsage,
+) error {
+	template.Status.InUseBy = &workspacev1alpha1.TemplateUsage{
+		Count:           int32(usage.Count),
+		Workspaces:      usage.Sample,
+		LastCheckedTime: metav1.NewTime(r.currentTime()),
+	}
+	if err := r.Status().Update(ctx, template); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update status.inUseBy", "templateName", template.Name)
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 // It configures watches for WorkspaceTemplate resources and triggers reconciliation
 // when Workspaces change to manage finalizers based on template usage.
@@ -338,9 +428,10 @@ func SetupWorkspaceTemplateController(mgr ctrl.Manager) error {
 	eventRecorder := mgr.GetEventRecorderFor("workspacetemplate-controller")
 
 	reconciler := &WorkspaceTemplateReconciler{
-		Client:   k8sClient,
-		Scheme:   scheme,
-		recorder: eventRecorder,
+		Client:    k8sClient,
+		Scheme:    scheme,
+		recorder:  eventRecorder,
+		apiReader: mgr.GetAPIReader(),
 	}
 
 	logger.Info("Calling SetupWithManager for WorkspaceTemplate controller")
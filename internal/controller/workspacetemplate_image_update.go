@@ -0,0 +1,66 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// reconcileImageUpdates moves the workspaces following the default image of the template to its
+// current default image, according to the update strategy of the template. A workspace follows the
+// default image while its image is the one recorded in its AnnotationTemplateImage annotation, so
+// that images set by users or by image rollouts are kept.
+// Under the OnStop strategy, running workspaces are updated once they stop, which reconciles the
+// template through its Workspace watch. Updates rejected by the workspace webhook are skipped.
+func (r *WorkspaceTemplateReconciler) reconcileImageUpdates(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	strategy := template.Spec.UpdateStrategy
+	if strategy != workspacev1alpha1.TemplateUpdateStrategyOnStop && strategy != workspacev1alpha1.TemplateUpdateStrategyImmediate {
+		return nil
+	}
+	logger := logf.FromContext(ctx)
+
+	workspaces, _, err := workspace.ListActiveWorkspacesByTemplate(ctx, r.Client, template.Name, template.Namespace, "", 0)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces using template: %w", err)
+	}
+
+	image := template.Spec.DefaultImage
+	for i := range workspaces {
+		ws := &workspaces[i]
+		followedImage, follows := ws.Annotations[AnnotationTemplateImage]
+		if !follows || followedImage != ws.Spec.Image || ws.Spec.Image == image {
+			continue
+		}
+		if strategy == workspacev1alpha1.TemplateUpdateStrategyOnStop && isRunningWorkspace(ws) {
+			continue
+		}
+
+		patch := client.MergeFrom(ws.DeepCopy())
+		ws.Spec.Image = image
+		ws.Annotations[AnnotationTemplateImage] = image
+		if err := r.Patch(ctx, ws, patch); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if errors.IsForbidden(err) || errors.IsInvalid(err) || errors.IsBadRequest(err) {
+				logger.Error(err, "Workspace image update was rejected", "workspace", ws.Name, "namespace", ws.Namespace)
+				continue
+			}
+			return fmt.Errorf("failed to update workspace %s/%s image: %w", ws.Namespace, ws.Name, err)
+		}
+		logger.Info("Updated workspace to the default image of its template",
+			"workspace", ws.Name, "namespace", ws.Namespace, "image", image, "updateStrategy", strategy)
+	}
+	return nil
+}
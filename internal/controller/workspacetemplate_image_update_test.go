@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	templateOldImage = "jupyter/base-notebook:2025.1"
+	templateNewImage = "jupyter/base-notebook:2025.2"
+)
+
+func newImageUpdateTemplate(strategy workspacev1alpha1.TemplateUpdateStrategy) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-template", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:    "Shared",
+			DefaultImage:   templateNewImage,
+			UpdateStrategy: strategy,
+		},
+	}
+}
+
+func newImageUpdateWorkspace(name, image, desiredStatus string, followsTemplate bool) *workspacev1alpha1.Workspace {
+	ws := newTemplateWorkspace(name)
+	ws.Spec.Image = image
+	ws.Spec.DesiredStatus = desiredStatus
+	if followsTemplate {
+		ws.Annotations = map[string]string{AnnotationTemplateImage: templateOldImage}
+	}
+	return ws
+}
+
+func getImageUpdateWorkspace(t *testing.T, reconciler *WorkspaceTemplateReconciler, name string) *workspacev1alpha1.Workspace {
+	ws := &workspacev1alpha1.Workspace{}
+	require.NoError(t, reconciler.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ws))
+	return ws
+}
+
+func TestTemplateImageUpdate_Immediate(t *testing.T) {
+	reconciler, _ := newTestTemplateReconciler(t, time.Now(),
+		newImageUpdateWorkspace("running", templateOldImage, DesiredStateRunning, true),
+		newImageUpdateWorkspace("custom", "ghcr.io/team/custom:1", DesiredStateRunning, true),
+		newImageUpdateWorkspace("explicit", templateOldImage, DesiredStateRunning, false))
+
+	require.NoError(t, reconciler.reconcileImageUpdates(context.Background(),
+		newImageUpdateTemplate(workspacev1alpha1.TemplateUpdateStrategyImmediate)))
+
+	running := getImageUpdateWorkspace(t, reconciler, "running")
+	assert.Equal(t, templateNewImage, running.Spec.Image)
+	assert.Equal(t, templateNewImage, running.Annotations[AnnotationTemplateImage])
+	assert.Equal(t, "ghcr.io/team/custom:1", getImageUpdateWorkspace(t, reconciler, "custom").Spec.Image)
+	assert.Equal(t, templateOldImage, getImageUpdateWorkspace(t, reconciler, "explicit").Spec.Image)
+}
+
+func TestTemplateImageUpdate_OnStopSkipsRunningWorkspaces(t *testing.T) {
+	reconciler, _ := newTestTemplateReconciler(t, time.Now(),
+		newImageUpdateWorkspace("running", templateOldImage, DesiredStateRunning, true),
+		newImageUpdateWorkspace("stopped", templateOldImage, DesiredStateStopped, true))
+
+	require.NoError(t, reconciler.reconcileImageUpdates(context.Background(),
+		newImageUpdateTemplate(workspacev1alpha1.TemplateUpdateStrategyOnStop)))
+
+	assert.Equal(t, templateOldImage, getImageUpdateWorkspace(t, reconciler, "running").Spec.Image)
+	assert.Equal(t, templateNewImage, getImageUpdateWorkspace(t, reconciler, "stopped").Spec.Image)
+}
+
+func TestTemplateImageUpdate_NeverKeepsImages(t *testing.T) {
+	reconciler, _ := newTestTemplateReconciler(t, time.Now(),
+		newImageUpdateWorkspace("stopped", templateOldImage, DesiredStateStopped, true))
+
+	require.NoError(t, reconciler.reconcileImageUpdates(context.Background(), newImageUpdateTemplate("")))
+
+	assert.Equal(t, templateOldImage, getImageUpdateWorkspace(t, reconciler, "stopped").Spec.Image)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newIndexedClientBuilder returns a fake client builder with the field indexes of the manager cache
+func newIndexedClientBuilder(scheme *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range workspace.FieldIndexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	return builder
+}
+
+func newDeletedTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	now := metav1.Now()
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "shared-template",
+			Namespace:         "default",
+			Finalizers:        []string{templateFinalizerName},
+			DeletionTimestamp: &now,
+		},
+	}
+}
+
+func newTemplateWorkspace(name string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				workspace.LabelWorkspaceTemplate:          "shared-template",
+				workspace.LabelWorkspaceTemplateNamespace: "default",
+			},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			TemplateRef: &workspacev1alpha1.TemplateRef{Name: "shared-template"},
+		},
+	}
+}
+
+func newTestTemplateReconciler(t *testing.T, now time.Time, objects ...client.Object) (*WorkspaceTemplateReconciler, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	k8sClient := newIndexedClientBuilder(scheme).WithObjects(objects...).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceTemplate{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	return &WorkspaceTemplateReconciler{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		recorder: recorder,
+		now:      func() time.Time { return now },
+	}, recorder
+}
+
+func TestTemplateDeletion_ReportsBlockingWorkspaces(t *testing.T) {
+	now := time.Now()
+	objects := []client.Object{newDeletedTemplate()}
+	for i := range 12 {
+		objects = append(objects, newTemplateWorkspace(fmt.Sprintf("ws-%02d", i)))
+	}
+	reconciler, recorder := newTestTemplateReconciler(t, now, objects...)
+	key := types.NamespacedName{Name: "shared-template", Namespace: "default"}
+
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, templateUsageRefreshInterval, result.RequeueAfter)
+
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, reconciler.Get(context.Background(), key, template))
+	assert.Contains(t, template.Finalizers, templateFinalizerName)
+	require.NotNil(t, template.Status.InUseBy)
+	assert.Equal(t, int32(12), template.Status.InUseBy.Count)
+	assert.Len(t, template.Status.InUseBy.Workspaces, templateUsageSampleSize)
+	assert.Contains(t, template.Status.InUseBy.Workspaces, "default/ws-00")
+	assert.Contains(t, <-recorder.Events, "in use by 12 workspace(s)")
+}
+
+func TestTemplateDeletion_SkipsRecountWithinRefreshInterval(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	template := newDeletedTemplate()
+	template.Status.InUseBy = &workspacev1alpha1.TemplateUsage{
+		Count:           2,
+		LastCheckedTime: metav1.NewTime(now.Add(-10 * time.Second)),
+	}
+	reconciler, recorder := newTestTemplateReconciler(t, now, template, newTemplateWorkspace("ws-1"))
+	key := types.NamespacedName{Name: "shared-template", Namespace: "default"}
+
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, result.RequeueAfter)
+	assert.Empty(t, recorder.Events)
+
+	updated := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, reconciler.Get(context.Background(), key, updated))
+	assert.Equal(t, int32(2), updated.Status.InUseBy.Count, "Should keep the previous count")
+}
+
+func TestTemplateDeletion_RemovesFinalizerOnceUnused(t *testing.T) {
+	now := time.Now()
+	template := newDeletedTemplate()
+	template.Status.InUseBy = &workspacev1alpha1.TemplateUsage{
+		Count:           1,
+		LastCheckedTime: metav1.NewTime(now.Add(-10 * time.Second)),
+	}
+	reconciler, _ := newTestTemplateReconciler(t, now, template)
+	key := types.NamespacedName{Name: "shared-template", Namespace: "default"}
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	err = reconciler.Get(context.Background(), key, &workspacev1alpha1.WorkspaceTemplate{})
+	assert.True(t, apierrors.IsNotFound(err), "Template should be deleted once its finalizer is removed")
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package errs provides typed errors that separate the sanitized message shown to users, in
+// workspace conditions, events and admission responses, from the full detail of their cause,
+// which only appears in the logs.
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Kind categorizes an error
+type Kind string
+
+// Kinds of errors
+const (
+	// KindNotFound reports a missing resource
+	KindNotFound Kind = "NotFound"
+	// KindConflict reports a resource modified concurrently, or owned by another object
+	KindConflict Kind = "Conflict"
+	// KindTemplateViolation reports a workspace that violates the constraints of its template
+	KindTemplateViolation Kind = "TemplateViolation"
+	// KindRender reports a template of an AccessStrategy that cannot be rendered
+	KindRender Kind = "RenderError"
+	// KindInternal reports any other failure
+	KindInternal Kind = "Internal"
+)
+
+// internalMessage is the user-facing message of an error that carries no typed error
+const internalMessage = "internal error, see the controller logs for details"
+
+// Error is an error with a user-facing message, wrapping the cause whose detail is only logged
+type Error struct {
+	// Kind categorizes the error
+	Kind Kind
+	// Message is the sanitized, user-facing description of the error
+	Message string
+	// Err is the cause of the error, nil if none
+	Err error
+}
+
+// Error returns the message followed by the full detail of the cause, for the logs
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind Kind, err error, format string, args []any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// NotFound returns an error reporting a missing resource, wrapping err when not nil
+func NotFound(err error, format string, args ...any) error {
+	return newError(KindNotFound, err, format, args)
+}
+
+// Conflict returns an error reporting a conflicting resource, wrapping err when not nil
+func Conflict(err error, format string, args ...any) error {
+	return newError(KindConflict, err, format, args)
+}
+
+// TemplateViolation returns an error reporting a violation of the constraints of a template,
+// wrapping err when not nil
+func TemplateViolation(err error, format string, args ...any) error {
+	return newError(KindTemplateViolation, err, format, args)
+}
+
+// Render returns an error reporting a template that cannot be rendered, wrapping err when not nil
+func Render(err error, format string, args ...any) error {
+	return newError(KindRender, err, format, args)
+}
+
+// Internal returns an error describing the failed operation to users, wrapping err when not nil
+func Internal(err error, format string, args ...any) error {
+	return newError(KindInternal, err, format, args)
+}
+
+// KindOf returns the kind of the outermost typed error of err, or of the Kubernetes API error it
+// wraps, and KindInternal otherwise
+func KindOf(err error) Kind {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case apierrors.IsNotFound(err):
+		return KindNotFound
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return KindConflict
+	}
+	return KindInternal
+}
+
+// Is returns true when err wraps a typed error of the given kind, or a Kubernetes API error of it
+func Is(err error, kind Kind) bool {
+	for err != nil {
+		var e *Error
+		if !errors.As(err, &e) {
+			return KindOf(err) == kind && kind != KindInternal
+		}
+		if e.Kind == kind {
+			return true
+		}
+		err = e.Err
+	}
+	return false
+}
+
+// UserMessage returns the message of err that is safe to show to users: the messages of the typed
+// errors it wraps, outermost first, followed by a generic description of the Kubernetes API error
+// at its root. The detail of any other cause is left out.
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var parts []string
+	for err != nil {
+		var e *Error
+		if !errors.As(err, &e) {
+			if msg := apiErrorMessage(err); msg != "" {
+				parts = append(parts, msg)
+			}
+			break
+		}
+		parts = append(parts, e.Message)
+		err = e.Err
+	}
+	if len(parts) == 0 {
+		return internalMessage
+	}
+	return strings.Join(parts, ": ")
+}
+
+// ForUser returns an error of the kind of err whose message is the user-facing message of err,
+// for the responses of admission webhooks
+func ForUser(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: KindOf(err), Message: UserMessage(err)}
+}
+
+// apiErrorMessage returns a generic description of the Kubernetes API error err wraps, which
+// may otherwise name service accounts, internal resources or webhook endpoints
+func apiErrorMessage(err error) string {
+	switch {
+	case apierrors.IsNotFound(err):
+		return "not found"
+	case apierrors.IsAlreadyExists(err):
+		return "already exists"
+	case apierrors.IsConflict(err):
+		return "modified concurrently, retrying"
+	case apierrors.IsForbidden(err):
+		return "forbidden"
+	case apierrors.IsInvalid(err):
+		return "rejected as invalid"
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err),
+		apierrors.IsTooManyRequests(err), apierrors.IsServiceUnavailable(err):
+		return "the API server is unavailable, retrying"
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var deploymentsResource = schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+func TestError_KeepsFullDetailForLogs(t *testing.T) {
+	cause := errors.New("dial tcp 10.0.0.1:443: connection refused")
+	err := Internal(cause, "failed to ensure deployment exists")
+
+	assert.Equal(t, "failed to ensure deployment exists: dial tcp 10.0.0.1:443: connection refused", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "template x not found", NotFound(nil, "template %s not found", "x").Error())
+}
+
+func TestUserMessage(t *testing.T) {
+	forbidden := apierrors.NewForbidden(deploymentsResource, "ws",
+		errors.New(`User "system:serviceaccount:jupyter-k8s-system:controller" cannot create resource`))
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: "",
+		},
+		{
+			name:     "untyped error",
+			err:      errors.New("secret detail"),
+			expected: internalMessage,
+		},
+		{
+			name:     "typed error hides untyped cause",
+			err:      Internal(errors.New("secret detail"), "failed to ensure deployment exists"),
+			expected: "failed to ensure deployment exists",
+		},
+		{
+			name:     "typed errors are joined outermost first",
+			err:      Internal(fmt.Errorf("wrapped: %w", Render(errors.New("detail"), "failed to execute env template for BASE_URL")), "failed to ensure deployment exists"),
+			expected: "failed to ensure deployment exists: failed to execute env template for BASE_URL",
+		},
+		{
+			name:     "API error is described generically",
+			err:      Internal(fmt.Errorf("failed to create deployment: %w", forbidden), "failed to ensure deployment exists"),
+			expected: "failed to ensure deployment exists: forbidden",
+		},
+		{
+			name:     "conflict",
+			err:      Internal(apierrors.NewConflict(deploymentsResource, "ws", errors.New("object was modified")), "failed to ensure deployment exists"),
+			expected: "failed to ensure deployment exists: modified concurrently, retrying",
+		},
+		{
+			name:     "untyped API error",
+			err:      apierrors.NewNotFound(deploymentsResource, "ws"),
+			expected: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, UserMessage(tt.err))
+		})
+	}
+}
+
+func TestKindOfAndIs(t *testing.T) {
+	notFound := NotFound(apierrors.NewNotFound(deploymentsResource, "ws"), "template x not found")
+	wrapped := Internal(notFound, "failed to apply template defaults")
+
+	assert.Equal(t, KindInternal, KindOf(wrapped))
+	assert.True(t, Is(wrapped, KindNotFound))
+	assert.False(t, Is(wrapped, KindRender))
+
+	assert.Equal(t, KindConflict, KindOf(apierrors.NewConflict(deploymentsResource, "ws", errors.New("modified"))))
+	assert.True(t, Is(fmt.Errorf("wrapped: %w", apierrors.NewNotFound(deploymentsResource, "ws")), KindNotFound))
+	assert.Equal(t, KindInternal, KindOf(errors.New("other")))
+	assert.False(t, Is(errors.New("other"), KindInternal))
+}
+
+func TestForUser(t *testing.T) {
+	assert.NoError(t, ForUser(nil))
+
+	err := ForUser(NotFound(apierrors.NewNotFound(deploymentsResource, "ws"), "failed to get template x"))
+	assert.Equal(t, "failed to get template x: not found", err.Error())
+	assert.True(t, Is(err, KindNotFound))
+	assert.NoError(t, errors.Unwrap(err))
+}
@@ -14,7 +14,6 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-	"text/template"
 
 	"github.com/go-logr/logr"
 	pluginapi "github.com/jupyter-infra/jupyter-k8s-plugin/api"
@@ -23,6 +22,7 @@ import (
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/jwt"
 	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -89,6 +89,14 @@ func (s *ExtensionServer) validateConnection(ws *workspacev1alpha1.Workspace, lo
 		return nil, nil, http.StatusBadRequest, fmt.Errorf("workspace is not available. Check workspace status for details")
 	}
 
+	// Check access to the workspace is not disabled
+	if !workspace.IsAccessEnabled(ws) {
+		logger.Info("Connection rejected: workspace access disabled",
+			"workspaceName", ws.Name,
+			"namespace", ws.Namespace)
+		return nil, nil, http.StatusForbidden, fmt.Errorf("access to the workspace is disabled")
+	}
+
 	// Resolve dynamic values in connection context (e.g. extensionapi::PodUid())
 	resolvedContext, err := ResolveConnectionContext(accessStrategy.Spec.CreateConnectionContext, s.k8sClient, ws.Name)
 	if err != nil {
@@ -368,22 +376,17 @@ func hasWebUIEnabled(accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy)
 
 // renderBearerAuthURL renders the BearerAuthURLTemplate with workspace variables
 func (s *ExtensionServer) renderBearerAuthURL(templateStr string, ws *workspacev1alpha1.Workspace, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (string, error) {
-	tmpl, err := template.New("bearerauth").Funcs(template.FuncMap{
-		"b32encode": workspace.EncodeNamespaceB32,
-	}).Parse(templateStr)
+	tmpl, err := accesstemplate.Parse(accesstemplate.FieldBearerAuthURLTemplate, templateStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	var result strings.Builder
-	data := map[string]interface{}{
-		"Workspace":      ws,
-		"AccessStrategy": accessStrategy,
-	}
+	data := accesstemplate.NewData(ws, accessStrategy, nil)
 
-	if err := tmpl.Execute(&result, data); err != nil {
+	result, err := accesstemplate.Execute(accesstemplate.FieldBearerAuthURLTemplate, tmpl, data)
+	if err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	return result.String(), nil
+	return result, nil
 }
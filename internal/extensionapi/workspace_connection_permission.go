@@ -9,6 +9,8 @@ package extensionapi
 import (
 	rlog "github.com/go-logr/logr"
 	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
 // PermissionCheckResult contains the result of the permission check
@@ -22,6 +24,7 @@ type PermissionCheckResult struct {
 // by performing the following checks in sequence:
 // 1. RBAC check - does the user have permission to create workspace/connection?
 // 2. Workspace check - is the workspace public or is the user the owner?
+// 3. Access check - is access to the workspace enabled?
 func (s *ExtensionServer) CheckWorkspaceConnectionPermission(
 	namespace string,
 	workspaceName string,
@@ -49,7 +52,7 @@ func (s *ExtensionServer) CheckWorkspaceConnectionPermission(
 	}
 
 	// Step 2: Check workspace access
-	_, workspaceResult, err := s.CheckWorkspaceAccess(namespace, workspaceName, username, logger)
+	ws, workspaceResult, err := s.CheckWorkspaceAccess(namespace, workspaceName, username, logger)
 	if err != nil {
 		logger.Error(err, "Workspace access check failed with error")
 		return nil, err
@@ -76,6 +79,16 @@ func (s *ExtensionServer) CheckWorkspaceConnectionPermission(
 		}, nil
 	}
 
+	// Step 3: Check that access to the workspace is not disabled
+	if ws != nil && !workspace.IsAccessEnabled(ws) {
+		logger.Info("Workspace access is disabled")
+		return &PermissionCheckResult{
+			Allowed:  false,
+			NotFound: false,
+			Reason:   "Access to the Workspace is disabled",
+		}, nil
+	}
+
 	// All checks passed, grant access
 	var reason string
 	if workspaceResult.AccessType == AccessTypePublic {
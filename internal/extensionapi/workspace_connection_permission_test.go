@@ -170,6 +170,34 @@ var _ = Describe("CheckWorkspaceConnectionPermission", func() {
 			Expect(response.Reason).To(ContainSubstring("public"))
 		})
 
+		It("Should return allowed=false, notFound=false, reason.include(disabled) when Get(Workspace) indicate access is disabled", func() {
+			mockSarClient.SetupAllowed("Permitted by RBAC")
+
+			// Create a public workspace with access disabled
+			accessEnabled := false
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testWorkspaceName,
+					Namespace: testNamespace,
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					AccessType:    AccessTypePublic,
+					AccessEnabled: &accessEnabled,
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), workspace)).To(Succeed())
+
+			response, err := server.CheckWorkspaceConnectionPermission(
+				testNamespace, testWorkspaceName, testUsername, testGroups, testUID, nil, &logger,
+			)
+
+			Expect(response).NotTo(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.NotFound).To(BeFalse())
+			Expect(response.Reason).To(ContainSubstring("disabled"))
+		})
+
 		It("Should return allowed=false, notFound=true, reason.include(not found) when Get(Workspace) indicate notFound", func() {
 			mockSarClient.SetupAllowed("Permitted by RBAC")
 
@@ -0,0 +1,57 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package hubactivity provides the JupyterHub activity API server, which mimics the activity
+// reporting endpoint of JupyterHub so that jupyterhub-singleuser and hub-aware extensions running
+// in workspaces can report their activity to the idle shutdown of the operator.
+package hubactivity
+
+import "time"
+
+// Default values
+const (
+	DefaultServerPort      = 8091
+	DefaultReadTimeout     = 10 * time.Second
+	DefaultWriteTimeout    = 30 * time.Second
+	DefaultShutdownTimeout = 10 * time.Second
+
+	// DefaultMaxRequestBodyBytes bounds the body of an activity report
+	DefaultMaxRequestBodyBytes = 64 << 10
+)
+
+// HubActivityConfig contains the configuration for the JupyterHub activity API server
+type HubActivityConfig struct {
+	ServerPort int
+
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
+	ShutdownTimeout     time.Duration
+	MaxRequestBodyBytes int64
+}
+
+// ConfigOption is a function that modifies a HubActivityConfig
+type ConfigOption func(*HubActivityConfig)
+
+// WithServerPort sets the server port
+func WithServerPort(port int) ConfigOption {
+	return func(c *HubActivityConfig) {
+		c.ServerPort = port
+	}
+}
+
+// NewConfig creates a new HubActivityConfig with default values and applies the given options
+func NewConfig(opts ...ConfigOption) *HubActivityConfig {
+	config := &HubActivityConfig{
+		ServerPort:          DefaultServerPort,
+		ReadTimeout:         DefaultReadTimeout,
+		WriteTimeout:        DefaultWriteTimeout,
+		ShutdownTimeout:     DefaultShutdownTimeout,
+		MaxRequestBodyBytes: DefaultMaxRequestBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package hubactivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HubActivityServer serves the JupyterHub activity API to which workspaces report their activity
+type HubActivityServer struct {
+	config *HubActivityConfig
+	// apiReader reads workspaces and their tokens from the API server: the cache of a sharded
+	// manager only holds the workspaces of its shard, and the manager does not cache Secrets
+	apiReader  client.Reader
+	k8sClient  client.Client
+	logger     *logr.Logger
+	httpServer *http.Server
+}
+
+// NewHubActivityServer creates a new JupyterHub activity API server
+func NewHubActivityServer(
+	config *HubActivityConfig,
+	logger *logr.Logger,
+	k8sClient client.Client,
+	apiReader client.Reader,
+) *HubActivityServer {
+	server := &HubActivityServer{
+		config:    config,
+		logger:    logger,
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /namespaces/{namespace}/hub/api/users/{name}/activity", server.handleActivity)
+	mux.HandleFunc("/health", server.handleHealth)
+
+	server.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", config.ServerPort),
+		Handler:      mux,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	}
+	return server
+}
+
+// Start starts the JupyterHub activity API server and implements the controller-runtime's Runnable
+// interface. It blocks until the context is cancelled, then shuts the server down gracefully.
+func (s *HubActivityServer) Start(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting JupyterHub activity API server", "port", s.config.ServerPort)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("hub activity API server error: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down hub activity API server: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// This indicates this runnable doesn't need to be a leader to run
+func (s *HubActivityServer) NeedLeaderElection() bool {
+	return false
+}
+
+// SetupHubActivityServerWithManager sets up the JupyterHub activity API server and adds it to the manager
+func SetupHubActivityServerWithManager(mgr ctrl.Manager, config *HubActivityConfig) error {
+	if config == nil {
+		config = NewConfig()
+	}
+
+	logger := mgr.GetLogger().WithName("hub-activity")
+
+	server := NewHubActivityServer(config, &logger, mgr.GetClient(), mgr.GetAPIReader())
+	if err := mgr.Add(server); err != nil {
+		return fmt.Errorf("failed to add hub activity API server to manager: %w", err)
+	}
+	return nil
+}
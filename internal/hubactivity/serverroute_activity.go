@@ -0,0 +1,183 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package hubactivity
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// naiveTimestampLayout is the layout of timestamps reported without a time zone, which JupyterHub
+// reads as UTC
+const naiveTimestampLayout = "2006-01-02T15:04:05.999999999"
+
+// activityReport is the body of a JupyterHub activity report: the last activity of the user, and
+// of each of their servers
+type activityReport struct {
+	LastActivity string                          `json:"last_activity"`
+	Servers      map[string]serverActivityReport `json:"servers"`
+}
+
+// serverActivityReport is the activity report of a single server
+type serverActivityReport struct {
+	LastActivity string `json:"last_activity"`
+}
+
+// errForbidden is returned for requests that do not carry the token of the workspace
+var errForbidden = errors.New("forbidden")
+
+// handleActivity records the activity that a workspace reports in the JupyterHub format, as the
+// user named after it, in the hub last activity annotation of the workspace. The report must carry
+// the token of the hub activity token Secret of the workspace. Activity older than the recorded
+// one is ignored, and activity in the future is recorded as now.
+func (s *HubActivityServer) handleActivity(w http.ResponseWriter, r *http.Request) {
+	key := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+	logger := s.logger.WithValues("workspace", key.Name, "namespace", key.Namespace)
+
+	workspace, err := s.authenticate(r, key)
+	if err != nil {
+		if errors.Is(err, errForbidden) {
+			writeError(w, http.StatusForbidden, "invalid token for the workspace")
+			return
+		}
+		logger.Error(err, "Failed to authenticate activity report")
+		writeError(w, http.StatusInternalServerError, "failed to authenticate the activity report")
+		return
+	}
+
+	var report activityReport
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.config.MaxRequestBodyBytes)).Decode(&report); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid activity report: %v", err))
+		return
+	}
+	lastActivity, err := report.lastActivity()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if now := time.Now(); lastActivity.After(now) {
+		lastActivity = now
+	}
+
+	if recorded, ok := controller.HubLastActivity(workspace); !ok || lastActivity.After(recorded) {
+		patch := client.MergeFrom(workspace.DeepCopy())
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[controller.AnnotationHubLastActivity] = lastActivity.UTC().Format(time.RFC3339)
+		if err := s.k8sClient.Patch(r.Context(), workspace, patch); err != nil {
+			logger.Error(err, "Failed to record reported activity")
+			writeError(w, http.StatusInternalServerError, "failed to record the activity")
+			return
+		}
+		logger.V(1).Info("Recorded reported activity", "lastActivity", lastActivity)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticate returns the workspace of the request when the request carries its hub activity
+// token, and errForbidden otherwise. Unknown workspaces are forbidden, so that their existence
+// does not leak.
+func (s *HubActivityServer) authenticate(r *http.Request, key types.NamespacedName) (*workspacev1alpha1.Workspace, error) {
+	token := requestToken(r)
+	if token == "" {
+		return nil, errForbidden
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := s.apiReader.Get(r.Context(), key, workspace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errForbidden
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	if !controller.HubActivityEnabled(workspace) {
+		return nil, errForbidden
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: key.Namespace, Name: controller.GenerateHubActivityTokenSecretName(workspace)}
+	if err := s.apiReader.Get(r.Context(), secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errForbidden
+		}
+		return nil, fmt.Errorf("failed to get hub activity token: %w", err)
+	}
+	expected := secret.Data[controller.HubActivityTokenSecretKey]
+	if len(expected) == 0 || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return nil, errForbidden
+	}
+	return workspace, nil
+}
+
+// requestToken returns the token of the Authorization header, in the "token <token>" format of
+// JupyterHub or the "Bearer <token>" format
+func requestToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || (!strings.EqualFold(scheme, "token") && !strings.EqualFold(scheme, "bearer")) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// lastActivity returns the most recent activity of the report
+func (report *activityReport) lastActivity() (time.Time, error) {
+	values := []string{report.LastActivity}
+	for _, server := range report.Servers {
+		values = append(values, server.LastActivity)
+	}
+
+	var lastActivity time.Time
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		activity, err := parseActivityTimestamp(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if activity.After(lastActivity) {
+			lastActivity = activity
+		}
+	}
+	if lastActivity.IsZero() {
+		return time.Time{}, errors.New("the activity report has no last_activity")
+	}
+	return lastActivity, nil
+}
+
+// parseActivityTimestamp parses an ISO 8601 timestamp, read as UTC without a time zone
+func parseActivityTimestamp(value string) (time.Time, error) {
+	if activity, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return activity, nil
+	}
+	activity, err := time.Parse(naiveTimestampLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last_activity %q: expected an ISO 8601 timestamp", value)
+	}
+	return activity, nil
+}
+
+// writeError writes an error in the JSON format of the JupyterHub API
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": status, "message": message})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package hubactivity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+const (
+	testNamespace = "team-a"
+	testWorkspace = "notebook"
+	testToken     = "s3cr3t-token"
+)
+
+func newTestWorkspace(hubActivity bool, annotations map[string]string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspace, Namespace: testNamespace, Annotations: annotations},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			IdleShutdown: &workspacev1alpha1.IdleShutdownSpec{
+				Enabled:              true,
+				IdleTimeoutInMinutes: 30,
+				Detection:            workspacev1alpha1.IdleDetectionSpec{HubActivity: hubActivity},
+			},
+		},
+	}
+}
+
+func newTestServer(t *testing.T, workspace *workspacev1alpha1.Workspace) (*HubActivityServer, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: controller.GenerateHubActivityTokenSecretName(workspace), Namespace: testNamespace},
+		Data:       map[string][]byte{controller.HubActivityTokenSecretKey: []byte(testToken)},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, secret).Build()
+	logger := logr.Discard()
+	return NewHubActivityServer(NewConfig(), &logger, k8sClient, k8sClient), k8sClient
+}
+
+func postActivity(server *HubActivityServer, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func activityPath() string {
+	return controller.HubActivityAPIPath(testNamespace) + "/users/" + testWorkspace + "/activity"
+}
+
+func recordedActivity(t *testing.T, k8sClient client.Client) string {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testWorkspace}, workspace))
+	return workspace.Annotations[controller.AnnotationHubLastActivity]
+}
+
+func TestHandleActivity_RecordsMostRecentActivity(t *testing.T) {
+	server, k8sClient := newTestServer(t, newTestWorkspace(true, nil))
+
+	rec := postActivity(server, activityPath(), "token "+testToken, `{
+		"last_activity": "2026-03-01T09:00:00.000000Z",
+		"servers": {"": {"last_activity": "2026-03-01T09:30:12.345678Z"}}
+	}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2026-03-01T09:30:12Z", recordedActivity(t, k8sClient))
+}
+
+func TestHandleActivity_IgnoresOlderActivity(t *testing.T) {
+	server, k8sClient := newTestServer(t, newTestWorkspace(true, map[string]string{
+		controller.AnnotationHubLastActivity: "2026-03-01T10:00:00Z",
+	}))
+
+	rec := postActivity(server, activityPath(), "Bearer "+testToken, `{"last_activity": "2026-03-01T09:00:00"}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2026-03-01T10:00:00Z", recordedActivity(t, k8sClient))
+}
+
+func TestHandleActivity_ClampsFutureActivity(t *testing.T) {
+	server, k8sClient := newTestServer(t, newTestWorkspace(true, nil))
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	rec := postActivity(server, activityPath(), "token "+testToken, `{"last_activity": "`+future+`"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	recorded, err := time.Parse(time.RFC3339, recordedActivity(t, k8sClient))
+	require.NoError(t, err)
+	assert.False(t, recorded.After(time.Now()))
+}
+
+func TestHandleActivity_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		hubActivity   bool
+		path          string
+		authorization string
+		body          string
+		expected      int
+	}{
+		{"missing token", true, activityPath(), "", `{"last_activity": "2026-03-01T09:00:00Z"}`, http.StatusForbidden},
+		{"wrong token", true, activityPath(), "token other", `{"last_activity": "2026-03-01T09:00:00Z"}`, http.StatusForbidden},
+		{"unknown workspace", true, controller.HubActivityAPIPath(testNamespace) + "/users/other/activity", "token " + testToken,
+			`{"last_activity": "2026-03-01T09:00:00Z"}`, http.StatusForbidden},
+		{"hub activity disabled", false, activityPath(), "token " + testToken, `{"last_activity": "2026-03-01T09:00:00Z"}`, http.StatusForbidden},
+		{"no activity", true, activityPath(), "token " + testToken, `{"servers": {}}`, http.StatusBadRequest},
+		{"invalid timestamp", true, activityPath(), "token " + testToken, `{"last_activity": "yesterday"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, k8sClient := newTestServer(t, newTestWorkspace(tt.hubActivity, nil))
+
+			rec := postActivity(server, tt.path, tt.authorization, tt.body)
+
+			assert.Equal(t, tt.expected, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+			assert.Empty(t, recordedActivity(t, k8sClient))
+		})
+	}
+}
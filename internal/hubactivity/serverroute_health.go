@@ -0,0 +1,17 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package hubactivity
+
+import "net/http"
+
+// handleHealth responds to health check requests
+func (s *HubActivityServer) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(`{"status":"ok"}`)); err != nil {
+		s.logger.Error(err, "Failed to write health response")
+	}
+}
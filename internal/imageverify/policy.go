@@ -0,0 +1,205 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultCacheTTL is how long the result of verifying an image is reused
+const DefaultCacheTTL = 10 * time.Minute
+
+// Policy is the image signature policy, read from a YAML file. An image is admitted when one of
+// its cosign signatures verifies against one of the public keys or keyless identities.
+type Policy struct {
+	// PublicKeys are PEM-encoded public keys (ECDSA, RSA or Ed25519) of key-based signatures
+	PublicKeys []string `json:"publicKeys,omitempty"`
+
+	// Keyless configures keyless signatures, whose short-lived certificates are issued by Fulcio
+	// to an OIDC identity and whose signatures are recorded in the Rekor transparency log
+	Keyless *KeylessPolicy `json:"keyless,omitempty"`
+
+	// CacheTTL is how long the result of verifying an image is reused, DefaultCacheTTL if unset
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+}
+
+// KeylessPolicy configures the verification of keyless signatures
+type KeylessPolicy struct {
+	// FulcioRoots is the PEM bundle of the root and intermediate certificates of Fulcio
+	FulcioRoots string `json:"fulcioRoots"`
+
+	// RekorPublicKeys are the PEM-encoded public keys of the Rekor transparency log
+	RekorPublicKeys []string `json:"rekorPublicKeys"`
+
+	// Identities are the signers whose keyless signatures are accepted
+	Identities []Identity `json:"identities"`
+}
+
+// Identity is a keyless signer: the OIDC issuer of its certificate and its subject, the email
+// or URI of the certificate
+type Identity struct {
+	Issuer        string `json:"issuer"`
+	Subject       string `json:"subject,omitempty"`
+	SubjectRegExp string `json:"subjectRegExp,omitempty"`
+}
+
+// compiledPolicy is the parsed form of a Policy
+type compiledPolicy struct {
+	publicKeys      []crypto.PublicKey
+	fulcioRoots     *x509.CertPool
+	fulcioChain     *x509.CertPool
+	rekorPublicKeys []crypto.PublicKey
+	identities      []compiledIdentity
+	cacheTTL        time.Duration
+}
+
+type compiledIdentity struct {
+	issuer        string
+	subject       string
+	subjectRegExp *regexp.Regexp
+}
+
+// LoadPolicy reads and parses the policy file at path
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image signature policy: %w", err)
+	}
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid image signature policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// compile parses the keys, certificates and identities of the policy
+func (p *Policy) compile() (*compiledPolicy, error) {
+	compiled := &compiledPolicy{cacheTTL: DefaultCacheTTL}
+	if p.CacheTTL != nil {
+		if p.CacheTTL.Duration < 0 {
+			return nil, errors.New("cacheTTL must not be negative")
+		}
+		compiled.cacheTTL = p.CacheTTL.Duration
+	}
+
+	for i, key := range p.PublicKeys {
+		publicKey, err := parsePublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("publicKeys[%d]: %w", i, err)
+		}
+		compiled.publicKeys = append(compiled.publicKeys, publicKey)
+	}
+
+	if p.Keyless != nil {
+		if err := p.Keyless.compile(compiled); err != nil {
+			return nil, fmt.Errorf("keyless: %w", err)
+		}
+	}
+
+	if len(compiled.publicKeys) == 0 && len(compiled.identities) == 0 {
+		return nil, errors.New("image signature policy must set publicKeys or keyless identities")
+	}
+	return compiled, nil
+}
+
+func (k *KeylessPolicy) compile(compiled *compiledPolicy) error {
+	if len(k.Identities) == 0 {
+		return errors.New("identities must not be empty")
+	}
+
+	certificates, err := parseCertificates(k.FulcioRoots)
+	if err != nil {
+		return fmt.Errorf("fulcioRoots: %w", err)
+	}
+	compiled.fulcioRoots = x509.NewCertPool()
+	compiled.fulcioChain = x509.NewCertPool()
+	for _, certificate := range certificates {
+		// Self-signed certificates are roots; the others are intermediates chaining to them
+		if certificate.CheckSignatureFrom(certificate) == nil {
+			compiled.fulcioRoots.AddCert(certificate)
+		} else {
+			compiled.fulcioChain.AddCert(certificate)
+		}
+	}
+
+	if len(k.RekorPublicKeys) == 0 {
+		return errors.New("rekorPublicKeys must not be empty")
+	}
+	for i, key := range k.RekorPublicKeys {
+		publicKey, err := parsePublicKey(key)
+		if err != nil {
+			return fmt.Errorf("rekorPublicKeys[%d]: %w", i, err)
+		}
+		compiled.rekorPublicKeys = append(compiled.rekorPublicKeys, publicKey)
+	}
+
+	for i, identity := range k.Identities {
+		if identity.Issuer == "" {
+			return fmt.Errorf("identities[%d]: issuer must be set", i)
+		}
+		if (identity.Subject == "") == (identity.SubjectRegExp == "") {
+			return fmt.Errorf("identities[%d]: exactly one of subject and subjectRegExp must be set", i)
+		}
+		compiledIdentity := compiledIdentity{issuer: identity.Issuer, subject: identity.Subject}
+		if identity.SubjectRegExp != "" {
+			re, err := regexp.Compile(identity.SubjectRegExp)
+			if err != nil {
+				return fmt.Errorf("identities[%d]: invalid subjectRegExp: %w", i, err)
+			}
+			compiledIdentity.subjectRegExp = re
+		}
+		compiled.identities = append(compiled.identities, compiledIdentity)
+	}
+	return nil
+}
+
+// parsePublicKey parses a PEM-encoded PKIX public key
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// parseCertificates parses a PEM bundle of certificates
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return certificates, nil
+}
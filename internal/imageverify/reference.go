@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// dockerHubRegistry is the registry of images whose reference names none
+	dockerHubRegistry = "docker.io"
+	// dockerHubAPIHost serves the registry API of docker.io
+	dockerHubAPIHost = "registry-1.docker.io"
+	defaultTag       = "latest"
+)
+
+// reference is a parsed container image reference
+type reference struct {
+	// registry is the registry host, e.g. ghcr.io or docker.io
+	registry string
+	// repository is the repository path in the registry, e.g. org/notebook or library/python
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseReference parses an image reference such as ghcr.io/org/notebook:1.0,
+// jupyter/base-notebook or python@sha256:<digest>
+func parseReference(image string) (reference, error) {
+	ref := reference{}
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		name, ref.digest = name[:at], name[at+1:]
+		if !strings.HasPrefix(ref.digest, "sha256:") || len(ref.digest) != len("sha256:")+64 {
+			return reference{}, fmt.Errorf("invalid digest in image %q", image)
+		}
+	}
+
+	// A tag follows the last colon after the last slash; earlier colons belong to a registry port
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, ref.tag = name[:colon], name[colon+1:]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = defaultTag
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry, ref.repository = first, rest
+	} else {
+		ref.registry, ref.repository = dockerHubRegistry, name
+	}
+	if ref.registry == dockerHubRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+
+	if ref.repository == "" || strings.HasSuffix(ref.repository, "/") || ref.repository != strings.ToLower(ref.repository) {
+		return reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	return ref, nil
+}
+
+// apiHost returns the host serving the registry API
+func (r reference) apiHost() string {
+	if r.registry == dockerHubRegistry {
+		return dockerHubAPIHost
+	}
+	return r.registry
+}
+
+// manifestRef returns the digest of the reference, or its tag when it has none
+func (r reference) manifestRef() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
+
+// String returns the canonical form of the reference
+func (r reference) String() string {
+	s := r.registry + "/" + r.repository
+	if r.tag != "" {
+		s += ":" + r.tag
+	}
+	if r.digest != "" {
+		s += "@" + r.digest
+	}
+	return s
+}
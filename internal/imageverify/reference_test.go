@@ -0,0 +1,65 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		image    string
+		expected reference
+	}{
+		{image: "python", expected: reference{registry: "docker.io", repository: "library/python", tag: "latest"}},
+		{image: "jupyter/base-notebook:2025.01", expected: reference{registry: "docker.io", repository: "jupyter/base-notebook", tag: "2025.01"}},
+		{image: "ghcr.io/org/team/notebook:1.0", expected: reference{registry: "ghcr.io", repository: "org/team/notebook", tag: "1.0"}},
+		{image: "registry.local:5000/notebook", expected: reference{registry: "registry.local:5000", repository: "notebook", tag: "latest"}},
+		{image: "localhost/notebook:dev", expected: reference{registry: "localhost", repository: "notebook", tag: "dev"}},
+		{image: "ghcr.io/org/notebook@" + digest, expected: reference{registry: "ghcr.io", repository: "org/notebook", digest: digest}},
+		{image: "ghcr.io/org/notebook:1.0@" + digest, expected: reference{registry: "ghcr.io", repository: "org/notebook", tag: "1.0", digest: digest}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := parseReference(tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		})
+	}
+}
+
+func TestParseReference_Invalid(t *testing.T) {
+	for _, image := range []string{"", "ghcr.io/", "ghcr.io/Org/notebook", "notebook@sha256:short", "notebook@md5:" + strings.Repeat("a", 64)} {
+		t.Run(image, func(t *testing.T) {
+			_, err := parseReference(image)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReference_APIHost(t *testing.T) {
+	ref, err := parseReference("python:3.12")
+	require.NoError(t, err)
+	assert.Equal(t, "registry-1.docker.io", ref.apiHost())
+	assert.Equal(t, "docker.io/library/python:3.12", ref.String())
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:org/nb:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:org/nb:pull",
+	}, params)
+}
@@ -0,0 +1,323 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Limits on the registry responses read during admission
+const (
+	maxManifestBytes = 4 << 20
+	maxBlobBytes     = 1 << 20
+	maxTokenBytes    = 64 << 10
+)
+
+// manifestMediaTypes are accepted when resolving the digest of an image
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// errNotFound is returned when the registry has no manifest or blob under a tag or digest
+var errNotFound = errors.New("not found")
+
+// descriptor is an OCI content descriptor
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// imageManifest is an OCI image manifest, the format cosign stores signatures in
+type imageManifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+// registryCredential authenticates to a registry
+type registryCredential struct {
+	username string
+	password string
+}
+
+// registryClient reads manifests and blobs through the OCI distribution API, authenticating
+// anonymously or with the credentials of a docker config file
+type registryClient struct {
+	httpClient  *http.Client
+	credentials map[string]registryCredential
+
+	mu sync.Mutex
+	// tokens caches the bearer tokens of each registry and repository
+	tokens map[string]string
+}
+
+func newRegistryClient(httpClient *http.Client, credentials map[string]registryCredential) *registryClient {
+	return &registryClient{
+		httpClient:  httpClient,
+		credentials: credentials,
+		tokens:      map[string]string{},
+	}
+}
+
+// dockerConfig is the format of ~/.docker/config.json and of kubernetes.io/dockerconfigjson Secrets
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+}
+
+// loadRegistryCredentials reads the registry credentials of a docker config file
+func loadRegistryCredentials(path string) (map[string]registryCredential, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry credentials: %w", err)
+	}
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid registry credentials %s: %w", path, err)
+	}
+
+	credentials := map[string]registryCredential{}
+	for server, auth := range config.Auths {
+		credential := registryCredential{username: auth.Username, password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", server, err)
+			}
+			username, password, found := strings.Cut(string(decoded), ":")
+			if !found {
+				return nil, fmt.Errorf("invalid auth of registry %s: missing password", server)
+			}
+			credential = registryCredential{username: username, password: password}
+		}
+		credentials[normalizeRegistryServer(server)] = credential
+	}
+	return credentials, nil
+}
+
+// normalizeRegistryServer turns the server keys of docker config files, such as
+// https://index.docker.io/v1/, into registry hosts
+func normalizeRegistryServer(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	if host == "index.docker.io" || host == dockerHubAPIHost {
+		return dockerHubRegistry
+	}
+	return host
+}
+
+// manifest returns the image manifest under tag
+func (c *registryClient) manifest(ctx context.Context, ref reference, tag string) (*imageManifest, error) {
+	body, err := c.get(ctx, ref, "manifests/"+tag, manifestMediaTypes[1:2], maxManifestBytes)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &imageManifest{}
+	if err := json.Unmarshal(body, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s of %s: %w", tag, ref.repository, err)
+	}
+	return manifest, nil
+}
+
+// blob returns the content of the blob, checked against its digest
+func (c *registryClient) blob(ctx context.Context, ref reference, digest string) ([]byte, error) {
+	body, err := c.get(ctx, ref, "blobs/"+digest, nil, maxBlobBytes)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("blob %s of %s does not match its digest", digest, ref.repository)
+	}
+	return body, nil
+}
+
+// get reads the body of a registry API path, up to limit bytes
+func (c *registryClient) get(ctx context.Context, ref reference, path string, accept []string, limit int64) ([]byte, error) {
+	resp, err := c.do(ctx, ref, http.MethodGet, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of %s: %w", path, ref.repository, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s of %s exceeds %d bytes", path, ref.repository, limit)
+	}
+	return body, nil
+}
+
+// do sends a request to the registry API of the repository, authenticating with a bearer token
+// or basic credentials when the registry challenges the request
+func (c *registryClient) do(ctx context.Context, ref reference, method, path string, accept []string) (*http.Response, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/%s", ref.apiHost(), ref.repository, path)
+
+	send := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create registry request: %w", err)
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("registry request to %s failed: %w", ref.apiHost(), err)
+		}
+		return resp, nil
+	}
+
+	c.mu.Lock()
+	token := c.tokens[tokenKey(ref)]
+	c.mu.Unlock()
+	authorization := ""
+	if token != "" {
+		authorization = "Bearer " + token
+	}
+
+	resp, err := send(authorization)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if authorization, err = c.authorize(ctx, ref, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = send(authorization); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s of %s", errNotFound, path, ref.repository)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("registry %s returned status %d for %s of %s", ref.apiHost(), resp.StatusCode, path, ref.repository)
+	}
+	return resp, nil
+}
+
+// authorize answers the WWW-Authenticate challenge of the registry, returning the value of the
+// Authorization header to retry the request with
+func (c *registryClient) authorize(ctx context.Context, ref reference, challenge string) (string, error) {
+	credential, hasCredential := c.credentials[ref.registry]
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !hasCredential {
+			return "", fmt.Errorf("registry %s requires credentials", ref.registry)
+		}
+		return "Basic " + basicAuth(credential), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s requested unsupported authentication %q", ref.registry, scheme)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("registry %s returned an invalid token realm %q", ref.registry, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+ref.repository+":pull")
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry token request: %w", err)
+	}
+	if hasCredential {
+		req.Header.Set("Authorization", "Basic "+basicAuth(credential))
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("registry token request to %s failed: %w", realm.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request to %s returned status %d", realm.Host, resp.StatusCode)
+	}
+
+	var response struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenBytes)).Decode(&response); err != nil {
+		return "", fmt.Errorf("invalid registry token response from %s: %w", realm.Host, err)
+	}
+	token := response.Token
+	if token == "" {
+		token = response.AccessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("registry token response from %s has no token", realm.Host)
+	}
+
+	c.mu.Lock()
+	c.tokens[tokenKey(ref)] = token
+	c.mu.Unlock()
+	return "Bearer " + token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return scheme, params
+}
+
+// tokenKey returns the key of the bearer token of the repository, whose scope is the repository
+func tokenKey(ref reference) string {
+	return ref.registry + "/" + ref.repository
+}
+
+func basicAuth(credential registryCredential) string {
+	return base64.StdEncoding.EncodeToString([]byte(credential.username + ":" + credential.password))
+}
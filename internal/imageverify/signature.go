@@ -0,0 +1,283 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Annotations of the layers of cosign signature manifests
+const (
+	annotationSignature   = "dev.cosignproject.cosign/signature"
+	annotationCertificate = "dev.sigstore.cosign/certificate"
+	annotationChain       = "dev.sigstore.cosign/chain"
+	annotationBundle      = "dev.sigstore.cosign/bundle"
+)
+
+// cosignSignatureType is the critical type of the simple signing payloads cosign signs
+const cosignSignatureType = "cosign container image signature"
+
+// Extensions of Fulcio certificates holding the OIDC issuer of the signer
+var (
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// simpleSigningPayload is the payload cosign signs, binding the signature to an image digest
+type simpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the offline proof that a signature was recorded in the Rekor transparency log
+type rekorBundle struct {
+	SignedEntryTimestamp []byte             `json:"SignedEntryTimestamp"`
+	Payload              rekorBundlePayload `json:"Payload"`
+}
+
+// rekorBundlePayload is the log entry the SignedEntryTimestamp signs. Its fields are in the
+// lexicographic order of their JSON keys, so that it marshals to its canonical JSON.
+type rekorBundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the body of a Rekor log entry of a signature
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifySignature checks that a signature layer of the image signs its digest with one of the
+// public keys or keyless identities of the policy
+func (p *compiledPolicy) verifySignature(layer descriptor, payload []byte, digest string) error {
+	var signed simpleSigningPayload
+	if err := json.Unmarshal(payload, &signed); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if signed.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("unexpected signature payload type %q", signed.Critical.Type)
+	}
+	if signed.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %s", signed.Critical.Image.DockerManifestDigest)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[annotationSignature])
+	if err != nil || len(signature) == 0 {
+		return errors.New("signature layer has no valid signature")
+	}
+
+	for _, publicKey := range p.publicKeys {
+		if verifyWithKey(publicKey, payload, signature) == nil {
+			return nil
+		}
+	}
+
+	if layer.Annotations[annotationCertificate] == "" {
+		return errors.New("signature does not verify against the public keys")
+	}
+	if len(p.identities) == 0 {
+		return errors.New("keyless signature, but the policy has no keyless identities")
+	}
+	return p.verifyKeyless(layer, payload, signature)
+}
+
+// verifyKeyless checks the Fulcio certificate and Rekor bundle of a keyless signature, and that
+// the certificate identifies one of the identities of the policy
+func (p *compiledPolicy) verifyKeyless(layer descriptor, payload, signature []byte) error {
+	certificates, err := parseCertificates(layer.Annotations[annotationCertificate])
+	if err != nil {
+		return fmt.Errorf("invalid signing certificate: %w", err)
+	}
+	certificate := certificates[0]
+
+	// Fulcio certificates expire minutes after issuance: check them at the time Rekor recorded
+	// the signature, which the bundle proves
+	integratedTime, err := p.verifyBundle(layer.Annotations[annotationBundle], certificate, payload, signature)
+	if err != nil {
+		return err
+	}
+
+	intermediates := p.fulcioChain.Clone()
+	if chain := layer.Annotations[annotationChain]; chain != "" {
+		chainCertificates, err := parseCertificates(chain)
+		if err != nil {
+			return fmt.Errorf("invalid certificate chain: %w", err)
+		}
+		for _, c := range chainCertificates {
+			intermediates.AddCert(c)
+		}
+	}
+	if _, err := certificate.Verify(x509.VerifyOptions{
+		Roots:         p.fulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("signing certificate does not chain to the Fulcio roots: %w", err)
+	}
+
+	if err := verifyWithKey(certificate.PublicKey, payload, signature); err != nil {
+		return fmt.Errorf("signature does not verify against its certificate: %w", err)
+	}
+
+	issuer := certificateIssuer(certificate)
+	for _, subject := range certificateSubjects(certificate) {
+		for _, identity := range p.identities {
+			if identity.matches(issuer, subject) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("signer %v of issuer %q is not an accepted identity", certificateSubjects(certificate), issuer)
+}
+
+// verifyBundle checks that the Rekor bundle is signed by a Rekor public key of the policy and
+// records the signature and certificate, and returns the time Rekor recorded it
+func (p *compiledPolicy) verifyBundle(annotation string, certificate *x509.Certificate, payload, signature []byte) (time.Time, error) {
+	if annotation == "" {
+		return time.Time{}, errors.New("keyless signature has no Rekor bundle")
+	}
+	var bundle rekorBundle
+	if err := json.Unmarshal([]byte(annotation), &bundle); err != nil {
+		return time.Time{}, fmt.Errorf("invalid Rekor bundle: %w", err)
+	}
+
+	canonical, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to marshal Rekor bundle payload: %w", err)
+	}
+	verified := false
+	for _, publicKey := range p.rekorPublicKeys {
+		if verifyWithKey(publicKey, canonical, bundle.SignedEntryTimestamp) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return time.Time{}, errors.New("rekor bundle is not signed by a Rekor public key of the policy")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Rekor entry: %w", err)
+	}
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("invalid Rekor entry: %w", err)
+	}
+	payloadHash := sha256.Sum256(payload)
+	if entry.Kind != "hashedrekord" || entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(payloadHash[:]) {
+		return time.Time{}, errors.New("rekor entry does not record the signed payload")
+	}
+	entrySignature, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.Content)
+	if err != nil || !bytes.Equal(entrySignature, signature) {
+		return time.Time{}, errors.New("rekor entry does not record the signature")
+	}
+	entryKey, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.PublicKey.Content)
+	if err != nil {
+		return time.Time{}, errors.New("rekor entry has an invalid certificate")
+	}
+	entryCertificates, err := parseCertificates(string(entryKey))
+	if err != nil || !entryCertificates[0].Equal(certificate) {
+		return time.Time{}, errors.New("rekor entry does not record the signing certificate")
+	}
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// verifyWithKey verifies the signature of payload, with SHA-256 for ECDSA and RSA keys
+func verifyWithKey(publicKey crypto.PublicKey, payload, signature []byte) error {
+	digest := sha256.Sum256(payload)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, signature) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// certificateIssuer returns the OIDC issuer recorded in a Fulcio certificate
+func certificateIssuer(certificate *x509.Certificate) string {
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(oidIssuerV2) {
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(extension.Value, &issuer, "utf8"); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(oidIssuerV1) {
+			return string(extension.Value)
+		}
+	}
+	return ""
+}
+
+// certificateSubjects returns the emails and URIs a Fulcio certificate is issued to
+func certificateSubjects(certificate *x509.Certificate) []string {
+	subjects := append([]string{}, certificate.EmailAddresses...)
+	for _, uri := range certificate.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	return subjects
+}
+
+func (i compiledIdentity) matches(issuer, subject string) bool {
+	if issuer != i.issuer {
+		return false
+	}
+	if i.subjectRegExp != nil {
+		return i.subjectRegExp.MatchString(subject)
+	}
+	return subject == i.subject
+}
@@ -0,0 +1,182 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package imageverify verifies the cosign signatures of container images against a policy of
+// public keys and keyless identities, reading the signatures from the registry of the image.
+package imageverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Verification runs in the admission path, so it is bounded well below the webhook timeout
+const (
+	verifyTimeout = 5 * time.Second
+	// maxSignatures bounds the signature layers checked per image
+	maxSignatures = 20
+	// maxCacheEntries bounds the verification results kept in the cache
+	maxCacheEntries = 4096
+)
+
+// ErrVerificationFailed wraps the reasons an image is rejected: it has no signature, or none of
+// its signatures verifies against the policy. Other errors, such as an unreachable registry, are
+// not cached.
+var ErrVerificationFailed = errors.New("image signature verification failed")
+
+// Options configures a Verifier
+type Options struct {
+	// RegistryCredentialsFile is a docker config file with the credentials of private registries
+	RegistryCredentialsFile string
+	// HTTPClient sends the registry requests, a client with a timeout if nil
+	HTTPClient *http.Client
+}
+
+// Verifier verifies the cosign signatures of images pinned by digest, caching the results per
+// image reference
+type Verifier struct {
+	policy   *compiledPolicy
+	registry *registryClient
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// cacheEntry is the cached result of verifying an image
+type cacheEntry struct {
+	err     error
+	expires time.Time
+}
+
+// NewVerifier creates a Verifier enforcing the policy
+func NewVerifier(policy *Policy, opts Options) (*Verifier, error) {
+	compiled, err := policy.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := map[string]registryCredential{}
+	if opts.RegistryCredentialsFile != "" {
+		if credentials, err = loadRegistryCredentials(opts.RegistryCredentialsFile); err != nil {
+			return nil, err
+		}
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: verifyTimeout}
+	}
+
+	return &Verifier{
+		policy:   compiled,
+		registry: newRegistryClient(httpClient, credentials),
+		now:      time.Now,
+		cache:    map[string]cacheEntry{},
+	}, nil
+}
+
+// VerifyImage returns nil when the image is pinned by digest and carries a cosign signature that
+// verifies against the policy. Results are cached for the cache TTL of the policy, so an image
+// re-signed in the meantime, or whose key was revoked, is re-verified only once its entry expires.
+func (v *Verifier) VerifyImage(ctx context.Context, image string) error {
+	if entry, ok := v.cached(image); ok {
+		return entry.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	err := v.verify(ctx, image)
+	if err == nil || errors.Is(err, ErrVerificationFailed) {
+		v.store(image, err)
+	}
+	return err
+}
+
+// verify reads the signatures of the image from its registry and checks them against the policy
+func (v *Verifier) verify(ctx context.Context, image string) error {
+	ref, err := parseReference(image)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+
+	// The pod pulls the image by the reference verified here, so a tag, which could be moved to an
+	// unsigned image after verification, is not accepted
+	if ref.digest == "" {
+		return fmt.Errorf("%w: image %s must be pinned by digest, e.g. %s@sha256:<digest>",
+			ErrVerificationFailed, image, strings.TrimSuffix(image, ":"+ref.tag))
+	}
+
+	// cosign stores the signatures of an image under the tag sha256-<digest>.sig
+	signatureTag := strings.Replace(ref.digest, ":", "-", 1) + ".sig"
+	manifest, err := v.registry.manifest(ctx, ref, signatureTag)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return fmt.Errorf("%w: image %s has no signature", ErrVerificationFailed, image)
+		}
+		return fmt.Errorf("failed to read the signatures of image %s: %w", image, err)
+	}
+
+	var reasons []string
+	for i, layer := range manifest.Layers {
+		if i == maxSignatures {
+			break
+		}
+		if layer.Annotations[annotationSignature] == "" {
+			continue
+		}
+		payload, err := v.registry.blob(ctx, ref, layer.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to read a signature of image %s: %w", image, err)
+		}
+		if err := v.policy.verifySignature(layer, payload, ref.digest); err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		return nil
+	}
+	if len(reasons) == 0 {
+		return fmt.Errorf("%w: image %s has no signature", ErrVerificationFailed, image)
+	}
+	return fmt.Errorf("%w: no signature of image %s verifies: %s", ErrVerificationFailed, image, strings.Join(reasons, "; "))
+}
+
+// cached returns the unexpired result of verifying the image
+func (v *Verifier) cached(image string) (cacheEntry, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.cache[image]
+	if !ok || !v.now().Before(entry.expires) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store caches the result of verifying the image, evicting expired entries when the cache is full
+func (v *Verifier) store(image string, err error) {
+	if v.policy.cacheTTL == 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if len(v.cache) >= maxCacheEntries {
+		for key, entry := range v.cache {
+			if !now.Before(entry.expires) {
+				delete(v.cache, key)
+			}
+		}
+		if len(v.cache) >= maxCacheEntries {
+			v.cache = map[string]cacheEntry{}
+		}
+	}
+	v.cache[image] = cacheEntry{err: err, expires: now.Add(v.policy.cacheTTL)}
+}
@@ -0,0 +1,524 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package imageverify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testRepository = "org/notebook"
+	testTag        = "1.0"
+	testIssuer     = "https://token.actions.githubusercontent.com"
+	testSubject    = "https://github.com/org/notebook/.github/workflows/release.yaml@refs/heads/main"
+)
+
+// testRegistry is an OCI registry serving one image and the cosign signature manifest of its digest
+type testRegistry struct {
+	server   *httptest.Server
+	digest   string
+	layers   []descriptor
+	blobs    map[string][]byte
+	requests atomic.Int32
+	// token, when set, is required as a bearer token obtained from the /token realm
+	token string
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+	sum := sha256.Sum256([]byte("image manifest"))
+	r := &testRegistry{digest: "sha256:" + hex.EncodeToString(sum[:]), blobs: map[string][]byte{}}
+	r.server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if req.URL.Query().Get("scope") != "repository:"+testRepository+":pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": r.token})
+		return
+	}
+
+	r.requests.Add(1)
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	prefix := "/v2/" + testRepository + "/"
+	switch path := strings.TrimPrefix(req.URL.Path, prefix); {
+	case path == "manifests/"+strings.Replace(r.digest, ":", "-", 1)+".sig" && len(r.layers) > 0:
+		_ = json.NewEncoder(w).Encode(imageManifest{Layers: r.layers})
+	case strings.HasPrefix(path, "blobs/") && r.blobs[strings.TrimPrefix(path, "blobs/")] != nil:
+		_, _ = w.Write(r.blobs[strings.TrimPrefix(path, "blobs/")])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// image returns the reference of the image the registry serves, pinned by digest
+func (r *testRegistry) image() string {
+	return r.imageByTag() + "@" + r.digest
+}
+
+// imageByTag returns the reference of the image the registry serves by its tag only
+func (r *testRegistry) imageByTag() string {
+	u, _ := url.Parse(r.server.URL)
+	return u.Host + "/" + testRepository + ":" + testTag
+}
+
+// payload returns the simple signing payload of a digest
+func (r *testRegistry) payload(digest string) []byte {
+	return fmt.Appendf(nil, `{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		testRepository, digest)
+}
+
+// addSignature stores a signature layer of payload with the annotations
+func (r *testRegistry) addSignature(payload []byte, annotations map[string]string) {
+	sum := sha256.Sum256(payload)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	r.blobs[digest] = payload
+	r.layers = append(r.layers, descriptor{
+		MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+		Digest:      digest,
+		Size:        int64(len(payload)),
+		Annotations: annotations,
+	})
+}
+
+func (r *testRegistry) verifier(t *testing.T, policy *Policy) *Verifier {
+	t.Helper()
+	verifier, err := NewVerifier(policy, Options{HTTPClient: r.server.Client()})
+	require.NoError(t, err)
+	return verifier
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	t.Helper()
+	sum := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func TestVerifyImage_PublicKey(t *testing.T) {
+	registry := newTestRegistry(t)
+	key := generateKey(t)
+	payload := registry.payload(registry.digest)
+	registry.addSignature(payload, map[string]string{
+		annotationSignature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	verifier := registry.verifier(t, &Policy{PublicKeys: []string{publicKeyPEM(t, key)}})
+	require.NoError(t, verifier.VerifyImage(context.Background(), registry.image()))
+}
+
+func TestVerifyImage_Rejections(t *testing.T) {
+	key := generateKey(t)
+
+	tests := []struct {
+		name     string
+		setup    func(r *testRegistry)
+		expected string
+	}{
+		{
+			name:     "unsigned image",
+			setup:    func(r *testRegistry) {},
+			expected: "has no signature",
+		},
+		{
+			name: "signature of another key",
+			setup: func(r *testRegistry) {
+				payload := r.payload(r.digest)
+				r.addSignature(payload, map[string]string{
+					annotationSignature: base64.StdEncoding.EncodeToString(sign(t, generateKey(t), payload)),
+				})
+			},
+			expected: "does not verify against the public keys",
+		},
+		{
+			name: "signature of another digest",
+			setup: func(r *testRegistry) {
+				payload := r.payload("sha256:" + strings.Repeat("0", 64))
+				r.addSignature(payload, map[string]string{
+					annotationSignature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+				})
+			},
+			expected: "signature is for digest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry(t)
+			tt.setup(registry)
+
+			err := registry.verifier(t, &Policy{PublicKeys: []string{publicKeyPEM(t, key)}}).
+				VerifyImage(context.Background(), registry.image())
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrVerificationFailed)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestVerifyImage_RejectsTags(t *testing.T) {
+	registry := newTestRegistry(t)
+	key := generateKey(t)
+	payload := registry.payload(registry.digest)
+	registry.addSignature(payload, map[string]string{
+		annotationSignature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	err := registry.verifier(t, &Policy{PublicKeys: []string{publicKeyPEM(t, key)}}).
+		VerifyImage(context.Background(), registry.imageByTag())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.Contains(t, err.Error(), "must be pinned by digest")
+	assert.Zero(t, registry.requests.Load(), "a tag must be rejected without querying the registry")
+}
+
+func TestVerifyImage_BearerToken(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.token = "registry-token"
+	key := generateKey(t)
+	payload := registry.payload(registry.digest)
+	registry.addSignature(payload, map[string]string{
+		annotationSignature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	verifier := registry.verifier(t, &Policy{PublicKeys: []string{publicKeyPEM(t, key)}})
+	require.NoError(t, verifier.VerifyImage(context.Background(), registry.image()))
+}
+
+func TestVerifyImage_Cache(t *testing.T) {
+	registry := newTestRegistry(t)
+	key := generateKey(t)
+	payload := registry.payload(registry.digest)
+	registry.addSignature(payload, map[string]string{
+		annotationSignature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	verifier := registry.verifier(t, &Policy{
+		PublicKeys: []string{publicKeyPEM(t, key)},
+		CacheTTL:   &metav1.Duration{Duration: time.Minute},
+	})
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	require.NoError(t, verifier.VerifyImage(context.Background(), registry.image()))
+	requests := registry.requests.Load()
+	require.NoError(t, verifier.VerifyImage(context.Background(), registry.image()))
+	assert.Equal(t, requests, registry.requests.Load(), "a cached result must not query the registry")
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, verifier.VerifyImage(context.Background(), registry.image()))
+	assert.Greater(t, registry.requests.Load(), requests, "an expired result must be verified again")
+}
+
+func TestVerifyImage_DoesNotCacheRegistryErrors(t *testing.T) {
+	registry := newTestRegistry(t)
+	key := generateKey(t)
+	verifier := registry.verifier(t, &Policy{PublicKeys: []string{publicKeyPEM(t, key)}})
+	registry.server.Close()
+
+	err := verifier.VerifyImage(context.Background(), registry.image())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrVerificationFailed)
+	_, cached := verifier.cached(registry.image())
+	assert.False(t, cached)
+}
+
+// keylessFixture is a Fulcio CA and Rekor log issuing keyless signatures
+type keylessFixture struct {
+	caKey    *ecdsa.PrivateKey
+	ca       *x509.Certificate
+	caPEM    string
+	rekorKey *ecdsa.PrivateKey
+}
+
+func newKeylessFixture(t *testing.T) *keylessFixture {
+	t.Helper()
+	caKey := generateKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(48 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &keylessFixture{
+		caKey:    caKey,
+		ca:       ca,
+		caPEM:    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		rekorKey: generateKey(t),
+	}
+}
+
+// sign returns the annotations of a keyless signature of payload by subject, with a certificate
+// valid for ten minutes from issued and recorded in Rekor at issued
+func (f *keylessFixture) sign(t *testing.T, payload []byte, subject string, issued time.Time) map[string]string {
+	t.Helper()
+	key := generateKey(t)
+	subjectURI, err := url.Parse(subject)
+	require.NoError(t, err)
+	issuer, err := asn1.MarshalWithParams(testIssuer, "utf8")
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       issued,
+		NotAfter:        issued.Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{subjectURI},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuer}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, f.ca, &key.PublicKey, f.caKey)
+	require.NoError(t, err)
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	signature := sign(t, key, payload)
+
+	var entry hashedRekord
+	payloadHash := sha256.Sum256(payload)
+	entry.Kind = "hashedrekord"
+	entry.Spec.Data.Hash.Algorithm = "sha256"
+	entry.Spec.Data.Hash.Value = hex.EncodeToString(payloadHash[:])
+	entry.Spec.Signature.Content = base64.StdEncoding.EncodeToString(signature)
+	entry.Spec.Signature.PublicKey.Content = base64.StdEncoding.EncodeToString(certificatePEM)
+	body, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	bundlePayload := rekorBundlePayload{
+		Body:           base64.StdEncoding.EncodeToString(body),
+		IntegratedTime: issued.Add(time.Minute).Unix(),
+		LogID:          strings.Repeat("c", 64),
+		LogIndex:       42,
+	}
+	canonical, err := json.Marshal(bundlePayload)
+	require.NoError(t, err)
+	bundle, err := json.Marshal(rekorBundle{SignedEntryTimestamp: sign(t, f.rekorKey, canonical), Payload: bundlePayload})
+	require.NoError(t, err)
+
+	return map[string]string{
+		annotationSignature:   base64.StdEncoding.EncodeToString(signature),
+		annotationCertificate: string(certificatePEM),
+		annotationBundle:      string(bundle),
+	}
+}
+
+func (f *keylessFixture) policy(t *testing.T, identity Identity) *Policy {
+	return &Policy{Keyless: &KeylessPolicy{
+		FulcioRoots:     f.caPEM,
+		RekorPublicKeys: []string{publicKeyPEM(t, f.rekorKey)},
+		Identities:      []Identity{identity},
+	}}
+}
+
+func TestVerifyImage_Keyless(t *testing.T) {
+	fixture := newKeylessFixture(t)
+	// The certificate expired long before admission, but was valid when Rekor recorded the signature
+	issued := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		identity Identity
+		tamper   func(annotations map[string]string)
+		expected string
+	}{
+		{
+			name:     "accepted subject",
+			identity: Identity{Issuer: testIssuer, Subject: testSubject},
+		},
+		{
+			name:     "accepted subject regular expression",
+			identity: Identity{Issuer: testIssuer, SubjectRegExp: `^https://github\.com/org/.*$`},
+		},
+		{
+			name:     "another subject",
+			identity: Identity{Issuer: testIssuer, Subject: "https://github.com/other/repo/.github/workflows/release.yaml@refs/heads/main"},
+			expected: "is not an accepted identity",
+		},
+		{
+			name:     "another issuer",
+			identity: Identity{Issuer: "https://accounts.google.com", Subject: testSubject},
+			expected: "is not an accepted identity",
+		},
+		{
+			name:     "missing bundle",
+			identity: Identity{Issuer: testIssuer, Subject: testSubject},
+			tamper:   func(annotations map[string]string) { delete(annotations, annotationBundle) },
+			expected: "has no Rekor bundle",
+		},
+		{
+			name:     "bundle of another signature",
+			identity: Identity{Issuer: testIssuer, Subject: testSubject},
+			tamper: func(annotations map[string]string) {
+				other := fixture.sign(t, []byte("other payload"), testSubject, issued)
+				annotations[annotationBundle] = other[annotationBundle]
+			},
+			expected: "rekor entry does not record the signed payload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTestRegistry(t)
+			payload := registry.payload(registry.digest)
+			annotations := fixture.sign(t, payload, testSubject, issued)
+			if tt.tamper != nil {
+				tt.tamper(annotations)
+			}
+			registry.addSignature(payload, annotations)
+
+			err := registry.verifier(t, fixture.policy(t, tt.identity)).VerifyImage(context.Background(), registry.image())
+			if tt.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrVerificationFailed)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestVerifyImage_KeylessUntrustedRekor(t *testing.T) {
+	fixture := newKeylessFixture(t)
+	registry := newTestRegistry(t)
+	payload := registry.payload(registry.digest)
+	registry.addSignature(payload, fixture.sign(t, payload, testSubject, time.Now()))
+
+	policy := fixture.policy(t, Identity{Issuer: testIssuer, Subject: testSubject})
+	policy.Keyless.RekorPublicKeys = []string{publicKeyPEM(t, generateKey(t))}
+
+	err := registry.verifier(t, policy).VerifyImage(context.Background(), registry.image())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not signed by a Rekor public key")
+}
+
+func TestNewVerifier_InvalidPolicy(t *testing.T) {
+	fixture := newKeylessFixture(t)
+	tests := []struct {
+		name   string
+		policy *Policy
+	}{
+		{name: "empty policy", policy: &Policy{}},
+		{name: "invalid public key", policy: &Policy{PublicKeys: []string{"not a key"}}},
+		{name: "keyless identity without issuer", policy: fixture.policy(t, Identity{Subject: testSubject})},
+		{name: "keyless identity without subject", policy: fixture.policy(t, Identity{Issuer: testIssuer})},
+		{name: "keyless identity with invalid regular expression", policy: fixture.policy(t, Identity{Issuer: testIssuer, SubjectRegExp: "("})},
+		{
+			name: "keyless without Fulcio roots",
+			policy: &Policy{Keyless: &KeylessPolicy{
+				RekorPublicKeys: []string{publicKeyPEM(t, fixture.rekorKey)},
+				Identities:      []Identity{{Issuer: testIssuer, Subject: testSubject}},
+			}},
+		},
+		{
+			name: "keyless without Rekor keys",
+			policy: &Policy{Keyless: &KeylessPolicy{
+				FulcioRoots: fixture.caPEM,
+				Identities:  []Identity{{Issuer: testIssuer, Subject: testSubject}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVerifier(tt.policy, Options{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	key := generateKey(t)
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	policyYAML := "cacheTTL: 5m\npublicKeys:\n  - |\n" + indent(publicKeyPEM(t, key), "    ")
+	require.NoError(t, os.WriteFile(path, []byte(policyYAML), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, policy.CacheTTL.Duration)
+	require.Len(t, policy.PublicKeys, 1)
+	_, err = NewVerifier(policy, Options{})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("publicKey: []\n"), 0o600))
+	_, err = LoadPolicy(path)
+	assert.Error(t, err, "unknown fields must be rejected")
+}
+
+func TestLoadRegistryCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{"auths":{
+		"https://index.docker.io/v1/":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("hub-user:hub-pass")) + `"},
+		"ghcr.io":{"username":"gh-user","password":"gh-pass"}}}`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	credentials, err := loadRegistryCredentials(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]registryCredential{
+		"docker.io": {username: "hub-user", password: "hub-pass"},
+		"ghcr.io":   {username: "gh-user", password: "gh-pass"},
+	}, credentials)
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	return prefix + strings.Join(lines, "\n"+prefix) + "\n"
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package landing provides the landing page server, which lists the workspaces
+// of the caller and links to their access URLs.
+package landing
+
+import "time"
+
+// Default values
+const (
+	DefaultServerPort      = 8090
+	DefaultUserHeader      = "X-Auth-Request-User"
+	DefaultGroupsHeader    = "X-Auth-Request-Groups"
+	DefaultTitle           = "Jupyter Workspaces"
+	DefaultReadTimeout     = 10 * time.Second
+	DefaultWriteTimeout    = 30 * time.Second
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+// LandingConfig contains the configuration for the landing page server
+type LandingConfig struct {
+	ServerPort int
+
+	// UserHeader and GroupsHeader name the headers that the authenticating proxy
+	// in front of the landing page sets with the identity of the caller
+	UserHeader   string
+	GroupsHeader string
+
+	Title           string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// ConfigOption is a function that modifies a LandingConfig
+type ConfigOption func(*LandingConfig)
+
+// WithServerPort sets the server port
+func WithServerPort(port int) ConfigOption {
+	return func(c *LandingConfig) {
+		c.ServerPort = port
+	}
+}
+
+// WithUserHeader sets the header holding the username of the caller
+func WithUserHeader(header string) ConfigOption {
+	return func(c *LandingConfig) {
+		c.UserHeader = header
+	}
+}
+
+// WithGroupsHeader sets the header holding the comma-separated groups of the caller
+func WithGroupsHeader(header string) ConfigOption {
+	return func(c *LandingConfig) {
+		c.GroupsHeader = header
+	}
+}
+
+// WithTitle sets the title of the landing page
+func WithTitle(title string) ConfigOption {
+	return func(c *LandingConfig) {
+		c.Title = title
+	}
+}
+
+// NewConfig creates a new LandingConfig with default values and applies the given options
+func NewConfig(opts ...ConfigOption) *LandingConfig {
+	config := &LandingConfig{
+		ServerPort:      DefaultServerPort,
+		UserHeader:      DefaultUserHeader,
+		GroupsHeader:    DefaultGroupsHeader,
+		Title:           DefaultTitle,
+		ReadTimeout:     DefaultReadTimeout,
+		WriteTimeout:    DefaultWriteTimeout,
+		ShutdownTimeout: DefaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package landing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/kubernetes"
+	v1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LandingServer serves the landing page that lists the workspaces of the caller
+type LandingServer struct {
+	config     *LandingConfig
+	k8sClient  client.Reader
+	sarClient  v1.SubjectAccessReviewInterface
+	logger     *logr.Logger
+	httpServer *http.Server
+}
+
+// NewLandingServer creates a new landing page server
+func NewLandingServer(
+	config *LandingConfig,
+	logger *logr.Logger,
+	k8sClient client.Reader,
+	sarClient v1.SubjectAccessReviewInterface,
+) *LandingServer {
+	server := &LandingServer{
+		config:    config,
+		logger:    logger,
+		k8sClient: k8sClient,
+		sarClient: sarClient,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", server.handleIndex)
+	mux.HandleFunc("/api/workspaces", server.handleListWorkspaces)
+	mux.HandleFunc("/health", server.handleHealth)
+
+	server.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", config.ServerPort),
+		Handler:      mux,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	}
+	return server
+}
+
+// Start starts the landing page server and implements the controller-runtime's Runnable interface.
+// It blocks until the context is cancelled, then shuts the server down gracefully.
+func (s *LandingServer) Start(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting landing page server", "port", s.config.ServerPort)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("landing page server error: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down landing page server: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// This indicates this runnable doesn't need to be a leader to run
+func (s *LandingServer) NeedLeaderElection() bool {
+	return false
+}
+
+// SetupLandingServerWithManager sets up the landing page server and adds it to the manager
+func SetupLandingServerWithManager(mgr ctrl.Manager, config *LandingConfig) error {
+	if config == nil {
+		config = NewConfig()
+	}
+
+	logger := mgr.GetLogger().WithName("landing")
+
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to instantiate the sar client: %w", err)
+	}
+
+	server := NewLandingServer(config, &logger, mgr.GetClient(), clientset.AuthorizationV1().SubjectAccessReviews())
+	if err := mgr.Add(server); err != nil {
+		return fmt.Errorf("failed to add landing page server to manager: %w", err)
+	}
+	return nil
+}
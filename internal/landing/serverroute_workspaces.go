@@ -0,0 +1,120 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package landing
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.4em 1em; text-align: left; border-bottom: 1px solid #ddd; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p>Signed in as {{ .Username }}</p>
+{{- if .Workspaces }}
+<table>
+<tr><th>Workspace</th><th>Namespace</th><th>State</th></tr>
+{{- range .Workspaces }}
+<tr>
+<td>{{ if .AccessURL }}<a href="{{ .AccessURL }}">{{ .DisplayName }}</a>{{ else }}{{ .DisplayName }}{{ end }}</td>
+<td>{{ .Namespace }}</td>
+<td>{{ .State }}</td>
+</tr>
+{{- end }}
+</table>
+{{- else }}
+<p>You do not have any workspaces.</p>
+{{- end }}
+</body>
+</html>
+`))
+
+// indexData is the data rendered by the index template
+type indexData struct {
+	Title      string
+	Username   string
+	Workspaces []WorkspaceEntry
+}
+
+// handleIndex renders the list of the caller's workspaces as an HTML page
+func (s *LandingServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	username, workspaces, ok := s.resolveWorkspaces(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, indexData{
+		Title:      s.config.Title,
+		Username:   username,
+		Workspaces: workspaces,
+	}); err != nil {
+		s.logger.Error(err, "Failed to render landing page")
+	}
+}
+
+// handleListWorkspaces returns the caller's workspaces as JSON
+func (s *LandingServer) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	_, workspaces, ok := s.resolveWorkspaces(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string][]WorkspaceEntry{"workspaces": workspaces}); err != nil {
+		s.logger.Error(err, "Failed to encode workspaces response")
+	}
+}
+
+// resolveWorkspaces reads the caller identity from the request and lists their workspaces.
+// It writes an error response and returns false if the request cannot be served.
+func (s *LandingServer) resolveWorkspaces(w http.ResponseWriter, r *http.Request) (string, []WorkspaceEntry, bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return "", nil, false
+	}
+
+	username := r.Header.Get(s.config.UserHeader)
+	if username == "" {
+		http.Error(w, "unauthenticated request", http.StatusUnauthorized)
+		return "", nil, false
+	}
+
+	workspaces, err := s.listUserWorkspaces(r.Context(), username, parseGroups(r.Header.Get(s.config.GroupsHeader)))
+	if err != nil {
+		s.logger.Error(err, "Failed to list workspaces", "username", username)
+		http.Error(w, "failed to list workspaces", http.StatusInternalServerError)
+		return "", nil, false
+	}
+	return username, workspaces, true
+}
+
+// parseGroups splits a comma-separated groups header, dropping empty entries
+func parseGroups(header string) []string {
+	var groups []string
+	for _, group := range strings.Split(header, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
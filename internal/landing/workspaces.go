@@ -0,0 +1,133 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package landing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	connectionv1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/connection/v1alpha1"
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	// ownerAnnotation is the annotation holding the username of the workspace creator
+	ownerAnnotation = "workspace.jupyter.org/created-by"
+
+	// accessTypeOwnerOnly restricts connections to the workspace creator
+	accessTypeOwnerOnly = "OwnerOnly"
+)
+
+// Workspace states shown on the landing page
+const (
+	StateRunning        = "Running"
+	StateStarting       = "Starting"
+	StateStopped        = "Stopped"
+	StateDegraded       = "Degraded"
+	StateAccessDisabled = "AccessDisabled"
+)
+
+// WorkspaceEntry describes a workspace the caller can connect to
+type WorkspaceEntry struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	DisplayName string `json:"displayName"`
+	State       string `json:"state"`
+	AccessURL   string `json:"accessURL,omitempty"`
+}
+
+// listUserWorkspaces returns the workspaces the user could connect to, applying the same rules as
+// the connection API: the user needs RBAC permission to create workspace connections in the
+// namespace, and the workspace must be public or owned by the user.
+func (s *LandingServer) listUserWorkspaces(ctx context.Context, username string, groups []string) ([]WorkspaceEntry, error) {
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := s.k8sClient.List(ctx, workspaces); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	allowedByNamespace := map[string]bool{}
+	entries := []WorkspaceEntry{}
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		if !ws.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if ws.Spec.AccessType == accessTypeOwnerOnly && ws.Annotations[ownerAnnotation] != username {
+			continue
+		}
+
+		allowed, checked := allowedByNamespace[ws.Namespace]
+		if !checked {
+			var err error
+			if allowed, err = s.canConnect(ctx, ws.Namespace, username, groups); err != nil {
+				return nil, err
+			}
+			allowedByNamespace[ws.Namespace] = allowed
+		}
+		if allowed {
+			entries = append(entries, newWorkspaceEntry(ws))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// canConnect checks whether the user has RBAC permission to create workspace connections in the namespace
+func (s *LandingServer) canConnect(ctx context.Context, namespace, username string, groups []string) (bool, error) {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "create",
+				Group:     connectionv1alpha1.SchemeGroupVersion.Group,
+				Resource:  "workspaceconnections",
+			},
+			User:   username,
+			Groups: groups,
+		},
+	}
+	result, err := s.sarClient.Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	return result.Status.Allowed, nil
+}
+
+// newWorkspaceEntry summarizes a workspace; the access URL is only set when the workspace can be reached
+func newWorkspaceEntry(ws *workspacev1alpha1.Workspace) WorkspaceEntry {
+	entry := WorkspaceEntry{
+		Name:        ws.Name,
+		Namespace:   ws.Namespace,
+		DisplayName: ws.Spec.DisplayName,
+	}
+
+	switch {
+	case meta.IsStatusConditionTrue(ws.Status.Conditions, "Stopped"):
+		entry.State = StateStopped
+	case meta.IsStatusConditionTrue(ws.Status.Conditions, "Degraded"):
+		entry.State = StateDegraded
+	case !workspaceutil.IsAccessEnabled(ws):
+		entry.State = StateAccessDisabled
+	case meta.IsStatusConditionTrue(ws.Status.Conditions, "Available"):
+		entry.State = StateRunning
+		entry.AccessURL = ws.Status.AccessURL
+	default:
+		entry.State = StateStarting
+	}
+	return entry
+}
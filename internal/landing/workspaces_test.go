@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package landing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testUser           = "alice"
+	testAllowedNs      = "team-a"
+	testForbiddenNs    = "team-b"
+	testOtherOwnerUser = "bob"
+)
+
+func newTestWorkspace(namespace, name, owner, accessType string, conditions ...metav1.Condition) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{ownerAnnotation: owner},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: name,
+			AccessType:  accessType,
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{
+			AccessURL:  "https://example.com/workspaces/" + namespace + "/" + name + "/",
+			Conditions: conditions,
+		},
+	}
+}
+
+// newTestServer creates a landing server whose SubjectAccessReviews only allow testAllowedNs
+func newTestServer(t *testing.T, objs ...client.Object) (*LandingServer, *int) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	sarCount := 0
+	clientset := k8sfake.NewClientset()
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sarCount++
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace == testAllowedNs && sar.Spec.User == testUser
+		return true, sar, nil
+	})
+
+	logger := logr.Discard()
+	return NewLandingServer(NewConfig(), &logger, k8sClient, clientset.AuthorizationV1().SubjectAccessReviews()), &sarCount
+}
+
+func TestListUserWorkspacesAppliesConnectionRules(t *testing.T) {
+	available := metav1.Condition{Type: "Available", Status: metav1.ConditionTrue}
+	stopped := metav1.Condition{Type: "Stopped", Status: metav1.ConditionTrue}
+	server, sarCount := newTestServer(t,
+		newTestWorkspace(testAllowedNs, "mine", testUser, accessTypeOwnerOnly, available),
+		newTestWorkspace(testAllowedNs, "public", testOtherOwnerUser, "Public", stopped),
+		newTestWorkspace(testAllowedNs, "private", testOtherOwnerUser, accessTypeOwnerOnly, available),
+		newTestWorkspace(testForbiddenNs, "forbidden", testUser, "Public", available),
+		newTestWorkspace(testForbiddenNs, "forbidden-2", testUser, "Public", available),
+	)
+
+	entries, err := server.listUserWorkspaces(t.Context(), testUser, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mine", entries[0].Name)
+	assert.Equal(t, StateRunning, entries[0].State)
+	assert.NotEmpty(t, entries[0].AccessURL)
+	assert.Equal(t, "public", entries[1].Name)
+	assert.Equal(t, StateStopped, entries[1].State)
+	assert.Empty(t, entries[1].AccessURL)
+
+	// One review per namespace
+	assert.Equal(t, 2, *sarCount)
+}
+
+func TestNewWorkspaceEntryHidesURLWhenAccessDisabled(t *testing.T) {
+	accessEnabled := false
+	ws := newTestWorkspace(testAllowedNs, "ws", testUser, "Public",
+		metav1.Condition{Type: "Available", Status: metav1.ConditionTrue})
+	ws.Spec.AccessEnabled = &accessEnabled
+
+	entry := newWorkspaceEntry(ws)
+	assert.Equal(t, StateAccessDisabled, entry.State)
+	assert.Empty(t, entry.AccessURL)
+}
+
+func TestHandleListWorkspacesRequiresIdentity(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/workspaces", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestHandleListWorkspacesReturnsJSON(t *testing.T) {
+	server, _ := newTestServer(t, newTestWorkspace(testAllowedNs, "mine", testUser, accessTypeOwnerOnly))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/workspaces", nil)
+	request.Header.Set(DefaultUserHeader, testUser)
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response map[string][]WorkspaceEntry
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response["workspaces"], 1)
+	assert.Equal(t, StateStarting, response["workspaces"][0].State)
+}
+
+func TestHandleIndexRendersWorkspaces(t *testing.T) {
+	server, _ := newTestServer(t, newTestWorkspace(testAllowedNs, "mine", testUser, accessTypeOwnerOnly,
+		metav1.Condition{Type: "Available", Status: metav1.ConditionTrue}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(DefaultUserHeader, testUser)
+	recorder := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `<a href="https://example.com/workspaces/team-a/mine/">mine</a>`)
+}
+
+func TestParseGroups(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, parseGroups(" a, ,b"))
+	assert.Empty(t, parseGroups(""))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package loadsim
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// APICallCounter counts the requests sent to the API server through a rest config,
+// by verb and resource (e.g. "patch workspaces/status")
+type APICallCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewAPICallCounter creates an empty APICallCounter
+func NewAPICallCounter() *APICallCounter {
+	return &APICallCounter{counts: make(map[string]int)}
+}
+
+// Wrap returns a copy of config whose requests are counted
+func (c *APICallCounter) Wrap(config *rest.Config) *rest.Config {
+	wrapped := rest.CopyConfig(config)
+	wrapped.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &countingRoundTripper{counter: c, next: rt}
+	})
+	return wrapped
+}
+
+// Reset clears the counts, e.g. to leave out the calls made while setting up the simulation
+func (c *APICallCounter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts = make(map[string]int)
+}
+
+// Snapshot returns a copy of the counts
+func (c *APICallCounter) Snapshot() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int, len(c.counts))
+	for key, count := range c.counts {
+		counts[key] = count
+	}
+	return counts
+}
+
+func (c *APICallCounter) record(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[key]++
+}
+
+// countingRoundTripper records each request before sending it
+type countingRoundTripper struct {
+	counter *APICallCounter
+	next    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.counter.record(requestKey(req))
+	return rt.next.RoundTrip(req)
+}
+
+// requestKey returns the Kubernetes verb and resource of a request to the API server,
+// e.g. "list workspaces" or "patch workspaces/status"
+func requestKey(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	// Skip the /api/<version> or /apis/<group>/<version> prefix
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return strings.ToLower(req.Method) + " " + req.URL.Path
+	}
+
+	// Skip the namespace of namespaced resources, but not the namespaces resource itself
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return strings.ToLower(req.Method) + " discovery"
+	}
+
+	resource := segments[0]
+	hasName := len(segments) >= 2
+	if len(segments) >= 3 {
+		resource += "/" + segments[2]
+	}
+
+	return requestVerb(req, hasName) + " " + resource
+}
+
+// requestVerb maps an HTTP request to its Kubernetes verb
+func requestVerb(req *http.Request, hasName bool) string {
+	switch req.Method {
+	case http.MethodGet:
+		if hasName {
+			return "get"
+		}
+		if req.URL.Query().Get("watch") == "true" {
+			return "watch"
+		}
+		return "list"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		if hasName {
+			return "delete"
+		}
+		return "deletecollection"
+	default:
+		return strings.ToLower(req.Method)
+	}
+}
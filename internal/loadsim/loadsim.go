@@ -0,0 +1,343 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package loadsim creates synthetic workspaces against a cluster and measures how fast the
+// controller reconciles them, to validate changes to the controller scaling.
+package loadsim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// LabelRun is the label set on every object created by a simulation, to the ID of the simulation
+const LabelRun = "loadsim.workspace.jupyter.org/run"
+
+// Default simulation options
+const (
+	DefaultWorkspaces          = 100
+	DefaultNamespace           = "loadsim"
+	DefaultCreationConcurrency = 10
+	DefaultTimeout             = 10 * time.Minute
+	DefaultImage               = "jupyter/base-notebook:latest"
+)
+
+// Options configures a simulation
+type Options struct {
+	// RunID identifies the objects of the simulation, through LabelRun and their names
+	RunID string
+
+	// Namespace is where the workspaces, template and access strategy are created. Created if missing.
+	Namespace string
+
+	// Workspaces is the number of workspaces to create
+	Workspaces int
+
+	// CreationConcurrency is the number of workspaces created in parallel
+	CreationConcurrency int
+
+	// Timeout is how long to wait for the controller to reconcile all the workspaces
+	Timeout time.Duration
+
+	// Cleanup deletes the objects of the simulation once it completes
+	Cleanup bool
+
+	// APICalls counts the requests of the controller, when it runs in-process. Optional.
+	APICalls *APICallCounter
+}
+
+// tracker records the creation and first reconcile time of each workspace
+type tracker struct {
+	mu           sync.Mutex
+	createdAt    map[string]time.Time
+	reconciledAt map[string]time.Time
+	done         chan struct{}
+	total        int
+}
+
+func newTracker(total int) *tracker {
+	return &tracker{
+		createdAt:    make(map[string]time.Time, total),
+		reconciledAt: make(map[string]time.Time, total),
+		done:         make(chan struct{}),
+		total:        total,
+	}
+}
+
+func (t *tracker) created(name string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.createdAt[name] = at
+}
+
+// observe records the first time the controller reports a status for a workspace
+func (t *tracker) observe(workspace *workspacev1alpha1.Workspace, at time.Time) {
+	if len(workspace.Status.Conditions) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.createdAt[workspace.Name]; !ok {
+		return
+	}
+	if _, ok := t.reconciledAt[workspace.Name]; ok {
+		return
+	}
+	t.reconciledAt[workspace.Name] = at
+	if len(t.reconciledAt) == t.total {
+		close(t.done)
+	}
+}
+
+// Run creates the workspaces of the simulation, waits until the controller reconciled them or the
+// timeout expires, and reports the reconcile throughput and latency
+func Run(ctx context.Context, k8sClient client.WithWatch, opts Options) (*Report, error) {
+	if opts.RunID == "" {
+		return nil, errors.New("run ID must be set")
+	}
+	if opts.Workspaces <= 0 {
+		return nil, errors.New("number of workspaces must be positive")
+	}
+	opts.CreationConcurrency = max(opts.CreationConcurrency, 1)
+
+	if err := setUp(ctx, k8sClient, opts); err != nil {
+		return nil, err
+	}
+	if opts.Cleanup {
+		defer cleanUp(k8sClient, opts)
+	}
+
+	// Watch before creating workspaces, so that no first status is missed
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	t := newTracker(opts.Workspaces)
+	watcher, err := watchRun(waitCtx, k8sClient, opts)
+	if err != nil {
+		return nil, err
+	}
+	watchDone := make(chan error, 1)
+	go func() { watchDone <- watchWorkspaces(waitCtx, k8sClient, watcher, opts, t) }()
+
+	if opts.APICalls != nil {
+		opts.APICalls.Reset()
+	}
+	start := time.Now()
+	if err := createWorkspaces(ctx, k8sClient, opts, t); err != nil {
+		return nil, err
+	}
+	creationDuration := time.Since(start)
+
+	select {
+	case <-t.done:
+	case <-waitCtx.Done():
+	case err := <-watchDone:
+		if err != nil && waitCtx.Err() == nil {
+			return nil, err
+		}
+	}
+	cancel()
+
+	return buildReport(t, opts, start, creationDuration), nil
+}
+
+// setUp creates the namespace, template and access strategy of the simulation
+func setUp(ctx context.Context, k8sClient client.Client, opts Options) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}}
+	if err := k8sClient.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", opts.Namespace, err)
+	}
+
+	// Access resource templates are left empty so that the access strategy does not require any
+	// routing CRD in the cluster
+	strategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: objectMeta(opts, resourceName(opts, "strategy")),
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			DisplayName:             "Load simulation",
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{},
+		},
+	}
+	if err := k8sClient.Create(ctx, strategy); err != nil {
+		return fmt.Errorf("failed to create access strategy: %w", err)
+	}
+
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: objectMeta(opts, resourceName(opts, "template")),
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  "Load simulation",
+			DefaultImage: DefaultImage,
+		},
+	}
+	if err := k8sClient.Create(ctx, template); err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+// createWorkspaces creates the workspaces of the simulation, CreationConcurrency at a time
+func createWorkspaces(ctx context.Context, k8sClient client.Client, opts Options, t *tracker) error {
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, opts.CreationConcurrency)
+
+	for i := range opts.Workspaces {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			workspace := newWorkspace(opts, i)
+			t.created(workspace.Name, time.Now())
+			if err := k8sClient.Create(ctx, workspace); err != nil {
+				errOnce.Do(func() { firstErr = fmt.Errorf("failed to create workspace %s: %w", workspace.Name, err) })
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// newWorkspace returns the i-th workspace of the simulation
+func newWorkspace(opts Options, i int) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: objectMeta(opts, fmt.Sprintf("%s-%05d", resourceName(opts, "ws"), i)),
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:   fmt.Sprintf("Load simulation %d", i),
+			Image:         DefaultImage,
+			DesiredStatus: controller.DesiredStateRunning,
+			TemplateRef: &workspacev1alpha1.TemplateRef{
+				Name: resourceName(opts, "template"),
+			},
+			AccessStrategy: &workspacev1alpha1.AccessStrategyRef{
+				Name: resourceName(opts, "strategy"),
+			},
+		},
+	}
+}
+
+// watchRun watches the workspaces of the simulation
+func watchRun(ctx context.Context, k8sClient client.WithWatch, opts Options) (watch.Interface, error) {
+	watcher, err := k8sClient.Watch(ctx, &workspacev1alpha1.WorkspaceList{},
+		client.InNamespace(opts.Namespace), client.MatchingLabels{LabelRun: opts.RunID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch workspaces: %w", err)
+	}
+	return watcher, nil
+}
+
+// watchWorkspaces records the first status of the workspaces until all are reconciled or the
+// context is done, re-establishing the watch when the API server closes it
+func watchWorkspaces(ctx context.Context, k8sClient client.WithWatch, watcher watch.Interface, opts Options, t *tracker) error {
+	for {
+		consumeEvents(ctx, watcher, opts, t)
+		watcher.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.done:
+			return nil
+		default:
+		}
+
+		var err error
+		if watcher, err = watchRun(ctx, k8sClient, opts); err != nil {
+			return err
+		}
+	}
+}
+
+// consumeEvents records the workspaces of the events until the watch closes or the context is done
+func consumeEvents(ctx context.Context, watcher watch.Interface, opts Options, t *tracker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.done:
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			workspace, isWorkspace := event.Object.(*workspacev1alpha1.Workspace)
+			if !isWorkspace || workspace.Labels[LabelRun] != opts.RunID {
+				continue
+			}
+			if event.Type == watch.Added || event.Type == watch.Modified {
+				t.observe(workspace, time.Now())
+			}
+		}
+	}
+}
+
+// buildReport summarizes the tracked workspaces
+func buildReport(t *tracker, opts Options, start time.Time, creationDuration time.Duration) *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latencies := make([]time.Duration, 0, len(t.reconciledAt))
+	var last time.Time
+	for name, reconciledAt := range t.reconciledAt {
+		latencies = append(latencies, reconciledAt.Sub(t.createdAt[name]))
+		if reconciledAt.After(last) {
+			last = reconciledAt
+		}
+	}
+
+	report := &Report{
+		Workspaces:       opts.Workspaces,
+		Reconciled:       len(latencies),
+		CreationDuration: creationDuration,
+		Latency:          summarizeLatencies(latencies),
+	}
+	if len(latencies) > 0 {
+		report.Duration = last.Sub(start)
+		if report.Duration > 0 {
+			report.Throughput = float64(len(latencies)) / report.Duration.Seconds()
+		}
+	}
+	if opts.APICalls != nil {
+		report.APICalls = opts.APICalls.Snapshot()
+	}
+	return report
+}
+
+// cleanUp deletes the objects of the simulation. The controller removes the finalizers of the
+// workspaces, so their deletion is not awaited.
+func cleanUp(k8sClient client.Client, opts Options) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	selector := []client.DeleteAllOfOption{client.InNamespace(opts.Namespace), client.MatchingLabels{LabelRun: opts.RunID}}
+	_ = k8sClient.DeleteAllOf(ctx, &workspacev1alpha1.Workspace{}, selector...)
+	_ = k8sClient.DeleteAllOf(ctx, &workspacev1alpha1.WorkspaceTemplate{}, selector...)
+	_ = k8sClient.DeleteAllOf(ctx, &workspacev1alpha1.WorkspaceAccessStrategy{}, selector...)
+}
+
+func resourceName(opts Options, kind string) string {
+	return fmt.Sprintf("loadsim-%s-%s", opts.RunID, kind)
+}
+
+func objectMeta(opts Options, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: opts.Namespace,
+		Labels:    map[string]string{LabelRun: opts.RunID},
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package loadsim
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testNamespace = "loadsim-test"
+
+// getTestClient creates a fake controller-runtime client for testing
+func getTestClient() client.WithWatch {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = workspacev1alpha1.AddToScheme(scheme)
+	return fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+		Build()
+}
+
+// simulateController sets a condition on every workspace it sees created, like the controller
+// does on its first reconcile, until the context is done
+func simulateController(ctx context.Context, t *testing.T, k8sClient client.WithWatch, skip string) {
+	watcher, err := k8sClient.Watch(ctx, &workspacev1alpha1.WorkspaceList{}, client.InNamespace(testNamespace))
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	go func() {
+		defer watcher.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, open := <-watcher.ResultChan():
+				if !open {
+					return
+				}
+				workspace, ok := event.Object.(*workspacev1alpha1.Workspace)
+				if !ok || event.Type != watch.Added || workspace.Name == skip {
+					continue
+				}
+				workspace.Status.Conditions = []metav1.Condition{{
+					Type:               "Progressing",
+					Status:             metav1.ConditionTrue,
+					Reason:             "Starting",
+					LastTransitionTime: metav1.Now(),
+				}}
+				_ = k8sClient.Status().Update(ctx, workspace)
+			}
+		}
+	}()
+}
+
+func TestRunReportsReconciledWorkspaces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	k8sClient := getTestClient()
+	simulateController(ctx, t, k8sClient, "")
+
+	counter := NewAPICallCounter()
+	counter.record("get workspaces")
+	report, err := Run(ctx, k8sClient, Options{
+		RunID:               "1",
+		Namespace:           testNamespace,
+		Workspaces:          20,
+		CreationConcurrency: 4,
+		Timeout:             10 * time.Second,
+		APICalls:            counter,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Workspaces != 20 || report.Reconciled != 20 {
+		t.Errorf("Expected 20 reconciled workspaces out of 20, got %d out of %d", report.Reconciled, report.Workspaces)
+	}
+	if report.Throughput <= 0 {
+		t.Errorf("Expected a positive throughput, got %f", report.Throughput)
+	}
+	if report.Latency.Max < report.Latency.P50 {
+		t.Errorf("Expected max latency %s to be at least p50 %s", report.Latency.Max, report.Latency.P50)
+	}
+	if len(report.APICalls) != 0 {
+		t.Errorf("Expected the API calls made before the workspaces were created to be reset, got %v", report.APICalls)
+	}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := k8sClient.List(ctx, workspaces, client.MatchingLabels{LabelRun: "1"}); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(workspaces.Items) != 20 {
+		t.Errorf("Expected the workspaces to be kept without cleanup, got %d", len(workspaces.Items))
+	}
+	if ref := workspaces.Items[0].Spec.TemplateRef; ref == nil || ref.Name != "loadsim-1-template" {
+		t.Errorf("Expected the workspaces to reference the simulation template, got %v", ref)
+	}
+}
+
+func TestRunTimesOutOnUnreconciledWorkspaces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	k8sClient := getTestClient()
+	simulateController(ctx, t, k8sClient, "loadsim-2-ws-00001")
+
+	report, err := Run(ctx, k8sClient, Options{
+		RunID:      "2",
+		Namespace:  testNamespace,
+		Workspaces: 3,
+		Timeout:    500 * time.Millisecond,
+		Cleanup:    true,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Reconciled != 2 {
+		t.Errorf("Expected 2 reconciled workspaces, got %d", report.Reconciled)
+	}
+	if report.APICalls != nil {
+		t.Errorf("Expected no API calls without an in-process controller, got %v", report.APICalls)
+	}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := k8sClient.List(ctx, workspaces, client.MatchingLabels{LabelRun: "2"}); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(workspaces.Items) != 0 {
+		t.Errorf("Expected the workspaces to be cleaned up, got %d", len(workspaces.Items))
+	}
+}
+
+func TestRunValidatesOptions(t *testing.T) {
+	if _, err := Run(context.Background(), getTestClient(), Options{Workspaces: 1}); err == nil {
+		t.Error("Expected an error without a run ID")
+	}
+	if _, err := Run(context.Background(), getTestClient(), Options{RunID: "3"}); err == nil {
+		t.Error("Expected an error without workspaces")
+	}
+}
+
+func TestSummarizeLatencies(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := summarizeLatencies(latencies)
+
+	expected := LatencySummary{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}
+	if summary != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summary)
+	}
+	if summarizeLatencies(nil) != (LatencySummary{}) {
+		t.Error("Expected an empty summary without latencies")
+	}
+}
+
+func TestAPICallCounter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	counter := NewAPICallCounter()
+	httpClient, err := rest.HTTPClientFor(counter.Wrap(&rest.Config{Host: server.URL}))
+	if err != nil {
+		t.Fatalf("HTTPClientFor failed: %v", err)
+	}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/apis/workspace.jupyter.org/v1alpha1/namespaces/team-a/workspaces/ws1"},
+		{http.MethodGet, "/apis/workspace.jupyter.org/v1alpha1/workspaces"},
+		{http.MethodGet, "/apis/workspace.jupyter.org/v1alpha1/namespaces/team-a/workspaces?watch=true"},
+		{http.MethodPatch, "/apis/workspace.jupyter.org/v1alpha1/namespaces/team-a/workspaces/ws1/status"},
+		{http.MethodPatch, "/apis/workspace.jupyter.org/v1alpha1/namespaces/team-a/workspaces/ws2/status"},
+		{http.MethodPost, "/apis/apps/v1/namespaces/team-a/deployments"},
+		{http.MethodPut, "/api/v1/namespaces/team-a/services/ws1"},
+		{http.MethodDelete, "/api/v1/namespaces/team-a/pods"},
+		{http.MethodGet, "/api/v1/namespaces/team-a"},
+	}
+	for _, request := range requests {
+		req, _ := http.NewRequest(request.method, server.URL+request.path, nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	expected := map[string]int{
+		"get workspaces":          1,
+		"list workspaces":         1,
+		"watch workspaces":        1,
+		"patch workspaces/status": 2,
+		"create deployments":      1,
+		"update services":         1,
+		"deletecollection pods":   1,
+		"get namespaces":          1,
+	}
+	counts := counter.Snapshot()
+	for key, count := range expected {
+		if counts[key] != count {
+			t.Errorf("Expected %d %q calls, got %d (all calls: %v)", count, key, counts[key], counts)
+		}
+	}
+
+	counter.Reset()
+	if len(counter.Snapshot()) != 0 {
+		t.Error("Expected no calls after reset")
+	}
+}
+
+func TestReportWriteText(t *testing.T) {
+	report := &Report{
+		Workspaces: 10,
+		Reconciled: 10,
+		Throughput: 2.5,
+		APICalls:   map[string]int{"patch workspaces/status": 20, "create deployments": 10},
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+
+	text := buf.String()
+	for _, expected := range []string{"Throughput:        2.50 workspaces/s", "API calls:         30", "API calls per workspace: 3.0"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, text)
+		}
+	}
+	if strings.Index(text, "create deployments") > strings.Index(text, "patch workspaces/status") {
+		t.Errorf("Expected API calls to be sorted, got:\n%s", text)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package loadsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Report summarizes a load simulation
+type Report struct {
+	// Workspaces is the number of workspaces created
+	Workspaces int `json:"workspaces"`
+
+	// Reconciled is the number of workspaces the controller reported a status for before the timeout
+	Reconciled int `json:"reconciled"`
+
+	// CreationDuration is how long it took to create all the workspaces
+	CreationDuration time.Duration `json:"creationDuration"`
+
+	// Duration is the time from the first workspace creation to the last first reconcile
+	Duration time.Duration `json:"duration"`
+
+	// Throughput is the number of workspaces reconciled per second over Duration
+	Throughput float64 `json:"throughput"`
+
+	// Latency is the distribution of the time from the creation of a workspace to its first status
+	Latency LatencySummary `json:"latency"`
+
+	// APICalls counts the controller requests to the API server by verb and resource.
+	// Nil when the controller does not run in-process.
+	APICalls map[string]int `json:"apiCalls,omitempty"`
+}
+
+// LatencySummary is a summary of a latency distribution
+type LatencySummary struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// summarizeLatencies computes the percentiles of latencies using the nearest-rank method
+func summarizeLatencies(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) time.Duration {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		return sorted[max(rank, 1)-1]
+	}
+	return LatencySummary{
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+// TotalAPICalls returns the total number of controller requests to the API server
+func (r *Report) TotalAPICalls() int {
+	total := 0
+	for _, count := range r.APICalls {
+		total += count
+	}
+	return total
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the report in a human-readable form
+func (r *Report) WriteText(w io.Writer) error {
+	lines := []string{
+		fmt.Sprintf("Workspaces:        %d", r.Workspaces),
+		fmt.Sprintf("Reconciled:        %d", r.Reconciled),
+		fmt.Sprintf("Creation duration: %s", r.CreationDuration.Round(time.Millisecond)),
+		fmt.Sprintf("Duration:          %s", r.Duration.Round(time.Millisecond)),
+		fmt.Sprintf("Throughput:        %.2f workspaces/s", r.Throughput),
+		fmt.Sprintf("Latency p50:       %s", r.Latency.P50.Round(time.Millisecond)),
+		fmt.Sprintf("Latency p90:       %s", r.Latency.P90.Round(time.Millisecond)),
+		fmt.Sprintf("Latency p99:       %s", r.Latency.P99.Round(time.Millisecond)),
+		fmt.Sprintf("Latency max:       %s", r.Latency.Max.Round(time.Millisecond)),
+	}
+
+	if r.APICalls != nil {
+		keys := make([]string, 0, len(r.APICalls))
+		for key := range r.APICalls {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		lines = append(lines, fmt.Sprintf("API calls:         %d", r.TotalAPICalls()))
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("  %-40s %d", key, r.APICalls[key]))
+		}
+		if r.Reconciled > 0 {
+			lines = append(lines, fmt.Sprintf("API calls per workspace: %.1f", float64(r.TotalAPICalls())/float64(r.Reconciled)))
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package metricsauth builds the options of the controller-runtime metrics server of the
+// operator binaries, so that their metrics endpoints can require authentication on
+// clusters where they are reachable by tenants.
+package metricsauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Authentication modes of the metrics endpoint
+const (
+	// AuthToken authenticates bearer tokens with a TokenReview and authorizes them
+	// with a SubjectAccessReview for the non-resource URL of the request
+	AuthToken = "token"
+	// AuthMTLS requires a client certificate signed by the client CA
+	AuthMTLS = "mtls"
+	// AuthNone serves the metrics endpoint without authentication
+	AuthNone = "none"
+)
+
+// Options configures the metrics server
+type Options struct {
+	// BindAddress is the address the metrics endpoint binds to, "0" to disable it
+	BindAddress string
+	// Secure serves the metrics endpoint over HTTPS
+	Secure bool
+	// Auth is the authentication mode. When empty, it defaults to AuthToken over HTTPS
+	// and AuthNone over HTTP.
+	Auth string
+	// ClientCAFile is the PEM bundle of the CAs signing the client certificates of AuthMTLS
+	ClientCAFile string
+	// CertDir, CertName and KeyName locate the serving certificate. When CertDir is empty,
+	// controller-runtime generates a self-signed certificate.
+	CertDir  string
+	CertName string
+	KeyName  string
+	// TLSOpts are applied to the TLS configuration of the server
+	TLSOpts []func(*tls.Config)
+}
+
+// ResolveAuth returns the authentication mode the options select
+func (o Options) ResolveAuth() string {
+	if o.Auth != "" {
+		return o.Auth
+	}
+	if o.Secure {
+		return AuthToken
+	}
+	return AuthNone
+}
+
+// Validate checks that the authentication mode is known and consistent with the other options
+func (o Options) Validate() error {
+	auth := o.ResolveAuth()
+	switch auth {
+	case AuthToken, AuthMTLS:
+		if !o.Secure {
+			return fmt.Errorf("metrics authentication %q requires serving metrics over HTTPS", auth)
+		}
+	case AuthNone:
+	default:
+		return fmt.Errorf("unknown metrics authentication %q, must be one of %s, %s or %s", auth, AuthToken, AuthMTLS, AuthNone)
+	}
+
+	if auth == AuthMTLS && o.ClientCAFile == "" {
+		return errors.New("metrics authentication mtls requires a client CA file")
+	}
+	if auth != AuthMTLS && o.ClientCAFile != "" {
+		return fmt.Errorf("a metrics client CA file requires metrics authentication mtls, got %q", auth)
+	}
+	return nil
+}
+
+// ServerOptions returns the metrics server options implementing the authentication mode
+func ServerOptions(o Options) (metricsserver.Options, error) {
+	if err := o.Validate(); err != nil {
+		return metricsserver.Options{}, err
+	}
+
+	serverOptions := metricsserver.Options{
+		BindAddress:   o.BindAddress,
+		SecureServing: o.Secure,
+		TLSOpts:       o.TLSOpts,
+		CertDir:       o.CertDir,
+		CertName:      o.CertName,
+		KeyName:       o.KeyName,
+	}
+
+	switch o.ResolveAuth() {
+	case AuthToken:
+		// Only authorized users and service accounts can access the metrics endpoint. More info:
+		// https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/metrics/filters#WithAuthenticationAndAuthorization
+		serverOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	case AuthMTLS:
+		clientCAs, err := loadClientCAs(o.ClientCAFile)
+		if err != nil {
+			return metricsserver.Options{}, err
+		}
+		// Copy the slice so the caller's TLS options, shared with other servers, are left untouched
+		serverOptions.TLSOpts = append(append([]func(*tls.Config){}, o.TLSOpts...), func(c *tls.Config) {
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+			c.ClientCAs = clientCAs
+		})
+	}
+	return serverOptions, nil
+}
+
+// loadClientCAs reads the PEM bundle of client CAs at path
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("metrics client CA file %s contains no PEM certificate", path)
+	}
+	return pool, nil
+}
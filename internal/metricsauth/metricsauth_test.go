@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package metricsauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCA writes a self-signed CA certificate in PEM to a temporary file and returns its path
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "metrics-client-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return path
+}
+
+func TestResolveAuth(t *testing.T) {
+	assert.Equal(t, AuthToken, Options{Secure: true}.ResolveAuth())
+	assert.Equal(t, AuthNone, Options{}.ResolveAuth())
+	assert.Equal(t, AuthMTLS, Options{Secure: true, Auth: AuthMTLS}.ResolveAuth())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     Options
+		expectError bool
+	}{
+		{name: "default over HTTPS", options: Options{Secure: true}},
+		{name: "default over HTTP", options: Options{}},
+		{name: "none over HTTPS", options: Options{Secure: true, Auth: AuthNone}},
+		{name: "mtls with a client CA", options: Options{Secure: true, Auth: AuthMTLS, ClientCAFile: "/ca.crt"}},
+		{name: "token over HTTP", options: Options{Auth: AuthToken}, expectError: true},
+		{name: "mtls over HTTP", options: Options{Auth: AuthMTLS, ClientCAFile: "/ca.crt"}, expectError: true},
+		{name: "mtls without a client CA", options: Options{Secure: true, Auth: AuthMTLS}, expectError: true},
+		{name: "client CA without mtls", options: Options{Secure: true, ClientCAFile: "/ca.crt"}, expectError: true},
+		{name: "unknown mode", options: Options{Secure: true, Auth: "basic"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServerOptions_Token(t *testing.T) {
+	serverOptions, err := ServerOptions(Options{BindAddress: ":8443", Secure: true, CertDir: "/certs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8443", serverOptions.BindAddress)
+	assert.True(t, serverOptions.SecureServing)
+	assert.Equal(t, "/certs", serverOptions.CertDir)
+	assert.NotNil(t, serverOptions.FilterProvider)
+}
+
+func TestServerOptions_None(t *testing.T) {
+	serverOptions, err := ServerOptions(Options{BindAddress: ":8080"})
+	require.NoError(t, err)
+
+	assert.False(t, serverOptions.SecureServing)
+	assert.Nil(t, serverOptions.FilterProvider)
+}
+
+func TestServerOptions_MTLS(t *testing.T) {
+	disableHTTP2 := func(c *tls.Config) { c.NextProtos = []string{"http/1.1"} }
+	tlsOpts := []func(*tls.Config){disableHTTP2}
+
+	serverOptions, err := ServerOptions(Options{
+		BindAddress:  ":8443",
+		Secure:       true,
+		Auth:         AuthMTLS,
+		ClientCAFile: writeTestCA(t),
+		TLSOpts:      tlsOpts,
+	})
+	require.NoError(t, err)
+
+	assert.Nil(t, serverOptions.FilterProvider)
+	assert.Len(t, tlsOpts, 1, "the caller's TLS options must not be modified")
+	require.Len(t, serverOptions.TLSOpts, 2)
+
+	config := &tls.Config{}
+	for _, opt := range serverOptions.TLSOpts {
+		opt(config)
+	}
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+	assert.Equal(t, []string{"http/1.1"}, config.NextProtos)
+}
+
+func TestServerOptions_MTLSInvalidClientCA(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+	_, err := ServerOptions(Options{Secure: true, Auth: AuthMTLS, ClientCAFile: path})
+	assert.ErrorContains(t, err, "contains no PEM certificate")
+
+	_, err = ServerOptions(Options{Secure: true, Auth: AuthMTLS, ClientCAFile: filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorContains(t, err, "failed to read metrics client CA file")
+}
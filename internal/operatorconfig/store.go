@@ -0,0 +1,127 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package operatorconfig holds the effective settings of the operator: the values of the manager
+// flags, overridden by the OperatorConfig resource of the controller namespace as it changes.
+package operatorconfig
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultName is the default name of the OperatorConfig read by the operator
+const DefaultName = "default"
+
+// Settings are the operator settings an OperatorConfig may change at runtime
+type Settings struct {
+	// ApplicationImagesRegistry is the prefix of the application images without a registry
+	ApplicationImagesRegistry string
+
+	// ApplicationImagesPullPolicy is the image pull policy of the workspace containers
+	ApplicationImagesPullPolicy corev1.PullPolicy
+
+	// ApplicationImagesPullSecret is the name of a Secret added to the image pull secrets of every
+	// workspace pod
+	ApplicationImagesPullSecret string
+
+	// RequireTemplate rejects the workspaces users create without a templateRef
+	RequireTemplate bool
+
+	// IdleCheckInterval is the interval between the idle checks of running workspaces
+	IdleCheckInterval time.Duration
+
+	// ResourceWatches are the resource kinds the controller watches for access resources
+	ResourceWatches []schema.GroupVersionKind
+}
+
+// Store holds the effective settings of the operator. It is safe for concurrent use.
+type Store struct {
+	// defaults are the settings of the manager flags
+	defaults Settings
+
+	mu          sync.RWMutex
+	settings    Settings
+	subscribers []chan struct{}
+}
+
+// NewStore creates a Store whose settings are the defaults until an OperatorConfig is applied
+func NewStore(defaults Settings) *Store {
+	return &Store{defaults: defaults, settings: defaults}
+}
+
+// Settings returns the effective settings
+func (s *Store) Settings() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings
+}
+
+// Apply makes the settings of an OperatorConfig spec effective, on top of the defaults, and returns
+// them. A nil spec restores the defaults, e.g. once the OperatorConfig is deleted.
+func (s *Store) Apply(spec *workspacev1alpha1.OperatorConfigSpec) Settings {
+	settings := Resolve(s.defaults, spec)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = settings
+	for _, subscriber := range s.subscribers {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+		}
+	}
+	return settings
+}
+
+// Subscribe returns a channel that receives a value once the settings are applied. Applies made
+// while a value is pending are coalesced into it, so that subscribers only read the latest settings.
+func (s *Store) Subscribe() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscriber := make(chan struct{}, 1)
+	s.subscribers = append(s.subscribers, subscriber)
+	return subscriber
+}
+
+// Resolve overrides the defaults with the fields set in an OperatorConfig spec. Resource watches
+// are added to those of the defaults.
+func Resolve(defaults Settings, spec *workspacev1alpha1.OperatorConfigSpec) Settings {
+	settings := defaults
+	settings.ResourceWatches = slices.Clone(defaults.ResourceWatches)
+	if spec == nil {
+		return settings
+	}
+
+	if images := spec.ApplicationImages; images != nil {
+		if images.Registry != "" {
+			settings.ApplicationImagesRegistry = images.Registry
+		}
+		if images.PullPolicy != "" {
+			settings.ApplicationImagesPullPolicy = images.PullPolicy
+		}
+		if images.PullSecret != "" {
+			settings.ApplicationImagesPullSecret = images.PullSecret
+		}
+	}
+	if spec.RequireTemplate != nil {
+		settings.RequireTemplate = *spec.RequireTemplate
+	}
+	if spec.IdleCheckInterval != nil && spec.IdleCheckInterval.Duration > 0 {
+		settings.IdleCheckInterval = spec.IdleCheckInterval.Duration
+	}
+	for _, watch := range spec.WatchResources {
+		gvk := schema.GroupVersionKind{Group: watch.Group, Version: watch.Version, Kind: watch.Kind}
+		if !slices.Contains(settings.ResourceWatches, gvk) {
+			settings.ResourceWatches = append(settings.ResourceWatches, gvk)
+		}
+	}
+	return settings
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package operatorconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var (
+	testRouteGVK   = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+	testIngressGVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+)
+
+func newTestDefaults() Settings {
+	return Settings{
+		ApplicationImagesRegistry:   "docker.io/library",
+		ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		IdleCheckInterval:           5 * time.Minute,
+		ResourceWatches:             []schema.GroupVersionKind{testIngressGVK},
+	}
+}
+
+func TestResolveWithoutSpecKeepsDefaults(t *testing.T) {
+	defaults := newTestDefaults()
+	assert.Equal(t, defaults, Resolve(defaults, nil))
+	assert.Equal(t, defaults, Resolve(defaults, &workspacev1alpha1.OperatorConfigSpec{}))
+}
+
+func TestResolveOverridesSetFields(t *testing.T) {
+	defaults := newTestDefaults()
+	settings := Resolve(defaults, &workspacev1alpha1.OperatorConfigSpec{
+		ApplicationImages: &workspacev1alpha1.ApplicationImagesConfig{
+			Registry:   "registry.example.com/notebooks",
+			PullSecret: "registry-credentials",
+		},
+		RequireTemplate:   ptr.To(true),
+		IdleCheckInterval: &metav1.Duration{Duration: 2 * time.Minute},
+		WatchResources: []workspacev1alpha1.WatchedResource{
+			{Group: testRouteGVK.Group, Version: testRouteGVK.Version, Kind: testRouteGVK.Kind},
+			{Group: testIngressGVK.Group, Version: testIngressGVK.Version, Kind: testIngressGVK.Kind},
+		},
+	})
+
+	assert.Equal(t, Settings{
+		ApplicationImagesRegistry:   "registry.example.com/notebooks",
+		ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		ApplicationImagesPullSecret: "registry-credentials",
+		RequireTemplate:             true,
+		IdleCheckInterval:           2 * time.Minute,
+		ResourceWatches:             []schema.GroupVersionKind{testIngressGVK, testRouteGVK},
+	}, settings)
+	assert.Equal(t, []schema.GroupVersionKind{testIngressGVK}, defaults.ResourceWatches,
+		"the watches of the defaults are not modified")
+}
+
+func TestStoreApplyRestoresDefaults(t *testing.T) {
+	store := NewStore(newTestDefaults())
+	store.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+	assert.True(t, store.Settings().RequireTemplate)
+
+	store.Apply(nil)
+	assert.Equal(t, newTestDefaults(), store.Settings())
+}
+
+func TestStoreSubscribeCoalescesApplies(t *testing.T) {
+	store := NewStore(newTestDefaults())
+	changes := store.Subscribe()
+	assert.Empty(t, changes)
+
+	store.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+	store.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(false)})
+	assert.Len(t, changes, 1)
+
+	<-changes
+	assert.Empty(t, changes)
+}
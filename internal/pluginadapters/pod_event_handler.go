@@ -16,12 +16,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// RemoteAccessInfo describes the remote access a plugin set up for a pod
+type RemoteAccessInfo struct {
+	// InstanceID identifies the pod with the remote access service (e.g. an SSM managed node ID).
+	// Empty when the plugin could not determine it.
+	InstanceID string
+}
+
 // PodEventPluginAdapter defines the interface for handling pod lifecycle events.
 // Implementations receive a resolved context map (with all dynamic values
 // like controller::PodUid() already substituted).
+// HandlePodRunning returns nil info and no error while the setup is pending,
+// e.g. when the containers are not running yet or another setup is in progress.
+// Errors are retried by the caller with backoff, so implementations must be idempotent.
+// Cleanup of deleted pods is handled separately by a RemoteAccessCleaner.
 type PodEventPluginAdapter interface {
-	HandlePodRunning(ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string) error
-	HandlePodDeleted(ctx context.Context, pod *corev1.Pod, podEventsContext map[string]string) error
+	HandlePodRunning(
+		ctx context.Context, pod *corev1.Pod, workspaceName, namespace string, podEventsContext map[string]string,
+	) (*RemoteAccessInfo, error)
 }
 
 // ResolvePodContext resolves dynamic values in the pod events context map.
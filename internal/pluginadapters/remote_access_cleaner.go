@@ -0,0 +1,35 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package pluginadapters
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// RemoteAccessCleaner releases the resources a plugin registered for the remote access
+// of a pod (e.g. an SSM managed node activation) once the pod is deleted.
+// It is only invoked for pods the controller marked as managed by the plugin,
+// so implementations may assume the pod went through HandlePodRunning.
+// podEventsContext is nil when the access strategy of the pod no longer exists.
+type RemoteAccessCleaner interface {
+	CleanupRemoteAccess(ctx context.Context, pod *corev1.Pod, podEventsContext map[string]string) error
+}
+
+// NoopRemoteAccessCleaner is a dry-run RemoteAccessCleaner: it logs the cleanup
+// it would perform and leaves the remote access resources in place.
+type NoopRemoteAccessCleaner struct{}
+
+var _ RemoteAccessCleaner = NoopRemoteAccessCleaner{}
+
+// CleanupRemoteAccess logs the pod whose remote access resources would be released
+func (NoopRemoteAccessCleaner) CleanupRemoteAccess(ctx context.Context, pod *corev1.Pod, _ map[string]string) error {
+	logf.FromContext(ctx).Info("Dry run: skipping remote access cleanup",
+		"pod", pod.Name, "namespace", pod.Namespace, "podUID", pod.UID)
+	return nil
+}
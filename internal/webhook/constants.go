@@ -10,6 +10,7 @@ package webhook
 const (
 	OwnershipTypeOwnerOnly = "OwnerOnly"
 	OwnershipTypePublic    = "Public"
+	OwnershipTypeGroupOnly = "GroupOnly"
 )
 
 // Admin group constants
@@ -6,89 +6,166 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // AccessStrategyValidator handles access strategy namespace validation for webhooks
 type AccessStrategyValidator struct {
 	sharedNamespace string
+
+	// namespaceReader and trustedNamespaceSelector extend the trust policy to every namespace whose
+	// labels match the selector. A nil selector trusts only the referrer's and the shared namespace.
+	namespaceReader          client.Reader
+	trustedNamespaceSelector labels.Selector
 }
 
-// NewAccessStrategyValidator creates a new AccessStrategyValidator
-func NewAccessStrategyValidator(sharedNamespace string) *AccessStrategyValidator {
+// NewAccessStrategyValidator creates a new AccessStrategyValidator.
+// trustedNamespaceSelector may be nil, in which case no labeled namespace is trusted.
+func NewAccessStrategyValidator(
+	sharedNamespace string,
+	namespaceReader client.Reader,
+	trustedNamespaceSelector labels.Selector,
+) *AccessStrategyValidator {
 	return &AccessStrategyValidator{
-		sharedNamespace: sharedNamespace,
+		sharedNamespace:          sharedNamespace,
+		namespaceReader:          namespaceReader,
+		trustedNamespaceSelector: trustedNamespaceSelector,
 	}
 }
 
-// validateNamespaceScope checks that an access strategy reference targets an allowed namespace.
+// validateNamespaceScope checks that an access strategy reference targets a trusted namespace.
 // Both workspaces and templates may only reference access strategies from the referrer's own
-// namespace or the configured shared namespace. referrerKind ("workspace" / "template") only
-// shapes the error message; the rules are identical.
-func (v *AccessStrategyValidator) validateNamespaceScope(asNamespace, referrerNamespace, referrerKind string) error {
+// namespace, the configured shared namespace, or a namespace matching the trusted namespace
+// selector. This prevents a tenant from pointing at another tenant's strategy to pick up its
+// routing configuration. referrerKind ("workspace" / "template") only shapes the error message;
+// the rules are identical.
+func (v *AccessStrategyValidator) validateNamespaceScope(
+	ctx context.Context, asNamespace, referrerNamespace, referrerKind string) error {
 	if asNamespace == "" || asNamespace == referrerNamespace {
 		return nil
 	}
 
-	if v.sharedNamespace == "" {
-		return fmt.Errorf(
-			"accessStrategy.namespace %q is not allowed: access strategies must be in the %s namespace %q",
-			asNamespace, referrerKind, referrerNamespace,
-		)
+	if v.sharedNamespace != "" && asNamespace == v.sharedNamespace {
+		return nil
 	}
 
-	if asNamespace == v.sharedNamespace {
+	trusted, err := v.isTrustedNamespace(ctx, asNamespace)
+	if err != nil {
+		return err
+	}
+	if trusted {
 		return nil
 	}
 
-	return fmt.Errorf(
-		"accessStrategy.namespace %q is not allowed: access strategies must be in the %s namespace %q or the shared namespace %q",
-		asNamespace, referrerKind, referrerNamespace, v.sharedNamespace,
-	)
+	allowed := fmt.Sprintf("the %s namespace %q", referrerKind, referrerNamespace)
+	if v.sharedNamespace != "" {
+		allowed += fmt.Sprintf(" or the shared namespace %q", v.sharedNamespace)
+	}
+	if v.trustedNamespaceSelector != nil {
+		allowed += fmt.Sprintf(" or a namespace labeled %q", v.trustedNamespaceSelector.String())
+	}
+	return fmt.Errorf("accessStrategy.namespace %q is not allowed: access strategies must be in %s", asNamespace, allowed)
+}
+
+// isTrustedNamespace returns true when the namespace's labels match the trusted namespace selector.
+// A missing namespace is not trusted.
+func (v *AccessStrategyValidator) isTrustedNamespace(ctx context.Context, namespace string) (bool, error) {
+	if v.trustedNamespaceSelector == nil || v.trustedNamespaceSelector.Empty() || v.namespaceReader == nil {
+		return false, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := v.namespaceReader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %q to verify access strategy trust: %w", namespace, err)
+	}
+	return v.trustedNamespaceSelector.Matches(labels.Set(ns.Labels)), nil
 }
 
 // validateAccessStrategyNamespace checks that accessStrategy.namespace targets an allowed namespace.
-// Workspaces can only reference access strategies from their own namespace or the shared namespace.
-func (v *AccessStrategyValidator) validateAccessStrategyNamespace(workspace *workspacev1alpha1.Workspace) error {
+// Workspaces can only reference access strategies from their own namespace, the shared namespace
+// or a trusted namespace.
+func (v *AccessStrategyValidator) validateAccessStrategyNamespace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
 	if workspace.Spec.AccessStrategy == nil {
 		return nil
 	}
-	return v.validateNamespaceScope(workspace.Spec.AccessStrategy.Namespace, workspace.Namespace, "workspace")
+	return v.validateNamespaceScope(ctx, workspace.Spec.AccessStrategy.Namespace, workspace.Namespace, "workspace")
 }
 
 // validateTemplateAccessStrategyNamespace checks that a template's defaultAccessStrategy.namespace
 // targets an allowed namespace. Templates can only reference access strategies from their own
-// namespace or the shared namespace — the same rule workspaces are subject to. Enforcing it here
+// namespace, the shared namespace or a trusted namespace — the same rule workspaces are subject to. Enforcing it here
 // prevents admins from creating templates that would make any referencing workspace un-admittable.
-func (v *AccessStrategyValidator) validateTemplateAccessStrategyNamespace(template *workspacev1alpha1.WorkspaceTemplate) error {
+func (v *AccessStrategyValidator) validateTemplateAccessStrategyNamespace(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
 	if template.Spec.DefaultAccessStrategy == nil {
 		return nil
 	}
-	return v.validateNamespaceScope(template.Spec.DefaultAccessStrategy.Namespace, template.Namespace, "template")
+	return v.validateNamespaceScope(ctx, template.Spec.DefaultAccessStrategy.Namespace, template.Namespace, "template")
+}
+
+// validateMigrationTargetNamespace checks that the access strategy a workspace requests to migrate
+// to, through its migration annotation, targets an allowed namespace. The controller switches
+// spec.accessStrategy to the target itself, so the target is held to the same rule here.
+func (v *AccessStrategyValidator) validateMigrationTargetNamespace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	target := controller.AccessStrategyMigrationTargetRef(workspace)
+	if target == nil {
+		return nil
+	}
+	if err := v.validateNamespaceScope(ctx, target.Namespace, workspace.Namespace, "workspace"); err != nil {
+		return fmt.Errorf("annotation %s: %w", controller.AnnotationAccessStrategyMigrationTarget, err)
+	}
+	return nil
+}
+
+// validateAccessStrategyProvider checks that spec.provider names an access provider compiled into
+// the controller. An empty provider selects the "template" provider.
+func validateAccessStrategyProvider(accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) error {
+	providers := controller.DefaultAccessProviders()
+	if _, err := providers.Get(accessStrategy.Spec.Provider); err != nil {
+		return fmt.Errorf("spec.provider: %w, must be one of %s",
+			err, strings.Join(providers.Names(), ", "))
+	}
+	return nil
 }
 
 // ValidateCreateWorkspace validates access strategy namespace on workspace creation
-func (v *AccessStrategyValidator) ValidateCreateWorkspace(workspace *workspacev1alpha1.Workspace) error {
-	return v.validateAccessStrategyNamespace(workspace)
+func (v *AccessStrategyValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if err := v.validateAccessStrategyNamespace(ctx, workspace); err != nil {
+		return err
+	}
+	return v.validateMigrationTargetNamespace(ctx, workspace)
 }
 
 // ValidateUpdateWorkspace validates access strategy namespace on workspace update.
 // No special-casing needed — validateAccessStrategyNamespace already handles nil accessStrategy,
 // which covers the "removed" case. The admission webhook is the single enforcement point.
-func (v *AccessStrategyValidator) ValidateUpdateWorkspace(_, newWorkspace *workspacev1alpha1.Workspace) error {
-	return v.validateAccessStrategyNamespace(newWorkspace)
+func (v *AccessStrategyValidator) ValidateUpdateWorkspace(ctx context.Context, _, newWorkspace *workspacev1alpha1.Workspace) error {
+	if err := v.validateAccessStrategyNamespace(ctx, newWorkspace); err != nil {
+		return err
+	}
+	return v.validateMigrationTargetNamespace(ctx, newWorkspace)
 }
 
 // ValidateCreateTemplate validates the access strategy namespace on template creation.
-func (v *AccessStrategyValidator) ValidateCreateTemplate(template *workspacev1alpha1.WorkspaceTemplate) error {
-	return v.validateTemplateAccessStrategyNamespace(template)
+func (v *AccessStrategyValidator) ValidateCreateTemplate(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	return v.validateTemplateAccessStrategyNamespace(ctx, template)
 }
 
 // ValidateUpdateTemplate validates the access strategy namespace on template update.
 // Like the workspace case, nil defaultAccessStrategy is handled (covers the "removed" case),
 // so removing the reference is always allowed.
-func (v *AccessStrategyValidator) ValidateUpdateTemplate(_, newTemplate *workspacev1alpha1.WorkspaceTemplate) error {
-	return v.validateTemplateAccessStrategyNamespace(newTemplate)
+func (v *AccessStrategyValidator) ValidateUpdateTemplate(ctx context.Context, _, newTemplate *workspacev1alpha1.WorkspaceTemplate) error {
+	return v.validateTemplateAccessStrategyNamespace(ctx, newTemplate)
 }
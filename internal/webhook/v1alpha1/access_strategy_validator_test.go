@@ -6,17 +6,25 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 var _ = Describe("AccessStrategyValidator", func() {
 	Context("Namespace scope validation", func() {
 		It("should reject accessStrategy targeting another team's namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -28,7 +36,7 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamA))
@@ -36,7 +44,7 @@ var _ = Describe("AccessStrategyValidator", func() {
 		})
 
 		It("should allow accessStrategy targeting the workspace's own namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -48,12 +56,12 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should allow accessStrategy targeting the shared namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -65,12 +73,12 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should allow accessStrategy with empty namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -81,12 +89,12 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should reject cross-namespace when no shared namespace is configured", func() {
-			validator := NewAccessStrategyValidator("")
+			validator := NewAccessStrategyValidator("", nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -98,7 +106,7 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testSharedNamespace))
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamA))
@@ -106,21 +114,21 @@ var _ = Describe("AccessStrategyValidator", func() {
 		})
 
 		It("should skip validation when workspace has no accessStrategy", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			workspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
 				Spec:       workspacev1alpha1.WorkspaceSpec{},
 			}
 
-			err := validator.ValidateCreateWorkspace(workspace)
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
 
 	Context("Update validation", func() {
 		It("should validate when accessStrategyRef is added", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			oldWorkspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -136,13 +144,13 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace)
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
 		})
 
 		It("should skip validation when accessStrategyRef is removed", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			oldWorkspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -158,12 +166,12 @@ var _ = Describe("AccessStrategyValidator", func() {
 				Spec:       workspacev1alpha1.WorkspaceSpec{},
 			}
 
-			err := validator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace)
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should validate when accessStrategyRef namespace changes", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			oldWorkspace := &workspacev1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
@@ -184,10 +192,43 @@ var _ = Describe("AccessStrategyValidator", func() {
 				},
 			}
 
-			err := validator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace)
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
+		})
+	})
+
+	Context("Migration target validation", func() {
+		newMigratingWorkspace := func(target string) *workspacev1alpha1.Workspace {
+			return &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ws",
+					Namespace:   testNamespaceTeamA,
+					Annotations: map[string]string{controller.AnnotationAccessStrategyMigrationTarget: target},
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					AccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: testSomeStrategy},
+				},
+			}
+		}
+
+		It("should reject a migration target in another team's namespace", func() {
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
+
+			workspace := newMigratingWorkspace(testNamespaceTeamB + "/gateway")
+			err := validator.ValidateUpdateWorkspace(ctx, workspace, workspace)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
 		})
+
+		It("should allow migration targets in the workspace and shared namespaces", func() {
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
+
+			for _, target := range []string{"gateway", testNamespaceTeamA + "/gateway", testSharedNamespace + "/gateway"} {
+				workspace := newMigratingWorkspace(target)
+				Expect(validator.ValidateCreateWorkspace(ctx, workspace)).To(Succeed(), "target %q", target)
+			}
+		})
 	})
 
 	Context("Template namespace scope validation", func() {
@@ -205,9 +246,9 @@ var _ = Describe("AccessStrategyValidator", func() {
 		}
 
 		It("should reject defaultAccessStrategy targeting another team's namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
-			err := validator.ValidateCreateTemplate(templateWithAS(testNamespaceTeamB))
+			err := validator.ValidateCreateTemplate(ctx, templateWithAS(testNamespaceTeamB))
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamA))
@@ -216,30 +257,30 @@ var _ = Describe("AccessStrategyValidator", func() {
 		})
 
 		It("should allow defaultAccessStrategy targeting the template's own namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
-			err := validator.ValidateCreateTemplate(templateWithAS(testNamespaceTeamA))
+			err := validator.ValidateCreateTemplate(ctx, templateWithAS(testNamespaceTeamA))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should allow defaultAccessStrategy targeting the shared namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
-			err := validator.ValidateCreateTemplate(templateWithAS(testSharedNamespace))
+			err := validator.ValidateCreateTemplate(ctx, templateWithAS(testSharedNamespace))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should allow defaultAccessStrategy with empty namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
-			err := validator.ValidateCreateTemplate(templateWithAS(""))
+			err := validator.ValidateCreateTemplate(ctx, templateWithAS(""))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should reject cross-namespace when no shared namespace is configured", func() {
-			validator := NewAccessStrategyValidator("")
+			validator := NewAccessStrategyValidator("", nil, nil)
 
-			err := validator.ValidateCreateTemplate(templateWithAS(testSharedNamespace))
+			err := validator.ValidateCreateTemplate(ctx, templateWithAS(testSharedNamespace))
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(testSharedNamespace))
 			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamA))
@@ -247,19 +288,19 @@ var _ = Describe("AccessStrategyValidator", func() {
 		})
 
 		It("should skip validation when template has no defaultAccessStrategy", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			template := &workspacev1alpha1.WorkspaceTemplate{
 				ObjectMeta: metav1.ObjectMeta{Name: testTemplateNameTmpl, Namespace: testNamespaceTeamA},
 			}
-			err := validator.ValidateCreateTemplate(template)
+			err := validator.ValidateCreateTemplate(ctx, template)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should validate when defaultAccessStrategy namespace changes to a foreign namespace", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
-			err := validator.ValidateUpdateTemplate(
+			err := validator.ValidateUpdateTemplate(ctx,
 				templateWithAS(testNamespaceTeamA),
 				templateWithAS(testNamespaceTeamB))
 			Expect(err).To(HaveOccurred())
@@ -267,12 +308,94 @@ var _ = Describe("AccessStrategyValidator", func() {
 		})
 
 		It("should skip validation when defaultAccessStrategy is removed", func() {
-			validator := NewAccessStrategyValidator(testSharedNamespace)
+			validator := NewAccessStrategyValidator(testSharedNamespace, nil, nil)
 
 			newTemplate := &workspacev1alpha1.WorkspaceTemplate{
 				ObjectMeta: metav1.ObjectMeta{Name: testTemplateNameTmpl, Namespace: testNamespaceTeamA},
 			}
-			err := validator.ValidateUpdateTemplate(templateWithAS(testNamespaceTeamB), newTemplate)
+			err := validator.ValidateUpdateTemplate(ctx, templateWithAS(testNamespaceTeamB), newTemplate)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+	Context("Trusted namespace selector", func() {
+		var trustedSelector labels.Selector
+
+		newNamespace := func(name string, nsLabels map[string]string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nsLabels}}
+		}
+
+		workspaceWithAS := func(asNamespace string) *workspacev1alpha1.Workspace {
+			return &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					AccessStrategy: &workspacev1alpha1.AccessStrategyRef{
+						Name:      testSomeStrategy,
+						Namespace: asNamespace,
+					},
+				},
+			}
+		}
+
+		newValidator := func(objs ...client.Object) *AccessStrategyValidator {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			return NewAccessStrategyValidator(testSharedNamespace, fakeClient, trustedSelector)
+		}
+
+		BeforeEach(func() {
+			var err error
+			trustedSelector, err = labels.Parse("workspace.jupyter.org/shared-access-strategies=true")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should allow accessStrategy in a namespace matching the selector", func() {
+			validator := newValidator(newNamespace("platform", map[string]string{
+				"workspace.jupyter.org/shared-access-strategies": "true",
+			}))
+
+			err := validator.ValidateCreateWorkspace(context.Background(), workspaceWithAS("platform"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject accessStrategy in a namespace not matching the selector", func() {
+			validator := newValidator(newNamespace(testNamespaceTeamB, map[string]string{"team": "b"}))
+
+			err := validator.ValidateCreateWorkspace(context.Background(), workspaceWithAS(testNamespaceTeamB))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("workspace.jupyter.org/shared-access-strategies=true"))
+		})
+
+		It("should reject accessStrategy in a namespace that does not exist", func() {
+			validator := newValidator()
+
+			err := validator.ValidateCreateWorkspace(context.Background(), workspaceWithAS("missing-ns"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing-ns"))
+		})
+
+		It("should still allow the shared namespace without looking up labels", func() {
+			validator := newValidator()
+
+			err := validator.ValidateCreateWorkspace(context.Background(), workspaceWithAS(testSharedNamespace))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should apply the selector to template defaultAccessStrategy", func() {
+			validator := newValidator(newNamespace("platform", map[string]string{
+				"workspace.jupyter.org/shared-access-strategies": "true",
+			}))
+
+			template := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplateNameTmpl, Namespace: testNamespaceTeamA},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DefaultAccessStrategy: &workspacev1alpha1.AccessStrategyRef{
+						Name:      testSomeStrategy,
+						Namespace: "platform",
+					},
+				},
+			}
+			err := validator.ValidateCreateTemplate(context.Background(), template)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
@@ -0,0 +1,79 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/accesstemplate"
+)
+
+var accessstrategylog = logf.Log.WithName("workspaceaccessstrategy-resource")
+
+// SetupWorkspaceAccessStrategyWebhookWithManager registers the webhook for WorkspaceAccessStrategy in the manager.
+func SetupWorkspaceAccessStrategyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &workspacev1alpha1.WorkspaceAccessStrategy{}).
+		WithValidator(&WorkspaceAccessStrategyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspaceaccessstrategy,mutating=false,failurePolicy=ignore,sideEffects=None,groups=workspace.jupyter.org,resources=workspaceaccessstrategies,verbs=create;update,versions=v1alpha1,name=vworkspaceaccessstrategy-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+
+// WorkspaceAccessStrategyCustomValidator rejects WorkspaceAccessStrategy resources whose templates do not
+// parse, pointing at the field, line and column of the error, or whose provider is unknown, instead of
+// failing every workspace that uses the strategy at reconcile time.
+//
+// Uses failurePolicy: Ignore so access strategy writes are not blocked if the webhook is unavailable; the
+// controller still reports template errors on the workspaces.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type WorkspaceAccessStrategyCustomValidator struct{}
+
+var _ admission.Validator[*workspacev1alpha1.WorkspaceAccessStrategy] = &WorkspaceAccessStrategyCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type WorkspaceAccessStrategy.
+func (v *WorkspaceAccessStrategyCustomValidator) ValidateCreate(ctx context.Context, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (admission.Warnings, error) {
+	accessstrategylog.Info("Validation for WorkspaceAccessStrategy upon creation", "name", accessStrategy.GetName())
+	if err := validateAccessStrategyProvider(accessStrategy); err != nil {
+		return nil, err
+	}
+	return nil, validateAccessStrategyTemplates(accessStrategy)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type WorkspaceAccessStrategy.
+func (v *WorkspaceAccessStrategyCustomValidator) ValidateUpdate(ctx context.Context, oldAccessStrategy, newAccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (admission.Warnings, error) {
+	accessstrategylog.Info("Validation for WorkspaceAccessStrategy upon update", "name", newAccessStrategy.GetName())
+
+	// Allow finalizer removal on a strategy being deleted, even if its templates are broken
+	if !newAccessStrategy.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if err := validateAccessStrategyProvider(newAccessStrategy); err != nil {
+		return nil, err
+	}
+	return nil, validateAccessStrategyTemplates(newAccessStrategy)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type WorkspaceAccessStrategy.
+func (v *WorkspaceAccessStrategyCustomValidator) ValidateDelete(ctx context.Context, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (admission.Warnings, error) {
+	// Deletion is handled by finalizers in the controller
+	return nil, nil
+}
+
+// validateAccessStrategyTemplates returns the parse errors of all the templates of the strategy
+func validateAccessStrategyTemplates(accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) error {
+	if errs := accesstemplate.Lint(accessStrategy); len(errs) > 0 {
+		return fmt.Errorf("invalid access strategy templates:\n%w", errs)
+	}
+	return nil
+}
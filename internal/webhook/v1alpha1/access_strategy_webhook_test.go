@@ -0,0 +1,99 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("WorkspaceAccessStrategy Webhook", func() {
+	var (
+		ctx       context.Context
+		validator WorkspaceAccessStrategyCustomValidator
+		strategy  *workspacev1alpha1.WorkspaceAccessStrategy
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		validator = WorkspaceAccessStrategyCustomValidator{}
+		strategy = &workspacev1alpha1.WorkspaceAccessStrategy{
+			ObjectMeta: metav1.ObjectMeta{Name: "strategy", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+				AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{{
+					Kind:       "Service",
+					ApiVersion: "v1",
+					NamePrefix: "svc",
+					Template:   "spec:\n  selector:\n    app: {{ .Workspace.Name }}\n",
+				}},
+				AccessURLTemplate: "https://example.com/{{ .Workspace.Name }}/",
+			},
+		}
+	})
+
+	It("should admit a strategy whose templates parse", func() {
+		_, err := validator.ValidateCreate(ctx, strategy)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a strategy with the position of the broken template on create", func() {
+		strategy.Spec.AccessResourceTemplates[0].Template = "spec:\n  selector:\n    app: {{ .Workspace.Name }\n"
+
+		_, err := validator.ValidateCreate(ctx, strategy)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.accessResourceTemplates[0].template:3: "))
+	})
+
+	It("should reject a broken template on update", func() {
+		updated := strategy.DeepCopy()
+		updated.Spec.AccessURLTemplate = "https://example.com/{{ .Workspace.Name }/"
+
+		_, err := validator.ValidateUpdate(ctx, strategy, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.accessURLTemplate:1: "))
+	})
+
+	It("should admit the built-in providers", func() {
+		for _, provider := range []string{"", controller.AccessProviderTraefik, controller.AccessProviderIngress} {
+			strategy.Spec.Provider = provider
+			_, err := validator.ValidateCreate(ctx, strategy)
+			Expect(err).NotTo(HaveOccurred(), "provider %q", provider)
+		}
+	})
+
+	It("should reject an unknown provider on create", func() {
+		strategy.Spec.Provider = "nginx"
+
+		_, err := validator.ValidateCreate(ctx, strategy)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`spec.provider: unknown access provider "nginx"`))
+	})
+
+	It("should reject switching to an unknown provider on update", func() {
+		updated := strategy.DeepCopy()
+		updated.Spec.Provider = "trafik"
+
+		_, err := validator.ValidateUpdate(ctx, strategy, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must be one of"))
+	})
+
+	It("should admit updates of a strategy being deleted", func() {
+		updated := strategy.DeepCopy()
+		updated.Spec.AccessURLTemplate = "{{"
+		now := metav1.Now()
+		updated.DeletionTimestamp = &now
+
+		_, err := validator.ValidateUpdate(ctx, strategy, updated)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// benchmarkLargeTemplate returns a template near the limits of its lists, with image patterns,
+// profiles and env and label requirements
+func benchmarkLargeTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	required := true
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  "Large template",
+			DefaultImage: "quay.io/jupyter/scipy-notebook:latest",
+			ResourceBounds: &workspacev1alpha1.ResourceBounds{
+				Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+					corev1.ResourceCPU:    {Min: resource.MustParse("100m"), Max: resource.MustParse("16")},
+					corev1.ResourceMemory: {Min: resource.MustParse("128Mi"), Max: resource.MustParse("64Gi")},
+				},
+			},
+		},
+	}
+	for i := range 100 {
+		template.Spec.AllowedImages = append(template.Spec.AllowedImages,
+			fmt.Sprintf("quay.io/team-%d/*", i),
+			fmt.Sprintf(`^ghcr\.io/team-%d/notebook:v[0-9]+$`, i))
+	}
+	template.Spec.AllowedImages = append(template.Spec.AllowedImages, "quay.io/jupyter/*")
+	for i := range 20 {
+		template.Spec.Profiles = append(template.Spec.Profiles, workspacev1alpha1.ResourceProfile{
+			Name: fmt.Sprintf("size-%d", i),
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewQuantity(int64(i%16+1), resource.DecimalSI),
+			}},
+		})
+	}
+	for i := range 50 {
+		template.Spec.EnvRequirements = append(template.Spec.EnvRequirements, workspacev1alpha1.EnvRequirement{
+			Name: fmt.Sprintf("VAR_%d", i), Required: &required, Regex: "^[a-z]+$",
+		})
+		template.Spec.LabelRequirements = append(template.Spec.LabelRequirements, workspacev1alpha1.LabelRequirement{
+			Key: fmt.Sprintf("example.com/label-%d", i), Required: &required, Regex: "^[a-z0-9-]+$",
+		})
+	}
+	return template
+}
+
+// benchmarkWorkspace returns a workspace admissible by benchmarkLargeTemplate
+func benchmarkWorkspace() *workspacev1alpha1.Workspace {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testDefaultNamespace, Labels: map[string]string{}},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName: testDisplayName,
+			Image:       "quay.io/jupyter/scipy-notebook:latest",
+			TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+			Resources: &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			}},
+		},
+	}
+	for i := range 50 {
+		workspace.Spec.Env = append(workspace.Spec.Env, corev1.EnvVar{Name: fmt.Sprintf("VAR_%d", i), Value: "value"})
+		workspace.Labels[fmt.Sprintf("example.com/label-%d", i)] = "value"
+	}
+	return workspace
+}
+
+// BenchmarkValidateCreateWorkspace_LargeTemplate measures the template validation of a workspace
+// admission against a template near the limits of its lists
+func BenchmarkValidateCreateWorkspace_LargeTemplate(b *testing.B) {
+	scheme := runtime.NewScheme()
+	if err := workspacev1alpha1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(benchmarkLargeTemplate()).Build()
+	validator := NewTemplateValidator(k8sClient, testDefaultNamespace)
+	workspace := benchmarkWorkspace()
+	ctx := context.Background()
+
+	if err := validator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		b.Fatalf("the benchmark workspace must be admissible: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := validator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkApplyTemplateDefaults_LargeTemplate measures the template defaulting of a workspace
+// admission against a template near the limits of its lists
+func BenchmarkApplyTemplateDefaults_LargeTemplate(b *testing.B) {
+	scheme := runtime.NewScheme()
+	if err := workspacev1alpha1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(benchmarkLargeTemplate()).Build()
+	defaulter := NewTemplateDefaulter(k8sClient, testDefaultNamespace)
+	workspace := benchmarkWorkspace()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := defaulter.ApplyTemplateDefaults(ctx, workspace.DeepCopy()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
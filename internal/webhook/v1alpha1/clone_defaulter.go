@@ -0,0 +1,196 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// CloneDefaulter copies the spec and labels of the source workspace of spec.cloneFrom into a
+// new workspace. The source only fills fields the workspace leaves empty, and runs before the
+// user preferences and template defaults, which then fill the remaining fields.
+type CloneDefaulter struct {
+	client client.Client
+}
+
+// NewCloneDefaulter creates a new CloneDefaulter
+func NewCloneDefaulter(k8sClient client.Client) *CloneDefaulter {
+	return &CloneDefaulter{
+		client: k8sClient,
+	}
+}
+
+// getCloneSourceKey returns the namespaced name of the source workspace of spec.cloneFrom
+func getCloneSourceKey(workspace *workspacev1alpha1.Workspace) types.NamespacedName {
+	namespace := workspace.Spec.CloneFrom.Namespace
+	if namespace == "" {
+		namespace = workspace.Namespace
+	}
+	return types.NamespacedName{Name: workspace.Spec.CloneFrom.Name, Namespace: namespace}
+}
+
+// ApplyCloneSource copies the source workspace into a workspace being created from spec.cloneFrom.
+// A missing source is left for the validating webhook to reject.
+func (cd *CloneDefaulter) ApplyCloneSource(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	// cloneFrom is immutable, and the source is only copied when the workspace is created
+	if workspace.Spec.CloneFrom == nil || !workspace.CreationTimestamp.IsZero() {
+		return nil
+	}
+
+	source := &workspacev1alpha1.Workspace{}
+	if err := cd.client.Get(ctx, getCloneSourceKey(workspace), source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get clone source workspace %s: %w", getCloneSourceKey(workspace), err)
+	}
+
+	applyCloneSource(workspace, source)
+	return nil
+}
+
+// applyCloneSource fills the empty workspace fields from the source workspace. Ownership, sharing,
+// SSH keys, secondary volumes and the desired status stay with the new workspace and its creator.
+// The custom domain is not copied either, since each custom domain serves a single workspace.
+func applyCloneSource(workspace, source *workspacev1alpha1.Workspace) {
+	for key, value := range source.Labels {
+		if strings.HasPrefix(key, controller.ReservedMetadataPrefix) {
+			continue
+		}
+		if _, ok := workspace.Labels[key]; ok {
+			continue
+		}
+		if workspace.Labels == nil {
+			workspace.Labels = make(map[string]string)
+		}
+		workspace.Labels[key] = value
+	}
+
+	spec := &workspace.Spec
+	sourceSpec := source.Spec.DeepCopy()
+
+	if spec.Image == "" {
+		spec.Image = sourceSpec.Image
+	}
+	if spec.AppType == "" {
+		spec.AppType = sourceSpec.AppType
+	}
+	if spec.ApplicationType == "" {
+		spec.ApplicationType = sourceSpec.ApplicationType
+	}
+	if spec.ServiceAccountName == "" {
+		spec.ServiceAccountName = sourceSpec.ServiceAccountName
+	}
+	if spec.ImagePullSecrets == nil {
+		spec.ImagePullSecrets = sourceSpec.ImagePullSecrets
+	}
+	if spec.Resources == nil {
+		spec.Resources = sourceSpec.Resources
+	}
+	if spec.Storage == nil && sourceSpec.Storage != nil {
+		spec.Storage = sourceSpec.Storage
+		spec.Storage.RestoreFromSnapshot = nil
+	}
+	if spec.ContainerConfig == nil {
+		spec.ContainerConfig = sourceSpec.ContainerConfig
+	}
+	if spec.Ports == nil {
+		spec.Ports = sourceSpec.Ports
+	}
+	if spec.Env == nil {
+		spec.Env = sourceSpec.Env
+	}
+	if spec.EnvFrom == nil {
+		spec.EnvFrom = sourceSpec.EnvFrom
+	}
+	if spec.ExtraEnv == nil {
+		spec.ExtraEnv = sourceSpec.ExtraEnv
+	}
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = sourceSpec.NodeSelector
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = sourceSpec.Affinity
+	}
+	if spec.Tolerations == nil {
+		spec.Tolerations = sourceSpec.Tolerations
+	}
+	if spec.PriorityClassName == "" {
+		spec.PriorityClassName = sourceSpec.PriorityClassName
+	}
+	if spec.RuntimeClassName == "" {
+		spec.RuntimeClassName = sourceSpec.RuntimeClassName
+	}
+	if spec.Lifecycle == nil {
+		spec.Lifecycle = sourceSpec.Lifecycle
+	}
+	if spec.StopGracePeriodSeconds == nil {
+		spec.StopGracePeriodSeconds = sourceSpec.StopGracePeriodSeconds
+	}
+	if spec.ReadinessProbe == nil {
+		spec.ReadinessProbe = sourceSpec.ReadinessProbe
+	}
+	if spec.Probes == nil {
+		spec.Probes = sourceSpec.Probes
+	}
+	if spec.StartupDeadlineSeconds == nil {
+		spec.StartupDeadlineSeconds = sourceSpec.StartupDeadlineSeconds
+	}
+	if spec.FailurePolicy == nil {
+		spec.FailurePolicy = sourceSpec.FailurePolicy
+	}
+	if spec.DriftPolicy == "" {
+		spec.DriftPolicy = sourceSpec.DriftPolicy
+	}
+	if spec.AccessStrategy == nil {
+		spec.AccessStrategy = sourceSpec.AccessStrategy
+	}
+	if spec.AccessEnabled == nil {
+		spec.AccessEnabled = sourceSpec.AccessEnabled
+	}
+	if spec.TemplateRef == nil {
+		spec.TemplateRef = sourceSpec.TemplateRef
+	}
+	if spec.IdleShutdown == nil {
+		spec.IdleShutdown = sourceSpec.IdleShutdown
+	}
+	if spec.PodSecurityContext == nil {
+		spec.PodSecurityContext = sourceSpec.PodSecurityContext
+	}
+	if spec.ContainerSecurityContext == nil {
+		spec.ContainerSecurityContext = sourceSpec.ContainerSecurityContext
+	}
+	if spec.SecurityProfile == nil {
+		spec.SecurityProfile = sourceSpec.SecurityProfile
+	}
+	if spec.NetworkIsolation == nil {
+		spec.NetworkIsolation = sourceSpec.NetworkIsolation
+	}
+	if spec.GPUSharing == nil {
+		spec.GPUSharing = sourceSpec.GPUSharing
+	}
+	if spec.ProvisioningHints == nil {
+		spec.ProvisioningHints = sourceSpec.ProvisioningHints
+	}
+	if spec.JupyterServerConfig == nil {
+		spec.JupyterServerConfig = sourceSpec.JupyterServerConfig
+	}
+	if spec.ObjectStorageMounts == nil {
+		spec.ObjectStorageMounts = sourceSpec.ObjectStorageMounts
+	}
+	if spec.InitContainers == nil {
+		spec.InitContainers = sourceSpec.InitContainers
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("Clone Defaulter", func() {
+	var (
+		ctx       context.Context
+		scheme    *runtime.Scheme
+		source    *workspacev1alpha1.Workspace
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		source = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source",
+				Namespace: testNamespaceTeamA,
+				Labels: map[string]string{
+					"team":                           "ml",
+					"project":                        "forecast",
+					"workspace.jupyter.org/template": "ignored",
+				},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName:   "Source",
+				Image:         "jupyter/scipy-notebook:latest",
+				OwnershipType: webhookconst.OwnershipTypeOwnerOnly,
+				SSHPublicKeys: []string{"ssh-ed25519 AAAA source"},
+				Env:           []corev1.EnvVar{{Name: "MODE", Value: "train"}},
+				Storage: &workspacev1alpha1.StorageSpec{
+					Size:                resource.MustParse("20Gi"),
+					RestoreFromSnapshot: &workspacev1alpha1.SnapshotRef{Name: "nightly"},
+				},
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: "gpu-template"},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "clone",
+				Namespace: testNamespaceTeamA,
+				Labels:    map[string]string{"project": "clone-project"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Clone",
+				Env:         []corev1.EnvVar{{Name: "MODE", Value: "eval"}},
+				CloneFrom:   &workspacev1alpha1.CloneSource{Name: "source"},
+			},
+		}
+	})
+
+	It("should fill the empty fields and labels of the clone from the source", func() {
+		cd := NewCloneDefaulter(fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build())
+
+		Expect(cd.ApplyCloneSource(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.Image).To(Equal(source.Spec.Image))
+		Expect(workspace.Spec.TemplateRef).To(Equal(source.Spec.TemplateRef))
+		Expect(workspace.Spec.Storage.Size.String()).To(Equal("20Gi"))
+		Expect(workspace.Spec.Storage.RestoreFromSnapshot).To(BeNil())
+		Expect(workspace.Spec.Env).To(Equal([]corev1.EnvVar{{Name: "MODE", Value: "eval"}}))
+		Expect(workspace.Spec.DisplayName).To(Equal("Clone"))
+		Expect(workspace.Labels).To(Equal(map[string]string{"team": "ml", "project": "clone-project"}))
+	})
+
+	It("should not copy the ownership and SSH keys of the source", func() {
+		cd := NewCloneDefaulter(fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build())
+
+		Expect(cd.ApplyCloneSource(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.OwnershipType).To(BeEmpty())
+		Expect(workspace.Spec.SSHPublicKeys).To(BeEmpty())
+	})
+
+	It("should read the source from the namespace of cloneFrom", func() {
+		source.Namespace = testNamespaceTeamB
+		workspace.Spec.CloneFrom.Namespace = testNamespaceTeamB
+		cd := NewCloneDefaulter(fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build())
+
+		Expect(cd.ApplyCloneSource(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.Image).To(Equal(source.Spec.Image))
+	})
+
+	It("should leave a missing source to the validating webhook", func() {
+		cd := NewCloneDefaulter(fake.NewClientBuilder().WithScheme(scheme).Build())
+
+		Expect(cd.ApplyCloneSource(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.Image).To(BeEmpty())
+	})
+
+	It("should not copy the source into an existing workspace", func() {
+		workspace.CreationTimestamp = metav1.Now()
+		cd := NewCloneDefaulter(fake.NewClientBuilder().WithScheme(scheme).WithObjects(source).Build())
+
+		Expect(cd.ApplyCloneSource(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.Image).To(BeEmpty())
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// CloneValidator checks the source workspace of spec.cloneFrom, and that the user creating the
+// clone may read it. A clone exposes the configuration of the source and, with includeStorage,
+// its data, so the creator needs both RBAC read access to the source and the permission the
+// source's ownershipType and accessType grant to modify and access it.
+type CloneValidator struct {
+	client             client.Client
+	ownershipValidator *OwnershipValidator
+}
+
+// NewCloneValidator creates a new CloneValidator
+func NewCloneValidator(k8sClient client.Client, ownershipValidator *OwnershipValidator) *CloneValidator {
+	return &CloneValidator{
+		client:             k8sClient,
+		ownershipValidator: ownershipValidator,
+	}
+}
+
+// ValidateCreateWorkspace checks that the source workspace exists, is accessible to the user unless
+// they are the controller or an admin, and has a volume the workspace can clone when includeStorage
+// is set. Updates cannot add cloneFrom, so this only needs to run on create.
+func (cv *CloneValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.CloneFrom == nil {
+		return nil
+	}
+	sourceKey := getCloneSourceKey(workspace)
+
+	source := &workspacev1alpha1.Workspace{}
+	if err := cv.client.Get(ctx, sourceKey, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("clone source workspace %q not found in namespace %q", sourceKey.Name, sourceKey.Namespace)
+		}
+		return fmt.Errorf("unable to validate clone source workspace %s: %w", sourceKey, err)
+	}
+	if !source.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("clone source workspace %s is being deleted", sourceKey)
+	}
+
+	if !isControllerOrAdminUser(ctx) {
+		if err := cv.validateSourceAccess(ctx, source); err != nil {
+			return err
+		}
+	}
+
+	if workspace.Spec.CloneFrom.IncludeStorage {
+		return cv.validateStorageClone(ctx, workspace, source)
+	}
+	return nil
+}
+
+// ValidateUpdateWorkspace rejects an update that adds spec.cloneFrom. The CEL rule on the field only
+// compares it to an old value, so without this check the field could be added to an existing
+// workspace whose PVC is not created yet, cloning the source volume without the checks
+// ValidateCreateWorkspace makes on create.
+func (cv *CloneValidator) ValidateUpdateWorkspace(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if newWorkspace.Spec.CloneFrom == nil || oldWorkspace.Spec.CloneFrom != nil {
+		return nil
+	}
+	return fmt.Errorf("spec.cloneFrom can only be set when the workspace is created")
+}
+
+// validateSourceAccess checks that the user of the admission request may get the source workspace
+// through RBAC, and may modify and access it according to its ownershipType and accessType
+func (cv *CloneValidator) validateSourceAccess(ctx context.Context, source *workspacev1alpha1.Workspace) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to extract user information from request context: %w", err)
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(req.UserInfo.Extra))
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: source.Namespace,
+				Verb:      "get",
+				Group:     workspacev1alpha1.GroupVersion.Group,
+				Resource:  "workspaces",
+				Name:      source.Name,
+			},
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+			Extra:  extra,
+		},
+	}
+	if err := cv.client.Create(ctx, review); err != nil {
+		workspacelog.Error(err, "Failed to create SubjectAccessReview for clone source", "source", source.Name, "namespace", source.Namespace)
+		return fmt.Errorf("unable to check access to clone source workspace %s/%s: %w", source.Namespace, source.Name, err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("access denied: user %s cannot get clone source workspace %s/%s",
+			req.UserInfo.Username, source.Namespace, source.Name)
+	}
+
+	return cv.ownershipValidator.ValidateDataAccess(ctx, source, "clone")
+}
+
+// validateStorageClone checks that the volume of the source workspace can be cloned into the
+// workspace's volume. Kubernetes only clones a PVC into a PVC of the same namespace and of at least
+// its size, and a clone of a PVC that does not exist would leave the new PVC pending forever.
+func (cv *CloneValidator) validateStorageClone(ctx context.Context, workspace, source *workspacev1alpha1.Workspace) error {
+	if source.Namespace != workspace.Namespace {
+		return fmt.Errorf("spec.cloneFrom.includeStorage requires the clone source workspace to be in namespace %q", workspace.Namespace)
+	}
+	if workspace.Spec.Storage == nil {
+		return fmt.Errorf("spec.cloneFrom.includeStorage requires storage, but clone source workspace %q has none", source.Name)
+	}
+	if workspace.Spec.Storage.RestoreFromSnapshot != nil {
+		return fmt.Errorf("spec.cloneFrom.includeStorage and spec.storage.restoreFromSnapshot cannot both be set")
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := cv.client.Get(ctx, types.NamespacedName{
+		Name:      controller.GeneratePVCName(source),
+		Namespace: source.Namespace,
+	}, pvc)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("clone source workspace %q has no provisioned volume to clone", source.Name)
+		}
+		return fmt.Errorf("unable to validate the volume of clone source workspace %q: %w", source.Name, err)
+	}
+
+	size := workspace.Spec.Storage.Size
+	sourceSize, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if ok && !size.IsZero() && size.Cmp(sourceSize) < 0 {
+		violation := &TemplateViolation{
+			Type:    ViolationTypeStorageExceeded,
+			Field:   fieldStorageSize,
+			Message: fmt.Sprintf("Storage size %s is smaller than the volume %s of clone source workspace '%s'", size.String(), sourceSize.String(), source.Name),
+			Allowed: fmt.Sprintf("size >= %s", sourceSize.String()),
+			Actual:  size.String(),
+		}
+		return fmt.Errorf("workspace violates storage constraints: %s", violation.Message)
+	}
+	return nil
+}
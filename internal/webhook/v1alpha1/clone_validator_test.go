@@ -0,0 +1,224 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("CloneValidator", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		source     *workspacev1alpha1.Workspace
+		sourcePVC  *corev1.PersistentVolumeClaim
+		workspace  *workspacev1alpha1.Workspace
+		rbacAllows bool
+		reviews    []*authorizationv1.SubjectAccessReview
+	)
+
+	// newValidator builds a CloneValidator whose SubjectAccessReviews are answered with rbacAllows
+	newValidator := func(objects ...client.Object) *CloneValidator {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+						reviews = append(reviews, review)
+						review.Status.Allowed = rbacAllows
+						return nil
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+		return NewCloneValidator(fakeClient, NewOwnershipValidator(nil))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		rbacAllows = true
+		reviews = nil
+
+		source = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "source",
+				Namespace:   testNamespaceTeamA,
+				Annotations: map[string]string{controller.AnnotationCreatedBy: testOwnerUser},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				OwnershipType: webhookconst.OwnershipTypePublic,
+				AccessType:    webhookconst.OwnershipTypePublic,
+				Storage:       &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+			},
+		}
+		sourcePVC = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: controller.GeneratePVCName(source), Namespace: testNamespaceTeamA},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage:   &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+				CloneFrom: &workspacev1alpha1.CloneSource{Name: "source", IncludeStorage: true},
+			},
+		}
+	})
+
+	It("should skip workspaces that are not clones", func() {
+		workspace.Spec.CloneFrom = nil
+		var cv *CloneValidator
+		Expect(cv.ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+	})
+
+	It("should admit a clone of an accessible source", func() {
+		cv := newValidator(source, sourcePVC)
+		userCtx := createUserContext(ctx, "CREATE", "team-member", "team-a-users")
+
+		Expect(cv.ValidateCreateWorkspace(userCtx, workspace)).To(Succeed())
+		Expect(reviews).To(HaveLen(1))
+		Expect(reviews[0].Spec.User).To(Equal("team-member"))
+		Expect(reviews[0].Spec.Groups).To(Equal([]string{"team-a-users"}))
+		Expect(*reviews[0].Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+			Namespace: testNamespaceTeamA,
+			Verb:      "get",
+			Group:     workspacev1alpha1.GroupVersion.Group,
+			Resource:  "workspaces",
+			Name:      "source",
+		}))
+	})
+
+	It("should reject a missing source", func() {
+		cv := newValidator()
+		err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`clone source workspace "source" not found in namespace "team-a"`))
+	})
+
+	It("should reject a source the user cannot get", func() {
+		rbacAllows = false
+		cv := newValidator(source, sourcePVC)
+		err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", "outsider"), workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("access denied: user outsider cannot get clone source workspace"))
+	})
+
+	It("should reject a clone of an OwnerOnly source by another user", func() {
+		source.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+		cv := newValidator(source, sourcePVC)
+
+		err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", "outsider"), workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot clone workspace team-a/source"))
+		Expect(cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)).To(Succeed())
+	})
+
+	It("should reject a clone of a source with OwnerOnly access by another user", func() {
+		source.Spec.AccessType = webhookconst.OwnershipTypeOwnerOnly
+		cv := newValidator(source, sourcePVC)
+
+		err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", "outsider"), workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("only the owner of workspace team-a/source can clone it"))
+	})
+
+	It("should let admins clone any source without an access review", func() {
+		source.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+		rbacAllows = false
+		cv := newValidator(source, sourcePVC)
+
+		Expect(cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", "admin", webhookconst.DefaultAdminGroup), workspace)).To(Succeed())
+		Expect(reviews).To(BeEmpty())
+	})
+
+	It("should reject an update that adds a clone source", func() {
+		var cv *CloneValidator
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.CloneFrom = nil
+
+		err := cv.ValidateUpdateWorkspace(oldWorkspace, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.cloneFrom can only be set when the workspace is created"))
+	})
+
+	It("should allow updates that keep or remove the clone source", func() {
+		var cv *CloneValidator
+		Expect(cv.ValidateUpdateWorkspace(workspace, workspace.DeepCopy())).To(Succeed())
+
+		removed := workspace.DeepCopy()
+		removed.Spec.CloneFrom = nil
+		Expect(cv.ValidateUpdateWorkspace(workspace, removed)).To(Succeed())
+	})
+
+	Context("with includeStorage", func() {
+		It("should reject a source in another namespace", func() {
+			source.Namespace = testNamespaceTeamB
+			workspace.Spec.CloneFrom.Namespace = testNamespaceTeamB
+			cv := newValidator(source)
+
+			err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requires the clone source workspace to be in namespace"))
+		})
+
+		It("should allow a source in another namespace without includeStorage", func() {
+			source.Namespace = testNamespaceTeamB
+			workspace.Spec.CloneFrom = &workspacev1alpha1.CloneSource{Name: "source", Namespace: testNamespaceTeamB}
+			cv := newValidator(source)
+
+			Expect(cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)).To(Succeed())
+		})
+
+		It("should reject a source without a provisioned volume", func() {
+			cv := newValidator(source)
+
+			err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has no provisioned volume to clone"))
+		})
+
+		It("should reject a volume smaller than the source volume", func() {
+			workspace.Spec.Storage.Size = resource.MustParse("5Gi")
+			cv := newValidator(source, sourcePVC)
+
+			err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("smaller than the volume 10Gi of clone source workspace"))
+		})
+
+		It("should reject restoring from a snapshot at the same time", func() {
+			workspace.Spec.Storage.RestoreFromSnapshot = &workspacev1alpha1.SnapshotRef{Name: "nightly"}
+			cv := newValidator(source, sourcePVC)
+
+			err := cv.ValidateCreateWorkspace(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot both be set"))
+		})
+	})
+})
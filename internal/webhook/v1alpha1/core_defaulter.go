@@ -7,13 +7,18 @@ package v1alpha1
 
 import (
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // applyCoreDefaults applies core workspace defaults from template to workspace
 func applyCoreDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
-	// Apply image defaults
+	// Apply image defaults, recording the default image so that the workspace follows it
 	if workspace.Spec.Image == "" && template.Spec.DefaultImage != "" {
 		workspace.Spec.Image = template.Spec.DefaultImage
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[controller.AnnotationTemplateImage] = template.Spec.DefaultImage
 	}
 
 	// Apply ownership type defaults
@@ -35,4 +40,9 @@ func applyCoreDefaults(workspace *workspacev1alpha1.Workspace, template *workspa
 	if workspace.Spec.AppType == "" && template.Spec.AppType != "" {
 		workspace.Spec.AppType = template.Spec.AppType
 	}
+
+	// Apply application type defaults
+	if workspace.Spec.ApplicationType == "" && template.Spec.ApplicationType != "" {
+		workspace.Spec.ApplicationType = template.Spec.ApplicationType
+	}
 }
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 var _ = Describe("CoreDefaulter", func() {
@@ -42,12 +43,14 @@ var _ = Describe("CoreDefaulter", func() {
 		It("should apply image default when empty", func() {
 			applyCoreDefaults(workspace, template)
 			Expect(workspace.Spec.Image).To(Equal(testValidBaseNotebook))
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateImage, testValidBaseNotebook))
 		})
 
 		It("should not override existing image", func() {
 			workspace.Spec.Image = "custom/image:latest"
 			applyCoreDefaults(workspace, template)
 			Expect(workspace.Spec.Image).To(Equal("custom/image:latest"))
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationTemplateImage))
 		})
 
 		It("should apply ownership type default when empty", func() {
@@ -134,5 +137,22 @@ var _ = Describe("CoreDefaulter", func() {
 
 			Expect(workspace.Spec.AppType).To(Equal("vscode"))
 		})
+
+		It("should apply application type defaults", func() {
+			template.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+			applyCoreDefaults(workspace, template)
+
+			Expect(workspace.Spec.ApplicationType).To(Equal(workspacev1alpha1.ApplicationTypeCodeServer))
+		})
+
+		It("should not override existing application type", func() {
+			workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeRStudio
+			template.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+
+			applyCoreDefaults(workspace, template)
+
+			Expect(workspace.Spec.ApplicationType).To(Equal(workspacev1alpha1.ApplicationTypeRStudio))
+		})
 	})
 })
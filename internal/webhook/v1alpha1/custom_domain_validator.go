@@ -0,0 +1,100 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// CustomDomainValidator checks that a custom domain is not already used by another workspace
+type CustomDomainValidator struct {
+	client client.Client
+}
+
+// NewCustomDomainValidator creates a new CustomDomainValidator
+func NewCustomDomainValidator(k8sClient client.Client) *CustomDomainValidator {
+	return &CustomDomainValidator{
+		client: k8sClient,
+	}
+}
+
+// ValidateCreateWorkspace rejects a workspace whose custom domain another workspace uses
+func (cv *CustomDomainValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.CustomDomain == "" {
+		return nil
+	}
+	return cv.validateCustomDomainUnique(ctx, workspace)
+}
+
+// ValidateUpdateWorkspace rejects changing the custom domain of a workspace to one another
+// workspace uses
+func (cv *CustomDomainValidator) ValidateUpdateWorkspace(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if newWorkspace.Spec.CustomDomain == "" || newWorkspace.Spec.CustomDomain == oldWorkspace.Spec.CustomDomain {
+		return nil
+	}
+	return cv.validateCustomDomainUnique(ctx, newWorkspace)
+}
+
+// validateCustomDomainUnique lists the workspaces of all namespaces, since a host name routes
+// to a single workspace whatever its namespace
+func (cv *CustomDomainValidator) validateCustomDomainUnique(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := cv.client.List(ctx, workspaces); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	for _, other := range workspaces.Items {
+		if other.Namespace == workspace.Namespace && other.Name == workspace.Name {
+			continue
+		}
+		if other.Spec.CustomDomain == workspace.Spec.CustomDomain {
+			return fmt.Errorf("customDomain %q is already used by another workspace", workspace.Spec.CustomDomain)
+		}
+	}
+	return nil
+}
+
+// validateCustomDomainRequiresTemplate rejects a custom domain on a workspace without template,
+// since the template lists the domains workspaces can use
+func validateCustomDomainRequiresTemplate(workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.TemplateRef == nil && workspace.Spec.CustomDomain != "" {
+		return fmt.Errorf("customDomain %q requires a templateRef", workspace.Spec.CustomDomain)
+	}
+	return nil
+}
+
+// validateCustomDomain checks that the custom domain is a subdomain of one of the domain suffixes
+// the template allows. Rejects any custom domain when the template allows none (secure by default).
+func validateCustomDomain(domain string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if domain == "" {
+		return nil
+	}
+
+	for _, suffix := range template.Spec.AllowedDomainSuffixes {
+		// A suffix matches at a label boundary, so team.example.com does not allow myteam.example.com
+		if strings.HasSuffix(domain, "."+suffix) {
+			return nil
+		}
+	}
+
+	allowed := "no custom domain"
+	if len(template.Spec.AllowedDomainSuffixes) > 0 {
+		allowed = "subdomains of " + strings.Join(template.Spec.AllowedDomainSuffixes, ", ")
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeCustomDomainNotAllowed,
+		Field:   "spec.customDomain",
+		Message: fmt.Sprintf("Custom domain '%s' is not allowed by template '%s'", domain, template.Name),
+		Allowed: allowed,
+		Actual:  domain,
+	}
+}
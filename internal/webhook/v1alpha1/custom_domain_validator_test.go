@@ -0,0 +1,120 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testDomainSuffix = "team.example.com"
+	testCustomDomain = "alice-notebook.team.example.com"
+)
+
+var _ = Describe("CustomDomainValidator", func() {
+	newWorkspace := func(namespace, name, domain string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName:  testDisplayName,
+				CustomDomain: domain,
+				TemplateRef:  &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+			},
+		}
+	}
+
+	Context("validateCustomDomain", func() {
+		var template *workspacev1alpha1.WorkspaceTemplate
+
+		BeforeEach(func() {
+			template = &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					AllowedDomainSuffixes: []string{testDomainSuffix},
+				},
+			}
+		})
+
+		It("should allow a subdomain of an allowed suffix", func() {
+			Expect(validateCustomDomain(testCustomDomain, template)).To(BeNil())
+		})
+
+		It("should allow workspaces without a custom domain", func() {
+			Expect(validateCustomDomain("", template)).To(BeNil())
+		})
+
+		It("should only match suffixes at a label boundary", func() {
+			violation := validateCustomDomain("alice.myteam.example.com", template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeCustomDomainNotAllowed))
+			Expect(violation.Field).To(Equal("spec.customDomain"))
+			Expect(violation.Allowed).To(Equal("subdomains of team.example.com"))
+		})
+
+		It("should reject the suffix itself", func() {
+			Expect(validateCustomDomain(testDomainSuffix, template)).NotTo(BeNil())
+		})
+
+		It("should reject any custom domain when the template allows none", func() {
+			template.Spec.AllowedDomainSuffixes = nil
+			violation := validateCustomDomain(testCustomDomain, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Allowed).To(Equal("no custom domain"))
+		})
+	})
+
+	Context("validateCustomDomainRequiresTemplate", func() {
+		It("should reject a custom domain on a workspace without template", func() {
+			workspace := newWorkspace(testNamespaceTeamA, "ws", testCustomDomain)
+			workspace.Spec.TemplateRef = nil
+			Expect(validateCustomDomainRequiresTemplate(workspace)).To(MatchError(ContainSubstring("requires a templateRef")))
+		})
+	})
+
+	Context("uniqueness", func() {
+		var validator *CustomDomainValidator
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			existing := newWorkspace("team-b", "bob", testCustomDomain)
+			validator = NewCustomDomainValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build())
+		})
+
+		It("should reject a custom domain used by a workspace of another namespace", func() {
+			err := validator.ValidateCreateWorkspace(context.Background(), newWorkspace(testNamespaceTeamA, "alice", testCustomDomain))
+			Expect(err).To(MatchError(ContainSubstring("already used by another workspace")))
+		})
+
+		It("should allow an unused custom domain", func() {
+			workspace := newWorkspace(testNamespaceTeamA, "alice", "alice."+testDomainSuffix)
+			Expect(validator.ValidateCreateWorkspace(context.Background(), workspace)).To(Succeed())
+		})
+
+		It("should allow updates of the workspace using the custom domain", func() {
+			workspace := newWorkspace("team-b", "bob", testCustomDomain)
+			Expect(validator.ValidateCreateWorkspace(context.Background(), workspace)).To(Succeed())
+		})
+
+		It("should only check a changed custom domain on update", func() {
+			oldWorkspace := newWorkspace(testNamespaceTeamA, "alice", testCustomDomain)
+			newWorkspace := oldWorkspace.DeepCopy()
+			Expect(validator.ValidateUpdateWorkspace(context.Background(), oldWorkspace, newWorkspace)).To(Succeed())
+
+			oldWorkspace.Spec.CustomDomain = ""
+			Expect(validator.ValidateUpdateWorkspace(context.Background(), oldWorkspace, newWorkspace)).
+				To(MatchError(ContainSubstring("already used by another workspace")))
+		})
+	})
+})
@@ -0,0 +1,177 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// Defaulting snapshots record, for each case directory under testdata/snapshots, the fully defaulted
+// workspace and the child resources the controller renders from it. Each case holds:
+//   - input.yaml: a Workspace and the WorkspaceTemplate, WorkspaceAccessStrategy and ServiceAccounts it uses
+//   - expected.yaml: the defaulted Workspace, then its Deployment, Service and PersistentVolumeClaim
+//
+// Run with UPDATE_SNAPSHOTS=true (make update-snapshots) to rewrite expected.yaml after an intended change,
+// and review the diff of the snapshots along with the code.
+const (
+	snapshotsDir            = "testdata/snapshots"
+	snapshotInputFile       = "input.yaml"
+	snapshotExpectedFile    = "expected.yaml"
+	updateSnapshotsEnvVar   = "UPDATE_SNAPSHOTS"
+	snapshotUser            = "snapshot-user"
+	snapshotSharedNamespace = "jupyter-k8s-shared"
+)
+
+var _ = Describe("Defaulting snapshots", func() {
+	entries, err := os.ReadDir(snapshotsDir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		caseDir := filepath.Join(snapshotsDir, entry.Name())
+
+		It("should match the snapshot of "+entry.Name(), func() {
+			actual := renderDefaultingSnapshot(caseDir)
+			expectedPath := filepath.Join(caseDir, snapshotExpectedFile)
+
+			if os.Getenv(updateSnapshotsEnvVar) == labelValueTrue {
+				Expect(os.WriteFile(expectedPath, actual, 0o644)).To(Succeed())
+				return
+			}
+
+			expected, err := os.ReadFile(expectedPath)
+			Expect(err).NotTo(HaveOccurred(), "missing snapshot, run with %s=true to create it", updateSnapshotsEnvVar)
+			Expect(string(actual)).To(Equal(string(expected)),
+				"snapshot mismatch, run with %s=true to update it if the change is intended", updateSnapshotsEnvVar)
+		})
+	}
+})
+
+// renderDefaultingSnapshot runs the mutating webhook and the resource builders on the input of a case
+func renderDefaultingSnapshot(caseDir string) []byte {
+	ctx := createUserContext(context.Background(), "CREATE", snapshotUser)
+
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	workspace, dependencies := readSnapshotInput(scheme, filepath.Join(caseDir, snapshotInputFile))
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(dependencies...).Build()
+
+	defaulter := &WorkspaceCustomDefaulter{
+		templateDefaulter:        NewTemplateDefaulter(fakeClient, snapshotSharedNamespace),
+		templateMigrator:         NewTemplateMigrator(fakeClient, snapshotSharedNamespace),
+		identityMappingDefaulter: NewIdentityMappingDefaulter(fakeClient, fakeClient, snapshotSharedNamespace),
+		userPreferencesDefaulter: NewUserPreferencesDefaulter(fakeClient, snapshotSharedNamespace),
+		serviceAccountDefaulter:  NewServiceAccountDefaulter(fakeClient),
+		templateGetter:           NewTemplateGetter(fakeClient, snapshotSharedNamespace),
+		templateValidator:        NewTemplateValidator(fakeClient, snapshotSharedNamespace),
+		accessStrategyValidator:  NewAccessStrategyValidator(snapshotSharedNamespace, fakeClient, nil),
+		client:                   fakeClient,
+	}
+	Expect(defaulter.Default(ctx, workspace)).To(Succeed())
+
+	accessStrategy := getSnapshotAccessStrategy(ctx, fakeClient, workspace)
+
+	deployment, err := controller.NewDeploymentBuilder(scheme, controller.WorkspaceControllerOptions{}, fakeClient).
+		BuildDeploymentWithAccessStrategy(ctx, workspace, accessStrategy)
+	Expect(err).NotTo(HaveOccurred())
+	service, err := controller.NewServiceBuilder(scheme).BuildService(workspace)
+	Expect(err).NotTo(HaveOccurred())
+	objects := []client.Object{workspace, deployment, service}
+
+	if controller.ResolveStorageConfig(workspace) != nil {
+		pvc, err := controller.NewPVCBuilder(scheme).BuildPVC(workspace)
+		Expect(err).NotTo(HaveOccurred())
+		objects = append(objects, pvc)
+	}
+
+	var snapshot bytes.Buffer
+	for i, obj := range objects {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		Expect(err).NotTo(HaveOccurred())
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+		data, err := yaml.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		if i > 0 {
+			snapshot.WriteString("---\n")
+		}
+		snapshot.Write(data)
+	}
+	return snapshot.Bytes()
+}
+
+// readSnapshotInput decodes the workspace of a case and the objects it depends on
+func readSnapshotInput(scheme *runtime.Scheme, path string) (*workspacev1alpha1.Workspace, []client.Object) {
+	data, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	var workspace *workspacev1alpha1.Workspace
+	var dependencies []client.Object
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(document, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if ws, ok := obj.(*workspacev1alpha1.Workspace); ok {
+			workspace = ws
+			continue
+		}
+		dependencies = append(dependencies, obj.(client.Object))
+	}
+
+	Expect(workspace).NotTo(BeNil(), "%s has no Workspace", path)
+	return workspace, dependencies
+}
+
+// getSnapshotAccessStrategy returns the access strategy the defaulted workspace references, if any
+func getSnapshotAccessStrategy(
+	ctx context.Context,
+	k8sClient client.Client,
+	workspace *workspacev1alpha1.Workspace,
+) *workspacev1alpha1.WorkspaceAccessStrategy {
+	if workspace.Spec.AccessStrategy == nil {
+		return nil
+	}
+	accessStrategy, err := controller.NewResourceManager(k8sClient, k8sClient.Scheme(), nil, nil, nil, nil, nil).
+		GetAccessStrategyForWorkspace(ctx, workspace)
+	Expect(err).NotTo(HaveOccurred())
+	return accessStrategy
+}
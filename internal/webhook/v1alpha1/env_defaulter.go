@@ -6,12 +6,17 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/equality"
+
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
 
-// applyEnvDefaults merges template's BaseEnv into workspace's Env.
+// applyEnvDefaults merges template's BaseEnv into workspace's Env, and template's BaseEnvFrom into
+// workspace's EnvFrom.
 // Workspace env vars take precedence by name (same pattern as baseLabels).
 func applyEnvDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	applyEnvFromDefaults(workspace, template)
+
 	if len(template.Spec.BaseEnv) == 0 {
 		return
 	}
@@ -29,3 +34,19 @@ func applyEnvDefaults(workspace *workspacev1alpha1.Workspace, template *workspac
 		}
 	}
 }
+
+// applyEnvFromDefaults adds the template's BaseEnvFrom sources the workspace does not already reference
+func applyEnvFromDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	for _, source := range template.Spec.BaseEnvFrom {
+		referenced := false
+		for _, existing := range workspace.Spec.EnvFrom {
+			if equality.Semantic.DeepEqual(existing, source) {
+				referenced = true
+				break
+			}
+		}
+		if !referenced {
+			workspace.Spec.EnvFrom = append(workspace.Spec.EnvFrom, *source.DeepCopy())
+		}
+	}
+}
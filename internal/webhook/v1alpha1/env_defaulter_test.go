@@ -77,4 +77,29 @@ var _ = Describe("EnvDefaulter", func() {
 
 		Expect(template.Spec.BaseEnv[0].Value).To(Equal("original"))
 	})
+
+	It("should add template envFrom sources the workspace does not reference", func() {
+		configMap := corev1.EnvFromSource{ConfigMapRef: &corev1.ConfigMapEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "team-config"},
+		}}
+		secret := corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "team-secret"},
+		}}
+		workspace.Spec.EnvFrom = []corev1.EnvFromSource{configMap}
+		template.Spec.BaseEnvFrom = []corev1.EnvFromSource{configMap, secret}
+
+		applyEnvDefaults(workspace, template)
+
+		Expect(workspace.Spec.EnvFrom).To(Equal([]corev1.EnvFromSource{configMap, secret}))
+	})
+
+	It("should not merge template env into extraEnv", func() {
+		workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "MINE", Value: "1"}}
+		template.Spec.BaseEnv = []corev1.EnvVar{{Name: "T", Value: "t"}}
+
+		applyEnvDefaults(workspace, template)
+
+		Expect(workspace.Spec.ExtraEnv).To(Equal([]corev1.EnvVar{{Name: "MINE", Value: "1"}}))
+		Expect(workspace.Spec.Env).To(Equal([]corev1.EnvVar{{Name: "T", Value: "t"}}))
+	})
 })
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"regexp"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
 
@@ -19,14 +22,23 @@ func validateEnvRequirements(workspace *workspacev1alpha1.Workspace, template *w
 	}
 
 	// Build lookup of workspace env vars by name
-	envMap := make(map[string]string, len(workspace.Spec.Env))
+	envMap := make(map[string]string, len(workspace.Spec.Env)+len(workspace.Spec.ExtraEnv))
 	for _, e := range workspace.Spec.Env {
 		envMap[e.Name] = e.Value
 	}
+	for _, e := range workspace.Spec.ExtraEnv {
+		if _, exists := envMap[e.Name]; !exists {
+			envMap[e.Name] = e.Value
+		}
+	}
 
 	var violations []TemplateViolation
 
 	for _, req := range template.Spec.EnvRequirements {
+		if req.Protected != nil && *req.Protected {
+			violations = append(violations, validateProtectedEnv(workspace, template, req.Name)...)
+		}
+
 		value, exists := envMap[req.Name]
 
 		// Check required
@@ -64,3 +76,52 @@ func validateEnvRequirements(workspace *workspacev1alpha1.Workspace, template *w
 
 	return violations
 }
+
+// validateProtectedEnv checks that the workspace does not override a template-protected env var:
+// spec.env may only hold the template's BaseEnv entry, and spec.extraEnv may not set it
+func validateProtectedEnv(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate, name string) []TemplateViolation {
+	var base *corev1.EnvVar
+	for i := range template.Spec.BaseEnv {
+		if template.Spec.BaseEnv[i].Name == name {
+			base = &template.Spec.BaseEnv[i]
+			break
+		}
+	}
+
+	var violations []TemplateViolation
+	for _, e := range workspace.Spec.Env {
+		if e.Name == name && (base == nil || !equality.Semantic.DeepEqual(e, *base)) {
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeEnvProtected,
+				Field:   fmt.Sprintf("spec.env[%s]", name),
+				Message: fmt.Sprintf("Environment variable '%s' is protected by template and cannot be overridden", name),
+			})
+		}
+	}
+	for _, e := range workspace.Spec.ExtraEnv {
+		if e.Name == name {
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeEnvProtected,
+				Field:   fmt.Sprintf("spec.extraEnv[%s]", name),
+				Message: fmt.Sprintf("Environment variable '%s' is protected by template and cannot be overridden", name),
+			})
+		}
+	}
+	return violations
+}
+
+// validateExtraEnv checks that spec.extraEnv only adds variables: it may not redefine a variable
+// of spec.env, nor define the same variable twice
+func validateExtraEnv(workspace *workspacev1alpha1.Workspace) error {
+	names := make(map[string]struct{}, len(workspace.Spec.Env)+len(workspace.Spec.ExtraEnv))
+	for _, e := range workspace.Spec.Env {
+		names[e.Name] = struct{}{}
+	}
+	for i, e := range workspace.Spec.ExtraEnv {
+		if _, exists := names[e.Name]; exists {
+			return fmt.Errorf("spec.extraEnv[%d]: environment variable '%s' is already defined", i, e.Name)
+		}
+		names[e.Name] = struct{}{}
+	}
+	return nil
+}
@@ -140,4 +140,81 @@ var _ = Describe("EnvValidator", func() {
 			Expect(violations).To(HaveLen(2))
 		})
 	})
+
+	Context("protected env vars", func() {
+		BeforeEach(func() {
+			protected := true
+			template.Spec.BaseEnv = []corev1.EnvVar{{Name: "AWS_REGION", Value: "us-west-2"}}
+			template.Spec.EnvRequirements = []workspacev1alpha1.EnvRequirement{
+				{Name: "AWS_REGION", Protected: &protected},
+				{Name: "PROXY", Protected: &protected},
+			}
+		})
+
+		It("should accept the template value", func() {
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "AWS_REGION", Value: "us-west-2"}}
+
+			Expect(validateEnvRequirements(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject an override in env", func() {
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "AWS_REGION", Value: "eu-west-1"}}
+
+			violations := validateEnvRequirements(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeEnvProtected))
+			Expect(violations[0].Field).To(Equal("spec.env[AWS_REGION]"))
+		})
+
+		It("should reject a protected var the template does not set", func() {
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "PROXY", Value: "http://proxy"}}
+
+			violations := validateEnvRequirements(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Field).To(Equal("spec.env[PROXY]"))
+		})
+
+		It("should reject a protected var in extraEnv", func() {
+			workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "PROXY", Value: "http://proxy"}}
+
+			violations := validateEnvRequirements(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeEnvProtected))
+			Expect(violations[0].Field).To(Equal("spec.extraEnv[PROXY]"))
+		})
+	})
+
+	Context("required env vars in extraEnv", func() {
+		It("should accept a required var set in extraEnv", func() {
+			required := true
+			template.Spec.EnvRequirements = []workspacev1alpha1.EnvRequirement{
+				{Name: "PROJECT", Required: &required},
+			}
+			workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "PROJECT", Value: "p1"}}
+
+			Expect(validateEnvRequirements(workspace, template)).To(BeEmpty())
+		})
+	})
+
+	Context("validateExtraEnv", func() {
+		It("should accept variables that are not in env", func() {
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "A", Value: "1"}}
+			workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "B", Value: "2"}}
+
+			Expect(validateExtraEnv(workspace)).To(Succeed())
+		})
+
+		It("should reject a variable redefined from env", func() {
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "A", Value: "1"}}
+			workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "A", Value: "2"}}
+
+			Expect(validateExtraEnv(workspace)).To(MatchError(ContainSubstring("spec.extraEnv[0]")))
+		})
+
+		It("should reject a variable defined twice", func() {
+			workspace.Spec.ExtraEnv = []corev1.EnvVar{{Name: "B", Value: "1"}, {Name: "B", Value: "2"}}
+
+			Expect(validateExtraEnv(workspace)).To(MatchError(ContainSubstring("spec.extraEnv[1]")))
+		})
+	})
 })
@@ -0,0 +1,201 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// External policy calls run in the admission path, so they are not retried
+const (
+	externalPolicyTimeout      = 5 * time.Second
+	externalPolicyMaxBodyBytes = 256 * 1024
+)
+
+// ExternalPolicyInterface evaluates the admission of a workspace against the policies of an
+// external engine, e.g. Open Policy Agent
+type ExternalPolicyInterface interface {
+	// Evaluate returns the violations of the policies, empty when the workspace is admitted
+	Evaluate(ctx context.Context, input *ExternalPolicyInput) ([]ExternalPolicyViolation, error)
+}
+
+// ExternalPolicyInput is the input of an external policy evaluation. It follows the input of
+// Gatekeeper, so that the Rego policies of a ConstraintTemplate read the workspace from
+// input.review.object, and adds the template the workspace resolves to.
+type ExternalPolicyInput struct {
+	Review   ExternalPolicyReview                 `json:"review"`
+	Template *workspacev1alpha1.WorkspaceTemplate `json:"template,omitempty"`
+}
+
+// ExternalPolicyReview describes the admission request under review
+type ExternalPolicyReview struct {
+	Operation admissionv1.Operation        `json:"operation"`
+	UserInfo  authenticationv1.UserInfo    `json:"userInfo"`
+	Namespace string                       `json:"namespace"`
+	Name      string                       `json:"name"`
+	Object    *workspacev1alpha1.Workspace `json:"object"`
+	OldObject *workspacev1alpha1.Workspace `json:"oldObject,omitempty"`
+}
+
+// ExternalPolicyViolation is a violation of an external policy, the shape of the violations
+// of Gatekeeper's Rego policies
+type ExternalPolicyViolation struct {
+	Msg string `json:"msg"`
+}
+
+// ExternalPolicyWebhookRequest is the JSON body posted to the external policy endpoint, the
+// input document of the OPA data API
+type ExternalPolicyWebhookRequest struct {
+	Input *ExternalPolicyInput `json:"input"`
+}
+
+// ExternalPolicyWebhookResponse is the JSON body returned by the external policy endpoint: the
+// result of the OPA data API, for a rule that produces the violations, e.g.
+// POST /v1/data/workspaces/violation
+type ExternalPolicyWebhookResponse struct {
+	Result []ExternalPolicyViolation `json:"result"`
+}
+
+// ExternalPolicyWebhook implements ExternalPolicyInterface by posting
+// ExternalPolicyWebhookRequests to an HTTP endpoint
+type ExternalPolicyWebhook struct {
+	endpoint string
+	client   *http.Client
+}
+
+var _ ExternalPolicyInterface = &ExternalPolicyWebhook{}
+
+// NewExternalPolicyWebhook creates an ExternalPolicyWebhook posting to endpoint
+func NewExternalPolicyWebhook(endpoint string) *ExternalPolicyWebhook {
+	return &ExternalPolicyWebhook{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: externalPolicyTimeout},
+	}
+}
+
+// Evaluate posts the input and returns the violations of the response
+func (p *ExternalPolicyWebhook) Evaluate(ctx context.Context, input *ExternalPolicyInput) ([]ExternalPolicyViolation, error) {
+	payload, err := json.Marshal(&ExternalPolicyWebhookRequest{Input: input})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal external policy request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create external policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("external policy call failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, externalPolicyMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external policy response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("external policy rejected the request: status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var response ExternalPolicyWebhookResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid external policy response: %w", err)
+	}
+	return response.Result, nil
+}
+
+// ExternalPolicyValidator rejects workspaces that violate the policies of an external engine.
+// It is enabled with --external-policy-url. When the engine cannot be reached, the workspace is
+// rejected unless --external-policy-fail-open is set.
+type ExternalPolicyValidator struct {
+	// policy evaluates the workspaces, nil when external policies are disabled
+	policy   ExternalPolicyInterface
+	failOpen bool
+	resolver *workspaceutil.TemplateResolver
+}
+
+// NewExternalPolicyValidator creates a new ExternalPolicyValidator. policy may be nil, in which
+// case no external policy is evaluated.
+func NewExternalPolicyValidator(policy ExternalPolicyInterface, failOpen bool, resolver *workspaceutil.TemplateResolver) *ExternalPolicyValidator {
+	return &ExternalPolicyValidator{policy: policy, failOpen: failOpen, resolver: resolver}
+}
+
+// ValidateCreateWorkspace evaluates the external policies against a new workspace
+func (v *ExternalPolicyValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	return v.validate(ctx, admissionv1.Create, nil, workspace)
+}
+
+// ValidateUpdateWorkspace evaluates the external policies against an updated workspace
+func (v *ExternalPolicyValidator) ValidateUpdateWorkspace(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	return v.validate(ctx, admissionv1.Update, oldWorkspace, newWorkspace)
+}
+
+func (v *ExternalPolicyValidator) validate(
+	ctx context.Context,
+	operation admissionv1.Operation,
+	oldWorkspace, workspace *workspacev1alpha1.Workspace,
+) error {
+	if v == nil || v.policy == nil {
+		return nil
+	}
+
+	input := &ExternalPolicyInput{
+		Review: ExternalPolicyReview{
+			Operation: operation,
+			Namespace: workspace.Namespace,
+			Name:      workspace.Name,
+			Object:    workspace,
+			OldObject: oldWorkspace,
+		},
+	}
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		input.Review.UserInfo = req.UserInfo
+	}
+	// A template that cannot be resolved is reported by the template validation
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		if template, err := v.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace); err == nil {
+			input.Template = template
+		}
+	}
+
+	violations, err := v.policy.Evaluate(ctx, input)
+	if err != nil {
+		if v.failOpen {
+			workspacelog.Error(err, "External policy evaluation failed, admitting workspace",
+				"workspace", workspace.Name, "namespace", workspace.Namespace)
+			return nil
+		}
+		workspacelog.Error(err, "External policy evaluation failed",
+			"workspace", workspace.Name, "namespace", workspace.Namespace)
+		return fmt.Errorf("external policy could not be evaluated, see the webhook logs for details")
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Msg)
+	}
+	return fmt.Errorf("workspace violates external policy: %s", strings.Join(messages, "; "))
+}
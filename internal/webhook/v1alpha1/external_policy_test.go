@@ -0,0 +1,158 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+type fakeExternalPolicy struct {
+	input      *ExternalPolicyInput
+	violations []ExternalPolicyViolation
+	err        error
+}
+
+func (f *fakeExternalPolicy) Evaluate(_ context.Context, input *ExternalPolicyInput) ([]ExternalPolicyViolation, error) {
+	f.input = input
+	return f.violations, f.err
+}
+
+var _ = Describe("ExternalPolicyWebhook", func() {
+	It("should post the input and return the violations of the result", func() {
+		var received map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			_, _ = w.Write([]byte(`{"result": [{"msg": "image must come from the internal registry"}]}`))
+		}))
+		defer server.Close()
+
+		input := &ExternalPolicyInput{Review: ExternalPolicyReview{
+			Operation: admissionv1.Create,
+			Name:      "ws",
+			Object:    &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{Image: testValidBaseNotebook}},
+		}}
+		violations, err := NewExternalPolicyWebhook(server.URL).Evaluate(context.Background(), input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(Equal([]ExternalPolicyViolation{{Msg: "image must come from the internal registry"}}))
+
+		review := received["input"].(map[string]any)["review"].(map[string]any)
+		Expect(review["operation"]).To(Equal("CREATE"))
+		Expect(review["object"].(map[string]any)["spec"].(map[string]any)["image"]).To(Equal(testValidBaseNotebook))
+	})
+
+	It("should return no violation for an empty result", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"result": []}`))
+		}))
+		defer server.Close()
+
+		violations, err := NewExternalPolicyWebhook(server.URL).Evaluate(context.Background(), &ExternalPolicyInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(BeEmpty())
+	})
+
+	It("should return an error when the endpoint fails", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "policy not found", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := NewExternalPolicyWebhook(server.URL).Evaluate(context.Background(), &ExternalPolicyInput{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 404"))
+	})
+})
+
+var _ = Describe("ExternalPolicyValidator", func() {
+	var (
+		policy    *fakeExternalPolicy
+		resolver  *workspaceutil.TemplateResolver
+		workspace *workspacev1alpha1.Workspace
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		policy = &fakeExternalPolicy{}
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+			Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Policy Template", DefaultImage: testValidBaseNotebook},
+		}
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		resolver = workspaceutil.NewTemplateResolver(
+			fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "")
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy-workspace", Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:       testValidBaseNotebook,
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+			},
+		}
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "alice", Groups: []string{"data-science"}},
+			},
+		})
+	})
+
+	It("should admit every workspace when disabled", func() {
+		Expect(NewExternalPolicyValidator(nil, false, resolver).ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+	})
+
+	It("should pass the workspace, the user and the resolved template to the policy", func() {
+		validator := NewExternalPolicyValidator(policy, false, resolver)
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.Image = "jupyter/old-notebook:latest"
+
+		Expect(validator.ValidateUpdateWorkspace(ctx, oldWorkspace, workspace)).To(Succeed())
+		Expect(policy.input.Review.Operation).To(Equal(admissionv1.Update))
+		Expect(policy.input.Review.UserInfo.Username).To(Equal("alice"))
+		Expect(policy.input.Review.Object).To(Equal(workspace))
+		Expect(policy.input.Review.OldObject).To(Equal(oldWorkspace))
+		Expect(policy.input.Template).NotTo(BeNil())
+		Expect(policy.input.Template.Name).To(Equal(testTemplateName))
+	})
+
+	It("should reject a workspace that violates the policy", func() {
+		policy.violations = []ExternalPolicyViolation{{Msg: "cost center label is required"}, {Msg: "GPUs need approval"}}
+		err := NewExternalPolicyValidator(policy, false, resolver).ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(MatchError("workspace violates external policy: cost center label is required; GPUs need approval"))
+	})
+
+	It("should reject the workspace when the policy cannot be evaluated and failing closed", func() {
+		policy.err = errors.New("connection refused")
+		err := NewExternalPolicyValidator(policy, false, resolver).ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).NotTo(ContainSubstring("connection refused"))
+	})
+
+	It("should admit the workspace when the policy cannot be evaluated and failing open", func() {
+		policy.err = errors.New("connection refused")
+		Expect(NewExternalPolicyValidator(policy, true, resolver).ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+	})
+
+	It("should evaluate a workspace whose template cannot be resolved without the template", func() {
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "missing-template"}
+		Expect(NewExternalPolicyValidator(policy, false, resolver).ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+		Expect(policy.input.Template).To(BeNil())
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyGPUSharingDefaults applies the GPU sharing of the template to the workspace. A workspace
+// that chooses its mode gets the rest of the template GPU sharing, which it cannot change.
+func applyGPUSharingDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	templateSharing := template.Spec.GPUSharing
+	if templateSharing == nil {
+		return
+	}
+
+	if workspace.Spec.GPUSharing == nil {
+		workspace.Spec.GPUSharing = templateSharing.DeepCopy()
+		return
+	}
+	if workspace.Spec.GPUSharing.SharedResourceName == "" {
+		workspace.Spec.GPUSharing.SharedResourceName = templateSharing.SharedResourceName
+	}
+	if workspace.Spec.GPUSharing.MPS == nil && templateSharing.MPS != nil {
+		workspace.Spec.GPUSharing.MPS = templateSharing.MPS.DeepCopy()
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// gpuSharingMode returns the mode of a GPU sharing, Exclusive when it is not set
+func gpuSharingMode(sharing *workspacev1alpha1.GPUSharing) workspacev1alpha1.GPUSharingMode {
+	if sharing == nil || sharing.Mode == "" {
+		return workspacev1alpha1.GPUSharingExclusive
+	}
+	return sharing.Mode
+}
+
+// allowedGPUSharingModes returns the GPU sharing modes workspaces of the template can use:
+// allowedGPUSharingModes, or only the mode of gpuSharing when the list is empty
+func allowedGPUSharingModes(template *workspacev1alpha1.WorkspaceTemplate) []workspacev1alpha1.GPUSharingMode {
+	if len(template.Spec.AllowedGPUSharingModes) > 0 {
+		return template.Spec.AllowedGPUSharingModes
+	}
+	return []workspacev1alpha1.GPUSharingMode{gpuSharingMode(template.Spec.GPUSharing)}
+}
+
+// validateGPUSharing checks that a workspace uses a GPU sharing mode allowed by the template, and
+// keeps the rest of the template GPU sharing, which describes how the nodes share their GPUs and
+// the limits of MPS clients
+func validateGPUSharing(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	var violations []TemplateViolation
+
+	mode := gpuSharingMode(workspace.Spec.GPUSharing)
+	allowed := allowedGPUSharingModes(template)
+	if !slices.Contains(allowed, mode) {
+		allowedDescription := make([]string, 0, len(allowed))
+		for _, allowedMode := range allowed {
+			allowedDescription = append(allowedDescription, string(allowedMode))
+		}
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeGPUSharingModeNotAllowed,
+			Field:   "spec.gpuSharing.mode",
+			Message: fmt.Sprintf("GPU sharing mode '%s' is not allowed by template '%s'", mode, template.Name),
+			Allowed: strings.Join(allowedDescription, ", "),
+			Actual:  string(mode),
+		})
+	}
+
+	templateSharing := template.Spec.GPUSharing
+	if templateSharing == nil {
+		templateSharing = &workspacev1alpha1.GPUSharing{}
+	}
+	workspaceSharing := workspace.Spec.GPUSharing
+	if workspaceSharing == nil {
+		workspaceSharing = &workspacev1alpha1.GPUSharing{}
+	}
+	if workspaceSharing.SharedResourceName != templateSharing.SharedResourceName ||
+		!equality.Semantic.DeepEqual(workspaceSharing.MPS, templateSharing.MPS) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeGPUSharingMismatch,
+			Field:   "spec.gpuSharing",
+			Message: fmt.Sprintf("GPU sharing must match the GPU sharing of template '%s', except for its mode", template.Name),
+			Allowed: fmt.Sprintf("GPU sharing of template '%s'", template.Name),
+			Actual:  "modified GPU sharing",
+		})
+	}
+	return violations
+}
+
+// validateTemplateGPUSharingConsistency rejects a template whose GPU sharing mode is not one of its
+// allowed modes, which would make its own workspaces un-admittable
+func validateTemplateGPUSharingConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	mode := gpuSharingMode(template.Spec.GPUSharing)
+	if !slices.Contains(allowedGPUSharingModes(template), mode) {
+		return fmt.Errorf("gpuSharing mode %q is not one of the allowedGPUSharingModes of template %q",
+			mode, template.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testSharedGPUResource = "nvidia.com/gpu.shared"
+
+var _ = Describe("GPUSharingValidator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				GPUSharing: &workspacev1alpha1.GPUSharing{
+					Mode:               workspacev1alpha1.GPUSharingTimeSliced,
+					SharedResourceName: testSharedGPUResource,
+					MPS:                &workspacev1alpha1.GPUMPSConfig{ActiveThreadPercentage: ptr.To(int32(25))},
+				},
+				AllowedGPUSharingModes: []workspacev1alpha1.GPUSharingMode{
+					workspacev1alpha1.GPUSharingTimeSliced,
+					workspacev1alpha1.GPUSharingMPS,
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-workspace"},
+		}
+	})
+
+	Context("applyGPUSharingDefaults", func() {
+		It("should copy the template GPU sharing to a workspace without one", func() {
+			applyGPUSharingDefaults(workspace, template)
+			Expect(workspace.Spec.GPUSharing).To(Equal(template.Spec.GPUSharing))
+			Expect(workspace.Spec.GPUSharing).NotTo(BeIdenticalTo(template.Spec.GPUSharing))
+		})
+
+		It("should keep the mode of the workspace and fill the rest from the template", func() {
+			workspace.Spec.GPUSharing = &workspacev1alpha1.GPUSharing{Mode: workspacev1alpha1.GPUSharingMPS}
+			applyGPUSharingDefaults(workspace, template)
+			Expect(workspace.Spec.GPUSharing.Mode).To(Equal(workspacev1alpha1.GPUSharingMPS))
+			Expect(workspace.Spec.GPUSharing.SharedResourceName).To(Equal(testSharedGPUResource))
+			Expect(workspace.Spec.GPUSharing.MPS).To(Equal(template.Spec.GPUSharing.MPS))
+		})
+
+		It("should leave workspaces alone when the template has no GPU sharing", func() {
+			template.Spec.GPUSharing = nil
+			applyGPUSharingDefaults(workspace, template)
+			Expect(workspace.Spec.GPUSharing).To(BeNil())
+		})
+	})
+
+	Context("validateGPUSharing", func() {
+		It("should allow a defaulted workspace with an allowed mode", func() {
+			workspace.Spec.GPUSharing = &workspacev1alpha1.GPUSharing{Mode: workspacev1alpha1.GPUSharingMPS}
+			applyGPUSharingDefaults(workspace, template)
+			Expect(validateGPUSharing(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject a mode outside of the allowed modes", func() {
+			workspace.Spec.GPUSharing = &workspacev1alpha1.GPUSharing{Mode: workspacev1alpha1.GPUSharingExclusive}
+			applyGPUSharingDefaults(workspace, template)
+			violations := validateGPUSharing(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeGPUSharingModeNotAllowed))
+			Expect(violations[0].Allowed).To(Equal("TimeSliced, MPS"))
+		})
+
+		It("should reject a workspace without GPU sharing when the template requires a shared mode", func() {
+			violations := validateGPUSharing(workspace, template)
+			Expect(violations).NotTo(BeEmpty())
+			Expect(violations[0].Actual).To(Equal("Exclusive"))
+		})
+
+		It("should reject a workspace that changes the MPS limits of the template", func() {
+			workspace.Spec.GPUSharing = &workspacev1alpha1.GPUSharing{
+				Mode:               workspacev1alpha1.GPUSharingMPS,
+				SharedResourceName: testSharedGPUResource,
+				MPS:                &workspacev1alpha1.GPUMPSConfig{ActiveThreadPercentage: ptr.To(int32(100))},
+			}
+			violations := validateGPUSharing(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeGPUSharingMismatch))
+		})
+
+		It("should only allow exclusive GPUs when the template has no GPU sharing", func() {
+			template.Spec.GPUSharing = nil
+			template.Spec.AllowedGPUSharingModes = nil
+			Expect(validateGPUSharing(workspace, template)).To(BeEmpty())
+
+			workspace.Spec.GPUSharing = &workspacev1alpha1.GPUSharing{Mode: workspacev1alpha1.GPUSharingTimeSliced}
+			violations := validateGPUSharing(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeGPUSharingModeNotAllowed))
+		})
+	})
+
+	Context("validateTemplateGPUSharingConsistency", func() {
+		It("should accept a mode of the allowed modes", func() {
+			Expect(validateTemplateGPUSharingConsistency(template)).To(Succeed())
+		})
+
+		It("should reject a mode outside of the allowed modes", func() {
+			template.Spec.AllowedGPUSharingModes = []workspacev1alpha1.GPUSharingMode{workspacev1alpha1.GPUSharingMPS}
+			Expect(validateTemplateGPUSharingConsistency(template)).To(
+				MatchError(ContainSubstring(`gpuSharing mode "TimeSliced"`)))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Group resolver calls run in the admission path, so they are not retried
+const (
+	groupResolverTimeout      = 5 * time.Second
+	groupResolverMaxBodyBytes = 64 * 1024
+)
+
+// GroupResolverInterface resolves the groups of a user from an external directory, for groups
+// the API server does not include in admission requests
+type GroupResolverInterface interface {
+	// ResolveGroups returns the groups the user belongs to
+	ResolveGroups(ctx context.Context, username string) ([]string, error)
+}
+
+// GroupResolverWebhookRequest is the JSON body posted to the group resolver webhook
+type GroupResolverWebhookRequest struct {
+	Username string `json:"username"`
+}
+
+// GroupResolverWebhookResponse is the JSON body returned by the group resolver webhook
+type GroupResolverWebhookResponse struct {
+	Groups []string `json:"groups"`
+}
+
+// GroupResolverWebhook implements GroupResolverInterface by posting
+// GroupResolverWebhookRequests to an HTTP endpoint
+type GroupResolverWebhook struct {
+	endpoint string
+	client   *http.Client
+}
+
+var _ GroupResolverInterface = &GroupResolverWebhook{}
+
+// NewGroupResolverWebhook creates a GroupResolverWebhook posting to endpoint
+func NewGroupResolverWebhook(endpoint string) *GroupResolverWebhook {
+	return &GroupResolverWebhook{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: groupResolverTimeout},
+	}
+}
+
+// ResolveGroups posts a request for the user and returns the groups of the response
+func (r *GroupResolverWebhook) ResolveGroups(ctx context.Context, username string) ([]string, error) {
+	payload, err := json.Marshal(&GroupResolverWebhookRequest{Username: username})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal group resolver request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group resolver request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("group resolver call failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, groupResolverMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group resolver response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("group resolver rejected the request: status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var response GroupResolverWebhookResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid group resolver response: %w", err)
+	}
+	return response.Groups, nil
+}
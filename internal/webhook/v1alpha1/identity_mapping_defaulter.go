@@ -0,0 +1,211 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// workspaceIdentity is the UID and GID a workspace runs with
+type workspaceIdentity struct {
+	uid int64
+	gid int64
+}
+
+// IdentityMappingDefaulter sets the UID and GID of workspaces whose template has an identity mapping,
+// from the created-by annotation of the workspace. It runs after the template defaults and on every
+// admission, so that the mapped identity always replaces the IDs of the workspace security contexts.
+// ConfigMaps are read from the API server rather than the cache, so that the manager does not
+// watch every ConfigMap of the cluster.
+type IdentityMappingDefaulter struct {
+	reader   client.Reader
+	resolver *workspaceutil.TemplateResolver
+}
+
+// NewIdentityMappingDefaulter creates a new IdentityMappingDefaulter
+func NewIdentityMappingDefaulter(
+	k8sClient client.Client,
+	apiReader client.Reader,
+	defaultTemplateNamespace string,
+) *IdentityMappingDefaulter {
+	return &IdentityMappingDefaulter{
+		reader:   apiReader,
+		resolver: workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+	}
+}
+
+// ApplyIdentityMapping sets the UID and GID of the workspace from the identity mapping of its template.
+// When the identity cannot be resolved on an update that keeps the creator and the security contexts
+// of the workspace, these still carry the identity mapped on a previous admission, and are kept: an
+// unavailable ConfigMap then does not block stopping the workspace, or the updates of the controller.
+func (d *IdentityMappingDefaulter) ApplyIdentityMapping(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil
+	}
+	user := workspace.Annotations[controller.AnnotationCreatedBy]
+	if user == "" {
+		return nil
+	}
+
+	err := d.applyIdentityMapping(ctx, workspace, user)
+	if err != nil && isIdentityUnchanged(ctx, workspace) {
+		workspacelog.Error(err, "Keeping the identity of the workspace, failed to resolve its identity mapping",
+			"workspace", workspace.Name, "namespace", workspace.Namespace)
+		return nil
+	}
+	return err
+}
+
+// applyIdentityMapping resolves the identity of the user under the identity mapping of the template
+// of the workspace, if any, and sets it on the workspace
+func (d *IdentityMappingDefaulter) applyIdentityMapping(ctx context.Context, workspace *workspacev1alpha1.Workspace, user string) error {
+	template, err := d.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		return err
+	}
+	mapping := template.Spec.IdentityMapping
+	if mapping == nil {
+		return nil
+	}
+
+	identity, err := d.resolveIdentity(ctx, mapping, template, user)
+	if err != nil {
+		return err
+	}
+	applyWorkspaceIdentity(workspace, identity, mapping.SetFSGroup)
+	return nil
+}
+
+// isIdentityUnchanged returns whether the admission request updates the workspace without changing its
+// creator or the security contexts that carry its identity
+func isIdentityUnchanged(ctx context.Context, workspace *workspacev1alpha1.Workspace) bool {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		return false
+	}
+
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return false
+	}
+	return oldWorkspace.Annotations[controller.AnnotationCreatedBy] == workspace.Annotations[controller.AnnotationCreatedBy] &&
+		equality.Semantic.DeepEqual(oldWorkspace.Spec.PodSecurityContext, workspace.Spec.PodSecurityContext) &&
+		equality.Semantic.DeepEqual(oldWorkspace.Spec.ContainerSecurityContext, workspace.Spec.ContainerSecurityContext)
+}
+
+// resolveIdentity returns the UID and GID of the user under the identity mapping of the template
+func (d *IdentityMappingDefaulter) resolveIdentity(
+	ctx context.Context,
+	mapping *workspacev1alpha1.IdentityMapping,
+	template *workspacev1alpha1.WorkspaceTemplate,
+	user string,
+) (workspaceIdentity, error) {
+	var identity workspaceIdentity
+	switch mapping.Source {
+	case workspacev1alpha1.IdentityMappingHash:
+		identity = hashedIdentity(user, mapping.MinID, mapping.MaxID)
+	case workspacev1alpha1.IdentityMappingConfigMap:
+		configMap := &corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: template.Namespace, Name: mapping.ConfigMapName}
+		if err := d.reader.Get(ctx, key, configMap); err != nil {
+			return identity, errs.NotFound(err, "identity mapping ConfigMap '%s' of template '%s' not found",
+				mapping.ConfigMapName, template.Name)
+		}
+		entry, ok := configMap.Data[user]
+		if !ok {
+			return identity, errs.TemplateViolation(nil, "template '%s' has no identity mapping for user '%s'",
+				template.Name, user)
+		}
+		parsed, err := parseIdentity(entry)
+		if err != nil {
+			return identity, errs.Internal(err, "invalid identity mapping for user '%s' in template '%s'", user, template.Name)
+		}
+		identity = parsed
+	default:
+		return identity, fmt.Errorf("unknown identity mapping source %q", mapping.Source)
+	}
+
+	if mapping.GroupID != nil {
+		identity.gid = *mapping.GroupID
+	}
+	for _, id := range []int64{identity.uid, identity.gid} {
+		if id < mapping.MinID || id > mapping.MaxID {
+			return identity, errs.TemplateViolation(nil, "mapped ID %d of user '%s' is outside of the range [%d, %d] of template '%s'",
+				id, user, mapping.MinID, mapping.MaxID, template.Name)
+		}
+	}
+	return identity, nil
+}
+
+// hashedIdentity derives the UID of a user from the FNV-1a hash of the username, between minID and maxID.
+// The GID is the UID.
+func hashedIdentity(user string, minID, maxID int64) workspaceIdentity {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(user))
+	uid := minID + int64(uint64(hash.Sum32())%uint64(maxID-minID+1))
+	return workspaceIdentity{uid: uid, gid: uid}
+}
+
+// parseIdentity parses an identity mapping entry, "uid" or "uid:gid". The GID defaults to the UID.
+func parseIdentity(entry string) (workspaceIdentity, error) {
+	uidPart, gidPart, hasGID := strings.Cut(strings.TrimSpace(entry), ":")
+	uid, err := strconv.ParseInt(uidPart, 10, 64)
+	if err != nil {
+		return workspaceIdentity{}, fmt.Errorf("invalid UID %q: %w", uidPart, err)
+	}
+	gid := uid
+	if hasGID {
+		if gid, err = strconv.ParseInt(gidPart, 10, 64); err != nil {
+			return workspaceIdentity{}, fmt.Errorf("invalid GID %q: %w", gidPart, err)
+		}
+	}
+	return workspaceIdentity{uid: uid, gid: gid}, nil
+}
+
+// applyWorkspaceIdentity sets the UID and GID of the pod security context, and replaces those the main
+// container security context sets, which would otherwise take precedence. Init containers and the storage
+// provisioning hook are not rewritten, since the template validation compares them to those of the
+// template: the validating webhook rejects them instead when they set other IDs, see validateIdentityMapping.
+func applyWorkspaceIdentity(workspace *workspacev1alpha1.Workspace, identity workspaceIdentity, setFSGroup bool) {
+	if workspace.Spec.PodSecurityContext == nil {
+		workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{}
+	}
+	podSecurityContext := workspace.Spec.PodSecurityContext
+	podSecurityContext.RunAsUser = ptr.To(identity.uid)
+	podSecurityContext.RunAsGroup = ptr.To(identity.gid)
+	if setFSGroup {
+		podSecurityContext.FSGroup = ptr.To(identity.gid)
+	}
+
+	if containerSecurityContext := workspace.Spec.ContainerSecurityContext; containerSecurityContext != nil {
+		if containerSecurityContext.RunAsUser != nil {
+			containerSecurityContext.RunAsUser = ptr.To(identity.uid)
+		}
+		if containerSecurityContext.RunAsGroup != nil {
+			containerSecurityContext.RunAsGroup = ptr.To(identity.gid)
+		}
+	}
+}
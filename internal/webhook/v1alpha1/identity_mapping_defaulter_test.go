@@ -0,0 +1,222 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+)
+
+const (
+	testIdentityUser      = "alice"
+	testIdentityConfigMap = "uid-map"
+)
+
+var _ = Describe("IdentityMappingDefaulter", func() {
+	var (
+		ctx       context.Context
+		scheme    *runtime.Scheme
+		template  *workspacev1alpha1.WorkspaceTemplate
+		configMap *corev1.ConfigMap
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	newDefaulter := func(objects ...client.Object) *IdentityMappingDefaulter {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return NewIdentityMappingDefaulter(k8sClient, k8sClient, "")
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName: testTemplateDisplayName,
+				IdentityMapping: &workspacev1alpha1.IdentityMapping{
+					Source:        workspacev1alpha1.IdentityMappingConfigMap,
+					ConfigMapName: testIdentityConfigMap,
+					MinID:         1000,
+					MaxID:         60000,
+				},
+			},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: testIdentityConfigMap, Namespace: testDefaultNamespace},
+			Data:       map[string]string{testIdentityUser: "1500:2500", "bob": "1600"},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testWorkspaceName,
+				Namespace:   testDefaultNamespace,
+				Annotations: map[string]string{controller.AnnotationCreatedBy: testIdentityUser},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+			},
+		}
+	})
+
+	It("should map the creator from the ConfigMap", func() {
+		Expect(newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.PodSecurityContext.RunAsUser).To(HaveValue(Equal(int64(1500))))
+		Expect(workspace.Spec.PodSecurityContext.RunAsGroup).To(HaveValue(Equal(int64(2500))))
+		Expect(workspace.Spec.PodSecurityContext.FSGroup).To(BeNil())
+	})
+
+	It("should default the GID to the UID and set the fsGroup when asked", func() {
+		workspace.Annotations[controller.AnnotationCreatedBy] = "bob"
+		template.Spec.IdentityMapping.SetFSGroup = true
+
+		Expect(newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.PodSecurityContext.RunAsGroup).To(HaveValue(Equal(int64(1600))))
+		Expect(workspace.Spec.PodSecurityContext.FSGroup).To(HaveValue(Equal(int64(1600))))
+	})
+
+	It("should replace the IDs of the workspace security contexts", func() {
+		workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: int64Ptr(1000)}
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{RunAsUser: int64Ptr(0)}
+
+		Expect(newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.PodSecurityContext.RunAsUser).To(HaveValue(Equal(int64(1500))))
+		Expect(workspace.Spec.ContainerSecurityContext.RunAsUser).To(HaveValue(Equal(int64(1500))))
+		Expect(workspace.Spec.ContainerSecurityContext.RunAsGroup).To(BeNil())
+	})
+
+	It("should use the group ID of the template for every user", func() {
+		template.Spec.IdentityMapping.GroupID = int64Ptr(3000)
+
+		Expect(newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.PodSecurityContext.RunAsGroup).To(HaveValue(Equal(int64(3000))))
+	})
+
+	It("should reject a creator without a mapping", func() {
+		workspace.Annotations[controller.AnnotationCreatedBy] = "mallory"
+
+		err := newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)
+		Expect(errs.Is(err, errs.KindTemplateViolation)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("no identity mapping for user 'mallory'"))
+	})
+
+	It("should reject a mapped ID outside of the range", func() {
+		configMap.Data[testIdentityUser] = "0"
+
+		err := newDefaulter(template, configMap).ApplyIdentityMapping(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("outside of the range [1000, 60000]")))
+	})
+
+	It("should report a missing ConfigMap", func() {
+		err := newDefaulter(template).ApplyIdentityMapping(ctx, workspace)
+		Expect(errs.Is(err, errs.KindNotFound)).To(BeTrue())
+	})
+
+	Context("when updating a mapped workspace", func() {
+		// updateContext returns the context of an admission request updating oldWorkspace
+		updateContext := func(oldWorkspace *workspacev1alpha1.Workspace) context.Context {
+			raw, err := json.Marshal(oldWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+			return admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					OldObject: runtime.RawExtension{Raw: raw},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{
+				RunAsUser:  ptr.To(int64(1500)),
+				RunAsGroup: ptr.To(int64(2500)),
+			}
+		})
+
+		It("should keep the mapped identity when the ConfigMap is unavailable", func() {
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.DesiredStatus = controller.DesiredStateStopped
+
+			Expect(newDefaulter(template).ApplyIdentityMapping(updateContext(oldWorkspace), workspace)).To(Succeed())
+			Expect(workspace.Spec.PodSecurityContext.RunAsUser).To(HaveValue(Equal(int64(1500))))
+		})
+
+		It("should still fail when the update changes the security contexts", func() {
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.PodSecurityContext.RunAsUser = ptr.To(int64(1600))
+
+			err := newDefaulter(template).ApplyIdentityMapping(updateContext(oldWorkspace), workspace)
+			Expect(errs.Is(err, errs.KindNotFound)).To(BeTrue())
+		})
+
+		It("should still fail when the update changes the creator", func() {
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Annotations[controller.AnnotationCreatedBy] = "mallory"
+
+			err := newDefaulter(template, configMap).ApplyIdentityMapping(updateContext(oldWorkspace), workspace)
+			Expect(err).To(MatchError(ContainSubstring("no identity mapping for user 'mallory'")))
+		})
+
+		It("should apply a changed mapping when it resolves", func() {
+			oldWorkspace := workspace.DeepCopy()
+			configMap.Data[testIdentityUser] = "1700"
+
+			Expect(newDefaulter(template, configMap).ApplyIdentityMapping(updateContext(oldWorkspace), workspace)).To(Succeed())
+			Expect(workspace.Spec.PodSecurityContext.RunAsUser).To(HaveValue(Equal(int64(1700))))
+		})
+	})
+
+	It("should derive a stable UID within the range from the username hash", func() {
+		template.Spec.IdentityMapping = &workspacev1alpha1.IdentityMapping{
+			Source: workspacev1alpha1.IdentityMappingHash,
+			MinID:  10000,
+			MaxID:  20000,
+		}
+		defaulter := newDefaulter(template)
+
+		Expect(defaulter.ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+		uid := *workspace.Spec.PodSecurityContext.RunAsUser
+		Expect(uid).To(BeNumerically(">=", 10000))
+		Expect(uid).To(BeNumerically("<=", 20000))
+		Expect(workspace.Spec.PodSecurityContext.RunAsGroup).To(HaveValue(Equal(uid)))
+		Expect(hashedIdentity(testIdentityUser, 10000, 20000).uid).To(Equal(uid))
+	})
+
+	It("should leave workspaces of templates without identity mapping alone", func() {
+		template.Spec.IdentityMapping = nil
+
+		Expect(newDefaulter(template).ApplyIdentityMapping(ctx, workspace)).To(Succeed())
+		Expect(workspace.Spec.PodSecurityContext).To(BeNil())
+	})
+
+	It("should parse identity mapping entries", func() {
+		Expect(parseIdentity(" 1200 ")).To(Equal(workspaceIdentity{uid: 1200, gid: 1200}))
+		Expect(parseIdentity("1200:100")).To(Equal(workspaceIdentity{uid: 1200, gid: 100}))
+		_, err := parseIdentity("alice")
+		Expect(err).To(HaveOccurred())
+	})
+})
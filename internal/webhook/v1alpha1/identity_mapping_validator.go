@@ -0,0 +1,77 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateIdentityMapping checks that the init containers and the storage provisioning hook of a
+// workspace whose template has an identity mapping run with the UID and GID mapped to its creator.
+// The defaulting webhook sets these IDs on the pod security context, which the containers inherit
+// unless their own security context sets other IDs.
+func validateIdentityMapping(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	if template.Spec.IdentityMapping == nil {
+		return nil
+	}
+	var uid, gid *int64
+	if podSecurityContext := workspace.Spec.PodSecurityContext; podSecurityContext != nil {
+		uid, gid = podSecurityContext.RunAsUser, podSecurityContext.RunAsGroup
+	}
+
+	var violations []TemplateViolation
+	for i, initContainer := range workspace.Spec.InitContainers {
+		violations = append(violations, identityViolations(initContainer.SecurityContext, uid, gid,
+			fmt.Sprintf("spec.initContainers[%d].securityContext", i), template)...)
+	}
+	if hook := provisioningHook(workspace); hook != nil {
+		violations = append(violations, identityViolations(hook.SecurityContext, uid, gid,
+			"spec.storage.provisioningHook.securityContext", template)...)
+	}
+	return violations
+}
+
+// identityViolations lists the IDs a container security context sets other than the mapped UID and GID
+func identityViolations(
+	securityContext *corev1.SecurityContext,
+	uid, gid *int64,
+	field string,
+	template *workspacev1alpha1.WorkspaceTemplate,
+) []TemplateViolation {
+	if securityContext == nil {
+		return nil
+	}
+	var violations []TemplateViolation
+	if violation := identityViolation(securityContext.RunAsUser, uid, field+".runAsUser", template); violation != nil {
+		violations = append(violations, *violation)
+	}
+	if violation := identityViolation(securityContext.RunAsGroup, gid, field+".runAsGroup", template); violation != nil {
+		violations = append(violations, *violation)
+	}
+	return violations
+}
+
+// identityViolation returns a violation when id is set and differs from the mapped ID
+func identityViolation(id, mapped *int64, field string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if id == nil || (mapped != nil && *id == *mapped) {
+		return nil
+	}
+	allowed := "unset"
+	if mapped != nil {
+		allowed = fmt.Sprintf("unset or %d", *mapped)
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeIdentityMismatch,
+		Field:   field,
+		Message: fmt.Sprintf("%s must match the identity that template '%s' maps the workspace creator to", field, template.Name),
+		Allowed: allowed,
+		Actual:  fmt.Sprintf("%d", *id),
+	}
+}
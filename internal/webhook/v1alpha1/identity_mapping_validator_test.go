@@ -0,0 +1,91 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("validateIdentityMapping", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				IdentityMapping: &workspacev1alpha1.IdentityMapping{
+					Source: workspacev1alpha1.IdentityMappingHash,
+					MinID:  1000,
+					MaxID:  60000,
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+				PodSecurityContext: &corev1.PodSecurityContext{
+					RunAsUser:  ptr.To(int64(1500)),
+					RunAsGroup: ptr.To(int64(2500)),
+				},
+			},
+		}
+	})
+
+	It("should reject init containers running as another user", func() {
+		workspace.Spec.InitContainers = []corev1.Container{{
+			Name:            "setup",
+			SecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To(int64(1600))},
+		}}
+
+		violations := validateIdentityMapping(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Type).To(Equal(ViolationTypeIdentityMismatch))
+		Expect(violations[0].Field).To(Equal("spec.initContainers[0].securityContext.runAsUser"))
+	})
+
+	It("should reject a provisioning hook running with another group", func() {
+		workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+			ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{
+				Image:           testValidBaseNotebook,
+				SecurityContext: &corev1.SecurityContext{RunAsGroup: ptr.To(int64(0))},
+			},
+		}
+
+		violations := validateIdentityMapping(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Field).To(Equal("spec.storage.provisioningHook.securityContext.runAsGroup"))
+	})
+
+	It("should allow containers that inherit or repeat the mapped identity", func() {
+		workspace.Spec.InitContainers = []corev1.Container{
+			{Name: "inherit", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)}},
+			{Name: "repeat", SecurityContext: &corev1.SecurityContext{
+				RunAsUser:  ptr.To(int64(1500)),
+				RunAsGroup: ptr.To(int64(2500)),
+			}},
+		}
+		Expect(validateIdentityMapping(workspace, template)).To(BeEmpty())
+	})
+
+	It("should leave templates without identity mapping alone", func() {
+		template.Spec.IdentityMapping = nil
+		workspace.Spec.InitContainers = []corev1.Container{{
+			Name:            "setup",
+			SecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To(int64(0))},
+		}}
+		Expect(validateIdentityMapping(workspace, template)).To(BeEmpty())
+	})
+})
@@ -8,6 +8,7 @@ package v1alpha1
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
@@ -141,3 +142,29 @@ func validateIdleTimeoutBounds(
 		Actual:  fmt.Sprintf("%d", timeout),
 	}
 }
+
+// validateCullingPolicy rejects a culling exemption on a workspace whose template locks idle
+// shutdown (idleShutdownOverrides.allow=false): an exemption would bypass the locked policy.
+func validateCullingPolicy(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	culling := workspace.Spec.CullingPolicy
+	if culling == nil || (!culling.Disabled && culling.KeepAliveUntil == nil) {
+		return nil
+	}
+
+	policy := template.Spec.IdleShutdownOverrides
+	if policy == nil || policy.Allow == nil || *policy.Allow {
+		return nil
+	}
+
+	actual := "disabled"
+	if !culling.Disabled {
+		actual = "keepAliveUntil " + culling.KeepAliveUntil.UTC().Format(time.RFC3339)
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeCullingExemptionNotAllowed,
+		Field:   "spec.cullingPolicy",
+		Message: fmt.Sprintf("Template '%s' does not allow overriding idle shutdown; culling exemptions are not permitted", template.Name),
+		Allowed: "no culling exemption",
+		Actual:  actual,
+	}
+}
@@ -6,6 +6,8 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -241,4 +243,34 @@ var _ = Describe("Idle Shutdown Validator", func() {
 			Expect(validateIdleShutdownOverrides(workspace, template)).To(HaveLen(1))
 		})
 	})
+
+	Context("culling policy", func() {
+		It("should reject a culling exemption when idle shutdown overrides are locked", func() {
+			workspace.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{Disabled: true}
+			violation := validateCullingPolicy(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeCullingExemptionNotAllowed))
+			Expect(violation.Actual).To(Equal("disabled"))
+		})
+
+		It("should reject a keep-alive when idle shutdown overrides are locked", func() {
+			workspace.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{
+				KeepAliveUntil: &metav1.Time{Time: time.Now().Add(time.Hour)},
+			}
+			violation := validateCullingPolicy(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Field).To(Equal("spec.cullingPolicy"))
+		})
+
+		It("should allow a culling exemption when overrides are permitted", func() {
+			template.Spec.IdleShutdownOverrides.Allow = boolPtr(true)
+			workspace.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{Disabled: true}
+			Expect(validateCullingPolicy(workspace, template)).To(BeNil())
+		})
+
+		It("should allow an empty culling policy on a locked template", func() {
+			workspace.Spec.CullingPolicy = &workspacev1alpha1.CullingPolicySpec{}
+			Expect(validateCullingPolicy(workspace, template)).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,26 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyImagePullSecretsDefaults adds the template's ImagePullSecrets the workspace does not already list
+func applyImagePullSecretsDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	for _, secret := range template.Spec.ImagePullSecrets {
+		listed := false
+		for _, existing := range workspace.Spec.ImagePullSecrets {
+			if existing.Name == secret.Name {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			workspace.Spec.ImagePullSecrets = append(workspace.Spec.ImagePullSecrets, secret)
+		}
+	}
+}
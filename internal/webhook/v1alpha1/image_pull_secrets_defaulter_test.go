@@ -0,0 +1,54 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ImagePullSecretsDefaulter", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{}
+		template = &workspacev1alpha1.WorkspaceTemplate{}
+	})
+
+	It("should do nothing when template has no ImagePullSecrets", func() {
+		workspace.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "own-registry"}}
+
+		applyImagePullSecretsDefaults(workspace, template)
+
+		Expect(workspace.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "own-registry"}}))
+	})
+
+	It("should add the template secrets after the workspace secrets", func() {
+		workspace.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "own-registry"}}
+		template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "team-registry"}, {Name: "mirror"}}
+
+		applyImagePullSecretsDefaults(workspace, template)
+
+		Expect(workspace.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{
+			{Name: "own-registry"}, {Name: "team-registry"}, {Name: "mirror"},
+		}))
+	})
+
+	It("should not add a secret the workspace already lists", func() {
+		workspace.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "team-registry"}}
+		template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "team-registry"}}
+
+		applyImagePullSecretsDefaults(workspace, template)
+
+		Expect(workspace.Spec.ImagePullSecrets).To(HaveLen(1))
+	})
+})
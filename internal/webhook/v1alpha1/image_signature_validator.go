@@ -0,0 +1,55 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ImageVerifierInterface verifies the signature of a container image
+type ImageVerifierInterface interface {
+	// VerifyImage returns an error unless the image carries a valid signature
+	VerifyImage(ctx context.Context, image string) error
+}
+
+// ImageSignatureValidator rejects workspaces whose image is not pinned by digest or does not
+// carry a valid cosign signature. It applies to every user, including admins, since a signature policy is a
+// cluster-wide supply chain guarantee rather than a template constraint.
+// It is enabled with --image-signature-policy-file.
+type ImageSignatureValidator struct {
+	// verifier verifies the image signatures, nil when verification is disabled
+	verifier ImageVerifierInterface
+}
+
+// NewImageSignatureValidator creates a new ImageSignatureValidator. verifier may be nil, in
+// which case image signatures are not verified.
+func NewImageSignatureValidator(verifier ImageVerifierInterface) *ImageSignatureValidator {
+	return &ImageSignatureValidator{verifier: verifier}
+}
+
+// ValidateCreateWorkspace verifies the signature of the image of a new workspace
+func (v *ImageSignatureValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if v == nil || v.verifier == nil || workspace.Spec.Image == "" {
+		return nil
+	}
+	if err := v.verifier.VerifyImage(ctx, workspace.Spec.Image); err != nil {
+		return fmt.Errorf("image %q is not allowed: %w", workspace.Spec.Image, err)
+	}
+	return nil
+}
+
+// ValidateUpdateWorkspace verifies the signature of the image of an updated workspace.
+// Only a changed image is verified, so that workspaces admitted before the policy was
+// enabled can still be stopped, started or deleted.
+func (v *ImageSignatureValidator) ValidateUpdateWorkspace(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.Image == newWorkspace.Spec.Image {
+		return nil
+	}
+	return v.ValidateCreateWorkspace(ctx, newWorkspace)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// fakeImageVerifier accepts the images of its signed set and records the verified images
+type fakeImageVerifier struct {
+	signed   map[string]bool
+	verified []string
+}
+
+func (f *fakeImageVerifier) VerifyImage(_ context.Context, image string) error {
+	f.verified = append(f.verified, image)
+	if !f.signed[image] {
+		return errors.New("image signature verification failed: image has no signature")
+	}
+	return nil
+}
+
+var _ = Describe("Image Signature Validator", func() {
+	var (
+		ctx       context.Context
+		workspace *workspacev1alpha1.Workspace
+		verifier  *fakeImageVerifier
+		validator *ImageSignatureValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{Image: testImgA},
+		}
+		verifier = &fakeImageVerifier{signed: map[string]bool{testImgA: true}}
+		validator = NewImageSignatureValidator(verifier)
+	})
+
+	Context("ValidateCreateWorkspace", func() {
+		It("should allow signed images", func() {
+			Expect(validator.ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+			Expect(verifier.verified).To(Equal([]string{testImgA}))
+		})
+
+		It("should reject images without a valid signature", func() {
+			workspace.Spec.Image = testImgB
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(testImgB))
+			Expect(err.Error()).To(ContainSubstring("has no signature"))
+		})
+
+		It("should skip workspaces without an image", func() {
+			workspace.Spec.Image = ""
+			Expect(validator.ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+			Expect(verifier.verified).To(BeEmpty())
+		})
+
+		It("should allow any image when verification is disabled", func() {
+			workspace.Spec.Image = testImgB
+			Expect(NewImageSignatureValidator(nil).ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+
+			var disabled *ImageSignatureValidator
+			Expect(disabled.ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+		})
+	})
+
+	Context("ValidateUpdateWorkspace", func() {
+		It("should not verify an unchanged image", func() {
+			workspace.Spec.Image = testImgB
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.DesiredStatus = "Stopped"
+			Expect(validator.ValidateUpdateWorkspace(ctx, workspace, newWorkspace)).To(Succeed())
+			Expect(verifier.verified).To(BeEmpty())
+		})
+
+		It("should reject a changed image without a valid signature", func() {
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.Image = testImgB
+			Expect(validator.ValidateUpdateWorkspace(ctx, workspace, newWorkspace)).NotTo(Succeed())
+		})
+	})
+
+	Context("Workspace webhook", func() {
+		It("should verify images for admins too", func() {
+			workspaceValidator := &WorkspaceCustomValidator{
+				podSecurityValidator:    NewPodSecurityValidator(false),
+				imageSignatureValidator: validator,
+			}
+			workspace.Spec.Image = testImgB
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Spec.Image = testImgA
+
+			adminCtx := createUserContext(ctx, "UPDATE", "admin-user", "system:masters")
+			_, err := workspaceValidator.ValidateUpdate(adminCtx, oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has no signature"))
+		})
+	})
+})
@@ -7,63 +7,149 @@ package v1alpha1
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
 
-// validateImageAllowed checks if image is in template's allowed list
+// defaultImageRegistry is the registry of images whose reference names none
+const defaultImageRegistry = "docker.io"
+
+// validateImageAllowed checks if image matches the template's allowed images or registries
 func validateImageAllowed(image string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
 	// Skip validation if custom images are allowed
 	if template.Spec.AllowCustomImages != nil && *template.Spec.AllowCustomImages {
 		return nil
 	}
 
-	effectiveAllowedImages := template.Spec.AllowedImages
-	if len(template.Spec.AllowedImages) == 0 {
-		effectiveAllowedImages = []string{template.Spec.DefaultImage}
+	if imageAllowedByTemplate(image, template) {
+		return nil
 	}
 
-	for _, allowed := range effectiveAllowedImages {
-		if image == allowed {
-			return nil
-		}
+	allowed := effectiveAllowedImages(template)
+	if len(template.Spec.AllowedRegistries) > 0 {
+		allowed = fmt.Sprintf("%s, registries %v", allowed, template.Spec.AllowedRegistries)
 	}
-
 	return &TemplateViolation{
 		Type:    ViolationTypeImageNotAllowed,
 		Field:   "spec.image",
-		Message: fmt.Sprintf("Image '%s' is not allowed by template '%s'. Allowed images: %v", image, template.Name, effectiveAllowedImages),
-		Allowed: fmt.Sprintf("%v", effectiveAllowedImages),
+		Message: fmt.Sprintf("Image '%s' is not allowed by template '%s'. Allowed images: %s", image, template.Name, allowed),
+		Allowed: allowed,
 		Actual:  image,
 	}
 }
 
+// effectiveAllowedImages formats the allowed image patterns of the template, which fall back
+// to its default image when neither images nor registries are listed
+func effectiveAllowedImages(template *workspacev1alpha1.WorkspaceTemplate) string {
+	if len(template.Spec.AllowedImages) == 0 && len(template.Spec.AllowedRegistries) == 0 {
+		return fmt.Sprintf("%v", []string{template.Spec.DefaultImage})
+	}
+	return fmt.Sprintf("%v", template.Spec.AllowedImages)
+}
+
+// imageAllowedByTemplate returns whether image matches one of the allowed images or registries
+// of the template, or its default image when neither is listed
+func imageAllowedByTemplate(image string, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	if len(template.Spec.AllowedImages) == 0 && len(template.Spec.AllowedRegistries) == 0 {
+		return image == template.Spec.DefaultImage
+	}
+
+	for _, pattern := range template.Spec.AllowedImages {
+		// Invalid patterns are rejected at template admission; treat them as matching nothing
+		if matched, err := matchImagePattern(pattern, image); err == nil && matched {
+			return true
+		}
+	}
+	for _, registry := range template.Spec.AllowedRegistries {
+		if imageInRegistry(image, registry) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchImagePattern returns whether image matches an allowed image pattern: a regular expression
+// when the pattern starts with ^ and ends with $, else a glob where * matches any sequence of
+// characters, including / and :, and every other character matches itself
+func matchImagePattern(pattern, image string) (bool, error) {
+	re, err := compileImagePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(image), nil
+}
+
+// compileImagePattern compiles an allowed image pattern into an anchored regular expression
+func compileImagePattern(pattern string) (*regexp.Regexp, error) {
+	if isImageRegexPattern(pattern) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed image regular expression %q: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$"), nil
+}
+
+// isImageRegexPattern returns whether an allowed image pattern is a regular expression
+func isImageRegexPattern(pattern string) bool {
+	return len(pattern) > 1 && strings.HasPrefix(pattern, "^") && strings.HasSuffix(pattern, "$")
+}
+
+// imageInRegistry returns whether image is under the registry prefix, at a path boundary so that
+// ghcr.io/org does not match ghcr.io/organization/image. Images that name no registry are on
+// docker.io, like the container runtime pulls them.
+func imageInRegistry(image, registry string) bool {
+	return strings.HasPrefix(qualifyImageRegistry(image), registry+"/")
+}
+
+// qualifyImageRegistry prefixes an image that names no registry with the default registry. The
+// first component of an image names a registry when it contains a . or a :, or is localhost.
+func qualifyImageRegistry(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	return defaultImageRegistry + "/" + image
+}
+
+// validateAllowedImagePatterns rejects allowed image regular expressions that do not compile
+func validateAllowedImagePatterns(template *workspacev1alpha1.WorkspaceTemplate) error {
+	for _, pattern := range template.Spec.AllowedImages {
+		if _, err := compileImagePattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateTemplateImageConsistency rejects a template whose own defaultImage would be
-// un-creatable under its own image policy. When allowedImages is non-empty and custom images
-// are not allowed, defaultImage must be a member of allowedImages: otherwise the workspace
+// un-creatable under its own image policy. When allowedImages or allowedRegistries is non-empty
+// and custom images are not allowed, defaultImage must match one of them: otherwise the workspace
 // defaulter fills image=defaultImage for a workspace that omits one, and validateImageAllowed
 // then rejects that same image, making the template's default impossible to use (see #440).
 //
-// When allowedImages is empty, validateImageAllowed falls back to [defaultImage], so the
-// default is always creatable and no consistency check is needed.
+// When both are empty, validateImageAllowed falls back to [defaultImage], so the default is
+// always creatable.
 func validateTemplateImageConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
 	if template.Spec.AllowCustomImages != nil && *template.Spec.AllowCustomImages {
 		return nil
 	}
 
-	if len(template.Spec.AllowedImages) == 0 {
+	if imageAllowedByTemplate(template.Spec.DefaultImage, template) {
 		return nil
 	}
 
-	for _, allowed := range template.Spec.AllowedImages {
-		if template.Spec.DefaultImage == allowed {
-			return nil
-		}
-	}
-
 	return fmt.Errorf(
-		"defaultImage %q is not in allowedImages %v: the template default would be rejected by its own "+
-			"image policy, making it un-creatable (template %q)",
-		template.Spec.DefaultImage, template.Spec.AllowedImages, template.GetName(),
+		"defaultImage %q does not match allowedImages %v or allowedRegistries %v: the template default "+
+			"would be rejected by its own image policy, making it un-creatable (template %q)",
+		template.Spec.DefaultImage, template.Spec.AllowedImages, template.Spec.AllowedRegistries, template.GetName(),
 	)
 }
@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Image allowlist matching", func() {
+	DescribeTable("matchImagePattern",
+		func(pattern, image string, expected bool) {
+			matched, err := matchImagePattern(pattern, image)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(Equal(expected))
+		},
+		Entry("exact image", "jupyter/base-notebook:latest", "jupyter/base-notebook:latest", true),
+		Entry("exact image with another tag", "jupyter/base-notebook:latest", "jupyter/base-notebook:2025.01", false),
+		Entry("repository glob", "ghcr.io/org/*", "ghcr.io/org/notebook:1.0", true),
+		Entry("repository glob across path components", "ghcr.io/org/*", "ghcr.io/org/team/notebook:1.0", true),
+		Entry("repository glob on another organization", "ghcr.io/org/*", "ghcr.io/organization/notebook:1.0", false),
+		Entry("tag glob", "*:2025.*", "quay.io/jupyter/scipy-notebook:2025.01", true),
+		Entry("tag glob on another tag", "*:2025.*", "quay.io/jupyter/scipy-notebook:2024.12", false),
+		Entry("dots are literal in globs", "ghcr.io/org/*", "ghcrxio/org/notebook", false),
+		Entry("regular expression", `^ghcr\.io/org/[a-z-]+:v[0-9]+$`, "ghcr.io/org/notebook:v2", true),
+		Entry("regular expression on another tag", `^ghcr\.io/org/[a-z-]+:v[0-9]+$`, "ghcr.io/org/notebook:latest", false),
+	)
+
+	It("should reject an invalid regular expression", func() {
+		_, err := matchImagePattern("^ghcr.io/(org$", "ghcr.io/org/notebook")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("imageInRegistry",
+		func(image, registry string, expected bool) {
+			Expect(imageInRegistry(image, registry)).To(Equal(expected))
+		},
+		Entry("image in registry", "ghcr.io/org/notebook:1.0", "ghcr.io", true),
+		Entry("image in registry path", "ghcr.io/org/notebook:1.0", "ghcr.io/org", true),
+		Entry("prefix without path boundary", "ghcr.io/organization/notebook:1.0", "ghcr.io/org", false),
+		Entry("image in another registry", "quay.io/org/notebook:1.0", "ghcr.io", false),
+		Entry("image without registry is on docker.io", "jupyter/base-notebook:latest", "docker.io", true),
+		Entry("official image is on docker.io", "python:3.12", "docker.io/python", false),
+		Entry("image without registry in docker.io path", "jupyter/base-notebook:latest", "docker.io/jupyter", true),
+		Entry("registry with port", "registry.local:5000/notebook:1.0", "registry.local:5000", true),
+		Entry("localhost registry", "localhost/notebook:1.0", "localhost", true),
+	)
+
+	Context("validateImageAllowed", func() {
+		var template *workspacev1alpha1.WorkspaceTemplate
+
+		BeforeEach(func() {
+			template = &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DefaultImage: "ghcr.io/org/notebook:1.0",
+				},
+			}
+		})
+
+		It("should allow images of an allowed registry", func() {
+			template.Spec.AllowedRegistries = []string{"ghcr.io/org"}
+			Expect(validateImageAllowed("ghcr.io/org/other:2.0", template)).To(BeNil())
+		})
+
+		It("should not fall back to the default image when only registries are allowed", func() {
+			template.Spec.AllowedRegistries = []string{"quay.io"}
+			violation := validateImageAllowed(template.Spec.DefaultImage, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Allowed).To(ContainSubstring("registries [quay.io]"))
+		})
+
+		It("should allow images matching an allowed image or an allowed registry", func() {
+			template.Spec.AllowedImages = []string{"*:2025.*"}
+			template.Spec.AllowedRegistries = []string{"ghcr.io"}
+			Expect(validateImageAllowed("quay.io/jupyter/scipy-notebook:2025.01", template)).To(BeNil())
+			Expect(validateImageAllowed("ghcr.io/org/notebook:1.0", template)).To(BeNil())
+			Expect(validateImageAllowed("quay.io/jupyter/scipy-notebook:2024.12", template)).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,18 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyJupyterServerConfigDefaults applies the Jupyter server config of the template, which
+// workspaces of the template cannot change
+func applyJupyterServerConfigDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.JupyterServerConfig == nil && template.Spec.JupyterServerConfig != nil {
+		workspace.Spec.JupyterServerConfig = template.Spec.JupyterServerConfig.DeepCopy()
+	}
+}
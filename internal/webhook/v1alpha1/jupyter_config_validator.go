@@ -0,0 +1,30 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateJupyterServerConfig checks that a workspace keeps the Jupyter server config of its template,
+// since workspace owners could otherwise re-enable the terminals or extensions the template disables
+func validateJupyterServerConfig(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	config := template.Spec.JupyterServerConfig
+	if config == nil || equality.Semantic.DeepEqual(workspace.Spec.JupyterServerConfig, config) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeJupyterServerConfigMismatch,
+		Field:   "spec.jupyterServerConfig",
+		Message: fmt.Sprintf("jupyter server config must match the jupyter server config of template '%s'", template.Name),
+		Allowed: fmt.Sprintf("jupyter server config of template '%s'", template.Name),
+		Actual:  "modified jupyter server config",
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("JupyterServerConfigValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				JupyterServerConfig: &workspacev1alpha1.JupyterServerConfig{
+					DefaultKernel:    "python3",
+					TerminalsEnabled: ptr.To(false),
+					Extensions:       map[string]bool{"jupyterlab_git": true},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+			},
+		}
+	})
+
+	It("should accept any workspace when the template has no jupyter server config", func() {
+		template.Spec.JupyterServerConfig = nil
+		workspace.Spec.JupyterServerConfig = &workspacev1alpha1.JupyterServerConfig{DefaultKernel: "ir"}
+		Expect(validateJupyterServerConfig(workspace, template)).To(BeNil())
+	})
+
+	It("should accept a workspace defaulted with the template jupyter server config", func() {
+		applyJupyterServerConfigDefaults(workspace, template)
+		Expect(workspace.Spec.JupyterServerConfig).To(Equal(template.Spec.JupyterServerConfig))
+		Expect(validateJupyterServerConfig(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a workspace that re-enables terminals", func() {
+		workspace.Spec.JupyterServerConfig = template.Spec.JupyterServerConfig.DeepCopy()
+		workspace.Spec.JupyterServerConfig.TerminalsEnabled = ptr.To(true)
+		violation := validateJupyterServerConfig(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeJupyterServerConfigMismatch))
+		Expect(violation.Field).To(Equal("spec.jupyterServerConfig"))
+	})
+
+	It("should reset a modified jupyter server config on migration", func() {
+		workspace.Spec.JupyterServerConfig = &workspacev1alpha1.JupyterServerConfig{DefaultKernel: "ir"}
+		Expect(migrateTemplateViolations(workspace, template)).To(ContainElement("spec.jupyterServerConfig"))
+		Expect(workspace.Spec.JupyterServerConfig).To(BeNil())
+	})
+})
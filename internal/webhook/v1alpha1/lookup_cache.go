@@ -0,0 +1,233 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultLookupCacheTTL is how long the admission webhooks reuse a WorkspaceTemplate or
+// WorkspaceAccessStrategy they read, unless the informer delivers a change to it first
+const DefaultLookupCacheTTL = 5 * time.Second
+
+// Kinds of the objects held by the LookupCache
+const (
+	lookupKindTemplate       = "WorkspaceTemplate"
+	lookupKindAccessStrategy = "WorkspaceAccessStrategy"
+)
+
+// webhookLookupCacheReads counts the template and access strategy reads of the admission webhooks,
+// by kind and by whether the LookupCache served them
+var webhookLookupCacheReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "workspace_webhook_lookup_cache_reads_total",
+	Help: "Number of WorkspaceTemplate and WorkspaceAccessStrategy reads of the admission webhooks, by kind and result (hit or miss)",
+}, []string{"kind", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(webhookLookupCacheReads)
+}
+
+// lookupKey identifies an object of the LookupCache
+type lookupKey struct {
+	kind string
+	key  types.NamespacedName
+}
+
+// lookupEntry is the result of a read: the object, or the NotFound error
+type lookupEntry struct {
+	object  client.Object
+	err     error
+	expires time.Time
+}
+
+// LookupCache holds the WorkspaceTemplates and WorkspaceAccessStrategies read by the admission
+// webhooks for a short TTL. A single admission resolves the template of a workspace in each of its
+// defaulters and validators, every one of them copying it out of the informer cache, and tries the
+// workspace namespace before the shared template namespace. The LookupCache serves the repeated reads,
+// including the NotFound ones, and the informers drop an entry as soon as its object changes.
+//
+// The LookupCache sits below the TemplateFreshness, which still reads templates older than an
+// admitted update from the API server.
+type LookupCache struct {
+	mu      sync.Mutex
+	entries map[lookupKey]lookupEntry
+	// epoch counts the invalidations, so that a read that raced one is not stored
+	epoch     uint64
+	nextSweep time.Time
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewLookupCache creates a LookupCache holding the objects for ttl
+func NewLookupCache(ttl time.Duration) *LookupCache {
+	return &LookupCache{
+		entries: make(map[lookupKey]lookupEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// SetupLookupCacheWithManager creates a LookupCache and registers it with the manager's
+// WorkspaceTemplate and WorkspaceAccessStrategy informers
+func SetupLookupCacheWithManager(mgr ctrl.Manager) (*LookupCache, error) {
+	lc := NewLookupCache(DefaultLookupCacheTTL)
+
+	invalidate := func(obj interface{}) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if object, ok := obj.(client.Object); ok {
+			lc.Invalidate(object)
+		}
+	}
+	for _, object := range []client.Object{&workspacev1alpha1.WorkspaceTemplate{}, &workspacev1alpha1.WorkspaceAccessStrategy{}} {
+		informer, err := mgr.GetCache().GetInformer(context.Background(), object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s informer: %w", lookupKind(object), err)
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    invalidate,
+			UpdateFunc: func(_, newObj interface{}) { invalidate(newObj) },
+			DeleteFunc: invalidate,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add event handler to %s informer: %w", lookupKind(object), err)
+		}
+	}
+
+	return lc, nil
+}
+
+// Invalidate drops the entry of a changed object
+func (lc *LookupCache) Invalidate(obj client.Object) {
+	if lc == nil {
+		return
+	}
+	kind := lookupKind(obj)
+	if kind == "" {
+		return
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.epoch++
+	delete(lc.entries, lookupKey{kind: kind, key: client.ObjectKeyFromObject(obj)})
+}
+
+// load copies the entry of the key into obj, and returns whether it was found and its error
+func (lc *LookupCache) load(kind string, key types.NamespacedName, obj client.Object) (bool, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	entry, ok := lc.entries[lookupKey{kind: kind, key: key}]
+	if !ok {
+		return false, nil
+	}
+	if lc.now().After(entry.expires) {
+		delete(lc.entries, lookupKey{kind: kind, key: key})
+		return false, nil
+	}
+	if entry.err != nil {
+		return true, entry.err
+	}
+	copyLookupObject(entry.object, obj)
+	return true, nil
+}
+
+// store records the result of a read started at epoch, unless an invalidation happened since
+func (lc *LookupCache) store(kind string, key types.NamespacedName, obj client.Object, err error, epoch uint64) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.epoch != epoch {
+		return
+	}
+	now := lc.now()
+	if now.After(lc.nextSweep) {
+		for k, entry := range lc.entries {
+			if now.After(entry.expires) {
+				delete(lc.entries, k)
+			}
+		}
+		lc.nextSweep = now.Add(lc.ttl)
+	}
+	entry := lookupEntry{err: err, expires: now.Add(lc.ttl)}
+	if err == nil {
+		entry.object = obj.DeepCopyObject().(client.Object)
+	}
+	lc.entries[lookupKey{kind: kind, key: key}] = entry
+}
+
+func (lc *LookupCache) currentEpoch() uint64 {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.epoch
+}
+
+// Client wraps a cached client so that its template and access strategy reads go through the LookupCache
+func (lc *LookupCache) Client(k8sClient client.Client) client.Client {
+	if lc == nil {
+		return k8sClient
+	}
+	return &lookupCacheClient{Client: k8sClient, cache: lc}
+}
+
+// lookupCacheClient reads WorkspaceTemplates and WorkspaceAccessStrategies through a LookupCache
+type lookupCacheClient struct {
+	client.Client
+	cache *LookupCache
+}
+
+// Get implements client.Reader
+func (c *lookupCacheClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	kind := lookupKind(obj)
+	if kind == "" || len(opts) > 0 {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	if ok, err := c.cache.load(kind, key, obj); ok {
+		webhookLookupCacheReads.WithLabelValues(kind, "hit").Inc()
+		return err
+	}
+
+	webhookLookupCacheReads.WithLabelValues(kind, "miss").Inc()
+	epoch := c.cache.currentEpoch()
+	err := c.Client.Get(ctx, key, obj)
+	if err == nil || apierrors.IsNotFound(err) {
+		c.cache.store(kind, key, obj, err, epoch)
+	}
+	return err
+}
+
+// lookupKind returns the kind of the objects the LookupCache holds, or an empty string
+func lookupKind(obj client.Object) string {
+	switch obj.(type) {
+	case *workspacev1alpha1.WorkspaceTemplate:
+		return lookupKindTemplate
+	case *workspacev1alpha1.WorkspaceAccessStrategy:
+		return lookupKindAccessStrategy
+	default:
+		return ""
+	}
+}
+
+// copyLookupObject deep copies a cached object into obj, of the same kind
+func copyLookupObject(cached, obj client.Object) {
+	switch out := obj.(type) {
+	case *workspacev1alpha1.WorkspaceTemplate:
+		cached.(*workspacev1alpha1.WorkspaceTemplate).DeepCopyInto(out)
+	case *workspacev1alpha1.WorkspaceAccessStrategy:
+		cached.(*workspacev1alpha1.WorkspaceAccessStrategy).DeepCopyInto(out)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("LookupCache", func() {
+	var (
+		ctx         context.Context
+		lookupCache *LookupCache
+		baseClient  client.Client
+		reads       int
+		onRead      func()
+		now         time.Time
+		templateKey types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		now = time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+		templateKey = types.NamespacedName{Name: "lookup-template", Namespace: testDefaultNamespace}
+		reads = 0
+		onRead = nil
+
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: templateKey.Name, Namespace: templateKey.Namespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  "Lookup Template",
+				DefaultImage: testValidBaseNotebook,
+			},
+		}
+		baseClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					reads++
+					err := c.Get(ctx, key, obj, opts...)
+					if onRead != nil {
+						onRead()
+					}
+					return err
+				},
+			}).Build()
+
+		lookupCache = NewLookupCache(DefaultLookupCacheTTL)
+		lookupCache.now = func() time.Time { return now }
+	})
+
+	getTemplate := func(c client.Client, key types.NamespacedName) (*workspacev1alpha1.WorkspaceTemplate, error) {
+		template := &workspacev1alpha1.WorkspaceTemplate{}
+		err := c.Get(ctx, key, template)
+		return template, err
+	}
+
+	It("should serve repeated reads from the cache", func() {
+		lookupClient := lookupCache.Client(baseClient)
+
+		first, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		first.Spec.DefaultImage = "mutated-by-the-caller"
+
+		second, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Spec.DefaultImage).To(Equal(testValidBaseNotebook), "callers should get their own copy")
+		Expect(reads).To(Equal(1))
+	})
+
+	It("should cache templates that do not exist", func() {
+		lookupClient := lookupCache.Client(baseClient)
+		missingKey := types.NamespacedName{Name: "missing-template", Namespace: testDefaultNamespace}
+
+		_, err := getTemplate(lookupClient, missingKey)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		_, err = getTemplate(lookupClient, missingKey)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(reads).To(Equal(1))
+	})
+
+	It("should read the template again once the informer delivers a change", func() {
+		lookupClient := lookupCache.Client(baseClient)
+		template, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		template.Spec.DefaultImage = "updated-image"
+		Expect(baseClient.Update(ctx, template)).To(Succeed())
+		lookupCache.Invalidate(template)
+
+		updated, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Spec.DefaultImage).To(Equal("updated-image"))
+		Expect(reads).To(Equal(2))
+	})
+
+	It("should read the template again once its entry expires", func() {
+		lookupClient := lookupCache.Client(baseClient)
+		_, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(DefaultLookupCacheTTL + time.Second)
+		_, err = getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reads).To(Equal(2))
+	})
+
+	It("should not store a read that raced an invalidation", func() {
+		lookupClient := lookupCache.Client(baseClient)
+		onRead = func() {
+			lookupCache.Invalidate(&workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: templateKey.Name, Namespace: templateKey.Namespace},
+			})
+		}
+		_, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		onRead = nil
+		_, err = getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reads).To(Equal(2))
+	})
+
+	It("should read other kinds through the wrapped client", func() {
+		lookupClient := lookupCache.Client(baseClient)
+		workspace := &workspacev1alpha1.Workspace{}
+		key := types.NamespacedName{Name: "any-workspace", Namespace: testDefaultNamespace}
+
+		Expect(apierrors.IsNotFound(lookupClient.Get(ctx, key, workspace))).To(BeTrue())
+		Expect(apierrors.IsNotFound(lookupClient.Get(ctx, key, workspace))).To(BeTrue())
+		Expect(reads).To(Equal(2))
+	})
+
+	It("should read through the wrapped client when disabled", func() {
+		var disabled *LookupCache
+		lookupClient := disabled.Client(baseClient)
+
+		_, err := getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = getTemplate(lookupClient, templateKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reads).To(Equal(2))
+	})
+})
@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultMaxAnnotationsSize is the default limit on the total size of a workspace's annotations
+const DefaultMaxAnnotationsSize = 32 * 1024
+
+// DefaultMaxLabels is the default limit on the number of labels of a workspace
+const DefaultMaxLabels = 64
+
+// MetadataLimits bounds the labels and annotations users may set on workspaces.
+// Workspace metadata is stored in etcd and rendered into access resource templates,
+// so cluster admins cap its size and reserve key prefixes used by their own tooling.
+type MetadataLimits struct {
+	// MaxAnnotationsSize is the maximum total size in bytes of annotation keys and values (0 = unlimited)
+	MaxAnnotationsSize int
+	// MaxLabels is the maximum number of labels (0 = unlimited)
+	MaxLabels int
+	// ReservedKeyPrefixes lists label and annotation key prefixes users may not set,
+	// in addition to the system prefix workspace.jupyter.org/
+	ReservedKeyPrefixes []string
+}
+
+// MetadataLimitsValidator enforces MetadataLimits on workspaces submitted by users
+type MetadataLimitsValidator struct {
+	limits MetadataLimits
+}
+
+// NewMetadataLimitsValidator creates a new MetadataLimitsValidator
+func NewMetadataLimitsValidator(limits MetadataLimits) *MetadataLimitsValidator {
+	return &MetadataLimitsValidator{limits: limits}
+}
+
+// ValidateCreateWorkspace checks the metadata of a new workspace against the limits
+func (v *MetadataLimitsValidator) ValidateCreateWorkspace(workspace *workspacev1alpha1.Workspace) error {
+	if err := v.checkSize(workspace); err != nil {
+		return err
+	}
+	if err := v.checkReservedKeys(nil, workspace.Labels, "label"); err != nil {
+		return err
+	}
+	return v.checkReservedKeys(nil, workspace.Annotations, "annotation")
+}
+
+// ValidateUpdateWorkspace checks the metadata of an updated workspace against the limits.
+// Size limits are only enforced when the labels or annotations change, so that lowering
+// a limit does not block unrelated updates of existing workspaces.
+func (v *MetadataLimitsValidator) ValidateUpdateWorkspace(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if !maps.Equal(oldWorkspace.Labels, newWorkspace.Labels) || !maps.Equal(oldWorkspace.Annotations, newWorkspace.Annotations) {
+		if err := v.checkSize(newWorkspace); err != nil {
+			return err
+		}
+	}
+	if err := v.checkReservedKeys(oldWorkspace.Labels, newWorkspace.Labels, "label"); err != nil {
+		return err
+	}
+	return v.checkReservedKeys(oldWorkspace.Annotations, newWorkspace.Annotations, "annotation")
+}
+
+// checkSize rejects workspaces whose annotations or labels exceed the limits
+func (v *MetadataLimitsValidator) checkSize(workspace *workspacev1alpha1.Workspace) error {
+	if v.limits.MaxAnnotationsSize > 0 {
+		size := 0
+		for key, value := range workspace.Annotations {
+			size += len(key) + len(value)
+		}
+		if size > v.limits.MaxAnnotationsSize {
+			return fmt.Errorf("annotations total %d bytes, exceeding the limit of %d bytes", size, v.limits.MaxAnnotationsSize)
+		}
+	}
+	if v.limits.MaxLabels > 0 && len(workspace.Labels) > v.limits.MaxLabels {
+		return fmt.Errorf("workspace has %d labels, exceeding the limit of %d", len(workspace.Labels), v.limits.MaxLabels)
+	}
+	return nil
+}
+
+// checkReservedKeys rejects additions, changes and removals of keys under the admin-reserved prefixes.
+// Pass nil oldMeta on create.
+func (v *MetadataLimitsValidator) checkReservedKeys(oldMeta, newMeta map[string]string, kind string) error {
+	for key, newVal := range newMeta {
+		prefix := v.reservedPrefix(key)
+		if prefix == "" {
+			continue
+		}
+		if oldVal, existed := oldMeta[key]; !existed || oldVal != newVal {
+			return fmt.Errorf("%s '%s' uses reserved prefix %s", kind, key, prefix)
+		}
+	}
+	for key := range oldMeta {
+		if _, exists := newMeta[key]; !exists && v.reservedPrefix(key) != "" {
+			return fmt.Errorf("%s '%s' cannot be removed", kind, key)
+		}
+	}
+	return nil
+}
+
+// reservedPrefix returns the admin-reserved prefix of key, or "" when the key is not reserved
+func (v *MetadataLimitsValidator) reservedPrefix(key string) string {
+	for _, prefix := range v.limits.ReservedKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
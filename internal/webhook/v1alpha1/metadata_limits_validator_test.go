@@ -0,0 +1,112 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Metadata Limits Validator", func() {
+	const reservedKey = "billing.example.com/cost-center"
+
+	var (
+		workspace *workspacev1alpha1.Workspace
+		validator *MetadataLimitsValidator
+	)
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+		}
+		validator = NewMetadataLimitsValidator(MetadataLimits{
+			MaxAnnotationsSize:  64,
+			MaxLabels:           2,
+			ReservedKeyPrefixes: []string{"billing.example.com/"},
+		})
+	})
+
+	Context("ValidateCreateWorkspace", func() {
+		It("should allow metadata within the limits", func() {
+			workspace.Labels = map[string]string{testLabelKeyTeam: testDataScience}
+			workspace.Annotations = map[string]string{"note": "test"}
+			Expect(validator.ValidateCreateWorkspace(workspace)).To(Succeed())
+		})
+
+		It("should reject annotations exceeding the size limit", func() {
+			workspace.Annotations = map[string]string{"note": strings.Repeat("x", 61)}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exceeding the limit of 64 bytes"))
+		})
+
+		It("should reject too many labels", func() {
+			workspace.Labels = map[string]string{"a": "1", "b": "2", "c": "3"}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("3 labels"))
+		})
+
+		It("should reject labels and annotations with an admin-reserved prefix", func() {
+			workspace.Labels = map[string]string{reservedKey: "42"}
+			Expect(validator.ValidateCreateWorkspace(workspace)).To(MatchError(ContainSubstring("reserved prefix")))
+
+			workspace.Labels = nil
+			workspace.Annotations = map[string]string{reservedKey: "42"}
+			Expect(validator.ValidateCreateWorkspace(workspace)).To(MatchError(ContainSubstring("reserved prefix")))
+		})
+
+		It("should not limit metadata when limits are unset", func() {
+			validator = NewMetadataLimitsValidator(MetadataLimits{})
+			workspace.Annotations = map[string]string{"note": strings.Repeat("x", 1024)}
+			workspace.Labels = map[string]string{}
+			for i := range 100 {
+				workspace.Labels[fmt.Sprintf("label-%d", i)] = "v"
+			}
+			Expect(validator.ValidateCreateWorkspace(workspace)).To(Succeed())
+		})
+	})
+
+	Context("ValidateUpdateWorkspace", func() {
+		var oldWorkspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			workspace.Annotations = map[string]string{"note": strings.Repeat("x", 100)}
+			workspace.Labels = map[string]string{reservedKey: "42"}
+			oldWorkspace = workspace.DeepCopy()
+		})
+
+		It("should allow updates that leave oversized metadata unchanged", func() {
+			workspace.Spec.DisplayName = "renamed"
+			Expect(validator.ValidateUpdateWorkspace(oldWorkspace, workspace)).To(Succeed())
+		})
+
+		It("should reject metadata changes that stay over the limit", func() {
+			workspace.Annotations["other"] = "y"
+			err := validator.ValidateUpdateWorkspace(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exceeding the limit"))
+		})
+
+		It("should reject changing or removing a key with an admin-reserved prefix", func() {
+			workspace.Annotations = map[string]string{}
+			workspace.Labels[reservedKey] = "43"
+			Expect(validator.ValidateUpdateWorkspace(oldWorkspace, workspace)).To(MatchError(ContainSubstring("reserved prefix")))
+
+			workspace.Labels = map[string]string{}
+			Expect(validator.ValidateUpdateWorkspace(oldWorkspace, workspace)).To(MatchError(ContainSubstring("cannot be removed")))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateNetworkIsolation checks that a workspace keeps the network isolation of its template, since
+// workspace owners could otherwise open their workspace to any namespace or address
+func validateNetworkIsolation(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	isolation := template.Spec.NetworkIsolation
+	if isolation == nil || equality.Semantic.DeepEqual(workspace.Spec.NetworkIsolation, isolation) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeNetworkIsolationMismatch,
+		Field:   "spec.networkIsolation",
+		Message: fmt.Sprintf("network isolation must match the network isolation of template '%s'", template.Name),
+		Allowed: fmt.Sprintf("network isolation of template '%s'", template.Name),
+		Actual:  "modified network isolation",
+	}
+}
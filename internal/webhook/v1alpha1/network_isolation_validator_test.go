@@ -0,0 +1,63 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("NetworkIsolationValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				NetworkIsolation: &workspacev1alpha1.NetworkIsolation{
+					IngressControllerNamespace: "traefik",
+					AllowedCIDRs:               []string{"10.0.0.0/16"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+			},
+		}
+	})
+
+	It("should accept any workspace when the template has no network isolation", func() {
+		template.Spec.NetworkIsolation = nil
+		workspace.Spec.NetworkIsolation = &workspacev1alpha1.NetworkIsolation{AllowedCIDRs: []string{"0.0.0.0/0"}}
+		Expect(validateNetworkIsolation(workspace, template)).To(BeNil())
+	})
+
+	It("should accept a workspace defaulted with the template network isolation", func() {
+		applySecurityDefaults(workspace, template)
+		Expect(workspace.Spec.NetworkIsolation).To(Equal(template.Spec.NetworkIsolation))
+		Expect(validateNetworkIsolation(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a workspace that opens its network isolation", func() {
+		workspace.Spec.NetworkIsolation = template.Spec.NetworkIsolation.DeepCopy()
+		workspace.Spec.NetworkIsolation.AllowedCIDRs = append(workspace.Spec.NetworkIsolation.AllowedCIDRs, "0.0.0.0/0")
+		violation := validateNetworkIsolation(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeNetworkIsolationMismatch))
+		Expect(violation.Field).To(Equal("spec.networkIsolation"))
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validateObjectStorageMountsRequireTemplate rejects object storage mounts on a workspace without
+// template, since the template lists the buckets workspaces can mount
+func validateObjectStorageMountsRequireTemplate(workspace *workspacev1alpha1.Workspace) error {
+	if len(workspace.Spec.ObjectStorageMounts) > 0 {
+		return fmt.Errorf("object storage mount %q requires a templateRef", workspace.Spec.ObjectStorageMounts[0].Name)
+	}
+	return nil
+}
+
+// validateObjectStorageMounts checks that object storage mounts use buckets the template allows, and
+// are mounted under the mount paths the template allows. Rejects any mount when the template allows
+// no bucket (secure by default).
+func validateObjectStorageMounts(mounts []workspacev1alpha1.ObjectStorageMount, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	var violations []TemplateViolation
+	for _, mount := range mounts {
+		url := controller.ObjectStorageURL(mount)
+		if !bucketAllowed(url, template.Spec.AllowedBuckets) {
+			allowed := "no bucket"
+			if len(template.Spec.AllowedBuckets) > 0 {
+				allowed = strings.Join(template.Spec.AllowedBuckets, ", ")
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeBucketNotAllowed,
+				Field:   fmt.Sprintf("spec.objectStorageMounts[%s].bucket", mount.Name),
+				Message: fmt.Sprintf("Bucket '%s' of object storage mount '%s' is not allowed by template '%s'", url, mount.Name, template.Name),
+				Allowed: allowed,
+				Actual:  url,
+			})
+		}
+		if !volumeMountPathAllowed(mount.MountPath, template.Spec.AllowedVolumeMountPaths) {
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeVolumeMountPathNotAllowed,
+				Field:   fmt.Sprintf("spec.objectStorageMounts[%s].mountPath", mount.Name),
+				Message: fmt.Sprintf("Mount path '%s' of object storage mount '%s' is not allowed by template '%s'", mount.MountPath, mount.Name, template.Name),
+				Allowed: "paths under " + strings.Join(template.Spec.AllowedVolumeMountPaths, ", "),
+				Actual:  mount.MountPath,
+			})
+		}
+	}
+	return violations
+}
+
+// bucketAllowed returns whether the bucket URL of a mount is one of the allowed URLs or under one of
+// them, at a path boundary so that s3://data does not allow s3://datasets
+func bucketAllowed(url string, allowedURLs []string) bool {
+	for _, allowed := range allowedURLs {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if url == allowed || strings.HasPrefix(url, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
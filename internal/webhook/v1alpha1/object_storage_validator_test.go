@@ -0,0 +1,86 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ObjectStorageValidator", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	newMount := func(provider workspacev1alpha1.ObjectStorageProvider, bucket, prefix string) workspacev1alpha1.ObjectStorageMount {
+		return workspacev1alpha1.ObjectStorageMount{
+			Name:      "datasets",
+			Provider:  provider,
+			Bucket:    bucket,
+			Prefix:    prefix,
+			MountPath: "/data/datasets",
+			Mode:      workspacev1alpha1.ObjectStorageMountModeCSI,
+		}
+	}
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				AllowedBuckets: []string{"s3://team-datasets", "gs://shared/public/"},
+			},
+		}
+	})
+
+	It("should allow buckets and prefixes under an allowed URL", func() {
+		mounts := []workspacev1alpha1.ObjectStorageMount{
+			newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets", ""),
+			newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets", "imagenet"),
+			newMount(workspacev1alpha1.ObjectStorageProviderGCS, "shared", "public/models"),
+		}
+		Expect(validateObjectStorageMounts(mounts, template)).To(BeEmpty())
+	})
+
+	It("should only match allowed URLs at a path boundary", func() {
+		mounts := []workspacev1alpha1.ObjectStorageMount{
+			newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets-private", ""),
+			newMount(workspacev1alpha1.ObjectStorageProviderGCS, "shared", "publication"),
+			newMount(workspacev1alpha1.ObjectStorageProviderGCS, "team-datasets", ""),
+		}
+		violations := validateObjectStorageMounts(mounts, template)
+		Expect(violations).To(HaveLen(3))
+		Expect(violations[0].Type).To(Equal(ViolationTypeBucketNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.objectStorageMounts[datasets].bucket"))
+		Expect(violations[2].Actual).To(Equal("gs://team-datasets"))
+	})
+
+	It("should reject any bucket when the template allows none", func() {
+		template.Spec.AllowedBuckets = nil
+		violations := validateObjectStorageMounts(
+			[]workspacev1alpha1.ObjectStorageMount{newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets", "")}, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Allowed).To(Equal("no bucket"))
+	})
+
+	It("should reject mount paths the template does not allow", func() {
+		template.Spec.AllowedVolumeMountPaths = []string{"/mnt"}
+		violations := validateObjectStorageMounts(
+			[]workspacev1alpha1.ObjectStorageMount{newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets", "")}, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Type).To(Equal(ViolationTypeVolumeMountPathNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.objectStorageMounts[datasets].mountPath"))
+	})
+
+	It("should reject object storage mounts on a workspace without template", func() {
+		workspace := &workspacev1alpha1.Workspace{
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				ObjectStorageMounts: []workspacev1alpha1.ObjectStorageMount{newMount(workspacev1alpha1.ObjectStorageProviderS3, "team-datasets", "")},
+			},
+		}
+		Expect(validateObjectStorageMountsRequireTemplate(workspace)).To(MatchError(ContainSubstring("requires a templateRef")))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+// OwnershipValidator checks who may update or delete a workspace according to its ownershipType
+type OwnershipValidator struct {
+	// groupResolver resolves the groups of users beyond those of the admission request, optional
+	groupResolver GroupResolverInterface
+}
+
+// NewOwnershipValidator creates a new OwnershipValidator. groupResolver may be nil, in which case
+// the membership of GroupOnly owner groups is only read from the admission request.
+func NewOwnershipValidator(groupResolver GroupResolverInterface) *OwnershipValidator {
+	return &OwnershipValidator{groupResolver: groupResolver}
+}
+
+// ValidateDataAccess checks that the user may read the data of the workspace, e.g. to clone or
+// snapshot it: the user must be allowed to modify the workspace according to its ownershipType, and
+// to access it when its accessType is OwnerOnly. action only shapes the error messages.
+func (ov *OwnershipValidator) ValidateDataAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace, action string) error {
+	if err := ov.ValidateModifyPermission(ctx, workspace); err != nil {
+		return fmt.Errorf("cannot %s workspace %s/%s: %w", action, workspace.Namespace, workspace.Name, err)
+	}
+	if workspace.Spec.AccessType == webhookconst.OwnershipTypeOwnerOnly && validateOwnershipPermission(ctx, workspace) != nil {
+		return fmt.Errorf("access denied: only the owner of workspace %s/%s can %s it", workspace.Namespace, workspace.Name, action)
+	}
+	return nil
+}
+
+// validateOwnerGroup checks that spec.ownerGroup is set exactly when ownershipType is GroupOnly
+func validateOwnerGroup(workspace *workspacev1alpha1.Workspace) error {
+	isGroupOnly := workspace.Spec.OwnershipType == webhookconst.OwnershipTypeGroupOnly
+	if isGroupOnly && workspace.Spec.OwnerGroup == "" {
+		return fmt.Errorf("spec.ownerGroup is required when ownershipType is %s", webhookconst.OwnershipTypeGroupOnly)
+	}
+	if !isGroupOnly && workspace.Spec.OwnerGroup != "" {
+		return fmt.Errorf("spec.ownerGroup can only be set when ownershipType is %s", webhookconst.OwnershipTypeGroupOnly)
+	}
+	return nil
+}
+
+// ValidateModifyPermission checks that the user may update or delete the workspace: anyone for
+// Public workspaces, the owner for OwnerOnly workspaces, and the owner or the members of
+// spec.ownerGroup for GroupOnly workspaces
+func (ov *OwnershipValidator) ValidateModifyPermission(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	switch getEffectiveOwnershipType(workspace.Spec.OwnershipType) {
+	case webhookconst.OwnershipTypeOwnerOnly:
+		return validateOwnershipPermission(ctx, workspace)
+	case webhookconst.OwnershipTypeGroupOnly:
+		if validateOwnershipPermission(ctx, workspace) == nil {
+			return nil
+		}
+		isMember, err := ov.isGroupMember(ctx, workspace.Spec.OwnerGroup)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return fmt.Errorf("access denied: only the workspace owner or members of group %s can modify GroupOnly workspaces",
+				workspace.Spec.OwnerGroup)
+		}
+	}
+	return nil
+}
+
+// isGroupMember returns true when the user of the admission request belongs to group, according
+// to the groups of the request or else to the group resolver
+func (ov *OwnershipValidator) isGroupMember(ctx context.Context, group string) (bool, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to extract user information from request context: %w", err)
+	}
+	if group == "" {
+		return false, nil
+	}
+	if slices.Contains(req.UserInfo.Groups, group) {
+		return true, nil
+	}
+	if ov == nil || ov.groupResolver == nil {
+		return false, nil
+	}
+
+	groups, err := ov.groupResolver.ResolveGroups(ctx, req.UserInfo.Username)
+	if err != nil {
+		workspacelog.Error(err, "Failed to resolve user groups", "user", req.UserInfo.Username)
+		return false, fmt.Errorf("unable to resolve the groups of user %s: %w", req.UserInfo.Username, err)
+	}
+	return slices.Contains(groups, group), nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+const testOwnerGroup = "data-science"
+
+// fakeGroupResolver resolves the groups of users from a map
+type fakeGroupResolver struct {
+	groups map[string][]string
+	err    error
+	calls  int
+}
+
+func (r *fakeGroupResolver) ResolveGroups(_ context.Context, username string) ([]string, error) {
+	r.calls++
+	return r.groups[username], r.err
+}
+
+var _ = Describe("OwnershipValidator", func() {
+	var (
+		ctx            context.Context
+		groupWorkspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		groupWorkspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-workspace",
+				Namespace:   "default",
+				Annotations: map[string]string{controller.AnnotationCreatedBy: testOwnerUser},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				OwnershipType: webhookconst.OwnershipTypeGroupOnly,
+				OwnerGroup:    testOwnerGroup,
+			},
+		}
+	})
+
+	Context("validateOwnerGroup", func() {
+		It("should require ownerGroup for GroupOnly workspaces", func() {
+			groupWorkspace.Spec.OwnerGroup = ""
+			err := validateOwnerGroup(groupWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ownerGroup is required"))
+		})
+
+		It("should reject ownerGroup for other ownership types", func() {
+			groupWorkspace.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+			Expect(validateOwnerGroup(groupWorkspace)).NotTo(Succeed())
+		})
+
+		It("should accept a GroupOnly workspace with an ownerGroup", func() {
+			Expect(validateOwnerGroup(groupWorkspace)).To(Succeed())
+		})
+	})
+
+	Context("ValidateModifyPermission", func() {
+		It("should allow the owner", func() {
+			validator := NewOwnershipValidator(nil)
+			userCtx := createUserContext(ctx, "UPDATE", testOwnerUser)
+			Expect(validator.ValidateModifyPermission(userCtx, groupWorkspace)).To(Succeed())
+		})
+
+		It("should allow members of the owner group from the request groups", func() {
+			resolver := &fakeGroupResolver{}
+			validator := NewOwnershipValidator(resolver)
+			userCtx := createUserContext(ctx, "UPDATE", "team-member", "other-group", testOwnerGroup)
+			Expect(validator.ValidateModifyPermission(userCtx, groupWorkspace)).To(Succeed())
+			Expect(resolver.calls).To(BeZero())
+		})
+
+		It("should allow members of the owner group from the group resolver", func() {
+			resolver := &fakeGroupResolver{groups: map[string][]string{"team-member": {testOwnerGroup}}}
+			validator := NewOwnershipValidator(resolver)
+			userCtx := createUserContext(ctx, "DELETE", "team-member")
+			Expect(validator.ValidateModifyPermission(userCtx, groupWorkspace)).To(Succeed())
+			Expect(resolver.calls).To(Equal(1))
+		})
+
+		It("should deny users outside of the owner group", func() {
+			validator := NewOwnershipValidator(&fakeGroupResolver{groups: map[string][]string{"outsider": {"other-group"}}})
+			userCtx := createUserContext(ctx, "UPDATE", "outsider", "other-group")
+			err := validator.ValidateModifyPermission(userCtx, groupWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("members of group " + testOwnerGroup))
+		})
+
+		It("should deny users when the group resolver fails", func() {
+			validator := NewOwnershipValidator(&fakeGroupResolver{err: errors.New("directory unavailable")})
+			userCtx := createUserContext(ctx, "UPDATE", "team-member")
+			err := validator.ValidateModifyPermission(userCtx, groupWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to resolve the groups"))
+		})
+
+		It("should allow anyone to modify Public workspaces", func() {
+			groupWorkspace.Spec.OwnershipType = webhookconst.OwnershipTypePublic
+			var validator *OwnershipValidator
+			userCtx := createUserContext(ctx, "UPDATE", "outsider")
+			Expect(validator.ValidateModifyPermission(userCtx, groupWorkspace)).To(Succeed())
+		})
+	})
+
+	Context("WorkspaceCustomValidator", func() {
+		var validator *WorkspaceCustomValidator
+
+		BeforeEach(func() {
+			validator = &WorkspaceCustomValidator{
+				templateValidator:       NewTemplateValidator(nil, ""),
+				metadataLimitsValidator: NewMetadataLimitsValidator(MetadataLimits{}),
+				podSecurityValidator:    NewPodSecurityValidator(false),
+				ownershipValidator:      NewOwnershipValidator(nil),
+			}
+		})
+
+		It("should reject a GroupOnly workspace deletion by a non-member", func() {
+			userCtx := createUserContext(ctx, "DELETE", "outsider")
+			_, err := validator.ValidateDelete(userCtx, groupWorkspace)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow a GroupOnly workspace deletion by a member", func() {
+			userCtx := createUserContext(ctx, "DELETE", "team-member", testOwnerGroup)
+			_, err := validator.ValidateDelete(userCtx, groupWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a member changing the owner group", func() {
+			userCtx := createUserContext(ctx, "UPDATE", "team-member", testOwnerGroup, "other-group")
+			newWorkspace := groupWorkspace.DeepCopy()
+			newWorkspace.Spec.OwnerGroup = "other-group"
+			_, err := validator.ValidateUpdate(userCtx, groupWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("access denied"))
+		})
+	})
+})
+
+var _ = Describe("GroupResolverWebhook", func() {
+	It("should post the username and return the groups of the response", func() {
+		var received GroupResolverWebhookRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			_ = json.NewEncoder(w).Encode(GroupResolverWebhookResponse{Groups: []string{testOwnerGroup}})
+		}))
+		defer server.Close()
+
+		groups, err := NewGroupResolverWebhook(server.URL).ResolveGroups(context.Background(), "alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(Equal([]string{testOwnerGroup}))
+		Expect(received.Username).To(Equal("alice"))
+	})
+
+	It("should return an error when the webhook rejects the request", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unknown user", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := NewGroupResolverWebhook(server.URL).ResolveGroups(context.Background(), "alice")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("status 404"))
+	})
+})
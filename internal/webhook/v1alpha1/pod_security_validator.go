@@ -0,0 +1,170 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// baselineCapabilities are the capabilities the Kubernetes baseline Pod Security Standard
+// allows containers to add. Any other capability grants access to the node.
+var baselineCapabilities = map[corev1.Capability]struct{}{
+	"AUDIT_WRITE":      {},
+	"CHOWN":            {},
+	"DAC_OVERRIDE":     {},
+	"FOWNER":           {},
+	"FSETID":           {},
+	"KILL":             {},
+	"MKNOD":            {},
+	"NET_BIND_SERVICE": {},
+	"SETFCAP":          {},
+	"SETGID":           {},
+	"SETPCAP":          {},
+	"SETUID":           {},
+	"SYS_CHROOT":       {},
+}
+
+// PodSecurityValidator rejects workspace pod overrides that grant access to the node:
+// privileged containers, host processes and capabilities beyond the baseline Pod Security
+// Standard. It applies to every user, regardless of the template defaults, because the
+// controller creates the workspace pods with its own permissions.
+// Cluster admins may allow privileged workspaces with --allow-privileged-workspaces.
+type PodSecurityValidator struct {
+	allowPrivileged bool
+}
+
+// NewPodSecurityValidator creates a new PodSecurityValidator
+func NewPodSecurityValidator(allowPrivileged bool) *PodSecurityValidator {
+	return &PodSecurityValidator{allowPrivileged: allowPrivileged}
+}
+
+// ValidateCreateWorkspace checks the pod overrides of a new workspace
+func (v *PodSecurityValidator) ValidateCreateWorkspace(workspace *workspacev1alpha1.Workspace) error {
+	if v.allowPrivileged {
+		return nil
+	}
+	if violations := podSecurityViolations(workspace); len(violations) > 0 {
+		return fmt.Errorf("workspace violates pod security constraints: %s", formatPodSecurityViolations(violations))
+	}
+	return nil
+}
+
+// ValidateUpdateWorkspace checks the pod overrides of an updated workspace.
+// Only changes to the pod overrides are checked, so that workspaces admitted while
+// privileged workspaces were allowed can still be stopped, started or deleted.
+func (v *PodSecurityValidator) ValidateUpdateWorkspace(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if equality.Semantic.DeepEqual(oldWorkspace.Spec.PodSecurityContext, newWorkspace.Spec.PodSecurityContext) &&
+		equality.Semantic.DeepEqual(oldWorkspace.Spec.ContainerSecurityContext, newWorkspace.Spec.ContainerSecurityContext) &&
+		equality.Semantic.DeepEqual(oldWorkspace.Spec.InitContainers, newWorkspace.Spec.InitContainers) &&
+		equality.Semantic.DeepEqual(provisioningHook(oldWorkspace), provisioningHook(newWorkspace)) {
+		return nil
+	}
+	return v.ValidateCreateWorkspace(newWorkspace)
+}
+
+// podSecurityViolations lists the pod overrides of a workspace that grant access to the node.
+// Workspaces cannot set host namespaces or hostPath volumes: volumes are always backed by
+// persistent volume claims, and the pod spec is owned by the controller.
+func podSecurityViolations(workspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	var violations []TemplateViolation
+
+	if podSecurityContext := workspace.Spec.PodSecurityContext; podSecurityContext != nil &&
+		podSecurityContext.WindowsOptions != nil && isTrue(podSecurityContext.WindowsOptions.HostProcess) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeHostProcessNotAllowed,
+			Field:   "spec.podSecurityContext.windowsOptions.hostProcess",
+			Message: "host processes are not allowed",
+			Allowed: "false",
+			Actual:  "true",
+		})
+	}
+
+	violations = append(violations,
+		securityContextViolations(workspace.Spec.ContainerSecurityContext, "spec.containerSecurityContext")...)
+	for i, initContainer := range workspace.Spec.InitContainers {
+		violations = append(violations,
+			securityContextViolations(initContainer.SecurityContext, fmt.Sprintf("spec.initContainers[%d].securityContext", i))...)
+	}
+	// The controller runs the storage provisioning hook as a Job against the workspace volume
+	if hook := provisioningHook(workspace); hook != nil {
+		violations = append(violations,
+			securityContextViolations(hook.SecurityContext, "spec.storage.provisioningHook.securityContext")...)
+	}
+
+	return violations
+}
+
+// provisioningHook returns the storage provisioning hook of a workspace, if any
+func provisioningHook(workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.StorageProvisioningHook {
+	if workspace.Spec.Storage == nil {
+		return nil
+	}
+	return workspace.Spec.Storage.ProvisioningHook
+}
+
+// securityContextViolations lists the settings of a container security context that grant access to the node
+func securityContextViolations(securityContext *corev1.SecurityContext, field string) []TemplateViolation {
+	if securityContext == nil {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	if isTrue(securityContext.Privileged) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypePrivilegedNotAllowed,
+			Field:   field + ".privileged",
+			Message: "privileged containers are not allowed",
+			Allowed: "false",
+			Actual:  "true",
+		})
+	}
+	if securityContext.WindowsOptions != nil && isTrue(securityContext.WindowsOptions.HostProcess) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeHostProcessNotAllowed,
+			Field:   field + ".windowsOptions.hostProcess",
+			Message: "host processes are not allowed",
+			Allowed: "false",
+			Actual:  "true",
+		})
+	}
+	if securityContext.Capabilities != nil {
+		for i, capability := range securityContext.Capabilities.Add {
+			if _, ok := baselineCapabilities[controller.NormalizeCapability(capability)]; ok {
+				continue
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeCapabilityNotAllowed,
+				Field:   fmt.Sprintf("%s.capabilities.add[%d]", field, i),
+				Message: fmt.Sprintf("capability %s is not allowed", capability),
+				Allowed: "capabilities of the baseline Pod Security Standard",
+				Actual:  string(capability),
+			})
+		}
+	}
+	return violations
+}
+
+// formatPodSecurityViolations formats violations with their reason code and field,
+// so that users can tell which override to remove
+func formatPodSecurityViolations(violations []TemplateViolation) string {
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, fmt.Sprintf("%s: %s (%s)", violation.Field, violation.Message, violation.Type))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// isTrue returns whether an optional boolean is set to true
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Pod Security Validator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		validator *PodSecurityValidator
+	)
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+		}
+		validator = NewPodSecurityValidator(false)
+	})
+
+	Context("ValidateCreateWorkspace", func() {
+		It("should allow baseline security contexts", func() {
+			workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE", "CAP_CHOWN"}},
+			}
+			Expect(validator.ValidateCreateWorkspace(workspace)).To(Succeed())
+		})
+
+		It("should reject privileged containers", func() {
+			workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true)}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.containerSecurityContext.privileged"))
+			Expect(err.Error()).To(ContainSubstring(ViolationTypePrivilegedNotAllowed))
+		})
+
+		It("should reject capabilities beyond the baseline in init containers", func() {
+			workspace.Spec.InitContainers = []corev1.Container{{
+				Name:  "setup",
+				Image: "busybox",
+				SecurityContext: &corev1.SecurityContext{
+					Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"CHOWN", "sys_admin"}},
+				},
+			}}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.initContainers[0].securityContext.capabilities.add[1]"))
+			Expect(err.Error()).To(ContainSubstring(ViolationTypeCapabilityNotAllowed))
+		})
+
+		It("should reject privileged storage provisioning hooks", func() {
+			workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+				ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{
+					Image:           testValidBaseNotebook,
+					SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+				},
+			}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.provisioningHook.securityContext.privileged"))
+		})
+
+		It("should reject host processes", func() {
+			workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{
+				WindowsOptions: &corev1.WindowsSecurityContextOptions{HostProcess: ptr.To(true)},
+			}
+			err := validator.ValidateCreateWorkspace(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(ViolationTypeHostProcessNotAllowed))
+		})
+
+		It("should allow privileged containers when the gate is enabled", func() {
+			workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true)}
+			Expect(NewPodSecurityValidator(true).ValidateCreateWorkspace(workspace)).To(Succeed())
+		})
+	})
+
+	Context("ValidateUpdateWorkspace", func() {
+		It("should allow unrelated updates of existing privileged workspaces", func() {
+			workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true)}
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.DesiredStatus = "Stopped"
+			Expect(validator.ValidateUpdateWorkspace(workspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should reject updates adding privileged storage provisioning hooks", func() {
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+				ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{
+					Image:           testValidBaseNotebook,
+					SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}}},
+				},
+			}
+			Expect(validator.ValidateUpdateWorkspace(workspace, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should reject updates adding privileged containers", func() {
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true)}
+			Expect(validator.ValidateUpdateWorkspace(workspace, newWorkspace)).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,20 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyPortDefaults applies default ports from template to workspace
+func applyPortDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if len(workspace.Spec.Ports) > 0 || len(template.Spec.DefaultPorts) == 0 {
+		return
+	}
+
+	workspace.Spec.Ports = make([]workspacev1alpha1.WorkspacePort, len(template.Spec.DefaultPorts))
+	copy(workspace.Spec.Ports, template.Spec.DefaultPorts)
+}
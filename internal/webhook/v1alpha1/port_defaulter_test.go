@@ -0,0 +1,61 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("PortDefaulter", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultPorts: []workspacev1alpha1.WorkspacePort{
+					{Name: "tensorboard", Port: 6006},
+				},
+			},
+		}
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName},
+			Spec:       workspacev1alpha1.WorkspaceSpec{DisplayName: testDisplayName},
+		}
+	})
+
+	Context("applyPortDefaults", func() {
+		It("should apply default ports when workspace has none", func() {
+			applyPortDefaults(workspace, template)
+
+			Expect(workspace.Spec.Ports).To(Equal([]workspacev1alpha1.WorkspacePort{{Name: "tensorboard", Port: 6006}}))
+		})
+
+		It("should not override existing workspace ports", func() {
+			workspace.Spec.Ports = []workspacev1alpha1.WorkspacePort{{Name: "mlflow", Port: 5000}}
+
+			applyPortDefaults(workspace, template)
+
+			Expect(workspace.Spec.Ports).To(HaveLen(1))
+			Expect(workspace.Spec.Ports[0].Name).To(Equal("mlflow"))
+		})
+
+		It("should not share the template ports with the workspace", func() {
+			applyPortDefaults(workspace, template)
+			workspace.Spec.Ports[0].Path = "/tb/"
+
+			Expect(template.Spec.DefaultPorts[0].Path).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateProvisioningHints checks that a workspace keeps the provisioning hints of its template, since
+// workspace owners could otherwise request costlier instances or capacity than the template allows
+func validateProvisioningHints(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	hints := template.Spec.ProvisioningHints
+	if hints == nil || equality.Semantic.DeepEqual(workspace.Spec.ProvisioningHints, hints) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeProvisioningHintsMismatch,
+		Field:   "spec.provisioningHints",
+		Message: fmt.Sprintf("provisioning hints must match the provisioning hints of template '%s'", template.Name),
+		Allowed: fmt.Sprintf("provisioning hints of template '%s'", template.Name),
+		Actual:  "modified provisioning hints",
+	}
+}
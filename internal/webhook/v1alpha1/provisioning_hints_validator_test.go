@@ -0,0 +1,70 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ProvisioningHintsValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				ProvisioningHints: &workspacev1alpha1.ProvisioningHints{
+					InstanceFamilies: []string{"g5"},
+					CapacityType:     workspacev1alpha1.CapacityTypeSpot,
+					Zones:            []string{"us-west-2a"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+			},
+		}
+	})
+
+	It("should accept any workspace when the template has no provisioning hints", func() {
+		template.Spec.ProvisioningHints = nil
+		workspace.Spec.ProvisioningHints = &workspacev1alpha1.ProvisioningHints{InstanceFamilies: []string{"p5"}}
+		Expect(validateProvisioningHints(workspace, template)).To(BeNil())
+	})
+
+	It("should accept a workspace defaulted with the template provisioning hints", func() {
+		applySecurityDefaults(workspace, template)
+		Expect(workspace.Spec.ProvisioningHints).To(Equal(template.Spec.ProvisioningHints))
+		Expect(validateProvisioningHints(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a workspace that changes its provisioning hints", func() {
+		workspace.Spec.ProvisioningHints = template.Spec.ProvisioningHints.DeepCopy()
+		workspace.Spec.ProvisioningHints.CapacityType = workspacev1alpha1.CapacityTypeOnDemand
+		violation := validateProvisioningHints(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeProvisioningHintsMismatch))
+		Expect(violation.Field).To(Equal("spec.provisioningHints"))
+	})
+
+	It("should reset modified provisioning hints on migration", func() {
+		workspace.Spec.ProvisioningHints = &workspacev1alpha1.ProvisioningHints{Zones: []string{"us-east-1a"}}
+		Expect(migrateTemplateViolations(workspace, template)).To(ContainElement("spec.provisioningHints"))
+		Expect(workspace.Spec.ProvisioningHints).To(BeNil())
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateStorageProvisioningHook checks that a workspace runs the provisioning hook of its template
+// and no other, since the hook runs with the security context its author chooses
+func validateStorageProvisioningHook(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if workspace.Spec.Storage == nil {
+		return nil
+	}
+	var hook *workspacev1alpha1.StorageProvisioningHook
+	if template.Spec.PrimaryStorage != nil {
+		hook = template.Spec.PrimaryStorage.ProvisioningHook
+	}
+	if equality.Semantic.DeepEqual(workspace.Spec.Storage.ProvisioningHook, hook) {
+		return nil
+	}
+
+	allowed := fmt.Sprintf("provisioning hook of template '%s'", template.Name)
+	if hook == nil {
+		allowed = "no provisioning hook"
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeProvisioningHookMismatch,
+		Field:   "spec.storage.provisioningHook",
+		Message: fmt.Sprintf("storage provisioning hook must match the provisioning hook of template '%s'", template.Name),
+		Allowed: allowed,
+		Actual:  "modified provisioning hook",
+	}
+}
+
+// validateStorageProvisioningHookRequiresTemplate rejects a provisioning hook on a workspace without
+// template, since only template authors may choose the hooks that run on workspace volumes
+func validateStorageProvisioningHookRequiresTemplate(workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.Storage != nil && workspace.Spec.Storage.ProvisioningHook != nil {
+		return fmt.Errorf("storage provisioning hook requires a templateRef")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ProvisioningHookValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				PrimaryStorage: &workspacev1alpha1.StorageConfig{
+					DefaultSize: resource.MustParse("10Gi"),
+					ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{
+						Image: "registry.example.com/skel:1",
+						Args:  []string{"$(STORAGE_MOUNT_PATH)"},
+					},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: testWorkspaceDisplayName,
+			},
+		}
+	})
+
+	It("should accept a workspace defaulted with the template provisioning hook", func() {
+		applyStorageDefaults(workspace, template)
+		Expect(workspace.Spec.Storage.ProvisioningHook).To(Equal(template.Spec.PrimaryStorage.ProvisioningHook))
+		Expect(validateStorageProvisioningHook(workspace, template)).To(BeNil())
+	})
+
+	It("should accept a workspace without storage", func() {
+		Expect(validateStorageProvisioningHook(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a workspace that changes the provisioning hook", func() {
+		applyStorageDefaults(workspace, template)
+		workspace.Spec.Storage.ProvisioningHook.Image = "registry.example.com/other:1"
+		violation := validateStorageProvisioningHook(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeProvisioningHookMismatch))
+		Expect(violation.Field).To(Equal("spec.storage.provisioningHook"))
+	})
+
+	It("should reject a provisioning hook the template does not define", func() {
+		template.Spec.PrimaryStorage = nil
+		workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+			ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{Image: "registry.example.com/skel:1"},
+		}
+		violation := validateStorageProvisioningHook(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Allowed).To(Equal("no provisioning hook"))
+	})
+
+	It("should reject a provisioning hook on a workspace without template", func() {
+		workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+			ProvisioningHook: &workspacev1alpha1.StorageProvisioningHook{Image: "registry.example.com/skel:1"},
+		}
+		Expect(validateStandaloneWorkspace(workspace)).To(MatchError(ContainSubstring("requires a templateRef")))
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// QuotaValidator enforces the WorkspaceQuotas of the workspace namespace
+type QuotaValidator struct {
+	client client.Client
+}
+
+// NewQuotaValidator creates a new QuotaValidator
+func NewQuotaValidator(k8sClient client.Client) *QuotaValidator {
+	return &QuotaValidator{
+		client: k8sClient,
+	}
+}
+
+// ValidateCreateWorkspace rejects a new workspace that would exceed a WorkspaceQuota
+func (qv *QuotaValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	return qv.validateQuotas(ctx, nil, workspace)
+}
+
+// ValidateUpdateWorkspace rejects an update that grows the workspace's usage beyond a WorkspaceQuota.
+// Updates that do not grow usage are allowed even when the quota is already exceeded,
+// e.g. after an admin lowered it, so that users can always stop or shrink their workspaces.
+func (qv *QuotaValidator) ValidateUpdateWorkspace(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	return qv.validateQuotas(ctx, oldWorkspace, newWorkspace)
+}
+
+// validateQuotas checks the workspace against every WorkspaceQuota of its namespace.
+// Pass nil oldWorkspace on create.
+func (qv *QuotaValidator) validateQuotas(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	quotas := &workspacev1alpha1.WorkspaceQuotaList{}
+	if err := qv.client.List(ctx, quotas, client.InNamespace(newWorkspace.Namespace)); err != nil {
+		return fmt.Errorf("failed to list workspace quotas: %w", err)
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := qv.client.List(ctx, workspaces, client.InNamespace(newWorkspace.Namespace)); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	for i := range quotas.Items {
+		quota := &quotas.Items[i]
+		if err := checkQuota(quota, workspaces.Items, oldWorkspace, newWorkspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkQuota rejects the workspace when it grows a usage of the quota beyond its limit
+func checkQuota(
+	quota *workspacev1alpha1.WorkspaceQuota,
+	workspaces []workspacev1alpha1.Workspace,
+	oldWorkspace, newWorkspace *workspacev1alpha1.Workspace,
+) error {
+	user := controller.WorkspaceQuotaUser(newWorkspace)
+	userScoped := quota.Spec.Scope == workspacev1alpha1.QuotaScopeUser
+
+	// Usage of the other workspaces the quota applies to
+	total := workspacev1alpha1.WorkspaceQuotaUsage{}
+	for i := range workspaces {
+		workspace := &workspaces[i]
+		if workspace.Name == newWorkspace.Name || !controller.CountsTowardsWorkspaceQuota(workspace) {
+			continue
+		}
+		if userScoped && controller.WorkspaceQuotaUser(workspace) != user {
+			continue
+		}
+		controller.AddWorkspaceQuotaUsage(&total, controller.WorkspaceQuotaUsageOf(workspace, quota))
+	}
+
+	newUsage := controller.WorkspaceQuotaUsageOf(newWorkspace, quota)
+	oldUsage := workspacev1alpha1.WorkspaceQuotaUsage{}
+	if oldWorkspace != nil {
+		oldUsage = controller.WorkspaceQuotaUsageOf(oldWorkspace, quota)
+	}
+	controller.AddWorkspaceQuotaUsage(&total, newUsage)
+
+	var violations []string
+	if limit := quota.Spec.MaxWorkspaces; limit != nil && total.Workspaces > *limit &&
+		newUsage.Workspaces > oldUsage.Workspaces {
+		violations = append(violations, fmt.Sprintf("workspaces: %d would exceed the limit of %d", total.Workspaces, *limit))
+	}
+	if limit := quota.Spec.MaxRunningWorkspaces; limit != nil && total.RunningWorkspaces > *limit &&
+		newUsage.RunningWorkspaces > oldUsage.RunningWorkspaces {
+		violations = append(violations, fmt.Sprintf("running workspaces: %d would exceed the limit of %d",
+			total.RunningWorkspaces, *limit))
+	}
+	for name, limit := range quota.Spec.Resources {
+		used := total.Resources[name]
+		newAmount := newUsage.Resources[name]
+		if used.Cmp(limit) > 0 && newAmount.Cmp(oldUsage.Resources[name]) > 0 {
+			violations = append(violations, fmt.Sprintf("%s: %s would exceed the limit of %s", name, used.String(), limit.String()))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	sort.Strings(violations)
+	if userScoped {
+		return fmt.Errorf("workspace exceeds WorkspaceQuota %s for user %s: %s",
+			quota.Name, user, strings.Join(violations, "; "))
+	}
+	return fmt.Errorf("workspace exceeds WorkspaceQuota %s: %s", quota.Name, strings.Join(violations, "; "))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Quota Validator", func() {
+	var ctx context.Context
+
+	newWorkspace := func(name, user, desiredStatus, cpu string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   testNamespaceTeamA,
+				Annotations: map[string]string{controller.AnnotationCreatedBy: user},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DesiredStatus: desiredStatus,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			},
+		}
+	}
+
+	newQuota := func(scope workspacev1alpha1.QuotaScope, spec workspacev1alpha1.WorkspaceQuotaSpec) *workspacev1alpha1.WorkspaceQuota {
+		spec.Scope = scope
+		return &workspacev1alpha1.WorkspaceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: testNamespaceTeamA},
+			Spec:       spec,
+		}
+	}
+
+	newValidator := func(objs ...client.Object) *QuotaValidator {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		return NewQuotaValidator(fakeClient)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should allow any workspace when the namespace has no quota", func() {
+		validator := newValidator(newWorkspace("existing", testUser1, controller.DesiredStateRunning, "4"))
+		Expect(validator.ValidateCreateWorkspace(ctx, newWorkspace("new", testUser1, controller.DesiredStateRunning, "4"))).To(Succeed())
+	})
+
+	It("should reject a workspace beyond the namespace workspace count", func() {
+		validator := newValidator(
+			newQuota(workspacev1alpha1.QuotaScopeNamespace, workspacev1alpha1.WorkspaceQuotaSpec{MaxWorkspaces: ptr.To[int32](1)}),
+			newWorkspace("existing", testOwnerUser, controller.DesiredStateStopped, "1"),
+		)
+		err := validator.ValidateCreateWorkspace(ctx, newWorkspace("new", testUser1, controller.DesiredStateStopped, "1"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("workspaces: 2 would exceed the limit of 1"))
+	})
+
+	It("should limit each user separately for User-scoped quotas", func() {
+		validator := newValidator(
+			newQuota(workspacev1alpha1.QuotaScopeUser, workspacev1alpha1.WorkspaceQuotaSpec{MaxRunningWorkspaces: ptr.To[int32](1)}),
+			newWorkspace("other-user", testOwnerUser, controller.DesiredStateRunning, "1"),
+		)
+		Expect(validator.ValidateCreateWorkspace(ctx, newWorkspace("first", testUser1, controller.DesiredStateRunning, "1"))).To(Succeed())
+
+		validator = newValidator(
+			newQuota(workspacev1alpha1.QuotaScopeUser, workspacev1alpha1.WorkspaceQuotaSpec{MaxRunningWorkspaces: ptr.To[int32](1)}),
+			newWorkspace("first", testUser1, controller.DesiredStateRunning, "1"),
+		)
+		err := validator.ValidateCreateWorkspace(ctx, newWorkspace("second", testUser1, controller.DesiredStateRunning, "1"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("for user " + testUser1))
+	})
+
+	It("should reject starting a workspace beyond the aggregate CPU", func() {
+		quota := newQuota(workspacev1alpha1.QuotaScopeNamespace, workspacev1alpha1.WorkspaceQuotaSpec{
+			Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		})
+		stopped := newWorkspace("stopped", testUser1, controller.DesiredStateStopped, "2")
+		validator := newValidator(quota, stopped, newWorkspace("running", testOwnerUser, controller.DesiredStateRunning, "3"))
+
+		started := stopped.DeepCopy()
+		started.Spec.DesiredStatus = controller.DesiredStateRunning
+		err := validator.ValidateUpdateWorkspace(ctx, stopped, started)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cpu: 5 would exceed the limit of 4"))
+	})
+
+	It("should allow updates that do not grow usage of an exceeded quota", func() {
+		quota := newQuota(workspacev1alpha1.QuotaScopeNamespace, workspacev1alpha1.WorkspaceQuotaSpec{
+			MaxRunningWorkspaces: ptr.To[int32](1),
+			Resources:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		})
+		running := newWorkspace("running", testUser1, controller.DesiredStateRunning, "2")
+		validator := newValidator(quota, running, newWorkspace("other", testOwnerUser, controller.DesiredStateRunning, "2"))
+
+		renamed := running.DeepCopy()
+		renamed.Spec.DisplayName = "renamed"
+		Expect(validator.ValidateUpdateWorkspace(ctx, running, renamed)).To(Succeed())
+
+		stopped := running.DeepCopy()
+		stopped.Spec.DesiredStatus = controller.DesiredStateStopped
+		Expect(validator.ValidateUpdateWorkspace(ctx, running, stopped)).To(Succeed())
+	})
+
+	It("should count the primary storage of stopped workspaces", func() {
+		quota := newQuota(workspacev1alpha1.QuotaScopeNamespace, workspacev1alpha1.WorkspaceQuotaSpec{
+			Resources: corev1.ResourceList{workspacev1alpha1.ResourceStorage: resource.MustParse("15Gi")},
+		})
+		existing := newWorkspace("existing", testUser1, controller.DesiredStateStopped, "1")
+		existing.Spec.Storage = &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")}
+		validator := newValidator(quota, existing)
+
+		workspace := newWorkspace("new", testUser1, controller.DesiredStateStopped, "1")
+		workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")}
+		err := validator.ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("storage: 20Gi would exceed the limit of 15Gi"))
+	})
+})
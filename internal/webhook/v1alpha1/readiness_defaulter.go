@@ -16,3 +16,11 @@ func applyReadinessProbeDefaults(workspace *workspacev1alpha1.Workspace, templat
 		workspace.Spec.ReadinessProbe = template.Spec.DefaultReadinessProbe.DeepCopy()
 	}
 }
+
+// applyProbesDefaults applies the probes default from template
+// to workspace when the workspace does not specify its own.
+func applyProbesDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.Probes == nil && template.Spec.DefaultProbes != nil {
+		workspace.Spec.Probes = template.Spec.DefaultProbes.DeepCopy()
+	}
+}
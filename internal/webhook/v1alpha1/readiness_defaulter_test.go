@@ -106,4 +106,32 @@ var _ = Describe("ReadinessProbeDefaulter", func() {
 			Expect(workspace.Spec.ReadinessProbe.TCPSocket.Port).To(Equal(intstr.FromInt(8888)))
 		})
 	})
+
+	Describe("applyProbesDefaults", func() {
+		It("should apply probes default when workspace has none", func() {
+			template.Spec.DefaultProbes = &workspacev1alpha1.WorkspaceProbes{
+				Startup: &corev1.Probe{FailureThreshold: 60},
+			}
+
+			applyProbesDefaults(workspace, template)
+
+			Expect(workspace.Spec.Probes).ToNot(BeNil())
+			Expect(workspace.Spec.Probes.Startup.FailureThreshold).To(Equal(int32(60)))
+
+			// Mutate the template's probes after defaulting; workspace must be unaffected.
+			template.Spec.DefaultProbes.Startup.FailureThreshold = 99
+			Expect(workspace.Spec.Probes.Startup.FailureThreshold).To(Equal(int32(60)))
+		})
+
+		It("should not override existing probes", func() {
+			workspace.Spec.Probes = &workspacev1alpha1.WorkspaceProbes{}
+			template.Spec.DefaultProbes = &workspacev1alpha1.WorkspaceProbes{
+				Startup: &corev1.Probe{FailureThreshold: 60},
+			}
+
+			applyProbesDefaults(workspace, template)
+
+			Expect(workspace.Spec.Probes.Startup).To(BeNil())
+		})
+	})
 })
@@ -25,7 +25,8 @@ func validateReservedPrefixOnCreate(workspace *workspacev1alpha1.Workspace) erro
 // validateReservedPrefixOnUpdate rejects user changes to workspace.jupyter.org/ prefixed labels or annotations.
 // For SetOnCreateOnly keys: rejects any value change or removal.
 // For SetAlways keys: allows changes (system will overwrite).
-// For unknown labels/annotations with reserved keys: rejects additions, changes, and removals.
+// For unknown labels/annotations with reserved keys: rejects additions, changes, and removals, so that
+// users keep the values the controller set on keys outside the allow-list, e.g. a scheduled idle stop.
 func validateReservedPrefixOnUpdate(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
 	if err := checkReservedKeyChanges(oldWorkspace.Labels, newWorkspace.Labels, "label"); err != nil {
 		return err
@@ -53,15 +54,17 @@ func checkReservedKeyChanges(oldMeta, newMeta map[string]string, kind string) er
 			continue
 		}
 
-		// Reject if newly added reserved key is not allow-listed
+		// Reject if newly added or changed reserved key is not allow-listed
+		oldVal, existed := oldMeta[key]
 		policy, isSystem := controller.SystemManagedMetadataKeys[key]
 		if !isSystem {
-			// Unknown reserved key added
+			if existed && oldVal == newVal {
+				continue
+			}
 			return fmt.Errorf("%s '%s' uses reserved prefix %s", kind, key, controller.ReservedMetadataPrefix)
 		}
 
 		// Reject if changed reserved key is set on create only
-		oldVal, existed := oldMeta[key]
 		if existed && oldVal != newVal && policy == controller.SetOnCreateOnly {
 			return fmt.Errorf("%s '%s' is immutable", kind, key)
 		}
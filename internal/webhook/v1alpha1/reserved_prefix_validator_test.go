@@ -53,6 +53,27 @@ var _ = Describe("Reserved Prefix Validator", func() {
 			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
 		})
 
+		It("should allow workspace with the keep-alive annotation", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationKeepAlive: "2026-01-01T00:00:00Z",
+			}
+			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
+		})
+
+		It("should allow workspace with the template image annotation", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationTemplateImage: testValidBaseNotebook,
+			}
+			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
+		})
+
+		It("should reject workspace with the idle stop schedule annotation", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationIdleStopScheduledAt: "9999-01-01T00:00:00Z",
+			}
+			Expect(validateReservedPrefixOnCreate(workspace)).NotTo(Succeed())
+		})
+
 		It("should reject workspace with unknown reserved prefix label", func() {
 			workspace.Labels = map[string]string{
 				"workspace.jupyter.org/custom-label": testLabelValue,
@@ -109,6 +130,29 @@ var _ = Describe("Reserved Prefix Validator", func() {
 			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/custom' uses reserved prefix workspace.jupyter.org/"))
 		})
 
+		It("should allow update keeping the idle stop schedule set by the controller", func() {
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationIdleStopScheduledAt: "2026-01-01T00:10:00Z",
+			}
+			workspace.Annotations = map[string]string{
+				controller.AnnotationIdleStopScheduledAt: "2026-01-01T00:10:00Z",
+				controller.AnnotationKeepAlive:           "2026-01-01T00:05:00Z",
+			}
+			Expect(validateReservedPrefixOnUpdate(oldWorkspace, workspace)).To(Succeed())
+		})
+
+		It("should reject changing the idle stop schedule set by the controller", func() {
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationIdleStopScheduledAt: "2026-01-01T00:10:00Z",
+			}
+			workspace.Annotations = map[string]string{
+				controller.AnnotationIdleStopScheduledAt: "9999-01-01T00:00:00Z",
+			}
+			err := validateReservedPrefixOnUpdate(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/idle-stop-scheduled-at' uses reserved prefix workspace.jupyter.org/"))
+		})
+
 		It("should reject changing SetOnCreateOnly annotation", func() {
 			oldWorkspace.Annotations = map[string]string{
 				controller.AnnotationCreatedBy: testOriginalUser,
@@ -10,8 +10,31 @@ import (
 )
 
 // applyResourceDefaults applies resource defaults from template to workspace
+// A profile, selected by the workspace or defaulted by the template, replaces the workspace
+// resources with its own, so that the workspace keeps matching its profile on every update
 func applyResourceDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.Profile == "" && workspace.Spec.Resources == nil {
+		workspace.Spec.Profile = template.Spec.DefaultProfile
+	}
+	if workspace.Spec.Profile != "" {
+		// An unknown profile is left for the validator to reject
+		if profile := findResourceProfile(template, workspace.Spec.Profile); profile != nil {
+			workspace.Spec.Resources = profile.Resources.DeepCopy()
+		}
+		return
+	}
+
 	if workspace.Spec.Resources == nil && template.Spec.DefaultResources != nil {
 		workspace.Spec.Resources = template.Spec.DefaultResources.DeepCopy()
 	}
 }
+
+// findResourceProfile returns the profile of the template with the given name, nil if none
+func findResourceProfile(template *workspacev1alpha1.WorkspaceTemplate, name string) *workspacev1alpha1.ResourceProfile {
+	for i := range template.Spec.Profiles {
+		if template.Spec.Profiles[i].Name == name {
+			return &template.Spec.Profiles[i]
+		}
+	}
+	return nil
+}
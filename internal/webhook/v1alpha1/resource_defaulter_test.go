@@ -85,5 +85,73 @@ var _ = Describe("ResourceDefaulter", func() {
 			// Template should remain unchanged
 			Expect(template.Spec.DefaultResources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("200m")))
 		})
+
+		Context("with profiles", func() {
+			BeforeEach(func() {
+				template.Spec.Profiles = []workspacev1alpha1.ResourceProfile{
+					{
+						Name: "small",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+						},
+					},
+					{
+						Name: "large",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+						},
+					},
+				}
+			})
+
+			It("should expand the selected profile", func() {
+				workspace.Spec.Profile = "large"
+
+				applyResourceDefaults(workspace, template)
+
+				Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("4")))
+			})
+
+			It("should replace the resources of the workspace with those of its profile", func() {
+				workspace.Spec.Profile = "small"
+				workspace.Spec.Resources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				}
+
+				applyResourceDefaults(workspace, template)
+
+				Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("250m")))
+			})
+
+			It("should select the default profile over the default resources", func() {
+				template.Spec.DefaultProfile = "small"
+
+				applyResourceDefaults(workspace, template)
+
+				Expect(workspace.Spec.Profile).To(Equal("small"))
+				Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("250m")))
+			})
+
+			It("should not select the default profile for workspaces with resources", func() {
+				template.Spec.DefaultProfile = "small"
+				workspace.Spec.Resources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				}
+
+				applyResourceDefaults(workspace, template)
+
+				Expect(workspace.Spec.Profile).To(BeEmpty())
+				Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("100m")))
+			})
+
+			It("should leave an unknown profile to the validator", func() {
+				workspace.Spec.Profile = "huge"
+
+				applyResourceDefaults(workspace, template)
+
+				Expect(workspace.Spec.Profile).To(Equal("huge"))
+				Expect(workspace.Spec.Resources).To(BeNil())
+			})
+		})
 	})
 })
@@ -7,6 +7,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -103,6 +104,67 @@ func validateResourceListBounds(
 	return violations
 }
 
+// validateResourceProfile checks that the profile of a workspace is one of the template profiles,
+// and that the workspace resources are those of the profile
+func validateResourceProfile(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if workspace.Spec.Profile == "" {
+		return nil
+	}
+
+	profile := findResourceProfile(template, workspace.Spec.Profile)
+	if profile == nil {
+		allowed := make([]string, 0, len(template.Spec.Profiles))
+		for _, p := range template.Spec.Profiles {
+			allowed = append(allowed, p.Name)
+		}
+		return &TemplateViolation{
+			Type:    ViolationTypeProfileNotAllowed,
+			Field:   "spec.profile",
+			Message: fmt.Sprintf("profile '%s' is not defined by template '%s'", workspace.Spec.Profile, template.Name),
+			Allowed: strings.Join(allowed, ", "),
+			Actual:  workspace.Spec.Profile,
+		}
+	}
+
+	if !resourcesEqual(&profile.Resources, workspace.Spec.Resources) {
+		return &TemplateViolation{
+			Type:    ViolationTypeProfileNotAllowed,
+			Field:   "spec.resources",
+			Message: fmt.Sprintf("resources must match profile '%s' of template '%s'; remove spec.profile to set resources", profile.Name, template.Name),
+			Allowed: fmt.Sprintf("resources of profile '%s'", profile.Name),
+			Actual:  "custom resources",
+		}
+	}
+
+	return nil
+}
+
+// validateProfileRequiresTemplate rejects a profile on a workspace without a template, which
+// has no profiles to select from
+func validateProfileRequiresTemplate(workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.TemplateRef == nil && workspace.Spec.Profile != "" {
+		return fmt.Errorf("profile %q requires a templateRef", workspace.Spec.Profile)
+	}
+	return nil
+}
+
+// validateTemplateProfileConsistency rejects a template whose profiles would make its own
+// workspaces un-admittable: a default profile it does not define, or a profile whose resources
+// violate the template resource bounds
+func validateTemplateProfileConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.DefaultProfile != "" && findResourceProfile(template, template.Spec.DefaultProfile) == nil {
+		return fmt.Errorf("defaultProfile %q is not one of the profiles of template %q", template.Spec.DefaultProfile, template.GetName())
+	}
+
+	for _, profile := range template.Spec.Profiles {
+		if violations := validateResourceBounds(profile.Resources, template); len(violations) > 0 {
+			return fmt.Errorf("profile %q of template %q is not admittable: %s", profile.Name, template.GetName(), formatViolations(violations))
+		}
+	}
+
+	return nil
+}
+
 // validateTemplateResourceBoundsConsistency rejects a template whose resource bounds are
 // self-contradictory (min > max for any resource). Such a bound can never admit any workspace
 // value for that resource. CEL cannot express this on resource.Quantity, so it is enforced at
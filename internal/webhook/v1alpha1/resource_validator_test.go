@@ -429,4 +429,74 @@ var _ = Describe("Resource Validator", func() {
 			Expect(resourcesEqual(resources1, resources2)).To(BeFalse())
 		})
 	})
+
+	Context("profiles", func() {
+		var workspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			template.Spec.Profiles = []workspacev1alpha1.ResourceProfile{
+				{
+					Name: "small",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					},
+				},
+			}
+			workspace = &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testDefaultNamespace},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+					Profile:     "small",
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					},
+				},
+			}
+		})
+
+		It("should allow a profile of the template with its resources", func() {
+			Expect(validateResourceProfile(workspace, template)).To(BeNil())
+		})
+
+		It("should allow workspaces without a profile", func() {
+			workspace.Spec.Profile = ""
+			workspace.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1")
+			Expect(validateResourceProfile(workspace, template)).To(BeNil())
+		})
+
+		It("should reject a profile the template does not define", func() {
+			workspace.Spec.Profile = "large"
+			violation := validateResourceProfile(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeProfileNotAllowed))
+			Expect(violation.Field).To(Equal("spec.profile"))
+			Expect(violation.Allowed).To(Equal("small"))
+		})
+
+		It("should reject resources that differ from the profile", func() {
+			workspace.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1")
+			violation := validateResourceProfile(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Field).To(Equal("spec.resources"))
+		})
+
+		It("should reject a profile without a template", func() {
+			Expect(validateProfileRequiresTemplate(workspace)).To(Succeed())
+			workspace.Spec.TemplateRef = nil
+			Expect(validateProfileRequiresTemplate(workspace)).To(MatchError(ContainSubstring("requires a templateRef")))
+		})
+
+		It("should reject a template whose default profile is not defined", func() {
+			template.Spec.DefaultProfile = "medium"
+			Expect(validateTemplateProfileConsistency(template)).To(MatchError(ContainSubstring(`defaultProfile "medium"`)))
+		})
+
+		It("should reject a template whose profile exceeds its resource bounds", func() {
+			template.Spec.DefaultProfile = "small"
+			Expect(validateTemplateProfileConsistency(template)).To(Succeed())
+
+			template.Spec.Profiles[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("8")
+			Expect(validateTemplateProfileConsistency(template)).To(MatchError(ContainSubstring(`profile "small"`)))
+		})
+	})
 })
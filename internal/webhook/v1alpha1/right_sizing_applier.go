@@ -0,0 +1,89 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// RightSizingApplier applies the requests recommended to a workspace by the right-sizing of the
+// controller as the workspace starts, when auto-apply is enabled. It leaves workspaces with a
+// resource profile alone, as well as recommendations outside the resource bounds of the template.
+type RightSizingApplier struct {
+	enabled  bool
+	resolver *workspaceutil.TemplateResolver
+}
+
+// NewRightSizingApplier creates a new RightSizingApplier, which does nothing unless enabled
+func NewRightSizingApplier(enabled bool, k8sClient client.Client, defaultTemplateNamespace string) *RightSizingApplier {
+	return &RightSizingApplier{
+		enabled:  enabled,
+		resolver: workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+	}
+}
+
+// ApplyRightSizing sets the recommended requests on a workspace being started
+func (a *RightSizingApplier) ApplyRightSizing(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if a == nil || !a.enabled || workspace.Spec.Profile != "" {
+		return nil
+	}
+	rightSizing := controller.RightSizingStatus(workspace)
+	if rightSizing == nil || len(rightSizing.RecommendedRequests) == 0 {
+		return nil
+	}
+
+	starting, err := isWorkspaceStarting(ctx, workspace)
+	if err != nil || !starting {
+		return err
+	}
+
+	resources := rightSizedResources(workspace, rightSizing.RecommendedRequests)
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		template, err := a.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+		if err != nil {
+			return err
+		}
+		if violations := validateResourceBounds(resources, template); len(violations) > 0 {
+			workspacelog.Info("Skipped right-sizing recommendation outside the template bounds on workspace start",
+				"workspace", workspace.Name, "template", template.Name, "violations", len(violations))
+			return nil
+		}
+	}
+
+	workspace.Spec.Resources = &resources
+	workspacelog.Info("Applied right-sizing recommendation on workspace start",
+		"workspace", workspace.Name, "requests", rightSizing.RecommendedRequests)
+	return nil
+}
+
+// rightSizedResources returns the resources of the workspace, or else of its pod, with the
+// recommended requests. Limits below the recommended requests are raised to them.
+func rightSizedResources(workspace *workspacev1alpha1.Workspace, recommended corev1.ResourceList) corev1.ResourceRequirements {
+	var resources corev1.ResourceRequirements
+	if workspace.Spec.Resources != nil {
+		workspace.Spec.Resources.DeepCopyInto(&resources)
+	} else if workspace.Status.EffectiveSpec != nil {
+		workspace.Status.EffectiveSpec.Resources.DeepCopyInto(&resources)
+	}
+
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	for name, request := range recommended {
+		resources.Requests[name] = request.DeepCopy()
+		if limit, ok := resources.Limits[name]; ok && limit.Cmp(request) < 0 {
+			resources.Limits[name] = request.DeepCopy()
+		}
+	}
+	return resources
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("RightSizingApplier", func() {
+	newTemplate := func() *workspacev1alpha1.WorkspaceTemplate {
+		return &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testSomeTemplate, Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  "Some Template",
+				DefaultImage: testValidBaseNotebook,
+				ResourceBounds: &workspacev1alpha1.ResourceBounds{
+					Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+						corev1.ResourceCPU: {Min: resource.MustParse("250m"), Max: resource.MustParse("4")},
+					},
+				},
+			},
+		}
+	}
+
+	newWorkspace := func(desiredStatus string, recommended corev1.ResourceList) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DesiredStatus: desiredStatus,
+				TemplateRef:   &workspacev1alpha1.TemplateRef{Name: testSomeTemplate},
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("2"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("2"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+			},
+			Status: workspacev1alpha1.WorkspaceStatus{
+				Usage: &workspacev1alpha1.WorkspaceUsageStatus{
+					RightSizing: &workspacev1alpha1.WorkspaceRightSizingStatus{RecommendedRequests: recommended},
+				},
+			},
+		}
+	}
+
+	buildApplier := func(enabled bool) *RightSizingApplier {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTemplate()).Build()
+		return NewRightSizingApplier(enabled, fakeClient, "")
+	}
+
+	startContext := func() context.Context {
+		raw, err := json.Marshal(newWorkspace(controller.DesiredStateStopped, nil))
+		Expect(err).NotTo(HaveOccurred())
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		})
+	}
+
+	It("should apply the recommended requests when a workspace starts", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("3Gi"),
+		})
+
+		Expect(buildApplier(true).ApplyRightSizing(startContext(), workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("500m"))
+		Expect(workspace.Spec.Resources.Requests.Memory().String()).To(Equal("3Gi"))
+		Expect(workspace.Spec.Resources.Limits.Cpu().String()).To(Equal("2"))
+		Expect(workspace.Spec.Resources.Limits.Memory().String()).To(Equal("3Gi"))
+	})
+
+	It("should leave the workspace unchanged when auto-apply is disabled", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")})
+
+		Expect(buildApplier(false).ApplyRightSizing(startContext(), workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("should leave a running workspace unchanged", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")})
+		raw, err := json.Marshal(newWorkspace(controller.DesiredStateRunning, nil))
+		Expect(err).NotTo(HaveOccurred())
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update, OldObject: runtime.RawExtension{Raw: raw}},
+		})
+
+		Expect(buildApplier(true).ApplyRightSizing(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("should skip recommendations outside the template bounds", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")})
+
+		Expect(buildApplier(true).ApplyRightSizing(startContext(), workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("should leave a workspace with a resource profile unchanged", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")})
+		workspace.Spec.Profile = "small"
+
+		Expect(buildApplier(true).ApplyRightSizing(startContext(), workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("should start from the resources of the workspace pod when the workspace sets none", func() {
+		workspace := newWorkspace(controller.DesiredStateRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")})
+		workspace.Status.EffectiveSpec = &workspacev1alpha1.EffectiveSpecStatus{Resources: *workspace.Spec.Resources}
+		workspace.Spec.Resources = nil
+
+		Expect(buildApplier(true).ApplyRightSizing(startContext(), workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Resources).NotTo(BeNil())
+		Expect(workspace.Spec.Resources.Requests.Cpu().String()).To(Equal("500m"))
+		Expect(workspace.Spec.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+	})
+})
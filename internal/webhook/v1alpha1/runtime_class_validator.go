@@ -0,0 +1,69 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// allowedRuntimeClassNames returns the RuntimeClasses workspaces of the template can use:
+// allowedRuntimeClassNames, or only runtimeClassName when the list is empty
+func allowedRuntimeClassNames(template *workspacev1alpha1.WorkspaceTemplate) []string {
+	if len(template.Spec.AllowedRuntimeClassNames) > 0 {
+		return template.Spec.AllowedRuntimeClassNames
+	}
+	if template.Spec.RuntimeClassName != "" {
+		return []string{template.Spec.RuntimeClassName}
+	}
+	return nil
+}
+
+// validateRuntimeClassName checks that the runtime class of a workspace is allowed by the template.
+// A template that sets a runtime class requires one, so that workspaces cannot opt out of a
+// sandboxed runtime by clearing the field.
+func validateRuntimeClassName(runtimeClassName string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if runtimeClassName == "" && template.Spec.RuntimeClassName == "" {
+		return nil
+	}
+
+	allowed := allowedRuntimeClassNames(template)
+	if runtimeClassName != "" && slices.Contains(allowed, runtimeClassName) {
+		return nil
+	}
+
+	allowedDescription := "no runtime class"
+	if len(allowed) > 0 {
+		allowedDescription = strings.Join(allowed, ", ")
+	}
+	message := fmt.Sprintf("runtime class '%s' is not allowed by template '%s'", runtimeClassName, template.Name)
+	if runtimeClassName == "" {
+		message = fmt.Sprintf("template '%s' requires a runtime class", template.Name)
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeRuntimeClassNotAllowed,
+		Field:   "spec.runtimeClassName",
+		Message: message,
+		Allowed: allowedDescription,
+		Actual:  runtimeClassName,
+	}
+}
+
+// validateTemplateRuntimeClassConsistency rejects a template whose runtime class is not one of its
+// allowed runtime classes, which would make its own workspaces un-admittable
+func validateTemplateRuntimeClassConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.RuntimeClassName == "" {
+		return nil
+	}
+	if violation := validateRuntimeClassName(template.Spec.RuntimeClassName, template); violation != nil {
+		return fmt.Errorf("runtimeClassName %q is not one of the allowedRuntimeClassNames of template %q",
+			template.Spec.RuntimeClassName, template.GetName())
+	}
+	return nil
+}
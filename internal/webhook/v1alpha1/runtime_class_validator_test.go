@@ -0,0 +1,90 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testRuntimeGVisor = "gvisor"
+	testRuntimeKata   = "kata"
+)
+
+var _ = Describe("RuntimeClassValidator", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				RuntimeClassName:         testRuntimeGVisor,
+				AllowedRuntimeClassNames: []string{testRuntimeGVisor, testRuntimeKata},
+			},
+		}
+	})
+
+	Context("validateRuntimeClassName", func() {
+		It("should allow a runtime class of the allowlist", func() {
+			Expect(validateRuntimeClassName(testRuntimeKata, template)).To(BeNil())
+		})
+
+		It("should reject a runtime class outside of the allowlist", func() {
+			violation := validateRuntimeClassName("runc", template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeRuntimeClassNotAllowed))
+			Expect(violation.Field).To(Equal("spec.runtimeClassName"))
+			Expect(violation.Allowed).To(Equal("gvisor, kata"))
+		})
+
+		It("should reject workspaces without a runtime class when the template sets one", func() {
+			violation := validateRuntimeClassName("", template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Message).To(ContainSubstring("requires a runtime class"))
+		})
+
+		It("should allow workspaces without a runtime class when the template sets none", func() {
+			template.Spec.RuntimeClassName = ""
+			template.Spec.AllowedRuntimeClassNames = nil
+			Expect(validateRuntimeClassName("", template)).To(BeNil())
+		})
+
+		It("should only allow the template runtime class when the allowlist is empty", func() {
+			template.Spec.AllowedRuntimeClassNames = nil
+			Expect(validateRuntimeClassName(testRuntimeGVisor, template)).To(BeNil())
+			Expect(validateRuntimeClassName(testRuntimeKata, template)).NotTo(BeNil())
+		})
+
+		It("should reject any runtime class when the template sets none", func() {
+			template.Spec.RuntimeClassName = ""
+			template.Spec.AllowedRuntimeClassNames = nil
+			violation := validateRuntimeClassName(testRuntimeGVisor, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Allowed).To(Equal("no runtime class"))
+		})
+	})
+
+	Context("validateTemplateRuntimeClassConsistency", func() {
+		It("should accept a runtime class of the allowlist", func() {
+			Expect(validateTemplateRuntimeClassConsistency(template)).To(Succeed())
+		})
+
+		It("should accept a template without a runtime class", func() {
+			template.Spec.RuntimeClassName = ""
+			Expect(validateTemplateRuntimeClassConsistency(template)).To(Succeed())
+		})
+
+		It("should reject a runtime class outside of the allowlist", func() {
+			template.Spec.AllowedRuntimeClassNames = []string{testRuntimeKata}
+			Expect(validateTemplateRuntimeClassConsistency(template)).To(
+				MatchError(ContainSubstring(`runtimeClassName "gvisor"`)))
+		})
+	})
+})
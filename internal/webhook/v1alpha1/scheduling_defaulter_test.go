@@ -129,6 +129,23 @@ var _ = Describe("SchedulingDefaulter", func() {
 			Expect(workspace.Spec.Tolerations[0].Key).To(Equal(testExistingKey))
 		})
 
+		It("should apply the default priority class when not set", func() {
+			template.Spec.DefaultPriorityClassName = "interactive"
+
+			applySchedulingDefaults(workspace, template)
+
+			Expect(workspace.Spec.PriorityClassName).To(Equal("interactive"))
+		})
+
+		It("should not override an existing priority class", func() {
+			template.Spec.DefaultPriorityClassName = "interactive"
+			workspace.Spec.PriorityClassName = "batch"
+
+			applySchedulingDefaults(workspace, template)
+
+			Expect(workspace.Spec.PriorityClassName).To(Equal("batch"))
+		})
+
 		It("should create independent copies (deep copy test)", func() {
 			applySchedulingDefaults(workspace, template)
 
@@ -0,0 +1,61 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// allowedPriorityClassNames returns the PriorityClasses workspaces of the template can use:
+// allowedPriorityClassNames, or only defaultPriorityClassName when the list is empty
+func allowedPriorityClassNames(template *workspacev1alpha1.WorkspaceTemplate) []string {
+	if len(template.Spec.AllowedPriorityClassNames) > 0 {
+		return template.Spec.AllowedPriorityClassNames
+	}
+	if template.Spec.DefaultPriorityClassName != "" {
+		return []string{template.Spec.DefaultPriorityClassName}
+	}
+	return nil
+}
+
+// validatePriorityClassName checks that the priority class of a workspace is allowed by the template,
+// so that users cannot raise the priority of their workspaces over other workloads
+func validatePriorityClassName(priorityClassName string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if priorityClassName == "" {
+		return nil
+	}
+
+	allowed := allowedPriorityClassNames(template)
+	if slices.Contains(allowed, priorityClassName) {
+		return nil
+	}
+
+	allowedDescription := "no priority class"
+	if len(allowed) > 0 {
+		allowedDescription = strings.Join(allowed, ", ")
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypePriorityClassNotAllowed,
+		Field:   "spec.priorityClassName",
+		Message: fmt.Sprintf("priority class '%s' is not allowed by template '%s'", priorityClassName, template.Name),
+		Allowed: allowedDescription,
+		Actual:  priorityClassName,
+	}
+}
+
+// validateTemplatePriorityClassConsistency rejects a template whose default priority class is not
+// one of its allowed priority classes, which would make its own workspaces un-admittable
+func validateTemplatePriorityClassConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if violation := validatePriorityClassName(template.Spec.DefaultPriorityClassName, template); violation != nil {
+		return fmt.Errorf("defaultPriorityClassName %q is not one of the allowedPriorityClassNames of template %q",
+			template.Spec.DefaultPriorityClassName, template.GetName())
+	}
+	return nil
+}
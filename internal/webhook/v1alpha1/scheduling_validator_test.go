@@ -0,0 +1,77 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	testPriorityInteractive = "interactive"
+	testPriorityBatch       = "batch"
+)
+
+var _ = Describe("SchedulingValidator", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultPriorityClassName:  testPriorityBatch,
+				AllowedPriorityClassNames: []string{testPriorityBatch, testPriorityInteractive},
+			},
+		}
+	})
+
+	Context("validatePriorityClassName", func() {
+		It("should allow workspaces without a priority class", func() {
+			Expect(validatePriorityClassName("", template)).To(BeNil())
+		})
+
+		It("should allow a priority class of the allowlist", func() {
+			Expect(validatePriorityClassName(testPriorityInteractive, template)).To(BeNil())
+		})
+
+		It("should reject a priority class outside of the allowlist", func() {
+			violation := validatePriorityClassName("system-cluster-critical", template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypePriorityClassNotAllowed))
+			Expect(violation.Field).To(Equal("spec.priorityClassName"))
+			Expect(violation.Allowed).To(Equal("batch, interactive"))
+		})
+
+		It("should only allow the default priority class when the allowlist is empty", func() {
+			template.Spec.AllowedPriorityClassNames = nil
+			Expect(validatePriorityClassName(testPriorityBatch, template)).To(BeNil())
+			Expect(validatePriorityClassName(testPriorityInteractive, template)).NotTo(BeNil())
+		})
+
+		It("should reject any priority class when the template sets none", func() {
+			template.Spec.AllowedPriorityClassNames = nil
+			template.Spec.DefaultPriorityClassName = ""
+			violation := validatePriorityClassName(testPriorityBatch, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Allowed).To(Equal("no priority class"))
+		})
+	})
+
+	Context("validateTemplatePriorityClassConsistency", func() {
+		It("should accept a default priority class of the allowlist", func() {
+			Expect(validateTemplatePriorityClassConsistency(template)).To(Succeed())
+		})
+
+		It("should reject a default priority class outside of the allowlist", func() {
+			template.Spec.AllowedPriorityClassNames = []string{testPriorityInteractive}
+			Expect(validateTemplatePriorityClassConsistency(template)).To(
+				MatchError(ContainSubstring(`defaultPriorityClassName "batch"`)))
+		})
+	})
+})
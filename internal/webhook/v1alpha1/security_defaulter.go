@@ -20,4 +20,24 @@ func applySecurityDefaults(workspace *workspacev1alpha1.Workspace, template *wor
 	if workspace.Spec.ContainerSecurityContext == nil && template.Spec.DefaultContainerSecurityContext != nil {
 		workspace.Spec.ContainerSecurityContext = template.Spec.DefaultContainerSecurityContext.DeepCopy()
 	}
+
+	// Apply the security profile, which workspaces of the template cannot change
+	if workspace.Spec.SecurityProfile == nil && template.Spec.SecurityProfile != nil {
+		workspace.Spec.SecurityProfile = template.Spec.SecurityProfile.DeepCopy()
+	}
+
+	// Apply the network isolation, which workspaces of the template cannot change
+	if workspace.Spec.NetworkIsolation == nil && template.Spec.NetworkIsolation != nil {
+		workspace.Spec.NetworkIsolation = template.Spec.NetworkIsolation.DeepCopy()
+	}
+
+	// Apply the provisioning hints, which workspaces of the template cannot change
+	if workspace.Spec.ProvisioningHints == nil && template.Spec.ProvisioningHints != nil {
+		workspace.Spec.ProvisioningHints = template.Spec.ProvisioningHints.DeepCopy()
+	}
+
+	// Apply runtime class defaults
+	if workspace.Spec.RuntimeClassName == "" {
+		workspace.Spec.RuntimeClassName = template.Spec.RuntimeClassName
+	}
 }
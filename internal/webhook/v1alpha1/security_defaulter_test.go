@@ -99,6 +99,35 @@ var _ = Describe("SecurityDefaulter", func() {
 			Expect(*workspace.Spec.ContainerSecurityContext.RunAsNonRoot).To(BeTrue())
 			Expect(*workspace.Spec.ContainerSecurityContext.RunAsUser).To(Equal(int64(1000)))
 		})
+
+		It("should copy the template security profile", func() {
+			template.Spec.SecurityProfile = &workspacev1alpha1.SecurityProfile{
+				Level:                  workspacev1alpha1.SecurityProfileRestricted,
+				ReadOnlyRootFilesystem: true,
+			}
+
+			applySecurityDefaults(workspace, template)
+
+			Expect(workspace.Spec.SecurityProfile).To(Equal(template.Spec.SecurityProfile))
+			Expect(workspace.Spec.SecurityProfile).NotTo(BeIdenticalTo(template.Spec.SecurityProfile))
+		})
+
+		It("should apply the template runtime class when not set", func() {
+			template.Spec.RuntimeClassName = testRuntimeGVisor
+
+			applySecurityDefaults(workspace, template)
+
+			Expect(workspace.Spec.RuntimeClassName).To(Equal(testRuntimeGVisor))
+		})
+
+		It("should not override an existing runtime class", func() {
+			template.Spec.RuntimeClassName = testRuntimeGVisor
+			workspace.Spec.RuntimeClassName = testRuntimeKata
+
+			applySecurityDefaults(workspace, template)
+
+			Expect(workspace.Spec.RuntimeClassName).To(Equal(testRuntimeKata))
+		})
 	})
 })
 
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validateSecurityProfile checks that a workspace keeps the security profile of its template, and that
+// its security contexts only request the exceptions the profile allows. The deployment builder would
+// otherwise silently override these settings, leaving users with a pod that differs from their spec.
+func validateSecurityProfile(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	profile := template.Spec.SecurityProfile
+	if profile == nil {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	if !equality.Semantic.DeepEqual(workspace.Spec.SecurityProfile, profile) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeSecurityProfileMismatch,
+			Field:   "spec.securityProfile",
+			Message: fmt.Sprintf("security profile must match the security profile of template '%s'", template.Name),
+			Allowed: fmt.Sprintf("security profile of template '%s'", template.Name),
+			Actual:  "modified security profile",
+		})
+	}
+
+	if podSecurityContext := workspace.Spec.PodSecurityContext; podSecurityContext != nil {
+		if !profile.AllowRunAsRoot && runsAsRoot(podSecurityContext.RunAsNonRoot, podSecurityContext.RunAsUser) {
+			violations = append(violations, runAsRootViolation("spec.podSecurityContext"))
+		}
+		if isUnconfined(podSecurityContext.SeccompProfile) {
+			violations = append(violations, unconfinedSeccompViolation("spec.podSecurityContext"))
+		}
+	}
+
+	violations = append(violations,
+		securityProfileViolations(workspace.Spec.ContainerSecurityContext, "spec.containerSecurityContext", profile)...)
+	for i, initContainer := range workspace.Spec.InitContainers {
+		violations = append(violations,
+			securityProfileViolations(initContainer.SecurityContext, fmt.Sprintf("spec.initContainers[%d].securityContext", i), profile)...)
+	}
+
+	return violations
+}
+
+// securityProfileViolations lists the settings of a container security context that the profile does not allow
+func securityProfileViolations(
+	securityContext *corev1.SecurityContext,
+	field string,
+	profile *workspacev1alpha1.SecurityProfile,
+) []TemplateViolation {
+	if securityContext == nil {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	if isTrue(securityContext.Privileged) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypePrivilegedNotAllowed,
+			Field:   field + ".privileged",
+			Message: "privileged containers are not allowed by the security profile",
+			Allowed: "false",
+			Actual:  "true",
+		})
+	}
+	if isTrue(securityContext.AllowPrivilegeEscalation) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypePrivilegeEscalationNotAllowed,
+			Field:   field + ".allowPrivilegeEscalation",
+			Message: "privilege escalation is not allowed by the security profile",
+			Allowed: "false",
+			Actual:  "true",
+		})
+	}
+	if !profile.AllowRunAsRoot && runsAsRoot(securityContext.RunAsNonRoot, securityContext.RunAsUser) {
+		violations = append(violations, runAsRootViolation(field))
+	}
+	if isUnconfined(securityContext.SeccompProfile) {
+		violations = append(violations, unconfinedSeccompViolation(field))
+	}
+	if profile.ReadOnlyRootFilesystem && securityContext.ReadOnlyRootFilesystem != nil && !*securityContext.ReadOnlyRootFilesystem {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeWritableRootFSNotAllowed,
+			Field:   field + ".readOnlyRootFilesystem",
+			Message: "a writable root filesystem is not allowed by the security profile",
+			Allowed: "true",
+			Actual:  "false",
+		})
+	}
+	if securityContext.Capabilities != nil {
+		for i, capability := range securityContext.Capabilities.Add {
+			if controller.SecurityProfileAllowsCapability(profile, capability) {
+				continue
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeCapabilityNotAllowed,
+				Field:   fmt.Sprintf("%s.capabilities.add[%d]", field, i),
+				Message: fmt.Sprintf("capability %s is not allowed by the security profile", capability),
+				Allowed: "NET_BIND_SERVICE and the allowedCapabilities of the security profile",
+				Actual:  string(capability),
+			})
+		}
+	}
+	return violations
+}
+
+// runsAsRoot returns whether a security context explicitly requests to run as root
+func runsAsRoot(runAsNonRoot *bool, runAsUser *int64) bool {
+	return (runAsNonRoot != nil && !*runAsNonRoot) || (runAsUser != nil && *runAsUser == 0)
+}
+
+// isUnconfined returns whether a seccomp profile disables seccomp
+func isUnconfined(seccompProfile *corev1.SeccompProfile) bool {
+	return seccompProfile != nil && seccompProfile.Type == corev1.SeccompProfileTypeUnconfined
+}
+
+// runAsRootViolation reports a security context that runs as root without the allowRunAsRoot exception
+func runAsRootViolation(field string) TemplateViolation {
+	return TemplateViolation{
+		Type:    ViolationTypeRunAsRootNotAllowed,
+		Field:   field,
+		Message: "running as root is not allowed by the security profile",
+		Allowed: "runAsNonRoot: true",
+		Actual:  "root",
+	}
+}
+
+// unconfinedSeccompViolation reports a security context that disables seccomp
+func unconfinedSeccompViolation(field string) TemplateViolation {
+	return TemplateViolation{
+		Type:    ViolationTypeSeccompProfileNotAllowed,
+		Field:   field + ".seccompProfile",
+		Message: "the Unconfined seccomp profile is not allowed by the security profile",
+		Allowed: "RuntimeDefault or Localhost",
+		Actual:  string(corev1.SeccompProfileTypeUnconfined),
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("SecurityProfileValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				SecurityProfile: &workspacev1alpha1.SecurityProfile{
+					Level:                  workspacev1alpha1.SecurityProfileRestricted,
+					ReadOnlyRootFilesystem: true,
+					AllowedCapabilities:    []corev1.Capability{"SYS_PTRACE"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName:     testWorkspaceDisplayName,
+				SecurityProfile: template.Spec.SecurityProfile.DeepCopy(),
+			},
+		}
+	})
+
+	It("should accept any workspace when the template has no security profile", func() {
+		template.Spec.SecurityProfile = nil
+		workspace.Spec.SecurityProfile = nil
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{RunAsUser: int64Ptr(0)}
+		Expect(validateSecurityProfile(workspace, template)).To(BeEmpty())
+	})
+
+	It("should accept a workspace with the template security profile", func() {
+		Expect(validateSecurityProfile(workspace, template)).To(BeEmpty())
+	})
+
+	It("should reject a workspace that changes the security profile", func() {
+		workspace.Spec.SecurityProfile.AllowRunAsRoot = true
+		violations := validateSecurityProfile(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Type).To(Equal(ViolationTypeSecurityProfileMismatch))
+		Expect(violations[0].Field).To(Equal("spec.securityProfile"))
+	})
+
+	It("should reject a workspace that removes the security profile", func() {
+		workspace.Spec.SecurityProfile = nil
+		violations := validateSecurityProfile(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Type).To(Equal(ViolationTypeSecurityProfileMismatch))
+	})
+
+	It("should reject running as root without the exception", func() {
+		workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: int64Ptr(0)}
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{RunAsNonRoot: boolPtr(false)}
+		violations := validateSecurityProfile(workspace, template)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Type).To(Equal(ViolationTypeRunAsRootNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.podSecurityContext"))
+		Expect(violations[1].Field).To(Equal("spec.containerSecurityContext"))
+	})
+
+	It("should accept running as root with the exception", func() {
+		template.Spec.SecurityProfile.AllowRunAsRoot = true
+		workspace.Spec.SecurityProfile.AllowRunAsRoot = true
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{RunAsUser: int64Ptr(0)}
+		Expect(validateSecurityProfile(workspace, template)).To(BeEmpty())
+	})
+
+	It("should only accept the allowed capabilities", func() {
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE", "CAP_SYS_PTRACE", "NET_ADMIN"}},
+		}
+		violations := validateSecurityProfile(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Type).To(Equal(ViolationTypeCapabilityNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.containerSecurityContext.capabilities.add[2]"))
+	})
+
+	It("should reject privilege escalation, unconfined seccomp and a writable root filesystem", func() {
+		workspace.Spec.InitContainers = []corev1.Container{{
+			Name: "setup",
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolPtr(true),
+				ReadOnlyRootFilesystem:   boolPtr(false),
+				SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+			},
+		}}
+		violations := validateSecurityProfile(workspace, template)
+		Expect(violations).To(HaveLen(3))
+		Expect(violations[0].Type).To(Equal(ViolationTypePrivilegeEscalationNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.initContainers[0].securityContext.allowPrivilegeEscalation"))
+		Expect(violations[1].Type).To(Equal(ViolationTypeSeccompProfileNotAllowed))
+		Expect(violations[2].Type).To(Equal(ViolationTypeWritableRootFSNotAllowed))
+	})
+})
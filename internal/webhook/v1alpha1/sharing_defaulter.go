@@ -12,7 +12,8 @@ import (
 
 // setWorkspaceDefaults sets default values for OwnershipType and AccessType
 // If OwnershipType is not set, we default to Public.
-// If AccessType is not set, we default to OwnershipType value
+// If AccessType is not set, we default to OwnershipType value, or to Public for GroupOnly
+// workspaces so that the members of the owner group can connect
 func setWorkspaceSharingDefaults(workspace *workspacev1alpha1.Workspace) {
 	if workspace.Spec.OwnershipType == "" {
 		workspace.Spec.OwnershipType = webhookconst.OwnershipTypePublic
@@ -20,5 +21,8 @@ func setWorkspaceSharingDefaults(workspace *workspacev1alpha1.Workspace) {
 
 	if workspace.Spec.AccessType == "" {
 		workspace.Spec.AccessType = workspace.Spec.OwnershipType
+		if workspace.Spec.OwnershipType == webhookconst.OwnershipTypeGroupOnly {
+			workspace.Spec.AccessType = webhookconst.OwnershipTypePublic
+		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
 )
 
 var _ = Describe("setWorkspaceSharingDefaults", func() {
@@ -113,4 +114,16 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 		Expect(workspace.Spec.OwnershipType).To(Equal(testOwnershipPublic))
 		Expect(workspace.Spec.AccessType).To(Equal(testOwnershipPublic))
 	})
+
+	It("should set Public AccessType when OwnershipType is GroupOnly and AccessType is not populated", func() {
+		workspace := &workspacev1alpha1.Workspace{
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				OwnershipType: webhookconst.OwnershipTypeGroupOnly,
+				OwnerGroup:    "team",
+			},
+		}
+		setWorkspaceSharingDefaults(workspace)
+		Expect(workspace.Spec.OwnershipType).To(Equal(webhookconst.OwnershipTypeGroupOnly))
+		Expect(workspace.Spec.AccessType).To(Equal(testOwnershipPublic))
+	})
 })
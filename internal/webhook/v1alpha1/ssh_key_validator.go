@@ -0,0 +1,31 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateSSHPublicKeys checks that each entry of spec.sshPublicKeys holds a single public key
+// in authorized_keys format. Entries are written one per line in the authorized_keys file of
+// the workspace, so an entry spanning several lines could authorize additional keys.
+func validateSSHPublicKeys(workspace *workspacev1alpha1.Workspace) error {
+	for i, key := range workspace.Spec.SSHPublicKeys {
+		key = strings.TrimSpace(key)
+		if strings.ContainsAny(key, "\r\n") {
+			return fmt.Errorf("spec.sshPublicKeys[%d] must hold a single public key on one line", i)
+		}
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+			return fmt.Errorf("spec.sshPublicKeys[%d] is not a valid SSH public key: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("SSH Public Keys Validator", func() {
+
+	var (
+		workspace *workspacev1alpha1.Workspace
+		publicKey string
+	)
+
+	BeforeEach(func() {
+		rawKey, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		sshKey, err := ssh.NewPublicKey(rawKey)
+		Expect(err).NotTo(HaveOccurred())
+		publicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshKey))) + " alice@laptop"
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testWorkspaceName,
+				Namespace: testDefaultNamespace,
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image: testValidBaseNotebook,
+			},
+		}
+	})
+
+	It("should allow a workspace without SSH keys", func() {
+		Expect(validateSSHPublicKeys(workspace)).To(Succeed())
+	})
+
+	It("should allow valid public keys", func() {
+		workspace.Spec.SSHPublicKeys = []string{publicKey, publicKey + "\n"}
+		Expect(validateSSHPublicKeys(workspace)).To(Succeed())
+	})
+
+	It("should reject an entry that is not a public key", func() {
+		workspace.Spec.SSHPublicKeys = []string{publicKey, "not-a-key"}
+		err := validateSSHPublicKeys(workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.sshPublicKeys[1]"))
+	})
+
+	It("should reject an entry holding several lines", func() {
+		workspace.Spec.SSHPublicKeys = []string{publicKey + "\n" + publicKey}
+		err := validateSSHPublicKeys(workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("one line"))
+	})
+})
@@ -36,5 +36,10 @@ func applyStorageDefaults(workspace *workspacev1alpha1.Workspace, template *work
 		if workspace.Spec.Storage.MountPath == "" && template.Spec.PrimaryStorage.DefaultMountPath != "" {
 			workspace.Spec.Storage.MountPath = template.Spec.PrimaryStorage.DefaultMountPath
 		}
+
+		// Apply the provisioning hook, which workspaces cannot change
+		if workspace.Spec.Storage.ProvisioningHook == nil && template.Spec.PrimaryStorage.ProvisioningHook != nil {
+			workspace.Spec.Storage.ProvisioningHook = template.Spec.PrimaryStorage.ProvisioningHook.DeepCopy()
+		}
 	}
 }
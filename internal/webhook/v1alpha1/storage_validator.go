@@ -21,13 +21,15 @@ import (
 
 // StorageValidator handles storage validation that needs cluster state (the workspace's PVC).
 type StorageValidator struct {
-	client client.Client
+	client             client.Client
+	ownershipValidator *OwnershipValidator
 }
 
 // NewStorageValidator creates a new StorageValidator.
-func NewStorageValidator(k8sClient client.Client) *StorageValidator {
+func NewStorageValidator(k8sClient client.Client, ownershipValidator *OwnershipValidator) *StorageValidator {
 	return &StorageValidator{
-		client: k8sClient,
+		client:             k8sClient,
+		ownershipValidator: ownershipValidator,
 	}
 }
 
@@ -51,8 +53,8 @@ func NewStorageValidator(k8sClient client.Client) *StorageValidator {
 // failurePolicy=fail rejection.
 //
 // Growth is intentionally not blocked here: whether an increase succeeds depends on the
-// StorageClass's allowVolumeExpansion, which the webhook does not resolve; that gotcha still
-// surfaces at reconcile time.
+// StorageClass's allowVolumeExpansion, which the webhook does not resolve; the controller reports
+// an unsupported expansion in status.storageExpansion at reconcile time.
 func (sv *StorageValidator) ValidateStorageSizeNotShrinking(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
 	if workspace.Spec.Storage == nil || workspace.Spec.Storage.Size.IsZero() {
 		return nil
@@ -61,7 +63,7 @@ func (sv *StorageValidator) ValidateStorageSizeNotShrinking(ctx context.Context,
 
 	pvc := &corev1.PersistentVolumeClaim{}
 	err := sv.client.Get(ctx, types.NamespacedName{
-		Name:      controller.GeneratePVCName(workspace.Name),
+		Name:      controller.GeneratePVCName(workspace),
 		Namespace: workspace.Namespace,
 	}, pvc)
 	if err != nil {
@@ -81,6 +83,101 @@ func (sv *StorageValidator) ValidateStorageSizeNotShrinking(ctx context.Context,
 	return nil
 }
 
+// ValidateRestoreFromSnapshot checks that the WorkspaceSnapshot a new workspace restores from
+// exists in the workspace's namespace, is ready to use, and fits in the requested storage size.
+// A volume restored from a snapshot cannot be smaller than the snapshot's restore size, and a
+// snapshot that never becomes ready would leave the PVC pending forever, so both are rejected
+// at admission rather than surfacing as a stuck workspace.
+//
+// A restore exposes the data of the snapshot's source workspace, so unless they are the controller
+// or an admin, the user must have the same access to the source workspace as to clone it. The
+// snapshot may have been taken by someone else, or before the source changed hands.
+func (sv *StorageValidator) ValidateRestoreFromSnapshot(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if !hasRestoreFromSnapshot(workspace) {
+		return nil
+	}
+	snapshotName := workspace.Spec.Storage.RestoreFromSnapshot.Name
+
+	snapshot := &workspacev1alpha1.WorkspaceSnapshot{}
+	err := sv.client.Get(ctx, types.NamespacedName{Name: snapshotName, Namespace: workspace.Namespace}, snapshot)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("workspace snapshot %q not found in namespace %q", snapshotName, workspace.Namespace)
+		}
+		return fmt.Errorf("unable to validate workspace snapshot %q: %w", snapshotName, err)
+	}
+
+	if snapshot.Status.Phase != workspacev1alpha1.SnapshotPhaseReady {
+		return fmt.Errorf("workspace snapshot %q is not ready to restore from (phase: %q)", snapshotName, snapshot.Status.Phase)
+	}
+
+	if violation := validateStorageSizeFitsSnapshot(workspace.Spec.Storage.Size, snapshot); violation != nil {
+		return fmt.Errorf("workspace violates storage constraints: %s", violation.Message)
+	}
+
+	if isControllerOrAdminUser(ctx) {
+		return nil
+	}
+	return sv.validateSnapshotSourceAccess(ctx, snapshot)
+}
+
+// validateRestoreFromSnapshotNotAdded rejects an update that adds spec.storage.restoreFromSnapshot.
+// The CEL rule on the field only compares it to an old value, so without this check the field could
+// be added to an existing workspace whose PVC is not created yet, restoring the snapshot without the
+// checks ValidateRestoreFromSnapshot makes on create.
+func validateRestoreFromSnapshotNotAdded(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if !hasRestoreFromSnapshot(newWorkspace) || hasRestoreFromSnapshot(oldWorkspace) {
+		return nil
+	}
+	return fmt.Errorf("spec.storage.restoreFromSnapshot can only be set when the workspace is created")
+}
+
+// hasRestoreFromSnapshot returns whether the workspace restores its primary storage from a snapshot
+func hasRestoreFromSnapshot(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.Spec.Storage != nil && workspace.Spec.Storage.RestoreFromSnapshot != nil
+}
+
+// validateSnapshotSourceAccess checks that the user may read the data of the snapshot's source
+// workspace. Once the source is deleted, only the owner recorded in the snapshot's created-by
+// annotation, e.g. by the Snapshot storage retention policy, may restore it.
+func (sv *StorageValidator) validateSnapshotSourceAccess(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) error {
+	source := &workspacev1alpha1.Workspace{}
+	err := sv.client.Get(ctx, types.NamespacedName{Name: snapshot.Spec.WorkspaceName, Namespace: snapshot.Namespace}, source)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			if isSnapshotOwner(ctx, snapshot) {
+				return nil
+			}
+			return fmt.Errorf("source workspace %q of workspace snapshot %q no longer exists, only its owner or an admin can restore from it",
+				snapshot.Spec.WorkspaceName, snapshot.Name)
+		}
+		return fmt.Errorf("unable to validate the source workspace of workspace snapshot %q: %w", snapshot.Name, err)
+	}
+	return sv.ownershipValidator.ValidateDataAccess(ctx, source, "restore a snapshot of")
+}
+
+// validateStorageSizeFitsSnapshot returns a violation when size is set and smaller than the
+// snapshot's restore size. An unset size is defaulted by the controller, and a snapshot without
+// a reported restore size cannot be checked, so both are allowed.
+func validateStorageSizeFitsSnapshot(size resource.Quantity, snapshot *workspacev1alpha1.WorkspaceSnapshot) *TemplateViolation {
+	if size.IsZero() || snapshot.Status.RestoreSize == nil {
+		return nil
+	}
+
+	restoreSize := *snapshot.Status.RestoreSize
+	if size.Cmp(restoreSize) < 0 {
+		return &TemplateViolation{
+			Type:    ViolationTypeStorageExceeded,
+			Field:   fieldStorageSize,
+			Message: fmt.Sprintf("Storage size %s is smaller than the restore size %s of workspace snapshot '%s'", size.String(), restoreSize.String(), snapshot.Name),
+			Allowed: fmt.Sprintf("size >= %s", restoreSize.String()),
+			Actual:  size.String(),
+		}
+	}
+
+	return nil
+}
+
 // validateStorageSizeNotBelowProvisioned returns a violation when newSize is smaller than the
 // PVC's current requested storage. A PVC with no storage request recorded cannot be shrunk below
 // an unknown value, so it is treated as allowed.
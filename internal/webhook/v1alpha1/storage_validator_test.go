@@ -23,6 +23,7 @@ import (
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
 )
 
 var _ = Describe("StorageValidator", func() {
@@ -56,7 +57,7 @@ var _ = Describe("StorageValidator", func() {
 	existingPVC := func(size string) *corev1.PersistentVolumeClaim {
 		return &corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      controller.GeneratePVCName(wsName),
+				Name:      controller.GeneratePVCName(makeWorkspace("")),
 				Namespace: wsNs,
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
@@ -77,7 +78,7 @@ var _ = Describe("StorageValidator", func() {
 	Context("when no PVC exists", func() {
 		It("allows any requested size (fake client returns NotFound)", func() {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
-			sv := NewStorageValidator(fakeClient)
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
 			Expect(sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))).To(Succeed())
 		})
 	})
@@ -93,7 +94,7 @@ var _ = Describe("StorageValidator", func() {
 					},
 				}).
 				Build()
-			sv := NewStorageValidator(failingClient)
+			sv := NewStorageValidator(failingClient, NewOwnershipValidator(nil))
 
 			Expect(sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(""))).To(Succeed())
 
@@ -109,7 +110,7 @@ var _ = Describe("StorageValidator", func() {
 				WithScheme(scheme).
 				WithObjects(existingPVC(pvcSize)).
 				Build()
-			sv := NewStorageValidator(fakeClient)
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
 
 			err := sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))
 			Expect(err).To(HaveOccurred())
@@ -121,7 +122,7 @@ var _ = Describe("StorageValidator", func() {
 				WithScheme(scheme).
 				WithObjects(existingPVC(pvcSize)).
 				Build()
-			sv := NewStorageValidator(fakeClient)
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
 			Expect(sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(sameAsPVC))).To(Succeed())
 		})
 
@@ -130,7 +131,7 @@ var _ = Describe("StorageValidator", func() {
 				WithScheme(scheme).
 				WithObjects(existingPVC(pvcSize)).
 				Build()
-			sv := NewStorageValidator(fakeClient)
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
 			Expect(sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(larger))).To(Succeed())
 		})
 	})
@@ -156,9 +157,9 @@ var _ = Describe("StorageValidator", func() {
 
 		It("fails closed on a Forbidden (RBAC) error", func() {
 			forbidden := apierrors.NewForbidden(
-				schema.GroupResource{Resource: pvcResource}, controller.GeneratePVCName(wsName),
+				schema.GroupResource{Resource: pvcResource}, controller.GeneratePVCName(makeWorkspace("")),
 				errors.New("not allowed"))
-			sv := NewStorageValidator(getErrorClient(forbidden))
+			sv := NewStorageValidator(getErrorClient(forbidden), NewOwnershipValidator(nil))
 
 			err := sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))
 			Expect(err).To(HaveOccurred())
@@ -168,7 +169,7 @@ var _ = Describe("StorageValidator", func() {
 		It("fails closed on a ServerTimeout (API outage) error", func() {
 			timeout := apierrors.NewServerTimeout(
 				schema.GroupResource{Resource: pvcResource}, "get", 1)
-			sv := NewStorageValidator(getErrorClient(timeout))
+			sv := NewStorageValidator(getErrorClient(timeout), NewOwnershipValidator(nil))
 
 			err := sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))
 			Expect(err).To(HaveOccurred())
@@ -176,7 +177,7 @@ var _ = Describe("StorageValidator", func() {
 		})
 
 		It("fails closed on an arbitrary non-status error", func() {
-			sv := NewStorageValidator(getErrorClient(errors.New("connection refused")))
+			sv := NewStorageValidator(getErrorClient(errors.New("connection refused")), NewOwnershipValidator(nil))
 			err := sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("unable to validate storage size"))
@@ -184,9 +185,156 @@ var _ = Describe("StorageValidator", func() {
 
 		It("still allows the update when the error is NotFound (no PVC yet)", func() {
 			notFound := apierrors.NewNotFound(
-				schema.GroupResource{Resource: pvcResource}, controller.GeneratePVCName(wsName))
-			sv := NewStorageValidator(getErrorClient(notFound))
+				schema.GroupResource{Resource: pvcResource}, controller.GeneratePVCName(makeWorkspace("")))
+			sv := NewStorageValidator(getErrorClient(notFound), NewOwnershipValidator(nil))
 			Expect(sv.ValidateStorageSizeNotShrinking(ctx, makeWorkspace(smaller))).To(Succeed())
 		})
 	})
+
+	Context("when restoring from a snapshot", func() {
+		const snapshotName = "nightly"
+
+		// restoringWorkspace builds a workspace restoring from the test snapshot.
+		restoringWorkspace := func(size string) *workspacev1alpha1.Workspace {
+			ws := makeWorkspace(size)
+			if ws.Spec.Storage == nil {
+				ws.Spec.Storage = &workspacev1alpha1.StorageSpec{}
+			}
+			ws.Spec.Storage.RestoreFromSnapshot = &workspacev1alpha1.SnapshotRef{Name: snapshotName}
+			return ws
+		}
+
+		// workspaceSnapshot builds the test snapshot in the given phase and restore size.
+		workspaceSnapshot := func(phase workspacev1alpha1.SnapshotPhase, restoreSize string) *workspacev1alpha1.WorkspaceSnapshot {
+			snapshot := &workspacev1alpha1.WorkspaceSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: snapshotName, Namespace: wsNs},
+				Spec:       workspacev1alpha1.WorkspaceSnapshotSpec{WorkspaceName: "source-ws"},
+				Status:     workspacev1alpha1.WorkspaceSnapshotStatus{Phase: phase},
+			}
+			if restoreSize != "" {
+				quantity := resource.MustParse(restoreSize)
+				snapshot.Status.RestoreSize = &quantity
+			}
+			return snapshot
+		}
+
+		// sourceWorkspace builds the Public workspace the test snapshot was taken of, owned by testOwnerUser.
+		sourceWorkspace := func() *workspacev1alpha1.Workspace {
+			return &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "source-ws",
+					Namespace:   wsNs,
+					Annotations: map[string]string{controller.AnnotationCreatedBy: testOwnerUser},
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					OwnershipType: webhookconst.OwnershipTypePublic,
+					AccessType:    webhookconst.OwnershipTypePublic,
+				},
+			}
+		}
+
+		It("skips workspaces that do not restore from a snapshot", func() {
+			sv := NewStorageValidator(fake.NewClientBuilder().WithScheme(scheme).Build(), NewOwnershipValidator(nil))
+			Expect(sv.ValidateRestoreFromSnapshot(ctx, makeWorkspace(larger))).To(Succeed())
+		})
+
+		It("rejects a snapshot that does not exist", func() {
+			sv := NewStorageValidator(fake.NewClientBuilder().WithScheme(scheme).Build(), NewOwnershipValidator(nil))
+			err := sv.ValidateRestoreFromSnapshot(ctx, restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		})
+
+		It("rejects a snapshot that is not ready", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseInProgress, "")).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+			err := sv.ValidateRestoreFromSnapshot(ctx, restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not ready"))
+		})
+
+		It("rejects a storage size smaller than the restore size", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize)).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+			err := sv.ValidateRestoreFromSnapshot(ctx, restoringWorkspace(smaller))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("smaller than the restore size"))
+		})
+
+		It("allows a ready snapshot that fits in the requested size", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize), sourceWorkspace()).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+			userCtx := createUserContext(ctx, "CREATE", testOwnerUser)
+			Expect(sv.ValidateRestoreFromSnapshot(userCtx, restoringWorkspace(larger))).To(Succeed())
+			Expect(sv.ValidateRestoreFromSnapshot(userCtx, restoringWorkspace(""))).To(Succeed())
+		})
+
+		It("rejects a restore by a user who may not modify an OwnerOnly source", func() {
+			source := sourceWorkspace()
+			source.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize), source).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+
+			err := sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", "outsider"), restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot restore a snapshot of workspace default/source-ws"))
+			Expect(sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", testOwnerUser), restoringWorkspace(larger))).To(Succeed())
+		})
+
+		It("rejects a restore by another user of a source with OwnerOnly access", func() {
+			source := sourceWorkspace()
+			source.Spec.AccessType = webhookconst.OwnershipTypeOwnerOnly
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize), source).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+
+			err := sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", "outsider"), restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only the owner of workspace default/source-ws can restore a snapshot of it"))
+		})
+
+		It("only lets admins restore a snapshot without owner whose source was deleted", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize)).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+
+			err := sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", testOwnerUser), restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no longer exists"))
+
+			adminCtx := createUserContext(ctx, "CREATE", "admin", webhookconst.DefaultAdminGroup)
+			Expect(sv.ValidateRestoreFromSnapshot(adminCtx, restoringWorkspace(larger))).To(Succeed())
+		})
+
+		It("lets the recorded owner restore a snapshot whose source was deleted", func() {
+			snapshot := workspaceSnapshot(workspacev1alpha1.SnapshotPhaseReady, pvcSize)
+			snapshot.Annotations = map[string]string{controller.AnnotationCreatedBy: testOwnerUser}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(snapshot).Build()
+			sv := NewStorageValidator(fakeClient, NewOwnershipValidator(nil))
+
+			Expect(sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", testOwnerUser), restoringWorkspace(larger))).To(Succeed())
+			err := sv.ValidateRestoreFromSnapshot(createUserContext(ctx, "CREATE", "outsider"), restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only its owner or an admin can restore from it"))
+		})
+
+		It("rejects an update that adds a snapshot to restore from", func() {
+			oldWorkspace := makeWorkspace(larger)
+			err := validateRestoreFromSnapshotNotAdded(oldWorkspace, restoringWorkspace(larger))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can only be set when the workspace is created"))
+
+			oldWorkspace.Spec.Storage = nil
+			Expect(validateRestoreFromSnapshotNotAdded(oldWorkspace, restoringWorkspace(larger))).NotTo(Succeed())
+		})
+
+		It("allows updates that keep or remove the snapshot to restore from", func() {
+			Expect(validateRestoreFromSnapshotNotAdded(restoringWorkspace(larger), restoringWorkspace(larger))).To(Succeed())
+			Expect(validateRestoreFromSnapshotNotAdded(restoringWorkspace(larger), makeWorkspace(larger))).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// TemplateAccessValidator checks that users may use the template of their workspaces, according
+// to the allowedUsers and allowedGroups of the template
+type TemplateAccessValidator struct {
+	resolver *workspaceutil.TemplateResolver
+	// groupResolver resolves the groups of users beyond those of the admission request, optional
+	groupResolver GroupResolverInterface
+}
+
+// NewTemplateAccessValidator creates a new TemplateAccessValidator. groupResolver may be nil, in
+// which case the groups of users are only read from the admission request.
+func NewTemplateAccessValidator(resolver *workspaceutil.TemplateResolver, groupResolver GroupResolverInterface) *TemplateAccessValidator {
+	return &TemplateAccessValidator{resolver: resolver, groupResolver: groupResolver}
+}
+
+// ValidateCreateWorkspace checks that the user may use the template of a new workspace
+func (v *TemplateAccessValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil
+	}
+	return v.validateTemplateAccess(ctx, workspace)
+}
+
+// ValidateUpdateWorkspace checks that the user may use the template of an updated workspace, when
+// the update switches it to another template. Workspaces keep the template they were admitted
+// with, so that their users can still stop, start or delete them once they lose access.
+func (v *TemplateAccessValidator) ValidateUpdateWorkspace(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	newTemplateRef := newWorkspace.Spec.TemplateRef
+	if newTemplateRef == nil || newTemplateRef.Name == "" {
+		return nil
+	}
+	oldTemplateRef := oldWorkspace.Spec.TemplateRef
+	if oldTemplateRef != nil && oldTemplateRef.Name == newTemplateRef.Name &&
+		workspaceutil.GetTemplateRefNamespace(oldWorkspace) == workspaceutil.GetTemplateRefNamespace(newWorkspace) {
+		return nil
+	}
+	return v.validateTemplateAccess(ctx, newWorkspace)
+}
+
+func (v *TemplateAccessValidator) validateTemplateAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if v == nil {
+		return nil
+	}
+	template, err := v.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		workspacelog.Error(err, "Failed to get template", "workspace", workspace.Name)
+		return errs.ForUser(err)
+	}
+	if len(template.Spec.AllowedUsers) == 0 && len(template.Spec.AllowedGroups) == 0 {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to extract user information from request context: %w", err)
+	}
+	if slices.Contains(template.Spec.AllowedUsers, req.UserInfo.Username) {
+		return nil
+	}
+	allowed, err := v.isMemberOfAny(ctx, req.UserInfo.Username, req.UserInfo.Groups, template.Spec.AllowedGroups)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("access denied: user %s may not use template '%s'", req.UserInfo.Username, template.Name)
+	}
+	return nil
+}
+
+// isMemberOfAny returns true when the user belongs to one of groups, according to the groups of
+// the request or else to the group resolver
+func (v *TemplateAccessValidator) isMemberOfAny(ctx context.Context, username string, requestGroups, groups []string) (bool, error) {
+	if len(groups) == 0 {
+		return false, nil
+	}
+	if slices.ContainsFunc(requestGroups, func(group string) bool { return slices.Contains(groups, group) }) {
+		return true, nil
+	}
+	if v.groupResolver == nil {
+		return false, nil
+	}
+
+	resolved, err := v.groupResolver.ResolveGroups(ctx, username)
+	if err != nil {
+		workspacelog.Error(err, "Failed to resolve user groups", "user", username)
+		return false, fmt.Errorf("unable to resolve the groups of user %s: %w", username, err)
+	}
+	return slices.ContainsFunc(resolved, func(group string) bool { return slices.Contains(groups, group) }), nil
+}
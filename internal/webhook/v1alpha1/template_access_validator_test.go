@@ -0,0 +1,132 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+var _ = Describe("TemplateAccessValidator", func() {
+	const restrictedTemplateName = "restricted-template"
+
+	var (
+		resolver  *workspaceutil.TemplateResolver
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	userContext := func(username string, groups ...string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		openTemplate := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+			Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Open Template", DefaultImage: testValidBaseNotebook},
+		}
+		restrictedTemplate := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: restrictedTemplateName, Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:   "Restricted Template",
+				DefaultImage:  testValidBaseNotebook,
+				AllowedUsers:  []string{"alice"},
+				AllowedGroups: []string{"ml-engineers"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		resolver = workspaceutil.NewTemplateResolver(
+			fake.NewClientBuilder().WithScheme(scheme).WithObjects(openTemplate, restrictedTemplate).Build(), "")
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "access-workspace", Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:       testValidBaseNotebook,
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: restrictedTemplateName},
+			},
+		}
+	})
+
+	It("should let any user use a template without allowed users or groups", func() {
+		workspace.Spec.TemplateRef.Name = testTemplateName
+		validator := NewTemplateAccessValidator(resolver, nil)
+		Expect(validator.ValidateCreateWorkspace(userContext("bob"), workspace)).To(Succeed())
+	})
+
+	It("should admit a workspace without template", func() {
+		workspace.Spec.TemplateRef = nil
+		validator := NewTemplateAccessValidator(resolver, nil)
+		Expect(validator.ValidateCreateWorkspace(userContext("bob"), workspace)).To(Succeed())
+	})
+
+	It("should let an allowed user use the template", func() {
+		validator := NewTemplateAccessValidator(resolver, nil)
+		Expect(validator.ValidateCreateWorkspace(userContext("alice"), workspace)).To(Succeed())
+	})
+
+	It("should let a member of an allowed group use the template", func() {
+		validator := NewTemplateAccessValidator(resolver, nil)
+		Expect(validator.ValidateCreateWorkspace(userContext("bob", "ml-engineers"), workspace)).To(Succeed())
+	})
+
+	It("should reject other users", func() {
+		validator := NewTemplateAccessValidator(resolver, nil)
+		err := validator.ValidateCreateWorkspace(userContext("bob", "data-science"), workspace)
+		Expect(err).To(MatchError("access denied: user bob may not use template '" + restrictedTemplateName + "'"))
+	})
+
+	It("should resolve the groups of users whose request groups are not allowed", func() {
+		groupResolver := &fakeGroupResolver{groups: map[string][]string{"bob": {"ml-engineers"}}}
+		validator := NewTemplateAccessValidator(resolver, groupResolver)
+		Expect(validator.ValidateCreateWorkspace(userContext("bob"), workspace)).To(Succeed())
+		Expect(groupResolver.calls).To(Equal(1))
+	})
+
+	It("should not resolve the groups of allowed users", func() {
+		groupResolver := &fakeGroupResolver{}
+		validator := NewTemplateAccessValidator(resolver, groupResolver)
+		Expect(validator.ValidateCreateWorkspace(userContext("alice"), workspace)).To(Succeed())
+		Expect(groupResolver.calls).To(BeZero())
+	})
+
+	It("should reject the workspace when the groups cannot be resolved", func() {
+		groupResolver := &fakeGroupResolver{err: errors.New("directory unavailable")}
+		validator := NewTemplateAccessValidator(resolver, groupResolver)
+		err := validator.ValidateCreateWorkspace(userContext("bob"), workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to resolve the groups of user bob"))
+	})
+
+	It("should let users update a workspace that keeps its template", func() {
+		oldWorkspace := workspace.DeepCopy()
+		workspace.Spec.DesiredStatus = "Stopped"
+		validator := NewTemplateAccessValidator(resolver, nil)
+		Expect(validator.ValidateUpdateWorkspace(userContext("bob"), oldWorkspace, workspace)).To(Succeed())
+	})
+
+	It("should reject switching a workspace to a template the user may not use", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.TemplateRef.Name = testTemplateName
+		validator := NewTemplateAccessValidator(resolver, nil)
+		err := validator.ValidateUpdateWorkspace(userContext("bob"), oldWorkspace, workspace)
+		Expect(err).To(MatchError(ContainSubstring("access denied")))
+	})
+})
@@ -40,9 +40,14 @@ var defaultApplicators = []DefaultApplicator{
 	applyAccessStrategyDefaults,
 	applyLifecycleDefaults,
 	applyReadinessProbeDefaults,
+	applyProbesDefaults,
 	applySecurityDefaults,
+	applyGPUSharingDefaults,
+	applyJupyterServerConfigDefaults,
 	applyEnvDefaults,
+	applyImagePullSecretsDefaults,
 	applyInitContainerDefaults,
+	applyPortDefaults,
 }
 
 // ApplyTemplateDefaults applies template defaults to workspace
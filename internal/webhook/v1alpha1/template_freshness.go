@@ -0,0 +1,184 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DefaultTemplateExpectationTTL is how long a template generation admitted by the template webhook
+// is expected before it is dropped, e.g. because a later admission webhook rejected the write
+const DefaultTemplateExpectationTTL = 2 * time.Minute
+
+// staleTemplateCacheAdmissions counts the workspace admissions that found a stale template in the
+// informer cache and read it from the API server instead
+var staleTemplateCacheAdmissions = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "workspace_template_stale_cache_admissions_total",
+	Help: "Number of workspace admissions that found a stale WorkspaceTemplate in the cache and read it from the API server",
+})
+
+func init() {
+	metrics.Registry.MustRegister(staleTemplateCacheAdmissions)
+}
+
+// templateExpectation is a template generation admitted by the template webhook but not yet
+// observed by the informer
+type templateExpectation struct {
+	generation int64
+	expires    time.Time
+}
+
+// TemplateFreshness keeps workspace admission from enforcing a template older than the last template
+// update admitted by this webhook server. The template webhook records the generation of each update it
+// admits; the template informer clears it once the cache holds that generation. Until then, workspace
+// admission reads the template from the API server, so tightened constraints apply within one watch
+// event of the update rather than whenever the cache catches up.
+//
+// Expectations are local to the webhook server that admitted the template update.
+type TemplateFreshness struct {
+	mu           sync.Mutex
+	expectations map[types.NamespacedName]templateExpectation
+	apiReader    client.Reader
+	ttl          time.Duration
+	now          func() time.Time
+}
+
+// NewTemplateFreshness creates a TemplateFreshness reading stale templates with apiReader
+func NewTemplateFreshness(apiReader client.Reader) *TemplateFreshness {
+	return &TemplateFreshness{
+		expectations: make(map[types.NamespacedName]templateExpectation),
+		apiReader:    apiReader,
+		ttl:          DefaultTemplateExpectationTTL,
+		now:          time.Now,
+	}
+}
+
+// SetupTemplateFreshnessWithManager creates a TemplateFreshness and registers it with the manager's
+// WorkspaceTemplate informer, shared with the WorkspaceTemplate controller
+func SetupTemplateFreshnessWithManager(mgr ctrl.Manager) (*TemplateFreshness, error) {
+	tf := NewTemplateFreshness(mgr.GetAPIReader())
+
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &workspacev1alpha1.WorkspaceTemplate{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace template informer: %w", err)
+	}
+
+	observe := func(obj interface{}) {
+		if template, ok := obj.(*workspacev1alpha1.WorkspaceTemplate); ok {
+			tf.Observe(template)
+		}
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    observe,
+		UpdateFunc: func(_, newObj interface{}) { observe(newObj) },
+		DeleteFunc: func(obj interface{}) {
+			if template, ok := obj.(*workspacev1alpha1.WorkspaceTemplate); ok {
+				tf.Forget(client.ObjectKeyFromObject(template))
+			}
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add event handler to workspace template informer: %w", err)
+	}
+
+	return tf, nil
+}
+
+// ExpectGeneration records that a template update with this generation was admitted
+func (tf *TemplateFreshness) ExpectGeneration(key types.NamespacedName, generation int64) {
+	if tf == nil {
+		return
+	}
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	if current, ok := tf.expectations[key]; ok && current.generation > generation {
+		return
+	}
+	tf.expectations[key] = templateExpectation{generation: generation, expires: tf.now().Add(tf.ttl)}
+}
+
+// Observe clears the expectation of a template once the informer delivers its expected generation
+func (tf *TemplateFreshness) Observe(template *workspacev1alpha1.WorkspaceTemplate) {
+	if tf == nil {
+		return
+	}
+	key := client.ObjectKeyFromObject(template)
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	if expectation, ok := tf.expectations[key]; ok && template.Generation >= expectation.generation {
+		delete(tf.expectations, key)
+	}
+}
+
+// Forget drops the expectation of a deleted template
+func (tf *TemplateFreshness) Forget(key types.NamespacedName) {
+	if tf == nil {
+		return
+	}
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	delete(tf.expectations, key)
+}
+
+// IsStale returns true when a cached template is older than the last admitted update
+func (tf *TemplateFreshness) IsStale(template *workspacev1alpha1.WorkspaceTemplate) bool {
+	if tf == nil {
+		return false
+	}
+	key := client.ObjectKeyFromObject(template)
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	expectation, ok := tf.expectations[key]
+	if !ok {
+		return false
+	}
+	if tf.now().After(expectation.expires) {
+		delete(tf.expectations, key)
+		return false
+	}
+	return template.Generation < expectation.generation
+}
+
+// Client wraps a cached client so that its template reads fall back to the API server when stale
+func (tf *TemplateFreshness) Client(k8sClient client.Client) client.Client {
+	if tf == nil {
+		return k8sClient
+	}
+	return &freshTemplateClient{Client: k8sClient, freshness: tf}
+}
+
+// freshTemplateClient reads WorkspaceTemplates from the cache, unless stale
+type freshTemplateClient struct {
+	client.Client
+	freshness *TemplateFreshness
+}
+
+// Get implements client.Reader
+func (c *freshTemplateClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	template, ok := obj.(*workspacev1alpha1.WorkspaceTemplate)
+	if !ok || !c.freshness.IsStale(template) {
+		return nil
+	}
+
+	staleTemplateCacheAdmissions.Inc()
+	workspacelog.Info("Cached template is stale, reading it from the API server",
+		"template", key.Name, "templateNamespace", key.Namespace, "cachedGeneration", template.Generation)
+	return c.freshness.apiReader.Get(ctx, key, obj, opts...)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("TemplateFreshness", func() {
+	var (
+		ctx         context.Context
+		scheme      *runtime.Scheme
+		freshness   *TemplateFreshness
+		now         time.Time
+		templateKey types.NamespacedName
+	)
+
+	newTemplate := func(generation int64, defaultImage string) *workspacev1alpha1.WorkspaceTemplate {
+		return &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "freshness-template",
+				Namespace:  testDefaultNamespace,
+				Generation: generation,
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  "Freshness Template",
+				DefaultImage: defaultImage,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		now = time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+		templateKey = types.NamespacedName{Name: "freshness-template", Namespace: testDefaultNamespace}
+	})
+
+	Context("expectations", func() {
+		BeforeEach(func() {
+			freshness = NewTemplateFreshness(nil)
+			freshness.now = func() time.Time { return now }
+		})
+
+		It("should not consider templates stale without an expectation", func() {
+			Expect(freshness.IsStale(newTemplate(1, testValidBaseNotebook))).To(BeFalse())
+		})
+
+		It("should consider templates older than the admitted generation stale", func() {
+			freshness.ExpectGeneration(templateKey, 2)
+
+			Expect(freshness.IsStale(newTemplate(1, testValidBaseNotebook))).To(BeTrue())
+			Expect(freshness.IsStale(newTemplate(2, testValidBaseNotebook))).To(BeFalse())
+		})
+
+		It("should keep the highest admitted generation", func() {
+			freshness.ExpectGeneration(templateKey, 3)
+			freshness.ExpectGeneration(templateKey, 2)
+
+			Expect(freshness.IsStale(newTemplate(2, testValidBaseNotebook))).To(BeTrue())
+		})
+
+		It("should clear the expectation once the informer observes the generation", func() {
+			freshness.ExpectGeneration(templateKey, 2)
+
+			freshness.Observe(newTemplate(1, testValidBaseNotebook))
+			Expect(freshness.expectations).To(HaveKey(templateKey))
+
+			freshness.Observe(newTemplate(2, testValidBaseNotebook))
+			Expect(freshness.expectations).NotTo(HaveKey(templateKey))
+		})
+
+		It("should clear the expectation of deleted templates", func() {
+			freshness.ExpectGeneration(templateKey, 2)
+
+			freshness.Forget(templateKey)
+			Expect(freshness.IsStale(newTemplate(1, testValidBaseNotebook))).To(BeFalse())
+		})
+
+		It("should drop expectations that were never observed", func() {
+			freshness.ExpectGeneration(templateKey, 2)
+
+			now = now.Add(DefaultTemplateExpectationTTL + time.Second)
+			Expect(freshness.IsStale(newTemplate(1, testValidBaseNotebook))).To(BeFalse())
+			Expect(freshness.expectations).NotTo(HaveKey(templateKey))
+		})
+
+		It("should be a no-op when nil", func() {
+			var nilFreshness *TemplateFreshness
+			nilFreshness.ExpectGeneration(templateKey, 2)
+			nilFreshness.Observe(newTemplate(2, testValidBaseNotebook))
+			nilFreshness.Forget(templateKey)
+
+			Expect(nilFreshness.IsStale(newTemplate(1, testValidBaseNotebook))).To(BeFalse())
+			cachedClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			Expect(nilFreshness.Client(cachedClient)).To(BeIdenticalTo(cachedClient))
+		})
+	})
+
+	Context("Client", func() {
+		var templateClient client.Client
+
+		BeforeEach(func() {
+			cachedClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newTemplate(1, "jupyter/old-notebook:latest")).
+				Build()
+			apiReader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newTemplate(2, testValidBaseNotebook)).
+				Build()
+			freshness = NewTemplateFreshness(apiReader)
+			templateClient = freshness.Client(cachedClient)
+		})
+
+		It("should read templates from the cache when fresh", func() {
+			template := &workspacev1alpha1.WorkspaceTemplate{}
+			Expect(templateClient.Get(ctx, templateKey, template)).To(Succeed())
+
+			Expect(template.Spec.DefaultImage).To(Equal("jupyter/old-notebook:latest"))
+		})
+
+		It("should read stale templates from the API server and count the admission", func() {
+			before := testutil.ToFloat64(staleTemplateCacheAdmissions)
+			freshness.ExpectGeneration(templateKey, 2)
+
+			template := &workspacev1alpha1.WorkspaceTemplate{}
+			Expect(templateClient.Get(ctx, templateKey, template)).To(Succeed())
+
+			Expect(template.Spec.DefaultImage).To(Equal(testValidBaseNotebook))
+			Expect(testutil.ToFloat64(staleTemplateCacheAdmissions)).To(Equal(before + 1))
+		})
+
+		It("should apply the admitted template defaults to workspaces", func() {
+			freshness.ExpectGeneration(templateKey, 2)
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName, Namespace: testDefaultNamespace},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: templateKey.Name},
+				},
+			}
+
+			Expect(NewTemplateDefaulter(templateClient, "").ApplyTemplateDefaults(ctx, workspace)).To(Succeed())
+			Expect(workspace.Spec.Image).To(Equal(testValidBaseNotebook))
+		})
+	})
+
+	Context("WorkspaceTemplate webhook", func() {
+		BeforeEach(func() {
+			freshness = NewTemplateFreshness(nil)
+		})
+
+		It("should expect the next generation when the spec changes", func() {
+			validator := &WorkspaceTemplateCustomValidator{
+				accessStrategyValidator: NewAccessStrategyValidator("", nil, nil),
+				templateFreshness:       freshness,
+			}
+			oldTemplate := newTemplate(1, testValidBaseNotebook)
+			newTemplate := newTemplate(1, "jupyter/new-notebook:latest")
+
+			_, err := validator.ValidateUpdate(ctx, oldTemplate, newTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(freshness.IsStale(oldTemplate)).To(BeTrue())
+		})
+
+		It("should not expect a new generation when only metadata changes", func() {
+			validator := &WorkspaceTemplateCustomValidator{
+				accessStrategyValidator: NewAccessStrategyValidator("", nil, nil),
+				templateFreshness:       freshness,
+			}
+			oldTemplate := newTemplate(1, testValidBaseNotebook)
+			newTemplate := oldTemplate.DeepCopy()
+			newTemplate.Labels = map[string]string{"team": "data"}
+
+			_, err := validator.ValidateUpdate(ctx, oldTemplate, newTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(freshness.expectations).To(BeEmpty())
+		})
+	})
+})
@@ -9,6 +9,10 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
@@ -31,7 +35,8 @@ func NewTemplateGetter(k8sClient client.Client, defaultTemplateNamespace string)
 
 // ApplyTemplateName finds the default template and sets it on the workspace.
 // It searches the workspace's namespace first, then the shared namespace (defaultTemplateNamespace).
-// A local default template always takes priority over the shared one.
+// A local default template always takes priority over the shared one. Default templates with a
+// defaultNamespaceSelector only apply to the workspaces of the namespaces it matches.
 func (tg *TemplateGetter) ApplyTemplateName(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
 	// Skip if workspace already has a template reference
 	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
@@ -39,16 +44,17 @@ func (tg *TemplateGetter) ApplyTemplateName(ctx context.Context, workspace *work
 	}
 
 	defaultLabel := client.MatchingLabels{webhookconst.DefaultTemplateLabel: labelValueTrue}
+	namespaceLabels := &lazyNamespaceLabels{reader: tg.client, namespace: workspace.Namespace}
 
 	// Search the workspace's own namespace first
-	template, err := tg.findDefaultTemplate(ctx, workspace.Namespace, defaultLabel)
+	template, err := tg.findDefaultTemplate(ctx, workspace.Namespace, defaultLabel, namespaceLabels)
 	if err != nil {
 		return err
 	}
 
 	// Fall back to the shared namespace if no local default was found
 	if template == nil && tg.defaultTemplateNamespace != "" && tg.defaultTemplateNamespace != workspace.Namespace {
-		template, err = tg.findDefaultTemplate(ctx, tg.defaultTemplateNamespace, defaultLabel)
+		template, err = tg.findDefaultTemplate(ctx, tg.defaultTemplateNamespace, defaultLabel, namespaceLabels)
 		if err != nil {
 			return err
 		}
@@ -66,26 +72,90 @@ func (tg *TemplateGetter) ApplyTemplateName(ctx context.Context, workspace *work
 	return nil
 }
 
-// findDefaultTemplate searches for a single default-labeled template in the given namespace.
-// Returns nil if no default template is found. Returns an error if multiple are found.
-func (tg *TemplateGetter) findDefaultTemplate(ctx context.Context, namespace string, labels client.MatchingLabels) (*workspacev1alpha1.WorkspaceTemplate, error) {
+// findDefaultTemplate searches for a single default-labeled template in the given namespace that
+// applies to the workspace namespace. Templates whose defaultNamespaceSelector matches take priority
+// over those without selector. Returns nil if no default template is found. Returns an error if
+// multiple are found with the same priority.
+func (tg *TemplateGetter) findDefaultTemplate(
+	ctx context.Context,
+	namespace string,
+	matchingLabels client.MatchingLabels,
+	namespaceLabels *lazyNamespaceLabels,
+) (*workspacev1alpha1.WorkspaceTemplate, error) {
 	templateList := &workspacev1alpha1.WorkspaceTemplateList{}
-	if err := tg.client.List(ctx, templateList, labels, client.InNamespace(namespace)); err != nil {
+	if err := tg.client.List(ctx, templateList, matchingLabels, client.InNamespace(namespace)); err != nil {
 		return nil, fmt.Errorf("failed to list default templates in namespace %s: %w", namespace, err)
 	}
 
-	if len(templateList.Items) == 0 {
+	var selected, unselected []workspacev1alpha1.WorkspaceTemplate
+	for _, template := range templateList.Items {
+		if template.Spec.DefaultNamespaceSelector == nil {
+			unselected = append(unselected, template)
+			continue
+		}
+		matches, err := matchesNamespaceSelector(ctx, template.Spec.DefaultNamespaceSelector, namespaceLabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match the defaultNamespaceSelector of template %s/%s: %w",
+				template.Namespace, template.Name, err)
+		}
+		if matches {
+			selected = append(selected, template)
+		}
+	}
+
+	candidates := selected
+	if len(candidates) == 0 {
+		candidates = unselected
+	}
+	if len(candidates) == 0 {
 		return nil, nil
 	}
 
-	if len(templateList.Items) > 1 {
+	if len(candidates) > 1 {
 		return nil, fmt.Errorf(
 			"multiple templates found with default-template label in namespace %s: %v, expected exactly one",
-			namespace, getTemplateNames(templateList.Items),
+			namespace, getTemplateNames(candidates),
 		)
 	}
 
-	return &templateList.Items[0], nil
+	return &candidates[0], nil
+}
+
+// matchesNamespaceSelector returns true when the labels of the workspace namespace match selector
+func matchesNamespaceSelector(ctx context.Context, selector *metav1.LabelSelector, namespaceLabels *lazyNamespaceLabels) (bool, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	set, err := namespaceLabels.get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return labelSelector.Matches(set), nil
+}
+
+// lazyNamespaceLabels reads the labels of a namespace on first use, so that namespaces are only
+// read when a default template has a defaultNamespaceSelector
+type lazyNamespaceLabels struct {
+	reader    client.Reader
+	namespace string
+	labels    labels.Set
+}
+
+// get returns the labels of the namespace. A missing namespace has no labels.
+func (l *lazyNamespaceLabels) get(ctx context.Context) (labels.Set, error) {
+	if l.labels != nil {
+		return l.labels, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := l.reader.Get(ctx, client.ObjectKey{Name: l.namespace}, ns); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get namespace %q: %w", l.namespace, err)
+	}
+	l.labels = labels.Set(ns.Labels)
+	if l.labels == nil {
+		l.labels = labels.Set{}
+	}
+	return l.labels, nil
 }
 
 // getTemplateNames extracts template names from a list of templates
@@ -293,5 +293,86 @@ var _ = Describe("TemplateGetter", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(workspace.Spec.TemplateRef).To(BeNil())
 		})
+
+		It("should prefer the shared default whose namespace selector matches the workspace namespace", func() {
+			selectedNs := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "getter-selected-ns", Labels: map[string]string{"team": "ml"}},
+			}
+			Expect(k8sClient.Create(ctx, selectedNs)).To(Succeed())
+
+			catchAllTemplate := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "shared-catch-all",
+					Namespace: sharedNamespace,
+					Labels: map[string]string{
+						webhookconst.DefaultTemplateLabel: labelValueTrue,
+					},
+				},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DisplayName:  "Shared Catch All",
+					DefaultImage: testValidBaseNotebook,
+				},
+			}
+			mlTemplate := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "shared-ml",
+					Namespace: sharedNamespace,
+					Labels: map[string]string{
+						webhookconst.DefaultTemplateLabel: labelValueTrue,
+					},
+				},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DisplayName:  "Shared ML",
+					DefaultImage: testValidBaseNotebook,
+					DefaultNamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "ml"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, catchAllTemplate)).To(Succeed())
+			Expect(k8sClient.Create(ctx, mlTemplate)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, catchAllTemplate)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, mlTemplate)).To(Succeed())
+			}()
+
+			getter := NewTemplateGetter(k8sClient, sharedNamespace)
+
+			mlWorkspace := workspace.DeepCopy()
+			mlWorkspace.Namespace = selectedNs.Name
+			Expect(getter.ApplyTemplateName(ctx, mlWorkspace)).To(Succeed())
+			Expect(mlWorkspace.Spec.TemplateRef).NotTo(BeNil())
+			Expect(mlWorkspace.Spec.TemplateRef.Name).To(Equal("shared-ml"))
+
+			Expect(getter.ApplyTemplateName(ctx, workspace)).To(Succeed())
+			Expect(workspace.Spec.TemplateRef).NotTo(BeNil())
+			Expect(workspace.Spec.TemplateRef.Name).To(Equal("shared-catch-all"))
+		})
+
+		It("should not inject a default template whose namespace selector does not match", func() {
+			template := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "shared-unmatched",
+					Namespace: sharedNamespace,
+					Labels: map[string]string{
+						webhookconst.DefaultTemplateLabel: labelValueTrue,
+					},
+				},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DisplayName:  "Shared Unmatched",
+					DefaultImage: testValidBaseNotebook,
+					DefaultNamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "finance"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, template)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, template)).To(Succeed()) }()
+
+			getter := NewTemplateGetter(k8sClient, sharedNamespace)
+			err := getter.ApplyTemplateName(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(workspace.Spec.TemplateRef).To(BeNil())
+		})
 	})
 })
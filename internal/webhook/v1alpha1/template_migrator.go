@@ -0,0 +1,127 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// TemplateMigrator adjusts a workspace to the constraints of a template in Migrate apply mode as the
+// workspace starts. It resets the violating image, resources, priority class and runtime class, which
+// the template defaults then refill. Other violations are left for the validating webhook to reject.
+type TemplateMigrator struct {
+	resolver *workspaceutil.TemplateResolver
+}
+
+// NewTemplateMigrator creates a new TemplateMigrator
+func NewTemplateMigrator(k8sClient client.Client, defaultTemplateNamespace string) *TemplateMigrator {
+	return &TemplateMigrator{
+		resolver: workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+	}
+}
+
+// ApplyTemplateMigration migrates a workspace being started to the constraints of its template
+// when the template is in Migrate apply mode
+func (tm *TemplateMigrator) ApplyTemplateMigration(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil
+	}
+
+	starting, err := isWorkspaceStarting(ctx, workspace)
+	if err != nil || !starting {
+		return err
+	}
+
+	template, err := tm.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		return err
+	}
+	if template.Spec.ApplyMode != workspacev1alpha1.TemplateApplyModeMigrate {
+		return nil
+	}
+
+	if migrated := migrateTemplateViolations(workspace, template); len(migrated) > 0 {
+		workspacelog.Info("Reset fields violating template in Migrate mode on workspace start",
+			"workspace", workspace.Name, "template", template.Name, "fields", migrated)
+	}
+	return nil
+}
+
+// isWorkspaceStarting returns whether the admission request updates a stopped or hibernated
+// workspace to running
+func isWorkspaceStarting(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if workspace.Spec.DesiredStatus != controller.DesiredStateRunning {
+		return false, nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		return false, nil
+	}
+
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return false, fmt.Errorf("failed to decode the workspace being updated: %w", err)
+	}
+	return controller.IsStoppedDesiredStatus(oldWorkspace.Spec.DesiredStatus), nil
+}
+
+// migrateTemplateViolations resets the fields of the workspace that violate the template and that the
+// template defaults can refill, and returns their paths
+func migrateTemplateViolations(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []string {
+	var migrated []string
+
+	if workspace.Spec.Image != "" && validateImageAllowed(workspace.Spec.Image, template) != nil {
+		workspace.Spec.Image = ""
+		migrated = append(migrated, "spec.image")
+	}
+
+	if validateResourceProfile(workspace, template) != nil {
+		workspace.Spec.Profile = ""
+		workspace.Spec.Resources = nil
+		migrated = append(migrated, "spec.profile")
+	} else if workspace.Spec.Resources != nil && len(validateResourceBounds(*workspace.Spec.Resources, template)) > 0 {
+		workspace.Spec.Profile = ""
+		workspace.Spec.Resources = nil
+		migrated = append(migrated, "spec.resources")
+	}
+
+	if validatePriorityClassName(workspace.Spec.PriorityClassName, template) != nil {
+		workspace.Spec.PriorityClassName = ""
+		migrated = append(migrated, "spec.priorityClassName")
+	}
+
+	if workspace.Spec.RuntimeClassName != "" && validateRuntimeClassName(workspace.Spec.RuntimeClassName, template) != nil {
+		workspace.Spec.RuntimeClassName = ""
+		migrated = append(migrated, "spec.runtimeClassName")
+	}
+
+	if len(validateGPUSharing(workspace, template)) > 0 {
+		workspace.Spec.GPUSharing = nil
+		migrated = append(migrated, "spec.gpuSharing")
+	}
+
+	if validateProvisioningHints(workspace, template) != nil {
+		workspace.Spec.ProvisioningHints = nil
+		migrated = append(migrated, "spec.provisioningHints")
+	}
+
+	if validateJupyterServerConfig(workspace, template) != nil {
+		workspace.Spec.JupyterServerConfig = nil
+		migrated = append(migrated, "spec.jupyterServerConfig")
+	}
+
+	return migrated
+}
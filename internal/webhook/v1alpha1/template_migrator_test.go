@@ -0,0 +1,139 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("TemplateMigrator", func() {
+	const oldImage = "ghcr.io/other/notebook:1.0"
+
+	newTemplate := func(mode workspacev1alpha1.TemplateApplyMode) *workspacev1alpha1.WorkspaceTemplate {
+		return &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testSomeTemplate, Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:              "Some Template",
+				DefaultImage:             testValidBaseNotebook,
+				DefaultPriorityClassName: "standard",
+				AllowedPriorityClassNames: []string{
+					"standard",
+				},
+				ApplyMode: mode,
+			},
+		}
+	}
+
+	newWorkspace := func(desiredStatus string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:             oldImage,
+				PriorityClassName: "high",
+				DesiredStatus:     desiredStatus,
+				TemplateRef:       &workspacev1alpha1.TemplateRef{Name: testSomeTemplate},
+			},
+		}
+	}
+
+	buildMigrator := func(template *workspacev1alpha1.WorkspaceTemplate) *TemplateMigrator {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build()
+		return NewTemplateMigrator(fakeClient, "")
+	}
+
+	updateContext := func(oldWorkspace *workspacev1alpha1.Workspace) context.Context {
+		raw, err := json.Marshal(oldWorkspace)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		})
+	}
+
+	It("should reset violating fields when a workspace starts under Migrate", func() {
+		migrator := buildMigrator(newTemplate(workspacev1alpha1.TemplateApplyModeMigrate))
+		workspace := newWorkspace(controller.DesiredStateRunning)
+
+		ctx := updateContext(newWorkspace(controller.DesiredStateStopped))
+		Expect(migrator.ApplyTemplateMigration(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(BeEmpty())
+		Expect(workspace.Spec.PriorityClassName).To(BeEmpty())
+	})
+
+	It("should leave a running workspace unchanged", func() {
+		migrator := buildMigrator(newTemplate(workspacev1alpha1.TemplateApplyModeMigrate))
+		workspace := newWorkspace(controller.DesiredStateRunning)
+
+		ctx := updateContext(newWorkspace(controller.DesiredStateRunning))
+		Expect(migrator.ApplyTemplateMigration(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal(oldImage))
+		Expect(workspace.Spec.PriorityClassName).To(Equal("high"))
+	})
+
+	It("should leave a starting workspace unchanged under Enforce and Audit", func() {
+		for _, mode := range []workspacev1alpha1.TemplateApplyMode{"", workspacev1alpha1.TemplateApplyModeAudit} {
+			migrator := buildMigrator(newTemplate(mode))
+			workspace := newWorkspace(controller.DesiredStateRunning)
+
+			ctx := updateContext(newWorkspace(controller.DesiredStateHibernated))
+			Expect(migrator.ApplyTemplateMigration(ctx, workspace)).To(Succeed())
+
+			Expect(workspace.Spec.Image).To(Equal(oldImage))
+		}
+	})
+
+	It("should leave a workspace being created unchanged", func() {
+		migrator := buildMigrator(newTemplate(workspacev1alpha1.TemplateApplyModeMigrate))
+		workspace := newWorkspace(controller.DesiredStateRunning)
+
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+		})
+		Expect(migrator.ApplyTemplateMigration(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal(oldImage))
+	})
+
+	It("should reset resources outside the template bounds", func() {
+		template := newTemplate(workspacev1alpha1.TemplateApplyModeMigrate)
+		template.Spec.ResourceBounds = &workspacev1alpha1.ResourceBounds{
+			Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+				corev1.ResourceCPU: {Min: resource.MustParse("100m"), Max: resource.MustParse("2")},
+			},
+		}
+		workspace := newWorkspace(controller.DesiredStateRunning)
+		workspace.Spec.Image = testValidBaseNotebook
+		workspace.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}
+
+		migrated := migrateTemplateViolations(workspace, template)
+
+		Expect(migrated).To(ConsistOf("spec.resources", "spec.priorityClassName"))
+		Expect(workspace.Spec.Resources).To(BeNil())
+		Expect(workspace.Spec.Image).To(Equal(testValidBaseNotebook))
+	})
+})
@@ -0,0 +1,53 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+// TemplateRequirementValidator rejects the workspaces created without a template, and the updates
+// removing the template of a workspace, while the operator settings require one. Workspaces that reference no template are given the default
+// template of their namespace, if any, before validation.
+type TemplateRequirementValidator struct {
+	config *operatorconfig.Store
+}
+
+// NewTemplateRequirementValidator creates a new TemplateRequirementValidator. config may be nil,
+// in which case workspaces never require a template.
+func NewTemplateRequirementValidator(config *operatorconfig.Store) *TemplateRequirementValidator {
+	return &TemplateRequirementValidator{config: config}
+}
+
+// ValidateCreateWorkspace checks that a new workspace references a template, when required
+func (v *TemplateRequirementValidator) ValidateCreateWorkspace(workspace *workspacev1alpha1.Workspace) error {
+	if !v.requireTemplate() || hasTemplateRef(workspace) {
+		return nil
+	}
+	return errors.New("workspaces must reference a template: set spec.templateRef")
+}
+
+// ValidateUpdateWorkspace checks that an update keeps the template of a workspace, when required.
+// Workspaces created without a template before one was required may still be updated.
+func (v *TemplateRequirementValidator) ValidateUpdateWorkspace(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if !v.requireTemplate() || !hasTemplateRef(oldWorkspace) || hasTemplateRef(newWorkspace) {
+		return nil
+	}
+	return errors.New("workspaces must reference a template: spec.templateRef cannot be removed")
+}
+
+// requireTemplate tells whether the operator settings require workspaces to reference a template
+func (v *TemplateRequirementValidator) requireTemplate() bool {
+	return v != nil && v.config != nil && v.config.Settings().RequireTemplate
+}
+
+// hasTemplateRef tells whether a workspace references a template
+func hasTemplateRef(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != ""
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
+)
+
+var _ = Describe("Template Requirement Validator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		config    *operatorconfig.Store
+		validator *TemplateRequirementValidator
+	)
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspaceName},
+			Spec:       workspacev1alpha1.WorkspaceSpec{DisplayName: testDisplayName},
+		}
+		config = operatorconfig.NewStore(operatorconfig.Settings{})
+		validator = NewTemplateRequirementValidator(config)
+	})
+
+	It("should allow workspaces without a template when none is required", func() {
+		Expect(validator.ValidateCreateWorkspace(workspace)).To(Succeed())
+	})
+
+	It("should reject workspaces without a template once the operator config requires one", func() {
+		config.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+
+		err := validator.ValidateCreateWorkspace(workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.templateRef"))
+	})
+
+	It("should allow workspaces referencing a template when one is required", func() {
+		config.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "basic"}
+
+		Expect(validator.ValidateCreateWorkspace(workspace)).To(Succeed())
+	})
+
+	It("should reject removing the template once the operator config requires one", func() {
+		config.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "basic"}
+
+		err := validator.ValidateUpdateWorkspace(oldWorkspace, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.templateRef"))
+
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "other"}
+		Expect(validator.ValidateUpdateWorkspace(oldWorkspace, workspace)).To(Succeed())
+	})
+
+	It("should allow updating workspaces created without a template before one was required", func() {
+		config.Apply(&workspacev1alpha1.OperatorConfigSpec{RequireTemplate: ptr.To(true)})
+
+		Expect(validator.ValidateUpdateWorkspace(workspace.DeepCopy(), workspace)).To(Succeed())
+	})
+
+	It("should allow removing the template when none is required", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "basic"}
+
+		Expect(validator.ValidateUpdateWorkspace(oldWorkspace, workspace)).To(Succeed())
+	})
+
+	It("should allow any workspace without settings", func() {
+		var nilValidator *TemplateRequirementValidator
+		Expect(nilValidator.ValidateCreateWorkspace(workspace)).To(Succeed())
+		Expect(NewTemplateRequirementValidator(nil).ValidateCreateWorkspace(workspace)).To(Succeed())
+	})
+})
@@ -8,11 +8,14 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
@@ -20,13 +23,17 @@ import (
 type TemplateValidator struct {
 	resolver                 *workspaceutil.TemplateResolver
 	defaultTemplateNamespace string
+	now                      func() time.Time
 }
 
+var _ controller.TemplateComplianceChecker = &TemplateValidator{}
+
 // NewTemplateValidator creates a new TemplateValidator
 func NewTemplateValidator(k8sClient client.Client, defaultTemplateNamespace string) *TemplateValidator {
 	return &TemplateValidator{
 		resolver:                 workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
 		defaultTemplateNamespace: defaultTemplateNamespace,
+		now:                      time.Now,
 	}
 }
 
@@ -76,8 +83,17 @@ func (tv *TemplateValidator) ValidateNamespaceScope(workspace *workspacev1alpha1
 
 // ValidateCreateWorkspace validates workspace against template constraints
 func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	return tv.validateTemplateConstraints(ctx, workspace, nil)
+}
+
+// validateTemplateConstraints validates workspace against the constraints of its template. When
+// the workspace is updated without changing its template, given the workspace before the update,
+// templates in Audit mode tolerate the violations it already had, e.g. after the template was
+// tightened, but not the ones the update adds.
+func (tv *TemplateValidator) validateTemplateConstraints(
+	ctx context.Context, workspace, oldWorkspace *workspacev1alpha1.Workspace) error {
 	if workspace.Spec.TemplateRef == nil {
-		return nil
+		return validateStandaloneWorkspace(workspace)
 	}
 
 	// Reject templateRef.namespace if it targets a namespace other than the workspace's own ns
@@ -87,9 +103,56 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 
 	template, err := tv.fetchTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
 	if err != nil {
-		return err
+		workspacelog.Error(err, "Failed to get template", "workspace", workspace.Name)
+		return errs.ForUser(err)
+	}
+
+	violations := templateViolations(workspace, template)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	// Templates in Audit mode only flag their admitted violating workspaces, through the TemplateViolation condition
+	if oldWorkspace != nil && template.Spec.ApplyMode == workspacev1alpha1.TemplateApplyModeAudit {
+		tolerated := templateViolations(oldWorkspace, template)
+		added := slices.DeleteFunc(slices.Clone(violations), func(violation TemplateViolation) bool {
+			return slices.Contains(tolerated, violation)
+		})
+		if len(added) == 0 {
+			workspacelog.Info("Admitting update of workspace that violates template in Audit mode",
+				"workspace", workspace.Name, "template", template.Name, "violations", formatViolations(violations))
+			return nil
+		}
+		violations = added
+	}
+
+	return errs.TemplateViolation(nil, "workspace violates template '%s' constraints: %s", workspace.Spec.TemplateRef.Name, formatViolations(violations))
+}
+
+// CheckTemplateCompliance implements controller.TemplateComplianceChecker, so that the controller
+// reports the violations of workspaces whose template was tightened after they were admitted
+func (tv *TemplateValidator) CheckTemplateCompliance(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*controller.TemplateCompliance, error) {
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil, nil
+	}
+
+	template, err := tv.fetchTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		return nil, err
 	}
 
+	compliance := &controller.TemplateCompliance{
+		Template:  template.Name,
+		ApplyMode: template.Spec.ApplyMode,
+	}
+	for _, violation := range templateViolations(workspace, template) {
+		compliance.Violations = append(compliance.Violations, violation.Message)
+	}
+	return compliance, nil
+}
+
+// templateViolations returns the constraints of the template that the workspace violates
+func templateViolations(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
 	var violations []TemplateViolation
 
 	// Validate image
@@ -99,6 +162,51 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		}
 	}
 
+	// Validate profile
+	if violation := validateResourceProfile(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate priority class
+	if violation := validatePriorityClassName(workspace.Spec.PriorityClassName, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate runtime class
+	if violation := validateRuntimeClassName(workspace.Spec.RuntimeClassName, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate GPU sharing
+	if sharingViolations := validateGPUSharing(workspace, template); len(sharingViolations) > 0 {
+		violations = append(violations, sharingViolations...)
+	}
+
+	// Validate custom domain
+	if violation := validateCustomDomain(workspace.Spec.CustomDomain, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the security profile and the exceptions it allows
+	if profileViolations := validateSecurityProfile(workspace, template); len(profileViolations) > 0 {
+		violations = append(violations, profileViolations...)
+	}
+
+	// Validate network isolation
+	if violation := validateNetworkIsolation(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate provisioning hints
+	if violation := validateProvisioningHints(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the Jupyter server config
+	if violation := validateJupyterServerConfig(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
 	// Validate resources
 	if workspace.Spec.Resources != nil {
 		if resourceViolations := validateResourceBounds(*workspace.Spec.Resources, template); len(resourceViolations) > 0 {
@@ -113,16 +221,36 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		}
 	}
 
+	// Validate the storage provisioning hook
+	if violation := validateStorageProvisioningHook(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
 	// Validate secondary storage volumes
 	if violation := validateSecondaryStorages(workspace.Spec.Volumes, template); violation != nil {
 		violations = append(violations, *violation)
 	}
 
+	// Validate volume mount paths and NFS servers
+	if volumeViolations := validateVolumeMounts(workspace.Spec.Volumes, template); len(volumeViolations) > 0 {
+		violations = append(violations, volumeViolations...)
+	}
+
+	// Validate object storage buckets and mount paths
+	if mountViolations := validateObjectStorageMounts(workspace.Spec.ObjectStorageMounts, template); len(mountViolations) > 0 {
+		violations = append(violations, mountViolations...)
+	}
+
 	// Validate init containers
 	if violation := validateInitContainers(workspace.Spec.InitContainers, template); violation != nil {
 		violations = append(violations, *violation)
 	}
 
+	// Validate the IDs of the init containers and the provisioning hook under the identity mapping
+	if identityViolations := validateIdentityMapping(workspace, template); len(identityViolations) > 0 {
+		violations = append(violations, identityViolations...)
+	}
+
 	// Validate label requirements
 	if labelViolations := validateLabelRequirements(workspace, template); len(labelViolations) > 0 {
 		violations = append(violations, labelViolations...)
@@ -138,11 +266,12 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		violations = append(violations, idleViolations...)
 	}
 
-	if len(violations) > 0 {
-		return fmt.Errorf("workspace violates template '%s' constraints: %s", workspace.Spec.TemplateRef.Name, formatViolations(violations))
+	// Validate culling exemptions against the template's idle shutdown lock
+	if violation := validateCullingPolicy(workspace, template); violation != nil {
+		violations = append(violations, *violation)
 	}
 
-	return nil
+	return violations
 }
 
 // ValidateUpdateWorkspace validates entire spec when any spec field changes (Kubernetes best practice)
@@ -157,10 +286,10 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 	templateRefChanged := oldTemplateRef != nil && newTemplateRef != nil && oldTemplateRef.Name != newTemplateRef.Name
 
 	// Case 1: TemplateRef deleted (template → standalone)
-	// Removing constraints is always safe - no validation needed
+	// Removing constraints is always safe - only a profile and a custom domain need the template
 	if templateRefDeleted {
 		workspacelog.Info("TemplateRef deleted, allowing transition to standalone workspace", "workspace", newWorkspace.Name)
-		return nil
+		return validateStandaloneWorkspace(newWorkspace)
 	}
 
 	// Case 2: TemplateRef changed (template A → template B)
@@ -183,7 +312,7 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 	// Case 4: No templateRef in both old and new
 	// No template constraints to validate
 	if newTemplateRef == nil {
-		return nil
+		return validateStandaloneWorkspace(newWorkspace)
 	}
 
 	// Case 5: TemplateRef unchanged - check other conditions
@@ -194,11 +323,10 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 		return nil
 	}
 
-	// Special case: If ONLY DesiredStatus changed to Stopped, allow without validation
+	// Special case: If ONLY DesiredStatus changed to Stopped or Hibernated, allow without validation
 	// This enables users to stop workspaces without validation (emergency shutdown, cost savings)
 	// However, if other spec fields also changed, those changes must be validated
-	if newWorkspace.Spec.DesiredStatus == controller.DesiredStateStopped &&
-		oldWorkspace.Spec.DesiredStatus != controller.DesiredStateStopped &&
+	if controller.IsStoppedDesiredStatus(newWorkspace.Spec.DesiredStatus) &&
 		onlyDesiredStatusChanged(&oldWorkspace.Spec, &newWorkspace.Spec) {
 		workspacelog.Info("Allowing workspace stop without template validation (status-only change)", "workspace", newWorkspace.Name)
 		return nil
@@ -208,7 +336,24 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 	// This follows Kubernetes best practices: admission webhooks validate desired state, not deltas
 	// This includes cases where stopping + other changes occur simultaneously
 	workspacelog.Info("Spec changed, validating entire workspace against template", "workspace", newWorkspace.Name)
-	return tv.ValidateCreateWorkspace(ctx, newWorkspace)
+	return tv.validateTemplateConstraints(ctx, newWorkspace, oldWorkspace)
+}
+
+// validateStandaloneWorkspace rejects the fields that only a workspace with a template can set
+func validateStandaloneWorkspace(workspace *workspacev1alpha1.Workspace) error {
+	if err := validateProfileRequiresTemplate(workspace); err != nil {
+		return err
+	}
+	if err := validateCustomDomainRequiresTemplate(workspace); err != nil {
+		return err
+	}
+	if err := validateNFSVolumesRequireTemplate(workspace); err != nil {
+		return err
+	}
+	if err := validateStorageProvisioningHookRequiresTemplate(workspace); err != nil {
+		return err
+	}
+	return validateObjectStorageMountsRequireTemplate(workspace)
 }
 
 // formatViolations formats template violations into a readable error message
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
 )
 
 var _ = Describe("TemplateValidator", func() {
@@ -279,6 +280,23 @@ var _ = Describe("TemplateValidator", func() {
 		})
 	})
 
+	Context("Missing template", func() {
+		It("should report a missing template without the raw API error", func() {
+			validator := buildValidator("")
+
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: "missing-template"},
+				},
+			}
+
+			err := validator.ValidateCreateWorkspace(ctx, workspace)
+			Expect(err).To(MatchError("failed to get template missing-template: not found"))
+			Expect(errs.Is(err, errs.KindNotFound)).To(BeTrue())
+		})
+	})
+
 	// ValidateNamespaceScope is the namespace-only gate the mutating webhook calls before stamping a
 	// protection finalizer. Unlike ValidateCreateWorkspace it must NOT fetch the template, so it
 	// enforces scope even when the template does not exist and never reports a missing-template error.
@@ -365,4 +383,89 @@ var _ = Describe("TemplateValidator", func() {
 			Expect(validator.ValidateNamespaceScope(workspace)).To(Succeed())
 		})
 	})
+
+	Context("Apply mode", func() {
+		newTemplate := func(mode workspacev1alpha1.TemplateApplyMode) *workspacev1alpha1.WorkspaceTemplate {
+			return &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: testSomeTemplate, Namespace: testNamespaceTeamA},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DisplayName:  "Some Template",
+					DefaultImage: testValidBaseNotebook,
+					ApplyMode:    mode,
+				},
+			}
+		}
+
+		newViolatingWorkspace := func() *workspacev1alpha1.Workspace {
+			return &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: testNamespaceTeamA},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Image:       "ghcr.io/other/notebook:1.0",
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: testSomeTemplate},
+				},
+			}
+		}
+
+		It("should reject a violating workspace under Enforce and Migrate", func() {
+			for _, mode := range []workspacev1alpha1.TemplateApplyMode{"", workspacev1alpha1.TemplateApplyModeEnforce, workspacev1alpha1.TemplateApplyModeMigrate} {
+				validator := buildValidator("", newTemplate(mode))
+				err := validator.ValidateCreateWorkspace(ctx, newViolatingWorkspace())
+				Expect(errs.Is(err, errs.KindTemplateViolation)).To(BeTrue(), "mode %q", mode)
+			}
+		})
+
+		It("should reject creating a violating workspace under Audit", func() {
+			validator := buildValidator("", newTemplate(workspacev1alpha1.TemplateApplyModeAudit))
+			err := validator.ValidateCreateWorkspace(ctx, newViolatingWorkspace())
+			Expect(errs.Is(err, errs.KindTemplateViolation)).To(BeTrue())
+		})
+
+		It("should reject switching a violating workspace to a template under Audit", func() {
+			validator := buildValidator("", newTemplate(workspacev1alpha1.TemplateApplyModeAudit))
+			oldWorkspace := newViolatingWorkspace()
+			oldWorkspace.Spec.TemplateRef = nil
+
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newViolatingWorkspace())
+			Expect(errs.Is(err, errs.KindTemplateViolation)).To(BeTrue())
+		})
+
+		It("should admit updates keeping the violations of a workspace under Audit", func() {
+			validator := buildValidator("", newTemplate(workspacev1alpha1.TemplateApplyModeAudit))
+			newWorkspace := newViolatingWorkspace()
+			newWorkspace.Spec.DisplayName = "Renamed"
+
+			Expect(validator.ValidateUpdateWorkspace(ctx, newViolatingWorkspace(), newWorkspace)).To(Succeed())
+		})
+
+		It("should reject updates adding violations to a workspace under Audit", func() {
+			validator := buildValidator("", newTemplate(workspacev1alpha1.TemplateApplyModeAudit))
+			newWorkspace := newViolatingWorkspace()
+			newWorkspace.Spec.Image = "ghcr.io/other/notebook:2.0"
+
+			err := validator.ValidateUpdateWorkspace(ctx, newViolatingWorkspace(), newWorkspace)
+			Expect(errs.Is(err, errs.KindTemplateViolation)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("notebook:2.0"))
+		})
+
+		It("should report the violations and apply mode of the template", func() {
+			validator := buildValidator("", newTemplate(workspacev1alpha1.TemplateApplyModeAudit))
+
+			compliance, err := validator.CheckTemplateCompliance(ctx, newViolatingWorkspace())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(compliance.Template).To(Equal(testSomeTemplate))
+			Expect(compliance.ApplyMode).To(Equal(workspacev1alpha1.TemplateApplyModeAudit))
+			Expect(compliance.Violations).To(HaveLen(1))
+			Expect(compliance.Violations[0]).To(ContainSubstring("is not allowed"))
+		})
+
+		It("should report no compliance for a workspace without template", func() {
+			validator := buildValidator("")
+
+			compliance, err := validator.CheckTemplateCompliance(ctx, &workspacev1alpha1.Workspace{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(compliance).To(BeNil())
+		})
+	})
 })
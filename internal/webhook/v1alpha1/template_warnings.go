@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// imageEndOfLifeWarningWindow is how long before the end of life of an image its workspaces get a warning
+const imageEndOfLifeWarningWindow = 30 * 24 * time.Hour
+
+// resourceBoundWarningRatio is the share of the maximum of a template resource bound from which
+// a request gets a warning
+const resourceBoundWarningRatio = 0.9
+
+// WarnWorkspace returns the admission warnings of a workspace: soft issues that do not prevent its
+// admission, such as deprecated fields, an image nearing its end of life in the template, or resource
+// requests close to the template bounds. A template that cannot be resolved produces no warning,
+// the validation reports it.
+func (tv *TemplateValidator) WarnWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) admission.Warnings {
+	var template *workspacev1alpha1.WorkspaceTemplate
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		resolved, err := tv.fetchTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+		if err != nil {
+			workspacelog.V(1).Info("Skipping template warnings", "workspace", workspace.Name, "error", err.Error())
+		} else {
+			template = resolved
+		}
+	}
+
+	var warnings admission.Warnings
+	warnings = append(warnings, deprecatedFieldWarnings(workspace, template)...)
+	if template == nil {
+		return warnings
+	}
+	if warning := imageEndOfLifeWarning(workspace.Spec.Image, template, tv.now()); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	warnings = append(warnings, resourceBoundWarnings(workspace, template)...)
+	return warnings
+}
+
+// deprecatedFieldWarnings returns a warning per deprecated field the workspace sets. Values
+// defaulted from the template are left to the template.
+func deprecatedFieldWarnings(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []string {
+	var warnings []string
+	if probe := workspace.Spec.ReadinessProbe; probe != nil &&
+		(template == nil || !equality.Semantic.DeepEqual(probe, template.Spec.DefaultReadinessProbe)) {
+		warnings = append(warnings, "spec.readinessProbe is deprecated; use spec.probes.readiness instead")
+	}
+	return warnings
+}
+
+// imageEndOfLifeWarning returns a warning when the template announces the end of life of the image
+// within imageEndOfLifeWarningWindow, or after it passed
+func imageEndOfLifeWarning(image string, template *workspacev1alpha1.WorkspaceTemplate, now time.Time) string {
+	for _, deprecation := range template.Spec.ImageDeprecations {
+		if deprecation.Image != image || now.Add(imageEndOfLifeWarningWindow).Before(deprecation.EndOfLife.Time) {
+			continue
+		}
+		date := deprecation.EndOfLife.UTC().Format(time.DateOnly)
+		warning := fmt.Sprintf("image %s reaches its end of life on %s in template '%s'", image, date, template.Name)
+		if !now.Before(deprecation.EndOfLife.Time) {
+			warning = fmt.Sprintf("image %s reached its end of life on %s in template '%s'", image, date, template.Name)
+		}
+		if deprecation.Replacement != "" {
+			warning += "; use " + deprecation.Replacement + " instead"
+		}
+		return warning
+	}
+	return ""
+}
+
+// resourceBoundWarnings returns a warning per resource request within the template bounds but
+// close to their maximum. Resources set by a profile or the template defaults are chosen by the
+// template and get no warning.
+func resourceBoundWarnings(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []string {
+	resources := workspace.Spec.Resources
+	bounds := template.Spec.ResourceBounds
+	if resources == nil || bounds == nil || workspace.Spec.Profile != "" ||
+		resourcesEqual(resources, template.Spec.DefaultResources) {
+		return nil
+	}
+
+	names := make([]string, 0, len(bounds.Resources))
+	for name := range bounds.Resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		resourceRange := bounds.Resources[corev1.ResourceName(name)]
+		request, exists := resources.Requests[corev1.ResourceName(name)]
+		if !exists || resourceRange.Max.IsZero() || request.Cmp(resourceRange.Max) > 0 {
+			continue
+		}
+		if request.AsApproximateFloat64() >= resourceBoundWarningRatio*resourceRange.Max.AsApproximateFloat64() {
+			warnings = append(warnings, fmt.Sprintf("%s request %s is close to the maximum %s allowed by template '%s'",
+				name, request.String(), resourceRange.Max.String(), template.Name))
+		}
+	}
+	return warnings
+}
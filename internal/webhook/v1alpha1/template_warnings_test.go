@@ -0,0 +1,150 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("TemplateValidator warnings", func() {
+	const deprecatedImage = "jupyter/scipy-notebook:2024-01-15"
+
+	var (
+		ctx       context.Context
+		now       time.Time
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplateName, Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  "Warnings Template",
+				DefaultImage: testValidBaseNotebook,
+				ResourceBounds: &workspacev1alpha1.ResourceBounds{
+					Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+						corev1.ResourceCPU:    {Min: resource.MustParse("100m"), Max: resource.MustParse("2")},
+						corev1.ResourceMemory: {Min: resource.MustParse("128Mi"), Max: resource.MustParse("4Gi")},
+					},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "warned-workspace", Namespace: testDefaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:       testValidBaseNotebook,
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName},
+			},
+		}
+	})
+
+	warn := func() []string {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator := NewTemplateValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "")
+		validator.now = func() time.Time { return now }
+		return validator.WarnWorkspace(ctx, workspace)
+	}
+
+	It("should not warn a workspace without soft issues", func() {
+		Expect(warn()).To(BeEmpty())
+	})
+
+	It("should warn about a deprecated readiness probe", func() {
+		workspace.Spec.ReadinessProbe = &corev1.Probe{PeriodSeconds: 5}
+		Expect(warn()).To(ConsistOf(ContainSubstring("spec.readinessProbe is deprecated")))
+	})
+
+	It("should not warn about a readiness probe defaulted from the template", func() {
+		template.Spec.DefaultReadinessProbe = &corev1.Probe{PeriodSeconds: 5}
+		workspace.Spec.ReadinessProbe = &corev1.Probe{PeriodSeconds: 5}
+		Expect(warn()).To(BeEmpty())
+	})
+
+	It("should warn about a deprecated field of a standalone workspace", func() {
+		workspace.Spec.TemplateRef = nil
+		workspace.Spec.ReadinessProbe = &corev1.Probe{PeriodSeconds: 5}
+		Expect(warn()).To(HaveLen(1))
+	})
+
+	It("should warn about an image nearing its end of life", func() {
+		workspace.Spec.Image = deprecatedImage
+		template.Spec.ImageDeprecations = []workspacev1alpha1.ImageDeprecation{{
+			Image:       deprecatedImage,
+			EndOfLife:   metav1.NewTime(now.Add(10 * 24 * time.Hour)),
+			Replacement: testValidBaseNotebook,
+		}}
+		Expect(warn()).To(ConsistOf(
+			"image " + deprecatedImage + " reaches its end of life on 2026-03-11 in template '" + testTemplateName +
+				"'; use " + testValidBaseNotebook + " instead"))
+	})
+
+	It("should warn about an image past its end of life", func() {
+		workspace.Spec.Image = deprecatedImage
+		template.Spec.ImageDeprecations = []workspacev1alpha1.ImageDeprecation{{
+			Image:     deprecatedImage,
+			EndOfLife: metav1.NewTime(now.Add(-24 * time.Hour)),
+		}}
+		Expect(warn()).To(ConsistOf(ContainSubstring("reached its end of life on 2026-02-28")))
+	})
+
+	It("should not warn about an image whose end of life is far away", func() {
+		workspace.Spec.Image = deprecatedImage
+		template.Spec.ImageDeprecations = []workspacev1alpha1.ImageDeprecation{{
+			Image:     deprecatedImage,
+			EndOfLife: metav1.NewTime(now.Add(60 * 24 * time.Hour)),
+		}}
+		Expect(warn()).To(BeEmpty())
+	})
+
+	It("should warn about resource requests close to the template maximum", func() {
+		workspace.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1900m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}
+		Expect(warn()).To(ConsistOf(
+			"cpu request 1900m is close to the maximum 2 allowed by template '" + testTemplateName + "'"))
+	})
+
+	It("should leave requests beyond the maximum to the validation", func() {
+		workspace.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		}
+		Expect(warn()).To(BeEmpty())
+	})
+
+	It("should not warn about the resources of a profile", func() {
+		workspace.Spec.Profile = "large"
+		workspace.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		}
+		Expect(warn()).To(BeEmpty())
+	})
+
+	It("should not warn when the template cannot be resolved", func() {
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "missing-template"}
+		workspace.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		}
+		Expect(warn()).To(BeEmpty())
+	})
+})
@@ -10,6 +10,8 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -23,11 +25,19 @@ import (
 var templatelog = logf.Log.WithName("workspacetemplate-resource")
 
 // SetupWorkspaceTemplateWebhookWithManager registers the webhook for WorkspaceTemplate in the manager.
-func SetupWorkspaceTemplateWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace string) error {
-	accessStrategyValidator := NewAccessStrategyValidator(defaultTemplateNamespace)
+func SetupWorkspaceTemplateWebhookWithManager(
+	mgr ctrl.Manager,
+	defaultTemplateNamespace string,
+	trustedNamespaceSelector labels.Selector,
+	templateFreshness *TemplateFreshness,
+	lookupCache *LookupCache,
+) error {
+	accessStrategyValidator := NewAccessStrategyValidator(
+		defaultTemplateNamespace, lookupCache.Client(mgr.GetClient()), trustedNamespaceSelector)
 	return ctrl.NewWebhookManagedBy(mgr, &workspacev1alpha1.WorkspaceTemplate{}).
 		WithValidator(&WorkspaceTemplateCustomValidator{
 			accessStrategyValidator: accessStrategyValidator,
+			templateFreshness:       templateFreshness,
 		}).
 		WithDefaulter(&WorkspaceTemplateCustomDefaulter{
 			client:                  mgr.GetClient(),
@@ -77,7 +87,7 @@ func (d *WorkspaceTemplateCustomDefaulter) Default(ctx context.Context, template
 	// the referenced AccessStrategy does not exist, matching the workspace webhook.
 	// Check namespace scope first so we never modify an AccessStrategy in a disallowed namespace.
 	if template.Spec.DefaultAccessStrategy != nil && template.Spec.DefaultAccessStrategy.Name != "" {
-		if err := d.accessStrategyValidator.ValidateCreateTemplate(template); err != nil {
+		if err := d.accessStrategyValidator.ValidateCreateTemplate(ctx, template); err != nil {
 			return err
 		}
 		asName := template.Labels[workspaceutil.LabelAccessStrategyName]
@@ -106,6 +116,7 @@ func (d *WorkspaceTemplateCustomDefaulter) Default(ctx context.Context, template
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type WorkspaceTemplateCustomValidator struct {
 	accessStrategyValidator *AccessStrategyValidator
+	templateFreshness       *TemplateFreshness
 }
 
 var _ admission.Validator[*workspacev1alpha1.WorkspaceTemplate] = &WorkspaceTemplateCustomValidator{}
@@ -116,7 +127,7 @@ func (v *WorkspaceTemplateCustomValidator) ValidateCreate(ctx context.Context, t
 
 	// Enforce that the referenced access strategy is in an allowed namespace, so the template
 	// cannot make referencing workspaces fail their own admission webhook.
-	if err := v.accessStrategyValidator.ValidateCreateTemplate(template); err != nil {
+	if err := v.accessStrategyValidator.ValidateCreateTemplate(ctx, template); err != nil {
 		return nil, err
 	}
 
@@ -135,7 +146,7 @@ func (v *WorkspaceTemplateCustomValidator) ValidateUpdate(ctx context.Context, o
 
 	// Enforce that the referenced access strategy is in an allowed namespace, so the template
 	// cannot make referencing workspaces fail their own admission webhook.
-	if err := v.accessStrategyValidator.ValidateUpdateTemplate(oldTemplate, newTemplate); err != nil {
+	if err := v.accessStrategyValidator.ValidateUpdateTemplate(ctx, oldTemplate, newTemplate); err != nil {
 		return nil, err
 	}
 
@@ -145,6 +156,12 @@ func (v *WorkspaceTemplateCustomValidator) ValidateUpdate(ctx context.Context, o
 		return nil, err
 	}
 
+	// Workspace admission must not enforce the previous spec once this update is admitted,
+	// even before the informer cache delivers it
+	if !equality.Semantic.DeepEqual(oldTemplate.Spec, newTemplate.Spec) {
+		v.templateFreshness.ExpectGeneration(client.ObjectKeyFromObject(newTemplate), oldTemplate.Generation+1)
+	}
+
 	// Check if constraint fields changed
 	if constraintsChanged(oldTemplate, newTemplate) {
 		templatelog.Info("Template constraints changed, controller will mark workspaces for compliance check", "template", newTemplate.GetName())
@@ -169,8 +186,9 @@ func constraintsChanged(oldTemplate, newTemplate *workspacev1alpha1.WorkspaceTem
 	oldSpec := &oldTemplate.Spec
 	newSpec := &newTemplate.Spec
 
-	// Check AllowedImages changes
-	if !equality.Semantic.DeepEqual(oldSpec.AllowedImages, newSpec.AllowedImages) {
+	// Check AllowedImages and AllowedRegistries changes
+	if !equality.Semantic.DeepEqual(oldSpec.AllowedImages, newSpec.AllowedImages) ||
+		!equality.Semantic.DeepEqual(oldSpec.AllowedRegistries, newSpec.AllowedRegistries) {
 		return true
 	}
 
@@ -199,6 +217,65 @@ func constraintsChanged(oldTemplate, newTemplate *workspacev1alpha1.WorkspaceTem
 		return true
 	}
 
+	// Check Profiles changes
+	if !equality.Semantic.DeepEqual(oldSpec.Profiles, newSpec.Profiles) {
+		return true
+	}
+
+	// Check priority class allowlist changes
+	if oldSpec.DefaultPriorityClassName != newSpec.DefaultPriorityClassName ||
+		!equality.Semantic.DeepEqual(oldSpec.AllowedPriorityClassNames, newSpec.AllowedPriorityClassNames) {
+		return true
+	}
+
+	// Check security profile changes
+	if !equality.Semantic.DeepEqual(oldSpec.SecurityProfile, newSpec.SecurityProfile) {
+		return true
+	}
+
+	// Check storage provisioning hook changes
+	if provisioningHookChanged(oldSpec.PrimaryStorage, newSpec.PrimaryStorage) {
+		return true
+	}
+
+	// Check network isolation changes
+	if !equality.Semantic.DeepEqual(oldSpec.NetworkIsolation, newSpec.NetworkIsolation) {
+		return true
+	}
+
+	// Check provisioning hints changes
+	if !equality.Semantic.DeepEqual(oldSpec.ProvisioningHints, newSpec.ProvisioningHints) {
+		return true
+	}
+
+	// Check Jupyter server config changes
+	if !equality.Semantic.DeepEqual(oldSpec.JupyterServerConfig, newSpec.JupyterServerConfig) {
+		return true
+	}
+
+	// Check volume mount path and NFS server allowlist changes
+	if !equality.Semantic.DeepEqual(oldSpec.AllowedVolumeMountPaths, newSpec.AllowedVolumeMountPaths) ||
+		!equality.Semantic.DeepEqual(oldSpec.AllowedNFSServers, newSpec.AllowedNFSServers) {
+		return true
+	}
+
+	// Check bucket allowlist changes
+	if !equality.Semantic.DeepEqual(oldSpec.AllowedBuckets, newSpec.AllowedBuckets) {
+		return true
+	}
+
+	// Check runtime class allowlist changes
+	if oldSpec.RuntimeClassName != newSpec.RuntimeClassName ||
+		!equality.Semantic.DeepEqual(oldSpec.AllowedRuntimeClassNames, newSpec.AllowedRuntimeClassNames) {
+		return true
+	}
+
+	// Check GPU sharing changes
+	if !equality.Semantic.DeepEqual(oldSpec.GPUSharing, newSpec.GPUSharing) ||
+		!equality.Semantic.DeepEqual(oldSpec.AllowedGPUSharingModes, newSpec.AllowedGPUSharingModes) {
+		return true
+	}
+
 	return false
 }
 
@@ -254,10 +331,27 @@ func maxStorageSizeChanged(oldStorage, newStorage *workspacev1alpha1.StorageConf
 	return false
 }
 
+// provisioningHookChanged returns whether the provisioning hook of the primary storage changed
+func provisioningHookChanged(oldStorage, newStorage *workspacev1alpha1.StorageConfig) bool {
+	var oldHook, newHook *workspacev1alpha1.StorageProvisioningHook
+	if oldStorage != nil {
+		oldHook = oldStorage.ProvisioningHook
+	}
+	if newStorage != nil {
+		newHook = newStorage.ProvisioningHook
+	}
+	return !equality.Semantic.DeepEqual(oldHook, newHook)
+}
+
 // validateTemplateConsistency rejects a template whose own constraints are internally
 // inconsistent - contradictions that would make the template's defaults or any workspace value
 // un-admittable, silently self-defeating the template. These checks run on create and update.
 func validateTemplateConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	// allowedImages regular expressions must compile.
+	if err := validateAllowedImagePatterns(template); err != nil {
+		return err
+	}
+
 	// defaultImage must be creatable under the template's own image policy (#440).
 	if err := validateTemplateImageConsistency(template); err != nil {
 		return err
@@ -273,10 +367,52 @@ func validateTemplateConsistency(template *workspacev1alpha1.WorkspaceTemplate)
 		return err
 	}
 
+	// defaultProfile must be defined, and profiles must be within resourceBounds.
+	if err := validateTemplateProfileConsistency(template); err != nil {
+		return err
+	}
+
+	// defaultPriorityClassName must be one of allowedPriorityClassNames.
+	if err := validateTemplatePriorityClassConsistency(template); err != nil {
+		return err
+	}
+
+	// runtimeClassName must be one of allowedRuntimeClassNames.
+	if err := validateTemplateRuntimeClassConsistency(template); err != nil {
+		return err
+	}
+
+	// gpuSharing mode must be one of allowedGPUSharingModes.
+	if err := validateTemplateGPUSharingConsistency(template); err != nil {
+		return err
+	}
+
+	// defaultVolumes must satisfy allowedVolumeMountPaths and allowedNFSServers.
+	if err := validateTemplateVolumeConsistency(template); err != nil {
+		return err
+	}
+
+	// defaultNamespaceSelector must be a valid label selector.
+	if err := validateTemplateDefaultNamespaceSelector(template); err != nil {
+		return err
+	}
+
 	// idleShutdownOverrides bounds must be consistent, and a locked policy needs a default.
 	return validateIdleShutdownPolicyConsistency(template)
 }
 
+// validateTemplateDefaultNamespaceSelector rejects a defaultNamespaceSelector that is not a valid
+// label selector, which would fail the defaulting of every workspace looking for a default template
+func validateTemplateDefaultNamespaceSelector(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.DefaultNamespaceSelector == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(template.Spec.DefaultNamespaceSelector); err != nil {
+		return fmt.Errorf("defaultNamespaceSelector of template %q is invalid: %w", template.GetName(), err)
+	}
+	return nil
+}
+
 // validateIdleShutdownPolicyConsistency rejects a template whose idle shutdown policy is
 // self-defeating: bounds that no timeout can satisfy, a locked policy with no default to enforce
 // against, or a default timeout that its own bounds would reject. All are surfaced at template
@@ -43,7 +43,7 @@ var _ = Describe("WorkspaceTemplate Defaulter", func() {
 		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
 		return WorkspaceTemplateCustomDefaulter{
 			client:                  fakeClient,
-			accessStrategyValidator: NewAccessStrategyValidator("shared-ns"),
+			accessStrategyValidator: NewAccessStrategyValidator("shared-ns", nil, nil),
 		}
 	}
 
@@ -141,7 +141,7 @@ var _ = Describe("WorkspaceTemplate Validator", func() {
 	BeforeEach(func() {
 		ctx = context.Background()
 		validator = WorkspaceTemplateCustomValidator{
-			accessStrategyValidator: NewAccessStrategyValidator("shared-ns"),
+			accessStrategyValidator: NewAccessStrategyValidator("shared-ns", nil, nil),
 		}
 	})
 
@@ -165,6 +165,16 @@ var _ = Describe("WorkspaceTemplate Validator", func() {
 			Expect(err.Error()).To(ContainSubstring("template namespace"))
 		})
 
+		It("rejects a template with an invalid default namespace selector", func() {
+			template := templateWithAS(testNamespaceTeamA)
+			template.Spec.DefaultNamespaceSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Equals"}},
+			}
+			_, err := validator.ValidateCreate(ctx, template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("defaultNamespaceSelector"))
+		})
+
 	})
 
 	Context("ValidateUpdate", func() {
@@ -408,6 +418,32 @@ var _ = Describe("WorkspaceTemplate Validator", func() {
 			_, err := validator.ValidateCreate(ctx, templateWithImages(testImgDeflt, []string{testImgA}, boolPtr(true)))
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("allows a defaultImage matching an allowedImages pattern", func() {
+			_, err := validator.ValidateCreate(ctx, templateWithImages("ghcr.io/org/notebook:1.0", []string{"ghcr.io/org/*"}, nil))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("allows a defaultImage in allowedRegistries", func() {
+			tmpl := templateWithImages("ghcr.io/org/notebook:1.0", nil, nil)
+			tmpl.Spec.AllowedRegistries = []string{"ghcr.io/org"}
+			_, err := validator.ValidateCreate(ctx, tmpl)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects a defaultImage outside allowedRegistries", func() {
+			tmpl := templateWithImages(testImgDeflt, nil, nil)
+			tmpl.Spec.AllowedRegistries = []string{"ghcr.io/org"}
+			_, err := validator.ValidateCreate(ctx, tmpl)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("allowedRegistries"))
+		})
+
+		It("rejects an invalid allowedImages regular expression", func() {
+			_, err := validator.ValidateCreate(ctx, templateWithImages(testImgDeflt, []string{"^ghcr.io/(org$", testImgDeflt}, nil))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid allowed image regular expression"))
+		})
 	})
 
 	Context("Storage bounds consistency", func() {
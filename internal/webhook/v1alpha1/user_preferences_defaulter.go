@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// TimezoneEnvVar is the environment variable set from the timezone preference of the user
+const TimezoneEnvVar = "TZ"
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaceuserpreferences,verbs=get;list;watch
+
+// UserPreferencesDefaulter applies the WorkspaceUserPreferences of the workspace creator.
+// Preferences only fill fields the workspace leaves empty, and run before the template
+// defaults, which then fill the remaining fields. Preferences the template does not allow
+// (image not allowed, resources out of bounds) are skipped rather than rejected, since the
+// user did not ask for them on this workspace.
+type UserPreferencesDefaulter struct {
+	client   client.Client
+	resolver *workspaceutil.TemplateResolver
+}
+
+// NewUserPreferencesDefaulter creates a new UserPreferencesDefaulter
+func NewUserPreferencesDefaulter(k8sClient client.Client, defaultTemplateNamespace string) *UserPreferencesDefaulter {
+	return &UserPreferencesDefaulter{
+		client:   k8sClient,
+		resolver: workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+	}
+}
+
+// ApplyUserPreferences applies the preferences of the workspace creator to the workspace
+func (pd *UserPreferencesDefaulter) ApplyUserPreferences(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	user := workspace.Annotations[controller.AnnotationCreatedBy]
+	if user == "" {
+		return nil
+	}
+
+	preferences, err := pd.findUserPreferences(ctx, workspace.Namespace, user)
+	if err != nil || preferences == nil {
+		return err
+	}
+
+	var template *workspacev1alpha1.WorkspaceTemplate
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		template, err = pd.resolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	applyUserPreferences(workspace, &preferences.Spec, template)
+	return nil
+}
+
+// findUserPreferences returns the preferences of the user in the namespace, or nil if none.
+// The validating webhook admits one per user, but when several match anyway, e.g. created
+// concurrently, the first by name wins, so that the result is deterministic.
+func (pd *UserPreferencesDefaulter) findUserPreferences(
+	ctx context.Context,
+	namespace, user string,
+) (*workspacev1alpha1.WorkspaceUserPreferences, error) {
+	list := &workspacev1alpha1.WorkspaceUserPreferencesList{}
+	if err := pd.client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list workspace user preferences: %w", err)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	for i := range list.Items {
+		if list.Items[i].Spec.User == user {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// applyUserPreferences fills the empty workspace fields from the preferences, within
+// the constraints of the template if any
+func applyUserPreferences(
+	workspace *workspacev1alpha1.Workspace,
+	preferences *workspacev1alpha1.WorkspaceUserPreferencesSpec,
+	template *workspacev1alpha1.WorkspaceTemplate,
+) {
+	if workspace.Spec.Image == "" && preferences.Image != "" {
+		if template == nil || validateImageAllowed(preferences.Image, template) == nil {
+			workspace.Spec.Image = preferences.Image
+		}
+	}
+
+	if workspace.Spec.Resources == nil && preferences.Resources != nil {
+		if template == nil || len(validateResourceBounds(*preferences.Resources, template)) == 0 {
+			workspace.Spec.Resources = preferences.Resources.DeepCopy()
+		}
+	}
+
+	if preferences.Timezone != "" {
+		for _, e := range workspace.Spec.Env {
+			if e.Name == TimezoneEnvVar {
+				return
+			}
+		}
+		workspace.Spec.Env = append(workspace.Spec.Env, corev1.EnvVar{Name: TimezoneEnvVar, Value: preferences.Timezone})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("User Preferences Defaulter", func() {
+	const (
+		preferredImage = "jupyter/scipy-notebook:latest"
+		templateImage  = "jupyter/base-notebook:latest"
+		preferredTZ    = "Europe/Paris"
+	)
+
+	var ctx context.Context
+
+	cpuResources := func(cpu string) *corev1.ResourceRequirements {
+		return &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}
+	}
+
+	newWorkspace := func(user string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "workspace",
+				Namespace:   testNamespaceTeamA,
+				Annotations: map[string]string{controller.AnnotationCreatedBy: user},
+			},
+		}
+	}
+
+	newPreferences := func(name, user string) *workspacev1alpha1.WorkspaceUserPreferences {
+		return &workspacev1alpha1.WorkspaceUserPreferences{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceUserPreferencesSpec{
+				User:      user,
+				Image:     preferredImage,
+				Resources: cpuResources("1"),
+				Timezone:  preferredTZ,
+			},
+		}
+	}
+
+	newTemplate := func(allowedImages []string, maxCPU string) *workspacev1alpha1.WorkspaceTemplate {
+		return &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:   "Template",
+				DefaultImage:  templateImage,
+				AllowedImages: allowedImages,
+				ResourceBounds: &workspacev1alpha1.ResourceBounds{
+					Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+						corev1.ResourceCPU: {Min: resource.MustParse("100m"), Max: resource.MustParse(maxCPU)},
+					},
+				},
+			},
+		}
+	}
+
+	newDefaulter := func(objs ...client.Object) *UserPreferencesDefaulter {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		return NewUserPreferencesDefaulter(fakeClient, "")
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should fill unset fields from the creator's preferences", func() {
+		defaulter := newDefaulter(newPreferences("user1-preferences", testUser1))
+		workspace := newWorkspace(testUser1)
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal(preferredImage))
+		Expect(workspace.Spec.Resources).To(Equal(cpuResources("1")))
+		Expect(workspace.Spec.Env).To(ContainElement(corev1.EnvVar{Name: TimezoneEnvVar, Value: preferredTZ}))
+	})
+
+	It("should keep the fields set on the workspace", func() {
+		defaulter := newDefaulter(newPreferences("user1-preferences", testUser1))
+		workspace := newWorkspace(testUser1)
+		workspace.Spec.Image = "custom:latest"
+		workspace.Spec.Resources = cpuResources("2")
+		workspace.Spec.Env = []corev1.EnvVar{{Name: TimezoneEnvVar, Value: "UTC"}}
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal("custom:latest"))
+		Expect(workspace.Spec.Resources).To(Equal(cpuResources("2")))
+		Expect(workspace.Spec.Env).To(Equal([]corev1.EnvVar{{Name: TimezoneEnvVar, Value: "UTC"}}))
+	})
+
+	It("should ignore the preferences of other users", func() {
+		defaulter := newDefaulter(newPreferences("owner-preferences", testOwnerUser))
+		workspace := newWorkspace(testUser1)
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(BeEmpty())
+		Expect(workspace.Spec.Resources).To(BeNil())
+		Expect(workspace.Spec.Env).To(BeEmpty())
+	})
+
+	It("should do nothing for workspaces without a creator", func() {
+		defaulter := newDefaulter(newPreferences("user1-preferences", testUser1))
+		workspace := newWorkspace("")
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(BeEmpty())
+	})
+
+	It("should use the first matching preferences by name", func() {
+		second := newPreferences("b-preferences", testUser1)
+		second.Spec.Image = "second:latest"
+		defaulter := newDefaulter(second, newPreferences("a-preferences", testUser1))
+		workspace := newWorkspace(testUser1)
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal(preferredImage))
+	})
+
+	It("should apply preferences allowed by the template", func() {
+		defaulter := newDefaulter(
+			newPreferences("user1-preferences", testUser1),
+			newTemplate([]string{templateImage, preferredImage}, "4"),
+		)
+		workspace := newWorkspace(testUser1)
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "template"}
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(Equal(preferredImage))
+		Expect(workspace.Spec.Resources).To(Equal(cpuResources("1")))
+	})
+
+	It("should skip preferences outside the template constraints", func() {
+		defaulter := newDefaulter(
+			newPreferences("user1-preferences", testUser1),
+			newTemplate([]string{templateImage}, "500m"),
+		)
+		workspace := newWorkspace(testUser1)
+		workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "template"}
+
+		Expect(defaulter.ApplyUserPreferences(ctx, workspace)).To(Succeed())
+
+		Expect(workspace.Spec.Image).To(BeEmpty())
+		Expect(workspace.Spec.Resources).To(BeNil())
+		Expect(workspace.Spec.Env).To(ContainElement(corev1.EnvVar{Name: TimezoneEnvVar, Value: preferredTZ}))
+	})
+})
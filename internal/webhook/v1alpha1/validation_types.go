@@ -28,17 +28,41 @@ type TemplateViolation struct {
 const (
 	ViolationTypeImageNotAllowed                = "ImageNotAllowed"
 	ViolationTypeResourceExceeded               = "ResourceExceeded"
+	ViolationTypeProfileNotAllowed              = "ProfileNotAllowed"
 	ViolationTypeStorageExceeded                = "StorageExceeded"
 	ViolationTypeSecondaryStorageNotAllowed     = "SecondaryStorageNotAllowed"
 	ViolationTypeVolumeOwnedByAnotherWorkspace  = "VolumeOwnedByAnotherWorkspace"
 	ViolationTypeInvalidTemplate                = "InvalidTemplate"
 	ViolationTypeIdleShutdownOverrideNotAllowed = "IdleShutdownOverrideNotAllowed"
 	ViolationTypeIdleShutdownTimeoutOutOfBounds = "IdleShutdownTimeoutOutOfBounds"
+	ViolationTypeCullingExemptionNotAllowed     = "CullingExemptionNotAllowed"
 	ViolationTypeLabelRequired                  = "LabelRequired"
 	ViolationTypeLabelRegexMismatch             = "LabelRegexMismatch"
 	ViolationTypeEnvRequired                    = "EnvRequired"
 	ViolationTypeEnvRegexMismatch               = "EnvRegexMismatch"
+	ViolationTypeEnvProtected                   = "EnvProtected"
 	ViolationTypeInitContainersNotAllowed       = "InitContainersNotAllowed"
+	ViolationTypePrivilegedNotAllowed           = "PrivilegedNotAllowed"
+	ViolationTypeHostProcessNotAllowed          = "HostProcessNotAllowed"
+	ViolationTypeCapabilityNotAllowed           = "CapabilityNotAllowed"
+	ViolationTypePriorityClassNotAllowed        = "PriorityClassNotAllowed"
+	ViolationTypeRuntimeClassNotAllowed         = "RuntimeClassNotAllowed"
+	ViolationTypeGPUSharingModeNotAllowed       = "GPUSharingModeNotAllowed"
+	ViolationTypeGPUSharingMismatch             = "GPUSharingMismatch"
+	ViolationTypeCustomDomainNotAllowed         = "CustomDomainNotAllowed"
+	ViolationTypeSecurityProfileMismatch        = "SecurityProfileMismatch"
+	ViolationTypeNetworkIsolationMismatch       = "NetworkIsolationMismatch"
+	ViolationTypeProvisioningHintsMismatch      = "ProvisioningHintsMismatch"
+	ViolationTypeJupyterServerConfigMismatch    = "JupyterServerConfigMismatch"
+	ViolationTypeVolumeMountPathNotAllowed      = "VolumeMountPathNotAllowed"
+	ViolationTypeNFSServerNotAllowed            = "NFSServerNotAllowed"
+	ViolationTypeBucketNotAllowed               = "BucketNotAllowed"
+	ViolationTypeProvisioningHookMismatch       = "ProvisioningHookMismatch"
+	ViolationTypeIdentityMismatch               = "IdentityMismatch"
+	ViolationTypeRunAsRootNotAllowed            = "RunAsRootNotAllowed"
+	ViolationTypePrivilegeEscalationNotAllowed  = "PrivilegeEscalationNotAllowed"
+	ViolationTypeSeccompProfileNotAllowed       = "SeccompProfileNotAllowed"
+	ViolationTypeWritableRootFSNotAllowed       = "WritableRootFilesystemNotAllowed"
 )
 
 // labelValueTrue is the string value used for boolean-style Kubernetes labels.
@@ -8,6 +8,9 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -57,9 +60,92 @@ func validateSecondaryStorages(volumes []workspacev1alpha1.VolumeSpec, template
 	return nil
 }
 
+// validateVolumeSubPaths rejects sub paths that would escape the root of their volume
+func validateVolumeSubPaths(workspace *workspacev1alpha1.Workspace) error {
+	for _, volume := range workspace.Spec.Volumes {
+		if slices.Contains(strings.Split(volume.SubPath, "/"), "..") {
+			return fmt.Errorf("spec.volumes[%s].subPath %q cannot contain '..'", volume.Name, volume.SubPath)
+		}
+	}
+	return nil
+}
+
+// validateNFSVolumesRequireTemplate rejects NFS volumes on a workspace without template, since
+// the template lists the NFS servers workspaces can mount
+func validateNFSVolumesRequireTemplate(workspace *workspacev1alpha1.Workspace) error {
+	for _, volume := range workspace.Spec.Volumes {
+		if volume.NFS != nil {
+			return fmt.Errorf("NFS volume %q requires a templateRef", volume.Name)
+		}
+	}
+	return nil
+}
+
+// validateVolumeMounts checks that volumes are mounted under the mount paths the template allows,
+// and that NFS volumes use servers the template allows. Rejects any NFS volume when the template
+// allows no server (secure by default).
+func validateVolumeMounts(volumes []workspacev1alpha1.VolumeSpec, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	var violations []TemplateViolation
+	for _, volume := range volumes {
+		if !volumeMountPathAllowed(volume.MountPath, template.Spec.AllowedVolumeMountPaths) {
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeVolumeMountPathNotAllowed,
+				Field:   fmt.Sprintf("spec.volumes[%s].mountPath", volume.Name),
+				Message: fmt.Sprintf("Mount path '%s' of volume '%s' is not allowed by template '%s'", volume.MountPath, volume.Name, template.Name),
+				Allowed: "paths under " + strings.Join(template.Spec.AllowedVolumeMountPaths, ", "),
+				Actual:  volume.MountPath,
+			})
+		}
+		if volume.NFS != nil && !slices.Contains(template.Spec.AllowedNFSServers, volume.NFS.Server) {
+			allowed := "no NFS server"
+			if len(template.Spec.AllowedNFSServers) > 0 {
+				allowed = strings.Join(template.Spec.AllowedNFSServers, ", ")
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeNFSServerNotAllowed,
+				Field:   fmt.Sprintf("spec.volumes[%s].nfs.server", volume.Name),
+				Message: fmt.Sprintf("NFS server '%s' of volume '%s' is not allowed by template '%s'", volume.NFS.Server, volume.Name, template.Name),
+				Allowed: allowed,
+				Actual:  volume.NFS.Server,
+			})
+		}
+	}
+	return violations
+}
+
+// volumeMountPathAllowed returns whether mountPath is one of the allowed paths or under one of them,
+// at a path boundary so that /data does not allow /database. Any path is allowed when none is listed.
+func volumeMountPathAllowed(mountPath string, allowedPaths []string) bool {
+	if len(allowedPaths) == 0 {
+		return true
+	}
+	mountPath = path.Clean(mountPath)
+	for _, allowed := range allowedPaths {
+		allowed = path.Clean(allowed)
+		if mountPath == allowed || strings.HasPrefix(mountPath, strings.TrimSuffix(allowed, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTemplateVolumeConsistency rejects a template whose default volumes its own volume mount
+// constraints reject, which would make its own workspaces un-admittable
+func validateTemplateVolumeConsistency(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if violations := validateVolumeMounts(template.Spec.DefaultVolumes, template); len(violations) > 0 {
+		return fmt.Errorf("defaultVolumes of template %q violate its volume constraints: %s",
+			template.GetName(), violations[0].Message)
+	}
+	return nil
+}
+
 // validateVolumeOwnership checks that volumes don't reference PVCs owned by other workspaces
 func validateVolumeOwnership(ctx context.Context, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) *TemplateViolation {
 	for _, volume := range workspace.Spec.Volumes {
+		if volume.PersistentVolumeClaimName == "" {
+			continue
+		}
+
 		// Get the PVC
 		pvc := &corev1.PersistentVolumeClaim{}
 		err := k8sClient.Get(ctx, types.NamespacedName{
@@ -99,7 +99,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupWorkspaceWebhookWithManager(mgr, "")
+	err = SetupWorkspaceWebhookWithManager(mgr, "", nil, MetadataLimits{}, false, nil, nil, nil, nil, nil, false, false, nil)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
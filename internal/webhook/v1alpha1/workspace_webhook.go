@@ -11,6 +11,7 @@ import (
 	"os"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +21,8 @@ import (
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
+	"github.com/jupyter-infra/jupyter-k8s/internal/operatorconfig"
 	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
 	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
@@ -36,9 +39,9 @@ func ensureTemplateFinalizer(ctx context.Context, k8sClient client.Client, templ
 		return nil
 	}
 
-	// Check if at least 1 active workspace uses this template (limit=1 for efficiency)
-	// ListActiveWorkspacesByTemplate filters out workspaces with DeletionTimestamp != nil
-	workspaces, _, err := workspaceutil.ListActiveWorkspacesByTemplate(ctx, k8sClient, templateName, templateNamespace, "", 1)
+	// Check if at least 1 active workspace uses this template
+	// HasActiveWorkspacesWithTemplate filters out workspaces with DeletionTimestamp != nil
+	inUse, err := workspaceutil.HasActiveWorkspacesWithTemplate(ctx, k8sClient, templateName, templateNamespace)
 	if err != nil {
 		workspacelog.Error(err, "Failed to check workspace usage", "template", templateName, "templateNamespace", templateNamespace)
 		return fmt.Errorf("failed to check workspace usage for template %s/%s: %w", templateNamespace, templateName, err)
@@ -46,7 +49,7 @@ func ensureTemplateFinalizer(ctx context.Context, k8sClient client.Client, templ
 
 	// If no active workspaces use the template, don't add finalizer
 	// This implements lazy finalizer pattern - controller will add it when needed
-	if len(workspaces) == 0 {
+	if !inUse {
 		workspacelog.V(1).Info("No active workspaces use template, skipping finalizer", "template", templateName, "templateNamespace", templateNamespace)
 		return nil
 	}
@@ -205,34 +208,102 @@ func validateOwnershipPermission(ctx context.Context, workspace *workspacev1alph
 	return fmt.Errorf("access denied: only workspace owner can modify OwnerOnly workspaces")
 }
 
+// validateOwnerTransferOnCreate rejects workspaces created with a pending ownership transfer, which
+// the controller would otherwise apply to a workspace the new owner never agreed to own
+func validateOwnerTransferOnCreate(workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.OwnerTransferTo != "" {
+		return fmt.Errorf("spec.ownerTransferTo cannot be set when creating a workspace")
+	}
+	return nil
+}
+
+// validateOwnerTransfer rejects users requesting or cancelling an ownership transfer. Transfers move
+// the data, identity and quota usage of a workspace to another user, so only admins request them;
+// the controller then applies them by rewriting the created-by annotation.
+func validateOwnerTransfer(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.OwnerTransferTo == newWorkspace.Spec.OwnerTransferTo {
+		return nil
+	}
+	return fmt.Errorf("access denied: only an admin can transfer workspace ownership")
+}
+
 // SetupWorkspaceWebhookWithManager registers the webhook for Workspace in the manager.
 // RBAC Note: This webhook requires WorkspaceTemplate access (get, update, finalizers/update)
 // which is provided by the workspacetemplate controller RBAC markers.
-func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace string) error {
-	templateValidator := NewTemplateValidator(mgr.GetClient(), defaultTemplateNamespace)
-	accessStrategyValidator := NewAccessStrategyValidator(defaultTemplateNamespace)
-	templateDefaulter := NewTemplateDefaulter(mgr.GetClient(), defaultTemplateNamespace)
-	templateGetter := NewTemplateGetter(mgr.GetClient(), defaultTemplateNamespace)
+func SetupWorkspaceWebhookWithManager(
+	mgr ctrl.Manager,
+	defaultTemplateNamespace string,
+	trustedNamespaceSelector labels.Selector,
+	metadataLimits MetadataLimits,
+	allowPrivilegedWorkspaces bool,
+	templateFreshness *TemplateFreshness,
+	lookupCache *LookupCache,
+	groupResolver GroupResolverInterface,
+	imageVerifier ImageVerifierInterface,
+	externalPolicy ExternalPolicyInterface,
+	externalPolicyFailOpen bool,
+	rightSizingAutoApply bool,
+	operatorConfig *operatorconfig.Store,
+) error {
+	// Template and access strategy reads are served from the lookup cache within its TTL, and
+	// template reads fall back to the API server while the cache lags an admitted template update
+	lookupClient := lookupCache.Client(mgr.GetClient())
+	templateClient := templateFreshness.Client(lookupClient)
+	templateValidator := NewTemplateValidator(templateClient, defaultTemplateNamespace)
+	accessStrategyValidator := NewAccessStrategyValidator(defaultTemplateNamespace, lookupClient, trustedNamespaceSelector)
+	templateDefaulter := NewTemplateDefaulter(templateClient, defaultTemplateNamespace)
+	templateMigrator := NewTemplateMigrator(templateClient, defaultTemplateNamespace)
+	rightSizingApplier := NewRightSizingApplier(rightSizingAutoApply, templateClient, defaultTemplateNamespace)
+	identityMappingDefaulter := NewIdentityMappingDefaulter(templateClient, mgr.GetAPIReader(), defaultTemplateNamespace)
+	userPreferencesDefaulter := NewUserPreferencesDefaulter(templateClient, defaultTemplateNamespace)
+	templateGetter := NewTemplateGetter(lookupClient, defaultTemplateNamespace)
 	serviceAccountValidator := NewServiceAccountValidator(mgr.GetClient())
 	serviceAccountDefaulter := NewServiceAccountDefaulter(mgr.GetClient())
 	volumeValidator := NewVolumeValidator(mgr.GetClient())
-	storageValidator := NewStorageValidator(mgr.GetClient())
+	customDomainValidator := NewCustomDomainValidator(mgr.GetClient())
+	ownershipValidator := NewOwnershipValidator(groupResolver)
+	storageValidator := NewStorageValidator(mgr.GetClient(), ownershipValidator)
+	metadataLimitsValidator := NewMetadataLimitsValidator(metadataLimits)
+	quotaValidator := NewQuotaValidator(mgr.GetClient())
+	podSecurityValidator := NewPodSecurityValidator(allowPrivilegedWorkspaces)
+	cloneValidator := NewCloneValidator(mgr.GetClient(), ownershipValidator)
+	cloneDefaulter := NewCloneDefaulter(mgr.GetClient())
+	imageSignatureValidator := NewImageSignatureValidator(imageVerifier)
+	templateResolver := workspaceutil.NewTemplateResolver(templateClient, defaultTemplateNamespace)
+	externalPolicyValidator := NewExternalPolicyValidator(externalPolicy, externalPolicyFailOpen, templateResolver)
+	templateAccessValidator := NewTemplateAccessValidator(templateResolver, groupResolver)
+	templateRequirementValidator := NewTemplateRequirementValidator(operatorConfig)
 
 	return ctrl.NewWebhookManagedBy(mgr, &workspacev1alpha1.Workspace{}).
 		WithValidator(&WorkspaceCustomValidator{
-			templateValidator:       templateValidator,
-			accessStrategyValidator: accessStrategyValidator,
-			serviceAccountValidator: serviceAccountValidator,
-			volumeValidator:         volumeValidator,
-			storageValidator:        storageValidator,
+			templateValidator:            templateValidator,
+			accessStrategyValidator:      accessStrategyValidator,
+			serviceAccountValidator:      serviceAccountValidator,
+			volumeValidator:              volumeValidator,
+			customDomainValidator:        customDomainValidator,
+			storageValidator:             storageValidator,
+			metadataLimitsValidator:      metadataLimitsValidator,
+			quotaValidator:               quotaValidator,
+			podSecurityValidator:         podSecurityValidator,
+			ownershipValidator:           ownershipValidator,
+			cloneValidator:               cloneValidator,
+			imageSignatureValidator:      imageSignatureValidator,
+			externalPolicyValidator:      externalPolicyValidator,
+			templateAccessValidator:      templateAccessValidator,
+			templateRequirementValidator: templateRequirementValidator,
 		}).
 		WithDefaulter(&WorkspaceCustomDefaulter{
-			templateDefaulter:       templateDefaulter,
-			serviceAccountDefaulter: serviceAccountDefaulter,
-			templateGetter:          templateGetter,
-			templateValidator:       templateValidator,
-			accessStrategyValidator: accessStrategyValidator,
-			client:                  mgr.GetClient(),
+			cloneDefaulter:           cloneDefaulter,
+			templateDefaulter:        templateDefaulter,
+			templateMigrator:         templateMigrator,
+			rightSizingApplier:       rightSizingApplier,
+			identityMappingDefaulter: identityMappingDefaulter,
+			userPreferencesDefaulter: userPreferencesDefaulter,
+			serviceAccountDefaulter:  serviceAccountDefaulter,
+			templateGetter:           templateGetter,
+			templateValidator:        templateValidator,
+			accessStrategyValidator:  accessStrategyValidator,
+			client:                   mgr.GetClient(),
 		}).
 		Complete()
 }
@@ -245,12 +316,17 @@ func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as it is used only for temporary operations and does not need to be deeply copied.
 type WorkspaceCustomDefaulter struct {
-	templateDefaulter       *TemplateDefaulter
-	serviceAccountDefaulter *ServiceAccountDefaulter
-	templateGetter          *TemplateGetter
-	templateValidator       *TemplateValidator
-	accessStrategyValidator *AccessStrategyValidator
-	client                  client.Client
+	cloneDefaulter           *CloneDefaulter
+	templateDefaulter        *TemplateDefaulter
+	templateMigrator         *TemplateMigrator
+	rightSizingApplier       *RightSizingApplier
+	identityMappingDefaulter *IdentityMappingDefaulter
+	userPreferencesDefaulter *UserPreferencesDefaulter
+	serviceAccountDefaulter  *ServiceAccountDefaulter
+	templateGetter           *TemplateGetter
+	templateValidator        *TemplateValidator
+	accessStrategyValidator  *AccessStrategyValidator
+	client                   client.Client
 }
 
 var _ admission.Defaulter[*workspacev1alpha1.Workspace] = &WorkspaceCustomDefaulter{}
@@ -284,6 +360,9 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, workspace *works
 		if req.Operation == "CREATE" {
 			workspace.Annotations[controller.AnnotationCreatedBy] = sanitizedUsername
 			workspacelog.Info("Added created-by annotation", "workspace", workspace.GetName(), "user", sanitizedUsername, "namespace", workspace.GetNamespace())
+
+			// Record the prefix of the child resource names, which must not change for the life of the workspace
+			workspace.Annotations[controller.AnnotationResourceNamePrefix] = controller.ConfiguredResourceNamePrefix()
 		}
 
 		// Always set last-updated-by (CREATE and UPDATE operations)
@@ -291,16 +370,46 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, workspace *works
 		workspacelog.Info("Added last-updated-by annotation", "workspace", workspace.GetName(), "user", sanitizedUsername, "namespace", workspace.GetNamespace())
 	}
 
+	// Copy the source workspace of a clone, beneath the explicit workspace fields and before the template is resolved
+	if err := d.cloneDefaulter.ApplyCloneSource(ctx, workspace); err != nil {
+		workspacelog.Error(err, "Failed to apply clone source", "workspace", workspace.GetName())
+		return fmt.Errorf("failed to apply clone source: %w", err)
+	}
+
 	// Apply template getter
 	if err := d.templateGetter.ApplyTemplateName(ctx, workspace); err != nil {
 		workspacelog.Error(err, "Failed to apply template reference", "workspace", workspace.GetName())
 		return fmt.Errorf("failed to apply template reference: %w", err)
 	}
 
+	// Apply the creator's preferences, beneath the explicit workspace fields and the template constraints
+	if err := d.userPreferencesDefaulter.ApplyUserPreferences(ctx, workspace); err != nil {
+		workspacelog.Error(err, "Failed to apply user preferences", "workspace", workspace.GetName())
+		return fmt.Errorf("failed to apply user preferences: %w", err)
+	}
+
+	// Reset the fields of a starting workspace that violate a template in Migrate mode, for the template defaults to refill
+	if err := d.templateMigrator.ApplyTemplateMigration(ctx, workspace); err != nil {
+		workspacelog.Error(err, "Failed to apply template migration", "workspace", workspace.GetName())
+		return fmt.Errorf("failed to apply template migration: %w", errs.ForUser(err))
+	}
+
+	// Apply the requests recommended by right-sizing to a starting workspace, within the template bounds
+	if err := d.rightSizingApplier.ApplyRightSizing(ctx, workspace); err != nil {
+		workspacelog.Error(err, "Failed to apply right-sizing recommendation", "workspace", workspace.GetName())
+		return fmt.Errorf("failed to apply right-sizing recommendation: %w", errs.ForUser(err))
+	}
+
 	// Apply template defaults
 	if err := d.templateDefaulter.ApplyTemplateDefaults(ctx, workspace); err != nil {
 		workspacelog.Error(err, "Failed to apply template defaults", "workspace", workspace.GetName())
-		return fmt.Errorf("failed to apply template defaults: %w", err)
+		return fmt.Errorf("failed to apply template defaults: %w", errs.ForUser(err))
+	}
+
+	// Map the creator to the UID and GID of the workspace, over the template and workspace security contexts
+	if err := d.identityMappingDefaulter.ApplyIdentityMapping(ctx, workspace); err != nil {
+		workspacelog.Error(err, "Failed to apply identity mapping", "workspace", workspace.GetName())
+		return fmt.Errorf("failed to apply identity mapping: %w", errs.ForUser(err))
 	}
 
 	// Apply service account defaults
@@ -322,7 +431,7 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, workspace *works
 			return err
 		}
 	}
-	if err := d.accessStrategyValidator.ValidateCreateWorkspace(workspace); err != nil {
+	if err := d.accessStrategyValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
 		return err
 	}
 
@@ -358,7 +467,18 @@ type WorkspaceCustomValidator struct {
 	accessStrategyValidator *AccessStrategyValidator
 	serviceAccountValidator *ServiceAccountValidator
 	volumeValidator         *VolumeValidator
+	customDomainValidator   *CustomDomainValidator
 	storageValidator        *StorageValidator
+	metadataLimitsValidator *MetadataLimitsValidator
+	quotaValidator          *QuotaValidator
+	podSecurityValidator    *PodSecurityValidator
+	ownershipValidator      *OwnershipValidator
+	cloneValidator          *CloneValidator
+	imageSignatureValidator *ImageSignatureValidator
+	externalPolicyValidator *ExternalPolicyValidator
+	templateAccessValidator *TemplateAccessValidator
+	// templateRequirementValidator may be nil, in which case workspaces never require a template
+	templateRequirementValidator *TemplateRequirementValidator
 }
 
 var _ admission.Validator[*workspacev1alpha1.Workspace] = &WorkspaceCustomValidator{}
@@ -373,7 +493,7 @@ func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, workspace
 	}
 
 	// Validate access strategy namespace scope
-	if err := v.accessStrategyValidator.ValidateCreateWorkspace(workspace); err != nil {
+	if err := v.accessStrategyValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
 		return nil, err
 	}
 
@@ -382,9 +502,64 @@ func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, workspace
 		return nil, err
 	}
 
+	// Validate that volume sub paths stay within their volume (applies to all users)
+	if err := validateVolumeSubPaths(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that no other workspace uses the custom domain (applies to all users)
+	if err := v.customDomainValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the authorized SSH keys (applies to all users)
+	if err := validateSSHPublicKeys(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that ownerGroup is set for GroupOnly workspaces (applies to all users)
+	if err := validateOwnerGroup(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that extraEnv only adds variables (applies to all users)
+	if err := validateExtraEnv(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that the workspace is not created with a pending ownership transfer (applies to all users)
+	if err := validateOwnerTransferOnCreate(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate pod overrides (security check - applies to all users, including template defaults)
+	if err := v.podSecurityValidator.ValidateCreateWorkspace(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the image signature (security check - applies to all users, when enabled)
+	if err := v.imageSignatureValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the snapshot to restore primary storage from (updates cannot add restoreFromSnapshot,
+	// so this only needs to run on create)
+	if err := v.storageValidator.ValidateRestoreFromSnapshot(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the source workspace of a clone and the user's access to it (updates cannot add
+	// cloneFrom, so this only needs to run on create; admins bypass the access check only)
+	if err := v.cloneValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Soft issues are reported to all users, without rejecting the workspace
+	warnings := v.templateValidator.WarnWorkspace(ctx, workspace)
+
 	// Controller or admin users bypass validation
 	if isControllerOrAdminUser(ctx) {
-		return nil, nil
+		return warnings, nil
 	}
 
 	// Validate no user-submitted reserved prefix labels/annotations
@@ -392,12 +567,37 @@ func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, workspace
 		return nil, err
 	}
 
+	// Validate label and annotation size and admin-reserved key prefixes
+	if err := v.metadataLimitsValidator.ValidateCreateWorkspace(workspace); err != nil {
+		return nil, err
+	}
+
 	// Validate service account access
 	if err := v.serviceAccountValidator.ValidateServiceAccountAccess(ctx, workspace); err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	// Validate that the workspace references a template, when the operator settings require one
+	if err := v.templateRequirementValidator.ValidateCreateWorkspace(workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that the user may use the template
+	if err := v.templateAccessValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the WorkspaceQuotas of the namespace
+	if err := v.quotaValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the policies of the external policy engine, when configured
+	if err := v.externalPolicyValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	return warnings, nil
 }
 
 // ValidateUpdate implements admission.Validator so a webhook will be registered for the type Workspace.
@@ -410,15 +610,63 @@ func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldWorksp
 		return nil, nil
 	}
 
+	// Validate that no other workspace uses the custom domain (applies to all users)
+	if err := v.customDomainValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the authorized SSH keys (applies to all users)
+	if err := validateSSHPublicKeys(newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that ownerGroup is set for GroupOnly workspaces (applies to all users)
+	if err := validateOwnerGroup(newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that extraEnv only adds variables (applies to all users)
+	if err := validateExtraEnv(newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that volume sub paths stay within their volume (applies to all users)
+	if err := validateVolumeSubPaths(newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate pod overrides (security check - applies to all users, including template defaults)
+	if err := v.podSecurityValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that the update does not add a snapshot to restore primary storage from (applies to all users)
+	if err := validateRestoreFromSnapshotNotAdded(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate that the update does not add a clone source (applies to all users)
+	if err := v.cloneValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the signature of a changed image (security check - applies to all users, when enabled)
+	if err := v.imageSignatureValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
 	// Controller or admin users bypass validation
 	isAdmin := isControllerOrAdminUser(ctx)
 
 	// NOTE: Removed templateRef immutability check to enable template mutability (PR #129)
 	// Templates can now be changed after workspace creation
 
+	// Soft issues are reported to all users, without rejecting the update
+	warnings := v.templateValidator.WarnWorkspace(ctx, newWorkspace)
+
 	// Admin users bypass user validation
 	if isAdmin {
-		return nil, nil
+		return warnings, nil
 	}
 
 	// Validate no user modifications to reserved prefix labels/annotations
@@ -426,27 +674,46 @@ func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldWorksp
 		return nil, err
 	}
 
+	// Validate that only admins request an ownership transfer
+	if err := validateOwnerTransfer(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate label and annotation size and admin-reserved key prefixes
+	if err := v.metadataLimitsValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
 	// Validate service account access for new workspace
 	if err := v.serviceAccountValidator.ValidateServiceAccountAccess(ctx, newWorkspace); err != nil {
 		return nil, err
 	}
 
+	// Validate that the update keeps the template, when the operator settings require one
+	if err := v.templateRequirementValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
 	originalOwnershipType := getEffectiveOwnershipType(oldWorkspace.Spec.OwnershipType)
 	newOwnershipType := getEffectiveOwnershipType(newWorkspace.Spec.OwnershipType)
 	workspacelog.Info("Ownership validation check", "originalType", originalOwnershipType, "newType", newOwnershipType)
-	// For OwnerOnly workspaces, check if user has permission
-	if originalOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
-		// Existing OwnerOnly workspace - check against old workspace
-		if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
-			return nil, err
-		}
-	} else if newOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
-		// Changing to OwnerOnly - only allow if user is the original creator
+	// For OwnerOnly and GroupOnly workspaces, check if user has permission against the old workspace
+	if err := v.ownershipValidator.ValidateModifyPermission(ctx, oldWorkspace); err != nil {
+		return nil, err
+	}
+	// Changing to OwnerOnly or GroupOnly, or changing the owner group - only allow if user is the original creator
+	if newOwnershipType != webhookconst.OwnershipTypePublic &&
+		(newOwnershipType != originalOwnershipType || newWorkspace.Spec.OwnerGroup != oldWorkspace.Spec.OwnerGroup) {
 		if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
 			return nil, err
 		}
 	}
 
+	// Validate that the user may use a new template
+	if err := v.templateAccessValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
 	// Validate template constraints for new workspace (only changed fields)
 	if err := v.templateValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
 		return nil, err
@@ -461,7 +728,7 @@ func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldWorksp
 	}
 
 	// Validate access strategy namespace scope
-	if err := v.accessStrategyValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
+	if err := v.accessStrategyValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
 		return nil, err
 	}
 
@@ -470,7 +737,17 @@ func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldWorksp
 		return nil, err
 	}
 
-	return nil, nil
+	// Validate the WorkspaceQuotas of the namespace
+	if err := v.quotaValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	// Validate the policies of the external policy engine, when configured
+	if err := v.externalPolicyValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	return warnings, nil
 }
 
 // ValidateDelete implements admission.Validator so a webhook will be registered for the type Workspace.
@@ -482,12 +759,9 @@ func (v *WorkspaceCustomValidator) ValidateDelete(ctx context.Context, workspace
 		return nil, nil
 	}
 
-	// For OwnerOnly workspaces, check if user has permission
-	effectiveOwnershipType := getEffectiveOwnershipType(workspace.Spec.OwnershipType)
-	if effectiveOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
-		if err := validateOwnershipPermission(ctx, workspace); err != nil {
-			return nil, err
-		}
+	// For OwnerOnly and GroupOnly workspaces, check if user has permission
+	if err := v.ownershipValidator.ValidateModifyPermission(ctx, workspace); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -23,6 +23,15 @@ import (
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
+// newIndexedClientBuilder returns a fake client builder with the field indexes of the manager cache
+func newIndexedClientBuilder(scheme *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range workspaceutil.FieldIndexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	return builder
+}
+
 // Mock client implementations to help with testing
 type fakeClientWithError struct {
 	client.Client
@@ -95,8 +104,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template).
 				Build()
 
@@ -140,8 +148,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace).
 				Build()
 
@@ -190,8 +197,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace).
 				Build()
 
@@ -237,8 +243,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace).
 				Build()
 
@@ -254,8 +259,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 		})
 
 		It("should skip finalizer addition when template does not exist", func() {
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				Build()
 
 			// Should not error when template doesn't exist
@@ -294,8 +298,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace).
 				Build()
 
@@ -359,8 +362,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace1, workspace2).
 				Build()
 
@@ -394,8 +396,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			getCalled := false
 
 			// Use fake client with a spy for Get
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace).
 				Build()
 
@@ -446,8 +447,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			updateCalled := false
 
 			// Create a client
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace).
 				Build()
 
@@ -513,8 +513,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace).
 				Build()
 
@@ -543,8 +542,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			}
 
 			// Create an empty client with only the workspace
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace).
 				Build()
 
@@ -594,8 +592,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 
 			// Create a client with a spy for Update
 			updateCalled := false
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace, accessStrategy).
 				Build()
 
@@ -652,8 +649,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			updateCalled := false
 			var updatedObj client.Object
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace, accessStrategy).
 				Build()
 
@@ -716,8 +712,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			updateCalled := false
 			var updatedObj client.Object
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace, accessStrategy).
 				Build()
 
@@ -776,8 +771,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 			}
 
 			// Create a client that will fail on Update
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(workspace, accessStrategy).
 				Build()
 
@@ -808,12 +802,15 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 
 		newDefaulter := func(k8sClient client.Client) WorkspaceCustomDefaulter {
 			return WorkspaceCustomDefaulter{
-				templateDefaulter:       NewTemplateDefaulter(k8sClient, ""),
-				serviceAccountDefaulter: NewServiceAccountDefaulter(k8sClient),
-				templateGetter:          NewTemplateGetter(k8sClient, ""),
-				templateValidator:       NewTemplateValidator(k8sClient, ""),
-				accessStrategyValidator: NewAccessStrategyValidator(""),
-				client:                  k8sClient,
+				templateDefaulter:        NewTemplateDefaulter(k8sClient, ""),
+				templateMigrator:         NewTemplateMigrator(k8sClient, ""),
+				identityMappingDefaulter: NewIdentityMappingDefaulter(k8sClient, k8sClient, ""),
+				userPreferencesDefaulter: NewUserPreferencesDefaulter(k8sClient, ""),
+				serviceAccountDefaulter:  NewServiceAccountDefaulter(k8sClient),
+				templateGetter:           NewTemplateGetter(k8sClient, ""),
+				templateValidator:        NewTemplateValidator(k8sClient, ""),
+				accessStrategyValidator:  NewAccessStrategyValidator("", nil, nil),
+				client:                   k8sClient,
 			}
 		}
 
@@ -844,8 +841,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, workspace).
 				Build()
 			defaulter = newDefaulter(k8sClient)
@@ -886,8 +882,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(accessStrategy, workspace).
 				Build()
 			defaulter = newDefaulter(k8sClient)
@@ -946,8 +941,7 @@ var _ = Describe("Lazy Finalizer Logic", func() {
 				},
 			}
 
-			k8sClient = fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient = newIndexedClientBuilder(scheme).
 				WithObjects(template, accessStrategy, workspace).
 				Build()
 			defaulter = newDefaulter(k8sClient)
@@ -126,17 +126,24 @@ var _ = Describe("Workspace Webhook", func() {
 
 		mockClient := &MockClient{}
 		defaulter = WorkspaceCustomDefaulter{
-			templateDefaulter:       NewTemplateDefaulter(mockClient, ""),
-			serviceAccountDefaulter: NewServiceAccountDefaulter(mockClient),
-			templateGetter:          NewTemplateGetter(mockClient, ""),
-			templateValidator:       NewTemplateValidator(mockClient, ""),
-			accessStrategyValidator: NewAccessStrategyValidator(""),
-			client:                  mockClient, // Add client field for testing
+			templateDefaulter:        NewTemplateDefaulter(mockClient, ""),
+			templateMigrator:         NewTemplateMigrator(mockClient, ""),
+			identityMappingDefaulter: NewIdentityMappingDefaulter(mockClient, mockClient, ""),
+			userPreferencesDefaulter: NewUserPreferencesDefaulter(mockClient, ""),
+			serviceAccountDefaulter:  NewServiceAccountDefaulter(mockClient),
+			templateGetter:           NewTemplateGetter(mockClient, ""),
+			templateValidator:        NewTemplateValidator(mockClient, ""),
+			accessStrategyValidator:  NewAccessStrategyValidator("", nil, nil),
+			client:                   mockClient, // Add client field for testing
 		}
 		validator = WorkspaceCustomValidator{
 			templateValidator:       NewTemplateValidator(mockClient, ""),
 			serviceAccountValidator: NewServiceAccountValidator(mockClient),
 			volumeValidator:         NewVolumeValidator(mockClient),
+			customDomainValidator:   NewCustomDomainValidator(mockClient),
+			metadataLimitsValidator: NewMetadataLimitsValidator(MetadataLimits{}),
+			quotaValidator:          NewQuotaValidator(mockClient),
+			podSecurityValidator:    NewPodSecurityValidator(false),
 		}
 		ctx = context.Background()
 	})
@@ -153,6 +160,17 @@ var _ = Describe("Workspace Webhook", func() {
 			Expect(workspace.Annotations[controller.AnnotationLastUpdatedBy]).To(Equal("test-user"))
 		})
 
+		It("should record the configured resource name prefix on create only", func() {
+			ctx = createUserContext(ctx, "CREATE", "test-user")
+			Expect(defaulter.Default(ctx, workspace)).To(Succeed())
+			Expect(workspace.Annotations[controller.AnnotationResourceNamePrefix]).To(Equal(controller.ConfiguredResourceNamePrefix()))
+
+			legacy := workspace.DeepCopy()
+			delete(legacy.Annotations, controller.AnnotationResourceNamePrefix)
+			Expect(defaulter.Default(createUserContext(context.Background(), "UPDATE", "test-user"), legacy)).To(Succeed())
+			Expect(legacy.Annotations).NotTo(HaveKey(controller.AnnotationResourceNamePrefix))
+		})
+
 		It("should not overwrite existing created-by annotation", func() {
 			workspace.Annotations = map[string]string{controller.AnnotationCreatedBy: testOriginalUser}
 			ctx = createUserContext(ctx, "UPDATE", "new-user")
@@ -357,6 +375,58 @@ var _ = Describe("Workspace Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 
+		It("should allow a non-admin user to request an access strategy migration", func() {
+			userCtx := createUserContext(ctx, "UPDATE", "test-user")
+			validator.accessStrategyValidator = NewAccessStrategyValidator(testSharedNamespace, nil, nil)
+
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Annotations = map[string]string{
+				controller.AnnotationAccessStrategyMigrationTarget: testSomeStrategy,
+			}
+
+			_, err := validator.ValidateUpdate(userCtx, oldWorkspace, workspace)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject an access strategy migration to another team's namespace", func() {
+			userCtx := createUserContext(ctx, "UPDATE", "test-user")
+			validator.accessStrategyValidator = NewAccessStrategyValidator(testSharedNamespace, nil, nil)
+
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Annotations = map[string]string{
+				controller.AnnotationAccessStrategyMigrationTarget: testNamespaceTeamB + "/" + testSomeStrategy,
+			}
+
+			_, err := validator.ValidateUpdate(userCtx, oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(controller.AnnotationAccessStrategyMigrationTarget))
+			Expect(err.Error()).To(ContainSubstring(testNamespaceTeamB))
+		})
+
+		It("should reject an update adding a snapshot to restore from, even by an admin", func() {
+			adminCtx := createUserContext(ctx, "UPDATE", "admin-user", webhookconst.DefaultAdminGroup)
+
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+				RestoreFromSnapshot: &workspacev1alpha1.SnapshotRef{Name: "nightly"},
+			}
+
+			_, err := validator.ValidateUpdate(adminCtx, oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.restoreFromSnapshot can only be set when the workspace is created"))
+		})
+
+		It("should reject an update adding a clone source, even by an admin", func() {
+			adminCtx := createUserContext(ctx, "UPDATE", "admin-user", webhookconst.DefaultAdminGroup)
+
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.CloneFrom = &workspacev1alpha1.CloneSource{Name: "source", IncludeStorage: true}
+
+			_, err := validator.ValidateUpdate(adminCtx, oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.cloneFrom can only be set when the workspace is created"))
+		})
+
 		It("should reject OwnerOnly workspace update by non-owner", func() {
 			userCtx := createUserContext(ctx, "UPDATE", "different-user")
 
@@ -519,6 +589,19 @@ var _ = Describe("Workspace Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 
+		It("should reject sub paths escaping their volume on update by admin", func() {
+			adminCtx := createUserContext(ctx, "UPDATE", "admin-user", "system:masters")
+
+			oldWorkspace := workspace.DeepCopy()
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Spec.Volumes = []workspacev1alpha1.VolumeSpec{
+				{Name: testVolumeNameData, PersistentVolumeClaimName: testPVCNameData, MountPath: testDataMountPath, SubPath: "../.."},
+			}
+
+			_, err := validator.ValidateUpdate(adminCtx, oldWorkspace, newWorkspace)
+			Expect(err).To(MatchError(ContainSubstring("cannot contain '..'")))
+		})
+
 		It("should reject update that removes created-by annotation", func() {
 			userCtx := createUserContext(ctx, "UPDATE", "different-user")
 
@@ -635,6 +718,64 @@ var _ = Describe("Workspace Webhook", func() {
 		})
 	})
 
+	Context("validateOwnerTransfer", func() {
+		var oldWorkspace, newWorkspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			oldWorkspace = workspace.DeepCopy()
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationCreatedBy: testOwnerUser,
+			}
+			newWorkspace = oldWorkspace.DeepCopy()
+			newWorkspace.Spec.OwnerTransferTo = "new-owner"
+		})
+
+		It("should deny a transfer requested by a user", func() {
+			err := validateOwnerTransfer(oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only an admin can transfer workspace ownership"))
+		})
+
+		It("should deny a user cancelling a pending transfer", func() {
+			oldWorkspace.Spec.OwnerTransferTo = "new-owner"
+			newWorkspace.Spec.OwnerTransferTo = ""
+			Expect(validateOwnerTransfer(oldWorkspace, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should ignore updates that leave the transfer unchanged", func() {
+			newWorkspace.Spec.OwnerTransferTo = ""
+			Expect(validateOwnerTransfer(oldWorkspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should deny the owner requesting a transfer through the validator", func() {
+			_, err := validator.ValidateUpdate(createUserContext(ctx, "UPDATE", testOwnerUser), oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only an admin can transfer workspace ownership"))
+		})
+
+		It("should allow an admin to request a transfer", func() {
+			adminCtx := createUserContext(ctx, "UPDATE", "admin-user", webhookconst.DefaultAdminGroup)
+			_, err := validator.ValidateUpdate(adminCtx, oldWorkspace, newWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a workspace created with a pending transfer", func() {
+			workspace.Spec.OwnerTransferTo = "victim"
+
+			_, err := validator.ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ownerTransferTo cannot be set when creating a workspace"))
+		})
+
+		It("should reject a workspace created by an admin with a pending transfer", func() {
+			workspace.Spec.OwnerTransferTo = "victim"
+			adminCtx := createUserContext(ctx, "CREATE", "admin-user", webhookconst.DefaultAdminGroup)
+
+			_, err := validator.ValidateCreate(adminCtx, workspace)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("Template Validator Functions", func() {
 		var template *workspacev1alpha1.WorkspaceTemplate
 
@@ -778,6 +919,74 @@ var _ = Describe("Workspace Webhook", func() {
 			})
 		})
 
+		Context("validateVolumeMounts", func() {
+			nfsVolume := func(server string) workspacev1alpha1.VolumeSpec {
+				return workspacev1alpha1.VolumeSpec{
+					Name:      testVolumeNameData,
+					NFS:       &workspacev1alpha1.NFSVolumeSource{Server: server, Path: "/exports"},
+					MountPath: testDataMountPath,
+					ReadOnly:  true,
+				}
+			}
+
+			It("should allow any mount path when the template allows all", func() {
+				volumes := []workspacev1alpha1.VolumeSpec{
+					{Name: testVolumeNameData, PersistentVolumeClaimName: testPVCNameData, MountPath: "/etc/data"},
+				}
+				Expect(validateVolumeMounts(volumes, template)).To(BeEmpty())
+			})
+
+			It("should only allow mount paths under the allowed paths", func() {
+				template.Spec.AllowedVolumeMountPaths = []string{"/data/"}
+				volumes := []workspacev1alpha1.VolumeSpec{
+					{Name: "datasets", PersistentVolumeClaimName: testPVCNameData, MountPath: "/data/datasets"},
+					{Name: "root", PersistentVolumeClaimName: testPVCNameData, MountPath: "/data"},
+					{Name: "database", PersistentVolumeClaimName: testPVCNameData, MountPath: "/database"},
+					{Name: "escape", PersistentVolumeClaimName: testPVCNameData, MountPath: "/data/../etc"},
+				}
+				violations := validateVolumeMounts(volumes, template)
+				Expect(violations).To(HaveLen(2))
+				Expect(violations[0].Type).To(Equal(ViolationTypeVolumeMountPathNotAllowed))
+				Expect(violations[0].Field).To(Equal("spec.volumes[database].mountPath"))
+				Expect(violations[1].Field).To(Equal("spec.volumes[escape].mountPath"))
+			})
+
+			It("should reject NFS volumes when the template allows no server", func() {
+				violations := validateVolumeMounts([]workspacev1alpha1.VolumeSpec{nfsVolume("nfs.example.com")}, template)
+				Expect(violations).To(HaveLen(1))
+				Expect(violations[0].Type).To(Equal(ViolationTypeNFSServerNotAllowed))
+				Expect(violations[0].Allowed).To(Equal("no NFS server"))
+			})
+
+			It("should allow NFS volumes of an allowed server", func() {
+				template.Spec.AllowedNFSServers = []string{"nfs.example.com"}
+				Expect(validateVolumeMounts([]workspacev1alpha1.VolumeSpec{nfsVolume("nfs.example.com")}, template)).To(BeEmpty())
+			})
+
+			It("should reject default volumes that the template constraints reject", func() {
+				template.Spec.DefaultVolumes = []workspacev1alpha1.VolumeSpec{nfsVolume("nfs.example.com")}
+				Expect(validateTemplateVolumeConsistency(template)).To(MatchError(ContainSubstring("defaultVolumes")))
+			})
+
+			It("should reject NFS volumes on a workspace without template", func() {
+				workspace := &workspacev1alpha1.Workspace{
+					Spec: workspacev1alpha1.WorkspaceSpec{Volumes: []workspacev1alpha1.VolumeSpec{nfsVolume("nfs.example.com")}},
+				}
+				Expect(validateNFSVolumesRequireTemplate(workspace)).To(MatchError(ContainSubstring("requires a templateRef")))
+			})
+
+			It("should reject sub paths escaping their volume", func() {
+				workspace := &workspacev1alpha1.Workspace{
+					Spec: workspacev1alpha1.WorkspaceSpec{Volumes: []workspacev1alpha1.VolumeSpec{
+						{Name: testVolumeNameData, PersistentVolumeClaimName: testPVCNameData, MountPath: testDataMountPath, SubPath: "team/../.."},
+					}},
+				}
+				Expect(validateVolumeSubPaths(workspace)).To(MatchError(ContainSubstring("cannot contain '..'")))
+				workspace.Spec.Volumes[0].SubPath = "team/..datasets"
+				Expect(validateVolumeSubPaths(workspace)).To(Succeed())
+			})
+		})
+
 		// StorageValidator storage-shrink validation is covered in storage_validator_test.go.
 	})
 
@@ -904,8 +1113,12 @@ var _ = Describe("Workspace Webhook", func() {
 
 			// Create validator with template validator initialized
 			validatorWithTemplate = &WorkspaceCustomValidator{
-				templateValidator: NewTemplateValidator(k8sClient, testDefaultNamespace),
-				volumeValidator:   NewVolumeValidator(k8sClient),
+				templateValidator:       NewTemplateValidator(k8sClient, testDefaultNamespace),
+				volumeValidator:         NewVolumeValidator(k8sClient),
+				customDomainValidator:   NewCustomDomainValidator(k8sClient),
+				metadataLimitsValidator: NewMetadataLimitsValidator(MetadataLimits{}),
+				quotaValidator:          NewQuotaValidator(k8sClient),
+				podSecurityValidator:    NewPodSecurityValidator(false),
 			}
 		})
 
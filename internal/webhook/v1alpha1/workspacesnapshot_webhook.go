@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
+)
+
+var snapshotlog = logf.Log.WithName("workspacesnapshot-resource")
+
+// SetupWorkspaceSnapshotWebhookWithManager registers the webhook for WorkspaceSnapshot in the manager.
+// groupResolver may be nil, in which case the membership of GroupOnly owner groups is only read
+// from the admission request.
+func SetupWorkspaceSnapshotWebhookWithManager(mgr ctrl.Manager, groupResolver GroupResolverInterface) error {
+	return ctrl.NewWebhookManagedBy(mgr, &workspacev1alpha1.WorkspaceSnapshot{}).
+		WithValidator(&WorkspaceSnapshotCustomValidator{
+			client:             mgr.GetClient(),
+			ownershipValidator: NewOwnershipValidator(groupResolver),
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspacesnapshot,mutating=false,failurePolicy=fail,sideEffects=None,groups=workspace.jupyter.org,resources=workspacesnapshots,verbs=create;update,versions=v1alpha1,name=vworkspacesnapshot-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+
+// WorkspaceSnapshotCustomValidator checks that the user creating a WorkspaceSnapshot may read the
+// data of the workspace it snapshots. A snapshot copies the workspace volume, and anyone allowed to
+// create workspaces in the namespace can restore it, so the creator needs the same permission the
+// workspace's ownershipType and accessType grant to clone it.
+//
+// The created-by annotation of a snapshot names the owner of its data, who may still restore it once
+// the workspace is deleted. Users may only name themselves in it, and only admins may change it.
+//
+// Uses failurePolicy: Fail, like the Workspace validating webhook, so that snapshots are never
+// admitted without the check.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type WorkspaceSnapshotCustomValidator struct {
+	client             client.Client
+	ownershipValidator *OwnershipValidator
+}
+
+var _ admission.Validator[*workspacev1alpha1.WorkspaceSnapshot] = &WorkspaceSnapshotCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type WorkspaceSnapshot.
+// The controller and admins bypass the access check.
+func (v *WorkspaceSnapshotCustomValidator) ValidateCreate(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) (admission.Warnings, error) {
+	snapshotlog.Info("Validation for WorkspaceSnapshot upon creation", "name", snapshot.GetName(), "namespace", snapshot.GetNamespace())
+	if isControllerOrAdminUser(ctx) {
+		return nil, nil
+	}
+
+	if owner, ok := snapshot.Annotations[controller.AnnotationCreatedBy]; ok && !isSnapshotOwner(ctx, snapshot) {
+		return nil, fmt.Errorf("annotation %s must name the user creating the workspace snapshot, got %q",
+			controller.AnnotationCreatedBy, owner)
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	err := v.client.Get(ctx, types.NamespacedName{Name: snapshot.Spec.WorkspaceName, Namespace: snapshot.Namespace}, workspace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("workspace %q not found in namespace %q", snapshot.Spec.WorkspaceName, snapshot.Namespace)
+		}
+		return nil, fmt.Errorf("unable to validate workspace %q: %w", snapshot.Spec.WorkspaceName, err)
+	}
+	return nil, v.ownershipValidator.ValidateDataAccess(ctx, workspace, "snapshot")
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type WorkspaceSnapshot.
+// spec.workspaceName is immutable, so the access check made on create still holds; only changes of
+// the owner recorded in the created-by annotation are checked.
+func (v *WorkspaceSnapshotCustomValidator) ValidateUpdate(ctx context.Context, oldSnapshot, newSnapshot *workspacev1alpha1.WorkspaceSnapshot) (admission.Warnings, error) {
+	if oldSnapshot.Annotations[controller.AnnotationCreatedBy] == newSnapshot.Annotations[controller.AnnotationCreatedBy] ||
+		isControllerOrAdminUser(ctx) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("access denied: only an admin can change annotation %s of a workspace snapshot",
+		controller.AnnotationCreatedBy)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type WorkspaceSnapshot.
+func (v *WorkspaceSnapshotCustomValidator) ValidateDelete(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// isSnapshotOwner returns whether the user of the admission request is the owner recorded in the
+// created-by annotation of the snapshot
+func isSnapshotOwner(ctx context.Context, snapshot *workspacev1alpha1.WorkspaceSnapshot) bool {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	owner := snapshot.Annotations[controller.AnnotationCreatedBy]
+	return owner != "" && owner == stringutil.SanitizeUsername(req.UserInfo.Username)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("WorkspaceSnapshot Webhook", func() {
+	var (
+		ctx       context.Context
+		scheme    *runtime.Scheme
+		workspace *workspacev1alpha1.Workspace
+		snapshot  *workspacev1alpha1.WorkspaceSnapshot
+	)
+
+	newValidator := func(objects ...client.Object) *WorkspaceSnapshotCustomValidator {
+		return &WorkspaceSnapshotCustomValidator{
+			client:             fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			ownershipValidator: NewOwnershipValidator(nil),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "source",
+				Namespace:   testNamespaceTeamA,
+				Annotations: map[string]string{controller.AnnotationCreatedBy: testOwnerUser},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				OwnershipType: webhookconst.OwnershipTypePublic,
+				AccessType:    webhookconst.OwnershipTypePublic,
+			},
+		}
+		snapshot = &workspacev1alpha1.WorkspaceSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: testNamespaceTeamA},
+			Spec:       workspacev1alpha1.WorkspaceSnapshotSpec{WorkspaceName: "source"},
+		}
+	})
+
+	It("should admit a snapshot of a Public workspace", func() {
+		_, err := newValidator(workspace).ValidateCreate(createUserContext(ctx, "CREATE", "team-member"), snapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a snapshot of a missing workspace", func() {
+		_, err := newValidator().ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), snapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`workspace "source" not found in namespace "team-a"`))
+	})
+
+	It("should reject a snapshot of an OwnerOnly workspace by another user", func() {
+		workspace.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+		validator := newValidator(workspace)
+
+		_, err := validator.ValidateCreate(createUserContext(ctx, "CREATE", "outsider"), snapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot snapshot workspace team-a/source"))
+
+		_, err = validator.ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), snapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a snapshot of a workspace with OwnerOnly access by another user", func() {
+		workspace.Spec.AccessType = webhookconst.OwnershipTypeOwnerOnly
+
+		_, err := newValidator(workspace).ValidateCreate(createUserContext(ctx, "CREATE", "outsider"), snapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("only the owner of workspace team-a/source can snapshot it"))
+	})
+
+	It("should only let users name themselves as the owner of a snapshot", func() {
+		snapshot.Annotations = map[string]string{controller.AnnotationCreatedBy: testOwnerUser}
+		validator := newValidator(workspace)
+
+		_, err := validator.ValidateCreate(createUserContext(ctx, "CREATE", "team-member"), snapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must name the user creating the workspace snapshot"))
+
+		_, err = validator.ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), snapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only let admins change the owner of a snapshot", func() {
+		updated := snapshot.DeepCopy()
+		updated.Annotations = map[string]string{controller.AnnotationCreatedBy: "team-member"}
+		validator := newValidator(workspace)
+
+		_, err := validator.ValidateUpdate(createUserContext(ctx, "UPDATE", "team-member"), snapshot, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("only an admin can change annotation"))
+
+		adminCtx := createUserContext(ctx, "UPDATE", "admin", webhookconst.DefaultAdminGroup)
+		_, err = validator.ValidateUpdate(adminCtx, snapshot, updated)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should let admins snapshot any workspace", func() {
+		workspace.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+		adminCtx := createUserContext(ctx, "CREATE", "admin", webhookconst.DefaultAdminGroup)
+
+		_, err := newValidator(workspace).ValidateCreate(adminCtx, snapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
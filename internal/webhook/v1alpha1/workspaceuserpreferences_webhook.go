@@ -0,0 +1,119 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
+)
+
+var userpreferenceslog = logf.Log.WithName("workspaceuserpreferences-resource")
+
+// SetupWorkspaceUserPreferencesWebhookWithManager registers the webhook for WorkspaceUserPreferences in the manager.
+func SetupWorkspaceUserPreferencesWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &workspacev1alpha1.WorkspaceUserPreferences{}).
+		WithValidator(&WorkspaceUserPreferencesCustomValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspaceuserpreferences,mutating=false,failurePolicy=fail,sideEffects=None,groups=workspace.jupyter.org,resources=workspaceuserpreferences,verbs=create;update,versions=v1alpha1,name=vworkspaceuserpreferences-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+
+// WorkspaceUserPreferencesCustomValidator checks that users only set their own preferences.
+// The workspace defaulting webhook applies the preferences whose spec.user matches the creator of a
+// workspace, so preferences naming another user would set the image and resources of that user's
+// workspaces. Users may therefore only name themselves in spec.user, and each user has at most one
+// WorkspaceUserPreferences per namespace. Admins may manage the preferences of any user.
+//
+// Uses failurePolicy: Fail, like the Workspace validating webhook, so that preferences are never
+// admitted without the check.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type WorkspaceUserPreferencesCustomValidator struct {
+	client client.Client
+}
+
+var _ admission.Validator[*workspacev1alpha1.WorkspaceUserPreferences] = &WorkspaceUserPreferencesCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type WorkspaceUserPreferences.
+func (v *WorkspaceUserPreferencesCustomValidator) ValidateCreate(ctx context.Context, preferences *workspacev1alpha1.WorkspaceUserPreferences) (admission.Warnings, error) {
+	userpreferenceslog.Info("Validation for WorkspaceUserPreferences upon creation", "name", preferences.GetName(), "namespace", preferences.GetNamespace())
+
+	// Validate that the user has no other preferences in the namespace (applies to all users)
+	if err := v.validateUniqueUser(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	// Controller or admin users bypass validation
+	if isControllerOrAdminUser(ctx) {
+		return nil, nil
+	}
+	return nil, validatePreferencesUser(ctx, preferences)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type WorkspaceUserPreferences.
+// Users may only update their own preferences, and may not hand them to another user.
+func (v *WorkspaceUserPreferencesCustomValidator) ValidateUpdate(ctx context.Context, oldPreferences, newPreferences *workspacev1alpha1.WorkspaceUserPreferences) (admission.Warnings, error) {
+	userpreferenceslog.Info("Validation for WorkspaceUserPreferences upon update", "name", newPreferences.GetName(), "namespace", newPreferences.GetNamespace())
+
+	// Validate that the user has no other preferences in the namespace (applies to all users)
+	if newPreferences.Spec.User != oldPreferences.Spec.User {
+		if err := v.validateUniqueUser(ctx, newPreferences); err != nil {
+			return nil, err
+		}
+	}
+
+	// Controller or admin users bypass validation
+	if isControllerOrAdminUser(ctx) {
+		return nil, nil
+	}
+	if err := validatePreferencesUser(ctx, oldPreferences); err != nil {
+		return nil, err
+	}
+	return nil, validatePreferencesUser(ctx, newPreferences)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type WorkspaceUserPreferences.
+func (v *WorkspaceUserPreferencesCustomValidator) ValidateDelete(ctx context.Context, preferences *workspacev1alpha1.WorkspaceUserPreferences) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validatePreferencesUser checks that spec.user names the user of the admission request, sanitized
+// the same way as the created-by annotation of the workspaces it is matched against
+func validatePreferencesUser(ctx context.Context, preferences *workspacev1alpha1.WorkspaceUserPreferences) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to extract user information from request")
+	}
+	if preferences.Spec.User != stringutil.SanitizeUsername(req.UserInfo.Username) {
+		return fmt.Errorf("access denied: spec.user %q of workspace user preferences %q must name the user managing them",
+			preferences.Spec.User, preferences.Name)
+	}
+	return nil
+}
+
+// validateUniqueUser rejects preferences for a user who already has other preferences in the namespace
+func (v *WorkspaceUserPreferencesCustomValidator) validateUniqueUser(ctx context.Context, preferences *workspacev1alpha1.WorkspaceUserPreferences) error {
+	list := &workspacev1alpha1.WorkspaceUserPreferencesList{}
+	if err := v.client.List(ctx, list, client.InNamespace(preferences.Namespace)); err != nil {
+		return fmt.Errorf("unable to validate workspace user preferences: %w", err)
+	}
+	for _, existing := range list.Items {
+		if existing.Name != preferences.Name && existing.Spec.User == preferences.Spec.User {
+			return fmt.Errorf("user %q already has workspace user preferences %q in namespace %q",
+				preferences.Spec.User, existing.Name, preferences.Namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("WorkspaceUserPreferences Webhook", func() {
+	var (
+		ctx         context.Context
+		scheme      *runtime.Scheme
+		preferences *workspacev1alpha1.WorkspaceUserPreferences
+	)
+
+	newValidator := func(objects ...client.Object) *WorkspaceUserPreferencesCustomValidator {
+		return &WorkspaceUserPreferencesCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		preferences = &workspacev1alpha1.WorkspaceUserPreferences{
+			ObjectMeta: metav1.ObjectMeta{Name: "owner-preferences", Namespace: testNamespaceTeamA},
+			Spec: workspacev1alpha1.WorkspaceUserPreferencesSpec{
+				User:     testOwnerUser,
+				Timezone: "Europe/Paris",
+			},
+		}
+	})
+
+	It("should admit preferences of the user creating them", func() {
+		_, err := newValidator().ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), preferences)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject preferences for another user", func() {
+		_, err := newValidator().ValidateCreate(createUserContext(ctx, "CREATE", "outsider"), preferences)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must name the user managing them"))
+	})
+
+	It("should let admins create preferences for any user", func() {
+		adminCtx := createUserContext(ctx, "CREATE", "admin", webhookconst.DefaultAdminGroup)
+		_, err := newValidator().ValidateCreate(adminCtx, preferences)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a second preferences object for the same user, even by an admin", func() {
+		existing := preferences.DeepCopy()
+		existing.Name = "aaa-preferences"
+		validator := newValidator(existing)
+
+		_, err := validator.ValidateCreate(createUserContext(ctx, "CREATE", testOwnerUser), preferences)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`already has workspace user preferences "aaa-preferences"`))
+
+		adminCtx := createUserContext(ctx, "CREATE", "admin", webhookconst.DefaultAdminGroup)
+		_, err = validator.ValidateCreate(adminCtx, preferences)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should let users update their own preferences", func() {
+		updated := preferences.DeepCopy()
+		updated.Spec.Timezone = "UTC"
+
+		_, err := newValidator(preferences).ValidateUpdate(createUserContext(ctx, "UPDATE", testOwnerUser), preferences, updated)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject updates of the preferences of another user", func() {
+		updated := preferences.DeepCopy()
+		updated.Spec.Image = "attacker/image:latest"
+
+		_, err := newValidator(preferences).ValidateUpdate(createUserContext(ctx, "UPDATE", "outsider"), preferences, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must name the user managing them"))
+	})
+
+	It("should reject users handing their preferences to another user", func() {
+		updated := preferences.DeepCopy()
+		updated.Spec.User = "victim"
+
+		_, err := newValidator(preferences).ValidateUpdate(createUserContext(ctx, "UPDATE", testOwnerUser), preferences, updated)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`spec.user "victim"`))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// JupyterBaseURLEnvVar is the environment variable holding the base URL of a Jupyter server, which
+// access strategies set to the path prefix the workspace is routed under
+const JupyterBaseURLEnvVar = "JUPYTER_BASE_URL"
+
+// Application describes how the workspace container serves an application type
+type Application struct {
+	// Type of the application
+	Type workspacev1alpha1.ApplicationType
+
+	// Port the application listens on in the workspace pod
+	Port int32
+
+	// ProbePath is the path of the health endpoint hit by the default probes
+	ProbePath string
+
+	// ServesBaseURL is whether the application serves its endpoints under its base URL, like
+	// Jupyter does, rather than at the root behind a proxy that strips the base URL
+	ServesBaseURL bool
+
+	// BaseURLEnvVar is the environment variable the application reads its base URL from
+	BaseURLEnvVar string
+
+	// StopCommand gracefully stops the application before the pod is terminated, nil when the
+	// application has none
+	StopCommand []string
+}
+
+// applications lists the applications of each application type
+var applications = map[workspacev1alpha1.ApplicationType]Application{
+	workspacev1alpha1.ApplicationTypeJupyterLab: {
+		Type:          workspacev1alpha1.ApplicationTypeJupyterLab,
+		Port:          8888,
+		ProbePath:     "/api/status",
+		ServesBaseURL: true,
+		BaseURLEnvVar: JupyterBaseURLEnvVar,
+		StopCommand:   []string{"jupyter", "server", "stop", "8888"},
+	},
+	workspacev1alpha1.ApplicationTypeCodeServer: {
+		Type:          workspacev1alpha1.ApplicationTypeCodeServer,
+		Port:          8080,
+		ProbePath:     "/healthz",
+		BaseURLEnvVar: "CODE_SERVER_BASE_URL",
+	},
+	workspacev1alpha1.ApplicationTypeRStudio: {
+		Type:          workspacev1alpha1.ApplicationTypeRStudio,
+		Port:          8787,
+		ProbePath:     "/health-check",
+		BaseURLEnvVar: "RSTUDIO_BASE_URL",
+	},
+	workspacev1alpha1.ApplicationTypeCustom: {
+		Type:          workspacev1alpha1.ApplicationTypeCustom,
+		Port:          8888,
+		ProbePath:     "/",
+		BaseURLEnvVar: JupyterBaseURLEnvVar,
+	},
+}
+
+// GetApplication returns the application served by a workspace, JupyterLab unless
+// spec.applicationType says otherwise
+func GetApplication(workspace *workspacev1alpha1.Workspace) Application {
+	if application, ok := applications[workspace.Spec.ApplicationType]; ok {
+		return application
+	}
+	return applications[workspacev1alpha1.ApplicationTypeJupyterLab]
+}
+
+// RoutePath returns the subpath access strategies route to an additional port of the workspace,
+// relative to its base path: the path of the port, /<name>/ by default
+func RoutePath(port workspacev1alpha1.WorkspacePort) string {
+	if port.Path != "" {
+		return port.Path
+	}
+	return "/" + port.Name + "/"
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Fields by which workspaces and templates are listed. Each is both a field index of the manager
+// cache and a selectable field of the CRD, so that lists by the field are served alike by the cache
+// and, for paginated lists, by the API server.
+const (
+	// WorkspaceTemplateRefNameField selects the workspaces by the name of the template they reference
+	WorkspaceTemplateRefNameField = "spec.templateRef.name"
+	// WorkspaceAccessStrategyNameField selects the workspaces by the name of the access strategy they reference
+	WorkspaceAccessStrategyNameField = "spec.accessStrategy.name"
+	// TemplateDefaultAccessStrategyNameField selects the templates by the name of their default access strategy
+	TemplateDefaultAccessStrategyNameField = "spec.defaultAccessStrategy.name"
+)
+
+// FieldIndex is a field index of the manager cache
+type FieldIndex struct {
+	Object  client.Object
+	Field   string
+	Extract client.IndexerFunc
+}
+
+// FieldIndexes returns the field indexes the queries of this package list by
+func FieldIndexes() []FieldIndex {
+	return []FieldIndex{
+		{
+			Object: &workspacev1alpha1.Workspace{},
+			Field:  WorkspaceTemplateRefNameField,
+			Extract: func(obj client.Object) []string {
+				ws := obj.(*workspacev1alpha1.Workspace)
+				if ws.Spec.TemplateRef == nil || ws.Spec.TemplateRef.Name == "" {
+					return nil
+				}
+				return []string{ws.Spec.TemplateRef.Name}
+			},
+		},
+		{
+			Object: &workspacev1alpha1.Workspace{},
+			Field:  WorkspaceAccessStrategyNameField,
+			Extract: func(obj client.Object) []string {
+				ws := obj.(*workspacev1alpha1.Workspace)
+				if ws.Spec.AccessStrategy == nil || ws.Spec.AccessStrategy.Name == "" {
+					return nil
+				}
+				return []string{ws.Spec.AccessStrategy.Name}
+			},
+		},
+		{
+			Object: &workspacev1alpha1.WorkspaceTemplate{},
+			Field:  TemplateDefaultAccessStrategyNameField,
+			Extract: func(obj client.Object) []string {
+				template := obj.(*workspacev1alpha1.WorkspaceTemplate)
+				if template.Spec.DefaultAccessStrategy == nil || template.Spec.DefaultAccessStrategy.Name == "" {
+					return nil
+				}
+				return []string{template.Spec.DefaultAccessStrategy.Name}
+			},
+		},
+	}
+}
+
+// SetupFieldIndexes registers the field indexes of FieldIndexes with the manager cache.
+// It must be called before the manager starts.
+func SetupFieldIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, index := range FieldIndexes() {
+		if err := indexer.IndexField(ctx, index.Object, index.Field, index.Extract); err != nil {
+			return fmt.Errorf("failed to index %s: %w", index.Field, err)
+		}
+	}
+	return nil
+}
@@ -34,15 +34,21 @@ func GetAccessStrategyRefNamespace(ws *workspacev1alpha1.Workspace) string {
 	return ws.Spec.AccessStrategy.Namespace
 }
 
+// IsAccessEnabled returns whether the workspace can be accessed; access is enabled unless
+// spec.accessEnabled is explicitly false
+func IsAccessEnabled(ws *workspacev1alpha1.Workspace) bool {
+	return ws.Spec.AccessEnabled == nil || *ws.Spec.AccessEnabled
+}
+
 // ListActiveWorkspacesByTemplate returns all active (non-deleted) workspaces using the specified template.
-// Reads from controller-runtime's informer cache (not direct API calls), providing efficient lookup
-// with eventual consistency guarantees. Filters out workspaces being deleted (DeletionTimestamp set).
-// Validates templateRef matches label to guard against drift.
+// Lists by WorkspaceTemplateRefNameField, from the field index of the informer cache or from the API
+// server for paginated lists. Filters out workspaces being deleted (DeletionTimestamp set) and
+// workspaces referencing a template of the same name in another namespace.
 // If templateNamespace is empty, it acts as a wildcard (backwards compatible).
 // Supports pagination for large-scale deployments via continueToken and limit parameters.
 func ListActiveWorkspacesByTemplate(
 	ctx context.Context,
-	k8sClient client.Client,
+	k8sClient client.Reader,
 	templateName string,
 	templateNamespace string,
 	continueToken string,
@@ -51,14 +57,8 @@ func ListActiveWorkspacesByTemplate(
 
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
 
-	// Build label selector
-	labels := map[string]string{
-		LabelWorkspaceTemplate:          templateName,
-		LabelWorkspaceTemplateNamespace: templateNamespace,
-	}
-
 	listOptions := []client.ListOption{
-		client.MatchingLabels(labels),
+		client.MatchingFields{WorkspaceTemplateRefNameField: templateName},
 	}
 
 	// Add pagination options if specified
@@ -70,7 +70,7 @@ func ListActiveWorkspacesByTemplate(
 	}
 
 	if err := k8sClient.List(ctx, workspaceList, listOptions...); err != nil {
-		return nil, "", fmt.Errorf("failed to list workspaces by template label: %w", err)
+		return nil, "", fmt.Errorf("failed to list workspaces by template reference: %w", err)
 	}
 
 	// Filter out workspaces being deleted and verify template reference
@@ -82,23 +82,8 @@ func ListActiveWorkspacesByTemplate(
 			continue // Skip workspaces being deleted
 		}
 
-		// Verify templateRef matches label to guard against label/spec mismatch.
-		// This is somewhat redundant given CEL immutability validation but adds zero cost and adds a layer of verification.
-		if ws.Spec.TemplateRef == nil {
-			logger.Info("Workspace has template label but nil templateRef - data integrity issue",
-				"workspace", ws.Name,
-				"workspaceNamespace", ws.Namespace,
-				"label", templateName)
-			continue
-		}
-
-		if ws.Spec.TemplateRef.Name != templateName {
-			// This should never happen - log if it occurs
-			logger.Info("Workspace has template label but different templateRef name",
-				"workspace", ws.Name,
-				"workspaceNamespace", ws.Namespace,
-				"label", templateName,
-				"spec", ws.Spec.TemplateRef.Name)
+		// Guard against readers that ignore the field selector
+		if ws.Spec.TemplateRef == nil || ws.Spec.TemplateRef.Name != templateName {
 			continue
 		}
 
@@ -106,10 +91,10 @@ func ListActiveWorkspacesByTemplate(
 		if templateNamespace != "" {
 			actualNamespace := GetTemplateRefNamespace(&ws)
 			if actualNamespace != templateNamespace {
-				logger.V(1).Info("Workspace has template label but different namespace",
+				logger.V(1).Info("Workspace references a template of the same name in another namespace",
 					"workspace", ws.Name,
 					"workspaceNamespace", ws.Namespace,
-					"labelNamespace", templateNamespace,
+					"templateNamespace", templateNamespace,
 					"specNamespace", actualNamespace)
 				continue
 			}
@@ -124,20 +109,53 @@ func ListActiveWorkspacesByTemplate(
 	return activeWorkspaces, nextToken, nil
 }
 
+// TemplateUsage summarizes the active workspaces using a template
+type TemplateUsage struct {
+	// Count is the number of active workspaces using the template
+	Count int
+	// Sample holds the namespace/name of the first workspaces found, up to the sample size
+	Sample []string
+}
+
+// SummarizeActiveWorkspacesByTemplate counts the active (non-deleted) workspaces using the specified
+// template, listing them pageSize at a time so that templates used by thousands of workspaces are
+// not listed at once, and keeps the names of up to sampleSize of them.
+// Pagination needs a reader backed by the API server, such as the manager's API reader: the informer
+// cache does not support continue tokens.
+func SummarizeActiveWorkspacesByTemplate(
+	ctx context.Context,
+	k8sClient client.Reader,
+	templateName string,
+	templateNamespace string,
+	pageSize int64,
+	sampleSize int) (*TemplateUsage, error) {
+	usage := &TemplateUsage{}
+	continueToken := ""
+	for {
+		workspaces, nextToken, err := ListActiveWorkspacesByTemplate(
+			ctx, k8sClient, templateName, templateNamespace, continueToken, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		usage.Count += len(workspaces)
+		for i := 0; i < len(workspaces) && len(usage.Sample) < sampleSize; i++ {
+			usage.Sample = append(usage.Sample, workspaces[i].Namespace+"/"+workspaces[i].Name)
+		}
+		if nextToken == "" {
+			return usage, nil
+		}
+		continueToken = nextToken
+	}
+}
+
 // HasActiveWorkspacesWithTemplate checks if any active (non-deleted) workspace uses the specified template.
-// Reads from controller-runtime's informer cache with eventual consistency guarantees.
+// Reads from the WorkspaceTemplateRefNameField index of the informer cache with eventual consistency guarantees.
 // Returns true if at least one active workspace uses the template.
 func HasActiveWorkspacesWithTemplate(ctx context.Context, k8sClient client.Client, templateName string, templateNamespace string) (bool, error) {
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
 
-	// Build label selector
-	labels := map[string]string{
-		LabelWorkspaceTemplate:          templateName,
-		LabelWorkspaceTemplateNamespace: templateNamespace,
-	}
-
-	if err := k8sClient.List(ctx, workspaceList, client.MatchingLabels(labels)); err != nil {
-		return false, fmt.Errorf("failed to check workspaces by template label: %w", err)
+	if err := k8sClient.List(ctx, workspaceList, client.MatchingFields{WorkspaceTemplateRefNameField: templateName}); err != nil {
+		return false, fmt.Errorf("failed to check workspaces by template reference: %w", err)
 	}
 
 	// Check if any non-deleted workspace exists
@@ -158,7 +176,7 @@ func HasActiveWorkspacesWithTemplate(ctx context.Context, k8sClient client.Clien
 }
 
 // HasActiveWorkspacesWithAccessStrategy checks if any active (non-deleted) workspace uses the specified access strategy.
-// Reads from controller-runtime's informer cache with eventual consistency guarantees.
+// Reads from the WorkspaceAccessStrategyNameField index of the informer cache with eventual consistency guarantees.
 // Returns true if at least one active workspace uses the template.
 func HasActiveWorkspacesWithAccessStrategy(
 	ctx context.Context,
@@ -167,13 +185,9 @@ func HasActiveWorkspacesWithAccessStrategy(
 	accessStrategyNamespace string) (bool, error) {
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
 
-	labels := map[string]string{
-		LabelAccessStrategyName:      accessStrategyName,
-		LabelAccessStrategyNamespace: accessStrategyNamespace,
-	}
-
-	if err := k8sClient.List(ctx, workspaceList, client.MatchingLabels(labels)); err != nil {
-		return false, fmt.Errorf("failed to check workspaces by access strategy label: %w", err)
+	if err := k8sClient.List(ctx, workspaceList,
+		client.MatchingFields{WorkspaceAccessStrategyNameField: accessStrategyName}); err != nil {
+		return false, fmt.Errorf("failed to check workspaces by access strategy reference: %w", err)
 	}
 
 	// Check if any non-deleted workspace exists
@@ -192,9 +206,9 @@ func HasActiveWorkspacesWithAccessStrategy(
 }
 
 // ListActiveWorkspacesByAccessStrategy returns all active (non-deleted) workspaces using the specified AccessStrategy.
-// Reads from controller-runtime's informer cache (not direct API calls), providing efficient lookup
-// with eventual consistency guarantees. Filters out workspaces being deleted (DeletionTimestamp set).
-// Validates AccessStrategy reference matches label to guard against drift.
+// Reads from the WorkspaceAccessStrategyNameField index of the informer cache (not direct API calls), providing
+// efficient lookup with eventual consistency guarantees. Filters out workspaces being deleted (DeletionTimestamp
+// set) and workspaces referencing an AccessStrategy of the same name in another namespace.
 // Supports pagination for large-scale deployments via continueToken and limit parameters.
 // Returns list of workspaces, continuationToken and error.
 func ListActiveWorkspacesByAccessStrategy(
@@ -208,13 +222,8 @@ func ListActiveWorkspacesByAccessStrategy(
 
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
 
-	labels := map[string]string{
-		LabelAccessStrategyName:      accessStrategyName,
-		LabelAccessStrategyNamespace: accessStrategyNamespace,
-	}
-
 	listOptions := []client.ListOption{
-		client.MatchingLabels(labels),
+		client.MatchingFields{WorkspaceAccessStrategyNameField: accessStrategyName},
 	}
 
 	// Add pagination options if specified
@@ -226,7 +235,7 @@ func ListActiveWorkspacesByAccessStrategy(
 	}
 
 	if err := k8sClient.List(ctx, workspaceList, listOptions...); err != nil {
-		return nil, "", fmt.Errorf("failed to list workspaces by AccessStrategy label: %w", err)
+		return nil, "", fmt.Errorf("failed to list workspaces by AccessStrategy reference: %w", err)
 	}
 
 	// Filter out workspaces being deleted and verify AccessStrategy reference
@@ -236,22 +245,8 @@ func ListActiveWorkspacesByAccessStrategy(
 			continue // Skip workspaces being deleted
 		}
 
-		// Verify AccessStrategy reference matches label to guard against label/spec mismatch
-		if ws.Spec.AccessStrategy == nil {
-			logger.Info("Workspace has AccessStrategy label but nil AccessStrategy reference - data integrity issue",
-				"workspace", ws.Name,
-				"workspaceNamespace", ws.Namespace,
-				"label", accessStrategyName)
-			continue
-		}
-
-		if ws.Spec.AccessStrategy.Name != accessStrategyName {
-			// This should never happen - log if it occurs
-			logger.Info("Workspace has AccessStrategy label but different AccessStrategy name",
-				"workspace", ws.Name,
-				"workspaceNamespace", ws.Namespace,
-				"label", accessStrategyName,
-				"spec", ws.Spec.AccessStrategy.Name)
+		// Guard against readers that ignore the field selector
+		if ws.Spec.AccessStrategy == nil || ws.Spec.AccessStrategy.Name != accessStrategyName {
 			continue
 		}
 
@@ -259,10 +254,10 @@ func ListActiveWorkspacesByAccessStrategy(
 		if accessStrategyNamespace != "" {
 			actualNamespace := GetAccessStrategyRefNamespace(&ws)
 			if actualNamespace != accessStrategyNamespace {
-				logger.V(1).Info("Workspace has AccessStrategy label but different namespace",
+				logger.V(1).Info("Workspace references an AccessStrategy of the same name in another namespace",
 					"workspace", ws.Name,
 					"workspaceNamespace", ws.Namespace,
-					"labelNamespace", accessStrategyNamespace,
+					"accessStrategyNamespace", accessStrategyNamespace,
 					"specNamespace", actualNamespace)
 				continue
 			}
@@ -277,7 +272,7 @@ func ListActiveWorkspacesByAccessStrategy(
 	return activeWorkspaces, nextToken, nil
 }
 
-// ApplyAccessStrategyLabels sets (or clears) the access strategy lookup labels on a template in place,
+// ApplyAccessStrategyLabels sets (or clears) the access strategy labels on a template in place,
 // based on its spec.defaultAccessStrategy. The label namespace is resolved the same way workspaces
 // resolve access strategy references: an explicit ref namespace is honored, otherwise it defaults to
 // the template's own namespace (there is no shared-namespace fallback). Returns true if any label
@@ -285,6 +280,8 @@ func ListActiveWorkspacesByAccessStrategy(
 //
 // This is the single source of truth shared by the WorkspaceTemplate mutating webhook (which stamps
 // the labels eagerly at admission) and the controller (which backfills them for pre-existing templates).
+// The labels let users select templates by access strategy; lookups list by
+// TemplateDefaultAccessStrategyNameField instead.
 func ApplyAccessStrategyLabels(template *workspacev1alpha1.WorkspaceTemplate) bool {
 	desiredName := ""
 	desiredNamespace := ""
@@ -321,8 +318,8 @@ func ApplyAccessStrategyLabels(template *workspacev1alpha1.WorkspaceTemplate) bo
 // counterpart of HasActiveWorkspacesWithAccessStrategy and, like it, is used by the lazy-finalizer
 // reconcile hot path where only a boolean is needed (so it can early-exit rather than materialize counts).
 //
-// Like the other template query this is label-based: the controller/webhook stamp
-// LabelAccessStrategyName / LabelAccessStrategyNamespace on templates from their defaultAccessStrategy.
+// It reads from the TemplateDefaultAccessStrategyNameField index of the informer cache. The namespace of
+// the reference is resolved the same way as in ApplyAccessStrategyLabels.
 func HasActiveTemplatesWithAccessStrategy(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -331,17 +328,21 @@ func HasActiveTemplatesWithAccessStrategy(
 
 	templateList := &workspacev1alpha1.WorkspaceTemplateList{}
 
-	labels := map[string]string{
-		LabelAccessStrategyName:      accessStrategyName,
-		LabelAccessStrategyNamespace: accessStrategyNamespace,
-	}
-
-	if err := k8sClient.List(ctx, templateList, client.MatchingLabels(labels)); err != nil {
-		return false, fmt.Errorf("failed to check templates by access strategy label: %w", err)
+	if err := k8sClient.List(ctx, templateList,
+		client.MatchingFields{TemplateDefaultAccessStrategyNameField: accessStrategyName}); err != nil {
+		return false, fmt.Errorf("failed to check templates by access strategy reference: %w", err)
 	}
 
 	for i := range templateList.Items {
-		if templateList.Items[i].DeletionTimestamp.IsZero() {
+		template := &templateList.Items[i]
+		if !template.DeletionTimestamp.IsZero() || template.Spec.DefaultAccessStrategy == nil {
+			continue
+		}
+		refNamespace := template.Spec.DefaultAccessStrategy.Namespace
+		if refNamespace == "" {
+			refNamespace = template.Namespace
+		}
+		if template.Spec.DefaultAccessStrategy.Name == accessStrategyName && refNamespace == accessStrategyNamespace {
 			return true, nil
 		}
 	}
@@ -381,3 +382,29 @@ func GetWorkspaceReconciliationRequestsForAccessStrategy(
 
 	return allRequests, nil
 }
+
+// GetWorkspaceReconciliationRequestsForTemplate retrieves all active workspaces using the specified template.
+// Like GetWorkspaceReconciliationRequestsForAccessStrategy, it lists them in a single call, which is
+// compatible with the controller-runtime cache client.
+func GetWorkspaceReconciliationRequestsForTemplate(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateName string,
+	templateNamespace string) ([]reconcile.Request, error) {
+
+	workspaces, _, err := ListActiveWorkspacesByTemplate(ctx, k8sClient, templateName, templateNamespace, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces by template: %w", err)
+	}
+
+	requests := make([]reconcile.Request, 0, len(workspaces))
+	for _, ws := range workspaces {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      ws.Name,
+				Namespace: ws.Namespace,
+			},
+		})
+	}
+	return requests, nil
+}
@@ -48,6 +48,15 @@ type MockClient struct {
 	ListOptions []client.ListOption
 }
 
+// newIndexedClientBuilder returns a fake client builder with the field indexes of the manager cache
+func newIndexedClientBuilder(scheme *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range FieldIndexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	return builder
+}
+
 func (m *MockClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
 	// Save the options for later verification
 	m.ListOptions = opts
@@ -59,51 +68,6 @@ func (m *MockClient) List(ctx context.Context, list client.ObjectList, opts ...c
 }
 
 // Helper functions to extract information from list options
-func getLabelsFromOption(optString string) map[string]string {
-	labels := make(map[string]string)
-
-	// Check if it's a map format like "map[key:value key2:value2]"
-	if strings.HasPrefix(optString, "map[") {
-		// Remove the "map[" prefix and "]" suffix
-		mapContent := optString[4 : len(optString)-1]
-
-		// Split by spaces to get individual key:value pairs
-		pairs := strings.Split(mapContent, " ")
-		for _, pair := range pairs {
-			parts := strings.SplitN(pair, ":", 2)
-			if len(parts) == 2 {
-				key := parts[0]
-				value := parts[1]
-				labels[key] = value
-			}
-		}
-		return labels
-	}
-
-	// Legacy format for older tests
-	if strings.Contains(optString, "labels") {
-		// Extract the part between square brackets
-		startIdx := strings.Index(optString, "[")
-		endIdx := strings.LastIndex(optString, "]")
-		if startIdx != -1 && endIdx != -1 && startIdx < endIdx {
-			labelsStr := optString[startIdx+1 : endIdx]
-
-			// Split by comma to get individual key-value pairs
-			pairs := strings.Split(labelsStr, ",")
-			for _, pair := range pairs {
-				parts := strings.SplitN(pair, ":", 2)
-				if len(parts) == 2 {
-					// Trim any whitespace
-					key := strings.TrimSpace(parts[0])
-					value := strings.TrimSpace(parts[1])
-					labels[key] = value
-				}
-			}
-		}
-	}
-	return labels
-}
-
 func getLimitFromOption(optString string) int64 {
 	// Direct integer value like "10"
 	if limit, err := strconv.ParseInt(optString, 10, 64); err == nil {
@@ -291,14 +255,35 @@ func TestGetAccessStrategyRefNamespace(t *testing.T) {
 	}
 }
 
-func TestHasActiveWorkspacesWithTemplate_UsesLabelMatcher(t *testing.T) {
+func TestIsAccessEnabled(t *testing.T) {
+	enabled, disabled := true, false
+	testCases := map[string]struct {
+		accessEnabled *bool
+		expected      bool
+	}{
+		"unset":    {accessEnabled: nil, expected: true},
+		"enabled":  {accessEnabled: &enabled, expected: true},
+		"disabled": {accessEnabled: &disabled, expected: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ws := &workspacev1alpha1.Workspace{
+				Spec: workspacev1alpha1.WorkspaceSpec{AccessEnabled: tc.accessEnabled},
+			}
+			assert.Equal(t, tc.expected, IsAccessEnabled(ws))
+		})
+	}
+}
+
+func TestHasActiveWorkspacesWithTemplate_UsesFieldMatcher(t *testing.T) {
 	// Setup test scheme
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
 
 	// Create a mock client to capture list options
 	mockClient := &MockClient{
-		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Client: newIndexedClientBuilder(scheme).Build(),
 	}
 
 	// Call the function
@@ -307,23 +292,9 @@ func TestHasActiveWorkspacesWithTemplate_UsesLabelMatcher(t *testing.T) {
 	// Assertions
 	assert.NoError(t, err)
 
-	// Verify that the List function was called with the correct labels
-	assert.NotEmpty(t, mockClient.ListOptions, "List options should not be empty")
-
-	// Extract the matching labels from the list options
-	labels := make(map[string]string)
-	for _, opt := range mockClient.ListOptions {
-		optString := fmt.Sprintf("%v", opt)
-		if matches := getLabelsFromOption(optString); len(matches) > 0 {
-			for k, v := range matches {
-				labels[k] = v
-			}
-		}
-	}
-
-	assert.NotEmpty(t, labels, "List should be called with labels")
-	assert.Equal(t, testTemplateName, labels[LabelWorkspaceTemplate], "Should filter by template name")
-	assert.Equal(t, templateNamespace, labels[LabelWorkspaceTemplateNamespace], "Should filter by template namespace")
+	// Verify that the List function was called with the indexed template reference
+	assert.Contains(t, mockClient.ListOptions, client.MatchingFields{WorkspaceTemplateRefNameField: testTemplateName},
+		"Should filter by template name")
 }
 
 func TestHasActiveWorkspacesWithTemplate_SkipsDeletedWorkspaces(t *testing.T) {
@@ -355,7 +326,7 @@ func TestHasActiveWorkspacesWithTemplate_SkipsDeletedWorkspaces(t *testing.T) {
 	}
 
 	// Create fake client with the workspace
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithTemplate(context.Background(), fakeClient, testTemplateName, templateNamespace)
@@ -408,7 +379,7 @@ func TestHasActiveWorkspacesWithTemplate_ReturnTrueOnFirstMatch(t *testing.T) {
 	}
 
 	// Create fake client with the workspaces
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws1, ws2).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithTemplate(context.Background(), fakeClient, testTemplateName, templateNamespace)
@@ -443,7 +414,7 @@ func TestHasActiveWorkspacesWithTemplate_ReturnFalseOnNoMatch(t *testing.T) {
 	}
 
 	// Create fake client with the workspace
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithTemplate(context.Background(), fakeClient, testTemplateName, templateNamespace)
@@ -465,17 +436,17 @@ func TestHasActiveWorkspacesWithTemplate_OnListError_ReturnError(t *testing.T) {
 	// Assertions
 	assert.Error(t, err)
 	assert.False(t, result, "Expected false when client returns an error")
-	assert.Contains(t, err.Error(), "failed to check workspaces by template label: mock list error")
+	assert.Contains(t, err.Error(), "failed to check workspaces by template reference: mock list error")
 }
 
-func TestHasActiveWorkspacesWithAccessStrategy_UsesLabelMatcher(t *testing.T) {
+func TestHasActiveWorkspacesWithAccessStrategy_UsesFieldMatcher(t *testing.T) {
 	// Setup test scheme
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
 
 	// Create a mock client to capture list options
 	mockClient := &MockClient{
-		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Client: newIndexedClientBuilder(scheme).Build(),
 	}
 
 	// Call the function
@@ -488,23 +459,9 @@ func TestHasActiveWorkspacesWithAccessStrategy_UsesLabelMatcher(t *testing.T) {
 	// Assertions
 	assert.NoError(t, err)
 
-	// Verify that the List function was called with the correct labels
-	assert.NotEmpty(t, mockClient.ListOptions, "List options should not be empty")
-
-	// Extract the matching labels from the list options
-	labels := make(map[string]string)
-	for _, opt := range mockClient.ListOptions {
-		optString := fmt.Sprintf("%v", opt)
-		if matches := getLabelsFromOption(optString); len(matches) > 0 {
-			for k, v := range matches {
-				labels[k] = v
-			}
-		}
-	}
-
-	assert.NotEmpty(t, labels, "List should be called with labels")
-	assert.Equal(t, testAccessStrategyName, labels[LabelAccessStrategyName], "Should filter by access strategy name")
-	assert.Equal(t, accessStrategyNamespace, labels[LabelAccessStrategyNamespace], "Should filter by access strategy namespace")
+	// Verify that the List function was called with the indexed access strategy reference
+	assert.Contains(t, mockClient.ListOptions, client.MatchingFields{WorkspaceAccessStrategyNameField: testAccessStrategyName},
+		"Should filter by access strategy name")
 }
 
 func TestHasActiveWorkspacesWithAccessStrategy_SkipsDeletedWorkspaces(t *testing.T) {
@@ -536,7 +493,7 @@ func TestHasActiveWorkspacesWithAccessStrategy_SkipsDeletedWorkspaces(t *testing
 	}
 
 	// Create fake client with the workspace
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithAccessStrategy(
@@ -593,7 +550,7 @@ func TestHasActiveWorkspacesWithAccessStrategy_ReturnTrueOnFirstMatch(t *testing
 	}
 
 	// Create fake client with the workspaces
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws1, ws2).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithAccessStrategy(
@@ -632,7 +589,7 @@ func TestHasActiveWorkspacesWithAccessStrategy_ReturnFalseOnNoMatch(t *testing.T
 	}
 
 	// Create fake client with the workspace
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws).Build()
 
 	// Call the function
 	result, err := HasActiveWorkspacesWithAccessStrategy(
@@ -662,10 +619,10 @@ func TestHasActiveWorkspacesWithAccessStrategy_OnListError_ReturnError(t *testin
 	// Assertions
 	assert.Error(t, err)
 	assert.False(t, result, "Expected false when client returns an error")
-	assert.Contains(t, err.Error(), "failed to check workspaces by access strategy label: mock list error")
+	assert.Contains(t, err.Error(), "failed to check workspaces by access strategy reference: mock list error")
 }
 
-func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchLabels_ReturnWorkspaces(t *testing.T) {
+func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchFields_ReturnWorkspaces(t *testing.T) {
 	// Setup test scheme
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
@@ -731,15 +688,15 @@ func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchLabels_ReturnWor
 	}
 
 	// Create a regular fakeClient with objects for testing the results
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2, wsDeleted).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws1, ws2, wsDeleted).Build()
 
 	// Create a mock client to capture list options
 	mockClient := &MockClient{
 		// Clone the real client's scheme
-		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2, wsDeleted).Build(),
+		Client: newIndexedClientBuilder(scheme).WithObjects(ws1, ws2, wsDeleted).Build(),
 	}
 
-	// Call the function with the mock client to check labels
+	// Call the function with the mock client to check the list options
 	_, _, err := ListActiveWorkspacesByAccessStrategy(
 		context.Background(),
 		mockClient,
@@ -751,11 +708,11 @@ func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchLabels_ReturnWor
 	// Assertions for the mock client
 	assert.NoError(t, err)
 
-	// Verify that the List function was called with the correct labels
-	assert.NotEmpty(t, mockClient.ListOptions, "List options should not be empty")
+	// Verify that the List function was called with the indexed access strategy reference
+	assert.Contains(t, mockClient.ListOptions, client.MatchingFields{WorkspaceAccessStrategyNameField: testAccessStrategyName},
+		"Should filter by access strategy name")
 
-	// Extract the labels, limit, and continue token from the list options
-	labels := make(map[string]string)
+	// Extract the limit and continue token from the list options
 	var limitValue int64
 	var continueToken string
 
@@ -763,13 +720,6 @@ func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchLabels_ReturnWor
 	for _, opt := range mockClient.ListOptions {
 		optString := fmt.Sprintf("%v", opt)
 
-		// Check for labels
-		if matches := getLabelsFromOption(optString); len(matches) > 0 {
-			for k, v := range matches {
-				labels[k] = v
-			}
-		}
-
 		// Check for limit
 		if limitMatch := getLimitFromOption(optString); limitMatch > 0 {
 			limitValue = limitMatch
@@ -781,11 +731,6 @@ func TestListActiveWorkspacesByAccessStrategy_CallsListWithMatchLabels_ReturnWor
 		}
 	}
 
-	// Verify the labels
-	assert.NotEmpty(t, labels, "List should be called with labels")
-	assert.Equal(t, testAccessStrategyName, labels[LabelAccessStrategyName], "Should filter by access strategy name")
-	assert.Equal(t, accessStrategyNamespace, labels[LabelAccessStrategyNamespace], "Should filter by access strategy namespace")
-
 	// Verify limit and continue token
 	assert.Equal(t, int64(10), limitValue, "Limit should be 10")
 	assert.Equal(t, "token-123", continueToken, "Continue token should match")
@@ -830,10 +775,10 @@ func TestListActiveWorkspacesByAccessStrategy_OnListError_ReturnErrors(t *testin
 	assert.Error(t, err)
 	assert.Nil(t, workspaces)
 	assert.Empty(t, nextToken)
-	assert.Contains(t, err.Error(), "failed to list workspaces by AccessStrategy label: mock list error")
+	assert.Contains(t, err.Error(), "failed to list workspaces by AccessStrategy reference: mock list error")
 }
 
-func TestGetWorkspaceReconciliationRequestsForAccessStrategy_CallsListWithMatchLabels_ReturnRequests(t *testing.T) {
+func TestGetWorkspaceReconciliationRequestsForAccessStrategy_CallsListWithMatchFields_ReturnRequests(t *testing.T) {
 	// Setup test scheme
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
@@ -899,15 +844,15 @@ func TestGetWorkspaceReconciliationRequestsForAccessStrategy_CallsListWithMatchL
 	}
 
 	// Create a regular fakeClient with objects for testing the results
-	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2, wsDeleted).Build()
+	fakeClient := newIndexedClientBuilder(scheme).WithObjects(ws1, ws2, wsDeleted).Build()
 
 	// Create a mock client to capture list options
 	mockClient := &MockClient{
 		// Clone the real client's scheme
-		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws1, ws2, wsDeleted).Build(),
+		Client: newIndexedClientBuilder(scheme).WithObjects(ws1, ws2, wsDeleted).Build(),
 	}
 
-	// Call the function with the mock client to check labels
+	// Call the function with the mock client to check the list options
 	_, err := GetWorkspaceReconciliationRequestsForAccessStrategy(
 		context.Background(),
 		mockClient,
@@ -917,24 +862,9 @@ func TestGetWorkspaceReconciliationRequestsForAccessStrategy_CallsListWithMatchL
 	// Assertions for the mock client
 	assert.NoError(t, err)
 
-	// Verify that the List function was called with the correct labels
-	assert.NotEmpty(t, mockClient.ListOptions, "List options should not be empty")
-
-	// Extract the matching labels from the list options
-	labels := make(map[string]string)
-	for _, opt := range mockClient.ListOptions {
-		optString := fmt.Sprintf("%v", opt)
-		if matches := getLabelsFromOption(optString); len(matches) > 0 {
-			for k, v := range matches {
-				labels[k] = v
-			}
-		}
-	}
-
-	// Verify the labels
-	assert.NotEmpty(t, labels, "List should be called with labels")
-	assert.Equal(t, testAccessStrategyName, labels[LabelAccessStrategyName], "Should filter by access strategy name")
-	assert.Equal(t, accessStrategyNamespace, labels[LabelAccessStrategyNamespace], "Should filter by access strategy namespace")
+	// Verify that the List function was called with the indexed access strategy reference
+	assert.Contains(t, mockClient.ListOptions, client.MatchingFields{WorkspaceAccessStrategyNameField: testAccessStrategyName},
+		"Should filter by access strategy name")
 
 	// Now call the function with the real client to test the actual functionality
 	requests, err := GetWorkspaceReconciliationRequestsForAccessStrategy(
@@ -976,7 +906,7 @@ func TestGetWorkspaceReconciliationRequestsForAccessStrategy_OnListError_ReturnE
 	// Assertions
 	assert.Error(t, err)
 	assert.Nil(t, requests)
-	assert.Contains(t, err.Error(), "failed to list workspaces by access strategy: failed to list workspaces by AccessStrategy label: mock list error")
+	assert.Contains(t, err.Error(), "failed to list workspaces by access strategy: failed to list workspaces by AccessStrategy reference: mock list error")
 }
 
 func TestApplyAccessStrategyLabels(t *testing.T) {
@@ -1062,15 +992,14 @@ func TestHasActiveTemplatesWithAccessStrategy(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = workspacev1alpha1.AddToScheme(scheme)
 
-	labeledTemplate := func(name string, deleting bool) *workspacev1alpha1.WorkspaceTemplate {
+	referencingTemplate := func(name string, deleting bool) *workspacev1alpha1.WorkspaceTemplate {
 		tmpl := &workspacev1alpha1.WorkspaceTemplate{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
 				Namespace: asNs,
-				Labels: map[string]string{
-					LabelAccessStrategyName:      asName,
-					LabelAccessStrategyNamespace: asNs,
-				},
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultAccessStrategy: &workspacev1alpha1.AccessStrategyRef{Name: asName},
 			},
 		}
 		if deleting {
@@ -1087,21 +1016,87 @@ func TestHasActiveTemplatesWithAccessStrategy(t *testing.T) {
 		want      bool
 	}{
 		{name: "no templates", templates: nil, want: false},
-		{name: "one active template", templates: []client.Object{labeledTemplate("t1", false)}, want: true},
-		{name: "only a deleting template", templates: []client.Object{labeledTemplate("t1", true)}, want: false},
+		{name: "one active template", templates: []client.Object{referencingTemplate("t1", false)}, want: true},
+		{name: "only a deleting template", templates: []client.Object{referencingTemplate("t1", true)}, want: false},
 		{
 			name:      "mix of active and deleting",
-			templates: []client.Object{labeledTemplate("t1", true), labeledTemplate("t2", false)},
+			templates: []client.Object{referencingTemplate("t1", true), referencingTemplate("t2", false)},
 			want:      true,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.templates...).Build()
+			fakeClient := newIndexedClientBuilder(scheme).WithObjects(tc.templates...).Build()
 			got, err := HasActiveTemplatesWithAccessStrategy(context.Background(), fakeClient, asName, asNs)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
+
+// pagingClient serves List calls page by page, honoring the limit and continue options
+type pagingClient struct {
+	client.Client
+	workspaces []workspacev1alpha1.Workspace
+	calls      int
+}
+
+func (c *pagingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.calls++
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := len(c.workspaces)
+	if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+		end = start + int(listOpts.Limit)
+	}
+
+	workspaceList := list.(*workspacev1alpha1.WorkspaceList)
+	workspaceList.Items = c.workspaces[start:end]
+	workspaceList.Continue = ""
+	if end < len(c.workspaces) {
+		workspaceList.Continue = strconv.Itoa(end)
+	}
+	return nil
+}
+
+func TestSummarizeActiveWorkspacesByTemplate_PaginatesAndSamples(t *testing.T) {
+	deletionTime := metav1.Now()
+	var workspaces []workspacev1alpha1.Workspace
+	for i := range 7 {
+		ws := workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ws-%d", i), Namespace: defaultNamespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: testTemplateName, Namespace: templateNamespace},
+			},
+		}
+		if i == 1 {
+			ws.DeletionTimestamp = &deletionTime
+		}
+		workspaces = append(workspaces, ws)
+	}
+	pagingClient := &pagingClient{workspaces: workspaces}
+
+	usage, err := SummarizeActiveWorkspacesByTemplate(context.Background(), pagingClient,
+		testTemplateName, templateNamespace, 3, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pagingClient.calls, "Should list 7 workspaces in pages of 3")
+	assert.Equal(t, 6, usage.Count, "Should skip the workspace being deleted")
+	assert.Equal(t, []string{"default/ws-0", "default/ws-2"}, usage.Sample)
+}
+
+func TestSummarizeActiveWorkspacesByTemplate_OnListError_ReturnError(t *testing.T) {
+	mockClient := &MockClient{ListError: fmt.Errorf("list failed")}
+
+	usage, err := SummarizeActiveWorkspacesByTemplate(context.Background(), mockClient,
+		testTemplateName, templateNamespace, 3, 2)
+
+	assert.Error(t, err)
+	assert.Nil(t, usage)
+}
@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/errs"
 )
 
 // TemplateResolver handles centralized template resolution with namespace fallback logic
@@ -55,16 +56,24 @@ func (tr *TemplateResolver) ResolveTemplate(ctx context.Context, templateRef *wo
 		if fallbackErr := tr.client.Get(ctx, templateKey, template); fallbackErr == nil {
 			return template, nil
 		} else {
-			return nil, fmt.Errorf("failed to get template %s from namespace %s or fallback namespace %s: %w", templateRef.Name, templateNamespace, tr.defaultTemplateNamespace, fallbackErr)
+			return nil, templateError(fallbackErr, "failed to get template %s from namespace %s or fallback namespace %s", templateRef.Name, templateNamespace, tr.defaultTemplateNamespace)
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get template %s: %w", templateRef.Name, err)
+		return nil, templateError(err, "failed to get template %s", templateRef.Name)
 	}
 	return template, nil
 }
 
+// templateError returns a NotFound error when err reports a missing template, and an Internal error otherwise
+func templateError(err error, format string, args ...any) error {
+	if apierrors.IsNotFound(err) {
+		return errs.NotFound(err, format, args...)
+	}
+	return errs.Internal(err, format, args...)
+}
+
 // ResolveTemplateForWorkspace convenience method that extracts templateRef and namespace from workspace
 func (tr *TemplateResolver) ResolveTemplateForWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.WorkspaceTemplate, error) {
 	if workspace.Spec.TemplateRef == nil {
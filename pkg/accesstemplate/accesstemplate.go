@@ -0,0 +1,259 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package accesstemplate parses, lints and renders the Go templates of WorkspaceAccessStrategy
+// resources. Errors are reported as *Error, which points at the strategy field, line and column
+// of the broken template, so that the controller, the access strategy webhook and the
+// accesstemplate CLI report the same positions.
+package accesstemplate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Error is an error in the template of a WorkspaceAccessStrategy field
+type Error struct {
+	// Field is the path of the field holding the template, e.g. spec.accessResourceTemplates[0].template
+	Field string
+	// Line is the 1-based line of the error in the template, 0 when unknown
+	Line int
+	// Column is the 1-based column of the error in the line, 0 when unknown.
+	// text/template only reports columns for execution errors.
+	Column int
+	// Message describes the error
+	Message string
+}
+
+// Error formats the error as field:line:column: message, omitting unknown positions
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Field)
+	if e.Line > 0 {
+		b.WriteString(":" + strconv.Itoa(e.Line))
+		if e.Column > 0 {
+			b.WriteString(":" + strconv.Itoa(e.Column))
+		}
+	}
+	b.WriteString(": " + e.Message)
+	return b.String()
+}
+
+// ErrorList holds the errors of all the templates of a WorkspaceAccessStrategy
+type ErrorList []*Error
+
+// Error joins the errors, one per line
+func (l ErrorList) Error() string {
+	messages := make([]string, 0, len(l))
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Data is the data templates are rendered with. Service is not set for the templates
+// rendered before the workspace Service exists, such as merged environment variables.
+type Data struct {
+	Workspace      *workspacev1alpha1.Workspace
+	AccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
+	Service        *corev1.Service
+	// Ports lists the ports of the workspace Service, empty when Service is not set,
+	// so that templates can range over them or look one up with the port function
+	Ports []corev1.ServicePort
+	// Routes lists the additional ports of the workspace with the subpath to route to each,
+	// so that templates can route subpaths of the workspace to different ports
+	Routes []Route
+	// CustomDomain is the host name the workspace is served on, empty when the workspace does not
+	// set one, so that templates can route a host rather than a path to the workspace
+	CustomDomain string
+	// Application is the application the workspace serves, so that templates can adapt to
+	// non-Jupyter IDEs, e.g. {{ if eq .Application.Type "codeserver" }}
+	Application Application
+}
+
+// Application is the application the workspace serves, from its spec.applicationType
+type Application struct {
+	// Type of the application, e.g. jupyterlab or codeserver
+	Type string
+	// Port the application listens on in the pod, which the "http" port of the workspace Service targets
+	Port int32
+	// BaseURLEnvVar is the environment variable the application reads its base URL from,
+	// e.g. JUPYTER_BASE_URL
+	BaseURLEnvVar string
+}
+
+// Route is an additional port of the workspace and the subpath access resources route to it
+type Route struct {
+	// Name of the port, e.g. tensorboard
+	Name string
+	// Port number, on both the workspace Service and the pod
+	Port int32
+	// Path relative to the base path of the workspace, /<name>/ unless the workspace sets it
+	Path string
+}
+
+// NewData returns the data to render the templates of accessStrategy for workspace with.
+// service may be nil.
+func NewData(
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+	service *corev1.Service,
+) *Data {
+	data := &Data{
+		Workspace:      workspace,
+		AccessStrategy: accessStrategy,
+		Service:        service,
+		Ports:          []corev1.ServicePort{},
+		Routes:         []Route{},
+		CustomDomain:   workspace.Spec.CustomDomain,
+	}
+	application := workspaceutil.GetApplication(workspace)
+	data.Application = Application{
+		Type:          string(application.Type),
+		Port:          application.Port,
+		BaseURLEnvVar: application.BaseURLEnvVar,
+	}
+	if service != nil {
+		data.Ports = service.Spec.Ports
+	}
+	for _, port := range workspace.Spec.Ports {
+		data.Routes = append(data.Routes, Route{Name: port.Name, Port: port.Port, Path: workspaceutil.RoutePath(port)})
+	}
+	return data
+}
+
+// templateErrorPattern matches the position text/template prefixes its errors with:
+// "template: <name>:<line>: <message>" on parse, "template: <name>:<line>:<column>: <message>" on execution
+var templateErrorPattern = regexp.MustCompile(`^template: [^:]*:(\d+)(?::(\d+))?: (.*)$`)
+
+// executingPattern matches the redundant `executing "<name>" at ` prefix of execution errors
+var executingPattern = regexp.MustCompile(`^executing "[^"]*" at `)
+
+// newError converts a text/template error into an *Error of field
+func newError(field string, err error) *Error {
+	templateErr := &Error{Field: field, Message: err.Error()}
+	matches := templateErrorPattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return templateErr
+	}
+	templateErr.Line, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		templateErr.Column, _ = strconv.Atoi(matches[2])
+	}
+	templateErr.Message = executingPattern.ReplaceAllString(matches[3], "")
+	return templateErr
+}
+
+// Parse parses the template of field. Parse errors are returned as *Error. Templates are strict:
+// a missing map key, e.g. {{ .Workspace.Labels.team }} on a workspace without that label, fails
+// to execute rather than rendering "<no value>".
+func Parse(field, text string) (*template.Template, error) {
+	// The template is named after the field, which holds no colon, so that error positions can be parsed
+	tmpl, err := template.New(field).Funcs(Funcs()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, newError(field, err)
+	}
+	return tmpl, nil
+}
+
+// Execute renders the template of field, returned by Parse, with data. Execution errors are
+// returned as *Error.
+func Execute(field string, tmpl *template.Template, data *Data) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", newError(field, err)
+	}
+	return b.String(), nil
+}
+
+// Render parses and executes the template of field with data. Errors are returned as *Error.
+func Render(field, text string, data *Data) (string, error) {
+	tmpl, err := Parse(field, text)
+	if err != nil {
+		return "", err
+	}
+	return Execute(field, tmpl, data)
+}
+
+// Paths of the template fields of a WorkspaceAccessStrategy
+const (
+	FieldAccessURLTemplate           = "spec.accessURLTemplate"
+	FieldApplicationBasePathTemplate = "spec.applicationBasePathTemplate"
+	FieldBearerAuthURLTemplate       = "spec.bearerAuthURLTemplate"
+	FieldAccessStartupProbeURL       = "spec.accessStartupProbe.httpGet.urlTemplate"
+)
+
+// ResourceTemplateField returns the path of the template of the i-th access resource template
+func ResourceTemplateField(i int) string {
+	return fmt.Sprintf("spec.accessResourceTemplates[%d].template", i)
+}
+
+// MergeEnvField returns the path of the value template of the i-th environment variable merged
+// into the primary container
+func MergeEnvField(i int) string {
+	return fmt.Sprintf("spec.deploymentModifications.podModifications.primaryContainerModifications"+
+		".mergeEnv[%d].valueTemplate", i)
+}
+
+// Field is a template of a WorkspaceAccessStrategy
+type Field struct {
+	// Path of the field, e.g. spec.accessURLTemplate
+	Path string
+	// Template text
+	Template string
+}
+
+// Templates returns the non-empty templates of the strategy, in field order
+func Templates(strategy *workspacev1alpha1.WorkspaceAccessStrategy) []Field {
+	spec := strategy.Spec
+	var fields []Field
+	add := func(path, text string) {
+		if text != "" {
+			fields = append(fields, Field{Path: path, Template: text})
+		}
+	}
+
+	for i, resourceTemplate := range spec.AccessResourceTemplates {
+		add(ResourceTemplateField(i), resourceTemplate.Template)
+	}
+	add(FieldAccessURLTemplate, spec.AccessURLTemplate)
+	add(FieldApplicationBasePathTemplate, spec.ApplicationBasePathTemplate)
+	add(FieldBearerAuthURLTemplate, spec.BearerAuthURLTemplate)
+	if spec.AccessStartupProbe != nil && spec.AccessStartupProbe.HTTPGet != nil {
+		add(FieldAccessStartupProbeURL, spec.AccessStartupProbe.HTTPGet.URLTemplate)
+	}
+	if spec.DeploymentModifications != nil &&
+		spec.DeploymentModifications.PodModifications != nil &&
+		spec.DeploymentModifications.PodModifications.PrimaryContainerModifications != nil {
+		for i, env := range spec.DeploymentModifications.PodModifications.PrimaryContainerModifications.MergeEnv {
+			add(MergeEnvField(i), env.ValueTemplate)
+		}
+	}
+	return fields
+}
+
+// Lint parses all the templates of the strategy and returns their errors, or nil. It does not
+// execute them: whether a template renders depends on the workspace it is rendered for.
+func Lint(strategy *workspacev1alpha1.WorkspaceAccessStrategy) ErrorList {
+	var errs ErrorList
+	for _, field := range Templates(strategy) {
+		if _, err := Parse(field.Path, field.Template); err != nil {
+			var templateErr *Error
+			if errors.As(err, &templateErr) {
+				errs = append(errs, templateErr)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,214 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accesstemplate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestParseReportsLine(t *testing.T) {
+	_, err := Parse(FieldAccessURLTemplate, "https://example.com/\n{{ .Workspace.Name }/")
+
+	var templateErr *Error
+	if !errors.As(err, &templateErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if templateErr.Field != FieldAccessURLTemplate || templateErr.Line != 2 || templateErr.Column != 0 {
+		t.Errorf("unexpected position %+v", templateErr)
+	}
+	if strings.HasPrefix(templateErr.Message, "template:") {
+		t.Errorf("message should not repeat the template position: %q", templateErr.Message)
+	}
+}
+
+func TestExecuteReportsLineAndColumn(t *testing.T) {
+	data := &Data{Workspace: &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws"},
+	}}
+
+	_, err := Render(ResourceTemplateField(0), "name: {{ .Workspace.Name }}\nport: {{ .Service.Name }}\n", data)
+
+	var templateErr *Error
+	if !errors.As(err, &templateErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if templateErr.Line != 2 || templateErr.Column != 17 {
+		t.Errorf("expected line 2 column 17, got %+v", templateErr)
+	}
+	if strings.HasPrefix(templateErr.Message, "executing") {
+		t.Errorf("message should not repeat the template name: %q", templateErr.Message)
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := &Data{Workspace: &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns"},
+	}}
+
+	rendered, err := Render(FieldAccessURLTemplate,
+		"https://example.com/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered != "https://example.com/ns/ws/" {
+		t.Errorf("unexpected rendered URL %q", rendered)
+	}
+}
+
+func TestRenderServicePorts(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}
+	service := &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+		{Name: "http", Port: 8888},
+		{Name: "metrics", Port: 9090},
+	}}}
+	data := NewData(workspace, nil, service)
+
+	rendered, err := Render(ResourceTemplateField(0),
+		"port: {{ port .Ports \"metrics\" }}\n{{ range .Ports }}- {{ .Name }}:{{ .Port }}\n{{ end }}", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered != "port: 9090\n- http:8888\n- metrics:9090\n" {
+		t.Errorf("unexpected rendered template %q", rendered)
+	}
+}
+
+func TestRenderServicePortsWithoutService(t *testing.T) {
+	data := NewData(&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}, nil, nil)
+
+	rendered, err := Render(FieldAccessURLTemplate, "{{ range .Ports }}{{ .Name }}{{ end }}", data)
+	if err != nil || rendered != "" {
+		t.Errorf("expected an empty port list, got %q, %v", rendered, err)
+	}
+
+	_, err = Render(FieldAccessURLTemplate, "{{ port .Ports \"http\" }}", data)
+	var templateErr *Error
+	if !errors.As(err, &templateErr) || !strings.Contains(templateErr.Message, `no service port named "http"`) {
+		t.Errorf("expected a missing port error, got %v", err)
+	}
+}
+
+func TestRenderRoutes(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws"},
+		Spec: workspacev1alpha1.WorkspaceSpec{Ports: []workspacev1alpha1.WorkspacePort{
+			{Name: "tensorboard", Port: 6006},
+			{Name: "mlflow", Port: 5000, Path: "/tracking/"},
+		}},
+	}
+	data := NewData(workspace, nil, nil)
+
+	rendered, err := Render(ResourceTemplateField(0),
+		"{{ range .Routes }}- /{{ $.Workspace.Name }}{{ .Path }} -> {{ .Name }}:{{ .Port }}\n{{ end }}", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered != "- /ws/tensorboard/ -> tensorboard:6006\n- /ws/tracking/ -> mlflow:5000\n" {
+		t.Errorf("unexpected rendered template %q", rendered)
+	}
+}
+
+func TestRenderCustomDomain(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns"}}
+	text := "{{ with .CustomDomain }}https://{{ . }}/{{ else }}https://example.com/{{ .Workspace.Name }}/{{ end }}"
+
+	rendered, err := Render(FieldAccessURLTemplate, text, NewData(workspace, nil, nil))
+	if err != nil || rendered != "https://example.com/ws/" {
+		t.Errorf("expected a path-prefixed URL, got %q, %v", rendered, err)
+	}
+
+	workspace.Spec.CustomDomain = "alice-notebook.team.example.com"
+	rendered, err = Render(FieldAccessURLTemplate, text, NewData(workspace, nil, nil))
+	if err != nil || rendered != "https://alice-notebook.team.example.com/" {
+		t.Errorf("expected the custom domain URL, got %q, %v", rendered, err)
+	}
+}
+
+func TestRenderApplication(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}
+	text := "{{ .Application.Type }}:{{ .Application.Port }}:{{ .Application.BaseURLEnvVar }}"
+
+	rendered, err := Render(FieldAccessURLTemplate, text, NewData(workspace, nil, nil))
+	if err != nil || rendered != "jupyterlab:8888:JUPYTER_BASE_URL" {
+		t.Errorf("expected JupyterLab by default, got %q, %v", rendered, err)
+	}
+
+	workspace.Spec.ApplicationType = workspacev1alpha1.ApplicationTypeCodeServer
+	rendered, err = Render(FieldAccessURLTemplate, text, NewData(workspace, nil, nil))
+	if err != nil || rendered != "codeserver:8080:CODE_SERVER_BASE_URL" {
+		t.Errorf("expected code-server, got %q, %v", rendered, err)
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	cases := []struct {
+		err      *Error
+		expected string
+	}{
+		{&Error{Field: "spec.accessURLTemplate", Message: "boom"}, "spec.accessURLTemplate: boom"},
+		{&Error{Field: "spec.accessURLTemplate", Line: 3, Message: "boom"}, "spec.accessURLTemplate:3: boom"},
+		{&Error{Field: "spec.accessURLTemplate", Line: 3, Column: 7, Message: "boom"}, "spec.accessURLTemplate:3:7: boom"},
+	}
+	for _, c := range cases {
+		if got := c.err.Error(); got != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, got)
+		}
+	}
+}
+
+func TestLintReportsFieldPaths(t *testing.T) {
+	strategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{
+				{NamePrefix: "ok", Template: "name: {{ .Workspace.Name }}"},
+				{NamePrefix: "broken", Template: "a: 1\nname: {{ .Workspace.Name"},
+			},
+			AccessURLTemplate:     "{{ .Workspace.Name }}",
+			BearerAuthURLTemplate: "{{ end }}",
+			DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+				PodModifications: &workspacev1alpha1.PodModifications{
+					PrimaryContainerModifications: &workspacev1alpha1.PrimaryContainerModifications{
+						MergeEnv: []workspacev1alpha1.AccessEnvTemplate{
+							{Name: "BROKEN", ValueTemplate: "{{ if }}"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	errs := Lint(strategy)
+
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	expected := []string{ResourceTemplateField(1), FieldBearerAuthURLTemplate, MergeEnvField(0)}
+	if strings.Join(fields, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected errors on %v, got %v", expected, errs)
+	}
+	if errs[0].Line != 2 {
+		t.Errorf("expected error on line 2, got %+v", errs[0])
+	}
+}
+
+func TestLintValidStrategy(t *testing.T) {
+	strategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			AccessURLTemplate: "https://example.com/{{ b32encode .Workspace.Namespace }}/",
+		},
+	}
+	if errs := Lint(strategy); errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accesstemplate
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// benchmarkTemplate is an IngressRoute template typical of the traefik access strategies
+const benchmarkTemplate = `spec:
+  entryPoints: [websecure]
+  routes:
+    - match: Host(` + "`{{ .AccessStrategy.Spec.CreateConnectionContext.domain }}`" + `) && PathPrefix(` + "`/workspaces/{{ .Workspace.Namespace }}/{{ .Workspace.Name }}/`" + `)
+      kind: Rule
+      middlewares:
+        - name: auth-{{ .Workspace.Name }}
+          namespace: {{ .Workspace.Namespace }}
+      services:
+        - name: {{ .Service.Name }}
+          namespace: {{ .Service.Namespace }}
+          port: {{ (index .Service.Spec.Ports 0).Port }}
+`
+
+func benchmarkData() *Data {
+	return &Data{
+		Workspace: &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "benchmark-workspace", Namespace: "team-a"},
+		},
+		AccessStrategy: &workspacev1alpha1.WorkspaceAccessStrategy{
+			ObjectMeta: metav1.ObjectMeta{Name: "traefik", Namespace: "jupyter-k8s-system"},
+			Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+				CreateConnectionContext: map[string]string{"domain": "notebooks.example.com"},
+			},
+		},
+		Service: &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "workspace-benchmark-workspace-service", Namespace: "team-a"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8888}}},
+		},
+	}
+}
+
+// BenchmarkRender measures rendering an access resource template, parsed on each reconcile
+func BenchmarkRender(b *testing.B) {
+	data := benchmarkData()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Render(ResourceTemplateField(0), benchmarkTemplate, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkExecute measures executing an already parsed template, the lower bound of caching
+// parsed templates
+func BenchmarkExecute(b *testing.B) {
+	data := benchmarkData()
+	tmpl, err := Parse(ResourceTemplateField(0), benchmarkTemplate)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Execute(ResourceTemplateField(0), tmpl, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accesstemplate
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// maxHostnameLength is the maximum length of a DNS label (RFC 1123)
+const maxHostnameLength = 63
+
+// Funcs returns the functions available to access strategy templates, in addition to the
+// text/template builtins. String functions take the string last, like their sprig counterparts,
+// so that they can end a pipeline, e.g. {{ .Workspace.Name | trimPrefix "ws-" | lower }}.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"b32encode":  workspaceutil.EncodeNamespaceB32,
+		"port":       port,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": trimPrefix,
+		"trimSuffix": trimSuffix,
+		"replace":    replace,
+		"trunc":      trunc,
+		"default":    defaultValue,
+		"sha256":     sha256Hex,
+		"b64enc":     b64enc,
+		"b64dec":     b64dec,
+		"hostname":   hostname,
+	}
+}
+
+// port returns the number of the port named name in ports, e.g. {{ port .Ports "http" }}
+func port(ports []corev1.ServicePort, name string) (int32, error) {
+	for _, p := range ports {
+		if p.Name == name {
+			return p.Port, nil
+		}
+	}
+	return 0, fmt.Errorf("no service port named %q", name)
+}
+
+// trimPrefix removes prefix from s, e.g. {{ trimPrefix "ws-" .Workspace.Name }}
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// trimSuffix removes suffix from s, e.g. {{ trimSuffix "/" .Workspace.Status.AccessURL }}
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// replace replaces all the occurrences of old with new in s, e.g. {{ replace "." "-" .Workspace.Name }}
+func replace(old, replacement, s string) string {
+	return strings.ReplaceAll(s, old, replacement)
+}
+
+// trunc keeps the first n bytes of s, e.g. {{ trunc 8 .Workspace.Name }}
+func trunc(n int, s string) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("trunc length %d is negative", n)
+	}
+	if len(s) <= n {
+		return s, nil
+	}
+	return s[:n], nil
+}
+
+// defaultValue returns value, or fallback when value is empty, e.g. {{ .Workspace.Spec.AppType | default "jupyter" }}
+func defaultValue(fallback, value string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// sha256Hex returns the hex encoded SHA-256 of s, e.g. {{ sha256 .Workspace.Name | trunc 10 }}
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// b64enc returns the standard base64 encoding of s
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// b64dec decodes the standard base64 encoding s
+func b64dec(s string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 value: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// hostname sanitizes s into a DNS label (RFC 1123), so that it can be used as a host name or
+// subdomain: it lowercases s, replaces each run of other characters than letters and digits with
+// a hyphen, trims leading and trailing hyphens and truncates the result to 63 characters,
+// e.g. {{ hostname .Workspace.Name }}.example.com. It fails when nothing remains.
+func hostname(s string) (string, error) {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+
+	label := b.String()
+	if len(label) > maxHostnameLength {
+		label = label[:maxHostnameLength]
+	}
+	label = strings.Trim(label, "-")
+	if label == "" {
+		return "", fmt.Errorf("%q has no letter or digit to make a hostname of", s)
+	}
+	return label, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package accesstemplate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestRenderFuncs(t *testing.T) {
+	data := NewData(&workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "WS-Team.Alpha", Namespace: "ns", Labels: map[string]string{"team": "ml"}},
+	}, nil, nil)
+
+	cases := []struct {
+		template string
+		expected string
+	}{
+		{`{{ .Workspace.Name | lower }}`, "ws-team.alpha"},
+		{`{{ .Workspace.Name | upper }}`, "WS-TEAM.ALPHA"},
+		{`{{ .Workspace.Name | trimPrefix "WS-" }}`, "Team.Alpha"},
+		{`{{ .Workspace.Name | trimSuffix ".Alpha" }}`, "WS-Team"},
+		{`{{ .Workspace.Name | replace "." "-" }}`, "WS-Team-Alpha"},
+		{`{{ .Workspace.Name | trunc 4 }}`, "WS-T"},
+		{`{{ .Workspace.Spec.AppType | default "jupyter" }}`, "jupyter"},
+		{`{{ sha256 "abc" | trunc 12 }}`, "ba7816bf8f01"},
+		{`{{ b64enc "ns/ws" }}`, "bnMvd3M="},
+		{`{{ b64dec "bnMvd3M=" }}`, "ns/ws"},
+		{`{{ hostname .Workspace.Name }}.example.com`, "ws-team-alpha.example.com"},
+		{`{{ .Workspace.Labels.team }}`, "ml"},
+	}
+	for _, c := range cases {
+		rendered, err := Render(FieldAccessURLTemplate, c.template, data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.template, err)
+			continue
+		}
+		if rendered != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.template, c.expected, rendered)
+		}
+	}
+}
+
+func TestSha256Hex(t *testing.T) {
+	if got := sha256Hex("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("unexpected digest %q", got)
+	}
+}
+
+func TestHostname(t *testing.T) {
+	cases := map[string]string{
+		"ws":                           "ws",
+		"My_Workspace":                 "my-workspace",
+		"--a..b--":                     "a-b",
+		"user@example.com":             "user-example-com",
+		strings.Repeat("a", 62) + ".b": strings.Repeat("a", 62),
+	}
+	for input, expected := range cases {
+		got, err := hostname(input)
+		if err != nil || got != expected {
+			t.Errorf("hostname(%q): expected %q, got %q, %v", input, expected, got, err)
+		}
+	}
+
+	if _, err := hostname("__"); err == nil {
+		t.Error("expected an error for a value without letters or digits")
+	}
+}
+
+func TestRenderFailsOnMissingKey(t *testing.T) {
+	data := NewData(&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}, nil, nil)
+
+	_, err := Render(FieldAccessURLTemplate, "https://{{ .Workspace.Labels.team }}.example.com/", data)
+
+	var templateErr *Error
+	if !errors.As(err, &templateErr) || !strings.Contains(templateErr.Message, `map has no entry for key "team"`) {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+}
+
+func TestRenderFuncErrors(t *testing.T) {
+	data := NewData(&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}, nil, nil)
+
+	for _, text := range []string{`{{ b64dec "%%%" }}`, `{{ trunc -1 "ws" }}`, `{{ hostname "--" }}`} {
+		if _, err := Render(FieldAccessURLTemplate, text, data); err == nil {
+			t.Errorf("%s: expected an error", text)
+		}
+	}
+}
@@ -15,12 +15,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 	"github.com/jupyter-infra/jupyter-k8s/test/utils"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// homePVCName returns the name of the home volume PVC of a workspace, created by the operator
+// deployed with the default resource name prefix
+func homePVCName(workspaceName string) string {
+	return controller.GeneratePVCName(&v1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: workspaceName}})
+}
+
 // isUsingFinch detects if the test environment is using Finch container runtime
 func isUsingFinch() bool {
 	// Check CONTAINER_TOOL environment variable
@@ -187,7 +195,7 @@ func VerifyPodCanAccessExternalVolumes(workspaceName, namespace, pvcName, mountP
 // VerifyPodCanAccessHomeVolume verifies pod can access home volumes
 func VerifyPodCanAccessHomeVolume(workspaceName, namespace string) {
 	ginkgo.GinkgoHelper()
-	pvcName := controller.GeneratePVCName(workspaceName)
+	pvcName := homePVCName(workspaceName)
 	VerifyPodCanAccessExternalVolumes(workspaceName, namespace, pvcName, "/home/jovyan")
 }
 
@@ -205,7 +213,7 @@ func VerifyHomeVolumeDataPersisted(workspaceName, namespace string) {
 		return
 	}
 	ginkgo.GinkgoHelper()
-	pvcName := controller.GeneratePVCName(workspaceName)
+	pvcName := homePVCName(workspaceName)
 
 	ginkgo.By("waiting for pvc to bound")
 	WaitForPVCBinding(pvcName, namespace)
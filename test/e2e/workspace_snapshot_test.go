@@ -0,0 +1,150 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package e2e
+
+import (
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jupyter-infra/jupyter-k8s/test/utils"
+)
+
+const (
+	snapshotGroupDir    = "snapshot"
+	snapshotSubgroupDir = ""
+	snapshotNamespace   = "default"
+	snapshotName        = "snapshot-source-nightly"
+)
+
+// The WorkspaceSnapshot controller is disabled in the e2e deployment, since kind has no CSI
+// snapshotter: these tests cover admission, and mark the snapshot ready through its status.
+var _ = Describe("Workspace Snapshot", Ordered, func() {
+	BeforeAll(func() {
+		for _, filename := range []string{
+			"snapshot-manager-role", "snapshot-manager-user1-binding", "snapshot-manager-user2-binding",
+		} {
+			By("creating " + filename)
+			cmd := exec.Command("kubectl", verbCreate, "-f",
+				BuildTestResourcePath(filename, snapshotGroupDir, snapshotSubgroupDir))
+			_, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("creating an OwnerOnly workspace as user-1")
+		err := createObjectAsUser(
+			BuildTestResourcePath("snapshot-source-workspace", snapshotGroupDir, snapshotSubgroupDir), user1, []string{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		deleteResourcesForSnapshotTest()
+	})
+
+	It("should deny user-2 from snapshotting an OwnerOnly workspace of user-1", func() {
+		err := createObjectAsUser(
+			BuildTestResourcePath("snapshot-of-source", snapshotGroupDir, snapshotSubgroupDir), user2, []string{})
+		Expect(err).To(HaveOccurred(), "user-2 should NOT be able to snapshot the workspace of user-1")
+		Expect(err.Error()).To(ContainSubstring("cannot snapshot workspace default/snapshot-source"))
+
+		VerifyResourceDoesNotExist("workspacesnapshot", snapshotName, snapshotNamespace)
+	})
+
+	It("should reject a snapshot of a workspace that does not exist", func() {
+		err := createObjectAsUser(
+			BuildTestResourcePath("snapshot-of-missing-workspace", snapshotGroupDir, snapshotSubgroupDir), user1, []string{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`workspace "workspace-does-not-exist" not found`))
+	})
+
+	It("should allow user-1 to snapshot their own workspace", func() {
+		err := createObjectAsUser(
+			BuildTestResourcePath("snapshot-of-source", snapshotGroupDir, snapshotSubgroupDir), user1, []string{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject restoring from a snapshot that is not ready", func() {
+		err := createRestoredWorkspaceAsUser(user1, []string{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is not ready to restore from"))
+	})
+
+	Context("with a ready snapshot", func() {
+		BeforeAll(func() {
+			By("marking the snapshot ready")
+			cmd := exec.Command("kubectl", "patch", "workspacesnapshot", snapshotName, "-n", snapshotNamespace,
+				"--subresource=status", "--type=merge", "-p", `{"status":{"phase":"Ready","restoreSize":"1Gi"}}`)
+			_, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should deny user-2 from restoring a snapshot of an OwnerOnly workspace of user-1", func() {
+			err := createRestoredWorkspaceAsUser(user2, []string{})
+			Expect(err).To(HaveOccurred(), "user-2 should NOT be able to restore the data of user-1")
+			Expect(err.Error()).To(ContainSubstring("cannot restore a snapshot of workspace default/snapshot-source"))
+		})
+
+		It("should allow user-1 and admins to restore the snapshot", func() {
+			Expect(createRestoredWorkspaceAsUser(user1, []string{})).To(Succeed())
+			Expect(createRestoredWorkspaceAsUser(adminUser, []string{"system:masters"})).To(Succeed())
+		})
+
+		It("should only let admins restore a snapshot without owner once its source is deleted", func() {
+			By("deleting the source workspace as user-1")
+			Expect(deleteWorkspaceAsUser("snapshot-source", user1, []string{})).To(Succeed())
+			WaitForResourceToNotExist("workspace", "snapshot-source", snapshotNamespace, 60*time.Second, 2*time.Second)
+
+			err := createRestoredWorkspaceAsUser(user1, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no longer exists, only its owner or an admin can restore from it"))
+
+			Expect(createRestoredWorkspaceAsUser(adminUser, []string{"system:masters"})).To(Succeed())
+		})
+	})
+})
+
+// createRestoredWorkspaceAsUser submits, as a server-side dry run, a workspace restoring from the
+// test snapshot, so that admission runs without provisioning a volume from the snapshot
+func createRestoredWorkspaceAsUser(user string, groups []string) error {
+	GinkgoHelper()
+	args := []string{verbCreate, "--dry-run=server", "-f",
+		BuildTestResourcePath("workspace-restored-from-snapshot", snapshotGroupDir, snapshotSubgroupDir), "--as=" + user}
+	for _, group := range groups {
+		args = append(args, "--as-group="+group)
+	}
+	cmd := exec.Command("kubectl", args...)
+	_, err := utils.Run(cmd)
+	return err
+}
+
+// deleteResourcesForSnapshotTest cleans up resources created during snapshot tests
+func deleteResourcesForSnapshotTest() {
+	GinkgoHelper()
+
+	By("cleaning up workspace snapshots and workspaces")
+	cmd := exec.Command("kubectl", "delete", "workspacesnapshot", "--all", "-n", snapshotNamespace,
+		"--ignore-not-found", "--wait=true", "--timeout=60s")
+	_, _ = utils.Run(cmd)
+	cmd = exec.Command("kubectl", "delete", "workspace", "--all", "-n", snapshotNamespace,
+		"--ignore-not-found", "--wait=true", "--timeout=120s")
+	_, _ = utils.Run(cmd)
+
+	By("cleaning up RBAC resources by label")
+	cmd = exec.Command("kubectl", "delete", "rolebinding", "-l", "jk8s/e2e=snapshot-test",
+		"-n", snapshotNamespace, "--ignore-not-found")
+	_, _ = utils.Run(cmd)
+	cmd = exec.Command("kubectl", "delete", "role", "-l", "jk8s/e2e=snapshot-test",
+		"-n", snapshotNamespace, "--ignore-not-found")
+	_, _ = utils.Run(cmd)
+
+	By("waiting an arbitrary fixed time for resources to be fully deleted")
+	time.Sleep(1 * time.Second)
+}
@@ -15,7 +15,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 	"github.com/jupyter-infra/jupyter-k8s/test/utils"
 )
 
@@ -144,7 +143,7 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 			)
 
 			By("verifying no pvc was created")
-			pvcName := controller.GeneratePVCName(workspaceName)
+			pvcName := homePVCName(workspaceName)
 			VerifyResourceDoesNotExist("pvc", pvcName, workspaceNamespace)
 		})
 
@@ -222,7 +221,7 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 			WaitForResourceToNotExist("workspace", workspaceName, workspaceNamespace, 60*time.Second, 5*time.Second)
 
 			By("verifying the pvc was deleted")
-			pvcName := controller.GeneratePVCName(workspaceName)
+			pvcName := homePVCName(workspaceName)
 			VerifyResourceDoesNotExist("pvc", pvcName, workspaceNamespace)
 		})
 
@@ -478,7 +477,7 @@ func testPvcForStorageTest(
 	workspaceNamespace string,
 	testCases []valueTestCaseForStorageTest,
 ) {
-	pvcName := controller.GeneratePVCName(workspaceName)
+	pvcName := homePVCName(workspaceName)
 
 	// Test each value case
 	for _, tc := range testCases {